@@ -0,0 +1,343 @@
+// ABOUTME: Contact affinity tags derived from meeting titles and email subjects
+// ABOUTME: Matches topic keywords per contact and stores suggested tags until they are accepted or rejected
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// affinityEvidenceLimit caps how many matching titles are kept per suggestion.
+const affinityEvidenceLimit = 3
+
+// AffinityTopic is a tag and the words that suggest it. Keywords are matched
+// as whole words or phrases; a trailing * matches any word starting with the
+// rest, e.g. "recruit*" matches "recruiting".
+type AffinityTopic struct {
+	Tag      string
+	Keywords []string
+}
+
+// AffinityTopics are the tags derived from interaction content.
+var AffinityTopics = []AffinityTopic{
+	{"ai", []string{"ai", "ml", "llm", "llms", "gpt", "genai", "machine learning", "artificial intelligence", "deep learning"}},
+	{"fundraising", []string{"fundrais*", "raise", "raising", "seed", "series a", "series b", "series c", "investor*", "term sheet", "pitch", "vc", "cap table"}},
+	{"hiring", []string{"hiring", "hire", "recruit*", "candidate*", "interview*", "job", "offer letter", "headcount"}},
+	{"partnerships", []string{"partnership*", "partner", "integration*", "co marketing", "reseller"}},
+	{"sales", []string{"demo", "pricing", "proposal", "quote", "contract", "renewal", "procurement"}},
+	{"product", []string{"roadmap", "feature*", "feedback", "beta", "launch", "user research"}},
+	{"security", []string{"security", "soc 2", "soc2", "pentest", "compliance", "gdpr"}},
+	{"crypto", []string{"crypto", "web3", "blockchain", "bitcoin", "ethereum"}},
+	{"climate", []string{"climate", "carbon", "sustainability", "renewable*"}},
+	{"advising", []string{"advisor*", "advisory", "mentor*", "office hours"}},
+	{"press", []string{"press", "podcast", "journalist", "media"}},
+}
+
+// TagSuggestion is a suggested affinity tag for a contact, stored as a
+// Suggestion of type SuggestionTypeContactTag.
+type TagSuggestion struct {
+	Suggestion  *Suggestion `json:"-"`
+	ContactID   uuid.UUID   `json:"contact_id"`
+	ContactName string      `json:"contact_name"`
+	Tag         string      `json:"tag"`
+	Mentions    int         `json:"mentions"`
+	Evidence    []string    `json:"evidence"` // a few of the matching titles and subjects
+}
+
+// tagSuggestionSourceID identifies a contact's suggestion for a tag, so
+// deriving again updates it rather than adding another.
+func tagSuggestionSourceID(contactID uuid.UUID, tag string) string {
+	return contactID.String() + ":" + tag
+}
+
+// affinityConfidence grows with the number of mentions.
+func affinityConfidence(mentions int) float64 {
+	confidence := 0.4 + 0.15*float64(mentions)
+	if confidence > 0.95 {
+		confidence = 0.95
+	}
+	return confidence
+}
+
+// AffinityResult counts what DeriveAffinityTags did.
+type AffinityResult struct {
+	Contacts    int              // contacts with any titles or subjects to scan
+	Created     int              // new suggestions
+	Updated     int              // pending suggestions with new mentions
+	Suggestions []*TagSuggestion // created and updated suggestions
+}
+
+// DeriveAffinityTags scans each contact's meeting titles, email subjects, and
+// event names for topic keywords and suggests a tag for each topic mentioned at
+// least minMentions times. Tags the contact already has, and suggestions that
+// were rejected, are not suggested again. With dryRun nothing is stored.
+func (c *Client) DeriveAffinityTags(minMentions int, dryRun bool) (*AffinityResult, error) {
+	if minMentions < 1 {
+		minMentions = 1
+	}
+	texts, err := c.affinityTexts()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := c.ListSuggestions(&SuggestionFilter{Type: SuggestionTypeContactTag})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suggestions: %w", err)
+	}
+	bySource := make(map[string]*Suggestion, len(existing))
+	for _, s := range existing {
+		bySource[s.SourceID] = s
+	}
+
+	result := &AffinityResult{}
+	contactIDs := make([]uuid.UUID, 0, len(texts))
+	for id := range texts {
+		contactIDs = append(contactIDs, id)
+	}
+	sort.Slice(contactIDs, func(i, j int) bool { return contactIDs[i].String() < contactIDs[j].String() })
+
+	for _, contactID := range contactIDs {
+		contact, err := c.GetContact(contactID)
+		if err != nil || contact.ArchivedAt != nil {
+			continue
+		}
+		result.Contacts++
+
+		for _, found := range matchAffinityTopics(texts[contactID]) {
+			if found.Mentions < minMentions || contact.HasTag(found.Tag) {
+				continue
+			}
+			found.ContactID, found.ContactName = contact.ID, contact.Name
+
+			suggestion := bySource[tagSuggestionSourceID(contact.ID, found.Tag)]
+			if suggestion != nil {
+				previous, err := decodeTagSuggestion(suggestion)
+				if err != nil || suggestion.Status != SuggestionStatusPending || previous.Mentions >= found.Mentions {
+					continue
+				}
+				result.Updated++
+			} else {
+				suggestion = &Suggestion{
+					Type:          SuggestionTypeContactTag,
+					SourceService: "affinity",
+					SourceID:      tagSuggestionSourceID(contact.ID, found.Tag),
+					Status:        SuggestionStatusPending,
+				}
+				result.Created++
+			}
+			found.Suggestion = suggestion
+			result.Suggestions = append(result.Suggestions, found)
+			if dryRun {
+				continue
+			}
+
+			data, err := json.Marshal(found)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal tag suggestion: %w", err)
+			}
+			suggestion.SourceData = string(data)
+			suggestion.Confidence = affinityConfidence(found.Mentions)
+			if suggestion.ID == uuid.Nil {
+				err = c.CreateSuggestion(suggestion)
+			} else {
+				err = c.UpdateSuggestion(suggestion)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to save tag suggestion: %w", err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// affinityTexts gathers each contact's meeting titles, email subjects, and
+// the names of events they attended.
+func (c *Client) affinityTexts() (map[uuid.UUID][]string, error) {
+	texts := make(map[uuid.UUID][]string)
+
+	logs, err := c.ListInteractionLogs(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	for _, log := range logs {
+		if log.InteractionType != InteractionMeeting && log.InteractionType != InteractionEmail {
+			continue
+		}
+		// Opens and clicks repeat the subject of a tracked email, already counted when sent
+		if strings.Contains(log.Metadata, `"tracked_email_id"`) {
+			continue
+		}
+		// Imported meetings and emails keep the title or subject as the first line
+		// (tracked emails and outreach replies prefix it, e.g. "Sent: ")
+		title, _, _ := strings.Cut(log.Notes, "\n")
+		texts[log.ContactID] = append(texts[log.ContactID], title)
+	}
+
+	// Outreach only logs an interaction once it's replied to
+	outreach, err := c.ListOutreach(OutreachPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outreach: %w", err)
+	}
+	for _, o := range outreach {
+		texts[o.ContactID] = append(texts[o.ContactID], o.Subject)
+	}
+
+	attendees, err := c.listEventAttendees(PrefixEventAttendee, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event attendees: %w", err)
+	}
+	for _, a := range attendees {
+		texts[a.ContactID] = append(texts[a.ContactID], a.EventName)
+	}
+	return texts, nil
+}
+
+// matchAffinityTopics counts the texts mentioning each topic, in
+// AffinityTopics order. A text counts once per topic however many of its
+// keywords it has.
+func matchAffinityTopics(texts []string) []*TagSuggestion {
+	var found []*TagSuggestion
+	for _, topic := range AffinityTopics {
+		match := &TagSuggestion{Tag: topic.Tag}
+		seen := make(map[string]bool)
+		for _, text := range texts {
+			words := affinityWords(text)
+			if len(words) == 0 || !matchesAnyKeyword(words, topic.Keywords) {
+				continue
+			}
+			match.Mentions++
+			text = strings.TrimSpace(text)
+			if len(match.Evidence) < affinityEvidenceLimit && !seen[strings.ToLower(text)] {
+				seen[strings.ToLower(text)] = true
+				match.Evidence = append(match.Evidence, text)
+			}
+		}
+		if match.Mentions > 0 {
+			found = append(found, match)
+		}
+	}
+	return found
+}
+
+// affinityWords lowercases text and splits it into letter and digit runs.
+func affinityWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesAnyKeyword reports whether words contain any keyword phrase.
+func matchesAnyKeyword(words, keywords []string) bool {
+	for _, keyword := range keywords {
+		phrase := strings.Fields(keyword)
+		for i := 0; i+len(phrase) <= len(words); i++ {
+			matched := true
+			for j, want := range phrase {
+				word := words[i+j]
+				if prefix, ok := strings.CutSuffix(want, "*"); ok {
+					matched = strings.HasPrefix(word, prefix)
+				} else {
+					matched = word == want
+				}
+				if !matched {
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeTagSuggestion reads the contact and tag from a tag suggestion.
+func decodeTagSuggestion(s *Suggestion) (*TagSuggestion, error) {
+	var ts TagSuggestion
+	if err := json.Unmarshal([]byte(s.SourceData), &ts); err != nil {
+		return nil, fmt.Errorf("invalid tag suggestion %s: %w", s.ID, err)
+	}
+	ts.Suggestion = s
+	return &ts, nil
+}
+
+// ListTagSuggestions returns tag suggestions with status (all when empty),
+// highest confidence first.
+func (c *Client) ListTagSuggestions(status string) ([]*TagSuggestion, error) {
+	suggestions, err := c.ListSuggestions(&SuggestionFilter{Type: SuggestionTypeContactTag, Status: status})
+	if err != nil {
+		return nil, err
+	}
+	var tags []*TagSuggestion
+	for _, s := range suggestions {
+		ts, err := decodeTagSuggestion(s)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, ts)
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Suggestion.Confidence != tags[j].Suggestion.Confidence {
+			return tags[i].Suggestion.Confidence > tags[j].Suggestion.Confidence
+		}
+		return tags[i].ContactName < tags[j].ContactName
+	})
+	return tags, nil
+}
+
+// AcceptTagSuggestion adds the suggested tag to its contact.
+func (c *Client) AcceptTagSuggestion(id uuid.UUID) (*TagSuggestion, error) {
+	ts, err := c.pendingTagSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	contact, err := c.GetContact(ts.ContactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+	if !contact.HasTag(ts.Tag) {
+		contact.Tags = append(contact.Tags, NormalizeTag(ts.Tag))
+		sort.Strings(contact.Tags)
+		if err := c.UpdateContact(contact); err != nil {
+			return nil, fmt.Errorf("failed to update contact: %w", err)
+		}
+	}
+	return ts, c.reviewSuggestion(ts.Suggestion, SuggestionStatusAccepted)
+}
+
+// RejectTagSuggestion marks a tag suggestion rejected, so deriving again
+// won't suggest it.
+func (c *Client) RejectTagSuggestion(id uuid.UUID) (*TagSuggestion, error) {
+	ts, err := c.pendingTagSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	return ts, c.reviewSuggestion(ts.Suggestion, SuggestionStatusRejected)
+}
+
+func (c *Client) pendingTagSuggestion(id uuid.UUID) (*TagSuggestion, error) {
+	s, err := c.GetSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Type != SuggestionTypeContactTag {
+		return nil, fmt.Errorf("suggestion %s is not a tag suggestion", id)
+	}
+	if s.Status != SuggestionStatusPending {
+		return nil, Conflictf("suggestion %s was already %s", id, s.Status)
+	}
+	return decodeTagSuggestion(s)
+}
+
+func (c *Client) reviewSuggestion(s *Suggestion, status string) error {
+	now := time.Now()
+	s.Status = status
+	s.ReviewedAt = &now
+	return c.UpdateSuggestion(s)
+}
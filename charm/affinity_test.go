@@ -0,0 +1,144 @@
+// ABOUTME: Tests for contact affinity tags
+// ABOUTME: Verifies keyword matching, suggestion review, and that rejected or existing tags aren't suggested again
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchAffinityTopics(t *testing.T) {
+	found := matchAffinityTopics([]string{
+		"Seed round: investor update",
+		"Re: Fundraising plan",
+		"Recruiting sync",
+		"Lunch at the pitch-perfect bistro",
+		"Maintenance window", // "ai" only as a whole word
+	})
+	mentions := make(map[string]int)
+	for _, f := range found {
+		mentions[f.Tag] = f.Mentions
+	}
+	if mentions["fundraising"] != 3 {
+		t.Errorf("expected 3 fundraising mentions, got %d", mentions["fundraising"])
+	}
+	if mentions["hiring"] != 1 {
+		t.Errorf("expected recruit* to match recruiting, got %d", mentions["hiring"])
+	}
+	if mentions["ai"] != 0 {
+		t.Errorf("ai should not match inside other words, got %d", mentions["ai"])
+	}
+}
+
+func TestDeriveAffinityTags(t *testing.T) {
+	client := NewTestClient(t)
+
+	ada := &Contact{Name: "Ada"}
+	grace := &Contact{Name: "Grace", Tags: []string{"ai"}}
+	for _, contact := range []*Contact{ada, grace} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	logs := []*InteractionLog{
+		{ContactID: ada.ID, InteractionType: InteractionMeeting, Notes: "LLM evals deep dive"},
+		{ContactID: ada.ID, InteractionType: InteractionEmail, Notes: "Re: Series A timing"},
+		{ContactID: ada.ID, InteractionType: InteractionEmail, Notes: "Investor intros"},
+		{ContactID: ada.ID, InteractionType: InteractionCall, Notes: "Talked about hiring"}, // calls aren't titles
+		{ContactID: grace.ID, InteractionType: InteractionMeeting, Notes: "AI roadmap"},
+		{ContactID: grace.ID, InteractionType: InteractionMeeting, Notes: "GPT pilot"},
+	}
+	for _, log := range logs {
+		log.Timestamp = time.Now()
+		if err := client.CreateInteractionLog(log); err != nil {
+			t.Fatalf("CreateInteractionLog failed: %v", err)
+		}
+	}
+	event := &Event{Name: "AI Engineer Summit", EventType: "conference", Date: time.Now()}
+	if err := client.CreateEvent(event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	if err := client.RecordAttendance(&EventAttendee{EventID: event.ID, EventName: event.Name, EventDate: event.Date, ContactID: ada.ID}); err != nil {
+		t.Fatalf("RecordAttendance failed: %v", err)
+	}
+
+	dry, err := client.DeriveAffinityTags(2, true)
+	if err != nil {
+		t.Fatalf("DeriveAffinityTags failed: %v", err)
+	}
+	if dry.Created != 2 {
+		t.Errorf("expected ai and fundraising for Ada in the dry run, got %+v", dry.Suggestions)
+	}
+	if pending, _ := client.ListTagSuggestions(SuggestionStatusPending); len(pending) != 0 {
+		t.Fatalf("dry run should not save suggestions, got %d", len(pending))
+	}
+
+	result, err := client.DeriveAffinityTags(2, false)
+	if err != nil {
+		t.Fatalf("DeriveAffinityTags failed: %v", err)
+	}
+	if result.Created != 2 || result.Contacts != 2 {
+		t.Errorf("unexpected result: created %d from %d contacts", result.Created, result.Contacts)
+	}
+	pending, err := client.ListTagSuggestions(SuggestionStatusPending)
+	if err != nil {
+		t.Fatalf("ListTagSuggestions failed: %v", err)
+	}
+	byTag := make(map[string]*TagSuggestion)
+	for _, ts := range pending {
+		if ts.ContactID != ada.ID {
+			t.Errorf("Grace already has the ai tag, got suggestion %+v", ts)
+		}
+		byTag[ts.Tag] = ts
+	}
+	if byTag["fundraising"] == nil || byTag["ai"] == nil || byTag["hiring"] != nil {
+		t.Fatalf("expected ai and fundraising suggestions, got %v", byTag)
+	}
+	if byTag["ai"].Mentions != 2 || len(byTag["ai"].Evidence) != 2 {
+		t.Errorf("expected the meeting and event as ai evidence, got %+v", byTag["ai"])
+	}
+
+	// Deriving again without new mentions changes nothing
+	again, err := client.DeriveAffinityTags(2, false)
+	if err != nil {
+		t.Fatalf("DeriveAffinityTags failed: %v", err)
+	}
+	if again.Created != 0 || again.Updated != 0 {
+		t.Errorf("expected no changes, got created %d updated %d", again.Created, again.Updated)
+	}
+
+	if _, err := client.AcceptTagSuggestion(byTag["fundraising"].Suggestion.ID); err != nil {
+		t.Fatalf("AcceptTagSuggestion failed: %v", err)
+	}
+	if _, err := client.RejectTagSuggestion(byTag["ai"].Suggestion.ID); err != nil {
+		t.Fatalf("RejectTagSuggestion failed: %v", err)
+	}
+	if _, err := client.AcceptTagSuggestion(byTag["ai"].Suggestion.ID); err == nil {
+		t.Error("accepting a rejected suggestion should fail")
+	}
+
+	updated, err := client.GetContact(ada.ID)
+	if err != nil {
+		t.Fatalf("GetContact failed: %v", err)
+	}
+	if !updated.HasTag("Fundraising") || updated.HasTag("ai") {
+		t.Errorf("expected only the accepted tag, got %v", updated.Tags)
+	}
+	tagged, err := client.ListContacts(&ContactFilter{Tag: "fundraising"})
+	if err != nil || len(tagged) != 1 || tagged[0].ID != ada.ID {
+		t.Errorf("expected Ada for the fundraising tag, got %v (%v)", tagged, err)
+	}
+
+	// More AI mentions don't bring back the rejected suggestion
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: ada.ID, InteractionType: InteractionMeeting, Notes: "Machine learning hiring plan", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+	last, err := client.DeriveAffinityTags(2, false)
+	if err != nil {
+		t.Fatalf("DeriveAffinityTags failed: %v", err)
+	}
+	if last.Created != 0 || last.Updated != 0 {
+		t.Errorf("rejected and accepted tags should not be suggested again, got %+v", last.Suggestions)
+	}
+}
@@ -0,0 +1,1338 @@
+// ABOUTME: Read-only analytics queries: a small SELECT language over fixed views of deals, contacts, companies, and interactions
+// ABOUTME: Supports filters, GROUP BY with count/sum/avg/min/max, ORDER BY, and LIMIT; nothing outside the views is reachable
+
+package charm
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Analytics query limits.
+const (
+	DefaultAnalyticsLimit = 100
+	MaxAnalyticsLimit     = 1000
+)
+
+// Analytics column types.
+const (
+	ColumnText   = "text"
+	ColumnNumber = "number"
+	ColumnDate   = "date"
+	ColumnBool   = "bool"
+	ColumnList   = "list" // e.g. tags; = matches any element, GROUP BY counts each
+)
+
+// AnalyticsColumn describes a column of an analytics view.
+type AnalyticsColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// AnalyticsView is a fixed, read-only table the query language selects from.
+type AnalyticsView struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Columns     []AnalyticsColumn `json:"columns"`
+
+	order string // rows come back sorted by this column without ORDER BY
+	rows  func(c *Client, now time.Time) ([]analyticsRow, error)
+}
+
+// column returns the named column, or nil.
+func (v *AnalyticsView) column(name string) *AnalyticsColumn {
+	for i := range v.Columns {
+		if v.Columns[i].Name == name {
+			return &v.Columns[i]
+		}
+	}
+	return nil
+}
+
+// analyticsRow maps column names to values: string, float64, time.Time,
+// bool, or []string. A missing key is NULL.
+type analyticsRow map[string]any
+
+// AnalyticsViews lists the views in name order.
+func AnalyticsViews() []*AnalyticsView {
+	return []*AnalyticsView{companiesView, contactsView, dealsView, interactionsView}
+}
+
+// FindAnalyticsView returns the named view, or nil.
+func FindAnalyticsView(name string) *AnalyticsView {
+	for _, view := range AnalyticsViews() {
+		if view.Name == strings.ToLower(name) {
+			return view
+		}
+	}
+	return nil
+}
+
+var dealsView = &AnalyticsView{
+	Name:        "deals",
+	order:       "title",
+	Description: "One row per deal. Amounts are whole units of the deal's currency.",
+	Columns: []AnalyticsColumn{
+		{"id", ColumnText, ""},
+		{"title", ColumnText, ""},
+		{"stage", ColumnText, "prospecting, qualification, proposal, negotiation, closed_won, closed_lost"},
+		{"open", ColumnBool, "not closed won or lost"},
+		{"amount", ColumnNumber, ""},
+		{"currency", ColumnText, ""},
+		{"probability", ColumnNumber, "win probability in percent, the override or the stage default"},
+		{"expected_value", ColumnNumber, "amount weighted by probability"},
+		{"source", ColumnText, ""},
+		{"referrer", ColumnText, ""},
+		{"company", ColumnText, ""},
+		{"industry", ColumnText, "the company's industry"},
+		{"contact", ColumnText, "primary contact"},
+		{"close_date", ColumnDate, "expected close date"},
+		{"close_quarter", ColumnText, "expected close quarter, e.g. 2025-Q3"},
+		{"created_at", ColumnDate, ""},
+		{"created_month", ColumnText, "e.g. 2025-07"},
+		{"last_activity_at", ColumnDate, ""},
+		{"tags", ColumnList, ""},
+	},
+	rows: func(c *Client, now time.Time) ([]analyticsRow, error) {
+		deals, err := c.ListDeals(&DealFilter{})
+		if err != nil {
+			return nil, err
+		}
+		industries, err := companyIndustries(c)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]analyticsRow, 0, len(deals))
+		for _, deal := range deals {
+			row := analyticsRow{
+				"id":               deal.ID.String(),
+				"title":            deal.Title,
+				"stage":            deal.Stage,
+				"open":             deal.Stage != StageClosedWon && deal.Stage != StageClosedLost,
+				"amount":           float64(deal.Amount) / 100,
+				"probability":      float64(deal.WinProbability()),
+				"expected_value":   float64(deal.ExpectedValue()) / 100,
+				"created_at":       deal.CreatedAt,
+				"created_month":    deal.CreatedAt.Format("2006-01"),
+				"last_activity_at": deal.LastActivityAt,
+			}
+			setText(row, "currency", deal.Currency)
+			setText(row, "source", deal.Source)
+			setText(row, "referrer", deal.ReferrerName)
+			setText(row, "company", deal.CompanyName)
+			setText(row, "industry", industries[deal.CompanyID])
+			setText(row, "contact", deal.ContactName)
+			setList(row, "tags", deal.Tags)
+			if deal.ExpectedCloseDate != nil {
+				row["close_date"] = *deal.ExpectedCloseDate
+				row["close_quarter"] = fmt.Sprintf("%d-Q%d", deal.ExpectedCloseDate.Year(), (int(deal.ExpectedCloseDate.Month())+2)/3)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	},
+}
+
+var contactsView = &AnalyticsView{
+	Name:        "contacts",
+	order:       "name",
+	Description: "One row per contact, archived contacts left out.",
+	Columns: []AnalyticsColumn{
+		{"id", ColumnText, ""},
+		{"name", ColumnText, ""},
+		{"email", ColumnText, ""},
+		{"title", ColumnText, "job title"},
+		{"company", ColumnText, ""},
+		{"industry", ColumnText, "the company's industry"},
+		{"seniority", ColumnText, ""},
+		{"function", ColumnText, ""},
+		{"met_via", ColumnText, ""},
+		{"met_date", ColumnDate, ""},
+		{"last_contacted_at", ColumnDate, ""},
+		{"days_since_contact", ColumnNumber, "NULL when never contacted"},
+		{"created_at", ColumnDate, ""},
+		{"tags", ColumnList, ""},
+	},
+	rows: func(c *Client, now time.Time) ([]analyticsRow, error) {
+		contacts, err := c.ListContacts(&ContactFilter{})
+		if err != nil {
+			return nil, err
+		}
+		industries, err := companyIndustries(c)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]analyticsRow, 0, len(contacts))
+		for _, contact := range contacts {
+			row := analyticsRow{
+				"id":         contact.ID.String(),
+				"name":       contact.Name,
+				"created_at": contact.CreatedAt,
+			}
+			setText(row, "email", contact.Email)
+			setText(row, "title", contact.Title)
+			setText(row, "company", contact.CompanyName)
+			if contact.CompanyID != nil {
+				setText(row, "industry", industries[*contact.CompanyID])
+			}
+			setText(row, "seniority", contact.Seniority)
+			setText(row, "function", contact.Function)
+			setText(row, "met_via", contact.MetVia)
+			setList(row, "tags", contact.Tags)
+			if contact.MetDate != nil {
+				row["met_date"] = *contact.MetDate
+			}
+			if contact.LastContactedAt != nil {
+				row["last_contacted_at"] = *contact.LastContactedAt
+				row["days_since_contact"] = math.Floor(now.Sub(*contact.LastContactedAt).Hours() / 24)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	},
+}
+
+var companiesView = &AnalyticsView{
+	Name:        "companies",
+	order:       "name",
+	Description: "One row per company, with its contact and deal totals. Amounts are whole units, currencies summed as-is.",
+	Columns: []AnalyticsColumn{
+		{"id", ColumnText, ""},
+		{"name", ColumnText, ""},
+		{"domain", ColumnText, ""},
+		{"industry", ColumnText, ""},
+		{"funding_stage", ColumnText, ""},
+		{"employees", ColumnNumber, "headcount, or the low end of a range"},
+		{"hq", ColumnText, "headquarters location"},
+		{"contacts", ColumnNumber, "number of contacts"},
+		{"open_deals", ColumnNumber, "number of open deals"},
+		{"pipeline", ColumnNumber, "total amount of open deals"},
+		{"won", ColumnNumber, "total amount of closed-won deals"},
+		{"created_at", ColumnDate, ""},
+		{"tags", ColumnList, ""},
+	},
+	rows: func(c *Client, now time.Time) ([]analyticsRow, error) {
+		companies, err := c.ListCompanies(&CompanyFilter{})
+		if err != nil {
+			return nil, err
+		}
+		contacts, err := c.ListContacts(&ContactFilter{})
+		if err != nil {
+			return nil, err
+		}
+		deals, err := c.ListDeals(&DealFilter{})
+		if err != nil {
+			return nil, err
+		}
+
+		contactCounts := make(map[uuid.UUID]int)
+		for _, contact := range contacts {
+			if contact.CompanyID != nil {
+				contactCounts[*contact.CompanyID]++
+			}
+		}
+		openDeals := make(map[uuid.UUID]int)
+		pipeline := make(map[uuid.UUID]int64)
+		won := make(map[uuid.UUID]int64)
+		for _, deal := range deals {
+			switch deal.Stage {
+			case StageClosedWon:
+				won[deal.CompanyID] += deal.Amount
+			case StageClosedLost:
+			default:
+				openDeals[deal.CompanyID]++
+				pipeline[deal.CompanyID] += deal.Amount
+			}
+		}
+
+		rows := make([]analyticsRow, 0, len(companies))
+		for _, company := range companies {
+			row := analyticsRow{
+				"id":         company.ID.String(),
+				"name":       company.Name,
+				"contacts":   float64(contactCounts[company.ID]),
+				"open_deals": float64(openDeals[company.ID]),
+				"pipeline":   float64(pipeline[company.ID]) / 100,
+				"won":        float64(won[company.ID]) / 100,
+				"created_at": company.CreatedAt,
+			}
+			setText(row, "domain", company.Domain)
+			setText(row, "industry", company.Industry)
+			setText(row, "funding_stage", company.FundingStage)
+			setText(row, "hq", company.HQLocation)
+			setList(row, "tags", company.Tags)
+			if company.EmployeeCount > 0 {
+				row["employees"] = float64(company.EmployeeCount)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	},
+}
+
+var interactionsView = &AnalyticsView{
+	Name:        "interactions",
+	order:       "timestamp",
+	Description: "One row per logged interaction.",
+	Columns: []AnalyticsColumn{
+		{"id", ColumnText, ""},
+		{"type", ColumnText, "meeting, call, email, message, event"},
+		{"contact", ColumnText, ""},
+		{"company", ColumnText, "the contact's company"},
+		{"timestamp", ColumnDate, ""},
+		{"month", ColumnText, "e.g. 2025-07"},
+		{"week", ColumnText, "ISO week, e.g. 2025-W28"},
+		{"sentiment", ColumnText, ""},
+		{"outcome", ColumnText, ""},
+		{"location", ColumnText, ""},
+	},
+	rows: func(c *Client, now time.Time) ([]analyticsRow, error) {
+		logs, err := c.ListInteractionLogs(&InteractionFilter{})
+		if err != nil {
+			return nil, err
+		}
+		contacts, err := c.ListContacts(&ContactFilter{IncludeArchived: true})
+		if err != nil {
+			return nil, err
+		}
+		companies := make(map[uuid.UUID]string)
+		for _, contact := range contacts {
+			companies[contact.ID] = contact.CompanyName
+		}
+
+		rows := make([]analyticsRow, 0, len(logs))
+		for _, log := range logs {
+			year, week := log.Timestamp.ISOWeek()
+			row := analyticsRow{
+				"id":        log.ID.String(),
+				"type":      log.InteractionType,
+				"timestamp": log.Timestamp,
+				"month":     log.Timestamp.Format("2006-01"),
+				"week":      fmt.Sprintf("%d-W%02d", year, week),
+			}
+			setText(row, "contact", log.ContactName)
+			setText(row, "company", companies[log.ContactID])
+			if log.Sentiment != nil {
+				setText(row, "sentiment", *log.Sentiment)
+			}
+			setText(row, "outcome", log.Outcome)
+			setText(row, "location", log.Location)
+			rows = append(rows, row)
+		}
+		return rows, nil
+	},
+}
+
+// companyIndustries maps company IDs to industries.
+func companyIndustries(c *Client) (map[uuid.UUID]string, error) {
+	companies, err := c.ListCompanies(&CompanyFilter{})
+	if err != nil {
+		return nil, err
+	}
+	industries := make(map[uuid.UUID]string, len(companies))
+	for _, company := range companies {
+		industries[company.ID] = company.Industry
+	}
+	return industries, nil
+}
+
+// setText and setList leave empty values NULL.
+func setText(row analyticsRow, column, value string) {
+	if value != "" {
+		row[column] = value
+	}
+}
+
+func setList(row analyticsRow, column string, values []string) {
+	if len(values) > 0 {
+		row[column] = values
+	}
+}
+
+// AnalyticsQuery is a parsed SELECT statement.
+type AnalyticsQuery struct {
+	Select  []SelectItem
+	View    *AnalyticsView
+	Where   []Condition // all must hold
+	GroupBy []string
+	OrderBy []OrderItem
+	Limit   int
+}
+
+// SelectItem is a column or an aggregate over one. Column is "*" for
+// count(*) and SELECT *.
+type SelectItem struct {
+	Func     string // count, sum, avg, min, max, or "" for a plain column
+	Distinct bool   // count(distinct column)
+	Column   string
+	Alias    string
+}
+
+// Name is the item's output column name: its alias, or the expression.
+func (s SelectItem) Name() string {
+	switch {
+	case s.Alias != "":
+		return s.Alias
+	case s.Func == "":
+		return s.Column
+	case s.Distinct:
+		return s.Func + "(distinct " + s.Column + ")"
+	}
+	return s.Func + "(" + s.Column + ")"
+}
+
+// Condition compares a column with literal values. Op is =, !=, <, <=, >,
+// >=, like, not like, in, not in, is null, or is not null.
+type Condition struct {
+	Column string
+	Op     string
+	Values []string
+}
+
+// OrderItem sorts by an output column.
+type OrderItem struct {
+	Column string
+	Desc   bool
+}
+
+// AnalyticsResult is a query's output table.
+type AnalyticsResult struct {
+	View      string   `json:"view"`
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Total     int      `json:"total"`               // rows before the limit
+	Truncated bool     `json:"truncated,omitempty"` // more rows than the limit
+}
+
+// RunAnalyticsQuery parses and runs a query. Syntax and column errors wrap
+// ErrInvalidQuery.
+func (c *Client) RunAnalyticsQuery(query string, now time.Time) (*AnalyticsResult, error) {
+	q, err := ParseAnalyticsQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.runAnalyticsQuery(q, now)
+}
+
+func (c *Client) runAnalyticsQuery(q *AnalyticsQuery, now time.Time) (*AnalyticsResult, error) {
+	rows, err := q.View.rows(c, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", q.View.Name, err)
+	}
+
+	var kept []analyticsRow
+	for _, row := range rows {
+		match := true
+		for _, cond := range q.Where {
+			ok, err := cond.matches(q.View, row, now)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				match = false
+				break
+			}
+		}
+		if match {
+			kept = append(kept, row)
+		}
+	}
+
+	result := &AnalyticsResult{View: q.View.Name, Rows: [][]any{}}
+	for _, item := range q.Select {
+		result.Columns = append(result.Columns, item.Name())
+	}
+	if q.grouped() {
+		result.Rows = q.aggregate(kept)
+		if len(q.OrderBy) > 0 {
+			indexes := make([]int, len(q.OrderBy))
+			for i, order := range q.OrderBy {
+				indexes[i] = indexOf(result.Columns, order.Column)
+			}
+			sort.SliceStable(result.Rows, func(i, j int) bool {
+				for k, order := range q.OrderBy {
+					if cmp := compareValues(result.Rows[i][indexes[k]], result.Rows[j][indexes[k]]); cmp != 0 {
+						return (cmp < 0) != order.Desc
+					}
+				}
+				return false
+			})
+		}
+	} else {
+		// Plain rows can be ordered by any column, selected or not
+		columns := make([]string, len(q.OrderBy))
+		for i, order := range q.OrderBy {
+			columns[i] = order.Column
+			for _, item := range q.Select {
+				if item.Alias == order.Column {
+					columns[i] = item.Column
+				}
+			}
+		}
+		sort.SliceStable(kept, func(i, j int) bool {
+			for k, order := range q.OrderBy {
+				if cmp := compareValues(kept[i][columns[k]], kept[j][columns[k]]); cmp != 0 {
+					return (cmp < 0) != order.Desc
+				}
+			}
+			return compareValues(kept[i][q.View.order], kept[j][q.View.order]) < 0
+		})
+		for _, row := range kept {
+			out := make([]any, len(q.Select))
+			for i, item := range q.Select {
+				out[i] = outputValue(q.View.column(item.Column), row[item.Column])
+			}
+			result.Rows = append(result.Rows, out)
+		}
+	}
+
+	result.Total = len(result.Rows)
+	if len(result.Rows) > q.Limit {
+		result.Rows = result.Rows[:q.Limit]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// grouped reports whether the query aggregates.
+func (q *AnalyticsQuery) grouped() bool {
+	if len(q.GroupBy) > 0 {
+		return true
+	}
+	for _, item := range q.Select {
+		if item.Func != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// aggregate groups rows and computes the selected aggregates, one output row
+// per group in group key order. A list column in GROUP BY puts a row in the
+// group of each of its elements.
+func (q *AnalyticsQuery) aggregate(rows []analyticsRow) [][]any {
+	type group struct {
+		keys []any
+		rows []analyticsRow
+	}
+	groups := make(map[string]*group)
+	var order []string
+
+	var expand func(row analyticsRow, i int, keys []any)
+	expand = func(row analyticsRow, i int, keys []any) {
+		if i == len(q.GroupBy) {
+			id := fmt.Sprint(keys...)
+			g, ok := groups[id]
+			if !ok {
+				g = &group{keys: append([]any(nil), keys...)}
+				groups[id] = g
+				order = append(order, id)
+			}
+			g.rows = append(g.rows, row)
+			return
+		}
+		column := q.GroupBy[i]
+		if list, ok := row[column].([]string); ok {
+			for _, value := range list {
+				expand(row, i+1, append(keys, value))
+			}
+			return
+		}
+		expand(row, i+1, append(keys, outputValue(q.View.column(column), row[column])))
+	}
+	for _, row := range rows {
+		expand(row, 0, nil)
+	}
+	// An aggregate over nothing is still one row, e.g. count(*) = 0
+	if len(q.GroupBy) == 0 && len(groups) == 0 {
+		groups[""] = &group{}
+		order = append(order, "")
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := groups[order[i]].keys, groups[order[j]].keys
+		for k := range a {
+			if cmp := compareValues(a[k], b[k]); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+
+	out := make([][]any, 0, len(order))
+	for _, id := range order {
+		g := groups[id]
+		row := make([]any, len(q.Select))
+		for i, item := range q.Select {
+			if item.Func == "" {
+				row[i] = g.keys[indexOf(q.GroupBy, item.Column)]
+				continue
+			}
+			row[i] = item.aggregate(q.View, g.rows)
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// aggregate computes the item's aggregate over rows. Sums, averages, and
+// extremes of nothing are NULL.
+func (s SelectItem) aggregate(view *AnalyticsView, rows []analyticsRow) any {
+	if s.Func == "count" {
+		if s.Column == "*" {
+			return float64(len(rows))
+		}
+		seen := make(map[string]bool)
+		count := 0
+		for _, row := range rows {
+			value, ok := row[s.Column]
+			if !ok {
+				continue
+			}
+			if s.Distinct {
+				key := fmt.Sprint(value)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			count++
+		}
+		return float64(count)
+	}
+
+	column := view.column(s.Column)
+	var values []any
+	for _, row := range rows {
+		if value, ok := row[s.Column]; ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	switch s.Func {
+	case "sum", "avg":
+		total := 0.0
+		for _, value := range values {
+			total += value.(float64)
+		}
+		if s.Func == "avg" {
+			return math.Round(total/float64(len(values))*100) / 100
+		}
+		return math.Round(total*100) / 100
+	case "min", "max":
+		best := values[0]
+		for _, value := range values[1:] {
+			cmp := compareValues(value, best)
+			if (s.Func == "min" && cmp < 0) || (s.Func == "max" && cmp > 0) {
+				best = value
+			}
+		}
+		return outputValue(column, best)
+	}
+	return nil
+}
+
+// outputValue formats a value for results: dates as YYYY-MM-DD.
+func outputValue(column *AnalyticsColumn, value any) any {
+	if t, ok := value.(time.Time); ok && column != nil && column.Type == ColumnDate {
+		return t.Format("2006-01-02")
+	}
+	return value
+}
+
+// compareValues orders values of one column; NULL sorts first.
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case !a:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches reports whether the row satisfies the condition. Text compares
+// case-insensitively; a list matches when any element does.
+func (cond Condition) matches(view *AnalyticsView, row analyticsRow, now time.Time) (bool, error) {
+	value, present := row[cond.Column]
+	switch cond.Op {
+	case "is null":
+		return !present, nil
+	case "is not null":
+		return present, nil
+	}
+	if !present {
+		return false, nil
+	}
+
+	column := view.column(cond.Column)
+	if list, ok := value.([]string); ok {
+		negated := strings.HasPrefix(cond.Op, "not ") || cond.Op == "!="
+		positive := cond
+		switch cond.Op {
+		case "!=":
+			positive.Op = "="
+		case "not like", "not in":
+			positive.Op = strings.TrimPrefix(cond.Op, "not ")
+		}
+		found := false
+		for _, element := range list {
+			ok, err := positive.compare(ColumnText, element, now)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				found = true
+				break
+			}
+		}
+		return found != negated, nil
+	}
+	return cond.compare(column.Type, value, now)
+}
+
+// compare tests one scalar value.
+func (cond Condition) compare(kind string, value any, now time.Time) (bool, error) {
+	switch cond.Op {
+	case "like", "not like":
+		ok := likePattern(cond.Values[0]).MatchString(fmt.Sprint(value))
+		return ok == (cond.Op == "like"), nil
+	case "in", "not in":
+		for i := range cond.Values {
+			literal, err := cond.literal(kind, i, now)
+			if err != nil {
+				return false, err
+			}
+			if compareValues(value, literal) == 0 {
+				return cond.Op == "in", nil
+			}
+		}
+		return cond.Op == "not in", nil
+	}
+
+	literal, err := cond.literal(kind, 0, now)
+	if err != nil {
+		return false, err
+	}
+	cmp := compareValues(value, literal)
+	switch cond.Op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("%w: unknown operator %s", ErrInvalidQuery, cond.Op)
+}
+
+// literal converts the condition's ith value to the column's type. Dates are
+// YYYY-MM-DD or an age like 90d for that long ago.
+func (cond Condition) literal(kind string, i int, now time.Time) (any, error) {
+	raw := cond.Values[i]
+	switch kind {
+	case ColumnNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s needs a number, not %q", ErrInvalidQuery, cond.Column, raw)
+		}
+		return n, nil
+	case ColumnBool:
+		switch strings.ToLower(raw) {
+		case "true", "yes", "1":
+			return true, nil
+		case "false", "no", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%w: %s needs true or false, not %q", ErrInvalidQuery, cond.Column, raw)
+	case ColumnDate:
+		if m := queryAgePattern.FindStringSubmatch(strings.ToLower(raw)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			switch m[2] {
+			case "d":
+				return now.AddDate(0, 0, -n), nil
+			case "w":
+				return now.AddDate(0, 0, -7*n), nil
+			case "m":
+				return now.AddDate(0, -n, 0), nil
+			default:
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+		day, err := time.ParseInLocation("2006-01-02", raw, now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s needs a date like 2006-01-02 or an age like 90d, not %q", ErrInvalidQuery, cond.Column, raw)
+		}
+		return day, nil
+	}
+	return raw, nil
+}
+
+// likePattern turns a LIKE pattern (% any run, _ one character) into a
+// case-insensitive regexp.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// analyticsToken is a lexical token: a keyword or identifier (lowercased),
+// a number, a quoted string, or a symbol.
+type analyticsToken struct {
+	text   string
+	quoted bool
+}
+
+// analyticsSymbols are the symbols the tokenizer accepts, longest first.
+var analyticsSymbols = []string{"!=", "<>", "<=", ">=", "=", "<", ">", ",", "(", ")", "*"}
+
+func tokenizeAnalytics(input string) ([]analyticsToken, error) {
+	var tokens []analyticsToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					if j+1 < len(runes) && runes[j+1] == r { // doubled quote
+						b.WriteRune(r)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidQuery)
+			}
+			tokens = append(tokens, analyticsToken{text: b.String(), quoted: true})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_' || unicode.IsDigit(r) || r == '-' || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || strings.ContainsRune("_-.", runes[j])) {
+				j++
+			}
+			tokens = append(tokens, analyticsToken{text: strings.ToLower(string(runes[i:j]))})
+			i = j
+		default:
+			matched := false
+			for _, symbol := range analyticsSymbols {
+				if strings.HasPrefix(string(runes[i:]), symbol) {
+					text := symbol
+					if text == "<>" {
+						text = "!="
+					}
+					tokens = append(tokens, analyticsToken{text: text})
+					i += len([]rune(symbol))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, string(r))
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// analyticsParser reads tokens left to right.
+type analyticsParser struct {
+	tokens []analyticsToken
+	pos    int
+}
+
+func (p *analyticsParser) peek() string {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *analyticsParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// accept consumes the next token when it is the unquoted word or symbol.
+func (p *analyticsParser) accept(word string) bool {
+	if p.peek() == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *analyticsParser) expect(word string) error {
+	if !p.accept(word) {
+		return fmt.Errorf("%w: expected %s %s", ErrInvalidQuery, strings.ToUpper(word), p.near())
+	}
+	return nil
+}
+
+// near describes where parsing stopped, for errors.
+func (p *analyticsParser) near() string {
+	if p.done() {
+		return "at end of query"
+	}
+	return fmt.Sprintf("near %q", p.tokens[p.pos].text)
+}
+
+var analyticsIdent = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// analyticsKeywords can't be used as column names or aliases.
+var analyticsKeywords = map[string]bool{
+	"select": true, "from": true, "where": true, "and": true, "or": true, "group": true, "by": true,
+	"order": true, "asc": true, "desc": true, "limit": true, "as": true, "like": true, "in": true,
+	"not": true, "is": true, "null": true, "distinct": true,
+}
+
+func (p *analyticsParser) ident(what string) (string, error) {
+	word := p.peek()
+	if !analyticsIdent.MatchString(word) || analyticsKeywords[word] {
+		return "", fmt.Errorf("%w: expected %s %s", ErrInvalidQuery, what, p.near())
+	}
+	p.pos++
+	return word, nil
+}
+
+// value reads a literal: a quoted string or a bare number or word.
+func (p *analyticsParser) value() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("%w: expected a value at end of query", ErrInvalidQuery)
+	}
+	token := p.tokens[p.pos]
+	if !token.quoted && (analyticsKeywords[token.text] || strings.ContainsAny(token.text, "(),*=<>!")) {
+		return "", fmt.Errorf("%w: expected a value %s", ErrInvalidQuery, p.near())
+	}
+	p.pos++
+	return token.text, nil
+}
+
+// analyticsFuncs are the aggregate functions.
+var analyticsFuncs = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true}
+
+// ParseAnalyticsQuery parses
+//
+//	SELECT item[, ...] FROM view [WHERE cond [AND cond]...]
+//	  [GROUP BY column[, ...]] [ORDER BY column [ASC|DESC][, ...]] [LIMIT n]
+//
+// where an item is *, a column, or count/sum/avg/min/max(column) with an
+// optional AS alias, and a condition is column =, !=, <, <=, >, >=, [NOT]
+// LIKE, or [NOT] IN (values), or column IS [NOT] NULL. Only the views'
+// columns can be read.
+func ParseAnalyticsQuery(input string) (*AnalyticsQuery, error) {
+	tokens, err := tokenizeAnalytics(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &analyticsParser{tokens: tokens}
+	q := &AnalyticsQuery{Limit: DefaultAnalyticsLimit}
+
+	if err := p.expect("select"); err != nil {
+		return nil, err
+	}
+	star := false
+	for {
+		if p.accept("*") {
+			star = true
+		} else {
+			item, err := p.selectItem()
+			if err != nil {
+				return nil, err
+			}
+			q.Select = append(q.Select, item)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if star && len(q.Select) > 0 {
+		return nil, fmt.Errorf("%w: SELECT * can't be combined with other columns", ErrInvalidQuery)
+	}
+
+	if err := p.expect("from"); err != nil {
+		return nil, err
+	}
+	name, err := p.ident("a view")
+	if err != nil {
+		return nil, err
+	}
+	if q.View = FindAnalyticsView(name); q.View == nil {
+		return nil, fmt.Errorf("%w: unknown view %s (use %s)", ErrInvalidQuery, name, strings.Join(analyticsViewNames(), ", "))
+	}
+	if star {
+		for _, column := range q.View.Columns {
+			q.Select = append(q.Select, SelectItem{Column: column.Name})
+		}
+	}
+
+	if p.accept("where") {
+		for {
+			cond, err := p.condition()
+			if err != nil {
+				return nil, err
+			}
+			q.Where = append(q.Where, cond)
+			if p.accept("or") {
+				return nil, fmt.Errorf("%w: OR isn't supported; use IN (...) or run two queries", ErrInvalidQuery)
+			}
+			if !p.accept("and") {
+				break
+			}
+		}
+	}
+
+	if p.accept("group") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		for {
+			column, err := p.ident("a column")
+			if err != nil {
+				return nil, err
+			}
+			q.GroupBy = append(q.GroupBy, column)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("order") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		for {
+			item, err := p.orderItem()
+			if err != nil {
+				return nil, err
+			}
+			q.OrderBy = append(q.OrderBy, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("limit") {
+		raw, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: LIMIT needs a positive number, not %q", ErrInvalidQuery, raw)
+		}
+		q.Limit = min(n, MaxAnalyticsLimit)
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, p.tokens[p.pos].text)
+	}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (p *analyticsParser) selectItem() (SelectItem, error) {
+	var item SelectItem
+	word := p.peek()
+	if analyticsFuncs[word] && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "(" && !p.tokens[p.pos+1].quoted {
+		p.pos += 2
+		item.Func = word
+		item.Distinct = p.accept("distinct")
+		if p.accept("*") {
+			item.Column = "*"
+		} else {
+			column, err := p.ident("a column")
+			if err != nil {
+				return item, err
+			}
+			item.Column = column
+		}
+		if err := p.expect(")"); err != nil {
+			return item, err
+		}
+	} else {
+		column, err := p.ident("a column or aggregate")
+		if err != nil {
+			return item, err
+		}
+		item.Column = column
+	}
+	if p.accept("as") {
+		alias, err := p.ident("an alias")
+		if err != nil {
+			return item, err
+		}
+		item.Alias = alias
+	}
+	return item, nil
+}
+
+func (p *analyticsParser) condition() (Condition, error) {
+	column, err := p.ident("a column")
+	if err != nil {
+		return Condition{}, err
+	}
+	cond := Condition{Column: column}
+
+	switch {
+	case p.accept("is"):
+		cond.Op = "is null"
+		if p.accept("not") {
+			cond.Op = "is not null"
+		}
+		return cond, p.expect("null")
+	case p.accept("not"):
+		switch {
+		case p.accept("like"):
+			cond.Op = "not like"
+		case p.accept("in"):
+			cond.Op = "not in"
+		default:
+			return cond, fmt.Errorf("%w: expected LIKE or IN after NOT %s", ErrInvalidQuery, p.near())
+		}
+	case p.accept("like"):
+		cond.Op = "like"
+	case p.accept("in"):
+		cond.Op = "in"
+	default:
+		for _, op := range []string{"=", "!=", "<=", ">=", "<", ">"} {
+			if p.accept(op) {
+				cond.Op = op
+				break
+			}
+		}
+		if cond.Op == "" {
+			return cond, fmt.Errorf("%w: expected a comparison after %s %s", ErrInvalidQuery, column, p.near())
+		}
+	}
+
+	if strings.HasSuffix(cond.Op, "in") {
+		if err := p.expect("("); err != nil {
+			return cond, err
+		}
+		for {
+			value, err := p.value()
+			if err != nil {
+				return cond, err
+			}
+			cond.Values = append(cond.Values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return cond, p.expect(")")
+	}
+	value, err := p.value()
+	if err != nil {
+		return cond, err
+	}
+	cond.Values = []string{value}
+	return cond, nil
+}
+
+func (p *analyticsParser) orderItem() (OrderItem, error) {
+	var item OrderItem
+	// Aggregates can be ordered by their expression, e.g. ORDER BY count(*)
+	if word := p.peek(); analyticsFuncs[word] {
+		selected, err := p.selectItem()
+		if err != nil {
+			return item, err
+		}
+		item.Column = selected.Name()
+	} else {
+		column, err := p.ident("a column")
+		if err != nil {
+			return item, err
+		}
+		item.Column = column
+	}
+	if p.accept("desc") {
+		item.Desc = true
+	} else {
+		p.accept("asc")
+	}
+	return item, nil
+}
+
+// validate checks columns against the view and that grouped queries only
+// select grouped columns and aggregates.
+func (q *AnalyticsQuery) validate() error {
+	known := func(name string) (*AnalyticsColumn, error) {
+		column := q.View.column(name)
+		if column == nil {
+			names := make([]string, len(q.View.Columns))
+			for i, c := range q.View.Columns {
+				names[i] = c.Name
+			}
+			return nil, fmt.Errorf("%w: %s has no column %s (columns: %s)", ErrInvalidQuery, q.View.Name, name, strings.Join(names, ", "))
+		}
+		return column, nil
+	}
+
+	grouped := q.grouped()
+	names := make(map[string]bool)
+	for _, item := range q.Select {
+		if names[item.Name()] {
+			return fmt.Errorf("%w: %s is selected twice; use AS to rename one", ErrInvalidQuery, item.Name())
+		}
+		names[item.Name()] = true
+
+		if item.Column == "*" {
+			if item.Func != "count" || item.Distinct {
+				return fmt.Errorf("%w: only count(*) takes *", ErrInvalidQuery)
+			}
+			continue
+		}
+		column, err := known(item.Column)
+		if err != nil {
+			return err
+		}
+		switch item.Func {
+		case "":
+			if grouped && indexOf(q.GroupBy, item.Column) < 0 {
+				return fmt.Errorf("%w: %s must be in GROUP BY or inside an aggregate", ErrInvalidQuery, item.Column)
+			}
+		case "sum", "avg":
+			if column.Type != ColumnNumber {
+				return fmt.Errorf("%w: %s(%s) needs a number column", ErrInvalidQuery, item.Func, item.Column)
+			}
+		case "min", "max":
+			if column.Type == ColumnList || column.Type == ColumnBool {
+				return fmt.Errorf("%w: %s(%s) needs a number, date, or text column", ErrInvalidQuery, item.Func, item.Column)
+			}
+		}
+		if item.Distinct && item.Func != "count" {
+			return fmt.Errorf("%w: DISTINCT only works in count()", ErrInvalidQuery)
+		}
+	}
+
+	lists := 0
+	for _, name := range q.GroupBy {
+		column, err := known(name)
+		if err != nil {
+			return err
+		}
+		if column.Type == ColumnList {
+			lists++
+		}
+	}
+	if lists > 1 {
+		return fmt.Errorf("%w: GROUP BY takes at most one list column", ErrInvalidQuery)
+	}
+
+	for _, cond := range q.Where {
+		column, err := known(cond.Column)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(cond.Op, "like") && column.Type != ColumnText && column.Type != ColumnList {
+			return fmt.Errorf("%w: LIKE needs a text column, not %s", ErrInvalidQuery, cond.Column)
+		}
+		// Check literals up front so a bad value fails even when no row is read
+		kind := column.Type
+		if kind == ColumnList {
+			kind = ColumnText
+		}
+		if !strings.Contains(cond.Op, "like") && !strings.HasPrefix(cond.Op, "is") {
+			for i := range cond.Values {
+				if _, err := cond.literal(kind, i, time.Now()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, order := range q.OrderBy {
+		if names[order.Column] || (!grouped && q.View.column(order.Column) != nil) {
+			continue
+		}
+		if grouped {
+			return fmt.Errorf("%w: ORDER BY %s must name a selected column, aggregate, or alias", ErrInvalidQuery, order.Column)
+		}
+		return fmt.Errorf("%w: ORDER BY %s must name a column or alias", ErrInvalidQuery, order.Column)
+	}
+	return nil
+}
+
+func analyticsViewNames() []string {
+	var names []string
+	for _, view := range AnalyticsViews() {
+		names = append(names, view.Name)
+	}
+	return names
+}
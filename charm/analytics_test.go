@@ -0,0 +1,147 @@
+// ABOUTME: Tests for read-only analytics queries
+// ABOUTME: Verifies parsing, filters, grouping and aggregates, ordering, limits, and rejected queries
+
+package charm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseAnalyticsQuery(t *testing.T) {
+	q, err := ParseAnalyticsQuery(`select industry, sum(amount) as total, count(*) FROM deals
+		WHERE open = true AND tags IN ('q3', "q4") AND close_date >= '2025-01-01'
+		GROUP BY industry ORDER BY total DESC LIMIT 5000`)
+	if err != nil {
+		t.Fatalf("ParseAnalyticsQuery failed: %v", err)
+	}
+	if q.View.Name != "deals" || q.Limit != MaxAnalyticsLimit {
+		t.Errorf("unexpected view %s, limit %d", q.View.Name, q.Limit)
+	}
+	var names []string
+	for _, item := range q.Select {
+		names = append(names, item.Name())
+	}
+	if fmt.Sprint(names) != "[industry total count(*)]" {
+		t.Errorf("unexpected select %v", names)
+	}
+	if len(q.Where) != 3 || q.Where[1].Op != "in" || fmt.Sprint(q.Where[1].Values) != "[q3 q4]" {
+		t.Errorf("unexpected where %+v", q.Where)
+	}
+	if len(q.OrderBy) != 1 || q.OrderBy[0] != (OrderItem{Column: "total", Desc: true}) {
+		t.Errorf("unexpected order %+v", q.OrderBy)
+	}
+
+	for _, bad := range []string{
+		"",
+		"select * from users",
+		"select password from contacts",
+		"select name, count(*) from contacts",
+		"select sum(name) from contacts",
+		"select name from contacts where name = 'a' or name = 'b'",
+		"select name from contacts where days_since_contact > soon",
+		"select name from contacts where created_at < 'yesterday'",
+		"select name from contacts where name = 'Ada",
+		"select name from contacts limit 0",
+		"select name from contacts; delete from contacts",
+		"select industry, count(*) from companies group by industry order by name",
+	} {
+		if _, err := ParseAnalyticsQuery(bad); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("ParseAnalyticsQuery(%q): expected ErrInvalidQuery, got %v", bad, err)
+		}
+	}
+}
+
+func TestRunAnalyticsQuery(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+	recent, old := now.AddDate(0, 0, -10), now.AddDate(0, -6, 0)
+
+	acme := &Company{Name: "Acme", Industry: "SaaS"}
+	globex := &Company{Name: "Globex", Industry: "Energy"}
+	for _, company := range []*Company{acme, globex} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("CreateCompany failed: %v", err)
+		}
+	}
+	ada := &Contact{Name: "Ada", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"vip", "investor"}, LastContactedAt: &recent}
+	for _, contact := range []*Contact{
+		ada,
+		{Name: "Bea", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"vip"}, LastContactedAt: &old},
+		{Name: "Cy", CompanyID: &globex.ID, CompanyName: "Globex"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	for _, deal := range []*Deal{
+		{Title: "Pilot", Amount: 1000000, Stage: StageProposal, CompanyID: acme.ID, CompanyName: "Acme"},
+		{Title: "Expansion", Amount: 500000, Stage: StageNegotiation, CompanyID: acme.ID, CompanyName: "Acme"},
+		{Title: "Grid", Amount: 2000000, Stage: StageProspecting, CompanyID: globex.ID, CompanyName: "Globex"},
+		{Title: "Renewal", Amount: 300000, Stage: StageClosedWon, CompanyID: globex.ID, CompanyName: "Globex"},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	run := func(query string) *AnalyticsResult {
+		t.Helper()
+		result, err := client.RunAnalyticsQuery(query, now)
+		if err != nil {
+			t.Fatalf("RunAnalyticsQuery(%q) failed: %v", query, err)
+		}
+		return result
+	}
+
+	// Total open pipeline by industry, biggest first
+	result := run("select industry, sum(amount) as pipeline, count(*) as deals from deals where open = true group by industry order by pipeline desc")
+	if fmt.Sprint(result.Columns) != "[industry pipeline deals]" {
+		t.Errorf("unexpected columns %v", result.Columns)
+	}
+	if got := fmt.Sprint(result.Rows); got != "[[Energy 20000 1] [SaaS 15000 2]]" {
+		t.Errorf("pipeline by industry = %s", got)
+	}
+
+	// Aggregates without GROUP BY make one row, even over nothing
+	if got := fmt.Sprint(run("select count(*), max(amount) from deals where stage = 'closed_lost'").Rows); got != "[[0 <nil>]]" {
+		t.Errorf("empty aggregate = %s", got)
+	}
+	if got := fmt.Sprint(run("select count(distinct company), avg(amount) from deals").Rows); got != "[[2 9500]]" {
+		t.Errorf("distinct count = %s", got)
+	}
+
+	// List columns match any element and group by each, untagged rows in a NULL group
+	if got := fmt.Sprint(run("select name from contacts where tags = 'VIP' and name like 'a%'").Rows); got != "[[Ada]]" {
+		t.Errorf("tag filter = %s", got)
+	}
+	if got := fmt.Sprint(run("select tags, count(*) from contacts group by tags").Rows); got != "[[<nil> 1] [investor 1] [vip 2]]" {
+		t.Errorf("group by tags = %s", got)
+	}
+
+	// Ages are relative to now, and NULLs only match IS NULL
+	if got := fmt.Sprint(run("select name from contacts where last_contacted_at < '90d'").Rows); got != "[[Bea]]" {
+		t.Errorf("age filter = %s", got)
+	}
+	if got := fmt.Sprint(run("select name from contacts where last_contacted_at is null").Rows); got != "[[Cy]]" {
+		t.Errorf("null filter = %s", got)
+	}
+
+	// Company rollups, ordered by an unselected column, with a limit
+	result = run("select name, pipeline, won from companies order by contacts desc limit 1")
+	if got := fmt.Sprint(result.Rows); got != "[[Acme 15000 0]]" || !result.Truncated || result.Total != 2 {
+		t.Errorf("company rollup = %s, truncated %v", got, result.Truncated)
+	}
+
+	// Dates come back as days
+	result = run("select * from contacts where name = 'ada'")
+	if len(result.Rows) != 1 || result.Rows[0][indexOf(result.Columns, "last_contacted_at")] != recent.Format("2006-01-02") {
+		t.Errorf("unexpected row %v", result.Rows)
+	}
+
+	if _, err := client.RunAnalyticsQuery("select * from notes", now); !errors.Is(err, ErrInvalidQuery) || CodeOf(err) != CodeValidation {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
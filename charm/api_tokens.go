@@ -0,0 +1,154 @@
+// ABOUTME: Bearer tokens for the web server's REST API
+// ABOUTME: Only a SHA-256 hash of each token is stored; the token itself is shown once when created
+
+package charm
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiTokenPrefix starts every API token, so a leaked one is easy to spot.
+const apiTokenPrefix = "pagen_"
+
+// APIToken is a named credential for the REST API. Scripts send the token as
+// "Authorization: Bearer <token>"; pagen keeps only its hash.
+type APIToken struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Hint      string    `json:"hint"` // first characters of the token, for telling tokens apart
+	Hash      string    `json:"hash"` // hex SHA-256 of the token
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateAPIToken creates a token called name and returns it with the token
+// itself, which can't be recovered later.
+func (c *Client) CreateAPIToken(name string) (*APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", Validationf("token name is required")
+	}
+	tokens, err := c.ListAPITokens()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, existing := range tokens {
+		if strings.EqualFold(existing.Name, name) {
+			return nil, "", Conflictf("a token named %s already exists", existing.Name)
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	secret := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	token := &APIToken{
+		ID:        uuid.New(),
+		Name:      name,
+		Hint:      secret[:len(apiTokenPrefix)+4],
+		Hash:      hashAPIToken(secret),
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal API token: %w", err)
+	}
+	if err := c.Set(APITokenKey(token.ID.String()), data); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// ListAPITokens returns all API tokens, oldest first.
+func (c *Client) ListAPITokens() ([]*APIToken, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixAPIToken))
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []*APIToken
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var token APIToken
+		if err := json.Unmarshal(data, &token); err != nil {
+			continue
+		}
+		tokens = append(tokens, &token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+// FindAPIToken returns the token matching secret, or nil if there is none.
+func (c *Client) FindAPIToken(secret string) (*APIToken, error) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil, nil
+	}
+	tokens, err := c.ListAPITokens()
+	if err != nil {
+		return nil, err
+	}
+	hash := hashAPIToken(secret)
+	for _, token := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token.Hash), []byte(hash)) == 1 {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+// RevokeAPIToken deletes the token named ref, or whose ID starts with ref,
+// and returns it.
+func (c *Client) RevokeAPIToken(ref string) (*APIToken, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, Validationf("token name or ID is required")
+	}
+	tokens, err := c.ListAPITokens()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*APIToken
+	for _, token := range tokens {
+		if strings.EqualFold(token.Name, ref) || (len(ref) >= MinIDPrefix && strings.HasPrefix(token.ID.String(), strings.ToLower(ref))) {
+			matches = append(matches, token)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no API token matches %s", ref)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d API tokens match %s; use more of the ID", len(matches), ref)
+	}
+
+	if err := c.Delete(APITokenKey(matches[0].ID.String())); err != nil {
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+func hashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+// ABOUTME: Tests for REST API tokens
+// ABOUTME: Verifies tokens are stored hashed, found by secret, unique by name, and revocable
+
+package charm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAPITokens(t *testing.T) {
+	client := NewTestClient(t)
+
+	token, secret, err := client.CreateAPIToken("Shortcuts")
+	if err != nil {
+		t.Fatalf("CreateAPIToken failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, "pagen_") || !strings.HasPrefix(secret, token.Hint) {
+		t.Errorf("unexpected token %q with hint %q", secret, token.Hint)
+	}
+	data, err := client.Get(APITokenKey(token.ID.String()))
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Error("the token itself should not be stored")
+	}
+
+	if _, _, err := client.CreateAPIToken("shortcuts"); err == nil {
+		t.Error("expected an error for a duplicate name")
+	}
+
+	found, err := client.FindAPIToken(secret)
+	if err != nil || found == nil || found.ID != token.ID {
+		t.Fatalf("expected to find the token, got %v (%v)", found, err)
+	}
+	if found, _ := client.FindAPIToken(secret + "x"); found != nil {
+		t.Error("expected no match for a wrong token")
+	}
+
+	if _, err := client.RevokeAPIToken("Shortcuts"); err != nil {
+		t.Fatalf("RevokeAPIToken failed: %v", err)
+	}
+	if found, _ := client.FindAPIToken(secret); found != nil {
+		t.Error("expected a revoked token not to match")
+	}
+}
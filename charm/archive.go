@@ -0,0 +1,173 @@
+// ABOUTME: Stale-contact archival policy
+// ABOUTME: Finds contacts created by sync that were never contacted or edited, and archives them
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultArchiveMonths is how old an untouched synced contact must be before
+// the archive policy picks it up.
+const DefaultArchiveMonths = 12
+
+// ArchivePolicy archives contacts that sync created but nobody has used.
+// A new policy starts in dry-run so its candidates can be reviewed first.
+type ArchivePolicy struct {
+	Enabled bool `json:"enabled"`
+	Months  int  `json:"months,omitempty"` // default: DefaultArchiveMonths
+	DryRun  bool `json:"dry_run"`          // only report what would be archived
+}
+
+// MonthsOrDefault returns the configured age threshold in months.
+func (p *ArchivePolicy) MonthsOrDefault() int {
+	if p != nil && p.Months > 0 {
+		return p.Months
+	}
+	return DefaultArchiveMonths
+}
+
+// StaleContact is a contact the archive policy would archive.
+type StaleContact struct {
+	Contact    *Contact
+	Source     string // sync service that created it
+	ImportedAt time.Time
+}
+
+// StaleContacts returns active contacts created by sync more than months ago
+// that have no interactions, were never marked contacted, aren't on a deal,
+// and haven't been edited since they were imported. Oldest first.
+func (c *Client) StaleContacts(months int, now time.Time) ([]*StaleContact, error) {
+	if months <= 0 {
+		return nil, Validationf("months must be positive")
+	}
+	cutoff := now.AddDate(0, -months, 0)
+
+	logs, err := c.ListRecentSyncLogs(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync logs: %w", err)
+	}
+	// Logs are newest first, so the first one seen is the latest import
+	imported := make(map[uuid.UUID]*SyncLog)
+	for _, log := range logs {
+		if log.EntityType != EntityContact {
+			continue
+		}
+		if _, ok := imported[log.EntityID]; !ok {
+			imported[log.EntityID] = log
+		}
+	}
+	if len(imported) == 0 {
+		return nil, nil
+	}
+
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	used := make(map[uuid.UUID]bool)
+	for _, interaction := range interactions {
+		used[interaction.ContactID] = true
+	}
+
+	deals, err := c.ListDeals(&DealFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	for _, deal := range deals {
+		if deal.ContactID != nil {
+			used[*deal.ContactID] = true
+		}
+		if deal.ReferrerID != nil {
+			used[*deal.ReferrerID] = true
+		}
+	}
+
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	var stale []*StaleContact
+	for _, contact := range contacts {
+		log, ok := imported[contact.ID]
+		if !ok || used[contact.ID] || contact.LastContactedAt != nil {
+			continue
+		}
+		if !contact.CreatedAt.Before(cutoff) || contact.UpdatedAt.After(log.ImportedAt) {
+			continue
+		}
+		stale = append(stale, &StaleContact{Contact: contact, Source: log.SourceService, ImportedAt: log.ImportedAt})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Contact.CreatedAt.Before(stale[j].Contact.CreatedAt)
+	})
+	return stale, nil
+}
+
+// ArchiveContact hides a contact from lists without deleting it.
+func (c *Client) ArchiveContact(id uuid.UUID) (*Contact, error) {
+	contact, err := c.GetContact(id)
+	if err != nil {
+		return nil, err
+	}
+	if contact.ArchivedAt != nil {
+		return contact, nil
+	}
+
+	now := time.Now()
+	contact.ArchivedAt = &now
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to archive contact: %w", err)
+	}
+	return contact, nil
+}
+
+// UnarchiveContact returns an archived contact to lists. The update counts as
+// an edit, so the archive policy won't pick the contact up again.
+func (c *Client) UnarchiveContact(id uuid.UUID) (*Contact, error) {
+	contact, err := c.GetContact(id)
+	if err != nil {
+		return nil, err
+	}
+	if contact.ArchivedAt == nil {
+		return nil, fmt.Errorf("contact is not archived: %s", contact.Name)
+	}
+
+	contact.ArchivedAt = nil
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to unarchive contact: %w", err)
+	}
+	return contact, nil
+}
+
+// ApplyArchivePolicy finds stale contacts under the policy and, unless the
+// policy is in dry-run, archives them. It returns the candidates and how many
+// were archived; a disabled policy does nothing.
+func (c *Client) ApplyArchivePolicy(policy *ArchivePolicy, now time.Time) ([]*StaleContact, int, error) {
+	if policy == nil || !policy.Enabled {
+		return nil, 0, nil
+	}
+
+	stale, err := c.StaleContacts(policy.MonthsOrDefault(), now)
+	if err != nil {
+		return nil, 0, err
+	}
+	if policy.DryRun {
+		return stale, 0, nil
+	}
+
+	archived := 0
+	for _, candidate := range stale {
+		if _, err := c.ArchiveContact(candidate.Contact.ID); err != nil {
+			return stale, archived, err
+		}
+		archived++
+	}
+	return stale, archived, nil
+}
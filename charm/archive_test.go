@@ -0,0 +1,94 @@
+// ABOUTME: Tests for the stale-contact archival policy
+// ABOUTME: Verifies which synced contacts count as stale, dry runs, archiving, and unarchiving
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleContacts(t *testing.T) {
+	client := NewTestClient(t)
+
+	create := func(name, email string, synced bool) *Contact {
+		t.Helper()
+		contact := &Contact{Name: name, Email: email}
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+		if synced {
+			if err := client.CreateSyncLog(&SyncLog{SourceService: "contacts", SourceID: name, EntityType: EntityContact, EntityID: contact.ID}); err != nil {
+				t.Fatalf("failed to create sync log: %v", err)
+			}
+		}
+		return contact
+	}
+
+	now := time.Now()
+	stale := create("Stale", "stale@example.com", true)
+	create("Manual", "manual@example.com", false)
+	edited := create("Edited", "edited@example.com", true)
+	talked := create("Talked", "talked@example.com", true)
+	onDeal := create("Buyer", "buyer@example.com", true)
+
+	edited.Phone = "555-0100"
+	if err := client.UpdateContact(edited); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: talked.ID, InteractionType: InteractionEmail, Timestamp: now}); err != nil {
+		t.Fatalf("failed to log interaction: %v", err)
+	}
+	if err := client.CreateDeal(&Deal{Title: "Pilot", Stage: StageProspecting, ContactID: &onDeal.ID}); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	// Nothing is old enough yet
+	candidates, err := client.StaleContacts(12, now)
+	if err != nil {
+		t.Fatalf("StaleContacts failed: %v", err)
+	}
+	if len(candidates) != 0 {
+		t.Errorf("expected no stale contacts yet, got %d", len(candidates))
+	}
+
+	later := now.AddDate(1, 1, 0)
+	candidates, err = client.StaleContacts(12, later)
+	if err != nil {
+		t.Fatalf("StaleContacts failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Contact.ID != stale.ID || candidates[0].Source != "contacts" {
+		t.Fatalf("expected only the untouched synced contact, got %+v", candidates)
+	}
+
+	policy := &ArchivePolicy{Enabled: true, Months: 12, DryRun: true}
+	if _, archived, err := client.ApplyArchivePolicy(policy, later); err != nil || archived != 0 {
+		t.Fatalf("expected a dry run to archive nothing, got %d, %v", archived, err)
+	}
+
+	policy.DryRun = false
+	if _, archived, err := client.ApplyArchivePolicy(policy, later); err != nil || archived != 1 {
+		t.Fatalf("expected one contact archived, got %d, %v", archived, err)
+	}
+
+	contacts, _ := client.ListContacts(&ContactFilter{})
+	for _, c := range contacts {
+		if c.ID == stale.ID {
+			t.Error("expected the archived contact to be hidden from lists")
+		}
+	}
+	if found, _ := client.FindContactByEmail("stale@example.com"); found == nil || found.ArchivedAt == nil {
+		t.Error("expected email lookup to still find the archived contact")
+	}
+
+	if _, err := client.UnarchiveContact(stale.ID); err != nil {
+		t.Fatalf("UnarchiveContact failed: %v", err)
+	}
+	if candidates, _ := client.StaleContacts(12, later); len(candidates) != 0 {
+		t.Errorf("expected an unarchived contact not to be stale again, got %d", len(candidates))
+	}
+	if _, err := client.UnarchiveContact(stale.ID); err == nil {
+		t.Error("expected unarchiving an active contact to fail")
+	}
+}
@@ -0,0 +1,212 @@
+// ABOUTME: Point-in-time CRM state reconstructed from revision history
+// ABOUTME: Replays each object's revisions up to a date so past pipelines can be listed and compared
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot is the CRM state as of a past time. Objects are taken from the
+// newest revision saved at or before At; objects deleted by then are left out.
+type Snapshot struct {
+	At time.Time
+
+	// Approximate counts objects shown with a newer state than At, because
+	// their older revisions were pruned or predate revision history.
+	Approximate int
+
+	contacts  []*Contact
+	companies []*Company
+	deals     []*Deal
+}
+
+// SnapshotAsOf reconstructs contacts, companies, and deals as of at.
+func (c *Client) SnapshotAsOf(at time.Time) (*Snapshot, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixRevision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	history := make(map[uuid.UUID][]*Revision)
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+		var rev Revision
+		if err := json.Unmarshal(data, &rev); err != nil {
+			continue
+		}
+		history[rev.ObjectID] = append(history[rev.ObjectID], &rev)
+	}
+
+	snapshot := &Snapshot{At: at}
+	states := make(map[string]map[uuid.UUID]json.RawMessage)
+	for _, entityType := range []string{EntityContact, EntityCompany, EntityDeal} {
+		states[entityType] = make(map[uuid.UUID]json.RawMessage)
+	}
+
+	for id, revs := range history {
+		sort.Slice(revs, func(i, j int) bool { return revs[i].Rev < revs[j].Rev })
+		state, ok := states[revs[0].EntityType]
+		if !ok {
+			continue
+		}
+
+		var latest *Revision
+		for _, rev := range revs {
+			if rev.CreatedAt.After(at) {
+				break
+			}
+			latest = rev
+		}
+
+		switch {
+		case latest != nil:
+			if latest.Op != RevisionOpDelete {
+				state[id] = latest.Data
+			}
+		case revs[0].Op != RevisionOpCreate && createdBy(revs[0].Data, at):
+			// The object existed at but its revisions from then were pruned;
+			// the oldest kept revision is the closest state we have
+			state[id] = revs[0].Data
+			snapshot.Approximate++
+		}
+	}
+
+	// Objects written before revision history was kept only have their current state
+	for entityType, prefix := range map[string]string{EntityContact: PrefixContact, EntityCompany: PrefixCompany, EntityDeal: PrefixDeal} {
+		objectKeys, err := c.KeysWithPrefix([]byte(prefix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %ss: %w", entityType, err)
+		}
+		for _, key := range objectKeys {
+			data, err := c.Get(key)
+			if err != nil {
+				continue
+			}
+			var object struct {
+				ID uuid.UUID `json:"id"`
+			}
+			if err := json.Unmarshal(data, &object); err != nil || history[object.ID] != nil {
+				continue
+			}
+			if createdBy(data, at) {
+				states[entityType][object.ID] = data
+				if !updatedBy(data, at) {
+					snapshot.Approximate++
+				}
+			}
+		}
+	}
+
+	for _, data := range states[EntityContact] {
+		var contact Contact
+		if err := json.Unmarshal(data, &contact); err == nil {
+			snapshot.contacts = append(snapshot.contacts, &contact)
+		}
+	}
+	for _, data := range states[EntityCompany] {
+		var company Company
+		if err := json.Unmarshal(data, &company); err == nil {
+			snapshot.companies = append(snapshot.companies, &company)
+		}
+	}
+	for _, data := range states[EntityDeal] {
+		var deal Deal
+		if err := json.Unmarshal(data, &deal); err == nil {
+			snapshot.deals = append(snapshot.deals, &deal)
+		}
+	}
+
+	sort.Slice(snapshot.contacts, func(i, j int) bool {
+		return snapshot.contacts[i].Name < snapshot.contacts[j].Name
+	})
+	sort.Slice(snapshot.companies, func(i, j int) bool {
+		return snapshot.companies[i].Name < snapshot.companies[j].Name
+	})
+	sort.Slice(snapshot.deals, func(i, j int) bool {
+		return snapshot.deals[i].LastActivityAt.After(snapshot.deals[j].LastActivityAt)
+	})
+	return snapshot, nil
+}
+
+// createdBy reports whether a serialized object was created at or before at.
+func createdBy(data []byte, at time.Time) bool {
+	var object struct {
+		CreatedAt time.Time `json:"created_at"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return false
+	}
+	return !object.CreatedAt.After(at)
+}
+
+// updatedBy reports whether a serialized object was last updated at or before at.
+func updatedBy(data []byte, at time.Time) bool {
+	var object struct {
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return false
+	}
+	return !object.UpdatedAt.After(at)
+}
+
+// Contacts returns the snapshot's contacts matching filter, sorted by name.
+func (s *Snapshot) Contacts(filter *ContactFilter) []*Contact {
+	var contacts []*Contact
+	for _, contact := range s.contacts {
+		if filter.Matches(contact) {
+			contacts = append(contacts, contact)
+		}
+	}
+	if filter != nil && filter.Limit > 0 && len(contacts) > filter.Limit {
+		contacts = contacts[:filter.Limit]
+	}
+	return contacts
+}
+
+// Companies returns the snapshot's companies matching filter, sorted by name.
+func (s *Snapshot) Companies(filter *CompanyFilter) []*Company {
+	var companies []*Company
+	for _, company := range s.companies {
+		if filter.Matches(company) {
+			companies = append(companies, company)
+		}
+	}
+	if filter != nil && filter.Limit > 0 && len(companies) > filter.Limit {
+		companies = companies[:filter.Limit]
+	}
+	return companies
+}
+
+// Deals returns the snapshot's deals matching filter, most recent activity first.
+func (s *Snapshot) Deals(filter *DealFilter) []*Deal {
+	var deals []*Deal
+	for _, deal := range s.deals {
+		if filter.Matches(deal) {
+			deals = append(deals, deal)
+		}
+	}
+	if filter != nil && filter.Limit > 0 && len(deals) > filter.Limit {
+		deals = deals[:filter.Limit]
+	}
+	return deals
+}
+
+// FindCompanyByName returns the snapshot's company with this exact name, or nil.
+func (s *Snapshot) FindCompanyByName(name string) *Company {
+	for _, company := range s.companies {
+		if company.Name == name {
+			return company
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Tests for point-in-time snapshots rebuilt from revisions
+// ABOUTME: Verifies updated, created-later, and deleted deals show as they were at the snapshot time
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotAsOf(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme Corp"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	pilot := &Deal{Title: "Pilot", CompanyID: company.ID, CompanyName: company.Name, Stage: StageProspecting, Amount: 1000}
+	dropped := &Deal{Title: "Dropped", CompanyID: company.ID, CompanyName: company.Name, Stage: StageQualification}
+	for _, deal := range []*Deal{pilot, dropped} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	at := time.Now()
+	time.Sleep(2 * time.Millisecond)
+
+	pilot.Stage = StageNegotiation
+	pilot.Amount = 5000
+	if err := client.UpdateDeal(pilot); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if err := client.DeleteDeal(dropped.ID); err != nil {
+		t.Fatalf("DeleteDeal failed: %v", err)
+	}
+	later := &Deal{Title: "Later", CompanyID: company.ID, CompanyName: company.Name, Stage: StageProspecting}
+	if err := client.CreateDeal(later); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	snapshot, err := client.SnapshotAsOf(at)
+	if err != nil {
+		t.Fatalf("SnapshotAsOf failed: %v", err)
+	}
+
+	deals := snapshot.Deals(&DealFilter{})
+	if len(deals) != 2 {
+		t.Fatalf("expected Pilot and Dropped, got %d deals", len(deals))
+	}
+	byTitle := make(map[string]*Deal)
+	for _, deal := range deals {
+		byTitle[deal.Title] = deal
+	}
+	if p := byTitle["Pilot"]; p == nil || p.Stage != StageProspecting || p.Amount != 1000 {
+		t.Errorf("expected Pilot as it was, got %+v", p)
+	}
+	if byTitle["Dropped"] == nil {
+		t.Error("expected the since-deleted deal")
+	}
+	if got := snapshot.Deals(&DealFilter{Stage: StageQualification}); len(got) != 1 {
+		t.Errorf("expected stage filter to match Dropped, got %d", len(got))
+	}
+	if snapshot.FindCompanyByName("Acme Corp") == nil {
+		t.Error("expected the company in the snapshot")
+	}
+	if snapshot.Approximate != 0 {
+		t.Errorf("expected exact history, got %d approximate", snapshot.Approximate)
+	}
+
+	// Before anything was created the pipeline is empty
+	empty, err := client.SnapshotAsOf(company.CreatedAt.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("SnapshotAsOf failed: %v", err)
+	}
+	if len(empty.Deals(nil)) != 0 || len(empty.Companies(nil)) != 0 {
+		t.Error("expected an empty snapshot before creation")
+	}
+}
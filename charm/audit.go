@@ -0,0 +1,169 @@
+// ABOUTME: Append-only audit log of changes to contacts, companies, deals, and relationships
+// ABOUTME: Records who changed what and when, with old and new field values; unlike revisions it is never pruned
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records one change to an object.
+type AuditEntry struct {
+	ID         uuid.UUID     `json:"id"`
+	EntityType string        `json:"entity_type"`
+	EntityID   uuid.UUID     `json:"entity_id"`
+	Op         string        `json:"op"`    // a RevisionOp
+	Actor      string        `json:"actor"` // the entry point that made the change, e.g. mcp
+	Summary    string        `json:"summary,omitempty"`
+	Rev        int           `json:"rev"`               // the revision the change saved
+	Changes    []FieldChange `json:"changes,omitempty"` // updates and restores only
+	Undoes     *uuid.UUID    `json:"undoes,omitempty"`  // the entry this change undid, see UndoChange
+	At         time.Time     `json:"at"`
+}
+
+// FieldChange is a field's value before and after a change. Old is empty for
+// a field the change added, and New for one it removed.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// AuditFilter selects audit log entries.
+type AuditFilter struct {
+	EntityID *uuid.UUID // one object's entries
+	Actor    string     // only changes made through this entry point
+	Since    *time.Time // only changes after this time
+	Limit    int        // the most recent entries only (0 = all)
+}
+
+// ForActor returns a copy of the client whose changes the audit log credits
+// to actor, such as EntryMCP. Changes are credited to EntryCLI by default.
+func (c *Client) ForActor(actor string) *Client {
+	actorClient := *c
+	actorClient.actor = actor
+	return &actorClient
+}
+
+// UndoRev returns the revision to restore to undo the change, or 0 for a
+// create, which is undone by deleting the object. Old revisions are pruned,
+// so it may no longer be stored.
+func (e *AuditEntry) UndoRev() int {
+	switch e.Op {
+	case RevisionOpCreate:
+		return 0
+	case RevisionOpDelete:
+		return e.Rev // the delete saved the object as it was
+	}
+	return e.Rev - 1
+}
+
+func (c *Client) auditActor() string {
+	if c.actor == "" {
+		return EntryCLI
+	}
+	return c.actor
+}
+
+// recordAudit appends an entry for a change saved as revision rev. before is
+// nil for creates, and after for deletes.
+func (c *Client) recordAudit(entityType string, id uuid.UUID, op string, rev int, before, after []byte) error {
+	entry := &AuditEntry{
+		ID:         uuid.New(),
+		EntityType: entityType,
+		EntityID:   id,
+		Op:         op,
+		Actor:      c.auditActor(),
+		Rev:        rev,
+		Undoes:     c.undoing,
+		At:         time.Now(),
+	}
+	if after != nil {
+		entry.Summary = snapshotSummary(after)
+	} else {
+		entry.Summary = snapshotSummary(before)
+	}
+	if before != nil && after != nil {
+		entry.Changes = diffFields(before, after)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	if err := c.Set(AuditKey(id.String(), entry.At, entry.ID.String()), data); err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLog returns the audit entries matching the filter, newest first.
+func (c *Client) ListAuditLog(filter *AuditFilter) ([]*AuditEntry, error) {
+	prefix := PrefixAudit
+	if filter != nil && filter.EntityID != nil {
+		prefix += filter.EntityID.String() + ":"
+	}
+	keys, err := c.KeysWithPrefix([]byte(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*AuditEntry
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		if filter != nil {
+			if filter.Actor != "" && entry.Actor != filter.Actor {
+				continue
+			}
+			if filter.Since != nil && entry.At.Before(*filter.Since) {
+				continue
+			}
+		}
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].At.After(entries[j].At)
+	})
+	if filter != nil && filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
+
+// diffFields returns the top-level fields that differ between two JSON
+// snapshots, sorted, leaving out bookkeepingFields.
+func diffFields(before, after []byte) []FieldChange {
+	var old, updated map[string]json.RawMessage
+	_ = json.Unmarshal(before, &old)
+	_ = json.Unmarshal(after, &updated)
+
+	var changes []FieldChange
+	for field, value := range updated {
+		if !bookkeepingFields[field] && string(old[field]) != string(value) {
+			changes = append(changes, FieldChange{Field: field, Old: old[field], New: value})
+		}
+	}
+	for field, value := range old {
+		if _, ok := updated[field]; !ok && !bookkeepingFields[field] {
+			changes = append(changes, FieldChange{Field: field, Old: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
@@ -0,0 +1,116 @@
+// ABOUTME: Tests for the audit log
+// ABOUTME: Verifies entries record the actor and field changes, survive deletes and pruning, and filter
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLogRecordsChanges(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Enterprise", Amount: 100000, Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	deal.Amount = 0 // omitted when zero, so recorded as a removed field
+	if err := client.ForActor(EntryMCP).UpdateDeal(deal); err != nil {
+		t.Fatalf("failed to update deal: %v", err)
+	}
+	if err := client.DeleteDeal(deal.ID); err != nil {
+		t.Fatalf("failed to delete deal: %v", err)
+	}
+
+	entries, err := client.ListAuditLog(&AuditFilter{EntityID: &deal.ID})
+	if err != nil {
+		t.Fatalf("ListAuditLog failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	deleted, updated, created := entries[0], entries[1], entries[2]
+	if created.Op != RevisionOpCreate || created.Actor != EntryCLI || created.Changes != nil || created.UndoRev() != 0 {
+		t.Errorf("unexpected create entry: %+v", created)
+	}
+	if updated.Op != RevisionOpUpdate || updated.Actor != EntryMCP || updated.Summary != "Enterprise" || updated.UndoRev() != 1 {
+		t.Errorf("unexpected update entry: %+v", updated)
+	}
+	if len(updated.Changes) != 1 || updated.Changes[0].Field != "amount" ||
+		string(updated.Changes[0].Old) != "100000" || updated.Changes[0].New != nil {
+		t.Errorf("expected only the amount change, got %+v", updated.Changes)
+	}
+	if deleted.Op != RevisionOpDelete || deleted.Summary != "Enterprise" || deleted.UndoRev() != deleted.Rev {
+		t.Errorf("unexpected delete entry: %+v", deleted)
+	}
+
+	// Undoing the update through its undo revision restores the amount
+	if _, err := client.RestoreRevision(deal.ID, updated.UndoRev()); err != nil {
+		t.Fatalf("RestoreRevision failed: %v", err)
+	}
+	restored, err := client.GetDeal(deal.ID)
+	if err != nil {
+		t.Fatalf("failed to get deal: %v", err)
+	}
+	if restored.Amount != 100000 {
+		t.Errorf("expected amount 100000, got %d", restored.Amount)
+	}
+}
+
+func TestAuditLogNotPruned(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	for i := 0; i < DefaultRevisionLimit+5; i++ {
+		contact.Notes = time.Now().Format(time.RFC3339Nano)
+		if err := client.UpdateContact(contact); err != nil {
+			t.Fatalf("failed to update contact: %v", err)
+		}
+	}
+
+	entries, err := client.ListAuditLog(&AuditFilter{EntityID: &contact.ID})
+	if err != nil {
+		t.Fatalf("ListAuditLog failed: %v", err)
+	}
+	if len(entries) != DefaultRevisionLimit+6 {
+		t.Errorf("expected %d entries, got %d", DefaultRevisionLimit+6, len(entries))
+	}
+}
+
+func TestAuditLogFilter(t *testing.T) {
+	client := NewTestClient(t)
+
+	if err := client.CreateContact(&Contact{Name: "Alice"}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	if err := client.ForActor(EntryMCP).CreateCompany(&Company{Name: "Acme"}); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	if err := client.ForActor(EntryMCP).CreateCompany(&Company{Name: "Globex"}); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	entries, err := client.ListAuditLog(&AuditFilter{Actor: EntryMCP})
+	if err != nil {
+		t.Fatalf("ListAuditLog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Summary != "Globex" || entries[1].Summary != "Acme" {
+		t.Fatalf("expected the two mcp companies newest first, got %+v", entries)
+	}
+
+	entries, _ = client.ListAuditLog(&AuditFilter{Limit: 1})
+	if len(entries) != 1 || entries[0].Summary != "Globex" {
+		t.Errorf("expected only the newest entry, got %+v", entries)
+	}
+
+	future := time.Now().Add(time.Hour)
+	entries, _ = client.ListAuditLog(&AuditFilter{Since: &future})
+	if len(entries) != 0 {
+		t.Errorf("expected no entries since the future, got %d", len(entries))
+	}
+}
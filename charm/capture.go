@@ -0,0 +1,233 @@
+// ABOUTME: Quick capture of free-form lines into tasks and interactions
+// ABOUTME: Parses #tags, @references, and due: dates, links the referenced records, and saves the result
+
+package charm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Capture kinds.
+const (
+	CaptureTask        = "task"
+	CaptureInteraction = "interaction"
+)
+
+// captureInteractionTags map #tags to the interaction they log.
+var captureInteractionTags = map[string]string{
+	"call":    InteractionCall,
+	"meeting": InteractionMeeting,
+	"met":     InteractionMeeting,
+	"email":   InteractionEmail,
+	"message": InteractionMessage,
+	"msg":     InteractionMessage,
+	"event":   InteractionEvent,
+}
+
+// Capture is a parsed quick-capture line such as
+// "call Dana re: renewal #task @dana due:fri".
+type Capture struct {
+	Kind            string
+	Text            string     // the line without tags, references, and due date
+	InteractionType string     // for interactions
+	Refs            []string   // @references without the @; underscores become spaces
+	DueAt           *time.Time // for tasks
+}
+
+// CaptureResult is what a capture saved.
+type CaptureResult struct {
+	Task         *Task
+	Interactions []*InteractionLog
+}
+
+// ParseCapture parses a quick-capture line. #task or #todo makes a task and
+// #call, #meeting, #email, #message, or #event logs that interaction; a line
+// without either is a task. @name references a contact, company, or deal
+// (use underscores for spaces: @dana_scully), and due: takes today, tomorrow,
+// a weekday, a count of days or weeks (3d, 2w), or YYYY-MM-DD relative to now.
+// Other #tags stay in the text.
+func ParseCapture(line string, now time.Time) (*Capture, error) {
+	capture := &Capture{Kind: CaptureTask}
+	var words []string
+	for _, word := range strings.Fields(line) {
+		lower := strings.ToLower(word)
+		switch {
+		case lower == "#task" || lower == "#todo":
+			capture.Kind = CaptureTask
+		case strings.HasPrefix(lower, "#") && captureInteractionTags[lower[1:]] != "":
+			capture.Kind = CaptureInteraction
+			capture.InteractionType = captureInteractionTags[lower[1:]]
+		case IsNameRef(word):
+			capture.Refs = append(capture.Refs, strings.ReplaceAll(word[1:], "_", " "))
+		case strings.HasPrefix(lower, "due:"):
+			due, err := ParseDue(word[len("due:"):], now)
+			if err != nil {
+				return nil, err
+			}
+			capture.DueAt = &due
+		default:
+			words = append(words, word)
+		}
+	}
+	capture.Text = strings.Join(words, " ")
+
+	if capture.Kind == CaptureInteraction {
+		if capture.DueAt != nil {
+			return nil, fmt.Errorf("due dates are for tasks, not #%s", capture.InteractionType)
+		}
+		if len(capture.Refs) == 0 {
+			return nil, fmt.Errorf("#%s needs an @contact", capture.InteractionType)
+		}
+	} else if capture.Text == "" {
+		return nil, fmt.Errorf("nothing to capture: add a description")
+	}
+	return capture, nil
+}
+
+// ParseDue parses a due date relative to now: today, tomorrow, a weekday name
+// (its next occurrence, today included), 3d or 2w (days or weeks from today),
+// or YYYY-MM-DD. It returns the start of that day.
+func ParseDue(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch value {
+	case "today", "tod":
+		return today, nil
+	case "tomorrow", "tom":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if len(value) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			name := strings.ToLower(day.String())
+			if strings.HasPrefix(name, value) {
+				return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7), nil
+			}
+		}
+	}
+
+	if n := strings.TrimPrefix(value, "+"); len(n) > 1 {
+		count, err := strconv.Atoi(n[:len(n)-1])
+		if err == nil && count >= 0 {
+			switch n[len(n)-1] {
+			case 'd':
+				return today.AddDate(0, 0, count), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*count), nil
+			}
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+	return time.Time{}, Validationf("invalid due date %q (use today, tomorrow, a weekday, 3d, 2w, or YYYY-MM-DD)", value)
+}
+
+// Capture parses a quick-capture line and saves it.
+func (c *Client) Capture(line string) (*CaptureResult, error) {
+	now := time.Now()
+	capture, err := ParseCapture(line, now)
+	if err != nil {
+		return nil, err
+	}
+	return c.ApplyCapture(capture, now)
+}
+
+// ApplyCapture resolves a capture's references and saves it: a task linked to
+// the referenced contact, company, and deal, or one interaction per
+// referenced contact that also updates its last contact date and cadence.
+func (c *Client) ApplyCapture(capture *Capture, now time.Time) (*CaptureResult, error) {
+	task := &Task{Title: capture.Text, DueAt: capture.DueAt}
+	var contacts []*Contact
+	for _, ref := range capture.Refs {
+		id, err := c.ResolveRef("@"+ref, EntityContact, EntityCompany, EntityDeal)
+		if err != nil {
+			return nil, err
+		}
+		if id == uuid.Nil {
+			return nil, fmt.Errorf("no contact, company, or deal matches @%s", ref)
+		}
+
+		if contact, err := c.GetContact(id); err == nil && contact != nil {
+			contacts = append(contacts, contact)
+			task.ContactID, task.ContactName = &contact.ID, contact.Name
+			continue
+		}
+		if capture.Kind == CaptureInteraction {
+			return nil, fmt.Errorf("@%s is not a contact", ref)
+		}
+		if company, err := c.GetCompany(id); err == nil && company != nil {
+			task.CompanyID, task.CompanyName = &company.ID, company.Name
+			continue
+		}
+		if deal, err := c.GetDeal(id); err == nil && deal != nil {
+			task.DealID, task.DealTitle = &deal.ID, deal.Title
+		}
+	}
+
+	if capture.Kind == CaptureTask {
+		if err := c.CreateTask(task); err != nil {
+			return nil, err
+		}
+		return &CaptureResult{Task: task}, nil
+	}
+
+	result := &CaptureResult{}
+	for _, contact := range contacts {
+		interaction := &InteractionLog{
+			ContactID:       contact.ID,
+			ContactName:     contact.Name,
+			InteractionType: capture.InteractionType,
+			Timestamp:       now,
+			Notes:           capture.Text,
+		}
+		if err := c.CreateInteractionLog(interaction); err != nil {
+			return result, fmt.Errorf("failed to log interaction: %w", err)
+		}
+		contact.LastContactedAt = &now
+		if err := c.UpdateContact(contact); err != nil {
+			return result, fmt.Errorf("failed to update contact: %w", err)
+		}
+		if err := c.UpdateCadenceAfterInteraction(contact.ID, now); err != nil {
+			return result, fmt.Errorf("failed to update cadence: %w", err)
+		}
+		result.Interactions = append(result.Interactions, interaction)
+	}
+	return result, nil
+}
+
+// Summary describes what was saved in one line.
+func (r *CaptureResult) Summary() string {
+	if r.Task != nil {
+		var links []string
+		for _, name := range []string{r.Task.ContactName, r.Task.CompanyName, r.Task.DealTitle} {
+			if name != "" {
+				links = append(links, name)
+			}
+		}
+		if r.Task.DueAt != nil {
+			links = append(links, "due "+r.Task.DueAt.Format("Mon Jan 2"))
+		}
+		if len(links) == 0 {
+			return "Task added: " + r.Task.Title
+		}
+		return fmt.Sprintf("Task added: %s (%s)", r.Task.Title, strings.Join(links, ", "))
+	}
+
+	names := make([]string, len(r.Interactions))
+	for i, interaction := range r.Interactions {
+		names[i] = interaction.ContactName
+	}
+	interactionType := "interaction"
+	if len(r.Interactions) > 0 {
+		interactionType = r.Interactions[0].InteractionType
+	}
+	return fmt.Sprintf("Logged %s with %s", interactionType, strings.Join(names, ", "))
+}
@@ -0,0 +1,131 @@
+// ABOUTME: Tests for quick capture
+// ABOUTME: Verifies parsing of tags, references, and due dates, and saving tasks and interactions
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	now := time.Date(2024, 6, 5, 15, 30, 0, 0, time.UTC) // a Wednesday
+	tests := map[string]string{
+		"today":      "2024-06-05",
+		"tomorrow":   "2024-06-06",
+		"fri":        "2024-06-07",
+		"Wednesday":  "2024-06-05",
+		"mon":        "2024-06-10",
+		"3d":         "2024-06-08",
+		"+2w":        "2024-06-19",
+		"2024-07-01": "2024-07-01",
+	}
+	for value, want := range tests {
+		got, err := ParseDue(value, now)
+		if err != nil {
+			t.Errorf("ParseDue(%q) failed: %v", value, err)
+			continue
+		}
+		if got.Format("2006-01-02") != want || got.Hour() != 0 {
+			t.Errorf("ParseDue(%q) = %s, want %s", value, got, want)
+		}
+	}
+	for _, value := range []string{"someday", "fr", "3x", ""} {
+		if _, err := ParseDue(value, now); err == nil {
+			t.Errorf("expected ParseDue(%q) to fail", value)
+		}
+	}
+}
+
+func TestParseCapture(t *testing.T) {
+	now := time.Date(2024, 6, 5, 15, 30, 0, 0, time.UTC)
+
+	capture, err := ParseCapture("call Dana re: renewal #task @dana due:fri", now)
+	if err != nil {
+		t.Fatalf("ParseCapture failed: %v", err)
+	}
+	if capture.Kind != CaptureTask || capture.Text != "call Dana re: renewal" {
+		t.Errorf("unexpected capture: %+v", capture)
+	}
+	if len(capture.Refs) != 1 || capture.Refs[0] != "dana" {
+		t.Errorf("expected @dana, got %v", capture.Refs)
+	}
+	if capture.DueAt == nil || capture.DueAt.Format("2006-01-02") != "2024-06-07" {
+		t.Errorf("expected due Friday, got %v", capture.DueAt)
+	}
+
+	capture, err = ParseCapture("talked pricing #Call @dana_scully #renewal", now)
+	if err != nil {
+		t.Fatalf("ParseCapture failed: %v", err)
+	}
+	if capture.Kind != CaptureInteraction || capture.InteractionType != InteractionCall {
+		t.Errorf("expected a call interaction, got %+v", capture)
+	}
+	if capture.Text != "talked pricing #renewal" || capture.Refs[0] != "dana scully" {
+		t.Errorf("unexpected text or refs: %+v", capture)
+	}
+
+	for _, line := range []string{"#call talked pricing", "#task @dana", "#meeting @dana due:fri", "call due:never"} {
+		if _, err := ParseCapture(line, now); err == nil {
+			t.Errorf("expected ParseCapture(%q) to fail", line)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	client := NewTestClient(t)
+
+	dana := &Contact{Name: "Dana Scully", Email: "dana@fbi.gov"}
+	if err := client.CreateContact(dana); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	acme := &Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	result, err := client.Capture("call re: renewal #task @dana @acme due:tomorrow")
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	task := result.Task
+	if task == nil || task.ContactID == nil || *task.ContactID != dana.ID || task.CompanyID == nil || *task.CompanyID != acme.ID {
+		t.Fatalf("expected a task linked to Dana and Acme, got %+v", task)
+	}
+	tasks, err := client.ListTasks(false)
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "call re: renewal" {
+		t.Fatalf("expected the task to be listed, got %v, %v", tasks, err)
+	}
+
+	if _, err := client.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if tasks, _ := client.ListTasks(false); len(tasks) != 0 {
+		t.Errorf("expected no open tasks, got %d", len(tasks))
+	}
+	if tasks, _ := client.ListTasks(true); len(tasks) != 1 || tasks[0].DoneAt == nil {
+		t.Errorf("expected the done task with --all, got %v", tasks)
+	}
+
+	result, err = client.Capture("wants a quote #call @scully")
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	if len(result.Interactions) != 1 || result.Interactions[0].ContactID != dana.ID {
+		t.Fatalf("expected a call with Dana, got %+v", result.Interactions)
+	}
+	if got := result.Summary(); got != "Logged call with Dana Scully" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+	cadence, err := client.GetContactCadence(dana.ID)
+	if err != nil || cadence == nil || cadence.LastInteractionDate == nil {
+		t.Errorf("expected the call to update Dana's cadence, got %+v, %v", cadence, err)
+	}
+
+	if _, err := client.Capture("#call @acme"); err == nil {
+		t.Error("expected a call with a company to fail")
+	}
+	if _, err := client.Capture("lunch #task @nobody"); err == nil {
+		t.Error("expected an unknown reference to fail")
+	}
+}
@@ -39,7 +39,7 @@ func (c *Client) DeleteCompanyWithCascade(id uuid.UUID) error {
 }
 
 // DeleteContactWithCascade deletes a contact and all related entities
-// Cascades: relationships, interaction logs, cadence settings.
+// Cascades: relationships, interaction logs, cadence settings, deal roles, tracked emails, event attendance, primary contact.
 func (c *Client) DeleteContactWithCascade(id uuid.UUID) error {
 	// 1. Delete all relationships involving this contact
 	rels, err := c.ListRelationshipsForContact(id)
@@ -79,12 +79,70 @@ func (c *Client) DeleteContactWithCascade(id uuid.UUID) error {
 		}
 	}
 
-	// 5. Delete the contact itself
+	// 5. Clear the referrer on deals this contact referred
+	referred, err := c.ListDeals(&DealFilter{ReferrerID: &id})
+	if err != nil {
+		return err
+	}
+	for _, deal := range referred {
+		deal.ReferrerID = nil
+		deal.ReferrerName = ""
+		if err := c.UpdateDeal(deal); err != nil {
+			return err
+		}
+	}
+
+	// 6. Remove the contact from any deals they hold a role on
+	roles, err := c.ListContactDealRoles(id)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if err := c.DeleteDealContact(role.DealID, id); err != nil {
+			return err
+		}
+	}
+
+	// 7. Delete tracked emails sent to this contact
+	emails, err := c.ListTrackedEmails(&id)
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		if err := c.Delete(TrackedEmailKey(email.ID.String())); err != nil {
+			return err
+		}
+	}
+
+	// 8. Remove the contact from events they attended
+	attended, err := c.ListContactEvents(id)
+	if err != nil {
+		return err
+	}
+	for _, a := range attended {
+		if err := c.RemoveAttendance(a.EventID, id); err != nil {
+			return err
+		}
+	}
+
+	// 9. Clear the contact as primary contact of any company
+	primaryAt, err := c.companiesByPrimaryContact()
+	if err != nil {
+		return err
+	}
+	for _, company := range primaryAt[id] {
+		company.SetPrimaryContact(nil)
+		if err := c.UpdateCompany(company); err != nil {
+			return err
+		}
+	}
+
+	// 10. Delete the contact itself
 	return c.DeleteContact(id)
 }
 
 // DeleteDealWithCascade deletes a deal and all related entities
-// Cascades: deal notes.
+// Cascades: deal notes, deal contact roles.
 func (c *Client) DeleteDealWithCascade(id uuid.UUID) error {
 	// 1. Delete all notes for this deal
 	notes, err := c.ListDealNotes(id)
@@ -97,7 +155,18 @@ func (c *Client) DeleteDealWithCascade(id uuid.UUID) error {
 		}
 	}
 
-	// 2. Delete the deal itself
+	// 2. Delete contact roles on this deal
+	roles, err := c.ListDealContacts(id)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if err := c.DeleteDealContact(id, role.ContactID); err != nil {
+			return err
+		}
+	}
+
+	// 3. Delete the deal itself
 	return c.DeleteDeal(id)
 }
 
@@ -190,6 +259,66 @@ func (c *Client) UpdateContactDenormalizedNames(contactID uuid.UUID, newName str
 		}
 	}
 
+	// Update deals this contact referred
+	referred, err := c.ListDeals(&DealFilter{ReferrerID: &contactID})
+	if err != nil {
+		return err
+	}
+	for _, deal := range referred {
+		deal.ReferrerName = newName
+		if err := c.UpdateDeal(deal); err != nil {
+			return err
+		}
+	}
+
+	// Update deal roles
+	roles, err := c.ListContactDealRoles(contactID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		role.ContactName = newName
+		if err := c.SaveDealContact(role); err != nil {
+			return err
+		}
+	}
+
+	// Update tracked emails
+	emails, err := c.ListTrackedEmails(&contactID)
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		email.ContactName = newName
+		if err := c.saveTrackedEmail(email); err != nil {
+			return err
+		}
+	}
+
+	// Update event attendance
+	attended, err := c.ListContactEvents(contactID)
+	if err != nil {
+		return err
+	}
+	for _, a := range attended {
+		a.ContactName = newName
+		if err := c.saveEventAttendee(a); err != nil {
+			return err
+		}
+	}
+
+	// Update companies this contact is the primary contact at
+	primaryAt, err := c.companiesByPrimaryContact()
+	if err != nil {
+		return err
+	}
+	for _, company := range primaryAt[contactID] {
+		company.PrimaryContactName = newName
+		if err := c.UpdateCompany(company); err != nil {
+			return err
+		}
+	}
+
 	// Update cadence
 	cadence, err := c.GetContactCadence(contactID)
 	if err == nil && cadence != nil {
@@ -219,5 +348,17 @@ func (c *Client) UpdateDealDenormalizedNames(dealID uuid.UUID, newTitle string)
 		}
 	}
 
+	// Update deal roles
+	roles, err := c.ListDealContacts(dealID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		role.DealTitle = newTitle
+		if err := c.SaveDealContact(role); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ package charm
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/charm/kv"
 )
@@ -52,6 +53,7 @@ func SyncLinkCommand(args []string) error {
 // SyncStatusCommand shows current sync configuration and status.
 func SyncStatusCommand(args []string) error {
 	fs := flag.NewFlagSet("sync status", flag.ExitOnError)
+	staleAfter := fs.Duration("stale-after", DefaultSyncStaleAfter, "Reset services stuck syncing for longer than this")
 	_ = fs.Parse(args)
 
 	cfg, err := LoadConfig()
@@ -59,12 +61,13 @@ func SyncStatusCommand(args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	return showSyncStatus(cfg)
+	return showSyncStatus(cfg, *staleAfter)
 }
 
-func showSyncStatus(cfg *Config) error {
+func showSyncStatus(cfg *Config, staleAfter time.Duration) error {
 	fmt.Println("Charm Sync Status")
 	fmt.Println("─────────────────")
+	fmt.Printf("Profile:   %s\n", ActiveProfile())
 	fmt.Printf("Server:    %s\n", cfg.Host)
 	fmt.Printf("Auto-sync: %v\n", cfg.AutoSync)
 
@@ -91,12 +94,50 @@ func showSyncStatus(cfg *Config) error {
 		fmt.Printf("Keys:      %d\n", len(keys))
 	}
 
+	showServiceSyncStates(c, staleAfter)
+
 	fmt.Println("\nCharm uses SSH keys for authentication - no login required!")
 	fmt.Println("Sync happens automatically in the background.")
 
 	return nil
 }
 
+// showServiceSyncStates prints each service's sync state, first resetting
+// any stuck syncing for longer than staleAfter so 'pagen sync reset' isn't
+// needed.
+func showServiceSyncStates(c *Client, staleAfter time.Duration) {
+	reset, err := c.ResetStaleSyncStates(staleAfter, time.Now())
+	if err != nil {
+		fmt.Printf("\n⚠ Stale sync check failed: %v\n", err)
+	}
+	for _, state := range reset {
+		fmt.Printf("\n⚠ %s was stuck syncing since %s; reset it so the next sync retries\n",
+			state.Service, state.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	states, err := c.ListSyncStates()
+	if err != nil || len(states) == 0 {
+		return
+	}
+	fmt.Println("\nServices:")
+	for _, state := range states {
+		line := state.Status
+		switch state.Status {
+		case SyncStatusSyncing:
+			line = fmt.Sprintf("syncing (since %s)", state.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		case SyncStatusError:
+			if state.ErrorMessage != "" {
+				line = "error: " + state.ErrorMessage
+			}
+		default:
+			if state.LastSyncTime != nil {
+				line = fmt.Sprintf("%s (last synced %s)", state.Status, state.LastSyncTime.Local().Format("2006-01-02 15:04"))
+			}
+		}
+		fmt.Printf("  %-10s %s\n", state.Service, line)
+	}
+}
+
 // SyncUnlinkCommand disconnects this device from the Charm account
 // Note: Charm doesn't provide a direct "unlink" API - users should remove
 // SSH keys from their Charm account to fully unlink.
@@ -214,7 +255,7 @@ func SyncRepairCommand(args []string) error {
 
 	fmt.Println("Running database repair...")
 
-	result, err := kv.Repair(AppName, *force)
+	result, err := kv.Repair(ProfileDBName(ActiveProfile()), *force)
 	if err != nil {
 		return fmt.Errorf("repair failed: %w", err)
 	}
@@ -257,7 +298,7 @@ func SyncResetCommand(args []string) error {
 		return nil
 	}
 
-	if err := kv.Reset(AppName); err != nil {
+	if err := kv.Reset(ProfileDBName(ActiveProfile())); err != nil {
 		return fmt.Errorf("reset failed: %w", err)
 	}
 
@@ -289,7 +330,7 @@ func SyncWipeDBCommand(args []string) error {
 		return nil
 	}
 
-	result, err := kv.Wipe(AppName)
+	result, err := kv.Wipe(ProfileDBName(ActiveProfile()))
 	if err != nil {
 		return fmt.Errorf("wipe failed: %w", err)
 	}
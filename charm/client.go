@@ -4,23 +4,34 @@
 package charm
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/charmbracelet/charm/client"
 	"github.com/charmbracelet/charm/kv"
 	charmproto "github.com/charmbracelet/charm/proto"
+	"github.com/google/uuid"
 )
 
 // Client holds configuration for KV operations.
 // Unlike the previous implementation, it does NOT hold a persistent connection.
 // Each operation opens the database, performs the operation, and closes it.
 type Client struct {
+	profile        string
 	dbName         string
 	autoSync       bool
 	staleThreshold time.Duration
-	testClient     *testClient // Used for testing without server dependency
+	revisionLimit  int
+	testClient     *testClient
+
+	stageRequirements map[string][]string // Used for testing without server dependency
+
+	observer QueryObserver // optional KV timing hook, see SetQueryObserver
+	actor    string        // who the audit log credits writes to, see ForActor
+	undoing  *uuid.UUID    // the audit entry being undone, see UndoChange
 }
 
 // Option configures a Client.
@@ -54,10 +65,23 @@ func NewClient(opts ...Option) (*Client, error) {
 		}
 	}
 
+	// Format amounts for the configured locale unless overridden by the environment
+	if cfg.Locale != "" && os.Getenv("PAGEN_LOCALE") == "" {
+		SetMoneyLocale(cfg.Locale)
+	}
+	if cfg.IDDisplay != "" && os.Getenv("PAGEN_ID_DISPLAY") == "" {
+		SetIDDisplay(cfg.IDDisplay)
+	}
+
+	profile := ActiveProfile()
 	c := &Client{
-		dbName:         AppName,
+		profile:        profile,
+		dbName:         ProfileDBName(profile),
 		autoSync:       cfg.AutoSync,
 		staleThreshold: cfg.StaleThreshold,
+		revisionLimit:  cfg.RevisionLimit,
+
+		stageRequirements: cfg.StageRequirements,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -66,7 +90,8 @@ func NewClient(opts ...Option) (*Client, error) {
 }
 
 // Get retrieves a value by key (read-only, no lock contention).
-func (c *Client) Get(key []byte) ([]byte, error) {
+func (c *Client) Get(key []byte) (val []byte, err error) {
+	defer c.observe("get", key, time.Now(), &err)
 	if c.testClient != nil {
 		return c.testClient.Get(key)
 	}
@@ -75,8 +100,7 @@ func (c *Client) Get(key []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	var val []byte
-	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+	err = kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
 		var err error
 		val, err = k.Get(key)
 		return err
@@ -85,7 +109,8 @@ func (c *Client) Get(key []byte) ([]byte, error) {
 }
 
 // Set stores a value with the given key.
-func (c *Client) Set(key, value []byte) error {
+func (c *Client) Set(key, value []byte) (err error) {
+	defer c.observe("set", key, time.Now(), &err)
 	if c.testClient != nil {
 		return c.testClient.Set(key, value)
 	}
@@ -95,14 +120,46 @@ func (c *Client) Set(key, value []byte) error {
 			return err
 		}
 		if c.autoSync {
-			return k.Sync()
+			return WithCode(CodeSyncUnavailable, k.Sync())
+		}
+		return nil
+	})
+}
+
+// update replaces the value at key with fn's result while holding the
+// database's write lock, so no other write lands between reading the stored
+// value and replacing it. fn gets nil when key doesn't exist.
+func (c *Client) update(key []byte, fn func(stored []byte) ([]byte, error)) (err error) {
+	defer c.observe("set", key, time.Now(), &err)
+	if c.testClient != nil {
+		return c.testClient.Update(key, fn)
+	}
+
+	return kv.Do(c.dbName, func(k *kv.KV) error {
+		stored, err := k.Get(key)
+		if errors.Is(err, kv.ErrMissingKey) {
+			stored, err = nil, nil
+		}
+		if err != nil {
+			return err
+		}
+		value, err := fn(stored)
+		if err != nil {
+			return err
+		}
+		if err := k.Set(key, value); err != nil {
+			return err
+		}
+		if c.autoSync {
+			return WithCode(CodeSyncUnavailable, k.Sync())
 		}
 		return nil
 	})
 }
 
 // Delete removes a key.
-func (c *Client) Delete(key []byte) error {
+func (c *Client) Delete(key []byte) (err error) {
+	defer c.observe("delete", key, time.Now(), &err)
 	if c.testClient != nil {
 		return c.testClient.Delete(key)
 	}
@@ -112,14 +169,15 @@ func (c *Client) Delete(key []byte) error {
 			return err
 		}
 		if c.autoSync {
-			return k.Sync()
+			return WithCode(CodeSyncUnavailable, k.Sync())
 		}
 		return nil
 	})
 }
 
 // Keys returns all keys in the database.
-func (c *Client) Keys() ([][]byte, error) {
+func (c *Client) Keys() (keys [][]byte, err error) {
+	defer c.observe("keys", nil, time.Now(), &err)
 	if c.testClient != nil {
 		return c.testClient.Keys()
 	}
@@ -128,8 +186,7 @@ func (c *Client) Keys() ([][]byte, error) {
 		return nil, err
 	}
 
-	var keys [][]byte
-	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+	err = kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
 		var err error
 		keys, err = k.Keys()
 		return err
@@ -138,7 +195,8 @@ func (c *Client) Keys() ([][]byte, error) {
 }
 
 // KeysWithPrefix returns all keys starting with the given prefix.
-func (c *Client) KeysWithPrefix(prefix []byte) ([][]byte, error) {
+func (c *Client) KeysWithPrefix(prefix []byte) (matched [][]byte, err error) {
+	defer c.observe("scan", prefix, time.Now(), &err)
 	if c.testClient != nil {
 		return c.testClient.KeysWithPrefix(prefix)
 	}
@@ -148,7 +206,7 @@ func (c *Client) KeysWithPrefix(prefix []byte) ([][]byte, error) {
 	}
 
 	var keys [][]byte
-	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+	err = kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
 		var err error
 		keys, err = k.Keys()
 		return err
@@ -157,7 +215,6 @@ func (c *Client) KeysWithPrefix(prefix []byte) ([][]byte, error) {
 		return nil, err
 	}
 
-	var matched [][]byte
 	for _, k := range keys {
 		if len(k) >= len(prefix) && string(k[:len(prefix)]) == string(prefix) {
 			matched = append(matched, k)
@@ -168,7 +225,8 @@ func (c *Client) KeysWithPrefix(prefix []byte) ([][]byte, error) {
 
 // DoReadOnly executes a function with read-only database access.
 // Use this for batch read operations that need multiple Gets.
-func (c *Client) DoReadOnly(fn func(k *kv.KV) error) error {
+func (c *Client) DoReadOnly(fn func(k *kv.KV) error) (err error) {
+	defer c.observe("batch_read", nil, time.Now(), &err)
 	if c.testClient != nil {
 		// For test client, we don't have a real KV to pass
 		// This is okay because test code should use the individual methods
@@ -184,7 +242,8 @@ func (c *Client) DoReadOnly(fn func(k *kv.KV) error) error {
 
 // Do executes a function with write access to the database.
 // Use this for batch write operations.
-func (c *Client) Do(fn func(k *kv.KV) error) error {
+func (c *Client) Do(fn func(k *kv.KV) error) (err error) {
+	defer c.observe("batch_write", nil, time.Now(), &err)
 	if c.testClient != nil {
 		// For test client, we don't have a real KV to pass
 		return fmt.Errorf("Do not supported with test client")
@@ -194,7 +253,7 @@ func (c *Client) Do(fn func(k *kv.KV) error) error {
 			return err
 		}
 		if c.autoSync {
-			return k.Sync()
+			return WithCode(CodeSyncUnavailable, k.Sync())
 		}
 		return nil
 	})
@@ -206,7 +265,7 @@ func (c *Client) Sync() error {
 		return nil // No-op for test client
 	}
 	return kv.Do(c.dbName, func(k *kv.KV) error {
-		return k.Sync()
+		return WithCode(CodeSyncUnavailable, k.Sync())
 	})
 }
 
@@ -242,7 +301,7 @@ func (c *Client) SyncIfStale() error {
 		return nil // No-op for test client
 	}
 	return kv.Do(c.dbName, func(k *kv.KV) error {
-		return k.SyncIfStale(c.staleThreshold)
+		return WithCode(CodeSyncUnavailable, k.SyncIfStale(c.staleThreshold))
 	})
 }
 
@@ -298,12 +357,46 @@ func (c *Client) Config() *Config {
 	return cfg
 }
 
+// Profile returns the name of the profile whose data the client reads.
+func (c *Client) Profile() string {
+	return c.profile
+}
+
 // IsConnected checks if the client can connect to charm cloud.
 func (c *Client) IsConnected() bool {
 	_, err := c.ID()
 	return err == nil
 }
 
+// DatabasePath returns the path of the local SQLite file backing the store.
+func (c *Client) DatabasePath() (string, error) {
+	cc, err := client.NewClientWithDefaults()
+	if err != nil {
+		return "", err
+	}
+	dataDir, err := cc.DataPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "kv", c.dbName+".db"), nil
+}
+
+// Doctor checks the live database: integrity, writes not yet synced, the
+// WAL file, and whether another process holds the sync lock. Unlike
+// DoReadOnly it doesn't sync first.
+func (c *Client) Doctor() (*kv.DoctorResult, error) {
+	if c.testClient != nil {
+		return nil, fmt.Errorf("Doctor not supported with test client")
+	}
+	var result *kv.DoctorResult
+	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+		var err error
+		result, err = k.Doctor()
+		return err
+	})
+	return result, err
+}
+
 // --- Legacy compatibility layer ---
 // These functions maintain backwards compatibility with existing code.
 
@@ -7,9 +7,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/adrg/xdg"
 	"github.com/charmbracelet/charm/kv"
 )
 
@@ -34,6 +34,167 @@ type Config struct {
 
 	// StaleThreshold is the duration before data is considered stale and needs a sync
 	StaleThreshold time.Duration `json:"stale_threshold,omitempty"`
+
+	// RevisionLimit is how many revisions are kept per object (default: 10)
+	RevisionLimit int `json:"revision_limit,omitempty"`
+
+	// StageRequirements lists deal fields that must be set before a deal enters a stage
+	// e.g. {"proposal": ["amount", "expected_close_date"], "closed_lost": ["close_reason"]}
+	StageRequirements map[string][]string `json:"stage_requirements,omitempty"`
+
+	// StageTimers act on open deals that sit in a stage too long, checked by `pagen watch run` and the background jobs
+	// e.g. {"proposal": {"days": 14, "actions": ["task", "flag"]}, "negotiation": {"days": 21, "actions": ["notify"]}}
+	StageTimers map[string]StageTimer `json:"stage_timers,omitempty"`
+
+	// Holidays are dates greeted in `pagen greetings`, each for contacts in the listed countries (all contacts when empty)
+	Holidays []Holiday `json:"holidays,omitempty"`
+
+	// MetAnniversaries are the years after first meeting a contact that `pagen greetings` and the digest
+	// remind you of, keyed by tag with "*" for everyone else, e.g. {"vip": [1, 2, 5, 10], "*": [1]}.
+	// A tag with no years turns the reminders off for its contacts (default: {"*": [1]})
+	MetAnniversaries map[string][]int `json:"met_anniversaries,omitempty"`
+
+	// GreetingTemplates override the default greeting drafts, keyed by kind (birthday, anniversary, met, holiday)
+	GreetingTemplates map[string]string `json:"greeting_templates,omitempty"`
+
+	// EmailTemplates override or add outreach email templates for `pagen crm email`, keyed by name
+	EmailTemplates map[string]EmailTemplate `json:"email_templates,omitempty"`
+
+	// EmailSender is the name outreach emails are signed with ({{.Sender}} in templates)
+	EmailSender string `json:"email_sender,omitempty"`
+
+	// ReplyWaitDays is how long a sent email waits for the contact's reply before it
+	// becomes a pending follow-up in `pagen followups pending-replies` (default: 3)
+	ReplyWaitDays int `json:"reply_wait_days,omitempty"`
+
+	// RelationshipRevalidateDays is how long a relationship between contacts goes unconfirmed before
+	// the digest asks whether it still holds; its strength also fades a level each period (default: 180)
+	RelationshipRevalidateDays int `json:"relationship_revalidate_days,omitempty"`
+
+	// OutreachGoals are weekly targets for how many contacts with a tag to get in touch with,
+	// tracked in `pagen followups goals`, e.g. {"vip": 5}
+	OutreachGoals map[string]int `json:"outreach_goals,omitempty"`
+
+	// ArchivePolicy auto-archives stale contacts created by sync (off when nil)
+	ArchivePolicy *ArchivePolicy `json:"archive_policy,omitempty"`
+
+	// MaintainAfter runs `pagen db maintain` from the background jobs once syncs have imported
+	// at least this many records since it last ran (0 disables)
+	MaintainAfter int `json:"maintain_after,omitempty"`
+
+	// News configures the company news enrichment job (`pagen news fetch`)
+	News *NewsConfig `json:"news,omitempty"`
+
+	// DuplicateEmails sets what creating a contact with a taken email does, per entry point
+	// (cli, mcp, tui, or default): allow, return the existing contact, or reject. MCP returns by default
+	// e.g. {"default": "reject", "mcp": "return"}
+	DuplicateEmails map[string]string `json:"duplicate_emails,omitempty"`
+
+	// StrictResolution makes deal creation refuse to guess between contacts or companies
+	// sharing a name and list the candidates instead (PAGEN_STRICT_RESOLUTION overrides)
+	StrictResolution bool `json:"strict_resolution,omitempty"`
+
+	// GeocodeURL is the Nominatim-compatible endpoint for `pagen viz places --geocode` (default: OpenStreetMap)
+	GeocodeURL string `json:"geocode_url,omitempty"`
+
+	// CompanyLogos lets the web UI fetch logos it hasn't cached; otherwise it shows cached logos or initials
+	CompanyLogos bool `json:"company_logos,omitempty"`
+
+	// LogoURL is a logo API tried before the site's favicon, with {domain} replaced, e.g. "https://logo.clearbit.com/{domain}"
+	LogoURL string `json:"logo_url,omitempty"`
+
+	// EnrichmentAPI is an optional person lookup used by `pagen crm enrich` and the enrich_contact MCP tool
+	EnrichmentAPI *EnrichmentAPIConfig `json:"enrichment_api,omitempty"`
+
+	// Transcription turns voice memos into interaction notes for `pagen followups log --audio` (off when nil)
+	Transcription *TranscriptionConfig `json:"transcription,omitempty"`
+
+	// EmailTracking enables open/click tracking endpoints on the web server (off by default)
+	EmailTracking bool `json:"email_tracking,omitempty"`
+
+	// TrackingBaseURL is the public URL of the web server used in tracking links
+	TrackingBaseURL string `json:"tracking_base_url,omitempty"`
+
+	// IDDisplay shows IDs in lists as "short" prefixes (default) or "full" UUIDs (PAGEN_ID_DISPLAY overrides)
+	IDDisplay string `json:"id_display,omitempty"`
+
+	// Locale controls how amounts are formatted, e.g. "de" or "fr_FR" (PAGEN_LOCALE overrides)
+	Locale string `json:"locale,omitempty"`
+
+	// WebMaintenance makes the web server answer 503 to every request; a running server picks it up
+	WebMaintenance bool `json:"web_maintenance,omitempty"`
+
+	// WebMaintenanceMessage is shown on the maintenance page
+	WebMaintenanceMessage string `json:"web_maintenance_message,omitempty"`
+
+	// WebBaseURL is the public URL of the web UI when served under a subpath behind a
+	// reverse proxy, e.g. "https://home.example.com/pagen/" (only the path is used for routing)
+	WebBaseURL string `json:"web_base_url,omitempty"`
+
+	// WebAuth protects the web UI with a password and/or OIDC login (open when nil)
+	WebAuth *WebAuthConfig `json:"web_auth,omitempty"`
+
+	// WebSharing serves read-only share pages at /share/<token> without login (off by default)
+	WebSharing bool `json:"web_sharing,omitempty"`
+
+	// WebSlowQueryThreshold is how long a KV operation may take before the web server logs it as slow (default: 100ms)
+	WebSlowQueryThreshold time.Duration `json:"web_slow_query_threshold,omitempty"`
+
+	// envRestores undo environment overrides when saving, see applyEnvOverrides
+	envRestores []func(dst *Config)
+}
+
+// EnrichmentAPIConfig is an HTTP API that answers with a JSON object about a
+// person, such as a People Data Labs or Apollo style lookup.
+type EnrichmentAPIConfig struct {
+	// URL has {email}, {name}, and {domain} replaced, e.g. "https://api.example.com/v1/person?email={email}"
+	URL string `json:"url"`
+
+	// APIKeyEnv names the environment variable holding the key, sent as a Bearer token (default: PAGEN_ENRICHMENT_API_KEY)
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+
+	// Fields maps response fields to metadata keys, e.g. {"linkedin": "linkedin_url", "job.title": "api_title"}
+	// Dotted names reach into nested objects. Default: every top-level text, number, or boolean field, under its own name
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// TranscriptionConfig runs a local speech-to-text command, or calls a hosted
+// API when no command is set.
+type TranscriptionConfig struct {
+	// Command runs through sh with {file} replaced by the quoted audio path, and its output is the transcript
+	// e.g. "whisper-cli -m ~/models/ggml-base.en.bin -f {file} -nt -np"
+	Command string `json:"command,omitempty"`
+
+	// URL is an OpenAI-compatible audio transcription endpoint, e.g. "https://api.openai.com/v1/audio/transcriptions"
+	URL   string `json:"url,omitempty"`
+	Model string `json:"model,omitempty"` // default: whisper-1
+
+	// APIKeyEnv names the environment variable holding the API key (default: PAGEN_TRANSCRIPTION_API_KEY)
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// WebAuthConfig configures web UI login. Password and OIDC login can be enabled together.
+type WebAuthConfig struct {
+	Username     string `json:"username,omitempty"`
+	PasswordHash string `json:"password_hash,omitempty"` // bcrypt
+
+	OIDCIssuer        string   `json:"oidc_issuer,omitempty"`
+	OIDCClientID      string   `json:"oidc_client_id,omitempty"`
+	OIDCClientSecret  string   `json:"oidc_client_secret,omitempty"`
+	OIDCAllowedEmails []string `json:"oidc_allowed_emails,omitempty"` // addresses, or "@domain" for a whole domain
+
+	// SessionSecret signs login cookies (hex); regenerate it to sign everyone out
+	SessionSecret string `json:"session_secret,omitempty"`
+}
+
+// PasswordEnabled reports whether username/password login is configured.
+func (a *WebAuthConfig) PasswordEnabled() bool {
+	return a != nil && a.Username != "" && a.PasswordHash != ""
+}
+
+// OIDCEnabled reports whether OIDC login is configured.
+func (a *WebAuthConfig) OIDCEnabled() bool {
+	return a != nil && a.OIDCIssuer != "" && a.OIDCClientID != ""
 }
 
 // DefaultConfig returns a new config with sensible defaults.
@@ -42,12 +203,19 @@ func DefaultConfig() *Config {
 		Host:           DefaultCharmHost,
 		AutoSync:       true,
 		StaleThreshold: kv.DefaultStaleThreshold,
+		RevisionLimit:  DefaultRevisionLimit,
 	}
 }
 
+// DataDir returns the directory holding the active profile's config and
+// local state.
+func DataDir() string {
+	return ProfileDataDir(ActiveProfile())
+}
+
 // configPath returns the path to the config file.
 func configPath() (string, error) {
-	dataDir := filepath.Join(xdg.DataHome, AppName)
+	dataDir := DataDir()
 	if err := os.MkdirAll(dataDir, 0700); err != nil {
 		return "", err
 	}
@@ -59,13 +227,13 @@ func LoadConfig() (*Config, error) {
 	path, err := configPath()
 	if err != nil {
 		// Can't determine config path, use defaults
-		return DefaultConfig(), nil //nolint:nilerr // Intentionally returning defaults on path error
+		return applyEnvOverrides(DefaultConfig()), nil //nolint:nilerr // Intentionally returning defaults on path error
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return DefaultConfig(), nil
+			return applyEnvOverrides(DefaultConfig()), nil
 		}
 		return nil, err
 	}
@@ -73,7 +241,7 @@ func LoadConfig() (*Config, error) {
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		// Invalid config, use defaults
-		return DefaultConfig(), nil //nolint:nilerr // Intentionally returning defaults on parse error
+		return applyEnvOverrides(DefaultConfig()), nil //nolint:nilerr // Intentionally returning defaults on parse error
 	}
 
 	// Apply defaults for missing fields
@@ -83,8 +251,113 @@ func LoadConfig() (*Config, error) {
 	if cfg.StaleThreshold == 0 {
 		cfg.StaleThreshold = kv.DefaultStaleThreshold
 	}
+	if cfg.RevisionLimit == 0 {
+		cfg.RevisionLimit = DefaultRevisionLimit
+	}
 
-	return &cfg, nil
+	return applyEnvOverrides(&cfg), nil
+}
+
+// applyEnvOverrides lets containers configure pagen without a config file.
+// Environment variables win over charm-config.json and are never written back to it:
+//   - PAGEN_HOST, PAGEN_AUTO_SYNC, PAGEN_STALE_THRESHOLD, PAGEN_STRICT_RESOLUTION
+//   - PAGEN_EMAIL_TRACKING, PAGEN_TRACKING_BASE_URL
+//   - PAGEN_WEB_BASE_URL, PAGEN_WEB_SLOW_QUERY_THRESHOLD
+//   - PAGEN_WEB_USER, PAGEN_WEB_PASSWORD_HASH (bcrypt)
+//   - PAGEN_WEB_OIDC_ISSUER, PAGEN_WEB_OIDC_CLIENT_ID, PAGEN_WEB_OIDC_CLIENT_SECRET,
+//     PAGEN_WEB_OIDC_ALLOWED_EMAILS (comma-separated)
+//   - PAGEN_WEB_SESSION_SECRET
+func applyEnvOverrides(cfg *Config) *Config {
+	file := *cfg
+	var restores []func(dst *Config)
+	override := func(restore func(dst *Config)) {
+		restores = append(restores, restore)
+	}
+
+	if v := os.Getenv("PAGEN_HOST"); v != "" {
+		cfg.Host = v
+		override(func(dst *Config) { dst.Host = file.Host })
+	}
+	if v := os.Getenv("PAGEN_AUTO_SYNC"); v != "" {
+		cfg.AutoSync = envBool(v)
+		override(func(dst *Config) { dst.AutoSync = file.AutoSync })
+	}
+	if d, err := time.ParseDuration(os.Getenv("PAGEN_STALE_THRESHOLD")); err == nil {
+		cfg.StaleThreshold = d
+		override(func(dst *Config) { dst.StaleThreshold = file.StaleThreshold })
+	}
+	if v := os.Getenv("PAGEN_STRICT_RESOLUTION"); v != "" {
+		cfg.StrictResolution = envBool(v)
+		override(func(dst *Config) { dst.StrictResolution = file.StrictResolution })
+	}
+	if v := os.Getenv("PAGEN_EMAIL_TRACKING"); v != "" {
+		cfg.EmailTracking = envBool(v)
+		override(func(dst *Config) { dst.EmailTracking = file.EmailTracking })
+	}
+	if v := os.Getenv("PAGEN_TRACKING_BASE_URL"); v != "" {
+		cfg.TrackingBaseURL = v
+		override(func(dst *Config) { dst.TrackingBaseURL = file.TrackingBaseURL })
+	}
+	if v := os.Getenv("PAGEN_WEB_BASE_URL"); v != "" {
+		cfg.WebBaseURL = v
+		override(func(dst *Config) { dst.WebBaseURL = file.WebBaseURL })
+	}
+	if v := os.Getenv("PAGEN_WEB_SHARING"); v != "" {
+		cfg.WebSharing = envBool(v)
+		override(func(dst *Config) { dst.WebSharing = file.WebSharing })
+	}
+	if v := os.Getenv("PAGEN_COMPANY_LOGOS"); v != "" {
+		cfg.CompanyLogos = envBool(v)
+		override(func(dst *Config) { dst.CompanyLogos = file.CompanyLogos })
+	}
+	if d, err := time.ParseDuration(os.Getenv("PAGEN_WEB_SLOW_QUERY_THRESHOLD")); err == nil {
+		cfg.WebSlowQueryThreshold = d
+		override(func(dst *Config) { dst.WebSlowQueryThreshold = file.WebSlowQueryThreshold })
+	}
+
+	auth := WebAuthConfig{}
+	if cfg.WebAuth != nil {
+		auth = *cfg.WebAuth
+	}
+	envAuth := map[string]*string{
+		"PAGEN_WEB_USER":               &auth.Username,
+		"PAGEN_WEB_PASSWORD_HASH":      &auth.PasswordHash,
+		"PAGEN_WEB_OIDC_ISSUER":        &auth.OIDCIssuer,
+		"PAGEN_WEB_OIDC_CLIENT_ID":     &auth.OIDCClientID,
+		"PAGEN_WEB_OIDC_CLIENT_SECRET": &auth.OIDCClientSecret,
+		"PAGEN_WEB_SESSION_SECRET":     &auth.SessionSecret,
+	}
+	authChanged := false
+	for name, field := range envAuth {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+			authChanged = true
+		}
+	}
+	if v := os.Getenv("PAGEN_WEB_OIDC_ALLOWED_EMAILS"); v != "" {
+		auth.OIDCAllowedEmails = nil
+		for _, email := range strings.Split(v, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				auth.OIDCAllowedEmails = append(auth.OIDCAllowedEmails, email)
+			}
+		}
+		authChanged = true
+	}
+	if authChanged {
+		cfg.WebAuth = &auth
+		override(func(dst *Config) { dst.WebAuth = file.WebAuth })
+	}
+
+	cfg.envRestores = restores
+	return cfg
+}
+
+func envBool(v string) bool {
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
 }
 
 // Save persists the config to disk.
@@ -94,7 +367,13 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	// Keep values that came from the environment out of the file
+	out := *c
+	for _, restore := range c.envRestores {
+		restore(&out)
+	}
+
+	data, err := json.MarshalIndent(&out, "", "  ")
 	if err != nil {
 		return err
 	}
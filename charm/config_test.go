@@ -0,0 +1,66 @@
+// ABOUTME: Tests for config loading
+// ABOUTME: Verifies environment overrides for containers and that they are not saved to disk
+
+package charm
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+func TestConfigEnvOverrides(t *testing.T) {
+	// Runs after t.Setenv restores the environment
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+
+	file := DefaultConfig()
+	file.Host = "charm.example.com"
+	file.WebBaseURL = "https://file.example.com/"
+	if err := file.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PAGEN_HOST", "charm.env.example.com")
+	t.Setenv("PAGEN_AUTO_SYNC", "false")
+	t.Setenv("PAGEN_STALE_THRESHOLD", "10m")
+	t.Setenv("PAGEN_WEB_USER", "harper")
+	t.Setenv("PAGEN_WEB_PASSWORD_HASH", "$2a$10$hash")
+	t.Setenv("PAGEN_WEB_OIDC_ALLOWED_EMAILS", "a@example.com, @example.org")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "charm.env.example.com" || cfg.AutoSync || cfg.StaleThreshold != 10*time.Minute {
+		t.Errorf("expected env overrides, got host=%q auto_sync=%v stale=%s", cfg.Host, cfg.AutoSync, cfg.StaleThreshold)
+	}
+	if cfg.WebBaseURL != "https://file.example.com/" {
+		t.Errorf("expected file value without an env override, got %q", cfg.WebBaseURL)
+	}
+	if !cfg.WebAuth.PasswordEnabled() || len(cfg.WebAuth.OIDCAllowedEmails) != 2 {
+		t.Errorf("expected web login from env, got %+v", cfg.WebAuth)
+	}
+
+	// Saving keeps env values (including secrets) out of the file
+	cfg.Locale = "de"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := configPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	if strings.Contains(saved, "env.example.com") || strings.Contains(saved, "hash") {
+		t.Errorf("env overrides leaked into the config file:\n%s", saved)
+	}
+	if !strings.Contains(saved, `"locale": "de"`) || !strings.Contains(saved, "charm.example.com") {
+		t.Errorf("expected file values and the new locale to be saved:\n%s", saved)
+	}
+}
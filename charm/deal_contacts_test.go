@@ -0,0 +1,109 @@
+// ABOUTME: Tests for contact roles on deals
+// ABOUTME: Verifies role validation, ordering, upserts, and cascades on delete and rename
+
+package charm
+
+import (
+	"testing"
+)
+
+func TestDealContactRoles(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Enterprise", Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	alice := &Contact{Name: "Alice"}
+	bob := &Contact{Name: "Bob"}
+	for _, contact := range []*Contact{alice, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	if err := client.SaveDealContact(&DealContact{DealID: deal.ID, ContactID: alice.ID, ContactName: "Alice", Role: DealRoleChampion}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+	if err := client.SaveDealContact(&DealContact{DealID: deal.ID, ContactID: bob.ID, ContactName: "Bob", Role: DealRoleDecisionMaker}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+	if err := client.SaveDealContact(&DealContact{DealID: deal.ID, ContactID: bob.ID, Role: "sponsor"}); err == nil {
+		t.Error("expected error for invalid role")
+	}
+
+	contacts, err := client.ListDealContacts(deal.ID)
+	if err != nil {
+		t.Fatalf("ListDealContacts failed: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 deal contacts, got %d", len(contacts))
+	}
+	if contacts[0].ContactName != "Bob" || contacts[1].ContactName != "Alice" {
+		t.Errorf("expected decision maker first, got %s, %s", contacts[0].ContactName, contacts[1].ContactName)
+	}
+
+	// Saving again changes the role rather than adding a second entry
+	if err := client.SaveDealContact(&DealContact{DealID: deal.ID, ContactID: alice.ID, ContactName: "Alice", Role: DealRoleProcurement}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+	roles, err := client.ListContactDealRoles(alice.ID)
+	if err != nil {
+		t.Fatalf("ListContactDealRoles failed: %v", err)
+	}
+	if len(roles) != 1 || roles[0].Role != DealRoleProcurement {
+		t.Errorf("expected single procurement role, got %+v", roles)
+	}
+}
+
+func TestDealContactCascades(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Enterprise", Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	alice := &Contact{Name: "Alice"}
+	bob := &Contact{Name: "Bob"}
+	for _, contact := range []*Contact{alice, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+		if err := client.SaveDealContact(&DealContact{DealID: deal.ID, DealTitle: deal.Title, ContactID: contact.ID, ContactName: contact.Name, Role: DealRoleInfluencer}); err != nil {
+			t.Fatalf("SaveDealContact failed: %v", err)
+		}
+	}
+
+	if err := client.UpdateContactDenormalizedNames(alice.ID, "Alice Smith"); err != nil {
+		t.Fatalf("UpdateContactDenormalizedNames failed: %v", err)
+	}
+	dc, err := client.GetDealContact(deal.ID, alice.ID)
+	if err != nil || dc == nil {
+		t.Fatalf("GetDealContact failed: %v", err)
+	}
+	if dc.ContactName != "Alice Smith" {
+		t.Errorf("expected renamed contact, got %q", dc.ContactName)
+	}
+
+	if err := client.DeleteContactWithCascade(bob.ID); err != nil {
+		t.Fatalf("DeleteContactWithCascade failed: %v", err)
+	}
+	contacts, err := client.ListDealContacts(deal.ID)
+	if err != nil {
+		t.Fatalf("ListDealContacts failed: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Errorf("expected 1 deal contact after contact delete, got %d", len(contacts))
+	}
+
+	if err := client.DeleteDealWithCascade(deal.ID); err != nil {
+		t.Fatalf("DeleteDealWithCascade failed: %v", err)
+	}
+	roles, err := client.ListContactDealRoles(alice.ID)
+	if err != nil {
+		t.Fatalf("ListContactDealRoles failed: %v", err)
+	}
+	if len(roles) != 0 {
+		t.Errorf("expected no roles after deal delete, got %d", len(roles))
+	}
+}
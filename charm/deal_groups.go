@@ -0,0 +1,119 @@
+// ABOUTME: Grouping deals by company, expected close quarter, or tag
+// ABOUTME: Shared by the TUI deals tab, the web deals page, and crm list-deals
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Deal groupings
+const (
+	DealGroupCompany = "company"
+	DealGroupQuarter = "quarter"
+	DealGroupTag     = "tag"
+)
+
+// DealGroupings lists the valid deal groupings, in the order the TUI cycles them.
+var DealGroupings = []string{DealGroupCompany, DealGroupQuarter, DealGroupTag}
+
+// DealGroup is a set of deals sharing a company, close quarter, or tag. Key
+// is stable across reloads so views can remember which groups are collapsed.
+type DealGroup struct {
+	Key      string  `json:"key"`
+	Label    string  `json:"label"`
+	Deals    []*Deal `json:"deals"`
+	Total    int64   `json:"total"`    // in cents; amounts in different currencies are added as-is
+	Currency string  `json:"currency"` // the first deal's currency
+}
+
+// Labels of the catch-all groups
+const (
+	noCompanyLabel   = "No company"
+	noCloseDateLabel = "No close date"
+	untaggedLabel    = "Untagged"
+)
+
+// ValidateDealGrouping checks a grouping name; "" means ungrouped.
+func ValidateDealGrouping(by string) error {
+	if by == "" {
+		return nil
+	}
+	for _, g := range DealGroupings {
+		if by == g {
+			return nil
+		}
+	}
+	return Validationf("invalid grouping: %s (use %s)", by, strings.Join(DealGroupings, ", "))
+}
+
+// DealQuarter labels the quarter of t like the pipeline trend does, e.g. 2024-Q3.
+func DealQuarter(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// GroupDeals splits deals into groups by company, expected close quarter, or
+// tag, keeping the deals' order within each group. Companies and tags sort by
+// name and quarters oldest first, with deals lacking one in a last group. A
+// deal with several tags is in each of their groups.
+func GroupDeals(deals []*Deal, by string) ([]*DealGroup, error) {
+	var keys func(deal *Deal) []string
+	var label func(key string) string
+	missing := ""
+	switch by {
+	case DealGroupCompany:
+		missing = noCompanyLabel
+		keys = func(deal *Deal) []string { return []string{deal.CompanyName} }
+		label = func(key string) string { return key }
+	case DealGroupQuarter:
+		missing = noCloseDateLabel
+		keys = func(deal *Deal) []string {
+			if deal.ExpectedCloseDate == nil {
+				return []string{""}
+			}
+			return []string{DealQuarter(*deal.ExpectedCloseDate)}
+		}
+		label = func(key string) string { return key }
+	case DealGroupTag:
+		missing = untaggedLabel
+		keys = func(deal *Deal) []string {
+			if len(deal.Tags) == 0 {
+				return []string{""}
+			}
+			return deal.Tags
+		}
+		label = func(key string) string { return "#" + key }
+	default:
+		return nil, ValidateDealGrouping(by)
+	}
+
+	byKey := make(map[string]*DealGroup)
+	var groups []*DealGroup
+	for _, deal := range deals {
+		for _, key := range keys(deal) {
+			group := byKey[key]
+			if group == nil {
+				group = &DealGroup{Key: key, Label: missing, Currency: deal.Currency}
+				if key != "" {
+					group.Label = label(key)
+				}
+				byKey[key] = group
+				groups = append(groups, group)
+			}
+			group.Deals = append(group.Deals, deal)
+			group.Total += deal.Amount
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Key, groups[j].Key
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return groups, nil
+}
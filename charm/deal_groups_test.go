@@ -0,0 +1,61 @@
+// ABOUTME: Tests for grouping deals by company, close quarter, and tag
+// ABOUTME: Verifies group order, catch-all groups, totals, and deals in several tag groups
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupDeals(t *testing.T) {
+	q3 := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+	q1 := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	deals := []*Deal{
+		{Title: "Pilot", CompanyName: "Globex", Amount: 1000, Currency: "USD", ExpectedCloseDate: &q1, Tags: []string{"enterprise", "q-focus"}},
+		{Title: "Renewal", CompanyName: "Acme", Amount: 500, Currency: "USD", ExpectedCloseDate: &q3},
+		{Title: "Side deal", Amount: 200, Currency: "USD", Tags: []string{"enterprise"}},
+		{Title: "Expansion", CompanyName: "acme corp", Amount: 300, Currency: "USD", ExpectedCloseDate: &q3},
+	}
+
+	labels := func(groups []*DealGroup) []string {
+		var out []string
+		for _, g := range groups {
+			out = append(out, g.Label)
+		}
+		return out
+	}
+	assertLabels := func(by string, want ...string) []*DealGroup {
+		t.Helper()
+		groups, err := GroupDeals(deals, by)
+		if err != nil {
+			t.Fatalf("GroupDeals(%s) failed: %v", by, err)
+		}
+		got := labels(groups)
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %v, got %v", by, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s: expected %v, got %v", by, want, got)
+			}
+		}
+		return groups
+	}
+
+	assertLabels(DealGroupCompany, "Acme", "acme corp", "Globex", "No company")
+
+	quarters := assertLabels(DealGroupQuarter, "2024-Q3", "2025-Q1", "No close date")
+	if quarters[0].Total != 800 || len(quarters[0].Deals) != 2 || quarters[0].Deals[0].Title != "Renewal" {
+		t.Errorf("expected Renewal then Expansion totalling 800, got %+v", quarters[0])
+	}
+
+	tags := assertLabels(DealGroupTag, "#enterprise", "#q-focus", "Untagged")
+	if len(tags[0].Deals) != 2 || len(tags[1].Deals) != 1 || len(tags[2].Deals) != 2 {
+		t.Errorf("expected Pilot in both tag groups, got %d, %d, %d", len(tags[0].Deals), len(tags[1].Deals), len(tags[2].Deals))
+	}
+
+	if _, err := GroupDeals(deals, "owner"); err == nil {
+		t.Error("expected an unknown grouping to fail")
+	}
+}
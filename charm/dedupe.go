@@ -0,0 +1,567 @@
+// ABOUTME: Duplicate contact detection and merging
+// ABOUTME: Finds likely duplicates by email, name and company, or similar names, and merges one contact into another
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Reasons two contacts look like duplicates, strongest first.
+const (
+	DuplicateSameEmail       = "same_email"
+	DuplicateSameNameCompany = "same_name_company"
+	DuplicateSimilarName     = "similar_name"
+)
+
+// similarNameThreshold is the minimum name similarity (0-1) for a fuzzy match.
+const similarNameThreshold = 0.85
+
+// DuplicatePair is two contacts that are likely the same person. Keep is the
+// suggested survivor: the one with more interactions, then the older one.
+type DuplicatePair struct {
+	Keep       *Contact `json:"keep"`
+	Drop       *Contact `json:"drop"`
+	Reason     string   `json:"reason"`
+	Similarity float64  `json:"similarity"` // name similarity, 1 for identical names
+}
+
+// Confident reports whether the match is strong enough to merge without
+// asking: a shared email, or the same name at the same company.
+func (p *DuplicatePair) Confident() bool {
+	return p.Reason != DuplicateSimilarName
+}
+
+// DuplicateReasonLabel describes a duplicate reason for display.
+func DuplicateReasonLabel(reason string) string {
+	switch reason {
+	case DuplicateSameEmail:
+		return "same email"
+	case DuplicateSameNameCompany:
+		return "same name and company"
+	case DuplicateSimilarName:
+		return "similar name"
+	}
+	return reason
+}
+
+// FindDuplicateContacts returns likely duplicate pairs among active contacts,
+// strongest reason first. Contacts sharing an email or a name and company are
+// each paired with the group's survivor; similar names are paired one to one
+// and never across two different companies.
+func (c *Client) FindDuplicateContacts() ([]*DuplicatePair, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, err
+	}
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uuid.UUID]int)
+	for _, log := range interactions {
+		counts[log.ContactID]++
+	}
+	// better reports whether a should survive over b
+	better := func(a, b *Contact) bool {
+		if counts[a.ID] != counts[b.ID] {
+			return counts[a.ID] > counts[b.ID]
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}
+
+	var pairs []*DuplicatePair
+	paired := make(map[[2]uuid.UUID]bool)
+	addPair := func(a, b *Contact, reason string, similarity float64) {
+		key := [2]uuid.UUID{a.ID, b.ID}
+		if b.ID.String() < a.ID.String() {
+			key = [2]uuid.UUID{b.ID, a.ID}
+		}
+		if paired[key] {
+			return
+		}
+		paired[key] = true
+		if better(b, a) {
+			a, b = b, a
+		}
+		pairs = append(pairs, &DuplicatePair{Keep: a, Drop: b, Reason: reason, Similarity: similarity})
+	}
+	addGroups := func(key func(*Contact) string, reason string) {
+		groups := make(map[string][]*Contact)
+		var order []string
+		for _, contact := range contacts {
+			k := key(contact)
+			if k == "" {
+				continue
+			}
+			if _, ok := groups[k]; !ok {
+				order = append(order, k)
+			}
+			groups[k] = append(groups[k], contact)
+		}
+		for _, k := range order {
+			group := groups[k]
+			if len(group) < 2 {
+				continue
+			}
+			sort.SliceStable(group, func(i, j int) bool { return better(group[i], group[j]) })
+			for _, other := range group[1:] {
+				addPair(group[0], other, reason, nameSimilarity(group[0].Name, other.Name))
+			}
+		}
+	}
+
+	addGroups(func(contact *Contact) string {
+		return strings.ToLower(strings.TrimSpace(contact.Email))
+	}, DuplicateSameEmail)
+	addGroups(func(contact *Contact) string {
+		name, company := normalizeContactName(contact.Name), contactCompanyKey(contact)
+		if name == "" || company == "" {
+			return ""
+		}
+		return name + "\x00" + company
+	}, DuplicateSameNameCompany)
+
+	// Similar names are only compared within blocks sharing the first two
+	// letters of the normalized name, to stay fast with many contacts
+	blocks := make(map[string][]*Contact)
+	var blockOrder []string
+	for _, contact := range contacts {
+		name := normalizeContactName(contact.Name)
+		if len([]rune(name)) < 4 {
+			continue
+		}
+		block := string([]rune(name)[:2])
+		if _, ok := blocks[block]; !ok {
+			blockOrder = append(blockOrder, block)
+		}
+		blocks[block] = append(blocks[block], contact)
+	}
+	for _, block := range blockOrder {
+		group := blocks[block]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				a, b := group[i], group[j]
+				if ka, kb := contactCompanyKey(a), contactCompanyKey(b); ka != "" && kb != "" && ka != kb {
+					continue
+				}
+				if similarity := nameSimilarity(a.Name, b.Name); similarity >= similarNameThreshold {
+					addPair(a, b, DuplicateSimilarName, similarity)
+				}
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+// normalizeContactName lowercases a name, drops punctuation, and sorts its
+// words, so "Smith, John" and "john  smith" compare equal.
+func normalizeContactName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// contactCompanyKey identifies a contact's company, by ID when linked.
+func contactCompanyKey(contact *Contact) string {
+	if contact.CompanyID != nil {
+		return contact.CompanyID.String()
+	}
+	return strings.ToLower(strings.TrimSpace(contact.CompanyName))
+}
+
+// nameSimilarity is 1 minus the edit distance between the normalized names
+// over the longer name's length.
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeContactName(a)), []rune(normalizeContactName(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// MergeResult counts what MergeContacts moved to the surviving contact.
+type MergeResult struct {
+	Kept          *Contact `json:"kept"`
+	Interactions  int      `json:"interactions"`
+	Relationships int      `json:"relationships"`
+	Deals         int      `json:"deals"`
+	DealRoles     int      `json:"deal_roles"`
+	Cadence       bool     `json:"cadence"`
+	Tasks         int      `json:"tasks"`
+	Events        int      `json:"events"`
+	Emails        int      `json:"emails"` // tracked and outreach emails
+}
+
+// MergeContacts merges the drop contact into keep and deletes it. Keep's
+// empty fields are filled from drop, and drop's interactions, relationships,
+// deals, deal roles, cadence, tasks, event attendance, emails, watch, and
+// sync records move to keep. Records that would duplicate one keep already
+// has (a relationship to the same person, a role on the same deal) are
+// dropped instead.
+func (c *Client) MergeContacts(keepID, dropID uuid.UUID) (*MergeResult, error) {
+	if keepID == dropID {
+		return nil, fmt.Errorf("cannot merge a contact into itself")
+	}
+	keep, err := c.GetContact(keepID)
+	if err != nil {
+		return nil, err
+	}
+	drop, err := c.GetContact(dropID)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeContactFields(keep, drop)
+	if err := c.UpdateContact(keep); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	result := &MergeResult{Kept: keep}
+
+	// 1. Interactions
+	logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &dropID})
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		log.ContactID, log.ContactName = keep.ID, keep.Name
+		if err := c.CreateInteractionLog(log); err != nil {
+			return nil, fmt.Errorf("failed to move interaction: %w", err)
+		}
+		result.Interactions++
+	}
+
+	// 2. Relationships, dropping ones between the two or already held by keep
+	rels, err := c.ListRelationshipsForContact(dropID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		other := rel.ContactID1
+		if other == dropID {
+			other = rel.ContactID2
+		}
+		existing, _ := c.GetRelationshipBetween(keepID, other)
+		if other == keepID || existing != nil {
+			if err := c.DeleteRelationship(rel.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if rel.ContactID1 == dropID {
+			rel.ContactID1, rel.Contact1Name = keep.ID, keep.Name
+		} else {
+			rel.ContactID2, rel.Contact2Name = keep.ID, keep.Name
+		}
+		if err := c.UpdateRelationship(rel); err != nil {
+			return nil, fmt.Errorf("failed to move relationship: %w", err)
+		}
+		result.Relationships++
+	}
+
+	// 3. Deals where drop is the primary contact or the referrer
+	deals, err := c.ListDeals(&DealFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, deal := range deals {
+		changed := false
+		if deal.ContactID != nil && *deal.ContactID == dropID {
+			deal.ContactID, deal.ContactName = &keep.ID, keep.Name
+			changed = true
+		}
+		if deal.ReferrerID != nil && *deal.ReferrerID == dropID {
+			deal.ReferrerID, deal.ReferrerName = &keep.ID, keep.Name
+			changed = true
+		}
+		if changed {
+			if err := c.UpdateDeal(deal); err != nil {
+				return nil, fmt.Errorf("failed to move deal: %w", err)
+			}
+			result.Deals++
+		}
+	}
+
+	// 4. Deal roles, keeping keep's role when both are on a deal
+	roles, err := c.ListContactDealRoles(dropID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if existing, _ := c.GetDealContact(role.DealID, keepID); existing == nil {
+			moved := *role
+			moved.ContactID, moved.ContactName = keep.ID, keep.Name
+			if err := c.SaveDealContact(&moved); err != nil {
+				return nil, fmt.Errorf("failed to move deal role: %w", err)
+			}
+			result.DealRoles++
+		}
+		if err := c.DeleteDealContact(role.DealID, dropID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Cadence: keep's settings win, but the latest interaction counts
+	dropCadence, err := c.GetContactCadence(dropID)
+	if err != nil {
+		return nil, err
+	}
+	if dropCadence != nil {
+		keepCadence, err := c.GetContactCadence(keepID)
+		if err != nil {
+			return nil, err
+		}
+		if keepCadence == nil {
+			keepCadence = dropCadence
+			keepCadence.ContactID = keep.ID
+		} else if laterTime(dropCadence.LastInteractionDate, keepCadence.LastInteractionDate) {
+			keepCadence.LastInteractionDate = dropCadence.LastInteractionDate
+		}
+		keepCadence.ContactName = keep.Name
+		if err := c.SaveContactCadence(keepCadence); err != nil {
+			return nil, fmt.Errorf("failed to save cadence: %w", err)
+		}
+		if err := c.DeleteContactCadence(dropID); err != nil {
+			return nil, err
+		}
+		result.Cadence = true
+	}
+
+	// 6. Tasks
+	tasks, err := c.ListTasks(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.ContactID != nil && *task.ContactID == dropID {
+			task.ContactID, task.ContactName = &keep.ID, keep.Name
+			if err := c.saveTask(task); err != nil {
+				return nil, fmt.Errorf("failed to move task: %w", err)
+			}
+			result.Tasks++
+		}
+	}
+
+	// 7. Event attendance
+	attended, err := c.ListContactEvents(dropID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attended {
+		existing, err := c.GetEventAttendee(a.EventID, keepID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			moved := *a
+			moved.ContactID, moved.ContactName = keep.ID, keep.Name
+			if err := c.saveEventAttendee(&moved); err != nil {
+				return nil, fmt.Errorf("failed to move attendance: %w", err)
+			}
+			result.Events++
+		} else if a.MetHere && !existing.MetHere {
+			existing.MetHere = true
+			if err := c.saveEventAttendee(existing); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.RemoveAttendance(a.EventID, dropID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 8. Tracked and outreach emails
+	emails, err := c.ListTrackedEmails(&dropID)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range emails {
+		email.ContactID, email.ContactName = keep.ID, keep.Name
+		if err := c.saveTrackedEmail(email); err != nil {
+			return nil, fmt.Errorf("failed to move tracked email: %w", err)
+		}
+		result.Emails++
+	}
+	outreach, err := c.ListOutreach("")
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range outreach {
+		if o.ContactID == dropID {
+			o.ContactID, o.ContactName = keep.ID, keep.Name
+			if err := c.saveOutreach(o); err != nil {
+				return nil, fmt.Errorf("failed to move outreach: %w", err)
+			}
+			result.Emails++
+		}
+	}
+
+	// 9. Watch
+	if watch, err := c.GetWatch(dropID); err == nil && watch != nil {
+		if existing, _ := c.GetWatch(keepID); existing == nil {
+			if _, err := c.AddWatch(EntityContact, keepID, watch.Desktop, watch.Webhook); err != nil {
+				return nil, fmt.Errorf("failed to move watch: %w", err)
+			}
+		}
+		if err := c.RemoveWatch(dropID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 10. Sync records, so the next sync updates keep instead of re-creating drop
+	if err := c.retargetSyncLogs(dropID, keepID); err != nil {
+		return nil, err
+	}
+
+	if err := c.DeleteContact(dropID); err != nil {
+		return nil, fmt.Errorf("failed to delete merged contact: %w", err)
+	}
+	return result, nil
+}
+
+// mergeContactFields fills keep's empty fields from drop. Notes are combined,
+// and an email or phone keep already has a different one of is noted.
+func mergeContactFields(keep, drop *Contact) {
+	fill := func(dst *string, src string) {
+		if strings.TrimSpace(*dst) == "" {
+			*dst = src
+		}
+	}
+	var extra []string
+	if drop.Email != "" && keep.Email != "" && !strings.EqualFold(drop.Email, keep.Email) {
+		extra = append(extra, "Other email: "+drop.Email)
+	}
+	if drop.Phone != "" && keep.Phone != "" && drop.Phone != keep.Phone {
+		extra = append(extra, "Other phone: "+drop.Phone)
+	}
+
+	fill(&keep.Email, drop.Email)
+	fill(&keep.Phone, drop.Phone)
+	fill(&keep.Title, drop.Title)
+	if keep.CompanyID == nil && keep.CompanyName == "" {
+		keep.CompanyID, keep.CompanyName = drop.CompanyID, drop.CompanyName
+	}
+	fill(&keep.Birthday, drop.Birthday)
+	fill(&keep.Country, drop.Country)
+	fill(&keep.MetVia, drop.MetVia)
+	fill(&keep.MetAt, drop.MetAt)
+	fill(&keep.Seniority, drop.Seniority)
+	fill(&keep.Function, drop.Function)
+	if keep.WorkStartDate == nil {
+		keep.WorkStartDate = drop.WorkStartDate
+	}
+	if keep.MetDate == nil || (drop.MetDate != nil && drop.MetDate.Before(*keep.MetDate)) {
+		keep.MetDate = drop.MetDate
+	}
+	if laterTime(drop.LastContactedAt, keep.LastContactedAt) {
+		keep.LastContactedAt = drop.LastContactedAt
+	}
+	if drop.CreatedAt.Before(keep.CreatedAt) {
+		keep.CreatedAt = drop.CreatedAt
+	}
+	// Either one being active keeps the merged contact active
+	if drop.ArchivedAt == nil {
+		keep.ArchivedAt = nil
+	}
+
+	for _, fact := range drop.Pinned {
+		found := false
+		for _, existing := range keep.Pinned {
+			if strings.EqualFold(existing, fact) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			keep.Pinned = append(keep.Pinned, fact)
+		}
+	}
+	for _, tag := range drop.Tags {
+		if !keep.HasTag(tag) {
+			keep.Tags = append(keep.Tags, tag)
+		}
+	}
+	sort.Strings(keep.Tags)
+
+	notes := strings.TrimSpace(drop.Notes)
+	if notes != "" && !strings.Contains(keep.Notes, notes) {
+		extra = append(extra, notes)
+	}
+	if len(extra) > 0 {
+		parts := []string{strings.TrimSpace(keep.Notes)}
+		if parts[0] == "" {
+			parts = nil
+		}
+		keep.Notes = strings.Join(append(parts, extra...), "\n\n")
+	}
+}
+
+// laterTime reports whether a is set and after b (or b is unset).
+func laterTime(a, b *time.Time) bool {
+	return a != nil && (b == nil || a.After(*b))
+}
+
+// retargetSyncLogs points sync records for one contact at another.
+func (c *Client) retargetSyncLogs(fromID, toID uuid.UUID) error {
+	keys, err := c.KeysWithPrefix([]byte(PrefixSyncLog))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+		var log SyncLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			continue
+		}
+		if log.EntityID != fromID || log.EntityType != EntityContact {
+			continue
+		}
+		log.EntityID = toID
+		data, err = json.Marshal(&log)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sync log: %w", err)
+		}
+		if err := c.Set(key, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
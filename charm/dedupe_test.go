@@ -0,0 +1,185 @@
+// ABOUTME: Tests for duplicate contact detection and merging
+// ABOUTME: Covers match reasons, survivor choice, and moving related records on merge
+
+package charm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	if got := nameSimilarity("Smith, John", "john  smith"); got != 1 {
+		t.Errorf("expected reordered names to match exactly, got %.2f", got)
+	}
+	if got := nameSimilarity("Jon Smith", "John Smith"); got < similarNameThreshold {
+		t.Errorf("expected Jon/John Smith to be similar, got %.2f", got)
+	}
+	if got := nameSimilarity("Alice Jones", "Bob Jones"); got >= similarNameThreshold {
+		t.Errorf("expected Alice/Bob Jones not to be similar, got %.2f", got)
+	}
+}
+
+func TestFindDuplicateContacts(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+	acme, globex := uuid.New(), uuid.New()
+
+	create := func(name, email string, companyID *uuid.UUID, age time.Duration) *Contact {
+		contact := &Contact{Name: name, Email: email, CompanyID: companyID}
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+		contact.CreatedAt = now.Add(-age)
+		if err := client.UpdateContact(contact); err != nil {
+			t.Fatalf("UpdateContact failed: %v", err)
+		}
+		return contact
+	}
+	alice := create("Alice Smith", "alice@acme.com", &acme, 48*time.Hour)
+	aliceAgain := create("A. Smith", "ALICE@acme.com ", nil, time.Hour)
+	bob := create("Bob Jones", "", &acme, 48*time.Hour)
+	bobAgain := create("Jones, Bob", "bob@example.com", &acme, time.Hour)
+	jon := create("Jon Parker", "", nil, 48*time.Hour)
+	john := create("John Parker", "", nil, time.Hour)
+	create("Carol White", "", &acme, time.Hour)
+	create("Carl White", "", &globex, time.Hour) // similar, but at another company
+
+	// The newer Jon has more interactions, so it survives
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: john.ID, InteractionType: "call"}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+
+	pairs, err := client.FindDuplicateContacts()
+	if err != nil {
+		t.Fatalf("FindDuplicateContacts failed: %v", err)
+	}
+	if len(pairs) != 3 {
+		for _, p := range pairs {
+			t.Logf("%s / %s: %s", p.Keep.Name, p.Drop.Name, p.Reason)
+		}
+		t.Fatalf("expected 3 pairs, got %d", len(pairs))
+	}
+
+	want := []struct {
+		keep, drop uuid.UUID
+		reason     string
+	}{
+		{alice.ID, aliceAgain.ID, DuplicateSameEmail},
+		{bob.ID, bobAgain.ID, DuplicateSameNameCompany},
+		{john.ID, jon.ID, DuplicateSimilarName},
+	}
+	for i, w := range want {
+		if pairs[i].Keep.ID != w.keep || pairs[i].Drop.ID != w.drop || pairs[i].Reason != w.reason {
+			t.Errorf("pair %d: got %s / %s (%s), want reason %s", i, pairs[i].Keep.Name, pairs[i].Drop.Name, pairs[i].Reason, w.reason)
+		}
+	}
+	if pairs[2].Confident() || !pairs[0].Confident() {
+		t.Error("expected only exact matches to be confident")
+	}
+}
+
+func TestMergeContacts(t *testing.T) {
+	client := NewTestClient(t)
+
+	keep := &Contact{Name: "Alice Smith", Email: "alice@acme.com", Notes: "Met at PyCon", Pinned: []string{"kids: 2"}}
+	drop := &Contact{Name: "Alice S", Email: "alice@home.example", Phone: "555-1234", Title: "CTO", Notes: "Likes cycling", Pinned: []string{"KIDS: 2", "vegetarian"}}
+	other := &Contact{Name: "Bob"}
+	for _, contact := range []*Contact{keep, drop, other} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	deal := &Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProposal, ContactID: &drop.ID, ReferrerID: &drop.ID}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if err := client.SaveDealContact(&DealContact{DealID: deal.ID, ContactID: drop.ID, Role: DealRoleChampion}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: drop.ID, InteractionType: "call"}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+	// A relationship between the two disappears; one to Bob moves
+	for _, rel := range []*Relationship{
+		{ContactID1: keep.ID, ContactID2: drop.ID, RelationshipType: "colleague"},
+		{ContactID1: drop.ID, ContactID2: other.ID, RelationshipType: "friend"},
+	} {
+		if err := client.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship failed: %v", err)
+		}
+	}
+	last := time.Now().Add(-24 * time.Hour)
+	if err := client.SaveContactCadence(&ContactCadence{ContactID: drop.ID, CadenceDays: 14, RelationshipStrength: StrengthStrong, LastInteractionDate: &last}); err != nil {
+		t.Fatalf("SaveContactCadence failed: %v", err)
+	}
+	task := &Task{Title: "Send deck", ContactID: &drop.ID}
+	if err := client.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := client.CreateSyncLog(&SyncLog{SourceService: "google_contacts", SourceID: "people/1", EntityType: EntityContact, EntityID: drop.ID}); err != nil {
+		t.Fatalf("CreateSyncLog failed: %v", err)
+	}
+
+	result, err := client.MergeContacts(keep.ID, drop.ID)
+	if err != nil {
+		t.Fatalf("MergeContacts failed: %v", err)
+	}
+	if result.Interactions != 1 || result.Relationships != 1 || result.Deals != 1 || result.DealRoles != 1 || result.Tasks != 1 || !result.Cadence {
+		t.Errorf("unexpected merge result: %+v", result)
+	}
+
+	merged, err := client.GetContact(keep.ID)
+	if err != nil {
+		t.Fatalf("GetContact failed: %v", err)
+	}
+	if merged.Email != "alice@acme.com" || merged.Phone != "555-1234" || merged.Title != "CTO" {
+		t.Errorf("expected empty fields filled from the duplicate, got %+v", merged)
+	}
+	if len(merged.Pinned) != 2 {
+		t.Errorf("expected pinned facts combined without duplicates, got %v", merged.Pinned)
+	}
+	if merged.Notes != "Met at PyCon\n\nOther email: alice@home.example\n\nLikes cycling" {
+		t.Errorf("unexpected notes: %q", merged.Notes)
+	}
+	if _, err := client.GetContact(drop.ID); err == nil {
+		t.Error("expected the duplicate to be deleted")
+	}
+
+	gotDeal, _ := client.GetDeal(deal.ID)
+	if *gotDeal.ContactID != keep.ID || *gotDeal.ReferrerID != keep.ID || gotDeal.ContactName != "Alice Smith" {
+		t.Errorf("expected the deal moved to the survivor, got %+v", gotDeal)
+	}
+	if role, _ := client.GetDealContact(deal.ID, keep.ID); role == nil || role.Role != DealRoleChampion {
+		t.Errorf("expected the champion role moved, got %+v", role)
+	}
+	if rel, _ := client.GetRelationshipBetween(keep.ID, other.ID); rel == nil {
+		t.Error("expected the relationship to Bob moved")
+	}
+	if rels, _ := client.ListRelationshipsForContact(keep.ID); len(rels) != 1 {
+		t.Errorf("expected only the relationship to Bob left, got %d", len(rels))
+	}
+	if cadence, _ := client.GetContactCadence(keep.ID); cadence == nil || cadence.CadenceDays != 14 {
+		t.Errorf("expected the cadence moved, got %+v", cadence)
+	}
+	if logs, _ := client.ListInteractionLogs(&InteractionFilter{ContactID: &keep.ID}); len(logs) != 1 {
+		t.Errorf("expected the interaction moved, got %d", len(logs))
+	}
+	if moved, _ := client.GetTask(task.ID); moved.ContactID == nil || *moved.ContactID != keep.ID {
+		t.Errorf("expected the task moved, got %+v", moved)
+	}
+	if log, _ := client.FindSyncLogBySource("google_contacts", "people/1"); log == nil || log.EntityID != keep.ID {
+		t.Errorf("expected the sync record retargeted, got %+v", log)
+	}
+
+	if _, err := client.MergeContacts(keep.ID, keep.ID); err == nil {
+		t.Error("expected an error merging a contact into itself")
+	}
+}
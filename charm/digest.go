@@ -0,0 +1,142 @@
+// ABOUTME: Digest ranking of follow-ups by a composite score
+// ABOUTME: Weighs days overdue, relationship strength, and open deal value at risk, and explains each rank
+
+package charm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DigestItem is a follow-up ranked for the digest, with the reasons behind
+// its score in the order they matter ("VIP, 21 days overdue, $50K deal in
+// negotiation").
+type DigestItem struct {
+	*FollowupContact
+	Score       float64  `json:"score"`
+	DaysOverdue int      `json:"days_overdue"`
+	ValueAtRisk int64    `json:"value_at_risk,omitempty"` // expected value of the contact's open deals, in cents
+	TopDeal     *Deal    `json:"top_deal,omitempty"`      // the contact's largest open deal
+	PrimaryAt   []string `json:"primary_at,omitempty"`    // companies the contact is the primary contact at
+	Reasons     []string `json:"reasons"`
+}
+
+// DigestScore combines a follow-up's priority (days overdue scaled by
+// relationship strength and fading engagement) with the value of the open
+// deals riding on the relationship. Each tenfold increase in expected value
+// past $1,000 adds the priority again, so a $50K deal in negotiation
+// ($37.5K expected) ranks a follow-up about 2.6x higher.
+func DigestScore(priority float64, valueAtRisk int64) float64 {
+	dollars := math.Max(float64(valueAtRisk)/100, 0)
+	return priority * (1 + math.Log10(1+dollars/1000))
+}
+
+// GetDigest returns the follow-up list ranked by DigestScore, highest first.
+func (c *Client) GetDigest(limit int) ([]*DigestItem, error) {
+	followups, err := c.GetFollowupList(0)
+	if err != nil {
+		return nil, err
+	}
+	dealsByContact, err := c.openDealsByContact()
+	if err != nil {
+		return nil, err
+	}
+	primaryAt, err := c.companiesByPrimaryContact()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*DigestItem, 0, len(followups))
+	for _, f := range followups {
+		item := &DigestItem{FollowupContact: f, DaysOverdue: f.DaysSinceContact - f.CadenceDays}
+		for _, deal := range dealsByContact[f.ID] {
+			item.ValueAtRisk += deal.ExpectedValue()
+			if item.TopDeal == nil || deal.Amount > item.TopDeal.Amount {
+				item.TopDeal = deal
+			}
+		}
+		for _, company := range primaryAt[f.ID] {
+			item.PrimaryAt = append(item.PrimaryAt, company.Name)
+		}
+		item.Score = DigestScore(f.PriorityScore, item.ValueAtRisk)
+		item.Reasons = digestReasons(item)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Score > items[j].Score
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// digestReasons explains an item's score: strong relationships are VIPs,
+// then the companies they're the primary contact at, how overdue the contact
+// is, and the largest open deal they're on.
+func digestReasons(item *DigestItem) []string {
+	reasons := []string{}
+	if item.RelationshipStrength == StrengthStrong {
+		reasons = append(reasons, "VIP")
+	}
+	for _, company := range item.PrimaryAt {
+		reasons = append(reasons, "primary contact at "+company)
+	}
+	if item.DaysOverdue > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d days overdue", item.DaysOverdue))
+	}
+	if deal := item.TopDeal; deal != nil {
+		reason := "open deal"
+		if deal.Amount > 0 {
+			reason = FormatMoneyCompact(deal.Amount, deal.Currency) + " deal"
+		}
+		reasons = append(reasons, reason+" in "+strings.ReplaceAll(deal.Stage, "_", " "))
+	}
+	return reasons
+}
+
+// openDealsByContact maps each contact to the open deals they are the
+// primary contact on or hold a role in.
+func (c *Client) openDealsByContact() (map[uuid.UUID][]*Deal, error) {
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	roles, err := c.listDealContacts(PrefixDealContact, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal contacts: %w", err)
+	}
+
+	open := make(map[uuid.UUID]*Deal)
+	for _, deal := range deals {
+		if deal.Stage != StageClosedWon && deal.Stage != StageClosedLost {
+			open[deal.ID] = deal
+		}
+	}
+
+	byContact := make(map[uuid.UUID][]*Deal)
+	seen := make(map[[2]uuid.UUID]bool)
+	add := func(contactID uuid.UUID, deal *Deal) {
+		key := [2]uuid.UUID{contactID, deal.ID}
+		if !seen[key] {
+			seen[key] = true
+			byContact[contactID] = append(byContact[contactID], deal)
+		}
+	}
+	for _, deal := range deals {
+		if open[deal.ID] != nil && deal.ContactID != nil {
+			add(*deal.ContactID, deal)
+		}
+	}
+	for _, role := range roles {
+		if deal, ok := open[role.DealID]; ok {
+			add(role.ContactID, deal)
+		}
+	}
+	return byContact, nil
+}
@@ -0,0 +1,77 @@
+// ABOUTME: Tests for digest ranking
+// ABOUTME: Verifies deal value at risk lifts a follow-up's rank and each item explains its score
+
+package charm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDigestScore(t *testing.T) {
+	if got := DigestScore(10, 0); got != 10 {
+		t.Errorf("expected no boost without deals, got %v", got)
+	}
+	// $9,000 expected is 10x the $1,000 pivot: double the priority
+	if got := DigestScore(10, 900_000); got != 20 {
+		t.Errorf("expected 20 for $9,000 at risk, got %v", got)
+	}
+}
+
+func TestGetDigest(t *testing.T) {
+	client := NewTestClient(t)
+	lastSpoke := time.Now().AddDate(0, 0, -51)
+
+	vip := &Contact{Name: "Vera"}
+	buyer := &Contact{Name: "Ben"}
+	current := &Contact{Name: "Cal"}
+	for _, contact := range []*Contact{vip, buyer, current} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	for contact, strength := range map[*Contact]string{vip: StrengthStrong, buyer: StrengthWeak, current: StrengthStrong} {
+		last := lastSpoke
+		if contact == current {
+			last = time.Now()
+		}
+		if err := client.SaveContactCadence(&ContactCadence{ContactID: contact.ID, CadenceDays: 30, RelationshipStrength: strength, LastInteractionDate: &last}); err != nil {
+			t.Fatalf("SaveContactCadence failed: %v", err)
+		}
+	}
+
+	open := &Deal{Title: "Platform", Stage: StageNegotiation, Amount: 5_000_000, Currency: "USD"}
+	won := &Deal{Title: "Pilot", Stage: StageClosedWon, Amount: 90_000_000, Currency: "USD", ContactID: &vip.ID}
+	for _, deal := range []*Deal{open, won} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+	if err := client.SaveDealContact(&DealContact{DealID: open.ID, ContactID: buyer.ID, Role: DealRoleDecisionMaker}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+
+	items, err := client.GetDigest(0)
+	if err != nil {
+		t.Fatalf("GetDigest failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 overdue items, got %d", len(items))
+	}
+
+	// Ben's weaker relationship is outweighed by the deal riding on it
+	if items[0].Name != "Ben" || items[1].Name != "Vera" {
+		t.Fatalf("expected Ben then Vera, got %s then %s", items[0].Name, items[1].Name)
+	}
+	if items[0].ValueAtRisk != 3_750_000 || items[0].TopDeal == nil || items[0].TopDeal.ID != open.ID {
+		t.Errorf("expected the open deal at risk, got %d, %+v", items[0].ValueAtRisk, items[0].TopDeal)
+	}
+	if want := []string{"21 days overdue", "$50K deal in negotiation"}; !reflect.DeepEqual(items[0].Reasons, want) {
+		t.Errorf("expected reasons %v, got %v", want, items[0].Reasons)
+	}
+	// Closed deals are not at risk
+	if want := []string{"VIP", "21 days overdue"}; !reflect.DeepEqual(items[1].Reasons, want) || items[1].TopDeal != nil {
+		t.Errorf("expected reasons %v and no deal, got %v, %+v", want, items[1].Reasons, items[1].TopDeal)
+	}
+}
@@ -0,0 +1,116 @@
+// ABOUTME: Optional uniqueness of contact emails, enforced when contacts are created
+// ABOUTME: Each entry point can allow duplicates, return the existing contact, or reject with its ID
+
+package charm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Duplicate email policies for creating a contact whose email another
+// contact already has.
+const (
+	DuplicateEmailAllow  = "allow"  // create the new contact anyway
+	DuplicateEmailReturn = "return" // return the existing contact instead
+	DuplicateEmailReject = "reject" // fail with a *DuplicateEmailError
+)
+
+// DuplicateEmailPolicies lists the valid policies.
+var DuplicateEmailPolicies = []string{DuplicateEmailAllow, DuplicateEmailReturn, DuplicateEmailReject}
+
+// Entry points that create contacts, each with its own duplicate email policy
+// under duplicate_emails in the config. "default" applies to the ones not set.
+// They also name who made a change in the audit log, along with EntryWeb.
+const (
+	EntryCLI = "cli"
+	EntryMCP = "mcp"
+	EntryTUI = "tui"
+	EntryAPI = "api" // the web server's REST API
+	EntryWeb = "web" // the web UI
+)
+
+// defaultDuplicateEmailPolicies keep each entry point's behaviour from before
+// policies could be set: add_contact already returned the existing contact.
+var defaultDuplicateEmailPolicies = map[string]string{
+	EntryMCP: DuplicateEmailReturn,
+}
+
+// DuplicateEmailError is returned when a contact's email is already taken and
+// the policy rejects duplicates.
+type DuplicateEmailError struct {
+	Email    string
+	Existing *Contact
+}
+
+func (e *DuplicateEmailError) Error() string {
+	return fmt.Sprintf("a contact with email %s already exists: %s (ID: %s)", e.Email, e.Existing.Name, FormatID(e.Existing.ID))
+}
+
+// ValidateDuplicateEmailPolicy checks a policy name.
+func ValidateDuplicateEmailPolicy(policy string) error {
+	for _, valid := range DuplicateEmailPolicies {
+		if policy == valid {
+			return nil
+		}
+	}
+	return Validationf("invalid duplicate email policy: %s (valid: %s)", policy, strings.Join(DuplicateEmailPolicies, ", "))
+}
+
+// DuplicateEmailPolicy returns the policy for an entry point: its own setting,
+// then the "default" setting, then the built-in default. Unknown settings
+// allow duplicates, as before policies existed.
+func (c *Client) DuplicateEmailPolicy(entryPoint string) string {
+	if cfg := c.Config(); cfg != nil {
+		for _, key := range []string{entryPoint, "default"} {
+			if policy, ok := cfg.DuplicateEmails[key]; ok && ValidateDuplicateEmailPolicy(policy) == nil {
+				return policy
+			}
+		}
+	}
+	if policy, ok := defaultDuplicateEmailPolicies[entryPoint]; ok {
+		return policy
+	}
+	return DuplicateEmailAllow
+}
+
+// CheckDuplicateEmail applies policy to a new contact's email before it is
+// created. It returns the existing contact to use instead under
+// DuplicateEmailReturn, a *DuplicateEmailError under DuplicateEmailReject, or
+// nil when the contact should be created. Archived contacts count, and emails
+// match case-insensitively; an empty email never matches.
+func (c *Client) CheckDuplicateEmail(email, policy string) (*Contact, error) {
+	if err := ValidateDuplicateEmailPolicy(policy); err != nil {
+		return nil, err
+	}
+	if policy == DuplicateEmailAllow || strings.TrimSpace(email) == "" {
+		return nil, nil
+	}
+
+	existing, err := c.FindContactByEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup contact: %w", err)
+	}
+	if existing != nil && policy == DuplicateEmailReject {
+		return nil, &DuplicateEmailError{Email: email, Existing: existing}
+	}
+	return existing, nil
+}
+
+// CreateContactUnique creates contact unless CheckDuplicateEmail finds
+// another contact with its email. It returns the contact created or found,
+// and whether it already existed.
+func (c *Client) CreateContactUnique(contact *Contact, policy string) (*Contact, bool, error) {
+	existing, err := c.CheckDuplicateEmail(contact.Email, policy)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	if err := c.CreateContact(contact); err != nil {
+		return nil, false, err
+	}
+	return contact, false, nil
+}
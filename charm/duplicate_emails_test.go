@@ -0,0 +1,64 @@
+// ABOUTME: Tests for the duplicate contact email policies
+// ABOUTME: Verifies allow, return, and reject, and how each entry point's policy is chosen
+package charm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateContactUnique(t *testing.T) {
+	client := NewTestClient(t)
+	ada := &Contact{Name: "Ada", Email: "ada@example.com"}
+	if err := client.CreateContact(ada); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	// Return hands back the existing contact, matching case-insensitively
+	got, existed, err := client.CreateContactUnique(&Contact{Name: "Ada L", Email: "ADA@example.com"}, DuplicateEmailReturn)
+	if err != nil {
+		t.Fatalf("CreateContactUnique failed: %v", err)
+	}
+	if !existed || got.ID != ada.ID {
+		t.Errorf("expected the existing contact, got %+v (existed=%v)", got, existed)
+	}
+
+	// Reject names the existing contact
+	_, _, err = client.CreateContactUnique(&Contact{Name: "Ada L", Email: "ada@example.com"}, DuplicateEmailReject)
+	var dupErr *DuplicateEmailError
+	if !errors.As(err, &dupErr) || dupErr.Existing.ID != ada.ID {
+		t.Fatalf("expected a DuplicateEmailError for Ada, got %v", err)
+	}
+
+	// Allow creates another contact
+	got, existed, err = client.CreateContactUnique(&Contact{Name: "Ada L", Email: "ada@example.com"}, DuplicateEmailAllow)
+	if err != nil || existed || got.ID == ada.ID {
+		t.Errorf("expected a new contact, got %+v (existed=%v, err=%v)", got, existed, err)
+	}
+
+	// Contacts without an email never collide
+	if _, existed, err := client.CreateContactUnique(&Contact{Name: "No Email"}, DuplicateEmailReject); err != nil || existed {
+		t.Errorf("expected a contact without email to be created, got existed=%v err=%v", existed, err)
+	}
+	if _, _, err := client.CreateContactUnique(&Contact{Name: "Bad"}, "ignore"); err == nil {
+		t.Error("expected an invalid policy to be rejected")
+	}
+}
+
+func TestDuplicateEmailPolicy(t *testing.T) {
+	client := NewTestClient(t)
+	if got := client.DuplicateEmailPolicy(EntryMCP); got != DuplicateEmailReturn {
+		t.Errorf("expected MCP to return existing contacts by default, got %s", got)
+	}
+	if got := client.DuplicateEmailPolicy(EntryCLI); got != DuplicateEmailAllow {
+		t.Errorf("expected the CLI to allow duplicates by default, got %s", got)
+	}
+
+	client.Config().DuplicateEmails = map[string]string{"default": DuplicateEmailReject, EntryMCP: DuplicateEmailReturn}
+	if got := client.DuplicateEmailPolicy(EntryCLI); got != DuplicateEmailReject {
+		t.Errorf("expected the default setting to apply to the CLI, got %s", got)
+	}
+	if got := client.DuplicateEmailPolicy(EntryMCP); got != DuplicateEmailReturn {
+		t.Errorf("expected the MCP setting to win over the default, got %s", got)
+	}
+}
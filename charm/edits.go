@@ -0,0 +1,63 @@
+// ABOUTME: Versioned writes for contacts, companies, and deals
+// ABOUTME: Bumps each object's version under the KV write lock and refuses edits based on an older one
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// anyVersion tells writeVersioned to save whatever version is stored.
+const anyVersion = -1
+
+// UpdateContactIfUnchanged updates contact unless the stored contact is no
+// longer at contact.Version, the version the edit is based on. Otherwise it
+// returns a conflict error so a concurrent edit isn't silently overwritten.
+func (c *Client) UpdateContactIfUnchanged(contact *Contact) error {
+	return c.updateContact(contact, contact.Version)
+}
+
+// UpdateCompanyIfUnchanged is UpdateContactIfUnchanged for companies.
+func (c *Client) UpdateCompanyIfUnchanged(company *Company) error {
+	return c.updateCompany(company, company.Version)
+}
+
+// UpdateDealIfUnchanged is UpdateContactIfUnchanged for deals.
+func (c *Client) UpdateDealIfUnchanged(deal *Deal) error {
+	return c.updateDeal(deal, deal.Version)
+}
+
+// writeVersioned saves an object under key as the version after the stored
+// copy's, reading that version and writing under the same write lock so two
+// writers can't both save on top of one version. Unless expected is
+// anyVersion, a stored copy at another version is a conflict. stamp sets the
+// object's version and returns its encoding, which is returned for the
+// revision log.
+func (c *Client) writeVersioned(entityType string, id uuid.UUID, key []byte, expected int64, stamp func(version int64) ([]byte, error)) ([]byte, error) {
+	var data []byte
+	err := c.update(key, func(stored []byte) ([]byte, error) {
+		var current struct {
+			Version int64 `json:"version"`
+		}
+		if stored == nil && expected != anyVersion {
+			return nil, NotFoundf("%s not found: %s", entityType, id)
+		}
+		if stored != nil {
+			if err := json.Unmarshal(stored, &current); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal %s: %w", entityType, err)
+			}
+		}
+		if expected != anyVersion && current.Version != expected {
+			return nil, Conflictf("%s %s changed since the version this edit is based on (version %d, now %d); read it again and retry",
+				entityType, FormatID(id), expected, current.Version)
+		}
+
+		var err error
+		data, err = stamp(current.Version + 1)
+		return data, err
+	})
+	return data, err
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Tests for versioned writes and optimistic concurrency on edits
+// ABOUTME: Verifies every write bumps the version and an edit of an older version is refused with a conflict
+
+package charm
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUpdateIfUnchanged(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Ada"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	if contact.Version != 1 {
+		t.Fatalf("expected a new contact at version 1, got %d", contact.Version)
+	}
+
+	// An edit of the current version goes through and bumps it
+	outdated := *contact
+	current, _ := client.GetContact(contact.ID)
+	current.Title = "CEO"
+	if err := client.UpdateContactIfUnchanged(current); err != nil {
+		t.Fatalf("UpdateContactIfUnchanged failed: %v", err)
+	}
+	if got, _ := client.GetContact(contact.ID); got.Version != 2 {
+		t.Errorf("expected version 2 after the edit, got %d", got.Version)
+	}
+
+	// One based on the version read before that save is refused
+	outdated.Title = "CTO"
+	if err := client.UpdateContactIfUnchanged(&outdated); CodeOf(err) != CodeConflict {
+		t.Fatalf("expected a conflict for the outdated edit, got %v", err)
+	}
+	if got, _ := client.GetContact(contact.ID); got.Title != "CEO" {
+		t.Errorf("expected the first edit kept, got title %q", got.Title)
+	}
+
+	// Plain updates bump the version too, so an edit based on the old one fails
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	stale := *company
+	if err := client.UpdateCompany(company); err != nil {
+		t.Fatalf("UpdateCompany failed: %v", err)
+	}
+	if err := client.UpdateCompanyIfUnchanged(&stale); CodeOf(err) != CodeConflict {
+		t.Errorf("expected a company conflict, got %v", err)
+	}
+
+	deal := &Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	staleDeal := *deal
+	if err := client.UpdateDealIfUnchanged(deal); err != nil {
+		t.Errorf("expected a current deal edit to succeed, got %v", err)
+	}
+	if err := client.UpdateDealIfUnchanged(&staleDeal); CodeOf(err) != CodeConflict {
+		t.Errorf("expected a deal conflict, got %v", err)
+	}
+}
+
+func TestUpdateIfUnchangedConcurrent(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Ada"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	// Writers editing the same version at once: exactly one wins
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			edit := *contact
+			edit.Title = string(rune('A' + i))
+			errs[i] = client.UpdateContactIfUnchanged(&edit)
+		}()
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			saved++
+		case CodeOf(err) != CodeConflict:
+			t.Errorf("expected only conflicts, got %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Errorf("expected exactly one writer to save, got %d", saved)
+	}
+	if got, _ := client.GetContact(contact.ID); got.Version != 2 {
+		t.Errorf("expected version 2, got %d", got.Version)
+	}
+}
@@ -0,0 +1,181 @@
+// ABOUTME: Gmail conversation threads with contacts and who spoke last
+// ABOUTME: Flags threads where the contact hasn't replied within a few days as pending reply follow-ups
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// Email thread directions: who sent the last message.
+const (
+	ThreadSent     = "sent"
+	ThreadReceived = "received"
+)
+
+// DefaultReplyWaitDays is how long a sent email waits for a reply before it
+// becomes a pending follow-up.
+const DefaultReplyWaitDays = 3
+
+// EmailThread is the state of one Gmail conversation with a contact. Only the
+// latest message matters: a thread whose last message was sent is waiting on
+// the contact.
+type EmailThread struct {
+	ThreadID       string     `json:"thread_id"`
+	ContactID      uuid.UUID  `json:"contact_id"`
+	ContactName    string     `json:"contact_name,omitempty"` // denormalized
+	Email          string     `json:"email"`
+	Subject        string     `json:"subject,omitempty"`
+	LastDirection  string     `json:"last_direction"` // sent or received
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	PendingAt      *time.Time `json:"pending_at,omitempty"` // when it became a pending follow-up
+}
+
+// DaysWaiting is how many whole days the thread has waited since its last message.
+func (t *EmailThread) DaysWaiting(now time.Time) int {
+	return int(now.Sub(t.LastActivityAt).Hours() / 24)
+}
+
+// ReplyWaitDays returns the configured reply wait, or DefaultReplyWaitDays.
+func ReplyWaitDays(cfg *Config) int {
+	if cfg == nil || cfg.ReplyWaitDays <= 0 {
+		return DefaultReplyWaitDays
+	}
+	return cfg.ReplyWaitDays
+}
+
+// GetEmailThread returns the stored thread, or nil if it isn't tracked.
+func (c *Client) GetEmailThread(threadID string) (*EmailThread, error) {
+	data, err := c.Get(EmailThreadKey(threadID))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var thread EmailThread
+	if err := json.Unmarshal(data, &thread); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal email thread: %w", err)
+	}
+	return &thread, nil
+}
+
+// RecordThreadMessage updates a thread with a message sent to or received
+// from contact at the given time. Messages older than the thread's last
+// activity are ignored, so a mailbox can be scanned again safely. Any newer
+// message clears a pending follow-up: a reply answers it, and a nudge
+// restarts the wait.
+func (c *Client) RecordThreadMessage(threadID string, contact *Contact, direction, subject string, at time.Time) (*EmailThread, error) {
+	if direction != ThreadSent && direction != ThreadReceived {
+		return nil, Validationf("invalid thread direction: %s", direction)
+	}
+	thread, err := c.GetEmailThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil {
+		thread = &EmailThread{ThreadID: threadID}
+	} else if !at.After(thread.LastActivityAt) {
+		return thread, nil
+	}
+
+	thread.ContactID = contact.ID
+	thread.ContactName = contact.Name
+	thread.Email = contact.Email
+	if thread.Subject == "" {
+		thread.Subject = subject
+	}
+	thread.LastDirection = direction
+	thread.LastActivityAt = at
+	thread.PendingAt = nil
+	return thread, c.saveEmailThread(thread)
+}
+
+// ListEmailThreads returns every tracked thread, most recent activity first.
+func (c *Client) ListEmailThreads() ([]*EmailThread, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixEmailThread))
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []*EmailThread
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var thread EmailThread
+		if err := json.Unmarshal(data, &thread); err != nil {
+			continue
+		}
+		threads = append(threads, &thread)
+	}
+
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].LastActivityAt.After(threads[j].LastActivityAt)
+	})
+	return threads, nil
+}
+
+// MarkPendingReplies turns threads whose last message was sent at least days
+// ago into pending follow-ups and returns the ones newly marked.
+func (c *Client) MarkPendingReplies(days int, now time.Time) ([]*EmailThread, error) {
+	threads, err := c.ListEmailThreads()
+	if err != nil {
+		return nil, err
+	}
+
+	var marked []*EmailThread
+	for _, thread := range threads {
+		if thread.LastDirection != ThreadSent || thread.PendingAt != nil || thread.DaysWaiting(now) < days {
+			continue
+		}
+		pendingAt := now
+		thread.PendingAt = &pendingAt
+		if err := c.saveEmailThread(thread); err != nil {
+			return marked, err
+		}
+		marked = append(marked, thread)
+	}
+	return marked, nil
+}
+
+// ListPendingReplies returns the pending reply follow-ups, longest waiting first.
+func (c *Client) ListPendingReplies() ([]*EmailThread, error) {
+	threads, err := c.ListEmailThreads()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*EmailThread
+	for _, thread := range threads {
+		if thread.PendingAt != nil {
+			pending = append(pending, thread)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].LastActivityAt.Before(pending[j].LastActivityAt)
+	})
+	return pending, nil
+}
+
+func (c *Client) saveEmailThread(thread *EmailThread) error {
+	data, err := json.Marshal(thread)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email thread: %w", err)
+	}
+	return c.Set(EmailThreadKey(thread.ThreadID), data)
+}
@@ -0,0 +1,73 @@
+// ABOUTME: Tests for Gmail thread state and pending reply follow-ups
+// ABOUTME: Verifies last-message tracking, out-of-order messages, and when threads become and stop being pending
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingReplies(t *testing.T) {
+	client := NewTestClient(t)
+	ada := &Contact{Name: "Ada", Email: "ada@example.com"}
+	if err := client.CreateContact(ada); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	now := time.Now()
+	sent := now.AddDate(0, 0, -4)
+	if _, err := client.RecordThreadMessage("t1", ada, ThreadSent, "Proposal", sent); err != nil {
+		t.Fatalf("RecordThreadMessage failed: %v", err)
+	}
+	// An older message read later doesn't change the thread
+	thread, err := client.RecordThreadMessage("t1", ada, ThreadReceived, "Proposal", sent.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("RecordThreadMessage failed: %v", err)
+	}
+	if thread.LastDirection != ThreadSent || !thread.LastActivityAt.Equal(sent) {
+		t.Errorf("expected the later sent message to stay last, got %s at %v", thread.LastDirection, thread.LastActivityAt)
+	}
+	// A thread sent yesterday isn't pending yet
+	if _, err := client.RecordThreadMessage("t2", ada, ThreadSent, "Lunch?", now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("RecordThreadMessage failed: %v", err)
+	}
+
+	marked, err := client.MarkPendingReplies(3, now)
+	if err != nil {
+		t.Fatalf("MarkPendingReplies failed: %v", err)
+	}
+	if len(marked) != 1 || marked[0].ThreadID != "t1" {
+		t.Fatalf("expected t1 to become pending, got %+v", marked)
+	}
+	if again, _ := client.MarkPendingReplies(3, now); len(again) != 0 {
+		t.Errorf("expected no newly pending threads on a second pass, got %d", len(again))
+	}
+	pending, err := client.ListPendingReplies()
+	if err != nil {
+		t.Fatalf("ListPendingReplies failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ContactName != "Ada" || pending[0].DaysWaiting(now) != 4 {
+		t.Fatalf("unexpected pending replies: %+v", pending)
+	}
+
+	// The reply clears it
+	if _, err := client.RecordThreadMessage("t1", ada, ThreadReceived, "Re: Proposal", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("RecordThreadMessage failed: %v", err)
+	}
+	if pending, _ := client.ListPendingReplies(); len(pending) != 0 {
+		t.Errorf("expected the reply to clear the pending follow-up, got %d", len(pending))
+	}
+
+	if _, err := client.RecordThreadMessage("t3", ada, "forwarded", "", now); err == nil {
+		t.Error("expected an invalid direction to be rejected")
+	}
+}
+
+func TestReplyWaitDays(t *testing.T) {
+	if got := ReplyWaitDays(nil); got != DefaultReplyWaitDays {
+		t.Errorf("expected the default wait, got %d", got)
+	}
+	if got := ReplyWaitDays(&Config{ReplyWaitDays: 7}); got != 7 {
+		t.Errorf("expected the configured wait, got %d", got)
+	}
+}
@@ -0,0 +1,150 @@
+// ABOUTME: Engagement decay model for follow-up prioritization
+// ABOUTME: Scores decay daily and are boosted by interactions weighted by channel and duration
+
+package charm
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EngagementHalfLifeDays is how long it takes an engagement boost to lose half its weight.
+const EngagementHalfLifeDays = 14.0
+
+// interactionWeights is the engagement boost per channel. Richer channels count for more.
+var interactionWeights = map[string]float64{
+	InteractionMeeting: 3.0,
+	InteractionCall:    2.0,
+	InteractionEvent:   1.5,
+	InteractionEmail:   1.0,
+	InteractionMessage: 0.5,
+}
+
+// InteractionWeight returns the engagement boost for a single interaction.
+// Interactions recording a duration_minutes in their metadata get up to
+// double weight for two hours or more.
+func InteractionWeight(log *InteractionLog) float64 {
+	weight, ok := interactionWeights[log.InteractionType]
+	if !ok {
+		weight = 1.0
+	}
+
+	if log.Metadata != "" {
+		var metadata struct {
+			DurationMinutes float64 `json:"duration_minutes"`
+		}
+		if err := json.Unmarshal([]byte(log.Metadata), &metadata); err == nil && metadata.DurationMinutes > 0 {
+			weight *= 1 + math.Min(metadata.DurationMinutes, 120)/120
+		}
+	}
+
+	return weight
+}
+
+// DecayEngagement applies exponential decay to a score last computed at from.
+func DecayEngagement(score float64, from, now time.Time) float64 {
+	days := now.Sub(from).Hours() / 24
+	if days <= 0 {
+		return score
+	}
+	return score * math.Pow(0.5, days/EngagementHalfLifeDays)
+}
+
+// EngagementScore sums the decayed weight of every interaction as of now.
+func EngagementScore(logs []*InteractionLog, now time.Time) float64 {
+	score := 0.0
+	for _, log := range logs {
+		if log.Timestamp.After(now) {
+			continue
+		}
+		score += DecayEngagement(InteractionWeight(log), log.Timestamp, now)
+	}
+	return score
+}
+
+// strengthMultiplier weights priority by relationship strength.
+func strengthMultiplier(strength string) float64 {
+	switch strength {
+	case StrengthStrong:
+		return 2.0
+	case StrengthMedium:
+		return 1.5
+	default:
+		return 1.0
+	}
+}
+
+// CurrentEngagement returns the cadence's engagement score decayed to now.
+func (cadence *ContactCadence) CurrentEngagement(now time.Time) float64 {
+	if cadence.EngagementUpdatedAt == nil {
+		return cadence.EngagementScore
+	}
+	return DecayEngagement(cadence.EngagementScore, *cadence.EngagementUpdatedAt, now)
+}
+
+// ComputePriorityScore scores how urgently a contact needs follow-up.
+// Contacts within their cadence score zero. Overdue contacts score
+// (days overdue × 2) × strength multiplier, scaled up to 2x as their
+// engagement decays towards zero.
+func ComputePriorityScore(cadence *ContactCadence, now time.Time) float64 {
+	if cadence.LastInteractionDate == nil {
+		return 0.0
+	}
+
+	daysSinceContact := int(now.Sub(*cadence.LastInteractionDate).Hours() / 24)
+	daysOverdue := daysSinceContact - cadence.CadenceDays
+	if daysOverdue <= 0 {
+		return 0.0
+	}
+
+	base := float64(daysOverdue*2) * strengthMultiplier(cadence.RelationshipStrength)
+	return base * (1 + 1/(1+cadence.CurrentEngagement(now)))
+}
+
+// RecomputePriorityScores rebuilds every cadence's engagement score from its
+// interaction history and refreshes its priority. Returns the number updated.
+func (c *Client) RecomputePriorityScores(now time.Time) (int, error) {
+	cadences, err := c.ListContactCadences()
+	if err != nil {
+		return 0, err
+	}
+
+	logs, err := c.ListInteractionLogs(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	byContact := make(map[uuid.UUID][]*InteractionLog)
+	for _, log := range logs {
+		byContact[log.ContactID] = append(byContact[log.ContactID], log)
+	}
+
+	updated := 0
+	for _, cadence := range cadences {
+		cadence.EngagementScore = EngagementScore(byContact[cadence.ContactID], now)
+		computedAt := now
+		cadence.EngagementUpdatedAt = &computedAt
+		cadence.PriorityScore = ComputePriorityScore(cadence, now)
+
+		if err := c.SaveContactCadence(cadence); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// sortFollowups orders follow-ups by priority, breaking ties by lowest engagement.
+func sortFollowups(followups []*FollowupContact) {
+	sort.SliceStable(followups, func(i, j int) bool {
+		if followups[i].PriorityScore != followups[j].PriorityScore {
+			return followups[i].PriorityScore > followups[j].PriorityScore
+		}
+		return followups[i].EngagementScore < followups[j].EngagementScore
+	})
+}
@@ -0,0 +1,121 @@
+// ABOUTME: Tests for the engagement decay model
+// ABOUTME: Verifies half-life decay, channel weighting, and follow-up ordering
+
+package charm
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayEngagementHalfLife(t *testing.T) {
+	now := time.Now()
+	from := now.Add(-time.Duration(EngagementHalfLifeDays*24) * time.Hour)
+
+	got := DecayEngagement(10, from, now)
+	if math.Abs(got-5) > 0.001 {
+		t.Errorf("expected score to halve after one half-life, got %.3f", got)
+	}
+
+	if got := DecayEngagement(10, now.Add(time.Hour), now); got != 10 {
+		t.Errorf("expected future timestamps not to decay, got %.3f", got)
+	}
+}
+
+func TestInteractionWeight(t *testing.T) {
+	meeting := &InteractionLog{InteractionType: InteractionMeeting}
+	email := &InteractionLog{InteractionType: InteractionEmail}
+	if InteractionWeight(meeting) <= InteractionWeight(email) {
+		t.Error("expected meetings to outweigh emails")
+	}
+
+	long := &InteractionLog{InteractionType: InteractionMeeting, Metadata: `{"duration_minutes": 240}`}
+	if got := InteractionWeight(long); got != 6.0 {
+		t.Errorf("expected long meeting weight capped at 6.0, got %.2f", got)
+	}
+}
+
+func TestComputePriorityScore(t *testing.T) {
+	now := time.Now()
+	last := now.AddDate(0, 0, -40)
+
+	cadence := &ContactCadence{
+		CadenceDays:          30,
+		RelationshipStrength: StrengthStrong,
+		LastInteractionDate:  &last,
+	}
+
+	// 10 days overdue × 2 × strong (2.0) × no engagement (2.0)
+	if got := ComputePriorityScore(cadence, now); got != 80 {
+		t.Errorf("expected priority 80, got %.2f", got)
+	}
+
+	cadence.EngagementScore = 3
+	cadence.EngagementUpdatedAt = &now
+	if got := ComputePriorityScore(cadence, now); got != 50 {
+		t.Errorf("expected engaged contact priority 50, got %.2f", got)
+	}
+
+	recent := now.AddDate(0, 0, -5)
+	cadence.LastInteractionDate = &recent
+	if got := ComputePriorityScore(cadence, now); got != 0 {
+		t.Errorf("expected contact within cadence to score 0, got %.2f", got)
+	}
+}
+
+func TestGetFollowupListOrdersByEngagement(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+	last := now.AddDate(0, 0, -40)
+
+	engaged := &Contact{Name: "Engaged", LastContactedAt: &last}
+	quiet := &Contact{Name: "Quiet", LastContactedAt: &last}
+	for _, contact := range []*Contact{engaged, quiet} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+		if err := client.SaveContactCadence(&ContactCadence{
+			ContactID:            contact.ID,
+			ContactName:          contact.Name,
+			CadenceDays:          30,
+			RelationshipStrength: StrengthMedium,
+			LastInteractionDate:  &last,
+		}); err != nil {
+			t.Fatalf("failed to save cadence: %v", err)
+		}
+	}
+
+	// A burst of older meetings keeps the engaged contact warm
+	for i := 0; i < 3; i++ {
+		if err := client.CreateInteractionLog(&InteractionLog{
+			ContactID:       engaged.ID,
+			InteractionType: InteractionMeeting,
+			Timestamp:       last.AddDate(0, 0, -i),
+		}); err != nil {
+			t.Fatalf("failed to log interaction: %v", err)
+		}
+	}
+
+	updated, err := client.RecomputePriorityScores(now)
+	if err != nil {
+		t.Fatalf("RecomputePriorityScores failed: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("expected 2 cadences updated, got %d", updated)
+	}
+
+	followups, err := client.GetFollowupList(10)
+	if err != nil {
+		t.Fatalf("GetFollowupList failed: %v", err)
+	}
+	if len(followups) != 2 {
+		t.Fatalf("expected 2 followups, got %d", len(followups))
+	}
+	if followups[0].Name != "Quiet" {
+		t.Errorf("expected the disengaged contact first, got %s", followups[0].Name)
+	}
+	if followups[1].EngagementScore <= 0 {
+		t.Errorf("expected engaged contact to carry an engagement score, got %.2f", followups[1].EngagementScore)
+	}
+}
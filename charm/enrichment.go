@@ -0,0 +1,276 @@
+// ABOUTME: Contact enrichment with seniority and job function
+// ABOUTME: Pluggable classifiers (an LLM, or title keyword rules) and writing results back to contacts
+
+package charm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Seniority levels, most senior first.
+const (
+	SeniorityExecutive  = "executive" // C-level, founder, president, partner
+	SeniorityVP         = "vp"
+	SeniorityDirector   = "director"
+	SeniorityManager    = "manager"
+	SeniorityIndividual = "individual" // individual contributor
+)
+
+// Seniorities lists the valid seniority levels.
+var Seniorities = []string{SeniorityExecutive, SeniorityVP, SeniorityDirector, SeniorityManager, SeniorityIndividual}
+
+// Functions lists the valid job functions.
+var Functions = []string{
+	"engineering", "product", "design", "data", "sales", "marketing", "customer_success",
+	"operations", "finance", "legal", "people", "research", "general_management", "other",
+}
+
+// ContactClassification is a contact's seniority and job function, as
+// classified from what we know about them.
+type ContactClassification struct {
+	Seniority string `json:"seniority,omitempty"`
+	Function  string `json:"function,omitempty"`
+	Source    string `json:"source"` // what classified it, e.g. "rules" or "llm:<model>"
+}
+
+// Classifier classifies a contact from a plain-text description of them
+// (see ClassificationText). Implementations return an empty classification
+// when there is too little to go on.
+type Classifier interface {
+	Classify(ctx context.Context, text string) (*ContactClassification, error)
+}
+
+// NormalizeSeniority returns the seniority level for s, accepting common
+// spellings ("C-level", "Vice President", "IC"). Empty is unknown.
+func NormalizeSeniority(s string) (string, error) {
+	key := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(s)))
+	switch key {
+	case "":
+		return "", nil
+	case "c_level", "c_suite", "exec", "founder", "owner", "partner":
+		return SeniorityExecutive, nil
+	case "vice_president", "svp", "evp", "head":
+		return SeniorityVP, nil
+	case "ic", "individual_contributor", "senior", "junior", "entry", "staff":
+		return SeniorityIndividual, nil
+	}
+	for _, valid := range Seniorities {
+		if key == valid {
+			return key, nil
+		}
+	}
+	return "", Validationf("invalid seniority %q (use %s)", s, strings.Join(Seniorities, ", "))
+}
+
+// NormalizeFunction returns the job function for s ("Customer Success" is
+// "customer_success"). Empty is unknown.
+func NormalizeFunction(s string) (string, error) {
+	key := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(s)))
+	switch key {
+	case "":
+		return "", nil
+	case "hr", "human_resources", "talent", "recruiting":
+		return "people", nil
+	case "management", "executive", "leadership":
+		return "general_management", nil
+	case "support", "customer_support":
+		return "customer_success", nil
+	}
+	for _, valid := range Functions {
+		if key == valid {
+			return key, nil
+		}
+	}
+	return "", Validationf("invalid function %q (use %s)", s, strings.Join(Functions, ", "))
+}
+
+// ClassificationText describes a contact for a classifier: their title,
+// company, pinned facts, and notes.
+func ClassificationText(contact *Contact) string {
+	var lines []string
+	add := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	add("Name", contact.Name)
+	add("Title", contact.Title)
+	add("Company", contact.CompanyName)
+	for _, fact := range contact.Pinned {
+		add("Fact", fact)
+	}
+	add("Notes", contact.Notes)
+	return strings.Join(lines, "\n")
+}
+
+// Role summarizes the contact's classified seniority and function, e.g.
+// "VP, customer success". Empty when neither is known.
+func (c *Contact) Role() string {
+	seniority := c.Seniority
+	switch seniority {
+	case SeniorityVP:
+		seniority = "VP"
+	case SeniorityIndividual:
+		seniority = "Individual contributor"
+	case "":
+	default:
+		seniority = strings.ToUpper(seniority[:1]) + seniority[1:]
+	}
+	function := strings.ReplaceAll(c.Function, "_", " ")
+	switch {
+	case seniority == "":
+		if function == "" {
+			return ""
+		}
+		return strings.ToUpper(function[:1]) + function[1:]
+	case function == "":
+		return seniority
+	default:
+		return seniority + ", " + function
+	}
+}
+
+// ApplyClassification writes a classification back to a contact. Unknown
+// fields in the classification leave the contact's values alone.
+func (c *Client) ApplyClassification(contactID uuid.UUID, classification *ContactClassification) (*Contact, error) {
+	seniority, err := NormalizeSeniority(classification.Seniority)
+	if err != nil {
+		return nil, err
+	}
+	function, err := NormalizeFunction(classification.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := c.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+	if seniority == "" && function == "" {
+		return contact, nil
+	}
+	if seniority != "" {
+		contact.Seniority = seniority
+	}
+	if function != "" {
+		contact.Function = function
+	}
+	now := time.Now()
+	contact.EnrichedAt = &now
+	contact.EnrichedBy = classification.Source
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	return contact, nil
+}
+
+// ListUnenrichedContacts returns up to limit active contacts that have no
+// seniority or function yet, newest first, for enriching new contacts.
+func (c *Client) ListUnenrichedContacts(limit int) ([]*Contact, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Contact
+	for _, contact := range contacts {
+		if contact.Seniority == "" && contact.Function == "" && contact.EnrichedAt == nil {
+			pending = append(pending, contact)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].CreatedAt.After(pending[j].CreatedAt) })
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+// RuleClassifier classifies contacts from keywords in their job title. It
+// needs no LLM, so it is the fallback when none is available.
+type RuleClassifier struct{}
+
+// seniorityRules and functionRules are checked in order; the first keyword
+// found in the title wins.
+var seniorityRules = []struct {
+	level    string
+	keywords []string
+}{
+	{SeniorityExecutive, []string{"chief", "ceo", "cto", "cfo", "coo", "cmo", "cio", "cpo", "founder", "president", "owner", "partner"}},
+	{SeniorityVP, []string{"vp", "vice president", "svp", "evp", "head of"}},
+	{SeniorityDirector, []string{"director"}},
+	{SeniorityManager, []string{"manager", "lead", "supervisor"}},
+	{SeniorityIndividual, []string{"engineer", "developer", "designer", "analyst", "associate", "specialist", "scientist", "coordinator", "representative", "consultant", "intern", "accountant", "recruiter"}},
+}
+
+var functionRules = []struct {
+	function string
+	keywords []string
+}{
+	{"engineering", []string{"engineer", "developer", "cto", "devops", "sre", "architect", "technical"}},
+	{"data", []string{"data", "analytics", "machine learning"}},
+	{"product", []string{"product", "cpo"}},
+	{"design", []string{"design", "ux", "ui "}},
+	{"sales", []string{"sales", "account executive", "business development", "bdr", "sdr", "partnerships"}},
+	{"marketing", []string{"marketing", "growth", "brand", "content", "cmo", "communications"}},
+	{"customer_success", []string{"customer", "support", "success"}},
+	{"finance", []string{"finance", "cfo", "accounting", "accountant", "controller"}},
+	{"legal", []string{"legal", "counsel", "attorney", "lawyer"}},
+	{"people", []string{"people", "hr", "human resources", "talent", "recruit"}},
+	{"research", []string{"research", "scientist"}},
+	{"operations", []string{"operations", "coo", "ops"}},
+	{"general_management", []string{"ceo", "founder", "president", "general manager", "owner"}},
+}
+
+// Classify finds the title in text and matches it against keyword rules.
+func (RuleClassifier) Classify(_ context.Context, text string) (*ContactClassification, error) {
+	title := ""
+	for _, line := range strings.Split(text, "\n") {
+		if value, ok := strings.CutPrefix(line, "Title: "); ok {
+			title = " " + strings.ToLower(value) + " "
+		}
+	}
+
+	classification := &ContactClassification{Source: "rules"}
+	if title == "" {
+		return classification, nil
+	}
+	hasWord := func(keyword string) bool {
+		// Short keywords like "vp" or "hr" must be whole words
+		if len(keyword) <= 3 {
+			for _, word := range strings.FieldsFunc(title, func(r rune) bool { return !('a' <= r && r <= 'z') }) {
+				if word == keyword {
+					return true
+				}
+			}
+			return false
+		}
+		return strings.Contains(title, keyword)
+	}
+	for _, rule := range seniorityRules {
+		if containsAny(rule.keywords, hasWord) {
+			classification.Seniority = rule.level
+			break
+		}
+	}
+	for _, rule := range functionRules {
+		if containsAny(rule.keywords, hasWord) {
+			classification.Function = rule.function
+			break
+		}
+	}
+	return classification, nil
+}
+
+func containsAny(keywords []string, has func(string) bool) bool {
+	for _, keyword := range keywords {
+		if has(keyword) {
+			return true
+		}
+	}
+	return false
+}
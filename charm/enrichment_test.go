@@ -0,0 +1,124 @@
+// ABOUTME: Tests for contact enrichment
+// ABOUTME: Covers title keyword rules, normalization, role summaries, and applying classifications
+
+package charm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRuleClassifier(t *testing.T) {
+	tests := []struct {
+		title     string
+		seniority string
+		function  string
+	}{
+		{"CTO", SeniorityExecutive, "engineering"},
+		{"Co-Founder & CEO", SeniorityExecutive, "general_management"},
+		{"VP of Sales", SeniorityVP, "sales"},
+		{"Head of People", SeniorityVP, "people"},
+		{"Director, Product Marketing", SeniorityDirector, "product"},
+		{"Engineering Manager", SeniorityManager, "engineering"},
+		{"Senior Software Engineer", SeniorityIndividual, "engineering"},
+		{"Shipping Clerk", "", ""}, // "hr" inside a word doesn't count
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		classification, err := RuleClassifier{}.Classify(context.Background(), ClassificationText(&Contact{Name: "Alice", Title: tt.title}))
+		if err != nil {
+			t.Fatalf("Classify(%q) failed: %v", tt.title, err)
+		}
+		if classification.Seniority != tt.seniority || classification.Function != tt.function {
+			t.Errorf("Classify(%q) = %s/%s, want %s/%s", tt.title, classification.Seniority, classification.Function, tt.seniority, tt.function)
+		}
+		if classification.Source != "rules" {
+			t.Errorf("expected source rules, got %q", classification.Source)
+		}
+	}
+}
+
+func TestNormalizeSeniorityAndFunction(t *testing.T) {
+	if got, err := NormalizeSeniority("Vice President"); err != nil || got != SeniorityVP {
+		t.Errorf("NormalizeSeniority(Vice President) = %q, %v", got, err)
+	}
+	if got, err := NormalizeSeniority("C-Level"); err != nil || got != SeniorityExecutive {
+		t.Errorf("NormalizeSeniority(C-Level) = %q, %v", got, err)
+	}
+	if _, err := NormalizeSeniority("intern-ish"); err == nil {
+		t.Error("expected an error for an unknown seniority")
+	}
+	if got, err := NormalizeFunction("Customer Success"); err != nil || got != "customer_success" {
+		t.Errorf("NormalizeFunction(Customer Success) = %q, %v", got, err)
+	}
+	if got, err := NormalizeFunction("HR"); err != nil || got != "people" {
+		t.Errorf("NormalizeFunction(HR) = %q, %v", got, err)
+	}
+}
+
+func TestContactRole(t *testing.T) {
+	tests := []struct {
+		contact Contact
+		want    string
+	}{
+		{Contact{Seniority: SeniorityVP, Function: "customer_success"}, "VP, customer success"},
+		{Contact{Seniority: SeniorityDirector}, "Director"},
+		{Contact{Function: "engineering"}, "Engineering"},
+		{Contact{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.contact.Role(); got != tt.want {
+			t.Errorf("Role() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestApplyClassification(t *testing.T) {
+	client := NewTestClient(t)
+
+	alice := &Contact{Name: "Alice", Title: "VP Engineering"}
+	bob := &Contact{Name: "Bob", Seniority: SeniorityManager}
+	for _, contact := range []*Contact{alice, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	pending, err := client.ListUnenrichedContacts(0)
+	if err != nil {
+		t.Fatalf("ListUnenrichedContacts failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != alice.ID {
+		t.Fatalf("expected only Alice pending, got %d contacts", len(pending))
+	}
+
+	updated, err := client.ApplyClassification(alice.ID, &ContactClassification{Seniority: "Vice President", Function: "engineering", Source: "rules"})
+	if err != nil {
+		t.Fatalf("ApplyClassification failed: %v", err)
+	}
+	if updated.Seniority != SeniorityVP || updated.Function != "engineering" || updated.EnrichedBy != "rules" || updated.EnrichedAt == nil {
+		t.Errorf("unexpected enrichment: %+v", updated)
+	}
+
+	// An empty classification leaves the contact alone
+	updated, err = client.ApplyClassification(bob.ID, &ContactClassification{Source: "rules"})
+	if err != nil {
+		t.Fatalf("ApplyClassification failed: %v", err)
+	}
+	if updated.Seniority != SeniorityManager || updated.EnrichedAt != nil {
+		t.Errorf("expected Bob unchanged, got %+v", updated)
+	}
+
+	if _, err := client.ApplyClassification(alice.ID, &ContactClassification{Seniority: "boss"}); err == nil {
+		t.Error("expected an error for an invalid seniority")
+	}
+
+	pending, err = client.ListUnenrichedContacts(0)
+	if err != nil {
+		t.Fatalf("ListUnenrichedContacts failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending contacts, got %d", len(pending))
+	}
+}
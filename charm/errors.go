@@ -0,0 +1,109 @@
+// ABOUTME: Stable error codes (not-found, validation, conflict, sync-unavailable) for failures callers branch on
+// ABOUTME: Coded errors keep their message and match sentinel errors, so errors.Is works through wrapping
+package charm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	charmproto "github.com/charmbracelet/charm/proto"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// ErrorCode names a kind of failure. The values are stable: scripts match on
+// them in --json error output.
+type ErrorCode string
+
+// Error codes
+const (
+	CodeNotFound        ErrorCode = "not-found"        // the record or reference doesn't exist
+	CodeValidation      ErrorCode = "validation"       // the input was rejected
+	CodeConflict        ErrorCode = "conflict"         // the input clashes with existing data, e.g. an ambiguous name
+	CodeSyncUnavailable ErrorCode = "sync-unavailable" // the Charm server couldn't be reached or refused the device
+	CodeError           ErrorCode = "error"            // anything else
+)
+
+// Sentinel errors for each code, for errors.Is.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrValidation      = errors.New("validation failed")
+	ErrConflict        = errors.New("conflict")
+	ErrSyncUnavailable = errors.New("sync unavailable")
+)
+
+var codeSentinels = map[ErrorCode]error{
+	CodeNotFound:        ErrNotFound,
+	CodeValidation:      ErrValidation,
+	CodeConflict:        ErrConflict,
+	CodeSyncUnavailable: ErrSyncUnavailable,
+}
+
+// CodedError is an error with a code. Its message is the wrapped error's.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string { return e.Err.Error() }
+
+func (e *CodedError) Unwrap() error { return e.Err }
+
+// Is matches the code's sentinel error.
+func (e *CodedError) Is(target error) bool {
+	return target != nil && codeSentinels[e.Code] == target
+}
+
+// WithCode wraps err with code; nil stays nil.
+func WithCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// NotFoundf formats a not-found error.
+func NotFoundf(format string, args ...any) error {
+	return WithCode(CodeNotFound, fmt.Errorf(format, args...))
+}
+
+// Validationf formats a validation error.
+func Validationf(format string, args ...any) error {
+	return WithCode(CodeValidation, fmt.Errorf(format, args...))
+}
+
+// Conflictf formats a conflict error.
+func Conflictf(format string, args ...any) error {
+	return WithCode(CodeConflict, fmt.Errorf(format, args...))
+}
+
+// CodeOf returns err's code: the outermost coded error's in its chain, or one
+// inferred from well-known errors (a missing KV key, a bad query, an ambiguous
+// reference or duplicate email, network and Charm authentication failures).
+// Other errors are CodeError.
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	var ambiguous *AmbiguousMatchError
+	var duplicate *DuplicateEmailError
+	var netErr net.Error
+	var authErr charmproto.ErrAuthFailed
+	switch {
+	case errors.Is(err, badger.ErrKeyNotFound), strings.Contains(err.Error(), "Key not found"):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidQuery):
+		return CodeValidation
+	case errors.As(err, &ambiguous), errors.As(err, &duplicate):
+		return CodeConflict
+	case errors.As(err, &netErr), errors.As(err, &authErr), errors.Is(err, charmproto.ErrMissingSSHAuth):
+		return CodeSyncUnavailable
+	}
+	return CodeError
+}
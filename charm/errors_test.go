@@ -0,0 +1,63 @@
+// ABOUTME: Tests for stable error codes
+// ABOUTME: Verifies coded errors keep their messages, match sentinels, and well-known errors are classified
+
+package charm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	charmproto "github.com/charmbracelet/charm/proto"
+	"github.com/google/uuid"
+)
+
+func TestCodedError(t *testing.T) {
+	err := fmt.Errorf("update failed: %w", NotFoundf("contact not found: %s", "abc"))
+	if err.Error() != "update failed: contact not found: abc" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrValidation) {
+		t.Error("expected the error to match ErrNotFound only")
+	}
+	if CodeOf(err) != CodeNotFound {
+		t.Errorf("expected %s, got %s", CodeNotFound, CodeOf(err))
+	}
+
+	// Wrapping keeps the cause reachable
+	cause := errors.New("connection refused")
+	if wrapped := WithCode(CodeSyncUnavailable, cause); !errors.Is(wrapped, cause) || !errors.Is(wrapped, ErrSyncUnavailable) {
+		t.Error("expected the wrapped error to match its cause and code")
+	}
+	if WithCode(CodeConflict, nil) != nil {
+		t.Error("expected WithCode(nil) to be nil")
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	client := NewTestClient(t)
+	_, missing := client.GetContact(uuid.New())
+	deal := &Deal{Title: "Bad", CompanyID: uuid.New(), Source: "cold-call"}
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), CodeError},
+		{"missing contact", missing, CodeNotFound},
+		{"invalid deal", client.CreateDeal(deal), CodeValidation},
+		{"bad query", fmt.Errorf("%w: unknown field", ErrInvalidQuery), CodeValidation},
+		{"ambiguous", &AmbiguousMatchError{EntityType: EntityContact, Ref: "Al"}, CodeConflict},
+		{"duplicate email", &DuplicateEmailError{Email: "a@example.com", Existing: &Contact{}}, CodeConflict},
+		{"network", fmt.Errorf("sync: %w", &net.OpError{Op: "dial", Err: errors.New("no such host")}), CodeSyncUnavailable},
+		{"auth", charmproto.ErrAuthFailed{Err: errors.New("denied")}, CodeSyncUnavailable},
+	}
+	for _, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("%s: CodeOf(%v) = %q, want %q", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
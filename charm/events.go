@@ -0,0 +1,350 @@
+// ABOUTME: Event operations for conferences, dinners, and meetups
+// ABOUTME: Records attendance and reports the contacts and deals sourced per event
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// EventSourcing summarizes the networking return of one event.
+// Contacts count towards the first event they were met at, and deals count
+// when a sourced contact is the deal's contact or holds a role on it.
+type EventSourcing struct {
+	Event           *Event   `json:"event"`
+	Attendees       int      `json:"attendees"`
+	ContactsMet     int      `json:"contacts_met"`
+	Deals           int      `json:"deals"`
+	OpenPipeline    int64    `json:"open_pipeline"` // in cents
+	WonDeals        int      `json:"won_deals"`
+	WonValue        int64    `json:"won_value"` // in cents
+	SourcedContacts []string `json:"sourced_contacts,omitempty"`
+}
+
+// IsValidEventType reports whether t is a known event type.
+func IsValidEventType(t string) bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateEvent creates a new event.
+func (c *Client) CreateEvent(event *Event) error {
+	if !IsValidEventType(event.EventType) {
+		return Validationf("invalid event type: %s (valid: %s)", event.EventType, strings.Join(EventTypes, ", "))
+	}
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	now := time.Now()
+	if event.Date.IsZero() {
+		event.Date = now
+	}
+	event.CreatedAt = now
+	event.UpdatedAt = now
+
+	return c.saveEvent(event)
+}
+
+// GetEvent retrieves an event by ID.
+func (c *Client) GetEvent(id uuid.UUID) (*Event, error) {
+	data, err := c.Get(EventKey(id.String()))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, NotFoundf("event not found: %s", id)
+	}
+
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	return &event, nil
+}
+
+// UpdateEvent updates an event and its denormalized name and date on attendance records.
+func (c *Client) UpdateEvent(event *Event) error {
+	if !IsValidEventType(event.EventType) {
+		return Validationf("invalid event type: %s (valid: %s)", event.EventType, strings.Join(EventTypes, ", "))
+	}
+	event.UpdatedAt = time.Now()
+	if err := c.saveEvent(event); err != nil {
+		return err
+	}
+
+	attendees, err := c.ListEventAttendees(event.ID)
+	if err != nil {
+		return err
+	}
+	for _, attendee := range attendees {
+		attendee.EventName = event.Name
+		attendee.EventDate = event.Date
+		if err := c.saveEventAttendee(attendee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteEvent deletes an event and its attendance records.
+func (c *Client) DeleteEvent(id uuid.UUID) error {
+	attendees, err := c.ListEventAttendees(id)
+	if err != nil {
+		return err
+	}
+	for _, attendee := range attendees {
+		if err := c.Delete(EventAttendeeKey(id.String(), attendee.ContactID.String())); err != nil {
+			return err
+		}
+	}
+	return c.Delete(EventKey(id.String()))
+}
+
+// ListEvents returns all events, most recent first.
+func (c *Client) ListEvents() ([]*Event, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixEvent))
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+		events = append(events, &event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Date.After(events[j].Date)
+	})
+	return events, nil
+}
+
+// RecordAttendance records a contact at an event, or updates an existing record.
+// New attendance is also logged as an event interaction on the event date.
+func (c *Client) RecordAttendance(attendee *EventAttendee) error {
+	existing, err := c.GetEventAttendee(attendee.EventID, attendee.ContactID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		attendee.CreatedAt = existing.CreatedAt
+		return c.saveEventAttendee(attendee)
+	}
+
+	attendee.CreatedAt = time.Now()
+	if err := c.saveEventAttendee(attendee); err != nil {
+		return err
+	}
+
+	interaction := &InteractionLog{
+		ContactID:       attendee.ContactID,
+		ContactName:     attendee.ContactName,
+		InteractionType: InteractionEvent,
+		Timestamp:       attendee.EventDate,
+		Notes:           "Attended " + attendee.EventName,
+	}
+	if event, err := c.GetEvent(attendee.EventID); err == nil {
+		interaction.Location = event.Location
+	}
+	return c.CreateInteractionLog(interaction)
+}
+
+// GetEventAttendee retrieves a contact's attendance at an event.
+// Returns (nil, nil) if the contact did not attend.
+func (c *Client) GetEventAttendee(eventID, contactID uuid.UUID) (*EventAttendee, error) {
+	data, err := c.Get(EventAttendeeKey(eventID.String(), contactID.String()))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var attendee EventAttendee
+	if err := json.Unmarshal(data, &attendee); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event attendee: %w", err)
+	}
+	return &attendee, nil
+}
+
+// RemoveAttendance removes a contact from an event.
+func (c *Client) RemoveAttendance(eventID, contactID uuid.UUID) error {
+	return c.Delete(EventAttendeeKey(eventID.String(), contactID.String()))
+}
+
+// ListEventAttendees returns the contacts who attended an event, ordered by name.
+func (c *Client) ListEventAttendees(eventID uuid.UUID) ([]*EventAttendee, error) {
+	return c.listEventAttendees(PrefixEventAttendee+eventID.String()+":", nil)
+}
+
+// ListContactEvents returns the events a contact attended, most recent first.
+func (c *Client) ListContactEvents(contactID uuid.UUID) ([]*EventAttendee, error) {
+	attendees, err := c.listEventAttendees(PrefixEventAttendee, func(a *EventAttendee) bool {
+		return a.ContactID == contactID
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(attendees, func(i, j int) bool {
+		return attendees[i].EventDate.After(attendees[j].EventDate)
+	})
+	return attendees, nil
+}
+
+func (c *Client) listEventAttendees(prefix string, match func(*EventAttendee) bool) ([]*EventAttendee, error) {
+	keys, err := c.KeysWithPrefix([]byte(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	var attendees []*EventAttendee
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var attendee EventAttendee
+		if err := json.Unmarshal(data, &attendee); err != nil {
+			continue
+		}
+		if match == nil || match(&attendee) {
+			attendees = append(attendees, &attendee)
+		}
+	}
+
+	sort.Slice(attendees, func(i, j int) bool {
+		return attendees[i].ContactName < attendees[j].ContactName
+	})
+	return attendees, nil
+}
+
+// EventSourcingReport returns networking stats for every event, most recent first.
+func (c *Client) EventSourcingReport() ([]*EventSourcing, error) {
+	events, err := c.ListEvents()
+	if err != nil {
+		return nil, err
+	}
+	attendees, err := c.listEventAttendees(PrefixEventAttendee, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make(map[uuid.UUID]*EventSourcing, len(events))
+	for _, event := range events {
+		report[event.ID] = &EventSourcing{Event: event}
+	}
+
+	// Credit each contact to the earliest event they were met at
+	sourcedAt := make(map[uuid.UUID]*EventAttendee)
+	for _, a := range attendees {
+		stats, ok := report[a.EventID]
+		if !ok {
+			continue
+		}
+		stats.Attendees++
+		if !a.MetHere {
+			continue
+		}
+		if first, ok := sourcedAt[a.ContactID]; !ok || a.EventDate.Before(first.EventDate) {
+			sourcedAt[a.ContactID] = a
+		}
+	}
+	for _, a := range sourcedAt {
+		stats := report[a.EventID]
+		stats.ContactsMet++
+		stats.SourcedContacts = append(stats.SourcedContacts, a.ContactName)
+	}
+
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := c.listDealContacts(PrefixDealContact, nil)
+	if err != nil {
+		return nil, err
+	}
+	dealContacts := make(map[uuid.UUID][]uuid.UUID)
+	for _, role := range roles {
+		dealContacts[role.DealID] = append(dealContacts[role.DealID], role.ContactID)
+	}
+
+	for _, deal := range deals {
+		contactIDs := dealContacts[deal.ID]
+		if deal.ContactID != nil {
+			contactIDs = append(contactIDs, *deal.ContactID)
+		}
+		source := earliestSource(contactIDs, sourcedAt)
+		if source == nil {
+			continue
+		}
+		stats := report[source.EventID]
+		stats.Deals++
+		switch deal.Stage {
+		case StageClosedWon:
+			stats.WonDeals++
+			stats.WonValue += deal.Amount
+		case StageClosedLost:
+		default:
+			stats.OpenPipeline += deal.Amount
+		}
+	}
+
+	result := make([]*EventSourcing, 0, len(events))
+	for _, event := range events {
+		stats := report[event.ID]
+		sort.Strings(stats.SourcedContacts)
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+// earliestSource returns the earliest sourcing event among contacts, if any.
+func earliestSource(contactIDs []uuid.UUID, sourcedAt map[uuid.UUID]*EventAttendee) *EventAttendee {
+	var source *EventAttendee
+	for _, id := range contactIDs {
+		if a, ok := sourcedAt[id]; ok && (source == nil || a.EventDate.Before(source.EventDate)) {
+			source = a
+		}
+	}
+	return source
+}
+
+func (c *Client) saveEvent(event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return c.Set(EventKey(event.ID.String()), data)
+}
+
+func (c *Client) saveEventAttendee(attendee *EventAttendee) error {
+	data, err := json.Marshal(attendee)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event attendee: %w", err)
+	}
+	return c.Set(EventAttendeeKey(attendee.EventID.String(), attendee.ContactID.String()), data)
+}
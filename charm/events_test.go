@@ -0,0 +1,133 @@
+// ABOUTME: Tests for events and attendance
+// ABOUTME: Verifies attendance logging, the per-event sourcing report, and cascades
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventAttendance(t *testing.T) {
+	client := NewTestClient(t)
+
+	if err := client.CreateEvent(&Event{Name: "Bad", EventType: "party"}); err == nil {
+		t.Error("expected error for invalid event type")
+	}
+
+	event := &Event{Name: "GopherCon", EventType: EventTypeConference, Date: time.Now().AddDate(0, 0, -10)}
+	if err := client.CreateEvent(event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	attendee := &EventAttendee{EventID: event.ID, EventName: event.Name, EventDate: event.Date, ContactID: contact.ID, ContactName: contact.Name}
+	if err := client.RecordAttendance(attendee); err != nil {
+		t.Fatalf("RecordAttendance failed: %v", err)
+	}
+	// Recording again updates the record without logging a second interaction
+	attendee.MetHere = true
+	if err := client.RecordAttendance(attendee); err != nil {
+		t.Fatalf("RecordAttendance failed: %v", err)
+	}
+
+	logs, err := client.ListInteractionLogs(&InteractionFilter{ContactID: &contact.ID})
+	if err != nil {
+		t.Fatalf("ListInteractionLogs failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].InteractionType != InteractionEvent {
+		t.Errorf("expected one event interaction, got %+v", logs)
+	}
+
+	event.Name = "GopherCon 2026"
+	if err := client.UpdateEvent(event); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	attended, err := client.ListContactEvents(contact.ID)
+	if err != nil {
+		t.Fatalf("ListContactEvents failed: %v", err)
+	}
+	if len(attended) != 1 || !attended[0].MetHere || attended[0].EventName != "GopherCon 2026" {
+		t.Errorf("unexpected attendance: %+v", attended)
+	}
+
+	if err := client.DeleteContactWithCascade(contact.ID); err != nil {
+		t.Fatalf("DeleteContactWithCascade failed: %v", err)
+	}
+	attendees, err := client.ListEventAttendees(event.ID)
+	if err != nil {
+		t.Fatalf("ListEventAttendees failed: %v", err)
+	}
+	if len(attendees) != 0 {
+		t.Errorf("expected attendance removed with contact, got %d", len(attendees))
+	}
+}
+
+func TestEventSourcingReport(t *testing.T) {
+	client := NewTestClient(t)
+
+	early := &Event{Name: "Meetup", EventType: EventTypeMeetup, Date: time.Now().AddDate(0, -2, 0)}
+	later := &Event{Name: "Conference", EventType: EventTypeConference, Date: time.Now().AddDate(0, -1, 0)}
+	for _, event := range []*Event{early, later} {
+		if err := client.CreateEvent(event); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	alice := &Contact{Name: "Alice"}
+	bob := &Contact{Name: "Bob"}
+	for _, contact := range []*Contact{alice, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	record := func(event *Event, contact *Contact, met bool) {
+		t.Helper()
+		err := client.RecordAttendance(&EventAttendee{
+			EventID: event.ID, EventName: event.Name, EventDate: event.Date,
+			ContactID: contact.ID, ContactName: contact.Name, MetHere: met,
+		})
+		if err != nil {
+			t.Fatalf("RecordAttendance failed: %v", err)
+		}
+	}
+	// Alice was met at both; the earlier event gets the credit
+	record(early, alice, true)
+	record(later, alice, true)
+	record(later, bob, true)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	won := &Deal{Title: "Won", Amount: 100000, Stage: StageClosedWon, CompanyID: company.ID, ContactID: &alice.ID}
+	open := &Deal{Title: "Open", Amount: 50000, Stage: StageProposal, CompanyID: company.ID}
+	for _, deal := range []*Deal{won, open} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("failed to create deal: %v", err)
+		}
+	}
+	if err := client.SaveDealContact(&DealContact{DealID: open.ID, ContactID: bob.ID, Role: DealRoleChampion}); err != nil {
+		t.Fatalf("SaveDealContact failed: %v", err)
+	}
+
+	report, err := client.EventSourcingReport()
+	if err != nil {
+		t.Fatalf("EventSourcingReport failed: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 events in report, got %d", len(report))
+	}
+
+	conf, meetup := report[0], report[1]
+	if meetup.ContactsMet != 1 || meetup.WonDeals != 1 || meetup.WonValue != 100000 {
+		t.Errorf("unexpected meetup stats: %+v", meetup)
+	}
+	if conf.Attendees != 2 || conf.ContactsMet != 1 || conf.Deals != 1 || conf.OpenPipeline != 50000 {
+		t.Errorf("unexpected conference stats: %+v", conf)
+	}
+}
@@ -0,0 +1,328 @@
+// ABOUTME: Exports contacts, companies, deals, and interactions as CSV, JSON, or NDJSON
+// ABOUTME: Streams objects one at a time with related names filled in, for spreadsheets and backups
+
+package charm
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Export formats.
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+)
+
+// ExportFormats lists the valid export formats.
+var ExportFormats = []string{ExportCSV, ExportJSON, ExportNDJSON}
+
+// ExportTypes lists the object types that can be exported, named as in
+// EntityPrefixes.
+var ExportTypes = []string{"contacts", "companies", "deals", "interactions"}
+
+// exportColumns are the CSV columns per type. JSON formats write the full
+// stored object instead, with the same names filled in.
+var exportColumns = map[string][]string{
+	"contacts": {"id", "name", "email", "phone", "title", "company_id", "company_name", "tags", "pinned", "notes",
+		"birthday", "work_start_date", "country", "met_via", "met_at", "met_date", "seniority", "function",
+		"last_contacted_at", "archived_at", "created_at", "updated_at"},
+	"companies": {"id", "name", "domain", "industry", "employees", "funding_stage", "hq_location", "tags", "notes",
+		"created_at", "updated_at"},
+	"deals": {"id", "title", "stage", "amount_cents", "currency", "probability", "expected_value_cents",
+		"company_id", "company_name", "contact_id", "contact_name", "expected_close_date", "close_reason",
+		"source", "referrer_id", "referrer_name", "tags", "created_at", "updated_at", "last_activity_at"},
+	"interactions": {"id", "contact_id", "contact_name", "company_name", "interaction_type", "timestamp",
+		"sentiment", "location", "outcome", "next_step", "template", "notes"},
+}
+
+// Export writes every object of exportType to w in format and returns how many
+// it wrote. Objects are read and written one at a time in ID order, so large
+// stores aren't held in memory. Company and contact names come from the
+// current records rather than the copies stored on each object, so renames
+// show up. Archived contacts are included.
+func (c *Client) Export(w io.Writer, exportType, format string) (int, error) {
+	columns, ok := exportColumns[exportType]
+	if !ok {
+		return 0, Validationf("invalid export type: %s (valid: %s)", exportType, strings.Join(ExportTypes, ", "))
+	}
+
+	buf := bufio.NewWriter(w)
+	var out exportWriter
+	switch format {
+	case ExportCSV:
+		out = &csvExportWriter{w: csv.NewWriter(buf)}
+	case ExportJSON:
+		out = &jsonExportWriter{w: buf}
+	case ExportNDJSON:
+		out = &jsonExportWriter{w: buf, lines: true}
+	default:
+		return 0, Validationf("invalid export format: %s (valid: %s)", format, strings.Join(ExportFormats, ", "))
+	}
+
+	names, err := c.exportNames(exportType)
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := c.KeysWithPrefix([]byte(EntityPrefixes[exportType]))
+	if err != nil {
+		return 0, err
+	}
+	if err := out.begin(columns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		obj, row, err := names.decode(exportType, data)
+		if err != nil {
+			continue
+		}
+		if err := out.write(obj, row); err != nil {
+			return count, fmt.Errorf("failed to write export: %w", err)
+		}
+		count++
+	}
+
+	if err := out.end(); err != nil {
+		return count, fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		return count, fmt.Errorf("failed to write export: %w", err)
+	}
+	return count, nil
+}
+
+// exportNames holds the current company and contact names used to fill in
+// related names.
+type exportNames struct {
+	companies      map[uuid.UUID]string
+	contacts       map[uuid.UUID]string
+	contactCompany map[uuid.UUID]string // each contact's company name, for interactions
+}
+
+// exportNames loads the names exportType needs. Companies are few, so they are
+// always loaded; contacts only for deals and interactions.
+func (c *Client) exportNames(exportType string) (*exportNames, error) {
+	names := &exportNames{
+		companies:      make(map[uuid.UUID]string),
+		contacts:       make(map[uuid.UUID]string),
+		contactCompany: make(map[uuid.UUID]string),
+	}
+	companies, err := c.ListCompanies(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+	for _, company := range companies {
+		names.companies[company.ID] = company.Name
+	}
+
+	if exportType == "deals" || exportType == "interactions" {
+		contacts, err := c.ListContacts(&ContactFilter{IncludeArchived: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		for _, contact := range contacts {
+			names.contacts[contact.ID] = contact.Name
+			if contact.CompanyID != nil {
+				names.contactCompany[contact.ID] = names.company(*contact.CompanyID, contact.CompanyName)
+			}
+		}
+	}
+	return names, nil
+}
+
+// company returns the current name of company id, or stored when it's gone.
+func (n *exportNames) company(id uuid.UUID, stored string) string {
+	if name, ok := n.companies[id]; ok {
+		return name
+	}
+	return stored
+}
+
+// contact returns the current name of contact id, or stored when it's gone.
+func (n *exportNames) contact(id uuid.UUID, stored string) string {
+	if name, ok := n.contacts[id]; ok {
+		return name
+	}
+	return stored
+}
+
+// decode unmarshals a stored object, fills in related names, and returns it
+// with its CSV row.
+func (n *exportNames) decode(exportType string, data []byte) (interface{}, []string, error) {
+	switch exportType {
+	case "contacts":
+		var contact Contact
+		if err := json.Unmarshal(data, &contact); err != nil {
+			return nil, nil, err
+		}
+		if contact.CompanyID != nil {
+			contact.CompanyName = n.company(*contact.CompanyID, contact.CompanyName)
+		}
+		return &contact, []string{
+			contact.ID.String(), contact.Name, contact.Email, contact.Phone, contact.Title,
+			exportID(contact.CompanyID), contact.CompanyName, strings.Join(contact.Tags, ";"), strings.Join(contact.Pinned, ";"), contact.Notes,
+			contact.Birthday, exportDate(contact.WorkStartDate), contact.Country, contact.MetVia, contact.MetAt, exportDate(contact.MetDate),
+			contact.Seniority, contact.Function,
+			exportTime(contact.LastContactedAt), exportTime(contact.ArchivedAt), exportTime(&contact.CreatedAt), exportTime(&contact.UpdatedAt),
+		}, nil
+
+	case "companies":
+		var company Company
+		if err := json.Unmarshal(data, &company); err != nil {
+			return nil, nil, err
+		}
+		return &company, []string{
+			company.ID.String(), company.Name, company.Domain, company.Industry, company.Employees(),
+			company.FundingStage, company.HQLocation, strings.Join(company.Tags, ";"), company.Notes,
+			exportTime(&company.CreatedAt), exportTime(&company.UpdatedAt),
+		}, nil
+
+	case "deals":
+		var deal Deal
+		if err := json.Unmarshal(data, &deal); err != nil {
+			return nil, nil, err
+		}
+		deal.CompanyName = n.company(deal.CompanyID, deal.CompanyName)
+		if deal.ContactID != nil {
+			deal.ContactName = n.contact(*deal.ContactID, deal.ContactName)
+		}
+		if deal.ReferrerID != nil {
+			deal.ReferrerName = n.contact(*deal.ReferrerID, deal.ReferrerName)
+		}
+		return &deal, []string{
+			deal.ID.String(), deal.Title, deal.Stage, strconv.FormatInt(deal.Amount, 10), deal.Currency,
+			strconv.Itoa(deal.WinProbability()), strconv.FormatInt(deal.ExpectedValue(), 10),
+			deal.CompanyID.String(), deal.CompanyName, exportID(deal.ContactID), deal.ContactName,
+			exportDate(deal.ExpectedCloseDate), deal.CloseReason,
+			deal.Source, exportID(deal.ReferrerID), deal.ReferrerName, strings.Join(deal.Tags, ";"),
+			exportTime(&deal.CreatedAt), exportTime(&deal.UpdatedAt), exportTime(&deal.LastActivityAt),
+		}, nil
+
+	case "interactions":
+		var log InteractionLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil, nil, err
+		}
+		log.ContactName = n.contact(log.ContactID, log.ContactName)
+		sentiment := ""
+		if log.Sentiment != nil {
+			sentiment = *log.Sentiment
+		}
+		// The company name isn't stored on interactions, so JSON formats get
+		// it alongside the interaction's own fields.
+		obj := struct {
+			*InteractionLog
+			CompanyName string `json:"company_name,omitempty"`
+		}{&log, n.contactCompany[log.ContactID]}
+		return obj, []string{
+			log.ID.String(), log.ContactID.String(), log.ContactName, obj.CompanyName, log.InteractionType, exportTime(&log.Timestamp),
+			sentiment, log.Location, log.Outcome, log.NextStep, log.Template, log.Notes,
+		}, nil
+	}
+	return nil, nil, Validationf("invalid export type: %s", exportType)
+}
+
+func exportID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func exportDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// exportWriter writes exported objects in one format.
+type exportWriter interface {
+	begin(columns []string) error
+	write(obj interface{}, row []string) error
+	end() error
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) begin(columns []string) error {
+	return e.w.Write(columns)
+}
+
+func (e *csvExportWriter) write(_ interface{}, row []string) error {
+	return e.w.Write(row)
+}
+
+func (e *csvExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExportWriter writes a JSON array, or with lines one object per line.
+type jsonExportWriter struct {
+	w     io.Writer
+	lines bool
+	count int
+}
+
+func (e *jsonExportWriter) begin(_ []string) error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(obj interface{}, _ []string) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if e.lines {
+		data = append(data, '\n')
+	} else {
+		sep := "\n"
+		if e.count > 0 {
+			sep = ",\n"
+		}
+		if _, err := io.WriteString(e.w, sep); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportWriter) end() error {
+	if e.lines {
+		return nil
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
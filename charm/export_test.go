@@ -0,0 +1,119 @@
+// ABOUTME: Tests for exporting objects as CSV, JSON, and NDJSON
+// ABOUTME: Verifies columns, current related names, and that each format parses back
+
+package charm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	client := NewTestClient(t)
+
+	acme := &Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	ada := &Contact{Name: "Ada", Email: "ada@acme.com", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"ai", "investor"}}
+	if err := client.CreateContact(ada); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	deal := &Deal{Title: "Pilot, phase 1", CompanyID: acme.ID, CompanyName: "Acme", ContactID: &ada.ID, ContactName: "Ada", Stage: StageProspecting, Amount: 500000, Currency: "USD"}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: ada.ID, ContactName: "Ada", InteractionType: InteractionMeeting, Notes: "Coffee\nand plans"}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+
+	// Renames after the fact show up in the export
+	acme.Name = "Acme Corp"
+	if err := client.UpdateCompany(acme); err != nil {
+		t.Fatalf("UpdateCompany failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	count, err := client.Export(&buf, "contacts", ExportCSV)
+	if err != nil {
+		t.Fatalf("Export contacts failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("contacts CSV doesn't parse: %v", err)
+	}
+	if count != 1 || len(rows) != 2 {
+		t.Fatalf("expected a header and one contact, got %d rows (count %d)", len(rows), count)
+	}
+	record := make(map[string]string)
+	for i, column := range rows[0] {
+		record[column] = rows[1][i]
+	}
+	if record["company_name"] != "Acme Corp" || record["tags"] != "ai;investor" || record["email"] != "ada@acme.com" {
+		t.Errorf("unexpected contact row: %v", record)
+	}
+
+	buf.Reset()
+	if _, err := client.Export(&buf, "deals", ExportJSON); err != nil {
+		t.Fatalf("Export deals failed: %v", err)
+	}
+	var deals []Deal
+	if err := json.Unmarshal(buf.Bytes(), &deals); err != nil {
+		t.Fatalf("deals JSON doesn't parse: %v\n%s", err, buf.String())
+	}
+	if len(deals) != 1 || deals[0].CompanyName != "Acme Corp" || deals[0].Amount != 500000 {
+		t.Errorf("unexpected deals: %+v", deals)
+	}
+
+	buf.Reset()
+	if _, err := client.Export(&buf, "interactions", ExportNDJSON); err != nil {
+		t.Fatalf("Export interactions failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one NDJSON line, got %d", len(lines))
+	}
+	var interaction struct {
+		ContactName string `json:"contact_name"`
+		CompanyName string `json:"company_name"`
+		Notes       string `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &interaction); err != nil {
+		t.Fatalf("interaction line doesn't parse: %v", err)
+	}
+	if interaction.CompanyName != "Acme Corp" || interaction.Notes != "Coffee\nand plans" {
+		t.Errorf("unexpected interaction: %+v", interaction)
+	}
+
+	buf.Reset()
+	if _, err := client.Export(&buf, "companies", ExportJSON); err != nil {
+		t.Fatalf("Export companies failed: %v", err)
+	}
+	var companies []Company
+	if err := json.Unmarshal(buf.Bytes(), &companies); err != nil || len(companies) != 1 {
+		t.Errorf("expected one company, got %d (%v)", len(companies), err)
+	}
+
+	if _, err := client.Export(&buf, "tasks", ExportCSV); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+	if _, err := client.Export(&buf, "contacts", "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestExportEmptyJSON(t *testing.T) {
+	client := NewTestClient(t)
+
+	var buf bytes.Buffer
+	if _, err := client.Export(&buf, "deals", ExportJSON); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	var deals []Deal
+	if err := json.Unmarshal(buf.Bytes(), &deals); err != nil || len(deals) != 0 {
+		t.Errorf("expected an empty array, got %q (%v)", buf.String(), err)
+	}
+}
@@ -15,12 +15,50 @@ type ContactFilter struct {
 	Query     string     // Full-text search in name, email, notes
 	CompanyID *uuid.UUID // Filter by company
 	Limit     int        // Max results (0 = unlimited)
+
+	IncludeArchived bool // Also match archived contacts (hidden by default)
+
+	MetFrom *time.Time // Met on or after this time
+	MetTo   *time.Time // Met before this time
+
+	City string // Met in person in this city; resolved from meeting locations by ListContacts
+
+	Tag string // Has this tag
+
+	CompanyName string // Substring of the company name
+	Email       string // Substring of the email address
+
+	LastContactedFrom *time.Time // Last contacted on or after this time
+	LastContactedTo   *time.Time // Last contacted before this time, or never
+
+	// Query may also hold field terms such as tag:vip last_contacted:<90d;
+	// ListContacts moves them into the fields above (see ParseSearchQuery).
 }
 
 // Matches returns true if the contact matches the filter.
 func (f *ContactFilter) Matches(c *Contact) bool {
 	if f == nil {
-		return true
+		return c.ArchivedAt == nil
+	}
+
+	if c.ArchivedAt != nil && !f.IncludeArchived {
+		return false
+	}
+
+	// Filter by met date
+	if f.MetFrom != nil && (c.MetDate == nil || c.MetDate.Before(*f.MetFrom)) {
+		return false
+	}
+	if f.MetTo != nil && (c.MetDate == nil || !c.MetDate.Before(*f.MetTo)) {
+		return false
+	}
+
+	// Filter by last contact
+	if f.LastContactedFrom != nil && (c.LastContactedAt == nil || c.LastContactedAt.Before(*f.LastContactedFrom)) {
+		return false
+	}
+	if f.LastContactedTo != nil && c.LastContactedAt != nil && !c.LastContactedAt.Before(*f.LastContactedTo) {
+		return false
 	}
 
 	// Filter by company
@@ -29,6 +67,17 @@ func (f *ContactFilter) Matches(c *Contact) bool {
 			return false
 		}
 	}
+	if f.CompanyName != "" && !containsFold(c.CompanyName, f.CompanyName) {
+		return false
+	}
+	if f.Email != "" && !containsFold(c.Email, f.Email) {
+		return false
+	}
+
+	// Filter by tag
+	if f.Tag != "" && !c.HasTag(f.Tag) {
+		return false
+	}
 
 	// Filter by query string
 	if f.Query != "" {
@@ -36,7 +85,8 @@ func (f *ContactFilter) Matches(c *Contact) bool {
 		if !strings.Contains(strings.ToLower(c.Name), q) &&
 			!strings.Contains(strings.ToLower(c.Email), q) &&
 			!strings.Contains(strings.ToLower(c.Notes), q) &&
-			!strings.Contains(strings.ToLower(c.CompanyName), q) {
+			!strings.Contains(strings.ToLower(c.CompanyName), q) &&
+			!c.HasTag(f.Query) {
 			return false
 		}
 	}
@@ -46,9 +96,17 @@ func (f *ContactFilter) Matches(c *Contact) bool {
 
 // CompanyFilter defines criteria for filtering companies.
 type CompanyFilter struct {
-	Query    string // Full-text search in name, domain, industry, notes
-	Industry string // Filter by industry
-	Limit    int    // Max results (0 = unlimited)
+	Query        string // Full-text search in name, domain, industry, notes
+	Industry     string // Filter by industry
+	FundingStage string // Filter by funding stage, in any spelling NormalizeFundingStage accepts
+	MinEmployees int    // Companies that may have at least this many employees
+	MaxEmployees int    // Companies that may have at most this many employees (0 = unlimited)
+	HQ           string // Substring of the headquarters location
+	Tag          string // Has this tag
+	Limit        int    // Max results (0 = unlimited)
+
+	// Query may also hold field terms such as industry:saas employees:>50;
+	// ListCompanies moves them into the fields above (see ParseSearchQuery).
 }
 
 // Matches returns true if the company matches the filter.
@@ -62,13 +120,31 @@ func (f *CompanyFilter) Matches(c *Company) bool {
 		return false
 	}
 
+	// Filter by size and funding
+	if f.FundingStage != "" && c.FundingStage != NormalizeFundingStage(f.FundingStage) {
+		return false
+	}
+	if f.MinEmployees > 0 && !c.employeesAtLeast(f.MinEmployees) {
+		return false
+	}
+	if f.MaxEmployees > 0 && !c.employeesAtMost(f.MaxEmployees) {
+		return false
+	}
+	if f.HQ != "" && !strings.Contains(strings.ToLower(c.HQLocation), strings.ToLower(f.HQ)) {
+		return false
+	}
+	if f.Tag != "" && !c.HasTag(f.Tag) {
+		return false
+	}
+
 	// Filter by query string
 	if f.Query != "" {
 		q := strings.ToLower(f.Query)
 		if !strings.Contains(strings.ToLower(c.Name), q) &&
 			!strings.Contains(strings.ToLower(c.Domain), q) &&
 			!strings.Contains(strings.ToLower(c.Industry), q) &&
-			!strings.Contains(strings.ToLower(c.Notes), q) {
+			!strings.Contains(strings.ToLower(c.Notes), q) &&
+			!c.HasTag(f.Query) {
 			return false
 		}
 	}
@@ -78,13 +154,26 @@ func (f *CompanyFilter) Matches(c *Company) bool {
 
 // DealFilter defines criteria for filtering deals.
 type DealFilter struct {
-	Query     string     // Full-text search in title, company name
-	Stage     string     // Filter by deal stage
-	CompanyID *uuid.UUID // Filter by company
-	ContactID *uuid.UUID // Filter by contact
-	MinAmount int64      // Minimum amount in cents
-	MaxAmount int64      // Maximum amount in cents (0 = unlimited)
-	Limit     int        // Max results (0 = unlimited)
+	Query      string     // Full-text search in title, company name
+	Stage      string     // Filter by deal stage
+	Source     string     // Filter by deal source
+	CompanyID  *uuid.UUID // Filter by company
+	ContactID  *uuid.UUID // Filter by contact
+	ReferrerID *uuid.UUID // Filter by referring contact
+	MinAmount  int64      // Minimum amount in cents
+	MaxAmount  int64      // Maximum amount in cents (0 = unlimited)
+	Tag        string     // Has this tag
+	Limit      int        // Max results (0 = unlimited)
+
+	CompanyName  string     // Substring of the company name
+	ContactName  string     // Substring of the primary contact's name
+	ActivityFrom *time.Time // Last activity on or after this time
+	ActivityTo   *time.Time // Last activity before this time
+	CloseFrom    *time.Time // Expected to close on or after this time
+	CloseTo      *time.Time // Expected to close before this time
+
+	// Query may also hold field terms such as stage:negotiation amount:>10k;
+	// ListDeals moves them into the fields above (see ParseSearchQuery).
 }
 
 // Matches returns true if the deal matches the filter.
@@ -97,7 +186,8 @@ func (f *DealFilter) Matches(d *Deal) bool {
 	if f.Query != "" {
 		q := strings.ToLower(f.Query)
 		if !strings.Contains(strings.ToLower(d.Title), q) &&
-			!strings.Contains(strings.ToLower(d.CompanyName), q) {
+			!strings.Contains(strings.ToLower(d.CompanyName), q) &&
+			!d.HasTag(f.Query) {
 			return false
 		}
 	}
@@ -107,6 +197,15 @@ func (f *DealFilter) Matches(d *Deal) bool {
 		return false
 	}
 
+	// Filter by source
+	if f.Source != "" && d.Source != f.Source {
+		return false
+	}
+
+	if f.Tag != "" && !d.HasTag(f.Tag) {
+		return false
+	}
+
 	// Filter by company
 	if f.CompanyID != nil && d.CompanyID != *f.CompanyID {
 		return false
@@ -119,6 +218,20 @@ func (f *DealFilter) Matches(d *Deal) bool {
 		}
 	}
 
+	if f.CompanyName != "" && !containsFold(d.CompanyName, f.CompanyName) {
+		return false
+	}
+	if f.ContactName != "" && !containsFold(d.ContactName, f.ContactName) {
+		return false
+	}
+
+	// Filter by referrer
+	if f.ReferrerID != nil {
+		if d.ReferrerID == nil || *d.ReferrerID != *f.ReferrerID {
+			return false
+		}
+	}
+
 	// Filter by amount range
 	if f.MinAmount > 0 && d.Amount < f.MinAmount {
 		return false
@@ -127,9 +240,28 @@ func (f *DealFilter) Matches(d *Deal) bool {
 		return false
 	}
 
+	// Filter by activity and expected close
+	if f.ActivityFrom != nil && d.LastActivityAt.Before(*f.ActivityFrom) {
+		return false
+	}
+	if f.ActivityTo != nil && !d.LastActivityAt.Before(*f.ActivityTo) {
+		return false
+	}
+	if f.CloseFrom != nil && (d.ExpectedCloseDate == nil || d.ExpectedCloseDate.Before(*f.CloseFrom)) {
+		return false
+	}
+	if f.CloseTo != nil && (d.ExpectedCloseDate == nil || !d.ExpectedCloseDate.Before(*f.CloseTo)) {
+		return false
+	}
+
 	return true
 }
 
+// containsFold reports whether substr is in s, ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
 // InteractionFilter defines criteria for filtering interaction logs.
 type InteractionFilter struct {
 	ContactID       *uuid.UUID // Filter by contact
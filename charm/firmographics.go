@@ -0,0 +1,152 @@
+// ABOUTME: Company size, funding stage, and headquarters fields
+// ABOUTME: Parses employee counts and ranges, normalizes funding stages, and cleans website domains
+
+package charm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Common funding stages. Others, such as the full range of Crunchbase
+// funding types, are stored normalized the same way.
+const (
+	FundingPreSeed = "pre_seed"
+	FundingAngel   = "angel"
+	FundingSeed    = "seed"
+	FundingSeriesA = "series_a"
+	FundingSeriesB = "series_b"
+	FundingSeriesC = "series_c"
+	FundingVenture = "venture" // a venture round of unknown series
+	FundingPublic  = "public"
+)
+
+// fundingStageAliases maps normalized spellings to a common stage.
+var fundingStageAliases = map[string]string{
+	"preseed":                 FundingPreSeed,
+	"venture_series_unknown":  FundingVenture,
+	"ipo":                     FundingPublic,
+	"post_ipo_equity":         FundingPublic,
+	"post_ipo_debt":           FundingPublic,
+	"post_ipo_secondary":      FundingPublic,
+	"initial_public_offering": FundingPublic,
+}
+
+// NormalizeFundingStage turns a funding stage such as "Series A" or
+// "Pre-Seed" into its stored form ("series_a", "pre_seed"). Stages are
+// lowercased with runs of spaces, dashes, and punctuation joined by "_".
+func NormalizeFundingStage(stage string) string {
+	words := strings.FieldsFunc(strings.ToLower(stage), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '+')
+	})
+	normalized := strings.Join(words, "_")
+	if alias, ok := fundingStageAliases[normalized]; ok {
+		return alias
+	}
+	return normalized
+}
+
+// FundingStageLabel returns a stored funding stage for display: "series_a"
+// becomes "Series A".
+func FundingStageLabel(stage string) string {
+	words := strings.Split(stage, "_")
+	for i, word := range words {
+		switch {
+		case word == "ipo":
+			words[i] = "IPO"
+		case len(word) == 1:
+			words[i] = strings.ToUpper(word)
+		case word != "":
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseEmployeeCount parses a headcount: an exact number ("42", "1,200"), a
+// range ("11-50"), or an open range ("10001+"). It returns the low end and,
+// for a closed range, the high end. Empty input returns zeros.
+func ParseEmployeeCount(value string) (count, max int, err error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == "" {
+		return 0, 0, nil
+	}
+	low, high, isRange := strings.Cut(value, "-")
+	if !isRange {
+		low, high, isRange = strings.Cut(value, "–")
+	}
+	low = strings.TrimSuffix(strings.TrimSpace(low), "+")
+
+	count, err = strconv.Atoi(low)
+	if err != nil || count < 0 {
+		return 0, 0, Validationf("invalid employee count %q (use a number like 42, or a range like 11-50)", value)
+	}
+	if isRange {
+		max, err = strconv.Atoi(strings.TrimSpace(high))
+		if err != nil || max < count {
+			return 0, 0, Validationf("invalid employee range %q", value)
+		}
+		if max == count {
+			max = 0
+		}
+	}
+	return count, max, nil
+}
+
+// SetEmployeeCount parses value (see ParseEmployeeCount) into the company.
+func (c *Company) SetEmployeeCount(value string) error {
+	count, max, err := ParseEmployeeCount(value)
+	if err != nil {
+		return err
+	}
+	c.EmployeeCount, c.EmployeeCountMax = count, max
+	return nil
+}
+
+// Employees returns the company's headcount for display: "42", "11-50", or
+// "" when unknown.
+func (c *Company) Employees() string {
+	switch {
+	case c.EmployeeCountMax > 0:
+		return fmt.Sprintf("%d-%d", c.EmployeeCount, c.EmployeeCountMax)
+	case c.EmployeeCount > 0:
+		return strconv.Itoa(c.EmployeeCount)
+	}
+	return ""
+}
+
+// employeesAtLeast reports whether the company may have at least n
+// employees: a range counts when its high end reaches n.
+func (c *Company) employeesAtLeast(n int) bool {
+	high := c.EmployeeCount
+	if c.EmployeeCountMax > high {
+		high = c.EmployeeCountMax
+	}
+	return high >= n
+}
+
+// employeesAtMost reports whether the company may have at most n employees.
+// Companies with no headcount don't count.
+func (c *Company) employeesAtMost(n int) bool {
+	return c.EmployeeCount > 0 && c.EmployeeCount <= n
+}
+
+// DomainFromWebsite returns the bare domain of a website URL:
+// "https://www.acme.com/about" becomes "acme.com".
+func DomainFromWebsite(website string) string {
+	domain := strings.ToLower(strings.TrimSpace(website))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if i := strings.LastIndex(domain, "@"); i >= 0 {
+		domain = domain[i+1:]
+	}
+	if host, _, ok := strings.Cut(domain, ":"); ok {
+		domain = host
+	}
+	return strings.TrimPrefix(domain, "www.")
+}
@@ -0,0 +1,119 @@
+// ABOUTME: Tests for company size, funding stage, and headquarters fields
+// ABOUTME: Covers employee count parsing, funding stage normalization, website domains, and filters
+
+package charm
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseEmployeeCount(t *testing.T) {
+	tests := []struct {
+		value     string
+		count     int
+		max       int
+		wantError bool
+	}{
+		{"", 0, 0, false},
+		{"42", 42, 0, false},
+		{"1,200", 1200, 0, false},
+		{"11-50", 11, 50, false},
+		{"51 – 100", 51, 100, false},
+		{"10001+", 10001, 0, false},
+		{"50-50", 50, 0, false},
+		{"lots", 0, 0, true},
+		{"50-10", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		count, max, err := ParseEmployeeCount(tt.value)
+		if (err != nil) != tt.wantError {
+			t.Errorf("ParseEmployeeCount(%q) error = %v, wantError %v", tt.value, err, tt.wantError)
+			continue
+		}
+		if count != tt.count || max != tt.max {
+			t.Errorf("ParseEmployeeCount(%q) = %d, %d, want %d, %d", tt.value, count, max, tt.count, tt.max)
+		}
+	}
+}
+
+func TestNormalizeFundingStage(t *testing.T) {
+	tests := map[string]string{
+		"Series A":               FundingSeriesA,
+		"series-b":               FundingSeriesB,
+		"Pre-Seed":               FundingPreSeed,
+		"preseed":                FundingPreSeed,
+		"Seed":                   FundingSeed,
+		"venture_series_unknown": FundingVenture,
+		"Post-IPO Equity":        FundingPublic,
+		"Convertible Note":       "convertible_note",
+		"":                       "",
+	}
+	for stage, want := range tests {
+		if got := NormalizeFundingStage(stage); got != want {
+			t.Errorf("NormalizeFundingStage(%q) = %q, want %q", stage, got, want)
+		}
+	}
+
+	if got := FundingStageLabel(FundingSeriesA); got != "Series A" {
+		t.Errorf("FundingStageLabel(series_a) = %q", got)
+	}
+}
+
+func TestDomainFromWebsite(t *testing.T) {
+	tests := map[string]string{
+		"https://www.acme.com/about": "acme.com",
+		"http://Globex.io:8080":      "globex.io",
+		"initech.com":                "initech.com",
+		"www.umbrella.co.uk/?ref=cb": "umbrella.co.uk",
+		"":                           "",
+	}
+	for website, want := range tests {
+		if got := DomainFromWebsite(website); got != want {
+			t.Errorf("DomainFromWebsite(%q) = %q, want %q", website, got, want)
+		}
+	}
+}
+
+func TestCompanyFilterFirmographics(t *testing.T) {
+	client := NewTestClient(t)
+
+	companies := []*Company{
+		{Name: "Tiny", FundingStage: FundingSeed, EmployeeCount: 5, HQLocation: "Chicago, IL"},
+		{Name: "Ranged", FundingStage: FundingSeed, EmployeeCount: 1, EmployeeCountMax: 10},
+		{Name: "Growing", FundingStage: FundingSeriesA, EmployeeCount: 51, EmployeeCountMax: 100},
+		{Name: "Unknown"},
+	}
+	for _, company := range companies {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("CreateCompany failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter CompanyFilter
+		want   []string
+	}{
+		{"stage", CompanyFilter{FundingStage: "Seed"}, []string{"Ranged", "Tiny"}},
+		{"min employees", CompanyFilter{FundingStage: FundingSeed, MinEmployees: 10}, []string{"Ranged"}},
+		{"max employees", CompanyFilter{MaxEmployees: 50}, []string{"Ranged", "Tiny"}},
+		{"hq", CompanyFilter{HQ: "chicago"}, []string{"Tiny"}},
+	}
+	for _, tt := range tests {
+		results, err := client.ListCompanies(&tt.filter)
+		if err != nil {
+			t.Fatalf("%s: ListCompanies failed: %v", tt.name, err)
+		}
+		var names []string
+		for _, company := range results {
+			names = append(names, company.Name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, names, tt.want)
+		}
+	}
+}
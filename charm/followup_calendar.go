@@ -0,0 +1,84 @@
+// ABOUTME: Follow-up due dates computed from contact cadences
+// ABOUTME: Lists overdue and upcoming follow-ups for calendar feeds
+
+package charm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FollowupDue is a contact's next follow-up date from their cadence.
+type FollowupDue struct {
+	ContactID            uuid.UUID `json:"contact_id"`
+	Name                 string    `json:"name"`
+	Email                string    `json:"email,omitempty"`
+	CompanyName          string    `json:"company_name,omitempty"`
+	CadenceDays          int       `json:"cadence_days"`
+	RelationshipStrength string    `json:"relationship_strength"`
+	LastInteraction      time.Time `json:"last_interaction"`
+	Due                  time.Time `json:"due"` // start of the due day
+	DaysOverdue          int       `json:"days_overdue"`
+}
+
+// Overdue reports whether the follow-up's due day is before today.
+func (f *FollowupDue) Overdue() bool {
+	return f.DaysOverdue > 0
+}
+
+// GetFollowupDueDates returns follow-ups due by the end of the next days,
+// overdue ones included, earliest first. Each is due cadence days after the
+// contact's last interaction, in now's time zone. Contacts never interacted
+// with and archived contacts have no due date.
+func (c *Client) GetFollowupDueDates(now time.Time, days int) ([]*FollowupDue, error) {
+	cadences, err := c.ListContactCadences()
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	horizon := today.AddDate(0, 0, days)
+
+	var due []*FollowupDue
+	for _, cadence := range cadences {
+		if cadence.LastInteractionDate == nil || cadence.CadenceDays <= 0 {
+			continue
+		}
+		last := cadence.LastInteractionDate.In(now.Location())
+		day := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, cadence.CadenceDays)
+		if day.After(horizon) {
+			continue
+		}
+
+		contact, err := c.GetContact(cadence.ContactID)
+		if err != nil || contact.ArchivedAt != nil {
+			continue
+		}
+
+		overdue := 0
+		if day.Before(today) {
+			overdue = int(today.Sub(day).Hours()/24 + 0.5)
+		}
+		due = append(due, &FollowupDue{
+			ContactID:            contact.ID,
+			Name:                 contact.Name,
+			Email:                contact.Email,
+			CompanyName:          contact.CompanyName,
+			CadenceDays:          cadence.CadenceDays,
+			RelationshipStrength: cadence.RelationshipStrength,
+			LastInteraction:      *cadence.LastInteractionDate,
+			Due:                  day,
+			DaysOverdue:          overdue,
+		})
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		if !due[i].Due.Equal(due[j].Due) {
+			return due[i].Due.Before(due[j].Due)
+		}
+		return due[i].Name < due[j].Name
+	})
+	return due, nil
+}
@@ -0,0 +1,162 @@
+// ABOUTME: Forecast of contacts likely to go cold before they are overdue
+// ABOUTME: Fits a trend to the gaps between interactions and flags gaps growing past the cadence
+
+package charm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoingColdWindow is how many of a contact's most recent gaps between
+// interactions the forecast fits a trend to.
+const GoingColdWindow = 6
+
+// MinGoingColdGaps is how many gaps a contact needs before a trend counts.
+const MinGoingColdGaps = 3
+
+// ColdForecast is a contact within their cadence whose gaps between
+// interactions are trending longer than it.
+type ColdForecast struct {
+	ContactID        uuid.UUID `json:"contact_id"`
+	Name             string    `json:"name"`
+	CadenceDays      int       `json:"cadence_days"`
+	DaysSinceContact int       `json:"days_since_contact"`
+	RecentGapDays    float64   `json:"recent_gap_days"`    // mean of the recent gaps
+	TrendDaysPerGap  float64   `json:"trend_days_per_gap"` // how much longer each gap gets
+	ProjectedGapDays float64   `json:"projected_gap_days"` // the next gap if the trend holds
+	DueDate          time.Time `json:"due_date"`           // when the contact becomes overdue
+}
+
+// InteractionGaps returns the days between consecutive interactions up to
+// now, oldest first. Interactions on the same day count once.
+func InteractionGaps(logs []*InteractionLog, now time.Time) []float64 {
+	var times []time.Time
+	for _, log := range logs {
+		if !log.Timestamp.After(now) {
+			times = append(times, log.Timestamp)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	var gaps []float64
+	for i := 1; i < len(times); i++ {
+		days := times[i].Sub(times[i-1]).Hours() / 24
+		if days < 1 {
+			continue
+		}
+		gaps = append(gaps, days)
+	}
+	return gaps
+}
+
+// ForecastGoingCold fits a least-squares line to the contact's last
+// GoingColdWindow gaps and projects the next one. A contact is going cold
+// when the gaps are growing and the projected gap exceeds their cadence
+// while they are not yet overdue. It returns nil otherwise.
+func ForecastGoingCold(logs []*InteractionLog, cadence *ContactCadence, now time.Time) *ColdForecast {
+	if cadence.CadenceDays <= 0 {
+		return nil
+	}
+	gaps := InteractionGaps(logs, now)
+	if len(gaps) < MinGoingColdGaps {
+		return nil
+	}
+	if len(gaps) > GoingColdWindow {
+		gaps = gaps[len(gaps)-GoingColdWindow:]
+	}
+
+	var last time.Time
+	for _, log := range logs {
+		if !log.Timestamp.After(now) && log.Timestamp.After(last) {
+			last = log.Timestamp
+		}
+	}
+	daysSince := int(now.Sub(last).Hours() / 24)
+	if daysSince > cadence.CadenceDays {
+		return nil // already overdue, so it's in the follow-up list
+	}
+
+	n := float64(len(gaps))
+	mean := 0.0
+	for _, gap := range gaps {
+		mean += gap
+	}
+	mean /= n
+
+	// Slope of gap length against gap index; the index mean is (n-1)/2
+	xMean := (n - 1) / 2
+	var num, den float64
+	for i, gap := range gaps {
+		dx := float64(i) - xMean
+		num += dx * (gap - mean)
+		den += dx * dx
+	}
+	slope := num / den
+	if slope <= 0 {
+		return nil
+	}
+
+	projected := mean + slope*(n-xMean)
+	if projected <= float64(cadence.CadenceDays) {
+		return nil
+	}
+
+	return &ColdForecast{
+		ContactID:        cadence.ContactID,
+		Name:             cadence.ContactName,
+		CadenceDays:      cadence.CadenceDays,
+		DaysSinceContact: daysSince,
+		RecentGapDays:    mean,
+		TrendDaysPerGap:  slope,
+		ProjectedGapDays: projected,
+		DueDate:          last.AddDate(0, 0, cadence.CadenceDays),
+	}
+}
+
+// GetGoingColdList returns contacts likely to lapse before they are overdue,
+// the furthest projected past their cadence first.
+func (c *Client) GetGoingColdList(limit int) ([]*ColdForecast, error) {
+	cadences, err := c.ListContactCadences()
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.ListInteractionLogs(nil)
+	if err != nil {
+		return nil, err
+	}
+	byContact := make(map[uuid.UUID][]*InteractionLog)
+	for _, log := range logs {
+		byContact[log.ContactID] = append(byContact[log.ContactID], log)
+	}
+
+	now := time.Now()
+	var forecasts []*ColdForecast
+	for _, cadence := range cadences {
+		forecast := ForecastGoingCold(byContact[cadence.ContactID], cadence, now)
+		if forecast == nil {
+			continue
+		}
+		contact, err := c.GetContact(cadence.ContactID)
+		if err != nil {
+			continue // Skip if contact not found
+		}
+		if contact.ArchivedAt != nil {
+			continue
+		}
+		forecast.Name = contact.Name
+		forecasts = append(forecasts, forecast)
+	}
+
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		return forecasts[i].ProjectedGapDays/float64(forecasts[i].CadenceDays) >
+			forecasts[j].ProjectedGapDays/float64(forecasts[j].CadenceDays)
+	})
+	if limit > 0 && len(forecasts) > limit {
+		forecasts = forecasts[:limit]
+	}
+	return forecasts, nil
+}
@@ -0,0 +1,97 @@
+// ABOUTME: Tests for the going-cold forecast
+// ABOUTME: Verifies gap trends against the cadence and the going-cold list
+
+package charm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// logsAtDaysAgo builds interactions the given numbers of days before now.
+func logsAtDaysAgo(contactID uuid.UUID, now time.Time, daysAgo ...int) []*InteractionLog {
+	logs := make([]*InteractionLog, len(daysAgo))
+	for i, days := range daysAgo {
+		logs[i] = &InteractionLog{ContactID: contactID, InteractionType: InteractionCall, Timestamp: now.AddDate(0, 0, -days)}
+	}
+	return logs
+}
+
+func TestInteractionGaps(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	logs := logsAtDaysAgo(uuid.New(), now, 5, 30, 30, 10)
+	logs = append(logs, &InteractionLog{Timestamp: now.AddDate(0, 0, 3)}) // future, ignored
+
+	gaps := InteractionGaps(logs, now)
+	if len(gaps) != 2 || gaps[0] != 20 || gaps[1] != 5 {
+		t.Errorf("expected gaps [20 5], got %v", gaps)
+	}
+}
+
+func TestForecastGoingCold(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	contactID := uuid.New()
+	cadence := &ContactCadence{ContactID: contactID, CadenceDays: 30}
+
+	tests := []struct {
+		name    string
+		daysAgo []int
+		cold    bool
+	}{
+		// Gaps 15, 20, 25, 30 project to 35, past the cadence
+		{"lengthening gaps", []int{95, 80, 60, 35, 5}, true},
+		{"steady gaps", []int{80, 60, 40, 20, 0}, false},
+		{"shrinking gaps", []int{70, 45, 25, 10, 2}, false},
+		{"growing but well within cadence", []int{14, 13, 11, 8, 4}, false},
+		{"already overdue", []int{120, 110, 95, 75, 40}, false},
+		{"too few interactions", []int{40, 20, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := ForecastGoingCold(logsAtDaysAgo(contactID, now, tt.daysAgo...), cadence, now)
+			if (forecast != nil) != tt.cold {
+				t.Fatalf("expected going cold %v, got %+v", tt.cold, forecast)
+			}
+			if forecast == nil {
+				return
+			}
+			if forecast.ProjectedGapDays <= 30 || forecast.TrendDaysPerGap != 5 || forecast.DaysSinceContact != 5 {
+				t.Errorf("unexpected forecast: %+v", forecast)
+			}
+			if forecast.DueDate.Format("2006-01-02") != "2024-06-26" {
+				t.Errorf("expected due 2024-06-26, got %s", forecast.DueDate)
+			}
+		})
+	}
+}
+
+func TestGetGoingColdList(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+
+	cooling := &Contact{Name: "Cooling"}
+	steady := &Contact{Name: "Steady"}
+	for _, contact := range []*Contact{cooling, steady} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+		if err := client.SaveContactCadence(&ContactCadence{ContactID: contact.ID, CadenceDays: 30, RelationshipStrength: StrengthMedium}); err != nil {
+			t.Fatalf("failed to save cadence: %v", err)
+		}
+	}
+	for _, log := range append(logsAtDaysAgo(cooling.ID, now, 95, 80, 60, 35, 5), logsAtDaysAgo(steady.ID, now, 80, 60, 40, 20, 0)...) {
+		if err := client.CreateInteractionLog(log); err != nil {
+			t.Fatalf("failed to log interaction: %v", err)
+		}
+	}
+
+	forecasts, err := client.GetGoingColdList(0)
+	if err != nil {
+		t.Fatalf("GetGoingColdList failed: %v", err)
+	}
+	if len(forecasts) != 1 || forecasts[0].Name != "Cooling" {
+		t.Fatalf("expected only Cooling, got %+v", forecasts)
+	}
+}
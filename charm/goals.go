@@ -0,0 +1,126 @@
+// ABOUTME: Weekly outreach goals per contact tag, e.g. touch 5 VIPs a week
+// ABOUTME: Progress counts distinct tagged contacts with an interaction logged since Monday
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// goalSuggestions is how many untouched contacts a goal suggests.
+const goalSuggestions = 3
+
+// GoalProgress is how far along a weekly outreach goal is.
+type GoalProgress struct {
+	Tag       string    `json:"tag"`
+	Target    int       `json:"target"`  // distinct contacts to touch per week
+	Touched   int       `json:"touched"` // distinct contacts touched so far this week
+	WeekStart time.Time `json:"week_start"`
+
+	// Suggestions are tagged contacts not touched this week, longest out of touch first
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// Met reports whether the week's target has been reached.
+func (p *GoalProgress) Met() bool {
+	return p.Touched >= p.Target
+}
+
+// Remaining is how many more contacts need touching this week.
+func (p *GoalProgress) Remaining() int {
+	return max(p.Target-p.Touched, 0)
+}
+
+// Bar draws progress toward the target as a bar width blocks wide.
+func (p *GoalProgress) Bar(width int) string {
+	filled := width
+	if p.Target > 0 && p.Touched < p.Target {
+		filled = p.Touched * width / p.Target
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// Behind reports whether progress trails an even pace through the week. Only
+// whole days that have passed count, so nobody is behind on Monday.
+func (p *GoalProgress) Behind(now time.Time) bool {
+	days := int(now.Sub(p.WeekStart).Hours() / 24)
+	return p.Touched < p.Target*min(days, 7)/7
+}
+
+// Nudge is a gentle reminder for a goal that's behind, naming a few contacts
+// to reach out to, or "" when the goal is on pace.
+func (p *GoalProgress) Nudge(now time.Time) string {
+	if p.Met() || !p.Behind(now) {
+		return ""
+	}
+	nudge := fmt.Sprintf("%d of %d %s contacts so far this week; %d more to go", p.Touched, p.Target, p.Tag, p.Remaining())
+	if len(p.Suggestions) > 0 {
+		nudge += " (maybe " + strings.Join(p.Suggestions, ", ") + "?)"
+	}
+	return nudge
+}
+
+// WeekStartOf returns midnight on the Monday of t's week, in t's location.
+func WeekStartOf(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// GoalProgress measures each goal (tag to weekly target) against the
+// interactions logged since Monday, sorted by tag. Archived contacts don't
+// count.
+func (c *Client) GoalProgress(goals map[string]int, now time.Time) ([]*GoalProgress, error) {
+	if len(goals) == 0 {
+		return nil, nil
+	}
+	weekStart := WeekStartOf(now)
+
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	logs, err := c.ListInteractionLogs(&InteractionFilter{Since: &weekStart})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	touched := make(map[uuid.UUID]bool)
+	for _, log := range logs {
+		if !log.Timestamp.After(now) {
+			touched[log.ContactID] = true
+		}
+	}
+
+	var progress []*GoalProgress
+	for _, tag := range sortedKeys(goals) {
+		goal := &GoalProgress{Tag: tag, Target: goals[tag], WeekStart: weekStart}
+		var untouched []*Contact
+		for _, contact := range contacts {
+			if !contact.HasTag(tag) {
+				continue
+			}
+			if touched[contact.ID] {
+				goal.Touched++
+			} else {
+				untouched = append(untouched, contact)
+			}
+		}
+		// Never contacted sorts first, then oldest contact
+		sort.SliceStable(untouched, func(i, j int) bool {
+			a, b := untouched[i].LastContactedAt, untouched[j].LastContactedAt
+			if a == nil || b == nil {
+				return a == nil && b != nil
+			}
+			return a.Before(*b)
+		})
+		for _, contact := range untouched[:min(len(untouched), goalSuggestions)] {
+			goal.Suggestions = append(goal.Suggestions, contact.Name)
+		}
+		progress = append(progress, goal)
+	}
+	return progress, nil
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Tests for weekly outreach goals
+// ABOUTME: Checks progress counts distinct tagged contacts this week and nudges only when behind pace
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekStartOf(t *testing.T) {
+	sunday := time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)
+	if got := WeekStartOf(sunday); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected Monday March 4, got %v", got)
+	}
+	monday := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	if got := WeekStartOf(monday); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the same Monday, got %v", got)
+	}
+}
+
+func TestGoalProgress(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Date(2024, 3, 7, 12, 0, 0, 0, time.Local) // Thursday
+	longAgo := now.AddDate(0, -6, 0)
+	lastMonth := now.AddDate(0, -1, 0)
+
+	contacts := map[string]*Contact{
+		"Ada":   {Name: "Ada", Tags: []string{"vip"}},
+		"Grace": {Name: "Grace", Tags: []string{"vip"}, LastContactedAt: &lastMonth},
+		"Linus": {Name: "Linus", Tags: []string{"vip"}, LastContactedAt: &longAgo},
+		"Ken":   {Name: "Ken", Tags: []string{"vip"}},
+		"Rob":   {Name: "Rob"},
+	}
+	for _, contact := range contacts {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	logs := []*InteractionLog{
+		{ContactID: contacts["Ken"].ID, InteractionType: "call", Timestamp: now.AddDate(0, 0, -1)},
+		{ContactID: contacts["Ken"].ID, InteractionType: "email", Timestamp: now.Add(-time.Hour)},  // same contact twice
+		{ContactID: contacts["Rob"].ID, InteractionType: "call", Timestamp: now.Add(-time.Hour)},   // not a VIP
+		{ContactID: contacts["Ada"].ID, InteractionType: "call", Timestamp: now.AddDate(0, 0, -7)}, // last week
+	}
+	for _, log := range logs {
+		if err := client.CreateInteractionLog(log); err != nil {
+			t.Fatalf("CreateInteractionLog failed: %v", err)
+		}
+	}
+
+	progress, err := client.GoalProgress(map[string]int{"vip": 4}, now)
+	if err != nil {
+		t.Fatalf("GoalProgress failed: %v", err)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("expected 1 goal, got %d", len(progress))
+	}
+	goal := progress[0]
+	if goal.Touched != 1 || goal.Remaining() != 3 || goal.Met() {
+		t.Errorf("expected 1 of 4 touched, got %+v", goal)
+	}
+	// Never contacted first, then longest out of touch
+	if len(goal.Suggestions) != 3 || goal.Suggestions[0] != "Ada" || goal.Suggestions[1] != "Linus" || goal.Suggestions[2] != "Grace" {
+		t.Errorf("unexpected suggestions %v", goal.Suggestions)
+	}
+	if goal.Bar(4) != "█░░░" {
+		t.Errorf("unexpected bar %q", goal.Bar(4))
+	}
+
+	// Three days in, an even pace is one contact
+	if goal.Behind(now) {
+		t.Error("expected 1 of 4 by Thursday to be on pace")
+	}
+	if goal.Nudge(now) != "" {
+		t.Errorf("expected no nudge on pace, got %q", goal.Nudge(now))
+	}
+	saturday := now.AddDate(0, 0, 2)
+	if !goal.Behind(saturday) || goal.Nudge(saturday) == "" {
+		t.Error("expected 1 of 4 by Saturday to be behind with a nudge")
+	}
+	if monday := goal.WeekStart.Add(time.Hour); goal.Behind(monday) {
+		t.Error("expected nobody to be behind on Monday")
+	}
+}
@@ -0,0 +1,413 @@
+// ABOUTME: Greeting queue built from contact birthdays, work and met anniversaries, and configured holidays
+// ABOUTME: Renders message drafts from Go templates, with defaults per greeting kind
+
+package charm
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Greeting kinds.
+const (
+	GreetingBirthday    = "birthday"
+	GreetingAnniversary = "anniversary"
+	GreetingMet         = "met"
+	GreetingHoliday     = "holiday"
+)
+
+// GreetingKinds lists the greeting kinds in display order.
+var GreetingKinds = []string{GreetingBirthday, GreetingAnniversary, GreetingMet, GreetingHoliday}
+
+// DefaultGreetingTemplates are used for kinds without a template in the config.
+var DefaultGreetingTemplates = map[string]string{
+	GreetingBirthday:    "Happy birthday, {{.FirstName}}! Hope the year ahead is a great one.",
+	GreetingAnniversary: "Congrats on {{.Years}} year{{if ne .Years 1}}s{{end}}{{if .Company}} at {{.Company}}{{end}}, {{.FirstName}}!",
+	GreetingMet:         "Hi {{.FirstName}}! I just realized we met {{.Years}} year{{if ne .Years 1}}s{{end}} ago today. How have you been?",
+	GreetingHoliday:     "Happy {{.Occasion}}, {{.FirstName}}! Wishing you and yours a wonderful day.",
+}
+
+// MetAnniversaryEveryone is the MetAnniversaries key for contacts without a
+// listed tag.
+const MetAnniversaryEveryone = "*"
+
+// DefaultMetAnniversaries greets every contact a year after meeting them. It
+// applies when the config sets no met anniversaries.
+var DefaultMetAnniversaries = map[string][]int{MetAnniversaryEveryone: {1}}
+
+// Holiday is a yearly date greeted for contacts in the listed countries.
+type Holiday struct {
+	Name      string   `json:"name"`
+	Date      string   `json:"date"`                // MM-DD
+	Countries []string `json:"countries,omitempty"` // ISO 3166 codes; empty means every contact
+}
+
+// AppliesTo reports whether the holiday is greeted for a contact in country.
+func (h Holiday) AppliesTo(country string) bool {
+	if len(h.Countries) == 0 {
+		return true
+	}
+	for _, c := range h.Countries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// Greeting is one occasion to reach out to a contact.
+type Greeting struct {
+	Kind     string
+	Contact  *Contact
+	Date     time.Time
+	Occasion string // "Birthday", "Work anniversary", "Met 1 year ago", or the holiday name
+	Years    int    // age, years at the company, or years since meeting; 0 when unknown
+}
+
+// ParseMonthDay parses an MM-DD date.
+func ParseMonthDay(s string) (time.Month, int, error) {
+	t, err := time.Parse("01-02", s)
+	if err != nil {
+		return 0, 0, Validationf("invalid date %q (use MM-DD): %w", s, err)
+	}
+	return t.Month(), t.Day(), nil
+}
+
+// ParseBirthday parses a YYYY-MM-DD or MM-DD birthday. The year is 0 when unknown.
+func ParseBirthday(s string) (year int, month time.Month, day int, err error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Year(), t.Month(), t.Day(), nil
+	}
+	// Feb 29 doesn't parse without a leap year
+	t, err := time.Parse("2006-01-02", "2000-"+s)
+	if err != nil {
+		return 0, 0, 0, Validationf("invalid birthday %q (use YYYY-MM-DD or MM-DD)", s)
+	}
+	return 0, t.Month(), t.Day(), nil
+}
+
+// SetGreetingFields validates and applies the birthday, work start date
+// (YYYY-MM-DD), and country used by the greeting queue. Empty values leave the
+// contact unchanged.
+func (c *Contact) SetGreetingFields(birthday, workStart, country string) error {
+	if birthday != "" {
+		if _, _, _, err := ParseBirthday(birthday); err != nil {
+			return err
+		}
+		c.Birthday = birthday
+	}
+	if workStart != "" {
+		started, err := time.Parse("2006-01-02", workStart)
+		if err != nil {
+			return Validationf("invalid work start date %q (use YYYY-MM-DD)", workStart)
+		}
+		c.WorkStartDate = &started
+	}
+	if country != "" {
+		c.Country = strings.ToUpper(strings.TrimSpace(country))
+	}
+	return nil
+}
+
+// BuildGreetingQueue returns the greetings falling on the days days starting at
+// from, ordered by date, kind, and contact name. Feb 29 dates are greeted on
+// Feb 28 in other years.
+func BuildGreetingQueue(contacts []*Contact, holidays []Holiday, from time.Time, days int) []*Greeting {
+	if days < 1 {
+		days = 1
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := start.AddDate(0, 0, days)
+
+	type holidayDate struct {
+		holiday Holiday
+		month   time.Month
+		day     int
+	}
+	var dated []holidayDate
+	for _, h := range holidays {
+		if month, day, err := ParseMonthDay(h.Date); err == nil {
+			dated = append(dated, holidayDate{h, month, day})
+		}
+	}
+
+	var queue []*Greeting
+	for _, contact := range contacts {
+		if contact.Birthday != "" {
+			if year, month, day, err := ParseBirthday(contact.Birthday); err == nil {
+				for _, date := range occurrences(month, day, start, end) {
+					g := &Greeting{Kind: GreetingBirthday, Contact: contact, Date: date, Occasion: "Birthday"}
+					if year > 0 {
+						g.Years = date.Year() - year
+					}
+					queue = append(queue, g)
+				}
+			}
+		}
+
+		if contact.WorkStartDate != nil {
+			started := *contact.WorkStartDate
+			for _, date := range occurrences(started.Month(), started.Day(), start, end) {
+				if years := date.Year() - started.Year(); years > 0 {
+					queue = append(queue, &Greeting{
+						Kind: GreetingAnniversary, Contact: contact, Date: date,
+						Occasion: "Work anniversary", Years: years,
+					})
+				}
+			}
+		}
+
+		for _, h := range dated {
+			if !h.holiday.AppliesTo(contact.Country) {
+				continue
+			}
+			for _, date := range occurrences(h.month, h.day, start, end) {
+				queue = append(queue, &Greeting{Kind: GreetingHoliday, Contact: contact, Date: date, Occasion: h.holiday.Name})
+			}
+		}
+	}
+
+	sortGreetings(queue)
+	return queue
+}
+
+// sortGreetings orders greetings by date, kind, and contact name.
+func sortGreetings(queue []*Greeting) {
+	kindOrder := make(map[string]int, len(GreetingKinds))
+	for i, k := range GreetingKinds {
+		kindOrder[k] = i
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.Contact.Name < b.Contact.Name
+	})
+}
+
+// ValidateMetAnniversaries checks that each tag lists positive years.
+func ValidateMetAnniversaries(rules map[string][]int) error {
+	for tag, years := range rules {
+		for _, y := range years {
+			if y <= 0 {
+				return Validationf("met anniversaries for %s: years must be positive, got %d", tag, y)
+			}
+		}
+	}
+	return nil
+}
+
+// metAnniversaryYears returns which anniversaries of meeting contact are
+// greeted: those listed for any of its tags, or for MetAnniversaryEveryone
+// when none of its tags is listed. A tag listed with no years turns the
+// reminders off for its contacts.
+func metAnniversaryYears(contact *Contact, rules map[string][]int) []int {
+	var years []int
+	matched := false
+	for _, tag := range contact.Tags {
+		if listed, ok := rules[tag]; ok {
+			matched = true
+			years = append(years, listed...)
+		}
+	}
+	if !matched {
+		years = rules[MetAnniversaryEveryone]
+	}
+	return years
+}
+
+// BuildMetReminders returns "we met N years ago" greetings for the days days
+// starting at from, using each contact's first meeting from firstMet and the
+// years rules lists for its tags (DefaultMetAnniversaries when rules is
+// empty). Contacts missing from firstMet are skipped.
+func BuildMetReminders(contacts []*Contact, firstMet map[uuid.UUID]time.Time, rules map[string][]int, from time.Time, days int) []*Greeting {
+	if days < 1 {
+		days = 1
+	}
+	if len(rules) == 0 {
+		rules = DefaultMetAnniversaries
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := start.AddDate(0, 0, days)
+
+	var queue []*Greeting
+	for _, contact := range contacts {
+		met, ok := firstMet[contact.ID]
+		if !ok {
+			continue
+		}
+		years := metAnniversaryYears(contact, rules)
+		if len(years) == 0 {
+			continue
+		}
+		met = met.In(start.Location())
+		for _, date := range occurrences(met.Month(), met.Day(), start, end) {
+			n := date.Year() - met.Year()
+			if n <= 0 || !slices.Contains(years, n) {
+				continue
+			}
+			occasion := fmt.Sprintf("Met %d years ago", n)
+			if n == 1 {
+				occasion = "Met 1 year ago"
+			}
+			queue = append(queue, &Greeting{Kind: GreetingMet, Contact: contact, Date: date, Occasion: occasion, Years: n})
+		}
+	}
+	sortGreetings(queue)
+	return queue
+}
+
+// FirstMetDates returns when each contact was first met: its met date when
+// set, otherwise its earliest logged interaction.
+func (c *Client) FirstMetDates(contacts []*Contact) (map[uuid.UUID]time.Time, error) {
+	firstMet := make(map[uuid.UUID]time.Time, len(contacts))
+	for _, contact := range contacts {
+		if contact.MetDate != nil {
+			firstMet[contact.ID] = *contact.MetDate
+		}
+	}
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	undated := make(map[uuid.UUID]bool, len(contacts))
+	for _, contact := range contacts {
+		undated[contact.ID] = contact.MetDate == nil
+	}
+	for _, interaction := range interactions {
+		if !undated[interaction.ContactID] {
+			continue
+		}
+		if first, ok := firstMet[interaction.ContactID]; !ok || interaction.Timestamp.Before(first) {
+			firstMet[interaction.ContactID] = interaction.Timestamp
+		}
+	}
+	return firstMet, nil
+}
+
+// MetReminders returns the "we met N years ago" greetings over the days days
+// starting at from, using the met anniversaries from the config.
+func (c *Client) MetReminders(from time.Time, days int) ([]*Greeting, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	return c.metReminders(contacts, from, days)
+}
+
+func (c *Client) metReminders(contacts []*Contact, from time.Time, days int) ([]*Greeting, error) {
+	firstMet, err := c.FirstMetDates(contacts)
+	if err != nil {
+		return nil, err
+	}
+	var rules map[string][]int
+	if cfg := c.Config(); cfg != nil {
+		rules = cfg.MetAnniversaries
+	}
+	return BuildMetReminders(contacts, firstMet, rules, from, days), nil
+}
+
+// occurrences returns each yearly month/day falling in [start, end).
+func occurrences(month time.Month, day int, start, end time.Time) []time.Time {
+	var dates []time.Time
+	for year := start.Year(); year <= end.Year(); year++ {
+		d := day
+		if month == time.February && day == 29 && !isLeapYear(year) {
+			d = 28
+		}
+		date := time.Date(year, month, d, 0, 0, 0, 0, start.Location())
+		if !date.Before(start) && date.Before(end) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// GreetingQueue returns the greetings for every contact over the days days
+// starting at from, using the holidays and met anniversaries from the config.
+func (c *Client) GreetingQueue(from time.Time, days int) ([]*Greeting, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	var holidays []Holiday
+	if cfg := c.Config(); cfg != nil {
+		holidays = cfg.Holidays
+	}
+	met, err := c.metReminders(contacts, from, days)
+	if err != nil {
+		return nil, err
+	}
+	queue := append(BuildGreetingQueue(contacts, holidays, from, days), met...)
+	sortGreetings(queue)
+	return queue, nil
+}
+
+// GreetingTemplateData is the value passed to greeting templates as ".".
+type GreetingTemplateData struct {
+	Name      string
+	FirstName string
+	Company   string
+	Occasion  string
+	Years     int
+	Date      time.Time
+}
+
+// DraftGreeting renders a message draft for g. templates override
+// DefaultGreetingTemplates per kind.
+func DraftGreeting(g *Greeting, templates map[string]string) (string, error) {
+	text, ok := templates[g.Kind]
+	if !ok || strings.TrimSpace(text) == "" {
+		text = DefaultGreetingTemplates[g.Kind]
+	}
+	tmpl, err := template.New(g.Kind).Parse(text)
+	if err != nil {
+		return "", Validationf("invalid %s template: %w", g.Kind, err)
+	}
+
+	data := GreetingTemplateData{
+		Name:     g.Contact.Name,
+		Company:  g.Contact.CompanyName,
+		Occasion: g.Occasion,
+		Years:    g.Years,
+		Date:     g.Date,
+	}
+	data.FirstName, _, _ = strings.Cut(strings.TrimSpace(g.Contact.Name), " ")
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", g.Kind, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// ValidateGreetingTemplate checks that text renders for a greeting of kind.
+func ValidateGreetingTemplate(kind, text string) error {
+	if _, ok := DefaultGreetingTemplates[kind]; !ok {
+		return fmt.Errorf("unknown greeting kind %q (use %s)", kind, strings.Join(GreetingKinds, ", "))
+	}
+	sample := &Greeting{
+		Kind:     kind,
+		Contact:  &Contact{Name: "Ada Lovelace", CompanyName: "Analytical Engines"},
+		Date:     time.Now(),
+		Occasion: "Sample",
+		Years:    3,
+	}
+	_, err := DraftGreeting(sample, map[string]string{kind: text})
+	return err
+}
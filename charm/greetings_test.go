@@ -0,0 +1,215 @@
+// ABOUTME: Tests for the greeting queue and message drafts
+// ABOUTME: Covers birthdays with and without a year, anniversaries, met reminders per tag, per-country holidays, and Feb 29
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildGreetingQueue(t *testing.T) {
+	started := time.Date(2021, 10, 18, 0, 0, 0, 0, time.UTC)
+	newHire := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	contacts := []*Contact{
+		{Name: "Ada Lovelace", Birthday: "1990-10-17", Country: "GB"},
+		{Name: "Bob Smith", Birthday: "10-19", WorkStartDate: &started, Country: "us", CompanyName: "Acme"},
+		{Name: "Carol Jones", WorkStartDate: &newHire},
+	}
+	holidays := []Holiday{
+		{Name: "Thanksgiving (Canada)", Date: "10-13", Countries: []string{"CA"}},
+		{Name: "Founders Day", Date: "10-18", Countries: []string{"US"}},
+		{Name: "Pagen Day", Date: "10-17"},
+		{Name: "Broken", Date: "13-45"},
+	}
+	from := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	today := BuildGreetingQueue(contacts, holidays, from, 1)
+	var got []string
+	for _, g := range today {
+		got = append(got, g.Contact.Name+"/"+g.Occasion)
+	}
+	want := []string{"Ada Lovelace/Birthday", "Ada Lovelace/Pagen Day", "Bob Smith/Pagen Day", "Carol Jones/Pagen Day"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("today: got %v, want %v", got, want)
+	}
+	if today[0].Years != 36 {
+		t.Errorf("expected age 36, got %d", today[0].Years)
+	}
+
+	week := BuildGreetingQueue(contacts, holidays, from, 7)
+	var anniversary, founders, bobBirthday *Greeting
+	for _, g := range week {
+		switch {
+		case g.Kind == GreetingAnniversary:
+			anniversary = g
+		case g.Occasion == "Founders Day":
+			founders = g
+		case g.Kind == GreetingBirthday && g.Contact.Name == "Bob Smith":
+			bobBirthday = g
+		}
+	}
+	if anniversary == nil || anniversary.Contact.Name != "Bob Smith" || anniversary.Years != 5 {
+		t.Errorf("expected Bob's 5 year anniversary, got %+v", anniversary)
+	}
+	if founders == nil || founders.Contact.Name != "Bob Smith" {
+		t.Errorf("expected the US holiday for the US contact only, got %+v", founders)
+	}
+	if bobBirthday == nil || bobBirthday.Years != 0 {
+		t.Errorf("expected Bob's birthday without an age, got %+v", bobBirthday)
+	}
+	for i := 1; i < len(week); i++ {
+		if week[i].Date.Before(week[i-1].Date) {
+			t.Fatal("expected greetings ordered by date")
+		}
+	}
+}
+
+func TestGreetingQueueLeapDayAndYearEnd(t *testing.T) {
+	contacts := []*Contact{
+		{Name: "Leap", Birthday: "02-29"},
+		{Name: "Eve", Birthday: "2000-01-01"},
+	}
+
+	queue := BuildGreetingQueue(contacts, nil, time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC), 1)
+	if len(queue) != 1 || queue[0].Contact.Name != "Leap" {
+		t.Errorf("expected Feb 29 birthday on Feb 28 in a common year, got %+v", queue)
+	}
+
+	queue = BuildGreetingQueue(contacts, nil, time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC), 5)
+	if len(queue) != 1 || queue[0].Years != 27 || queue[0].Date.Year() != 2027 {
+		t.Errorf("expected the window to cross into the new year, got %+v", queue)
+	}
+}
+
+func TestBuildMetReminders(t *testing.T) {
+	ada := &Contact{ID: uuid.New(), Name: "Ada", Tags: []string{"vip"}}
+	bob := &Contact{ID: uuid.New(), Name: "Bob"}
+	cy := &Contact{ID: uuid.New(), Name: "Cy", Tags: []string{"newsletter"}}
+	dee := &Contact{ID: uuid.New(), Name: "Dee", Tags: []string{"newsletter", "vip"}}
+	never := &Contact{ID: uuid.New(), Name: "Never met"}
+	contacts := []*Contact{ada, bob, cy, dee, never}
+	firstMet := map[uuid.UUID]time.Time{
+		ada.ID: time.Date(2021, 10, 17, 9, 0, 0, 0, time.UTC),
+		bob.ID: time.Date(2025, 10, 18, 9, 0, 0, 0, time.UTC),
+		cy.ID:  time.Date(2025, 10, 17, 9, 0, 0, 0, time.UTC),
+		dee.ID: time.Date(2025, 10, 17, 9, 0, 0, 0, time.UTC),
+	}
+	rules := map[string][]int{"vip": {1, 5}, "newsletter": {}, MetAnniversaryEveryone: {1}}
+	from := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	describe := func(queue []*Greeting) string {
+		var got []string
+		for _, g := range queue {
+			if g.Kind != GreetingMet {
+				t.Errorf("unexpected kind %s", g.Kind)
+			}
+			got = append(got, g.Date.Format("01-02")+" "+g.Contact.Name+"/"+g.Occasion)
+		}
+		return strings.Join(got, ",")
+	}
+
+	// Ada's 5th is listed for vip, Cy's newsletter tag turns them off, and
+	// Dee's vip tag still counts
+	if got := describe(BuildMetReminders(contacts, firstMet, rules, from, 1)); got != "10-17 Ada/Met 5 years ago,10-17 Dee/Met 1 year ago" {
+		t.Errorf("today: got %s", got)
+	}
+	if got := describe(BuildMetReminders(contacts, firstMet, rules, from, 2)); !strings.HasSuffix(got, ",10-18 Bob/Met 1 year ago") {
+		t.Errorf("two days: got %s", got)
+	}
+	// Without rules everyone is reminded on the first anniversary only
+	if got := describe(BuildMetReminders(contacts, firstMet, nil, from, 1)); got != "10-17 Cy/Met 1 year ago,10-17 Dee/Met 1 year ago" {
+		t.Errorf("defaults: got %s", got)
+	}
+
+	if err := ValidateMetAnniversaries(map[string][]int{"vip": {0}}); CodeOf(err) != CodeValidation {
+		t.Errorf("expected a validation error for year 0, got %v", err)
+	}
+}
+
+func TestGreetingQueueMetFromInteractions(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	metDate := time.Date(2024, 10, 17, 0, 0, 0, 0, time.UTC)
+	ada := &Contact{Name: "Ada"}
+	bob := &Contact{Name: "Bob", MetDate: &metDate, MetAt: "PyCon"}
+	for _, contact := range []*Contact{ada, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	for _, at := range []time.Time{now.AddDate(-1, 0, 0), now.AddDate(0, -2, 0)} {
+		for _, contact := range []*Contact{ada, bob} {
+			log := &InteractionLog{ContactID: contact.ID, InteractionType: "meeting", Timestamp: at}
+			if err := client.CreateInteractionLog(log); err != nil {
+				t.Fatalf("CreateInteractionLog failed: %v", err)
+			}
+		}
+	}
+
+	// Ada's first interaction was a year ago; Bob's met date wins over his
+	client.Config().MetAnniversaries = map[string][]int{MetAnniversaryEveryone: {1, 2}}
+	queue, err := client.GreetingQueue(now, 1)
+	if err != nil {
+		t.Fatalf("GreetingQueue failed: %v", err)
+	}
+	var got []string
+	for _, g := range queue {
+		got = append(got, g.Contact.Name+"/"+g.Occasion)
+	}
+	if strings.Join(got, ",") != "Ada/Met 1 year ago,Bob/Met 2 years ago" {
+		t.Errorf("unexpected queue %v", got)
+	}
+
+	draft, err := DraftGreeting(queue[1], nil)
+	if err != nil || !strings.Contains(draft, "we met 2 years ago today") {
+		t.Errorf("unexpected draft %q (%v)", draft, err)
+	}
+}
+
+func TestDraftGreeting(t *testing.T) {
+	g := &Greeting{
+		Kind:     GreetingAnniversary,
+		Contact:  &Contact{Name: "Bob Smith", CompanyName: "Acme"},
+		Occasion: "Work anniversary",
+		Years:    1,
+	}
+	draft, err := DraftGreeting(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft != "Congrats on 1 year at Acme, Bob!" {
+		t.Errorf("unexpected default draft %q", draft)
+	}
+
+	draft, err = DraftGreeting(g, map[string]string{GreetingAnniversary: "{{.Name}}: {{.Years}}"})
+	if err != nil || draft != "Bob Smith: 1" {
+		t.Errorf("expected custom template, got %q, %v", draft, err)
+	}
+
+	if err := ValidateGreetingTemplate(GreetingBirthday, "Hi {{.Nickname}}"); err == nil {
+		t.Error("expected unknown template fields to be rejected")
+	}
+	if err := ValidateGreetingTemplate("wedding", "Hi"); err == nil {
+		t.Error("expected unknown kinds to be rejected")
+	}
+}
+
+func TestSetGreetingFields(t *testing.T) {
+	c := &Contact{Name: "Ada"}
+	if err := c.SetGreetingFields("02-29", "2020-01-15", " gb "); err != nil {
+		t.Fatal(err)
+	}
+	if c.Birthday != "02-29" || c.Country != "GB" || c.WorkStartDate == nil {
+		t.Errorf("unexpected fields %+v", c)
+	}
+	if err := c.SetGreetingFields("1990-13-01", "", ""); err == nil {
+		t.Error("expected invalid birthday to be rejected")
+	}
+	if err := c.SetGreetingFields("", "Jan 2020", ""); err == nil {
+		t.Error("expected invalid work start date to be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+// ABOUTME: Handoffs: small notes or contact references left for another linked device
+// ABOUTME: `pagen send` stores one and `pagen receive` pops it on the other machine after a sync
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Handoff kinds
+const (
+	HandoffNote    = "note"
+	HandoffContact = "contact"
+)
+
+// MaxHandoffSize caps a handoff's text; it is for drafts, not files.
+const MaxHandoffSize = 64 << 10
+
+// Handoff is a payload sent from one device to be popped on another. A
+// contact handoff keeps the contact's name as it was when sent.
+type Handoff struct {
+	ID        uuid.UUID  `json:"id"`
+	Kind      string     `json:"kind"` // note or contact
+	Text      string     `json:"text"` // the note, or the contact's name
+	ContactID *uuid.UUID `json:"contact_id,omitempty"`
+	From      string     `json:"from,omitempty"` // sending device's hostname
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SendHandoff stores a handoff for another device to receive. The ID and
+// creation time are filled in.
+func (c *Client) SendHandoff(h *Handoff) error {
+	switch h.Kind {
+	case HandoffNote:
+		if strings.TrimSpace(h.Text) == "" {
+			return fmt.Errorf("nothing to send")
+		}
+	case HandoffContact:
+		if h.ContactID == nil {
+			return fmt.Errorf("contact handoff needs a contact ID")
+		}
+	default:
+		return Validationf("invalid handoff kind: %s (use %s or %s)", h.Kind, HandoffNote, HandoffContact)
+	}
+	if len(h.Text) > MaxHandoffSize {
+		return fmt.Errorf("handoff is %d bytes; the limit is %d", len(h.Text), MaxHandoffSize)
+	}
+
+	h.ID = uuid.New()
+	h.CreatedAt = time.Now()
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal handoff: %w", err)
+	}
+	return c.Set(HandoffKey(h.ID.String()), data)
+}
+
+// ListHandoffs returns the handoffs waiting to be received, oldest first.
+func (c *Client) ListHandoffs() ([]*Handoff, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixHandoff))
+	if err != nil {
+		return nil, err
+	}
+
+	var handoffs []*Handoff
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var h Handoff
+		if err := json.Unmarshal(data, &h); err != nil {
+			continue
+		}
+		handoffs = append(handoffs, &h)
+	}
+
+	sort.Slice(handoffs, func(i, j int) bool {
+		return handoffs[i].CreatedAt.Before(handoffs[j].CreatedAt)
+	})
+	return handoffs, nil
+}
+
+// PopHandoffs returns the waiting handoffs, oldest first, and deletes them.
+// With latest set only the newest is popped and the rest keep waiting.
+func (c *Client) PopHandoffs(latest bool) ([]*Handoff, error) {
+	handoffs, err := c.ListHandoffs()
+	if err != nil {
+		return nil, err
+	}
+	if latest && len(handoffs) > 1 {
+		handoffs = handoffs[len(handoffs)-1:]
+	}
+	for _, h := range handoffs {
+		if err := c.Delete(HandoffKey(h.ID.String())); err != nil {
+			return nil, fmt.Errorf("failed to delete handoff: %w", err)
+		}
+	}
+	return handoffs, nil
+}
@@ -0,0 +1,66 @@
+// ABOUTME: Tests for handoffs between linked devices
+// ABOUTME: Covers sending notes and contacts, receive order, and popping only the newest
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandoffs(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	if err := client.SendHandoff(&Handoff{Kind: HandoffNote, Text: "Draft: thanks for the intro", From: "phone"}); err != nil {
+		t.Fatalf("SendHandoff note failed: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond) // distinct creation times
+	if err := client.SendHandoff(&Handoff{Kind: HandoffContact, Text: contact.Name, ContactID: &contact.ID}); err != nil {
+		t.Fatalf("SendHandoff contact failed: %v", err)
+	}
+
+	for _, bad := range []*Handoff{
+		{Kind: HandoffNote, Text: "  "},
+		{Kind: HandoffContact, Text: "Bob"},
+		{Kind: "file", Text: "x"},
+		{Kind: HandoffNote, Text: strings.Repeat("x", MaxHandoffSize+1)},
+	} {
+		if err := client.SendHandoff(bad); err == nil {
+			t.Errorf("expected %s handoff %q to be rejected", bad.Kind, bad.Text[:min(len(bad.Text), 10)])
+		}
+	}
+
+	waiting, err := client.ListHandoffs()
+	if err != nil {
+		t.Fatalf("ListHandoffs failed: %v", err)
+	}
+	if len(waiting) != 2 || waiting[0].Kind != HandoffNote || waiting[1].Kind != HandoffContact {
+		t.Fatalf("expected the note then the contact, got %+v", waiting)
+	}
+
+	popped, err := client.PopHandoffs(true)
+	if err != nil {
+		t.Fatalf("PopHandoffs latest failed: %v", err)
+	}
+	if len(popped) != 1 || popped[0].ContactID == nil || *popped[0].ContactID != contact.ID {
+		t.Fatalf("expected only the contact, got %+v", popped)
+	}
+
+	popped, err = client.PopHandoffs(false)
+	if err != nil {
+		t.Fatalf("PopHandoffs failed: %v", err)
+	}
+	if len(popped) != 1 || popped[0].Text != "Draft: thanks for the intro" || popped[0].From != "phone" {
+		t.Fatalf("expected the note, got %+v", popped)
+	}
+
+	if waiting, _ := client.ListHandoffs(); len(waiting) != 0 {
+		t.Errorf("expected nothing waiting, got %d", len(waiting))
+	}
+}
@@ -0,0 +1,143 @@
+// ABOUTME: Data-hygiene report for contacts, companies, and deals
+// ABOUTME: Finds records missing key fields and scores how complete the CRM data is
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields checked by the hygiene report.
+const (
+	HygieneFieldEmail     = "email"
+	HygieneFieldCompany   = "company"
+	HygieneFieldTitle     = "title"
+	HygieneFieldDomain    = "domain"
+	HygieneFieldCloseDate = "expected_close_date"
+)
+
+// HygieneIssue is a record missing one or more checked fields.
+type HygieneIssue struct {
+	EntityType string
+	ID         string
+	Name       string
+	Missing    []string
+	Score      int // percent of checked fields present
+}
+
+// HygieneReport lists incomplete records and the overall completeness score.
+type HygieneReport struct {
+	Contacts  []*HygieneIssue
+	Companies []*HygieneIssue
+	Deals     []*HygieneIssue // open deals only; closed deals don't need a close date
+
+	Checked int // total fields checked
+	Filled  int // checked fields with a value
+}
+
+// Score returns the percent of checked fields that have a value, or 100 when
+// there is nothing to check.
+func (r *HygieneReport) Score() int {
+	if r.Checked == 0 {
+		return 100
+	}
+	return r.Filled * 100 / r.Checked
+}
+
+// Issues returns every issue, contacts first.
+func (r *HygieneReport) Issues() []*HygieneIssue {
+	issues := make([]*HygieneIssue, 0, len(r.Contacts)+len(r.Companies)+len(r.Deals))
+	issues = append(issues, r.Contacts...)
+	issues = append(issues, r.Companies...)
+	return append(issues, r.Deals...)
+}
+
+// ContactQuality returns the percent of a contact's checked fields that have a
+// value, and the names of those that don't.
+func ContactQuality(contact *Contact) (int, []string) {
+	return fieldQuality(contactChecks(contact))
+}
+
+type hygieneCheck struct {
+	field   string
+	present bool
+}
+
+func contactChecks(contact *Contact) []hygieneCheck {
+	return []hygieneCheck{
+		{HygieneFieldEmail, strings.TrimSpace(contact.Email) != ""},
+		{HygieneFieldCompany, contact.CompanyID != nil},
+		{HygieneFieldTitle, strings.TrimSpace(contact.Title) != ""},
+	}
+}
+
+func fieldQuality(checks []hygieneCheck) (int, []string) {
+	var missing []string
+	for _, check := range checks {
+		if !check.present {
+			missing = append(missing, check.field)
+		}
+	}
+	return (len(checks) - len(missing)) * 100 / len(checks), missing
+}
+
+// BuildHygieneReport checks contacts for email, company, and title, companies
+// for a domain, and open deals for an expected close date. Issues are sorted
+// by score, worst first, then name.
+func BuildHygieneReport(contacts []*Contact, companies []*Company, deals []*Deal) *HygieneReport {
+	report := &HygieneReport{}
+	add := func(issues *[]*HygieneIssue, entityType, id, name string, checks []hygieneCheck) {
+		score, missing := fieldQuality(checks)
+		report.Checked += len(checks)
+		report.Filled += len(checks) - len(missing)
+		if len(missing) > 0 {
+			*issues = append(*issues, &HygieneIssue{EntityType: entityType, ID: id, Name: name, Missing: missing, Score: score})
+		}
+	}
+
+	for _, contact := range contacts {
+		add(&report.Contacts, EntityContact, contact.ID.String(), contact.Name, contactChecks(contact))
+	}
+	for _, company := range companies {
+		add(&report.Companies, EntityCompany, company.ID.String(), company.Name, []hygieneCheck{
+			{HygieneFieldDomain, strings.TrimSpace(company.Domain) != ""},
+		})
+	}
+	for _, deal := range deals {
+		if deal.Stage == StageClosedWon || deal.Stage == StageClosedLost {
+			continue
+		}
+		add(&report.Deals, EntityDeal, deal.ID.String(), deal.Title, []hygieneCheck{
+			{HygieneFieldCloseDate, deal.ExpectedCloseDate != nil},
+		})
+	}
+
+	for _, issues := range [][]*HygieneIssue{report.Contacts, report.Companies, report.Deals} {
+		sort.Slice(issues, func(i, j int) bool {
+			if issues[i].Score != issues[j].Score {
+				return issues[i].Score < issues[j].Score
+			}
+			return strings.ToLower(issues[i].Name) < strings.ToLower(issues[j].Name)
+		})
+	}
+	return report
+}
+
+// GetHygieneReport builds the data-hygiene report over the whole CRM.
+func (c *Client) GetHygieneReport() (*HygieneReport, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	companies, err := c.ListCompanies(&CompanyFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+	deals, err := c.ListDeals(&DealFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	return BuildHygieneReport(contacts, companies, deals), nil
+}
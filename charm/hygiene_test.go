@@ -0,0 +1,61 @@
+// ABOUTME: Tests for the data-hygiene report
+// ABOUTME: Verifies missing fields, per-record scores, and the overall completeness score
+
+package charm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildHygieneReport(t *testing.T) {
+	companyID := uuid.New()
+	closeDate := time.Now()
+
+	contacts := []*Contact{
+		{ID: uuid.New(), Name: "Complete", Email: "c@example.com", Title: "CTO", CompanyID: &companyID},
+		{ID: uuid.New(), Name: "Bare"},
+		{ID: uuid.New(), Name: "Almost", Email: "a@example.com", CompanyID: &companyID},
+	}
+	companies := []*Company{
+		{ID: companyID, Name: "Acme", Domain: "acme.com"},
+		{ID: uuid.New(), Name: "Nodomain"},
+	}
+	deals := []*Deal{
+		{ID: uuid.New(), Title: "Open", Stage: StageProposal},
+		{ID: uuid.New(), Title: "Dated", Stage: StageProposal, ExpectedCloseDate: &closeDate},
+		{ID: uuid.New(), Title: "Won", Stage: StageClosedWon},
+	}
+
+	report := BuildHygieneReport(contacts, companies, deals)
+
+	if len(report.Contacts) != 2 || report.Contacts[0].Name != "Bare" || report.Contacts[1].Name != "Almost" {
+		t.Fatalf("expected Bare then Almost, got %+v", report.Contacts)
+	}
+	if bare := report.Contacts[0]; bare.Score != 0 || len(bare.Missing) != 3 {
+		t.Errorf("unexpected score for Bare: %+v", bare)
+	}
+	if almost := report.Contacts[1]; almost.Score != 66 || len(almost.Missing) != 1 || almost.Missing[0] != HygieneFieldTitle {
+		t.Errorf("unexpected score for Almost: %+v", almost)
+	}
+	if len(report.Companies) != 1 || report.Companies[0].Name != "Nodomain" {
+		t.Errorf("expected Nodomain, got %+v", report.Companies)
+	}
+	if len(report.Deals) != 1 || report.Deals[0].Name != "Open" {
+		t.Errorf("expected only the open undated deal, got %+v", report.Deals)
+	}
+
+	// 9 contact fields (5 filled), 2 company (1), 2 open deals (1)
+	if report.Checked != 13 || report.Filled != 7 || report.Score() != 53 {
+		t.Errorf("unexpected totals: %d/%d (%d%%)", report.Filled, report.Checked, report.Score())
+	}
+	if len(report.Issues()) != 4 {
+		t.Errorf("expected 4 issues, got %d", len(report.Issues()))
+	}
+
+	if empty := BuildHygieneReport(nil, nil, nil); empty.Score() != 100 {
+		t.Errorf("expected an empty CRM to score 100, got %d", empty.Score())
+	}
+}
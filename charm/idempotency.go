@@ -0,0 +1,64 @@
+// ABOUTME: Idempotency keys for MCP write tools
+// ABOUTME: Records which object a keyed tool call created so retries return it instead of a duplicate
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyTTL is how long a recorded idempotency key is honored.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyRecord is the object a tool call created for an idempotency key.
+type IdempotencyRecord struct {
+	Tool      string    `json:"tool"`
+	Key       string    `json:"key"`
+	ObjectID  uuid.UUID `json:"object_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LookupIdempotencyKey returns the record for a tool's idempotency key, or nil
+// when the key is unused or older than IdempotencyKeyTTL.
+func (c *Client) LookupIdempotencyKey(tool, key string) (*IdempotencyRecord, error) {
+	data, err := c.Get(IdempotencyKey(tool, key))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	if time.Since(record.CreatedAt) > IdempotencyKeyTTL {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// SaveIdempotencyKey records that a tool call with key created objectID.
+func (c *Client) SaveIdempotencyKey(tool, key string, objectID uuid.UUID) error {
+	data, err := json.Marshal(&IdempotencyRecord{
+		Tool:      tool,
+		Key:       key,
+		ObjectID:  objectID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	return c.Set(IdempotencyKey(tool, key), data)
+}
@@ -0,0 +1,166 @@
+// ABOUTME: Company inference from contact email domains
+// ABOUTME: Groups contacts without a company by work email domain and links them to a matching or new company
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commonEmailDomains are email providers rather than companies.
+var commonEmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"yahoo.com":      true,
+	"hotmail.com":    true,
+	"outlook.com":    true,
+	"live.com":       true,
+	"msn.com":        true,
+	"icloud.com":     true,
+	"me.com":         true,
+	"mac.com":        true,
+	"aol.com":        true,
+	"protonmail.com": true,
+	"pm.me":          true,
+}
+
+// IsCommonEmailDomain reports whether domain is an email provider (gmail.com,
+// icloud.com, ...) rather than a company's own domain.
+func IsCommonEmailDomain(domain string) bool {
+	return commonEmailDomains[strings.ToLower(domain)]
+}
+
+// EmailDomain returns the lowercased domain of an email address, or "".
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// CompanyNameFromDomain guesses a company name from its domain:
+// "acme-labs.io" becomes "Acme Labs".
+func CompanyNameFromDomain(domain string) string {
+	name := strings.TrimSuffix(domain, ".com")
+	name = strings.TrimSuffix(name, ".org")
+	name = strings.TrimSuffix(name, ".net")
+	name = strings.TrimSuffix(name, ".io")
+
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CompanyInference is one email domain shared by contacts without a company,
+// and the company they would be linked to.
+type CompanyInference struct {
+	Domain      string
+	CompanyName string
+	Company     *Company // existing company; nil when one would be created
+	Contacts    []*Contact
+}
+
+// InferCompanies groups contacts that have no company by their work email
+// domain and pairs each domain with the company it belongs to: an existing
+// company with that domain or the inferred name, or a new one. Email provider
+// domains are skipped. Results are sorted by contact count, largest first.
+func (c *Client) InferCompanies() ([]*CompanyInference, error) {
+	contacts, err := c.ListContacts(&ContactFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	companies, err := c.ListCompanies(&CompanyFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+
+	byDomain := make(map[string]*CompanyInference)
+	for _, contact := range contacts {
+		if contact.CompanyID != nil {
+			continue
+		}
+		domain := EmailDomain(contact.Email)
+		if domain == "" || IsCommonEmailDomain(domain) {
+			continue
+		}
+		inference, ok := byDomain[domain]
+		if !ok {
+			inference = &CompanyInference{Domain: domain, CompanyName: CompanyNameFromDomain(domain)}
+			inference.Company = MatchDomainCompany(companies, domain, inference.CompanyName)
+			if inference.Company != nil {
+				inference.CompanyName = inference.Company.Name
+			}
+			byDomain[domain] = inference
+		}
+		inference.Contacts = append(inference.Contacts, contact)
+	}
+
+	inferences := make([]*CompanyInference, 0, len(byDomain))
+	for _, inference := range byDomain {
+		inferences = append(inferences, inference)
+	}
+	sort.Slice(inferences, func(i, j int) bool {
+		if len(inferences[i].Contacts) != len(inferences[j].Contacts) {
+			return len(inferences[i].Contacts) > len(inferences[j].Contacts)
+		}
+		return inferences[i].Domain < inferences[j].Domain
+	})
+	return inferences, nil
+}
+
+// MatchDomainCompany finds the company whose domain is domain (or a parent of
+// it), falling back to one named name.
+func MatchDomainCompany(companies []*Company, domain, name string) *Company {
+	for _, company := range companies {
+		companyDomain := strings.ToLower(strings.TrimSpace(company.Domain))
+		if companyDomain != "" && (domain == companyDomain || strings.HasSuffix(domain, "."+companyDomain)) {
+			return company
+		}
+	}
+	for _, company := range companies {
+		if strings.EqualFold(company.Name, name) {
+			return company
+		}
+	}
+	return nil
+}
+
+// ApplyCompanyInference links the inference's contacts to its company,
+// creating the company (named CompanyName, with the domain) when it doesn't
+// exist yet. It returns the company the contacts were linked to and whether
+// it was created.
+func (c *Client) ApplyCompanyInference(inference *CompanyInference) (company *Company, created bool, err error) {
+	company = inference.Company
+	if company == nil {
+		company, err = c.FindCompanyByName(inference.CompanyName)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to lookup company: %w", err)
+		}
+	}
+	if company == nil {
+		company = &Company{Name: inference.CompanyName, Domain: inference.Domain}
+		if err := c.CreateCompany(company); err != nil {
+			return nil, false, fmt.Errorf("failed to create company: %w", err)
+		}
+		created = true
+	}
+
+	for _, contact := range inference.Contacts {
+		contact.CompanyID = &company.ID
+		contact.CompanyName = company.Name
+		if err := c.UpdateContact(contact); err != nil {
+			return company, created, fmt.Errorf("failed to update contact %s: %w", contact.Name, err)
+		}
+	}
+	inference.Company = company
+	return company, created, nil
+}
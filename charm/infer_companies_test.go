@@ -0,0 +1,88 @@
+// ABOUTME: Tests for company inference from email domains
+// ABOUTME: Verifies domain grouping, matching existing companies, and linking contacts
+
+package charm
+
+import (
+	"testing"
+)
+
+func TestCompanyNameFromDomain(t *testing.T) {
+	tests := map[string]string{
+		"acme.com":      "Acme",
+		"acme-labs.io":  "Acme Labs",
+		"eng.globex.co": "Eng Globex Co",
+	}
+	for domain, want := range tests {
+		if got := CompanyNameFromDomain(domain); got != want {
+			t.Errorf("CompanyNameFromDomain(%q) = %q, want %q", domain, got, want)
+		}
+	}
+	if !IsCommonEmailDomain("Gmail.com") || IsCommonEmailDomain("acme.com") {
+		t.Error("unexpected IsCommonEmailDomain result")
+	}
+}
+
+func TestInferCompanies(t *testing.T) {
+	client := NewTestClient(t)
+
+	globex := &Company{Name: "Globex Corporation", Domain: "globex.com"}
+	if err := client.CreateCompany(globex); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	linked := &Contact{Name: "Linked", Email: "linked@acme.com", CompanyID: &globex.ID}
+	contacts := []*Contact{
+		{Name: "Alice", Email: "alice@acme.com"},
+		{Name: "Bob", Email: "BOB@Acme.com"},
+		{Name: "Hank", Email: "hank@eng.globex.com"},
+		{Name: "Gail", Email: "gail@gmail.com"},
+		{Name: "Nomail"},
+		linked,
+	}
+	for _, contact := range contacts {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	inferences, err := client.InferCompanies()
+	if err != nil {
+		t.Fatalf("InferCompanies failed: %v", err)
+	}
+	if len(inferences) != 2 {
+		t.Fatalf("expected 2 domains, got %d", len(inferences))
+	}
+	acme, eng := inferences[0], inferences[1]
+	if acme.Domain != "acme.com" || acme.Company != nil || acme.CompanyName != "Acme" || len(acme.Contacts) != 2 {
+		t.Errorf("unexpected acme.com inference: %+v", acme)
+	}
+	if eng.Domain != "eng.globex.com" || eng.Company == nil || eng.Company.ID != globex.ID {
+		t.Errorf("expected eng.globex.com to match Globex, got %+v", eng)
+	}
+
+	company, created, err := client.ApplyCompanyInference(acme)
+	if err != nil {
+		t.Fatalf("ApplyCompanyInference failed: %v", err)
+	}
+	if !created || company.Name != "Acme" || company.Domain != "acme.com" {
+		t.Errorf("expected a new Acme company, got %+v (created %v)", company, created)
+	}
+	alice, err := client.GetContact(contacts[0].ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if alice.CompanyID == nil || *alice.CompanyID != company.ID || alice.CompanyName != "Acme" {
+		t.Errorf("expected Alice linked to Acme, got %+v", alice)
+	}
+
+	if _, created, err := client.ApplyCompanyInference(eng); err != nil || created {
+		t.Fatalf("expected eng.globex.com to link to the existing company: created %v, %v", created, err)
+	}
+	inferences, err = client.InferCompanies()
+	if err != nil {
+		t.Fatalf("InferCompanies failed: %v", err)
+	}
+	if len(inferences) != 0 {
+		t.Errorf("expected nothing left to infer, got %d domain(s)", len(inferences))
+	}
+}
@@ -0,0 +1,139 @@
+// ABOUTME: Influencer maps of the people involved in a deal
+// ABOUTME: Collects their deal roles, how well I know each, and the relationships between them
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Influence roles for people on a deal besides the DealRoles.
+const (
+	InfluenceRolePrimary  = "primary" // the deal's contact
+	InfluenceRoleReferrer = "referrer"
+)
+
+// InfluencePerson is someone on a deal's influencer map.
+type InfluencePerson struct {
+	Contact      *Contact
+	Roles        []string   // deal roles, plus primary and referrer; empty when not on the deal yet
+	Strength     string     // my relationship strength from their cadence, or "" when untracked
+	Interactions int        // interactions I've logged with them
+	LastContact  *time.Time // most recent of those
+}
+
+// Involved reports whether the person has any role on the deal.
+func (p *InfluencePerson) Involved() bool {
+	return len(p.Roles) > 0
+}
+
+// Known reports whether I have any relationship with the person.
+func (p *InfluencePerson) Known() bool {
+	return p.Strength != "" || p.Interactions > 0
+}
+
+// InfluenceMap is a deal, the people involved in it, and how they're connected.
+type InfluenceMap struct {
+	Deal   *Deal
+	People []*InfluencePerson // involved people by role, then others at the company, each by name
+	Links  []*Relationship    // relationships between people on the map
+}
+
+// DealInfluenceMap builds the influencer map for a deal: its contacts with
+// their roles, its primary contact and referrer, and with withCompany the
+// other people at its company who aren't on the deal yet.
+func (c *Client) DealInfluenceMap(dealID uuid.UUID, withCompany bool) (*InfluenceMap, error) {
+	deal, err := c.GetDeal(dealID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	people := make(map[uuid.UUID]*InfluencePerson)
+	add := func(contactID uuid.UUID, role string) {
+		person, ok := people[contactID]
+		if !ok {
+			contact, err := c.GetContact(contactID)
+			if err != nil {
+				return // linked contact was deleted
+			}
+			person = &InfluencePerson{Contact: contact}
+			people[contactID] = person
+		}
+		person.Roles = append(person.Roles, role)
+	}
+
+	roles, err := c.ListDealContacts(dealID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal contacts: %w", err)
+	}
+	for _, dc := range roles {
+		add(dc.ContactID, dc.Role)
+	}
+	if deal.ContactID != nil {
+		add(*deal.ContactID, InfluenceRolePrimary)
+	}
+	if deal.ReferrerID != nil {
+		add(*deal.ReferrerID, InfluenceRoleReferrer)
+	}
+	if withCompany && deal.CompanyID != uuid.Nil {
+		contacts, err := c.ListContacts(&ContactFilter{CompanyID: &deal.CompanyID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list company contacts: %w", err)
+		}
+		for _, contact := range contacts {
+			if _, ok := people[contact.ID]; !ok {
+				people[contact.ID] = &InfluencePerson{Contact: contact}
+			}
+		}
+	}
+
+	m := &InfluenceMap{Deal: deal}
+	for id, person := range people {
+		cadence, err := c.GetContactCadence(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cadence: %w", err)
+		}
+		if cadence != nil {
+			person.Strength = cadence.RelationshipStrength
+		}
+		logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &id})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list interactions: %w", err)
+		}
+		person.Interactions = len(logs)
+		for _, log := range logs {
+			if person.LastContact == nil || log.Timestamp.After(*person.LastContact) {
+				ts := log.Timestamp
+				person.LastContact = &ts
+			}
+		}
+		m.People = append(m.People, person)
+	}
+	sort.Slice(m.People, func(i, j int) bool {
+		a, b := m.People[i], m.People[j]
+		if a.Involved() != b.Involved() {
+			return a.Involved()
+		}
+		return a.Contact.Name < b.Contact.Name
+	})
+
+	seen := make(map[uuid.UUID]bool)
+	for _, person := range m.People {
+		rels, err := c.ListRelationshipsForContact(person.Contact.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list relationships: %w", err)
+		}
+		for _, rel := range rels {
+			if seen[rel.ID] || people[rel.ContactID1] == nil || people[rel.ContactID2] == nil {
+				continue
+			}
+			seen[rel.ID] = true
+			m.Links = append(m.Links, rel)
+		}
+	}
+	return m, nil
+}
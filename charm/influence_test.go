@@ -0,0 +1,94 @@
+// ABOUTME: Tests for deal influencer maps
+// ABOUTME: Verifies who is on the map, their roles, how well I know them, and the links between them
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDealInfluenceMap(t *testing.T) {
+	client := NewTestClient(t)
+
+	acme := &Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	ceo := &Contact{Name: "Carol", Title: "CEO", CompanyID: &acme.ID}
+	eng := &Contact{Name: "Dan", CompanyID: &acme.ID}
+	intern := &Contact{Name: "Erin", CompanyID: &acme.ID}
+	friend := &Contact{Name: "Frank"}
+	for _, contact := range []*Contact{ceo, eng, intern, friend} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	deal := &Deal{Title: "Pilot", CompanyID: acme.ID, Stage: StageProspecting, Currency: "USD", ContactID: &eng.ID, ReferrerID: &friend.ID}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	for _, dc := range []*DealContact{
+		{DealID: deal.ID, ContactID: ceo.ID, Role: DealRoleDecisionMaker},
+		{DealID: deal.ID, ContactID: eng.ID, Role: DealRoleChampion},
+	} {
+		if err := client.SaveDealContact(dc); err != nil {
+			t.Fatalf("SaveDealContact failed: %v", err)
+		}
+	}
+	if err := client.CreateRelationship(&Relationship{ContactID1: ceo.ID, ContactID2: eng.ID, RelationshipType: "manager"}); err != nil {
+		t.Fatalf("CreateRelationship failed: %v", err)
+	}
+	if err := client.CreateRelationship(&Relationship{ContactID1: ceo.ID, ContactID2: friend.ID, RelationshipType: "friend"}); err != nil {
+		t.Fatalf("CreateRelationship failed: %v", err)
+	}
+	met := time.Now().AddDate(0, 0, -3)
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: eng.ID, InteractionType: InteractionMeeting, Timestamp: met}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+	if err := client.SaveContactCadence(&ContactCadence{ContactID: friend.ID, CadenceDays: 30, RelationshipStrength: StrengthStrong}); err != nil {
+		t.Fatalf("SaveContactCadence failed: %v", err)
+	}
+
+	m, err := client.DealInfluenceMap(deal.ID, true)
+	if err != nil {
+		t.Fatalf("DealInfluenceMap failed: %v", err)
+	}
+
+	byName := make(map[string]*InfluencePerson)
+	var order []string
+	for _, person := range m.People {
+		byName[person.Contact.Name] = person
+		order = append(order, person.Contact.Name)
+	}
+	if len(order) != 4 || order[3] != "Erin" {
+		t.Fatalf("expected involved people first and Erin last, got %v", order)
+	}
+	if roles := byName["Dan"].Roles; len(roles) != 2 || roles[0] != DealRoleChampion || roles[1] != InfluenceRolePrimary {
+		t.Errorf("expected Dan to be champion and primary, got %v", roles)
+	}
+	if byName["Erin"].Involved() {
+		t.Error("Erin has no role on the deal")
+	}
+	if !byName["Frank"].Known() || byName["Frank"].Strength != StrengthStrong || byName["Frank"].Roles[0] != InfluenceRoleReferrer {
+		t.Errorf("unexpected referrer: %+v", byName["Frank"])
+	}
+	if dan := byName["Dan"]; dan.Interactions != 1 || dan.LastContact == nil || !dan.LastContact.Equal(met) {
+		t.Errorf("expected one interaction with Dan, got %+v", dan)
+	}
+	if byName["Carol"].Known() {
+		t.Error("Carol is cold")
+	}
+	if len(m.Links) != 2 {
+		t.Errorf("expected both relationships on the map, got %d", len(m.Links))
+	}
+
+	m, err = client.DealInfluenceMap(deal.ID, false)
+	if err != nil {
+		t.Fatalf("DealInfluenceMap failed: %v", err)
+	}
+	if len(m.People) != 3 {
+		t.Errorf("expected only involved people without the company, got %d", len(m.People))
+	}
+}
@@ -3,6 +3,11 @@
 
 package charm
 
+import (
+	"fmt"
+	"time"
+)
+
 // Key prefixes for entity types
 // Format: "prefix:uuid" enables efficient prefix scanning.
 const (
@@ -16,8 +21,64 @@ const (
 	PrefixSuggestion     = "suggestion:"
 	PrefixSyncState      = "syncstate:"
 	PrefixSyncLog        = "synclog:"
+	PrefixRevision       = "revision:"
+	PrefixDealContact    = "dealcontact:"
+	PrefixTrackedEmail   = "trackedemail:"
+	PrefixEvent          = "event:"
+	PrefixEventAttendee  = "eventattendee:"
+	PrefixIdempotency    = "idempotency:"
+	PrefixWatch          = "watch:"
+	PrefixCompanyNews    = "companynews:"
+	PrefixGeocode        = "geocode:"
+	PrefixSnapshot       = "pipelinesnapshot:"
+	PrefixOutreach       = "outreach:"
+	PrefixTask           = "task:"
+	PrefixShare          = "share:"
+	PrefixEmailThread    = "emailthread:"
+	PrefixAPIToken       = "apitoken:"
+	PrefixAudit          = "audit:"
+	PrefixStageTimer     = "stagetimer:"
+	PrefixHandoff        = "handoff:"
+	PrefixLogo           = "logo:"
 )
 
+// SchemaVersion is the version of the stored key and JSON layout.
+// Bump it when stored entities change in a way that needs migration.
+const SchemaVersion = 1
+
+// EntityPrefixes maps entity type names to their key prefixes.
+var EntityPrefixes = map[string]string{
+	"contacts":         PrefixContact,
+	"companies":        PrefixCompany,
+	"deals":            PrefixDeal,
+	"deal_notes":       PrefixDealNote,
+	"relationships":    PrefixRelationship,
+	"interactions":     PrefixInteractionLog,
+	"cadences":         PrefixContactCadence,
+	"suggestions":      PrefixSuggestion,
+	"sync_states":      PrefixSyncState,
+	"sync_logs":        PrefixSyncLog,
+	"revisions":        PrefixRevision,
+	"deal_contacts":    PrefixDealContact,
+	"tracked_emails":   PrefixTrackedEmail,
+	"events":           PrefixEvent,
+	"event_attendees":  PrefixEventAttendee,
+	"idempotency_keys": PrefixIdempotency,
+	"watches":          PrefixWatch,
+	"company_news":     PrefixCompanyNews,
+	"geocodes":         PrefixGeocode,
+	"snapshots":        PrefixSnapshot,
+	"outreach":         PrefixOutreach,
+	"tasks":            PrefixTask,
+	"shares":           PrefixShare,
+	"email_threads":    PrefixEmailThread,
+	"api_tokens":       PrefixAPIToken,
+	"audit_log":        PrefixAudit,
+	"stage_timers":     PrefixStageTimer,
+	"handoffs":         PrefixHandoff,
+	"logos":            PrefixLogo,
+}
+
 // Key helper functions
 
 // ContactKey returns the KV key for a contact.
@@ -40,6 +101,12 @@ func DealNoteKey(id string) []byte {
 	return []byte(PrefixDealNote + id)
 }
 
+// DealContactKey returns the KV key for a contact's role on a deal
+// Note: keyed by deal and contact so a contact has one role per deal.
+func DealContactKey(dealID, contactID string) []byte {
+	return []byte(PrefixDealContact + dealID + ":" + contactID)
+}
+
 // RelationshipKey returns the KV key for a relationship.
 func RelationshipKey(id string) []byte {
 	return []byte(PrefixRelationship + id)
@@ -70,3 +137,96 @@ func SyncStateKey(service string) []byte {
 func SyncLogKey(id string) []byte {
 	return []byte(PrefixSyncLog + id)
 }
+
+// RevisionKey returns the KV key for an object revision
+// Note: zero-padded so keys sort by revision number.
+func RevisionKey(objectID string, rev int) []byte {
+	return []byte(fmt.Sprintf("%s%s:%08d", PrefixRevision, objectID, rev))
+}
+
+// TrackedEmailKey returns the KV key for a tracked email.
+func TrackedEmailKey(id string) []byte {
+	return []byte(PrefixTrackedEmail + id)
+}
+
+// EventKey returns the KV key for an event.
+func EventKey(id string) []byte {
+	return []byte(PrefixEvent + id)
+}
+
+// EventAttendeeKey returns the KV key for a contact's attendance at an event
+// Note: keyed by event and contact so a contact attends an event once.
+func EventAttendeeKey(eventID, contactID string) []byte {
+	return []byte(PrefixEventAttendee + eventID + ":" + contactID)
+}
+
+// IdempotencyKey returns the KV key recording what a tool call with an idempotency key created.
+func IdempotencyKey(tool, key string) []byte {
+	return []byte(PrefixIdempotency + tool + ":" + key)
+}
+
+// WatchKey returns the KV key for a watch on an object.
+func WatchKey(objectID string) []byte {
+	return []byte(PrefixWatch + objectID)
+}
+
+// CompanyNewsKey returns the KV key for a company's recent news.
+func CompanyNewsKey(companyID string) []byte {
+	return []byte(PrefixCompanyNews + companyID)
+}
+
+// GeocodeKey returns the KV key for a cached geocode of a normalized location.
+func GeocodeKey(location string) []byte {
+	return []byte(PrefixGeocode + location)
+}
+
+// PipelineSnapshotKey returns the KV key for the pipeline snapshot of the week starting on date (YYYY-MM-DD).
+func PipelineSnapshotKey(week string) []byte {
+	return []byte(PrefixSnapshot + week)
+}
+
+// OutreachKey returns the KV key for an outreach email.
+func OutreachKey(id string) []byte {
+	return []byte(PrefixOutreach + id)
+}
+
+// TaskKey returns the KV key for a task.
+func TaskKey(id string) []byte {
+	return []byte(PrefixTask + id)
+}
+
+// ShareKey returns the KV key for a share link.
+func ShareKey(id string) []byte {
+	return []byte(PrefixShare + id)
+}
+
+// APITokenKey returns the KV key for a REST API token.
+func APITokenKey(id string) []byte {
+	return []byte(PrefixAPIToken + id)
+}
+
+// AuditKey returns the KV key for an audit log entry
+// Note: keyed by object, then zero-padded time, so an object's entries sort oldest first.
+func AuditKey(objectID string, at time.Time, entryID string) []byte {
+	return []byte(fmt.Sprintf("%s%s:%020d:%s", PrefixAudit, objectID, at.UnixNano(), entryID))
+}
+
+// StageTimerKey returns the KV key for the stage timer that last fired on a deal.
+func StageTimerKey(dealID string) []byte {
+	return []byte(PrefixStageTimer + dealID)
+}
+
+// HandoffKey returns the KV key for a payload waiting for another device.
+func HandoffKey(id string) []byte {
+	return []byte(PrefixHandoff + id)
+}
+
+// LogoKey returns the KV key for a cached company logo, by bare domain.
+func LogoKey(domain string) []byte {
+	return []byte(PrefixLogo + domain)
+}
+
+// EmailThreadKey returns the KV key for a Gmail thread, by Gmail thread ID.
+func EmailThreadKey(threadID string) []byte {
+	return []byte(PrefixEmailThread + threadID)
+}
@@ -0,0 +1,149 @@
+// ABOUTME: Company logo cache and initials placeholders for the web UI
+// ABOUTME: Logos are cached in Charm KV by domain; misses are remembered for a while so dead sites aren't refetched
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+// MaxLogoSize caps a cached logo; anything bigger isn't a logo.
+const MaxLogoSize = 256 << 10
+
+// LogoRetryAfter is how long a domain with no logo is left alone before it is
+// tried again.
+const LogoRetryAfter = 7 * 24 * time.Hour
+
+// CompanyLogo is a cached logo image for a domain. A logo with no data
+// records that none was found.
+type CompanyLogo struct {
+	Domain      string    `json:"domain"`
+	ContentType string    `json:"content_type,omitempty"`
+	Data        []byte    `json:"data,omitempty"`
+	Source      string    `json:"source,omitempty"` // URL it was fetched from
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// Found reports whether the logo has an image.
+func (l *CompanyLogo) Found() bool {
+	return len(l.Data) > 0
+}
+
+// Stale reports whether the logo should be fetched again: misses are retried
+// after LogoRetryAfter, and found logos are kept until cleared.
+func (l *CompanyLogo) Stale(now time.Time) bool {
+	return !l.Found() && now.Sub(l.FetchedAt) >= LogoRetryAfter
+}
+
+// LogoDomain returns the bare domain a company's logo is cached under, or ""
+// when it has none.
+func LogoDomain(company *Company) string {
+	if company == nil {
+		return ""
+	}
+	return DomainFromWebsite(company.Domain)
+}
+
+// GetCompanyLogo returns the cached logo for a domain, or nil when it hasn't
+// been fetched.
+func (c *Client) GetCompanyLogo(domain string) (*CompanyLogo, error) {
+	data, err := c.Get(LogoKey(domain))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var logo CompanyLogo
+	if err := json.Unmarshal(data, &logo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal logo: %w", err)
+	}
+	return &logo, nil
+}
+
+// SaveCompanyLogo caches a logo, or a miss when it has no data.
+func (c *Client) SaveCompanyLogo(logo *CompanyLogo) error {
+	if logo.Domain == "" {
+		return Validationf("logo domain is required")
+	}
+	if len(logo.Data) > MaxLogoSize {
+		return fmt.Errorf("logo for %s is %d bytes; the limit is %d", logo.Domain, len(logo.Data), MaxLogoSize)
+	}
+	data, err := json.Marshal(logo)
+	if err != nil {
+		return fmt.Errorf("failed to marshal logo: %w", err)
+	}
+	return c.Set(LogoKey(logo.Domain), data)
+}
+
+// ClearCompanyLogos removes cached logos, misses included, so they are
+// fetched again. With no domains it clears them all. Returns how many were removed.
+func (c *Client) ClearCompanyLogos(domains ...string) (int, error) {
+	var keys [][]byte
+	if len(domains) == 0 {
+		var err error
+		keys, err = c.KeysWithPrefix([]byte(PrefixLogo))
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, domain := range domains {
+		if logo, err := c.GetCompanyLogo(domain); err != nil {
+			return 0, err
+		} else if logo != nil {
+			keys = append(keys, LogoKey(domain))
+		}
+	}
+
+	for i, key := range keys {
+		if err := c.Delete(key); err != nil {
+			return i, fmt.Errorf("failed to delete logo: %w", err)
+		}
+	}
+	return len(keys), nil
+}
+
+// placeholderColors are the backgrounds of initials placeholders, picked by name.
+var placeholderColors = []string{"#7c3aed", "#2563eb", "#0891b2", "#059669", "#d97706", "#dc2626", "#db2777", "#4b5563"}
+
+// LogoPlaceholderSVG draws a company's initials on a colored square, shown
+// when there is no logo or it can't be fetched. The color is stable per name.
+func LogoPlaceholderSVG(name string) []byte {
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials = append(initials, unicode.ToUpper(r))
+				break
+			}
+		}
+		if len(initials) == 2 {
+			break
+		}
+	}
+	if len(initials) == 0 {
+		initials = []rune{'?'}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(name)))
+	color := placeholderColors[h.Sum32()%uint32(len(placeholderColors))]
+
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64" viewBox="0 0 64 64">`+
+		`<rect width="64" height="64" rx="12" fill="%s"/>`+
+		`<text x="32" y="32" dy=".35em" text-anchor="middle" font-family="system-ui,sans-serif" font-size="26" font-weight="600" fill="#fff">%s</text>`+
+		`</svg>`, color, html.EscapeString(string(initials))))
+}
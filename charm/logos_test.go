@@ -0,0 +1,75 @@
+// ABOUTME: Tests for the company logo cache
+// ABOUTME: Covers cached misses going stale, clearing by domain or all, and initials placeholders
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompanyLogoCache(t *testing.T) {
+	client := NewTestClient(t)
+
+	if logo, err := client.GetCompanyLogo("acme.com"); err != nil || logo != nil {
+		t.Fatalf("expected no cached logo, got %v, %v", logo, err)
+	}
+
+	now := time.Now()
+	for _, logo := range []*CompanyLogo{
+		{Domain: "acme.com", ContentType: "image/png", Data: []byte("png"), FetchedAt: now},
+		{Domain: "globex.com", FetchedAt: now.Add(-LogoRetryAfter)},
+		{Domain: "initech.com", FetchedAt: now},
+	} {
+		if err := client.SaveCompanyLogo(logo); err != nil {
+			t.Fatalf("SaveCompanyLogo failed: %v", err)
+		}
+	}
+	if err := client.SaveCompanyLogo(&CompanyLogo{Domain: "big.com", Data: make([]byte, MaxLogoSize+1)}); err == nil {
+		t.Error("expected an oversized logo to be rejected")
+	}
+
+	acme, _ := client.GetCompanyLogo("acme.com")
+	globex, _ := client.GetCompanyLogo("globex.com")
+	initech, _ := client.GetCompanyLogo("initech.com")
+	if !acme.Found() || acme.Stale(now) {
+		t.Error("expected a found logo to stay fresh")
+	}
+	if globex.Found() || !globex.Stale(now) {
+		t.Error("expected an old miss to be stale")
+	}
+	if initech.Stale(now) {
+		t.Error("expected a recent miss to be left alone")
+	}
+
+	if n, err := client.ClearCompanyLogos("acme.com", "unknown.com"); err != nil || n != 1 {
+		t.Errorf("expected 1 logo cleared, got %d, %v", n, err)
+	}
+	if logo, _ := client.GetCompanyLogo("acme.com"); logo != nil {
+		t.Error("expected acme.com to be cleared")
+	}
+	if n, err := client.ClearCompanyLogos(); err != nil || n != 2 {
+		t.Errorf("expected the other 2 cleared, got %d, %v", n, err)
+	}
+}
+
+func TestLogoPlaceholderSVG(t *testing.T) {
+	for name, want := range map[string]string{
+		"Acme Corp":     ">AC</text>",
+		"globex":        ">G</text>",
+		"<script> & co": ">SC</text>",
+		"":              ">?</text>",
+	} {
+		svg := string(LogoPlaceholderSVG(name))
+		if !strings.Contains(svg, want) {
+			t.Errorf("%q: expected %s in %s", name, want, svg)
+		}
+	}
+	if string(LogoPlaceholderSVG("Acme")) != string(LogoPlaceholderSVG("ACME")) {
+		t.Error("expected the color to ignore case")
+	}
+	if LogoDomain(&Company{Domain: "https://www.Acme.com/about"}) != "acme.com" {
+		t.Error("expected the bare domain")
+	}
+}
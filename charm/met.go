@@ -0,0 +1,131 @@
+// ABOUTME: "How we met" fields on contacts
+// ABOUTME: Sets, summarizes, and backfills met via / met at / met date from the first imported interaction
+
+package charm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetMetFields applies "how we met" values from user input. Empty values leave
+// the existing field unchanged; the date is YYYY-MM-DD.
+func (c *Contact) SetMetFields(via, at, date string) error {
+	if date = strings.TrimSpace(date); date != "" {
+		metDate, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return Validationf("invalid met date (use YYYY-MM-DD): %w", err)
+		}
+		c.MetDate = &metDate
+	}
+	if via = strings.TrimSpace(via); via != "" {
+		c.MetVia = via
+	}
+	if at = strings.TrimSpace(at); at != "" {
+		c.MetAt = at
+	}
+	return nil
+}
+
+// FillMet records how we met from an interaction, unless a met date is
+// already set. Returns whether the contact changed.
+func (c *Contact) FillMet(timestamp time.Time, via, at string) bool {
+	if c.MetDate != nil {
+		return false
+	}
+	c.MetDate = &timestamp
+	if c.MetVia == "" {
+		c.MetVia = via
+	}
+	if c.MetAt == "" {
+		c.MetAt = at
+	}
+	return true
+}
+
+// HowWeMet summarizes the met fields, e.g. "Met via Zoom at Q3 planning on
+// 2024-03-02", or returns "" when none are set.
+func (c *Contact) HowWeMet() string {
+	if c.MetVia == "" && c.MetAt == "" && c.MetDate == nil {
+		return ""
+	}
+
+	summary := "Met"
+	if c.MetVia != "" {
+		summary += " via " + c.MetVia
+	}
+	if c.MetAt != "" {
+		summary += " at " + c.MetAt
+	}
+	if c.MetDate != nil {
+		summary += " on " + c.MetDate.Format("2006-01-02")
+	}
+	return summary
+}
+
+// ParseMetPeriod parses a year (2024), month (2024-03), or day (2024-03-02)
+// into the half-open range [from, to) for filtering contacts by met date.
+func ParseMetPeriod(period string) (time.Time, time.Time, error) {
+	period = strings.TrimSpace(period)
+	for _, p := range []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006", 1, 0, 0},
+		{"2006-01", 0, 1, 0},
+		{"2006-01-02", 0, 0, 1},
+	} {
+		if from, err := time.Parse(p.layout, period); err == nil {
+			return from, from.AddDate(p.years, p.months, p.days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, Validationf("invalid period %q (use YYYY, YYYY-MM, or YYYY-MM-DD)", period)
+}
+
+// FillMetFromImports sets how we met on contacts without a met date from their
+// earliest imported interaction. Returns how many contacts were filled in.
+func (c *Client) FillMetFromImports() (int, error) {
+	logs, err := c.ListRecentSyncLogs(0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sync logs: %w", err)
+	}
+
+	type firstMeeting struct {
+		interaction *InteractionLog
+		source      string
+	}
+	first := make(map[uuid.UUID]firstMeeting)
+	for _, log := range logs {
+		if log.EntityType != "interaction" {
+			continue
+		}
+		interaction, err := c.GetInteractionLog(log.EntityID)
+		if err != nil {
+			continue // the interaction was deleted
+		}
+		if seen, ok := first[interaction.ContactID]; !ok || interaction.Timestamp.Before(seen.interaction.Timestamp) {
+			first[interaction.ContactID] = firstMeeting{interaction: interaction, source: log.SourceService}
+		}
+	}
+
+	filled := 0
+	for contactID, meeting := range first {
+		contact, err := c.GetContact(contactID)
+		if err != nil {
+			continue // the contact was deleted
+		}
+		if !contact.FillMet(meeting.interaction.Timestamp, meeting.source, meeting.interaction.Notes) {
+			continue
+		}
+		if err := c.UpdateContact(contact); err != nil {
+			return filled, fmt.Errorf("failed to update contact: %w", err)
+		}
+		filled++
+	}
+	return filled, nil
+}
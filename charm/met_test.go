@@ -0,0 +1,173 @@
+// ABOUTME: Tests for the "how we met" contact fields
+// ABOUTME: Verifies setting, summarizing, period filtering, and backfilling from imported interactions
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetMetFields(t *testing.T) {
+	contact := &Contact{Name: "Alice"}
+	if err := contact.SetMetFields("intro from Bob", "PyCon", "2024-05-17"); err != nil {
+		t.Fatalf("SetMetFields failed: %v", err)
+	}
+	if got := contact.HowWeMet(); got != "Met via intro from Bob at PyCon on 2024-05-17" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+
+	// Empty values leave fields alone
+	if err := contact.SetMetFields("", "", ""); err != nil {
+		t.Fatalf("SetMetFields failed: %v", err)
+	}
+	if contact.MetVia != "intro from Bob" || contact.MetAt != "PyCon" || contact.MetDate == nil {
+		t.Errorf("expected fields unchanged, got %q", contact.HowWeMet())
+	}
+
+	if err := contact.SetMetFields("", "", "May 2024"); err == nil {
+		t.Error("expected error for invalid date")
+	}
+
+	if got := (&Contact{}).HowWeMet(); got != "" {
+		t.Errorf("expected empty summary, got %q", got)
+	}
+}
+
+func TestFillMet(t *testing.T) {
+	first := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+	contact := &Contact{Name: "Alice", MetVia: "conference"}
+
+	if !contact.FillMet(first, "Zoom", "Q3 planning") {
+		t.Fatal("expected FillMet to fill an empty met date")
+	}
+	if contact.MetVia != "conference" || contact.MetAt != "Q3 planning" || !contact.MetDate.Equal(first) {
+		t.Errorf("unexpected met fields: %q", contact.HowWeMet())
+	}
+
+	if contact.FillMet(first.AddDate(0, 1, 0), "Google Meet", "Standup") {
+		t.Error("expected FillMet to keep an existing met date")
+	}
+}
+
+func TestParseMetPeriod(t *testing.T) {
+	tests := []struct {
+		period string
+		from   string
+		to     string
+	}{
+		{"2024", "2024-01-01", "2025-01-01"},
+		{"2024-03", "2024-03-01", "2024-04-01"},
+		{"2024-03-02", "2024-03-02", "2024-03-03"},
+	}
+	for _, tt := range tests {
+		from, to, err := ParseMetPeriod(tt.period)
+		if err != nil {
+			t.Fatalf("ParseMetPeriod(%q) failed: %v", tt.period, err)
+		}
+		if from.Format("2006-01-02") != tt.from || to.Format("2006-01-02") != tt.to {
+			t.Errorf("ParseMetPeriod(%q) = %s..%s, want %s..%s", tt.period, from.Format("2006-01-02"), to.Format("2006-01-02"), tt.from, tt.to)
+		}
+	}
+
+	if _, _, err := ParseMetPeriod("last year"); err == nil {
+		t.Error("expected error for invalid period")
+	}
+}
+
+func TestListContactsMetFilter(t *testing.T) {
+	client := NewTestClient(t)
+
+	for name, date := range map[string]string{"Early": "2023-12-31", "Spring": "2024-03-02", "Fall": "2024-10-09"} {
+		contact := &Contact{Name: name}
+		if err := contact.SetMetFields("", "", date); err != nil {
+			t.Fatalf("SetMetFields failed: %v", err)
+		}
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+	if err := client.CreateContact(&Contact{Name: "Unknown"}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	from, to, err := ParseMetPeriod("2024")
+	if err != nil {
+		t.Fatalf("ParseMetPeriod failed: %v", err)
+	}
+	contacts, err := client.ListContacts(&ContactFilter{MetFrom: &from, MetTo: &to})
+	if err != nil {
+		t.Fatalf("ListContacts failed: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts met in 2024, got %d", len(contacts))
+	}
+	for _, contact := range contacts {
+		if contact.Name != "Spring" && contact.Name != "Fall" {
+			t.Errorf("unexpected contact met in 2024: %s", contact.Name)
+		}
+	}
+}
+
+func TestFillMetFromImports(t *testing.T) {
+	client := NewTestClient(t)
+
+	alice := &Contact{Name: "Alice"}
+	known := &Contact{Name: "Known"}
+	if err := known.SetMetFields("school", "", "2010-09-01"); err != nil {
+		t.Fatalf("SetMetFields failed: %v", err)
+	}
+	for _, contact := range []*Contact{alice, known} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	first := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+	imports := []*InteractionLog{
+		{ContactID: alice.ID, InteractionType: InteractionMeeting, Timestamp: first.AddDate(0, 2, 0), Notes: "Follow-up"},
+		{ContactID: alice.ID, InteractionType: InteractionMeeting, Timestamp: first, Notes: "Q3 planning"},
+		{ContactID: known.ID, InteractionType: InteractionMeeting, Timestamp: first, Notes: "Reunion"},
+	}
+	for _, interaction := range imports {
+		if err := client.CreateInteractionLog(interaction); err != nil {
+			t.Fatalf("failed to log interaction: %v", err)
+		}
+		if err := client.CreateSyncLog(&SyncLog{SourceService: "zoom", SourceID: interaction.ID.String(), EntityType: "interaction", EntityID: interaction.ID}); err != nil {
+			t.Fatalf("failed to create sync log: %v", err)
+		}
+	}
+
+	filled, err := client.FillMetFromImports()
+	if err != nil {
+		t.Fatalf("FillMetFromImports failed: %v", err)
+	}
+	if filled != 1 {
+		t.Errorf("expected 1 contact filled, got %d", filled)
+	}
+
+	got, err := client.GetContact(alice.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if got.HowWeMet() != "Met via zoom at Q3 planning on 2024-03-02" {
+		t.Errorf("unexpected summary: %q", got.HowWeMet())
+	}
+
+	got, err = client.GetContact(known.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if got.HowWeMet() != "Met via school on 2010-09-01" {
+		t.Errorf("expected existing met fields kept, got %q", got.HowWeMet())
+	}
+
+	// Running again changes nothing
+	filled, err = client.FillMetFromImports()
+	if err != nil {
+		t.Fatalf("FillMetFromImports failed: %v", err)
+	}
+	if filled != 0 {
+		t.Errorf("expected no contacts filled on second run, got %d", filled)
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Enriched metadata on contacts and companies from public sources and enrichment APIs
+// ABOUTME: Each value keeps its provenance and fetch time so it can be told apart from what was typed in
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Well-known metadata keys. Social profiles found on a company site are
+// stored as <network>_url, e.g. linkedin_url, and enrichment APIs may add
+// keys of their own.
+const (
+	MetaAvatarURL       = "avatar_url"
+	MetaSiteTitle       = "site_title"
+	MetaSiteDescription = "site_description"
+	MetaSiteImage       = "site_image"
+)
+
+// MetaValue is one enriched fact and where it came from.
+type MetaValue struct {
+	Value     string    `json:"value"`
+	Source    string    `json:"source"` // provenance, e.g. "gravatar", "website:acme.com", or "api:api.example.com"
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Metadata maps keys like avatar_url to enriched values.
+type Metadata map[string]MetaValue
+
+// Keys returns the metadata keys in sorted order, for display.
+func (m Metadata) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// merge copies values into m and returns the keys that were added or changed.
+// A value that only differs in fetch time counts as unchanged.
+func (m Metadata) merge(values Metadata) []string {
+	var changed []string
+	for _, key := range values.Keys() {
+		value := values[key]
+		if old, ok := m[key]; !ok || old.Value != value.Value || old.Source != value.Source {
+			changed = append(changed, key)
+		}
+		m[key] = value
+	}
+	return changed
+}
+
+// ApplyContactMetadata merges enriched values into a contact's metadata,
+// replacing values with the same key, and returns the changed keys.
+func (c *Client) ApplyContactMetadata(contactID uuid.UUID, values Metadata) ([]string, error) {
+	contact, err := c.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if contact.Metadata == nil {
+		contact.Metadata = Metadata{}
+	}
+	changed := contact.Metadata.merge(values)
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	return changed, nil
+}
+
+// ApplyCompanyMetadata merges enriched values into a company's metadata,
+// replacing values with the same key, and returns the changed keys.
+func (c *Client) ApplyCompanyMetadata(companyID uuid.UUID, values Metadata) ([]string, error) {
+	company, err := c.GetCompany(companyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if company.Metadata == nil {
+		company.Metadata = Metadata{}
+	}
+	changed := company.Metadata.merge(values)
+	if err := c.UpdateCompany(company); err != nil {
+		return nil, fmt.Errorf("failed to update company: %w", err)
+	}
+	return changed, nil
+}
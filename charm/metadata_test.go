@@ -0,0 +1,54 @@
+// ABOUTME: Tests for enriched contact and company metadata
+// ABOUTME: Checks merging keeps provenance and reports only changed keys
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyContactMetadata(t *testing.T) {
+	client := NewTestClient(t)
+	contact := &Contact{Name: "Alice", Tags: []string{"vip"}}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	first := time.Now().Add(-time.Hour)
+	changed, err := client.ApplyContactMetadata(contact.ID, Metadata{
+		MetaAvatarURL: {Value: "https://gravatar.com/avatar/abc", Source: "gravatar", FetchedAt: first},
+		"title":       {Value: "CTO", Source: "api:api.test", FetchedAt: first},
+	})
+	if err != nil {
+		t.Fatalf("ApplyContactMetadata failed: %v", err)
+	}
+	if len(changed) != 2 || changed[0] != MetaAvatarURL {
+		t.Errorf("expected both keys changed in sorted order, got %v", changed)
+	}
+
+	// Refetching the same value only bumps its fetch time
+	changed, err = client.ApplyContactMetadata(contact.ID, Metadata{
+		MetaAvatarURL: {Value: "https://gravatar.com/avatar/abc", Source: "gravatar", FetchedAt: time.Now()},
+		"title":       {Value: "CEO", Source: "api:api.test", FetchedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("ApplyContactMetadata failed: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "title" {
+		t.Errorf("expected only title changed, got %v", changed)
+	}
+
+	saved, err := client.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("GetContact failed: %v", err)
+	}
+	if saved.Metadata["title"].Value != "CEO" || saved.Metadata["title"].Source != "api:api.test" {
+		t.Errorf("expected title CEO from api:api.test, got %+v", saved.Metadata["title"])
+	}
+	if !saved.Metadata[MetaAvatarURL].FetchedAt.After(first) {
+		t.Error("expected the fetch time to be updated")
+	}
+	if len(saved.Tags) != 1 {
+		t.Errorf("expected the rest of the contact untouched, got tags %v", saved.Tags)
+	}
+}
@@ -16,41 +16,74 @@ type Contact struct {
 	Name            string     `json:"name"`
 	Email           string     `json:"email,omitempty"`
 	Phone           string     `json:"phone,omitempty"`
+	Title           string     `json:"title,omitempty"` // job title
 	CompanyID       *uuid.UUID `json:"company_id,omitempty"`
 	CompanyName     string     `json:"company_name,omitempty"` // denormalized
 	Notes           string     `json:"notes,omitempty"`
+	Pinned          []string   `json:"pinned,omitempty"` // key facts shown above notes, oldest first
 	LastContactedAt *time.Time `json:"last_contacted_at,omitempty"`
+	Birthday        string     `json:"birthday,omitempty"`        // YYYY-MM-DD, or MM-DD when the year is unknown
+	WorkStartDate   *time.Time `json:"work_start_date,omitempty"` // started at their current company
+	Country         string     `json:"country,omitempty"`         // ISO 3166 code, selects holidays
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`     // hidden from lists when set
+	MetVia          string     `json:"met_via,omitempty"`         // how we met, e.g. "Zoom" or "intro from Bob"
+	MetAt           string     `json:"met_at,omitempty"`          // event or place we met
+	MetDate         *time.Time `json:"met_date,omitempty"`        // when we met
+	Seniority       string     `json:"seniority,omitempty"`       // see enrichment.go
+	Function        string     `json:"function,omitempty"`        // job function, see enrichment.go
+	EnrichedAt      *time.Time `json:"enriched_at,omitempty"`     // when seniority/function were last classified
+	EnrichedBy      string     `json:"enriched_by,omitempty"`     // classifier source, e.g. "rules"
+	Tags            []string   `json:"tags,omitempty"`            // normalized and sorted; see tags.go
+	Metadata        Metadata   `json:"metadata,omitempty"`        // enriched from public sources; see metadata.go
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int64      `json:"version,omitempty"` // bumped on every write; see edits.go
 }
 
 // Company represents a company stored in KV.
 type Company struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Domain    string    `json:"domain,omitempty"`
-	Industry  string    `json:"industry,omitempty"`
-	Notes     string    `json:"notes,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name"`
+	Domain             string     `json:"domain,omitempty"`
+	Industry           string     `json:"industry,omitempty"`
+	Notes              string     `json:"notes,omitempty"`
+	EmployeeCount      int        `json:"employee_count,omitempty"`     // exact count, or the low end of a range
+	EmployeeCountMax   int        `json:"employee_count_max,omitempty"` // high end when only a range like 11-50 is known
+	FundingStage       string     `json:"funding_stage,omitempty"`      // normalized, see firmographics.go
+	HQLocation         string     `json:"hq_location,omitempty"`
+	Tags               []string   `json:"tags,omitempty"`                 // normalized and sorted; see tags.go
+	PrimaryContactID   *uuid.UUID `json:"primary_contact_id,omitempty"`   // who to reach there by default; see primary_contact.go
+	PrimaryContactName string     `json:"primary_contact_name,omitempty"` // denormalized
+	Metadata           Metadata   `json:"metadata,omitempty"`             // enriched from the website; see metadata.go
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	Version            int64      `json:"version,omitempty"` // bumped on every write; see edits.go
 }
 
 // Deal represents a deal stored in KV
 // CompanyName and ContactName are denormalized for display without lookups.
 type Deal struct {
-	ID                uuid.UUID  `json:"id"`
-	Title             string     `json:"title"`
-	Amount            int64      `json:"amount,omitempty"` // in cents
-	Currency          string     `json:"currency"`
-	Stage             string     `json:"stage"`
-	CompanyID         uuid.UUID  `json:"company_id"`
-	CompanyName       string     `json:"company_name,omitempty"` // denormalized
-	ContactID         *uuid.UUID `json:"contact_id,omitempty"`
-	ContactName       string     `json:"contact_name,omitempty"` // denormalized
-	ExpectedCloseDate *time.Time `json:"expected_close_date,omitempty"`
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
-	LastActivityAt    time.Time  `json:"last_activity_at"`
+	ID                uuid.UUID     `json:"id"`
+	Title             string        `json:"title"`
+	Amount            int64         `json:"amount,omitempty"` // in cents
+	Currency          string        `json:"currency"`
+	Stage             string        `json:"stage"`
+	CompanyID         uuid.UUID     `json:"company_id"`
+	CompanyName       string        `json:"company_name,omitempty"` // denormalized
+	ContactID         *uuid.UUID    `json:"contact_id,omitempty"`
+	ContactName       string        `json:"contact_name,omitempty"` // denormalized
+	ExpectedCloseDate *time.Time    `json:"expected_close_date,omitempty"`
+	CloseReason       string        `json:"close_reason,omitempty"`
+	Source            string        `json:"source,omitempty"`
+	ReferrerID        *uuid.UUID    `json:"referrer_id,omitempty"`
+	ReferrerName      string        `json:"referrer_name,omitempty"` // denormalized
+	Probability       *int          `json:"probability,omitempty"`   // win % override; nil uses the stage default
+	Tags              []string      `json:"tags,omitempty"`          // normalized and sorted; see tags.go
+	StageHistory      []StageChange `json:"stage_history,omitempty"` // stages entered, oldest first; see velocity.go
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	Version           int64         `json:"version,omitempty"` // bumped on every write; see edits.go
+	LastActivityAt    time.Time     `json:"last_activity_at"`
 }
 
 // DealNote represents a note attached to a deal
@@ -64,18 +97,32 @@ type DealNote struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// DealContact links a contact to a deal with a role (champion, decision maker, ...)
+// Deal title and contact name are denormalized for display.
+type DealContact struct {
+	DealID      uuid.UUID `json:"deal_id"`
+	DealTitle   string    `json:"deal_title,omitempty"` // denormalized
+	ContactID   uuid.UUID `json:"contact_id"`
+	ContactName string    `json:"contact_name,omitempty"` // denormalized
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // Relationship represents a bidirectional relationship between contacts
 // Contact names are denormalized for display.
 type Relationship struct {
-	ID               uuid.UUID `json:"id"`
-	ContactID1       uuid.UUID `json:"contact_id_1"`
-	ContactID2       uuid.UUID `json:"contact_id_2"`
-	Contact1Name     string    `json:"contact1_name,omitempty"` // denormalized
-	Contact2Name     string    `json:"contact2_name,omitempty"` // denormalized
-	RelationshipType string    `json:"relationship_type,omitempty"`
-	Context          string    `json:"context,omitempty"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               uuid.UUID  `json:"id"`
+	ContactID1       uuid.UUID  `json:"contact_id_1"`
+	ContactID2       uuid.UUID  `json:"contact_id_2"`
+	Contact1Name     string     `json:"contact1_name,omitempty"` // denormalized
+	Contact2Name     string     `json:"contact2_name,omitempty"` // denormalized
+	RelationshipType string     `json:"relationship_type,omitempty"`
+	Context          string     `json:"context,omitempty"`
+	Strength         string     `json:"strength,omitempty"`          // weak, medium, or strong as of LastValidatedAt
+	LastValidatedAt  *time.Time `json:"last_validated_at,omitempty"` // when the link was last confirmed
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // InteractionLog records an interaction with a contact.
@@ -87,6 +134,10 @@ type InteractionLog struct {
 	Timestamp       time.Time `json:"timestamp"`
 	Notes           string    `json:"notes,omitempty"`
 	Sentiment       *string   `json:"sentiment,omitempty"`
+	Location        string    `json:"location,omitempty"` // where a meeting happened (address or venue)
+	Outcome         string    `json:"outcome,omitempty"`  // how a follow-up went, see outcomes.go
+	NextStep        string    `json:"next_step,omitempty"`
+	Template        string    `json:"template,omitempty"` // outreach email template the follow-up answered
 	Metadata        string    `json:"metadata,omitempty"`
 }
 
@@ -99,6 +150,8 @@ type ContactCadence struct {
 	PriorityScore        float64    `json:"priority_score"`
 	LastInteractionDate  *time.Time `json:"last_interaction_date,omitempty"`
 	NextFollowupDate     *time.Time `json:"next_followup_date,omitempty"`
+	EngagementScore      float64    `json:"engagement_score,omitempty"`      // decays daily, see engagement.go
+	EngagementUpdatedAt  *time.Time `json:"engagement_updated_at,omitempty"` // when EngagementScore was computed
 }
 
 // FollowupContact combines Contact with cadence info for follow-up views.
@@ -118,6 +171,32 @@ type FollowupContact struct {
 	PriorityScore        float64    `json:"priority_score"`
 	DaysSinceContact     int        `json:"days_since_contact"`
 	NextFollowupDate     *time.Time `json:"next_followup_date,omitempty"`
+	EngagementScore      float64    `json:"engagement_score"`
+}
+
+// Event represents a conference, dinner, or meetup where contacts are met.
+type Event struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	EventType string    `json:"event_type"`
+	Date      time.Time `json:"date"`
+	Location  string    `json:"location,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EventAttendee records that a contact attended an event
+// MetHere marks contacts first met at the event, which the sourcing report counts.
+type EventAttendee struct {
+	EventID     uuid.UUID `json:"event_id"`
+	EventName   string    `json:"event_name,omitempty"` // denormalized
+	EventDate   time.Time `json:"event_date"`           // denormalized
+	ContactID   uuid.UUID `json:"contact_id"`
+	ContactName string    `json:"contact_name,omitempty"` // denormalized
+	MetHere     bool      `json:"met_here"`
+	Notes       string    `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // Suggestion represents an AI-generated suggestion.
@@ -165,6 +244,40 @@ const (
 	StageClosedLost    = "closed_lost"
 )
 
+// DealSource constants for where a deal came from.
+const (
+	DealSourceReferral = "referral"
+	DealSourceInbound  = "inbound"
+	DealSourceOutbound = "outbound"
+	DealSourceEvent    = "event"
+	DealSourcePartner  = "partner"
+)
+
+// DealSources lists the valid deal sources.
+var DealSources = []string{DealSourceReferral, DealSourceInbound, DealSourceOutbound, DealSourceEvent, DealSourcePartner}
+
+// DealRole constants for contacts on a deal, in display order.
+const (
+	DealRoleDecisionMaker = "decision_maker"
+	DealRoleChampion      = "champion"
+	DealRoleInfluencer    = "influencer"
+	DealRoleProcurement   = "procurement"
+)
+
+// DealRoles lists the valid deal contact roles in display order.
+var DealRoles = []string{DealRoleDecisionMaker, DealRoleChampion, DealRoleInfluencer, DealRoleProcurement}
+
+// EventType constants.
+const (
+	EventTypeConference = "conference"
+	EventTypeDinner     = "dinner"
+	EventTypeMeetup     = "meetup"
+	EventTypeOther      = "other"
+)
+
+// EventTypes lists the valid event types.
+var EventTypes = []string{EventTypeConference, EventTypeDinner, EventTypeMeetup, EventTypeOther}
+
 // RelationshipStrength constants.
 const (
 	StrengthWeak   = "weak"
@@ -200,6 +313,7 @@ const (
 	SuggestionTypeDeal         = "deal"
 	SuggestionTypeRelationship = "relationship"
 	SuggestionTypeCompany      = "company"
+	SuggestionTypeContactTag   = "contact_tag" // affinity tag for a contact, see affinity.go
 )
 
 // SuggestionStatus constants.
@@ -0,0 +1,178 @@
+// ABOUTME: Currency and locale-aware formatting for amounts stored in cents
+// ABOUTME: Used by the CLI, TUI, web UI, viz, and MCP outputs so amounts render consistently
+
+package charm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultCurrency is used for deals without a currency.
+const DefaultCurrency = "USD"
+
+// moneyLocale describes how a locale writes numbers and currency symbols.
+type moneyLocale struct {
+	group        string // thousands separator (\u202f is a narrow no-break space)
+	decimal      string // decimal separator
+	symbolSuffix bool   // "1.234,56 €" rather than "€1,234.56"
+}
+
+// moneyLocales are keyed by language code.
+var moneyLocales = map[string]moneyLocale{
+	"en": {group: ",", decimal: "."},
+	"ja": {group: ",", decimal: "."},
+	"zh": {group: ",", decimal: "."},
+	"de": {group: ".", decimal: ",", symbolSuffix: true},
+	"es": {group: ".", decimal: ",", symbolSuffix: true},
+	"it": {group: ".", decimal: ",", symbolSuffix: true},
+	"nl": {group: ".", decimal: ",", symbolSuffix: true},
+	"pt": {group: ".", decimal: ",", symbolSuffix: true},
+	"fr": {group: "\u202f", decimal: ",", symbolSuffix: true},
+	"sv": {group: "\u202f", decimal: ",", symbolSuffix: true},
+}
+
+// currencySymbols maps ISO 4217 codes to display symbols.
+// Currencies not listed are shown by code.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "CN¥",
+	"INR": "₹",
+	"CAD": "CA$",
+	"AUD": "A$",
+	"KRW": "₩",
+	"BRL": "R$",
+}
+
+// zeroDecimalCurrencies have no minor unit in everyday use.
+var zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true}
+
+// currentMoneyLocale is the process-wide locale for formatting amounts.
+var currentMoneyLocale = detectMoneyLocale()
+
+// SetMoneyLocale sets the locale used to format amounts, e.g. "de" or "fr_FR.UTF-8".
+// Unknown locales fall back to English formatting.
+func SetMoneyLocale(locale string) {
+	currentMoneyLocale = lookupMoneyLocale(locale)
+}
+
+// detectMoneyLocale reads the locale from PAGEN_LOCALE or the standard locale variables.
+func detectMoneyLocale() moneyLocale {
+	for _, name := range []string{"PAGEN_LOCALE", "LC_ALL", "LC_MONETARY", "LANG"} {
+		if v := os.Getenv(name); v != "" && v != "C" && v != "POSIX" {
+			return lookupMoneyLocale(v)
+		}
+	}
+	return moneyLocales["en"]
+}
+
+func lookupMoneyLocale(locale string) moneyLocale {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	if l, ok := moneyLocales[lang]; ok {
+		return l
+	}
+	return moneyLocales["en"]
+}
+
+// FormatMoney formats an amount in cents with its currency, e.g. "$1,234.56".
+func FormatMoney(cents int64, currency string) string {
+	currency = normalizeCurrency(currency)
+	decimals := 2
+	if zeroDecimalCurrencies[currency] {
+		decimals = 0
+	}
+	return formatMoney(cents, currency, decimals)
+}
+
+// FormatMoneyRounded formats an amount in whole currency units, e.g. "$1,235".
+func FormatMoneyRounded(cents int64, currency string) string {
+	return formatMoney(cents, normalizeCurrency(currency), 0)
+}
+
+// FormatMoneyCompact formats an amount for tight spaces, e.g. "$45K" or "$1.2M".
+func FormatMoneyCompact(cents int64, currency string) string {
+	currency = normalizeCurrency(currency)
+	units := float64(cents) / 100
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	var number string
+	switch {
+	case units >= 1_000_000:
+		number = compactNumber(units/1_000_000) + "M"
+	case units >= 1_000:
+		number = compactNumber(units/1_000) + "K"
+	default:
+		number = fmt.Sprintf("%.0f", units)
+	}
+	return sign + withSymbol(number, currency)
+}
+
+func formatMoney(cents int64, currency string, decimals int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	whole := cents / 100
+	frac := cents % 100
+	if decimals == 0 && frac >= 50 {
+		whole++
+	}
+
+	number := groupThousands(whole, currentMoneyLocale.group)
+	if decimals > 0 {
+		number += fmt.Sprintf("%s%02d", currentMoneyLocale.decimal, frac)
+	}
+	return sign + withSymbol(number, currency)
+}
+
+// withSymbol places the currency symbol, or the code if there's no symbol.
+func withSymbol(number, currency string) string {
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		return number + " " + currency
+	}
+	if currentMoneyLocale.symbolSuffix {
+		return number + " " + symbol
+	}
+	return symbol + number
+}
+
+// compactNumber formats with at most one decimal, dropping a trailing ".0".
+func compactNumber(v float64) string {
+	s := fmt.Sprintf("%.1f", v)
+	s = strings.TrimSuffix(s, ".0")
+	return strings.Replace(s, ".", currentMoneyLocale.decimal, 1)
+}
+
+func groupThousands(n int64, sep string) string {
+	digits := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
@@ -0,0 +1,71 @@
+// ABOUTME: Tests for currency and locale-aware amount formatting
+// ABOUTME: Covers separators, symbol placement, zero-decimal currencies, and compact output
+
+package charm
+
+import "testing"
+
+func TestFormatMoney(t *testing.T) {
+	defer SetMoneyLocale("en")
+
+	tests := []struct {
+		locale   string
+		cents    int64
+		currency string
+		want     string
+	}{
+		{"en", 123456, "USD", "$1,234.56"},
+		{"en", 123456, "", "$1,234.56"},
+		{"en", 5, "eur", "€0.05"},
+		{"en", -250000, "GBP", "-£2,500.00"},
+		{"en", 1234500, "JPY", "¥12,345"},
+		{"en", 123456, "CHF", "1,234.56 CHF"},
+		{"de_DE.UTF-8", 123456, "EUR", "1.234,56 €"},
+		{"fr", 123456789, "EUR", "1\u202f234\u202f567,89 €"},
+		{"xx", 123456, "USD", "$1,234.56"},
+	}
+
+	for _, tt := range tests {
+		SetMoneyLocale(tt.locale)
+		if got := FormatMoney(tt.cents, tt.currency); got != tt.want {
+			t.Errorf("FormatMoney(%d, %q) in %s = %q, want %q", tt.cents, tt.currency, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMoneyRounded(t *testing.T) {
+	SetMoneyLocale("en")
+
+	if got := FormatMoneyRounded(123456, "USD"); got != "$1,235" {
+		t.Errorf("expected $1,235, got %q", got)
+	}
+	if got := FormatMoneyRounded(-123449, "USD"); got != "-$1,234" {
+		t.Errorf("expected -$1,234, got %q", got)
+	}
+}
+
+func TestFormatMoneyCompact(t *testing.T) {
+	defer SetMoneyLocale("en")
+	SetMoneyLocale("en")
+
+	tests := []struct {
+		cents int64
+		want  string
+	}{
+		{50000, "$500"},
+		{4500000, "$45K"},
+		{12345600, "$123.5K"},
+		{120000000, "$1.2M"},
+		{-4500000, "-$45K"},
+	}
+	for _, tt := range tests {
+		if got := FormatMoneyCompact(tt.cents, "USD"); got != tt.want {
+			t.Errorf("FormatMoneyCompact(%d) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+
+	SetMoneyLocale("de")
+	if got := FormatMoneyCompact(120000000, "EUR"); got != "1,2M €" {
+		t.Errorf("expected 1,2M €, got %q", got)
+	}
+}
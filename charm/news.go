@@ -0,0 +1,170 @@
+// ABOUTME: Company news enrichment storage
+// ABOUTME: Keeps recent headlines per company from configured feeds, deduplicated and pruned by age
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// Defaults for the news enrichment job.
+const (
+	DefaultNewsMaxItems   = 5
+	DefaultNewsMaxAgeDays = 30
+)
+
+// NewsConfig configures the company news enrichment job.
+// Feeds are RSS or Atom URLs. A feed containing {name} or {domain} is a
+// per-company search (e.g. a news provider's search API) and every item counts;
+// a plain feed is fetched once and only items mentioning the company are kept.
+type NewsConfig struct {
+	Feeds      []string `json:"feeds,omitempty"`
+	MaxItems   int      `json:"max_items,omitempty"`    // per company, default: DefaultNewsMaxItems
+	MaxAgeDays int      `json:"max_age_days,omitempty"` // default: DefaultNewsMaxAgeDays
+}
+
+// MaxItemsOrDefault returns how many headlines are kept per company.
+func (n *NewsConfig) MaxItemsOrDefault() int {
+	if n != nil && n.MaxItems > 0 {
+		return n.MaxItems
+	}
+	return DefaultNewsMaxItems
+}
+
+// MaxAgeOrDefault returns how long headlines are kept.
+func (n *NewsConfig) MaxAgeOrDefault() time.Duration {
+	days := DefaultNewsMaxAgeDays
+	if n != nil && n.MaxAgeDays > 0 {
+		days = n.MaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// IsCompanyFeed reports whether a feed URL is a per-company search template.
+func IsCompanyFeed(feed string) bool {
+	return strings.Contains(feed, "{name}") || strings.Contains(feed, "{domain}")
+}
+
+// CompanyFeedURL fills a feed template's {name} and {domain} placeholders for a company.
+func CompanyFeedURL(feed string, company *Company) string {
+	return strings.NewReplacer(
+		"{name}", url.QueryEscape(company.Name),
+		"{domain}", url.QueryEscape(company.Domain),
+	).Replace(feed)
+}
+
+// NewsItem is one headline about a company.
+type NewsItem struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// CompanyNews is the stored recent news for a company, newest first.
+type CompanyNews struct {
+	CompanyID uuid.UUID   `json:"company_id"`
+	Items     []*NewsItem `json:"items"`
+	FetchedAt time.Time   `json:"fetched_at"`
+}
+
+// MergeNews combines stored and freshly fetched items, dropping duplicates (by
+// URL, or title when there is no URL) and items published before cutoff, and
+// keeps the newest max.
+func MergeNews(existing, fetched []*NewsItem, cutoff time.Time, max int) []*NewsItem {
+	seen := make(map[string]bool)
+	var merged []*NewsItem
+	for _, item := range append(append([]*NewsItem{}, fetched...), existing...) {
+		key := item.URL
+		if key == "" {
+			key = strings.ToLower(strings.TrimSpace(item.Title))
+		}
+		if seen[key] || item.PublishedAt.Before(cutoff) {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, item)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].PublishedAt.After(merged[j].PublishedAt)
+	})
+	if max > 0 && len(merged) > max {
+		merged = merged[:max]
+	}
+	return merged
+}
+
+// GetCompanyNews returns a company's stored news, or nil if none has been fetched.
+func (c *Client) GetCompanyNews(companyID uuid.UUID) (*CompanyNews, error) {
+	data, err := c.Get(CompanyNewsKey(companyID.String()))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var news CompanyNews
+	if err := json.Unmarshal(data, &news); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal company news: %w", err)
+	}
+	return &news, nil
+}
+
+// RecentCompanyNews returns a company's stored headlines, newest first.
+func (c *Client) RecentCompanyNews(companyID uuid.UUID) ([]*NewsItem, error) {
+	news, err := c.GetCompanyNews(companyID)
+	if err != nil || news == nil {
+		return nil, err
+	}
+	return news.Items, nil
+}
+
+// SaveCompanyNews merges fetched headlines into a company's stored news and
+// returns how many of them are new.
+func (c *Client) SaveCompanyNews(companyID uuid.UUID, fetched []*NewsItem, cfg *NewsConfig, now time.Time) (int, error) {
+	news, err := c.GetCompanyNews(companyID)
+	if err != nil {
+		return 0, err
+	}
+	if news == nil {
+		news = &CompanyNews{CompanyID: companyID}
+	}
+
+	known := make(map[string]bool)
+	for _, item := range news.Items {
+		known[item.URL+"\x00"+item.Title] = true
+	}
+
+	news.Items = MergeNews(news.Items, fetched, now.Add(-cfg.MaxAgeOrDefault()), cfg.MaxItemsOrDefault())
+	news.FetchedAt = now
+
+	added := 0
+	for _, item := range news.Items {
+		if !known[item.URL+"\x00"+item.Title] {
+			added++
+		}
+	}
+
+	data, err := json.Marshal(news)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal company news: %w", err)
+	}
+	if err := c.Set(CompanyNewsKey(companyID.String()), data); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
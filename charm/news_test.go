@@ -0,0 +1,114 @@
+// ABOUTME: Tests for company news storage
+// ABOUTME: Verifies feed templates, merging, pruning by age, and stored news per company
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompanyFeedURL(t *testing.T) {
+	company := &Company{Name: "Acme Corp", Domain: "acme.com"}
+
+	if !IsCompanyFeed("https://news.example.com/rss?q={name}") {
+		t.Error("expected {name} feed to be per-company")
+	}
+	if IsCompanyFeed("https://techcrunch.com/feed/") {
+		t.Error("expected plain feed not to be per-company")
+	}
+
+	got := CompanyFeedURL("https://news.example.com/rss?q={name}&site={domain}", company)
+	if got != "https://news.example.com/rss?q=Acme+Corp&site=acme.com" {
+		t.Errorf("unexpected feed URL: %s", got)
+	}
+}
+
+func TestMergeNews(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	existing := []*NewsItem{
+		{Title: "Acme raises Series B", URL: "https://example.com/b", PublishedAt: now.AddDate(0, 0, -10)},
+		{Title: "Acme founded", URL: "https://example.com/old", PublishedAt: now.AddDate(0, -3, 0)},
+	}
+	fetched := []*NewsItem{
+		{Title: "Acme raises Series B (updated)", URL: "https://example.com/b", PublishedAt: now.AddDate(0, 0, -10)},
+		{Title: "Acme opens Berlin office", PublishedAt: now.AddDate(0, 0, -1)},
+		{Title: "Acme hires CFO", URL: "https://example.com/cfo", PublishedAt: now.AddDate(0, 0, -5)},
+	}
+
+	merged := MergeNews(existing, fetched, now.AddDate(0, 0, -30), 2)
+	if len(merged) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(merged))
+	}
+	if merged[0].Title != "Acme opens Berlin office" || merged[1].Title != "Acme hires CFO" {
+		t.Errorf("expected newest first, got %q, %q", merged[0].Title, merged[1].Title)
+	}
+
+	merged = MergeNews(existing, fetched, now.AddDate(0, 0, -30), 0)
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 items without a cap, got %d", len(merged))
+	}
+	if merged[2].Title != "Acme raises Series B (updated)" {
+		t.Errorf("expected fetched item to replace its duplicate, got %q", merged[2].Title)
+	}
+}
+
+func TestSaveCompanyNews(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme", Domain: "acme.com"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	items, err := client.RecentCompanyNews(company.ID)
+	if err != nil {
+		t.Fatalf("RecentCompanyNews failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no news before a fetch, got %d", len(items))
+	}
+
+	now := time.Now()
+	cfg := &NewsConfig{MaxItems: 10}
+	fetched := []*NewsItem{
+		{Title: "Acme launches widget", URL: "https://example.com/widget", PublishedAt: now.Add(-time.Hour)},
+		{Title: "Acme in 2019", URL: "https://example.com/2019", PublishedAt: now.AddDate(-5, 0, 0)},
+	}
+	added, err := client.SaveCompanyNews(company.ID, fetched, cfg, now)
+	if err != nil {
+		t.Fatalf("SaveCompanyNews failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 new item (old one pruned), got %d", added)
+	}
+
+	// Saving the same items again adds nothing
+	added, err = client.SaveCompanyNews(company.ID, fetched, cfg, now)
+	if err != nil {
+		t.Fatalf("SaveCompanyNews failed: %v", err)
+	}
+	if added != 0 {
+		t.Errorf("expected no new items on refetch, got %d", added)
+	}
+
+	items, err = client.RecentCompanyNews(company.ID)
+	if err != nil {
+		t.Fatalf("RecentCompanyNews failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Acme launches widget" {
+		t.Errorf("unexpected stored news: %+v", items)
+	}
+
+	// Deleting the company drops its news
+	if err := client.DeleteCompany(company.ID); err != nil {
+		t.Fatalf("DeleteCompany failed: %v", err)
+	}
+	news, err := client.GetCompanyNews(company.ID)
+	if err != nil {
+		t.Fatalf("GetCompanyNews failed: %v", err)
+	}
+	if news != nil {
+		t.Error("expected news to be deleted with the company")
+	}
+}
@@ -0,0 +1,111 @@
+// ABOUTME: Markdown note helpers - task list checklists in deal and contact notes
+// ABOUTME: Finds "- [ ] item" lines outside code blocks and toggles them in place
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ChecklistItem is a Markdown task list item, e.g. "- [x] Send proposal".
+type ChecklistItem struct {
+	Index int // position among the note's checklist items, from 0
+	Text  string
+	Done  bool
+}
+
+// checklistPattern matches a list item that starts with a task box. The
+// submatches are the marker, the box state, and the item text.
+var checklistPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d{1,9}[.)])\s+)\[([ xX])\](?:\s+(.*))?$`)
+
+// ParseChecklist returns the checklist items in a Markdown note, in order.
+func ParseChecklist(markdown string) []ChecklistItem {
+	var items []ChecklistItem
+	eachChecklistLine(strings.SplitAfter(markdown, "\n"), func(_ int, line string, match []int) {
+		items = append(items, ChecklistItem{
+			Index: len(items),
+			Text:  checklistText(line, match),
+			Done:  line[match[4]] != ' ',
+		})
+	})
+	return items
+}
+
+// ToggleChecklistItem flips the checklist item at index, leaving the rest of
+// the note untouched.
+func ToggleChecklistItem(markdown string, index int) (string, error) {
+	lines := strings.SplitAfter(markdown, "\n")
+	count := 0
+	found := false
+	eachChecklistLine(lines, func(i int, line string, match []int) {
+		if count == index {
+			box := "x"
+			if line[match[4]] != ' ' {
+				box = " "
+			}
+			lines[i] = line[:match[4]] + box + lines[i][match[4]+1:]
+			found = true
+		}
+		count++
+	})
+	if !found {
+		return "", fmt.Errorf("no checklist item %d (note has %d)", index+1, count)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// ToggleDealNoteItem flips a checklist item in a deal note and saves it.
+func (c *Client) ToggleDealNoteItem(noteID uuid.UUID, index int) (*DealNote, error) {
+	note, err := c.GetDealNote(noteID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ToggleChecklistItem(note.Content, index)
+	if err != nil {
+		return nil, err
+	}
+	note.Content = content
+
+	data, err := json.Marshal(note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal deal note: %w", err)
+	}
+	if err := c.Set(DealNoteKey(note.ID.String()), data); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// eachChecklistLine calls fn with the index, text (without line ending),
+// and pattern submatch offsets of every checklist line, skipping fenced code.
+func eachChecklistLine(lines []string, fn func(i int, line string, match []int)) {
+	fence := ""
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, "\r\n")
+		trimmed := strings.TrimLeft(line, " \t")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if match := checklistPattern.FindStringSubmatchIndex(line); match != nil {
+			fn(i, line, match)
+		}
+	}
+}
+
+func checklistText(line string, match []int) string {
+	if match[6] < 0 {
+		return ""
+	}
+	return strings.TrimSpace(line[match[6]:match[7]])
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Tests for Markdown note checklists
+// ABOUTME: Verifies parsing and toggling task items, skipping code blocks, and saving toggled deal notes
+package charm
+
+import (
+	"testing"
+)
+
+const checklistNote = "Next steps:\r\n" +
+	"- [ ] Send proposal\r\n" +
+	"* [x] Intro call\r\n" +
+	"  1. [X] Nested in a numbered list\r\n" +
+	"```\r\n" +
+	"- [ ] not a task, just code\r\n" +
+	"```\r\n" +
+	"- [] not a task either\r\n" +
+	"+ [ ]\r\n"
+
+func TestParseChecklist(t *testing.T) {
+	items := ParseChecklist(checklistNote)
+	want := []ChecklistItem{
+		{Index: 0, Text: "Send proposal"},
+		{Index: 1, Text: "Intro call", Done: true},
+		{Index: 2, Text: "Nested in a numbered list", Done: true},
+		{Index: 3, Text: ""},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d items, got %+v", len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("item %d: expected %+v, got %+v", i, want[i], items[i])
+		}
+	}
+}
+
+func TestToggleChecklistItem(t *testing.T) {
+	toggled, err := ToggleChecklistItem(checklistNote, 0)
+	if err != nil {
+		t.Fatalf("ToggleChecklistItem failed: %v", err)
+	}
+	if toggled != "Next steps:\r\n- [x] Send proposal\r\n"+checklistNote[len("Next steps:\r\n- [ ] Send proposal\r\n"):] {
+		t.Errorf("only the first box should change, got %q", toggled)
+	}
+
+	// Toggling twice restores the note, and checked boxes uncheck
+	restored, err := ToggleChecklistItem(toggled, 0)
+	if err != nil || restored != checklistNote {
+		t.Errorf("expected the original note back, got %q, %v", restored, err)
+	}
+	unchecked, _ := ToggleChecklistItem(checklistNote, 2)
+	if items := ParseChecklist(unchecked); items[2].Done {
+		t.Error("expected the nested item to be unchecked")
+	}
+
+	for _, index := range []int{-1, 4} {
+		if _, err := ToggleChecklistItem(checklistNote, index); err == nil {
+			t.Errorf("expected an error for item %d", index)
+		}
+	}
+}
+
+func TestToggleDealNoteItem(t *testing.T) {
+	client := NewTestClient(t)
+	deal := &Deal{Title: "Acme renewal", Stage: StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	note := &DealNote{DealID: deal.ID, Content: "- [ ] Send proposal\n- [ ] Book call"}
+	if err := client.CreateDealNote(note); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	if _, err := client.ToggleDealNoteItem(note.ID, 1); err != nil {
+		t.Fatalf("ToggleDealNoteItem failed: %v", err)
+	}
+	saved, err := client.GetDealNote(note.ID)
+	if err != nil {
+		t.Fatalf("failed to get note: %v", err)
+	}
+	if saved.Content != "- [ ] Send proposal\n- [x] Book call" || !saved.CreatedAt.Equal(note.CreatedAt) {
+		t.Errorf("unexpected saved note: %+v", saved)
+	}
+}
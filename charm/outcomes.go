@@ -0,0 +1,178 @@
+// ABOUTME: Follow-up outcomes and next steps recorded on interactions
+// ABOUTME: Attributes outcomes to outreach templates and reports response rates by channel and template
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Follow-up outcome constants.
+const (
+	OutcomeReplied       = "replied"
+	OutcomeNoAnswer      = "no_answer"
+	OutcomeMeetingBooked = "meeting_booked"
+)
+
+// Outcomes lists the valid follow-up outcomes.
+var Outcomes = []string{OutcomeReplied, OutcomeNoAnswer, OutcomeMeetingBooked}
+
+// OutcomeAttributionDays is how recent an outreach email must be for a
+// follow-up outcome to be credited to its template.
+const OutcomeAttributionDays = 30
+
+// NormalizeOutcome returns the outcome constant for s, accepting spaces or
+// dashes for underscores ("no answer", "meeting-booked"). Empty is no outcome.
+func NormalizeOutcome(s string) (string, error) {
+	outcome := strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(s)))
+	if outcome == "" {
+		return "", nil
+	}
+	for _, valid := range Outcomes {
+		if outcome == valid {
+			return outcome, nil
+		}
+	}
+	return "", Validationf("invalid outcome %q (use %s)", s, strings.Join(Outcomes, ", "))
+}
+
+// OutcomeResponded reports whether an outcome means the contact responded.
+func OutcomeResponded(outcome string) bool {
+	return outcome == OutcomeReplied || outcome == OutcomeMeetingBooked
+}
+
+// LogFollowup records an interaction logged from the follow-up flow. An
+// outcome without a template is credited to the contact's latest outreach
+// email, which is closed as replied when the contact responded.
+func (c *Client) LogFollowup(interaction *InteractionLog) error {
+	outcome, err := NormalizeOutcome(interaction.Outcome)
+	if err != nil {
+		return err
+	}
+	interaction.Outcome = outcome
+	interaction.NextStep = strings.TrimSpace(interaction.NextStep)
+	if interaction.Timestamp.IsZero() {
+		interaction.Timestamp = time.Now()
+	}
+
+	if outcome != "" && interaction.Template == "" {
+		outreach, err := c.latestOutreach(interaction.ContactID, interaction.Timestamp)
+		if err != nil {
+			return fmt.Errorf("failed to find outreach: %w", err)
+		}
+		if outreach != nil {
+			interaction.Template = outreach.Template
+			if OutcomeResponded(outcome) && outreach.Status == OutreachPending {
+				outreach.Status = OutreachReplied
+				outreach.RepliedAt = &interaction.Timestamp
+				if err := c.saveOutreach(outreach); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return c.CreateInteractionLog(interaction)
+}
+
+// latestOutreach returns the contact's most recent outreach email sent in the
+// OutcomeAttributionDays before at, or nil.
+func (c *Client) latestOutreach(contactID uuid.UUID, at time.Time) (*Outreach, error) {
+	list, err := c.ListOutreach("")
+	if err != nil {
+		return nil, err
+	}
+	cutoff := at.AddDate(0, 0, -OutcomeAttributionDays)
+	// ListOutreach is newest first
+	for _, outreach := range list {
+		if outreach.ContactID == contactID && !outreach.CreatedAt.After(at) && outreach.CreatedAt.After(cutoff) {
+			return outreach, nil
+		}
+	}
+	return nil, nil
+}
+
+// OutcomeStats counts follow-up outcomes for one channel or template.
+type OutcomeStats struct {
+	Name           string `json:"name"`
+	Attempts       int    `json:"attempts"`
+	Replied        int    `json:"replied"`
+	NoAnswer       int    `json:"no_answer"`
+	MeetingsBooked int    `json:"meetings_booked"`
+}
+
+// ResponseRate is the share of attempts where the contact replied or booked a meeting.
+func (s *OutcomeStats) ResponseRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Replied+s.MeetingsBooked) / float64(s.Attempts)
+}
+
+// OutcomeReport breaks follow-up outcomes down by channel (interaction type)
+// and by outreach template, best response rate first.
+type OutcomeReport struct {
+	ByChannel  []*OutcomeStats `json:"by_channel"`
+	ByTemplate []*OutcomeStats `json:"by_template"`
+}
+
+// GetOutcomeReport tallies the outcomes of interactions since the given time
+// (all when nil). Interactions without an outcome are not counted.
+func (c *Client) GetOutcomeReport(since *time.Time) (*OutcomeReport, error) {
+	logs, err := c.ListInteractionLogs(&InteractionFilter{Since: since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+
+	byChannel := make(map[string]*OutcomeStats)
+	byTemplate := make(map[string]*OutcomeStats)
+	for _, log := range logs {
+		if log.Outcome == "" {
+			continue
+		}
+		addOutcome(byChannel, log.InteractionType, log.Outcome)
+		if log.Template != "" {
+			addOutcome(byTemplate, log.Template, log.Outcome)
+		}
+	}
+	return &OutcomeReport{ByChannel: sortOutcomeStats(byChannel), ByTemplate: sortOutcomeStats(byTemplate)}, nil
+}
+
+func addOutcome(stats map[string]*OutcomeStats, name, outcome string) {
+	s := stats[name]
+	if s == nil {
+		s = &OutcomeStats{Name: name}
+		stats[name] = s
+	}
+	s.Attempts++
+	switch outcome {
+	case OutcomeReplied:
+		s.Replied++
+	case OutcomeNoAnswer:
+		s.NoAnswer++
+	case OutcomeMeetingBooked:
+		s.MeetingsBooked++
+	}
+}
+
+func sortOutcomeStats(stats map[string]*OutcomeStats) []*OutcomeStats {
+	list := make([]*OutcomeStats, 0, len(stats))
+	for _, s := range stats {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if ri, rj := list[i].ResponseRate(), list[j].ResponseRate(); ri != rj {
+			return ri > rj
+		}
+		if list[i].Attempts != list[j].Attempts {
+			return list[i].Attempts > list[j].Attempts
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
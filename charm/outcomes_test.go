@@ -0,0 +1,116 @@
+// ABOUTME: Tests for follow-up outcomes
+// ABOUTME: Verifies outcome parsing, template attribution, closing outreach, and response rates
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeOutcome(t *testing.T) {
+	for input, want := range map[string]string{
+		"":               "",
+		"replied":        OutcomeReplied,
+		"No Answer":      OutcomeNoAnswer,
+		"meeting-booked": OutcomeMeetingBooked,
+	} {
+		if got, err := NormalizeOutcome(input); err != nil || got != want {
+			t.Errorf("NormalizeOutcome(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := NormalizeOutcome("ghosted"); err == nil {
+		t.Error("expected an error for an unknown outcome")
+	}
+}
+
+func TestLogFollowup(t *testing.T) {
+	client := NewTestClient(t)
+
+	ada := &Contact{Name: "Ada Lovelace", Email: "ada@example.com"}
+	grace := &Contact{Name: "Grace Hopper", Email: "grace@example.com"}
+	for _, contact := range []*Contact{ada, grace} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	now := time.Now()
+	outreach := &Outreach{ContactID: ada.ID, Template: "intro", Subject: "Quick introduction", CreatedAt: now.AddDate(0, 0, -3)}
+	stale := &Outreach{ContactID: grace.ID, Template: "check-in", Subject: "Checking in", CreatedAt: now.AddDate(0, 0, -(OutcomeAttributionDays + 1))}
+	for _, o := range []*Outreach{outreach, stale} {
+		if err := client.CreateOutreach(o); err != nil {
+			t.Fatalf("CreateOutreach failed: %v", err)
+		}
+	}
+
+	// A response is credited to the latest outreach and closes it
+	booked := &InteractionLog{ContactID: ada.ID, InteractionType: InteractionEmail, Outcome: "meeting booked", NextStep: " send the deck "}
+	if err := client.LogFollowup(booked); err != nil {
+		t.Fatalf("LogFollowup failed: %v", err)
+	}
+	if booked.Outcome != OutcomeMeetingBooked || booked.Template != "intro" || booked.NextStep != "send the deck" {
+		t.Errorf("unexpected interaction: %+v", booked)
+	}
+	if pending, _ := client.ListOutreach(OutreachPending); len(pending) != 1 || pending[0].ID != stale.ID {
+		t.Errorf("expected only the stale outreach pending, got %d", len(pending))
+	}
+
+	// Outreach older than the attribution window isn't credited
+	noAnswer := &InteractionLog{ContactID: grace.ID, InteractionType: InteractionCall, Outcome: OutcomeNoAnswer}
+	if err := client.LogFollowup(noAnswer); err != nil {
+		t.Fatalf("LogFollowup failed: %v", err)
+	}
+	if noAnswer.Template != "" {
+		t.Errorf("expected no template, got %q", noAnswer.Template)
+	}
+
+	if err := client.LogFollowup(&InteractionLog{ContactID: ada.ID, Outcome: "ghosted"}); err == nil {
+		t.Error("expected an error for an unknown outcome")
+	}
+	if logs, _ := client.ListInteractionLogs(nil); len(logs) != 2 {
+		t.Errorf("expected 2 interactions logged, got %d", len(logs))
+	}
+}
+
+func TestGetOutcomeReport(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Ada Lovelace"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	now := time.Now()
+	for _, log := range []*InteractionLog{
+		{InteractionType: InteractionEmail, Template: "intro", Outcome: OutcomeReplied},
+		{InteractionType: InteractionEmail, Template: "intro", Outcome: OutcomeNoAnswer},
+		{InteractionType: InteractionEmail, Template: "check-in", Outcome: OutcomeMeetingBooked},
+		{InteractionType: InteractionCall, Outcome: OutcomeNoAnswer},
+		{InteractionType: InteractionCall, Outcome: OutcomeNoAnswer, Timestamp: now.AddDate(0, 0, -60)},
+		{InteractionType: InteractionMeeting},
+	} {
+		log.ContactID = contact.ID
+		if err := client.CreateInteractionLog(log); err != nil {
+			t.Fatalf("CreateInteractionLog failed: %v", err)
+		}
+	}
+
+	since := now.AddDate(0, 0, -30)
+	report, err := client.GetOutcomeReport(&since)
+	if err != nil {
+		t.Fatalf("GetOutcomeReport failed: %v", err)
+	}
+
+	if len(report.ByChannel) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(report.ByChannel))
+	}
+	email, call := report.ByChannel[0], report.ByChannel[1]
+	if email.Name != InteractionEmail || email.Attempts != 3 || email.Replied != 1 || email.MeetingsBooked != 1 || email.NoAnswer != 1 {
+		t.Errorf("unexpected email stats: %+v", email)
+	}
+	if call.Name != InteractionCall || call.Attempts != 1 || call.ResponseRate() != 0 {
+		t.Errorf("unexpected call stats: %+v", call)
+	}
+
+	if len(report.ByTemplate) != 2 || report.ByTemplate[0].Name != "check-in" || report.ByTemplate[1].ResponseRate() != 0.5 {
+		t.Errorf("expected check-in ahead of intro at 50%%, got %+v, %+v", report.ByTemplate[0], report.ByTemplate[1])
+	}
+}
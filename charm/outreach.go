@@ -0,0 +1,202 @@
+// ABOUTME: Outreach emails drafted from templates and awaiting a reply
+// ABOUTME: Renders email templates, tracks pending outreach, and logs the reply as an interaction
+
+package charm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outreach statuses.
+const (
+	OutreachPending = "pending"
+	OutreachReplied = "replied"
+)
+
+// Outreach is an email drafted to a contact that is waiting on a reply.
+type Outreach struct {
+	ID          uuid.UUID  `json:"id"`
+	ContactID   uuid.UUID  `json:"contact_id"`
+	ContactName string     `json:"contact_name,omitempty"` // denormalized
+	Email       string     `json:"email"`
+	Template    string     `json:"template"`
+	Subject     string     `json:"subject"`
+	DraftID     string     `json:"draft_id,omitempty"`  // Gmail draft, when one was created
+	ThreadID    string     `json:"thread_id,omitempty"` // Gmail thread the reply will land in
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty"`
+}
+
+// EmailTemplate is a subject and body, both Go templates.
+type EmailTemplate struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// DefaultEmailTemplates are used for names without a template in the config.
+var DefaultEmailTemplates = map[string]EmailTemplate{
+	"intro": {
+		Subject: "Quick introduction",
+		Body:    "Hi {{.FirstName}},\n\nI wanted to introduce myself{{if .Company}} and learn more about what you're working on at {{.Company}}{{end}}. Would you be open to a quick call in the next week or two?\n\nBest,{{if .Sender}}\n{{.Sender}}{{end}}",
+	},
+	"follow-up": {
+		Subject: "Following up",
+		Body:    "Hi {{.FirstName}},\n\nJust following up on my last note. Let me know if now is a better time to connect.\n\nBest,{{if .Sender}}\n{{.Sender}}{{end}}",
+	},
+	"check-in": {
+		Subject: "Checking in",
+		Body:    "Hi {{.FirstName}},\n\nIt's been a while, so I wanted to check in and see how things are going{{if .Company}} at {{.Company}}{{end}}.\n\nBest,{{if .Sender}}\n{{.Sender}}{{end}}",
+	},
+}
+
+// EmailTemplateData is the value passed to email templates as ".".
+type EmailTemplateData struct {
+	Name      string
+	FirstName string
+	Company   string
+	Email     string
+	Sender    string
+}
+
+// EmailTemplateNames returns the default and configured template names, sorted.
+func EmailTemplateNames(templates map[string]EmailTemplate) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, set := range []map[string]EmailTemplate{DefaultEmailTemplates, templates} {
+		for name := range set {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DraftEmail renders the named template for contact. templates override
+// DefaultEmailTemplates by name; sender signs the email.
+func DraftEmail(contact *Contact, name, sender string, templates map[string]EmailTemplate) (subject, body string, err error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		tmpl, ok = DefaultEmailTemplates[name]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q (use %s)", name, strings.Join(EmailTemplateNames(templates), ", "))
+	}
+
+	data := EmailTemplateData{
+		Name:    contact.Name,
+		Company: contact.CompanyName,
+		Email:   contact.Email,
+		Sender:  sender,
+	}
+	data.FirstName, _, _ = strings.Cut(strings.TrimSpace(contact.Name), " ")
+
+	if subject, err = renderEmailTemplate(name+" subject", tmpl.Subject, data); err != nil {
+		return "", "", err
+	}
+	if body, err = renderEmailTemplate(name+" body", tmpl.Body, data); err != nil {
+		return "", "", err
+	}
+	return strings.Join(strings.Fields(subject), " "), body, nil
+}
+
+func renderEmailTemplate(name, text string, data EmailTemplateData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", Validationf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// CreateOutreach records a pending outreach email.
+func (c *Client) CreateOutreach(outreach *Outreach) error {
+	if outreach.ID == uuid.Nil {
+		outreach.ID = uuid.New()
+	}
+	if outreach.CreatedAt.IsZero() {
+		outreach.CreatedAt = time.Now()
+	}
+	if outreach.Status == "" {
+		outreach.Status = OutreachPending
+	}
+	return c.saveOutreach(outreach)
+}
+
+// ListOutreach returns outreach emails, newest first. An empty status returns all.
+func (c *Client) ListOutreach(status string) ([]*Outreach, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixOutreach))
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Outreach
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var outreach Outreach
+		if err := json.Unmarshal(data, &outreach); err != nil {
+			continue
+		}
+		if status != "" && outreach.Status != status {
+			continue
+		}
+		list = append(list, &outreach)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+// MarkOutreachReplied closes a pending outreach and logs the reply as an
+// email interaction at the time it arrived, credited to its template.
+func (c *Client) MarkOutreachReplied(outreach *Outreach, at time.Time, notes string) error {
+	if outreach.Status == OutreachReplied {
+		return nil
+	}
+	outreach.Status = OutreachReplied
+	outreach.RepliedAt = &at
+	if err := c.saveOutreach(outreach); err != nil {
+		return err
+	}
+
+	if notes == "" {
+		notes = "Replied: " + outreach.Subject
+	}
+	return c.CreateInteractionLog(&InteractionLog{
+		ContactID:       outreach.ContactID,
+		ContactName:     outreach.ContactName,
+		InteractionType: InteractionEmail,
+		Timestamp:       at,
+		Notes:           notes,
+		Outcome:         OutcomeReplied,
+		Template:        outreach.Template,
+	})
+}
+
+func (c *Client) saveOutreach(outreach *Outreach) error {
+	data, err := json.Marshal(outreach)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outreach: %w", err)
+	}
+	return c.Set(OutreachKey(outreach.ID.String()), data)
+}
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for outreach emails
+// ABOUTME: Verifies template rendering and overrides, pending listing, and reply logging
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDraftEmail(t *testing.T) {
+	contact := &Contact{Name: "Ada Lovelace", Email: "ada@example.com", CompanyName: "Analytical Engines"}
+
+	subject, body, err := DraftEmail(contact, "intro", "Grace", nil)
+	if err != nil {
+		t.Fatalf("DraftEmail failed: %v", err)
+	}
+	if subject != "Quick introduction" {
+		t.Errorf("unexpected subject: %q", subject)
+	}
+	if !strings.HasPrefix(body, "Hi Ada,") || !strings.Contains(body, "Analytical Engines") || !strings.HasSuffix(body, "Grace") {
+		t.Errorf("unexpected body: %q", body)
+	}
+
+	templates := map[string]EmailTemplate{
+		"intro": {Subject: "Hi {{.FirstName}}", Body: "Short one."},
+		"demo":  {Subject: "Demo for {{.Company}}", Body: "Want a demo?"},
+	}
+	if subject, _, _ := DraftEmail(contact, "intro", "", templates); subject != "Hi Ada" {
+		t.Errorf("expected the config template to override intro, got %q", subject)
+	}
+	if subject, _, _ := DraftEmail(contact, "demo", "", templates); subject != "Demo for Analytical Engines" {
+		t.Errorf("expected a custom template, got %q", subject)
+	}
+	if _, _, err := DraftEmail(contact, "missing", "", templates); err == nil || !strings.Contains(err.Error(), "demo") {
+		t.Errorf("expected unknown template error listing names, got %v", err)
+	}
+}
+
+func TestOutreachReplied(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Ada Lovelace", Email: "ada@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	outreach := &Outreach{ContactID: contact.ID, ContactName: contact.Name, Email: contact.Email, Template: "intro", Subject: "Quick introduction"}
+	if err := client.CreateOutreach(outreach); err != nil {
+		t.Fatalf("CreateOutreach failed: %v", err)
+	}
+
+	pending, err := client.ListOutreach(OutreachPending)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected 1 pending outreach, got %d, %v", len(pending), err)
+	}
+
+	at := time.Now().Add(time.Hour)
+	if err := client.MarkOutreachReplied(pending[0], at, ""); err != nil {
+		t.Fatalf("MarkOutreachReplied failed: %v", err)
+	}
+	// A second check must not log the reply again
+	if err := client.MarkOutreachReplied(pending[0], at, ""); err != nil {
+		t.Fatalf("MarkOutreachReplied failed: %v", err)
+	}
+
+	if pending, _ := client.ListOutreach(OutreachPending); len(pending) != 0 {
+		t.Errorf("expected no pending outreach, got %d", len(pending))
+	}
+	if all, _ := client.ListOutreach(""); len(all) != 1 || all[0].RepliedAt == nil {
+		t.Errorf("expected the replied outreach to be kept, got %+v", all)
+	}
+
+	interactions, err := client.ListInteractionLogs(&InteractionFilter{ContactID: &contact.ID})
+	if err != nil {
+		t.Fatalf("ListInteractionLogs failed: %v", err)
+	}
+	if len(interactions) != 1 || interactions[0].InteractionType != InteractionEmail || interactions[0].Notes != "Replied: Quick introduction" {
+		t.Errorf("expected one reply interaction, got %+v", interactions)
+	}
+	if len(interactions) == 1 && (interactions[0].Outcome != OutcomeReplied || interactions[0].Template != "intro") {
+		t.Errorf("expected the reply credited to the intro template, got %+v", interactions[0])
+	}
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Pinned facts on contacts
+// ABOUTME: Key facts kept apart from the chronological notes and shown first in summaries and briefs
+
+package charm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PinFact adds a fact to the contact's pinned facts. Returns false when the
+// fact is empty or already pinned, ignoring case.
+func (c *Contact) PinFact(fact string) bool {
+	fact = strings.TrimSpace(fact)
+	if fact == "" || c.pinnedIndex(fact) >= 0 {
+		return false
+	}
+	c.Pinned = append(c.Pinned, fact)
+	return true
+}
+
+// UnpinFact removes a pinned fact by its position as listed (from 1) or by
+// its text, ignoring case, and returns the removed fact.
+func (c *Contact) UnpinFact(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	i := c.pinnedIndex(ref)
+	if n, err := strconv.Atoi(ref); err == nil && i < 0 {
+		if n < 1 || n > len(c.Pinned) {
+			return "", fmt.Errorf("no pinned fact %d (%s has %d)", n, c.Name, len(c.Pinned))
+		}
+		i = n - 1
+	}
+	if i < 0 {
+		return "", fmt.Errorf("%q is not pinned on %s", ref, c.Name)
+	}
+	fact := c.Pinned[i]
+	c.Pinned = append(c.Pinned[:i], c.Pinned[i+1:]...)
+	if len(c.Pinned) == 0 {
+		c.Pinned = nil
+	}
+	return fact, nil
+}
+
+func (c *Contact) pinnedIndex(fact string) int {
+	for i, pinned := range c.Pinned {
+		if strings.EqualFold(pinned, fact) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PinContactFact pins a fact on a contact. Pinning a fact that is already
+// pinned leaves the contact unchanged.
+func (c *Client) PinContactFact(contactID uuid.UUID, fact string) (*Contact, error) {
+	if strings.TrimSpace(fact) == "" {
+		return nil, Validationf("fact is required")
+	}
+	contact, err := c.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+	if !contact.PinFact(fact) {
+		return contact, nil
+	}
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	return contact, nil
+}
+
+// UnpinContactFact removes a pinned fact from a contact by position or text.
+func (c *Client) UnpinContactFact(contactID uuid.UUID, ref string) (*Contact, error) {
+	contact, err := c.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := contact.UnpinFact(ref); err != nil {
+		return nil, err
+	}
+	if err := c.UpdateContact(contact); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	return contact, nil
+}
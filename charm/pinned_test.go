@@ -0,0 +1,92 @@
+// ABOUTME: Tests for pinned facts on contacts
+// ABOUTME: Verifies pinning, deduplication, unpinning by number or text, and persistence
+
+package charm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPinFact(t *testing.T) {
+	contact := &Contact{Name: "Alice"}
+	if !contact.PinFact("  kids: 2 ") {
+		t.Fatal("expected fact to be pinned")
+	}
+	if !contact.PinFact("likes cycling") {
+		t.Fatal("expected second fact to be pinned")
+	}
+	if contact.PinFact("Kids: 2") {
+		t.Error("expected a fact differing only in case to be ignored")
+	}
+	if contact.PinFact("   ") {
+		t.Error("expected an empty fact to be ignored")
+	}
+	if want := []string{"kids: 2", "likes cycling"}; !reflect.DeepEqual(contact.Pinned, want) {
+		t.Errorf("expected %v, got %v", want, contact.Pinned)
+	}
+}
+
+func TestUnpinFact(t *testing.T) {
+	contact := &Contact{Name: "Alice", Pinned: []string{"kids: 2", "likes cycling", "2"}}
+
+	// Text matches win over positions
+	if fact, err := contact.UnpinFact("2"); err != nil || fact != "2" {
+		t.Fatalf("UnpinFact(2) = %q, %v", fact, err)
+	}
+	if fact, err := contact.UnpinFact("2"); err != nil || fact != "likes cycling" {
+		t.Fatalf("UnpinFact(2) = %q, %v", fact, err)
+	}
+	if _, err := contact.UnpinFact("5"); err == nil {
+		t.Error("expected an error for an out of range number")
+	}
+	if _, err := contact.UnpinFact("vegan"); err == nil {
+		t.Error("expected an error for a fact that is not pinned")
+	}
+	if fact, err := contact.UnpinFact("KIDS: 2"); err != nil || fact != "kids: 2" {
+		t.Fatalf("UnpinFact(KIDS: 2) = %q, %v", fact, err)
+	}
+	if contact.Pinned != nil {
+		t.Errorf("expected no pinned facts, got %v", contact.Pinned)
+	}
+}
+
+func TestPinContactFact(t *testing.T) {
+	client := NewTestClient(t)
+	contact := &Contact{Name: "Alice", Notes: "Met at the conference"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	if _, err := client.PinContactFact(contact.ID, "kids: 2"); err != nil {
+		t.Fatalf("PinContactFact failed: %v", err)
+	}
+	if _, err := client.PinContactFact(contact.ID, "likes cycling"); err != nil {
+		t.Fatalf("PinContactFact failed: %v", err)
+	}
+	if _, err := client.PinContactFact(contact.ID, " "); err == nil {
+		t.Error("expected an error for an empty fact")
+	}
+
+	got, err := client.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if want := []string{"kids: 2", "likes cycling"}; !reflect.DeepEqual(got.Pinned, want) {
+		t.Errorf("expected %v, got %v", want, got.Pinned)
+	}
+	if got.Notes != "Met at the conference" {
+		t.Errorf("expected notes unchanged, got %q", got.Notes)
+	}
+
+	if _, err := client.UnpinContactFact(contact.ID, "1"); err != nil {
+		t.Fatalf("UnpinContactFact failed: %v", err)
+	}
+	got, err = client.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if want := []string{"likes cycling"}; !reflect.DeepEqual(got.Pinned, want) {
+		t.Errorf("expected %v, got %v", want, got.Pinned)
+	}
+}
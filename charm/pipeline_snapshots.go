@@ -0,0 +1,221 @@
+// ABOUTME: Weekly pipeline snapshots for trend charts
+// ABOUTME: Records deal count, amount, and expected value per stage once a week and backfills past weeks from revisions
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StageTotals is the size of one pipeline stage.
+type StageTotals struct {
+	Count    int   `json:"count"`
+	Amount   int64 `json:"amount"`   // in cents
+	Expected int64 `json:"expected"` // amount × win probability, in cents
+}
+
+// PipelineSnapshot is the pipeline per stage in one week.
+type PipelineSnapshot struct {
+	Week    string                 `json:"week"` // Monday the week starts on, YYYY-MM-DD
+	TakenAt time.Time              `json:"taken_at"`
+	Stages  map[string]StageTotals `json:"stages"`
+
+	// Backfilled snapshots were rebuilt from revision history rather than taken that week
+	Backfilled bool `json:"backfilled,omitempty"`
+}
+
+// WeekOf returns the Monday starting t's week, as YYYY-MM-DD.
+func WeekOf(t time.Time) string {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	return t.AddDate(0, 0, -offset).Format("2006-01-02")
+}
+
+// WeekStart returns midnight on the Monday of a snapshot's week, in local time.
+func (s *PipelineSnapshot) WeekStart() time.Time {
+	t, _ := time.ParseInLocation("2006-01-02", s.Week, time.Local)
+	return t
+}
+
+// Open returns the totals across stages that aren't closed.
+func (s *PipelineSnapshot) Open() StageTotals {
+	var open StageTotals
+	for stage, totals := range s.Stages {
+		if stage == StageClosedWon || stage == StageClosedLost {
+			continue
+		}
+		open.Count += totals.Count
+		open.Amount += totals.Amount
+		open.Expected += totals.Expected
+	}
+	return open
+}
+
+// NewPipelineSnapshot totals deals per stage.
+func NewPipelineSnapshot(deals []*Deal, takenAt time.Time) *PipelineSnapshot {
+	snapshot := &PipelineSnapshot{
+		Week:    WeekOf(takenAt),
+		TakenAt: takenAt,
+		Stages:  make(map[string]StageTotals),
+	}
+	for _, deal := range deals {
+		stage := deal.Stage
+		if stage == "" {
+			stage = "unknown"
+		}
+		totals := snapshot.Stages[stage]
+		totals.Count++
+		totals.Amount += deal.Amount
+		totals.Expected += deal.ExpectedValue()
+		snapshot.Stages[stage] = totals
+	}
+	return snapshot
+}
+
+// SavePipelineSnapshot stores a snapshot, replacing any other for its week.
+func (c *Client) SavePipelineSnapshot(snapshot *PipelineSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pipeline snapshot: %w", err)
+	}
+	return c.Set(PipelineSnapshotKey(snapshot.Week), data)
+}
+
+// TakePipelineSnapshot snapshots the current pipeline for this week.
+func (c *Client) TakePipelineSnapshot(now time.Time) (*PipelineSnapshot, error) {
+	deals, err := c.ListDeals(&DealFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	snapshot := NewPipelineSnapshot(deals, now)
+	if err := c.SavePipelineSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// EnsureWeeklySnapshot takes this week's snapshot unless one was already
+// taken. It reports whether a snapshot was taken.
+func (c *Client) EnsureWeeklySnapshot(now time.Time) (bool, error) {
+	data, err := c.Get(PipelineSnapshotKey(WeekOf(now)))
+	if err == nil && data != nil {
+		var existing PipelineSnapshot
+		if json.Unmarshal(data, &existing) == nil && !existing.Backfilled {
+			return false, nil
+		}
+	}
+	if _, err := c.TakePipelineSnapshot(now); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListPipelineSnapshots returns the stored snapshots, oldest week first.
+func (c *Client) ListPipelineSnapshots() ([]*PipelineSnapshot, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixSnapshot))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*PipelineSnapshot
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+		var snapshot PipelineSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Week < snapshots[j].Week
+	})
+	return snapshots, nil
+}
+
+// BackfillPipelineSnapshots rebuilds snapshots for the given number of past
+// weeks that have none, from the state at the end of each week (see
+// SnapshotAsOf). It returns how many were added.
+func (c *Client) BackfillPipelineSnapshots(weeks int, now time.Time) (int, error) {
+	existing, err := c.ListPipelineSnapshots()
+	if err != nil {
+		return 0, err
+	}
+	have := make(map[string]bool)
+	for _, snapshot := range existing {
+		have[snapshot.Week] = true
+	}
+
+	thisWeek, _ := time.ParseInLocation("2006-01-02", WeekOf(now), time.Local)
+	added := 0
+	for i := 1; i <= weeks; i++ {
+		start := thisWeek.AddDate(0, 0, -7*i)
+		week := start.Format("2006-01-02")
+		if have[week] {
+			continue
+		}
+
+		end := start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+		state, err := c.SnapshotAsOf(end)
+		if err != nil {
+			return added, err
+		}
+		deals := state.Deals(nil)
+		if len(deals) == 0 {
+			continue
+		}
+
+		snapshot := NewPipelineSnapshot(deals, end)
+		snapshot.Backfilled = true
+		if err := c.SavePipelineSnapshot(snapshot); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Trend periods for grouping snapshots.
+const (
+	TrendWeek    = "week"
+	TrendMonth   = "month"
+	TrendQuarter = "quarter"
+)
+
+// TrendPoint is the last snapshot in a week, month, or quarter.
+type TrendPoint struct {
+	Label    string
+	Snapshot *PipelineSnapshot
+}
+
+// PipelineTrend groups snapshots (oldest first) by period, keeping the last
+// snapshot of each period.
+func PipelineTrend(snapshots []*PipelineSnapshot, period string) ([]TrendPoint, error) {
+	label := func(t time.Time) string { return t.Format("2006-01-02") }
+	switch strings.ToLower(period) {
+	case TrendWeek:
+	case TrendMonth:
+		label = func(t time.Time) string { return t.Format("2006-01") }
+	case TrendQuarter, "":
+		label = func(t time.Time) string { return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1) }
+	default:
+		return nil, Validationf("invalid period: %s (use week, month, or quarter)", period)
+	}
+
+	var points []TrendPoint
+	for _, snapshot := range snapshots {
+		l := label(snapshot.WeekStart())
+		if n := len(points); n > 0 && points[n-1].Label == l {
+			points[n-1].Snapshot = snapshot
+			continue
+		}
+		points = append(points, TrendPoint{Label: l, Snapshot: snapshot})
+	}
+	return points, nil
+}
@@ -0,0 +1,113 @@
+// ABOUTME: Tests for weekly pipeline snapshots
+// ABOUTME: Verifies week keys, stage totals, once-a-week snapshots, period grouping, and backfill
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekOf(t *testing.T) {
+	tests := map[string]string{
+		"2024-06-24": "2024-06-24", // Monday
+		"2024-06-26": "2024-06-24",
+		"2024-06-30": "2024-06-24", // Sunday
+		"2024-07-01": "2024-07-01",
+	}
+	for day, want := range tests {
+		d, _ := time.Parse("2006-01-02", day)
+		if got := WeekOf(d); got != want {
+			t.Errorf("WeekOf(%s) = %s, want %s", day, got, want)
+		}
+	}
+}
+
+func TestNewPipelineSnapshot(t *testing.T) {
+	deals := []*Deal{
+		{Stage: StageProspecting, Amount: 1000},
+		{Stage: StageProspecting, Amount: 3000},
+		{Stage: StageNegotiation, Amount: 10000},
+		{Stage: StageClosedWon, Amount: 5000},
+	}
+	snapshot := NewPipelineSnapshot(deals, time.Date(2024, 6, 26, 12, 0, 0, 0, time.UTC))
+
+	if snapshot.Week != "2024-06-24" {
+		t.Errorf("unexpected week: %s", snapshot.Week)
+	}
+	if got := snapshot.Stages[StageProspecting]; got.Count != 2 || got.Amount != 4000 {
+		t.Errorf("unexpected prospecting totals: %+v", got)
+	}
+	open := snapshot.Open()
+	if open.Count != 3 || open.Amount != 14000 {
+		t.Errorf("expected closed deals left out of open totals, got %+v", open)
+	}
+}
+
+func TestEnsureWeeklySnapshot(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	if err := client.CreateDeal(&Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProposal, Amount: 2000}); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	now := time.Now()
+	taken, err := client.EnsureWeeklySnapshot(now)
+	if err != nil || !taken {
+		t.Fatalf("expected a snapshot to be taken, got %v, %v", taken, err)
+	}
+	if taken, err := client.EnsureWeeklySnapshot(now); err != nil || taken {
+		t.Errorf("expected one snapshot per week, got %v, %v", taken, err)
+	}
+
+	// The deal existed last week too, so it can be backfilled from revisions
+	added, err := client.BackfillPipelineSnapshots(2, now.AddDate(0, 0, 14))
+	if err != nil {
+		t.Fatalf("BackfillPipelineSnapshots failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected 1 backfilled week, got %d", added)
+	}
+
+	snapshots, err := client.ListPipelineSnapshots()
+	if err != nil {
+		t.Fatalf("ListPipelineSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Week > snapshots[1].Week {
+		t.Fatalf("expected 2 snapshots oldest first, got %d", len(snapshots))
+	}
+	if !snapshots[1].Backfilled || snapshots[1].Stages[StageProposal].Amount != 2000 {
+		t.Errorf("unexpected backfilled snapshot: %+v", snapshots[1])
+	}
+}
+
+func TestPipelineTrend(t *testing.T) {
+	snapshots := []*PipelineSnapshot{
+		{Week: "2024-01-08"},
+		{Week: "2024-03-25"},
+		{Week: "2024-04-01"},
+		{Week: "2024-06-24"},
+	}
+
+	points, err := PipelineTrend(snapshots, TrendQuarter)
+	if err != nil {
+		t.Fatalf("PipelineTrend failed: %v", err)
+	}
+	if len(points) != 2 || points[0].Label != "2024-Q1" || points[1].Label != "2024-Q2" {
+		t.Fatalf("unexpected quarters: %+v", points)
+	}
+	if points[0].Snapshot.Week != "2024-03-25" || points[1].Snapshot.Week != "2024-06-24" {
+		t.Errorf("expected the last snapshot of each quarter, got %s and %s", points[0].Snapshot.Week, points[1].Snapshot.Week)
+	}
+
+	if points, _ := PipelineTrend(snapshots, TrendMonth); len(points) != 4 {
+		t.Errorf("expected 4 months, got %d", len(points))
+	}
+	if _, err := PipelineTrend(snapshots, "year"); err == nil {
+		t.Error("expected invalid period to fail")
+	}
+}
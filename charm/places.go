@@ -0,0 +1,369 @@
+// ABOUTME: Meeting places derived from interaction locations
+// ABOUTME: Resolves locations to cities (cached geocodes or address parsing) and aggregates where you meet people
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// Place is the city a meeting location resolves to.
+type Place struct {
+	City    string `json:"city"`
+	Region  string `json:"region,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// Name returns the city with its region or country, e.g. "Portland, OR".
+func (p *Place) Name() string {
+	switch {
+	case p.Region != "":
+		return p.City + ", " + p.Region
+	case p.Country != "":
+		return p.City + ", " + p.Country
+	}
+	return p.City
+}
+
+// PlaceSummary is how often you met people in one city.
+type PlaceSummary struct {
+	Place
+	Meetings int
+	Contacts []string // names, most met first
+	LastMet  time.Time
+}
+
+// MeetingLocation returns where an in-person interaction happened: its
+// Location, or the location calendar imports kept in metadata. Only meetings
+// and events have one.
+func (i *InteractionLog) MeetingLocation() string {
+	if i.InteractionType != InteractionMeeting && i.InteractionType != InteractionEvent {
+		return ""
+	}
+	if location := strings.TrimSpace(i.Location); location != "" {
+		return location
+	}
+	if i.Metadata == "" {
+		return ""
+	}
+	var metadata struct {
+		Location string `json:"location"`
+	}
+	if err := json.Unmarshal([]byte(i.Metadata), &metadata); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(metadata.Location)
+}
+
+// virtualLocationMarkers identify video calls and dial-ins, which have no place.
+var virtualLocationMarkers = []string{
+	"http://", "https://", "zoom", "meet.google", "google meet", "teams", "webex",
+	"hangout", "skype", "whereby", "dial-in", "phone", "virtual", "online", "remote",
+}
+
+// IsVirtualLocation reports whether a location is a video call or phone line.
+func IsVirtualLocation(location string) bool {
+	lower := strings.ToLower(location)
+	for _, marker := range virtualLocationMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// countryNames maps common country spellings in addresses to one name.
+var countryNames = map[string]string{
+	"us": "United States", "usa": "United States", "u.s.a.": "United States",
+	"united states": "United States", "united states of america": "United States",
+	"uk": "United Kingdom", "u.k.": "United Kingdom", "united kingdom": "United Kingdom",
+	"england": "United Kingdom", "scotland": "United Kingdom",
+	"canada": "Canada", "mexico": "Mexico", "brazil": "Brazil",
+	"ireland": "Ireland", "france": "France", "germany": "Germany", "deutschland": "Germany",
+	"spain": "Spain", "portugal": "Portugal", "italy": "Italy", "netherlands": "Netherlands",
+	"belgium": "Belgium", "switzerland": "Switzerland", "austria": "Austria",
+	"sweden": "Sweden", "norway": "Norway", "denmark": "Denmark", "finland": "Finland",
+	"poland": "Poland", "israel": "Israel", "india": "India", "china": "China",
+	"japan": "Japan", "singapore": "Singapore", "australia": "Australia", "new zealand": "New Zealand",
+}
+
+var (
+	// "CA 94103", "NY", "ON M5V 2T6"
+	regionPostalPattern = regexp.MustCompile(`^([A-Z]{2})(?:\s+[A-Z0-9][A-Z0-9 -]{2,9})?$`)
+	// "94103", "SW1A 1AA", "10115"
+	postalPattern = regexp.MustCompile(`^[A-Z0-9]{2,5}(?:[ -][A-Z0-9]{2,4})?$`)
+	// "10115 Berlin", "75001 Paris"
+	leadingPostalPattern = regexp.MustCompile(`^\d{4,6}\s+`)
+	digitPattern         = regexp.MustCompile(`\d`)
+)
+
+// notCityWords mark address parts that name a room or building rather than a city.
+var notCityWords = []string{"room", "floor", "suite", "building", "office", "lobby", "hq", "conference"}
+
+// ParsePlace finds the city in an address-style location ("Blue Bottle,
+// 66 Mint St, San Francisco, CA 94103" or "Soho House, London"). It returns nil
+// for virtual locations and locations without a recognizable city, such as a
+// bare venue or room name.
+func ParsePlace(location string) *Place {
+	if IsVirtualLocation(location) {
+		return nil
+	}
+
+	var parts []string
+	for _, part := range strings.Split(location, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) < 2 {
+		return nil
+	}
+
+	place := &Place{}
+	if country, ok := countryNames[strings.ToLower(parts[len(parts)-1])]; ok {
+		place.Country = country
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 0 {
+		last := parts[len(parts)-1]
+		if m := regionPostalPattern.FindStringSubmatch(last); m != nil {
+			place.Region = m[1]
+			parts = parts[:len(parts)-1]
+		} else if postalPattern.MatchString(last) && digitPattern.MatchString(last) {
+			parts = parts[:len(parts)-1]
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	// A lone city with its country ("Berlin, Germany") is fine; otherwise the
+	// first part is a venue and the city follows it
+	if len(parts) == 1 && place.Country == "" && place.Region == "" {
+		return nil
+	}
+
+	city := leadingPostalPattern.ReplaceAllString(parts[len(parts)-1], "")
+	if digitPattern.MatchString(city) {
+		return nil
+	}
+	lower := strings.ToLower(city)
+	for _, word := range notCityWords {
+		if strings.Contains(lower, word) {
+			return nil
+		}
+	}
+	place.City = city
+	return place
+}
+
+// geocodeCacheKey normalizes a location for the geocode cache.
+func geocodeCacheKey(location string) []byte {
+	return GeocodeKey(strings.ToLower(strings.Join(strings.Fields(location), " ")))
+}
+
+// GetGeocode returns the cached geocode for a location. The bool is false when
+// the location hasn't been geocoded; a cached nil place means the geocoder
+// couldn't find it.
+func (c *Client) GetGeocode(location string) (*Place, bool, error) {
+	data, err := c.Get(geocodeCacheKey(location))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if data == nil {
+		return nil, false, nil
+	}
+
+	var place Place
+	if err := json.Unmarshal(data, &place); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal geocode: %w", err)
+	}
+	if place.City == "" {
+		return nil, true, nil
+	}
+	return &place, true, nil
+}
+
+// SaveGeocode caches a geocoded location; a nil place records that the
+// geocoder couldn't find it so it isn't looked up again.
+func (c *Client) SaveGeocode(location string, place *Place) error {
+	if place == nil {
+		place = &Place{}
+	}
+	data, err := json.Marshal(place)
+	if err != nil {
+		return fmt.Errorf("failed to marshal geocode: %w", err)
+	}
+	return c.Set(geocodeCacheKey(location), data)
+}
+
+// ResolvePlace returns the city for a meeting location, preferring a cached
+// geocode over parsing the address. Returns nil when it can't tell.
+func (c *Client) ResolvePlace(location string) (*Place, error) {
+	if IsVirtualLocation(location) {
+		return nil, nil
+	}
+	place, cached, err := c.GetGeocode(location)
+	if err != nil {
+		return nil, err
+	}
+	if cached && place != nil {
+		return place, nil
+	}
+	return ParsePlace(location), nil
+}
+
+// MeetingLocations returns the distinct non-virtual locations of meetings and events.
+func (c *Client) MeetingLocations() ([]string, error) {
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var locations []string
+	for _, interaction := range interactions {
+		location := interaction.MeetingLocation()
+		if location == "" || IsVirtualLocation(location) || seen[location] {
+			continue
+		}
+		seen[location] = true
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	return locations, nil
+}
+
+// MeetingPlaces groups in-person meetings and events by city, most meetings
+// first. It also returns the locations that couldn't be resolved to a city.
+func (c *Client) MeetingPlaces() ([]*PlaceSummary, []string, error) {
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+
+	resolved := make(map[string]*Place)
+	byCity := make(map[string]*PlaceSummary)
+	contactMeetings := make(map[string]map[uuid.UUID]int)
+	contactNames := make(map[uuid.UUID]string)
+	unresolvedSeen := make(map[string]bool)
+	var unresolved []string
+
+	for _, interaction := range interactions {
+		location := interaction.MeetingLocation()
+		if location == "" || IsVirtualLocation(location) {
+			continue
+		}
+
+		place, ok := resolved[location]
+		if !ok {
+			if place, err = c.ResolvePlace(location); err != nil {
+				return nil, nil, err
+			}
+			resolved[location] = place
+		}
+		if place == nil {
+			if !unresolvedSeen[location] {
+				unresolvedSeen[location] = true
+				unresolved = append(unresolved, location)
+			}
+			continue
+		}
+
+		key := strings.ToLower(place.City)
+		summary, ok := byCity[key]
+		if !ok {
+			summary = &PlaceSummary{Place: *place}
+			byCity[key] = summary
+			contactMeetings[key] = make(map[uuid.UUID]int)
+		}
+		if summary.Region == "" && summary.Country == "" {
+			summary.Region, summary.Country = place.Region, place.Country
+		}
+		summary.Meetings++
+		if interaction.Timestamp.After(summary.LastMet) {
+			summary.LastMet = interaction.Timestamp
+		}
+		contactMeetings[key][interaction.ContactID]++
+		if interaction.ContactName != "" {
+			contactNames[interaction.ContactID] = interaction.ContactName
+		}
+	}
+
+	places := make([]*PlaceSummary, 0, len(byCity))
+	for key, summary := range byCity {
+		counts := contactMeetings[key]
+		ids := make([]uuid.UUID, 0, len(counts))
+		for id := range counts {
+			ids = append(ids, id)
+		}
+		for _, id := range ids {
+			if contactNames[id] == "" {
+				if contact, err := c.GetContact(id); err == nil {
+					contactNames[id] = contact.Name
+				}
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if counts[ids[i]] != counts[ids[j]] {
+				return counts[ids[i]] > counts[ids[j]]
+			}
+			return contactNames[ids[i]] < contactNames[ids[j]]
+		})
+		for _, id := range ids {
+			if name := contactNames[id]; name != "" {
+				summary.Contacts = append(summary.Contacts, name)
+			}
+		}
+		places = append(places, summary)
+	}
+
+	sort.Slice(places, func(i, j int) bool {
+		if places[i].Meetings != places[j].Meetings {
+			return places[i].Meetings > places[j].Meetings
+		}
+		return places[i].City < places[j].City
+	})
+	sort.Strings(unresolved)
+	return places, unresolved, nil
+}
+
+// ContactIDsInCity returns the contacts you've met in person in a city.
+func (c *Client) ContactIDsInCity(city string) (map[uuid.UUID]bool, error) {
+	interactions, err := c.ListInteractionLogs(&InteractionFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+
+	city = strings.TrimSpace(city)
+	resolved := make(map[string]*Place)
+	ids := make(map[uuid.UUID]bool)
+	for _, interaction := range interactions {
+		location := interaction.MeetingLocation()
+		if location == "" {
+			continue
+		}
+		place, ok := resolved[location]
+		if !ok {
+			if place, err = c.ResolvePlace(location); err != nil {
+				return nil, err
+			}
+			resolved[location] = place
+		}
+		if place != nil && strings.EqualFold(place.City, city) {
+			ids[interaction.ContactID] = true
+		}
+	}
+	return ids, nil
+}
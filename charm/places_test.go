@@ -0,0 +1,141 @@
+// ABOUTME: Tests for meeting places
+// ABOUTME: Verifies address parsing, geocode caching, per-city aggregation, and the city contact filter
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePlace(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string // Place.Name(), or "" for nil
+	}{
+		{"Blue Bottle, 66 Mint St, San Francisco, CA 94103", "San Francisco, CA"},
+		{"New York, NY", "New York, NY"},
+		{"Berlin, Germany", "Berlin, Germany"},
+		{"Café Einstein, Unter den Linden 42, 10117 Berlin, Germany", "Berlin, Germany"},
+		{"Soho House, London", "London"},
+		{"Office, 1 Main St, SW1A 1AA, UK", ""},
+		{"Conference Room A", ""},
+		{"Acme HQ, Board Room", ""},
+		{"https://zoom.us/j/123", ""},
+		{"Google Meet", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if place := ParsePlace(tt.location); place != nil {
+			got = place.Name()
+		}
+		if got != tt.want {
+			t.Errorf("ParsePlace(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestMeetingLocation(t *testing.T) {
+	tests := []struct {
+		interaction InteractionLog
+		want        string
+	}{
+		{InteractionLog{InteractionType: InteractionMeeting, Location: "Soho House, London"}, "Soho House, London"},
+		{InteractionLog{InteractionType: InteractionMeeting, Metadata: `{"location":"Room 42, Chicago, IL"}`}, "Room 42, Chicago, IL"},
+		{InteractionLog{InteractionType: InteractionEmail, Location: "Soho House, London"}, ""},
+		{InteractionLog{InteractionType: InteractionMeeting, Metadata: `not json`}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.interaction.MeetingLocation(); got != tt.want {
+			t.Errorf("MeetingLocation() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMeetingPlaces(t *testing.T) {
+	client := NewTestClient(t)
+
+	alice := &Contact{Name: "Alice"}
+	bob := &Contact{Name: "Bob"}
+	carol := &Contact{Name: "Carol"}
+	for _, contact := range []*Contact{alice, bob, carol} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	now := time.Now()
+	meetings := []*InteractionLog{
+		{ContactID: alice.ID, ContactName: "Alice", Location: "Blue Bottle, 66 Mint St, San Francisco, CA 94103", Timestamp: now.AddDate(0, -2, 0)},
+		{ContactID: alice.ID, ContactName: "Alice", Location: "Sightglass, 270 7th St, San Francisco, CA", Timestamp: now.AddDate(0, -1, 0)},
+		{ContactID: bob.ID, ContactName: "Bob", Location: "Ferry Building, San Francisco, CA", Timestamp: now},
+		{ContactID: bob.ID, ContactName: "Bob", Location: "Soho House", Timestamp: now},
+		{ContactID: carol.ID, ContactName: "Carol", Location: "https://zoom.us/j/123", Timestamp: now},
+		{ContactID: carol.ID, ContactName: "Carol", Location: "Soho House, London", Timestamp: now},
+	}
+	for _, meeting := range meetings {
+		meeting.InteractionType = InteractionMeeting
+		if err := client.CreateInteractionLog(meeting); err != nil {
+			t.Fatalf("failed to log interaction: %v", err)
+		}
+	}
+
+	places, unresolved, err := client.MeetingPlaces()
+	if err != nil {
+		t.Fatalf("MeetingPlaces failed: %v", err)
+	}
+	if len(places) != 2 {
+		t.Fatalf("expected 2 places, got %d", len(places))
+	}
+	sf := places[0]
+	if sf.City != "San Francisco" || sf.Meetings != 3 {
+		t.Errorf("expected San Francisco with 3 meetings first, got %s with %d", sf.City, sf.Meetings)
+	}
+	if len(sf.Contacts) != 2 || sf.Contacts[0] != "Alice" {
+		t.Errorf("expected Alice (most met) then Bob, got %v", sf.Contacts)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "Soho House" {
+		t.Errorf("expected Soho House unresolved, got %v", unresolved)
+	}
+
+	// A cached geocode places the bare venue
+	if err := client.SaveGeocode("soho house", &Place{City: "London", Country: "United Kingdom"}); err != nil {
+		t.Fatalf("SaveGeocode failed: %v", err)
+	}
+	places, unresolved, err = client.MeetingPlaces()
+	if err != nil {
+		t.Fatalf("MeetingPlaces failed: %v", err)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("expected no unresolved locations after geocoding, got %v", unresolved)
+	}
+	if places[1].City != "London" || places[1].Meetings != 2 {
+		t.Errorf("expected London with 2 meetings, got %s with %d", places[1].City, places[1].Meetings)
+	}
+
+	// A cached miss is remembered but still unresolved
+	if err := client.SaveGeocode("Nowhere Cafe", nil); err != nil {
+		t.Fatalf("SaveGeocode failed: %v", err)
+	}
+	place, cached, err := client.GetGeocode("Nowhere  Cafe")
+	if err != nil || !cached || place != nil {
+		t.Errorf("expected a cached miss, got place=%v cached=%v err=%v", place, cached, err)
+	}
+
+	contacts, err := client.ListContacts(&ContactFilter{City: "london"})
+	if err != nil {
+		t.Fatalf("ListContacts failed: %v", err)
+	}
+	if len(contacts) != 2 || contacts[0].Name != "Bob" || contacts[1].Name != "Carol" {
+		t.Errorf("expected Bob and Carol met in London, got %d contacts", len(contacts))
+	}
+
+	contacts, err = client.ListContacts(&ContactFilter{City: "Paris"})
+	if err != nil {
+		t.Fatalf("ListContacts failed: %v", err)
+	}
+	if len(contacts) != 0 {
+		t.Errorf("expected no contacts met in Paris, got %d", len(contacts))
+	}
+}
@@ -0,0 +1,49 @@
+// ABOUTME: A company's primary contact, the person to reach there by default
+// ABOUTME: Emails to a company go to them, and the digest and company overview point them out
+
+package charm
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SetPrimaryContact makes contact the company's primary contact, or clears it
+// when contact is nil. The caller saves the company.
+func (c *Company) SetPrimaryContact(contact *Contact) {
+	if contact == nil {
+		c.PrimaryContactID, c.PrimaryContactName = nil, ""
+		return
+	}
+	c.PrimaryContactID, c.PrimaryContactName = &contact.ID, contact.Name
+}
+
+// PrimaryContact returns the company's primary contact, or nil when it has
+// none or the contact can't be loaded.
+func (c *Client) PrimaryContact(company *Company) *Contact {
+	if company.PrimaryContactID == nil {
+		return nil
+	}
+	contact, err := c.GetContact(*company.PrimaryContactID)
+	if err != nil {
+		return nil
+	}
+	return contact
+}
+
+// companiesByPrimaryContact maps each primary contact to the companies they
+// are the primary contact at.
+func (c *Client) companiesByPrimaryContact() (map[uuid.UUID][]*Company, error) {
+	companies, err := c.ListCompanies(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+	byContact := make(map[uuid.UUID][]*Company)
+	for _, company := range companies {
+		if company.PrimaryContactID != nil {
+			byContact[*company.PrimaryContactID] = append(byContact[*company.PrimaryContactID], company)
+		}
+	}
+	return byContact, nil
+}
@@ -0,0 +1,89 @@
+// ABOUTME: Tests for company primary contacts
+// ABOUTME: Verifies setting and clearing one, the rename and delete cascades, and the digest reason
+
+package charm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrimaryContact(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	if got := client.PrimaryContact(company); got != nil {
+		t.Fatalf("expected no primary contact, got %s", got.Name)
+	}
+
+	alice := &Contact{Name: "Alice", CompanyID: &company.ID}
+	if err := client.CreateContact(alice); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	company.SetPrimaryContact(alice)
+	if err := client.UpdateCompany(company); err != nil {
+		t.Fatalf("UpdateCompany failed: %v", err)
+	}
+
+	stored, err := client.GetCompany(company.ID)
+	if err != nil {
+		t.Fatalf("GetCompany failed: %v", err)
+	}
+	if got := client.PrimaryContact(stored); got == nil || got.ID != alice.ID || stored.PrimaryContactName != "Alice" {
+		t.Fatalf("expected Alice as primary contact, got %+v", stored)
+	}
+
+	// Renaming the contact updates the denormalized name
+	if err := client.UpdateContactDenormalizedNames(alice.ID, "Alice Adams"); err != nil {
+		t.Fatalf("UpdateContactDenormalizedNames failed: %v", err)
+	}
+	stored, _ = client.GetCompany(company.ID)
+	if stored.PrimaryContactName != "Alice Adams" {
+		t.Errorf("expected renamed primary contact, got %q", stored.PrimaryContactName)
+	}
+
+	// Deleting the contact clears it
+	if err := client.DeleteContactWithCascade(alice.ID); err != nil {
+		t.Fatalf("DeleteContactWithCascade failed: %v", err)
+	}
+	stored, _ = client.GetCompany(company.ID)
+	if stored.PrimaryContactID != nil || stored.PrimaryContactName != "" {
+		t.Errorf("expected primary contact cleared, got %v %q", stored.PrimaryContactID, stored.PrimaryContactName)
+	}
+}
+
+func TestDigestPrimaryContact(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Pat"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	last := time.Now().AddDate(0, 0, -40)
+	if err := client.SaveContactCadence(&ContactCadence{ContactID: contact.ID, CadenceDays: 30, RelationshipStrength: StrengthMedium, LastInteractionDate: &last}); err != nil {
+		t.Fatalf("SaveContactCadence failed: %v", err)
+	}
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	company.SetPrimaryContact(contact)
+	if err := client.UpdateCompany(company); err != nil {
+		t.Fatalf("UpdateCompany failed: %v", err)
+	}
+
+	items, err := client.GetDigest(0)
+	if err != nil {
+		t.Fatalf("GetDigest failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if want := []string{"primary contact at Acme", "10 days overdue"}; !reflect.DeepEqual(items[0].Reasons, want) {
+		t.Errorf("expected reasons %v, got %v", want, items[0].Reasons)
+	}
+}
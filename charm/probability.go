@@ -0,0 +1,35 @@
+// ABOUTME: Win probability and expected value for deals
+// ABOUTME: Uses a per-deal override when set, otherwise the default for the deal's stage
+
+package charm
+
+// StageProbabilities are the default win probabilities (percent) per stage.
+var StageProbabilities = map[string]int{
+	StageProspecting:   10,
+	StageQualification: 25,
+	StageProposal:      50,
+	StageNegotiation:   75,
+	StageClosedWon:     100,
+	StageClosedLost:    0,
+}
+
+// WinProbability returns the deal's probability override, or the stage default.
+func (d *Deal) WinProbability() int {
+	if d.Probability != nil {
+		return *d.Probability
+	}
+	return StageProbabilities[d.Stage]
+}
+
+// ExpectedValue returns amount × win probability, in cents.
+func (d *Deal) ExpectedValue() int64 {
+	return d.Amount * int64(d.WinProbability()) / 100
+}
+
+// validateDealProbability checks the deal's probability override, which is optional.
+func validateDealProbability(deal *Deal) error {
+	if deal.Probability != nil && (*deal.Probability < 0 || *deal.Probability > 100) {
+		return Validationf("invalid probability: %d (must be 0-100)", *deal.Probability)
+	}
+	return nil
+}
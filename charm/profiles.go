@@ -0,0 +1,166 @@
+// ABOUTME: Profiles keep separate CRMs, such as work and personal, side by side
+// ABOUTME: Each profile has its own Charm KV database, config, and sync tokens; one is current
+
+package charm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// DefaultProfile is the profile pagen used before profiles existed. Its
+	// database, config, and tokens stay where they always were.
+	DefaultProfile = "default"
+
+	profilesDirName    = "profiles"
+	currentProfileFile = "current-profile"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// profileOverride is the profile chosen for this process, see SetProfile.
+var profileOverride string
+
+// ValidateProfileName checks that name can be used as a directory and KV
+// database name.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return Validationf("invalid profile name %q: use up to 32 lowercase letters, digits, dashes, and underscores", name)
+	}
+	return nil
+}
+
+// SetProfile makes this process use profile name instead of the current one,
+// as --profile and PAGEN_PROFILE do. It must run before the first client is
+// created. An empty name goes back to the current profile.
+func SetProfile(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		profileOverride = ""
+		return nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !profileExists(name) {
+		return NotFoundf("profile %q not found; create it with 'pagen profiles create %s'", name, name)
+	}
+	profileOverride = name
+	return nil
+}
+
+// ActiveProfile returns the profile this process uses: the SetProfile
+// override, else the one chosen with pagen profiles switch, else the default.
+func ActiveProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	return CurrentProfile()
+}
+
+// CurrentProfile returns the profile chosen with pagen profiles switch. A
+// missing or unusable choice falls back to the default profile.
+func CurrentProfile() string {
+	data, err := os.ReadFile(filepath.Join(baseDataDir(), currentProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil || !profileExists(name) {
+		return DefaultProfile
+	}
+	return name
+}
+
+// ProfileDataDir returns the directory holding a profile's config and local
+// state. The default profile uses pagen's data directory itself.
+func ProfileDataDir(name string) string {
+	if name == DefaultProfile {
+		return baseDataDir()
+	}
+	return filepath.Join(baseDataDir(), profilesDirName, name)
+}
+
+// ProfileDBName returns the Charm KV database name for a profile, so each
+// profile syncs as its own namespace.
+func ProfileDBName(name string) string {
+	if name == DefaultProfile {
+		return AppName
+	}
+	return AppName + "-" + name
+}
+
+// ListProfiles returns every profile name, sorted, including the default.
+func ListProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(baseDataDir(), profilesDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile && ValidateProfileName(entry.Name()) == nil {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// CreateProfile adds an empty profile. Its config starts from the defaults
+// and its database is created on first use.
+func CreateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if profileExists(name) {
+		return Validationf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(ProfileDataDir(name), 0700); err != nil {
+		return fmt.Errorf("failed to create profile %s: %w", name, err)
+	}
+	return nil
+}
+
+// SwitchProfile makes name the current profile for later runs.
+func SwitchProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !profileExists(name) {
+		return NotFoundf("profile %q not found; create it with 'pagen profiles create %s'", name, name)
+	}
+	if err := os.MkdirAll(baseDataDir(), 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(baseDataDir(), currentProfileFile)
+	if name == DefaultProfile {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to switch profile: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to switch profile: %w", err)
+	}
+	return nil
+}
+
+func profileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(ProfileDataDir(name))
+	return err == nil && info.IsDir()
+}
+
+// baseDataDir is pagen's data directory, shared by all profiles.
+func baseDataDir() string {
+	return filepath.Join(xdg.DataHome, AppName)
+}
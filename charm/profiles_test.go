@@ -0,0 +1,94 @@
+// ABOUTME: Tests for profiles
+// ABOUTME: Verifies creating, listing, and switching profiles, and that each gets its own paths and KV namespace
+
+package charm
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestProfiles(t *testing.T) {
+	// Runs after t.Setenv restores the environment
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+	t.Setenv("CHARM_HOST", "") // NewClient sets it from the config
+	t.Cleanup(func() { _ = SetProfile("") })
+
+	base := filepath.Join(xdg.DataHome, AppName)
+	if ActiveProfile() != DefaultProfile || DataDir() != base {
+		t.Fatalf("expected the default profile in %s, got %s in %s", base, ActiveProfile(), DataDir())
+	}
+	if ProfileDBName(DefaultProfile) != AppName {
+		t.Errorf("the default profile should keep the existing database, got %s", ProfileDBName(DefaultProfile))
+	}
+
+	for _, bad := range []string{"", "Work", "../etc", "a b", strings.Repeat("x", 33)} {
+		if err := CreateProfile(bad); CodeOf(err) != CodeValidation {
+			t.Errorf("CreateProfile(%q): expected a validation error, got %v", bad, err)
+		}
+	}
+	if err := SetProfile("work"); CodeOf(err) != CodeNotFound {
+		t.Errorf("expected a missing profile to be not found, got %v", err)
+	}
+
+	if err := CreateProfile("work"); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	if err := CreateProfile("work"); CodeOf(err) != CodeValidation {
+		t.Errorf("expected creating a profile twice to fail, got %v", err)
+	}
+	if err := CreateProfile("personal"); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles failed: %v", err)
+	}
+	if got := strings.Join(profiles, ","); got != "default,personal,work" {
+		t.Errorf("unexpected profiles %s", got)
+	}
+
+	// Switching is remembered; --profile overrides it for one run
+	if err := SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile failed: %v", err)
+	}
+	if CurrentProfile() != "work" || DataDir() != filepath.Join(base, "profiles", "work") {
+		t.Errorf("expected work to be current, got %s in %s", CurrentProfile(), DataDir())
+	}
+	if err := SetProfile("personal"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	if ActiveProfile() != "personal" || CurrentProfile() != "work" {
+		t.Errorf("expected personal for this run only, got active %s, current %s", ActiveProfile(), CurrentProfile())
+	}
+
+	// Each profile keeps its own config and KV database
+	cfg := DefaultConfig()
+	cfg.WebBaseURL = "https://personal.example.com/"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.Profile() != "personal" || client.dbName != "pagen-personal" || client.Config().WebBaseURL != cfg.WebBaseURL {
+		t.Errorf("unexpected client for personal: profile %s, db %s, base URL %q", client.Profile(), client.dbName, client.Config().WebBaseURL)
+	}
+	_ = SetProfile("")
+	if cfg, _ := LoadConfig(); cfg.WebBaseURL != "" {
+		t.Errorf("the work profile shouldn't see personal's config, got base URL %q", cfg.WebBaseURL)
+	}
+
+	if err := SwitchProfile(DefaultProfile); err != nil {
+		t.Fatalf("SwitchProfile failed: %v", err)
+	}
+	if CurrentProfile() != DefaultProfile || DataDir() != base {
+		t.Errorf("expected to be back on the default profile, got %s", CurrentProfile())
+	}
+}
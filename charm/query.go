@@ -0,0 +1,384 @@
+// ABOUTME: Mini query language for search and list queries
+// ABOUTME: Parses field operators like company:acme tag:vip last_contacted:<90d into filter fields
+
+package charm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query fields. A term is written field:value, optionally with a comparison
+// (<, <=, >, >=) after the colon; values with spaces go in double quotes.
+const (
+	QueryFieldCompany       = "company"        // contacts, deals: company name contains
+	QueryFieldContact       = "contact"        // deals: primary contact name contains
+	QueryFieldTag           = "tag"            // contacts, companies, deals
+	QueryFieldEmail         = "email"          // contacts: email contains
+	QueryFieldCity          = "city"           // contacts: met in person in this city
+	QueryFieldMet           = "met"            // contacts: met date
+	QueryFieldLastContacted = "last_contacted" // contacts: last contact date
+	QueryFieldIndustry      = "industry"       // companies
+	QueryFieldFunding       = "funding"        // companies: funding stage
+	QueryFieldHQ            = "hq"             // companies: headquarters contains
+	QueryFieldEmployees     = "employees"      // companies: headcount
+	QueryFieldStage         = "stage"          // deals
+	QueryFieldSource        = "source"         // deals
+	QueryFieldAmount        = "amount"         // deals: amount in whole currency units
+	QueryFieldLastActivity  = "last_activity"  // deals: last activity date
+	QueryFieldClose         = "close"          // deals: expected close date
+)
+
+// ErrInvalidQuery wraps every query syntax and field value error, so callers
+// can report them as bad input.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// queryFields lists the fields each entity understands. Terms on any other
+// known field match nothing for that entity.
+var queryFields = map[string]map[string]bool{
+	EntityContact: {QueryFieldCompany: true, QueryFieldTag: true, QueryFieldEmail: true, QueryFieldCity: true, QueryFieldMet: true, QueryFieldLastContacted: true},
+	EntityCompany: {QueryFieldTag: true, QueryFieldIndustry: true, QueryFieldFunding: true, QueryFieldHQ: true, QueryFieldEmployees: true},
+	EntityDeal:    {QueryFieldCompany: true, QueryFieldContact: true, QueryFieldTag: true, QueryFieldStage: true, QueryFieldSource: true, QueryFieldAmount: true, QueryFieldLastActivity: true, QueryFieldClose: true},
+}
+
+// isQueryField reports whether any entity understands the field. Unknown
+// prefixes such as "http:" stay part of the free text.
+func isQueryField(field string) bool {
+	for _, fields := range queryFields {
+		if fields[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryTerm is one field:value term. Op is "", "<", "<=", ">", or ">=".
+type QueryTerm struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// String formats the term so that ParseSearchQuery reads it back.
+func (t QueryTerm) String() string {
+	value := t.Value
+	if strings.ContainsAny(value, " \t\"") {
+		value = strconv.Quote(value)
+	}
+	return t.Field + ":" + t.Op + value
+}
+
+// SearchQuery is a parsed query: free text plus field terms.
+type SearchQuery struct {
+	Text  string
+	Terms []QueryTerm
+}
+
+// Filters returns the field terms alone, as query text.
+func (q *SearchQuery) Filters() string {
+	parts := make([]string, len(q.Terms))
+	for i, term := range q.Terms {
+		parts[i] = term.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseSearchQuery splits a query into free text and field terms, e.g.
+// `company:acme tag:vip last_contacted:<90d pilot` has the text "pilot".
+func ParseSearchQuery(input string) (*SearchQuery, error) {
+	tokens, err := splitQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &SearchQuery{}
+	var text []string
+	for _, token := range tokens {
+		field, rest, ok := strings.Cut(token, ":")
+		field = strings.ToLower(field)
+		if !ok || !isQueryField(field) {
+			text = append(text, strings.ReplaceAll(token, `"`, ""))
+			continue
+		}
+
+		term := QueryTerm{Field: field}
+		for _, op := range []string{"<=", ">=", "<", ">", "="} {
+			if strings.HasPrefix(rest, op) {
+				term.Op, rest = strings.TrimPrefix(op, "="), rest[len(op):]
+				break
+			}
+		}
+		term.Value = strings.TrimSpace(strings.ReplaceAll(rest, `"`, ""))
+		if term.Value == "" {
+			return nil, fmt.Errorf("%w: missing value for %s:", ErrInvalidQuery, field)
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// splitQuery splits on whitespace outside double quotes.
+func splitQuery(input string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidQuery, input)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// parseFilterQuery parses a filter's Query for the entity. It returns nil
+// when the query has no field terms, so it is matched as plain text, and
+// ok false when a term names a field the entity doesn't have.
+func parseFilterQuery(entity, query string) (q *SearchQuery, ok bool, err error) {
+	if !strings.Contains(query, ":") {
+		return nil, true, nil
+	}
+	q, err = ParseSearchQuery(query)
+	if err != nil || len(q.Terms) == 0 {
+		return nil, true, err
+	}
+	for _, term := range q.Terms {
+		if !queryFields[entity][term.Field] {
+			return q, false, nil
+		}
+	}
+	return q, true, nil
+}
+
+// withQueryTerms returns a copy of the filter with the field terms in its
+// Query moved into filter fields. ok is false when no contact can match.
+func (f *ContactFilter) withQueryTerms(now time.Time) (*ContactFilter, bool, error) {
+	if f == nil {
+		return nil, true, nil
+	}
+	q, ok, err := parseFilterQuery(EntityContact, f.Query)
+	if err != nil || q == nil || !ok {
+		return f, ok, err
+	}
+
+	out := *f
+	out.Query = q.Text
+	for _, term := range q.Terms {
+		switch term.Field {
+		case QueryFieldCompany:
+			out.CompanyName = term.Value
+		case QueryFieldTag:
+			out.Tag = term.Value
+		case QueryFieldEmail:
+			out.Email = term.Value
+		case QueryFieldCity:
+			out.City = term.Value
+		case QueryFieldMet:
+			if out.MetFrom, out.MetTo, err = queryTimeRange(term, now); err != nil {
+				return nil, false, err
+			}
+		case QueryFieldLastContacted:
+			if out.LastContactedFrom, out.LastContactedTo, err = queryTimeRange(term, now); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return &out, true, nil
+}
+
+// withQueryTerms returns a copy of the filter with the field terms in its
+// Query moved into filter fields. ok is false when no company can match.
+func (f *CompanyFilter) withQueryTerms() (*CompanyFilter, bool, error) {
+	if f == nil {
+		return nil, true, nil
+	}
+	q, ok, err := parseFilterQuery(EntityCompany, f.Query)
+	if err != nil || q == nil || !ok {
+		return f, ok, err
+	}
+
+	out := *f
+	out.Query = q.Text
+	for _, term := range q.Terms {
+		switch term.Field {
+		case QueryFieldTag:
+			out.Tag = term.Value
+		case QueryFieldIndustry:
+			out.Industry = term.Value
+		case QueryFieldFunding:
+			out.FundingStage = term.Value
+		case QueryFieldHQ:
+			out.HQ = term.Value
+		case QueryFieldEmployees:
+			n, err := strconv.Atoi(strings.ReplaceAll(term.Value, ",", ""))
+			if err != nil || n < 0 {
+				return nil, false, fmt.Errorf("%w: employees value %q is not a number", ErrInvalidQuery, term.Value)
+			}
+			min, max := queryNumberRange(term.Op, int64(n))
+			if max < 0 {
+				return &out, false, nil
+			}
+			out.MinEmployees, out.MaxEmployees = int(min), int(max)
+		}
+	}
+	return &out, true, nil
+}
+
+// withQueryTerms returns a copy of the filter with the field terms in its
+// Query moved into filter fields. ok is false when no deal can match.
+func (f *DealFilter) withQueryTerms(now time.Time) (*DealFilter, bool, error) {
+	if f == nil {
+		return nil, true, nil
+	}
+	q, ok, err := parseFilterQuery(EntityDeal, f.Query)
+	if err != nil || q == nil || !ok {
+		return f, ok, err
+	}
+
+	out := *f
+	out.Query = q.Text
+	for _, term := range q.Terms {
+		switch term.Field {
+		case QueryFieldCompany:
+			out.CompanyName = term.Value
+		case QueryFieldContact:
+			out.ContactName = term.Value
+		case QueryFieldTag:
+			out.Tag = term.Value
+		case QueryFieldStage:
+			out.Stage = strings.ReplaceAll(strings.ToLower(term.Value), " ", "_")
+		case QueryFieldSource:
+			out.Source = term.Value
+		case QueryFieldAmount:
+			cents, err := queryAmount(term.Value)
+			if err != nil {
+				return nil, false, err
+			}
+			min, max := queryNumberRange(term.Op, cents)
+			if max < 0 {
+				return &out, false, nil
+			}
+			out.MinAmount, out.MaxAmount = min, max
+		case QueryFieldLastActivity:
+			if out.ActivityFrom, out.ActivityTo, err = queryTimeRange(term, now); err != nil {
+				return nil, false, err
+			}
+		case QueryFieldClose:
+			if out.CloseFrom, out.CloseTo, err = queryTimeRange(term, now); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return &out, true, nil
+}
+
+// queryNumberRange turns a comparison into inclusive bounds, where 0 means
+// unbounded as in the filters. max is -1 when nothing can match.
+func queryNumberRange(op string, n int64) (min, max int64) {
+	switch op {
+	case "<":
+		if n <= 1 {
+			return 0, -1 // at most zero, which the filters read as unbounded
+		}
+		return 0, n - 1
+	case "<=":
+		if n == 0 {
+			return 0, -1
+		}
+		return 0, n
+	case ">":
+		return n + 1, 0
+	case ">=":
+		return n, 0
+	default:
+		if n == 0 {
+			return 0, -1
+		}
+		return n, n
+	}
+}
+
+// queryAmount parses an amount in whole currency units, with an optional
+// currency sign, thousands separators, and a k or m suffix, into cents.
+func queryAmount(value string) (int64, error) {
+	v := strings.ToLower(strings.TrimLeft(strings.ReplaceAll(value, ",", ""), "$€£"))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(v, "k"):
+		multiplier, v = 1e3, strings.TrimSuffix(v, "k")
+	case strings.HasSuffix(v, "m"):
+		multiplier, v = 1e6, strings.TrimSuffix(v, "m")
+	}
+	amount, err := strconv.ParseFloat(v, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("%w: amount %q is not a number", ErrInvalidQuery, value)
+	}
+	return int64(math.Round(amount * multiplier * 100)), nil
+}
+
+var queryAgePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// queryTimeRange turns a time term into the range [from, to) it selects.
+// Values are either ages (90d, 6w, 3m, 1y), where <90d means within the last
+// 90 days, or dates (2024-01-31), compared by day.
+func queryTimeRange(term QueryTerm, now time.Time) (from, to *time.Time, err error) {
+	if m := queryAgePattern.FindStringSubmatch(strings.ToLower(term.Value)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		var cutoff time.Time
+		switch m[2] {
+		case "d":
+			cutoff = now.AddDate(0, 0, -n)
+		case "w":
+			cutoff = now.AddDate(0, 0, -7*n)
+		case "m":
+			cutoff = now.AddDate(0, -n, 0)
+		case "y":
+			cutoff = now.AddDate(-n, 0, 0)
+		}
+		switch term.Op {
+		case "", "<", "<=":
+			return &cutoff, nil, nil
+		default:
+			return nil, &cutoff, nil
+		}
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", term.Value, now.Location())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s value %q is not an age like 90d or a date like 2006-01-02", ErrInvalidQuery, term.Field, term.Value)
+	}
+	next := day.AddDate(0, 0, 1)
+	switch term.Op {
+	case "<":
+		return nil, &day, nil
+	case "<=":
+		return nil, &next, nil
+	case ">":
+		return &next, nil, nil
+	case ">=":
+		return &day, nil, nil
+	default:
+		return &day, &next, nil
+	}
+}
@@ -0,0 +1,121 @@
+// ABOUTME: Tests for the search query language
+// ABOUTME: Verifies parsing, field terms on contacts, companies, and deals, and bad values
+
+package charm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q, err := ParseSearchQuery(`acme Tag:vip company:"Big Co" last_contacted:<90d amount:>=10k see http://x.io`)
+	if err != nil {
+		t.Fatalf("ParseSearchQuery failed: %v", err)
+	}
+	if q.Text != "acme see http://x.io" {
+		t.Errorf("unexpected text %q", q.Text)
+	}
+	want := []QueryTerm{
+		{Field: "tag", Value: "vip"},
+		{Field: "company", Value: "Big Co"},
+		{Field: "last_contacted", Op: "<", Value: "90d"},
+		{Field: "amount", Op: ">=", Value: "10k"},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("expected terms %+v, got %+v", want, q.Terms)
+	}
+	for i := range want {
+		if q.Terms[i] != want[i] {
+			t.Errorf("term %d: expected %+v, got %+v", i, want[i], q.Terms[i])
+		}
+	}
+	if got := q.Filters(); got != `tag:vip company:"Big Co" last_contacted:<90d amount:>=10k` {
+		t.Errorf("unexpected filters %q", got)
+	}
+
+	for _, bad := range []string{`company:"Acme`, "tag:"} {
+		if _, err := ParseSearchQuery(bad); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("ParseSearchQuery(%q): expected ErrInvalidQuery, got %v", bad, err)
+		}
+	}
+}
+
+func TestListWithQueryTerms(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+	recent, old := now.AddDate(0, 0, -10), now.AddDate(0, -6, 0)
+
+	acme := &Company{Name: "Acme", Industry: "SaaS", EmployeeCount: 120}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	if err := client.CreateCompany(&Company{Name: "Globex", Industry: "Energy", EmployeeCount: 20}); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	for _, contact := range []*Contact{
+		{Name: "Ada", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"vip"}, LastContactedAt: &recent},
+		{Name: "Bea", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"vip"}, LastContactedAt: &old},
+		{Name: "Cy", Tags: []string{"vip"}},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	for _, deal := range []*Deal{
+		{Title: "Pilot", CompanyID: acme.ID, CompanyName: "Acme", Stage: StageNegotiation, Amount: 2500000},
+		{Title: "Renewal", CompanyID: acme.ID, CompanyName: "Acme", Stage: StageProposal, Amount: 500000},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	contactNames := func(query string) string {
+		t.Helper()
+		contacts, err := client.ListContacts(&ContactFilter{Query: query})
+		if err != nil {
+			t.Fatalf("ListContacts(%q) failed: %v", query, err)
+		}
+		var names []string
+		for _, c := range contacts {
+			names = append(names, c.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	for query, want := range map[string]string{
+		"company:acme tag:vip last_contacted:<90d": "Ada",
+		"tag:vip last_contacted:>90d":              "Bea,Cy", // never contacted counts as not recently
+		"company:acme bea":                         "Bea",
+		"stage:negotiation":                        "", // a deal field matches no contacts
+	} {
+		if got := contactNames(query); got != want {
+			t.Errorf("contacts %q: expected %q, got %q", query, want, got)
+		}
+	}
+
+	companies, err := client.ListCompanies(&CompanyFilter{Query: "industry:saas employees:>100"})
+	if err != nil || len(companies) != 1 || companies[0].Name != "Acme" {
+		t.Errorf("expected only Acme, got %v (%v)", companies, err)
+	}
+
+	deals, err := client.ListDeals(&DealFilter{Query: "company:acme stage:negotiation amount:>10k last_activity:<1d"})
+	if err != nil || len(deals) != 1 || deals[0].Title != "Pilot" {
+		t.Errorf("expected only Pilot, got %v (%v)", deals, err)
+	}
+	if deals, _ := client.ListDeals(&DealFilter{Query: "amount:<=5000"}); len(deals) != 1 || deals[0].Title != "Renewal" {
+		t.Errorf("expected only Renewal, got %v", deals)
+	}
+
+	if _, err := client.ListContacts(&ContactFilter{Query: "last_contacted:<soon"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for a bad age, got %v", err)
+	}
+	if _, err := client.ListCompanies(&CompanyFilter{Query: "employees:many"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for a bad headcount, got %v", err)
+	}
+	if _, err := client.ListDeals(&DealFilter{Query: "amount:lots"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery for a bad amount, got %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+// ABOUTME: Short ID display and ID references
+// ABOUTME: Resolves full IDs, unique git-style ID prefixes, and @name references to a record ID
+
+package charm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// EntityEvent is the reference kind for events.
+const EntityEvent = "event"
+
+// MinIDPrefix is the shortest ID prefix accepted in place of a full ID.
+const MinIDPrefix = 4
+
+// ShortIDLength is how many characters of an ID are shown when IDs display short.
+const ShortIDLength = 8
+
+// ID display modes.
+const (
+	IDDisplayShort = "short"
+	IDDisplayFull  = "full"
+)
+
+// refPrefixes are the key prefixes searched for each reference kind.
+var refPrefixes = map[string]string{
+	EntityContact:      PrefixContact,
+	EntityCompany:      PrefixCompany,
+	EntityDeal:         PrefixDeal,
+	EntityRelationship: PrefixRelationship,
+	EntityEvent:        PrefixEvent,
+	EntityTask:         PrefixTask,
+}
+
+// currentIDDisplay is the process-wide ID display mode.
+var currentIDDisplay = detectIDDisplay()
+
+// SetIDDisplay sets how IDs are shown in lists: "short" (default) or "full".
+// Unknown modes fall back to short.
+func SetIDDisplay(mode string) {
+	currentIDDisplay = lookupIDDisplay(mode)
+}
+
+// detectIDDisplay reads the display mode from PAGEN_ID_DISPLAY.
+func detectIDDisplay() string {
+	return lookupIDDisplay(os.Getenv("PAGEN_ID_DISPLAY"))
+}
+
+func lookupIDDisplay(mode string) string {
+	if strings.EqualFold(strings.TrimSpace(mode), IDDisplayFull) {
+		return IDDisplayFull
+	}
+	return IDDisplayShort
+}
+
+// FormatID formats an ID for display in the configured mode. Short IDs are
+// accepted back as prefixes wherever an ID is expected.
+func FormatID(id uuid.UUID) string {
+	if currentIDDisplay == IDDisplayFull {
+		return id.String()
+	}
+	return id.String()[:ShortIDLength]
+}
+
+// IsIDPrefix reports whether ref could be an ID prefix: at least MinIDPrefix
+// hex digits, optionally with the dashes of the full form.
+func IsIDPrefix(ref string) bool {
+	if len(ref) < MinIDPrefix || len(ref) > 36 {
+		return false
+	}
+	for _, r := range strings.ToLower(ref) {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNameRef reports whether ref is an @name reference.
+func IsNameRef(ref string) bool {
+	return strings.HasPrefix(ref, "@") && len(strings.TrimSpace(ref)) > 1
+}
+
+// ResolveRef turns a reference into the ID of a record of one of the given
+// kinds (EntityContact, EntityCompany, EntityDeal, EntityRelationship,
+// EntityEvent, EntityTask; all but tasks when none are given). A reference is
+// a full ID, a unique ID prefix of at least MinIDPrefix characters, or "@name"
+// for a contact, company, or event name or a deal or task title. It returns
+// uuid.Nil when nothing matches, and an *AmbiguousMatchError listing the
+// candidates when more than one record does.
+func (c *Client) ResolveRef(ref string, kinds ...string) (uuid.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	if len(kinds) == 0 {
+		kinds = []string{EntityContact, EntityCompany, EntityDeal, EntityRelationship, EntityEvent}
+	}
+
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+
+	var matches []MatchCandidate
+	var err error
+	switch {
+	case IsNameRef(ref):
+		matches, err = c.matchNameRef(strings.TrimSpace(ref[1:]), kinds)
+	case IsIDPrefix(ref):
+		matches, err = c.matchIDPrefix(strings.ToLower(ref), kinds)
+	default:
+		return uuid.Nil, Validationf("invalid ID %q (use a full ID, an ID prefix of at least %d characters, or @name)", ref, MinIDPrefix)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, nil
+	case 1:
+		return matches[0].ID, nil
+	}
+	if len(matches) > maxCandidates {
+		matches = matches[:maxCandidates]
+	}
+	entityType := "record"
+	if len(kinds) == 1 {
+		entityType = kinds[0]
+	}
+	return uuid.Nil, &AmbiguousMatchError{EntityType: entityType, Ref: ref, Candidates: matches}
+}
+
+// matchIDPrefix finds records of the given kinds whose ID starts with prefix.
+func (c *Client) matchIDPrefix(prefix string, kinds []string) ([]MatchCandidate, error) {
+	var matches []MatchCandidate
+	for _, kind := range kinds {
+		keyPrefix, ok := refPrefixes[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown reference kind: %s", kind)
+		}
+		keys, err := c.KeysWithPrefix([]byte(keyPrefix + prefix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up ID prefix: %w", err)
+		}
+		for _, key := range keys {
+			id, err := uuid.Parse(strings.TrimPrefix(string(key), keyPrefix))
+			if err != nil {
+				continue
+			}
+			matches = append(matches, c.describeRef(kind, id))
+		}
+	}
+	return matches, nil
+}
+
+// matchNameRef finds records of the given kinds named name (ignoring case):
+// contacts and companies by name (contacts also by email), deals and tasks by
+// title, and events by name. Exact matches win; without any, records whose name
+// contains name are the candidates.
+func (c *Client) matchNameRef(name string, kinds []string) ([]MatchCandidate, error) {
+	var exact, partial []MatchCandidate
+	add := func(candidate MatchCandidate, names ...string) {
+		for _, n := range names {
+			if strings.EqualFold(n, name) {
+				exact = append(exact, candidate)
+				return
+			}
+		}
+		for _, n := range names {
+			if strings.Contains(strings.ToLower(n), strings.ToLower(name)) {
+				partial = append(partial, candidate)
+				return
+			}
+		}
+	}
+
+	for _, kind := range kinds {
+		switch kind {
+		case EntityContact:
+			contacts, err := c.ListContacts(&ContactFilter{Query: name})
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup contact: %w", err)
+			}
+			for _, contact := range contacts {
+				add(ContactCandidate(contact), contact.Name, contact.Email)
+			}
+		case EntityCompany:
+			companies, err := c.ListCompanies(&CompanyFilter{Query: name})
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup company: %w", err)
+			}
+			for _, company := range companies {
+				add(MatchCandidate{ID: company.ID, Name: company.Name, Detail: company.Domain}, company.Name)
+			}
+		case EntityDeal:
+			deals, err := c.ListDeals(&DealFilter{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup deal: %w", err)
+			}
+			for _, deal := range deals {
+				add(MatchCandidate{ID: deal.ID, Name: deal.Title, Detail: deal.CompanyName}, deal.Title)
+			}
+		case EntityEvent:
+			events, err := c.ListEvents()
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup event: %w", err)
+			}
+			for _, event := range events {
+				add(MatchCandidate{ID: event.ID, Name: event.Name, Detail: event.Date.Format("2006-01-02")}, event.Name)
+			}
+		case EntityTask:
+			tasks, err := c.ListTasks(true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup task: %w", err)
+			}
+			for _, task := range tasks {
+				add(MatchCandidate{ID: task.ID, Name: task.Title, Detail: task.ContactName}, task.Title)
+			}
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact, nil
+	}
+	return partial, nil
+}
+
+// describeRef names a record for disambiguation, falling back to its kind.
+func (c *Client) describeRef(kind string, id uuid.UUID) MatchCandidate {
+	candidate := MatchCandidate{ID: id, Name: kind}
+	switch kind {
+	case EntityContact:
+		if contact, err := c.GetContact(id); err == nil && contact != nil {
+			return ContactCandidate(contact)
+		}
+	case EntityCompany:
+		if company, err := c.GetCompany(id); err == nil && company != nil {
+			candidate.Name, candidate.Detail = company.Name, company.Domain
+		}
+	case EntityDeal:
+		if deal, err := c.GetDeal(id); err == nil && deal != nil {
+			candidate.Name, candidate.Detail = deal.Title, deal.CompanyName
+		}
+	case EntityRelationship:
+		if rel, err := c.GetRelationship(id); err == nil && rel != nil {
+			candidate.Detail = rel.Contact1Name + " & " + rel.Contact2Name
+		}
+	case EntityEvent:
+		if event, err := c.GetEvent(id); err == nil && event != nil {
+			candidate.Name, candidate.Detail = event.Name, event.Date.Format("2006-01-02")
+		}
+	case EntityTask:
+		if task, err := c.GetTask(id); err == nil && task != nil {
+			candidate.Name, candidate.Detail = task.Title, task.ContactName
+		}
+	}
+	return candidate
+}
+
+// ResolveObjectRef resolves a reference to a contact, company, deal, or
+// relationship, including deleted ones that still have revisions, for
+// commands that work on revision history.
+func (c *Client) ResolveObjectRef(ref string) (uuid.UUID, error) {
+	id, err := c.ResolveRef(ref, EntityContact, EntityCompany, EntityDeal, EntityRelationship)
+	if err != nil || id != uuid.Nil || !IsIDPrefix(strings.TrimSpace(ref)) {
+		return id, err
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(ref))
+	keys, err := c.KeysWithPrefix([]byte(PrefixRevision + prefix))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to look up ID prefix: %w", err)
+	}
+	seen := make(map[uuid.UUID]bool)
+	var matches []MatchCandidate
+	for _, key := range keys {
+		objectID, _, _ := strings.Cut(strings.TrimPrefix(string(key), PrefixRevision), ":")
+		id, err := uuid.Parse(objectID)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		matches = append(matches, MatchCandidate{ID: id, Name: "deleted object"})
+	}
+
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, nil
+	case 1:
+		return matches[0].ID, nil
+	}
+	if len(matches) > maxCandidates {
+		matches = matches[:maxCandidates]
+	}
+	return uuid.Nil, &AmbiguousMatchError{EntityType: "record", Ref: ref, Candidates: matches}
+}
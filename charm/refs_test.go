@@ -0,0 +1,105 @@
+// ABOUTME: Tests for short ID display and ID references
+// ABOUTME: Verifies full IDs, unique and ambiguous prefixes, @name lookups, and deleted objects
+package charm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFormatID(t *testing.T) {
+	id := uuid.MustParse("0f3a9c2e-1111-4222-8333-444455556666")
+	defer SetIDDisplay(IDDisplayShort)
+
+	SetIDDisplay(IDDisplayShort)
+	if got := FormatID(id); got != "0f3a9c2e" {
+		t.Errorf("unexpected short ID: %s", got)
+	}
+	SetIDDisplay("FULL")
+	if got := FormatID(id); got != id.String() {
+		t.Errorf("unexpected full ID: %s", got)
+	}
+}
+
+func TestResolveRef(t *testing.T) {
+	client := NewTestClient(t)
+
+	ada := &Contact{ID: uuid.MustParse("abcd1111-0000-4000-8000-000000000001"), Name: "Ada Lovelace", Email: "ada@example.com"}
+	alan := &Contact{ID: uuid.MustParse("abcd2222-0000-4000-8000-000000000002"), Name: "Alan Turing"}
+	for _, contact := range []*Contact{ada, alan} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	acme := &Company{ID: uuid.MustParse("abcd3333-0000-4000-8000-000000000003"), Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+
+	tests := []struct {
+		ref   string
+		kinds []string
+		want  uuid.UUID
+	}{
+		{ada.ID.String(), nil, ada.ID},
+		{"ABCD1111", []string{EntityContact}, ada.ID},
+		{"abcd2222-0000", []string{EntityContact}, alan.ID},
+		{"abcd", []string{EntityCompany}, acme.ID},
+		{"@ada lovelace", []string{EntityContact}, ada.ID},
+		{"@alan", []string{EntityContact}, alan.ID}, // the only partial match
+		{"@Acme", nil, acme.ID},
+		{"beef", []string{EntityContact}, uuid.Nil},
+		{"@nobody", []string{EntityContact}, uuid.Nil},
+	}
+	for _, tt := range tests {
+		got, err := client.ResolveRef(tt.ref, tt.kinds...)
+		if err != nil {
+			t.Errorf("ResolveRef(%q) failed: %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveRef(%q) = %s, want %s", tt.ref, got, tt.want)
+		}
+	}
+
+	var ambiguous *AmbiguousMatchError
+	if _, err := client.ResolveRef("abcd", EntityContact); !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Errorf("expected an ambiguous prefix with 2 candidates, got %v", err)
+	} else if ambiguous.Candidates[0].Name == EntityContact {
+		t.Errorf("expected candidates to be named, got %+v", ambiguous.Candidates)
+	}
+	if _, err := client.ResolveRef("abcd"); !errors.As(err, &ambiguous) || ambiguous.EntityType != "record" {
+		t.Errorf("expected an ambiguous prefix across kinds, got %v", err)
+	}
+	if _, err := client.ResolveRef("@a", EntityContact); !errors.As(err, &ambiguous) {
+		t.Errorf("expected @a to be ambiguous, got %v", err)
+	}
+	if _, err := client.ResolveRef("abc", EntityContact); err == nil {
+		t.Error("expected a too-short prefix to be invalid")
+	}
+}
+
+func TestResolveObjectRefDeleted(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{ID: uuid.MustParse("feed1111-0000-4000-8000-000000000001"), Name: "Gone"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	if err := client.DeleteContact(contact.ID); err != nil {
+		t.Fatalf("DeleteContact failed: %v", err)
+	}
+
+	if id, err := client.ResolveRef("feed1111", EntityContact); err != nil || id != uuid.Nil {
+		t.Errorf("expected a deleted contact to be gone from live lookups, got %s, %v", id, err)
+	}
+	id, err := client.ResolveObjectRef("feed1111")
+	if err != nil {
+		t.Fatalf("ResolveObjectRef failed: %v", err)
+	}
+	if id != contact.ID {
+		t.Errorf("expected the deleted contact from its revisions, got %s", id)
+	}
+}
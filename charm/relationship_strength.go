@@ -0,0 +1,169 @@
+// ABOUTME: Strength and revalidation of relationships between contacts
+// ABOUTME: Strength fades a level per unconfirmed period, and stale links are queued for the digest to re-check
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRelationshipRevalidateDays is how long a relationship goes
+// unconfirmed before it is stale.
+const DefaultRelationshipRevalidateDays = 180
+
+// relationshipStrengths orders strengths from weakest to strongest.
+var relationshipStrengths = []string{StrengthWeak, StrengthMedium, StrengthStrong}
+
+// RelationshipRevalidateDays returns the configured revalidation period, or
+// DefaultRelationshipRevalidateDays.
+func RelationshipRevalidateDays(cfg *Config) int {
+	if cfg == nil || cfg.RelationshipRevalidateDays <= 0 {
+		return DefaultRelationshipRevalidateDays
+	}
+	return cfg.RelationshipRevalidateDays
+}
+
+// ValidateRelationshipStrength checks a strength name. Empty is allowed and
+// means untracked.
+func ValidateRelationshipStrength(strength string) error {
+	if strength == "" {
+		return nil
+	}
+	for _, valid := range relationshipStrengths {
+		if strength == valid {
+			return nil
+		}
+	}
+	return Validationf("invalid relationship strength: %s (valid: %s)", strength, strings.Join(relationshipStrengths, ", "))
+}
+
+// ValidatedAt is when the relationship was last confirmed, or when it was
+// created if it never has been.
+func (r *Relationship) ValidatedAt() time.Time {
+	if r.LastValidatedAt != nil {
+		return *r.LastValidatedAt
+	}
+	return r.CreatedAt
+}
+
+// IsStale reports whether the relationship has gone days or more unconfirmed.
+func (r *Relationship) IsStale(days int, now time.Time) bool {
+	return now.Sub(r.ValidatedAt()) >= time.Duration(days)*24*time.Hour
+}
+
+// CurrentStrength is the strength decayed to now: it drops a level for every
+// full period of days since the relationship was last confirmed, bottoming
+// out at weak. Untracked strengths stay empty.
+func (r *Relationship) CurrentStrength(days int, now time.Time) string {
+	level := -1
+	for i, strength := range relationshipStrengths {
+		if r.Strength == strength {
+			level = i
+		}
+	}
+	if level < 0 {
+		return r.Strength
+	}
+	if days > 0 {
+		level -= int(now.Sub(r.ValidatedAt()).Hours() / 24 / float64(days))
+	}
+	if level < 0 {
+		level = 0
+	}
+	return relationshipStrengths[level]
+}
+
+// RelationshipEdgeWeight is how heavily a relationship of the given strength
+// is drawn in graphs. Untracked strengths draw like weak ones.
+func RelationshipEdgeWeight(strength string) float64 {
+	switch strength {
+	case StrengthStrong:
+		return 3.0
+	case StrengthMedium:
+		return 2.0
+	default:
+		return 1.0
+	}
+}
+
+// ValidateRelationship confirms that a relationship still holds as of now,
+// optionally setting a new strength, and restarts its decay.
+func (c *Client) ValidateRelationship(id uuid.UUID, strength string, now time.Time) (*Relationship, error) {
+	if err := ValidateRelationshipStrength(strength); err != nil {
+		return nil, err
+	}
+	rel, err := c.GetRelationship(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strength != "" {
+		rel.Strength = strength
+	}
+	rel.LastValidatedAt = &now
+	if err := c.UpdateRelationship(rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+// ListStaleRelationships returns relationships unconfirmed for days or more,
+// longest unconfirmed first. A limit of 0 returns them all.
+func (c *Client) ListStaleRelationships(days int, now time.Time, limit int) ([]*Relationship, error) {
+	rels, err := c.ListRelationships(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
+	}
+
+	var stale []*Relationship
+	for _, rel := range rels {
+		if rel.IsStale(days, now) {
+			stale = append(stale, rel)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].ValidatedAt().Before(stale[j].ValidatedAt())
+	})
+	if limit > 0 && len(stale) > limit {
+		stale = stale[:limit]
+	}
+	return stale, nil
+}
+
+// RevalidationPrompt asks whether a stale relationship still holds.
+type RevalidationPrompt struct {
+	*Relationship
+	DaysUnconfirmed int    `json:"days_unconfirmed"`
+	CurrentStrength string `json:"current_strength,omitempty"` // Strength after decay
+}
+
+// Question is the prompt shown in the digest.
+func (p *RevalidationPrompt) Question() string {
+	return fmt.Sprintf("Are %s and %s still in touch?", p.Contact1Name, p.Contact2Name)
+}
+
+// GetRevalidationPrompts returns up to limit stale relationships to re-check,
+// longest unconfirmed first, using the configured revalidation period.
+func (c *Client) GetRevalidationPrompts(limit int) ([]*RevalidationPrompt, error) {
+	days := RelationshipRevalidateDays(c.Config())
+	now := time.Now()
+	stale, err := c.ListStaleRelationships(days, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	prompts := make([]*RevalidationPrompt, 0, len(stale))
+	for _, rel := range stale {
+		prompts = append(prompts, &RevalidationPrompt{
+			Relationship:    rel,
+			DaysUnconfirmed: int(now.Sub(rel.ValidatedAt()).Hours() / 24),
+			CurrentStrength: rel.CurrentStrength(days, now),
+		})
+	}
+	return prompts, nil
+}
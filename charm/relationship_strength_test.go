@@ -0,0 +1,67 @@
+// ABOUTME: Tests for relationship strength decay and revalidation
+// ABOUTME: Verifies strength fading per unconfirmed period, stale listing, and confirming a relationship
+package charm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRelationshipCurrentStrength(t *testing.T) {
+	now := time.Now()
+	rel := &Relationship{Strength: StrengthStrong, CreatedAt: now.AddDate(0, 0, -200)}
+
+	if got := rel.CurrentStrength(180, now); got != StrengthMedium {
+		t.Errorf("expected strong to fade to medium after one period, got %s", got)
+	}
+	if got := rel.CurrentStrength(30, now); got != StrengthWeak {
+		t.Errorf("expected strength to bottom out at weak, got %s", got)
+	}
+
+	validated := now.AddDate(0, 0, -10)
+	rel.LastValidatedAt = &validated
+	if got := rel.CurrentStrength(180, now); got != StrengthStrong {
+		t.Errorf("expected a recent confirmation to keep it strong, got %s", got)
+	}
+	if rel.IsStale(180, now) {
+		t.Error("expected a recently confirmed relationship not to be stale")
+	}
+
+	if got := (&Relationship{CreatedAt: now.AddDate(-2, 0, 0)}).CurrentStrength(180, now); got != "" {
+		t.Errorf("expected an untracked strength to stay empty, got %s", got)
+	}
+}
+
+func TestStaleRelationships(t *testing.T) {
+	client := NewTestClient(t)
+	rel := &Relationship{ContactID1: uuid.New(), ContactID2: uuid.New(), Contact1Name: "Ada", Contact2Name: "Grace", Strength: StrengthMedium}
+	if err := client.CreateRelationship(rel); err != nil {
+		t.Fatalf("CreateRelationship failed: %v", err)
+	}
+
+	later := time.Now().AddDate(0, 0, 200)
+	stale, err := client.ListStaleRelationships(180, later, 0)
+	if err != nil {
+		t.Fatalf("ListStaleRelationships failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != rel.ID {
+		t.Fatalf("expected the relationship to be stale, got %+v", stale)
+	}
+
+	confirmed, err := client.ValidateRelationship(rel.ID, StrengthStrong, later)
+	if err != nil {
+		t.Fatalf("ValidateRelationship failed: %v", err)
+	}
+	if confirmed.Strength != StrengthStrong || confirmed.LastValidatedAt == nil {
+		t.Errorf("expected a confirmed strong relationship, got %+v", confirmed)
+	}
+	if stale, _ := client.ListStaleRelationships(180, later, 0); len(stale) != 0 {
+		t.Errorf("expected no stale relationships after confirming, got %d", len(stale))
+	}
+
+	if _, err := client.ValidateRelationship(rel.ID, "close", later); err == nil {
+		t.Error("expected an invalid strength to be rejected")
+	}
+}
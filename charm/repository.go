@@ -28,12 +28,12 @@ func (c *Client) CreateContact(contact *Contact) error {
 	contact.CreatedAt = now
 	contact.UpdatedAt = now
 
-	data, err := json.Marshal(contact)
+	data, err := c.saveContact(contact, anyVersion)
 	if err != nil {
-		return fmt.Errorf("failed to marshal contact: %w", err)
+		return err
 	}
 
-	return c.Set(ContactKey(contact.ID.String()), data)
+	return c.recordRevision(EntityContact, contact.ID, RevisionOpCreate, data)
 }
 
 // GetContact retrieves a contact by ID.
@@ -43,7 +43,7 @@ func (c *Client) GetContact(id uuid.UUID) (*Contact, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("contact not found: %s", id)
+		return nil, NotFoundf("contact not found: %s", id)
 	}
 
 	var contact Contact
@@ -55,28 +55,63 @@ func (c *Client) GetContact(id uuid.UUID) (*Contact, error) {
 
 // UpdateContact updates an existing contact.
 func (c *Client) UpdateContact(contact *Contact) error {
+	return c.updateContact(contact, anyVersion)
+}
+
+// updateContact saves contact if the stored contact is at version expected
+// (any version for anyVersion).
+func (c *Client) updateContact(contact *Contact, expected int64) error {
 	contact.UpdatedAt = time.Now()
 
-	data, err := json.Marshal(contact)
+	data, err := c.saveContact(contact, expected)
 	if err != nil {
-		return fmt.Errorf("failed to marshal contact: %w", err)
+		return err
 	}
 
-	return c.Set(ContactKey(contact.ID.String()), data)
+	return c.recordRevision(EntityContact, contact.ID, RevisionOpUpdate, data)
+}
+
+// saveContact stores contact as the next version; see writeVersioned.
+func (c *Client) saveContact(contact *Contact, expected int64) ([]byte, error) {
+	return c.writeVersioned(EntityContact, contact.ID, ContactKey(contact.ID.String()), expected, func(version int64) ([]byte, error) {
+		contact.Version = version
+		data, err := json.Marshal(contact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal contact: %w", err)
+		}
+		return data, nil
+	})
 }
 
 // DeleteContact removes a contact by ID.
 func (c *Client) DeleteContact(id uuid.UUID) error {
+	if err := c.recordDeleteRevision(EntityContact, id); err != nil {
+		return err
+	}
+
 	return c.Delete(ContactKey(id.String()))
 }
 
 // ListContacts returns all contacts matching the filter.
 func (c *Client) ListContacts(filter *ContactFilter) ([]*Contact, error) {
+	filter, ok, err := filter.withQueryTerms(time.Now())
+	if err != nil || !ok {
+		return nil, err
+	}
+
 	keys, err := c.KeysWithPrefix([]byte(PrefixContact))
 	if err != nil {
 		return nil, err
 	}
 
+	// The city filter depends on interactions, not the contact record
+	var inCity map[uuid.UUID]bool
+	if filter != nil && filter.City != "" {
+		if inCity, err = c.ContactIDsInCity(filter.City); err != nil {
+			return nil, err
+		}
+	}
+
 	var contacts []*Contact
 	for _, key := range keys {
 		data, err := c.Get(key)
@@ -89,6 +124,9 @@ func (c *Client) ListContacts(filter *ContactFilter) ([]*Contact, error) {
 			continue
 		}
 
+		if inCity != nil && !inCity[contact.ID] {
+			continue
+		}
 		if filter.Matches(&contact) {
 			contacts = append(contacts, &contact)
 		}
@@ -123,6 +161,26 @@ func (c *Client) FindContactByName(name string) (*Contact, error) {
 	return nil, nil
 }
 
+// FindContactByEmail finds a contact by case-insensitive email match.
+func (c *Client) FindContactByEmail(email string) (*Contact, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+
+	// Archived contacts still count, so re-adding one doesn't duplicate it
+	contacts, err := c.ListContacts(&ContactFilter{Query: email, IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Email, email) {
+			return contact, nil
+		}
+	}
+	return nil, nil
+}
+
 // ============================================================================
 // Company Operations
 // ============================================================================
@@ -136,12 +194,12 @@ func (c *Client) CreateCompany(company *Company) error {
 	company.CreatedAt = now
 	company.UpdatedAt = now
 
-	data, err := json.Marshal(company)
+	data, err := c.saveCompany(company, anyVersion)
 	if err != nil {
-		return fmt.Errorf("failed to marshal company: %w", err)
+		return err
 	}
 
-	return c.Set(CompanyKey(company.ID.String()), data)
+	return c.recordRevision(EntityCompany, company.ID, RevisionOpCreate, data)
 }
 
 // GetCompany retrieves a company by ID.
@@ -151,7 +209,7 @@ func (c *Client) GetCompany(id uuid.UUID) (*Company, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("company not found: %s", id)
+		return nil, NotFoundf("company not found: %s", id)
 	}
 
 	var company Company
@@ -163,23 +221,56 @@ func (c *Client) GetCompany(id uuid.UUID) (*Company, error) {
 
 // UpdateCompany updates an existing company.
 func (c *Client) UpdateCompany(company *Company) error {
+	return c.updateCompany(company, anyVersion)
+}
+
+// updateCompany saves company if the stored company is at version expected
+// (any version for anyVersion).
+func (c *Client) updateCompany(company *Company, expected int64) error {
 	company.UpdatedAt = time.Now()
 
-	data, err := json.Marshal(company)
+	data, err := c.saveCompany(company, expected)
 	if err != nil {
-		return fmt.Errorf("failed to marshal company: %w", err)
+		return err
 	}
 
-	return c.Set(CompanyKey(company.ID.String()), data)
+	return c.recordRevision(EntityCompany, company.ID, RevisionOpUpdate, data)
+}
+
+// saveCompany stores company as the next version; see writeVersioned.
+func (c *Client) saveCompany(company *Company, expected int64) ([]byte, error) {
+	return c.writeVersioned(EntityCompany, company.ID, CompanyKey(company.ID.String()), expected, func(version int64) ([]byte, error) {
+		company.Version = version
+		data, err := json.Marshal(company)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal company: %w", err)
+		}
+		return data, nil
+	})
 }
 
 // DeleteCompany removes a company by ID.
 func (c *Client) DeleteCompany(id uuid.UUID) error {
-	return c.Delete(CompanyKey(id.String()))
+	if err := c.recordDeleteRevision(EntityCompany, id); err != nil {
+		return err
+	}
+
+	if err := c.Delete(CompanyKey(id.String())); err != nil {
+		return err
+	}
+
+	// Drop the company's fetched news with it
+	_ = c.Delete(CompanyNewsKey(id.String()))
+	return nil
 }
 
 // ListCompanies returns all companies matching the filter.
 func (c *Client) ListCompanies(filter *CompanyFilter) ([]*Company, error) {
+	filter, ok, err := filter.withQueryTerms()
+	if err != nil || !ok {
+		return nil, err
+	}
+
 	keys, err := c.KeysWithPrefix([]byte(PrefixCompany))
 	if err != nil {
 		return nil, err
@@ -242,13 +333,26 @@ func (c *Client) CreateDeal(deal *Deal) error {
 	deal.CreatedAt = now
 	deal.UpdatedAt = now
 	deal.LastActivityAt = now
+	if len(deal.StageHistory) == 0 {
+		deal.StageHistory = []StageChange{{Stage: deal.Stage, At: now}}
+	}
 
-	data, err := json.Marshal(deal)
+	if err := validateDealSource(deal); err != nil {
+		return err
+	}
+	if err := validateDealProbability(deal); err != nil {
+		return err
+	}
+	if err := c.checkDealStage(deal, true); err != nil {
+		return err
+	}
+
+	data, err := c.saveDeal(deal, anyVersion)
 	if err != nil {
-		return fmt.Errorf("failed to marshal deal: %w", err)
+		return err
 	}
 
-	return c.Set(DealKey(deal.ID.String()), data)
+	return c.recordRevision(EntityDeal, deal.ID, RevisionOpCreate, data)
 }
 
 // GetDeal retrieves a deal by ID.
@@ -258,7 +362,7 @@ func (c *Client) GetDeal(id uuid.UUID) (*Deal, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("deal not found: %s", id)
+		return nil, NotFoundf("deal not found: %s", id)
 	}
 
 	var deal Deal
@@ -270,24 +374,82 @@ func (c *Client) GetDeal(id uuid.UUID) (*Deal, error) {
 
 // UpdateDeal updates an existing deal.
 func (c *Client) UpdateDeal(deal *Deal) error {
+	return c.updateDeal(deal, anyVersion)
+}
+
+// updateDeal saves deal if the stored deal is at version expected (any
+// version for anyVersion).
+func (c *Client) updateDeal(deal *Deal, expected int64) error {
+	if err := validateDealSource(deal); err != nil {
+		return err
+	}
+	if err := validateDealProbability(deal); err != nil {
+		return err
+	}
+	if err := c.checkDealStage(deal, false); err != nil {
+		return err
+	}
+
 	deal.UpdatedAt = time.Now()
 	deal.LastActivityAt = time.Now()
+	if err := c.recordStageChange(deal, deal.UpdatedAt); err != nil {
+		return err
+	}
 
-	data, err := json.Marshal(deal)
+	data, err := c.saveDeal(deal, expected)
 	if err != nil {
-		return fmt.Errorf("failed to marshal deal: %w", err)
+		return err
 	}
 
-	return c.Set(DealKey(deal.ID.String()), data)
+	return c.recordRevision(EntityDeal, deal.ID, RevisionOpUpdate, data)
+}
+
+// saveDeal stores deal as the next version; see writeVersioned.
+func (c *Client) saveDeal(deal *Deal, expected int64) ([]byte, error) {
+	return c.writeVersioned(EntityDeal, deal.ID, DealKey(deal.ID.String()), expected, func(version int64) ([]byte, error) {
+		deal.Version = version
+		data, err := json.Marshal(deal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal deal: %w", err)
+		}
+		return data, nil
+	})
+}
+
+// validateDealSource checks the deal's source, which is optional.
+func validateDealSource(deal *Deal) error {
+	if deal.Source != "" && !IsValidDealSource(deal.Source) {
+		return Validationf("invalid source: %s (valid: %s)", deal.Source, strings.Join(DealSources, ", "))
+	}
+	return nil
+}
+
+// IsValidDealSource reports whether source is a known deal source.
+func IsValidDealSource(source string) bool {
+	for _, s := range DealSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
 }
 
 // DeleteDeal removes a deal by ID.
 func (c *Client) DeleteDeal(id uuid.UUID) error {
+	if err := c.recordDeleteRevision(EntityDeal, id); err != nil {
+		return err
+	}
+
 	return c.Delete(DealKey(id.String()))
 }
 
 // ListDeals returns all deals matching the filter.
 func (c *Client) ListDeals(filter *DealFilter) ([]*Deal, error) {
+	filter, ok, err := filter.withQueryTerms(time.Now())
+	if err != nil || !ok {
+		return nil, err
+	}
+
 	keys, err := c.KeysWithPrefix([]byte(PrefixDeal))
 	if err != nil {
 		return nil, err
@@ -323,6 +485,25 @@ func (c *Client) ListDeals(filter *DealFilter) ([]*Deal, error) {
 	return deals, nil
 }
 
+// FindDealByTitle finds a company's deal by case-insensitive title match.
+func (c *Client) FindDealByTitle(companyID uuid.UUID, title string) (*Deal, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, nil
+	}
+
+	deals, err := c.ListDeals(&DealFilter{CompanyID: &companyID})
+	if err != nil {
+		return nil, err
+	}
+	for _, deal := range deals {
+		if strings.EqualFold(strings.TrimSpace(deal.Title), title) {
+			return deal, nil
+		}
+	}
+	return nil, nil
+}
+
 // ============================================================================
 // DealNote Operations
 // ============================================================================
@@ -349,7 +530,7 @@ func (c *Client) GetDealNote(id uuid.UUID) (*DealNote, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("deal note not found: %s", id)
+		return nil, NotFoundf("deal note not found: %s", id)
 	}
 
 	var note DealNote
@@ -366,12 +547,22 @@ func (c *Client) DeleteDealNote(id uuid.UUID) error {
 
 // ListDealNotes returns all notes for a deal.
 func (c *Client) ListDealNotes(dealID uuid.UUID) ([]*DealNote, error) {
+	notes, err := c.DealNotesByDeal()
+	if err != nil {
+		return nil, err
+	}
+	return notes[dealID], nil
+}
+
+// DealNotesByDeal returns every deal note grouped by deal, oldest first, so
+// callers covering many deals read the notes once.
+func (c *Client) DealNotesByDeal() (map[uuid.UUID][]*DealNote, error) {
 	keys, err := c.KeysWithPrefix([]byte(PrefixDealNote))
 	if err != nil {
 		return nil, err
 	}
 
-	var notes []*DealNote
+	notes := make(map[uuid.UUID][]*DealNote)
 	for _, key := range keys {
 		data, err := c.Get(key)
 		if err != nil {
@@ -382,18 +573,132 @@ func (c *Client) ListDealNotes(dealID uuid.UUID) ([]*DealNote, error) {
 		if err := json.Unmarshal(data, &note); err != nil {
 			continue
 		}
+		notes[note.DealID] = append(notes[note.DealID], &note)
+	}
+
+	// Sort by created time (oldest first)
+	for _, dealNotes := range notes {
+		sort.Slice(dealNotes, func(i, j int) bool {
+			return dealNotes[i].CreatedAt.Before(dealNotes[j].CreatedAt)
+		})
+	}
+
+	return notes, nil
+}
+
+// ============================================================================
+// DealContact Operations
+// ============================================================================
+
+// SaveDealContact adds a contact to a deal, or updates their role if already on it.
+func (c *Client) SaveDealContact(dc *DealContact) error {
+	if !IsValidDealRole(dc.Role) {
+		return Validationf("invalid role: %s (valid: %s)", dc.Role, strings.Join(DealRoles, ", "))
+	}
+
+	now := time.Now()
+	if existing, err := c.GetDealContact(dc.DealID, dc.ContactID); err == nil && existing != nil {
+		dc.CreatedAt = existing.CreatedAt
+	} else {
+		dc.CreatedAt = now
+	}
+	dc.UpdatedAt = now
+
+	data, err := json.Marshal(dc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deal contact: %w", err)
+	}
+
+	return c.Set(DealContactKey(dc.DealID.String(), dc.ContactID.String()), data)
+}
+
+// GetDealContact retrieves a contact's role on a deal.
+// Returns (nil, nil) if the contact is not on the deal.
+func (c *Client) GetDealContact(dealID, contactID uuid.UUID) (*DealContact, error) {
+	data, err := c.Get(DealContactKey(dealID.String(), contactID.String()))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var dc DealContact
+	if err := json.Unmarshal(data, &dc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deal contact: %w", err)
+	}
+	return &dc, nil
+}
+
+// DeleteDealContact removes a contact from a deal.
+func (c *Client) DeleteDealContact(dealID, contactID uuid.UUID) error {
+	return c.Delete(DealContactKey(dealID.String(), contactID.String()))
+}
+
+// ListDealContacts returns the contacts on a deal, ordered by role then name.
+func (c *Client) ListDealContacts(dealID uuid.UUID) ([]*DealContact, error) {
+	return c.listDealContacts(PrefixDealContact+dealID.String()+":", nil)
+}
+
+// ListContactDealRoles returns every deal role held by a contact.
+func (c *Client) ListContactDealRoles(contactID uuid.UUID) ([]*DealContact, error) {
+	return c.listDealContacts(PrefixDealContact, func(dc *DealContact) bool {
+		return dc.ContactID == contactID
+	})
+}
+
+func (c *Client) listDealContacts(prefix string, match func(*DealContact) bool) ([]*DealContact, error) {
+	keys, err := c.KeysWithPrefix([]byte(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []*DealContact
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
 
-		if note.DealID == dealID {
-			notes = append(notes, &note)
+		var dc DealContact
+		if err := json.Unmarshal(data, &dc); err != nil {
+			continue
+		}
+		if match == nil || match(&dc) {
+			contacts = append(contacts, &dc)
 		}
 	}
 
-	// Sort by created time (oldest first)
-	sort.Slice(notes, func(i, j int) bool {
-		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	sort.Slice(contacts, func(i, j int) bool {
+		ri, rj := dealRoleOrder(contacts[i].Role), dealRoleOrder(contacts[j].Role)
+		if ri != rj {
+			return ri < rj
+		}
+		return contacts[i].ContactName < contacts[j].ContactName
 	})
+	return contacts, nil
+}
 
-	return notes, nil
+// IsValidDealRole reports whether role is a known deal contact role.
+func IsValidDealRole(role string) bool {
+	return dealRoleOrder(role) < len(DealRoles)
+}
+
+// DealRoleLabel returns a human-readable role name, e.g. "decision maker".
+func DealRoleLabel(role string) string {
+	return strings.ReplaceAll(role, "_", " ")
+}
+
+func dealRoleOrder(role string) int {
+	for i, r := range DealRoles {
+		if r == role {
+			return i
+		}
+	}
+	return len(DealRoles)
 }
 
 // ============================================================================
@@ -414,7 +719,11 @@ func (c *Client) CreateRelationship(rel *Relationship) error {
 		return fmt.Errorf("failed to marshal relationship: %w", err)
 	}
 
-	return c.Set(RelationshipKey(rel.ID.String()), data)
+	if err := c.Set(RelationshipKey(rel.ID.String()), data); err != nil {
+		return err
+	}
+
+	return c.recordRevision(EntityRelationship, rel.ID, RevisionOpCreate, data)
 }
 
 // GetRelationship retrieves a relationship by ID.
@@ -424,7 +733,7 @@ func (c *Client) GetRelationship(id uuid.UUID) (*Relationship, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("relationship not found: %s", id)
+		return nil, NotFoundf("relationship not found: %s", id)
 	}
 
 	var rel Relationship
@@ -443,11 +752,19 @@ func (c *Client) UpdateRelationship(rel *Relationship) error {
 		return fmt.Errorf("failed to marshal relationship: %w", err)
 	}
 
-	return c.Set(RelationshipKey(rel.ID.String()), data)
+	if err := c.Set(RelationshipKey(rel.ID.String()), data); err != nil {
+		return err
+	}
+
+	return c.recordRevision(EntityRelationship, rel.ID, RevisionOpUpdate, data)
 }
 
 // DeleteRelationship removes a relationship by ID.
 func (c *Client) DeleteRelationship(id uuid.UUID) error {
+	if err := c.recordDeleteRevision(EntityRelationship, id); err != nil {
+		return err
+	}
+
 	return c.Delete(RelationshipKey(id.String()))
 }
 
@@ -556,7 +873,7 @@ func (c *Client) GetInteractionLog(id uuid.UUID) (*InteractionLog, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("interaction log not found: %s", id)
+		return nil, NotFoundf("interaction log not found: %s", id)
 	}
 
 	var log InteractionLog
@@ -566,6 +883,16 @@ func (c *Client) GetInteractionLog(id uuid.UUID) (*InteractionLog, error) {
 	return &log, nil
 }
 
+// UpdateInteractionLog replaces an existing interaction log entry.
+func (c *Client) UpdateInteractionLog(log *InteractionLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal interaction log: %w", err)
+	}
+
+	return c.Set(InteractionLogKey(log.ID.String()), data)
+}
+
 // DeleteInteractionLog removes an interaction log entry by ID.
 func (c *Client) DeleteInteractionLog(id uuid.UUID) error {
 	return c.Delete(InteractionLogKey(id.String()))
@@ -685,17 +1012,21 @@ func (c *Client) ListContactCadences() ([]*ContactCadence, error) {
 
 // GetFollowupList returns contacts needing follow-up, sorted by priority
 // This combines cadence data with contact information similar to the SQL version.
+// Priority is recomputed against the current time so engagement decay since
+// the last recompute is reflected in the ordering.
 func (c *Client) GetFollowupList(limit int) ([]*FollowupContact, error) {
-	// Get all cadences sorted by priority
 	cadences, err := c.ListContactCadences()
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	var followups []*FollowupContact
 	for _, cadence := range cadences {
+		priority := ComputePriorityScore(cadence, now)
+
 		// Only include contacts with priority > 0
-		if cadence.PriorityScore <= 0 {
+		if priority <= 0 {
 			continue
 		}
 
@@ -708,7 +1039,7 @@ func (c *Client) GetFollowupList(limit int) ([]*FollowupContact, error) {
 		// Calculate days since contact
 		daysSince := 0
 		if cadence.LastInteractionDate != nil {
-			daysSince = int(time.Since(*cadence.LastInteractionDate).Hours() / 24)
+			daysSince = int(now.Sub(*cadence.LastInteractionDate).Hours() / 24)
 		}
 
 		// Build followup contact
@@ -725,23 +1056,28 @@ func (c *Client) GetFollowupList(limit int) ([]*FollowupContact, error) {
 			UpdatedAt:            contact.UpdatedAt,
 			CadenceDays:          cadence.CadenceDays,
 			RelationshipStrength: cadence.RelationshipStrength,
-			PriorityScore:        cadence.PriorityScore,
+			PriorityScore:        priority,
 			DaysSinceContact:     daysSince,
 			NextFollowupDate:     cadence.NextFollowupDate,
+			EngagementScore:      cadence.CurrentEngagement(now),
 		}
 
 		followups = append(followups, followup)
+	}
 
-		// Apply limit
-		if limit > 0 && len(followups) >= limit {
-			break
-		}
+	sortFollowups(followups)
+
+	// Apply limit
+	if limit > 0 && len(followups) > limit {
+		followups = followups[:limit]
 	}
 
 	return followups, nil
 }
 
 // UpdateCadenceAfterInteraction updates cadence when interaction is logged.
+// The contact's engagement score is rebuilt from its interaction history,
+// so the interaction should be logged before calling this.
 func (c *Client) UpdateCadenceAfterInteraction(contactID uuid.UUID, timestamp time.Time) error {
 	// Get or create cadence
 	cadence, err := c.GetContactCadence(contactID)
@@ -758,30 +1094,24 @@ func (c *Client) UpdateCadenceAfterInteraction(contactID uuid.UUID, timestamp ti
 		}
 	}
 
-	// Update timestamps
-	cadence.LastInteractionDate = &timestamp
-	next := timestamp.AddDate(0, 0, cadence.CadenceDays)
-	cadence.NextFollowupDate = &next
-
-	// Compute priority score
-	daysSinceContact := int(time.Since(*cadence.LastInteractionDate).Hours() / 24)
-	daysOverdue := daysSinceContact - cadence.CadenceDays
+	// Update timestamps, never moving them backwards for older interactions
+	if cadence.LastInteractionDate == nil || timestamp.After(*cadence.LastInteractionDate) {
+		cadence.LastInteractionDate = &timestamp
+		next := timestamp.AddDate(0, 0, cadence.CadenceDays)
+		cadence.NextFollowupDate = &next
+	}
 
-	if daysOverdue <= 0 {
-		cadence.PriorityScore = 0.0
-	} else {
-		baseScore := float64(daysOverdue * 2)
-		multiplier := 1.0
-		switch cadence.RelationshipStrength {
-		case StrengthStrong:
-			multiplier = 2.0
-		case StrengthMedium:
-			multiplier = 1.5
-		case StrengthWeak:
-			multiplier = 1.0
-		}
-		cadence.PriorityScore = baseScore * multiplier
+	// Boost engagement from the contact's interaction history
+	now := time.Now()
+	logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &contactID})
+	if err != nil {
+		return err
 	}
+	cadence.EngagementScore = EngagementScore(logs, now)
+	cadence.EngagementUpdatedAt = &now
+
+	// Compute priority score
+	cadence.PriorityScore = ComputePriorityScore(cadence, now)
 
 	return c.SaveContactCadence(cadence)
 }
@@ -812,7 +1142,7 @@ func (c *Client) GetSuggestion(id uuid.UUID) (*Suggestion, error) {
 		return nil, err
 	}
 	if data == nil {
-		return nil, fmt.Errorf("suggestion not found: %s", id)
+		return nil, NotFoundf("suggestion not found: %s", id)
 	}
 
 	var suggestion Suggestion
@@ -938,6 +1268,15 @@ func (c *Client) CreateSyncLog(log *SyncLog) error {
 	return c.Set(SyncLogKey(log.ID.String()), data)
 }
 
+// CountSyncLogs returns the number of entities imported across all services.
+func (c *Client) CountSyncLogs() (int, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixSyncLog))
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
 // FindSyncLogBySource finds a sync log by source service and ID.
 func (c *Client) FindSyncLogBySource(service, sourceID string) (*SyncLog, error) {
 	keys, err := c.KeysWithPrefix([]byte(PrefixSyncLog))
@@ -962,3 +1301,60 @@ func (c *Client) FindSyncLogBySource(service, sourceID string) (*SyncLog, error)
 	}
 	return nil, nil
 }
+
+// ListSyncStates returns sync state for every service, sorted by service name.
+func (c *Client) ListSyncStates() ([]*SyncState, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixSyncState))
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*SyncState
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var state SyncState
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		states = append(states, &state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Service < states[j].Service
+	})
+	return states, nil
+}
+
+// ListRecentSyncLogs returns the most recently imported sync log entries.
+func (c *Client) ListRecentSyncLogs(limit int) ([]*SyncLog, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixSyncLog))
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []*SyncLog
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var log SyncLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			continue
+		}
+		logs = append(logs, &log)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ImportedAt.After(logs[j].ImportedAt)
+	})
+	if limit > 0 && len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
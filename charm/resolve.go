@@ -0,0 +1,146 @@
+// ABOUTME: Name resolution for contacts and companies
+// ABOUTME: Strict mode refuses to guess between records sharing a name and lists the candidates instead
+
+package charm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxCandidates caps how many candidates an ambiguous lookup lists.
+const maxCandidates = 10
+
+// MatchCandidate is one record an ambiguous name could refer to.
+type MatchCandidate struct {
+	ID     uuid.UUID
+	Name   string
+	Detail string // email or company, to tell candidates apart
+}
+
+// String formats a candidate as "Name <detail> (id)".
+func (m MatchCandidate) String() string {
+	if m.Detail != "" {
+		return fmt.Sprintf("%s <%s> (%s)", m.Name, m.Detail, m.ID)
+	}
+	return fmt.Sprintf("%s (%s)", m.Name, m.ID)
+}
+
+// AmbiguousMatchError is returned by strict lookups when a name doesn't
+// identify exactly one record.
+type AmbiguousMatchError struct {
+	EntityType string // EntityContact or EntityCompany
+	Ref        string
+	Candidates []MatchCandidate
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		names[i] = candidate.String()
+	}
+	return fmt.Sprintf("%q matches %d %ss, use an ID: %s", e.Ref, len(e.Candidates), e.EntityType, strings.Join(names, "; "))
+}
+
+// ContactCandidate describes a contact for disambiguation.
+func ContactCandidate(contact *Contact) MatchCandidate {
+	detail := contact.Email
+	if detail == "" {
+		detail = contact.CompanyName
+	}
+	return MatchCandidate{ID: contact.ID, Name: contact.Name, Detail: detail}
+}
+
+// ResolveContactName finds the contact a name refers to, or nil when nothing
+// matches. Without strict it prefers an exact (case-insensitive) name match
+// and otherwise takes the first search result. With strict it only accepts a
+// name that exactly matches one contact; other matches return an
+// *AmbiguousMatchError listing the candidates.
+func (c *Client) ResolveContactName(name string, strict bool) (*Contact, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+
+	contacts, err := c.ListContacts(&ContactFilter{Query: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup contact: %w", err)
+	}
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	var exact []*Contact
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Name, name) || strings.EqualFold(contact.Email, name) {
+			exact = append(exact, contact)
+		}
+	}
+
+	if !strict {
+		if len(exact) > 0 {
+			return exact[0], nil
+		}
+		return contacts[0], nil
+	}
+
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	// Several people share the name, or the name only partly matches
+	candidates := exact
+	if len(candidates) == 0 {
+		candidates = contacts
+	}
+	ambiguous := &AmbiguousMatchError{EntityType: EntityContact, Ref: name}
+	for i, contact := range candidates {
+		if i == maxCandidates {
+			break
+		}
+		ambiguous.Candidates = append(ambiguous.Candidates, ContactCandidate(contact))
+	}
+	return nil, ambiguous
+}
+
+// ResolveCompanyName finds the company a name refers to, or nil when none has
+// that name. Without strict it behaves like FindCompanyByName; with strict,
+// names shared by several companies (ignoring case) return an
+// *AmbiguousMatchError.
+func (c *Client) ResolveCompanyName(name string, strict bool) (*Company, error) {
+	if !strict {
+		company, err := c.FindCompanyByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup company: %w", err)
+		}
+		return company, nil
+	}
+
+	companies, err := c.ListCompanies(&CompanyFilter{Query: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup company: %w", err)
+	}
+
+	var exact []*Company
+	for _, company := range companies {
+		if strings.EqualFold(company.Name, strings.TrimSpace(name)) {
+			exact = append(exact, company)
+		}
+	}
+	switch len(exact) {
+	case 0:
+		return nil, nil
+	case 1:
+		return exact[0], nil
+	}
+
+	ambiguous := &AmbiguousMatchError{EntityType: EntityCompany, Ref: name}
+	for i, company := range exact {
+		if i == maxCandidates {
+			break
+		}
+		ambiguous.Candidates = append(ambiguous.Candidates, MatchCandidate{ID: company.ID, Name: company.Name, Detail: company.Domain})
+	}
+	return nil, ambiguous
+}
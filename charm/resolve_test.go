@@ -0,0 +1,98 @@
+// ABOUTME: Tests for contact and company name resolution
+// ABOUTME: Verifies lenient first-match lookups and strict lookups that list candidates for ambiguous names
+
+package charm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveContactName(t *testing.T) {
+	client := NewTestClient(t)
+
+	for _, contact := range []*Contact{
+		{Name: "John Smith", Email: "john@acme.com"},
+		{Name: "John Smith", Email: "jsmith@globex.com"},
+		{Name: "Jane Doe", Email: "jane@acme.com"},
+		{Name: "Janet Doerr"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	// Lenient lookups guess
+	contact, err := client.ResolveContactName("john smith", false)
+	if err != nil || contact == nil || contact.Name != "John Smith" {
+		t.Fatalf("expected a John Smith, got %v, %v", contact, err)
+	}
+
+	// Strict accepts a unique exact name or email
+	contact, err = client.ResolveContactName("Jane Doe", true)
+	if err != nil || contact == nil || contact.Email != "jane@acme.com" {
+		t.Fatalf("expected Jane Doe, got %v, %v", contact, err)
+	}
+	contact, err = client.ResolveContactName("jsmith@globex.com", true)
+	if err != nil || contact == nil || contact.Email != "jsmith@globex.com" {
+		t.Fatalf("expected the Globex John Smith, got %v, %v", contact, err)
+	}
+
+	// Strict refuses shared names and partial matches
+	_, err = client.ResolveContactName("John Smith", true)
+	var ambiguous *AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousMatchError, got %v", err)
+	}
+	if ambiguous.EntityType != EntityContact || len(ambiguous.Candidates) != 2 {
+		t.Errorf("unexpected candidates: %+v", ambiguous)
+	}
+	if !strings.Contains(err.Error(), "john@acme.com") || !strings.Contains(err.Error(), "jsmith@globex.com") {
+		t.Errorf("expected candidates in error, got %q", err)
+	}
+
+	_, err = client.ResolveContactName("Jan", true)
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Errorf("expected partial match to list Jane and Janet, got %v", err)
+	}
+
+	// No match is nil either way
+	if contact, err := client.ResolveContactName("Nobody", true); err != nil || contact != nil {
+		t.Errorf("expected no match, got %v, %v", contact, err)
+	}
+}
+
+func TestResolveCompanyName(t *testing.T) {
+	client := NewTestClient(t)
+
+	for _, company := range []*Company{
+		{Name: "Acme", Domain: "acme.com"},
+		{Name: "ACME", Domain: "acme.io"},
+		{Name: "Globex"},
+	} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("failed to create company: %v", err)
+		}
+	}
+
+	company, err := client.ResolveCompanyName("Globex", true)
+	if err != nil || company == nil || company.Name != "Globex" {
+		t.Fatalf("expected Globex, got %v, %v", company, err)
+	}
+
+	company, err = client.ResolveCompanyName("Acme", false)
+	if err != nil || company == nil {
+		t.Fatalf("expected lenient lookup to find Acme, got %v", err)
+	}
+
+	_, err = client.ResolveCompanyName("acme", true)
+	var ambiguous *AmbiguousMatchError
+	if !errors.As(err, &ambiguous) || ambiguous.EntityType != EntityCompany || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected two Acme candidates, got %v", err)
+	}
+
+	if company, err := client.ResolveCompanyName("Initech", true); err != nil || company != nil {
+		t.Errorf("expected no match, got %v, %v", company, err)
+	}
+}
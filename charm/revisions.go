@@ -0,0 +1,261 @@
+// ABOUTME: Object-level revision history for contacts, companies, deals, and relationships
+// ABOUTME: Keeps the last N serialized versions of each object so bad edits can be restored
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRevisionLimit is how many revisions are kept per object.
+const DefaultRevisionLimit = 10
+
+// Entity type names recorded on revisions.
+const (
+	EntityContact      = "contact"
+	EntityCompany      = "company"
+	EntityDeal         = "deal"
+	EntityRelationship = "relationship"
+)
+
+// Revision operation constants.
+const (
+	RevisionOpCreate  = "create"
+	RevisionOpUpdate  = "update"
+	RevisionOpDelete  = "delete"
+	RevisionOpRestore = "restore"
+)
+
+// revisionKeyFuncs maps entity types to their KV key functions.
+var revisionKeyFuncs = map[string]func(string) []byte{
+	EntityContact:      ContactKey,
+	EntityCompany:      CompanyKey,
+	EntityDeal:         DealKey,
+	EntityRelationship: RelationshipKey,
+}
+
+// Revision is a serialized snapshot of an object.
+// For deletes, Data holds the object as it was just before deletion.
+type Revision struct {
+	ObjectID   uuid.UUID       `json:"object_id"`
+	EntityType string          `json:"entity_type"`
+	Rev        int             `json:"rev"`
+	Op         string          `json:"op"`
+	Data       json.RawMessage `json:"data"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Summary returns the object's name or title from the snapshot.
+func (r *Revision) Summary() string {
+	return snapshotSummary(r.Data)
+}
+
+// snapshotSummary returns the name or title from an object's JSON snapshot.
+func snapshotSummary(data []byte) string {
+	var fields struct {
+		Name             string `json:"name"`
+		Title            string `json:"title"`
+		Contact1Name     string `json:"contact1_name"`
+		Contact2Name     string `json:"contact2_name"`
+		RelationshipType string `json:"relationship_type"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+
+	switch {
+	case fields.Name != "":
+		return fields.Name
+	case fields.Title != "":
+		return fields.Title
+	case fields.Contact1Name != "" || fields.Contact2Name != "":
+		summary := fields.Contact1Name + " ↔ " + fields.Contact2Name
+		if fields.RelationshipType != "" {
+			summary += " (" + fields.RelationshipType + ")"
+		}
+		return summary
+	}
+	return ""
+}
+
+// bookkeepingFields change on every write, or follow from other fields, and
+// aren't reported by ChangedFields.
+var bookkeepingFields = map[string]bool{"updated_at": true, "version": true, "last_activity_at": true, "stage_history": true}
+
+// ChangedFields returns the top-level fields that differ from prev, sorted.
+func (r *Revision) ChangedFields(prev *Revision) []string {
+	var before []byte
+	if prev != nil {
+		before = prev.Data
+	}
+
+	var fields []string
+	for _, change := range diffFields(before, r.Data) {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+// revisionLimitOrDefault returns the configured revision limit.
+func (c *Client) revisionLimitOrDefault() int {
+	if c.revisionLimit > 0 {
+		return c.revisionLimit
+	}
+	return DefaultRevisionLimit
+}
+
+// recordRevision stores a snapshot of an object, prunes old revisions, and
+// adds the change to the audit log.
+func (c *Client) recordRevision(entityType string, id uuid.UUID, op string, data []byte) error {
+	keys, err := c.KeysWithPrefix(revisionPrefix(id))
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	revs := revisionNumbers(keys)
+	next := 1
+	var before []byte
+	if len(revs) > 0 {
+		next = revs[len(revs)-1] + 1
+		if prev, err := c.GetRevision(id, revs[len(revs)-1]); err == nil {
+			before = prev.Data
+		}
+	}
+
+	rev := &Revision{
+		ObjectID:   id,
+		EntityType: entityType,
+		Rev:        next,
+		Op:         op,
+		Data:       data,
+		CreatedAt:  time.Now(),
+	}
+	revData, err := json.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision: %w", err)
+	}
+	if err := c.Set(RevisionKey(id.String(), next), revData); err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	// Prune the oldest revisions beyond the limit
+	revs = append(revs, next)
+	for len(revs) > c.revisionLimitOrDefault() {
+		if err := c.Delete(RevisionKey(id.String(), revs[0])); err != nil {
+			return fmt.Errorf("failed to prune revision: %w", err)
+		}
+		revs = revs[1:]
+	}
+
+	switch op {
+	case RevisionOpCreate:
+		return c.recordAudit(entityType, id, op, next, nil, data)
+	case RevisionOpDelete:
+		return c.recordAudit(entityType, id, op, next, data, nil)
+	}
+	return c.recordAudit(entityType, id, op, next, before, data)
+}
+
+// recordDeleteRevision snapshots an object before it is deleted.
+// Missing objects are ignored.
+func (c *Client) recordDeleteRevision(entityType string, id uuid.UUID) error {
+	data, err := c.Get(revisionKeyFuncs[entityType](id.String()))
+	if err != nil || data == nil {
+		return nil //nolint:nilerr // Nothing to snapshot if the object doesn't exist
+	}
+	return c.recordRevision(entityType, id, RevisionOpDelete, data)
+}
+
+// ListRevisions returns the stored revisions for an object, oldest first.
+func (c *Client) ListRevisions(id uuid.UUID) ([]*Revision, error) {
+	keys, err := c.KeysWithPrefix(revisionPrefix(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*Revision
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var rev Revision
+		if err := json.Unmarshal(data, &rev); err != nil {
+			continue
+		}
+		revisions = append(revisions, &rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Rev < revisions[j].Rev
+	})
+	return revisions, nil
+}
+
+// GetRevision retrieves a single revision of an object.
+func (c *Client) GetRevision(id uuid.UUID, rev int) (*Revision, error) {
+	data, err := c.Get(RevisionKey(id.String(), rev))
+	if err != nil || data == nil {
+		return nil, fmt.Errorf("revision %d not found for %s", rev, id)
+	}
+
+	var revision Revision
+	if err := json.Unmarshal(data, &revision); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision: %w", err)
+	}
+	return &revision, nil
+}
+
+// RestoreRevision writes a revision's snapshot back as the current object,
+// re-creating it if it was deleted. The restore is itself recorded as a revision.
+func (c *Client) RestoreRevision(id uuid.UUID, rev int) (*Revision, error) {
+	revision, err := c.GetRevision(id, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	keyFunc, ok := revisionKeyFuncs[revision.EntityType]
+	if !ok {
+		return nil, fmt.Errorf("cannot restore entity type: %s", revision.EntityType)
+	}
+
+	if err := c.Set(keyFunc(id.String()), revision.Data); err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", revision.EntityType, err)
+	}
+	if err := c.recordRevision(revision.EntityType, id, RevisionOpRestore, revision.Data); err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// revisionPrefix returns the key prefix for all revisions of an object.
+func revisionPrefix(id uuid.UUID) []byte {
+	return []byte(PrefixRevision + id.String() + ":")
+}
+
+// revisionNumbers extracts sorted revision numbers from revision keys.
+func revisionNumbers(keys [][]byte) []int {
+	var revs []int
+	for _, key := range keys {
+		idx := strings.LastIndex(string(key), ":")
+		if idx < 0 {
+			continue
+		}
+		rev, err := strconv.Atoi(string(key[idx+1:]))
+		if err != nil {
+			continue
+		}
+		revs = append(revs, rev)
+	}
+	sort.Ints(revs)
+	return revs
+}
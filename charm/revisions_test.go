@@ -0,0 +1,123 @@
+// ABOUTME: Tests for object revision history
+// ABOUTME: Verifies revisions are recorded, pruned, and restorable after updates and deletes
+
+package charm
+
+import (
+	"testing"
+)
+
+func TestRevisionsRecordedOnWrite(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	contact.Name = "Alice Smith"
+	if err := client.UpdateContact(contact); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+
+	revisions, err := client.ListRevisions(contact.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[0].Op != RevisionOpCreate || revisions[1].Op != RevisionOpUpdate {
+		t.Errorf("unexpected ops: %s, %s", revisions[0].Op, revisions[1].Op)
+	}
+	if revisions[0].Summary() != "Alice" || revisions[1].Summary() != "Alice Smith" {
+		t.Errorf("unexpected summaries: %q, %q", revisions[0].Summary(), revisions[1].Summary())
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Enterprise", Amount: 100000, Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	deal.Amount = 0
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("failed to update deal: %v", err)
+	}
+
+	if _, err := client.RestoreRevision(deal.ID, 1); err != nil {
+		t.Fatalf("RestoreRevision failed: %v", err)
+	}
+
+	restored, err := client.GetDeal(deal.ID)
+	if err != nil {
+		t.Fatalf("failed to get deal: %v", err)
+	}
+	if restored.Amount != 100000 {
+		t.Errorf("expected amount restored to 100000, got %d", restored.Amount)
+	}
+
+	revisions, _ := client.ListRevisions(deal.ID)
+	if len(revisions) != 3 || revisions[2].Op != RevisionOpRestore {
+		t.Errorf("expected restore to be recorded as revision 3, got %d revisions", len(revisions))
+	}
+}
+
+func TestRestoreDeletedObject(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme Corp"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	if err := client.DeleteCompany(company.ID); err != nil {
+		t.Fatalf("failed to delete company: %v", err)
+	}
+
+	revisions, _ := client.ListRevisions(company.ID)
+	if len(revisions) != 2 || revisions[1].Op != RevisionOpDelete {
+		t.Fatalf("expected delete revision, got %d revisions", len(revisions))
+	}
+
+	if _, err := client.RestoreRevision(company.ID, 2); err != nil {
+		t.Fatalf("RestoreRevision failed: %v", err)
+	}
+	restored, err := client.GetCompany(company.ID)
+	if err != nil {
+		t.Fatalf("expected company to be restored: %v", err)
+	}
+	if restored.Name != "Acme Corp" {
+		t.Errorf("expected restored name Acme Corp, got %s", restored.Name)
+	}
+}
+
+func TestRevisionsPruned(t *testing.T) {
+	client := NewTestClient(t)
+	client.revisionLimit = 3
+
+	contact := &Contact{Name: "Bob"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := client.UpdateContact(contact); err != nil {
+			t.Fatalf("failed to update contact: %v", err)
+		}
+	}
+
+	revisions, err := client.ListRevisions(contact.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("expected 3 revisions kept, got %d", len(revisions))
+	}
+	if revisions[0].Rev != 4 || revisions[2].Rev != 6 {
+		t.Errorf("expected revisions 4-6 kept, got %d-%d", revisions[0].Rev, revisions[2].Rev)
+	}
+
+	if _, err := client.GetRevision(contact.ID, 1); err == nil {
+		t.Error("expected pruned revision to be gone")
+	}
+}
@@ -0,0 +1,146 @@
+// ABOUTME: Contact segments, the emailable contacts matching a query
+// ABOUTME: Writes them as a mail-merge CSV, leaving out contacts tagged do-not-contact
+
+package charm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TagDoNotContact marks contacts that segments leave out.
+const TagDoNotContact = "do-not-contact"
+
+// Segment is the contacts matching a query that can be mailed, plus counts of
+// the matches left out.
+type Segment struct {
+	Contacts   []*Contact // sorted by name, one per email address
+	Suppressed int        // tagged do-not-contact
+	NoEmail    int        // without an email address
+	Duplicates int        // sharing an email with an earlier contact
+}
+
+// mailMergeColumns maps each mail-merge field to its CSV header and value.
+// Headers use the names mail-merge tools such as Word, Google Sheets add-ons,
+// and Mailchimp recognize.
+var mailMergeColumns = map[string]struct {
+	header string
+	value  func(*Contact) string
+}{
+	"first_name":     {"First Name", func(c *Contact) string { first, _ := splitName(c.Name); return first }},
+	"last_name":      {"Last Name", func(c *Contact) string { _, last := splitName(c.Name); return last }},
+	"name":           {"Full Name", func(c *Contact) string { return c.Name }},
+	"email":          {"Email Address", func(c *Contact) string { return c.Email }},
+	"company":        {"Company", func(c *Contact) string { return c.CompanyName }},
+	"title":          {"Title", func(c *Contact) string { return c.Title }},
+	"phone":          {"Phone", func(c *Contact) string { return c.Phone }},
+	"country":        {"Country", func(c *Contact) string { return c.Country }},
+	"birthday":       {"Birthday", func(c *Contact) string { return c.Birthday }},
+	"met_via":        {"Met Via", func(c *Contact) string { return c.MetVia }},
+	"met_at":         {"Met At", func(c *Contact) string { return c.MetAt }},
+	"seniority":      {"Seniority", func(c *Contact) string { return c.Seniority }},
+	"function":       {"Function", func(c *Contact) string { return c.Function }},
+	"tags":           {"Tags", func(c *Contact) string { return strings.Join(c.Tags, ", ") }},
+	"last_contacted": {"Last Contacted", func(c *Contact) string { return exportDate(c.LastContactedAt) }},
+}
+
+// MailMergeFields lists the fields a mail-merge CSV can include, in the
+// order they are documented.
+var MailMergeFields = []string{"first_name", "last_name", "name", "email", "company", "title", "phone", "country",
+	"birthday", "met_via", "met_at", "seniority", "function", "tags", "last_contacted"}
+
+// DefaultMailMergeFields are the columns written when none are chosen.
+var DefaultMailMergeFields = []string{"first_name", "last_name", "email", "company", "title"}
+
+// ContactSegment returns the unarchived contacts matching query, which takes
+// the same free text and field terms as list-contacts (an empty query matches
+// everyone). Contacts tagged do-not-contact or without an email are left out,
+// as is any contact whose email an earlier one already has.
+func (c *Client) ContactSegment(query string) (*Segment, error) {
+	contacts, err := c.ListContacts(&ContactFilter{Query: query})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(contacts[i].Name) < strings.ToLower(contacts[j].Name)
+	})
+
+	segment := &Segment{}
+	seen := make(map[string]bool)
+	for _, contact := range contacts {
+		email := strings.ToLower(strings.TrimSpace(contact.Email))
+		switch {
+		case contact.HasTag(TagDoNotContact):
+			segment.Suppressed++
+		case email == "":
+			segment.NoEmail++
+		case seen[email]:
+			segment.Duplicates++
+		default:
+			seen[email] = true
+			segment.Contacts = append(segment.Contacts, contact)
+		}
+	}
+	return segment, nil
+}
+
+// ParseMailMergeFields splits a comma-separated field list, checking each
+// against MailMergeFields. An empty list gives DefaultMailMergeFields.
+func ParseMailMergeFields(s string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := mailMergeColumns[field]; !ok {
+			return nil, fmt.Errorf("unknown mail-merge field: %s (valid: %s)", field, strings.Join(MailMergeFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return DefaultMailMergeFields, nil
+	}
+	return fields, nil
+}
+
+// WriteMailMerge writes contacts as a CSV with a header row, one column per
+// field.
+func WriteMailMerge(w io.Writer, contacts []*Contact, fields []string) error {
+	out := csv.NewWriter(w)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		column, ok := mailMergeColumns[field]
+		if !ok {
+			return fmt.Errorf("unknown mail-merge field: %s", field)
+		}
+		header[i] = column.header
+	}
+	if err := out.Write(header); err != nil {
+		return fmt.Errorf("failed to write mail merge: %w", err)
+	}
+
+	row := make([]string, len(fields))
+	for _, contact := range contacts {
+		for i, field := range fields {
+			row[i] = mailMergeColumns[field].value(contact)
+		}
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("failed to write mail merge: %w", err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write mail merge: %w", err)
+	}
+	return nil
+}
+
+// splitName splits a name into its first word and the rest.
+func splitName(name string) (first, last string) {
+	first, last, _ = strings.Cut(strings.TrimSpace(name), " ")
+	return first, strings.TrimSpace(last)
+}
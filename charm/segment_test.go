@@ -0,0 +1,73 @@
+// ABOUTME: Tests for contact segments and the mail-merge CSV
+// ABOUTME: Verifies do-not-contact suppression, skipped contacts, field parsing, and the CSV columns
+
+package charm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestContactSegment(t *testing.T) {
+	client := NewTestClient(t)
+
+	for _, contact := range []*Contact{
+		{Name: "Ada Lovelace", Email: "ada@example.com", CompanyName: "Engines, Ltd", Tags: []string{"investor"}},
+		{Name: "Grace Hopper", Email: "grace@example.com", Tags: []string{"investor", "do-not-contact"}},
+		{Name: "Alan Turing", Tags: []string{"investor"}},
+		{Name: "Ada L.", Email: "ADA@example.com", Tags: []string{"investor"}},
+		{Name: "Charles Babbage", Email: "charles@example.com"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	segment, err := client.ContactSegment("tag:investor")
+	if err != nil {
+		t.Fatalf("ContactSegment failed: %v", err)
+	}
+	if len(segment.Contacts) != 1 || segment.Contacts[0].Name != "Ada L." {
+		t.Fatalf("expected only the first Ada by name, got %+v", segment.Contacts)
+	}
+	if segment.Suppressed != 1 || segment.NoEmail != 1 || segment.Duplicates != 1 {
+		t.Errorf("unexpected counts: %+v", segment)
+	}
+
+	everyone, err := client.ContactSegment("")
+	if err != nil {
+		t.Fatalf("ContactSegment failed: %v", err)
+	}
+	if len(everyone.Contacts) != 2 {
+		t.Errorf("expected 2 contacts in the whole list, got %d", len(everyone.Contacts))
+	}
+
+	if _, err := client.ContactSegment("last_contacted:<soon"); err == nil {
+		t.Error("expected an invalid query to fail")
+	}
+}
+
+func TestWriteMailMerge(t *testing.T) {
+	fields, err := ParseMailMergeFields("")
+	if err != nil || len(fields) != len(DefaultMailMergeFields) {
+		t.Fatalf("expected the default fields, got %v, %v", fields, err)
+	}
+	if _, err := ParseMailMergeFields("email,shoe_size"); err == nil {
+		t.Error("expected an unknown field to fail")
+	}
+
+	contacts := []*Contact{
+		{Name: "Ada King Lovelace", Email: "ada@example.com", CompanyName: "Engines, Ltd", Title: "Analyst"},
+		{Name: "Cher", Email: "cher@example.com"},
+	}
+	var buf bytes.Buffer
+	if err := WriteMailMerge(&buf, contacts, fields); err != nil {
+		t.Fatalf("WriteMailMerge failed: %v", err)
+	}
+	want := "First Name,Last Name,Email Address,Company,Title\n" +
+		"Ada,King Lovelace,ada@example.com,\"Engines, Ltd\",Analyst\n" +
+		"Cher,,cher@example.com,,\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%q\nwant\n%q", buf.String(), want)
+	}
+}
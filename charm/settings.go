@@ -0,0 +1,352 @@
+// ABOUTME: Portable settings bundle for moving a pagen setup between machines
+// ABOUTME: Exports rules, templates, preferences, and cadences without CRM data and merges them back in
+
+package charm
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SettingsBundleVersion is the version of the settings bundle layout.
+const SettingsBundleVersion = 1
+
+// SettingsBundle is the shareable part of a pagen setup. Connection settings,
+// web server settings, and credentials are left out.
+type SettingsBundle struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Rules
+	StageRequirements          map[string][]string   `json:"stage_requirements,omitempty"`
+	StageTimers                map[string]StageTimer `json:"stage_timers,omitempty"`
+	ArchivePolicy              *ArchivePolicy        `json:"archive_policy,omitempty"`
+	Holidays                   []Holiday             `json:"holidays,omitempty"`
+	News                       *NewsConfig           `json:"news,omitempty"`
+	ReplyWaitDays              int                   `json:"reply_wait_days,omitempty"`
+	RelationshipRevalidateDays int                   `json:"relationship_revalidate_days,omitempty"`
+	OutreachGoals              map[string]int        `json:"outreach_goals,omitempty"`
+	MetAnniversaries           map[string][]int      `json:"met_anniversaries,omitempty"`
+	DuplicateEmails            map[string]string     `json:"duplicate_emails,omitempty"`
+
+	// Templates
+	GreetingTemplates map[string]string        `json:"greeting_templates,omitempty"`
+	EmailTemplates    map[string]EmailTemplate `json:"email_templates,omitempty"`
+	EmailSender       string                   `json:"email_sender,omitempty"`
+
+	// Preferences
+	Locale           string `json:"locale,omitempty"`
+	IDDisplay        string `json:"id_display,omitempty"`
+	StrictResolution *bool  `json:"strict_resolution,omitempty"`
+	RevisionLimit    int    `json:"revision_limit,omitempty"`
+
+	// Cadences are matched to contacts by email, then name, on import
+	Cadences []CadenceSetting `json:"cadences,omitempty"`
+}
+
+// CadenceSetting is a contact's follow-up cadence, identified by email and name
+// instead of ID so it can be applied to another copy of the contact.
+type CadenceSetting struct {
+	Contact              string `json:"contact"`
+	Email                string `json:"email,omitempty"`
+	CadenceDays          int    `json:"cadence_days"`
+	RelationshipStrength string `json:"relationship_strength,omitempty"`
+}
+
+// NewSettingsBundle copies the shareable settings out of cfg.
+func NewSettingsBundle(cfg *Config, now time.Time) *SettingsBundle {
+	strict := cfg.StrictResolution
+	return &SettingsBundle{
+		Version:                    SettingsBundleVersion,
+		ExportedAt:                 now,
+		StageRequirements:          cfg.StageRequirements,
+		StageTimers:                cfg.StageTimers,
+		ArchivePolicy:              cfg.ArchivePolicy,
+		Holidays:                   cfg.Holidays,
+		News:                       cfg.News,
+		ReplyWaitDays:              cfg.ReplyWaitDays,
+		RelationshipRevalidateDays: cfg.RelationshipRevalidateDays,
+		OutreachGoals:              cfg.OutreachGoals,
+		MetAnniversaries:           cfg.MetAnniversaries,
+		DuplicateEmails:            cfg.DuplicateEmails,
+		GreetingTemplates:          cfg.GreetingTemplates,
+		EmailTemplates:             cfg.EmailTemplates,
+		EmailSender:                cfg.EmailSender,
+		Locale:                     cfg.Locale,
+		IDDisplay:                  cfg.IDDisplay,
+		StrictResolution:           &strict,
+		RevisionLimit:              cfg.RevisionLimit,
+	}
+}
+
+// ExportCadences adds every contact cadence to the bundle, sorted by contact name.
+func (c *Client) ExportCadences(bundle *SettingsBundle) error {
+	cadences, err := c.ListContactCadences()
+	if err != nil {
+		return fmt.Errorf("failed to list cadences: %w", err)
+	}
+	for _, cadence := range cadences {
+		contact, err := c.GetContact(cadence.ContactID)
+		if err != nil {
+			continue // Skip cadences of deleted contacts
+		}
+		bundle.Cadences = append(bundle.Cadences, CadenceSetting{
+			Contact:              contact.Name,
+			Email:                contact.Email,
+			CadenceDays:          cadence.CadenceDays,
+			RelationshipStrength: cadence.RelationshipStrength,
+		})
+	}
+	sort.Slice(bundle.Cadences, func(i, j int) bool {
+		return bundle.Cadences[i].Contact < bundle.Cadences[j].Contact
+	})
+	return nil
+}
+
+// Validate checks a bundle before it is applied.
+func (b *SettingsBundle) Validate() error {
+	if b.Version == 0 || b.Version > SettingsBundleVersion {
+		return fmt.Errorf("unsupported settings bundle version %d (this pagen reads version %d)", b.Version, SettingsBundleVersion)
+	}
+	if err := ValidateStageRequirementsConfig(b.StageRequirements); err != nil {
+		return err
+	}
+	if err := ValidateStageTimersConfig(b.StageTimers); err != nil {
+		return fmt.Errorf("stage_timers: %w", err)
+	}
+	for entryPoint, policy := range b.DuplicateEmails {
+		if err := ValidateDuplicateEmailPolicy(policy); err != nil {
+			return fmt.Errorf("duplicate_emails %s: %w", entryPoint, err)
+		}
+	}
+	for tag, target := range b.OutreachGoals {
+		if target <= 0 {
+			return fmt.Errorf("outreach_goals: %s needs a positive weekly target", tag)
+		}
+	}
+	if err := ValidateMetAnniversaries(b.MetAnniversaries); err != nil {
+		return err
+	}
+	for _, holiday := range b.Holidays {
+		if _, _, err := ParseMonthDay(holiday.Date); err != nil {
+			return fmt.Errorf("holiday %s: %w", holiday.Name, err)
+		}
+	}
+	for _, cadence := range b.Cadences {
+		if cadence.CadenceDays <= 0 {
+			return fmt.Errorf("cadences: %s needs a positive cadence_days", cadence.Contact)
+		}
+	}
+	return nil
+}
+
+// MergeInto applies the bundle's settings to cfg and describes each change.
+// Bundle entries win over existing ones with the same key (stage, template
+// name, holiday name and date, feed URL); everything else in cfg is kept.
+func (b *SettingsBundle) MergeInto(cfg *Config) []string {
+	var changes []string
+
+	if len(b.StageRequirements) > 0 {
+		if cfg.StageRequirements == nil {
+			cfg.StageRequirements = make(map[string][]string)
+		}
+		for stage, fields := range b.StageRequirements {
+			cfg.StageRequirements[stage] = fields
+		}
+		changes = append(changes, "stage requirements: "+strings.Join(sortedKeys(b.StageRequirements), ", "))
+	}
+	if len(b.StageTimers) > 0 {
+		if cfg.StageTimers == nil {
+			cfg.StageTimers = make(map[string]StageTimer)
+		}
+		for stage, timer := range b.StageTimers {
+			cfg.StageTimers[stage] = timer
+		}
+		changes = append(changes, "stage timers: "+strings.Join(sortedKeys(b.StageTimers), ", "))
+	}
+	if b.ArchivePolicy != nil {
+		policy := *b.ArchivePolicy
+		cfg.ArchivePolicy = &policy
+		changes = append(changes, "archive policy")
+	}
+	if added := mergeHolidays(cfg, b.Holidays); added > 0 {
+		changes = append(changes, fmt.Sprintf("holidays: %d added", added))
+	}
+	if b.News != nil {
+		if cfg.News == nil {
+			cfg.News = &NewsConfig{}
+		}
+		added := 0
+		for _, feed := range b.News.Feeds {
+			if !slices.Contains(cfg.News.Feeds, feed) {
+				cfg.News.Feeds = append(cfg.News.Feeds, feed)
+				added++
+			}
+		}
+		if b.News.MaxItems > 0 {
+			cfg.News.MaxItems = b.News.MaxItems
+		}
+		if b.News.MaxAgeDays > 0 {
+			cfg.News.MaxAgeDays = b.News.MaxAgeDays
+		}
+		changes = append(changes, fmt.Sprintf("news: %d feed(s) added", added))
+	}
+
+	if len(b.OutreachGoals) > 0 {
+		if cfg.OutreachGoals == nil {
+			cfg.OutreachGoals = make(map[string]int)
+		}
+		for tag, target := range b.OutreachGoals {
+			cfg.OutreachGoals[tag] = target
+		}
+		changes = append(changes, "outreach goals: "+strings.Join(sortedKeys(b.OutreachGoals), ", "))
+	}
+
+	if len(b.MetAnniversaries) > 0 {
+		if cfg.MetAnniversaries == nil {
+			cfg.MetAnniversaries = make(map[string][]int)
+		}
+		for tag, years := range b.MetAnniversaries {
+			cfg.MetAnniversaries[tag] = years
+		}
+		changes = append(changes, "met anniversaries: "+strings.Join(sortedKeys(b.MetAnniversaries), ", "))
+	}
+
+	if len(b.DuplicateEmails) > 0 {
+		if cfg.DuplicateEmails == nil {
+			cfg.DuplicateEmails = make(map[string]string)
+		}
+		for entryPoint, policy := range b.DuplicateEmails {
+			cfg.DuplicateEmails[entryPoint] = policy
+		}
+		changes = append(changes, "duplicate emails: "+strings.Join(sortedKeys(b.DuplicateEmails), ", "))
+	}
+	if len(b.GreetingTemplates) > 0 {
+		if cfg.GreetingTemplates == nil {
+			cfg.GreetingTemplates = make(map[string]string)
+		}
+		for kind, text := range b.GreetingTemplates {
+			cfg.GreetingTemplates[kind] = text
+		}
+		changes = append(changes, "greeting templates: "+strings.Join(sortedKeys(b.GreetingTemplates), ", "))
+	}
+	if len(b.EmailTemplates) > 0 {
+		if cfg.EmailTemplates == nil {
+			cfg.EmailTemplates = make(map[string]EmailTemplate)
+		}
+		for name, tmpl := range b.EmailTemplates {
+			cfg.EmailTemplates[name] = tmpl
+		}
+		changes = append(changes, "email templates: "+strings.Join(sortedKeys(b.EmailTemplates), ", "))
+	}
+
+	setString := func(name string, dst *string, value string) {
+		if value != "" && *dst != value {
+			*dst = value
+			changes = append(changes, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	setString("email sender", &cfg.EmailSender, b.EmailSender)
+	setString("locale", &cfg.Locale, b.Locale)
+	setString("id display", &cfg.IDDisplay, b.IDDisplay)
+	if b.StrictResolution != nil && cfg.StrictResolution != *b.StrictResolution {
+		cfg.StrictResolution = *b.StrictResolution
+		changes = append(changes, fmt.Sprintf("strict resolution: %v", cfg.StrictResolution))
+	}
+	if b.ReplyWaitDays > 0 && cfg.ReplyWaitDays != b.ReplyWaitDays {
+		cfg.ReplyWaitDays = b.ReplyWaitDays
+		changes = append(changes, fmt.Sprintf("reply wait: %d days", b.ReplyWaitDays))
+	}
+	if b.RelationshipRevalidateDays > 0 && cfg.RelationshipRevalidateDays != b.RelationshipRevalidateDays {
+		cfg.RelationshipRevalidateDays = b.RelationshipRevalidateDays
+		changes = append(changes, fmt.Sprintf("relationship revalidation: %d days", b.RelationshipRevalidateDays))
+	}
+	if b.RevisionLimit > 0 && cfg.RevisionLimit != b.RevisionLimit {
+		cfg.RevisionLimit = b.RevisionLimit
+		changes = append(changes, fmt.Sprintf("revision limit: %d", b.RevisionLimit))
+	}
+
+	return changes
+}
+
+// mergeHolidays adds holidays missing from cfg and returns how many were added.
+func mergeHolidays(cfg *Config, holidays []Holiday) int {
+	added := 0
+	for _, holiday := range holidays {
+		exists := false
+		for _, existing := range cfg.Holidays {
+			if strings.EqualFold(existing.Name, holiday.Name) && existing.Date == holiday.Date {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			cfg.Holidays = append(cfg.Holidays, holiday)
+			added++
+		}
+	}
+	return added
+}
+
+// ImportCadences applies cadences to the contacts they match, by email and
+// then exact name, keeping each contact's interaction history. It returns
+// how many were applied and the contacts that matched nothing. With dryRun
+// nothing is saved.
+func (c *Client) ImportCadences(settings []CadenceSetting, dryRun bool) (int, []string, error) {
+	applied := 0
+	var unmatched []string
+	for _, setting := range settings {
+		contact, err := c.FindContactByEmail(setting.Email)
+		if err != nil {
+			return applied, unmatched, fmt.Errorf("failed to lookup contact: %w", err)
+		}
+		if contact == nil {
+			contact, err = c.FindContactByName(setting.Contact)
+			if err != nil {
+				return applied, unmatched, fmt.Errorf("failed to lookup contact: %w", err)
+			}
+		}
+		if contact == nil {
+			unmatched = append(unmatched, setting.Contact)
+			continue
+		}
+		applied++
+		if dryRun {
+			continue
+		}
+
+		cadence, err := c.GetContactCadence(contact.ID)
+		if err != nil {
+			return applied, unmatched, fmt.Errorf("failed to get cadence: %w", err)
+		}
+		if cadence == nil {
+			cadence = &ContactCadence{ContactID: contact.ID, ContactName: contact.Name}
+		}
+		cadence.CadenceDays = setting.CadenceDays
+		cadence.RelationshipStrength = setting.RelationshipStrength
+		if cadence.RelationshipStrength == "" {
+			cadence.RelationshipStrength = StrengthMedium
+		}
+		if cadence.LastInteractionDate != nil {
+			next := cadence.LastInteractionDate.AddDate(0, 0, cadence.CadenceDays)
+			cadence.NextFollowupDate = &next
+		}
+		cadence.PriorityScore = ComputePriorityScore(cadence, time.Now())
+		if err := c.SaveContactCadence(cadence); err != nil {
+			return applied, unmatched, fmt.Errorf("failed to save cadence: %w", err)
+		}
+	}
+	return applied, unmatched, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+// ABOUTME: Tests for the settings bundle
+// ABOUTME: Verifies export, validation, merging into a config, and applying cadences by email or name
+package charm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettingsBundleMergeInto(t *testing.T) {
+	source := &Config{
+		StageRequirements: map[string][]string{StageProposal: {DealFieldAmount}},
+		ArchivePolicy:     &ArchivePolicy{Enabled: true, Months: 12},
+		Holidays:          []Holiday{{Name: "Founders Day", Date: "03-14"}},
+		News:              &NewsConfig{Feeds: []string{"https://a.example/feed", "https://b.example/feed"}, MaxItems: 3},
+		GreetingTemplates: map[string]string{"birthday": "Happy birthday {first_name}!"},
+		EmailTemplates:    map[string]EmailTemplate{"intro": {Subject: "Hi", Body: "Hello"}},
+		Locale:            "en-GB",
+		StrictResolution:  true,
+		RevisionLimit:     20,
+		Host:              "cloud.charm.sh",
+	}
+	bundle := NewSettingsBundle(source, time.Now())
+
+	// The bundle survives a JSON round trip and leaves out the host
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("failed to encode bundle: %v", err)
+	}
+	if strings.Contains(string(data), "cloud.charm.sh") {
+		t.Error("bundle should not include the Charm host")
+	}
+	var decoded SettingsBundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Fatalf("bundle should be valid: %v", err)
+	}
+
+	target := &Config{
+		Holidays:          []Holiday{{Name: "Founders Day", Date: "03-14"}, {Name: "Local", Date: "06-01"}},
+		News:              &NewsConfig{Feeds: []string{"https://a.example/feed"}},
+		GreetingTemplates: map[string]string{"birthday": "old", "anniversary": "kept"},
+		Locale:            "en-US",
+	}
+	changes := decoded.MergeInto(target)
+	if len(changes) == 0 {
+		t.Fatal("expected changes")
+	}
+	if len(target.Holidays) != 2 {
+		t.Errorf("expected holidays to be merged without duplicates, got %v", target.Holidays)
+	}
+	if len(target.News.Feeds) != 2 || target.News.MaxItems != 3 {
+		t.Errorf("expected feeds to be merged, got %+v", target.News)
+	}
+	if target.GreetingTemplates["birthday"] != "Happy birthday {first_name}!" || target.GreetingTemplates["anniversary"] != "kept" {
+		t.Errorf("unexpected greeting templates: %v", target.GreetingTemplates)
+	}
+	if target.Locale != "en-GB" || !target.StrictResolution || target.RevisionLimit != 20 {
+		t.Errorf("expected preferences to be applied, got %+v", target)
+	}
+	if target.ArchivePolicy == nil || target.ArchivePolicy.Months != 12 {
+		t.Errorf("expected the archive policy, got %+v", target.ArchivePolicy)
+	}
+
+	for _, change := range decoded.MergeInto(target) {
+		if strings.HasPrefix(change, "holidays") || strings.HasPrefix(change, "locale") {
+			t.Errorf("unchanged settings should not be reported again: %s", change)
+		}
+	}
+}
+
+func TestSettingsBundleValidate(t *testing.T) {
+	tests := map[string]*SettingsBundle{
+		"version":  {Version: SettingsBundleVersion + 1},
+		"stage":    {Version: 1, StageRequirements: map[string][]string{"pondering": {DealFieldAmount}}},
+		"holiday":  {Version: 1, Holidays: []Holiday{{Name: "Bad", Date: "13-40"}}},
+		"cadences": {Version: 1, Cadences: []CadenceSetting{{Contact: "Dana", CadenceDays: 0}}},
+	}
+	for name, bundle := range tests {
+		if err := bundle.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExportImportCadences(t *testing.T) {
+	source := NewTestClient(t)
+	dana := &Contact{Name: "Dana Scully", Email: "dana@fbi.gov"}
+	if err := source.CreateContact(dana); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	if err := source.SaveContactCadence(&ContactCadence{ContactID: dana.ID, ContactName: dana.Name, CadenceDays: 14, RelationshipStrength: StrengthStrong}); err != nil {
+		t.Fatalf("failed to save cadence: %v", err)
+	}
+	bundle := NewSettingsBundle(&Config{}, time.Now())
+	if err := source.ExportCadences(bundle); err != nil {
+		t.Fatalf("ExportCadences failed: %v", err)
+	}
+	bundle.Cadences = append(bundle.Cadences, CadenceSetting{Contact: "Fox Mulder", CadenceDays: 7})
+	if len(bundle.Cadences) != 2 || bundle.Cadences[0].Email != "dana@fbi.gov" {
+		t.Fatalf("unexpected cadences: %+v", bundle.Cadences)
+	}
+
+	// The target has Dana under a different email, so the match is by name
+	target := NewTestClient(t)
+	scully := &Contact{Name: "Dana Scully", Email: "scully@example.com"}
+	if err := target.CreateContact(scully); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	applied, unmatched, err := target.ImportCadences(bundle.Cadences, true)
+	if err != nil || applied != 1 || len(unmatched) != 1 || unmatched[0] != "Fox Mulder" {
+		t.Fatalf("unexpected dry run: %d, %v, %v", applied, unmatched, err)
+	}
+	if cadence, _ := target.GetContactCadence(scully.ID); cadence != nil {
+		t.Fatal("dry run should not save a cadence")
+	}
+
+	if _, _, err := target.ImportCadences(bundle.Cadences, false); err != nil {
+		t.Fatalf("ImportCadences failed: %v", err)
+	}
+	cadence, err := target.GetContactCadence(scully.ID)
+	if err != nil || cadence == nil || cadence.CadenceDays != 14 || cadence.RelationshipStrength != StrengthStrong {
+		t.Errorf("expected Dana's cadence to be applied, got %+v, %v", cadence, err)
+	}
+}
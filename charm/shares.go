@@ -0,0 +1,335 @@
+// ABOUTME: Read-only share links for a deal's or company's status and timeline
+// ABOUTME: Each share has an unguessable token; the web server renders it for people without pagen
+
+package charm
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// shareTimelineLimit caps the entries shown on a share page.
+const shareTimelineLimit = 50
+
+// Share is a read-only link to one deal's or company's status. Anyone with the
+// token can view it, so it lists only what a share page shows and can expire.
+type Share struct {
+	ID         uuid.UUID  `json:"id"`
+	Token      string     `json:"token"`
+	EntityType string     `json:"entity_type"` // deal or company
+	ObjectID   uuid.UUID  `json:"object_id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the share has passed its expiry.
+func (s *Share) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// CreateShare creates a share link for a deal or company, valid for ttl
+// (forever when zero).
+func (c *Client) CreateShare(entityType string, id uuid.UUID, ttl time.Duration) (*Share, error) {
+	var name string
+	switch entityType {
+	case EntityDeal:
+		deal, err := c.GetDeal(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deal: %w", err)
+		}
+		name = deal.Title
+	case EntityCompany:
+		company, err := c.GetCompany(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get company: %w", err)
+		}
+		name = company.Name
+	default:
+		return nil, fmt.Errorf("cannot share entity type: %s (use %s or %s)", entityType, EntityDeal, EntityCompany)
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	share := &Share{
+		ID:         uuid.New(),
+		Token:      base64.RawURLEncoding.EncodeToString(raw),
+		EntityType: entityType,
+		ObjectID:   id,
+		Name:       name,
+		CreatedAt:  time.Now(),
+	}
+	if ttl > 0 {
+		expires := share.CreatedAt.Add(ttl)
+		share.ExpiresAt = &expires
+	}
+
+	data, err := json.Marshal(share)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal share: %w", err)
+	}
+	if err := c.Set(ShareKey(share.ID.String()), data); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// ListShares returns all shares, expired ones included, oldest first.
+func (c *Client) ListShares() ([]*Share, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixShare))
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []*Share
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var share Share
+		if err := json.Unmarshal(data, &share); err != nil {
+			continue
+		}
+		shares = append(shares, &share)
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+// FindShare returns the unexpired share with token, or nil if there is none.
+func (c *Client) FindShare(token string) (*Share, error) {
+	if token == "" {
+		return nil, nil
+	}
+	shares, err := c.ListShares()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, share := range shares {
+		if subtle.ConstantTimeCompare([]byte(share.Token), []byte(token)) == 1 {
+			if share.Expired(now) {
+				return nil, nil
+			}
+			return share, nil
+		}
+	}
+	return nil, nil
+}
+
+// RevokeShare deletes the share whose ID starts with ref, or whose token is
+// ref, and returns it.
+func (c *Client) RevokeShare(ref string) (*Share, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, Validationf("share ID is required")
+	}
+	shares, err := c.ListShares()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Share
+	for _, share := range shares {
+		if share.Token == ref || (len(ref) >= MinIDPrefix && strings.HasPrefix(share.ID.String(), strings.ToLower(ref))) {
+			matches = append(matches, share)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no share matches %s", ref)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d shares match %s; use more of the ID", len(matches), ref)
+	}
+
+	if err := c.Delete(ShareKey(matches[0].ID.String())); err != nil {
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+// ShareStatus is what a share page shows: a few headline facts and a
+// timeline of what happened, newest first.
+type ShareStatus struct {
+	Kind      string // deal or company
+	Title     string
+	Subtitle  string
+	Facts     []ShareFact
+	Timeline  []ShareEntry
+	UpdatedAt time.Time
+}
+
+// ShareFact is a labelled headline value, e.g. Stage: negotiation.
+type ShareFact struct {
+	Label string
+	Value string
+}
+
+// ShareEntry is one timeline event. Markdown entries are deal notes, which the
+// page renders like the deal view does.
+type ShareEntry struct {
+	Time     time.Time
+	Text     string
+	Detail   string
+	Markdown bool
+}
+
+// ShareStatus builds the status page for a share from the current state of
+// its deal or company.
+func (c *Client) ShareStatus(share *Share) (*ShareStatus, error) {
+	var status *ShareStatus
+	var err error
+	switch share.EntityType {
+	case EntityDeal:
+		status, err = c.dealShareStatus(share.ObjectID)
+	case EntityCompany:
+		status, err = c.companyShareStatus(share.ObjectID)
+	default:
+		return nil, fmt.Errorf("cannot share entity type: %s", share.EntityType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(status.Timeline, func(i, j int) bool {
+		return status.Timeline[i].Time.After(status.Timeline[j].Time)
+	})
+	if len(status.Timeline) > shareTimelineLimit {
+		status.Timeline = status.Timeline[:shareTimelineLimit]
+	}
+	return status, nil
+}
+
+func (c *Client) dealShareStatus(id uuid.UUID) (*ShareStatus, error) {
+	deal, err := c.GetDeal(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	status := &ShareStatus{
+		Kind:      EntityDeal,
+		Title:     deal.Title,
+		Subtitle:  deal.CompanyName,
+		UpdatedAt: deal.UpdatedAt,
+		Facts: []ShareFact{
+			{Label: "Stage", Value: deal.Stage},
+			{Label: "Amount", Value: FormatMoney(deal.Amount, deal.Currency)},
+		},
+	}
+	if deal.ExpectedCloseDate != nil {
+		status.Facts = append(status.Facts, ShareFact{Label: "Expected close", Value: deal.ExpectedCloseDate.Format("2006-01-02")})
+	}
+	if deal.CloseReason != "" {
+		status.Facts = append(status.Facts, ShareFact{Label: "Close reason", Value: deal.CloseReason})
+	}
+
+	if status.Subtitle == "" && deal.CompanyID != uuid.Nil {
+		if company, err := c.GetCompany(deal.CompanyID); err == nil {
+			status.Subtitle = company.Name
+		}
+	}
+
+	status.Timeline = append(status.Timeline, c.dealStageEntries(deal)...)
+	notes, err := c.ListDealNotes(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal notes: %w", err)
+	}
+	for _, note := range notes {
+		status.Timeline = append(status.Timeline, ShareEntry{Time: note.CreatedAt, Text: "Note", Detail: note.Content, Markdown: true})
+	}
+	return status, nil
+}
+
+// dealStageEntries lists when the deal was opened and each stage change
+// still in its revision history.
+func (c *Client) dealStageEntries(deal *Deal) []ShareEntry {
+	entries := []ShareEntry{{Time: deal.CreatedAt, Text: "Deal opened"}}
+
+	revisions, err := c.ListRevisions(deal.ID)
+	if err != nil {
+		return entries
+	}
+	prevStage := ""
+	for _, rev := range revisions {
+		var fields struct {
+			Stage string `json:"stage"`
+		}
+		if err := json.Unmarshal(rev.Data, &fields); err != nil || fields.Stage == "" {
+			continue
+		}
+		if prevStage != "" && fields.Stage != prevStage {
+			entries = append(entries, ShareEntry{Time: rev.CreatedAt, Text: fmt.Sprintf("Moved from %s to %s", prevStage, fields.Stage)})
+		}
+		prevStage = fields.Stage
+	}
+	return entries
+}
+
+func (c *Client) companyShareStatus(id uuid.UUID) (*ShareStatus, error) {
+	company, err := c.GetCompany(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	status := &ShareStatus{
+		Kind:      EntityCompany,
+		Title:     company.Name,
+		Subtitle:  company.Industry,
+		UpdatedAt: company.UpdatedAt,
+	}
+
+	deals, err := c.ListDeals(&DealFilter{CompanyID: &id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	for _, deal := range deals {
+		status.Facts = append(status.Facts, ShareFact{Label: deal.Title, Value: deal.Stage + ", " + FormatMoney(deal.Amount, deal.Currency)})
+		for _, entry := range c.dealStageEntries(deal) {
+			entry.Text = deal.Title + ": " + entry.Text
+			status.Timeline = append(status.Timeline, entry)
+		}
+	}
+
+	contacts, err := c.ListContacts(&ContactFilter{CompanyID: &id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	for _, contact := range contacts {
+		logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &contact.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list interactions: %w", err)
+		}
+		for _, log := range logs {
+			entry := ShareEntry{Time: log.Timestamp, Text: interactionLabel(log.InteractionType) + " with " + contact.Name}
+			if log.NextStep != "" {
+				entry.Detail = "Next step: " + log.NextStep
+			}
+			status.Timeline = append(status.Timeline, entry)
+		}
+	}
+	return status, nil
+}
+
+// interactionLabel capitalizes an interaction type for display, e.g. "Meeting".
+func interactionLabel(interactionType string) string {
+	if interactionType == "" {
+		return "Interaction"
+	}
+	return strings.ToUpper(interactionType[:1]) + interactionType[1:]
+}
@@ -0,0 +1,158 @@
+// ABOUTME: Tests for read-only share links
+// ABOUTME: Verifies token lookup, expiry, revocation, and the status timelines for deals and companies
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestShareLifecycle(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Renewal", Amount: 500000, Currency: "USD", Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	share, err := client.CreateShare(EntityDeal, deal.ID, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	if share.Name != "Renewal" || len(share.Token) < 32 || share.ExpiresAt == nil {
+		t.Errorf("unexpected share: %+v", share)
+	}
+
+	found, err := client.FindShare(share.Token)
+	if err != nil || found == nil || found.ID != share.ID {
+		t.Fatalf("FindShare = %+v, %v; want the share", found, err)
+	}
+	for _, token := range []string{"", "nope", share.Token[:len(share.Token)-1]} {
+		if found, _ := client.FindShare(token); found != nil {
+			t.Errorf("FindShare(%q) should find nothing", token)
+		}
+	}
+	if !share.Expired(share.ExpiresAt.Add(time.Second)) || share.Expired(time.Now()) {
+		t.Error("share should expire at ExpiresAt and not before")
+	}
+
+	if _, err := client.CreateShare(EntityContact, deal.ID, 0); err == nil {
+		t.Error("sharing a contact should fail")
+	}
+	if _, err := client.CreateShare(EntityCompany, uuid.New(), 0); err == nil {
+		t.Error("sharing a missing company should fail")
+	}
+
+	revoked, err := client.RevokeShare(share.ID.String()[:8])
+	if err != nil || revoked.ID != share.ID {
+		t.Fatalf("RevokeShare = %+v, %v", revoked, err)
+	}
+	if found, _ := client.FindShare(share.Token); found != nil {
+		t.Error("revoked share should not be found")
+	}
+	if _, err := client.RevokeShare(share.ID.String()); err == nil {
+		t.Error("revoking twice should fail")
+	}
+}
+
+func TestShareExpired(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	share, err := client.CreateShare(EntityCompany, company.ID, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if found, _ := client.FindShare(share.Token); found != nil {
+		t.Error("expired share should not be found")
+	}
+}
+
+func TestShareStatus(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme", Industry: "Robotics"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	contact := &Contact{Name: "Ada", CompanyID: &company.ID}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	deal := &Deal{Title: "Pilot", Amount: 100000, Currency: "USD", Stage: StageProspecting, CompanyID: company.ID}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	deal.Stage = StageNegotiation
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("failed to update deal: %v", err)
+	}
+	if err := client.CreateDealNote(&DealNote{DealID: deal.ID, Content: "Legal review **done**"}); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: contact.ID, InteractionType: "meeting", Timestamp: time.Now(), Notes: "private gossip", NextStep: "Send order form"}); err != nil {
+		t.Fatalf("failed to log interaction: %v", err)
+	}
+
+	dealShare, err := client.CreateShare(EntityDeal, deal.ID, 0)
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	status, err := client.ShareStatus(dealShare)
+	if err != nil {
+		t.Fatalf("ShareStatus failed: %v", err)
+	}
+	if status.Title != "Pilot" || status.Subtitle != "Acme" || status.Facts[0].Value != StageNegotiation {
+		t.Errorf("unexpected deal status: %+v", status)
+	}
+	texts := shareEntryTexts(status)
+	for _, want := range []string{"Deal opened", "Moved from " + StageProspecting + " to " + StageNegotiation, "Note"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("expected %q in deal timeline:\n%s", want, texts)
+		}
+	}
+
+	companyShare, err := client.CreateShare(EntityCompany, company.ID, 0)
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+	status, err = client.ShareStatus(companyShare)
+	if err != nil {
+		t.Fatalf("ShareStatus failed: %v", err)
+	}
+	texts = shareEntryTexts(status)
+	for _, want := range []string{"Meeting with Ada", "Next step: Send order form", "Pilot: Deal opened"} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("expected %q in company timeline:\n%s", want, texts)
+		}
+	}
+	if strings.Contains(texts, "private gossip") {
+		t.Error("interaction notes should not be shared")
+	}
+	if len(status.Facts) != 1 || status.Facts[0].Label != "Pilot" {
+		t.Errorf("expected the company's deal as a fact, got %+v", status.Facts)
+	}
+
+	if err := client.DeleteDeal(deal.ID); err != nil {
+		t.Fatalf("failed to delete deal: %v", err)
+	}
+	if _, err := client.ShareStatus(dealShare); err == nil {
+		t.Error("status of a deleted deal should fail")
+	}
+}
+
+func shareEntryTexts(status *ShareStatus) string {
+	var lines []string
+	for _, entry := range status.Timeline {
+		lines = append(lines, entry.Text+" | "+entry.Detail)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,690 @@
+// ABOUTME: Spreadsheet round-trip for contacts and deals: a CSV with object IDs to edit and import back
+// ABOUTME: Import applies only the cells that changed, matches rows by ID, and skips rows edited in pagen since the export
+
+package charm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SheetTypes lists the object types that round-trip through a sheet.
+var SheetTypes = []string{"contacts", "deals"}
+
+// sheetColumns are the sheet columns per type. Related records are named, not
+// referenced by ID, and amounts are whole units so the sheet reads naturally.
+var sheetColumns = map[string][]string{
+	"contacts": {"id", "name", "email", "phone", "title", "company", "tags", "notes", "birthday", "work_start_date",
+		"country", "met_via", "met_at", "met_date", "seniority", "function", "updated_at"},
+	"deals": {"id", "title", "stage", "amount", "currency", "probability", "company", "contact", "expected_close_date",
+		"close_reason", "source", "referrer", "tags", "updated_at"},
+}
+
+// sheetReadOnly columns are exported for reference and never imported.
+var sheetReadOnly = map[string]bool{"id": true, "updated_at": true}
+
+// ExportSheet writes every contact (archived ones left out) or deal of
+// sheetType to w as CSV for editing, and returns how many it wrote.
+func (c *Client) ExportSheet(w io.Writer, sheetType string) (int, error) {
+	columns, ok := sheetColumns[sheetType]
+	if !ok {
+		return 0, Validationf("invalid sheet type: %s (valid: %s)", sheetType, strings.Join(SheetTypes, ", "))
+	}
+	names, err := c.exportNames("deals")
+	if err != nil {
+		return 0, err
+	}
+
+	var rows []sheetRecord
+	switch sheetType {
+	case "contacts":
+		contacts, err := c.ListContacts(&ContactFilter{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		for _, contact := range contacts {
+			rows = append(rows, names.current(contact))
+		}
+	case "deals":
+		deals, err := c.ListDeals(&DealFilter{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list deals: %w", err)
+		}
+		for _, deal := range deals {
+			rows = append(rows, names.current(deal))
+		}
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return 0, fmt.Errorf("failed to write sheet: %w", err)
+	}
+	for _, record := range rows {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = record.sheetValue(column)
+		}
+		if err := out.Write(row); err != nil {
+			return 0, fmt.Errorf("failed to write sheet: %w", err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write sheet: %w", err)
+	}
+	return len(rows), nil
+}
+
+// SheetImportOptions controls ImportSheet.
+type SheetImportOptions struct {
+	DryRun bool // report the changes without writing them
+	Force  bool // apply rows even when the record changed in pagen since the export
+}
+
+// SheetImportResult summarizes an import. Rows are numbered as in a
+// spreadsheet, the header being row 1.
+type SheetImportResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Changes   []SheetChange
+	Problems  []string // rows left alone, and why
+}
+
+// SheetChange is one changed cell, or with no Column a created record.
+type SheetChange struct {
+	Row    int
+	ID     uuid.UUID
+	Name   string
+	Column string
+	Old    string
+	New    string
+}
+
+// ImportSheet applies an edited sheet of sheetType. Rows are matched by ID,
+// and only cells that differ from the stored record (after normalizing, so a
+// reformatted amount or reordered tags don't count) are applied. When a
+// record changed in pagen after the sheet's updated_at, only cells edited in
+// the sheet are applied, and a cell edited on both sides is a conflict that
+// skips the row unless Force. Rows with no ID create records, unless one
+// already exists with the same email (contacts) or title and company (deals).
+// Rows missing from the sheet are left alone. Bad rows are reported in
+// Problems without stopping the import.
+func (c *Client) ImportSheet(r io.Reader, sheetType string, opts SheetImportOptions) (*SheetImportResult, error) {
+	columns, ok := sheetColumns[sheetType]
+	if !ok {
+		return nil, Validationf("invalid sheet type: %s (valid: %s)", sheetType, strings.Join(SheetTypes, ", "))
+	}
+
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	header, err := in.Read()
+	if err == io.EOF {
+		return nil, Validationf("sheet is empty")
+	}
+	if err != nil {
+		return nil, Validationf("failed to read sheet: %w", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(columns, name) {
+			return nil, Validationf("unknown %s sheet column %q (valid: %s)", sheetType, name, strings.Join(columns, ", "))
+		}
+		index[name] = i
+	}
+	if _, ok := index["id"]; !ok {
+		return nil, Validationf("sheet has no id column; start from pagen crm export sheet --type %s", sheetType)
+	}
+
+	// Compare with the names the export wrote, not stale copies
+	names, err := c.exportNames("deals")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SheetImportResult{}
+	seen := make(map[uuid.UUID]int)
+	for rowNum := 2; ; rowNum++ {
+		row, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, Validationf("failed to read sheet row %d: %w", rowNum, err)
+		}
+		cell := func(column string) (string, bool) {
+			i, ok := index[column]
+			if !ok || i >= len(row) {
+				return "", ok
+			}
+			return strings.TrimSpace(row[i]), true
+		}
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+
+		rawID, _ := cell("id")
+		if rawID == "" {
+			if err := c.importSheetRow(sheetType, nil, names, rowNum, cell, opts, result); err != nil {
+				return result, err
+			}
+			continue
+		}
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			result.Problems = append(result.Problems, fmt.Sprintf("row %d: invalid id %q", rowNum, rawID))
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			result.Problems = append(result.Problems, fmt.Sprintf("row %d: same id as row %d", rowNum, first))
+			continue
+		}
+		seen[id] = rowNum
+		if err := c.importSheetRow(sheetType, &id, names, rowNum, cell, opts, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// importSheetRow applies one row, creating the record when id is nil. Only
+// storage failures are returned; row problems go in the result.
+func (c *Client) importSheetRow(sheetType string, id *uuid.UUID, names *exportNames, rowNum int, cell func(string) (string, bool), opts SheetImportOptions, result *SheetImportResult) error {
+	problem := func(name string, err error) {
+		if name != "" {
+			result.Problems = append(result.Problems, fmt.Sprintf("row %d (%s): %v", rowNum, name, err))
+		} else {
+			result.Problems = append(result.Problems, fmt.Sprintf("row %d: %v", rowNum, err))
+		}
+	}
+
+	var record sheetRecord
+	switch {
+	case id == nil && sheetType == "contacts":
+		record = &Contact{}
+	case id == nil:
+		record = &Deal{Stage: StageProspecting}
+	case sheetType == "contacts":
+		contact, err := c.GetContact(*id)
+		if CodeOf(err) == CodeNotFound {
+			problem("", fmt.Errorf("no contact with id %s", *id))
+			return nil
+		} else if err != nil {
+			return err
+		}
+		record = names.current(contact)
+	default:
+		deal, err := c.GetDeal(*id)
+		if CodeOf(err) == CodeNotFound {
+			problem("", fmt.Errorf("no deal with id %s", *id))
+			return nil
+		} else if err != nil {
+			return err
+		}
+		record = names.current(deal)
+	}
+	oldName := record.sheetName()
+
+	// When the record changed after the export, compare the sheet with the
+	// record as exported so edits made in pagen since then aren't reverted
+	var base sheetRecord
+	stale := false
+	if exported, ok := cell("updated_at"); ok && exported != "" && id != nil {
+		at, err := time.Parse(time.RFC3339, exported)
+		if err != nil {
+			problem(oldName, fmt.Errorf("invalid updated_at %q", exported))
+			return nil
+		}
+		if record.updatedAt().Truncate(time.Second).After(at) {
+			stale = true
+			if base, err = c.sheetBase(sheetType, *id, at); err != nil {
+				return err
+			}
+			if base != nil {
+				names.current(base)
+			}
+		}
+	}
+
+	var changes []SheetChange
+	var conflicts []string
+	for _, column := range sheetColumns[sheetType] {
+		value, ok := cell(column)
+		if !ok || sheetReadOnly[column] {
+			continue
+		}
+		old := record.sheetValue(column)
+		if value == old {
+			continue
+		}
+		if base != nil {
+			was := base.sheetValue(column)
+			if err := base.setSheetValue(c, column, value); err != nil {
+				problem(oldName, fmt.Errorf("%s: %w", column, err))
+				return nil
+			}
+			if base.sheetValue(column) == was {
+				continue // not edited in the sheet
+			}
+			if old != was && !opts.Force {
+				conflicts = append(conflicts, column)
+				continue
+			}
+		}
+		if err := record.setSheetValue(c, column, value); err != nil {
+			problem(oldName, fmt.Errorf("%s: %w", column, err))
+			return nil
+		}
+		if updated := record.sheetValue(column); updated != old {
+			changes = append(changes, SheetChange{Row: rowNum, Column: column, Old: old, New: updated})
+		}
+	}
+
+	if id == nil {
+		if err := c.checkNewSheetRecord(record); err != nil {
+			problem(record.sheetName(), err)
+			return nil
+		}
+		if !opts.DryRun {
+			if err := record.create(c); err != nil {
+				problem(record.sheetName(), err)
+				return nil
+			}
+		}
+		result.Created++
+		result.Changes = append(result.Changes, SheetChange{Row: rowNum, ID: record.sheetID(), Name: record.sheetName()})
+		return nil
+	}
+
+	if len(conflicts) > 0 {
+		problem(oldName, Conflictf("%s changed in both pagen and the sheet; export again or use --force", strings.Join(conflicts, ", ")))
+		return nil
+	}
+	if len(changes) == 0 {
+		result.Unchanged++
+		return nil
+	}
+	if stale && base == nil && !opts.Force {
+		// Without the exported version there's no telling whose edit is newer
+		problem(oldName, Conflictf("changed in pagen since the export; export again or use --force"))
+		return nil
+	}
+
+	if !opts.DryRun {
+		if err := record.update(c); err != nil {
+			problem(oldName, err)
+			return nil
+		}
+		if name := record.sheetName(); name != oldName {
+			if err := record.renamed(c); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range changes {
+		changes[i].ID, changes[i].Name = record.sheetID(), record.sheetName()
+	}
+	result.Updated++
+	result.Changes = append(result.Changes, changes...)
+	return nil
+}
+
+// sheetBase returns the record as it was at (the sheet's updated_at), from
+// revision history, or nil when that revision is no longer kept.
+func (c *Client) sheetBase(sheetType string, id uuid.UUID, at time.Time) (sheetRecord, error) {
+	revisions, err := c.ListRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		var record sheetRecord = &Contact{}
+		if sheetType == "deals" {
+			record = &Deal{}
+		}
+		if err := json.Unmarshal(revisions[i].Data, record); err != nil {
+			continue
+		}
+		if !record.updatedAt().Truncate(time.Second).After(at) {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// current fills in the current names of a record's company and contacts, as
+// the sheet shows them.
+func (n *exportNames) current(record sheetRecord) sheetRecord {
+	switch r := record.(type) {
+	case *Contact:
+		if r.CompanyID != nil {
+			r.CompanyName = n.company(*r.CompanyID, r.CompanyName)
+		}
+	case *Deal:
+		r.CompanyName = n.company(r.CompanyID, r.CompanyName)
+		if r.ContactID != nil {
+			r.ContactName = n.contact(*r.ContactID, r.ContactName)
+		}
+		if r.ReferrerID != nil {
+			r.ReferrerName = n.contact(*r.ReferrerID, r.ReferrerName)
+		}
+	}
+	return record
+}
+
+// checkNewSheetRecord checks a new row has what creating needs and doesn't
+// duplicate an existing record.
+func (c *Client) checkNewSheetRecord(record sheetRecord) error {
+	switch r := record.(type) {
+	case *Contact:
+		if r.Name == "" {
+			return Validationf("name is required")
+		}
+		existing, err := c.FindContactByEmail(r.Email)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return Conflictf("%s is already %s; put its id %s in the id column", r.Email, existing.Name, existing.ID)
+		}
+	case *Deal:
+		if r.Title == "" {
+			return Validationf("title is required")
+		}
+		if r.CompanyID == uuid.Nil {
+			return Validationf("company is required")
+		}
+		deals, err := c.ListDeals(&DealFilter{CompanyID: &r.CompanyID})
+		if err != nil {
+			return err
+		}
+		for _, deal := range deals {
+			if strings.EqualFold(deal.Title, r.Title) {
+				return Conflictf("%s already has a deal %q; put its id %s in the id column", r.CompanyName, deal.Title, deal.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// sheetRecord is a contact or deal as sheet cells.
+type sheetRecord interface {
+	sheetID() uuid.UUID
+	sheetName() string
+	sheetValue(column string) string
+	setSheetValue(c *Client, column, value string) error
+	updatedAt() time.Time
+	create(c *Client) error
+	update(c *Client) error
+	renamed(c *Client) error
+}
+
+func (contact *Contact) sheetID() uuid.UUID     { return contact.ID }
+func (contact *Contact) sheetName() string      { return contact.Name }
+func (contact *Contact) updatedAt() time.Time   { return contact.UpdatedAt }
+func (contact *Contact) create(c *Client) error { return c.CreateContact(contact) }
+func (contact *Contact) update(c *Client) error { return c.UpdateContact(contact) }
+func (contact *Contact) renamed(c *Client) error {
+	return c.UpdateContactDenormalizedNames(contact.ID, contact.Name)
+}
+func (deal *Deal) sheetID() uuid.UUID      { return deal.ID }
+func (deal *Deal) sheetName() string       { return deal.Title }
+func (deal *Deal) updatedAt() time.Time    { return deal.UpdatedAt }
+func (deal *Deal) create(c *Client) error  { return c.CreateDeal(deal) }
+func (deal *Deal) update(c *Client) error  { return c.UpdateDeal(deal) }
+func (deal *Deal) renamed(c *Client) error { return c.UpdateDealDenormalizedNames(deal.ID, deal.Title) }
+
+func (contact *Contact) sheetValue(column string) string {
+	switch column {
+	case "id":
+		return contact.ID.String()
+	case "name":
+		return contact.Name
+	case "email":
+		return contact.Email
+	case "phone":
+		return contact.Phone
+	case "title":
+		return contact.Title
+	case "company":
+		return contact.CompanyName
+	case "tags":
+		return strings.Join(contact.Tags, ", ")
+	case "notes":
+		return contact.Notes
+	case "birthday":
+		return contact.Birthday
+	case "work_start_date":
+		return exportDate(contact.WorkStartDate)
+	case "country":
+		return contact.Country
+	case "met_via":
+		return contact.MetVia
+	case "met_at":
+		return contact.MetAt
+	case "met_date":
+		return exportDate(contact.MetDate)
+	case "seniority":
+		return contact.Seniority
+	case "function":
+		return contact.Function
+	case "updated_at":
+		return exportTime(&contact.UpdatedAt)
+	}
+	return ""
+}
+
+func (contact *Contact) setSheetValue(c *Client, column, value string) error {
+	var err error
+	switch column {
+	case "name":
+		if value == "" {
+			return Validationf("name can't be empty")
+		}
+		contact.Name = value
+	case "email":
+		contact.Email = value
+	case "phone":
+		contact.Phone = value
+	case "title":
+		contact.Title = value
+	case "company":
+		if value == "" {
+			contact.CompanyID, contact.CompanyName = nil, ""
+			return nil
+		}
+		company, err := c.sheetCompany(value)
+		if err != nil {
+			return err
+		}
+		contact.CompanyID, contact.CompanyName = &company.ID, company.Name
+	case "tags":
+		contact.Tags = sheetTags(value)
+	case "notes":
+		contact.Notes = value
+	case "birthday":
+		if value != "" {
+			if _, _, _, err := ParseBirthday(value); err != nil {
+				return err
+			}
+		}
+		contact.Birthday = value
+	case "work_start_date":
+		contact.WorkStartDate, err = sheetDate(value)
+	case "country":
+		contact.Country = strings.ToUpper(value)
+	case "met_via":
+		contact.MetVia = value
+	case "met_at":
+		contact.MetAt = value
+	case "met_date":
+		contact.MetDate, err = sheetDate(value)
+	case "seniority":
+		contact.Seniority, err = sheetChoice(value, Seniorities)
+	case "function":
+		contact.Function, err = sheetChoice(value, Functions)
+	}
+	return err
+}
+
+func (deal *Deal) sheetValue(column string) string {
+	switch column {
+	case "id":
+		return deal.ID.String()
+	case "title":
+		return deal.Title
+	case "stage":
+		return deal.Stage
+	case "amount":
+		return strconv.FormatFloat(float64(deal.Amount)/100, 'f', -1, 64)
+	case "currency":
+		return deal.Currency
+	case "probability":
+		if deal.Probability == nil {
+			return ""
+		}
+		return strconv.Itoa(*deal.Probability)
+	case "company":
+		return deal.CompanyName
+	case "contact":
+		return deal.ContactName
+	case "expected_close_date":
+		return exportDate(deal.ExpectedCloseDate)
+	case "close_reason":
+		return deal.CloseReason
+	case "source":
+		return deal.Source
+	case "referrer":
+		return deal.ReferrerName
+	case "tags":
+		return strings.Join(deal.Tags, ", ")
+	case "updated_at":
+		return exportTime(&deal.UpdatedAt)
+	}
+	return ""
+}
+
+func (deal *Deal) setSheetValue(c *Client, column, value string) error {
+	var err error
+	switch column {
+	case "title":
+		if value == "" {
+			return Validationf("title can't be empty")
+		}
+		deal.Title = value
+	case "stage":
+		stage := strings.ReplaceAll(strings.ToLower(value), " ", "_")
+		if !isDealStage(stage) {
+			return Validationf("invalid stage %q (valid: %s)", value, strings.Join(DealStages, ", "))
+		}
+		deal.Stage = stage
+	case "amount":
+		if value == "" {
+			deal.Amount = 0
+			return nil
+		}
+		amount, err := queryAmount(value)
+		if err != nil {
+			return Validationf("invalid amount %q (use whole units, e.g. 12500 or 12.5k)", value)
+		}
+		deal.Amount = amount
+	case "currency":
+		deal.Currency = strings.ToUpper(value)
+	case "probability":
+		if value == "" {
+			deal.Probability = nil
+			return nil
+		}
+		p, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || p < 0 || p > 100 {
+			return Validationf("invalid probability %q (use 0-100, or blank for the stage default)", value)
+		}
+		deal.Probability = &p
+	case "company":
+		if value == "" {
+			return Validationf("company can't be empty")
+		}
+		company, err := c.sheetCompany(value)
+		if err != nil {
+			return err
+		}
+		deal.CompanyID, deal.CompanyName = company.ID, company.Name
+	case "contact":
+		deal.ContactID, deal.ContactName, err = c.sheetContact(value)
+	case "expected_close_date":
+		deal.ExpectedCloseDate, err = sheetDate(value)
+	case "close_reason":
+		deal.CloseReason = value
+	case "source":
+		if value != "" && !IsValidDealSource(value) {
+			return Validationf("invalid source %q (valid: %s)", value, strings.Join(DealSources, ", "))
+		}
+		deal.Source = value
+	case "referrer":
+		deal.ReferrerID, deal.ReferrerName, err = c.sheetContact(value)
+	case "tags":
+		deal.Tags = sheetTags(value)
+	}
+	return err
+}
+
+// sheetCompany finds the one company named name. Sheets never create
+// companies, so a typo can't add a duplicate.
+func (c *Client) sheetCompany(name string) (*Company, error) {
+	company, err := c.ResolveCompanyName(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil {
+		return nil, NotFoundf("no company named %q; add it first", name)
+	}
+	return company, nil
+}
+
+// sheetContact finds the one contact named (or with the email) name, or
+// clears the reference when name is empty.
+func (c *Client) sheetContact(name string) (*uuid.UUID, string, error) {
+	if name == "" {
+		return nil, "", nil
+	}
+	contact, err := c.ResolveContactName(name, true)
+	if err != nil {
+		return nil, "", err
+	}
+	if contact == nil {
+		return nil, "", NotFoundf("no contact named %q", name)
+	}
+	return &contact.ID, contact.Name, nil
+}
+
+// sheetTags reads a comma- or semicolon-separated tag list.
+func sheetTags(value string) []string {
+	return ParseTags(strings.ReplaceAll(value, ";", ","))
+}
+
+func sheetDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, Validationf("invalid date %q (use YYYY-MM-DD)", value)
+	}
+	return &date, nil
+}
+
+func sheetChoice(value string, choices []string) (string, error) {
+	value = strings.ToLower(value)
+	if value != "" && !slices.Contains(choices, value) {
+		return "", Validationf("invalid value %q (valid: %s)", value, strings.Join(choices, ", "))
+	}
+	return value, nil
+}
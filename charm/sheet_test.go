@@ -0,0 +1,207 @@
+// ABOUTME: Tests for the spreadsheet round-trip of contacts and deals
+// ABOUTME: Verifies export, changed-cell imports, new rows, duplicates, conflicts, and dry runs
+
+package charm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// editSheet exports sheetType, lets edit change the rows (header first), and
+// returns the edited CSV.
+func editSheet(t *testing.T, client *Client, sheetType string, edit func(rows [][]string) [][]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := client.ExportSheet(&buf, sheetType); err != nil {
+		t.Fatalf("ExportSheet failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("bad sheet CSV: %v", err)
+	}
+	rows = edit(rows)
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	_ = w.WriteAll(rows)
+	return out.String()
+}
+
+func sheetColumn(rows [][]string, name string) int {
+	for i, column := range rows[0] {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestSheetContactsRoundTrip(t *testing.T) {
+	client := NewTestClient(t)
+	acme := &Company{Name: "Acme"}
+	globex := &Company{Name: "Globex"}
+	for _, company := range []*Company{acme, globex} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("CreateCompany failed: %v", err)
+		}
+	}
+	ada := &Contact{Name: "Ada", Email: "ada@acme.com", CompanyID: &acme.ID, CompanyName: "Acme", Tags: []string{"ai", "vip"}}
+	bob := &Contact{Name: "Bob", Email: "bob@acme.com"}
+	for _, contact := range []*Contact{ada, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	sheet := editSheet(t, client, "contacts", func(rows [][]string) [][]string {
+		if len(rows) != 3 || rows[1][0] != ada.ID.String() {
+			t.Fatalf("unexpected export %v", rows)
+		}
+		// Ada: reordered tags are no change, a new title and company are
+		rows[1][sheetColumn(rows, "tags")] = "VIP; ai"
+		rows[1][sheetColumn(rows, "title")] = "CTO"
+		rows[1][sheetColumn(rows, "company")] = "globex"
+		// Rows shorter than the header leave the missing cells alone
+		rows[2] = rows[2][:3]
+		// A new row, and one duplicating Bob
+		return append(rows, []string{"", "Cy", "cy@globex.com"}, []string{"", "Robert", "BOB@acme.com"})
+	})
+
+	preview, err := client.ImportSheet(strings.NewReader(sheet), "contacts", SheetImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportSheet dry run failed: %v", err)
+	}
+	if preview.Updated != 1 || preview.Created != 1 || preview.Unchanged != 1 || len(preview.Problems) != 1 {
+		t.Fatalf("unexpected dry run %+v", preview)
+	}
+	if got, _ := client.GetContact(ada.ID); got.Title != "" {
+		t.Error("a dry run shouldn't write")
+	}
+
+	result, err := client.ImportSheet(strings.NewReader(sheet), "contacts", SheetImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	var changed []string
+	for _, change := range result.Changes {
+		changed = append(changed, change.Name+":"+change.Column)
+	}
+	if got := strings.Join(changed, ","); got != "Ada:title,Ada:company,Cy:" {
+		t.Errorf("unexpected changes %s", got)
+	}
+	if len(result.Problems) != 1 || !strings.Contains(result.Problems[0], "row 5") || !strings.Contains(result.Problems[0], bob.ID.String()) {
+		t.Errorf("expected the duplicate of Bob reported, got %v", result.Problems)
+	}
+
+	got, err := client.GetContact(ada.ID)
+	if err != nil {
+		t.Fatalf("GetContact failed: %v", err)
+	}
+	if got.Title != "CTO" || got.CompanyID == nil || *got.CompanyID != globex.ID || got.CompanyName != "Globex" {
+		t.Errorf("unexpected contact %+v", got)
+	}
+	if strings.Join(got.Tags, ",") != "ai,vip" {
+		t.Errorf("tags shouldn't change, got %v", got.Tags)
+	}
+	if cy, _ := client.FindContactByEmail("cy@globex.com"); cy == nil || cy.Name != "Cy" {
+		t.Errorf("expected Cy created, got %+v", cy)
+	}
+
+	// Unknown companies are problems, not new companies
+	sheet = editSheet(t, client, "contacts", func(rows [][]string) [][]string {
+		rows[1][sheetColumn(rows, "company")] = "Initech"
+		return rows
+	})
+	result, err = client.ImportSheet(strings.NewReader(sheet), "contacts", SheetImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	if result.Updated != 0 || len(result.Problems) != 1 || !strings.Contains(result.Problems[0], `no company named "Initech"`) {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	for _, bad := range []string{"", "name,email\nAda,a@x.io\n", "id,nickname\n"} {
+		if _, err := client.ImportSheet(strings.NewReader(bad), "contacts", SheetImportOptions{}); CodeOf(err) != CodeValidation {
+			t.Errorf("ImportSheet(%q): expected a validation error, got %v", bad, err)
+		}
+	}
+}
+
+func TestSheetDealsRoundTrip(t *testing.T) {
+	client := NewTestClient(t)
+	acme := &Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	pilot := &Deal{Title: "Pilot", Amount: 1250000, Currency: "USD", Stage: StageProposal, CompanyID: acme.ID, CompanyName: "Acme"}
+	if err := client.CreateDeal(pilot); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	sheet := editSheet(t, client, "deals", func(rows [][]string) [][]string {
+		if rows[1][sheetColumn(rows, "amount")] != "12500" {
+			t.Errorf("expected whole-unit amounts, got %v", rows[1])
+		}
+		rows[1][sheetColumn(rows, "amount")] = "12,500.00" // reformatted, not changed
+		rows[1][sheetColumn(rows, "probability")] = "80%"
+		return rows
+	})
+	conflicting := editSheet(t, client, "deals", func(rows [][]string) [][]string {
+		rows[1][sheetColumn(rows, "stage")] = "Negotiation"
+		return rows
+	})
+
+	// Edits in pagen after the export survive the import
+	time.Sleep(1100 * time.Millisecond)
+	pilot.CloseReason = "budget"
+	pilot.Stage = StageQualification
+	if err := client.UpdateDeal(pilot); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	result, err := client.ImportSheet(strings.NewReader(sheet), "deals", SheetImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	if result.Updated != 1 || len(result.Changes) != 1 || result.Changes[0].Column != "probability" || len(result.Problems) != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	got, err := client.GetDeal(pilot.ID)
+	if err != nil {
+		t.Fatalf("GetDeal failed: %v", err)
+	}
+	if got.Stage != StageQualification || got.Amount != 1250000 || got.Probability == nil || *got.Probability != 80 || got.CloseReason != "budget" {
+		t.Errorf("unexpected deal %+v", got)
+	}
+
+	// A cell edited on both sides is a conflict unless forced
+	result, err = client.ImportSheet(strings.NewReader(conflicting), "deals", SheetImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	if result.Updated != 0 || len(result.Problems) != 1 || !strings.Contains(result.Problems[0], "stage changed in both") {
+		t.Fatalf("expected a conflict, got %+v", result)
+	}
+	if _, err := client.ImportSheet(strings.NewReader(conflicting), "deals", SheetImportOptions{Force: true}); err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	if got, _ := client.GetDeal(pilot.ID); got.Stage != StageNegotiation || *got.Probability != 80 {
+		t.Errorf("expected the forced stage and the earlier probability, got %+v", got)
+	}
+
+	// New deals need a known company and can't repeat a title there
+	sheet = "id,title,company,amount\n,Expansion,Acme,5k\n,pilot,Acme,1\n,Grid,Initech,1\n"
+	result, err = client.ImportSheet(strings.NewReader(sheet), "deals", SheetImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportSheet failed: %v", err)
+	}
+	if result.Created != 1 || len(result.Problems) != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	deals, _ := client.ListDeals(&DealFilter{Query: "Expansion"})
+	if len(deals) != 1 || deals[0].Amount != 500000 || deals[0].Stage != StageProspecting {
+		t.Errorf("unexpected new deal %+v", deals)
+	}
+}
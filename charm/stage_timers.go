@@ -0,0 +1,288 @@
+// ABOUTME: Stage automation timers for deals that sit in one stage too long
+// ABOUTME: Creates a follow-up task, sends a notification, or flags the deal once per stage entry
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stage timer actions
+const (
+	StageTimerTask   = "task"   // create a follow-up task for the deal
+	StageTimerNotify = "notify" // desktop notification, and a webhook post when set
+	StageTimerFlag   = "flag"   // tag the deal until it leaves the stage
+)
+
+// StageTimerActions lists the valid stage timer actions.
+var StageTimerActions = []string{StageTimerTask, StageTimerNotify, StageTimerFlag}
+
+// DefaultStageTimerTag is the tag the flag action adds when none is configured.
+const DefaultStageTimerTag = "stalled"
+
+// StageTimer is what happens to an open deal that sits in a stage for Days
+// or more. Timers are keyed by stage in the config and run by `pagen watch run`
+// and the stage-timers background job.
+type StageTimer struct {
+	Days    int      `json:"days"`
+	Actions []string `json:"actions"`           // task, notify, and/or flag
+	Tag     string   `json:"tag,omitempty"`     // flag: tag added to the deal (default: stalled)
+	Webhook string   `json:"webhook,omitempty"` // notify: also POST the alert here
+}
+
+// TagOrDefault returns the tag the flag action adds.
+func (t StageTimer) TagOrDefault() string {
+	if tag := NormalizeTag(t.Tag); tag != "" {
+		return tag
+	}
+	return DefaultStageTimerTag
+}
+
+// Has reports whether the timer runs action.
+func (t StageTimer) Has(action string) bool {
+	return slices.Contains(t.Actions, action)
+}
+
+// ValidateStageTimersConfig checks that every timer is on an open stage, has a
+// positive threshold, and only uses known actions.
+func ValidateStageTimersConfig(timers map[string]StageTimer) error {
+	for stage, timer := range timers {
+		if !isDealStage(stage) {
+			return Validationf("unknown stage %q (valid: %s)", stage, strings.Join(DealStages, ", "))
+		}
+		if stage == StageClosedWon || stage == StageClosedLost {
+			return Validationf("stage %s is closed; timers only run on open stages", stage)
+		}
+		if timer.Days <= 0 {
+			return Validationf("stage %s needs a positive days threshold", stage)
+		}
+		if len(timer.Actions) == 0 {
+			return Validationf("stage %s needs at least one action (valid: %s)", stage, strings.Join(StageTimerActions, ", "))
+		}
+		for _, action := range timer.Actions {
+			if !slices.Contains(StageTimerActions, action) {
+				return Validationf("unknown action %q for stage %s (valid: %s)", action, stage, strings.Join(StageTimerActions, ", "))
+			}
+		}
+		if timer.Webhook != "" && !strings.HasPrefix(timer.Webhook, "http://") && !strings.HasPrefix(timer.Webhook, "https://") {
+			return Validationf("stage %s webhook must be an http:// or https:// URL", stage)
+		}
+	}
+	return nil
+}
+
+// StageTimerAlert is a stage timer that went off for a deal.
+type StageTimerAlert struct {
+	DealID      uuid.UUID  `json:"deal_id"`
+	DealTitle   string     `json:"deal_title"`
+	CompanyName string     `json:"company_name,omitempty"`
+	Stage       string     `json:"stage"`
+	DaysInStage int        `json:"days_in_stage"`
+	EnteredAt   time.Time  `json:"entered_at"`
+	Actions     []string   `json:"actions"`
+	TaskID      *uuid.UUID `json:"task_id,omitempty"` // the follow-up task, when created
+	Tag         string     `json:"tag,omitempty"`     // the flag tag, when added
+}
+
+// Message describes the alert in one line.
+func (a *StageTimerAlert) Message() string {
+	name := a.DealTitle
+	if a.CompanyName != "" {
+		name += " (" + a.CompanyName + ")"
+	}
+	return fmt.Sprintf("%s has been in %s for %d days", name, a.Stage, a.DaysInStage)
+}
+
+// stageTimerFiring records that a deal's timer went off for the stage entry
+// at EnteredAt, so each timer fires once per time a deal enters a stage.
+type stageTimerFiring struct {
+	DealID    uuid.UUID  `json:"deal_id"`
+	Stage     string     `json:"stage"`
+	EnteredAt time.Time  `json:"entered_at"`
+	TaskID    *uuid.UUID `json:"task_id,omitempty"` // the follow-up task, created under this ID
+	Tag       string     `json:"tag,omitempty"`     // flag tag to lift when the deal moves on
+	FiredAt   time.Time  `json:"fired_at"`
+	Pending   bool       `json:"pending,omitempty"` // the task or flag hasn't been done yet
+}
+
+// RunStageTimers fires the timer of each open deal that has been in its stage
+// at least the timer's days, once per stage entry, and returns the alerts
+// that went off. notify delivers the notify action; when it fails the deal's
+// other actions wait for the next run so nothing is done twice. A task or
+// flag that fails is retried on the next run. Deals that have moved on since
+// their timer fired have the flag tag lifted.
+func (c *Client) RunStageTimers(timers map[string]StageTimer, now time.Time, notify func(alert *StageTimerAlert, timer StageTimer) error) ([]*StageTimerAlert, error) {
+	if len(timers) == 0 {
+		return nil, nil
+	}
+
+	firings, err := c.listStageTimerFirings()
+	if err != nil {
+		return nil, err
+	}
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+
+	var alerts []*StageTimerAlert
+	var errs []string
+	seen := make(map[uuid.UUID]bool, len(deals))
+	for _, deal := range deals {
+		seen[deal.ID] = true
+		history, err := c.DealStageHistory(deal)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", deal.Title, err))
+			continue
+		}
+		var entered time.Time
+		if len(history) > 0 {
+			entered = history[len(history)-1].At
+		}
+
+		fired := firings[deal.ID]
+		if fired != nil && (fired.Stage != deal.Stage || !fired.EnteredAt.Equal(entered)) {
+			if err := c.clearStageTimerFiring(fired); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", deal.Title, err))
+				continue
+			}
+			fired = nil
+		}
+
+		timer, ok := timers[deal.Stage]
+		if !ok || (fired != nil && !fired.Pending) || entered.IsZero() {
+			continue
+		}
+		days := int(now.Sub(entered).Hours() / 24)
+		if days < timer.Days {
+			continue
+		}
+
+		alert := &StageTimerAlert{
+			DealID: deal.ID, DealTitle: deal.Title, CompanyName: deal.CompanyName, Stage: deal.Stage,
+			DaysInStage: days, EnteredAt: entered, Actions: timer.Actions,
+		}
+		if err := c.fireStageTimer(deal, timer, alert, fired, now, notify); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", deal.Title, err))
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	// Forget firings of deleted deals
+	for id, fired := range firings {
+		if !seen[id] {
+			_ = c.Delete(StageTimerKey(fired.DealID.String()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return alerts, fmt.Errorf("stage timers failed: %s", strings.Join(errs, "; "))
+	}
+	return alerts, nil
+}
+
+// fireStageTimer runs the timer's actions for deal. Once the notification is
+// out, the firing is recorded as pending with the task's ID before the task
+// and flag, so a failure between them retries those for the same task on the
+// next run (pending) rather than notifying again or creating a second task.
+func (c *Client) fireStageTimer(deal *Deal, timer StageTimer, alert *StageTimerAlert, pending *stageTimerFiring, now time.Time, notify func(*StageTimerAlert, StageTimer) error) error {
+	firing := pending
+	if firing == nil {
+		if timer.Has(StageTimerNotify) && notify != nil {
+			if err := notify(alert, timer); err != nil {
+				return err
+			}
+		}
+
+		firing = &stageTimerFiring{DealID: deal.ID, Stage: deal.Stage, EnteredAt: alert.EnteredAt, FiredAt: now, Pending: true}
+		if timer.Has(StageTimerTask) {
+			id := uuid.New()
+			firing.TaskID = &id
+		}
+		if timer.Has(StageTimerFlag) {
+			firing.Tag = timer.TagOrDefault()
+		}
+		if err := c.saveStageTimerFiring(firing); err != nil {
+			return err
+		}
+	}
+
+	if firing.TaskID != nil {
+		due := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		task := &Task{
+			ID:          *firing.TaskID,
+			Title:       fmt.Sprintf("Follow up on %s: %d days in %s", deal.Title, alert.DaysInStage, deal.Stage),
+			ContactID:   deal.ContactID,
+			ContactName: deal.ContactName,
+			DealID:      &deal.ID,
+			DealTitle:   deal.Title,
+			DueAt:       &due,
+		}
+		if deal.CompanyID != uuid.Nil {
+			task.CompanyID = &deal.CompanyID
+			task.CompanyName = deal.CompanyName
+		}
+		// Saving under the recorded ID again replaces, rather than repeats, the task
+		if err := c.CreateTask(task); err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+		alert.TaskID = firing.TaskID
+	}
+
+	if firing.Tag != "" {
+		if _, err := c.UpdateTags(deal.ID, []string{firing.Tag}, nil); err != nil {
+			return fmt.Errorf("failed to flag deal: %w", err)
+		}
+		alert.Tag = firing.Tag
+	}
+
+	firing.Pending = false
+	return c.saveStageTimerFiring(firing)
+}
+
+func (c *Client) saveStageTimerFiring(firing *stageTimerFiring) error {
+	data, err := json.Marshal(firing)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stage timer firing: %w", err)
+	}
+	return c.Set(StageTimerKey(firing.DealID.String()), data)
+}
+
+// clearStageTimerFiring lifts the flag of a deal that has left the stage its
+// timer fired in, and forgets the firing.
+func (c *Client) clearStageTimerFiring(fired *stageTimerFiring) error {
+	if fired.Tag != "" {
+		if _, err := c.UpdateTags(fired.DealID, nil, []string{fired.Tag}); err != nil {
+			return fmt.Errorf("failed to lift flag: %w", err)
+		}
+	}
+	return c.Delete(StageTimerKey(fired.DealID.String()))
+}
+
+func (c *Client) listStageTimerFirings() (map[uuid.UUID]*stageTimerFiring, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixStageTimer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage timer firings: %w", err)
+	}
+
+	firings := make(map[uuid.UUID]*stageTimerFiring, len(keys))
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+		var fired stageTimerFiring
+		if err := json.Unmarshal(data, &fired); err != nil {
+			continue
+		}
+		firings[fired.DealID] = &fired
+	}
+	return firings, nil
+}
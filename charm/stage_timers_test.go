@@ -0,0 +1,193 @@
+// ABOUTME: Tests for stage automation timers
+// ABOUTME: Verifies timers fire once per stage entry, retry failed notifications and flags, and lift flags when deals move on
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunStageTimers(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	stuck := &Deal{Title: "Stuck", CompanyID: company.ID, CompanyName: company.Name, Stage: StageProposal, StageHistory: []StageChange{
+		{StageProspecting, now.AddDate(0, 0, -40)}, {StageProposal, now.AddDate(0, 0, -20)}}}
+	fresh := &Deal{Title: "Fresh", Stage: StageProposal, StageHistory: []StageChange{
+		{StageProposal, now.AddDate(0, 0, -3)}}}
+	for _, deal := range []*Deal{stuck, fresh} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	timers := map[string]StageTimer{
+		StageProposal: {Days: 14, Actions: []string{StageTimerTask, StageTimerNotify, StageTimerFlag}},
+	}
+	var notified []string
+	notify := func(alert *StageTimerAlert, _ StageTimer) error {
+		notified = append(notified, alert.Message())
+		return nil
+	}
+
+	alerts, err := client.RunStageTimers(timers, now, notify)
+	if err != nil {
+		t.Fatalf("RunStageTimers failed: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].DealID != stuck.ID || alerts[0].DaysInStage != 20 {
+		t.Fatalf("expected only Stuck after 20 days, got %+v", alerts)
+	}
+	if len(notified) != 1 || notified[0] != "Stuck (Acme) has been in proposal for 20 days" {
+		t.Errorf("unexpected notifications: %v", notified)
+	}
+	if alerts[0].TaskID == nil {
+		t.Fatal("expected a follow-up task")
+	}
+	task, err := client.GetTask(*alerts[0].TaskID)
+	if err != nil || task.DealID == nil || *task.DealID != stuck.ID || task.CompanyName != "Acme" {
+		t.Errorf("expected a task linked to the deal and company, got %+v, %v", task, err)
+	}
+	if got, _ := client.GetDeal(stuck.ID); !got.HasTag(DefaultStageTimerTag) {
+		t.Errorf("expected the deal flagged, got tags %v", got.Tags)
+	}
+
+	// Once per stage entry
+	alerts, err = client.RunStageTimers(timers, now.AddDate(0, 0, 5), notify)
+	if err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no repeat alerts, got %+v, %v", alerts, err)
+	}
+
+	// Moving on lifts the flag, and re-entering the stage starts a new timer
+	stuck, _ = client.GetDeal(stuck.ID)
+	stuck.Stage = StageNegotiation
+	if err := client.UpdateDeal(stuck); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if _, err := client.RunStageTimers(timers, now, notify); err != nil {
+		t.Fatalf("RunStageTimers failed: %v", err)
+	}
+	if got, _ := client.GetDeal(stuck.ID); got.HasTag(DefaultStageTimerTag) {
+		t.Errorf("expected the flag lifted, got tags %v", got.Tags)
+	}
+	stuck, _ = client.GetDeal(stuck.ID)
+	stuck.Stage = StageProposal
+	if err := client.UpdateDeal(stuck); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	alerts, err = client.RunStageTimers(timers, time.Now().AddDate(0, 0, 15), notify)
+	if err != nil {
+		t.Fatalf("RunStageTimers failed: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("expected both deals to fire after another 15 days, got %d", len(alerts))
+	}
+}
+
+func TestRunStageTimersRetriesFailedNotify(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+
+	deal := &Deal{Title: "Stuck", Stage: StageNegotiation, StageHistory: []StageChange{
+		{StageNegotiation, now.AddDate(0, 0, -30)}}}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	timers := map[string]StageTimer{
+		StageNegotiation: {Days: 21, Actions: []string{StageTimerNotify, StageTimerTask}},
+	}
+
+	alerts, err := client.RunStageTimers(timers, now, func(*StageTimerAlert, StageTimer) error {
+		return errors.New("webhook down")
+	})
+	if err == nil || len(alerts) != 0 {
+		t.Fatalf("expected the failed notification reported, got %+v, %v", alerts, err)
+	}
+	if tasks, _ := client.ListTasks(false); len(tasks) != 0 {
+		t.Fatalf("expected no task until the notification goes out, got %d", len(tasks))
+	}
+
+	alerts, err = client.RunStageTimers(timers, now, func(*StageTimerAlert, StageTimer) error { return nil })
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("expected the timer retried, got %+v, %v", alerts, err)
+	}
+	if tasks, _ := client.ListTasks(false); len(tasks) != 1 {
+		t.Errorf("expected one task, got %d", len(tasks))
+	}
+}
+
+func TestRunStageTimersRetriesFailedFlag(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+
+	deal := &Deal{Title: "Stuck", Stage: StageProposal, StageHistory: []StageChange{
+		{StageProposal, now.AddDate(0, 0, -20)}}}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	timers := map[string]StageTimer{
+		StageProposal: {Days: 14, Actions: []string{StageTimerNotify, StageTimerTask, StageTimerFlag}},
+	}
+
+	// A deal that no longer saves makes flagging fail after the task is made
+	probability := 150
+	broken := *deal
+	broken.Probability = &probability
+	data, _ := json.Marshal(&broken)
+	if err := client.Set(DealKey(deal.ID.String()), data); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	notified := 0
+	notify := func(*StageTimerAlert, StageTimer) error {
+		notified++
+		return nil
+	}
+	if _, err := client.RunStageTimers(timers, now, notify); err == nil {
+		t.Fatal("expected the failed flag reported")
+	}
+
+	// The next run finishes the flag without notifying again or a second task
+	data, _ = json.Marshal(deal)
+	if err := client.Set(DealKey(deal.ID.String()), data); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	alerts, err := client.RunStageTimers(timers, now, notify)
+	if err != nil || len(alerts) != 1 || alerts[0].Tag != DefaultStageTimerTag {
+		t.Fatalf("expected the flag retried, got %+v, %v", alerts, err)
+	}
+	if notified != 1 {
+		t.Errorf("expected one notification, got %d", notified)
+	}
+	if tasks, _ := client.ListTasks(false); len(tasks) != 1 {
+		t.Errorf("expected one task, got %d", len(tasks))
+	}
+	if alerts, _ := client.RunStageTimers(timers, now, notify); len(alerts) != 0 {
+		t.Errorf("expected the timer done once the flag is on, got %+v", alerts)
+	}
+}
+
+func TestValidateStageTimersConfig(t *testing.T) {
+	valid := map[string]StageTimer{StageProposal: {Days: 14, Actions: []string{StageTimerTask}}}
+	if err := ValidateStageTimersConfig(valid); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	for name, timers := range map[string]map[string]StageTimer{
+		"unknown stage":  {"limbo": {Days: 1, Actions: []string{StageTimerTask}}},
+		"closed stage":   {StageClosedWon: {Days: 1, Actions: []string{StageTimerTask}}},
+		"no days":        {StageProposal: {Actions: []string{StageTimerTask}}},
+		"no actions":     {StageProposal: {Days: 1}},
+		"unknown action": {StageProposal: {Days: 1, Actions: []string{"email"}}},
+		"bad webhook":    {StageProposal: {Days: 1, Actions: []string{StageTimerNotify}, Webhook: "ftp://x"}},
+	} {
+		if err := ValidateStageTimersConfig(timers); CodeOf(err) != CodeValidation {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+}
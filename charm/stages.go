@@ -0,0 +1,122 @@
+// ABOUTME: Per-stage required-field rules for deals
+// ABOUTME: Validates that a deal has the configured fields before it enters a stage
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Deal fields that can be required by a stage.
+const (
+	DealFieldAmount            = "amount"
+	DealFieldExpectedCloseDate = "expected_close_date"
+	DealFieldContact           = "contact"
+	DealFieldCloseReason       = "close_reason"
+)
+
+// dealFieldCheckers report whether a deal has a value for each requirable field.
+var dealFieldCheckers = map[string]func(*Deal) bool{
+	DealFieldAmount:            func(d *Deal) bool { return d.Amount > 0 },
+	DealFieldExpectedCloseDate: func(d *Deal) bool { return d.ExpectedCloseDate != nil },
+	DealFieldContact:           func(d *Deal) bool { return d.ContactID != nil },
+	DealFieldCloseReason:       func(d *Deal) bool { return strings.TrimSpace(d.CloseReason) != "" },
+}
+
+// DealStages lists the deal stages in pipeline order.
+var DealStages = []string{
+	StageProspecting,
+	StageQualification,
+	StageProposal,
+	StageNegotiation,
+	StageClosedWon,
+	StageClosedLost,
+}
+
+// StageRequirementError reports the fields a deal is missing for a stage.
+type StageRequirementError struct {
+	Stage   string
+	Missing []string
+}
+
+func (e *StageRequirementError) Error() string {
+	return fmt.Sprintf("deal cannot move to stage %q: missing required %s", e.Stage, strings.Join(e.Missing, ", "))
+}
+
+// RequirableDealFields returns the field names that stages can require.
+func RequirableDealFields() []string {
+	fields := make([]string, 0, len(dealFieldCheckers))
+	for field := range dealFieldCheckers {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ValidateStageRequirementsConfig checks that rules only name known stages and fields.
+func ValidateStageRequirementsConfig(requirements map[string][]string) error {
+	for stage, fields := range requirements {
+		if !isDealStage(stage) {
+			return fmt.Errorf("unknown stage %q (valid: %s)", stage, strings.Join(DealStages, ", "))
+		}
+		for _, field := range fields {
+			if _, ok := dealFieldCheckers[field]; !ok {
+				return fmt.Errorf("unknown field %q for stage %s (valid: %s)", field, stage, strings.Join(RequirableDealFields(), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// CheckStageRequirements returns a *StageRequirementError if the deal is
+// missing any field required by its current stage.
+func CheckStageRequirements(deal *Deal, requirements map[string][]string) error {
+	var missing []string
+	for _, field := range requirements[deal.Stage] {
+		if check, ok := dealFieldCheckers[field]; ok && !check(deal) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return &StageRequirementError{Stage: deal.Stage, Missing: missing}
+	}
+	return nil
+}
+
+// StageRequirements returns the configured required fields per stage.
+func (c *Client) StageRequirements() map[string][]string {
+	return c.stageRequirements
+}
+
+// SetStageRequirements replaces the required fields per stage for this client.
+func (c *Client) SetStageRequirements(requirements map[string][]string) {
+	c.stageRequirements = requirements
+}
+
+// checkDealStage enforces stage requirements when a deal is created or
+// changes stage. Deals staying in their stage are not re-checked, so adding
+// a rule doesn't block unrelated edits to existing deals.
+func (c *Client) checkDealStage(deal *Deal, isNew bool) error {
+	if len(c.stageRequirements[deal.Stage]) == 0 {
+		return nil
+	}
+
+	if !isNew {
+		if existing, err := c.GetDeal(deal.ID); err == nil && existing.Stage == deal.Stage {
+			return nil
+		}
+	}
+
+	return CheckStageRequirements(deal, c.stageRequirements)
+}
+
+func isDealStage(stage string) bool {
+	for _, s := range DealStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for per-stage deal requirements
+// ABOUTME: Verifies rules are enforced on create and stage change only
+
+package charm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckStageRequirements(t *testing.T) {
+	requirements := map[string][]string{
+		StageProposal:   {DealFieldAmount, DealFieldExpectedCloseDate},
+		StageClosedLost: {DealFieldCloseReason},
+	}
+
+	err := CheckStageRequirements(&Deal{Stage: StageProposal, Amount: 1000}, requirements)
+	var reqErr *StageRequirementError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected StageRequirementError, got %v", err)
+	}
+	if len(reqErr.Missing) != 1 || reqErr.Missing[0] != DealFieldExpectedCloseDate {
+		t.Errorf("expected missing expected_close_date, got %v", reqErr.Missing)
+	}
+
+	closeDate := time.Now()
+	if err := CheckStageRequirements(&Deal{Stage: StageProposal, Amount: 1000, ExpectedCloseDate: &closeDate}, requirements); err != nil {
+		t.Errorf("expected complete deal to pass, got %v", err)
+	}
+	if err := CheckStageRequirements(&Deal{Stage: StageProspecting}, requirements); err != nil {
+		t.Errorf("expected unconstrained stage to pass, got %v", err)
+	}
+}
+
+func TestValidateStageRequirementsConfig(t *testing.T) {
+	if err := ValidateStageRequirementsConfig(map[string][]string{StageProposal: {DealFieldAmount}}); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+	if err := ValidateStageRequirementsConfig(map[string][]string{"won": {DealFieldAmount}}); err == nil {
+		t.Error("expected unknown stage to fail")
+	}
+	if err := ValidateStageRequirementsConfig(map[string][]string{StageProposal: {"budget"}}); err == nil {
+		t.Error("expected unknown field to fail")
+	}
+}
+
+func TestDealStageRequirementsEnforced(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Expansion", Stage: StageQualification}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	client.SetStageRequirements(map[string][]string{
+		StageQualification: {DealFieldContact},
+		StageClosedLost:    {DealFieldCloseReason},
+	})
+
+	// Edits that keep the stage aren't blocked by rules added later
+	deal.Title = "Expansion 2025"
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Errorf("expected same-stage update to pass, got %v", err)
+	}
+
+	deal.Stage = StageClosedLost
+	if err := client.UpdateDeal(deal); err == nil {
+		t.Error("expected move to closed_lost without close reason to fail")
+	}
+
+	stored, err := client.GetDeal(deal.ID)
+	if err != nil {
+		t.Fatalf("failed to get deal: %v", err)
+	}
+	if stored.Stage != StageQualification {
+		t.Errorf("expected rejected update not to be saved, got stage %s", stored.Stage)
+	}
+
+	deal.CloseReason = "Budget cut"
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Errorf("expected move with close reason to pass, got %v", err)
+	}
+
+	if err := client.CreateDeal(&Deal{Title: "New", Stage: StageQualification}); err == nil {
+		t.Error("expected create without contact to fail")
+	}
+}
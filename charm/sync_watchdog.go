@@ -0,0 +1,45 @@
+// ABOUTME: Watchdog for sync states left "syncing" by a process that died midway
+// ABOUTME: Resets stale ones to "error" with an explanation so the next sync retries without a manual reset
+
+package charm
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSyncStaleAfter is how long a service may stay "syncing" without its
+// state changing before ResetStaleSyncStates treats the sync as dead.
+const DefaultSyncStaleAfter = 2 * time.Hour
+
+// ResetStaleSyncStates sets every service that has been "syncing" with no
+// state change for longer than olderThan to "error", with a message saying
+// when the sync started and that the watchdog reset it. It returns the
+// states it reset, as they were before the reset.
+func (c *Client) ResetStaleSyncStates(olderThan time.Duration, now time.Time) ([]*SyncState, error) {
+	if olderThan <= 0 {
+		olderThan = DefaultSyncStaleAfter
+	}
+
+	states, err := c.ListSyncStates()
+	if err != nil {
+		return nil, err
+	}
+
+	var reset []*SyncState
+	for _, state := range states {
+		if state.Status != SyncStatusSyncing || now.Sub(state.UpdatedAt) <= olderThan {
+			continue
+		}
+
+		stuck := *state
+		state.Status = SyncStatusError
+		state.ErrorMessage = fmt.Sprintf("sync stopped responding: status was syncing since %s UTC, so the process likely exited mid-sync; reset by the watchdog, the next sync retries",
+			stuck.UpdatedAt.UTC().Format("2006-01-02 15:04"))
+		if err := c.SaveSyncState(state); err != nil {
+			return reset, fmt.Errorf("failed to reset stale sync state for %s: %w", state.Service, err)
+		}
+		reset = append(reset, &stuck)
+	}
+	return reset, nil
+}
@@ -0,0 +1,58 @@
+// ABOUTME: Tests for the stuck sync watchdog
+// ABOUTME: Verifies only services syncing past the threshold are reset, with an explanation
+
+package charm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResetStaleSyncStates(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Now()
+
+	// SaveSyncState stamps UpdatedAt, so age the states by writing them directly
+	for service, age := range map[string]time.Duration{
+		"calendar": 3 * time.Hour,
+		"gmail":    30 * time.Minute,
+	} {
+		data, err := json.Marshal(&SyncState{Service: service, Status: SyncStatusSyncing, UpdatedAt: now.Add(-age)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Set(SyncStateKey(service), data); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := client.SaveSyncState(&SyncState{Service: "contacts", Status: SyncStatusIdle}); err != nil {
+		t.Fatalf("SaveSyncState failed: %v", err)
+	}
+
+	reset, err := client.ResetStaleSyncStates(time.Hour, now)
+	if err != nil {
+		t.Fatalf("ResetStaleSyncStates failed: %v", err)
+	}
+	if len(reset) != 1 || reset[0].Service != "calendar" || reset[0].Status != SyncStatusSyncing {
+		t.Fatalf("expected only calendar reset, got %+v", reset)
+	}
+
+	state, err := client.GetSyncState("calendar")
+	if err != nil {
+		t.Fatalf("GetSyncState failed: %v", err)
+	}
+	if state.Status != SyncStatusError || !strings.Contains(state.ErrorMessage, "watchdog") {
+		t.Errorf("expected calendar reset to error with an explanation, got %+v", state)
+	}
+	if state, _ := client.GetSyncState("gmail"); state.Status != SyncStatusSyncing {
+		t.Errorf("gmail: expected still syncing, got %q", state.Status)
+	}
+
+	// Already reset states are left alone
+	reset, err = client.ResetStaleSyncStates(time.Hour, now)
+	if err != nil || len(reset) != 0 {
+		t.Errorf("expected nothing to reset, got %+v, %v", reset, err)
+	}
+}
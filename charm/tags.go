@@ -0,0 +1,247 @@
+// ABOUTME: Tags on contacts, companies, and deals for segmenting the network
+// ABOUTME: Normalizes tag lists, adds and removes tags on any taggable object, and finds objects by tag
+
+package charm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TaggableTypes are the entity types that carry tags.
+var TaggableTypes = []string{EntityContact, EntityCompany, EntityDeal}
+
+// NormalizeTag lowercases a tag and joins its words with dashes, e.g.
+// "Machine Learning" is "machine-learning".
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(tag, "-", " "))), "-")
+}
+
+// NormalizeTags normalizes each tag, drops empty ones and duplicates, and
+// sorts the rest.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParseTags splits a comma-separated list like "ai, Fundraising" into
+// normalized tags.
+func ParseTags(s string) []string {
+	return NormalizeTags(strings.Split(s, ","))
+}
+
+func hasTag(tags []string, tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag reports whether the contact has tag.
+func (c *Contact) HasTag(tag string) bool {
+	return hasTag(c.Tags, tag)
+}
+
+// HasTag reports whether the company has tag.
+func (c *Company) HasTag(tag string) bool {
+	return hasTag(c.Tags, tag)
+}
+
+// HasTag reports whether the deal has tag.
+func (d *Deal) HasTag(tag string) bool {
+	return hasTag(d.Tags, tag)
+}
+
+// TaggedObject is a contact, company, or deal and its tags.
+type TaggedObject struct {
+	EntityType string    `json:"entity_type"`
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Tags       []string  `json:"tags"`
+}
+
+// UpdateTags adds and removes tags on the contact, company, or deal with id,
+// and returns it with its tags afterwards. Tags it already has, or doesn't
+// have, are left alone.
+func (c *Client) UpdateTags(id uuid.UUID, add, remove []string) (*TaggedObject, error) {
+	remove = NormalizeTags(remove)
+	retag := func(tags []string) []string {
+		var kept []string
+		for _, tag := range tags {
+			if !hasTag(remove, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		return NormalizeTags(append(kept, add...))
+	}
+
+	if contact, err := c.GetContact(id); err == nil {
+		tags := retag(contact.Tags)
+		if !equalTags(tags, contact.Tags) {
+			contact.Tags = tags
+			if err := c.UpdateContact(contact); err != nil {
+				return nil, fmt.Errorf("failed to update contact: %w", err)
+			}
+		}
+		return &TaggedObject{EntityType: EntityContact, ID: id, Name: contact.Name, Tags: tags}, nil
+	}
+	if company, err := c.GetCompany(id); err == nil {
+		tags := retag(company.Tags)
+		if !equalTags(tags, company.Tags) {
+			company.Tags = tags
+			if err := c.UpdateCompany(company); err != nil {
+				return nil, fmt.Errorf("failed to update company: %w", err)
+			}
+		}
+		return &TaggedObject{EntityType: EntityCompany, ID: id, Name: company.Name, Tags: tags}, nil
+	}
+	if deal, err := c.GetDeal(id); err == nil {
+		tags := retag(deal.Tags)
+		if !equalTags(tags, deal.Tags) {
+			deal.Tags = tags
+			if err := c.UpdateDeal(deal); err != nil {
+				return nil, fmt.Errorf("failed to update deal: %w", err)
+			}
+		}
+		return &TaggedObject{EntityType: EntityDeal, ID: id, Name: deal.Title, Tags: tags}, nil
+	}
+	return nil, fmt.Errorf("no contact, company, or deal with ID %s", id)
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FindByTag returns the objects with tag, contacts first, then companies,
+// then deals. entityTypes limits which kinds are searched (all when empty).
+// Archived contacts are skipped.
+func (c *Client) FindByTag(tag string, entityTypes ...string) ([]*TaggedObject, error) {
+	tag = NormalizeTag(tag)
+	if tag == "" {
+		return nil, Validationf("tag is required")
+	}
+	if len(entityTypes) == 0 {
+		entityTypes = TaggableTypes
+	}
+
+	var found []*TaggedObject
+	for _, entityType := range entityTypes {
+		switch entityType {
+		case EntityContact:
+			contacts, err := c.ListContacts(&ContactFilter{Tag: tag})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list contacts: %w", err)
+			}
+			for _, contact := range contacts {
+				found = append(found, &TaggedObject{EntityType: EntityContact, ID: contact.ID, Name: contact.Name, Tags: contact.Tags})
+			}
+		case EntityCompany:
+			companies, err := c.ListCompanies(&CompanyFilter{Tag: tag})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list companies: %w", err)
+			}
+			for _, company := range companies {
+				found = append(found, &TaggedObject{EntityType: EntityCompany, ID: company.ID, Name: company.Name, Tags: company.Tags})
+			}
+		case EntityDeal:
+			deals, err := c.ListDeals(&DealFilter{Tag: tag})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list deals: %w", err)
+			}
+			for _, deal := range deals {
+				found = append(found, &TaggedObject{EntityType: EntityDeal, ID: deal.ID, Name: deal.Title, Tags: deal.Tags})
+			}
+		default:
+			return nil, fmt.Errorf("cannot tag entity type: %s (use %s)", entityType, strings.Join(TaggableTypes, ", "))
+		}
+	}
+	return found, nil
+}
+
+// TagCount is how many contacts, companies, and deals have a tag.
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Contacts  int    `json:"contacts"`
+	Companies int    `json:"companies"`
+	Deals     int    `json:"deals"`
+}
+
+// Total is the number of objects with the tag.
+func (t *TagCount) Total() int {
+	return t.Contacts + t.Companies + t.Deals
+}
+
+// ListTags returns every tag in use, most used first.
+func (c *Client) ListTags() ([]*TagCount, error) {
+	counts := make(map[string]*TagCount)
+	count := func(tag string) *TagCount {
+		if counts[tag] == nil {
+			counts[tag] = &TagCount{Tag: tag}
+		}
+		return counts[tag]
+	}
+
+	contacts, err := c.ListContacts(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	for _, contact := range contacts {
+		for _, tag := range contact.Tags {
+			count(tag).Contacts++
+		}
+	}
+	companies, err := c.ListCompanies(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+	for _, company := range companies {
+		for _, tag := range company.Tags {
+			count(tag).Companies++
+		}
+	}
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	for _, deal := range deals {
+		for _, tag := range deal.Tags {
+			count(tag).Deals++
+		}
+	}
+
+	tags := make([]*TagCount, 0, len(counts))
+	for _, tc := range counts {
+		tags = append(tags, tc)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Total() != tags[j].Total() {
+			return tags[i].Total() > tags[j].Total()
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
@@ -0,0 +1,132 @@
+// ABOUTME: Tests for tags on contacts, companies, and deals
+// ABOUTME: Verifies normalization, adding and removing tags on each type, filters, and tag counts
+
+package charm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" Fundraising, ai,, Machine Learning ,AI")
+	want := []string{"ai", "fundraising", "machine-learning"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags = %v, want %v", got, want)
+	}
+	if tags := ParseTags(""); tags != nil {
+		t.Errorf("expected no tags from an empty string, got %v", tags)
+	}
+}
+
+func TestUpdateTags(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Ada", Tags: []string{"ai"}}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	deal := &Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProspecting, Currency: "USD"}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	obj, err := client.UpdateTags(contact.ID, []string{"Investor", "ai"}, nil)
+	if err != nil {
+		t.Fatalf("UpdateTags failed: %v", err)
+	}
+	if obj.EntityType != EntityContact || !reflect.DeepEqual(obj.Tags, []string{"ai", "investor"}) {
+		t.Errorf("unexpected tagged contact: %+v", obj)
+	}
+
+	if _, err := client.UpdateTags(company.ID, []string{"portfolio"}, nil); err != nil {
+		t.Fatalf("UpdateTags on company failed: %v", err)
+	}
+	if _, err := client.UpdateTags(deal.ID, []string{"q3", "enterprise"}, nil); err != nil {
+		t.Fatalf("UpdateTags on deal failed: %v", err)
+	}
+	obj, err = client.UpdateTags(deal.ID, nil, []string{"Q3"})
+	if err != nil {
+		t.Fatalf("UpdateTags removing failed: %v", err)
+	}
+	if obj.EntityType != EntityDeal || !reflect.DeepEqual(obj.Tags, []string{"enterprise"}) {
+		t.Errorf("unexpected tagged deal: %+v", obj)
+	}
+
+	stored, err := client.GetCompany(company.ID)
+	if err != nil {
+		t.Fatalf("GetCompany failed: %v", err)
+	}
+	if !stored.HasTag("Portfolio") {
+		t.Errorf("expected company to be saved with its tag, got %v", stored.Tags)
+	}
+
+	if _, err := client.UpdateTags(uuid.New(), []string{"ai"}, nil); err == nil {
+		t.Error("expected an error tagging an unknown ID")
+	}
+}
+
+func TestFindByTagAndListTags(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme", Tags: []string{"ai"}}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	for _, contact := range []*Contact{
+		{Name: "Ada", Tags: []string{"ai", "investor"}},
+		{Name: "Grace", Tags: []string{"investor"}},
+		{Name: "Linus"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	deal := &Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProspecting, Currency: "USD", Tags: []string{"ai"}}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	found, err := client.FindByTag("AI")
+	if err != nil {
+		t.Fatalf("FindByTag failed: %v", err)
+	}
+	var names []string
+	for _, obj := range found {
+		names = append(names, obj.EntityType+":"+obj.Name)
+	}
+	want := []string{"contact:Ada", "company:Acme", "deal:Pilot"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("FindByTag = %v, want %v", names, want)
+	}
+
+	found, err = client.FindByTag("investor", EntityCompany, EntityDeal)
+	if err != nil {
+		t.Fatalf("FindByTag with types failed: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("expected no companies or deals tagged investor, got %d", len(found))
+	}
+	if _, err := client.FindByTag("ai", "task"); err == nil {
+		t.Error("expected an error for an entity type that can't be tagged")
+	}
+
+	deals, err := client.ListDeals(&DealFilter{Tag: "ai"})
+	if err != nil || len(deals) != 1 {
+		t.Errorf("expected the deal filter to match one tagged deal, got %d (%v)", len(deals), err)
+	}
+
+	tags, err := client.ListTags()
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Tag != "ai" || tags[0].Total() != 3 || tags[1].Contacts != 2 {
+		t.Errorf("unexpected tag counts: %+v, %+v", tags[0], tags[1])
+	}
+}
@@ -0,0 +1,135 @@
+// ABOUTME: Lightweight tasks linked to contacts, companies, and deals
+// ABOUTME: Stores to-dos captured from the TUI scratchpad or CLI and lists the open ones by due date
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntityTask is the reference kind for tasks.
+const EntityTask = "task"
+
+// Task is a to-do, optionally linked to a contact, company, or deal.
+type Task struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	ContactID   *uuid.UUID `json:"contact_id,omitempty"`
+	ContactName string     `json:"contact_name,omitempty"` // denormalized
+	CompanyID   *uuid.UUID `json:"company_id,omitempty"`
+	CompanyName string     `json:"company_name,omitempty"` // denormalized
+	DealID      *uuid.UUID `json:"deal_id,omitempty"`
+	DealTitle   string     `json:"deal_title,omitempty"` // denormalized
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	DoneAt      *time.Time `json:"done_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsOverdue reports whether an open task's due date is before today.
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.DoneAt != nil || t.DueAt == nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return t.DueAt.Before(today)
+}
+
+// CreateTask stores a new task.
+func (c *Client) CreateTask(task *Task) error {
+	if task.ID == uuid.Nil {
+		task.ID = uuid.New()
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	return c.saveTask(task)
+}
+
+// GetTask retrieves a task by ID.
+func (c *Client) GetTask(id uuid.UUID) (*Task, error) {
+	data, err := c.Get(TaskKey(id.String()))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, NotFoundf("task not found: %s", id)
+	}
+
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+	return &task, nil
+}
+
+// ListTasks returns open tasks, or all tasks with includeDone, soonest due
+// first; tasks without a due date come last, oldest first.
+func (c *Client) ListTasks(includeDone bool) ([]*Task, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixTask))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var task Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			continue
+		}
+		if task.DoneAt != nil && !includeDone {
+			continue
+		}
+		tasks = append(tasks, &task)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		switch {
+		case a.DueAt != nil && b.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+			return a.DueAt.Before(*b.DueAt)
+		case a.DueAt != nil && b.DueAt == nil:
+			return true
+		case a.DueAt == nil && b.DueAt != nil:
+			return false
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return tasks, nil
+}
+
+// CompleteTask marks a task done. Completing a done task is a no-op.
+func (c *Client) CompleteTask(id uuid.UUID) (*Task, error) {
+	task, err := c.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if task.DoneAt != nil {
+		return task, nil
+	}
+	now := time.Now()
+	task.DoneAt = &now
+	return task, c.saveTask(task)
+}
+
+// DeleteTask removes a task by ID.
+func (c *Client) DeleteTask(id uuid.UUID) error {
+	return c.Delete(TaskKey(id.String()))
+}
+
+func (c *Client) saveTask(task *Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	return c.Set(TaskKey(task.ID.String()), data)
+}
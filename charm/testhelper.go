@@ -4,6 +4,7 @@
 package charm
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
@@ -93,6 +94,23 @@ func (c *testClient) Set(key, value []byte) error {
 	return c.tkv.Set(key, value)
 }
 
+func (c *testClient) Update(key []byte, fn func(stored []byte) ([]byte, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored, err := c.tkv.Get(key)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		stored, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	value, err := fn(stored)
+	if err != nil {
+		return err
+	}
+	return c.tkv.Set(key, value)
+}
+
 func (c *testClient) Delete(key []byte) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -182,6 +200,7 @@ func NewTestClient(t *testing.T) *Client {
 
 	// Return a Client with the test implementation
 	c := &Client{
+		profile:    DefaultProfile,
 		dbName:     AppName,
 		autoSync:   false,
 		testClient: tc,
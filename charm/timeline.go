@@ -0,0 +1,349 @@
+// ABOUTME: Contact timelines of interactions, deal milestones, and deal notes
+// ABOUTME: Builds vis.js timeline documents for export and the newest-first activity feed for the web UI
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Timeline groups, one row each in a vis.js timeline.
+const (
+	TimelineGroupInteractions = "interactions"
+	TimelineGroupDeals        = "deals"
+)
+
+// Timeline is a contact's history as a vis.js timeline document: pass Items
+// and Groups to a vis.Timeline as they are. Content and titles are escaped
+// HTML.
+type Timeline struct {
+	Contact     TimelineContact `json:"contact"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Groups      []TimelineGroup `json:"groups"`
+	Items       []TimelineItem  `json:"items"`
+}
+
+// TimelineContact identifies whose timeline it is.
+type TimelineContact struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	CompanyName string `json:"company_name,omitempty"`
+}
+
+// TimelineGroup is a vis.js timeline group.
+type TimelineGroup struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// TimelineItem is a vis.js timeline item. ClassName is "interaction-<type>"
+// or "deal-<milestone>" so dashboards can style them.
+type TimelineItem struct {
+	ID        string    `json:"id"`
+	Group     string    `json:"group"`
+	Content   string    `json:"content"`
+	Title     string    `json:"title,omitempty"` // hover text
+	Start     time.Time `json:"start"`
+	Type      string    `json:"type"` // box or point
+	ClassName string    `json:"className"`
+}
+
+// GetContactTimeline returns every interaction with the contact and the
+// milestones of the deals they are the primary contact on or hold a role in:
+// when each deal opened, its stage changes, and its expected close date.
+// Stage changes come from revision history, so only the last revision_limit
+// changes of a deal show.
+func (c *Client) GetContactTimeline(contactID uuid.UUID) (*Timeline, error) {
+	contact, err := c.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &Timeline{
+		Contact:     TimelineContact{ID: contact.ID.String(), Name: contact.Name, Email: contact.Email},
+		GeneratedAt: time.Now(),
+		Groups: []TimelineGroup{
+			{ID: TimelineGroupInteractions, Content: "Interactions"},
+			{ID: TimelineGroupDeals, Content: "Deals"},
+		},
+		Items: []TimelineItem{},
+	}
+	if contact.CompanyID != nil {
+		if company, err := c.GetCompany(*contact.CompanyID); err == nil {
+			timeline.Contact.CompanyName = company.Name
+		}
+	}
+
+	logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &contactID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	for _, log := range logs {
+		timeline.Items = append(timeline.Items, interactionTimelineItem(log))
+	}
+
+	deals, err := c.contactDeals(contactID)
+	if err != nil {
+		return nil, err
+	}
+	for _, deal := range deals {
+		items, err := c.dealTimelineItems(deal)
+		if err != nil {
+			return nil, err
+		}
+		timeline.Items = append(timeline.Items, items...)
+	}
+
+	sort.SliceStable(timeline.Items, func(i, j int) bool {
+		return timeline.Items[i].Start.Before(timeline.Items[j].Start)
+	})
+	return timeline, nil
+}
+
+// contactDeals returns the deals a contact is the primary contact on or holds
+// a role in, oldest first.
+func (c *Client) contactDeals(contactID uuid.UUID) ([]*Deal, error) {
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	roles, err := c.listDealContacts(PrefixDealContact, func(dc *DealContact) bool { return dc.ContactID == contactID })
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal contacts: %w", err)
+	}
+	hasRole := make(map[uuid.UUID]bool, len(roles))
+	for _, role := range roles {
+		hasRole[role.DealID] = true
+	}
+
+	var matched []*Deal
+	for _, deal := range deals {
+		if hasRole[deal.ID] || (deal.ContactID != nil && *deal.ContactID == contactID) {
+			matched = append(matched, deal)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+	})
+	return matched, nil
+}
+
+func interactionTimelineItem(log *InteractionLog) TimelineItem {
+	kind := log.InteractionType
+	if kind == "" {
+		kind = "interaction"
+	}
+	content := strings.ToUpper(kind[:1]) + kind[1:]
+	if summary := timelineSummary(log.Notes, 60); summary != "" {
+		content += ": " + summary
+	}
+
+	var details []string
+	if log.Notes != "" {
+		details = append(details, log.Notes)
+	}
+	if log.Location != "" {
+		details = append(details, "Location: "+log.Location)
+	}
+	if log.Outcome != "" {
+		details = append(details, "Outcome: "+log.Outcome)
+	}
+	if log.NextStep != "" {
+		details = append(details, "Next step: "+log.NextStep)
+	}
+
+	return TimelineItem{
+		ID:        "interaction:" + log.ID.String(),
+		Group:     TimelineGroupInteractions,
+		Content:   html.EscapeString(content),
+		Title:     html.EscapeString(strings.Join(details, "\n")),
+		Start:     log.Timestamp,
+		Type:      "box",
+		ClassName: "interaction-" + kind,
+	}
+}
+
+// dealTimelineItems returns a deal's milestones: opened, each stage change
+// found in its revisions, and the expected close date while it is open.
+func (c *Client) dealTimelineItems(deal *Deal) ([]TimelineItem, error) {
+	revisions, err := c.ListRevisions(deal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal revisions: %w", err)
+	}
+
+	title := html.EscapeString(deal.Title)
+	hover := deal.Title
+	if deal.CompanyName != "" {
+		hover += " (" + deal.CompanyName + ")"
+	}
+	if deal.Amount > 0 {
+		hover += ", " + FormatMoney(deal.Amount, deal.Currency)
+	}
+	hover = html.EscapeString(hover)
+
+	items := []TimelineItem{{
+		ID:        "deal:" + deal.ID.String() + ":opened",
+		Group:     TimelineGroupDeals,
+		Content:   "Opened: " + title,
+		Title:     hover,
+		Start:     deal.CreatedAt,
+		Type:      "box",
+		ClassName: "deal-opened",
+	}}
+
+	prevStage := ""
+	for _, rev := range revisions {
+		if rev.Op == RevisionOpDelete {
+			continue
+		}
+		var snapshot struct {
+			Stage string `json:"stage"`
+		}
+		if err := json.Unmarshal(rev.Data, &snapshot); err != nil || snapshot.Stage == "" {
+			continue
+		}
+		if prevStage != "" && snapshot.Stage != prevStage {
+			items = append(items, stageTimelineItem(deal, rev, snapshot.Stage, title, hover))
+		}
+		prevStage = snapshot.Stage
+	}
+
+	if deal.ExpectedCloseDate != nil && deal.Stage != StageClosedWon && deal.Stage != StageClosedLost {
+		items = append(items, TimelineItem{
+			ID:        "deal:" + deal.ID.String() + ":expected-close",
+			Group:     TimelineGroupDeals,
+			Content:   "Expected close: " + title,
+			Title:     hover,
+			Start:     *deal.ExpectedCloseDate,
+			Type:      "point",
+			ClassName: "deal-expected-close",
+		})
+	}
+	return items, nil
+}
+
+func stageTimelineItem(deal *Deal, rev *Revision, stage, title, hover string) TimelineItem {
+	label := strings.ReplaceAll(stage, "_", " ")
+	content, class := strings.ToUpper(label[:1])+label[1:]+": "+title, "deal-stage"
+	switch stage {
+	case StageClosedWon:
+		content, class = "Won: "+title, "deal-won"
+	case StageClosedLost:
+		content, class = "Lost: "+title, "deal-lost"
+	}
+	return TimelineItem{
+		ID:        fmt.Sprintf("deal:%s:rev:%d", deal.ID, rev.Rev),
+		Group:     TimelineGroupDeals,
+		Content:   content,
+		Title:     hover,
+		Start:     rev.CreatedAt,
+		Type:      "box",
+		ClassName: class,
+	}
+}
+
+// timelineSummary is the first line of text, cut to max runes with an ellipsis.
+func timelineSummary(text string, max int) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if runes := []rune(line); len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+	return line
+}
+
+// ActivityDealNote is the kind of activity entries for notes on a contact's
+// deals; other entries are interactions and take their interaction type.
+const ActivityDealNote = "deal_note"
+
+// ActivityEntry is one item in a contact's activity feed: an interaction, or
+// a note on one of their deals.
+type ActivityEntry struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"` // interaction type, or deal_note
+	Timestamp time.Time `json:"timestamp"`
+	Summary   string    `json:"summary"`
+	Body      string    `json:"body,omitempty"`
+	Manual    bool      `json:"manual"` // logged by hand rather than imported
+	Location  string    `json:"location,omitempty"`
+	Outcome   string    `json:"outcome,omitempty"`
+	NextStep  string    `json:"next_step,omitempty"`
+	DealID    string    `json:"deal_id,omitempty"`
+	DealTitle string    `json:"deal_title,omitempty"`
+}
+
+// ListContactActivity returns a contact's interactions and the notes on
+// their deals, newest first. When kinds is non-empty only those kinds are
+// returned.
+func (c *Client) ListContactActivity(contactID uuid.UUID, kinds []string) ([]*ActivityEntry, error) {
+	wanted := func(kind string) bool {
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, k := range kinds {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	var entries []*ActivityEntry
+	logs, err := c.ListInteractionLogs(&InteractionFilter{ContactID: &contactID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interactions: %w", err)
+	}
+	for _, log := range logs {
+		if !wanted(log.InteractionType) {
+			continue
+		}
+		entries = append(entries, &ActivityEntry{
+			ID:        log.ID.String(),
+			Kind:      log.InteractionType,
+			Timestamp: log.Timestamp,
+			Summary:   timelineSummary(log.Notes, 80),
+			Body:      log.Notes,
+			Manual:    log.Metadata == "", // importers record where an interaction came from
+			Location:  log.Location,
+			Outcome:   log.Outcome,
+			NextStep:  log.NextStep,
+		})
+	}
+
+	if wanted(ActivityDealNote) {
+		deals, err := c.contactDeals(contactID)
+		if err != nil {
+			return nil, err
+		}
+		for _, deal := range deals {
+			notes, err := c.ListDealNotes(deal.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list deal notes: %w", err)
+			}
+			for _, note := range notes {
+				entries = append(entries, &ActivityEntry{
+					ID:        note.ID.String(),
+					Kind:      ActivityDealNote,
+					Timestamp: note.CreatedAt,
+					Summary:   timelineSummary(note.Content, 80),
+					Body:      note.Content,
+					Manual:    true,
+					DealID:    deal.ID.String(),
+					DealTitle: deal.Title,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	return entries, nil
+}
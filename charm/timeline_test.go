@@ -0,0 +1,66 @@
+// ABOUTME: Tests for contact timeline export
+// ABOUTME: Verifies interaction items, deal milestones from revisions, and escaping for vis.js
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetContactTimeline(t *testing.T) {
+	client := NewTestClient(t)
+
+	acme := &Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	ada := &Contact{Name: "Ada", Email: "ada@acme.com", CompanyID: &acme.ID}
+	if err := client.CreateContact(ada); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	if err := client.CreateInteractionLog(&InteractionLog{ContactID: ada.ID, InteractionType: InteractionMeeting, Notes: "Coffee <3\nand plans", Timestamp: time.Now().AddDate(0, 0, -3)}); err != nil {
+		t.Fatalf("CreateInteractionLog failed: %v", err)
+	}
+
+	closeDate := time.Now().AddDate(0, 1, 0)
+	deal := &Deal{Title: "Pilot", CompanyID: acme.ID, CompanyName: "Acme", ContactID: &ada.ID, Stage: StageProspecting, ExpectedCloseDate: &closeDate}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	deal.Stage = StageProposal
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	// Deals the contact isn't on stay off their timeline
+	if err := client.CreateDeal(&Deal{Title: "Other", CompanyID: acme.ID, Stage: StageProspecting}); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	timeline, err := client.GetContactTimeline(ada.ID)
+	if err != nil {
+		t.Fatalf("GetContactTimeline failed: %v", err)
+	}
+	if timeline.Contact.Name != "Ada" || timeline.Contact.CompanyName != "Acme" {
+		t.Errorf("unexpected contact: %+v", timeline.Contact)
+	}
+	if len(timeline.Groups) != 2 {
+		t.Errorf("expected interaction and deal groups, got %+v", timeline.Groups)
+	}
+
+	var contents []string
+	for _, item := range timeline.Items {
+		contents = append(contents, item.Content)
+	}
+	want := []string{"Meeting: Coffee &lt;3", "Opened: Pilot", "Proposal: Pilot", "Expected close: Pilot"}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected items %q in order, got %q", want, contents)
+	}
+	if item := timeline.Items[0]; item.Group != TimelineGroupInteractions || item.ClassName != "interaction-meeting" || item.Type != "box" {
+		t.Errorf("unexpected interaction item: %+v", item)
+	}
+	if item := timeline.Items[3]; item.Type != "point" || item.ClassName != "deal-expected-close" {
+		t.Errorf("unexpected expected close item: %+v", item)
+	}
+}
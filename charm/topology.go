@@ -0,0 +1,164 @@
+// ABOUTME: Snapshot of the Charm sync topology: linked devices, the cloud backup manifest, and local outbox
+// ABOUTME: Collected best-effort so `pagen sync viz` can draw what it can reach when offline
+
+package charm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/charm/client"
+	charmfs "github.com/charmbracelet/charm/fs"
+	"github.com/charmbracelet/charm/kv"
+	charmproto "github.com/charmbracelet/charm/proto"
+)
+
+// SyncDevice is an SSH key linked to the Charm account. Each device links its own key.
+type SyncDevice struct {
+	Name     string // key type and short fingerprint
+	LinkedAt *time.Time
+	Current  bool // the key this device authenticates with
+}
+
+// SyncUploader is the newest cloud backup written by one device ID.
+type SyncUploader struct {
+	DeviceID string
+	Seq      uint64
+	At       time.Time
+}
+
+// SyncTopology describes this device, the Charm cloud, and the other linked devices.
+type SyncTopology struct {
+	Host     string
+	AutoSync bool
+
+	// Local database
+	LocalSeq        uint64
+	PendingOps      int64
+	OldestPendingOp time.Time
+	LastSync        time.Time
+	SyncLockHolder  string
+
+	// Cloud backup manifest
+	CloudSeq     uint64
+	CloudBackups int
+	Uploaders    []SyncUploader
+
+	Devices []SyncDevice
+
+	// Errors lists the parts that couldn't be collected, e.g. when offline
+	Errors []string
+}
+
+// Behind reports whether the cloud has changes this device hasn't applied.
+func (t *SyncTopology) Behind() bool {
+	return t.CloudSeq > t.LocalSeq
+}
+
+// SyncTopology collects the sync topology. It never fails outright; unreachable
+// parts are recorded in Errors.
+func (c *Client) SyncTopology() *SyncTopology {
+	cfg := c.Config()
+	t := &SyncTopology{Host: cfg.Host, AutoSync: cfg.AutoSync}
+
+	if c.testClient != nil {
+		t.Errors = append(t.Errors, "sync topology is unavailable for test clients")
+		return t
+	}
+
+	doctor, err := kv.DoctorDB(c.dbName)
+	if err != nil {
+		t.Errors = append(t.Errors, fmt.Sprintf("local database: %v", err))
+	} else {
+		t.LocalSeq = doctor.LocalSeq
+		t.PendingOps = doctor.PendingOpsCount
+		t.OldestPendingOp = doctor.OldestPendingOp
+		if doctor.SyncLockHeld {
+			t.SyncLockHolder = doctor.SyncLockHolder
+		}
+		t.Errors = append(t.Errors, doctor.Errors...)
+	}
+	if last, err := c.LastSyncTime(); err == nil {
+		t.LastSync = last
+	}
+
+	cc, err := client.NewClientWithDefaults()
+	if err != nil {
+		t.Errors = append(t.Errors, fmt.Sprintf("charm client: %v", err))
+		return t
+	}
+
+	if keys, err := cc.AuthorizedKeysWithMetadata(); err != nil {
+		t.Errors = append(t.Errors, fmt.Sprintf("linked devices: %v", err))
+	} else {
+		t.setDevices(keys)
+	}
+
+	if manifest, err := loadCloudManifest(cc, c.dbName); err != nil {
+		t.Errors = append(t.Errors, fmt.Sprintf("cloud manifest: %v", err))
+	} else {
+		t.setManifest(manifest)
+	}
+
+	return t
+}
+
+// loadCloudManifest reads the backup manifest charm KV keeps next to the cloud backups.
+func loadCloudManifest(cc *client.Client, name string) (*kv.Manifest, error) {
+	cfs, err := charmfs.NewFSWithClient(cc)
+	if err != nil {
+		return nil, err
+	}
+	f, err := cfs.Open(name + "/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return kv.UnmarshalManifest(data)
+}
+
+func (t *SyncTopology) setDevices(keys *charmproto.Keys) {
+	t.Devices = nil
+	for i, key := range keys.Keys {
+		keyType, _, _ := strings.Cut(key.Key, " ")
+		sha := key.Sha()
+		if len(sha) > 8 {
+			sha = sha[:8]
+		}
+		t.Devices = append(t.Devices, SyncDevice{
+			Name:     keyType + " " + sha,
+			LinkedAt: key.CreatedAt,
+			Current:  i == keys.ActiveKey,
+		})
+	}
+}
+
+func (t *SyncTopology) setManifest(m *kv.Manifest) {
+	t.CloudSeq = m.LatestSeq
+	t.CloudBackups = len(m.Backups)
+
+	latest := make(map[string]SyncUploader)
+	for _, b := range m.Backups {
+		id := b.DeviceID
+		if id == "" {
+			id = "unknown"
+		}
+		if u, ok := latest[id]; !ok || b.Seq > u.Seq {
+			latest[id] = SyncUploader{DeviceID: id, Seq: b.Seq, At: b.CreatedAt}
+		}
+	}
+	t.Uploaders = nil
+	for _, u := range latest {
+		t.Uploaders = append(t.Uploaders, u)
+	}
+	sort.Slice(t.Uploaders, func(i, j int) bool {
+		return t.Uploaders[i].Seq > t.Uploaders[j].Seq
+	})
+}
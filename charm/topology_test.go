@@ -0,0 +1,75 @@
+// ABOUTME: Tests for sync topology collection helpers
+// ABOUTME: Verifies manifest and linked key summaries without a Charm server
+
+package charm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/charm/kv"
+	charmproto "github.com/charmbracelet/charm/proto"
+)
+
+func TestSyncTopologyManifest(t *testing.T) {
+	now := time.Now()
+	topo := &SyncTopology{LocalSeq: 7}
+	topo.setManifest(&kv.Manifest{
+		LatestSeq: 9,
+		Backups: []kv.BackupEntry{
+			{Seq: 9, DeviceID: "laptop", CreatedAt: now},
+			{Seq: 8, DeviceID: "desktop", CreatedAt: now.Add(-time.Hour)},
+			{Seq: 5, DeviceID: "laptop", CreatedAt: now.Add(-2 * time.Hour)},
+			{Seq: 2},
+		},
+	})
+
+	if topo.CloudSeq != 9 || topo.CloudBackups != 4 {
+		t.Errorf("unexpected cloud summary: seq %d, %d backups", topo.CloudSeq, topo.CloudBackups)
+	}
+	if !topo.Behind() {
+		t.Error("expected local seq 7 to be behind cloud seq 9")
+	}
+	if len(topo.Uploaders) != 3 {
+		t.Fatalf("expected 3 uploaders, got %+v", topo.Uploaders)
+	}
+	if u := topo.Uploaders[0]; u.DeviceID != "laptop" || u.Seq != 9 {
+		t.Errorf("expected laptop's newest backup first, got %+v", u)
+	}
+	if u := topo.Uploaders[2]; u.DeviceID != "unknown" {
+		t.Errorf("expected backups without a device ID grouped as unknown, got %+v", u)
+	}
+}
+
+func TestSyncTopologyDevices(t *testing.T) {
+	linked := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	topo := &SyncTopology{}
+	topo.setDevices(&charmproto.Keys{
+		ActiveKey: 1,
+		Keys: []*charmproto.PublicKey{
+			{Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIA laptop", CreatedAt: &linked},
+			{Key: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQAB desktop"},
+		},
+	})
+
+	if len(topo.Devices) != 2 {
+		t.Fatalf("expected 2 devices, got %+v", topo.Devices)
+	}
+	if topo.Devices[0].Current || !topo.Devices[1].Current {
+		t.Error("expected the active key to be marked current")
+	}
+	if name := topo.Devices[0].Name; len(name) != len("ssh-ed25519 ")+8 {
+		t.Errorf("expected key type and short fingerprint, got %q", name)
+	}
+	if topo.Devices[0].LinkedAt == nil || !topo.Devices[0].LinkedAt.Equal(linked) {
+		t.Error("expected link time to be kept")
+	}
+}
+
+func TestSyncTopologyTestClient(t *testing.T) {
+	client := NewTestClient(t)
+	topo := client.SyncTopology()
+	if len(topo.Errors) == 0 {
+		t.Error("expected test clients to report the topology as unavailable")
+	}
+}
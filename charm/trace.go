@@ -0,0 +1,22 @@
+// ABOUTME: Optional timing hook for KV operations
+// ABOUTME: Lets long-running callers like the web server trace slow reads and writes
+
+package charm
+
+import "time"
+
+// QueryObserver is called after every KV operation with how long it took.
+// key is nil for whole-database operations.
+type QueryObserver func(op string, key []byte, elapsed time.Duration, err error)
+
+// SetQueryObserver installs fn to be told about every KV operation.
+// Call it before the client is shared between goroutines.
+func (c *Client) SetQueryObserver(fn QueryObserver) {
+	c.observer = fn
+}
+
+func (c *Client) observe(op string, key []byte, start time.Time, err *error) {
+	if c.observer != nil {
+		c.observer(op, key, time.Since(start), *err)
+	}
+}
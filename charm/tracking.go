@@ -0,0 +1,208 @@
+// ABOUTME: Optional open/click tracking for follow-up emails
+// ABOUTME: Tracked emails record opens and link clicks as interactions with the recipient
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tracked email event types, recorded in interaction metadata.
+const (
+	TrackingEventOpen  = "open"
+	TrackingEventClick = "click"
+)
+
+// TrackedEmail is a sent follow-up email whose opens and clicks are recorded.
+// The ID doubles as the unguessable token in tracking URLs.
+type TrackedEmail struct {
+	ID            uuid.UUID  `json:"id"`
+	ContactID     uuid.UUID  `json:"contact_id"`
+	ContactName   string     `json:"contact_name,omitempty"` // denormalized
+	Subject       string     `json:"subject"`
+	Links         []string   `json:"links,omitempty"`
+	SentAt        time.Time  `json:"sent_at"`
+	Opens         int        `json:"opens"`
+	Clicks        int        `json:"clicks"`
+	FirstOpenedAt *time.Time `json:"first_opened_at,omitempty"`
+	LastEventAt   *time.Time `json:"last_event_at,omitempty"`
+}
+
+// trackingMetadata is stored on interactions created by tracking events.
+type trackingMetadata struct {
+	TrackedEmailID string `json:"tracked_email_id"`
+	Event          string `json:"event"`
+	Link           string `json:"link,omitempty"`
+}
+
+// PixelURL returns the tracking pixel URL to embed in the email body.
+func (e *TrackedEmail) PixelURL(baseURL string) string {
+	return fmt.Sprintf("%s/t/o/%s.gif", strings.TrimRight(baseURL, "/"), e.ID)
+}
+
+// LinkURL returns the tracked redirect URL for the link at index i.
+func (e *TrackedEmail) LinkURL(baseURL string, i int) string {
+	return fmt.Sprintf("%s/t/c/%s/%d", strings.TrimRight(baseURL, "/"), e.ID, i)
+}
+
+// CreateTrackedEmail records a sent email and logs it as an email interaction.
+func (c *Client) CreateTrackedEmail(email *TrackedEmail) error {
+	if email.ID == uuid.Nil {
+		email.ID = uuid.New()
+	}
+	if email.SentAt.IsZero() {
+		email.SentAt = time.Now()
+	}
+
+	if err := c.saveTrackedEmail(email); err != nil {
+		return err
+	}
+
+	return c.CreateInteractionLog(&InteractionLog{
+		ContactID:       email.ContactID,
+		ContactName:     email.ContactName,
+		InteractionType: InteractionEmail,
+		Timestamp:       email.SentAt,
+		Notes:           "Sent: " + email.Subject,
+	})
+}
+
+// GetTrackedEmail retrieves a tracked email by ID.
+func (c *Client) GetTrackedEmail(id uuid.UUID) (*TrackedEmail, error) {
+	data, err := c.Get(TrackedEmailKey(id.String()))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, NotFoundf("tracked email not found: %s", id)
+	}
+
+	var email TrackedEmail
+	if err := json.Unmarshal(data, &email); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tracked email: %w", err)
+	}
+	return &email, nil
+}
+
+// ListTrackedEmails returns tracked emails, newest first.
+// A nil contactID returns emails for every contact.
+func (c *Client) ListTrackedEmails(contactID *uuid.UUID) ([]*TrackedEmail, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixTrackedEmail))
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []*TrackedEmail
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var email TrackedEmail
+		if err := json.Unmarshal(data, &email); err != nil {
+			continue
+		}
+		if contactID != nil && email.ContactID != *contactID {
+			continue
+		}
+		emails = append(emails, &email)
+	}
+
+	sort.Slice(emails, func(i, j int) bool {
+		return emails[i].SentAt.After(emails[j].SentAt)
+	})
+	return emails, nil
+}
+
+// RecordEmailOpen counts an open. Only the first open is logged as an
+// interaction, since mail clients re-fetch the pixel every time the email is viewed.
+func (c *Client) RecordEmailOpen(id uuid.UUID, at time.Time) error {
+	email, err := c.GetTrackedEmail(id)
+	if err != nil {
+		return err
+	}
+
+	email.Opens++
+	email.LastEventAt = &at
+	firstOpen := email.FirstOpenedAt == nil
+	if firstOpen {
+		email.FirstOpenedAt = &at
+	}
+	if err := c.saveTrackedEmail(email); err != nil {
+		return err
+	}
+
+	if !firstOpen {
+		return nil
+	}
+	return c.logTrackingEvent(email, TrackingEventOpen, "", at)
+}
+
+// RecordEmailClick counts a click on the link at index i and logs it as an
+// interaction. Returns the link's destination URL.
+func (c *Client) RecordEmailClick(id uuid.UUID, i int, at time.Time) (string, error) {
+	email, err := c.GetTrackedEmail(id)
+	if err != nil {
+		return "", err
+	}
+	if i < 0 || i >= len(email.Links) {
+		return "", fmt.Errorf("link %d not found for tracked email %s", i, id)
+	}
+
+	link := email.Links[i]
+	email.Clicks++
+	email.LastEventAt = &at
+	// A click implies the email was opened, even if images were blocked
+	if email.FirstOpenedAt == nil {
+		email.FirstOpenedAt = &at
+	}
+	if err := c.saveTrackedEmail(email); err != nil {
+		return "", err
+	}
+
+	if err := c.logTrackingEvent(email, TrackingEventClick, link, at); err != nil {
+		return "", err
+	}
+	return link, nil
+}
+
+func (c *Client) saveTrackedEmail(email *TrackedEmail) error {
+	data, err := json.Marshal(email)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracked email: %w", err)
+	}
+	return c.Set(TrackedEmailKey(email.ID.String()), data)
+}
+
+// logTrackingEvent records an open or click as an email interaction.
+func (c *Client) logTrackingEvent(email *TrackedEmail, event, link string, at time.Time) error {
+	metadata, err := json.Marshal(trackingMetadata{
+		TrackedEmailID: email.ID.String(),
+		Event:          event,
+		Link:           link,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracking metadata: %w", err)
+	}
+
+	notes := "Opened: " + email.Subject
+	if event == TrackingEventClick {
+		notes = fmt.Sprintf("Clicked %s in: %s", link, email.Subject)
+	}
+
+	return c.CreateInteractionLog(&InteractionLog{
+		ContactID:       email.ContactID,
+		ContactName:     email.ContactName,
+		InteractionType: InteractionEmail,
+		Timestamp:       at,
+		Notes:           notes,
+		Metadata:        string(metadata),
+	})
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests for email open/click tracking
+// ABOUTME: Verifies opens and clicks are counted and logged as interactions
+
+package charm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackedEmailEvents(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	email := &TrackedEmail{
+		ContactID:   contact.ID,
+		ContactName: contact.Name,
+		Subject:     "Following up",
+		Links:       []string{"https://example.com/deck"},
+	}
+	if err := client.CreateTrackedEmail(email); err != nil {
+		t.Fatalf("CreateTrackedEmail failed: %v", err)
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := client.RecordEmailOpen(email.ID, now); err != nil {
+			t.Fatalf("RecordEmailOpen failed: %v", err)
+		}
+	}
+	link, err := client.RecordEmailClick(email.ID, 0, now)
+	if err != nil {
+		t.Fatalf("RecordEmailClick failed: %v", err)
+	}
+	if link != "https://example.com/deck" {
+		t.Errorf("expected deck link, got %q", link)
+	}
+	if _, err := client.RecordEmailClick(email.ID, 1, now); err == nil {
+		t.Error("expected error for unknown link index")
+	}
+
+	got, err := client.GetTrackedEmail(email.ID)
+	if err != nil {
+		t.Fatalf("GetTrackedEmail failed: %v", err)
+	}
+	if got.Opens != 3 || got.Clicks != 1 || got.FirstOpenedAt == nil {
+		t.Errorf("unexpected counts: opens=%d clicks=%d first_opened=%v", got.Opens, got.Clicks, got.FirstOpenedAt)
+	}
+
+	// Sent, first open, and click are logged; repeat opens are not
+	logs, err := client.ListInteractionLogs(&InteractionFilter{ContactID: &contact.ID})
+	if err != nil {
+		t.Fatalf("ListInteractionLogs failed: %v", err)
+	}
+	if len(logs) != 3 {
+		t.Errorf("expected 3 interactions, got %d", len(logs))
+	}
+}
+
+func TestTrackedEmailURLs(t *testing.T) {
+	email := &TrackedEmail{Links: []string{"https://example.com"}}
+	if err := NewTestClient(t).CreateTrackedEmail(email); err != nil {
+		t.Fatalf("CreateTrackedEmail failed: %v", err)
+	}
+
+	if got, want := email.PixelURL("https://crm.example.com/"), "https://crm.example.com/t/o/"+email.ID.String()+".gif"; got != want {
+		t.Errorf("PixelURL = %q, want %q", got, want)
+	}
+	if got, want := email.LinkURL("https://crm.example.com", 0), "https://crm.example.com/t/c/"+email.ID.String()+"/0"; got != want {
+		t.Errorf("LinkURL = %q, want %q", got, want)
+	}
+}
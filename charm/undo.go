@@ -0,0 +1,105 @@
+// ABOUTME: Undo of recent changes recorded in the audit log
+// ABOUTME: Reverses creates, updates, and deletes newest first by deleting objects or restoring revisions
+
+package charm
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UndoableChanges returns the n most recent changes undo would reverse, newest
+// first: audit entries that aren't undos themselves and haven't been undone.
+// actor, when set, limits them to changes made through that entry point.
+func (c *Client) UndoableChanges(n int, actor string) ([]*AuditEntry, error) {
+	entries, err := c.ListAuditLog(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	undone := undoneEntries(entries)
+
+	var changes []*AuditEntry
+	for _, entry := range entries {
+		if len(changes) == n {
+			break
+		}
+		if entry.Undoes != nil || undone[entry.ID] || (actor != "" && entry.Actor != actor) {
+			continue
+		}
+		changes = append(changes, entry)
+	}
+	return changes, nil
+}
+
+// UndoChange reverses one change. A create is undone by deleting the object,
+// and an update, restore, or delete by restoring the revision from before it.
+// It fails when the object has changed since, other than by undos, so later
+// changes aren't lost; undo those first. The changes undo makes are recorded
+// in the audit log as undoing entry.
+func (c *Client) UndoChange(entry *AuditEntry) error {
+	history, err := c.ListAuditLog(&AuditFilter{EntityID: &entry.EntityID})
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	undone := undoneEntries(history)
+	if undone[entry.ID] || entry.Undoes != nil {
+		return Conflictf("change to %s %s is already undone", entry.EntityType, FormatID(entry.EntityID))
+	}
+	for _, later := range history {
+		if later.At.After(entry.At) && later.Undoes == nil && !undone[later.ID] {
+			return Conflictf("%s %s was changed again through %s at %s; undo that first",
+				entry.EntityType, FormatID(entry.EntityID), later.Actor, later.At.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	undoClient := *c
+	undoClient.undoing = &entry.ID
+	if entry.Op == RevisionOpCreate {
+		return undoClient.undoCreate(entry)
+	}
+	if _, err := undoClient.RestoreRevision(entry.EntityID, entry.UndoRev()); err != nil {
+		return fmt.Errorf("cannot undo %s of %s %s: %w", entry.Op, entry.EntityType, FormatID(entry.EntityID), err)
+	}
+	return nil
+}
+
+// undoCreate deletes a created object along with what hangs off it. A
+// company is only deleted once no contacts or deals refer to it.
+func (c *Client) undoCreate(entry *AuditEntry) error {
+	id := entry.EntityID
+	switch entry.EntityType {
+	case EntityContact:
+		return c.DeleteContactWithCascade(id)
+	case EntityDeal:
+		return c.DeleteDealWithCascade(id)
+	case EntityRelationship:
+		return c.DeleteRelationship(id)
+	case EntityCompany:
+		contacts, err := c.ListContacts(&ContactFilter{CompanyID: &id, IncludeArchived: true})
+		if err != nil {
+			return fmt.Errorf("failed to list contacts: %w", err)
+		}
+		deals, err := c.ListDeals(&DealFilter{CompanyID: &id})
+		if err != nil {
+			return fmt.Errorf("failed to list deals: %w", err)
+		}
+		if len(contacts) > 0 || len(deals) > 0 {
+			return Conflictf("company %s still has %d contact(s) and %d deal(s); undo or move those first",
+				FormatID(id), len(contacts), len(deals))
+		}
+		return c.DeleteCompany(id)
+	}
+	return fmt.Errorf("cannot undo create of entity type: %s", entry.EntityType)
+}
+
+// undoneEntries returns the IDs of the entries that later entries undid.
+func undoneEntries(entries []*AuditEntry) map[uuid.UUID]bool {
+	undone := make(map[uuid.UUID]bool)
+	for _, entry := range entries {
+		if entry.Undoes != nil {
+			undone[*entry.Undoes] = true
+		}
+	}
+	return undone
+}
@@ -0,0 +1,122 @@
+// ABOUTME: Tests for undoing recent changes
+// ABOUTME: Verifies creates, updates, and deletes are reversed newest first, and conflicts are refused
+
+package charm
+
+import (
+	"testing"
+)
+
+func undoLast(t *testing.T, client *Client, n int, actor string) {
+	t.Helper()
+	changes, err := client.UndoableChanges(n, actor)
+	if err != nil {
+		t.Fatalf("UndoableChanges failed: %v", err)
+	}
+	for _, change := range changes {
+		if err := client.UndoChange(change); err != nil {
+			t.Fatalf("UndoChange failed: %v", err)
+		}
+	}
+}
+
+func TestUndoUpdateAndDelete(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice", Email: "alice@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	mcpClient := client.ForActor(EntryMCP)
+	contact.Email = "mangled"
+	if err := mcpClient.UpdateContact(contact); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+	if err := mcpClient.DeleteContact(contact.ID); err != nil {
+		t.Fatalf("failed to delete contact: %v", err)
+	}
+
+	// The delete first, then the update
+	undoLast(t, client, 1, "")
+	restored, err := client.GetContact(contact.ID)
+	if err != nil || restored.Email != "mangled" {
+		t.Fatalf("expected the deleted contact back as it was, got %+v, %v", restored, err)
+	}
+	undoLast(t, client, 1, "")
+	restored, _ = client.GetContact(contact.ID)
+	if restored.Email != "alice@example.com" {
+		t.Errorf("expected the original email, got %q", restored.Email)
+	}
+
+	// Only the create is left, and undos are never undone themselves
+	changes, err := client.UndoableChanges(10, "")
+	if err != nil {
+		t.Fatalf("UndoableChanges failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Op != RevisionOpCreate {
+		t.Fatalf("expected only the create left, got %+v", changes)
+	}
+	entries, _ := client.ListAuditLog(&AuditFilter{EntityID: &contact.ID, Limit: 1})
+	if entries[0].Undoes == nil || entries[0].Actor != EntryCLI {
+		t.Errorf("expected the latest entry to be a cli undo, got %+v", entries[0])
+	}
+}
+
+func TestUndoCreate(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	deal := &Deal{Title: "Pilot", CompanyID: company.ID, Stage: StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	undoLast(t, client, 2, "")
+	if got, _ := client.GetDeal(deal.ID); got != nil {
+		t.Errorf("expected the deal deleted, got %+v", got)
+	}
+	if got, _ := client.GetCompany(company.ID); got != nil {
+		t.Errorf("expected the company deleted, got %+v", got)
+	}
+}
+
+func TestUndoRefusesConflicts(t *testing.T) {
+	client := NewTestClient(t)
+
+	company := &Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	company.Industry = "Widgets"
+	if err := client.ForActor(EntryMCP).UpdateCompany(company); err != nil {
+		t.Fatalf("failed to update company: %v", err)
+	}
+	company.Notes = "Checked by hand"
+	if err := client.UpdateCompany(company); err != nil {
+		t.Fatalf("failed to update company: %v", err)
+	}
+
+	// The mcp update was edited over since, so undoing it alone would lose the notes
+	changes, err := client.UndoableChanges(1, EntryMCP)
+	if err != nil {
+		t.Fatalf("UndoableChanges failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected the mcp update, got %d changes", len(changes))
+	}
+	if err := client.UndoChange(changes[0]); CodeOf(err) != CodeConflict {
+		t.Errorf("expected a conflict undoing an overwritten change, got %v", err)
+	}
+
+	// A company that still has contacts isn't deleted by undoing its create
+	if err := client.CreateContact(&Contact{Name: "Alice", CompanyID: &company.ID}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	entries, _ := client.ListAuditLog(&AuditFilter{EntityID: &company.ID})
+	if err := client.undoCreate(entries[len(entries)-1]); CodeOf(err) != CodeConflict {
+		t.Errorf("expected a conflict undoing the create of a company with contacts, got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+// ABOUTME: Deal stage history and pipeline velocity metrics
+// ABOUTME: Records when deals enter each stage and reports time in stage, stage conversion, and stalled deals
+
+package charm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultStalledDays is how long an open deal can sit in one stage before
+// it counts as stalled.
+const DefaultStalledDays = 30
+
+// StageChange records a deal entering a stage.
+type StageChange struct {
+	Stage string    `json:"stage"`
+	At    time.Time `json:"at"`
+}
+
+// recordStageChange appends the deal's stage to its history when it differs
+// from the last stage entered. Deals saved before stage history was kept get
+// theirs rebuilt from revisions first.
+func (c *Client) recordStageChange(deal *Deal, at time.Time) error {
+	if len(deal.StageHistory) == 0 {
+		history, err := c.stageHistoryFromRevisions(deal.ID, deal.CreatedAt)
+		if err != nil {
+			return err
+		}
+		deal.StageHistory = history
+	}
+	if n := len(deal.StageHistory); n == 0 || deal.StageHistory[n-1].Stage != deal.Stage {
+		deal.StageHistory = append(deal.StageHistory, StageChange{Stage: deal.Stage, At: at})
+	}
+	return nil
+}
+
+// DealStageHistory returns the stages the deal has entered, oldest first,
+// rebuilding them from revisions for deals saved before stage history was
+// kept. Rebuilt history only reaches back as far as the kept revisions.
+func (c *Client) DealStageHistory(deal *Deal) ([]StageChange, error) {
+	if len(deal.StageHistory) > 0 {
+		return deal.StageHistory, nil
+	}
+	history, err := c.stageHistoryFromRevisions(deal.ID, deal.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 && deal.Stage != "" {
+		history = []StageChange{{Stage: deal.Stage, At: deal.CreatedAt}}
+	}
+	return history, nil
+}
+
+// stageHistoryFromRevisions reads each stage change out of a deal's stored
+// revisions. The first stage is dated createdAt when the create revision is
+// still kept, and otherwise from the oldest kept revision.
+func (c *Client) stageHistoryFromRevisions(id uuid.UUID, createdAt time.Time) ([]StageChange, error) {
+	revisions, err := c.ListRevisions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deal revisions: %w", err)
+	}
+
+	var history []StageChange
+	for _, rev := range revisions {
+		if rev.Op == RevisionOpDelete {
+			continue
+		}
+		var snapshot struct {
+			Stage string `json:"stage"`
+		}
+		if err := json.Unmarshal(rev.Data, &snapshot); err != nil || snapshot.Stage == "" {
+			continue
+		}
+		if n := len(history); n > 0 && history[n-1].Stage == snapshot.Stage {
+			continue
+		}
+		at := rev.CreatedAt
+		if rev.Op == RevisionOpCreate && !createdAt.IsZero() {
+			at = createdAt
+		}
+		history = append(history, StageChange{Stage: snapshot.Stage, At: at})
+	}
+	return history, nil
+}
+
+// StageVelocity is how deals move through one open stage.
+type StageVelocity struct {
+	Stage          string  `json:"stage"`
+	Entered        int     `json:"entered"`         // deals that reached the stage
+	Current        int     `json:"current"`         // deals in the stage now
+	AvgDays        float64 `json:"avg_days"`        // mean days in the stage, over deals that have left it
+	Advanced       int     `json:"advanced"`        // left it for a later stage or a win
+	Lost           int     `json:"lost"`            // left it by being lost, or moved back
+	ConversionRate float64 `json:"conversion_rate"` // advanced / (advanced + lost), 0 when none have left
+}
+
+// StalledDeal is an open deal that has sat in its stage too long.
+type StalledDeal struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	CompanyName string    `json:"company_name,omitempty"`
+	Stage       string    `json:"stage"`
+	DaysInStage int       `json:"days_in_stage"`
+	Amount      int64     `json:"amount"` // in cents
+	Currency    string    `json:"currency"`
+}
+
+// PipelineMetrics reports deal velocity through the pipeline.
+type PipelineMetrics struct {
+	Stages         []StageVelocity `json:"stages"` // open stages in pipeline order
+	Won            int             `json:"won"`
+	Lost           int             `json:"lost"`
+	WinRate        float64         `json:"win_rate"`          // won / (won + lost)
+	AvgDaysToClose float64         `json:"avg_days_to_close"` // won deals, from their first stage to the win
+	StalledDays    int             `json:"stalled_days"`
+	Stalled        []StalledDeal   `json:"stalled"` // longest in stage first
+}
+
+// PipelineMetrics measures time in stage and conversion per open stage from
+// each deal's stage history, and lists open deals that have been in their
+// stage stalledDays or more (DefaultStalledDays when 0).
+func (c *Client) PipelineMetrics(stalledDays int, now time.Time) (*PipelineMetrics, error) {
+	if stalledDays <= 0 {
+		stalledDays = DefaultStalledDays
+	}
+	deals, err := c.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+
+	order := make(map[string]int, len(DealStages))
+	for i, stage := range DealStages {
+		order[stage] = i
+	}
+	type stageTotals struct {
+		StageVelocity
+		days float64
+	}
+	totals := make(map[string]*stageTotals)
+	for _, stage := range DealStages {
+		totals[stage] = &stageTotals{StageVelocity: StageVelocity{Stage: stage}}
+	}
+
+	metrics := &PipelineMetrics{StalledDays: stalledDays, Stages: []StageVelocity{}, Stalled: []StalledDeal{}}
+	var daysToClose float64
+	for _, deal := range deals {
+		history, err := c.DealStageHistory(deal)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, change := range history {
+			stage := totals[change.Stage]
+			if stage == nil {
+				continue
+			}
+			stage.Entered++
+			if i == len(history)-1 {
+				stage.Current++
+				continue
+			}
+			next := history[i+1]
+			stage.days += next.At.Sub(change.At).Hours() / 24
+			if next.Stage != StageClosedLost && order[next.Stage] > order[change.Stage] {
+				stage.Advanced++
+			} else {
+				stage.Lost++
+			}
+		}
+
+		switch deal.Stage {
+		case StageClosedWon:
+			metrics.Won++
+			if len(history) > 0 {
+				daysToClose += history[len(history)-1].At.Sub(history[0].At).Hours() / 24
+			}
+		case StageClosedLost:
+			metrics.Lost++
+		default:
+			if len(history) == 0 {
+				continue
+			}
+			days := int(now.Sub(history[len(history)-1].At).Hours() / 24)
+			if days >= stalledDays {
+				metrics.Stalled = append(metrics.Stalled, StalledDeal{
+					ID: deal.ID, Title: deal.Title, CompanyName: deal.CompanyName, Stage: deal.Stage,
+					DaysInStage: days, Amount: deal.Amount, Currency: deal.Currency,
+				})
+			}
+		}
+	}
+
+	for _, stage := range DealStages {
+		if stage == StageClosedWon || stage == StageClosedLost {
+			continue
+		}
+		t := totals[stage]
+		if left := t.Advanced + t.Lost; left > 0 {
+			t.AvgDays = t.days / float64(left)
+			t.ConversionRate = float64(t.Advanced) / float64(left)
+		}
+		metrics.Stages = append(metrics.Stages, t.StageVelocity)
+	}
+	if closed := metrics.Won + metrics.Lost; closed > 0 {
+		metrics.WinRate = float64(metrics.Won) / float64(closed)
+	}
+	if metrics.Won > 0 {
+		metrics.AvgDaysToClose = daysToClose / float64(metrics.Won)
+	}
+	sort.SliceStable(metrics.Stalled, func(i, j int) bool {
+		return metrics.Stalled[i].DaysInStage > metrics.Stalled[j].DaysInStage
+	})
+	return metrics, nil
+}
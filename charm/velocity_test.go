@@ -0,0 +1,123 @@
+// ABOUTME: Tests for deal stage history and pipeline velocity
+// ABOUTME: Verifies stage changes are recorded or rebuilt, and time in stage, conversion, and stalled deals
+
+package charm
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStageHistoryRecorded(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Pilot", Stage: StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	deal.Amount = 100000
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	deal.Stage = StageProposal
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+
+	stored, err := client.GetDeal(deal.ID)
+	if err != nil {
+		t.Fatalf("GetDeal failed: %v", err)
+	}
+	if len(stored.StageHistory) != 2 || stored.StageHistory[0].Stage != StageProspecting || stored.StageHistory[1].Stage != StageProposal {
+		t.Fatalf("expected prospecting then proposal, got %+v", stored.StageHistory)
+	}
+	if !stored.StageHistory[0].At.Equal(stored.CreatedAt) {
+		t.Errorf("expected the first stage entered at creation, got %v", stored.StageHistory[0].At)
+	}
+}
+
+func TestStageHistoryRebuiltFromRevisions(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Legacy", Stage: StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	deal.Stage = StageQualification
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+
+	// Strip the history, as on a deal saved before it was kept
+	deal.StageHistory = nil
+	data, _ := json.Marshal(deal)
+	if err := client.Set(DealKey(deal.ID.String()), data); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	history, err := client.DealStageHistory(deal)
+	if err != nil {
+		t.Fatalf("DealStageHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[0].Stage != StageProspecting || history[1].Stage != StageQualification {
+		t.Fatalf("expected the stages from revisions, got %+v", history)
+	}
+
+	// The next update keeps the rebuilt history and adds to it
+	deal.Stage = StageProposal
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if len(deal.StageHistory) != 3 || deal.StageHistory[2].Stage != StageProposal {
+		t.Errorf("expected three stages, got %+v", deal.StageHistory)
+	}
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	client := NewTestClient(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, n) }
+
+	for _, deal := range []*Deal{
+		{Title: "Won", Stage: StageClosedWon, StageHistory: []StageChange{
+			{StageProspecting, day(-60)}, {StageQualification, day(-50)}, {StageProposal, day(-30)}, {StageClosedWon, day(-20)}}},
+		{Title: "Lost early", Stage: StageClosedLost, StageHistory: []StageChange{
+			{StageProspecting, day(-40)}, {StageClosedLost, day(-36)}}},
+		{Title: "Stuck", Stage: StageProposal, StageHistory: []StageChange{
+			{StageProspecting, day(-50)}, {StageProposal, day(-45)}}},
+		{Title: "Fresh", Stage: StageProspecting, StageHistory: []StageChange{
+			{StageProspecting, day(-2)}}},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	metrics, err := client.PipelineMetrics(0, now)
+	if err != nil {
+		t.Fatalf("PipelineMetrics failed: %v", err)
+	}
+	if len(metrics.Stages) != 4 || metrics.StalledDays != DefaultStalledDays {
+		t.Fatalf("expected the four open stages and the default stall, got %+v", metrics)
+	}
+
+	prospecting := metrics.Stages[0]
+	if prospecting.Entered != 4 || prospecting.Current != 1 || prospecting.Advanced != 2 || prospecting.Lost != 1 {
+		t.Errorf("unexpected prospecting counts: %+v", prospecting)
+	}
+	if math.Abs(prospecting.AvgDays-19.0/3) > 0.01 || math.Abs(prospecting.ConversionRate-2.0/3) > 0.01 {
+		t.Errorf("expected 6.33 days and 67%% conversion, got %.2f and %.2f", prospecting.AvgDays, prospecting.ConversionRate)
+	}
+	if proposal := metrics.Stages[2]; proposal.Entered != 2 || proposal.Current != 1 || proposal.AvgDays != 10 || proposal.ConversionRate != 1 {
+		t.Errorf("unexpected proposal stage: %+v", proposal)
+	}
+
+	if metrics.Won != 1 || metrics.Lost != 1 || metrics.WinRate != 0.5 || metrics.AvgDaysToClose != 40 {
+		t.Errorf("unexpected close stats: %+v", metrics)
+	}
+	if len(metrics.Stalled) != 1 || metrics.Stalled[0].Title != "Stuck" || metrics.Stalled[0].DaysInStage != 45 {
+		t.Errorf("expected only Stuck stalled 45 days, got %+v", metrics.Stalled)
+	}
+}
@@ -0,0 +1,204 @@
+// ABOUTME: Watch subscriptions on individual deals and contacts
+// ABOUTME: Tracks the last revision seen per watched object so new changes can be notified
+
+package charm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// Watch is a subscription to changes on one deal or contact. Changes are read
+// from the object's revision history, so a watch only sees the revisions still
+// kept (see DefaultRevisionLimit) when it is checked.
+type Watch struct {
+	ObjectID   uuid.UUID `json:"object_id"`
+	EntityType string    `json:"entity_type"` // deal or contact
+	Name       string    `json:"name"`
+	Desktop    bool      `json:"desktop"`           // desktop notification
+	Webhook    string    `json:"webhook,omitempty"` // URL to POST changes to
+	LastRev    int       `json:"last_rev"`          // last revision notified
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WatchChange is a revision of a watched object that hasn't been notified yet.
+type WatchChange struct {
+	Watch    *Watch
+	Revision *Revision
+	Fields   []string // fields changed from the previous revision
+}
+
+// Message describes the change for a notification.
+func (wc *WatchChange) Message() string {
+	name := wc.Revision.Summary()
+	if name == "" {
+		name = wc.Watch.Name
+	}
+	switch wc.Revision.Op {
+	case RevisionOpUpdate:
+		if len(wc.Fields) > 0 {
+			return fmt.Sprintf("%s updated: %s", name, strings.Join(wc.Fields, ", "))
+		}
+		return name + " updated"
+	case RevisionOpCreate:
+		return name + " created"
+	case RevisionOpDelete:
+		return name + " deleted"
+	case RevisionOpRestore:
+		return name + " restored"
+	}
+	return name + " changed"
+}
+
+// AddWatch starts watching a deal or contact, or replaces the existing watch's
+// delivery settings. Only changes made after the watch starts are notified.
+func (c *Client) AddWatch(entityType string, id uuid.UUID, desktop bool, webhook string) (*Watch, error) {
+	var name string
+	switch entityType {
+	case EntityDeal:
+		deal, err := c.GetDeal(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deal: %w", err)
+		}
+		name = deal.Title
+	case EntityContact:
+		contact, err := c.GetContact(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contact: %w", err)
+		}
+		name = contact.Name
+	default:
+		return nil, fmt.Errorf("cannot watch entity type: %s (use %s or %s)", entityType, EntityDeal, EntityContact)
+	}
+	if !desktop && webhook == "" {
+		return nil, fmt.Errorf("a watch needs desktop notifications or a webhook")
+	}
+
+	revisions, err := c.ListRevisions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	lastRev := 0
+	if len(revisions) > 0 {
+		lastRev = revisions[len(revisions)-1].Rev
+	}
+
+	watch := &Watch{
+		ObjectID:   id,
+		EntityType: entityType,
+		Name:       name,
+		Desktop:    desktop,
+		Webhook:    webhook,
+		LastRev:    lastRev,
+		CreatedAt:  time.Now(),
+	}
+	if existing, err := c.GetWatch(id); err != nil {
+		return nil, err
+	} else if existing != nil {
+		watch.LastRev = existing.LastRev
+		watch.CreatedAt = existing.CreatedAt
+	}
+
+	if err := c.SaveWatch(watch); err != nil {
+		return nil, err
+	}
+	return watch, nil
+}
+
+// GetWatch returns the watch on an object, or nil if it isn't watched.
+func (c *Client) GetWatch(id uuid.UUID) (*Watch, error) {
+	data, err := c.Get(WatchKey(id.String()))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) || strings.Contains(err.Error(), "Key not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var watch Watch
+	if err := json.Unmarshal(data, &watch); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watch: %w", err)
+	}
+	return &watch, nil
+}
+
+// SaveWatch stores a watch.
+func (c *Client) SaveWatch(watch *Watch) error {
+	data, err := json.Marshal(watch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watch: %w", err)
+	}
+	return c.Set(WatchKey(watch.ObjectID.String()), data)
+}
+
+// RemoveWatch stops watching an object.
+func (c *Client) RemoveWatch(id uuid.UUID) error {
+	watch, err := c.GetWatch(id)
+	if err != nil {
+		return err
+	}
+	if watch == nil {
+		return fmt.Errorf("%s is not watched", id)
+	}
+	return c.Delete(WatchKey(id.String()))
+}
+
+// ListWatches returns all watches, oldest first.
+func (c *Client) ListWatches() ([]*Watch, error) {
+	keys, err := c.KeysWithPrefix([]byte(PrefixWatch))
+	if err != nil {
+		return nil, err
+	}
+
+	var watches []*Watch
+	for _, key := range keys {
+		data, err := c.Get(key)
+		if err != nil {
+			continue
+		}
+
+		var watch Watch
+		if err := json.Unmarshal(data, &watch); err != nil {
+			continue
+		}
+		watches = append(watches, &watch)
+	}
+
+	sort.Slice(watches, func(i, j int) bool {
+		return watches[i].CreatedAt.Before(watches[j].CreatedAt)
+	})
+	return watches, nil
+}
+
+// PendingChanges returns the revisions of a watched object after its LastRev,
+// oldest first. It doesn't advance LastRev; save the watch once they're delivered.
+func (c *Client) PendingChanges(watch *Watch) ([]*WatchChange, error) {
+	revisions, err := c.ListRevisions(watch.ObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	var changes []*WatchChange
+	var prev *Revision
+	for _, rev := range revisions {
+		if rev.Rev > watch.LastRev {
+			change := &WatchChange{Watch: watch, Revision: rev}
+			if rev.Op == RevisionOpUpdate {
+				change.Fields = rev.ChangedFields(prev)
+			}
+			changes = append(changes, change)
+		}
+		prev = rev
+	}
+	return changes, nil
+}
@@ -0,0 +1,92 @@
+// ABOUTME: Tests for watch subscriptions on deals and contacts
+// ABOUTME: Verifies pending changes come from revisions after the watch started
+
+package charm
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWatchPendingChanges(t *testing.T) {
+	client := NewTestClient(t)
+
+	deal := &Deal{Title: "Enterprise", Amount: 100000, Stage: StageProposal}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	watch, err := client.AddWatch(EntityDeal, deal.ID, true, "")
+	if err != nil {
+		t.Fatalf("AddWatch failed: %v", err)
+	}
+	if watch.LastRev != 1 || watch.Name != "Enterprise" {
+		t.Errorf("expected the watch to start after the create, got %+v", watch)
+	}
+
+	changes, err := client.PendingChanges(watch)
+	if err != nil {
+		t.Fatalf("PendingChanges failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes before an update, got %d", len(changes))
+	}
+
+	deal.Stage = StageNegotiation
+	if err := client.UpdateDeal(deal); err != nil {
+		t.Fatalf("failed to update deal: %v", err)
+	}
+
+	changes, err = client.PendingChanges(watch)
+	if err != nil {
+		t.Fatalf("PendingChanges failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Revision.Rev != 2 {
+		t.Fatalf("expected one change at rev 2, got %+v", changes)
+	}
+	if msg := changes[0].Message(); msg != "Enterprise updated: stage" {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	// Re-watching keeps the last seen revision
+	if watch, err = client.AddWatch(EntityDeal, deal.ID, false, "https://example.com/hook"); err != nil {
+		t.Fatalf("AddWatch failed: %v", err)
+	}
+	if watch.LastRev != 1 || watch.Desktop {
+		t.Errorf("expected settings replaced and LastRev kept, got %+v", watch)
+	}
+}
+
+func TestWatchValidation(t *testing.T) {
+	client := NewTestClient(t)
+
+	contact := &Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	if _, err := client.AddWatch(EntityContact, uuid.New(), true, ""); err == nil {
+		t.Error("expected watching a missing contact to fail")
+	}
+	if _, err := client.AddWatch(EntityCompany, contact.ID, true, ""); err == nil {
+		t.Error("expected watching a company to fail")
+	}
+	if _, err := client.AddWatch(EntityContact, contact.ID, false, ""); err == nil {
+		t.Error("expected a watch without delivery to fail")
+	}
+
+	if _, err := client.AddWatch(EntityContact, contact.ID, true, ""); err != nil {
+		t.Fatalf("AddWatch failed: %v", err)
+	}
+	watches, err := client.ListWatches()
+	if err != nil || len(watches) != 1 {
+		t.Fatalf("expected one watch, got %d, %v", len(watches), err)
+	}
+	if err := client.RemoveWatch(contact.ID); err != nil {
+		t.Fatalf("RemoveWatch failed: %v", err)
+	}
+	if err := client.RemoveWatch(contact.ID); err == nil {
+		t.Error("expected removing an unwatched contact to fail")
+	}
+}
@@ -0,0 +1,89 @@
+// ABOUTME: REST API token CLI commands
+// ABOUTME: Creates, lists, and revokes the bearer tokens scripts use against /api/v1
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// WebTokenCommand manages REST API tokens:
+// pagen web token [list] | create <name> | revoke <name|id>
+func WebTokenCommand(client *charm.Client, args []string) error {
+	action := ""
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "", "list":
+		return tokenListCommand(client)
+	case "create":
+		return tokenCreateCommand(client, args)
+	case "revoke":
+		return tokenRevokeCommand(client, args)
+	default:
+		return fmt.Errorf("usage: web token [list] | create <name> | revoke <name|id>")
+	}
+}
+
+func tokenCreateCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("create", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		return fmt.Errorf("token name is required, e.g. pagen web token create \"iPhone shortcuts\"")
+	}
+	token, secret, err := client.CreateAPIToken(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Created API token %s\n", token.Name)
+	fmt.Printf("  %s\n", secret)
+	fmt.Println("  Copy it now; it won't be shown again. Send it as 'Authorization: Bearer <token>'.")
+	return nil
+}
+
+func tokenListCommand(client *charm.Client) error {
+	tokens, err := client.ListAPITokens()
+	if err != nil {
+		return fmt.Errorf("failed to list API tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		fmt.Println("No API tokens (create one with 'pagen web token create <name>')")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tTOKEN\tCREATED")
+	_, _ = fmt.Fprintln(w, "--\t----\t-----\t-------")
+	for _, token := range tokens {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s…\t%s\n",
+			charm.FormatID(token.ID), token.Name, token.Hint, token.CreatedAt.Format("2006-01-02"))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+func tokenRevokeCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("revoke", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	ref := strings.Join(fs.Args(), " ")
+	if ref == "" {
+		return charm.Validationf("token name or ID is required")
+	}
+	token, err := client.RevokeAPIToken(ref)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Revoked API token %s\n", token.Name)
+	return nil
+}
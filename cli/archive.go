@@ -0,0 +1,152 @@
+// ABOUTME: Stale-contact archival CLI commands
+// ABOUTME: Reports and archives untouched synced contacts, configures the archive policy, and unarchives
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ArchiveStaleCommand lists stale synced contacts, archiving them with --apply.
+func ArchiveStaleCommand(client *charm.Client, args []string) error {
+	cfg := client.Config()
+
+	fs := flag.NewFlagSet("archive-stale", flagErrorHandling)
+	months := fs.Int("months", cfg.ArchivePolicy.MonthsOrDefault(), "Only contacts imported more than this many months ago")
+	apply := fs.Bool("apply", false, "Archive the listed contacts (default: dry run)")
+	_ = fs.Parse(args)
+
+	stale, err := client.StaleContacts(*months, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to find stale contacts: %w", err)
+	}
+
+	if len(stale) == 0 {
+		fmt.Println("No stale contacts")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tEMAIL\tSOURCE\tIMPORTED\tID")
+	_, _ = fmt.Fprintln(w, "----\t-----\t------\t--------\t--")
+
+	for _, s := range stale {
+		email := s.Contact.Email
+		if email == "" {
+			email = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			s.Contact.Name, email, s.Source, s.ImportedAt.Format("2006-01-02"), charm.FormatID(s.Contact.ID))
+	}
+	_ = w.Flush()
+
+	if !*apply {
+		fmt.Printf("\n%d contact(s) would be archived. Run with --apply to archive them.\n", len(stale))
+		return nil
+	}
+
+	for _, s := range stale {
+		if _, err := client.ArchiveContact(s.Contact.ID); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", s.Contact.Name, err)
+		}
+	}
+	fmt.Printf("\n✓ Archived %d contact(s). Restore one with: pagen crm unarchive-contact <id>\n", len(stale))
+	return nil
+}
+
+// ArchivePolicyCommand shows or changes the automatic archive policy.
+func ArchivePolicyCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("archive-policy", flagErrorHandling)
+	enable := fs.Bool("enable", false, "Turn the policy on (starts in dry run)")
+	disable := fs.Bool("disable", false, "Turn the policy off")
+	months := fs.Int("months", 0, "Archive synced contacts untouched for this many months")
+	dryRun := fs.Bool("dry-run", true, "Only log what would be archived; --dry-run=false archives")
+	_ = fs.Parse(args)
+
+	if *enable && *disable {
+		return fmt.Errorf("--enable and --disable are mutually exclusive")
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["months"] && *months <= 0 {
+		return fmt.Errorf("--months must be positive")
+	}
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(set) > 0 {
+		policy := cfg.ArchivePolicy
+		if policy == nil {
+			policy = &charm.ArchivePolicy{DryRun: true}
+		}
+		if *enable {
+			if !policy.Enabled && !set["dry-run"] {
+				// Re-enabling always starts with a dry run to review
+				policy.DryRun = true
+			}
+			policy.Enabled = true
+		}
+		if *disable {
+			policy.Enabled = false
+		}
+		if set["months"] {
+			policy.Months = *months
+		}
+		if set["dry-run"] {
+			policy.DryRun = *dryRun
+		}
+
+		cfg.ArchivePolicy = policy
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println("✓ Archive policy updated")
+	}
+
+	policy := cfg.ArchivePolicy
+	switch {
+	case policy == nil || !policy.Enabled:
+		fmt.Println("Archive policy: off")
+	case policy.DryRun:
+		fmt.Printf("Archive policy: dry run (synced contacts untouched for %d months are reported, not archived)\n", policy.MonthsOrDefault())
+	default:
+		fmt.Printf("Archive policy: on (synced contacts untouched for %d months are archived daily)\n", policy.MonthsOrDefault())
+	}
+	if policy != nil && policy.Enabled {
+		fmt.Println("The policy runs with the background jobs (after `pagen sync now`, and in `pagen mcp` and `pagen web`); review candidates with: pagen crm archive-stale")
+	}
+	return nil
+}
+
+// UnarchiveContactCommand returns an archived contact to lists.
+func UnarchiveContactCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("unarchive-contact", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	// First positional arg is the contact ID
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("contact ID is required")
+	}
+
+	id, err := resolveID(client, fs.Arg(0), charm.EntityContact)
+	if err != nil {
+		return err
+	}
+
+	contact, err := client.UnarchiveContact(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Contact unarchived: %s (ID: %s)\n", contact.Name, contact.ID)
+	return nil
+}
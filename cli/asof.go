@@ -0,0 +1,137 @@
+// ABOUTME: Time-travel CLI command
+// ABOUTME: Lists deals, contacts, or companies as they were on a past date, rebuilt from revision history
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// AsOfCommand runs a list command against the CRM state at the end of a past
+// date: pagen crm asof 2024-06-30 list-deals --stage proposal
+func AsOfCommand(client *charm.Client, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: pagen crm asof <YYYY-MM-DD|RFC3339> <list-deals|list-contacts|list-companies> [flags]")
+	}
+
+	at, err := parseAsOf(args[0])
+	if err != nil {
+		return err
+	}
+	if at.After(time.Now()) {
+		return fmt.Errorf("as-of date is in the future: %s", args[0])
+	}
+
+	snapshot, err := client.SnapshotAsOf(at)
+	if err != nil {
+		return fmt.Errorf("failed to reconstruct state: %w", err)
+	}
+
+	fmt.Printf("As of %s\n\n", at.Format("2006-01-02 15:04"))
+	switch args[1] {
+	case "list-deals":
+		err = asOfListDeals(snapshot, args[2:])
+	case "list-contacts":
+		err = asOfListContacts(snapshot, args[2:])
+	case "list-companies":
+		err = asOfListCompanies(snapshot, args[2:])
+	default:
+		return fmt.Errorf("unknown asof command: %s (use list-deals, list-contacts, or list-companies)", args[1])
+	}
+	if err != nil {
+		return err
+	}
+
+	if snapshot.Approximate > 0 {
+		fmt.Printf("\n⚠ %d record(s) have no revision from that date and are shown as last saved (raise revision_limit to keep more history)\n", snapshot.Approximate)
+	}
+	return nil
+}
+
+// parseAsOf parses a date as the end of that day in local time, or an exact RFC 3339 time.
+func parseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, charm.Validationf("invalid as-of date (use YYYY-MM-DD or RFC3339): %s", value)
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+func asOfListDeals(snapshot *charm.Snapshot, args []string) error {
+	fs := flag.NewFlagSet("list-deals", flagErrorHandling)
+	stage := fs.String("stage", "", "Filter by stage")
+	source := fs.String("source", "", "Filter by source")
+	company := fs.String("company", "", "Filter by company name")
+	limit := fs.Int("limit", 0, "Maximum results (default: all)")
+	withStats := fs.Bool("with-stats", false, "Show totals and expected value per stage")
+	_ = fs.Parse(args)
+
+	filter := &charm.DealFilter{
+		Stage:  *stage,
+		Source: *source,
+		Limit:  *limit,
+	}
+	if *company != "" {
+		if existing := snapshot.FindCompanyByName(*company); existing != nil {
+			filter.CompanyID = &existing.ID
+		}
+	}
+
+	deals := snapshot.Deals(filter)
+	if len(deals) == 0 {
+		fmt.Println("No deals found")
+		return nil
+	}
+	printDeals(deals, *withStats)
+	return nil
+}
+
+func asOfListContacts(snapshot *charm.Snapshot, args []string) error {
+	fs := flag.NewFlagSet("list-contacts", flagErrorHandling)
+	query := fs.String("query", "", "Search by name or email")
+	company := fs.String("company", "", "Filter by company name")
+	limit := fs.Int("limit", 0, "Maximum results (default: all)")
+	archived := fs.Bool("archived", false, "Include contacts archived by then")
+	_ = fs.Parse(args)
+
+	filter := &charm.ContactFilter{
+		Query:           *query,
+		Limit:           *limit,
+		IncludeArchived: *archived,
+	}
+	if *company != "" {
+		if existing := snapshot.FindCompanyByName(*company); existing != nil {
+			filter.CompanyID = &existing.ID
+		}
+	}
+
+	contacts := snapshot.Contacts(filter)
+	if len(contacts) == 0 {
+		fmt.Println("No contacts found")
+		return nil
+	}
+	printContacts(contacts)
+	return nil
+}
+
+func asOfListCompanies(snapshot *charm.Snapshot, args []string) error {
+	fs := flag.NewFlagSet("list-companies", flagErrorHandling)
+	limit := fs.Int("limit", 0, "Maximum results (default: all)")
+	filter := companyFilterFlags(fs)
+	_ = fs.Parse(args)
+
+	filter.Limit = *limit
+	companies := snapshot.Companies(filter)
+	if len(companies) == 0 {
+		fmt.Println("No companies found")
+		return nil
+	}
+	printCompanies(companies)
+	return nil
+}
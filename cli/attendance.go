@@ -0,0 +1,72 @@
+// ABOUTME: CLI command for importing Zoom/Google Meet attendance exports
+// ABOUTME: Logs meeting interactions with actual durations for matched contacts
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// ImportAttendanceCommand imports an attendance CSV and logs meeting interactions.
+func ImportAttendanceCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("import-attendance", flagErrorHandling)
+	file := fs.String("file", "", "Attendance CSV export from Zoom or Google Meet (required)")
+	date := fs.String("date", "", "Meeting date (YYYY-MM-DD) for exports that only include times (default: today)")
+	topic := fs.String("topic", "", "Meeting title to use for logged interactions")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without writing")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return charm.Validationf("--file is required")
+	}
+
+	meetingDate := time.Now()
+	if *date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+		if err != nil {
+			return charm.Validationf("invalid date format (use YYYY-MM-DD): %w", err)
+		}
+		meetingDate = parsed
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open attendance file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	report, err := sync.ParseAttendanceCSV(f, meetingDate)
+	if err != nil {
+		return fmt.Errorf("failed to parse attendance file: %w", err)
+	}
+	if *topic != "" {
+		report.Topic = *topic
+	}
+
+	result, err := sync.ImportAttendance(client, report, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Logged"
+	if *dryRun {
+		verb = "Would log"
+	}
+	fmt.Printf("✓ %s %d meeting interactions from %s export (%d participants)\n", verb, result.Logged, report.Source, len(report.Records))
+	if result.Skipped > 0 {
+		fmt.Printf("  Skipped %d already imported\n", result.Skipped)
+	}
+	if len(result.Unmatched) > 0 {
+		fmt.Printf("  %d participants not in CRM:\n", len(result.Unmatched))
+		for _, email := range result.Unmatched {
+			fmt.Printf("    - %s\n", email)
+		}
+	}
+
+	return nil
+}
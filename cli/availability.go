@@ -0,0 +1,142 @@
+// ABOUTME: Calendar availability for follow-ups
+// ABOUTME: Suggests open Google Calendar slots for catch-ups and schedules them as tasks
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// slotSearchDays is how far ahead catch-up slots are looked for.
+const slotSearchDays = 7
+
+// freeSlots returns open slots on the Google Calendar over the next days,
+// also skipping catch-ups already scheduled as tasks.
+func freeSlots(client *charm.Client, now time.Time, days int, opts sync.SlotOptions) ([]sync.TimeSlot, error) {
+	token, err := sync.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("no authentication token found. Run 'pagen sync init' first: %w", err)
+	}
+	service, err := sync.NewCalendarClient(token)
+	if err != nil {
+		return nil, err
+	}
+
+	to := now.AddDate(0, 0, days)
+	busy, err := sync.QueryBusy(context.Background(), service, now, to)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := client.ListTasks(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	busy = append(busy, scheduledBusy(tasks, opts.Duration)...)
+	return sync.FreeSlots(busy, now, to, opts), nil
+}
+
+// scheduledBusy treats open tasks due at a time of day, such as catch-ups
+// from `followups schedule`, as busy for the slot length. Tasks due on a day
+// but not at a time don't block anything.
+func scheduledBusy(tasks []*charm.Task, duration time.Duration) []sync.TimeSlot {
+	if duration <= 0 {
+		duration = time.Hour
+	}
+	var busy []sync.TimeSlot
+	for _, task := range tasks {
+		if task.DoneAt != nil || task.DueAt == nil {
+			continue
+		}
+		due := *task.DueAt
+		if due.Hour() == 0 && due.Minute() == 0 {
+			continue
+		}
+		busy = append(busy, sync.TimeSlot{Start: due, End: due.Add(duration)})
+	}
+	return busy
+}
+
+// parseSlotTime parses a catch-up time: "YYYY-MM-DD HH:MM" in local time, or RFC 3339.
+func parseSlotTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, charm.Validationf("invalid time %q (use YYYY-MM-DD HH:MM)", value)
+}
+
+// ScheduleFollowupCommand schedules a catch-up with a contact: at the given
+// time, or in the first open slot on the Google Calendar. The catch-up is
+// saved as a task due at that time and becomes the contact's next follow-up.
+func ScheduleFollowupCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("schedule", flagErrorHandling)
+	contactIDStr := fs.String("contact", "", "Contact ID or name (required)")
+	at := fs.String("at", "", "When to catch up, YYYY-MM-DD HH:MM (default: the first open calendar slot)")
+	length := fs.Duration("slot-length", time.Hour, "Length of the catch-up")
+	within := fs.Int("within", slotSearchDays, "Days ahead to look for an open slot")
+	_ = fs.Parse(args)
+
+	if *contactIDStr == "" {
+		return charm.Validationf("--contact is required")
+	}
+	contactID, err := resolveID(client, *contactIDStr, charm.EntityContact)
+	if err != nil {
+		return err
+	}
+	contact, err := client.GetContact(contactID)
+	if err != nil {
+		return err
+	}
+
+	var slot sync.TimeSlot
+	if *at != "" {
+		start, err := parseSlotTime(*at)
+		if err != nil {
+			return err
+		}
+		slot = sync.TimeSlot{Start: start, End: start.Add(*length)}
+	} else {
+		slots, err := freeSlots(client, time.Now(), *within, sync.SlotOptions{Duration: *length})
+		if err != nil {
+			return err
+		}
+		if len(slots) == 0 {
+			return fmt.Errorf("no open %s slot in the next %d days", *length, *within)
+		}
+		slot = slots[0]
+	}
+
+	task := &charm.Task{
+		Title:       fmt.Sprintf("Catch up with %s", contact.Name),
+		ContactID:   &contact.ID,
+		ContactName: contact.Name,
+		DueAt:       &slot.Start,
+	}
+	if err := client.CreateTask(task); err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	cadence, err := client.GetContactCadence(contactID)
+	if err != nil {
+		return fmt.Errorf("failed to get cadence: %w", err)
+	}
+	if cadence != nil {
+		cadence.NextFollowupDate = &slot.Start
+		if err := client.SaveContactCadence(cadence); err != nil {
+			return fmt.Errorf("failed to save cadence: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Scheduled catch-up with %s: %s (%s)\n", contact.Name, slot, slot.Start.Format("2006-01-02"))
+	return nil
+}
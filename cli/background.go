@@ -0,0 +1,260 @@
+// ABOUTME: Background jobs that keep derived CRM data current without a separate daemon
+// ABOUTME: Run after each `pagen sync now` and hourly by the MCP and web servers, each job at its own cadence
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/db"
+)
+
+// BackgroundJobInterval is how often the MCP and web servers run the
+// background jobs while they're up.
+const BackgroundJobInterval = time.Hour
+
+// backgroundJobsFile records when each job last ran on this machine. It is a
+// local file rather than a KV key so every device keeps its own schedule.
+const backgroundJobsFile = "background-jobs.json"
+
+// backgroundJob is one job RunBackgroundJobs can run. A job with no Every
+// runs each time; otherwise it waits until Every has passed since it last
+// succeeded. Jobs may keep their own bookkeeping in state.
+type backgroundJob struct {
+	Name  string
+	Every time.Duration
+	Run   func(client *charm.Client, state *backgroundJobState, now time.Time, logf func(format string, args ...any)) error
+}
+
+var backgroundJobs = []backgroundJob{
+	{Name: "sync-watchdog", Run: syncWatchdogJob},
+	{Name: "priorities", Every: 24 * time.Hour, Run: recomputePrioritiesJob},
+	{Name: "archive-policy", Every: 24 * time.Hour, Run: archivePolicyJob},
+	{Name: "pipeline-snapshot", Every: 6 * time.Hour, Run: pipelineSnapshotJob},
+	{Name: "stage-timers", Run: stageTimersJob},
+	{Name: "maintenance", Run: maintenanceJob},
+}
+
+// maxSnapshotGapWeeks caps how many missed weeks one run rebuilds.
+const maxSnapshotGapWeeks = 52
+
+// backgroundJobState is the content of backgroundJobsFile.
+type backgroundJobState struct {
+	LastRun map[string]time.Time `json:"last_run"`
+
+	// MaintainedSyncLogs is how many sync log entries there were when the
+	// maintenance job last ran, or first saw the database.
+	MaintainedSyncLogs *int `json:"maintained_sync_logs,omitempty"`
+}
+
+// RunBackgroundJobs runs every background job that is due, logging what each
+// did with logf. A failing job is logged and doesn't stop the others.
+func RunBackgroundJobs(client *charm.Client, now time.Time, logf func(format string, args ...any)) {
+	state := loadBackgroundJobState()
+	for _, job := range backgroundJobs {
+		if last, ok := state.LastRun[job.Name]; ok && job.Every > 0 && now.Sub(last) < job.Every {
+			continue
+		}
+		if err := job.Run(client, state, now, logf); err != nil {
+			logf("Background job %s failed: %v", job.Name, err)
+			continue
+		}
+		state.LastRun[job.Name] = now
+	}
+	if err := saveBackgroundJobState(state); err != nil {
+		logf("Failed to save background job state: %v", err)
+	}
+}
+
+// RunBackgroundJobsEvery runs the background jobs now and then every interval
+// until ctx is done, for long-running commands.
+func RunBackgroundJobsEvery(ctx context.Context, client *charm.Client, interval time.Duration, logf func(format string, args ...any)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		RunBackgroundJobs(client, time.Now(), logf)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncWatchdogJob resets services left "syncing" by a process that died, so
+// they don't stay stuck until someone runs `pagen sync status`.
+func syncWatchdogJob(client *charm.Client, _ *backgroundJobState, now time.Time, logf func(format string, args ...any)) error {
+	reset, err := client.ResetStaleSyncStates(charm.DefaultSyncStaleAfter, now)
+	for _, state := range reset {
+		logf("Reset %s sync, stuck syncing since %s", state.Service, state.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return err
+}
+
+// recomputePrioritiesJob refreshes stored engagement and priority scores, so
+// they decay daily even when nobody lists follow-ups.
+func recomputePrioritiesJob(client *charm.Client, _ *backgroundJobState, now time.Time, logf func(format string, args ...any)) error {
+	updated, err := client.RecomputePriorityScores(now)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		logf("Recomputed priority for %d contacts", updated)
+	}
+	return nil
+}
+
+// archivePolicyJob applies the archive_policy config setting. In dry run it
+// only logs how many contacts would be archived.
+func archivePolicyJob(client *charm.Client, _ *backgroundJobState, now time.Time, logf func(format string, args ...any)) error {
+	policy := client.Config().ArchivePolicy
+	if policy == nil || !policy.Enabled {
+		return nil
+	}
+
+	stale, archived, err := client.ApplyArchivePolicy(policy, now)
+	if err != nil {
+		return err
+	}
+	if policy.DryRun {
+		if len(stale) > 0 {
+			logf("Archive policy (dry run): %d stale contact(s) would be archived; review with `pagen crm archive-stale`", len(stale))
+		}
+		return nil
+	}
+	if archived > 0 {
+		logf("Archive policy: archived %d stale contact(s)", archived)
+	}
+	return nil
+}
+
+// pipelineSnapshotJob takes this week's pipeline snapshot. Weeks missed since
+// the latest snapshot, while nothing ran the jobs, are rebuilt from revision
+// history first so the trend has no gaps.
+func pipelineSnapshotJob(client *charm.Client, _ *backgroundJobState, now time.Time, logf func(format string, args ...any)) error {
+	snapshots, err := client.ListPipelineSnapshots()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) > 0 {
+		latest := snapshots[len(snapshots)-1].WeekStart()
+		thisWeek, _ := time.ParseInLocation("2006-01-02", charm.WeekOf(now), time.Local)
+		missed := int(thisWeek.Sub(latest).Hours()/(24*7)+0.5) - 1
+		if missed > maxSnapshotGapWeeks {
+			missed = maxSnapshotGapWeeks
+		}
+		if missed > 0 {
+			added, err := client.BackfillPipelineSnapshots(missed, now)
+			if err != nil {
+				return err
+			}
+			if added > 0 {
+				logf("Rebuilt %d missed pipeline snapshot(s) from revision history", added)
+			}
+		}
+	}
+
+	taken, err := client.EnsureWeeklySnapshot(now)
+	if err != nil {
+		return err
+	}
+	if taken {
+		logf("Pipeline snapshot taken for week of %s", charm.WeekOf(now))
+	}
+	return nil
+}
+
+// stageTimersJob fires the stage_timers config setting, so deals sitting in a
+// stage too long are acted on without `pagen watch run`. Each timer still
+// fires once per stage entry however often the jobs run.
+func stageTimersJob(client *charm.Client, _ *backgroundJobState, now time.Time, logf func(format string, args ...any)) error {
+	alerts, err := client.RunStageTimers(client.Config().StageTimers, now, stageTimerNotifier(desktopNotify))
+	for _, alert := range alerts {
+		logf("Stage timer: %s (%s)", alert.Message(), strings.Join(alert.Actions, ", "))
+	}
+	return err
+}
+
+// maintenanceJob runs full database maintenance once syncs have imported at
+// least maintain_after records since it last ran. A large import leaves free
+// pages behind and the planner statistics out of date.
+func maintenanceJob(client *charm.Client, state *backgroundJobState, _ time.Time, logf func(format string, args ...any)) error {
+	threshold := client.Config().MaintainAfter
+	if threshold <= 0 {
+		return nil
+	}
+
+	imported, err := client.CountSyncLogs()
+	if err != nil {
+		return err
+	}
+	if state.MaintainedSyncLogs == nil {
+		// Count from here rather than maintaining an existing database at once
+		state.MaintainedSyncLogs = &imported
+		return nil
+	}
+	if imported-*state.MaintainedSyncLogs < threshold {
+		return nil
+	}
+
+	logf("Imported %d records, running database maintenance...", imported-*state.MaintainedSyncLogs)
+	report, err := maintainDatabase(client)
+	if err != nil {
+		return err
+	}
+	if !report.IntegrityOK {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(report.IntegrityErrors, "; "))
+	}
+	state.MaintainedSyncLogs = &imported
+	logf("Database maintained in %.2fs (%s → %s)",
+		report.Duration.Seconds(), formatBytes(report.SizeBefore), formatBytes(report.SizeAfter))
+	return nil
+}
+
+// maintainDatabase runs full maintenance on the client's Charm KV database.
+// Tests replace it, since test clients have no SQLite file.
+var maintainDatabase = func(client *charm.Client) (*db.MaintenanceReport, error) {
+	path := ""
+	database, err := openMaintainDB(client, &path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = database.Close() }()
+	return db.Maintain(database, db.FullMaintenance)
+}
+
+func backgroundJobStatePath() string {
+	return filepath.Join(charm.DataDir(), backgroundJobsFile)
+}
+
+// loadBackgroundJobState reads the job schedule. A missing or unreadable
+// file means every job is due.
+func loadBackgroundJobState() *backgroundJobState {
+	state := &backgroundJobState{}
+	if data, err := os.ReadFile(backgroundJobStatePath()); err == nil {
+		_ = json.Unmarshal(data, state)
+	}
+	if state.LastRun == nil {
+		state.LastRun = make(map[string]time.Time)
+	}
+	return state
+}
+
+func saveBackgroundJobState(state *backgroundJobState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := backgroundJobStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,233 @@
+// ABOUTME: Tests for the background jobs run after syncs and by the servers
+// ABOUTME: Verifies each job runs when due, keeps its own schedule, and logs what it did
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/db"
+)
+
+// backgroundJobsClient returns a test client whose job schedule is kept in a
+// temporary data directory, and a function returning what the jobs logged.
+func backgroundJobsClient(t *testing.T) (*charm.Client, func(format string, args ...any), func() string) {
+	t.Helper()
+	// Runs after t.Setenv restores the environment
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+
+	var logged []string
+	logf := func(format string, args ...any) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	return charm.NewTestClient(t), logf, func() string {
+		out := strings.Join(logged, "\n")
+		logged = nil
+		return out
+	}
+}
+
+func TestBackgroundJobsRecomputePriorities(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	contact := &charm.Contact{Name: "Ada"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	last := now.AddDate(0, 0, -40)
+	if err := client.SaveContactCadence(&charm.ContactCadence{
+		ContactID:            contact.ID,
+		ContactName:          contact.Name,
+		CadenceDays:          30,
+		RelationshipStrength: charm.StrengthMedium,
+		LastInteractionDate:  &last,
+	}); err != nil {
+		t.Fatalf("SaveContactCadence failed: %v", err)
+	}
+
+	RunBackgroundJobs(client, now, logf)
+	if got := logs(); !strings.Contains(got, "Recomputed priority for 1 contacts") {
+		t.Errorf("expected a recompute, got %q", got)
+	}
+	cadence, err := client.GetContactCadence(contact.ID)
+	if err != nil {
+		t.Fatalf("GetContactCadence failed: %v", err)
+	}
+	if cadence.EngagementUpdatedAt == nil || !cadence.EngagementUpdatedAt.Equal(now) || cadence.PriorityScore == 0 {
+		t.Errorf("expected priority recomputed at %s, got %+v", now, cadence)
+	}
+
+	// Once a day, however often the jobs run
+	RunBackgroundJobs(client, now.Add(time.Hour), logf)
+	if got := logs(); strings.Contains(got, "Recomputed") {
+		t.Errorf("expected no recompute an hour later, got %q", got)
+	}
+	RunBackgroundJobs(client, now.Add(25*time.Hour), logf)
+	if got := logs(); !strings.Contains(got, "Recomputed") {
+		t.Errorf("expected a recompute the next day, got %q", got)
+	}
+}
+
+func TestBackgroundJobsArchivePolicy(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	contact := &charm.Contact{Name: "Stale", Email: "stale@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	if err := client.CreateSyncLog(&charm.SyncLog{SourceService: "contacts", SourceID: "stale", EntityType: charm.EntityContact, EntityID: contact.ID}); err != nil {
+		t.Fatalf("CreateSyncLog failed: %v", err)
+	}
+	later := time.Now().AddDate(1, 1, 0)
+
+	// Off by default, then a dry run only reports
+	RunBackgroundJobs(client, later, logf)
+	if got := logs(); strings.Contains(got, "Archive policy") {
+		t.Errorf("expected no archiving without a policy, got %q", got)
+	}
+	client.Config().ArchivePolicy = &charm.ArchivePolicy{Enabled: true, Months: 12, DryRun: true}
+	RunBackgroundJobs(client, later.Add(25*time.Hour), logf)
+	if got := logs(); !strings.Contains(got, "1 stale contact(s) would be archived") {
+		t.Errorf("expected a dry run report, got %q", got)
+	}
+
+	client.Config().ArchivePolicy.DryRun = false
+	RunBackgroundJobs(client, later.Add(50*time.Hour), logf)
+	if got := logs(); !strings.Contains(got, "archived 1 stale contact(s)") {
+		t.Errorf("expected the contact archived, got %q", got)
+	}
+	if got, _ := client.GetContact(contact.ID); got == nil || got.ArchivedAt == nil {
+		t.Errorf("expected the contact archived, got %+v", got)
+	}
+}
+
+func TestBackgroundJobsPipelineSnapshot(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	company := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	if err := client.CreateDeal(&charm.Deal{Title: "Pilot", CompanyID: company.ID, Stage: charm.StageProposal, Amount: 2000}); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	now := time.Now()
+	RunBackgroundJobs(client, now, logf)
+	if got := logs(); !strings.Contains(got, "Pipeline snapshot taken for week of "+charm.WeekOf(now)) {
+		t.Errorf("expected this week's snapshot, got %q", got)
+	}
+
+	// Three weeks with nothing running the jobs leaves two weeks to rebuild
+	later := now.AddDate(0, 0, 21)
+	RunBackgroundJobs(client, later, logf)
+	if got := logs(); !strings.Contains(got, "Rebuilt 2 missed pipeline snapshot(s)") || !strings.Contains(got, "week of "+charm.WeekOf(later)) {
+		t.Errorf("expected the missed weeks rebuilt and a new snapshot, got %q", got)
+	}
+	snapshots, err := client.ListPipelineSnapshots()
+	if err != nil {
+		t.Fatalf("ListPipelineSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 4 {
+		t.Errorf("expected 4 consecutive weekly snapshots, got %d", len(snapshots))
+	}
+}
+
+func TestBackgroundJobsStageTimers(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	now := time.Now()
+	deal := &charm.Deal{Title: "Pilot", Stage: charm.StageProposal, StageHistory: []charm.StageChange{
+		{Stage: charm.StageProposal, At: now.AddDate(0, 0, -20)}}}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	client.Config().StageTimers = map[string]charm.StageTimer{
+		charm.StageProposal: {Days: 14, Actions: []string{charm.StageTimerTask, charm.StageTimerFlag}},
+	}
+
+	RunBackgroundJobs(client, now, logf)
+	if got := logs(); !strings.Contains(got, "Stage timer: Pilot has been in proposal for 20 days") {
+		t.Errorf("expected the timer fired, got %q", got)
+	}
+	if got, _ := client.GetDeal(deal.ID); !got.HasTag(charm.DefaultStageTimerTag) {
+		t.Errorf("expected the deal flagged, got tags %v", got.Tags)
+	}
+
+	// The next run doesn't fire it again for the same stage entry
+	RunBackgroundJobs(client, now.Add(time.Hour), logf)
+	if got := logs(); strings.Contains(got, "Stage timer") {
+		t.Errorf("expected no second firing, got %q", got)
+	}
+	if tasks, _ := client.ListTasks(false); len(tasks) != 1 {
+		t.Errorf("expected one task, got %d", len(tasks))
+	}
+}
+
+func TestBackgroundJobsMaintenance(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	maintained := 0
+	original := maintainDatabase
+	t.Cleanup(func() { maintainDatabase = original })
+	maintainDatabase = func(*charm.Client) (*db.MaintenanceReport, error) {
+		maintained++
+		return &db.MaintenanceReport{IntegrityOK: true}, nil
+	}
+	importLogs := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := client.CreateSyncLog(&charm.SyncLog{SourceService: "contacts", SourceID: fmt.Sprintf("c%d", i), EntityType: charm.EntityContact}); err != nil {
+				t.Fatalf("CreateSyncLog failed: %v", err)
+			}
+		}
+	}
+
+	// Off by default
+	importLogs(10)
+	RunBackgroundJobs(client, time.Now(), logf)
+	if maintained != 0 {
+		t.Fatalf("expected no maintenance without maintain_after, got %d runs", maintained)
+	}
+
+	// The first run only records the count; small imports don't trigger it
+	client.Config().MaintainAfter = 5
+	RunBackgroundJobs(client, time.Now(), logf)
+	importLogs(3)
+	RunBackgroundJobs(client, time.Now(), logf)
+	if maintained != 0 {
+		t.Errorf("expected no maintenance before 5 imports, got %d runs", maintained)
+	}
+
+	importLogs(3)
+	RunBackgroundJobs(client, time.Now(), logf)
+	if maintained != 1 || !strings.Contains(logs(), "Imported 6 records, running database maintenance") {
+		t.Errorf("expected maintenance after 6 imports, got %d runs", maintained)
+	}
+	RunBackgroundJobs(client, time.Now(), logf)
+	if maintained != 1 {
+		t.Errorf("expected the count to restart after maintenance, got %d runs", maintained)
+	}
+}
+
+func TestBackgroundJobsSyncWatchdog(t *testing.T) {
+	client, logf, logs := backgroundJobsClient(t)
+	now := time.Now()
+	data, err := json.Marshal(&charm.SyncState{Service: "gmail", Status: charm.SyncStatusSyncing, UpdatedAt: now.Add(-3 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Set(charm.SyncStateKey("gmail"), data); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	RunBackgroundJobs(client, now, logf)
+	if got := logs(); !strings.Contains(got, "Reset gmail sync, stuck syncing since") {
+		t.Errorf("expected the stuck sync reset, got %q", got)
+	}
+	if state, _ := client.GetSyncState("gmail"); state == nil || state.Status != charm.SyncStatusError {
+		t.Errorf("expected gmail reset to error, got %+v", state)
+	}
+}
@@ -1,35 +1,46 @@
 // ABOUTME: Company CLI commands
-// ABOUTME: Human-friendly commands for managing companies
+// ABOUTME: Human-friendly commands for managing companies and importing company data
 package cli
 
 import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
-	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
 )
 
 // AddCompanyCommand adds a new company.
 func AddCompanyCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("add-company", flag.ExitOnError)
+	fs := flag.NewFlagSet("add-company", flagErrorHandling)
 	name := fs.String("name", "", "Company name (required)")
 	domain := fs.String("domain", "", "Company domain (e.g., acme.com)")
 	industry := fs.String("industry", "", "Industry")
 	notes := fs.String("notes", "", "Notes about the company")
+	employees := fs.String("employees", "", "Employee count or range (e.g., 42 or 11-50)")
+	fundingStage := fs.String("funding-stage", "", "Funding stage (e.g., seed, series_a)")
+	hq := fs.String("hq", "", "Headquarters location")
+	tags := fs.String("tag", "", "Comma-separated tags (e.g., ai,portfolio)")
 	_ = fs.Parse(args)
 
 	if *name == "" {
-		return fmt.Errorf("--name is required")
+		return charm.Validationf("--name is required")
 	}
 
 	company := &charm.Company{
-		Name:     *name,
-		Domain:   *domain,
-		Industry: *industry,
-		Notes:    *notes,
+		Name:         *name,
+		Domain:       *domain,
+		Industry:     *industry,
+		Notes:        *notes,
+		FundingStage: charm.NormalizeFundingStage(*fundingStage),
+		HQLocation:   *hq,
+		Tags:         charm.ParseTags(*tags),
+	}
+	if err := company.SetEmployeeCount(*employees); err != nil {
+		return err
 	}
 
 	if err := client.CreateCompany(company); err != nil {
@@ -43,22 +54,30 @@ func AddCompanyCommand(client *charm.Client, args []string) error {
 	if company.Industry != "" {
 		fmt.Printf("  Industry: %s\n", company.Industry)
 	}
+	if employees := company.Employees(); employees != "" {
+		fmt.Printf("  Employees: %s\n", employees)
+	}
+	if company.FundingStage != "" {
+		fmt.Printf("  Funding: %s\n", charm.FundingStageLabel(company.FundingStage))
+	}
+	if company.HQLocation != "" {
+		fmt.Printf("  HQ: %s\n", company.HQLocation)
+	}
+	if len(company.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(company.Tags, ", "))
+	}
 
 	return nil
 }
 
 // ListCompaniesCommand lists all companies.
 func ListCompaniesCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("list-companies", flag.ExitOnError)
-	query := fs.String("query", "", "Search by name or domain")
+	fs := flag.NewFlagSet("list-companies", flagErrorHandling)
 	limit := fs.Int("limit", 50, "Maximum results")
+	filter := companyFilterFlags(fs)
 	_ = fs.Parse(args)
 
-	filter := &charm.CompanyFilter{
-		Query: *query,
-		Limit: *limit,
-	}
-
+	filter.Limit = *limit
 	companies, err := client.ListCompanies(filter)
 	if err != nil {
 		return fmt.Errorf("failed to find companies: %w", err)
@@ -69,47 +88,73 @@ func ListCompaniesCommand(client *charm.Client, args []string) error {
 		return nil
 	}
 
+	printCompanies(companies)
+	return nil
+}
+
+// companyFilterFlags registers the list-companies filter flags on fs. The
+// returned filter is filled in when fs is parsed.
+func companyFilterFlags(fs *flag.FlagSet) *charm.CompanyFilter {
+	filter := &charm.CompanyFilter{}
+	fs.StringVar(&filter.Query, "query", "", "Search by name or domain, with field terms like industry:saas employees:>50")
+	fs.StringVar(&filter.Industry, "industry", "", "Filter by industry")
+	fs.StringVar(&filter.FundingStage, "stage", "", "Filter by funding stage (e.g., seed, series_a)")
+	fs.IntVar(&filter.MinEmployees, "min-employees", 0, "Companies with at least this many employees")
+	fs.IntVar(&filter.MaxEmployees, "max-employees", 0, "Companies with at most this many employees")
+	fs.StringVar(&filter.HQ, "hq", "", "Filter by headquarters location (substring)")
+	fs.StringVar(&filter.Tag, "tag", "", "Only companies with this tag")
+	return filter
+}
+
+// printCompanies prints a company table with a total.
+func printCompanies(companies []*charm.Company) {
 	// Pretty print results
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tDOMAIN\tINDUSTRY\tID")
-	_, _ = fmt.Fprintln(w, "----\t------\t--------\t--")
+	_, _ = fmt.Fprintln(w, "NAME\tDOMAIN\tINDUSTRY\tEMPLOYEES\tFUNDING\tID")
+	_, _ = fmt.Fprintln(w, "----\t------\t--------\t---------\t-------\t--")
 
-	for _, company := range companies {
-		domain := company.Domain
-		if domain == "" {
-			domain = "-"
-		}
-		industry := company.Industry
-		if industry == "" {
-			industry = "-"
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
 		}
-
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			company.Name, domain, industry, company.ID.String()[:8])
+		return s
+	}
+	for _, company := range companies {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			company.Name, orDash(company.Domain), orDash(company.Industry), orDash(company.Employees()),
+			orDash(charm.FundingStageLabel(company.FundingStage)), charm.FormatID(company.ID))
 	}
 	_ = w.Flush()
 
 	fmt.Printf("\nTotal: %d company(ies)\n", len(companies))
-	return nil
 }
 
 // UpdateCompanyCommand updates an existing company.
 func UpdateCompanyCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("update-company", flag.ExitOnError)
+	fs := flag.NewFlagSet("update-company", flagErrorHandling)
 	name := fs.String("name", "", "Company name")
 	domain := fs.String("domain", "", "Domain")
 	industry := fs.String("industry", "", "Industry")
 	notes := fs.String("notes", "", "Notes")
+	employees := fs.String("employees", "", "Employee count or range (e.g., 42 or 11-50)")
+	fundingStage := fs.String("funding-stage", "", "Funding stage (e.g., seed, series_a)")
+	hq := fs.String("hq", "", "Headquarters location")
+	primary := fs.String("primary-contact", "", "Contact to reach at the company by default (ID, ID prefix, or name)")
+	noPrimary := fs.Bool("no-primary-contact", false, "Clear the primary contact")
 	_ = fs.Parse(args)
 
+	if *primary != "" && *noPrimary {
+		return fmt.Errorf("--primary-contact and --no-primary-contact can't be used together")
+	}
+
 	// First positional arg is the company ID
 	if len(fs.Args()) < 1 {
-		return fmt.Errorf("company ID is required")
+		return charm.Validationf("company ID is required")
 	}
 
-	companyID, err := uuid.Parse(fs.Args()[0])
+	companyID, err := resolveID(client, fs.Arg(0), charm.EntityCompany)
 	if err != nil {
-		return fmt.Errorf("invalid company ID: %w", err)
+		return err
 	}
 
 	// Get existing company
@@ -131,6 +176,27 @@ func UpdateCompanyCommand(client *charm.Client, args []string) error {
 	if *notes != "" {
 		existing.Notes = *notes
 	}
+	if *employees != "" {
+		if err := existing.SetEmployeeCount(*employees); err != nil {
+			return err
+		}
+	}
+	if *fundingStage != "" {
+		existing.FundingStage = charm.NormalizeFundingStage(*fundingStage)
+	}
+	if *hq != "" {
+		existing.HQLocation = *hq
+	}
+	if *primary != "" {
+		contact, err := findContact(client, *primary)
+		if err != nil {
+			return err
+		}
+		existing.SetPrimaryContact(contact)
+	}
+	if *noPrimary {
+		existing.SetPrimaryContact(nil)
+	}
 
 	err = client.UpdateCompany(existing)
 	if err != nil {
@@ -143,17 +209,17 @@ func UpdateCompanyCommand(client *charm.Client, args []string) error {
 
 // DeleteCompanyCommand deletes a company.
 func DeleteCompanyCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("delete-company", flag.ExitOnError)
+	fs := flag.NewFlagSet("delete-company", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// First positional arg is the company ID
 	if len(fs.Args()) < 1 {
-		return fmt.Errorf("company ID is required")
+		return charm.Validationf("company ID is required")
 	}
 
-	companyID, err := uuid.Parse(fs.Args()[0])
+	companyID, err := resolveID(client, fs.Arg(0), charm.EntityCompany)
 	if err != nil {
-		return fmt.Errorf("invalid company ID: %w", err)
+		return err
 	}
 
 	err = client.DeleteCompany(companyID)
@@ -164,3 +230,44 @@ func DeleteCompanyCommand(client *charm.Client, args []string) error {
 	fmt.Printf("✓ Company deleted: %s\n", companyID)
 	return nil
 }
+
+// ImportCompaniesCommand sets size, funding, and headquarters from a CSV of
+// company data keyed by domain: pagen crm import-companies --file orgs.csv
+func ImportCompaniesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("import-companies", flagErrorHandling)
+	file := fs.String("file", "", "CSV of company data with a domain or website column, e.g. a Crunchbase export (required)")
+	updateOnly := fs.Bool("update-only", false, "Only update existing companies; skip unknown domains")
+	dryRun := fs.Bool("dry-run", false, "Show what would change without writing")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return charm.Validationf("--file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open company file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := sync.ParseCompaniesCSV(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse company file: %w", err)
+	}
+
+	result, err := sync.ImportCompanyRecords(client, records, sync.CompanyImportOptions{UpdateOnly: *updateOnly, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	prefix := "✓"
+	if *dryRun {
+		prefix = "Dry run:"
+	}
+	fmt.Printf("%s %d rows: %d created, %d updated, %d unchanged, %d skipped\n",
+		prefix, len(records), result.Created, result.Updated, result.Unchanged, result.Skipped)
+	for _, problem := range result.Problems {
+		fmt.Printf("  ⚠ %s\n", problem)
+	}
+	return nil
+}
@@ -3,10 +3,12 @@
 package cli
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/harperreed/sweet/vault"
@@ -34,23 +36,61 @@ func queueContactToVault(client *charm.Client, contact *charm.Contact, op vault.
 
 // AddContactCommand adds a new contact.
 func AddContactCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("add-contact", flag.ExitOnError)
+	fs := flag.NewFlagSet("add-contact", flagErrorHandling)
 	name := fs.String("name", "", "Contact name (required)")
 	email := fs.String("email", "", "Email address")
 	phone := fs.String("phone", "", "Phone number")
+	title := fs.String("title", "", "Job title")
 	company := fs.String("company", "", "Company name")
 	notes := fs.String("notes", "", "Notes about the contact")
+	birthday := fs.String("birthday", "", "Birthday (YYYY-MM-DD, or MM-DD)")
+	workStart := fs.String("work-start", "", "Date they started at their company (YYYY-MM-DD)")
+	country := fs.String("country", "", "Country code for holiday greetings (e.g. US)")
+	metVia := fs.String("met-via", "", "How you met (e.g. Zoom, intro from Bob)")
+	metAt := fs.String("met-at", "", "Event or place you met")
+	metDate := fs.String("met-date", "", "Date you met (YYYY-MM-DD)")
+	tags := fs.String("tag", "", "Comma-separated tags (e.g., ai,investor)")
+	onDuplicate := fs.String("on-duplicate", "", "When the email is taken: allow, return, or reject (default: duplicate_emails config, or allow)")
 	_ = fs.Parse(args)
 
 	if *name == "" {
-		return fmt.Errorf("--name is required")
+		return charm.Validationf("--name is required")
+	}
+
+	// Check the email before anything is created, so a rejected contact
+	// doesn't leave a new company behind
+	policy := *onDuplicate
+	if policy == "" {
+		policy = client.DuplicateEmailPolicy(charm.EntryCLI)
+	}
+	existing, err := client.CheckDuplicateEmail(*email, policy)
+	if err != nil {
+		var dup *charm.DuplicateEmailError
+		if errors.As(err, &dup) {
+			return fmt.Errorf("%w\n  Change it with 'pagen crm update-contact %s', or pass --on-duplicate allow to add another contact with this email",
+				err, charm.FormatID(dup.Existing.ID))
+		}
+		return err
+	}
+	if existing != nil {
+		fmt.Printf("✓ Contact already exists: %s (ID: %s)\n", existing.Name, existing.ID)
+		fmt.Printf("  Email: %s\n", existing.Email)
+		return nil
 	}
 
 	contact := &charm.Contact{
 		Name:  *name,
 		Email: *email,
 		Phone: *phone,
+		Title: *title,
 		Notes: *notes,
+		Tags:  charm.ParseTags(*tags),
+	}
+	if err := contact.SetGreetingFields(*birthday, *workStart, *country); err != nil {
+		return err
+	}
+	if err := contact.SetMetFields(*metVia, *metAt, *metDate); err != nil {
+		return err
 	}
 
 	// Handle company association
@@ -91,16 +131,23 @@ func AddContactCommand(client *charm.Client, args []string) error {
 	if *company != "" {
 		fmt.Printf("  Company: %s\n", *company)
 	}
+	if len(contact.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(contact.Tags, ", "))
+	}
 
 	return nil
 }
 
 // ListContactsCommand lists all contacts.
 func ListContactsCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("list-contacts", flag.ExitOnError)
-	query := fs.String("query", "", "Search by name or email")
+	fs := flag.NewFlagSet("list-contacts", flagErrorHandling)
+	query := fs.String("query", "", "Search by name or email, with field terms like tag:vip last_contacted:<90d")
 	company := fs.String("company", "", "Filter by company name")
 	limit := fs.Int("limit", 50, "Maximum results")
+	archived := fs.Bool("archived", false, "Include archived contacts")
+	metIn := fs.String("met-in", "", "Only contacts met in this year, month, or day (YYYY, YYYY-MM, or YYYY-MM-DD)")
+	city := fs.String("city", "", "Only contacts met in person in this city")
+	tag := fs.String("tag", "", "Only contacts with this tag")
 	_ = fs.Parse(args)
 
 	var companyIDPtr *uuid.UUID
@@ -114,11 +161,24 @@ func ListContactsCommand(client *charm.Client, args []string) error {
 		}
 	}
 
-	contacts, err := client.ListContacts(&charm.ContactFilter{
+	filter := &charm.ContactFilter{
 		Query:     *query,
 		CompanyID: companyIDPtr,
 		Limit:     *limit,
-	})
+
+		IncludeArchived: *archived,
+		City:            *city,
+		Tag:             *tag,
+	}
+	if *metIn != "" {
+		from, to, err := charm.ParseMetPeriod(*metIn)
+		if err != nil {
+			return err
+		}
+		filter.MetFrom, filter.MetTo = &from, &to
+	}
+
+	contacts, err := client.ListContacts(filter)
 	if err != nil {
 		return fmt.Errorf("failed to find contacts: %w", err)
 	}
@@ -128,6 +188,12 @@ func ListContactsCommand(client *charm.Client, args []string) error {
 		return nil
 	}
 
+	printContacts(contacts)
+	return nil
+}
+
+// printContacts prints a contact table with a total.
+func printContacts(contacts []*charm.Contact) {
 	// Pretty print results
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	_, _ = fmt.Fprintln(w, "NAME\tEMAIL\tPHONE\tCOMPANY\tID")
@@ -148,33 +214,44 @@ func ListContactsCommand(client *charm.Client, args []string) error {
 			companyName = contact.CompanyName
 		}
 
+		name := contact.Name
+		if contact.ArchivedAt != nil {
+			name += " (archived)"
+		}
+
 		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			contact.Name, email, phone, companyName, contact.ID.String()[:8])
+			name, email, phone, companyName, charm.FormatID(contact.ID))
 	}
 	_ = w.Flush()
 
 	fmt.Printf("\nTotal: %d contact(s)\n", len(contacts))
-	return nil
 }
 
 // UpdateContactCommand updates an existing contact.
 func UpdateContactCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("update-contact", flag.ExitOnError)
+	fs := flag.NewFlagSet("update-contact", flagErrorHandling)
 	name := fs.String("name", "", "Contact name")
 	email := fs.String("email", "", "Email address")
 	phone := fs.String("phone", "", "Phone number")
-	company := fs.String("company", "", "Company name")
+	title := fs.String("title", "", "Job title")
+	company := fs.String("company", "", "Company name or ID")
 	notes := fs.String("notes", "", "Notes about the contact")
+	birthday := fs.String("birthday", "", "Birthday (YYYY-MM-DD, or MM-DD)")
+	workStart := fs.String("work-start", "", "Date they started at their company (YYYY-MM-DD)")
+	country := fs.String("country", "", "Country code for holiday greetings (e.g. US)")
+	metVia := fs.String("met-via", "", "How you met (e.g. Zoom, intro from Bob)")
+	metAt := fs.String("met-at", "", "Event or place you met")
+	metDate := fs.String("met-date", "", "Date you met (YYYY-MM-DD)")
 	_ = fs.Parse(args)
 
 	// First positional arg is the contact ID
 	if len(fs.Args()) < 1 {
-		return fmt.Errorf("contact ID is required")
+		return charm.Validationf("contact ID is required")
 	}
 
-	contactID, err := uuid.Parse(fs.Args()[0])
+	contactID, err := resolveID(client, fs.Arg(0), charm.EntityContact)
 	if err != nil {
-		return fmt.Errorf("invalid contact ID: %w", err)
+		return err
 	}
 
 	// Get existing contact
@@ -193,17 +270,23 @@ func UpdateContactCommand(client *charm.Client, args []string) error {
 	if *phone != "" {
 		existing.Phone = *phone
 	}
+	if *title != "" {
+		existing.Title = *title
+	}
 	if *notes != "" {
 		existing.Notes = *notes
 	}
+	if err := existing.SetGreetingFields(*birthday, *workStart, *country); err != nil {
+		return err
+	}
+	if err := existing.SetMetFields(*metVia, *metAt, *metDate); err != nil {
+		return err
+	}
 
 	if *company != "" {
-		existingCompany, err := client.FindCompanyByName(*company)
+		existingCompany, err := resolveCompany(client, *company)
 		if err != nil {
-			return fmt.Errorf("failed to lookup company: %w", err)
-		}
-		if existingCompany == nil {
-			return fmt.Errorf("company not found: %s", *company)
+			return err
 		}
 		existing.CompanyID = &existingCompany.ID
 		existing.CompanyName = existingCompany.Name
@@ -223,17 +306,17 @@ func UpdateContactCommand(client *charm.Client, args []string) error {
 
 // DeleteContactCommand deletes a contact.
 func DeleteContactCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("delete-contact", flag.ExitOnError)
+	fs := flag.NewFlagSet("delete-contact", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// First positional arg is the contact ID
 	if len(fs.Args()) < 1 {
-		return fmt.Errorf("contact ID is required")
+		return charm.Validationf("contact ID is required")
 	}
 
-	contactID, err := uuid.Parse(fs.Args()[0])
+	contactID, err := resolveID(client, fs.Arg(0), charm.EntityContact)
 	if err != nil {
-		return fmt.Errorf("invalid contact ID: %w", err)
+		return err
 	}
 
 	// Get contact before deletion for vault sync
@@ -253,3 +336,17 @@ func DeleteContactCommand(client *charm.Client, args []string) error {
 	fmt.Printf("✓ Contact deleted: %s\n", contactID)
 	return nil
 }
+
+// FillMetCommand backfills how we met from each contact's first imported interaction.
+func FillMetCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("fill-met", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	filled, err := client.FillMetFromImports()
+	if err != nil {
+		return fmt.Errorf("failed to fill how we met: %w", err)
+	}
+
+	fmt.Printf("✓ Filled in how we met for %d contact(s)\n", filled)
+	return nil
+}
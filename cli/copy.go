@@ -0,0 +1,187 @@
+// ABOUTME: Contact list copy CLI command
+// ABOUTME: Puts matching contacts on the clipboard as CSV or a Markdown table for pasting into emails and docs
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/harperreed/pagen/charm"
+)
+
+// copyFields maps the field names accepted by --fields to a contact's value.
+var copyFields = map[string]func(*charm.Contact) string{
+	"id":      func(c *charm.Contact) string { return c.ID.String() },
+	"name":    func(c *charm.Contact) string { return c.Name },
+	"email":   func(c *charm.Contact) string { return c.Email },
+	"phone":   func(c *charm.Contact) string { return c.Phone },
+	"title":   func(c *charm.Contact) string { return c.Title },
+	"company": func(c *charm.Contact) string { return c.CompanyName },
+	"country": func(c *charm.Contact) string { return c.Country },
+	"met_at":  func(c *charm.Contact) string { return c.MetAt },
+}
+
+// copyFieldOrder lists the fields in help text order.
+var copyFieldOrder = []string{"id", "name", "email", "phone", "title", "company", "country", "met_at"}
+
+// CopyContactsCommand copies matching contacts to the clipboard:
+// pagen crm copy --query acme --fields name,email --format markdown
+func CopyContactsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("copy", flagErrorHandling)
+	query := fs.String("query", "", "Search by name or email")
+	company := fs.String("company", "", "Filter by company name")
+	event := fs.String("event", "", "Only contacts who attended this event (name or ID)")
+	fields := fs.String("fields", "name,email", "Comma-separated fields: "+strings.Join(copyFieldOrder, ", "))
+	format := fs.String("format", "csv", "Output format: csv or markdown")
+	noHeader := fs.Bool("no-header", false, "Leave out the header row (csv only)")
+	limit := fs.Int("limit", 0, "Maximum contacts (default: all)")
+	printOnly := fs.Bool("print", false, "Print instead of copying to the clipboard")
+	_ = fs.Parse(args)
+
+	columns, err := parseCopyFields(*fields)
+	if err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "markdown" {
+		return charm.Validationf("invalid format: %s (use csv or markdown)", *format)
+	}
+
+	filter := &charm.ContactFilter{Query: *query}
+	if *company != "" {
+		existing, err := client.FindCompanyByName(*company)
+		if err != nil {
+			return fmt.Errorf("failed to lookup company: %w", err)
+		}
+		if existing == nil {
+			return charm.NotFoundf("company not found: %s", *company)
+		}
+		filter.CompanyID = &existing.ID
+	}
+
+	contacts, err := client.ListContacts(filter)
+	if err != nil {
+		return fmt.Errorf("failed to find contacts: %w", err)
+	}
+
+	if *event != "" {
+		found, err := findEvent(client, *event)
+		if err != nil {
+			return err
+		}
+		attendees, err := client.ListEventAttendees(found.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list attendees: %w", err)
+		}
+		attended := make(map[string]bool)
+		for _, attendee := range attendees {
+			attended[attendee.ContactID.String()] = true
+		}
+		var matched []*charm.Contact
+		for _, contact := range contacts {
+			if attended[contact.ID.String()] {
+				matched = append(matched, contact)
+			}
+		}
+		contacts = matched
+	}
+
+	if *limit > 0 && len(contacts) > *limit {
+		contacts = contacts[:*limit]
+	}
+	if len(contacts) == 0 {
+		fmt.Println("No contacts found")
+		return nil
+	}
+
+	var text string
+	if *format == "markdown" {
+		text = formatContactsMarkdown(contacts, columns)
+	} else {
+		text, err = formatContactsCSV(contacts, columns, !*noHeader)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !*printOnly {
+		err := clipboard.WriteAll(text)
+		if err == nil {
+			fmt.Printf("✓ Copied %d contact(s) as %s\n", len(contacts), *format)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "⚠ Clipboard unavailable (%v), printing instead\n", err)
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// parseCopyFields validates a comma-separated field list.
+func parseCopyFields(value string) ([]string, error) {
+	var columns []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := copyFields[field]; !ok {
+			return nil, fmt.Errorf("unknown field: %s (use %s)", field, strings.Join(copyFieldOrder, ", "))
+		}
+		columns = append(columns, field)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("--fields needs at least one field")
+	}
+	return columns, nil
+}
+
+// formatContactsCSV writes contacts as CSV rows, optionally after a header row.
+func formatContactsCSV(contacts []*charm.Contact, columns []string, header bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if header {
+		_ = w.Write(columns)
+	}
+	for _, contact := range contacts {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = copyFields[column](contact)
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// formatContactsMarkdown writes contacts as a Markdown table.
+func formatContactsMarkdown(contacts []*charm.Contact, columns []string) string {
+	cell := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.Join(strings.Fields(s), " ")
+	}
+
+	var out strings.Builder
+	headers := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column[:1]) + strings.ReplaceAll(column[1:], "_", " ")
+		separators[i] = "---"
+	}
+	fmt.Fprintf(&out, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(&out, "| %s |\n", strings.Join(separators, " | "))
+	for _, contact := range contacts {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = cell(copyFields[column](contact))
+		}
+		fmt.Fprintf(&out, "| %s |\n", strings.Join(row, " | "))
+	}
+	return out.String()
+}
@@ -0,0 +1,50 @@
+// ABOUTME: Tests for the contact list copy command
+// ABOUTME: Validates field parsing and the CSV and Markdown table output
+package cli
+
+import (
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestParseCopyFields(t *testing.T) {
+	columns, err := parseCopyFields(" Name, email ,,company")
+	if err != nil {
+		t.Fatalf("parseCopyFields failed: %v", err)
+	}
+	if len(columns) != 3 || columns[0] != "name" || columns[2] != "company" {
+		t.Errorf("unexpected columns: %v", columns)
+	}
+	if _, err := parseCopyFields("name,birthday"); err == nil {
+		t.Error("expected unknown field to fail")
+	}
+	if _, err := parseCopyFields(" , "); err == nil {
+		t.Error("expected empty field list to fail")
+	}
+}
+
+func TestFormatContacts(t *testing.T) {
+	contacts := []*charm.Contact{
+		{Name: "Ada Lovelace", Email: "ada@example.com", CompanyName: "Engines, Ltd"},
+		{Name: "Grace | Hopper", Email: "grace@example.com", MetAt: "Navy\nyard"},
+	}
+
+	csv, err := formatContactsCSV(contacts, []string{"name", "company"}, true)
+	if err != nil {
+		t.Fatalf("formatContactsCSV failed: %v", err)
+	}
+	want := "name,company\nAda Lovelace,\"Engines, Ltd\"\nGrace | Hopper,\n"
+	if csv != want {
+		t.Errorf("unexpected CSV:\n%q\nwant\n%q", csv, want)
+	}
+	if csv, _ := formatContactsCSV(contacts[:1], []string{"email"}, false); csv != "ada@example.com\n" {
+		t.Errorf("expected no header row, got %q", csv)
+	}
+
+	markdown := formatContactsMarkdown(contacts, []string{"name", "met_at"})
+	want = "| Name | Met at |\n| --- | --- |\n| Ada Lovelace |  |\n| Grace \\| Hopper | Navy yard |\n"
+	if markdown != want {
+		t.Errorf("unexpected Markdown:\n%q\nwant\n%q", markdown, want)
+	}
+}
@@ -0,0 +1,171 @@
+// ABOUTME: Deal contact role CLI commands
+// ABOUTME: Adds, removes, and lists contacts on a deal with roles like champion or decision maker
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// DealAddContactCommand adds a contact to a deal with a role.
+func DealAddContactCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("add-contact", flagErrorHandling)
+	dealID := fs.String("deal", "", "Deal ID (required)")
+	contactRef := fs.String("contact", "", "Contact ID or name (required)")
+	role := fs.String("role", "", "Role: "+strings.Join(charm.DealRoles, ", ")+" (required)")
+	_ = fs.Parse(args)
+
+	if *dealID == "" || *contactRef == "" || *role == "" {
+		return charm.Validationf("--deal, --contact, and --role are required")
+	}
+
+	deal, contact, err := findDealAndContact(client, *dealID, *contactRef)
+	if err != nil {
+		return err
+	}
+
+	dc := &charm.DealContact{
+		DealID:      deal.ID,
+		DealTitle:   deal.Title,
+		ContactID:   contact.ID,
+		ContactName: contact.Name,
+		Role:        *role,
+	}
+	if err := client.SaveDealContact(dc); err != nil {
+		return fmt.Errorf("failed to add contact to deal: %w", err)
+	}
+
+	fmt.Printf("✓ %s is %s on %s\n", contact.Name, charm.DealRoleLabel(dc.Role), deal.Title)
+	return nil
+}
+
+// DealRemoveContactCommand removes a contact from a deal.
+func DealRemoveContactCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("remove-contact", flagErrorHandling)
+	dealID := fs.String("deal", "", "Deal ID (required)")
+	contactRef := fs.String("contact", "", "Contact ID or name (required)")
+	_ = fs.Parse(args)
+
+	if *dealID == "" || *contactRef == "" {
+		return charm.Validationf("--deal and --contact are required")
+	}
+
+	deal, contact, err := findDealAndContact(client, *dealID, *contactRef)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.GetDealContact(deal.ID, contact.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get deal contact: %w", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("%s is not on deal %s", contact.Name, deal.Title)
+	}
+
+	if err := client.DeleteDealContact(deal.ID, contact.ID); err != nil {
+		return fmt.Errorf("failed to remove contact from deal: %w", err)
+	}
+
+	fmt.Printf("✓ Removed %s from %s\n", contact.Name, deal.Title)
+	return nil
+}
+
+// DealContactsCommand lists the contacts and roles on a deal.
+func DealContactsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("contacts", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) != 1 {
+		return fmt.Errorf("usage: deal contacts <deal-id>")
+	}
+
+	id, err := resolveID(client, fs.Arg(0), charm.EntityDeal)
+	if err != nil {
+		return err
+	}
+	deal, err := client.GetDeal(id)
+	if err != nil {
+		return fmt.Errorf("deal not found: %w", err)
+	}
+
+	contacts, err := client.ListDealContacts(deal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list deal contacts: %w", err)
+	}
+
+	fmt.Printf("%s\n\n", deal.Title)
+	if len(contacts) == 0 {
+		fmt.Println("No contacts on this deal")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ROLE\tCONTACT\tID")
+	_, _ = fmt.Fprintln(w, "----\t-------\t--")
+	for _, dc := range contacts {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", charm.DealRoleLabel(dc.Role), dc.ContactName, dc.ContactID)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// findDealAndContact resolves a deal ID and a contact ID or name.
+func findDealAndContact(client *charm.Client, dealRef, contactRef string) (*charm.Deal, *charm.Contact, error) {
+	id, err := resolveID(client, dealRef, charm.EntityDeal)
+	if err != nil {
+		return nil, nil, err
+	}
+	deal, err := client.GetDeal(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deal not found: %w", err)
+	}
+
+	contact, err := findContact(client, contactRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deal, contact, nil
+}
+
+// findContact resolves a contact ID, ID prefix, or name (see resolveID).
+func findContact(client *charm.Client, ref string) (*charm.Contact, error) {
+	contactID, err := resolveID(client, ref, charm.EntityContact)
+	if err != nil {
+		return nil, err
+	}
+	contact, err := client.GetContact(contactID)
+	if err != nil {
+		return nil, fmt.Errorf("contact not found: %w", err)
+	}
+	return contact, nil
+}
+
+// resolveContactStrict resolves a contact ID, ID prefix, @name, or a name
+// matching exactly one contact. Other names return a
+// *charm.AmbiguousMatchError listing the candidates; nil means nothing matched.
+func resolveContactStrict(client *charm.Client, ref string) (*charm.Contact, error) {
+	contactID, ok, err := refID(client, ref, charm.EntityContact)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		contact, err := client.GetContact(contactID)
+		if err != nil {
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		return contact, nil
+	}
+	return client.ResolveContactName(ref, true)
+}
+
+// strictResolution returns the configured default for --strict.
+func strictResolution(client *charm.Client) bool {
+	cfg := client.Config()
+	return cfg != nil && cfg.StrictResolution
+}
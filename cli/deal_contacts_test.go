@@ -0,0 +1,37 @@
+// ABOUTME: Tests for contact lookups shared by CLI commands
+// ABOUTME: Validates ID prefixes, @name references, and hex-looking names falling back to name search
+package cli
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestFindContactRefs(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	abbe := &charm.Contact{ID: uuid.MustParse("11112222-0000-4000-8000-000000000001"), Name: "Abbe"}
+	if err := client.CreateContact(abbe); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	for _, ref := range []string{abbe.ID.String(), "11112222", "@abbe", "Abbe"} {
+		contact, err := findContact(client, ref)
+		if err != nil {
+			t.Errorf("findContact(%q) failed: %v", ref, err)
+			continue
+		}
+		if contact.ID != abbe.ID {
+			t.Errorf("findContact(%q) found %s", ref, contact.Name)
+		}
+	}
+
+	if _, err := findContact(client, "@nobody"); err == nil {
+		t.Error("expected an unknown @name to fail")
+	}
+	if _, err := resolveID(client, "2222", charm.EntityContact); err == nil {
+		t.Error("expected an unmatched prefix to fail")
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
@@ -14,7 +16,7 @@ import (
 
 // AddDealCommand adds a new deal.
 func AddDealCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("add-deal", flag.ExitOnError)
+	fs := flag.NewFlagSet("add-deal", flagErrorHandling)
 	title := fs.String("title", "", "Deal title (required)")
 	company := fs.String("company", "", "Company name (required)")
 	contact := fs.String("contact", "", "Contact name (optional)")
@@ -22,19 +24,77 @@ func AddDealCommand(client *charm.Client, args []string) error {
 	currency := fs.String("currency", "USD", "Currency code")
 	stage := fs.String("stage", "prospecting", "Stage (prospecting, qualification, proposal, negotiation, closed_won, closed_lost)")
 	notes := fs.String("notes", "", "Initial notes")
+	closeDate := fs.String("close-date", "", "Expected close date (YYYY-MM-DD)")
+	closeReason := fs.String("close-reason", "", "Why the deal was won or lost")
+	source := fs.String("source", "", "Deal source ("+strings.Join(charm.DealSources, ", ")+")")
+	referrer := fs.String("referrer", "", "Contact who referred the deal (name or ID)")
+	probability := fs.Int("probability", -1, "Win probability override, 0-100 (default: stage probability)")
+	strict := fs.Bool("strict", strictResolution(client), "Fail with the candidates instead of guessing when a company or contact name is ambiguous")
+	tags := fs.String("tag", "", "Comma-separated tags (e.g., enterprise,q3)")
 	_ = fs.Parse(args)
 
 	if *title == "" {
-		return fmt.Errorf("--title is required")
+		return charm.Validationf("--title is required")
 	}
 	if *company == "" {
-		return fmt.Errorf("--company is required")
+		return charm.Validationf("--company is required")
+	}
+
+	var expectedClose *time.Time
+	if *closeDate != "" {
+		parsed, err := time.Parse("2006-01-02", *closeDate)
+		if err != nil {
+			return charm.Validationf("invalid --close-date (use YYYY-MM-DD): %w", err)
+		}
+		expectedClose = &parsed
+	}
+
+	if *probability > 100 {
+		return charm.Validationf("invalid --probability: %d (must be 0-100)", *probability)
+	}
+
+	if *source != "" && !charm.IsValidDealSource(*source) {
+		return charm.Validationf("invalid --source: %s (valid: %s)", *source, strings.Join(charm.DealSources, ", "))
+	}
+
+	var referrerContact *charm.Contact
+	if *referrer != "" {
+		var err error
+		if *strict {
+			referrerContact, err = resolveContactStrict(client, *referrer)
+			if err == nil && referrerContact == nil {
+				err = fmt.Errorf("no contact found matching: %s", *referrer)
+			}
+		} else {
+			referrerContact, err = findContact(client, *referrer)
+		}
+		if err != nil {
+			return fmt.Errorf("referrer: %w", err)
+		}
+	}
+
+	// Check stage rules before creating any company or contact
+	candidate := &charm.Deal{Stage: *stage, Amount: *amount, ExpectedCloseDate: expectedClose, CloseReason: *closeReason}
+	if *contact != "" {
+		candidate.ContactID = &uuid.Nil
+	}
+	if err := charm.CheckStageRequirements(candidate, client.StageRequirements()); err != nil {
+		return err
 	}
 
 	// Find or create company
-	existingCompany, err := client.FindCompanyByName(*company)
+	var existingCompany *charm.Company
+	var err error
+	companyRefID, isRef, err := refID(client, *company, charm.EntityCompany)
 	if err != nil {
-		return fmt.Errorf("failed to lookup company: %w", err)
+		return err
+	}
+	if isRef {
+		if existingCompany, err = client.GetCompany(companyRefID); err != nil {
+			return fmt.Errorf("company not found: %w", err)
+		}
+	} else if existingCompany, err = client.ResolveCompanyName(*company, *strict); err != nil {
+		return err
 	}
 
 	var companyUUID uuid.UUID
@@ -55,12 +115,17 @@ func AddDealCommand(client *charm.Client, args []string) error {
 	var contactUUID *uuid.UUID
 	var contactName string
 	if *contact != "" {
-		contacts, err := client.ListContacts(&charm.ContactFilter{Query: *contact, Limit: 1})
+		var existingContact *charm.Contact
+		if *strict {
+			existingContact, err = resolveContactStrict(client, *contact)
+		} else {
+			existingContact, err = client.ResolveContactName(*contact, false)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to lookup contact: %w", err)
+			return err
 		}
 
-		if len(contacts) == 0 {
+		if existingContact == nil {
 			// Create contact
 			newContact := &charm.Contact{Name: *contact, CompanyID: &companyUUID, CompanyName: companyName}
 			if err := client.CreateContact(newContact); err != nil {
@@ -69,8 +134,8 @@ func AddDealCommand(client *charm.Client, args []string) error {
 			contactUUID = &newContact.ID
 			contactName = newContact.Name
 		} else {
-			contactUUID = &contacts[0].ID
-			contactName = contacts[0].Name
+			contactUUID = &existingContact.ID
+			contactName = existingContact.Name
 		}
 	}
 
@@ -83,6 +148,18 @@ func AddDealCommand(client *charm.Client, args []string) error {
 		CompanyName: companyName,
 		ContactID:   contactUUID,
 		ContactName: contactName,
+
+		ExpectedCloseDate: expectedClose,
+		CloseReason:       *closeReason,
+		Source:            *source,
+		Tags:              charm.ParseTags(*tags),
+	}
+	if referrerContact != nil {
+		deal.ReferrerID = &referrerContact.ID
+		deal.ReferrerName = referrerContact.Name
+	}
+	if *probability >= 0 {
+		deal.Probability = probability
 	}
 
 	if err := client.CreateDeal(deal); err != nil {
@@ -93,11 +170,20 @@ func AddDealCommand(client *charm.Client, args []string) error {
 
 	fmt.Printf("✓ Deal created: %s (ID: %s)\n", deal.Title, deal.ID)
 	fmt.Printf("  Company: %s\n", companyName)
-	fmt.Printf("  Amount: $%.2f %s\n", float64(deal.Amount)/100.0, deal.Currency)
-	fmt.Printf("  Stage: %s\n", deal.Stage)
+	fmt.Printf("  Amount: %s\n", charm.FormatMoney(deal.Amount, deal.Currency))
+	fmt.Printf("  Stage: %s (%d%%, expected %s)\n", deal.Stage, deal.WinProbability(), charm.FormatMoney(deal.ExpectedValue(), deal.Currency))
 	if contactName != "" {
 		fmt.Printf("  Contact: %s\n", contactName)
 	}
+	if deal.Source != "" {
+		fmt.Printf("  Source: %s\n", deal.Source)
+	}
+	if deal.ReferrerName != "" {
+		fmt.Printf("  Referred by: %s\n", deal.ReferrerName)
+	}
+	if len(deal.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(deal.Tags, ", "))
+	}
 
 	// Add initial note if provided
 	if *notes != "" {
@@ -119,15 +205,27 @@ func AddDealCommand(client *charm.Client, args []string) error {
 
 // ListDealsCommand lists all deals.
 func ListDealsCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("list-deals", flag.ExitOnError)
+	fs := flag.NewFlagSet("list-deals", flagErrorHandling)
 	stage := fs.String("stage", "", "Filter by stage")
+	source := fs.String("source", "", "Filter by source")
 	company := fs.String("company", "", "Filter by company name")
 	limit := fs.Int("limit", 50, "Maximum results")
+	tag := fs.String("tag", "", "Only deals with this tag")
+	query := fs.String("query", "", "Search by title or company, with field terms like stage:negotiation amount:>10k")
+	withStats := fs.Bool("with-stats", false, "Show totals and expected value per stage")
+	groupBy := fs.String("group-by", "", "Group deals by company, quarter (expected close), or tag")
 	_ = fs.Parse(args)
 
+	if err := charm.ValidateDealGrouping(*groupBy); err != nil {
+		return err
+	}
+
 	filter := &charm.DealFilter{
-		Stage: *stage,
-		Limit: *limit,
+		Query:  *query,
+		Stage:  *stage,
+		Source: *source,
+		Tag:    *tag,
+		Limit:  *limit,
 	}
 
 	if *company != "" {
@@ -150,10 +248,38 @@ func ListDealsCommand(client *charm.Client, args []string) error {
 		return nil
 	}
 
+	if *groupBy != "" {
+		groups, err := charm.GroupDeals(deals, *groupBy)
+		if err != nil {
+			return err
+		}
+		for i, group := range groups {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s: %d deal(s) - %s\n\n", group.Label, len(group.Deals), charm.FormatMoney(group.Total, group.Currency))
+			printDealTable(group.Deals)
+		}
+		printDealTotals(deals, *withStats)
+		return nil
+	}
+
+	printDeals(deals, *withStats)
+	return nil
+}
+
+// printDeals prints a deal table with totals, and per-stage stats when asked.
+func printDeals(deals []*charm.Deal, withStats bool) {
+	printDealTable(deals)
+	printDealTotals(deals, withStats)
+}
+
+// printDealTable prints one row per deal.
+func printDealTable(deals []*charm.Deal) {
 	// Pretty print results
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "TITLE\tCOMPANY\tAMOUNT\tSTAGE\tID")
-	_, _ = fmt.Fprintln(w, "-----\t-------\t------\t-----\t--")
+	_, _ = fmt.Fprintln(w, "TITLE\tCOMPANY\tAMOUNT\tSTAGE\tPROB\tEXPECTED\tID")
+	_, _ = fmt.Fprintln(w, "-----\t-------\t------\t-----\t----\t--------\t--")
 
 	for _, deal := range deals {
 		companyName := deal.CompanyName
@@ -161,35 +287,83 @@ func ListDealsCommand(client *charm.Client, args []string) error {
 			companyName = "-"
 		}
 
-		amountStr := fmt.Sprintf("$%.2f", float64(deal.Amount)/100.0)
+		amountStr := charm.FormatMoney(deal.Amount, deal.Currency)
+		expectedStr := charm.FormatMoney(deal.ExpectedValue(), deal.Currency)
+
+		probStr := fmt.Sprintf("%d%%", deal.WinProbability())
+		if deal.Probability != nil {
+			probStr += "*"
+		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			deal.Title, companyName, amountStr, deal.Stage, deal.ID.String()[:8])
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			deal.Title, companyName, amountStr, deal.Stage, probStr, expectedStr, charm.FormatID(deal.ID))
 	}
 	_ = w.Flush()
+}
 
-	// Calculate total
-	var total int64
+// printDealTotals prints the amount and expected value of deals, and
+// per-stage stats when asked.
+func printDealTotals(deals []*charm.Deal, withStats bool) {
+	var total, expected int64
 	for _, deal := range deals {
 		total += deal.Amount
+		expected += deal.ExpectedValue()
 	}
 
-	fmt.Printf("\nTotal: %d deal(s) - $%.2f\n", len(deals), float64(total)/100.0)
-	return nil
+	fmt.Printf("\nTotal: %d deal(s) - %s (expected %s)\n", len(deals), charm.FormatMoney(total, ""), charm.FormatMoney(expected, ""))
+	if withStats {
+		printDealStageStats(deals)
+	}
+}
+
+// printDealStageStats prints count, amount, and expected value per stage.
+// Probabilities marked * in the list are per-deal overrides.
+func printDealStageStats(deals []*charm.Deal) {
+	type stageStats struct {
+		count    int
+		amount   int64
+		expected int64
+	}
+	byStage := make(map[string]*stageStats)
+	for _, deal := range deals {
+		stats, ok := byStage[deal.Stage]
+		if !ok {
+			stats = &stageStats{}
+			byStage[deal.Stage] = stats
+		}
+		stats.count++
+		stats.amount += deal.Amount
+		stats.expected += deal.ExpectedValue()
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "STAGE\tDEALS\tDEFAULT PROB\tAMOUNT\tEXPECTED")
+	_, _ = fmt.Fprintln(w, "-----\t-----\t------------\t------\t--------")
+	for _, stage := range charm.DealStages {
+		stats, ok := byStage[stage]
+		if !ok {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d%%\t%s\t%s\n",
+			stage, stats.count, charm.StageProbabilities[stage],
+			charm.FormatMoney(stats.amount, ""), charm.FormatMoney(stats.expected, ""))
+	}
+	_ = w.Flush()
 }
 
 // DeleteDealCommand deletes a deal.
 func DeleteDealCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("delete-deal", flag.ExitOnError)
+	fs := flag.NewFlagSet("delete-deal", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	if len(fs.Args()) != 1 {
 		return fmt.Errorf("usage: delete-deal <id>")
 	}
 
-	dealID, err := uuid.Parse(fs.Arg(0))
+	dealID, err := resolveID(client, fs.Arg(0), charm.EntityDeal)
 	if err != nil {
-		return fmt.Errorf("invalid deal ID: %w", err)
+		return err
 	}
 
 	// Get deal before deletion for vault sync
@@ -198,7 +372,7 @@ func DeleteDealCommand(client *charm.Client, args []string) error {
 		return fmt.Errorf("deal not found: %w", err)
 	}
 	if deal == nil {
-		return fmt.Errorf("deal not found: %s", dealID)
+		return charm.NotFoundf("deal not found: %s", dealID)
 	}
 
 	err = client.DeleteDeal(dealID)
@@ -211,3 +385,148 @@ func DeleteDealCommand(client *charm.Client, args []string) error {
 	fmt.Printf("✓ Deleted deal: %s\n", dealID)
 	return nil
 }
+
+// StageRequirementsCommand shows or sets the fields required per deal stage.
+func StageRequirementsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("stage-requirements", flagErrorHandling)
+	stage := fs.String("stage", "", "Stage to configure")
+	require := fs.String("require", "", "Comma-separated required fields (empty clears the stage)")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *stage != "" {
+		var fields []string
+		for _, field := range strings.Split(*require, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+
+		requirements := make(map[string][]string)
+		for s, f := range cfg.StageRequirements {
+			requirements[s] = f
+		}
+		if len(fields) == 0 {
+			delete(requirements, *stage)
+		} else {
+			requirements[*stage] = fields
+		}
+
+		if err := charm.ValidateStageRequirementsConfig(requirements); err != nil {
+			return err
+		}
+
+		cfg.StageRequirements = requirements
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		client.SetStageRequirements(requirements)
+
+		if len(fields) == 0 {
+			fmt.Printf("✓ Cleared required fields for %s\n", *stage)
+		} else {
+			fmt.Printf("✓ %s now requires: %s\n", *stage, strings.Join(fields, ", "))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "STAGE\tREQUIRED FIELDS")
+	_, _ = fmt.Fprintln(w, "-----\t---------------")
+	for _, s := range charm.DealStages {
+		fields := strings.Join(cfg.StageRequirements[s], ", ")
+		if fields == "" {
+			fields = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", s, fields)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nFields: %s\n", strings.Join(charm.RequirableDealFields(), ", "))
+	return nil
+}
+
+// StageTimersCommand shows or sets the timers that act on deals sitting in a stage too long.
+func StageTimersCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("stage-timers", flagErrorHandling)
+	stage := fs.String("stage", "", "Stage to configure")
+	days := fs.Int("days", 0, "Days in the stage before the timer fires (0 clears the stage)")
+	actions := fs.String("actions", "", "Comma-separated actions: task, notify, flag")
+	tag := fs.String("tag", "", "Tag the flag action adds (default: stalled)")
+	webhook := fs.String("webhook", "", "URL the notify action also posts to")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *stage != "" {
+		timers := make(map[string]charm.StageTimer)
+		for s, t := range cfg.StageTimers {
+			timers[s] = t
+		}
+		var timerActions []string
+		for _, action := range strings.Split(*actions, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				timerActions = append(timerActions, action)
+			}
+		}
+
+		if *days == 0 {
+			delete(timers, *stage)
+		} else {
+			timers[*stage] = charm.StageTimer{Days: *days, Actions: timerActions, Tag: *tag, Webhook: *webhook}
+		}
+
+		if err := charm.ValidateStageTimersConfig(timers); err != nil {
+			return err
+		}
+
+		cfg.StageTimers = timers
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		if *days == 0 {
+			fmt.Printf("✓ Cleared the timer for %s\n", *stage)
+		} else {
+			fmt.Printf("✓ %s deals get %s after %d days\n", *stage, strings.Join(timers[*stage].Actions, ", "), *days)
+			fmt.Println("Run 'pagen watch run' to check timers")
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "STAGE\tDAYS\tACTIONS")
+	_, _ = fmt.Fprintln(w, "-----\t----\t-------")
+	for _, s := range charm.DealStages {
+		timer, ok := cfg.StageTimers[s]
+		if !ok {
+			continue
+		}
+		var described []string
+		for _, action := range timer.Actions {
+			switch action {
+			case charm.StageTimerFlag:
+				action += " (" + timer.TagOrDefault() + ")"
+			case charm.StageTimerNotify:
+				if timer.Webhook != "" {
+					action += " (+ " + timer.Webhook + ")"
+				}
+			}
+			described = append(described, action)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", s, timer.Days, strings.Join(described, ", "))
+	}
+	_ = w.Flush()
+
+	if len(cfg.StageTimers) == 0 {
+		fmt.Println("\nNo stage timers. Set one with --stage, --days, and --actions")
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// ABOUTME: Debug CLI commands for bug reports
+// ABOUTME: Prints redacted environment diagnostics and writes them to a zip bundle
+package cli
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// debugEnvPrefixes are the environment variables included in diagnostics.
+var debugEnvPrefixes = []string{"PAGEN_", "CHARM_", "GOOGLE_", "XDG_"}
+
+// debugSecretMarkers flag environment variable names whose values are redacted.
+var debugSecretMarkers = []string{"SECRET", "TOKEN", "KEY", "PASSWORD", "CREDENTIAL"}
+
+// debugRecentSyncLogs is how many sync log entries go into a bundle.
+const debugRecentSyncLogs = 50
+
+const redacted = "[REDACTED]"
+
+// Diagnostics is a snapshot of the local installation for bug reports.
+type Diagnostics struct {
+	Version       string             `json:"version"`
+	GoVersion     string             `json:"go_version"`
+	Platform      string             `json:"platform"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	SchemaVersion int                `json:"schema_version"`
+	Profile       string             `json:"profile"`
+	Config        *charm.Config      `json:"config,omitempty"`
+	VaultConfig   *sync.VaultConfig  `json:"vault_config,omitempty"`
+	Env           map[string]string  `json:"env"`
+	Counts        map[string]int     `json:"counts,omitempty"`
+	LastCloudSync *time.Time         `json:"last_cloud_sync,omitempty"`
+	SyncStates    []*charm.SyncState `json:"sync_states,omitempty"`
+	SyncLogs      []*charm.SyncLog   `json:"-"` // written separately to sync_logs.json
+	Errors        []string           `json:"errors,omitempty"`
+}
+
+// CollectDiagnostics gathers diagnostics. Failures are recorded in Errors
+// rather than returned, since a broken install is exactly what a bug report
+// needs to describe. A nil client records only environment details.
+func CollectDiagnostics(client *charm.Client, version string) *Diagnostics {
+	d := &Diagnostics{
+		Version:       version,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		GeneratedAt:   time.Now(),
+		SchemaVersion: charm.SchemaVersion,
+		Profile:       charm.ActiveProfile(),
+		Env:           redactedEnv(os.Environ()),
+	}
+
+	if cfg, err := charm.LoadConfig(); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("config: %v", err))
+	} else {
+		d.Config = cfg
+	}
+
+	if vaultCfg, err := sync.LoadVaultConfig(); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("vault config: %v", err))
+	} else {
+		d.VaultConfig = redactVaultConfig(vaultCfg)
+	}
+
+	if client == nil {
+		d.Errors = append(d.Errors, "client: Charm KV client unavailable")
+		return d
+	}
+
+	d.Counts = make(map[string]int)
+	for name, prefix := range charm.EntityPrefixes {
+		keys, err := client.KeysWithPrefix([]byte(prefix))
+		if err != nil {
+			d.Errors = append(d.Errors, fmt.Sprintf("count %s: %v", name, err))
+			continue
+		}
+		d.Counts[name] = len(keys)
+	}
+
+	if last, err := client.LastSyncTime(); err == nil && !last.IsZero() {
+		d.LastCloudSync = &last
+	}
+
+	states, err := client.ListSyncStates()
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("sync states: %v", err))
+	}
+	d.SyncStates = states
+	for _, state := range states {
+		if state.ErrorMessage != "" {
+			d.Errors = append(d.Errors, fmt.Sprintf("sync %s: %s", state.Service, state.ErrorMessage))
+		}
+	}
+
+	logs, err := client.ListRecentSyncLogs(debugRecentSyncLogs)
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("sync logs: %v", err))
+	}
+	d.SyncLogs = logs
+
+	return d
+}
+
+// redactedEnv returns the relevant environment variables with secrets hidden.
+func redactedEnv(environ []string) map[string]string {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !hasAnyPrefix(name, debugEnvPrefixes) {
+			continue
+		}
+		if isSecretName(name) && value != "" {
+			value = redacted
+		}
+		env[name] = value
+	}
+	return env
+}
+
+// redactVaultConfig returns a copy of cfg with credentials hidden.
+func redactVaultConfig(cfg *sync.VaultConfig) *sync.VaultConfig {
+	out := *cfg
+	for _, field := range []*string{&out.Token, &out.RefreshToken, &out.DerivedKey} {
+		if *field != "" {
+			*field = redacted
+		}
+	}
+	return &out
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range debugSecretMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// DebugEnvCommand prints diagnostics to stdout.
+func DebugEnvCommand(client *charm.Client, version string, args []string) error {
+	fs := flag.NewFlagSet("env", flagErrorHandling)
+	asJSON := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(args)
+
+	d := CollectDiagnostics(client, version)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	}
+
+	return writeDiagnosticsText(os.Stdout, d)
+}
+
+// DebugBundleCommand writes diagnostics to a zip file for attaching to issues.
+func DebugBundleCommand(client *charm.Client, version string, args []string) error {
+	fs := flag.NewFlagSet("bundle", flagErrorHandling)
+	output := fs.String("output", "", "Output zip file (default: pagen-debug-<timestamp>.zip)")
+	_ = fs.Parse(args)
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("pagen-debug-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	d := CollectDiagnostics(client, version)
+	if err := WriteDebugBundle(f, d); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Debug bundle written to %s\n", path)
+	fmt.Println("  Secrets are redacted, but please review the contents before attaching it to an issue.")
+	return nil
+}
+
+// WriteDebugBundle writes diagnostics as a zip archive.
+func WriteDebugBundle(w io.Writer, d *Diagnostics) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"summary.txt", func(w io.Writer) error { return writeDiagnosticsText(w, d) }},
+		{"diagnostics.json", func(w io.Writer) error { return writeJSON(w, d) }},
+		{"sync_logs.json", func(w io.Writer) error { return writeJSON(w, d.SyncLogs) }},
+		{"errors.txt", func(w io.Writer) error {
+			for _, e := range d.Errors {
+				if _, err := fmt.Fprintln(w, e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", file.name, err)
+		}
+		if err := file.write(fw); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeDiagnosticsText writes a human-readable summary of diagnostics.
+func writeDiagnosticsText(w io.Writer, d *Diagnostics) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "pagen %s (%s, %s)\n", d.Version, d.GoVersion, d.Platform)
+	fmt.Fprintf(&b, "Generated: %s\n", d.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Schema version: %d\n", d.SchemaVersion)
+	fmt.Fprintf(&b, "Profile: %s\n", d.Profile)
+
+	if d.Config != nil {
+		fmt.Fprintf(&b, "\nCharm host: %s\n", d.Config.Host)
+		fmt.Fprintf(&b, "Auto-sync: %v\n", d.Config.AutoSync)
+		fmt.Fprintf(&b, "Stale threshold: %s\n", d.Config.StaleThreshold)
+	}
+	if d.LastCloudSync != nil {
+		fmt.Fprintf(&b, "Last cloud sync: %s\n", d.LastCloudSync.Format(time.RFC3339))
+	}
+
+	if len(d.Counts) > 0 {
+		b.WriteString("\nCounts:\n")
+		for _, name := range sortedKeys(d.Counts) {
+			fmt.Fprintf(&b, "  %-14s %d\n", name, d.Counts[name])
+		}
+	}
+
+	if len(d.SyncStates) > 0 {
+		b.WriteString("\nSync state:\n")
+		for _, state := range d.SyncStates {
+			last := "never"
+			if state.LastSyncTime != nil {
+				last = state.LastSyncTime.Format(time.RFC3339)
+			}
+			fmt.Fprintf(&b, "  %-14s %-8s last: %s\n", state.Service, state.Status, last)
+		}
+	}
+
+	b.WriteString("\nEnvironment:\n")
+	if len(d.Env) == 0 {
+		b.WriteString("  (none set)\n")
+	}
+	for _, name := range sortedKeys(d.Env) {
+		fmt.Fprintf(&b, "  %s=%s\n", name, d.Env[name])
+	}
+
+	if len(d.Errors) > 0 {
+		b.WriteString("\nErrors:\n")
+		for _, e := range d.Errors {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
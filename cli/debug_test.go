@@ -0,0 +1,101 @@
+// ABOUTME: Tests for debug CLI commands
+// ABOUTME: Validates secret redaction and debug bundle contents
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+func TestRedactedEnv(t *testing.T) {
+	env := redactedEnv([]string{
+		"GOOGLE_CLIENT_ID=abc.apps.googleusercontent.com",
+		"GOOGLE_CLIENT_SECRET=hunter2",
+		"PAGEN_VAULT_TOKEN=tok",
+		"CHARM_HOST=charm.example.com",
+		"HOME=/root",
+	})
+
+	if env["GOOGLE_CLIENT_ID"] != "abc.apps.googleusercontent.com" {
+		t.Errorf("expected client ID to be kept, got %q", env["GOOGLE_CLIENT_ID"])
+	}
+	if env["GOOGLE_CLIENT_SECRET"] != redacted || env["PAGEN_VAULT_TOKEN"] != redacted {
+		t.Errorf("expected secrets to be redacted, got %v", env)
+	}
+	if _, ok := env["HOME"]; ok {
+		t.Error("expected unrelated variables to be excluded")
+	}
+}
+
+func TestRedactVaultConfig(t *testing.T) {
+	cfg := &sync.VaultConfig{Server: "https://vault.example.com", Token: "secret", DerivedKey: "abcd"}
+	out := redactVaultConfig(cfg)
+
+	if out.Token != redacted || out.DerivedKey != redacted || out.RefreshToken != "" {
+		t.Errorf("unexpected redaction: %+v", out)
+	}
+	if cfg.Token != "secret" {
+		t.Error("expected original config to be untouched")
+	}
+}
+
+func TestWriteDebugBundle(t *testing.T) {
+	client := charm.NewTestClient(t)
+	t.Setenv("GOOGLE_CLIENT_SECRET", "hunter2")
+
+	if err := client.CreateContact(&charm.Contact{Name: "Alice"}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	if err := client.SaveSyncState(&charm.SyncState{Service: "gmail", Status: charm.SyncStatusError, ErrorMessage: "token expired"}); err != nil {
+		t.Fatalf("failed to save sync state: %v", err)
+	}
+
+	d := CollectDiagnostics(client, "1.2.3")
+	if d.Counts["contacts"] != 1 {
+		t.Errorf("expected 1 contact counted, got %d", d.Counts["contacts"])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDebugBundle(&buf, d); err != nil {
+		t.Fatalf("WriteDebugBundle failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		contents[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"summary.txt", "diagnostics.json", "sync_logs.json", "errors.txt"} {
+		if _, ok := contents[name]; !ok {
+			t.Errorf("expected %s in bundle", name)
+		}
+	}
+	if !strings.Contains(contents["summary.txt"], "pagen 1.2.3") {
+		t.Errorf("expected version in summary, got %q", contents["summary.txt"])
+	}
+	if !strings.Contains(contents["errors.txt"], "sync gmail: token expired") {
+		t.Errorf("expected sync error in errors.txt, got %q", contents["errors.txt"])
+	}
+	for name, content := range contents {
+		if strings.Contains(content, "hunter2") {
+			t.Errorf("secret leaked into %s", name)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// ABOUTME: Duplicate contact CLI command
+// ABOUTME: Finds likely duplicate contacts and merges them, one prompt per pair or automatically
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// DedupeCommand finds likely duplicate contacts and merges them:
+// pagen crm dedupe [--auto] [--dry-run]
+func DedupeCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("dedupe", flagErrorHandling)
+	auto := fs.Bool("auto", false, "Merge same-email and same-name-and-company duplicates without asking (similar names are skipped)")
+	dryRun := fs.Bool("dry-run", false, "List likely duplicates without merging")
+	_ = fs.Parse(args)
+
+	pairs, err := client.FindDuplicateContacts()
+	if err != nil {
+		return fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	if len(pairs) == 0 {
+		fmt.Println("No likely duplicate contacts found")
+		return nil
+	}
+
+	if *dryRun {
+		for _, pair := range pairs {
+			printDuplicatePair(os.Stdout, pair)
+		}
+		fmt.Printf("\nDry run: %d likely duplicate(s). Run without --dry-run to merge.\n", len(pairs))
+		return nil
+	}
+
+	merged, skipped, err := dedupeContacts(client, pairs, *auto, bufio.NewReader(os.Stdin), os.Stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%d merged, %d skipped\n", merged, skipped)
+	return nil
+}
+
+// dedupeContacts merges duplicate pairs, asking about each one unless auto is
+// set, in which case only confident pairs are merged. Pairs whose contacts
+// were already merged are followed to the surviving contact.
+func dedupeContacts(client *charm.Client, pairs []*charm.DuplicatePair, auto bool, in *bufio.Reader, out io.Writer) (merged, skipped int, err error) {
+	mergedInto := make(map[uuid.UUID]uuid.UUID)
+	resolve := func(id uuid.UUID) uuid.UUID {
+		for {
+			next, ok := mergedInto[id]
+			if !ok {
+				return id
+			}
+			id = next
+		}
+	}
+
+	for _, pair := range pairs {
+		keepID, dropID := resolve(pair.Keep.ID), resolve(pair.Drop.ID)
+		if keepID == dropID {
+			continue
+		}
+		if keepID != pair.Keep.ID || dropID != pair.Drop.ID {
+			keep, err := client.GetContact(keepID)
+			if err != nil {
+				return merged, skipped, err
+			}
+			drop, err := client.GetContact(dropID)
+			if err != nil {
+				return merged, skipped, err
+			}
+			pair = &charm.DuplicatePair{Keep: keep, Drop: drop, Reason: pair.Reason, Similarity: pair.Similarity}
+		}
+
+		if auto {
+			if !pair.Confident() {
+				skipped++
+				continue
+			}
+		} else {
+			swap, apply, quit := reviewDuplicate(pair, in, out)
+			if quit {
+				break
+			}
+			if !apply {
+				skipped++
+				continue
+			}
+			if swap {
+				pair.Keep, pair.Drop = pair.Drop, pair.Keep
+			}
+		}
+
+		result, err := client.MergeContacts(pair.Keep.ID, pair.Drop.ID)
+		if err != nil {
+			return merged, skipped, fmt.Errorf("failed to merge %s into %s: %w", pair.Drop.Name, pair.Keep.Name, err)
+		}
+		mergedInto[pair.Drop.ID] = pair.Keep.ID
+		merged++
+		_, _ = fmt.Fprintf(out, "✓ Merged %s into %s (%s)\n", pair.Drop.Name, result.Kept.Name, describeMerge(result))
+	}
+	return merged, skipped, nil
+}
+
+// printDuplicatePair shows both contacts of a pair, the survivor first.
+func printDuplicatePair(out io.Writer, pair *charm.DuplicatePair) {
+	_, _ = fmt.Fprintf(out, "Possible duplicate (%s):\n", charm.DuplicateReasonLabel(pair.Reason))
+	for i, contact := range []*charm.Contact{pair.Keep, pair.Drop} {
+		details := []string{}
+		if contact.Email != "" {
+			details = append(details, "<"+contact.Email+">")
+		}
+		if contact.Title != "" {
+			details = append(details, contact.Title)
+		}
+		if contact.CompanyName != "" {
+			details = append(details, "at "+contact.CompanyName)
+		}
+		details = append(details, "added "+contact.CreatedAt.Format("2006-01-02"), charm.FormatID(contact.ID))
+		_, _ = fmt.Fprintf(out, "  %d) %s %s\n", i+1, contact.Name, strings.Join(details, "  "))
+	}
+}
+
+// reviewDuplicate asks whether to merge the second contact into the first.
+// swap merges the first into the second instead; quit stops the review.
+func reviewDuplicate(pair *charm.DuplicatePair, in *bufio.Reader, out io.Writer) (swap, apply, quit bool) {
+	printDuplicatePair(out, pair)
+	_, _ = fmt.Fprint(out, "Merge 2 into 1? [y]es, [s]wap (merge 1 into 2), [n]o, [q]uit: ")
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return false, false, true
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return false, true, false
+	case "s", "swap":
+		return true, true, false
+	case "q", "quit":
+		return false, false, true
+	}
+	return false, false, false
+}
+
+// describeMerge summarizes what a merge moved, e.g. "3 interactions, 1 deal".
+func describeMerge(result *charm.MergeResult) string {
+	var parts []string
+	add := func(n int, noun string) {
+		if n == 1 {
+			parts = append(parts, "1 "+noun)
+		} else if n > 1 {
+			parts = append(parts, fmt.Sprintf("%d %ss", n, noun))
+		}
+	}
+	add(result.Interactions, "interaction")
+	add(result.Relationships, "relationship")
+	add(result.Deals, "deal")
+	add(result.DealRoles, "deal role")
+	add(result.Tasks, "task")
+	add(result.Events, "event")
+	add(result.Emails, "email")
+	if result.Cadence {
+		parts = append(parts, "cadence")
+	}
+	if len(parts) == 0 {
+		return "nothing else to move"
+	}
+	return strings.Join(parts, ", ") + " moved"
+}
@@ -0,0 +1,68 @@
+// ABOUTME: Tests for the dedupe command
+// ABOUTME: Verifies prompted and automatic merges, and following pairs through earlier merges
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestDedupeContacts(t *testing.T) {
+	client := charm.NewTestClient(t)
+	for _, contact := range []*charm.Contact{
+		{Name: "Alice Smith", Email: "alice@acme.com"},
+		{Name: "Alice Smith", Email: "alice@acme.com"},
+		{Name: "alice smith", Email: "ALICE@acme.com"},
+		{Name: "Jon Parker"},
+		{Name: "John Parker"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	// --auto merges the three Alices but leaves the similar names alone
+	pairs, err := client.FindDuplicateContacts()
+	if err != nil {
+		t.Fatalf("FindDuplicateContacts failed: %v", err)
+	}
+	var out bytes.Buffer
+	merged, skipped, err := dedupeContacts(client, pairs, true, bufio.NewReader(strings.NewReader("")), &out)
+	if err != nil {
+		t.Fatalf("dedupeContacts failed: %v", err)
+	}
+	if merged != 2 || skipped != 1 {
+		t.Errorf("expected 2 merged and 1 skipped, got %d and %d:\n%s", merged, skipped, out.String())
+	}
+
+	// Interactively, swapping keeps the second contact
+	pairs, err = client.FindDuplicateContacts()
+	if err != nil {
+		t.Fatalf("FindDuplicateContacts failed: %v", err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("expected only the similar names left, got %d pairs", len(pairs))
+	}
+	survivor := pairs[0].Drop
+	out.Reset()
+	merged, _, err = dedupeContacts(client, pairs, false, bufio.NewReader(strings.NewReader("s\n")), &out)
+	if err != nil {
+		t.Fatalf("dedupeContacts failed: %v", err)
+	}
+	if merged != 1 || !strings.Contains(out.String(), "similar name") {
+		t.Errorf("expected a prompted merge, got %d:\n%s", merged, out.String())
+	}
+	if _, err := client.GetContact(survivor.ID); err != nil {
+		t.Errorf("expected the swapped survivor to remain: %v", err)
+	}
+
+	contacts, _ := client.ListContacts(&charm.ContactFilter{})
+	if len(contacts) != 2 {
+		t.Errorf("expected 2 contacts left, got %d", len(contacts))
+	}
+}
@@ -0,0 +1,138 @@
+// ABOUTME: Deprecation shims mapping old command invocations to their replacements
+// ABOUTME: Prints migration hints and uses distinct exit codes so scripts notice renamed or removed commands
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Exit codes for deprecated invocations, distinct from the 1 of an ordinary
+// failure so scripts can detect them.
+const (
+	// ExitDeprecated means a renamed command was not run because strict
+	// deprecations are on (--strict-deprecations or PAGEN_STRICT_DEPRECATIONS).
+	ExitDeprecated = 3
+	// ExitRemoved means the command no longer exists and nothing was run.
+	ExitRemoved = 4
+)
+
+// Deprecation maps an old invocation to its replacement. Renamed commands
+// keep working under the old name, with a hint on stderr; removed ones only
+// print the hint.
+type Deprecation struct {
+	Old  string `json:"old"`           // old command words, e.g. "sync vault-status"
+	New  string `json:"new,omitempty"` // replacement command words; empty when removed
+	Hint string `json:"hint,omitempty"`
+}
+
+// Removed reports whether the command has no replacement to run.
+func (d *Deprecation) Removed() bool {
+	return d.New == ""
+}
+
+// Deprecations lists the old invocations, checked in order.
+var Deprecations = []Deprecation{
+	{Old: "sync vault-init", New: "sync link"},
+	{Old: "sync vault-login", New: "sync link"},
+	{Old: "sync vault-status", New: "sync status"},
+	{Old: "sync charm-status", New: "sync status"},
+	{Old: "sync vault-now", New: "sync now"},
+	{Old: "sync vault-logout", New: "sync unlink"},
+	{Old: "sync vault-wipe", New: "sync wipe"},
+	{Old: "sync vault-pending", Hint: "Charm KV syncs each change directly, so nothing is queued; see 'pagen sync status'"},
+	{Old: "sync init", Hint: googleSyncHint},
+	{Old: "sync contacts", Hint: googleSyncHint},
+	{Old: "sync calendar", Hint: googleSyncHint},
+	{Old: "sync gmail", Hint: googleSyncHint},
+	{Old: "sync daemon", Hint: "Use 'pagen sync auto' to sync in the background"},
+}
+
+const googleSyncHint = "Google sync has been replaced by Charm KV sync: 'pagen sync link' links this device, 'pagen sync now' syncs"
+
+// FindDeprecation returns the deprecation matching the start of args, or nil.
+func FindDeprecation(args []string) *Deprecation {
+	for i := range Deprecations {
+		old := strings.Fields(Deprecations[i].Old)
+		if len(args) < len(old) {
+			continue
+		}
+		matched := true
+		for j, word := range old {
+			if args[j] != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return &Deprecations[i]
+		}
+	}
+	return nil
+}
+
+// Rewrite replaces the old command words at the start of args with the new
+// ones, keeping any remaining arguments.
+func (d *Deprecation) Rewrite(args []string) []string {
+	rest := args[len(strings.Fields(d.Old)):]
+	return append(strings.Fields(d.New), rest...)
+}
+
+// Warn writes the migration hint for d and returns the exit code to stop
+// with, or 0 when the replacement should run. Each hint line starts with
+// "pagen: deprecated:" so it is easy to grep from script logs.
+func (d *Deprecation) Warn(w io.Writer, strict bool) int {
+	switch {
+	case d.Removed():
+		fmt.Fprintf(w, "pagen: deprecated: 'pagen %s' has been removed\n", d.Old)
+	case strict:
+		fmt.Fprintf(w, "pagen: deprecated: 'pagen %s' is now 'pagen %s'; not run because strict deprecations are on\n", d.Old, d.New)
+	default:
+		fmt.Fprintf(w, "pagen: deprecated: 'pagen %s' is now 'pagen %s'; running that instead\n", d.Old, d.New)
+	}
+	if d.Hint != "" {
+		fmt.Fprintf(w, "pagen: deprecated: %s\n", d.Hint)
+	}
+
+	switch {
+	case d.Removed():
+		return ExitRemoved
+	case strict:
+		return ExitDeprecated
+	default:
+		return 0
+	}
+}
+
+// DeprecationsCommand lists deprecated commands and their replacements, to
+// check scripts against.
+func DeprecationsCommand(args []string) error {
+	fs := flag.NewFlagSet("deprecations", flagErrorHandling)
+	asJSON := fs.Bool("json", false, "Output as JSON")
+	_ = fs.Parse(args)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(Deprecations)
+	}
+
+	for _, d := range Deprecations {
+		replacement := "pagen " + d.New
+		if d.Removed() {
+			replacement = "(removed)"
+		}
+		fmt.Printf("pagen %-22s → %s\n", d.Old, replacement)
+		if d.Hint != "" {
+			fmt.Printf("  %s\n", d.Hint)
+		}
+	}
+	fmt.Printf("\nRenamed commands still run, with a hint on stderr. Removed commands exit %d;\n", ExitRemoved)
+	fmt.Printf("with --strict-deprecations (or PAGEN_STRICT_DEPRECATIONS=1) renamed ones exit %d instead of running.\n", ExitDeprecated)
+	return nil
+}
@@ -0,0 +1,70 @@
+// ABOUTME: Tests for command deprecation shims
+// ABOUTME: Verifies matching old invocations, rewriting arguments, hints, and exit codes
+
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDeprecation(t *testing.T) {
+	if d := FindDeprecation([]string{"sync", "status"}); d != nil {
+		t.Errorf("expected no deprecation for a current command, got %+v", d)
+	}
+	if d := FindDeprecation([]string{"sync"}); d != nil {
+		t.Errorf("expected no deprecation for a partial match, got %+v", d)
+	}
+
+	d := FindDeprecation([]string{"sync", "vault-wipe", "--confirm"})
+	if d == nil || d.New != "sync wipe" {
+		t.Fatalf("expected sync vault-wipe to map to sync wipe, got %+v", d)
+	}
+	if got := d.Rewrite([]string{"sync", "vault-wipe", "--confirm"}); !reflect.DeepEqual(got, []string{"sync", "wipe", "--confirm"}) {
+		t.Errorf("Rewrite = %v", got)
+	}
+}
+
+func TestDeprecationWarn(t *testing.T) {
+	renamed := FindDeprecation([]string{"sync", "vault-status"})
+	var out bytes.Buffer
+	if code := renamed.Warn(&out, false); code != 0 {
+		t.Errorf("expected a renamed command to run, got exit %d", code)
+	}
+	if !strings.Contains(out.String(), "pagen: deprecated: 'pagen sync vault-status' is now 'pagen sync status'") {
+		t.Errorf("unexpected hint: %q", out.String())
+	}
+
+	out.Reset()
+	if code := renamed.Warn(&out, true); code != ExitDeprecated {
+		t.Errorf("expected exit %d in strict mode, got %d", ExitDeprecated, code)
+	}
+
+	out.Reset()
+	removed := FindDeprecation([]string{"sync", "gmail", "--initial"})
+	if code := removed.Warn(&out, false); code != ExitRemoved {
+		t.Errorf("expected exit %d for a removed command, got %d", ExitRemoved, code)
+	}
+	if !strings.Contains(out.String(), "has been removed") || !strings.Contains(out.String(), "sync link") {
+		t.Errorf("expected a removal hint pointing at sync link, got %q", out.String())
+	}
+}
+
+func TestDeprecationsAreConsistent(t *testing.T) {
+	seen := map[string]bool{}
+	for _, d := range Deprecations {
+		if seen[d.Old] {
+			t.Errorf("duplicate deprecation for %q", d.Old)
+		}
+		seen[d.Old] = true
+		if d.Removed() && d.Hint == "" {
+			t.Errorf("removed command %q needs a hint", d.Old)
+		}
+		// A replacement that is itself deprecated would loop through two hints
+		if d.New != "" && FindDeprecation(strings.Fields(d.New)) != nil {
+			t.Errorf("%q is replaced by deprecated %q", d.Old, d.New)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// ABOUTME: Emails the daily follow-up digest over SMTP or the Gmail API
+// ABOUTME: Builds an HTML message with a plain-text alternative; SMTP settings come from PAGEN_SMTP_* in .env
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/sync"
+)
+
+// Ways to send the digest
+const (
+	digestViaSMTP  = "smtp"
+	digestViaGmail = "gmail"
+)
+
+// smtpSettings are read from PAGEN_SMTP_HOST, PAGEN_SMTP_PORT (default 587),
+// PAGEN_SMTP_USERNAME, PAGEN_SMTP_PASSWORD, and PAGEN_SMTP_FROM (default the username).
+type smtpSettings struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// smtpSettingsFromEnv returns the SMTP settings, or nil when no host is set.
+func smtpSettingsFromEnv() *smtpSettings {
+	host := os.Getenv("PAGEN_SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	s := &smtpSettings{
+		host:     host,
+		port:     os.Getenv("PAGEN_SMTP_PORT"),
+		username: os.Getenv("PAGEN_SMTP_USERNAME"),
+		password: os.Getenv("PAGEN_SMTP_PASSWORD"),
+		from:     os.Getenv("PAGEN_SMTP_FROM"),
+	}
+	if s.port == "" {
+		s.port = "587"
+	}
+	if s.from == "" {
+		s.from = s.username
+	}
+	return s
+}
+
+// emailDigest sends the digest via SMTP or Gmail to the comma-separated
+// recipients in to, defaulting to PAGEN_DIGEST_TO and then the sending
+// address. It returns who it was sent to.
+func emailDigest(via, to, subject string, text, html []byte) (string, error) {
+	smtpConfig := smtpSettingsFromEnv()
+	if via == "" {
+		via = digestViaGmail
+		if smtpConfig != nil {
+			via = digestViaSMTP
+		}
+	}
+	if to == "" {
+		to = os.Getenv("PAGEN_DIGEST_TO")
+	}
+
+	switch via {
+	case digestViaSMTP:
+		if smtpConfig == nil {
+			return "", fmt.Errorf("set PAGEN_SMTP_HOST (and PAGEN_SMTP_USERNAME, PAGEN_SMTP_PASSWORD) in .env to send over SMTP")
+		}
+		if smtpConfig.from == "" {
+			return "", fmt.Errorf("set PAGEN_SMTP_FROM or PAGEN_SMTP_USERNAME to send over SMTP")
+		}
+		if to == "" {
+			to = smtpConfig.from
+		}
+		recipients := splitRecipients(to)
+		msg, err := buildDigestEmail(smtpConfig.from, recipients, subject, text, html, time.Now())
+		if err != nil {
+			return "", err
+		}
+		if err := sendSMTP(smtpConfig, recipients, msg); err != nil {
+			return "", err
+		}
+		return strings.Join(recipients, ", "), nil
+
+	case digestViaGmail:
+		token, err := sync.LoadToken()
+		if err != nil {
+			return "", fmt.Errorf("no Google token; run a Google sync first, or set PAGEN_SMTP_HOST to send over SMTP")
+		}
+		service, err := sync.NewGmailClient(token)
+		if err != nil {
+			return "", err
+		}
+		ctx := context.Background()
+		from, err := sync.GmailAddress(ctx, service)
+		if err != nil {
+			return "", err
+		}
+		if to == "" {
+			to = from
+		}
+		recipients := splitRecipients(to)
+		msg, err := buildDigestEmail(from, recipients, subject, text, html, time.Now())
+		if err != nil {
+			return "", err
+		}
+		if _, err := sync.SendGmailMessage(ctx, service, msg); err != nil {
+			return "", fmt.Errorf("%w (the Google token needs a gmail.send or gmail.compose scope)", err)
+		}
+		return strings.Join(recipients, ", "), nil
+	}
+	return "", fmt.Errorf("unsupported --via: %s (use smtp or gmail)", via)
+}
+
+// splitRecipients splits a comma-separated address list.
+func splitRecipients(to string) []string {
+	var recipients []string
+	for _, addr := range strings.Split(to, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+	return recipients
+}
+
+// buildDigestEmail formats a multipart/alternative RFC 2822 message with a
+// plain-text and an HTML part, both quoted-printable.
+func buildDigestEmail(from string, to []string, subject string, text, html []byte, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	var msg bytes.Buffer
+	if from != "" {
+		fmt.Fprintf(&msg, "From: %s\r\n", from)
+	}
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// sendSMTP delivers msg with STARTTLS when the server offers it, or over
+// implicit TLS on port 465.
+func sendSMTP(s *smtpSettings, to []string, msg []byte) error {
+	addr := net.JoinHostPort(s.host, s.port)
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if s.port != "465" {
+		if err := smtp.SendMail(addr, auth, s.from, to, msg); err != nil {
+			return fmt.Errorf("failed to send email via %s: %w", addr, err)
+		}
+		return nil
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: s.host})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP login failed: %w", err)
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return fmt.Errorf("SMTP server refused sender %s: %w", s.from, err)
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return fmt.Errorf("SMTP server refused recipient %s: %w", addr, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,147 @@
+// ABOUTME: Tests for emailing the follow-up digest
+// ABOUTME: Verifies the multipart message and delivery to a fake SMTP server configured from the environment
+package cli
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestBuildDigestEmail(t *testing.T) {
+	msg, err := buildDigestEmail("me@example.com", []string{"a@example.com", "b@example.com"}, "Follow-ups for 2024-06-01: 1 overdue, 0 due soon",
+		[]byte("FOLLOW-UPS\n"), []byte("<h1>Follow-Ups</h1>"), time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildDigestEmail failed: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != "a@example.com, b@example.com" {
+		t.Errorf("unexpected To: %q", got)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject")); subject != "Follow-ups for 2024-06-01: 1 overdue, 0 due soon" {
+		t.Errorf("unexpected Subject: %q", subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q, %v", mediaType, err)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		body, _ := io.ReadAll(part) // quoted-printable is decoded by NextPart
+		types = append(types, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Fatalf("expected text then HTML parts, got %v", types)
+	}
+	if bodies[1] != "<h1>Follow-Ups</h1>" {
+		t.Errorf("unexpected HTML part: %q", bodies[1])
+	}
+}
+
+// fakeSMTP accepts one message without TLS or auth and sends what it got on
+// the returned channel.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+		var transcript strings.Builder
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					transcript.WriteString(data)
+				}
+				reply("250 queued")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestDigestCommandEmailSMTP(t *testing.T) {
+	client := charm.NewTestClient(t)
+	addr, received := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	t.Setenv("PAGEN_SMTP_HOST", host)
+	t.Setenv("PAGEN_SMTP_PORT", port)
+	t.Setenv("PAGEN_SMTP_USERNAME", "")
+	t.Setenv("PAGEN_SMTP_FROM", "pagen@example.com")
+	t.Setenv("PAGEN_DIGEST_TO", "me@example.com")
+
+	if err := DigestCommand(client, []string{"--email"}); err != nil {
+		t.Fatalf("DigestCommand --email failed: %v", err)
+	}
+
+	select {
+	case transcript := <-received:
+		for _, want := range []string{"MAIL FROM:<pagen@example.com>", "RCPT TO:<me@example.com>", "Subject: Follow-ups for ", "text/html"} {
+			if !strings.Contains(transcript, want) {
+				t.Errorf("expected %q in the SMTP session, got:\n%s", want, transcript)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	if err := DigestCommand(client, []string{"--email", "--output", "digest.html"}); err == nil {
+		t.Error("expected --email with --output to fail")
+	}
+}
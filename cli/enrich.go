@@ -0,0 +1,101 @@
+// ABOUTME: Contact enrichment CLI command
+// ABOUTME: Classifies a contact and pulls Gravatar, company website, and enrichment API metadata with provenance
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// EnrichCommand enriches one contact from the selected sources and saves the
+// results, printing each value with where it came from.
+func EnrichCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("enrich", flagErrorHandling)
+	sourceList := fs.String("sources", "", "Comma-separated: classify, gravatar, website, api (default: all)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be saved")
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("contact ID is required")
+	}
+	sources, err := sync.ParseEnrichSources(*sourceList)
+	if err != nil {
+		return err
+	}
+	contactID, err := resolveID(client, fs.Arg(0), charm.EntityContact)
+	if err != nil {
+		return err
+	}
+	contact, err := client.GetContact(contactID)
+	if err != nil {
+		return fmt.Errorf("contact not found: %w", err)
+	}
+
+	fmt.Printf("Enriching %s\n", contact.Name)
+	if sync.HasEnrichSource(sources, sync.EnrichClassify) {
+		classification, err := charm.RuleClassifier{}.Classify(context.Background(), charm.ClassificationText(contact))
+		switch {
+		case err != nil:
+			fmt.Printf("  ✗ classify: %v\n", err)
+		case classification.Seniority == "" && classification.Function == "":
+			fmt.Println("  - classify: title doesn't say enough")
+		default:
+			fmt.Printf("  seniority           %s  (%s)\n", orDash(classification.Seniority), classification.Source)
+			fmt.Printf("  function            %s  (%s)\n", orDash(classification.Function), classification.Source)
+			if !*dryRun {
+				if _, err := client.ApplyClassification(contact.ID, classification); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*sync.EnrichTimeout)
+	defer cancel()
+	result := sync.NewPublicEnricher(client.Config()).Enrich(ctx, client, contact, sources)
+	printMetadata("", result.Contact)
+	if len(result.Company) > 0 {
+		company, _ := client.GetCompany(result.CompanyID)
+		if company != nil {
+			fmt.Printf("  %s:\n", company.Name)
+		}
+		printMetadata("  ", result.Company)
+	}
+	for _, note := range result.Notes {
+		fmt.Printf("  - %s\n", note)
+	}
+	for _, failure := range result.Errors {
+		fmt.Printf("  ✗ %s\n", failure)
+	}
+
+	if *dryRun {
+		fmt.Println("\nDry run: nothing saved")
+		return nil
+	}
+	contactKeys, companyKeys, err := result.Apply(client, contact.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n✓ Updated %d contact and %d company field(s)\n", len(contactKeys), len(companyKeys))
+	return nil
+}
+
+// printMetadata prints metadata values with their provenance and age.
+func printMetadata(indent string, metadata charm.Metadata) {
+	for _, key := range metadata.Keys() {
+		value := metadata[key]
+		fmt.Printf("  %s%-18s  %s  (%s, %s)\n", indent, key, value.Value, value.Source, value.FetchedAt.Format(time.DateOnly))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
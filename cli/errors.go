@@ -0,0 +1,93 @@
+// ABOUTME: Exit statuses and --json error output for CLI failures
+// ABOUTME: Maps each error code to a distinct exit status so scripts branch without parsing messages
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// Exit statuses by error code. 2 is bad usage and 3 and 4 are deprecated
+// commands (see ExitDeprecated).
+const (
+	ExitError           = 1
+	ExitUsage           = 2
+	ExitNotFound        = 5
+	ExitValidation      = 6
+	ExitConflict        = 7
+	ExitSyncUnavailable = 8
+)
+
+var exitStatuses = map[charm.ErrorCode]int{
+	charm.CodeNotFound:        ExitNotFound,
+	charm.CodeValidation:      ExitValidation,
+	charm.CodeConflict:        ExitConflict,
+	charm.CodeSyncUnavailable: ExitSyncUnavailable,
+}
+
+// ExitStatus returns the exit status for err's code: 0 for nil, ExitError
+// for errors without a more specific code.
+func ExitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	if status, ok := exitStatuses[charm.CodeOf(err)]; ok {
+		return status
+	}
+	return ExitError
+}
+
+// ErrorReport is the --json form of a failure, written to stderr.
+type ErrorReport struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure by stable code, message, and exit status.
+type ErrorDetail struct {
+	Code       charm.ErrorCode `json:"code"`
+	Message    string          `json:"message"`
+	ExitStatus int             `json:"exit_status"`
+}
+
+// WriteError reports err to w, as an ErrorReport when asJSON, and returns the
+// exit status to use.
+func WriteError(w io.Writer, err error, asJSON bool) int {
+	status := ExitStatus(err)
+	if !asJSON {
+		log.New(w, "", log.LstdFlags).Printf("Error: %v", err)
+		return status
+	}
+	report := ErrorReport{Error: ErrorDetail{Code: charm.CodeOf(err), Message: err.Error(), ExitStatus: status}}
+	data, _ := json.Marshal(report)
+	fmt.Fprintln(w, string(data))
+	return status
+}
+
+// Fail reports err on stderr and exits with its status.
+func Fail(err error, asJSON bool) {
+	os.Exit(WriteError(os.Stderr, err, asJSON))
+}
+
+// WantsJSON reports whether args ask for JSON output with --json.
+func WantsJSON(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		switch strings.TrimLeft(arg, "-") {
+		case "json", "json=true", "json=1":
+			return true
+		}
+	}
+	return false
+}
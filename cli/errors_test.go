@@ -0,0 +1,84 @@
+// ABOUTME: Tests for CLI exit statuses and --json error output
+// ABOUTME: Verifies each error code maps to its own exit status and the JSON report shape
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestExitStatus(t *testing.T) {
+	client := charm.NewTestClient(t)
+	_, notFound := client.GetContact(uuid.New())
+	_, badRef := resolveID(client, "@Nobody", charm.EntityContact)
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain", errors.New("boom"), ExitError},
+		{"missing contact", notFound, ExitNotFound},
+		{"unmatched reference", badRef, ExitNotFound},
+		{"wrapped", fmt.Errorf("lookup failed: %w", charm.NotFoundf("deal not found")), ExitNotFound},
+		{"validation", charm.Validationf("--name is required"), ExitValidation},
+		{"conflict", charm.Conflictf("feed already added"), ExitConflict},
+		{"sync", charm.WithCode(charm.CodeSyncUnavailable, errors.New("dial tcp: no such host")), ExitSyncUnavailable},
+	}
+	for _, tt := range tests {
+		if got := ExitStatus(tt.err); got != tt.want {
+			t.Errorf("%s: ExitStatus(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	err := fmt.Errorf("failed to add deal: %w", charm.Validationf("invalid source: cold"))
+
+	var out bytes.Buffer
+	if status := WriteError(&out, err, false); status != ExitValidation {
+		t.Errorf("expected exit %d, got %d", ExitValidation, status)
+	}
+	if !strings.Contains(out.String(), "Error: failed to add deal: invalid source: cold") {
+		t.Errorf("unexpected text error: %q", out.String())
+	}
+
+	out.Reset()
+	WriteError(&out, err, true)
+	var report ErrorReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected a JSON report, got %q: %v", out.String(), err)
+	}
+	want := ErrorDetail{Code: charm.CodeValidation, Message: "failed to add deal: invalid source: cold", ExitStatus: ExitValidation}
+	if report.Error != want {
+		t.Errorf("report = %+v, want %+v", report.Error, want)
+	}
+}
+
+func TestWantsJSON(t *testing.T) {
+	tests := map[string]bool{
+		"crm list-contacts":                false,
+		"deprecations --json":              true,
+		"debug -json":                      true,
+		"deprecations --json=true":         true,
+		"crm add-contact --name json":      false,
+		"crm add-note -- --json":           false,
+		"followups digest --format=html":   false,
+		"followups digest --json=false":    false,
+		"crm search --query json --json=1": true,
+	}
+	for args, want := range tests {
+		if got := WantsJSON(strings.Fields(args)); got != want {
+			t.Errorf("WantsJSON(%q) = %v, want %v", args, got, want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// ABOUTME: Event CLI commands for conferences, dinners, and meetups
+// ABOUTME: Adds events, records who attended or was met there, and reports contacts sourced per event
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// EventsAddCommand adds a new event.
+func EventsAddCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("add", flagErrorHandling)
+	name := fs.String("name", "", "Event name (required)")
+	eventType := fs.String("type", charm.EventTypeConference, "Event type: "+strings.Join(charm.EventTypes, ", "))
+	date := fs.String("date", "", "Event date (YYYY-MM-DD, default: today)")
+	location := fs.String("location", "", "Location")
+	notes := fs.String("notes", "", "Notes")
+	_ = fs.Parse(args)
+
+	if *name == "" {
+		return charm.Validationf("--name is required")
+	}
+
+	event := &charm.Event{
+		Name:      *name,
+		EventType: *eventType,
+		Location:  *location,
+		Notes:     *notes,
+	}
+	if *date != "" {
+		parsed, err := time.Parse("2006-01-02", *date)
+		if err != nil {
+			return charm.Validationf("invalid --date (use YYYY-MM-DD): %w", err)
+		}
+		event.Date = parsed
+	}
+
+	if err := client.CreateEvent(event); err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	fmt.Printf("✓ Created event: %s (ID: %s)\n", event.Name, event.ID)
+	fmt.Printf("  %s on %s\n", event.EventType, event.Date.Format("2006-01-02"))
+	return nil
+}
+
+// EventsListCommand lists events.
+func EventsListCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flagErrorHandling)
+	eventType := fs.String("type", "", "Filter by event type")
+	_ = fs.Parse(args)
+
+	events, err := client.ListEvents()
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tNAME\tTYPE\tLOCATION\tATTENDEES\tID")
+	_, _ = fmt.Fprintln(w, "----\t----\t----\t--------\t---------\t--")
+
+	count := 0
+	for _, event := range events {
+		if *eventType != "" && event.EventType != *eventType {
+			continue
+		}
+		attendees, err := client.ListEventAttendees(event.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list attendees: %w", err)
+		}
+		location := event.Location
+		if location == "" {
+			location = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			event.Date.Format("2006-01-02"), event.Name, event.EventType, location, len(attendees), charm.FormatID(event.ID))
+		count++
+	}
+
+	if count == 0 {
+		fmt.Println("No events found")
+		return nil
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// EventsAttendCommand records contacts attending an event.
+func EventsAttendCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("attend", flagErrorHandling)
+	eventRef := fs.String("event", "", "Event ID or name (required)")
+	contactRefs := fs.String("contact", "", "Contact ID or name, comma-separated for several (required)")
+	met := fs.Bool("met", false, "First met these contacts at the event")
+	notes := fs.String("notes", "", "Notes")
+	_ = fs.Parse(args)
+
+	if *eventRef == "" || *contactRefs == "" {
+		return charm.Validationf("--event and --contact are required")
+	}
+
+	event, err := findEvent(client, *eventRef)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range strings.Split(*contactRefs, ",") {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		contact, err := findContact(client, ref)
+		if err != nil {
+			return err
+		}
+
+		attendee := &charm.EventAttendee{
+			EventID:     event.ID,
+			EventName:   event.Name,
+			EventDate:   event.Date,
+			ContactID:   contact.ID,
+			ContactName: contact.Name,
+			MetHere:     *met,
+			Notes:       *notes,
+		}
+		if err := client.RecordAttendance(attendee); err != nil {
+			return fmt.Errorf("failed to record attendance: %w", err)
+		}
+
+		if *met {
+			fmt.Printf("✓ Met %s at %s\n", contact.Name, event.Name)
+		} else {
+			fmt.Printf("✓ %s attended %s\n", contact.Name, event.Name)
+		}
+	}
+	return nil
+}
+
+// EventsAttendeesCommand lists the contacts who attended an event.
+func EventsAttendeesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("attendees", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) != 1 {
+		return fmt.Errorf("usage: events attendees <event-id-or-name>")
+	}
+
+	event, err := findEvent(client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	attendees, err := client.ListEventAttendees(event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list attendees: %w", err)
+	}
+
+	fmt.Printf("%s (%s, %s)\n\n", event.Name, event.EventType, event.Date.Format("2006-01-02"))
+	if len(attendees) == 0 {
+		fmt.Println("No attendees recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CONTACT\tMET HERE\tNOTES")
+	_, _ = fmt.Fprintln(w, "-------\t--------\t-----")
+	for _, a := range attendees {
+		met := "-"
+		if a.MetHere {
+			met = "yes"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", a.ContactName, met, a.Notes)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// EventsReportCommand reports the contacts and deals sourced per event.
+func EventsReportCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("report", flagErrorHandling)
+	verbose := fs.Bool("verbose", false, "List the contacts sourced at each event")
+	_ = fs.Parse(args)
+
+	report, err := client.EventSourcingReport()
+	if err != nil {
+		return fmt.Errorf("failed to build event report: %w", err)
+	}
+	if len(report) == 0 {
+		fmt.Println("No events found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tEVENT\tATTENDEES\tMET\tDEALS\tOPEN PIPELINE\tWON")
+	_, _ = fmt.Fprintln(w, "----\t-----\t---------\t---\t-----\t-------------\t---")
+	for _, stats := range report {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\t%s (%d)\n",
+			stats.Event.Date.Format("2006-01-02"),
+			stats.Event.Name,
+			stats.Attendees,
+			stats.ContactsMet,
+			stats.Deals,
+			charm.FormatMoneyRounded(stats.OpenPipeline, ""),
+			charm.FormatMoneyRounded(stats.WonValue, ""),
+			stats.WonDeals,
+		)
+	}
+	_ = w.Flush()
+
+	if *verbose {
+		for _, stats := range report {
+			if len(stats.SourcedContacts) == 0 {
+				continue
+			}
+			fmt.Printf("\n%s: %s\n", stats.Event.Name, strings.Join(stats.SourcedContacts, ", "))
+		}
+	}
+	return nil
+}
+
+// EventsDeleteCommand deletes an event and its attendance records.
+func EventsDeleteCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("delete", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) != 1 {
+		return fmt.Errorf("usage: events delete <event-id>")
+	}
+
+	id, err := resolveID(client, fs.Arg(0), charm.EntityEvent)
+	if err != nil {
+		return err
+	}
+	event, err := client.GetEvent(id)
+	if err != nil {
+		return fmt.Errorf("event not found: %w", err)
+	}
+
+	if err := client.DeleteEvent(id); err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	fmt.Printf("✓ Deleted event: %s\n", event.Name)
+	return nil
+}
+
+// findEvent resolves an event ID, ID prefix, or name (see resolveID).
+func findEvent(client *charm.Client, ref string) (*charm.Event, error) {
+	id, err := resolveID(client, ref, charm.EntityEvent)
+	if err != nil {
+		return nil, err
+	}
+	event, err := client.GetEvent(id)
+	if err != nil {
+		return nil, fmt.Errorf("event not found: %w", err)
+	}
+	return event, nil
+}
@@ -0,0 +1,183 @@
+// ABOUTME: Data export CLI commands
+// ABOUTME: Writes contacts, companies, deals, or interactions as CSV, JSON, or NDJSON, contact timelines as JSON,
+// ABOUTME: and contact segments as mail-merge CSVs
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ExportCommand exports every object of one type:
+// pagen crm export --type contacts|companies|deals|interactions [--format csv|json|ndjson] [--output file]
+// pagen crm export sheet ... writes an editable sheet instead; see ExportSheetCommand.
+func ExportCommand(client *charm.Client, args []string) error {
+	if len(args) > 0 && args[0] == "sheet" {
+		return ExportSheetCommand(client, args[1:])
+	}
+
+	fs := flag.NewFlagSet("export", flagErrorHandling)
+	exportType := fs.String("type", "", "What to export: "+strings.Join(charm.ExportTypes, ", ")+" (required)")
+	format := fs.String("format", "", "Output format: csv, json, or ndjson (default: from the output file extension, else csv)")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	_ = fs.Parse(args)
+
+	if *exportType == "" {
+		return fmt.Errorf("--type is required (%s)", strings.Join(charm.ExportTypes, ", "))
+	}
+	if *format == "" {
+		*format = exportFormatFor(*output)
+	}
+
+	if *output == "" {
+		_, err := client.Export(os.Stdout, *exportType, *format)
+		return err
+	}
+
+	f, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	count, err := client.Export(f, *exportType, *format)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write %s: %w", *output, closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(*output)
+		return err
+	}
+
+	fmt.Printf("✓ Exported %d %s to %s\n", count, *exportType, *output)
+	return nil
+}
+
+// SegmentCommand lists the emailable contacts matching a query, or writes them
+// as a mail-merge CSV:
+// pagen crm segment [--query q] [--export file.csv] [--fields first_name,email,...]
+func SegmentCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("segment", flagErrorHandling)
+	query := fs.String("query", "", "Contacts to include, as in list-contacts, e.g. 'tag:investor last_contacted:<90d' (default: everyone)")
+	export := fs.String("export", "", "Write a mail-merge CSV to this file (- for stdout)")
+	fields := fs.String("fields", "", "Columns to export: "+strings.Join(charm.MailMergeFields, ", ")+" (default: "+strings.Join(charm.DefaultMailMergeFields, ",")+")")
+	_ = fs.Parse(args)
+
+	columns, err := charm.ParseMailMergeFields(*fields)
+	if err != nil {
+		return err
+	}
+	segment, err := client.ContactSegment(*query)
+	if err != nil {
+		return err
+	}
+
+	switch *export {
+	case "":
+		if len(segment.Contacts) == 0 {
+			fmt.Println("No contacts in segment")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "NAME\tEMAIL\tCOMPANY")
+			_, _ = fmt.Fprintln(w, "----\t-----\t-------")
+			for _, contact := range segment.Contacts {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", contact.Name, contact.Email, contact.CompanyName)
+			}
+			_ = w.Flush()
+			fmt.Printf("\nTotal: %d contact(s)\n", len(segment.Contacts))
+		}
+	case "-":
+		if err := charm.WriteMailMerge(os.Stdout, segment.Contacts, columns); err != nil {
+			return err
+		}
+	default:
+		f, err := os.OpenFile(*export, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *export, err)
+		}
+		err = charm.WriteMailMerge(f, segment.Contacts, columns)
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write %s: %w", *export, closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(*export)
+			return err
+		}
+		fmt.Printf("✓ Exported %d contact(s) to %s\n", len(segment.Contacts), *export)
+	}
+
+	// Keep the notes out of a CSV written to stdout
+	notes := os.Stdout
+	if *export == "-" {
+		notes = os.Stderr
+	}
+	if segment.Suppressed > 0 {
+		_, _ = fmt.Fprintf(notes, "  Left out %d tagged %s\n", segment.Suppressed, charm.TagDoNotContact)
+	}
+	if segment.NoEmail > 0 {
+		_, _ = fmt.Fprintf(notes, "  Left out %d without an email address\n", segment.NoEmail)
+	}
+	if segment.Duplicates > 0 {
+		_, _ = fmt.Fprintf(notes, "  Left out %d sharing an email address with another contact\n", segment.Duplicates)
+	}
+	return nil
+}
+
+// exportFormatFor picks the format matching an output file's extension.
+func exportFormatFor(output string) string {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".json":
+		return charm.ExportJSON
+	case ".ndjson", ".jsonl":
+		return charm.ExportNDJSON
+	default:
+		return charm.ExportCSV
+	}
+}
+
+// ExportTimelineCommand exports a contact's interactions and deal milestones
+// as a vis.js timeline document:
+// pagen export timeline --contact <id> [--format json] [--output file]
+func ExportTimelineCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("timeline", flagErrorHandling)
+	contactRef := fs.String("contact", "", "Contact ID or name (required)")
+	format := fs.String("format", charm.ExportJSON, "Output format: json")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	_ = fs.Parse(args)
+
+	if *contactRef == "" {
+		return charm.Validationf("--contact is required")
+	}
+	if *format != charm.ExportJSON {
+		return fmt.Errorf("unsupported timeline format: %s (valid: json)", *format)
+	}
+	contactID, err := resolveID(client, *contactRef, charm.EntityContact)
+	if err != nil {
+		return err
+	}
+
+	timeline, err := client.GetContactTimeline(contactID)
+	if err != nil {
+		return fmt.Errorf("failed to build timeline: %w", err)
+	}
+	data, err := json.MarshalIndent(timeline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timeline: %w", err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("✓ Exported %d timeline item(s) for %s to %s\n", len(timeline.Items), timeline.Contact.Name, *output)
+	return nil
+}
@@ -3,22 +3,32 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"html"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"strings"
 	"text/tabwriter"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
 )
 
 // FollowupListCommand lists contacts needing follow-up.
 func FollowupListCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	fs := flag.NewFlagSet("list", flagErrorHandling)
 	overdueOnly := fs.Bool("overdue-only", false, "Show only overdue contacts")
 	strength := fs.String("strength", "", "Filter by relationship strength (weak/medium/strong)")
 	limit := fs.Int("limit", 10, "Maximum number of contacts to show")
+	slots := fs.Bool("slots", false, "Suggest an open Google Calendar slot for each catch-up")
+	slotLength := fs.Duration("slot-length", time.Hour, "Length of suggested slots")
 	_ = fs.Parse(args)
 
 	followups, err := client.GetFollowupList(*limit)
@@ -38,12 +48,25 @@ func FollowupListCommand(client *charm.Client, args []string) error {
 		filtered = append(filtered, f)
 	}
 
+	// Each contact gets its own slot, in priority order
+	var free []sync.TimeSlot
+	if *slots {
+		free, err = freeSlots(client, time.Now(), slotSearchDays, sync.SlotOptions{Duration: *slotLength})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Print results
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tDAYS SINCE\tPRIORITY\tSTRENGTH\tEMAIL")
-	_, _ = fmt.Fprintln(w, "----\t----------\t--------\t--------\t-----")
+	header, rule := "NAME\tDAYS SINCE\tPRIORITY\tENGAGEMENT\tSTRENGTH\tEMAIL", "----\t----------\t--------\t----------\t--------\t-----"
+	if *slots {
+		header, rule = header+"\tSUGGESTED", rule+"\t---------"
+	}
+	_, _ = fmt.Fprintln(w, header)
+	_, _ = fmt.Fprintln(w, rule)
 
-	for _, f := range filtered {
+	for i, f := range filtered {
 		indicator := "🟢"
 		if f.DaysSinceContact > f.CadenceDays+7 {
 			indicator = "🔴"
@@ -51,17 +74,30 @@ func FollowupListCommand(client *charm.Client, args []string) error {
 			indicator = "🟡"
 		}
 
-		_, _ = fmt.Fprintf(w, "%s %s\t%d\t%.1f\t%s\t%s\n",
-			indicator, f.Name, f.DaysSinceContact, f.PriorityScore,
+		_, _ = fmt.Fprintf(w, "%s %s\t%d\t%.1f\t%.1f\t%s\t%s",
+			indicator, f.Name, f.DaysSinceContact, f.PriorityScore, f.EngagementScore,
 			f.RelationshipStrength, f.Email)
+		if *slots {
+			suggestion := "-"
+			if i < len(free) {
+				suggestion = "free " + free[i].String()
+			}
+			_, _ = fmt.Fprintf(w, "\t%s", suggestion)
+		}
+		_, _ = fmt.Fprintln(w)
 	}
 
 	_ = w.Flush()
 	return nil
 }
 
-// FollowupStatsCommand shows follow-up statistics.
+// FollowupStatsCommand shows follow-up statistics: network health and, once
+// outcomes are logged, response rates by channel and outreach template.
 func FollowupStatsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("stats", flagErrorHandling)
+	days := fs.Int("days", 0, "Only count outcomes from the last n days (default: all)")
+	_ = fs.Parse(args)
+
 	cadences, err := client.ListContactCadences()
 	if err != nil {
 		return fmt.Errorf("failed to get cadences: %w", err)
@@ -106,40 +142,68 @@ func FollowupStatsCommand(client *charm.Client, args []string) error {
 		}
 	}
 
-	return nil
+	var since *time.Time
+	if *days > 0 {
+		t := time.Now().AddDate(0, 0, -*days)
+		since = &t
+	}
+	report, err := client.GetOutcomeReport(since)
+	if err != nil {
+		return fmt.Errorf("failed to get outcomes: %w", err)
+	}
+	if len(report.ByChannel) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("OUTCOMES")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writeOutcomeStats(w, "CHANNEL", report.ByChannel)
+	if len(report.ByTemplate) > 0 {
+		_, _ = fmt.Fprintln(w)
+		writeOutcomeStats(w, "TEMPLATE", report.ByTemplate)
+	}
+	return w.Flush()
+}
+
+func writeOutcomeStats(w io.Writer, heading string, stats []*charm.OutcomeStats) {
+	_, _ = fmt.Fprintf(w, "%s\tATTEMPTS\tREPLIED\tMEETINGS\tNO ANSWER\tRESPONSE RATE\n", heading)
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.0f%%\n",
+			s.Name, s.Attempts, s.Replied, s.MeetingsBooked, s.NoAnswer, s.ResponseRate()*100)
+	}
 }
 
 // LogInteractionCommand logs an interaction with a contact.
 func LogInteractionCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	fs := flag.NewFlagSet("log", flagErrorHandling)
 	contactIDStr := fs.String("contact", "", "Contact ID or name (required)")
 	interactionType := fs.String("type", "meeting", "Interaction type (meeting/call/email/message/event)")
 	notes := fs.String("notes", "", "Notes about the interaction")
 	sentiment := fs.String("sentiment", "", "Sentiment (positive/neutral/negative)")
+	location := fs.String("location", "", "Where the meeting happened (address or venue, city)")
+	outcome := fs.String("outcome", "", "How it went (replied/no_answer/meeting_booked)")
+	nextStep := fs.String("next-step", "", "What happens next")
+	template := fs.String("template", "", "Outreach template this follows up (default: the contact's latest outreach email)")
+	audio := fs.String("audio", "", "Voice memo to transcribe into the notes (needs transcription in charm-config.json)")
 	_ = fs.Parse(args)
 
 	if *contactIDStr == "" {
-		return fmt.Errorf("--contact is required")
+		return charm.Validationf("--contact is required")
+	}
+
+	contactID, err := resolveID(client, *contactIDStr, charm.EntityContact)
+	if err != nil {
+		return err
 	}
 
-	// Try to parse as UUID, otherwise search by name
-	var contactID uuid.UUID
-	parsedID, err := uuid.Parse(*contactIDStr)
-	if err == nil {
-		contactID = parsedID
-	} else {
-		// Search by name
-		contacts, err := client.ListContacts(&charm.ContactFilter{Query: *contactIDStr, Limit: 10})
+	if *audio != "" {
+		transcript, err := transcribeMemo(*audio)
 		if err != nil {
-			return fmt.Errorf("failed to find contact: %w", err)
+			return err
 		}
-		if len(contacts) == 0 {
-			return fmt.Errorf("no contact found matching: %s", *contactIDStr)
-		}
-		if len(contacts) > 1 {
-			return fmt.Errorf("multiple contacts found, please use ID")
-		}
-		contactID = contacts[0].ID
+		*notes = strings.TrimSpace(strings.Join([]string{*notes, transcript}, "\n\n"))
 	}
 
 	timestamp := time.Now()
@@ -148,13 +212,17 @@ func LogInteractionCommand(client *charm.Client, args []string) error {
 		InteractionType: *interactionType,
 		Timestamp:       timestamp,
 		Notes:           *notes,
+		Location:        *location,
+		Outcome:         *outcome,
+		NextStep:        *nextStep,
+		Template:        *template,
 	}
 
 	if *sentiment != "" {
 		interaction.Sentiment = sentiment
 	}
 
-	if err := client.CreateInteractionLog(interaction); err != nil {
+	if err := client.LogFollowup(interaction); err != nil {
 		return fmt.Errorf("failed to log interaction: %w", err)
 	}
 
@@ -174,35 +242,63 @@ func LogInteractionCommand(client *charm.Client, args []string) error {
 	}
 
 	fmt.Printf("✓ Logged %s interaction with contact\n", *interactionType)
+	if interaction.Outcome != "" {
+		fmt.Printf("  Outcome: %s", strings.ReplaceAll(interaction.Outcome, "_", " "))
+		if interaction.Template != "" {
+			fmt.Printf(" (template: %s)", interaction.Template)
+		}
+		fmt.Println()
+	}
+	if interaction.NextStep != "" {
+		fmt.Printf("  Next step: %s\n", interaction.NextStep)
+	}
 	return nil
 }
 
+// transcribeMemo turns a voice memo into text with the configured transcriber.
+func transcribeMemo(path string) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	transcriber, err := sync.NewTranscriber(cfg.Transcription)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Transcribing %s...\n", filepath.Base(path))
+	ctx, cancel := context.WithTimeout(context.Background(), sync.TranscribeTimeout)
+	defer cancel()
+	transcript, err := transcriber.Transcribe(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if transcript == "" {
+		return "", fmt.Errorf("transcription of %s came back empty", path)
+	}
+	fmt.Printf("✓ Transcribed %d words\n", len(strings.Fields(transcript)))
+	return transcript, nil
+}
+
 // SetCadenceCommand sets the follow-up cadence for a contact.
 func SetCadenceCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("set-cadence", flag.ExitOnError)
+	fs := flag.NewFlagSet("set-cadence", flagErrorHandling)
 	contactIDStr := fs.String("contact", "", "Contact ID or name (required)")
 	days := fs.Int("days", 30, "Cadence in days")
 	strength := fs.String("strength", "medium", "Relationship strength (weak/medium/strong)")
 	_ = fs.Parse(args)
 
 	if *contactIDStr == "" {
-		return fmt.Errorf("--contact is required")
+		return charm.Validationf("--contact is required")
 	}
 
 	// Resolve contact ID
-	var contactID uuid.UUID
-	parsedID, err := uuid.Parse(*contactIDStr)
-	if err == nil {
-		contactID = parsedID
-	} else {
-		contacts, err := client.ListContacts(&charm.ContactFilter{Query: *contactIDStr, Limit: 10})
-		if err != nil {
-			return fmt.Errorf("failed to find contact: %w", err)
-		}
-		if len(contacts) == 0 {
-			return fmt.Errorf("no contact found matching: %s", *contactIDStr)
-		}
-		contactID = contacts[0].ID
+	contactID, err := resolveID(client, *contactIDStr, charm.EntityContact)
+	if err != nil {
+		return err
 	}
 
 	// Get or create cadence
@@ -221,26 +317,8 @@ func SetCadenceCommand(client *charm.Client, args []string) error {
 	cadence.RelationshipStrength = *strength
 
 	// Compute priority score
+	cadence.PriorityScore = charm.ComputePriorityScore(cadence, time.Now())
 	if cadence.LastInteractionDate != nil {
-		daysSinceContact := int(time.Since(*cadence.LastInteractionDate).Hours() / 24)
-		daysOverdue := daysSinceContact - cadence.CadenceDays
-
-		if daysOverdue <= 0 {
-			cadence.PriorityScore = 0.0
-		} else {
-			baseScore := float64(daysOverdue * 2)
-			multiplier := 1.0
-			switch cadence.RelationshipStrength {
-			case charm.StrengthStrong:
-				multiplier = 2.0
-			case charm.StrengthMedium:
-				multiplier = 1.5
-			case charm.StrengthWeak:
-				multiplier = 1.0
-			}
-			cadence.PriorityScore = baseScore * multiplier
-		}
-
 		// Update next followup
 		next := cadence.LastInteractionDate.AddDate(0, 0, cadence.CadenceDays)
 		cadence.NextFollowupDate = &next
@@ -254,37 +332,131 @@ func SetCadenceCommand(client *charm.Client, args []string) error {
 	return nil
 }
 
-// DigestCommand generates a daily follow-up digest.
-func DigestCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("digest", flag.ExitOnError)
-	format := fs.String("format", "text", "Output format (text/json/html)")
+// RecomputePrioritiesCommand recomputes engagement and priority scores for all
+// cadences. The background jobs run this daily (see RunBackgroundJobs).
+func RecomputePrioritiesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("recompute", flagErrorHandling)
 	_ = fs.Parse(args)
 
-	followups, err := client.GetFollowupList(50)
+	updated, err := client.RecomputePriorityScores(time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to get followup list: %w", err)
+		return fmt.Errorf("failed to recompute priorities: %w", err)
+	}
+	fmt.Printf("✓ Recomputed priority for %d contacts\n", updated)
+	return nil
+}
+
+// DigestCommand generates a daily follow-up digest, printed, written to a file, or emailed.
+func DigestCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("digest", flagErrorHandling)
+	format := fs.String("format", "text", "Output format (text/json/md/html)")
+	output := fs.String("output", "", "Write the digest to this file instead of stdout")
+	email := fs.Bool("email", false, "Email the digest as HTML instead of printing it")
+	to := fs.String("to", "", "Comma-separated recipients for --email (default: PAGEN_DIGEST_TO, else yourself)")
+	via := fs.String("via", "", "Send --email with smtp or gmail (default: smtp when PAGEN_SMTP_HOST is set)")
+	_ = fs.Parse(args)
+
+	if *email && *output != "" {
+		return fmt.Errorf("--email and --output can't be used together")
+	}
+	if *via != "" && *via != digestViaSMTP && *via != digestViaGmail {
+		return fmt.Errorf("unsupported --via: %s (use smtp or gmail)", *via)
+	}
+
+	// A .md or .html output file picks its format unless --format is given
+	formatSet := false
+	fs.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+	if *output != "" && !formatSet {
+		switch strings.ToLower(filepath.Ext(*output)) {
+		case ".md", ".markdown":
+			*format = "md"
+		case ".html", ".htm":
+			*format = "html"
+		case ".json":
+			*format = "json"
+		}
 	}
 
+	var write func(io.Writer, *followupDigest) error
 	switch *format {
 	case "text":
-		return printTextDigest(followups)
+		write = writeTextDigest
 	case "json":
-		return printJSONDigest(followups)
+		write = writeJSONDigest
+	case "md", "markdown":
+		write = writeMarkdownDigest
 	case "html":
-		return printHTMLDigest(followups)
+		write = writeHTMLDigest
+	default:
+		return fmt.Errorf("unsupported format: %s", *format)
+	}
+
+	d := &followupDigest{Date: time.Now()}
+	var err error
+	if d.Followups, err = client.GetDigest(50); err != nil {
+		return fmt.Errorf("failed to get followup list: %w", err)
+	}
+	if d.Cold, err = client.GetGoingColdList(20); err != nil {
+		return fmt.Errorf("failed to forecast going cold: %w", err)
+	}
+	// A few stale relationships a day keeps the re-checking manageable
+	if d.Revalidate, err = client.GetRevalidationPrompts(5); err != nil {
+		return fmt.Errorf("failed to list stale relationships: %w", err)
+	}
+	if cfg := client.Config(); cfg != nil {
+		if d.Goals, err = client.GoalProgress(cfg.OutreachGoals, d.Date); err != nil {
+			return err
+		}
+	}
+	if d.Met, err = client.MetReminders(d.Date, 1); err != nil {
+		return fmt.Errorf("failed to list met anniversaries: %w", err)
+	}
+
+	if *email {
+		var text, body bytes.Buffer
+		if err := writeTextDigest(&text, d); err != nil {
+			return err
+		}
+		if err := writeHTMLDigest(&body, d); err != nil {
+			return err
+		}
+		overdue, dueSoon := splitDigest(d.Followups)
+		subject := fmt.Sprintf("Follow-ups for %s: %d overdue, %d due soon", d.Date.Format("2006-01-02"), len(overdue), len(dueSoon))
+		sentTo, err := emailDigest(*via, *to, subject, text.Bytes(), body.Bytes())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Digest emailed to %s\n", sentTo)
+		return nil
 	}
 
-	return fmt.Errorf("unsupported format: %s", *format)
+	if *output == "" {
+		return write(os.Stdout, d)
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, d); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write digest: %w", err)
+	}
+	fmt.Printf("✓ Digest written to %s\n", *output)
+	return nil
 }
 
-func printTextDigest(followups []*charm.FollowupContact) error {
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("  FOLLOW-UPS FOR %s\n", time.Now().Format("2006-01-02"))
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
+// followupDigest is one day's digest, the sections every digest format writes.
+type followupDigest struct {
+	Date       time.Time
+	Followups  []*charm.DigestItem
+	Cold       []*charm.ColdForecast
+	Revalidate []*charm.RevalidationPrompt
+	Goals      []*charm.GoalProgress
+	Met        []*charm.Greeting
+}
 
-	// Split into categories
-	var overdue, dueSoon []*charm.FollowupContact
+// splitDigest groups follow-ups into overdue and due soon, keeping their rank.
+func splitDigest(followups []*charm.DigestItem) (overdue, dueSoon []*charm.DigestItem) {
 	for _, f := range followups {
 		if f.DaysSinceContact > f.CadenceDays+7 {
 			overdue = append(overdue, f)
@@ -292,50 +464,315 @@ func printTextDigest(followups []*charm.FollowupContact) error {
 			dueSoon = append(dueSoon, f)
 		}
 	}
+	return overdue, dueSoon
+}
+
+// digestReasons joins an item's reasons for display.
+func digestReasons(f *charm.DigestItem) string {
+	return strings.Join(f.Reasons, ", ")
+}
+
+func writeTextDigest(w io.Writer, d *followupDigest) error {
+	_, _ = fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	_, _ = fmt.Fprintf(w, "  FOLLOW-UPS FOR %s\n", d.Date.Format("2006-01-02"))
+	_, _ = fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	_, _ = fmt.Fprintln(w)
+
+	overdue, dueSoon := splitDigest(d.Followups)
 
 	if len(overdue) > 0 {
-		fmt.Printf("🔴 OVERDUE (%d contacts)\n", len(overdue))
+		_, _ = fmt.Fprintf(w, "🔴 OVERDUE (%d contacts)\n", len(overdue))
 		for _, f := range overdue {
-			fmt.Printf("  %-20s  %3d days  (priority: %.0f)\n", f.Name, f.DaysSinceContact, f.PriorityScore)
+			_, _ = fmt.Fprintf(w, "  %-20s  %3d days  (score: %.0f)  %s\n", f.Name, f.DaysSinceContact, f.Score, digestReasons(f))
 		}
-		fmt.Println()
+		_, _ = fmt.Fprintln(w)
 	}
 
 	if len(dueSoon) > 0 {
-		fmt.Printf("🟡 DUE SOON (%d contacts)\n", len(dueSoon))
+		_, _ = fmt.Fprintf(w, "🟡 DUE SOON (%d contacts)\n", len(dueSoon))
 		for _, f := range dueSoon {
-			fmt.Printf("  %-20s  %3d days  (priority: %.0f)\n", f.Name, f.DaysSinceContact, f.PriorityScore)
+			_, _ = fmt.Fprintf(w, "  %-20s  %3d days  (score: %.0f)  %s\n", f.Name, f.DaysSinceContact, f.Score, digestReasons(f))
 		}
-		fmt.Println()
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(d.Cold) > 0 {
+		_, _ = fmt.Fprintf(w, "🧊 GOING COLD (%d contacts)\n", len(d.Cold))
+		for _, f := range d.Cold {
+			_, _ = fmt.Fprintf(w, "  %-20s  gaps ~%.0f → ~%.0f days, cadence %d  (due %s)\n",
+				f.Name, f.RecentGapDays, f.ProjectedGapDays, f.CadenceDays, f.DueDate.Format("2006-01-02"))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(d.Revalidate) > 0 {
+		_, _ = fmt.Fprintf(w, "🤝 STILL IN TOUCH? (%d relationships)\n", len(d.Revalidate))
+		for _, p := range d.Revalidate {
+			_, _ = fmt.Fprintf(w, "  %s  %s  (%s)\n", p.Question(), revalidationDetail(p), charm.FormatID(p.ID))
+		}
+		_, _ = fmt.Fprintln(w, "  Confirm with: pagen crm update-relationship --validate <id>")
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if nudges := goalNudges(d.Goals, d.Date); len(nudges) > 0 {
+		_, _ = fmt.Fprintln(w, "🎯 OUTREACH GOALS")
+		for _, nudge := range nudges {
+			_, _ = fmt.Fprintf(w, "  %s\n", nudge)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(d.Met) > 0 {
+		_, _ = fmt.Fprintf(w, "📅 MET ON THIS DAY (%d contacts)\n", len(d.Met))
+		for _, g := range d.Met {
+			_, _ = fmt.Fprintf(w, "  %-20s  %s\n", g.Contact.Name, metReminderDetail(g))
+		}
+		_, _ = fmt.Fprintln(w)
 	}
 
 	return nil
 }
 
-func printJSONDigest(followups []*charm.FollowupContact) error {
+// metReminderDetail describes a met anniversary, with where you met when known.
+func metReminderDetail(g *charm.Greeting) string {
+	detail := strings.ToLower(g.Occasion)
+	if g.Contact.MetAt != "" {
+		detail += " at " + g.Contact.MetAt
+	}
+	return detail
+}
+
+// goalNudges returns a gentle nudge for each outreach goal that's behind pace.
+func goalNudges(goals []*charm.GoalProgress, now time.Time) []string {
+	var nudges []string
+	for _, goal := range goals {
+		if nudge := goal.Nudge(now); nudge != "" {
+			nudges = append(nudges, nudge)
+		}
+	}
+	return nudges
+}
+
+// revalidationDetail describes a stale relationship: its type, how its
+// strength has faded, and how long it has gone unconfirmed.
+func revalidationDetail(p *charm.RevalidationPrompt) string {
+	var parts []string
+	if p.RelationshipType != "" {
+		parts = append(parts, p.RelationshipType)
+	}
+	if p.Strength != "" {
+		if p.CurrentStrength != p.Strength {
+			parts = append(parts, p.Strength+" → "+p.CurrentStrength)
+		} else {
+			parts = append(parts, p.Strength)
+		}
+	}
+	parts = append(parts, fmt.Sprintf("unconfirmed %d days", p.DaysUnconfirmed))
+	return strings.Join(parts, ", ")
+}
+
+func writeJSONDigest(w io.Writer, d *followupDigest) error {
 	// Simple JSON output for webhook integration
-	fmt.Printf("{\"date\":\"%s\",\"followups\":[", time.Now().Format("2006-01-02"))
-	for i, f := range followups {
-		if i > 0 {
-			fmt.Print(",")
+	type digestEntry struct {
+		Name     string   `json:"name"`
+		Days     int      `json:"days"`
+		Priority float64  `json:"priority"`
+		Score    float64  `json:"score"`
+		Reasons  []string `json:"reasons"`
+	}
+	type coldEntry struct {
+		Name         string  `json:"name"`
+		Days         int     `json:"days"`
+		RecentGap    float64 `json:"recent_gap_days"`
+		ProjectedGap float64 `json:"projected_gap_days"`
+		CadenceDays  int     `json:"cadence_days"`
+		DueDate      string  `json:"due_date"`
+	}
+	type revalidateEntry struct {
+		RelationshipID  string `json:"relationship_id"`
+		Question        string `json:"question"`
+		Type            string `json:"relationship_type,omitempty"`
+		Strength        string `json:"strength,omitempty"`
+		CurrentStrength string `json:"current_strength,omitempty"`
+		DaysUnconfirmed int    `json:"days_unconfirmed"`
+	}
+	type metEntry struct {
+		Name  string `json:"name"`
+		Years int    `json:"years"`
+		MetAt string `json:"met_at,omitempty"`
+	}
+	type goalEntry struct {
+		Tag     string `json:"tag"`
+		Target  int    `json:"target"`
+		Touched int    `json:"touched"`
+		Behind  bool   `json:"behind"`
+		Nudge   string `json:"nudge,omitempty"`
+	}
+	digest := struct {
+		Date       string            `json:"date"`
+		Followups  []digestEntry     `json:"followups"`
+		GoingCold  []coldEntry       `json:"going_cold"`
+		Revalidate []revalidateEntry `json:"still_in_touch"`
+		Goals      []goalEntry       `json:"goals"`
+		Met        []metEntry        `json:"met_anniversaries"`
+	}{Date: d.Date.Format("2006-01-02"), Followups: []digestEntry{}, GoingCold: []coldEntry{}, Revalidate: []revalidateEntry{}, Goals: []goalEntry{}, Met: []metEntry{}}
+	for _, f := range d.Followups {
+		digest.Followups = append(digest.Followups, digestEntry{
+			Name:     f.Name,
+			Days:     f.DaysSinceContact,
+			Priority: math.Round(f.PriorityScore*10) / 10,
+			Score:    math.Round(f.Score*10) / 10,
+			Reasons:  f.Reasons,
+		})
+	}
+	for _, f := range d.Cold {
+		digest.GoingCold = append(digest.GoingCold, coldEntry{
+			Name:         f.Name,
+			Days:         f.DaysSinceContact,
+			RecentGap:    math.Round(f.RecentGapDays*10) / 10,
+			ProjectedGap: math.Round(f.ProjectedGapDays*10) / 10,
+			CadenceDays:  f.CadenceDays,
+			DueDate:      f.DueDate.Format("2006-01-02"),
+		})
+	}
+	for _, p := range d.Revalidate {
+		digest.Revalidate = append(digest.Revalidate, revalidateEntry{
+			RelationshipID:  p.ID.String(),
+			Question:        p.Question(),
+			Type:            p.RelationshipType,
+			Strength:        p.Strength,
+			CurrentStrength: p.CurrentStrength,
+			DaysUnconfirmed: p.DaysUnconfirmed,
+		})
+	}
+	for _, goal := range d.Goals {
+		digest.Goals = append(digest.Goals, goalEntry{
+			Tag:     goal.Tag,
+			Target:  goal.Target,
+			Touched: goal.Touched,
+			Behind:  !goal.Met() && goal.Behind(d.Date),
+			Nudge:   goal.Nudge(d.Date),
+		})
+	}
+	for _, g := range d.Met {
+		digest.Met = append(digest.Met, metEntry{Name: g.Contact.Name, Years: g.Years, MetAt: g.Contact.MetAt})
+	}
+	return json.NewEncoder(w).Encode(digest)
+}
+
+func writeMarkdownDigest(w io.Writer, d *followupDigest) error {
+	_, _ = fmt.Fprintf(w, "# Follow-Ups for %s\n", d.Date.Format("2006-01-02"))
+
+	overdue, dueSoon := splitDigest(d.Followups)
+	nudges := goalNudges(d.Goals, d.Date)
+	if len(overdue) == 0 && len(dueSoon) == 0 && len(d.Cold) == 0 && len(d.Revalidate) == 0 && len(nudges) == 0 && len(d.Met) == 0 {
+		_, _ = fmt.Fprintln(w, "\nNo follow-ups due.")
+		return nil
+	}
+
+	for _, section := range []struct {
+		title     string
+		followups []*charm.DigestItem
+	}{
+		{"Overdue", overdue},
+		{"Due Soon", dueSoon},
+	} {
+		if len(section.followups) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n## %s (%d)\n\n", section.title, len(section.followups))
+		_, _ = fmt.Fprintln(w, "| Name | Days Since | Score | Why |")
+		_, _ = fmt.Fprintln(w, "| --- | ---: | ---: | --- |")
+		for _, f := range section.followups {
+			_, _ = fmt.Fprintf(w, "| %s | %d | %.0f | %s |\n", markdownCell(f.Name), f.DaysSinceContact, f.Score, markdownCell(digestReasons(f)))
+		}
+	}
+
+	if len(d.Cold) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Going Cold (%d)\n\n", len(d.Cold))
+		_, _ = fmt.Fprintln(w, "| Name | Days Since | Recent Gap | Projected Gap | Cadence | Due |")
+		_, _ = fmt.Fprintln(w, "| --- | ---: | ---: | ---: | ---: | --- |")
+		for _, f := range d.Cold {
+			_, _ = fmt.Fprintf(w, "| %s | %d | %.0f | %.0f | %d | %s |\n", markdownCell(f.Name), f.DaysSinceContact,
+				f.RecentGapDays, f.ProjectedGapDays, f.CadenceDays, f.DueDate.Format("2006-01-02"))
+		}
+	}
+
+	if len(d.Revalidate) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Still in Touch? (%d)\n\n", len(d.Revalidate))
+		_, _ = fmt.Fprintln(w, "| Question | Details | ID |")
+		_, _ = fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, p := range d.Revalidate {
+			_, _ = fmt.Fprintf(w, "| %s | %s | %s |\n", markdownCell(p.Question()), markdownCell(revalidationDetail(p)), charm.FormatID(p.ID))
+		}
+	}
+
+	if len(nudges) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Outreach Goals\n\n")
+		for _, nudge := range nudges {
+			_, _ = fmt.Fprintf(w, "- %s\n", nudge)
+		}
+	}
+
+	if len(d.Met) > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Met on This Day (%d)\n\n", len(d.Met))
+		for _, g := range d.Met {
+			_, _ = fmt.Fprintf(w, "- %s: %s\n", g.Contact.Name, metReminderDetail(g))
 		}
-		fmt.Printf("{\"name\":\"%s\",\"days\":%d,\"priority\":%.1f}",
-			f.Name, f.DaysSinceContact, f.PriorityScore)
 	}
-	fmt.Println("]}")
 	return nil
 }
 
-func printHTMLDigest(followups []*charm.FollowupContact) error {
-	fmt.Println("<html><body>")
-	fmt.Printf("<h1>Follow-Ups for %s</h1>\n", time.Now().Format("2006-01-02"))
-	fmt.Println("<table border='1'>")
-	fmt.Println("<tr><th>Name</th><th>Days Since</th><th>Priority</th></tr>")
-	for _, f := range followups {
-		fmt.Printf("<tr><td>%s</td><td>%d</td><td>%.1f</td></tr>\n",
-			f.Name, f.DaysSinceContact, f.PriorityScore)
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+func writeHTMLDigest(w io.Writer, d *followupDigest) error {
+	_, _ = fmt.Fprintln(w, "<html><body>")
+	_, _ = fmt.Fprintf(w, "<h1>Follow-Ups for %s</h1>\n", d.Date.Format("2006-01-02"))
+	_, _ = fmt.Fprintln(w, "<table border='1'>")
+	_, _ = fmt.Fprintln(w, "<tr><th>Name</th><th>Days Since</th><th>Score</th><th>Why</th></tr>")
+	for _, f := range d.Followups {
+		_, _ = fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%.1f</td><td>%s</td></tr>\n",
+			html.EscapeString(f.Name), f.DaysSinceContact, f.Score, html.EscapeString(digestReasons(f)))
+	}
+	_, _ = fmt.Fprintln(w, "</table>")
+	if len(d.Cold) > 0 {
+		_, _ = fmt.Fprintln(w, "<h2>Going Cold</h2>")
+		_, _ = fmt.Fprintln(w, "<table border='1'>")
+		_, _ = fmt.Fprintln(w, "<tr><th>Name</th><th>Days Since</th><th>Recent Gap</th><th>Projected Gap</th><th>Cadence</th><th>Due</th></tr>")
+		for _, f := range d.Cold {
+			_, _ = fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%.0f</td><td>%.0f</td><td>%d</td><td>%s</td></tr>\n",
+				html.EscapeString(f.Name), f.DaysSinceContact, f.RecentGapDays, f.ProjectedGapDays, f.CadenceDays, f.DueDate.Format("2006-01-02"))
+		}
+		_, _ = fmt.Fprintln(w, "</table>")
+	}
+	if len(d.Revalidate) > 0 {
+		_, _ = fmt.Fprintln(w, "<h2>Still in Touch?</h2>")
+		_, _ = fmt.Fprintln(w, "<ul>")
+		for _, p := range d.Revalidate {
+			_, _ = fmt.Fprintf(w, "<li>%s %s (%s)</li>\n",
+				html.EscapeString(p.Question()), html.EscapeString(revalidationDetail(p)), charm.FormatID(p.ID))
+		}
+		_, _ = fmt.Fprintln(w, "</ul>")
+	}
+	if nudges := goalNudges(d.Goals, d.Date); len(nudges) > 0 {
+		_, _ = fmt.Fprintln(w, "<h2>Outreach Goals</h2>")
+		_, _ = fmt.Fprintln(w, "<ul>")
+		for _, nudge := range nudges {
+			_, _ = fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(nudge))
+		}
+		_, _ = fmt.Fprintln(w, "</ul>")
+	}
+	if len(d.Met) > 0 {
+		_, _ = fmt.Fprintln(w, "<h2>Met on This Day</h2>")
+		_, _ = fmt.Fprintln(w, "<ul>")
+		for _, g := range d.Met {
+			_, _ = fmt.Fprintf(w, "<li>%s: %s</li>\n", html.EscapeString(g.Contact.Name), html.EscapeString(metReminderDetail(g)))
+		}
+		_, _ = fmt.Fprintln(w, "</ul>")
 	}
-	fmt.Println("</table>")
-	fmt.Println("</body></html>")
+	_, _ = fmt.Fprintln(w, "</body></html>")
 	return nil
 }
@@ -1,9 +1,14 @@
 // ABOUTME: Tests for followup CLI commands
-// ABOUTME: Validates followup list and interaction logging commands
+// ABOUTME: Validates followup list, interaction logging, scheduling, and digest export commands
 package cli
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
@@ -53,3 +58,208 @@ func TestLogInteractionCommand(t *testing.T) {
 		t.Errorf("expected 1 interaction, got %d", len(logs))
 	}
 }
+
+func TestWriteMarkdownDigest(t *testing.T) {
+	followups := []*charm.DigestItem{
+		{
+			FollowupContact: &charm.FollowupContact{Name: "Alice | Co", DaysSinceContact: 40, CadenceDays: 30, PriorityScore: 6},
+			Score:           12,
+			Reasons:         []string{"VIP", "10 days overdue"},
+		},
+		{FollowupContact: &charm.FollowupContact{Name: "Bob", DaysSinceContact: 28, CadenceDays: 30}, Score: 3, Reasons: []string{}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMarkdownDigest(&buf, &followupDigest{Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Followups: followups}); err != nil {
+		t.Fatalf("writeMarkdownDigest failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"# Follow-Ups for 2024-03-02",
+		"## Overdue (1)",
+		"| Alice \\| Co | 40 | 12 | VIP, 10 days overdue |",
+		"## Due Soon (1)",
+		"| Bob | 28 | 3 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in digest:\n%s", want, out)
+		}
+	}
+}
+
+func TestDigestCommandOutputFile(t *testing.T) {
+	client := charm.NewTestClient(t)
+	dir := t.TempDir()
+
+	// The format follows the file extension unless --format is given
+	mdPath := filepath.Join(dir, "digest.md")
+	if err := DigestCommand(client, []string{"--output", mdPath}); err != nil {
+		t.Fatalf("DigestCommand failed: %v", err)
+	}
+	data, err := os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatalf("failed to read digest: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Follow-Ups for ") {
+		t.Errorf("expected markdown digest, got:\n%s", data)
+	}
+
+	htmlPath := filepath.Join(dir, "digest.txt")
+	if err := DigestCommand(client, []string{"--output", htmlPath, "--format", "html"}); err != nil {
+		t.Fatalf("DigestCommand failed: %v", err)
+	}
+	data, err = os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("failed to read digest: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "<html>") {
+		t.Errorf("expected html digest, got:\n%s", data)
+	}
+
+	if err := DigestCommand(client, []string{"--format", "pdf"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestWriteDigestGoingCold(t *testing.T) {
+	cold := []*charm.ColdForecast{
+		{Name: "Carol", DaysSinceContact: 12, CadenceDays: 30, RecentGapDays: 22.5, ProjectedGapDays: 35, DueDate: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	date := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	var md bytes.Buffer
+	if err := writeMarkdownDigest(&md, &followupDigest{Date: date, Cold: cold}); err != nil {
+		t.Fatalf("writeMarkdownDigest failed: %v", err)
+	}
+	for _, want := range []string{"## Going Cold (1)", "| Carol | 12 | 22 | 35 | 30 | 2024-03-20 |"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("expected %q in digest:\n%s", want, md.String())
+		}
+	}
+
+	var text bytes.Buffer
+	if err := writeTextDigest(&text, &followupDigest{Date: date, Cold: cold}); err != nil {
+		t.Fatalf("writeTextDigest failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "GOING COLD (1 contacts)") {
+		t.Errorf("expected a going cold section:\n%s", text.String())
+	}
+}
+
+func TestWriteDigestRevalidation(t *testing.T) {
+	revalidate := []*charm.RevalidationPrompt{{
+		Relationship:    &charm.Relationship{ID: uuid.New(), Contact1Name: "Ada", Contact2Name: "Grace", RelationshipType: "colleague", Strength: charm.StrengthStrong},
+		DaysUnconfirmed: 200,
+		CurrentStrength: charm.StrengthMedium,
+	}}
+	date := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	var text bytes.Buffer
+	if err := writeTextDigest(&text, &followupDigest{Date: date, Revalidate: revalidate}); err != nil {
+		t.Fatalf("writeTextDigest failed: %v", err)
+	}
+	for _, want := range []string{"STILL IN TOUCH? (1 relationships)", "Are Ada and Grace still in touch?", "colleague, strong → medium, unconfirmed 200 days"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("expected %q in digest:\n%s", want, text.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := writeMarkdownDigest(&md, &followupDigest{Date: date, Revalidate: revalidate}); err != nil {
+		t.Fatalf("writeMarkdownDigest failed: %v", err)
+	}
+	if !strings.Contains(md.String(), "## Still in Touch? (1)") {
+		t.Errorf("expected a still in touch section:\n%s", md.String())
+	}
+}
+
+func TestWriteDigestGoals(t *testing.T) {
+	// Thursday, so an even pace is 3 of 7 days in
+	date := time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)
+	goals := []*charm.GoalProgress{
+		{Tag: "vip", Target: 7, Touched: 1, WeekStart: charm.WeekStartOf(date), Suggestions: []string{"Ada", "Grace"}},
+		{Tag: "investors", Target: 2, Touched: 1, WeekStart: charm.WeekStartOf(date)},
+	}
+
+	var text bytes.Buffer
+	if err := writeTextDigest(&text, &followupDigest{Date: date, Goals: goals}); err != nil {
+		t.Fatalf("writeTextDigest failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "1 of 7 vip contacts so far this week; 6 more to go (maybe Ada, Grace?)") {
+		t.Errorf("expected a nudge for the vip goal:\n%s", text.String())
+	}
+	if strings.Contains(text.String(), "investors") {
+		t.Errorf("expected no nudge for a goal on pace:\n%s", text.String())
+	}
+
+	var md bytes.Buffer
+	if err := writeMarkdownDigest(&md, &followupDigest{Date: date, Goals: goals}); err != nil {
+		t.Fatalf("writeMarkdownDigest failed: %v", err)
+	}
+	if !strings.Contains(md.String(), "## Outreach Goals") || strings.Contains(md.String(), "No follow-ups due") {
+		t.Errorf("expected an outreach goals section:\n%s", md.String())
+	}
+}
+
+func TestWriteDigestMetAnniversaries(t *testing.T) {
+	date := time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)
+	met := []*charm.Greeting{
+		{Kind: charm.GreetingMet, Contact: &charm.Contact{Name: "Ada", MetAt: "PyCon"}, Date: date, Occasion: "Met 1 year ago", Years: 1},
+	}
+
+	var text bytes.Buffer
+	if err := writeTextDigest(&text, &followupDigest{Date: date, Met: met}); err != nil {
+		t.Fatalf("writeTextDigest failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "MET ON THIS DAY") || !strings.Contains(text.String(), "met 1 year ago at PyCon") {
+		t.Errorf("expected a met anniversary:\n%s", text.String())
+	}
+
+	var md bytes.Buffer
+	if err := writeMarkdownDigest(&md, &followupDigest{Date: date, Met: met}); err != nil {
+		t.Fatalf("writeMarkdownDigest failed: %v", err)
+	}
+	if !strings.Contains(md.String(), "- Ada: met 1 year ago at PyCon") || strings.Contains(md.String(), "No follow-ups due") {
+		t.Errorf("expected a met on this day section:\n%s", md.String())
+	}
+}
+
+func TestScheduleFollowupCommand(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Dana"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	if err := client.SaveContactCadence(&charm.ContactCadence{ContactID: contact.ID, CadenceDays: 30, RelationshipStrength: charm.StrengthMedium}); err != nil {
+		t.Fatalf("SaveContactCadence failed: %v", err)
+	}
+
+	if err := ScheduleFollowupCommand(client, []string{"--contact", "Dana", "--at", "2024-03-07 10:00"}); err != nil {
+		t.Fatalf("ScheduleFollowupCommand failed: %v", err)
+	}
+
+	tasks, err := client.ListTasks(false)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	want := time.Date(2024, 3, 7, 10, 0, 0, 0, time.Local)
+	if len(tasks) != 1 || tasks[0].Title != "Catch up with Dana" || !tasks[0].DueAt.Equal(want) {
+		t.Fatalf("expected a catch-up task at %s, got %+v", want, tasks)
+	}
+	cadence, err := client.GetContactCadence(contact.ID)
+	if err != nil || cadence.NextFollowupDate == nil || !cadence.NextFollowupDate.Equal(want) {
+		t.Errorf("expected next follow-up at %s, got %+v (%v)", want, cadence, err)
+	}
+
+	// The scheduled catch-up blocks its slot; date-only tasks don't
+	busy := scheduledBusy(append(tasks, &charm.Task{Title: "Renewal", DueAt: &time.Time{}}), 30*time.Minute)
+	if len(busy) != 1 || !busy[0].End.Equal(want.Add(30*time.Minute)) {
+		t.Errorf("unexpected busy periods: %+v", busy)
+	}
+
+	if err := ScheduleFollowupCommand(client, []string{"--contact", "Dana", "--at", "thursday"}); err == nil {
+		t.Error("expected error for invalid time")
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Weekly outreach goals CLI command
+// ABOUTME: Sets a per-tag weekly target and shows this week's progress from logged interactions
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// GoalsCommand shows this week's progress on outreach goals, or sets or
+// removes the goal for a tag.
+func GoalsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("goals", flagErrorHandling)
+	tag := fs.String("tag", "", "Contact tag the goal is for")
+	perWeek := fs.Int("per-week", 0, "Distinct contacts with the tag to get in touch with each week")
+	remove := fs.Bool("remove", false, "Remove the goal for --tag")
+	_ = fs.Parse(args)
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if (set["per-week"] || *remove) && *tag == "" {
+		return fmt.Errorf("--tag is required to change a goal")
+	}
+	if set["per-week"] && *remove {
+		return fmt.Errorf("--per-week and --remove are mutually exclusive")
+	}
+	if set["per-week"] && *perWeek <= 0 {
+		return fmt.Errorf("--per-week must be positive")
+	}
+
+	if set["per-week"] || *remove {
+		cfg, err := charm.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		name := charm.NormalizeTag(*tag)
+		if *remove {
+			if _, ok := cfg.OutreachGoals[name]; !ok {
+				return fmt.Errorf("no goal for tag %s", name)
+			}
+			delete(cfg.OutreachGoals, name)
+		} else {
+			if cfg.OutreachGoals == nil {
+				cfg.OutreachGoals = make(map[string]int)
+			}
+			cfg.OutreachGoals[name] = *perWeek
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if *remove {
+			fmt.Printf("✓ Removed the %s goal\n\n", name)
+		} else {
+			fmt.Printf("✓ Goal: %d %s contact(s) a week\n\n", *perWeek, name)
+		}
+		return printGoals(client, cfg.OutreachGoals)
+	}
+
+	var goals map[string]int
+	if cfg := client.Config(); cfg != nil {
+		goals = cfg.OutreachGoals
+	}
+	return printGoals(client, goals)
+}
+
+func printGoals(client *charm.Client, goals map[string]int) error {
+	if len(goals) == 0 {
+		fmt.Println("No outreach goals. Set one with: pagen followups goals --tag vip --per-week 5")
+		return nil
+	}
+	now := time.Now()
+	progress, err := client.GoalProgress(goals, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("WEEKLY OUTREACH GOALS (week of %s)\n", charm.WeekStartOf(now).Format("2006-01-02"))
+	for _, goal := range progress {
+		status := ""
+		switch {
+		case goal.Met():
+			status = "✓"
+		case goal.Behind(now):
+			status = "behind"
+		}
+		fmt.Printf("  %-14s %s  %d/%d  %s\n", goal.Tag, goal.Bar(10), goal.Touched, goal.Target, status)
+		if !goal.Met() && len(goal.Suggestions) > 0 {
+			fmt.Printf("  %-14s Not yet this week: %s\n", "", strings.Join(goal.Suggestions, ", "))
+		}
+	}
+	return nil
+}
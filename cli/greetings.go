@@ -0,0 +1,338 @@
+// ABOUTME: Greeting queue CLI commands
+// ABOUTME: Lists birthdays, anniversaries, and holidays due, and manages holidays, met reminders, and draft templates
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// GreetingsTodayCommand lists the greetings due today, or over the next few days.
+func GreetingsTodayCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("today", flagErrorHandling)
+	days := fs.Int("days", 1, "Number of days to include, starting today")
+	kind := fs.String("kind", "", "Only show birthday, anniversary, met, or holiday greetings")
+	draft := fs.Bool("draft", false, "Print a message draft for each greeting")
+	_ = fs.Parse(args)
+
+	if *kind != "" {
+		if _, ok := charm.DefaultGreetingTemplates[*kind]; !ok {
+			return fmt.Errorf("unknown kind %q (use %s)", *kind, strings.Join(charm.GreetingKinds, ", "))
+		}
+	}
+
+	queue, err := client.GreetingQueue(time.Now(), *days)
+	if err != nil {
+		return fmt.Errorf("failed to build greeting queue: %w", err)
+	}
+
+	var greetings []*charm.Greeting
+	for _, g := range queue {
+		if *kind == "" || g.Kind == *kind {
+			greetings = append(greetings, g)
+		}
+	}
+
+	if len(greetings) == 0 {
+		fmt.Println("No greetings due")
+		return nil
+	}
+
+	if *draft {
+		templates := client.Config().GreetingTemplates
+		for i, g := range greetings {
+			message, err := charm.DraftGreeting(g, templates)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s  %s — %s\n", g.Date.Format("Mon Jan 2"), g.Contact.Name, greetingOccasion(g))
+			if g.Contact.Email != "" {
+				fmt.Printf("To: %s\n", g.Contact.Email)
+			}
+			fmt.Println(message)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tCONTACT\tOCCASION\tEMAIL")
+	_, _ = fmt.Fprintln(w, "----\t-------\t--------\t-----")
+	for _, g := range greetings {
+		email := g.Contact.Email
+		if email == "" {
+			email = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			g.Date.Format("Mon Jan 2"), g.Contact.Name, greetingOccasion(g), email)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nTotal: %d greeting(s)\n", len(greetings))
+	return nil
+}
+
+// greetingOccasion describes a greeting, with the age or years at the company when known.
+func greetingOccasion(g *charm.Greeting) string {
+	switch {
+	case g.Kind == charm.GreetingBirthday && g.Years > 0:
+		return fmt.Sprintf("Birthday (%d)", g.Years)
+	case g.Kind == charm.GreetingAnniversary:
+		return fmt.Sprintf("Work anniversary (%d yr)", g.Years)
+	default:
+		return g.Occasion
+	}
+}
+
+// GreetingsHolidaysCommand lists, adds, or removes greeted holidays.
+func GreetingsHolidaysCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("holidays", flagErrorHandling)
+	add := fs.String("add", "", "Holiday name to add or replace")
+	date := fs.String("date", "", "Holiday date (MM-DD), with --add")
+	countries := fs.String("countries", "", "Comma-separated country codes, with --add (default: every contact)")
+	remove := fs.String("remove", "", "Holiday name to remove")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	switch {
+	case *add != "":
+		if _, _, err := charm.ParseMonthDay(*date); err != nil {
+			return err
+		}
+		holiday := charm.Holiday{Name: *add, Date: *date}
+		for _, c := range strings.Split(*countries, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				holiday.Countries = append(holiday.Countries, strings.ToUpper(c))
+			}
+		}
+
+		holidays := []charm.Holiday{holiday}
+		for _, h := range cfg.Holidays {
+			if !strings.EqualFold(h.Name, *add) {
+				holidays = append(holidays, h)
+			}
+		}
+		cfg.Holidays = holidays
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Holiday saved: %s (%s)\n", holiday.Name, holiday.Date)
+		return nil
+
+	case *remove != "":
+		var holidays []charm.Holiday
+		for _, h := range cfg.Holidays {
+			if !strings.EqualFold(h.Name, *remove) {
+				holidays = append(holidays, h)
+			}
+		}
+		if len(holidays) == len(cfg.Holidays) {
+			return charm.NotFoundf("holiday not found: %s", *remove)
+		}
+		cfg.Holidays = holidays
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Holiday removed: %s\n", *remove)
+		return nil
+	}
+
+	if len(cfg.Holidays) == 0 {
+		fmt.Println("No holidays configured")
+		fmt.Println("Add one with: pagen greetings holidays --add \"New Year's Day\" --date 01-01")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tHOLIDAY\tCOUNTRIES")
+	_, _ = fmt.Fprintln(w, "----\t-------\t---------")
+	for _, h := range cfg.Holidays {
+		countries := strings.Join(h.Countries, ", ")
+		if countries == "" {
+			countries = "all"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", h.Date, h.Name, countries)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// GreetingsMetCommand lists or changes which anniversaries of first meeting
+// a contact are greeted, per tag.
+func GreetingsMetCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("met", flagErrorHandling)
+	tag := fs.String("tag", "", `Tag to set years for, or "*" for contacts without a listed tag`)
+	years := fs.String("years", "", "Comma-separated years after meeting to greet, with --tag (\"none\" turns them off)")
+	remove := fs.String("remove", "", "Tag to stop listing")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	switch {
+	case *tag != "":
+		name := *tag
+		if name != charm.MetAnniversaryEveryone {
+			name = charm.NormalizeTag(name)
+		}
+		if *years == "" {
+			return charm.Validationf("--years is required with --tag")
+		}
+		listed := []int{}
+		if *years != "none" {
+			for _, y := range strings.Split(*years, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(y))
+				if err != nil {
+					return charm.Validationf("invalid year %q in --years", y)
+				}
+				listed = append(listed, n)
+			}
+		}
+		rules := map[string][]int{name: listed}
+		if err := charm.ValidateMetAnniversaries(rules); err != nil {
+			return err
+		}
+		if cfg.MetAnniversaries == nil {
+			cfg.MetAnniversaries = make(map[string][]int)
+			for k, v := range charm.DefaultMetAnniversaries {
+				cfg.MetAnniversaries[k] = v
+			}
+		}
+		cfg.MetAnniversaries[name] = listed
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Met reminders for %s: %s\n", metTagLabel(name), metYearsLabel(listed))
+		return nil
+
+	case *remove != "":
+		name := *remove
+		if name != charm.MetAnniversaryEveryone {
+			name = charm.NormalizeTag(name)
+		}
+		if _, ok := cfg.MetAnniversaries[name]; !ok {
+			return charm.NotFoundf("no met reminders listed for %s", name)
+		}
+		delete(cfg.MetAnniversaries, name)
+		if len(cfg.MetAnniversaries) == 0 {
+			cfg.MetAnniversaries = nil
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Met reminders removed for %s\n", metTagLabel(name))
+		return nil
+	}
+
+	rules := cfg.MetAnniversaries
+	if len(rules) == 0 {
+		rules = charm.DefaultMetAnniversaries
+		fmt.Println("Using the default met reminders:")
+	}
+	tags := make([]string, 0, len(rules))
+	for t := range rules {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TAG\tYEARS AFTER MEETING")
+	_, _ = fmt.Fprintln(w, "---\t-------------------")
+	for _, t := range tags {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", metTagLabel(t), metYearsLabel(rules[t]))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// metTagLabel names a met anniversaries key for display.
+func metTagLabel(tag string) string {
+	if tag == charm.MetAnniversaryEveryone {
+		return "* (everyone else)"
+	}
+	return tag
+}
+
+// metYearsLabel lists years for display, or "off" when there are none.
+func metYearsLabel(years []int) string {
+	if len(years) == 0 {
+		return "off"
+	}
+	parts := make([]string, len(years))
+	for i, y := range years {
+		parts[i] = strconv.Itoa(y)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GreetingsTemplateCommand shows or changes the message draft templates.
+func GreetingsTemplateCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("template", flagErrorHandling)
+	kind := fs.String("kind", "", "Greeting kind: "+strings.Join(charm.GreetingKinds, ", "))
+	text := fs.String("text", "", "Template text (Go template: .Name .FirstName .Company .Occasion .Years .Date)")
+	reset := fs.Bool("reset", false, "Restore the default template for --kind")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *kind != "" && (*text != "" || *reset) {
+		if err := charm.ValidateGreetingTemplate(*kind, *text); err != nil {
+			return err
+		}
+		templates := make(map[string]string)
+		for k, t := range cfg.GreetingTemplates {
+			templates[k] = t
+		}
+		if *reset {
+			delete(templates, *kind)
+		} else {
+			templates[*kind] = *text
+		}
+		if len(templates) == 0 {
+			templates = nil
+		}
+		cfg.GreetingTemplates = templates
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if *reset {
+			fmt.Printf("✓ Restored default %s template\n", *kind)
+		} else {
+			fmt.Printf("✓ Saved %s template\n", *kind)
+		}
+		return nil
+	}
+
+	for _, k := range charm.GreetingKinds {
+		if *kind != "" && k != *kind {
+			continue
+		}
+		t, custom := cfg.GreetingTemplates[k]
+		label := "custom"
+		if !custom {
+			t = charm.DefaultGreetingTemplates[k]
+			label = "default"
+		}
+		fmt.Printf("%s (%s):\n  %s\n", k, label, t)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// ABOUTME: Send and receive CLI commands for moving a note or contact between linked devices
+// ABOUTME: Payloads go through Charm KV, synced right after sending and just before receiving
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/harperreed/pagen/charm"
+)
+
+// SendCommand leaves a note or a contact for another linked device:
+// pagen send "Draft: thanks for the intro..." or pagen send --contact "Alice"
+func SendCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("send", flagErrorHandling)
+	contactRef := fs.String("contact", "", "Send this contact (ID or name) instead of a note")
+	_ = fs.Parse(args)
+
+	h := &charm.Handoff{Kind: charm.HandoffNote}
+	ref := *contactRef
+	text := strings.Join(fs.Args(), " ")
+	switch {
+	case ref != "":
+		if text != "" {
+			return fmt.Errorf("send either --contact or a note, not both")
+		}
+	case text == "-":
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, charm.MaxHandoffSize+1))
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		text = strings.TrimRight(string(data), "\n")
+	case fs.NArg() == 1:
+		// A lone ID, ID prefix, or @name sends that contact
+		id, ok, err := refID(client, text, charm.EntityContact)
+		if err != nil {
+			return err
+		}
+		if ok {
+			ref = id.String()
+		}
+	case text == "":
+		return fmt.Errorf("usage: pagen send <contact-id|note|->")
+	}
+
+	if ref != "" {
+		id, err := resolveID(client, ref, charm.EntityContact)
+		if err != nil {
+			return err
+		}
+		contact, err := client.GetContact(id)
+		if err != nil {
+			return fmt.Errorf("failed to get contact: %w", err)
+		}
+		h = &charm.Handoff{Kind: charm.HandoffContact, Text: contact.Name, ContactID: &contact.ID}
+	} else {
+		h.Text = text
+	}
+	h.From, _ = os.Hostname()
+
+	if err := client.SendHandoff(h); err != nil {
+		return err
+	}
+	if err := client.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Saved, but sync failed (%v); it goes out with the next sync\n", err)
+	}
+
+	if h.Kind == charm.HandoffContact {
+		fmt.Printf("✓ Sent contact %s\n", h.Text)
+	} else {
+		fmt.Printf("✓ Sent note (%d chars)\n", len(h.Text))
+	}
+	fmt.Println("  Run `pagen receive` on your other machine")
+	return nil
+}
+
+// ReceiveCommand prints the notes and contacts sent from other devices and
+// removes them. Headers go to stderr so the payload can be piped.
+func ReceiveCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("receive", flagErrorHandling)
+	latest := fs.Bool("latest", false, "Only the newest; older ones keep waiting")
+	peek := fs.Bool("peek", false, "Show without removing")
+	copyLatest := fs.Bool("copy", false, "Also copy the newest to the clipboard")
+	_ = fs.Parse(args)
+
+	if err := client.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Sync failed (%v); showing what is already here\n", err)
+	}
+
+	var handoffs []*charm.Handoff
+	var err error
+	if *peek {
+		handoffs, err = client.ListHandoffs()
+		if *latest && len(handoffs) > 1 {
+			handoffs = handoffs[len(handoffs)-1:]
+		}
+	} else {
+		handoffs, err = client.PopHandoffs(*latest)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to receive: %w", err)
+	}
+	if len(handoffs) == 0 {
+		fmt.Fprintln(os.Stderr, "Nothing waiting.")
+		return nil
+	}
+
+	var body string
+	for i, h := range handoffs {
+		if i > 0 {
+			fmt.Println()
+		}
+		from := h.From
+		if from == "" {
+			from = "another device"
+		}
+		fmt.Fprintf(os.Stderr, "── %s from %s, %s ──\n", h.Kind, from, h.CreatedAt.Local().Format("2006-01-02 15:04"))
+		body = handoffBody(client, h)
+		fmt.Println(body)
+	}
+
+	if *copyLatest {
+		if err := clipboard.WriteAll(body); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ Clipboard unavailable (%v)\n", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "✓ Copied the newest to the clipboard")
+		}
+	}
+	return nil
+}
+
+// handoffBody is what a handoff prints: the note, or the contact's current
+// details.
+func handoffBody(client *charm.Client, h *charm.Handoff) string {
+	if h.Kind != charm.HandoffContact || h.ContactID == nil {
+		return h.Text
+	}
+	contact, err := client.GetContact(*h.ContactID)
+	if err != nil || contact == nil {
+		return fmt.Sprintf("%s (no longer in the CRM)", h.Text)
+	}
+
+	lines := []string{contact.Name}
+	switch {
+	case contact.Title != "" && contact.CompanyName != "":
+		lines = append(lines, contact.Title+" at "+contact.CompanyName)
+	case contact.Title != "":
+		lines = append(lines, contact.Title)
+	case contact.CompanyName != "":
+		lines = append(lines, contact.CompanyName)
+	}
+	if contact.Email != "" {
+		lines = append(lines, contact.Email)
+	}
+	if contact.Phone != "" {
+		lines = append(lines, contact.Phone)
+	}
+	lines = append(lines, "ID: "+contact.ID.String())
+	return strings.Join(lines, "\n")
+}
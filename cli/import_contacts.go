@@ -0,0 +1,65 @@
+// ABOUTME: CLI command for importing contacts from CSV or vCard files
+// ABOUTME: Creates new contacts, merges duplicates, and links companies from email domains
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// ImportContactsCommand imports a contact list: pagen crm import-contacts --file contacts.csv
+func ImportContactsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("import-contacts", flagErrorHandling)
+	file := fs.String("file", "", "CSV or vCard (.vcf) file of contacts (required)")
+	format := fs.String("format", "", "File format: csv or vcard (default: from the extension or content)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without writing")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return charm.Validationf("--file is required")
+	}
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".vcf", ".vcard":
+			*format = sync.ContactFormatVCard
+		case ".csv":
+			*format = sync.ContactFormatCSV
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open contacts file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := sync.ParseContactFile(f, *format)
+	if err != nil {
+		return fmt.Errorf("failed to parse contacts file: %w", err)
+	}
+
+	result, err := sync.ImportContactRecords(client, records, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("✓ %s %d contacts from %s: %d created, %d merged, %d skipped\n",
+		verb, len(records), filepath.Base(*file), result.Created, result.Merged, result.Skipped)
+	if len(result.CompaniesCreated) > 0 {
+		fmt.Printf("  %d companies created: %s\n", len(result.CompaniesCreated), strings.Join(result.CompaniesCreated, ", "))
+	}
+	for _, problem := range result.Problems {
+		fmt.Printf("  ⚠ %s\n", problem)
+	}
+	return nil
+}
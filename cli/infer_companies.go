@@ -0,0 +1,117 @@
+// ABOUTME: Company inference CLI command
+// ABOUTME: Links existing contacts without a company to one inferred from their work email domain
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// InferCompaniesCommand links contacts without a company to the company their
+// email domain belongs to: pagen crm infer-companies [--dry-run] [--review]
+func InferCompaniesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("infer-companies", flagErrorHandling)
+	dryRun := fs.Bool("dry-run", false, "Show what would be linked without changing anything")
+	review := fs.Bool("review", false, "Confirm or rename each domain's company before linking")
+	domain := fs.String("domain", "", "Only this email domain")
+	minContacts := fs.Int("min", 1, "Only domains shared by at least this many contacts")
+	_ = fs.Parse(args)
+
+	inferences, err := client.InferCompanies()
+	if err != nil {
+		return err
+	}
+
+	var selected []*charm.CompanyInference
+	for _, inference := range inferences {
+		if *domain != "" && !strings.EqualFold(inference.Domain, strings.TrimSpace(*domain)) {
+			continue
+		}
+		if len(inference.Contacts) < *minContacts {
+			continue
+		}
+		selected = append(selected, inference)
+	}
+	if len(selected) == 0 {
+		fmt.Println("No contacts to link: every contact with a work email already has a company")
+		return nil
+	}
+
+	if *dryRun {
+		for _, inference := range selected {
+			printInference(os.Stdout, inference)
+		}
+		fmt.Printf("\nDry run: %d domain(s) would be linked. Run without --dry-run to apply.\n", len(selected))
+		return nil
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	linked, created := 0, 0
+	for _, inference := range selected {
+		if *review {
+			apply, quit := reviewInference(inference, in, os.Stdout)
+			if quit {
+				break
+			}
+			if !apply {
+				continue
+			}
+		}
+
+		company, isNew, err := client.ApplyCompanyInference(inference)
+		if err != nil {
+			return err
+		}
+		if isNew {
+			created++
+		}
+		linked += len(inference.Contacts)
+		fmt.Printf("✓ Linked %d contact(s) at %s to %s\n", len(inference.Contacts), inference.Domain, company.Name)
+	}
+
+	fmt.Printf("\n%d contact(s) linked, %d company(ies) created\n", linked, created)
+	return nil
+}
+
+// printInference shows a domain, the company it maps to, and its contacts.
+func printInference(out io.Writer, inference *charm.CompanyInference) {
+	target := inference.CompanyName + " (new)"
+	if inference.Company != nil {
+		target = inference.Company.Name
+	}
+	_, _ = fmt.Fprintf(out, "%s → %s (%d contact(s))\n", inference.Domain, target, len(inference.Contacts))
+	for _, contact := range inference.Contacts {
+		_, _ = fmt.Fprintf(out, "  %s <%s>\n", contact.Name, contact.Email)
+	}
+}
+
+// reviewInference asks whether to link a domain's contacts. Typing a name
+// links them to that company instead. quit is true when the user stops the
+// review.
+func reviewInference(inference *charm.CompanyInference, in *bufio.Reader, out io.Writer) (apply, quit bool) {
+	printInference(out, inference)
+	_, _ = fmt.Fprint(out, "Link? [y]es, [n]o, [q]uit, or type a company name: ")
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return false, true
+	}
+	answer := strings.TrimSpace(line)
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, false
+	case "", "n", "no":
+		return false, false
+	case "q", "quit":
+		return false, true
+	}
+	inference.CompanyName = answer
+	inference.Company = nil
+	return true, false
+}
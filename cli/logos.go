@@ -0,0 +1,124 @@
+// ABOUTME: Company logo cache CLI commands
+// ABOUTME: Prefetches logos for the web UI and clears cached logos so they are fetched again
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// LogosFetchCommand fetches and caches logos for companies with a domain.
+// Cached logos and recent misses are skipped unless --refresh is set.
+func LogosFetchCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("logos fetch", flagErrorHandling)
+	companyRef := fs.String("company", "", "Only this company (ID or name)")
+	refresh := fs.Bool("refresh", false, "Fetch again even when cached")
+	_ = fs.Parse(args)
+
+	var companies []*charm.Company
+	if *companyRef != "" {
+		company, err := resolveCompany(client, *companyRef)
+		if err != nil {
+			return err
+		}
+		if charm.LogoDomain(company) == "" {
+			return fmt.Errorf("company %s has no domain; set one with: pagen crm update-company --domain <domain> %s", company.Name, company.ID)
+		}
+		companies = []*charm.Company{company}
+	} else {
+		all, err := client.ListCompanies(&charm.CompanyFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list companies: %w", err)
+		}
+		companies = all
+	}
+
+	logoURL := ""
+	if cfg := client.Config(); cfg != nil {
+		logoURL = cfg.LogoURL
+	}
+	fetcher := sync.NewLogoFetcher(logoURL)
+
+	var fetched, missing, cached, failed int
+	seen := make(map[string]bool)
+	for _, company := range companies {
+		domain := charm.LogoDomain(company)
+		if domain == "" || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		if !*refresh {
+			existing, err := client.GetCompanyLogo(domain)
+			if err != nil {
+				return err
+			}
+			if existing != nil && !existing.Stale(time.Now()) {
+				cached++
+				continue
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sync.LogoFetchTimeout)
+		logo, err := fetcher.FetchLogo(ctx, domain)
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", company.Name, err)
+			continue
+		}
+		if err := client.SaveCompanyLogo(logo); err != nil {
+			return fmt.Errorf("failed to cache logo for %s: %w", company.Name, err)
+		}
+		if logo.Found() {
+			fetched++
+			fmt.Printf("✓ %s (%s) from %s\n", company.Name, domain, logo.Source)
+		} else {
+			missing++
+			fmt.Printf("- %s (%s): no logo found\n", company.Name, domain)
+		}
+	}
+
+	fmt.Printf("\nFetched %d, no logo %d, already cached %d, failed %d\n", fetched, missing, cached, failed)
+	if failed > 0 {
+		fmt.Println("Failed fetches aren't cached; run again when the sites are reachable.")
+	}
+	return nil
+}
+
+// LogosClearCommand removes cached logos so the next view or fetch gets them again.
+func LogosClearCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("logos clear", flagErrorHandling)
+	companyRef := fs.String("company", "", "Only this company's logo (ID or name)")
+	_ = fs.Parse(args)
+
+	var domains []string
+	if *companyRef != "" {
+		company, err := resolveCompany(client, *companyRef)
+		if err != nil {
+			return err
+		}
+		domain := charm.LogoDomain(company)
+		if domain == "" {
+			return fmt.Errorf("company %s has no domain, so it has no cached logo", company.Name)
+		}
+		domains = append(domains, domain)
+	}
+
+	cleared, err := client.ClearCompanyLogos(domains...)
+	if err != nil {
+		return err
+	}
+	scope := "all companies"
+	if len(domains) > 0 {
+		scope = strings.Join(domains, ", ")
+	}
+	fmt.Printf("✓ Cleared %d cached logo(s) for %s\n", cleared, scope)
+	return nil
+}
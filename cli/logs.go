@@ -0,0 +1,168 @@
+// ABOUTME: Log viewing CLI commands
+// ABOUTME: Shows the web server's request log, slow KV operations, and error rates
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/web"
+)
+
+// logFollowInterval is how often --follow checks for new log lines.
+const logFollowInterval = 500 * time.Millisecond
+
+// LogsWebCommand prints recent web server log entries and a latency summary.
+func LogsWebCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("logs web", flagErrorHandling)
+	limit := fs.Int("limit", 50, "Number of entries to show")
+	slow := fs.Bool("slow", false, "Only show slow KV operations")
+	errorsOnly := fs.Bool("errors", false, "Only show failed requests and KV errors")
+	follow := fs.Bool("follow", false, "Keep printing new entries as they are logged")
+	_ = fs.Parse(args)
+
+	path := web.LogPath()
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("No web log yet (%s). Start `pagen web` to record requests.\n", path)
+			return nil
+		}
+		return fmt.Errorf("failed to open web log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	keep := func(e web.LogEntry) bool {
+		if *slow && e.Kind != "slow_query" {
+			return false
+		}
+		if *errorsOnly && e.Status < 500 && e.Error == "" {
+			return false
+		}
+		return true
+	}
+
+	all, err := readLogEntries(f)
+	if err != nil {
+		return err
+	}
+	var shown []web.LogEntry
+	for _, e := range all {
+		if keep(e) {
+			shown = append(shown, e)
+		}
+	}
+	if *limit > 0 && len(shown) > *limit {
+		shown = shown[len(shown)-*limit:]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tKIND\tDETAIL\tSTATUS\tDURATION")
+	for _, e := range shown {
+		printLogEntry(w, e)
+	}
+	_ = w.Flush()
+
+	if !*follow {
+		printLogSummary(all)
+		return nil
+	}
+
+	// Tail the file; entries are appended one JSON line at a time
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			time.Sleep(logFollowInterval)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read web log: %w", err)
+		}
+		var e web.LogEntry
+		if json.Unmarshal(line, &e) != nil || !keep(e) {
+			continue
+		}
+		printLogEntry(w, e)
+		_ = w.Flush()
+	}
+}
+
+func readLogEntries(r io.Reader) ([]web.LogEntry, error) {
+	var entries []web.LogEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e web.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // skip partial lines
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read web log: %w", err)
+	}
+	return entries, nil
+}
+
+func printLogEntry(w io.Writer, e web.LogEntry) {
+	detail := e.Method + " " + e.Path
+	status := fmt.Sprintf("%d", e.Status)
+	if e.Kind == "slow_query" {
+		detail = e.Op
+		if e.Key != "" {
+			detail += " " + e.Key
+		}
+		status = "-"
+		if e.Error != "" {
+			status = "error: " + e.Error
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1fms\n",
+		e.Time.Local().Format("2006-01-02 15:04:05"), e.Kind, detail, status, e.DurationMS)
+}
+
+// printLogSummary prints request counts, error rate, and latency percentiles.
+func printLogSummary(entries []web.LogEntry) {
+	var latencies []float64
+	var serverErrors, slowQueries int
+	for _, e := range entries {
+		switch e.Kind {
+		case "request":
+			latencies = append(latencies, e.DurationMS)
+			if e.Status >= 500 {
+				serverErrors++
+			}
+		case "slow_query":
+			slowQueries++
+		}
+	}
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Float64s(latencies)
+	fmt.Printf("\nRequests: %d  Errors: %d (%.1f%%)  Slow KV operations: %d\n",
+		len(latencies), serverErrors, 100*float64(serverErrors)/float64(len(latencies)), slowQueries)
+	fmt.Printf("Latency: p50 %.1fms  p95 %.1fms  p99 %.1fms  max %.1fms\n",
+		percentile(latencies, 50), percentile(latencies, 95), percentile(latencies, 99), latencies[len(latencies)-1])
+}
+
+// percentile returns the p-th percentile of sorted values (nearest rank).
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
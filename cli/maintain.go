@@ -0,0 +1,238 @@
+// ABOUTME: Database maintenance and health CLI commands
+// ABOUTME: Runs integrity check, REINDEX, ANALYZE, and VACUUM on the local SQLite file and reports table sizes
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/db"
+
+	_ "modernc.org/sqlite" // built with dbstat, so table sizes can be reported
+)
+
+// maintainMaxProblems caps how many integrity problems are printed.
+const maintainMaxProblems = 10
+
+// DBMaintainCommand runs maintenance on the local Charm KV database, or on
+// the SQLite file given with --path.
+func DBMaintainCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("maintain", flagErrorHandling)
+	path := fs.String("path", "", "SQLite file to maintain (default: the local Charm KV database)")
+	checkOnly := fs.Bool("check", false, "Only check integrity and report sizes")
+	noVacuum := fs.Bool("no-vacuum", false, "Skip VACUUM, which rewrites the whole file")
+	_ = fs.Parse(args)
+
+	database, err := openMaintainDB(client, path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = database.Close() }()
+
+	opts := db.FullMaintenance
+	if *checkOnly {
+		opts = db.MaintenanceOptions{}
+	}
+	if *noVacuum {
+		opts.Vacuum = false
+	}
+
+	fmt.Printf("Maintaining %s...\n\n", *path)
+	report, err := db.Maintain(database, opts)
+	if err != nil {
+		return err
+	}
+	printMaintenanceReport(report)
+
+	if !report.IntegrityOK {
+		return fmt.Errorf("integrity check failed; run 'pagen sync repair --force' to attempt recovery")
+	}
+	return nil
+}
+
+// DBHealthCommand reports whether the local Charm KV database is set up for
+// several pagen processes at once. With --path it checks a SQLite file as
+// db.Open opens it, the way the legacy database and migration tool do.
+func DBHealthCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("health", flagErrorHandling)
+	path := fs.String("path", "", "SQLite file to check through db.Open (default: the live Charm KV database)")
+	_ = fs.Parse(args)
+
+	if *path == "" && client != nil {
+		return kvHealth(client)
+	}
+
+	database, err := openMaintainDB(client, path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = database.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*db.DefaultBusyTimeout)
+	defer cancel()
+	health, err := db.CheckHealth(ctx, database)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Database: %s\n\n", *path)
+	fmt.Printf("  Journal mode:   %s\n", health.JournalMode)
+	fmt.Printf("  Busy timeout:   %s\n", health.BusyTimeout)
+	fmt.Printf("  Connections:    %d open, %d in use, %d idle (max %d)\n",
+		health.OpenConnections, health.InUse, health.Idle, health.MaxOpenConnections)
+	fmt.Printf("  Waits:          %d (%s)\n", health.WaitCount, health.WaitDuration.Round(time.Millisecond))
+	fmt.Println()
+	if !health.OK() {
+		for _, problem := range health.Problems {
+			fmt.Printf("✗ %s\n", problem)
+		}
+		return fmt.Errorf("database isn't set up for concurrent access")
+	}
+	fmt.Println("✓ Ready for concurrent access")
+	return nil
+}
+
+// kvHealth checks the Charm KV database as the running processes use it:
+// the journal mode stored in the file, without switching it the way db.Open
+// would, and the KV library's own report on its connection.
+func kvHealth(client *charm.Client) error {
+	path, err := client.DatabasePath()
+	if err != nil {
+		return fmt.Errorf("failed to locate database: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*db.DefaultBusyTimeout)
+	defer cancel()
+	mode, err := db.FileJournalMode(ctx, "sqlite", path)
+	if err != nil {
+		return err
+	}
+	doctor, err := client.Doctor()
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+
+	var problems []string
+	if mode != "wal" {
+		problems = append(problems, fmt.Sprintf("journal mode is %s, so readers block writers", mode))
+	}
+	if !doctor.IntegrityOK {
+		problems = append(problems, fmt.Sprintf("integrity check failed: %s", doctor.IntegrityDetails))
+	}
+	problems = append(problems, doctor.Errors...)
+
+	fmt.Printf("Database: %s\n\n", path)
+	fmt.Printf("  Journal mode:   %s\n", mode)
+	fmt.Printf("  Integrity:      %s\n", doctor.IntegrityDetails)
+	fmt.Printf("  Unsynced ops:   %d\n", doctor.PendingOpsCount)
+	if doctor.WALSize >= 0 {
+		fmt.Printf("  WAL file:       %s\n", formatBytes(doctor.WALSize))
+	} else {
+		fmt.Println("  WAL file:       not present")
+	}
+	if doctor.SyncLockHeld {
+		fmt.Printf("  Sync lock:      held by %s until %s\n", doctor.SyncLockHolder, doctor.SyncLockExpiresAt.Local().Format("15:04:05"))
+	} else {
+		fmt.Println("  Sync lock:      free")
+	}
+	fmt.Println()
+	for _, warning := range doctor.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Printf("✗ %s\n", problem)
+		}
+		return fmt.Errorf("database isn't healthy")
+	}
+	fmt.Println("✓ Ready for concurrent access")
+	return nil
+}
+
+// openMaintainDB resolves *path, defaulting to the Charm KV database, and
+// opens it with the shared settings from db.Open.
+func openMaintainDB(client *charm.Client, path *string) (*sql.DB, error) {
+	if *path == "" {
+		if client == nil {
+			return nil, fmt.Errorf("charm KV is unavailable; pass --path to use a file directly")
+		}
+		var err error
+		if *path, err = client.DatabasePath(); err != nil {
+			return nil, fmt.Errorf("failed to locate database: %w", err)
+		}
+	}
+	if _, err := os.Stat(*path); err != nil {
+		return nil, fmt.Errorf("no database at %s: %w", *path, err)
+	}
+
+	database, err := db.Open(*path, db.Options{Driver: "sqlite"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", *path, err)
+	}
+	return database, nil
+}
+
+// printMaintenanceReport prints the steps Maintain ran and the table sizes.
+func printMaintenanceReport(report *db.MaintenanceReport) {
+	if report.IntegrityOK {
+		fmt.Println("✓ Integrity check passed")
+	} else {
+		fmt.Printf("✗ Integrity check found %d problem(s):\n", len(report.IntegrityErrors))
+		for i, problem := range report.IntegrityErrors {
+			if i == maintainMaxProblems {
+				fmt.Printf("  ... and %d more\n", len(report.IntegrityErrors)-i)
+				break
+			}
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+	if report.Reindexed {
+		fmt.Println("✓ Rebuilt indexes")
+	}
+	if report.Analyzed {
+		fmt.Println("✓ Refreshed query planner statistics")
+	}
+	if report.Vacuumed {
+		reclaimed := max(report.SizeBefore-report.SizeAfter, 0)
+		fmt.Printf("✓ Vacuumed: %s → %s (%s reclaimed)\n",
+			formatBytes(report.SizeBefore), formatBytes(report.SizeAfter), formatBytes(reclaimed))
+	} else {
+		fmt.Printf("  Database size: %s\n", formatBytes(report.SizeAfter))
+	}
+
+	if len(report.Tables) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "TABLE\tROWS\tSIZE")
+		_, _ = fmt.Fprintln(w, "-----\t----\t----")
+		for _, table := range report.Tables {
+			size := "-"
+			if table.Bytes > 0 {
+				size = formatBytes(table.Bytes)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", table.Name, table.Rows, size)
+		}
+		_ = w.Flush()
+	}
+
+	fmt.Printf("\nFinished in %s\n", report.Duration.Round(time.Millisecond))
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
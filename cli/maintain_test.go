@@ -0,0 +1,66 @@
+// ABOUTME: Tests for the database maintenance and health commands
+// ABOUTME: Verifies maintaining and checking a file by path
+package cli
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/harperreed/pagen/db"
+)
+
+func TestDBMaintainCommandPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pagen.db")
+	database, err := db.OpenDatabase(path)
+	if err != nil {
+		t.Fatalf("OpenDatabase failed: %v", err)
+	}
+	if err := db.CreateSyncLog(database, "log-1", "gmail", "m1", "interaction", "i1", "{}"); err != nil {
+		t.Fatalf("CreateSyncLog failed: %v", err)
+	}
+	_ = database.Close()
+
+	if err := DBMaintainCommand(nil, []string{"--path", path}); err != nil {
+		t.Fatalf("DBMaintainCommand failed: %v", err)
+	}
+	if err := DBMaintainCommand(nil, []string{"--path", filepath.Join(t.TempDir(), "missing.db")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if err := DBMaintainCommand(nil, nil); err == nil {
+		t.Error("expected an error without Charm KV or --path")
+	}
+
+	// The command's driver has dbstat, so tables get byte sizes
+	maintained, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	defer func() { _ = maintained.Close() }()
+	tables, err := db.TableSizes(maintained)
+	if err != nil {
+		t.Fatalf("TableSizes failed: %v", err)
+	}
+	for _, table := range tables {
+		if table.Name == "sync_log" && (table.Rows != 1 || table.Bytes == 0) {
+			t.Errorf("expected sync_log to have 1 row and a size, got %+v", table)
+		}
+	}
+}
+
+func TestDBHealthCommandPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pagen.db")
+	database, err := db.OpenDatabase(path)
+	if err != nil {
+		t.Fatalf("OpenDatabase failed: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	// Healthy while another handle holds the file open
+	if err := DBHealthCommand(nil, []string{"--path", path}); err != nil {
+		t.Errorf("DBHealthCommand failed: %v", err)
+	}
+	if err := DBHealthCommand(nil, []string{"--path", filepath.Join(t.TempDir(), "missing.db")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -15,6 +15,9 @@ import (
 func MCPCommand(client *charm.Client) error {
 	log.Println("Starting CRM MCP Server...")
 
+	// Credit changes made through the server to mcp in the audit log
+	client = client.ForActor(charm.EntryMCP)
+
 	// Create handlers
 	companyHandlers := handlers.NewCompanyHandlers(client)
 	contactHandlers := handlers.NewContactHandlers(client)
@@ -25,6 +28,11 @@ func MCPCommand(client *charm.Client) error {
 	promptHandlers := handlers.NewPromptHandlers(client)
 	vizHandlers := handlers.NewVizHandlers(client)
 	followupHandlers := handlers.NewFollowupHandlers(client)
+	bulkHandlers := handlers.NewBulkHandlers(client)
+	searchHandlers := handlers.NewSearchHandlers(client)
+	enrichmentHandlers := handlers.NewEnrichmentHandlers(client)
+	tagHandlers := handlers.NewTagHandlers(client)
+	historyHandlers := handlers.NewHistoryHandlers(client)
 
 	// Create MCP server
 	server := mcp.NewServer(&mcp.Implementation{
@@ -55,12 +63,12 @@ func MCPCommand(client *charm.Client) error {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "add_contact",
-		Description: "Add a new contact to the CRM",
+		Description: "Add a new contact to the CRM. Returns the existing contact instead when one has the same email or the idempotency_key was already used",
 	}, contactHandlers.AddContact)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "find_contacts",
-		Description: "Search for contacts by name, email, or company",
+		Description: "Search for contacts by name, email, or company, by when you met them (e.g., everyone met in 2024), or by the city you met them in",
 	}, contactHandlers.FindContacts)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -73,6 +81,16 @@ func MCPCommand(client *charm.Client) error {
 		Description: "Log an interaction with a contact and update last contacted timestamp",
 	}, contactHandlers.LogContactInteraction)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "pin_contact_fact",
+		Description: "Pin a key fact on a contact (e.g. kids: 2, likes cycling). Pinned facts are kept apart from the notes and lead the contact summary and meeting briefs",
+	}, contactHandlers.PinContactFact)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "unpin_contact_fact",
+		Description: "Remove a pinned fact from a contact, by its text or position",
+	}, contactHandlers.UnpinContactFact)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "delete_contact",
 		Description: "Delete a contact and all associated relationships",
@@ -80,7 +98,7 @@ func MCPCommand(client *charm.Client) error {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "create_deal",
-		Description: "Create a new deal in the CRM with company and optional contact",
+		Description: "Create a new deal in the CRM with company and optional contact. Returns the existing deal instead when the company has one with the same title or the idempotency_key was already used. With strict, an ambiguous company, contact, or referrer name returns an error with a disambiguation listing the candidates",
 	}, dealHandlers.CreateDeal)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -93,11 +111,26 @@ func MCPCommand(client *charm.Client) error {
 		Description: "Add a note to a deal and update activity timestamps",
 	}, dealHandlers.AddDealNote)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_deal_contact",
+		Description: "Add a contact to a deal with a role (decision_maker, champion, influencer, procurement), or change their role",
+	}, dealHandlers.AddDealContact)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_deal_contact",
+		Description: "Remove a contact's role from a deal",
+	}, dealHandlers.RemoveDealContact)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "delete_deal",
 		Description: "Delete a deal and all associated notes",
 	}, dealHandlers.DeleteDeal)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "pipeline_metrics",
+		Description: "Pipeline velocity from deal stage history: average days in each stage, the share of deals leaving each stage that moved forward, win rate, average days to win, and stalled open deals",
+	}, dealHandlers.PipelineMetrics)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "link_contacts",
 		Description: "Create a relationship between two contacts with optional type and context",
@@ -118,11 +151,26 @@ func MCPCommand(client *charm.Client) error {
 		Description: "Delete a relationship between contacts",
 	}, relationshipHandlers.RemoveRelationship)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "bulk_upsert",
+		Description: "Create or update many contacts, companies, and deals in one call (e.g. a pasted table or meeting attendee list). Contacts match on email or exact name, companies on name, deals on title and company. Returns a result per record",
+	}, bulkHandlers.BulkUpsert)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "import_contacts",
+		Description: "Import contacts from a CSV or vCard file (path) or text (content). Duplicates of existing contacts are merged, companies are linked or created from work email domains, and created/merged/skipped counts are reported",
+	}, bulkHandlers.ImportContacts)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "query_crm",
-		Description: "Universal query tool for flexible filtering across all CRM entity types (contact, company, deal, relationship)",
+		Description: "Universal query tool for flexible filtering across all CRM entity types (contact, company, deal, relationship). For totals and breakdowns (e.g. pipeline by industry, interactions per month) pass a read-only SELECT in select instead of paging through lists; views: deals, contacts, companies, interactions",
 	}, queryHandlers.QueryCRM)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_crm",
+		Description: "Ranked full-text search across contacts, companies, and deals: names, emails, notes (including deal notes), and other fields. Each word matches the start of a word, so partial names work",
+	}, searchHandlers.SearchCRM)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "generate_graph",
 		Description: "Generate GraphViz relationship/org/pipeline graphs",
@@ -143,6 +191,36 @@ func MCPCommand(client *charm.Client) error {
 		Description: "Set the follow-up cadence and relationship strength for a contact",
 	}, followupHandlers.SetCadence)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_outcome_stats",
+		Description: "Response rates of logged follow-up outcomes by channel (interaction type) and outreach email template",
+	}, followupHandlers.GetOutcomeStats)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "enrich_contact",
+		Description: "Enrich a contact and save the results: classify seniority and job function (your LLM via sampling when supported, otherwise job title rules), and pull a Gravatar avatar, company website metadata, and fields from the configured enrichment API, each value recorded with its source. Without contact_id, classifies new contacts not yet classified",
+	}, enrichmentHandlers.EnrichContact)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_tag",
+		Description: "Tag a contact, company, or deal by ID (separate several tags with commas)",
+	}, tagHandlers.AddTag)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_tag",
+		Description: "Remove tags from a contact, company, or deal by ID",
+	}, tagHandlers.RemoveTag)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "find_by_tag",
+		Description: "Find the contacts, companies, and deals with a tag",
+	}, tagHandlers.FindByTag)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_entity_history",
+		Description: "Show the audit log of changes to a contact, company, deal, or relationship (or to everything): who made each change, when, and the old and new field values. undo_rev is the revision to restore to undo a change",
+	}, historyHandlers.GetEntityHistory)
+
 	// Register resources
 	server.AddResourceTemplate(&mcp.ResourceTemplate{
 		URITemplate: "crm://contacts/{id}",
@@ -165,6 +243,20 @@ func MCPCommand(client *charm.Client) error {
 		MIMEType:    "application/json",
 	}, resourceHandlers.ReadResource)
 
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "crm://contacts/{id}/interactions",
+		Name:        "Contact Interactions",
+		Description: "A contact's 100 most recent interactions, newest first",
+		MIMEType:    "application/json",
+	}, resourceHandlers.ReadResource)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "crm://companies/{id}/overview",
+		Name:        "Company Overview",
+		Description: "A company's primary contact, contacts, deals, notes, and recent news as text",
+		MIMEType:    "text/markdown",
+	}, resourceHandlers.ReadResource)
+
 	server.AddResource(&mcp.Resource{
 		URI:         "crm://contacts",
 		Name:        "All Contacts",
@@ -186,6 +278,13 @@ func MCPCommand(client *charm.Client) error {
 		MIMEType:    "application/json",
 	}, resourceHandlers.ReadResource)
 
+	server.AddResource(&mcp.Resource{
+		URI:         "crm://deals/pipeline",
+		Name:        "Pipeline Deals",
+		Description: "Every stage in pipeline order with its totals, expected value, and deals",
+		MIMEType:    "application/json",
+	}, resourceHandlers.ReadResource)
+
 	server.AddResource(&mcp.Resource{
 		URI:         "crm://pipeline",
 		Name:        "Deal Pipeline",
@@ -232,7 +331,39 @@ func MCPCommand(client *charm.Client) error {
 		},
 	}, promptHandlers.GetPrompt)
 
-	// Run server on stdio transport
-	ctx := context.Background()
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "meeting-prep",
+		Description: "Prepare for a meeting with attendee summaries, open deals, and talking points",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "event_id", Description: "Calendar event ID from Google Calendar sync", Required: false},
+			{Name: "attendees", Description: "Comma-separated attendee emails", Required: false},
+		},
+	}, promptHandlers.GetPrompt)
+
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "qbr",
+		Description: "Quarterly business review brief for a company",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "company_id", Description: "UUID of the company", Required: true},
+			{Name: "period_days", Description: "Length of the review period in days (default: 90)", Required: false},
+		},
+	}, promptHandlers.GetPrompt)
+
+	// Register user prompt templates from the config directory; these may
+	// override the built-in prompts above
+	templates, err := handlers.LoadPromptTemplates(handlers.PromptTemplatesDir())
+	if err != nil {
+		log.Printf("Warning: failed to load prompt templates: %v", err)
+	}
+	promptHandlers.AddTemplates(templates)
+	for _, pt := range templates {
+		server.AddPrompt(pt.MCPPrompt(), promptHandlers.GetPrompt)
+		log.Printf("Loaded prompt template %q from %s", pt.Name, pt.Path)
+	}
+
+	// Run server on stdio transport, with the background jobs alongside
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go RunBackgroundJobsEvery(ctx, client, BackgroundJobInterval, log.Printf)
 	return server.Run(ctx, &mcp.StdioTransport{})
 }
@@ -0,0 +1,252 @@
+// ABOUTME: Company news enrichment CLI commands
+// ABOUTME: Fetches headlines for companies from configured feeds, lists stored news, and manages feeds
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// NewsFetchCommand fetches headlines for companies with domains. With --watch
+// it keeps running and fetches on an interval.
+func NewsFetchCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("fetch", flagErrorHandling)
+	companyRef := fs.String("company", "", "Only fetch news for this company (ID or name)")
+	watch := fs.Bool("watch", false, "Keep running and fetch on an interval")
+	interval := fs.String("interval", "6h", "Fetch interval when watching (e.g., 1h, 24h)")
+	_ = fs.Parse(args)
+
+	var companies []*charm.Company
+	if *companyRef != "" {
+		company, err := resolveCompany(client, *companyRef)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(company.Domain) == "" {
+			return fmt.Errorf("company %s has no domain; set one with: pagen crm update-company --domain <domain> %s", company.Name, company.ID)
+		}
+		companies = []*charm.Company{company}
+	}
+
+	httpClient := &http.Client{Timeout: sync.NewsFetchTimeout}
+	fetch := func() error {
+		cfg := client.Config()
+		targets := companies
+		if targets == nil {
+			all, err := client.ListCompanies(&charm.CompanyFilter{})
+			if err != nil {
+				return fmt.Errorf("failed to list companies: %w", err)
+			}
+			targets = all
+		}
+
+		start := time.Now()
+		result, err := sync.FetchCompanyNews(context.Background(), httpClient, client, cfg.News, targets)
+		if err != nil {
+			return err
+		}
+		for _, e := range result.Errors {
+			log.Printf("⚠ %s", e)
+		}
+		log.Printf("✓ Checked %d companies, %d new headline(s) (%.2fs)", result.Companies, result.Added, time.Since(start).Seconds())
+		return nil
+	}
+
+	if !*watch {
+		return fetch()
+	}
+
+	duration, err := time.ParseDuration(*interval)
+	if err != nil {
+		return charm.Validationf("invalid interval format: %w", err)
+	}
+	if duration < time.Minute {
+		return fmt.Errorf("interval must be at least 1 minute")
+	}
+
+	log.Printf("Starting news fetch daemon (interval: %s)", duration)
+
+	// Setup signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	ticker := time.NewTicker(duration)
+	defer ticker.Stop()
+
+	run := func() {
+		if err := fetch(); err != nil {
+			log.Printf("✗ News fetch failed: %v", err)
+		}
+	}
+
+	run()
+	for {
+		select {
+		case <-ticker.C:
+			run()
+		case sig := <-sigChan:
+			log.Printf("Received signal %s, shutting down gracefully...", sig)
+			return nil
+		}
+	}
+}
+
+// NewsListCommand shows stored headlines for one company, or every company with news.
+func NewsListCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flagErrorHandling)
+	companyRef := fs.String("company", "", "Only show news for this company (ID or name)")
+	_ = fs.Parse(args)
+
+	var companies []*charm.Company
+	if *companyRef != "" {
+		company, err := resolveCompany(client, *companyRef)
+		if err != nil {
+			return err
+		}
+		companies = []*charm.Company{company}
+	} else {
+		all, err := client.ListCompanies(&charm.CompanyFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list companies: %w", err)
+		}
+		companies = all
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "COMPANY\tDATE\tHEADLINE\tSOURCE")
+	_, _ = fmt.Fprintln(w, "-------\t----\t--------\t------")
+
+	found := 0
+	for _, company := range companies {
+		items, err := client.RecentCompanyNews(company.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get news for %s: %w", company.Name, err)
+		}
+		for _, item := range items {
+			source := item.Source
+			if source == "" {
+				source = "-"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", company.Name, item.PublishedAt.Format("2006-01-02"), item.Title, source)
+			found++
+		}
+	}
+
+	if found == 0 {
+		fmt.Println("No company news yet. Fetch it with: pagen news fetch")
+		return nil
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// NewsFeedsCommand lists, adds, or removes news feeds and sets retention.
+func NewsFeedsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("feeds", flagErrorHandling)
+	add := fs.String("add", "", "Feed URL to add; {name} and {domain} make it a per-company search")
+	remove := fs.String("remove", "", "Feed URL to remove")
+	maxItems := fs.Int("max-items", 0, "Headlines kept per company")
+	maxAgeDays := fs.Int("max-age-days", 0, "Drop headlines older than this many days")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	news := cfg.News
+	if news == nil {
+		news = &charm.NewsConfig{}
+	}
+
+	changed := false
+	if *add != "" {
+		if !strings.HasPrefix(*add, "http://") && !strings.HasPrefix(*add, "https://") {
+			return fmt.Errorf("feed must be an http(s) URL: %s", *add)
+		}
+		for _, feed := range news.Feeds {
+			if feed == *add {
+				return charm.Conflictf("feed already added: %s", *add)
+			}
+		}
+		news.Feeds = append(news.Feeds, *add)
+		changed = true
+	}
+	if *remove != "" {
+		var feeds []string
+		for _, feed := range news.Feeds {
+			if feed != *remove {
+				feeds = append(feeds, feed)
+			}
+		}
+		if len(feeds) == len(news.Feeds) {
+			return charm.NotFoundf("feed not found: %s", *remove)
+		}
+		news.Feeds = feeds
+		changed = true
+	}
+	if *maxItems < 0 || *maxAgeDays < 0 {
+		return fmt.Errorf("--max-items and --max-age-days must be positive")
+	}
+	if *maxItems > 0 {
+		news.MaxItems = *maxItems
+		changed = true
+	}
+	if *maxAgeDays > 0 {
+		news.MaxAgeDays = *maxAgeDays
+		changed = true
+	}
+
+	if changed {
+		cfg.News = news
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println("✓ News feeds updated")
+	}
+
+	if len(news.Feeds) == 0 {
+		fmt.Println("No news feeds configured")
+		fmt.Println("Add one with: pagen news feeds --add \"https://news.google.com/rss/search?q={name}\"")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tFEED")
+	_, _ = fmt.Fprintln(w, "----\t----")
+	for _, feed := range news.Feeds {
+		kind := "shared"
+		if charm.IsCompanyFeed(feed) {
+			kind = "per-company"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", kind, feed)
+	}
+	_ = w.Flush()
+	fmt.Printf("\nKeeping up to %d headline(s) per company from the last %d day(s)\n",
+		news.MaxItemsOrDefault(), int(news.MaxAgeOrDefault().Hours()/24))
+	return nil
+}
+
+// resolveCompany finds a company by ID, ID prefix, or name (see resolveID).
+func resolveCompany(client *charm.Client, ref string) (*charm.Company, error) {
+	id, err := resolveID(client, ref, charm.EntityCompany)
+	if err != nil {
+		return nil, err
+	}
+	company, err := client.GetCompany(id)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+	return company, nil
+}
@@ -0,0 +1,170 @@
+// ABOUTME: Deal notes CLI command and plain-text Markdown rendering
+// ABOUTME: Prints notes without Markdown syntax; checklists show as [ ] and [x]
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// DealNotesCommand prints a deal's notes as plain text, oldest first.
+func DealNotesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("deal-notes", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: deal-notes <deal-id>")
+	}
+	dealID, err := resolveID(client, fs.Arg(0), charm.EntityDeal)
+	if err != nil {
+		return err
+	}
+	deal, err := client.GetDeal(dealID)
+	if err != nil {
+		return fmt.Errorf("deal not found: %w", err)
+	}
+
+	notes, err := client.ListDealNotes(dealID)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+	if len(notes) == 0 {
+		fmt.Printf("No notes for %s\n", deal.Title)
+		return nil
+	}
+
+	fmt.Printf("Notes for %s:\n", deal.Title)
+	for _, note := range notes {
+		fmt.Printf("\n[%s]\n", note.CreatedAt.Format("2006-01-02"))
+		for _, line := range strings.Split(PlainMarkdown(note.Content), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+		if items := charm.ParseChecklist(note.Content); len(items) > 0 {
+			done := 0
+			for _, item := range items {
+				if item.Done {
+					done++
+				}
+			}
+			fmt.Printf("  (%d/%d checklist items done)\n", done, len(items))
+		}
+	}
+	return nil
+}
+
+// PlainMarkdown renders Markdown as plain text for terminals and pipes:
+// emphasis and headings lose their markers, links keep their URL, lists
+// and quotes keep their shape.
+func PlainMarkdown(source string) string {
+	src := []byte(source)
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(src))
+	var b strings.Builder
+	writePlainBlocks(&b, doc, src, "")
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writePlainBlocks(b *strings.Builder, parent ast.Node, src []byte, indent string) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		if n.PreviousSibling() != nil && n.HasBlankPreviousLines() {
+			b.WriteString(strings.TrimRight(indent, " ") + "\n")
+		}
+		switch n := n.(type) {
+		case *ast.List:
+			number := n.Start
+			for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+				marker := "•"
+				if n.IsOrdered() {
+					marker = strconv.Itoa(number) + "."
+					number++
+				}
+				var inner strings.Builder
+				writePlainBlocks(&inner, item, src, "")
+				for i, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+					if i == 0 {
+						b.WriteString(indent + marker + " " + line + "\n")
+					} else {
+						b.WriteString(indent + strings.Repeat(" ", len([]rune(marker))+1) + line + "\n")
+					}
+				}
+			}
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				b.WriteString(indent + "    " + strings.TrimRight(string(segment.Value(src)), "\n") + "\n")
+			}
+		case *ast.Blockquote:
+			writePlainBlocks(b, n, src, indent+"> ")
+		case *ast.ThematicBreak:
+			b.WriteString(indent + "---\n")
+		case *east.Table:
+			for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+				var cells []string
+				for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+					cells = append(cells, plainInline(cell, src))
+				}
+				b.WriteString(indent + strings.Join(cells, " | ") + "\n")
+			}
+		default:
+			for _, line := range strings.Split(plainInline(n, src), "\n") {
+				b.WriteString(indent + line + "\n")
+			}
+		}
+	}
+}
+
+// plainInline returns the text of a block's inline content.
+func plainInline(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := node.(type) {
+		case *east.TaskCheckBox:
+			if entering && node.IsChecked {
+				b.WriteString("[x] ")
+			} else if entering {
+				b.WriteString("[ ] ")
+			}
+		case *ast.Text:
+			if entering {
+				b.Write(node.Segment.Value(src))
+				if node.HardLineBreak() {
+					b.WriteString("\n")
+				} else if node.SoftLineBreak() {
+					b.WriteString(" ")
+				}
+			}
+		case *ast.String:
+			if entering {
+				b.Write(node.Value)
+			}
+		case *ast.AutoLink:
+			if entering {
+				b.Write(node.URL(src))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Link:
+			if !entering && len(node.Destination) > 0 {
+				b.WriteString(" (" + string(node.Destination) + ")")
+			}
+		case *ast.RawHTML:
+			if entering {
+				for i := 0; i < node.Segments.Len(); i++ {
+					segment := node.Segments.At(i)
+					b.Write(segment.Value(src))
+				}
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
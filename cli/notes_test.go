@@ -0,0 +1,31 @@
+// ABOUTME: Tests for plain-text Markdown rendering of notes
+// ABOUTME: Verifies markers are stripped while lists, checklists, links, and code keep their shape
+package cli
+
+import "testing"
+
+func TestPlainMarkdown(t *testing.T) {
+	source := "# Kickoff\n\n" +
+		"Met with **Dana** and _Fox_, see [the brief](https://example.com/brief).\n\n" +
+		"- [x] Send proposal\n" +
+		"- [ ] Book follow-up\n" +
+		"  - Ask about `budget`\n\n" +
+		"1. Legal\n" +
+		"2. Security review\n\n" +
+		"> Budget approved\n\n" +
+		"```\nterms: net 30\n```"
+
+	want := "Kickoff\n\n" +
+		"Met with Dana and Fox, see the brief (https://example.com/brief).\n\n" +
+		"• [x] Send proposal\n" +
+		"• [ ] Book follow-up\n" +
+		"  • Ask about budget\n\n" +
+		"1. Legal\n" +
+		"2. Security review\n\n" +
+		"> Budget approved\n\n" +
+		"    terms: net 30"
+
+	if got := PlainMarkdown(source); got != want {
+		t.Errorf("unexpected plain text:\n%s\n\nwant:\n%s", got, want)
+	}
+}
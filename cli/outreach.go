@@ -0,0 +1,191 @@
+// ABOUTME: Outreach email CLI commands
+// ABOUTME: Drafts templated emails to contacts in Gmail or the browser and tracks them until the contact replies
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// EmailCommand drafts a templated email to a contact, or a company's primary
+// contact, and records it as pending outreach:
+// pagen crm email <contact> --template intro
+func EmailCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("email", flagErrorHandling)
+	templateName := fs.String("template", "intro", "Email template name")
+	companyRef := fs.String("company", "", "Email this company's primary contact instead of a named contact")
+	sender := fs.String("sender", "", "Name to sign the email with (default: email_sender in config)")
+	browser := fs.Bool("browser", false, "Open a prefilled Gmail compose window instead of creating a draft")
+	dryRun := fs.Bool("dry-run", false, "Print the email without drafting or recording it")
+	_ = fs.Parse(args)
+
+	var contact *charm.Contact
+	var err error
+	switch {
+	case *companyRef != "" && fs.NArg() == 0:
+		contact, err = companyPrimaryContact(client, *companyRef)
+	case *companyRef == "" && fs.NArg() == 1:
+		contact, err = findContact(client, fs.Arg(0))
+	default:
+		return fmt.Errorf("usage: pagen crm email <contact-id|name> | --company <company> [--template name] [--browser] [--dry-run]")
+	}
+	if err != nil {
+		return err
+	}
+	if contact.Email == "" {
+		return fmt.Errorf("%s has no email address", contact.Name)
+	}
+
+	var templates map[string]charm.EmailTemplate
+	if cfg := client.Config(); cfg != nil {
+		templates = cfg.EmailTemplates
+		if *sender == "" {
+			*sender = cfg.EmailSender
+		}
+	}
+	subject, body, err := charm.DraftEmail(contact, *templateName, *sender, templates)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("To: %s <%s>\nSubject: %s\n\n%s\n", contact.Name, contact.Email, subject, body)
+		return nil
+	}
+
+	outreach := &charm.Outreach{
+		ContactID:   contact.ID,
+		ContactName: contact.Name,
+		Email:       contact.Email,
+		Template:    *templateName,
+		Subject:     subject,
+	}
+
+	if !*browser {
+		draftID, threadID, err := createDraft(contact.Email, subject, body)
+		if err == nil {
+			outreach.DraftID = draftID
+			outreach.ThreadID = threadID
+			fmt.Printf("✓ Gmail draft created for %s: %s\n", contact.Name, subject)
+		} else {
+			fmt.Printf("⚠ Could not create a Gmail draft (%v), opening compose window instead\n", err)
+			*browser = true
+		}
+	}
+	if *browser {
+		composeURL := sync.GmailComposeURL(contact.Email, subject, body)
+		if err := openBrowser(composeURL); err != nil {
+			fmt.Printf("Open this link to compose the email:\n  %s\n", composeURL)
+		} else {
+			fmt.Printf("✓ Opened compose window for %s: %s\n", contact.Name, subject)
+		}
+	}
+
+	if err := client.CreateOutreach(outreach); err != nil {
+		return fmt.Errorf("failed to record outreach: %w", err)
+	}
+	fmt.Println("  Waiting for a reply; it is logged as an interaction on the next `pagen sync now`.")
+	return nil
+}
+
+// companyPrimaryContact resolves a company and returns its primary contact.
+func companyPrimaryContact(client *charm.Client, ref string) (*charm.Contact, error) {
+	companyID, err := resolveID(client, ref, charm.EntityCompany)
+	if err != nil {
+		return nil, err
+	}
+	company, err := client.GetCompany(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found: %w", err)
+	}
+	contact := client.PrimaryContact(company)
+	if contact == nil {
+		return nil, fmt.Errorf("%s has no primary contact; set one with: pagen crm update-company %s --primary-contact <contact>", company.Name, charm.FormatID(company.ID))
+	}
+	return contact, nil
+}
+
+// createDraft creates a Gmail draft with the stored Google token.
+func createDraft(to, subject, body string) (string, string, error) {
+	token, err := sync.LoadToken()
+	if err != nil {
+		return "", "", fmt.Errorf("no Google token")
+	}
+	service, err := sync.NewGmailClient(token)
+	if err != nil {
+		return "", "", err
+	}
+	return sync.CreateGmailDraft(context.Background(), service, to, subject, body)
+}
+
+// OutreachCommand lists outreach emails, or checks Gmail for replies with "check".
+func OutreachCommand(client *charm.Client, args []string) error {
+	if len(args) > 0 && args[0] == "check" {
+		result, err := CheckOutreachReplies(client)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Checked %d pending outreach email(s), %d replied\n", result.Checked, len(result.Replied))
+		for _, outreach := range result.Replied {
+			fmt.Printf("  %s replied to: %s\n", outreach.ContactName, outreach.Subject)
+		}
+		return nil
+	}
+
+	fs := flag.NewFlagSet("outreach", flagErrorHandling)
+	all := fs.Bool("all", false, "Include outreach that was replied to")
+	_ = fs.Parse(args)
+
+	status := charm.OutreachPending
+	if *all {
+		status = ""
+	}
+	list, err := client.ListOutreach(status)
+	if err != nil {
+		return fmt.Errorf("failed to list outreach: %w", err)
+	}
+	if len(list) == 0 {
+		fmt.Println("No pending outreach")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CREATED\tCONTACT\tTEMPLATE\tSUBJECT\tSTATUS\tREPLIED")
+	_, _ = fmt.Fprintln(w, "-------\t-------\t--------\t-------\t------\t-------")
+	for _, outreach := range list {
+		replied := "-"
+		if outreach.RepliedAt != nil {
+			replied = outreach.RepliedAt.Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			outreach.CreatedAt.Format("2006-01-02"),
+			outreach.ContactName,
+			outreach.Template,
+			outreach.Subject,
+			outreach.Status,
+			replied,
+		)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// CheckOutreachReplies checks Gmail for replies to pending outreach using the
+// stored Google token.
+func CheckOutreachReplies(client *charm.Client) (*sync.OutreachCheckResult, error) {
+	token, err := sync.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("no Google token found, replies can't be checked: %w", err)
+	}
+	service, err := sync.NewGmailClient(token)
+	if err != nil {
+		return nil, err
+	}
+	return sync.CheckOutreachReplies(context.Background(), service, client)
+}
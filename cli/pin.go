@@ -0,0 +1,77 @@
+// ABOUTME: Pinned fact commands
+// ABOUTME: Pins, lists, and unpins key facts shown at the top of a contact's summary
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// PinCommand pins a fact on a contact, or lists its pinned facts when no fact is given.
+func PinCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("pin", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) < 1 {
+		return fmt.Errorf("usage: pin <contact> [fact]")
+	}
+
+	contactID, err := resolveID(client, fs.Arg(0), charm.EntityContact)
+	if err != nil {
+		return err
+	}
+
+	fact := strings.Join(fs.Args()[1:], " ")
+	if strings.TrimSpace(fact) == "" {
+		contact, err := client.GetContact(contactID)
+		if err != nil {
+			return err
+		}
+		printPinned(contact)
+		return nil
+	}
+
+	contact, err := client.PinContactFact(contactID, fact)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Pinned on %s: %s\n", contact.Name, strings.TrimSpace(fact))
+	return nil
+}
+
+// UnpinCommand removes a pinned fact from a contact by its number or text.
+func UnpinCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("unpin", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) < 2 {
+		return fmt.Errorf("usage: unpin <contact> <number|text>")
+	}
+
+	contactID, err := resolveID(client, fs.Arg(0), charm.EntityContact)
+	if err != nil {
+		return err
+	}
+
+	contact, err := client.UnpinContactFact(contactID, strings.Join(fs.Args()[1:], " "))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Unpinned from %s\n", contact.Name)
+	printPinned(contact)
+	return nil
+}
+
+func printPinned(contact *charm.Contact) {
+	if len(contact.Pinned) == 0 {
+		fmt.Printf("No pinned facts on %s\n", contact.Name)
+		return
+	}
+	fmt.Printf("Pinned on %s:\n", contact.Name)
+	for i, fact := range contact.Pinned {
+		fmt.Printf("  %d. %s\n", i+1, fact)
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Profile CLI commands for keeping separate CRMs side by side
+// ABOUTME: Lists, creates, and switches profiles, each with its own database, config, and sync tokens
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ProfilesListCommand lists profiles, marking the current one:
+// pagen profiles list
+func ProfilesListCommand(args []string) error {
+	fs := flag.NewFlagSet("profiles list", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	profiles, err := charm.ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	current := charm.CurrentProfile()
+	active := charm.ActiveProfile()
+	for _, name := range profiles {
+		marker := " "
+		if name == current {
+			marker = "*"
+		}
+		note := ""
+		if name == active && active != current {
+			note = " (this run, from --profile or PAGEN_PROFILE)"
+		}
+		fmt.Printf("%s %-16s %s%s\n", marker, name, charm.ProfileDataDir(name), note)
+	}
+	return nil
+}
+
+// ProfilesCreateCommand adds an empty profile:
+// pagen profiles create <name> [--switch]
+func ProfilesCreateCommand(args []string) error {
+	fs := flag.NewFlagSet("profiles create", flagErrorHandling)
+	switchTo := fs.Bool("switch", false, "Make the new profile current")
+	name, err := parseProfileName(fs, args, "usage: pagen profiles create <name> [--switch]")
+	if err != nil {
+		return err
+	}
+
+	if err := charm.CreateProfile(name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Created profile %s at %s\n", name, charm.ProfileDataDir(name))
+
+	if *switchTo {
+		if err := charm.SwitchProfile(name); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Switched to profile %s\n", name)
+	} else {
+		fmt.Printf("  Use it with: pagen --profile %s <command>, or pagen profiles switch %s\n", name, name)
+	}
+	return nil
+}
+
+// ProfilesSwitchCommand makes a profile current for later runs:
+// pagen profiles switch <name>
+func ProfilesSwitchCommand(args []string) error {
+	fs := flag.NewFlagSet("profiles switch", flagErrorHandling)
+	name, err := parseProfileName(fs, args, "usage: pagen profiles switch <name>")
+	if err != nil {
+		return err
+	}
+
+	if err := charm.SwitchProfile(name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Switched to profile %s\n", name)
+	return nil
+}
+
+// parseProfileName parses fs from args, taking the profile name from the
+// first argument so flags may come before or after it.
+func parseProfileName(fs *flag.FlagSet, args []string, usage string) (string, error) {
+	var name string
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		name, args = args[0], args[1:]
+	}
+	_ = fs.Parse(args)
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		return "", charm.Validationf("%s", usage)
+	}
+	return name, nil
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Record references in CLI arguments
+// ABOUTME: Resolves IDs, short ID prefixes, and names, asking which record was meant when a name is ambiguous
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+	"golang.org/x/term"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// chooseCandidate picks the record an ambiguous reference meant. It asks on a
+// terminal and otherwise returns the ambiguity error; tests replace it.
+var chooseCandidate = func(ambiguous *charm.AmbiguousMatchError) (uuid.UUID, error) {
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return uuid.Nil, ambiguous
+	}
+	return promptCandidate(ambiguous, bufio.NewReader(os.Stdin), os.Stdout)
+}
+
+// promptCandidate lists the candidates and reads the number of the chosen one.
+func promptCandidate(ambiguous *charm.AmbiguousMatchError, in *bufio.Reader, out io.Writer) (uuid.UUID, error) {
+	_, _ = fmt.Fprintf(out, "%q matches %d %ss:\n", ambiguous.Ref, len(ambiguous.Candidates), ambiguous.EntityType)
+	for i, candidate := range ambiguous.Candidates {
+		_, _ = fmt.Fprintf(out, "  %d) %s\n", i+1, candidate)
+	}
+	_, _ = fmt.Fprintf(out, "Which one? [1-%d, Enter to cancel]: ", len(ambiguous.Candidates))
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return uuid.Nil, ambiguous
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(ambiguous.Candidates) {
+		return uuid.Nil, fmt.Errorf("cancelled: %w", ambiguous)
+	}
+	return ambiguous.Candidates[n-1].ID, nil
+}
+
+// resolveID resolves a full ID, a unique ID prefix, an @name, or a plain name
+// to a record of one of kinds (any kind when none are given). Hex-looking
+// names that match no ID prefix are looked up as names too. When a reference
+// matches several records, chooseCandidate picks one.
+func resolveID(client *charm.Client, ref string, kinds ...string) (uuid.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	lookup := ref
+	if _, err := uuid.Parse(ref); err != nil && !charm.IsNameRef(ref) && !charm.IsIDPrefix(ref) {
+		lookup = "@" + ref
+	}
+
+	id, err := client.ResolveRef(lookup, kinds...)
+	if err == nil && id == uuid.Nil && charm.IsIDPrefix(lookup) {
+		id, err = client.ResolveRef("@"+ref, kinds...)
+	}
+	var ambiguous *charm.AmbiguousMatchError
+	if errors.As(err, &ambiguous) {
+		id, err = chooseCandidate(ambiguous)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if id == uuid.Nil {
+		kind := "record"
+		if len(kinds) == 1 {
+			kind = kinds[0]
+		}
+		return uuid.Nil, charm.NotFoundf("no %s found matching: %s", kind, ref)
+	}
+	return id, nil
+}
+
+// refID resolves ref when it is an ID, an ID prefix matching a record of kind,
+// or an @name reference. ok is false when ref should be looked up as a plain
+// name instead, including hex-looking names that match no ID. Commands that
+// create a record for an unknown name use it instead of resolveID.
+func refID(client *charm.Client, ref, kind string) (id uuid.UUID, ok bool, err error) {
+	if _, err := uuid.Parse(ref); err != nil && !charm.IsNameRef(ref) && !charm.IsIDPrefix(ref) {
+		return uuid.Nil, false, nil
+	}
+	id, err = client.ResolveRef(ref, kind)
+	if err != nil {
+		return uuid.Nil, true, err
+	}
+	if id == uuid.Nil {
+		if charm.IsNameRef(ref) {
+			return uuid.Nil, true, charm.NotFoundf("no %s found matching: %s", kind, ref)
+		}
+		return uuid.Nil, false, nil
+	}
+	return id, true, nil
+}
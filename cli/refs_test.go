@@ -0,0 +1,80 @@
+// ABOUTME: Tests for record references in CLI arguments
+// ABOUTME: Validates plain-name lookups and choosing among ambiguous matches
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestResolveIDPlainNames(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	alice := &charm.Contact{Name: "Alice Smith", Email: "alice@acme.com"}
+	alicia := &charm.Contact{Name: "Alicia Jones"}
+	for _, contact := range []*charm.Contact{alice, alicia} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+	acme := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	id, err := resolveID(client, "Alice Smith", charm.EntityContact)
+	if err != nil || id != alice.ID {
+		t.Errorf("resolveID(Alice Smith) = %s, %v", id, err)
+	}
+	id, err = resolveID(client, "acme", charm.EntityCompany)
+	if err != nil || id != acme.ID {
+		t.Errorf("resolveID(acme) = %s, %v", id, err)
+	}
+
+	defer func(prev func(*charm.AmbiguousMatchError) (uuid.UUID, error)) { chooseCandidate = prev }(chooseCandidate)
+	var asked *charm.AmbiguousMatchError
+	chooseCandidate = func(ambiguous *charm.AmbiguousMatchError) (uuid.UUID, error) {
+		asked = ambiguous
+		return ambiguous.Candidates[0].ID, nil
+	}
+	if _, err := resolveID(client, "Ali", charm.EntityContact); err != nil {
+		t.Fatalf("resolveID(Ali) failed: %v", err)
+	}
+	if asked == nil || len(asked.Candidates) != 2 {
+		t.Errorf("expected to be asked about 2 candidates, got %+v", asked)
+	}
+}
+
+func TestPromptCandidate(t *testing.T) {
+	ambiguous := &charm.AmbiguousMatchError{
+		EntityType: charm.EntityContact,
+		Ref:        "@ali",
+		Candidates: []charm.MatchCandidate{
+			{ID: uuid.New(), Name: "Alice Smith"},
+			{ID: uuid.New(), Name: "Alicia Jones"},
+		},
+	}
+
+	var out bytes.Buffer
+	id, err := promptCandidate(ambiguous, bufio.NewReader(strings.NewReader("2\n")), &out)
+	if err != nil || id != ambiguous.Candidates[1].ID {
+		t.Errorf("choosing 2 = %s, %v", id, err)
+	}
+	if !strings.Contains(out.String(), "2) Alicia Jones") {
+		t.Errorf("expected numbered candidates, got:\n%s", out.String())
+	}
+
+	for _, input := range []string{"\n", "3\n", ""} {
+		_, err := promptCandidate(ambiguous, bufio.NewReader(strings.NewReader(input)), &out)
+		var target *charm.AmbiguousMatchError
+		if !errors.As(err, &target) {
+			t.Errorf("input %q: expected the ambiguity error, got %v", input, err)
+		}
+	}
+}
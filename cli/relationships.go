@@ -5,25 +5,30 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
 )
 
 // UpdateRelationshipCommand updates a relationship.
 func UpdateRelationshipCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("update-relationship", flag.ExitOnError)
+	fs := flag.NewFlagSet("update-relationship", flagErrorHandling)
 	relType := fs.String("type", "", "Relationship type")
 	context := fs.String("context", "", "Relationship context")
+	strength := fs.String("strength", "", "Relationship strength: weak, medium, or strong (also confirms it)")
+	validate := fs.Bool("validate", false, "Confirm the relationship still holds")
 	_ = fs.Parse(args)
 
 	if len(fs.Args()) != 1 {
-		return fmt.Errorf("usage: update-relationship <id> [--type <type>] [--context <context>]")
+		return fmt.Errorf("usage: update-relationship <id> [--type <type>] [--context <context>] [--strength <strength>] [--validate]")
+	}
+	if err := charm.ValidateRelationshipStrength(*strength); err != nil {
+		return err
 	}
 
-	relID, err := uuid.Parse(fs.Arg(0))
+	relID, err := resolveID(client, fs.Arg(0), charm.EntityRelationship)
 	if err != nil {
-		return fmt.Errorf("invalid relationship ID: %w", err)
+		return err
 	}
 
 	rel, err := client.GetRelationship(relID)
@@ -37,6 +42,13 @@ func UpdateRelationshipCommand(client *charm.Client, args []string) error {
 	if *context != "" {
 		rel.Context = *context
 	}
+	if *strength != "" {
+		rel.Strength = *strength
+	}
+	if *strength != "" || *validate {
+		now := time.Now()
+		rel.LastValidatedAt = &now
+	}
 
 	err = client.UpdateRelationship(rel)
 	if err != nil {
@@ -49,16 +61,16 @@ func UpdateRelationshipCommand(client *charm.Client, args []string) error {
 
 // DeleteRelationshipCommand deletes a relationship.
 func DeleteRelationshipCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("delete-relationship", flag.ExitOnError)
+	fs := flag.NewFlagSet("delete-relationship", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	if len(fs.Args()) != 1 {
 		return fmt.Errorf("usage: delete-relationship <id>")
 	}
 
-	relID, err := uuid.Parse(fs.Arg(0))
+	relID, err := resolveID(client, fs.Arg(0), charm.EntityRelationship)
 	if err != nil {
-		return fmt.Errorf("invalid relationship ID: %w", err)
+		return err
 	}
 
 	err = client.DeleteRelationship(relID)
@@ -0,0 +1,91 @@
+// ABOUTME: Pending reply CLI command for emails the contact hasn't answered
+// ABOUTME: Checks Gmail threads with contacts and lists those waiting on a reply past the configured days
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// PendingRepliesCommand lists sent emails the contact hasn't replied to:
+// pagen followups pending-replies [--check] [--days N]
+func PendingRepliesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("pending-replies", flagErrorHandling)
+	check := fs.Bool("check", false, "Check Gmail for new messages first")
+	days := fs.Int("days", 0, "Days without a reply before an email is pending (default: reply_wait_days, or 3)")
+	_ = fs.Parse(args)
+
+	if *days < 0 {
+		return fmt.Errorf("--days cannot be negative")
+	}
+	if *days == 0 {
+		*days = charm.ReplyWaitDays(client.Config())
+	}
+
+	now := time.Now()
+	if *check {
+		result, err := CheckEmailThreads(client, *days, now)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Checked %d message(s) in %d thread(s)\n", result.Messages, result.Threads)
+	} else if _, err := client.MarkPendingReplies(*days, now); err != nil {
+		return fmt.Errorf("failed to mark pending replies: %w", err)
+	}
+
+	all, err := client.ListPendingReplies()
+	if err != nil {
+		return fmt.Errorf("failed to list pending replies: %w", err)
+	}
+	// Threads marked under a shorter wait are left out
+	var pending []*charm.EmailThread
+	for _, thread := range all {
+		if thread.DaysWaiting(now) >= *days {
+			pending = append(pending, thread)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println("No emails waiting on a reply")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CONTACT\tEMAIL\tSUBJECT\tSENT\tDAYS WAITING")
+	_, _ = fmt.Fprintln(w, "-------\t-----\t-------\t----\t------------")
+	for _, thread := range pending {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
+			thread.ContactName, thread.Email, thread.Subject,
+			thread.LastActivityAt.Format("2006-01-02"), thread.DaysWaiting(now))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// HasGoogleToken reports whether a Google token is stored, so Gmail checks
+// that run alongside other commands can be skipped quietly without one.
+func HasGoogleToken() bool {
+	_, err := os.Stat(sync.TokenPath())
+	return err == nil
+}
+
+// CheckEmailThreads reads recent Gmail threads with contacts using the stored
+// Google token and marks the ones waiting on a reply for days or more.
+func CheckEmailThreads(client *charm.Client, days int, now time.Time) (*sync.ThreadTrackResult, error) {
+	token, err := sync.LoadToken()
+	if err != nil {
+		return nil, fmt.Errorf("no Google token found, threads can't be checked: %w", err)
+	}
+	service, err := sync.NewGmailClient(token)
+	if err != nil {
+		return nil, err
+	}
+	since := now.AddDate(0, 0, -sync.ThreadLookbackDays)
+	return sync.TrackEmailThreads(context.Background(), service, client, since, days, now)
+}
@@ -0,0 +1,175 @@
+// ABOUTME: Report CLI commands
+// ABOUTME: Data-hygiene report of incomplete contacts, companies, and deals with prompts to fill them in
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/sweet/vault"
+)
+
+// ReportHygieneCommand lists records missing key fields and the overall completeness score.
+func ReportHygieneCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("hygiene", flagErrorHandling)
+	limit := fs.Int("limit", 20, "Maximum records to list per section (0 for all)")
+	fix := fs.Bool("fix", false, "Prompt for each missing field and save the answers")
+	_ = fs.Parse(args)
+
+	report, err := client.GetHygieneReport()
+	if err != nil {
+		return fmt.Errorf("failed to build hygiene report: %w", err)
+	}
+
+	fmt.Printf("Data completeness: %d%% (%d of %d fields filled)\n", report.Score(), report.Filled, report.Checked)
+
+	issues := report.Issues()
+	if len(issues) == 0 {
+		fmt.Println("✓ No missing fields")
+		return nil
+	}
+
+	printHygieneSection("CONTACTS", report.Contacts, *limit)
+	printHygieneSection("COMPANIES", report.Companies, *limit)
+	printHygieneSection("OPEN DEALS", report.Deals, *limit)
+
+	if !*fix {
+		fmt.Println("\nFill in missing fields with: pagen report hygiene --fix")
+		return nil
+	}
+
+	fmt.Println("\nEnter a value for each missing field; leave blank to skip, or type q to stop.")
+	fixed, err := fixHygieneIssues(client, issues, bufio.NewReader(os.Stdin))
+	fmt.Printf("\n✓ Fixed %d field(s)\n", fixed)
+	return err
+}
+
+func printHygieneSection(title string, issues []*charm.HygieneIssue, limit int) {
+	if len(issues) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s (%d incomplete)\n", title, len(issues))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSCORE\tMISSING\tID")
+	_, _ = fmt.Fprintln(w, "----\t-----\t-------\t--")
+
+	for i, issue := range issues {
+		if limit > 0 && i >= limit {
+			_, _ = fmt.Fprintf(w, "... %d more\t\t\t\n", len(issues)-limit)
+			break
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d%%\t%s\t%s\n", issue.Name, issue.Score, strings.Join(issue.Missing, ", "), issue.ID)
+	}
+
+	_ = w.Flush()
+}
+
+// fixHygieneIssues prompts for each missing field and saves the answers,
+// returning how many fields were filled in.
+func fixHygieneIssues(client *charm.Client, issues []*charm.HygieneIssue, in *bufio.Reader) (int, error) {
+	fixed := 0
+	for _, issue := range issues {
+		for _, field := range issue.Missing {
+			fmt.Printf("%s %s — %s: ", issue.EntityType, issue.Name, hygienePrompt(field))
+			line, err := in.ReadString('\n')
+			value := strings.TrimSpace(line)
+			if value == "q" || (err != nil && value == "") {
+				return fixed, nil
+			}
+			if value == "" {
+				continue
+			}
+
+			if err := applyHygieneFix(client, issue, field, value); err != nil {
+				fmt.Printf("  ✗ %v\n", err)
+				continue
+			}
+			fixed++
+		}
+	}
+	return fixed, nil
+}
+
+func hygienePrompt(field string) string {
+	switch field {
+	case charm.HygieneFieldCompany:
+		return "company name"
+	case charm.HygieneFieldTitle:
+		return "job title"
+	case charm.HygieneFieldCloseDate:
+		return "expected close date (YYYY-MM-DD)"
+	}
+	return field
+}
+
+// applyHygieneFix saves one answered field on the issue's record.
+func applyHygieneFix(client *charm.Client, issue *charm.HygieneIssue, field, value string) error {
+	id, err := uuid.Parse(issue.ID)
+	if err != nil {
+		return charm.Validationf("invalid ID: %w", err)
+	}
+
+	switch issue.EntityType {
+	case charm.EntityContact:
+		contact, err := client.GetContact(id)
+		if err != nil {
+			return fmt.Errorf("contact not found: %w", err)
+		}
+		switch field {
+		case charm.HygieneFieldEmail:
+			contact.Email = value
+		case charm.HygieneFieldTitle:
+			contact.Title = value
+		case charm.HygieneFieldCompany:
+			company, err := client.FindCompanyByName(value)
+			if err != nil {
+				return fmt.Errorf("failed to lookup company: %w", err)
+			}
+			if company == nil {
+				company = &charm.Company{Name: value}
+				if err := client.CreateCompany(company); err != nil {
+					return fmt.Errorf("failed to create company: %w", err)
+				}
+			}
+			contact.CompanyID = &company.ID
+			contact.CompanyName = company.Name
+		}
+		if err := client.UpdateContact(contact); err != nil {
+			return fmt.Errorf("failed to update contact: %w", err)
+		}
+		queueContactToVault(client, contact, vault.OpUpsert)
+
+	case charm.EntityCompany:
+		company, err := client.GetCompany(id)
+		if err != nil {
+			return fmt.Errorf("company not found: %w", err)
+		}
+		company.Domain = value
+		if err := client.UpdateCompany(company); err != nil {
+			return fmt.Errorf("failed to update company: %w", err)
+		}
+
+	case charm.EntityDeal:
+		closeDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return charm.Validationf("invalid date (use YYYY-MM-DD): %w", err)
+		}
+		deal, err := client.GetDeal(id)
+		if err != nil {
+			return fmt.Errorf("deal not found: %w", err)
+		}
+		deal.ExpectedCloseDate = &closeDate
+		if err := client.UpdateDeal(deal); err != nil {
+			return fmt.Errorf("failed to update deal: %w", err)
+		}
+	}
+	return nil
+}
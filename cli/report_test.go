@@ -0,0 +1,60 @@
+// ABOUTME: Tests for report CLI commands
+// ABOUTME: Validates the hygiene report and filling in missing fields from prompts
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestFixHygieneIssues(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	deal := &charm.Deal{Title: "Pilot", Stage: charm.StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	if err := ReportHygieneCommand(client, nil); err != nil {
+		t.Fatalf("ReportHygieneCommand failed: %v", err)
+	}
+
+	report, err := client.GetHygieneReport()
+	if err != nil {
+		t.Fatalf("GetHygieneReport failed: %v", err)
+	}
+
+	// Contact email, company, title skipped; then deal close date (bad, so skipped)
+	input := "alice@example.com\nAcme\n\nnot-a-date\n"
+	fixed, err := fixHygieneIssues(client, report.Issues(), bufio.NewReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("fixHygieneIssues failed: %v", err)
+	}
+	if fixed != 2 {
+		t.Errorf("expected 2 fields fixed, got %d", fixed)
+	}
+
+	updated, err := client.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if updated.Email != "alice@example.com" || updated.CompanyName != "Acme" || updated.Title != "" {
+		t.Errorf("unexpected contact after fixes: %+v", updated)
+	}
+	if company, _ := client.FindCompanyByName("Acme"); company == nil {
+		t.Error("expected the typed company to be created")
+	}
+
+	fixed, _ = fixHygieneIssues(client, []*charm.HygieneIssue{{EntityType: charm.EntityDeal, ID: deal.ID.String(), Name: deal.Title, Missing: []string{charm.HygieneFieldCloseDate}}},
+		bufio.NewReader(strings.NewReader("2026-12-31\n")))
+	if got, _ := client.GetDeal(deal.ID); fixed != 1 || got.ExpectedCloseDate == nil {
+		t.Errorf("expected the close date to be set, got %+v", got)
+	}
+}
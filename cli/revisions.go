@@ -0,0 +1,211 @@
+// ABOUTME: Revision history CLI commands
+// ABOUTME: Lists stored revisions of an object and restores a previous one
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// RevisionsCommand lists the stored revisions of an object.
+func RevisionsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("revisions", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	// First positional arg is the object ID
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("object ID is required")
+	}
+
+	id, err := client.ResolveObjectRef(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if id == uuid.Nil {
+		return fmt.Errorf("no object found matching: %s", fs.Arg(0))
+	}
+
+	revisions, err := client.ListRevisions(id)
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	if len(revisions) == 0 {
+		fmt.Println("No revisions found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REV\tOP\tTYPE\tSAVED\tSUMMARY")
+	_, _ = fmt.Fprintln(w, "---\t--\t----\t-----\t-------")
+
+	for _, rev := range revisions {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			rev.Rev, rev.Op, rev.EntityType, rev.CreatedAt.Format("2006-01-02 15:04:05"), rev.Summary())
+	}
+
+	_ = w.Flush()
+	fmt.Printf("\nRestore with: pagen crm restore --rev <n> %s\n", id)
+	return nil
+}
+
+// RestoreCommand restores an object to a previous revision.
+func RestoreCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("restore", flagErrorHandling)
+	rev := fs.Int("rev", 0, "Revision number to restore (required)")
+	_ = fs.Parse(args)
+
+	// First positional arg is the object ID
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("object ID is required")
+	}
+	if *rev <= 0 {
+		return charm.Validationf("--rev is required")
+	}
+
+	id, err := client.ResolveObjectRef(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if id == uuid.Nil {
+		return fmt.Errorf("no object found matching: %s", fs.Arg(0))
+	}
+
+	revision, err := client.RestoreRevision(id, *rev)
+	if err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+
+	fmt.Printf("✓ Restored %s %s to revision %d", revision.EntityType, id, revision.Rev)
+	if summary := revision.Summary(); summary != "" {
+		fmt.Printf(" (%s)", summary)
+	}
+	fmt.Println()
+	return nil
+}
+
+// HistoryCommand shows the audit log of changes to an object, or of all
+// objects when no ID is given, newest first.
+func HistoryCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("history", flagErrorHandling)
+	actor := fs.String("actor", "", "Only changes made through cli, mcp, tui, web, or api")
+	days := fs.Int("days", 0, "Only changes from the last n days (default: all)")
+	limit := fs.Int("limit", 50, "Maximum entries to show (0 = all)")
+	_ = fs.Parse(args)
+
+	filter := &charm.AuditFilter{Actor: *actor, Limit: *limit}
+	if fs.NArg() > 0 {
+		id, err := client.ResolveObjectRef(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if id == uuid.Nil {
+			return fmt.Errorf("no object found matching: %s", fs.Arg(0))
+		}
+		filter.EntityID = &id
+	}
+	if *days > 0 {
+		since := time.Now().AddDate(0, 0, -*days)
+		filter.Since = &since
+	}
+
+	entries, err := client.ListAuditLog(filter)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No changes recorded")
+		return nil
+	}
+
+	// Revisions still stored, per object, for the undo hints
+	stored := make(map[uuid.UUID]map[int]bool)
+	for _, entry := range entries {
+		revs, ok := stored[entry.EntityID]
+		if !ok {
+			revs = make(map[int]bool)
+			revisions, err := client.ListRevisions(entry.EntityID)
+			if err != nil {
+				return fmt.Errorf("failed to list revisions: %w", err)
+			}
+			for _, rev := range revisions {
+				revs[rev.Rev] = true
+			}
+			stored[entry.EntityID] = revs
+		}
+
+		fmt.Printf("%s  %-4s %-8s %s %s", entry.At.Format("2006-01-02 15:04:05"), entry.Actor, entry.Op, entry.EntityType, charm.FormatID(entry.EntityID))
+		if entry.Summary != "" {
+			fmt.Printf(" (%s)", entry.Summary)
+		}
+		if entry.Undoes != nil {
+			fmt.Print(" [undo]")
+		}
+		fmt.Println()
+		for _, change := range entry.Changes {
+			fmt.Printf("    %s: %s → %s\n", change.Field, auditValue(change.Old), auditValue(change.New))
+		}
+		if undo := entry.UndoRev(); undo > 0 && revs[undo] {
+			fmt.Printf("    undo: pagen crm restore --rev %d %s\n", undo, entry.EntityID)
+		}
+	}
+	return nil
+}
+
+// auditValue shortens a JSON field value for display.
+func auditValue(value []byte) string {
+	if len(value) == 0 {
+		return "(none)"
+	}
+	text := []rune(string(value))
+	if len(text) > 60 {
+		return string(text[:57]) + "..."
+	}
+	return string(text)
+}
+
+// UndoCommand reverses the most recent changes in the audit log, newest first.
+func UndoCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("undo", flagErrorHandling)
+	last := fs.Int("last", 1, "How many of the most recent changes to undo")
+	actor := fs.String("actor", "", "Only undo changes made through cli, mcp, tui, web, or api")
+	dryRun := fs.Bool("dry-run", false, "List the changes that would be undone without undoing them")
+	_ = fs.Parse(args)
+
+	if *last < 1 {
+		return fmt.Errorf("--last must be at least 1")
+	}
+
+	changes, err := client.UndoableChanges(*last, *actor)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("Nothing to undo")
+		return nil
+	}
+
+	for i, change := range changes {
+		description := fmt.Sprintf("%s of %s %s", change.Op, change.EntityType, charm.FormatID(change.EntityID))
+		if change.Summary != "" {
+			description += " (" + change.Summary + ")"
+		}
+		description += fmt.Sprintf(" through %s at %s", change.Actor, change.At.Format("2006-01-02 15:04:05"))
+
+		if *dryRun {
+			fmt.Printf("Would undo %s\n", description)
+			continue
+		}
+		if err := client.UndoChange(change); err != nil {
+			return fmt.Errorf("undid %d of %d change(s), then: %w", i, len(changes), err)
+		}
+		fmt.Printf("✓ Undid %s\n", description)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// ABOUTME: Tests for revision history CLI commands
+// ABOUTME: Validates listing revisions and restoring a previous version
+package cli
+
+import (
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestRestoreCommand(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Alice", Email: "alice@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	contact.Email = "wrong@example.com"
+	if err := client.UpdateContact(contact); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+
+	if err := RevisionsCommand(client, []string{contact.ID.String()}); err != nil {
+		t.Errorf("RevisionsCommand failed: %v", err)
+	}
+
+	if err := RestoreCommand(client, []string{"--rev", "1", contact.ID.String()}); err != nil {
+		t.Fatalf("RestoreCommand failed: %v", err)
+	}
+
+	restored, err := client.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if restored.Email != "alice@example.com" {
+		t.Errorf("expected email restored, got %s", restored.Email)
+	}
+
+	if err := RestoreCommand(client, []string{contact.ID.String()}); err == nil {
+		t.Error("expected error without --rev")
+	}
+}
@@ -0,0 +1,61 @@
+// ABOUTME: CRM-wide search command
+// ABOUTME: Ranks contacts, companies, and deals matching a query with the full-text index
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/handlers"
+)
+
+// SearchCommand searches names, emails, notes, and other fields of contacts,
+// companies, and deals, best match first.
+func SearchCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("search", flagErrorHandling)
+	types := fs.String("type", "", "Only these record types, comma-separated: contact, company, deal")
+	limit := fs.Int("limit", 20, "Max results")
+	_ = fs.Parse(args)
+
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("usage: search [--type contact,company,deal] [--limit n] <query>")
+	}
+	var typeList []string
+	if *types != "" {
+		typeList = strings.Split(*types, ",")
+	}
+
+	results, err := handlers.SearchCRM(client, query, typeList, *limit)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Printf("No matches for %q\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TYPE\tNAME\tDETAIL\tID")
+	_, _ = fmt.Fprintln(w, "----\t----\t------\t--")
+	for _, result := range results {
+		detail := result.Detail
+		if detail == "" {
+			detail = "-"
+		}
+		id := result.ID
+		if parsed, err := uuid.Parse(result.ID); err == nil {
+			id = charm.FormatID(parsed)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Type, result.Name, detail, id)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nTotal: %d result(s)\n", len(results))
+	return nil
+}
@@ -0,0 +1,125 @@
+// ABOUTME: Settings export and import CLI commands
+// ABOUTME: Writes rules, templates, preferences, and cadences to a bundle and merges a bundle into this setup
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ConfigExportCommand writes the settings bundle as JSON to stdout or a file.
+func ConfigExportCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("export", flagErrorHandling)
+	output := fs.String("output", "", "Write the bundle to this file instead of stdout")
+	noCadences := fs.Bool("no-cadences", false, "Leave contact cadences out of the bundle")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	bundle := charm.NewSettingsBundle(cfg, time.Now())
+	if !*noCadences {
+		if err := client.ExportCadences(bundle); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("✓ Settings exported to %s (%d cadence(s))\n", *output, len(bundle.Cadences))
+	return nil
+}
+
+// ConfigImportCommand merges a settings bundle into the local config and
+// applies its cadences to matching contacts.
+func ConfigImportCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("import", flagErrorHandling)
+	dryRun := fs.Bool("dry-run", false, "Show what would change without saving")
+	noCadences := fs.Bool("no-cadences", false, "Skip the bundle's contact cadences")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: pagen config import [--dry-run] [--no-cadences] <file|->")
+	}
+	bundle, err := readSettingsBundle(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := bundle.Validate(); err != nil {
+		return charm.Validationf("invalid settings bundle: %w", err)
+	}
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	changes := bundle.MergeInto(cfg)
+	for _, change := range changes {
+		fmt.Printf("✓ %s\n", change)
+	}
+	if len(changes) == 0 {
+		fmt.Println("Settings already match the bundle.")
+	}
+	if !*dryRun && len(changes) > 0 {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	if !*noCadences && len(bundle.Cadences) > 0 {
+		applied, unmatched, err := client.ImportCadences(bundle.Cadences, *dryRun)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Cadences: %d applied\n", applied)
+		if len(unmatched) > 0 {
+			fmt.Printf("No matching contact for %d cadence(s):\n", len(unmatched))
+			for _, name := range unmatched {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("\nDry run - nothing was saved.")
+	}
+	return nil
+}
+
+// readSettingsBundle reads a bundle from a file, or stdin when path is "-".
+func readSettingsBundle(path string) (*charm.SettingsBundle, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var bundle charm.SettingsBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &bundle, nil
+}
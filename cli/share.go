@@ -0,0 +1,156 @@
+// ABOUTME: Share link CLI commands for read-only deal and company status pages
+// ABOUTME: Creates, lists, and revokes share tokens, and turns public share pages on or off
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/web"
+)
+
+// defaultShareDays is how long a new share link works unless --days says otherwise.
+const defaultShareDays = 30
+
+// WebShareCommand manages share links:
+// pagen web share [list] | deal <ref> | company <ref> | revoke <id> | on | off
+func WebShareCommand(client *charm.Client, args []string) error {
+	action := ""
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "", "list":
+		return shareListCommand(client)
+	case charm.EntityDeal, charm.EntityCompany:
+		return shareCreateCommand(client, action, args)
+	case "revoke":
+		return shareRevokeCommand(client, args)
+	case "on", "off":
+		return shareToggleCommand(action == "on")
+	default:
+		return fmt.Errorf("usage: web share [list] | deal <ref> | company <ref> | revoke <id> | on | off")
+	}
+}
+
+func shareCreateCommand(client *charm.Client, entityType string, args []string) error {
+	fs := flag.NewFlagSet(entityType, flagErrorHandling)
+	days := fs.Int("days", defaultShareDays, "Days until the link stops working (0 = never)")
+	_ = fs.Parse(args)
+
+	// First positional arg is the deal or company, flags may follow it
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("%s ID is required", entityType)
+	}
+	ref := fs.Arg(0)
+	_ = fs.Parse(fs.Args()[1:])
+	if *days < 0 {
+		return fmt.Errorf("--days cannot be negative")
+	}
+
+	id, err := resolveID(client, ref, entityType)
+	if err != nil {
+		return err
+	}
+	share, err := client.CreateShare(entityType, id, time.Duration(*days)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Shared %s %s\n", entityType, share.Name)
+	fmt.Printf("  %s\n", web.ShareURL(shareBaseURL(), share))
+	if share.ExpiresAt != nil {
+		fmt.Printf("  Expires %s\n", share.ExpiresAt.Format("2006-01-02"))
+	}
+	if cfg := client.Config(); cfg == nil || !cfg.WebSharing {
+		fmt.Println("  Share pages are off; run 'pagen web share on' and restart 'pagen web' to serve it.")
+	}
+	return nil
+}
+
+func shareListCommand(client *charm.Client) error {
+	shares, err := client.ListShares()
+	if err != nil {
+		return fmt.Errorf("failed to list shares: %w", err)
+	}
+	if cfg := client.Config(); cfg == nil || !cfg.WebSharing {
+		fmt.Println("Share pages: off (enable with 'pagen web share on')")
+	} else {
+		fmt.Println("Share pages: on")
+	}
+	if len(shares) == 0 {
+		fmt.Println("No share links")
+		return nil
+	}
+
+	baseURL := shareBaseURL()
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tNAME\tEXPIRES\tURL")
+	_, _ = fmt.Fprintln(w, "--\t----\t----\t-------\t---")
+	for _, share := range shares {
+		expires := "never"
+		if share.Expired(now) {
+			expires = "expired"
+		} else if share.ExpiresAt != nil {
+			expires = share.ExpiresAt.Format("2006-01-02")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			charm.FormatID(share.ID), share.EntityType, share.Name, expires, web.ShareURL(baseURL, share))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+func shareRevokeCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("revoke", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("share ID is required")
+	}
+	share, err := client.RevokeShare(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Revoked share of %s %s\n", share.EntityType, share.Name)
+	return nil
+}
+
+// shareToggleCommand turns public share pages on or off. A running server
+// picks the change up when restarted.
+func shareToggleCommand(enabled bool) error {
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.WebSharing = enabled
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if enabled {
+		fmt.Println("✓ Share pages on")
+		if cfg.WebBaseURL == "" {
+			fmt.Println("  Set web_base_url to the server's public URL so share links point at it.")
+		}
+	} else {
+		fmt.Println("✓ Share pages off (links stay valid if turned back on; revoke them to remove)")
+	}
+	fmt.Println("  Restart `pagen web` to apply.")
+	return nil
+}
+
+// shareBaseURL is where share links point: the configured public URL of the
+// web UI, or the local server.
+func shareBaseURL() string {
+	if cfg, err := charm.LoadConfig(); err == nil && cfg.WebBaseURL != "" {
+		return cfg.WebBaseURL
+	}
+	return "http://localhost:10666"
+}
@@ -0,0 +1,102 @@
+// ABOUTME: Spreadsheet round-trip CLI commands for contacts and deals
+// ABOUTME: Exports an editable CSV with object IDs and imports only the changed cells back
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ExportSheetCommand writes contacts or deals as an editable sheet:
+// pagen crm export sheet --type contacts|deals [--output file.csv]
+func ExportSheetCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("export sheet", flagErrorHandling)
+	sheetType := fs.String("type", "", "What to export: "+strings.Join(charm.SheetTypes, ", ")+" (required)")
+	output := fs.String("output", "", "Write to this file instead of stdout")
+	_ = fs.Parse(args)
+
+	if *sheetType == "" {
+		return charm.Validationf("--type is required (%s)", strings.Join(charm.SheetTypes, ", "))
+	}
+
+	if *output == "" {
+		_, err := client.ExportSheet(os.Stdout, *sheetType)
+		return err
+	}
+
+	f, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	count, err := client.ExportSheet(f, *sheetType)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write %s: %w", *output, closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(*output)
+		return err
+	}
+
+	fmt.Printf("✓ Exported %d %s to %s\n", count, *sheetType, *output)
+	fmt.Printf("  Edit it, then: pagen crm import sheet --type %s --file %s\n", *sheetType, *output)
+	return nil
+}
+
+// ImportCommand dispatches pagen crm import subcommands.
+func ImportCommand(client *charm.Client, args []string) error {
+	if len(args) == 0 || args[0] != "sheet" {
+		return charm.Validationf("usage: pagen crm import sheet --type %s --file <file.csv>", strings.Join(charm.SheetTypes, "|"))
+	}
+	return ImportSheetCommand(client, args[1:])
+}
+
+// ImportSheetCommand applies an edited sheet:
+// pagen crm import sheet --type contacts|deals --file file.csv [--dry-run] [--force]
+func ImportSheetCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("import sheet", flagErrorHandling)
+	sheetType := fs.String("type", "", "What the sheet holds: "+strings.Join(charm.SheetTypes, ", ")+" (required)")
+	file := fs.String("file", "", "Edited sheet from pagen crm export sheet (required)")
+	dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+	force := fs.Bool("force", false, "Apply cells edited in both pagen and the sheet since the export")
+	_ = fs.Parse(args)
+
+	if *sheetType == "" {
+		return charm.Validationf("--type is required (%s)", strings.Join(charm.SheetTypes, ", "))
+	}
+	if *file == "" {
+		return charm.Validationf("--file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open sheet: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	result, err := client.ImportSheet(f, *sheetType, charm.SheetImportOptions{DryRun: *dryRun, Force: *force})
+	if err != nil {
+		return err
+	}
+
+	for _, change := range result.Changes {
+		if change.Column == "" {
+			fmt.Printf("  row %d: + %s\n", change.Row, change.Name)
+			continue
+		}
+		fmt.Printf("  row %d: %s %s: %q → %q\n", change.Row, change.Name, change.Column, change.Old, change.New)
+	}
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("✓ %s %s: %d updated, %d created, %d unchanged, %d skipped\n",
+		verb, *sheetType, result.Updated, result.Created, result.Unchanged, len(result.Problems))
+	for _, problem := range result.Problems {
+		fmt.Printf("  ⚠ %s\n", problem)
+	}
+	return nil
+}
@@ -0,0 +1,605 @@
+// ABOUTME: Interactive CRM shell (REPL) command
+// ABOUTME: Runs CLI commands against a warm client with history, name completion, and guided flows
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/term"
+)
+
+// flagErrorHandling controls how command flag sets react to bad flags.
+// The CLI exits on errors; the shell switches to panics so it can recover
+// and return to the prompt instead of terminating the session.
+var flagErrorHandling = flag.ExitOnError
+
+// shellCommand is a CLI command runnable from the shell.
+type shellCommand struct {
+	run     func(client *charm.Client, args []string) error
+	mutates bool // refresh the name index after running
+	help    string
+}
+
+// shellCommands mirrors the CLI command tree. Keys are the words typed after `pagen`.
+var shellCommands = map[string]shellCommand{
+	"crm search":                {SearchCommand, false, "Search contacts, companies, and deals"},
+	"crm add-contact":           {AddContactCommand, true, "Add a new contact"},
+	"crm list-contacts":         {ListContactsCommand, false, "List contacts"},
+	"crm update-contact":        {UpdateContactCommand, true, "Update a contact"},
+	"crm delete-contact":        {DeleteContactCommand, true, "Delete a contact"},
+	"crm import-contacts":       {ImportContactsCommand, true, "Import contacts from a CSV or vCard file"},
+	"crm fill-met":              {FillMetCommand, false, "Fill in how we met from imported interactions"},
+	"crm archive-stale":         {ArchiveStaleCommand, true, "List or archive stale synced contacts"},
+	"crm archive-policy":        {ArchivePolicyCommand, false, "Show or change automatic archiving"},
+	"crm unarchive-contact":     {UnarchiveContactCommand, true, "Return an archived contact to lists"},
+	"crm pin":                   {PinCommand, true, "Pin a key fact on a contact, or list its pins"},
+	"crm unpin":                 {UnpinCommand, true, "Remove a pinned fact from a contact"},
+	"crm add-company":           {AddCompanyCommand, true, "Add a new company"},
+	"crm list-companies":        {ListCompaniesCommand, false, "List companies"},
+	"crm update-company":        {UpdateCompanyCommand, true, "Update a company"},
+	"crm delete-company":        {DeleteCompanyCommand, true, "Delete a company"},
+	"crm infer-companies":       {InferCompaniesCommand, true, "Link contacts to companies by email domain"},
+	"crm import-companies":      {ImportCompaniesCommand, true, "Import company size, funding, and HQ from a CSV"},
+	"crm add-deal":              {AddDealCommand, true, "Add a new deal"},
+	"crm list-deals":            {ListDealsCommand, false, "List deals"},
+	"crm delete-deal":           {DeleteDealCommand, true, "Delete a deal"},
+	"crm deal-notes":            {DealNotesCommand, false, "Show a deal's notes as plain text"},
+	"crm stage-requirements":    {StageRequirementsCommand, false, "Show or set required fields per stage"},
+	"crm deal add-contact":      {DealAddContactCommand, false, "Add a contact to a deal with a role"},
+	"crm deal remove-contact":   {DealRemoveContactCommand, false, "Remove a contact from a deal"},
+	"crm deal contacts":         {DealContactsCommand, false, "List contacts and roles on a deal"},
+	"crm tag":                   {TagCommand, true, "Tag a contact, company, or deal"},
+	"crm untag":                 {UntagCommand, true, "Remove tags from a contact, company, or deal"},
+	"crm tags list":             {TagsListCommand, false, "List tags in use"},
+	"crm tags find":             {TagsFindCommand, false, "Find contacts, companies, and deals with a tag"},
+	"crm tags derive":           {TagsDeriveCommand, false, "Suggest topic tags from meeting titles and email subjects"},
+	"crm tags suggestions":      {TagsSuggestionsCommand, false, "List tag suggestions waiting for review"},
+	"crm tags accept":           {TagsAcceptCommand, true, "Add suggested tags to their contacts"},
+	"crm tags reject":           {TagsRejectCommand, false, "Reject suggested tags"},
+	"crm events add":            {EventsAddCommand, false, "Add an event"},
+	"crm events list":           {EventsListCommand, false, "List events"},
+	"crm events attend":         {EventsAttendCommand, false, "Record contacts attending an event"},
+	"crm events attendees":      {EventsAttendeesCommand, false, "List who attended an event"},
+	"crm events report":         {EventsReportCommand, false, "Contacts and deals sourced per event"},
+	"crm events delete":         {EventsDeleteCommand, false, "Delete an event"},
+	"crm update-relationship":   {UpdateRelationshipCommand, true, "Update a relationship"},
+	"crm delete-relationship":   {DeleteRelationshipCommand, true, "Delete a relationship"},
+	"crm revisions":             {RevisionsCommand, false, "List revisions of an object"},
+	"crm restore":               {RestoreCommand, true, "Restore an object to a revision"},
+	"crm asof":                  {AsOfCommand, false, "List records as of a past date"},
+	"crm copy":                  {CopyContactsCommand, false, "Copy contacts to the clipboard as CSV or Markdown"},
+	"crm export":                {ExportCommand, false, "Export contacts, companies, deals, or interactions as CSV or JSON"},
+	"crm email":                 {EmailCommand, true, "Draft a templated email to a contact"},
+	"crm outreach":              {OutreachCommand, true, "List or check outreach awaiting replies"},
+	"crm capture":               {CaptureCommand, true, "Save a line as a task or interaction"},
+	"crm list-tasks":            {ListTasksCommand, false, "List open tasks"},
+	"crm complete-task":         {CompleteTaskCommand, true, "Mark a task done"},
+	"followups list":            {FollowupListCommand, false, "List contacts needing follow-up"},
+	"followups log":             {LogInteractionCommand, false, "Log an interaction"},
+	"followups set-cadence":     {SetCadenceCommand, false, "Set follow-up cadence"},
+	"followups schedule":        {ScheduleFollowupCommand, false, "Schedule a catch-up in an open calendar slot"},
+	"followups stats":           {FollowupStatsCommand, false, "Show network health stats"},
+	"followups digest":          {DigestCommand, false, "Generate follow-up digest"},
+	"followups recompute":       {RecomputePrioritiesCommand, false, "Recompute priority scores"},
+	"followups tracking":        {EmailTrackingCommand, false, "Show or change email tracking"},
+	"followups track-email":     {TrackEmailCommand, false, "Create a tracked follow-up email"},
+	"followups tracked":         {TrackedEmailsCommand, false, "List tracked emails"},
+	"followups pending-replies": {PendingRepliesCommand, false, "List emails waiting on a contact's reply"},
+	"greetings today":           {GreetingsTodayCommand, false, "Birthdays, anniversaries, and holidays due"},
+	"greetings holidays":        {GreetingsHolidaysCommand, false, "List or change greeted holidays"},
+	"greetings met":             {GreetingsMetCommand, false, "List or change met anniversary reminders"},
+	"greetings template":        {GreetingsTemplateCommand, false, "Show or change greeting draft templates"},
+	"watch deal":                {WatchDealCommand, false, "Notify when a deal changes"},
+	"watch contact":             {WatchContactCommand, false, "Notify when a contact changes"},
+	"watch list":                {WatchListCommand, false, "List watched deals and contacts"},
+	"watch remove":              {WatchRemoveCommand, false, "Stop watching a deal or contact"},
+	"news fetch":                {NewsFetchCommand, false, "Fetch company headlines from news feeds"},
+	"news list":                 {NewsListCommand, false, "Show stored company headlines"},
+	"news feeds":                {NewsFeedsCommand, false, "List or change news feeds"},
+	"config export":             {ConfigExportCommand, false, "Export rules, templates, and cadences as JSON"},
+	"config import":             {ConfigImportCommand, true, "Import a settings bundle"},
+	"db maintain":               {DBMaintainCommand, false, "Check integrity, VACUUM, and show table sizes"},
+	"report hygiene":            {ReportHygieneCommand, true, "Incomplete records and data completeness score"},
+	"viz graph all":             {VizGraphAllCommand, false, "Generate complete graph"},
+	"viz graph contacts":        {VizGraphContactsCommand, false, "Generate contact network graph"},
+	"viz graph company":         {VizGraphCompanyCommand, false, "Generate company org chart"},
+	"viz graph deal":            {VizGraphDealCommand, false, "Generate a deal's influencer map"},
+	"viz graph pipeline":        {VizGraphPipelineCommand, false, "Generate deal pipeline graph"},
+	"viz places":                {VizPlacesCommand, false, "Cities where you meet people in person"},
+	"viz trend":                 {VizTrendCommand, true, "Open pipeline over time"},
+	"viz snapshot":              {VizSnapshotCommand, true, "Snapshot this week's pipeline"},
+	"viz sources":               {VizSourcesCommand, false, "Pipeline and win rate by deal source"},
+	"sync viz":                  {SyncVizCommand, false, "Diagram of sync devices, cloud, and outbox"},
+	"web maintenance":           {WebMaintenanceCommand, false, "Show or toggle web maintenance mode"},
+	"web auth":                  {WebAuthCommand, false, "Show or change web UI login"},
+	"web healthcheck":           {WebHealthcheckCommand, false, "Check that the local web server is healthy"},
+	"logs web":                  {LogsWebCommand, false, "Show the web server request log"},
+}
+
+// shellBuiltins are commands handled by the shell itself.
+var shellBuiltins = map[string]string{
+	"help":        "Show this help",
+	"new-contact": "Guided flow: create a contact, link a company, log an interaction",
+	"exit":        "Leave the shell",
+}
+
+// shellContactFlags and shellCompanyFlags complete to contact and company names.
+var (
+	shellContactFlags = map[string]bool{"--contact": true, "-contact": true}
+	shellCompanyFlags = map[string]bool{"--company": true, "-company": true}
+)
+
+// Shell is an interactive session over a single CRM client.
+type Shell struct {
+	client    *charm.Client
+	out       io.Writer
+	contacts  []string
+	companies []string
+
+	terminal *term.Terminal
+	fd       int
+	scanner  *bufio.Scanner
+}
+
+// ShellCommand starts the interactive CRM shell.
+func ShellCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("shell", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	sh := NewShell(client, os.Stdin, os.Stdout)
+	return sh.Run()
+}
+
+// NewShell creates a shell reading from in. A terminal gets line editing,
+// history, and tab completion; anything else is read line by line.
+func NewShell(client *charm.Client, in *os.File, out io.Writer) *Shell {
+	sh := &Shell{client: client, out: out, fd: int(in.Fd())}
+
+	if term.IsTerminal(sh.fd) {
+		sh.terminal = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{in, out}, "pagen> ")
+		sh.terminal.AutoCompleteCallback = sh.autoComplete
+	} else {
+		sh.scanner = bufio.NewScanner(in)
+	}
+
+	return sh
+}
+
+// Run reads and executes commands until exit or EOF.
+func (sh *Shell) Run() error {
+	// Commands run many times per session, so keep bad flags from exiting.
+	flagErrorHandling = flag.PanicOnError
+	defer func() { flagErrorHandling = flag.ExitOnError }()
+
+	if err := sh.refreshNames(); err != nil {
+		return err
+	}
+
+	if sh.terminal != nil {
+		_, _ = fmt.Fprintln(sh.out, "pagen shell - type 'help' for commands, tab to complete, 'exit' to quit")
+	}
+
+	for {
+		line, err := sh.readLine("pagen> ")
+		if err == io.EOF {
+			_, _ = fmt.Fprintln(sh.out)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if done := sh.Execute(line); done {
+			return nil
+		}
+	}
+}
+
+// Execute runs a single shell line. Returns true when the shell should exit.
+func (sh *Shell) Execute(line string) bool {
+	words, err := splitShellWords(line)
+	if err != nil {
+		_, _ = fmt.Fprintf(sh.out, "Error: %v\n", err)
+		return false
+	}
+	if len(words) == 0 {
+		return false
+	}
+	if words[0] == "pagen" {
+		words = words[1:]
+		if len(words) == 0 {
+			return false
+		}
+	}
+
+	switch words[0] {
+	case "exit", "quit":
+		return true
+	case "help", "?":
+		sh.printHelp()
+		return false
+	case "new-contact":
+		if err := sh.newContactFlow(); err != nil {
+			_, _ = fmt.Fprintf(sh.out, "Error: %v\n", err)
+		}
+		_ = sh.refreshNames()
+		return false
+	}
+
+	cmd, args, ok := lookupShellCommand(words)
+	if !ok {
+		_, _ = fmt.Fprintf(sh.out, "Unknown command: %s (type 'help' for commands)\n", strings.Join(words, " "))
+		return false
+	}
+
+	if err := sh.runCommand(cmd, args); err != nil {
+		_, _ = fmt.Fprintf(sh.out, "Error: %v\n", err)
+	}
+	if cmd.mutates {
+		if err := sh.refreshNames(); err != nil {
+			_, _ = fmt.Fprintf(sh.out, "warning: %v\n", err)
+		}
+	}
+	return false
+}
+
+// runCommand runs a CLI command, turning flag parse panics into errors.
+func (sh *Shell) runCommand(cmd shellCommand, args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok && errors.Is(e, flag.ErrHelp) {
+				err = nil
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return cmd.run(sh.client, args)
+}
+
+// lookupShellCommand finds the longest command matching the leading words.
+// CRM commands may be typed without the `crm` prefix.
+func lookupShellCommand(words []string) (shellCommand, []string, bool) {
+	for n := 3; n >= 1; n-- {
+		if len(words) < n {
+			continue
+		}
+		name := strings.Join(words[:n], " ")
+		if cmd, ok := shellCommands[name]; ok {
+			return cmd, words[n:], true
+		}
+		if cmd, ok := shellCommands["crm "+name]; ok {
+			return cmd, words[n:], true
+		}
+	}
+	return shellCommand{}, nil, false
+}
+
+// newContactFlow walks through creating a contact, linking a company, and
+// logging a first interaction.
+func (sh *Shell) newContactFlow() error {
+	name, err := sh.ask("Name: ")
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return charm.Validationf("name is required")
+	}
+	email, err := sh.ask("Email (optional): ")
+	if err != nil {
+		return err
+	}
+	company, err := sh.ask("Company (optional, created if new): ")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"--name", name}
+	if email != "" {
+		args = append(args, "--email", email)
+	}
+	if company != "" {
+		args = append(args, "--company", company)
+	}
+	if err := sh.runCommand(shellCommands["crm add-contact"], args); err != nil {
+		return err
+	}
+
+	contact, err := sh.newestContactNamed(name)
+	if err != nil {
+		return err
+	}
+
+	interactionType, err := sh.ask("Log interaction type (meeting/call/email/message/event, blank to skip): ")
+	if err != nil || interactionType == "" {
+		return err
+	}
+	notes, err := sh.ask("Notes (optional): ")
+	if err != nil {
+		return err
+	}
+
+	return sh.runCommand(shellCommands["followups log"], []string{
+		"--contact", contact.ID.String(),
+		"--type", interactionType,
+		"--notes", notes,
+	})
+}
+
+// newestContactNamed returns the most recently created contact with the exact name.
+func (sh *Shell) newestContactNamed(name string) (*charm.Contact, error) {
+	contacts, err := sh.client.ListContacts(&charm.ContactFilter{Query: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find contact: %w", err)
+	}
+
+	var newest *charm.Contact
+	for _, contact := range contacts {
+		if !strings.EqualFold(contact.Name, name) {
+			continue
+		}
+		if newest == nil || contact.CreatedAt.After(newest.CreatedAt) {
+			newest = contact
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no contact found matching: %s", name)
+	}
+	return newest, nil
+}
+
+// refreshNames reloads the contact and company names used for completion.
+func (sh *Shell) refreshNames() error {
+	contacts, err := sh.client.ListContacts(nil)
+	if err != nil {
+		return fmt.Errorf("failed to load contacts: %w", err)
+	}
+	companies, err := sh.client.ListCompanies(nil)
+	if err != nil {
+		return fmt.Errorf("failed to load companies: %w", err)
+	}
+
+	sh.contacts = sh.contacts[:0]
+	for _, contact := range contacts {
+		sh.contacts = append(sh.contacts, contact.Name)
+	}
+	sh.companies = sh.companies[:0]
+	for _, company := range companies {
+		sh.companies = append(sh.companies, company.Name)
+	}
+	sort.Strings(sh.contacts)
+	sort.Strings(sh.companies)
+	return nil
+}
+
+func (sh *Shell) printHelp() {
+	_, _ = fmt.Fprintln(sh.out, "Commands (same flags as the CLI, `crm` prefix optional):")
+
+	names := make([]string, 0, len(shellCommands))
+	for name := range shellCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(sh.out, "  %-26s %s\n", name, shellCommands[name].help)
+	}
+
+	_, _ = fmt.Fprintln(sh.out, "\nShell:")
+	for _, name := range []string{"new-contact", "help", "exit"} {
+		_, _ = fmt.Fprintf(sh.out, "  %-26s %s\n", name, shellBuiltins[name])
+	}
+}
+
+// readLine reads a line with the given prompt. Terminal input is read in raw
+// mode, which is restored before commands print their output.
+func (sh *Shell) readLine(prompt string) (string, error) {
+	if sh.terminal == nil {
+		if !sh.scanner.Scan() {
+			if err := sh.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return sh.scanner.Text(), nil
+	}
+
+	state, err := term.MakeRaw(sh.fd)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = term.Restore(sh.fd, state) }()
+
+	sh.terminal.SetPrompt(prompt)
+	return sh.terminal.ReadLine()
+}
+
+// ask prompts for a single answer during a guided flow.
+func (sh *Shell) ask(prompt string) (string, error) {
+	if sh.terminal == nil {
+		_, _ = fmt.Fprint(sh.out, prompt)
+	}
+	answer, err := sh.readLine(prompt)
+	if sh.terminal == nil {
+		_, _ = fmt.Fprintln(sh.out)
+	}
+	return strings.TrimSpace(answer), err
+}
+
+// autoComplete implements tab completion for term.Terminal.
+func (sh *Shell) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head, ok := completeShellLine(line[:pos], sh.contacts, sh.companies)
+	if !ok {
+		return "", 0, false
+	}
+	return head + line[pos:], len(head), true
+}
+
+// completeShellLine completes the last word of line: a command word, or a
+// contact/company name after --contact/--company. Returns the new line.
+func completeShellLine(line string, contacts, companies []string) (string, bool) {
+	start, partial := lastShellWord(line)
+	words, err := splitShellWords(line[:start])
+	if err != nil {
+		return "", false
+	}
+
+	var candidates []string
+	if len(words) > 0 && shellContactFlags[words[len(words)-1]] {
+		candidates = contacts
+	} else if len(words) > 0 && shellCompanyFlags[words[len(words)-1]] {
+		candidates = companies
+	} else {
+		candidates = shellCommandWords(words)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(partial)) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	completion := commonPrefix(matches)
+	if len(completion) < len(partial) {
+		return "", false
+	}
+	if len(matches) == 1 {
+		if strings.ContainsAny(completion, " \t") {
+			completion = `"` + completion + `"`
+		}
+		completion += " "
+	}
+	return line[:start] + completion, true
+}
+
+// shellCommandWords lists the words that can follow the given command words.
+func shellCommandWords(words []string) []string {
+	prefix := strings.Join(words, " ")
+	seen := make(map[string]bool)
+
+	var next []string
+	add := func(name string) {
+		rest := name
+		if prefix != "" {
+			if !strings.HasPrefix(name, prefix+" ") {
+				return
+			}
+			rest = strings.TrimPrefix(name, prefix+" ")
+		}
+		word := strings.Fields(rest)[0]
+		if !seen[word] {
+			seen[word] = true
+			next = append(next, word)
+		}
+	}
+
+	for name := range shellCommands {
+		add(name)
+		add(strings.TrimPrefix(name, "crm "))
+	}
+	if prefix == "" {
+		for name := range shellBuiltins {
+			add(name)
+		}
+	}
+
+	sort.Strings(next)
+	return next
+}
+
+// lastShellWord returns where the word being typed starts, and its unquoted text.
+func lastShellWord(line string) (int, string) {
+	inQuote := false
+	start := 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			if !inQuote {
+				start = i
+			}
+			inQuote = !inQuote
+		case (r == ' ' || r == '\t') && !inQuote:
+			start = i + 1
+		}
+	}
+	return start, strings.TrimPrefix(line[start:], `"`)
+}
+
+// commonPrefix returns the longest case-insensitive common prefix, using the
+// casing of the first match.
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		n := 0
+		for n < len(prefix) && n < len(value) && strings.EqualFold(prefix[n:n+1], value[n:n+1]) {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return prefix
+}
+
+// splitShellWords splits a line into words, honoring double and single
+// quotes and backslash escapes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,139 @@
+// ABOUTME: Tests for the interactive CRM shell
+// ABOUTME: Validates word splitting, completion, dispatch, and guided flows
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	words, err := splitShellWords(`add-contact --name "Alice Smith" --notes 'met at "conf"' --email a\ b`)
+	if err != nil {
+		t.Fatalf("splitShellWords failed: %v", err)
+	}
+
+	expected := []string{"add-contact", "--name", "Alice Smith", "--notes", `met at "conf"`, "--email", "a b"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("expected %q, got %q", expected, words)
+	}
+
+	if _, err := splitShellWords(`--name "Alice`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestCompleteShellLine(t *testing.T) {
+	contacts := []string{"Alice Smith", "Alan Turing", "Bob"}
+	companies := []string{"Acme Corp"}
+
+	tests := []struct {
+		line string
+		want string
+		ok   bool
+	}{
+		{"follow", "followups ", true},
+		{"followups se", "followups set-cadence ", true},
+		{"crm list-c", "crm list-co", true},
+		{"log --contact ali", `log --contact "Alice Smith" `, true},
+		{"log --contact Al", "log --contact Al", true},
+		{"add-contact --company ac", `add-contact --company "Acme Corp" `, true},
+		{"log --contact zed", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := completeShellLine(tt.line, contacts, companies)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("completeShellLine(%q) = %q, %v; want %q, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLookupShellCommand(t *testing.T) {
+	if _, args, ok := lookupShellCommand([]string{"add-contact", "--name", "Alice"}); !ok || len(args) != 2 {
+		t.Errorf("expected bare crm command to resolve, got ok=%v args=%v", ok, args)
+	}
+	if _, args, ok := lookupShellCommand([]string{"viz", "graph", "pipeline", "--output", "x.dot"}); !ok || len(args) != 2 {
+		t.Errorf("expected three-word command to resolve, got ok=%v args=%v", ok, args)
+	}
+	if _, _, ok := lookupShellCommand([]string{"list"}); ok {
+		t.Error("expected ambiguous bare 'list' not to resolve")
+	}
+}
+
+// newScriptedShell returns a shell that reads the given input lines.
+func newScriptedShell(t *testing.T, client *charm.Client, input string) (*Shell, *bytes.Buffer) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open input: %v", err)
+	}
+	t.Cleanup(func() { _ = in.Close() })
+
+	var out bytes.Buffer
+	return NewShell(client, in, &out), &out
+}
+
+func TestShellSurvivesBadFlags(t *testing.T) {
+	client := charm.NewTestClient(t)
+	sh, out := newScriptedShell(t, client, "list-contacts --bogus\nadd-contact --name Alice\n")
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Error:") {
+		t.Errorf("expected bad flag to be reported, got %q", out.String())
+	}
+
+	contacts, err := client.ListContacts(nil)
+	if err != nil {
+		t.Fatalf("failed to list contacts: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Errorf("expected shell to keep running after bad flags, got %d contacts", len(contacts))
+	}
+	if flagErrorHandling != flag.ExitOnError {
+		t.Errorf("expected flag handling to be restored after the shell exits")
+	}
+}
+
+func TestShellNewContactFlow(t *testing.T) {
+	client := charm.NewTestClient(t)
+	sh, _ := newScriptedShell(t, client, "new-contact\nAlice Smith\nalice@acme.com\nAcme Corp\ncall\nIntro call\nexit\n")
+
+	if err := sh.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	contact, err := sh.newestContactNamed("Alice Smith")
+	if err != nil {
+		t.Fatalf("expected contact to be created: %v", err)
+	}
+	if contact.CompanyName != "Acme Corp" {
+		t.Errorf("expected company to be linked, got %q", contact.CompanyName)
+	}
+
+	logs, err := client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &contact.ID})
+	if err != nil {
+		t.Fatalf("failed to list interactions: %v", err)
+	}
+	if len(logs) != 1 || logs[0].InteractionType != charm.InteractionCall {
+		t.Errorf("expected one logged call, got %+v", logs)
+	}
+
+	if !reflect.DeepEqual(sh.companies, []string{"Acme Corp"}) {
+		t.Errorf("expected name index to be refreshed, got %v", sh.companies)
+	}
+}
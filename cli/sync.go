@@ -87,7 +87,7 @@ func SyncAllCommand(database *sql.DB) error {
 
 // SyncInitCommand handles OAuth setup.
 func SyncInitCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs := flag.NewFlagSet("init", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	ctx := context.Background()
@@ -157,7 +157,7 @@ func SyncInitCommand(database *sql.DB, args []string) error {
 
 // SyncContactsCommand syncs Google Contacts.
 func SyncContactsCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("contacts", flag.ExitOnError)
+	fs := flag.NewFlagSet("contacts", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Load OAuth token
@@ -182,7 +182,7 @@ func SyncContactsCommand(database *sql.DB, args []string) error {
 
 // SyncCalendarCommand syncs Google Calendar events.
 func SyncCalendarCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	fs := flag.NewFlagSet("calendar", flagErrorHandling)
 	initial := fs.Bool("initial", false, "Full import (last 6 months)")
 	_ = fs.Parse(args)
 
@@ -208,7 +208,7 @@ func SyncCalendarCommand(database *sql.DB, args []string) error {
 
 // SyncGmailCommand syncs Gmail emails.
 func SyncGmailCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("gmail", flag.ExitOnError)
+	fs := flag.NewFlagSet("gmail", flagErrorHandling)
 	initial := fs.Bool("initial", false, "Import last 30 days")
 	_ = fs.Parse(args)
 
@@ -274,7 +274,7 @@ func SyncResetCommand(database *sql.DB, args []string) error {
 
 // SyncStatusCommand displays the sync status for all Google services.
 func SyncStatusCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	fs := flag.NewFlagSet("status", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Get all sync states
@@ -410,7 +410,7 @@ func openBrowser(url string) error {
 
 // SyncDaemonCommand runs sync in daemon mode with configurable interval.
 func SyncDaemonCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	fs := flag.NewFlagSet("daemon", flagErrorHandling)
 	interval := fs.String("interval", "1h", "Sync interval (e.g., 15m, 1h, 4h)")
 	servicesStr := fs.String("services", "all", "Comma-separated services to sync (contacts,calendar,gmail,all)")
 	_ = fs.Parse(args)
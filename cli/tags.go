@@ -0,0 +1,257 @@
+// ABOUTME: Tag CLI commands for contacts, companies, and deals
+// ABOUTME: Adds, removes, lists, and finds tags, and derives suggested contact tags from interaction content
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// TagCommand adds tags to a contact, company, or deal:
+// pagen crm tag <ref> <tag>...
+func TagCommand(client *charm.Client, args []string) error {
+	return retagCommand(client, "tag", args)
+}
+
+// UntagCommand removes tags from a contact, company, or deal:
+// pagen crm untag <ref> <tag>...
+func UntagCommand(client *charm.Client, args []string) error {
+	return retagCommand(client, "untag", args)
+}
+
+func retagCommand(client *charm.Client, action string, args []string) error {
+	fs := flag.NewFlagSet(action, flagErrorHandling)
+	_ = fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return fmt.Errorf("usage: crm %s <contact|company|deal> <tag>...", action)
+	}
+	tags := charm.ParseTags(strings.Join(fs.Args()[1:], ","))
+	if len(tags) == 0 {
+		return charm.Validationf("at least one tag is required")
+	}
+
+	id, err := resolveID(client, fs.Arg(0), charm.TaggableTypes...)
+	if err != nil {
+		return err
+	}
+	var obj *charm.TaggedObject
+	if action == "tag" {
+		obj, err = client.UpdateTags(id, tags, nil)
+	} else {
+		obj, err = client.UpdateTags(id, nil, tags)
+	}
+	if err != nil {
+		return err
+	}
+
+	current := "none"
+	if len(obj.Tags) > 0 {
+		current = strings.Join(obj.Tags, ", ")
+	}
+	fmt.Printf("✓ %s %s tags: %s\n", obj.EntityType, obj.Name, current)
+	return nil
+}
+
+// TagsListCommand lists every tag in use with how many objects have it.
+func TagsListCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	tags, err := client.ListTags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+	if len(tags) == 0 {
+		fmt.Println("No tags")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TAG\tCONTACTS\tCOMPANIES\tDEALS")
+	_, _ = fmt.Fprintln(w, "---\t--------\t---------\t-----")
+	for _, tc := range tags {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", tc.Tag, tc.Contacts, tc.Companies, tc.Deals)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// TagsFindCommand lists the contacts, companies, and deals with a tag:
+// pagen crm tags find <tag> [--type contact,company,deal]
+func TagsFindCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("find", flagErrorHandling)
+	types := fs.String("type", "", "Only these types, comma-separated: contact, company, deal")
+	_ = fs.Parse(args)
+
+	// First positional arg is the tag, flags may follow it
+	if fs.NArg() < 1 {
+		return charm.Validationf("tag is required")
+	}
+	tag := fs.Arg(0)
+	_ = fs.Parse(fs.Args()[1:])
+
+	var entityTypes []string
+	if *types != "" {
+		for _, t := range strings.Split(*types, ",") {
+			entityTypes = append(entityTypes, strings.TrimSpace(t))
+		}
+	}
+	found, err := client.FindByTag(tag, entityTypes...)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Printf("Nothing tagged %s\n", charm.NormalizeTag(tag))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TYPE\tNAME\tTAGS\tID")
+	_, _ = fmt.Fprintln(w, "----\t----\t----\t--")
+	for _, obj := range found {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", obj.EntityType, obj.Name, strings.Join(obj.Tags, ", "), charm.FormatID(obj.ID))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// TagsDeriveCommand suggests affinity tags from interaction content:
+// pagen crm tags derive [--min-mentions N] [--dry-run]
+func TagsDeriveCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("derive", flagErrorHandling)
+	minMentions := fs.Int("min-mentions", 2, "Titles or subjects that must mention a topic before it is suggested")
+	dryRun := fs.Bool("dry-run", false, "Show suggestions without saving them")
+	_ = fs.Parse(args)
+
+	result, err := client.DeriveAffinityTags(*minMentions, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to derive tags: %w", err)
+	}
+
+	for _, ts := range result.Suggestions {
+		fmt.Printf("  %s: %s (%d mention(s), e.g. %q)\n", ts.ContactName, ts.Tag, ts.Mentions, ts.Evidence[0])
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d new and %d updated suggestion(s) from %d contact(s)\n", result.Created, result.Updated, result.Contacts)
+		return nil
+	}
+	fmt.Printf("✓ %d new and %d updated suggestion(s) from %d contact(s)\n", result.Created, result.Updated, result.Contacts)
+	if result.Created+result.Updated > 0 {
+		fmt.Println("Review them with 'pagen crm tags suggestions'")
+	}
+	return nil
+}
+
+// TagsSuggestionsCommand lists pending tag suggestions.
+func TagsSuggestionsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("suggestions", flagErrorHandling)
+	status := fs.String("status", charm.SuggestionStatusPending, "pending, accepted, rejected, or all")
+	_ = fs.Parse(args)
+
+	filter := *status
+	if filter == "all" {
+		filter = ""
+	}
+	suggestions, err := client.ListTagSuggestions(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list tag suggestions: %w", err)
+	}
+	if len(suggestions) == 0 {
+		fmt.Println("No tag suggestions")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tCONTACT\tTAG\tMENTIONS\tCONFIDENCE\tEVIDENCE")
+	_, _ = fmt.Fprintln(w, "--\t-------\t---\t--------\t----------\t--------")
+	for _, ts := range suggestions {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.0f%%\t%s\n",
+			charm.FormatID(ts.Suggestion.ID), ts.ContactName, ts.Tag, ts.Mentions, ts.Suggestion.Confidence*100, strings.Join(ts.Evidence, "; "))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// TagsAcceptCommand adds suggested tags to their contacts:
+// pagen crm tags accept <id>... | --all [--min-confidence N]
+func TagsAcceptCommand(client *charm.Client, args []string) error {
+	return reviewTagSuggestions(client, "accept", args, client.AcceptTagSuggestion)
+}
+
+// TagsRejectCommand rejects suggested tags so they aren't suggested again.
+func TagsRejectCommand(client *charm.Client, args []string) error {
+	return reviewTagSuggestions(client, "reject", args, client.RejectTagSuggestion)
+}
+
+func reviewTagSuggestions(client *charm.Client, action string, args []string, review func(id uuid.UUID) (*charm.TagSuggestion, error)) error {
+	fs := flag.NewFlagSet(action, flagErrorHandling)
+	all := fs.Bool("all", false, "Every pending suggestion")
+	minConfidence := fs.Float64("min-confidence", 0, "With --all, only suggestions at least this confident (0-1)")
+	_ = fs.Parse(args)
+
+	pending, err := client.ListTagSuggestions(charm.SuggestionStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to list tag suggestions: %w", err)
+	}
+
+	var selected []*charm.TagSuggestion
+	if *all {
+		for _, ts := range pending {
+			if ts.Suggestion.Confidence >= *minConfidence {
+				selected = append(selected, ts)
+			}
+		}
+	} else {
+		if fs.NArg() == 0 {
+			return fmt.Errorf("suggestion ID is required (or --all)")
+		}
+		for _, ref := range fs.Args() {
+			ts, err := findTagSuggestion(pending, ref)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, ts)
+		}
+	}
+
+	for _, ts := range selected {
+		if _, err := review(ts.Suggestion.ID); err != nil {
+			return fmt.Errorf("failed to %s %s for %s: %w", action, ts.Tag, ts.ContactName, err)
+		}
+		if action == "accept" {
+			fmt.Printf("✓ Tagged %s: %s\n", ts.ContactName, ts.Tag)
+		} else {
+			fmt.Printf("✓ Rejected %s for %s\n", ts.Tag, ts.ContactName)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println("No pending tag suggestions")
+	}
+	return nil
+}
+
+// findTagSuggestion finds a pending suggestion by full ID or unique ID prefix.
+func findTagSuggestion(pending []*charm.TagSuggestion, ref string) (*charm.TagSuggestion, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	var matches []*charm.TagSuggestion
+	for _, ts := range pending {
+		if len(ref) >= charm.MinIDPrefix && strings.HasPrefix(ts.Suggestion.ID.String(), ref) {
+			matches = append(matches, ts)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no pending tag suggestion matches %s", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d tag suggestions match %s; use more of the ID", len(matches), ref)
+	}
+}
@@ -0,0 +1,98 @@
+// ABOUTME: Task and quick-capture CLI commands
+// ABOUTME: Captures free-form lines as tasks or interactions, lists open tasks, and completes them
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// CaptureCommand saves a quick-capture line, the same as the TUI scratchpad:
+// pagen crm capture "call Dana re: renewal #task @dana due:fri"
+func CaptureCommand(client *charm.Client, args []string) error {
+	line := strings.TrimSpace(strings.Join(args, " "))
+	if line == "" {
+		return fmt.Errorf("usage: pagen crm capture <text> [#task|#call|#meeting|#email|#message] [@name] [due:<when>]")
+	}
+
+	result, err := client.Capture(line)
+	if err != nil {
+		return err
+	}
+	fmt.Println("✓ " + result.Summary())
+	return nil
+}
+
+// ListTasksCommand lists open tasks, soonest due first.
+func ListTasksCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("list-tasks", flagErrorHandling)
+	all := fs.Bool("all", false, "Include completed tasks")
+	_ = fs.Parse(args)
+
+	tasks, err := client.ListTasks(*all)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No open tasks")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tDUE\tTASK\tLINKED TO\tSTATUS")
+	_, _ = fmt.Fprintln(w, "--\t---\t----\t---------\t------")
+	for _, task := range tasks {
+		due := "-"
+		if task.DueAt != nil {
+			due = task.DueAt.Format("2006-01-02")
+			if task.DueAt.Hour() != 0 || task.DueAt.Minute() != 0 {
+				due = task.DueAt.Format("2006-01-02 15:04")
+			}
+			if task.IsOverdue(now) {
+				due += " ⚠"
+			}
+		}
+		var links []string
+		for _, name := range []string{task.ContactName, task.CompanyName, task.DealTitle} {
+			if name != "" {
+				links = append(links, name)
+			}
+		}
+		linked := "-"
+		if len(links) > 0 {
+			linked = strings.Join(links, ", ")
+		}
+		status := "open"
+		if task.DoneAt != nil {
+			status = "done"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", charm.FormatID(task.ID), due, task.Title, linked, status)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// CompleteTaskCommand marks a task done.
+func CompleteTaskCommand(client *charm.Client, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: pagen crm complete-task <task-id|title>")
+	}
+
+	id, err := resolveID(client, strings.Join(args, " "), charm.EntityTask)
+	if err != nil {
+		return err
+	}
+	task, err := client.CompleteTask(id)
+	if err != nil {
+		return fmt.Errorf("failed to complete task: %w", err)
+	}
+	fmt.Printf("✓ Completed: %s\n", task.Title)
+	return nil
+}
@@ -0,0 +1,111 @@
+// ABOUTME: Per-command timing for the global --timing flag
+// ABOUTME: Splits a command's wall time into client setup, KV queries, and processing/output
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing records where a command spends its time. Install ObserveQuery as
+// the client's charm.QueryObserver so KV operations are counted.
+type Timing struct {
+	start time.Time
+
+	mu      sync.Mutex
+	open    time.Duration
+	queries map[string]*opTiming
+	slowest slowQuery
+}
+
+type opTiming struct {
+	count int
+	total time.Duration
+}
+
+type slowQuery struct {
+	op      string
+	key     string
+	elapsed time.Duration
+}
+
+// NewTiming starts timing a command.
+func NewTiming() *Timing {
+	return &Timing{start: time.Now(), queries: make(map[string]*opTiming)}
+}
+
+// Opened records how long the client took to set up.
+func (t *Timing) Opened(elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.open += elapsed
+}
+
+// ObserveQuery records one KV operation. It matches charm.QueryObserver.
+func (t *Timing) ObserveQuery(op string, key []byte, elapsed time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q := t.queries[op]
+	if q == nil {
+		q = &opTiming{}
+		t.queries[op] = q
+	}
+	q.count++
+	q.total += elapsed
+	if elapsed > t.slowest.elapsed {
+		t.slowest = slowQuery{op: op, key: string(key), elapsed: elapsed}
+	}
+}
+
+// Report writes the phase breakdown. Time not spent opening the client or
+// in KV operations is counted as render: filtering, sorting, and output.
+func (t *Timing) Report(w io.Writer) {
+	total := time.Since(t.start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ops := make([]string, 0, len(t.queries))
+	var queryTotal time.Duration
+	var queryCount int
+	for op, q := range t.queries {
+		ops = append(ops, op)
+		queryTotal += q.total
+		queryCount += q.count
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if t.queries[ops[i]].total != t.queries[ops[j]].total {
+			return t.queries[ops[i]].total > t.queries[ops[j]].total
+		}
+		return ops[i] < ops[j]
+	})
+	render := max(total-t.open-queryTotal, 0)
+
+	_, _ = fmt.Fprintln(w, "\nTiming:")
+	_, _ = fmt.Fprintf(w, "  open    %10s  Charm KV client setup\n", formatDuration(t.open))
+	_, _ = fmt.Fprintf(w, "  query   %10s  %d KV operation(s)\n", formatDuration(queryTotal), queryCount)
+	for _, op := range ops {
+		q := t.queries[op]
+		_, _ = fmt.Fprintf(w, "    %-8s%8s  %d call(s), %s avg\n", op, formatDuration(q.total), q.count, formatDuration(q.total/time.Duration(q.count)))
+	}
+	if t.slowest.elapsed > 0 {
+		_, _ = fmt.Fprintf(w, "    slowest: %s %s (%s)\n", t.slowest.op, t.slowest.key, formatDuration(t.slowest.elapsed))
+	}
+	_, _ = fmt.Fprintf(w, "  render  %10s  processing and output\n", formatDuration(render))
+	_, _ = fmt.Fprintf(w, "  total   %10s\n", formatDuration(total))
+}
+
+// formatDuration rounds to a precision that suits the magnitude.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
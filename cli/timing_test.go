@@ -0,0 +1,41 @@
+// ABOUTME: Tests for --timing reports
+// ABOUTME: Verifies KV operations are grouped by op and the remainder is counted as render
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestTimingReport(t *testing.T) {
+	timing := NewTiming()
+	timing.Opened(2 * time.Millisecond)
+
+	client := charm.NewTestClient(t)
+	client.SetQueryObserver(timing.ObserveQuery)
+	if err := client.CreateContact(&charm.Contact{Name: "Ada Lovelace"}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	if _, err := client.ListContacts(nil); err != nil {
+		t.Fatalf("failed to list contacts: %v", err)
+	}
+	timing.ObserveQuery("get", []byte("contact:slow"), 30*time.Millisecond, nil)
+
+	var out bytes.Buffer
+	timing.Report(&out)
+	report := out.String()
+
+	for _, want := range []string{"open", "2ms", "set", "get", "render", "total", "slowest: get contact:slow (30ms)"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q:\n%s", want, report)
+		}
+	}
+	// The slowest op sorts first
+	if strings.Index(report, "    get") > strings.Index(report, "    set") {
+		t.Errorf("expected ops ordered by total time:\n%s", report)
+	}
+}
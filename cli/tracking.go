@@ -0,0 +1,177 @@
+// ABOUTME: Email open/click tracking CLI commands
+// ABOUTME: Enables tracking, creates tracked follow-up emails, and lists their opens and clicks
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// EmailTrackingCommand shows or changes the email tracking setting.
+func EmailTrackingCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("tracking", flagErrorHandling)
+	enable := fs.Bool("enable", false, "Enable open/click tracking")
+	disable := fs.Bool("disable", false, "Disable open/click tracking")
+	baseURL := fs.String("base-url", "", "Public URL of `pagen web`, used in tracking links")
+	_ = fs.Parse(args)
+
+	if *enable && *disable {
+		return fmt.Errorf("--enable and --disable are mutually exclusive")
+	}
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if !*enable && !*disable && *baseURL == "" {
+		status := "disabled"
+		if cfg.EmailTracking {
+			status = "enabled"
+		}
+		fmt.Printf("Email tracking: %s\n", status)
+		if cfg.TrackingBaseURL != "" {
+			fmt.Printf("Base URL: %s\n", cfg.TrackingBaseURL)
+		}
+		return nil
+	}
+
+	if *baseURL != "" {
+		u, err := url.Parse(*baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return charm.Validationf("invalid base URL: %s", *baseURL)
+		}
+		cfg.TrackingBaseURL = strings.TrimRight(*baseURL, "/")
+	}
+	if *enable {
+		if cfg.TrackingBaseURL == "" {
+			return fmt.Errorf("--base-url is required to enable tracking")
+		}
+		cfg.EmailTracking = true
+	}
+	if *disable {
+		cfg.EmailTracking = false
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if cfg.EmailTracking {
+		fmt.Printf("✓ Email tracking enabled (%s)\n", cfg.TrackingBaseURL)
+		fmt.Println("  Restart `pagen web` to serve the tracking endpoints.")
+	} else {
+		fmt.Println("✓ Email tracking disabled")
+	}
+	return nil
+}
+
+// TrackEmailCommand records a follow-up email and prints its tracking pixel and links.
+func TrackEmailCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("track-email", flagErrorHandling)
+	contactRef := fs.String("contact", "", "Contact ID or name (required)")
+	subject := fs.String("subject", "", "Email subject (required)")
+	links := fs.String("links", "", "Comma-separated URLs to track clicks on")
+	_ = fs.Parse(args)
+
+	if *contactRef == "" || *subject == "" {
+		return charm.Validationf("--contact and --subject are required")
+	}
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.EmailTracking {
+		return fmt.Errorf("email tracking is disabled (enable with: pagen followups tracking --enable --base-url <url>)")
+	}
+
+	contact, err := findContact(client, *contactRef)
+	if err != nil {
+		return err
+	}
+
+	email := &charm.TrackedEmail{
+		ContactID:   contact.ID,
+		ContactName: contact.Name,
+		Subject:     *subject,
+	}
+	for _, link := range strings.Split(*links, ",") {
+		if link = strings.TrimSpace(link); link == "" {
+			continue
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return charm.Validationf("invalid link: %s", link)
+		}
+		email.Links = append(email.Links, link)
+	}
+
+	if err := client.CreateTrackedEmail(email); err != nil {
+		return fmt.Errorf("failed to create tracked email: %w", err)
+	}
+
+	fmt.Printf("✓ Tracking email to %s: %s\n\n", contact.Name, email.Subject)
+	fmt.Println("Add this to the end of the HTML body:")
+	fmt.Printf("  <img src=\"%s\" width=\"1\" height=\"1\" alt=\"\">\n", email.PixelURL(cfg.TrackingBaseURL))
+	if len(email.Links) > 0 {
+		fmt.Println("\nReplace links with:")
+		for i, link := range email.Links {
+			fmt.Printf("  %s\n    → %s\n", link, email.LinkURL(cfg.TrackingBaseURL, i))
+		}
+	}
+	return nil
+}
+
+// TrackedEmailsCommand lists tracked emails with their open and click counts.
+func TrackedEmailsCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("tracked", flagErrorHandling)
+	contactRef := fs.String("contact", "", "Filter by contact ID or name")
+	_ = fs.Parse(args)
+
+	var contactID *uuid.UUID
+	if *contactRef != "" {
+		contact, err := findContact(client, *contactRef)
+		if err != nil {
+			return err
+		}
+		contactID = &contact.ID
+	}
+
+	emails, err := client.ListTrackedEmails(contactID)
+	if err != nil {
+		return fmt.Errorf("failed to list tracked emails: %w", err)
+	}
+
+	if len(emails) == 0 {
+		fmt.Println("No tracked emails")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SENT\tCONTACT\tSUBJECT\tOPENS\tCLICKS\tFIRST OPENED")
+	_, _ = fmt.Fprintln(w, "----\t-------\t-------\t-----\t------\t------------")
+	for _, email := range emails {
+		firstOpened := "-"
+		if email.FirstOpenedAt != nil {
+			firstOpened = email.FirstOpenedAt.Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n",
+			email.SentAt.Format("2006-01-02"),
+			email.ContactName,
+			email.Subject,
+			email.Opens,
+			email.Clicks,
+			firstOpened,
+		)
+	}
+	_ = w.Flush()
+	return nil
+}
@@ -21,7 +21,7 @@ import (
 
 // SyncVaultInitCommand initializes device ID for vault sync.
 func SyncVaultInitCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-init", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-init", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Load existing config or create new one
@@ -51,7 +51,7 @@ func SyncVaultInitCommand(database *sql.DB, args []string) error {
 
 // SyncVaultLoginCommand authenticates with vault server.
 func SyncVaultLoginCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-login", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-login", flagErrorHandling)
 	server := fs.String("server", "https://api.storeusa.org", "Vault server URL")
 	_ = fs.Parse(args)
 
@@ -144,7 +144,7 @@ func SyncVaultLoginCommand(database *sql.DB, args []string) error {
 
 // SyncVaultStatusCommand shows vault sync status.
 func SyncVaultStatusCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-status", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-status", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Load config
@@ -212,8 +212,9 @@ func SyncVaultStatusCommand(database *sql.DB, args []string) error {
 
 // SyncVaultNowCommand triggers manual vault sync.
 func SyncVaultNowCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-now", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-now", flagErrorHandling)
 	verbose := fs.Bool("verbose", false, "Show detailed progress")
+	strict := fs.Bool("strict", false, "Fail on pulled changes whose company or contact name matches several records instead of taking the first")
 	_ = fs.Parse(args)
 
 	// Load config
@@ -221,6 +222,9 @@ func SyncVaultNowCommand(database *sql.DB, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load vault config: %w", err)
 	}
+	if *strict {
+		cfg.StrictResolution = true
+	}
 
 	if !cfg.IsConfigured() {
 		return fmt.Errorf("vault not configured. Run 'pagen sync vault-login' first")
@@ -271,7 +275,7 @@ func SyncVaultNowCommand(database *sql.DB, args []string) error {
 
 // SyncVaultPendingCommand lists pending vault changes.
 func SyncVaultPendingCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-pending", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-pending", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Load config
@@ -317,7 +321,7 @@ func SyncVaultPendingCommand(database *sql.DB, args []string) error {
 
 // SyncVaultLogoutCommand clears vault tokens.
 func SyncVaultLogoutCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-logout", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-logout", flagErrorHandling)
 	_ = fs.Parse(args)
 
 	// Load config
@@ -347,7 +351,7 @@ func SyncVaultLogoutCommand(database *sql.DB, args []string) error {
 
 // SyncVaultWipeCommand clears all vault sync data.
 func SyncVaultWipeCommand(database *sql.DB, args []string) error {
-	fs := flag.NewFlagSet("sync vault-wipe", flag.ExitOnError)
+	fs := flag.NewFlagSet("sync vault-wipe", flagErrorHandling)
 	confirm := fs.Bool("confirm", false, "Confirm wipe operation")
 	_ = fs.Parse(args)
 
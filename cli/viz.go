@@ -3,31 +3,39 @@
 package cli
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
 	"github.com/harperreed/pagen/viz"
 )
 
 // VizGraphContactsCommand generates a contact relationship network graph.
 func VizGraphContactsCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("viz graph contacts", flag.ExitOnError)
+	fs := flag.NewFlagSet("viz graph contacts", flagErrorHandling)
 	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "", "Output format: dot, mermaid, graphml, json (default: from --output extension, else dot)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	generator := viz.NewGraphGenerator(client)
+	generator, err := graphGenerator(client, *format, *output)
+	if err != nil {
+		return err
+	}
 
 	var contactID *uuid.UUID
 	if fs.NArg() > 0 {
-		id, err := uuid.Parse(fs.Arg(0))
+		id, err := resolveID(client, fs.Arg(0), charm.EntityContact)
 		if err != nil {
-			return fmt.Errorf("invalid contact ID: %w", err)
+			return err
 		}
 		contactID = &id
 	}
@@ -47,23 +55,27 @@ func VizGraphContactsCommand(client *charm.Client, args []string) error {
 
 // VizGraphCompanyCommand generates a company org chart.
 func VizGraphCompanyCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("viz graph company", flag.ExitOnError)
+	fs := flag.NewFlagSet("viz graph company", flagErrorHandling)
 	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "", "Output format: dot, mermaid, graphml, json (default: from --output extension, else dot)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
 	if fs.NArg() < 1 {
-		return fmt.Errorf("company ID required")
+		return charm.Validationf("company ID required")
 	}
 
-	companyID, err := uuid.Parse(fs.Arg(0))
+	companyID, err := resolveID(client, fs.Arg(0), charm.EntityCompany)
 	if err != nil {
-		return fmt.Errorf("invalid company ID: %w", err)
+		return err
 	}
 
-	generator := viz.NewGraphGenerator(client)
+	generator, err := graphGenerator(client, *format, *output)
+	if err != nil {
+		return err
+	}
 	dot, err := generator.GenerateCompanyGraph(companyID)
 	if err != nil {
 		return err
@@ -77,16 +89,57 @@ func VizGraphCompanyCommand(client *charm.Client, args []string) error {
 	return nil
 }
 
+// VizGraphDealCommand generates a deal's influencer map.
+func VizGraphDealCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz graph deal", flagErrorHandling)
+	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "", "Output format: dot, mermaid, graphml, json (default: from --output extension, else dot)")
+	involvedOnly := fs.Bool("involved-only", false, "Leave out people at the company who aren't on the deal")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 1 {
+		return charm.Validationf("deal ID required")
+	}
+
+	dealID, err := resolveID(client, fs.Arg(0), charm.EntityDeal)
+	if err != nil {
+		return err
+	}
+
+	generator, err := graphGenerator(client, *format, *output)
+	if err != nil {
+		return err
+	}
+	dot, err := generator.GenerateDealGraph(dealID, !*involvedOnly)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		return os.WriteFile(*output, []byte(dot), 0644)
+	}
+
+	fmt.Println(dot)
+	return nil
+}
+
 // VizGraphPipelineCommand generates a deal pipeline graph.
 func VizGraphPipelineCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("viz graph pipeline", flag.ExitOnError)
+	fs := flag.NewFlagSet("viz graph pipeline", flagErrorHandling)
 	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "", "Output format: dot, mermaid, graphml, json (default: from --output extension, else dot)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	generator := viz.NewGraphGenerator(client)
+	generator, err := graphGenerator(client, *format, *output)
+	if err != nil {
+		return err
+	}
 	dot, err := generator.GeneratePipelineGraph()
 	if err != nil {
 		return err
@@ -102,14 +155,18 @@ func VizGraphPipelineCommand(client *charm.Client, args []string) error {
 
 // VizGraphAllCommand generates a complete graph with all entities.
 func VizGraphAllCommand(client *charm.Client, args []string) error {
-	fs := flag.NewFlagSet("viz graph all", flag.ExitOnError)
+	fs := flag.NewFlagSet("viz graph all", flagErrorHandling)
 	output := fs.String("output", "", "Output file (default: stdout)")
+	format := fs.String("format", "", "Output format: dot, mermaid, graphml, json (default: from --output extension, else dot)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	generator := viz.NewGraphGenerator(client)
+	generator, err := graphGenerator(client, *format, *output)
+	if err != nil {
+		return err
+	}
 	dot, err := generator.GenerateCompleteGraph()
 	if err != nil {
 		return err
@@ -123,6 +180,21 @@ func VizGraphAllCommand(client *charm.Client, args []string) error {
 	return nil
 }
 
+// graphGenerator returns a generator for the requested format, falling back
+// to the output file's extension and then DOT.
+func graphGenerator(client *charm.Client, format, output string) (*viz.GraphGenerator, error) {
+	if format == "" {
+		format = viz.GraphFormatForFile(output)
+	}
+	if format == "" {
+		format = viz.GraphFormatDOT
+	}
+	if !viz.ValidGraphFormat(format) {
+		return nil, charm.Validationf("invalid --format: %s (use %s)", format, strings.Join(viz.GraphFormats, ", "))
+	}
+	return viz.NewGraphGenerator(client).WithFormat(format), nil
+}
+
 func VizDashboardCommand(client *charm.Client, args []string) error {
 	stats, err := viz.GenerateDashboardStats(client)
 	if err != nil {
@@ -134,3 +206,185 @@ func VizDashboardCommand(client *charm.Client, args []string) error {
 
 	return nil
 }
+
+// VizSourcesCommand reports pipeline and win rate by deal source, with top referrers.
+func VizSourcesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz sources", flagErrorHandling)
+	top := fs.Int("top", 5, "Number of top referrers to show")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := viz.GenerateSourceReport(client, *top)
+	if err != nil {
+		return fmt.Errorf("failed to generate source report: %w", err)
+	}
+
+	fmt.Print(viz.RenderSourceReport(report))
+	return nil
+}
+
+// VizPipelineVelocityCommand reports time in stage, stage conversion, and
+// stalled deals from deal stage history.
+func VizPipelineVelocityCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz pipeline-velocity", flagErrorHandling)
+	stalledDays := fs.Int("stalled-days", charm.DefaultStalledDays, "Days in one stage before an open deal counts as stalled")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	metrics, err := client.PipelineMetrics(*stalledDays, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to measure pipeline velocity: %w", err)
+	}
+
+	fmt.Print(viz.RenderPipelineVelocity(metrics))
+	return nil
+}
+
+// VizPlacesCommand ranks the cities where you meet people in person. With
+// --geocode it first looks up meeting locations address parsing can't place.
+func VizPlacesCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz places", flagErrorHandling)
+	top := fs.Int("top", 10, "Number of cities to show (0 for all)")
+	geocode := fs.Bool("geocode", false, "Look up new meeting locations with the geocoding service first")
+	showUnresolved := fs.Bool("unresolved", false, "List locations that couldn't be placed in a city")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *geocode {
+		geocoder := sync.NewNominatimGeocoder(client.Config().GeocodeURL)
+		fmt.Println("Geocoding meeting locations...")
+		result, err := sync.GeocodeMeetingLocations(context.Background(), client, geocoder, sync.GeocodeInterval)
+		if err != nil {
+			return fmt.Errorf("failed to geocode locations: %w", err)
+		}
+		for _, e := range result.Errors {
+			fmt.Printf("  ⚠ %s\n", e)
+		}
+		fmt.Printf("✓ Geocoded %d location(s), %d not found\n\n", result.Resolved, result.NotFound)
+	}
+
+	places, unresolved, err := client.MeetingPlaces()
+	if err != nil {
+		return fmt.Errorf("failed to build places: %w", err)
+	}
+
+	fmt.Print(viz.RenderPlaces(places, unresolved, *top, *showUnresolved))
+	return nil
+}
+
+// VizTrendCommand charts how the open pipeline changed over time from weekly
+// pipeline snapshots, taking this week's snapshot first if it's missing.
+func VizTrendCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz trend", flagErrorHandling)
+	by := fs.String("by", charm.TrendQuarter, "Group snapshots by week, month, or quarter")
+	metric := fs.String("metric", viz.TrendMetricValue, "Chart value, expected (probability-weighted), or count")
+	format := fs.String("format", "", "Output format: text or svg (default: svg for .svg output files, else text)")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	last := fs.Int("last", 0, "Only chart the last N periods (0 for all)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !viz.ValidTrendMetric(*metric) {
+		return charm.Validationf("invalid --metric: %s (use value, expected, or count)", *metric)
+	}
+	if *format == "" {
+		*format = "text"
+		if strings.HasSuffix(strings.ToLower(*output), ".svg") {
+			*format = "svg"
+		}
+	}
+	if *format != "text" && *format != "svg" {
+		return charm.Validationf("invalid --format: %s (use text or svg)", *format)
+	}
+
+	if _, err := client.EnsureWeeklySnapshot(time.Now()); err != nil {
+		return fmt.Errorf("failed to snapshot pipeline: %w", err)
+	}
+	snapshots, err := client.ListPipelineSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to list pipeline snapshots: %w", err)
+	}
+	points, err := charm.PipelineTrend(snapshots, *by)
+	if err != nil {
+		return err
+	}
+	if *last > 0 && len(points) > *last {
+		points = points[len(points)-*last:]
+	}
+
+	chart := viz.RenderTrend(points, *metric)
+	if *format == "svg" {
+		chart = viz.RenderTrendSVG(points, *metric)
+	}
+
+	if *output != "" {
+		if err := os.WriteFile(*output, []byte(chart), 0644); err != nil {
+			return fmt.Errorf("failed to write chart: %w", err)
+		}
+		fmt.Printf("✓ Wrote pipeline trend to %s\n", *output)
+		return nil
+	}
+	fmt.Print(chart)
+	if len(points) == 1 && *format == "text" {
+		fmt.Println("\nOnly one snapshot so far; backfill past weeks with: pagen viz snapshot --backfill 26")
+	}
+	return nil
+}
+
+// VizSnapshotCommand takes this week's pipeline snapshot, replacing an earlier
+// one from the same week, and optionally rebuilds past weeks from revisions.
+func VizSnapshotCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("viz snapshot", flagErrorHandling)
+	backfill := fs.Int("backfill", 0, "Also rebuild snapshots for this many past weeks from revision history")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	snapshot, err := client.TakePipelineSnapshot(now)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot pipeline: %w", err)
+	}
+	open := snapshot.Open()
+	fmt.Printf("✓ Snapshot for week of %s: %d open deal(s), %s\n", snapshot.Week, open.Count, charm.FormatMoneyCompact(open.Amount, ""))
+
+	if *backfill > 0 {
+		added, err := client.BackfillPipelineSnapshots(*backfill, now)
+		if err != nil {
+			return fmt.Errorf("failed to backfill snapshots: %w", err)
+		}
+		fmt.Printf("✓ Backfilled %d past week(s) from revision history\n", added)
+	}
+	return nil
+}
+
+// SyncVizCommand draws the sync topology: this device, the Charm cloud, and linked devices.
+func SyncVizCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("sync viz", flagErrorHandling)
+	output := fs.String("output", "", "Output file (default: stdout)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dot, err := viz.GenerateSyncGraph(client.SyncTopology())
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		return os.WriteFile(*output, []byte(dot), 0644)
+	}
+
+	fmt.Println(dot)
+	return nil
+}
@@ -0,0 +1,336 @@
+// ABOUTME: Watch CLI commands for per-deal and per-contact change notifications
+// ABOUTME: Polls revision history and delivers changes as desktop notifications or webhook posts, and runs stage timers
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// webhookTimeout bounds each webhook post so a slow endpoint can't stall the loop.
+const webhookTimeout = 10 * time.Second
+
+// WatchDealCommand starts watching a deal for changes.
+func WatchDealCommand(client *charm.Client, args []string) error {
+	return watchCommand(client, charm.EntityDeal, args)
+}
+
+// WatchContactCommand starts watching a contact for changes.
+func WatchContactCommand(client *charm.Client, args []string) error {
+	return watchCommand(client, charm.EntityContact, args)
+}
+
+func watchCommand(client *charm.Client, entityType string, args []string) error {
+	fs := flag.NewFlagSet(entityType, flagErrorHandling)
+	webhook := fs.String("webhook", "", "URL to POST changes to")
+	noDesktop := fs.Bool("no-desktop", false, "Don't show desktop notifications (requires --webhook)")
+	_ = fs.Parse(args)
+
+	// First positional arg is the object ID
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("%s ID is required", entityType)
+	}
+
+	id, err := resolveID(client, fs.Arg(0), entityType)
+	if err != nil {
+		return err
+	}
+	if *webhook != "" && !strings.HasPrefix(*webhook, "http://") && !strings.HasPrefix(*webhook, "https://") {
+		return fmt.Errorf("webhook must be an http:// or https:// URL")
+	}
+
+	watch, err := client.AddWatch(entityType, id, !*noDesktop, *webhook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Watching %s %s (%s)\n", entityType, watch.Name, watchDelivery(watch))
+	fmt.Println("Run 'pagen watch run' to deliver notifications")
+	return nil
+}
+
+// WatchListCommand lists watched deals and contacts.
+func WatchListCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	watches, err := client.ListWatches()
+	if err != nil {
+		return fmt.Errorf("failed to list watches: %w", err)
+	}
+
+	if len(watches) == 0 {
+		fmt.Println("No watches")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tNAME\tNOTIFY\tSINCE")
+	_, _ = fmt.Fprintln(w, "--\t----\t----\t------\t-----")
+
+	for _, watch := range watches {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			charm.FormatID(watch.ObjectID), watch.EntityType, watch.Name, watchDelivery(watch), watch.CreatedAt.Format("2006-01-02"))
+	}
+
+	_ = w.Flush()
+	return nil
+}
+
+// WatchRemoveCommand stops watching a deal or contact.
+func WatchRemoveCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("remove", flagErrorHandling)
+	_ = fs.Parse(args)
+
+	// First positional arg is the object ID
+	if len(fs.Args()) < 1 {
+		return charm.Validationf("object ID is required")
+	}
+
+	id, err := resolveID(client, fs.Arg(0), charm.EntityDeal, charm.EntityContact)
+	if err != nil {
+		return err
+	}
+
+	if err := client.RemoveWatch(id); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Stopped watching %s\n", id)
+	return nil
+}
+
+// WatchRunCommand polls watched objects and delivers their changes.
+func WatchRunCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("run", flagErrorHandling)
+	interval := fs.Duration("interval", time.Minute, "How often to check for changes")
+	once := fs.Bool("once", false, "Check once and exit")
+	_ = fs.Parse(args)
+
+	if *interval < 5*time.Second {
+		return fmt.Errorf("interval must be at least 5s")
+	}
+
+	if *once {
+		delivered, err := deliverWatchChanges(client, desktopNotify)
+		fmt.Printf("✓ Delivered %d change(s)\n", delivered)
+		alerts, timerErr := runStageTimers(client, desktopNotify, time.Now())
+		for _, alert := range alerts {
+			fmt.Printf("⏰ %s: %s\n", alert.Message(), strings.Join(alert.Actions, ", "))
+		}
+		return errors.Join(err, timerErr)
+	}
+
+	log.Printf("Watching for changes every %s", *interval)
+
+	// Setup signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		if delivered, err := deliverWatchChanges(client, desktopNotify); err != nil {
+			log.Printf("Watch check failed: %v", err)
+		} else if delivered > 0 {
+			log.Printf("Delivered %d change(s)", delivered)
+		}
+		alerts, err := runStageTimers(client, desktopNotify, time.Now())
+		if err != nil {
+			log.Printf("Stage timer check failed: %v", err)
+		}
+		for _, alert := range alerts {
+			log.Printf("Stage timer: %s (%s)", alert.Message(), strings.Join(alert.Actions, ", "))
+		}
+
+		select {
+		case <-ticker.C:
+		case sig := <-sigChan:
+			log.Printf("Received signal %s, shutting down", sig)
+			return nil
+		}
+	}
+}
+
+// watchPayload is the JSON body posted to a watch's webhook.
+type watchPayload struct {
+	ObjectID   string          `json:"object_id"`
+	EntityType string          `json:"entity_type"`
+	Name       string          `json:"name"`
+	Rev        int             `json:"rev"`
+	Op         string          `json:"op"`
+	Fields     []string        `json:"fields,omitempty"`
+	Message    string          `json:"message"`
+	ChangedAt  time.Time       `json:"changed_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// deliverWatchChanges sends each watch's pending changes in order and advances
+// its last seen revision. A failed webhook post is retried on the next check.
+func deliverWatchChanges(client *charm.Client, notify func(title, message string) error) (int, error) {
+	watches, err := client.ListWatches()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list watches: %w", err)
+	}
+
+	delivered := 0
+	var errs []string
+	for _, watch := range watches {
+		changes, err := client.PendingChanges(watch)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", watch.Name, err))
+			continue
+		}
+
+		for _, change := range changes {
+			if err := deliverWatchChange(change, notify); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", watch.Name, err))
+				break
+			}
+			watch.LastRev = change.Revision.Rev
+			if summary := change.Revision.Summary(); summary != "" {
+				watch.Name = summary
+			}
+			delivered++
+		}
+
+		if len(changes) > 0 {
+			if err := client.SaveWatch(watch); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", watch.Name, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return delivered, fmt.Errorf("failed to deliver: %s", strings.Join(errs, "; "))
+	}
+	return delivered, nil
+}
+
+func deliverWatchChange(change *charm.WatchChange, notify func(title, message string) error) error {
+	watch := change.Watch
+	message := change.Message()
+
+	if watch.Webhook != "" {
+		if err := postWebhook(watch.Webhook, watchPayload{
+			ObjectID:   watch.ObjectID.String(),
+			EntityType: watch.EntityType,
+			Name:       watch.Name,
+			Rev:        change.Revision.Rev,
+			Op:         change.Revision.Op,
+			Fields:     change.Fields,
+			Message:    message,
+			ChangedAt:  change.Revision.CreatedAt,
+			Data:       change.Revision.Data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Desktop notifications are best effort; retrying would re-post the webhook
+	if watch.Desktop {
+		if err := notify("pagen: "+watch.EntityType+" changed", message); err != nil {
+			log.Printf("warning: desktop notification failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// runStageTimers fires the configured stage timers. The config is reloaded
+// each check so a running watch picks up timer changes.
+func runStageTimers(client *charm.Client, notify func(title, message string) error, now time.Time) ([]*charm.StageTimerAlert, error) {
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return client.RunStageTimers(cfg.StageTimers, now, stageTimerNotifier(notify))
+}
+
+// stageTimerNotifier delivers a stage timer's notify action: the timer's
+// webhook, when set, and a desktop notification.
+func stageTimerNotifier(notify func(title, message string) error) func(*charm.StageTimerAlert, charm.StageTimer) error {
+	return func(alert *charm.StageTimerAlert, timer charm.StageTimer) error {
+		if timer.Webhook != "" {
+			if err := postWebhook(timer.Webhook, alert); err != nil {
+				return err
+			}
+		}
+		// Desktop notifications are best effort, as for watches
+		if err := notify("pagen: deal stalled in "+alert.Stage, alert.Message()); err != nil {
+			log.Printf("warning: desktop notification failed: %v", err)
+		}
+		return nil
+	}
+}
+
+func postWebhook(url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: webhookTimeout}
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// desktopNotify shows a desktop notification with terminal-notifier on macOS
+// or notify-send elsewhere.
+func desktopNotify(title, message string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = "terminal-notifier"
+		args = []string{"-title", title, "-message", message, "-group", "pagen"}
+	default:
+		cmd = "notify-send"
+		args = []string{title, message}
+	}
+
+	if _, err := exec.LookPath(cmd); err != nil {
+		return fmt.Errorf("%s not found; install it or use --webhook with --no-desktop", cmd)
+	}
+	if err := exec.Command(cmd, args...).Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", cmd, err)
+	}
+	return nil
+}
+
+// watchDelivery describes how a watch's changes are delivered.
+func watchDelivery(watch *charm.Watch) string {
+	var methods []string
+	if watch.Desktop {
+		methods = append(methods, "desktop")
+	}
+	if watch.Webhook != "" {
+		methods = append(methods, "webhook "+watch.Webhook)
+	}
+	return strings.Join(methods, ", ")
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests for watch CLI commands
+// ABOUTME: Validates webhook delivery, desktop notification, and retry after a failed post
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestDeliverWatchChanges(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	var payloads []watchPayload
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var payload watchPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	contact := &charm.Contact{Name: "Alice", Email: "alice@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	if err := WatchContactCommand(client, []string{"--webhook", server.URL, contact.ID.String()}); err != nil {
+		t.Fatalf("WatchContactCommand failed: %v", err)
+	}
+
+	contact.Email = "alice@newco.com"
+	if err := client.UpdateContact(contact); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+
+	var notified []string
+	notify := func(title, message string) error {
+		notified = append(notified, message)
+		return nil
+	}
+
+	// A failed post leaves the change pending
+	if _, err := deliverWatchChanges(client, notify); err == nil {
+		t.Fatal("expected a failed webhook to be reported")
+	}
+	fail = false
+
+	delivered, err := deliverWatchChanges(client, notify)
+	if err != nil {
+		t.Fatalf("deliverWatchChanges failed: %v", err)
+	}
+	if delivered != 1 || len(payloads) != 1 {
+		t.Fatalf("expected one delivered change, got %d (%d posts)", delivered, len(payloads))
+	}
+	if p := payloads[0]; p.Op != charm.RevisionOpUpdate || p.Rev != 2 || len(p.Fields) != 1 || p.Fields[0] != "email" {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if len(notified) != 1 || notified[0] != "Alice updated: email" {
+		t.Errorf("unexpected desktop notifications %v", notified)
+	}
+
+	if delivered, _ := deliverWatchChanges(client, notify); delivered != 0 {
+		t.Errorf("expected nothing left to deliver, got %d", delivered)
+	}
+
+	if err := WatchRemoveCommand(client, []string{contact.ID.String()}); err != nil {
+		t.Errorf("WatchRemoveCommand failed: %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+// ABOUTME: Web UI server CLI commands
+// ABOUTME: Starts the server with optional TLS, configures login, and toggles maintenance mode
+package cli
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/web"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/term"
+)
+
+// WebCommand starts the web UI server.
+func WebCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("web", flagErrorHandling)
+	port := fs.Int("port", 10666, "Port to listen on")
+	cert := fs.String("cert", "", "TLS certificate file (use with --key)")
+	key := fs.String("key", "", "TLS private key file (use with --cert)")
+	autocertDomains := fs.String("autocert", "", "Comma-separated domains for automatic Let's Encrypt certificates")
+	baseURL := fs.String("base-url", "", "Public URL when served under a subpath, e.g. https://home.example.com/pagen/ (default: web_base_url config)")
+	shutdownTimeout := fs.Duration("shutdown-timeout", web.DefaultShutdownTimeout, "How long to drain connections on shutdown")
+	slowQuery := fs.Duration("slow-query", 0, "Log KV operations slower than this (default: web_slow_query_threshold config or 100ms)")
+	_ = fs.Parse(args)
+
+	server, err := web.NewServer(client)
+	if err != nil {
+		return fmt.Errorf("failed to create web server: %w", err)
+	}
+	if *baseURL != "" {
+		if err := server.SetBaseURL(*baseURL); err != nil {
+			return err
+		}
+	}
+
+	opts := web.Options{
+		Port:               *port,
+		CertFile:           *cert,
+		KeyFile:            *key,
+		ShutdownTimeout:    *shutdownTimeout,
+		SlowQueryThreshold: *slowQuery,
+		BackgroundJobs: func(ctx context.Context) {
+			RunBackgroundJobsEvery(ctx, client, BackgroundJobInterval, log.Printf)
+		},
+	}
+	for _, domain := range strings.Split(*autocertDomains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			opts.AutocertDomains = append(opts.AutocertDomains, domain)
+		}
+	}
+
+	return server.Run(opts)
+}
+
+// WebHealthcheckCommand exits non-zero unless the local web server is healthy.
+// Container images use it as their HEALTHCHECK.
+func WebHealthcheckCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("healthcheck", flagErrorHandling)
+	port := fs.Int("port", 10666, "Port the server listens on")
+	ready := fs.Bool("ready", false, "Check readiness (database opens, not in maintenance) instead of liveness")
+	target := fs.String("url", "", "Full URL to check (overrides --port and --ready)")
+	timeout := fs.Duration("timeout", 5*time.Second, "Request timeout")
+	_ = fs.Parse(args)
+
+	url := *target
+	if url == "" {
+		path := "/healthz"
+		if *ready {
+			path = "/readyz"
+		}
+		url = fmt.Sprintf("http://localhost:%d%s", *port, path)
+	}
+	if err := web.CheckHealth(url, *timeout); err != nil {
+		return err
+	}
+	fmt.Println("✓ healthy")
+	return nil
+}
+
+// WebMaintenanceCommand shows or toggles maintenance mode.
+// A running server picks up the change within a few seconds.
+func WebMaintenanceCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("maintenance", flagErrorHandling)
+	message := fs.String("message", "", "Message shown on the maintenance page")
+	_ = fs.Parse(args)
+
+	// Allow flags after the on/off argument
+	action := fs.Arg(0)
+	if action != "" {
+		_ = fs.Parse(fs.Args()[1:])
+	}
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if action == "" {
+		status := "off"
+		if cfg.WebMaintenance {
+			status = "on"
+		}
+		fmt.Printf("Maintenance mode: %s\n", status)
+		if cfg.WebMaintenanceMessage != "" {
+			fmt.Printf("Message: %s\n", cfg.WebMaintenanceMessage)
+		}
+		return nil
+	}
+
+	switch action {
+	case "on":
+		cfg.WebMaintenance = true
+		cfg.WebMaintenanceMessage = *message
+	case "off":
+		cfg.WebMaintenance = false
+		cfg.WebMaintenanceMessage = ""
+	default:
+		return fmt.Errorf("usage: web maintenance [on|off] [--message \"...\"]")
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if cfg.WebMaintenance {
+		fmt.Println("✓ Maintenance mode on")
+	} else {
+		fmt.Println("✓ Maintenance mode off")
+	}
+	fmt.Println("  Running servers pick this up within a few seconds.")
+	return nil
+}
+
+// WebAuthCommand shows or changes web UI login settings.
+func WebAuthCommand(client *charm.Client, args []string) error {
+	fs := flag.NewFlagSet("auth", flagErrorHandling)
+	user := fs.String("user", "", "Enable password login for this username (prompts for the password)")
+	issuer := fs.String("oidc-issuer", "", "OIDC issuer URL, e.g. https://accounts.google.com")
+	clientID := fs.String("oidc-client-id", "", "OIDC client ID")
+	clientSecret := fs.String("oidc-client-secret", "", "OIDC client secret")
+	allow := fs.String("allow", "", "Comma-separated emails or @domains allowed to sign in with OIDC")
+	disablePassword := fs.Bool("disable-password", false, "Disable password login")
+	disableOIDC := fs.Bool("disable-oidc", false, "Disable OIDC login")
+	resetSessions := fs.Bool("reset-sessions", false, "Sign out every session")
+	_ = fs.Parse(args)
+
+	cfg, err := charm.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	auth := cfg.WebAuth
+	if auth == nil {
+		auth = &charm.WebAuthConfig{}
+	}
+
+	changed := *user != "" || *issuer != "" || *clientID != "" || *clientSecret != "" || *allow != "" ||
+		*disablePassword || *disableOIDC || *resetSessions
+	if !changed {
+		printWebAuthStatus(auth)
+		return nil
+	}
+
+	if *user != "" {
+		password, err := readNewPassword()
+		if err != nil {
+			return err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		auth.Username = *user
+		auth.PasswordHash = string(hash)
+	}
+	if *disablePassword {
+		auth.Username = ""
+		auth.PasswordHash = ""
+	}
+
+	if *issuer != "" {
+		u, err := url.Parse(*issuer)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return charm.Validationf("invalid --oidc-issuer (must be an https URL): %s", *issuer)
+		}
+		auth.OIDCIssuer = strings.TrimRight(*issuer, "/")
+	}
+	if *clientID != "" {
+		auth.OIDCClientID = *clientID
+	}
+	if *clientSecret != "" {
+		auth.OIDCClientSecret = *clientSecret
+	}
+	if *allow != "" {
+		auth.OIDCAllowedEmails = nil
+		for _, email := range strings.Split(*allow, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				auth.OIDCAllowedEmails = append(auth.OIDCAllowedEmails, email)
+			}
+		}
+	}
+	if *disableOIDC {
+		auth.OIDCIssuer = ""
+		auth.OIDCClientID = ""
+		auth.OIDCClientSecret = ""
+		auth.OIDCAllowedEmails = nil
+	}
+	if (auth.OIDCIssuer != "" || auth.OIDCClientID != "") && !auth.OIDCEnabled() {
+		return fmt.Errorf("OIDC needs both --oidc-issuer and --oidc-client-id")
+	}
+	if auth.OIDCEnabled() && len(auth.OIDCAllowedEmails) == 0 {
+		return fmt.Errorf("--allow is required so only your accounts can sign in")
+	}
+
+	if auth.SessionSecret == "" || *resetSessions {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate session secret: %w", err)
+		}
+		auth.SessionSecret = hex.EncodeToString(secret)
+	}
+
+	cfg.WebAuth = auth
+	if !auth.PasswordEnabled() && !auth.OIDCEnabled() {
+		cfg.WebAuth = nil
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println("✓ Web login updated")
+	printWebAuthStatus(cfg.WebAuth)
+	fmt.Println("  Restart `pagen web` to apply.")
+	return nil
+}
+
+func printWebAuthStatus(auth *charm.WebAuthConfig) {
+	if !auth.PasswordEnabled() && !auth.OIDCEnabled() {
+		fmt.Println("Web login: off (anyone who can reach the server can read your CRM)")
+		return
+	}
+	if auth.PasswordEnabled() {
+		fmt.Printf("Password login: on (user %s)\n", auth.Username)
+	}
+	if auth.OIDCEnabled() {
+		fmt.Printf("OIDC login: on (%s, allowed: %s)\n", auth.OIDCIssuer, strings.Join(auth.OIDCAllowedEmails, ", "))
+	}
+}
+
+// readNewPassword prompts twice on a terminal, or reads one line from piped stdin.
+func readNewPassword() (string, error) {
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", fmt.Errorf("password cannot be empty")
+		}
+		return password, nil
+	}
+
+	fmt.Print("Password: ")
+	first, err := term.ReadPassword(syscall.Stdin)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Print("Confirm password: ")
+	second, err := term.ReadPassword(syscall.Stdin)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	if len(first) == 0 {
+		return "", fmt.Errorf("password cannot be empty")
+	}
+	if string(first) != string(second) {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return string(first), nil
+}
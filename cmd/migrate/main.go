@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/harperreed/pagen/db"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 func main() {
@@ -53,7 +52,7 @@ func migrate(dbPath string, dryRun, createBackup, force bool) error {
 		log.Printf("Backup created successfully")
 	}
 
-	database, err := sql.Open("sqlite3", dbPath)
+	database, err := db.Open(dbPath, db.Options{})
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
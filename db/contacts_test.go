@@ -3,6 +3,8 @@
 package db
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -94,3 +96,85 @@ func TestUpdateContactLastContacted(t *testing.T) {
 		t.Errorf("LastContactedAt time mismatch: got %v, want %v (diff: %v)", found.LastContactedAt, now, diff)
 	}
 }
+
+func TestUpdateContactVersion(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	contact := &models.Contact{Name: "Ada", Email: "ada@example.com"}
+	if err := CreateContact(db, contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	read, _ := GetContact(db, contact.ID)
+	if read.Version != 1 {
+		t.Fatalf("expected version 1, got %d", read.Version)
+	}
+
+	// Sync records an interaction after the contact was read
+	met := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if err := UpdateContactLastContacted(db, contact.ID, met); err != nil {
+		t.Fatalf("UpdateContactLastContacted failed: %v", err)
+	}
+
+	read.Phone = "555"
+	if err := UpdateContact(db, contact.ID, read); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected a version conflict for a stale read, got %v", err)
+	}
+	if err := UpdateContact(db, contact.ID, read, MergeFields("phone")); err != nil {
+		t.Fatalf("UpdateContact with a resolver failed: %v", err)
+	}
+	got, _ := GetContact(db, contact.ID)
+	if got.Phone != "555" || got.LastContactedAt == nil || !got.LastContactedAt.Equal(met) || got.Version != 3 || read.Version != 3 {
+		t.Errorf("expected both writes kept at version 3, got %+v", got)
+	}
+}
+
+func TestFindContactByEmail(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	for _, contact := range []*models.Contact{
+		{Name: "Alice Smith", Email: " Alice@Example.com "},
+		{Name: "John Doe", Email: "john@a.com"},
+		{Name: "john doe", Email: "john@b.com"},
+	} {
+		if err := CreateContact(db, contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	contact, err := FindContactByEmail(db, "alice@example.com")
+	if err != nil || contact == nil || contact.Name != "Alice Smith" {
+		t.Errorf("expected Alice by email, got %+v, %v", contact, err)
+	}
+	if contact, err := FindContactByEmail(db, "nobody@example.com"); contact != nil || err != nil {
+		t.Errorf("expected no contact, got %+v, %v", contact, err)
+	}
+
+}
+
+func TestFindContactByEmailUsesIndex(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query(`EXPLAIN QUERY PLAN
+		SELECT id FROM objects
+		WHERE kind = 'Contact' AND lower(trim(json_extract(fields, '$.email'))) = ?`, "alice@example.com")
+	if err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var plan string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		plan += detail + "\n"
+	}
+	if !strings.Contains(plan, "idx_objects_contact_email") {
+		t.Errorf("expected the email index to be used, got plan:\n%s", plan)
+	}
+}
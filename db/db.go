@@ -1,32 +1,114 @@
 // ABOUTME: Database connection management and initialization
-// ABOUTME: Handles opening SQLite database with WAL mode at XDG path
+// ABOUTME: Opens SQLite with WAL, a busy timeout, and a single writer connection, and reports connection health
 package db
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func OpenDatabase(path string) (*sql.DB, error) {
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, err
+// DefaultBusyTimeout is how long a connection waits on another process's
+// lock before failing with "database is locked".
+const DefaultBusyTimeout = 5 * time.Second
+
+// Options configures Open.
+type Options struct {
+	// Driver is the database/sql driver name: "sqlite3" (mattn, the
+	// default) or "sqlite" (modernc). The caller imports the driver.
+	Driver string
+	// BusyTimeout defaults to DefaultBusyTimeout.
+	BusyTimeout time.Duration
+}
+
+// Open opens the SQLite file at path: the file behind the Charm KV store for
+// `db health --path`, `db maintain`, and the background maintenance job, or a
+// legacy pagen database for cmd/migrate. The KV store may have the same file
+// open while maintenance runs, so every connection uses WAL so readers never
+// block the writer, waits BusyTimeout for locks, and begins transactions
+// IMMEDIATE so a writer takes the lock up front rather than failing when it
+// upgrades from a read. The pool holds a single connection, so the process is
+// one writer. Open doesn't initialize the schema; OpenDatabase does.
+func Open(path string, opts Options) (*sql.DB, error) {
+	if opts.Driver == "" {
+		opts.Driver = "sqlite3"
+	}
+	if opts.BusyTimeout <= 0 {
+		opts.BusyTimeout = DefaultBusyTimeout
+	}
+
+	memory := path == ":memory:"
+	if !memory {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
 	}
 
-	// Open database with WAL mode
-	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL")
+	dsn, err := openDSN(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(opts.Driver, dsn)
 	if err != nil {
 		return nil, err
 	}
-
-	// Configure connection pool for SQLite (avoid database locked errors)
 	db.SetMaxOpenConns(1)
 
-	// Initialize schema
+	// sql.Open is lazy; connect now so a bad path or pragma fails here
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if mode != "wal" && !(memory && mode == "memory") {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open %s: journal mode is %q, not WAL", path, mode)
+	}
+
+	return db, nil
+}
+
+// openDSN adds the connection settings to path in the form the driver reads.
+func openDSN(path string, opts Options) (string, error) {
+	ms := opts.BusyTimeout.Milliseconds()
+	params := url.Values{}
+	switch opts.Driver {
+	case "sqlite3":
+		params.Set("_busy_timeout", fmt.Sprint(ms))
+		params.Set("_journal_mode", "WAL")
+		params.Set("_synchronous", "NORMAL")
+	case "sqlite":
+		// busy_timeout first, so setting WAL waits on other processes too
+		params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", ms))
+		params.Add("_pragma", "journal_mode(WAL)")
+		params.Add("_pragma", "synchronous(NORMAL)")
+	default:
+		return "", fmt.Errorf("unsupported SQLite driver %q", opts.Driver)
+	}
+	params.Set("_txlock", "immediate")
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + params.Encode(), nil
+}
+
+// OpenDatabase opens the SQLite file at path with Open and initializes the
+// schema.
+func OpenDatabase(path string) (*sql.DB, error) {
+	db, err := Open(path, Options{})
+	if err != nil {
+		return nil, err
+	}
+
 	if err := InitSchema(db); err != nil {
 		_ = db.Close()
 		return nil, err
@@ -34,3 +116,87 @@ func OpenDatabase(path string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// FileJournalMode reads the journal mode stored in the SQLite file at path
+// through a read-only handle. Unlike a handle from Open, which switches the
+// file to WAL, it reports the file as other processes find it.
+func FileJournalMode(ctx context.Context, driver, path string) (string, error) {
+	db, err := sql.Open(driver, "file:"+path+"?mode=ro")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = db.Close() }()
+
+	var mode string
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		return "", fmt.Errorf("failed to read journal mode of %s: %w", path, err)
+	}
+	return mode, nil
+}
+
+// Health describes a database handle's settings and connection pool.
+type Health struct {
+	JournalMode        string
+	BusyTimeout        time.Duration
+	MaxOpenConnections int
+	OpenConnections    int
+	InUse              int
+	Idle               int
+	WaitCount          int64         // times a caller waited for the connection
+	WaitDuration       time.Duration // total time spent waiting
+	Problems           []string      // settings that let processes corrupt or lock each other
+}
+
+// OK reports whether the handle is set up for concurrent access.
+func (h *Health) OK() bool {
+	return len(h.Problems) == 0
+}
+
+// CheckHealth pings db and reports its journal mode, busy timeout, and pool
+// statistics. Settings other than those Open applies are listed as
+// Problems; an error means the database couldn't be reached at all.
+func CheckHealth(ctx context.Context, db *sql.DB) (*Health, error) {
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("database unreachable: %w", err)
+	}
+
+	health := &Health{}
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&health.JournalMode); err != nil {
+		return nil, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	var ms int64
+	if err := db.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&ms); err != nil {
+		return nil, fmt.Errorf("failed to read busy timeout: %w", err)
+	}
+	health.BusyTimeout = time.Duration(ms) * time.Millisecond
+
+	stats := db.Stats()
+	health.MaxOpenConnections = stats.MaxOpenConnections
+	health.OpenConnections = stats.OpenConnections
+	health.InUse = stats.InUse
+	health.Idle = stats.Idle
+	health.WaitCount = stats.WaitCount
+	health.WaitDuration = stats.WaitDuration
+
+	if health.JournalMode != "wal" && health.JournalMode != "memory" {
+		health.Problems = append(health.Problems,
+			fmt.Sprintf("journal mode is %s, so readers block writers; open with db.Open to use WAL", health.JournalMode))
+	}
+	if health.BusyTimeout == 0 {
+		health.Problems = append(health.Problems,
+			"busy timeout is 0, so any lock held by another process fails immediately")
+	}
+	if health.MaxOpenConnections != 1 {
+		health.Problems = append(health.Problems,
+			fmt.Sprintf("pool allows %s connections, so writes in this process can lock each other", connLimit(health.MaxOpenConnections)))
+	}
+
+	return health, nil
+}
+
+func connLimit(n int) string {
+	if n <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(n)
+}
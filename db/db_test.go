@@ -1,9 +1,12 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestOpenDatabase(t *testing.T) {
@@ -84,3 +87,103 @@ func TestOpenDatabaseSchemaInitFailure(t *testing.T) {
 		t.Errorf("Expected at least 4 tables after re-initialization, got %d", count)
 	}
 }
+
+func TestOpenSharedAccess(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "nested", "shared.db")
+
+	writer, err := Open(dbPath, Options{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = writer.Close() }()
+
+	health, err := CheckHealth(context.Background(), writer)
+	if err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	if !health.OK() || health.JournalMode != "wal" || health.BusyTimeout != DefaultBusyTimeout || health.MaxOpenConnections != 1 {
+		t.Errorf("unexpected health %+v", health)
+	}
+	if _, err := writer.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+		t.Fatalf("CREATE TABLE failed: %v", err)
+	}
+
+	// A second process waits out the busy timeout for the write lock, and
+	// reads the last committed state meanwhile
+	other, err := Open(dbPath, Options{BusyTimeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+
+	tx, err := writer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if _, err := tx.Exec("INSERT INTO notes VALUES ('held')"); err != nil {
+		t.Fatalf("INSERT failed: %v", err)
+	}
+	start := time.Now()
+	if _, err := other.Exec("INSERT INTO notes VALUES ('blocked')"); err == nil {
+		t.Error("expected the write to fail while the lock is held")
+	}
+	if waited := time.Since(start); waited < 150*time.Millisecond {
+		t.Errorf("expected the write to wait for the busy timeout, gave up after %s", waited)
+	}
+	var count int
+	if err := other.QueryRow("SELECT COUNT(*) FROM notes").Scan(&count); err != nil || count != 0 {
+		t.Errorf("expected a reader to see 0 committed rows, got %d (%v)", count, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if _, err := other.Exec("INSERT INTO notes VALUES ('after')"); err != nil {
+		t.Errorf("expected the write to succeed once the lock is free: %v", err)
+	}
+
+	if _, err := Open(dbPath, Options{Driver: "postgres"}); err == nil {
+		t.Error("expected an error for an unsupported driver")
+	}
+}
+
+func TestCheckHealthProblems(t *testing.T) {
+	// A handle opened without Open keeps the rollback journal and an unlimited pool
+	raw, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "raw.db"))
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer func() { _ = raw.Close() }()
+
+	health, err := CheckHealth(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	if health.OK() || len(health.Problems) != 2 || health.JournalMode != "delete" {
+		t.Errorf("expected journal mode and pool problems, got %+v", health)
+	}
+}
+
+func TestFileJournalMode(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mode.db")
+	raw, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	if _, err := raw.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_ = raw.Close()
+
+	// Reading the mode doesn't switch the file to WAL the way Open does
+	if mode, err := FileJournalMode(context.Background(), "sqlite3", dbPath); err != nil || mode != "delete" {
+		t.Fatalf("expected delete, got %q, %v", mode, err)
+	}
+	database, err := Open(dbPath, Options{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	_ = database.Close()
+	if mode, err := FileJournalMode(context.Background(), "sqlite3", dbPath); err != nil || mode != "wal" {
+		t.Errorf("expected wal after Open, got %q, %v", mode, err)
+	}
+}
@@ -33,6 +33,7 @@ func CompanyToObject(company *models.Company) *Object {
 		Kind:      ObjectTypeCompany,
 		CreatedAt: company.CreatedAt,
 		UpdatedAt: company.UpdatedAt,
+		Version:   company.Version,
 		CreatedBy: "system",
 		ACL:       `[{"actorId":"system","role":"owner"}]`,
 		Tags:      "[]",
@@ -60,6 +61,7 @@ func ObjectToCompany(obj *Object) (*models.Company, error) {
 		ID:        id,
 		CreatedAt: obj.CreatedAt,
 		UpdatedAt: obj.UpdatedAt,
+		Version:   obj.Version,
 	}
 
 	// Extract fields with type assertions
@@ -101,6 +103,7 @@ func ContactToObject(contact *models.Contact) *Object {
 		Kind:      ObjectTypeContact,
 		CreatedAt: contact.CreatedAt,
 		UpdatedAt: contact.UpdatedAt,
+		Version:   contact.Version,
 		CreatedBy: "system",
 		ACL:       `[{"actorId":"system","role":"owner"}]`,
 		Tags:      "[]",
@@ -123,6 +126,7 @@ func ObjectToContact(obj *Object) (*models.Contact, error) {
 		ID:        id,
 		CreatedAt: obj.CreatedAt,
 		UpdatedAt: obj.UpdatedAt,
+		Version:   obj.Version,
 	}
 
 	// Extract fields with type assertions
@@ -178,6 +182,10 @@ func DealToObject(deal *models.Deal) *Object {
 		fields["expected_close_date"] = deal.ExpectedCloseDate.Format(time.RFC3339Nano)
 	}
 
+	if deal.Probability != nil {
+		fields["probability"] = *deal.Probability
+	}
+
 	fields["last_activity_at"] = deal.LastActivityAt.Format(time.RFC3339Nano)
 
 	return &Object{
@@ -185,6 +193,7 @@ func DealToObject(deal *models.Deal) *Object {
 		Kind:      ObjectTypeDeal,
 		CreatedAt: deal.CreatedAt,
 		UpdatedAt: deal.UpdatedAt,
+		Version:   deal.Version,
 		CreatedBy: "system",
 		ACL:       `[{"actorId":"system","role":"owner"}]`,
 		Tags:      "[]",
@@ -207,6 +216,7 @@ func ObjectToDeal(obj *Object) (*models.Deal, error) {
 		ID:        id,
 		CreatedAt: obj.CreatedAt,
 		UpdatedAt: obj.UpdatedAt,
+		Version:   obj.Version,
 		Currency:  "USD", // Default
 	}
 
@@ -257,6 +267,14 @@ func ObjectToDeal(obj *Object) (*models.Deal, error) {
 		}
 	}
 
+	// Parse optional probability override
+	if probability, ok := obj.Fields["probability"].(float64); ok {
+		p := int(probability)
+		deal.Probability = &p
+	} else if probability, ok := obj.Fields["probability"].(int); ok {
+		deal.Probability = &probability
+	}
+
 	// Parse last_activity_at (required for deals)
 	if activityStr, ok := obj.Fields["last_activity_at"].(string); ok && activityStr != "" {
 		activity, err := time.Parse(time.RFC3339Nano, activityStr)
@@ -15,6 +15,20 @@ import (
 	"github.com/harperreed/pagen/models"
 )
 
+// legacyResolver is how the legacy Update functions handle a version
+// conflict: a caller-supplied resolver if there is one, none when the caller
+// read the record (version set), so the conflict is returned, and otherwise
+// re-applying just the fields the function writes onto the current object.
+func legacyResolver(version int64, resolve []ConflictResolver, fields ...string) ConflictResolver {
+	if len(resolve) > 0 {
+		return resolve[0]
+	}
+	if version != 0 {
+		return nil
+	}
+	return MergeFields(fields...)
+}
+
 // Legacy Company Functions
 
 func CreateCompany(db *sql.DB, company *models.Company) error {
@@ -50,36 +64,20 @@ func FindCompanies(db *sql.DB, query string, limit int) ([]models.Company, error
 		limit = 10
 	}
 
-	repo := NewObjectsRepository(db)
-	objects, err := repo.List(context.Background(), ObjectTypeCompany)
+	objects, err := findObjects(db, ObjectTypeCompany, query, ObjectFilter{}, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	var companies []models.Company
-	queryLower := strings.ToLower(query)
 
 	for _, obj := range objects {
-		// Apply search filter if query is provided
-		if query != "" {
-			nameLower := strings.ToLower(getStringFromMetadata(obj.Fields, "name"))
-			domainLower := strings.ToLower(getStringFromMetadata(obj.Fields, "domain"))
-
-			if !strings.Contains(nameLower, queryLower) && !strings.Contains(domainLower, queryLower) {
-				continue
-			}
-		}
-
 		company, err := ObjectToCompany(obj)
 		if err != nil {
 			continue // Skip malformed objects
 		}
 
 		companies = append(companies, *company)
-
-		if len(companies) >= limit {
-			break
-		}
 	}
 
 	return companies, nil
@@ -103,7 +101,10 @@ func FindCompanyByName(db *sql.DB, name string) (*models.Company, error) {
 	return nil, nil
 }
 
-func UpdateCompany(db *sql.DB, id uuid.UUID, updates *models.Company) error {
+// UpdateCompany saves updates over the company. If updates.Version is set
+// and the company changed since, it returns a *VersionConflictError unless
+// resolve is given.
+func UpdateCompany(db *sql.DB, id uuid.UUID, updates *models.Company, resolve ...ConflictResolver) error {
 	repo := NewObjectsRepository(db)
 
 	// Get existing object to preserve ID and timestamps
@@ -117,8 +118,16 @@ func UpdateCompany(db *sql.DB, id uuid.UUID, updates *models.Company) error {
 	existing.Fields["domain"] = updates.Domain
 	existing.Fields["industry"] = updates.Industry
 	existing.Fields["notes"] = updates.Notes
+	if updates.Version != 0 {
+		existing.Version = updates.Version
+	}
 
-	return repo.Update(context.Background(), existing)
+	resolver := legacyResolver(updates.Version, resolve, "name", "domain", "industry", "notes")
+	if err := repo.UpdateResolving(context.Background(), existing, resolver); err != nil {
+		return err
+	}
+	updates.Version = existing.Version
+	return nil
 }
 
 func DeleteCompany(db *sql.DB, id uuid.UUID) error {
@@ -154,7 +163,7 @@ func DeleteCompany(db *sql.DB, id uuid.UUID) error {
 		companyIDStr := getStringFromMetadata(contact.Fields, "company_id")
 		if companyIDStr == id.String() {
 			delete(contact.Fields, "company_id")
-			if err := repo.Update(context.Background(), contact); err != nil {
+			if err := repo.UpdateResolving(context.Background(), contact, MergeFields("company_id")); err != nil {
 				return fmt.Errorf("failed to update contact: %w", err)
 			}
 		}
@@ -230,50 +239,55 @@ func FindContacts(db *sql.DB, query string, companyID *uuid.UUID, limit int) ([]
 		limit = 10
 	}
 
-	repo := NewObjectsRepository(db)
-	objects, err := repo.List(context.Background(), ObjectTypeContact)
+	filter := ObjectFilter{Fields: map[string]string{}}
+	if companyID != nil {
+		filter.Fields["company_id"] = companyID.String()
+	}
+
+	objects, err := findObjects(db, ObjectTypeContact, query, filter, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	var contacts []models.Contact
-	queryLower := strings.ToLower(query)
 
 	for _, obj := range objects {
-		// Apply company filter if provided
-		if companyID != nil {
-			objCompanyID := getStringFromMetadata(obj.Fields, "company_id")
-			if objCompanyID != companyID.String() {
-				continue
-			}
-		}
-
-		// Apply search filter if query is provided
-		if query != "" {
-			nameLower := strings.ToLower(getStringFromMetadata(obj.Fields, "name"))
-			emailLower := strings.ToLower(getStringFromMetadata(obj.Fields, "email"))
-
-			if !strings.Contains(nameLower, queryLower) && !strings.Contains(emailLower, queryLower) {
-				continue
-			}
-		}
-
 		contact, err := ObjectToContact(obj)
 		if err != nil {
 			continue // Skip malformed objects
 		}
 
 		contacts = append(contacts, *contact)
-
-		if len(contacts) >= limit {
-			break
-		}
 	}
 
 	return contacts, nil
 }
 
-func UpdateContact(db *sql.DB, id uuid.UUID, updates *models.Contact) error {
+// FindContactByEmail returns the oldest contact with the email, ignoring case
+// and surrounding spaces, or nil if there is none. The kind is written out so
+// SQLite can use the partial email index.
+func FindContactByEmail(db *sql.DB, email string) (*models.Contact, error) {
+	var id string
+	err := db.QueryRow(`
+		SELECT id FROM objects
+		WHERE kind = 'Contact' AND lower(trim(json_extract(fields, '$.email'))) = ?
+		ORDER BY created_at
+		LIMIT 1
+	`, strings.ToLower(strings.TrimSpace(email))).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	contactID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contact ID %q: %w", id, err)
+	}
+	return GetContact(db, contactID)
+}
+
+func UpdateContact(db *sql.DB, id uuid.UUID, updates *models.Contact, resolve ...ConflictResolver) error {
 	repo := NewObjectsRepository(db)
 	relRepo := NewRelationshipsRepository(db)
 
@@ -300,10 +314,15 @@ func UpdateContact(db *sql.DB, id uuid.UUID, updates *models.Contact) error {
 	} else {
 		delete(existing.Fields, "company_id")
 	}
+	if updates.Version != 0 {
+		existing.Version = updates.Version
+	}
 
-	if err := repo.Update(context.Background(), existing); err != nil {
+	resolver := legacyResolver(updates.Version, resolve, "name", "email", "phone", "notes", "company_id")
+	if err := repo.UpdateResolving(context.Background(), existing, resolver); err != nil {
 		return err
 	}
+	updates.Version = existing.Version
 
 	// Update works_at relationship if company changed
 	newCompanyIDStr := ""
@@ -369,7 +388,7 @@ func DeleteContact(db *sql.DB, id uuid.UUID) error {
 		contactIDStr := getStringFromMetadata(deal.Fields, "contact_id")
 		if contactIDStr == id.String() {
 			delete(deal.Fields, "contact_id")
-			if err := repo.Update(context.Background(), deal); err != nil {
+			if err := repo.UpdateResolving(context.Background(), deal, MergeFields("contact_id")); err != nil {
 				return fmt.Errorf("failed to update deal: %w", err)
 			}
 		}
@@ -388,7 +407,7 @@ func UpdateContactLastContacted(db *sql.DB, contactID uuid.UUID, timestamp time.
 	}
 
 	obj.Fields["last_contacted_at"] = timestamp.Format(time.RFC3339Nano)
-	return repo.Update(context.Background(), obj)
+	return repo.UpdateResolving(context.Background(), obj, MergeFields("last_contacted_at"))
 }
 
 // Legacy Deal Functions
@@ -425,22 +444,47 @@ func GetDeal(db *sql.DB, id uuid.UUID) (*models.Deal, error) {
 	return ObjectToDeal(obj)
 }
 
-func UpdateDeal(db *sql.DB, deal *models.Deal) error {
+// UpdateDeal saves deal. If deal.Version is set and the deal changed since,
+// it returns a *VersionConflictError unless resolve is given.
+func UpdateDeal(db *sql.DB, deal *models.Deal, resolve ...ConflictResolver) error {
 	// Update last_activity_at timestamp
 	deal.LastActivityAt = time.Now().UTC()
 
 	repo := NewObjectsRepository(db)
 	obj := DealToObject(deal)
-	return repo.Update(context.Background(), obj)
+	if deal.Version == 0 {
+		existing, err := repo.Get(context.Background(), obj.ID)
+		if err != nil {
+			return err
+		}
+		obj.Version = existing.Version
+	}
+
+	if err := repo.UpdateResolving(context.Background(), obj, legacyResolver(deal.Version, resolve, dealFields...)); err != nil {
+		return err
+	}
+	deal.Version = obj.Version
+	return nil
 }
 
+// dealFields are the fields DealToObject writes.
+var dealFields = []string{"title", "amount", "currency", "stage", "company_id", "contact_id",
+	"expected_close_date", "probability", "last_activity_at"}
+
 func FindDeals(db *sql.DB, stage string, companyID *uuid.UUID, limit int) ([]models.Deal, error) {
 	if limit <= 0 {
 		limit = 10
 	}
 
-	repo := NewObjectsRepository(db)
-	objects, err := repo.List(context.Background(), ObjectTypeDeal)
+	filter := ObjectFilter{Fields: map[string]string{}}
+	if companyID != nil {
+		filter.Fields["company_id"] = companyID.String()
+	}
+	if stage != "" {
+		filter.Fields["stage"] = stage
+	}
+
+	objects, err := findObjects(db, ObjectTypeDeal, "", filter, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -448,32 +492,12 @@ func FindDeals(db *sql.DB, stage string, companyID *uuid.UUID, limit int) ([]mod
 	var deals []models.Deal
 
 	for _, obj := range objects {
-		// Apply company filter if provided
-		if companyID != nil {
-			objCompanyID := getStringFromMetadata(obj.Fields, "company_id")
-			if objCompanyID != companyID.String() {
-				continue
-			}
-		}
-
-		// Apply stage filter if provided
-		if stage != "" {
-			objStage := getStringFromMetadata(obj.Fields, "stage")
-			if objStage != stage {
-				continue
-			}
-		}
-
 		deal, err := ObjectToDeal(obj)
 		if err != nil {
 			continue // Skip malformed objects
 		}
 
 		deals = append(deals, *deal)
-
-		if len(deals) >= limit {
-			break
-		}
 	}
 
 	return deals, nil
@@ -522,7 +546,17 @@ func AddDealNote(db *sql.DB, note *models.DealNote) error {
 	// Update last_activity_at
 	deal.Fields["last_activity_at"] = note.CreatedAt.Format(time.RFC3339Nano)
 
-	if err := repo.Update(context.Background(), deal); err != nil {
+	// If the deal changed meanwhile, append to its notes as they are now
+	appendNote := func(current, _ *Object) (*Object, error) {
+		var notes []interface{}
+		if existingNotes, ok := current.Fields["notes"].([]interface{}); ok {
+			notes = existingNotes
+		}
+		current.Fields["notes"] = append(notes, newNote)
+		current.Fields["last_activity_at"] = newNote["created_at"]
+		return current, nil
+	}
+	if err := repo.UpdateResolving(context.Background(), deal, appendNote); err != nil {
 		return err
 	}
 
@@ -531,7 +565,7 @@ func AddDealNote(db *sql.DB, note *models.DealNote) error {
 		contact, err := repo.Get(context.Background(), contactIDStr)
 		if err == nil {
 			contact.Fields["last_contacted_at"] = note.CreatedAt.Format(time.RFC3339Nano)
-			_ = repo.Update(context.Background(), contact)
+			_ = repo.UpdateResolving(context.Background(), contact, MergeFields("last_contacted_at"))
 		}
 	}
 
@@ -0,0 +1,225 @@
+// ABOUTME: SQLite maintenance - integrity check, index rebuilds, ANALYZE, and VACUUM
+// ABOUTME: Works on any SQLite handle and reports database and per-table sizes
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaintenanceOptions selects the steps Maintain runs. The integrity check
+// always runs; the other steps are skipped when it fails.
+type MaintenanceOptions struct {
+	Reindex bool
+	Analyze bool
+	Vacuum  bool
+}
+
+// FullMaintenance runs every maintenance step.
+var FullMaintenance = MaintenanceOptions{Reindex: true, Analyze: true, Vacuum: true}
+
+// TableSize is a table's row count and the bytes used by it and its indexes.
+// Bytes is zero when the SQLite build has no dbstat table.
+type TableSize struct {
+	Name  string
+	Rows  int64
+	Bytes int64
+}
+
+// MaintenanceReport describes what Maintain did.
+type MaintenanceReport struct {
+	IntegrityOK     bool
+	IntegrityErrors []string
+	Reindexed       bool
+	Analyzed        bool
+	Vacuumed        bool
+	SizeBefore      int64
+	SizeAfter       int64
+	Tables          []TableSize
+	Duration        time.Duration
+}
+
+// Maintain checks the database's integrity and, if it is sound, rebuilds
+// indexes, refreshes planner statistics, and vacuums. VACUUM needs the
+// database to itself, so run this between writes.
+func Maintain(db *sql.DB, opts MaintenanceOptions) (*MaintenanceReport, error) {
+	start := time.Now()
+	report := &MaintenanceReport{}
+
+	var err error
+	if report.SizeBefore, err = DatabaseSize(db); err != nil {
+		return nil, err
+	}
+
+	if report.IntegrityErrors, err = IntegrityCheck(db); err != nil {
+		return nil, err
+	}
+	report.IntegrityOK = len(report.IntegrityErrors) == 0
+
+	if report.IntegrityOK {
+		if opts.Reindex {
+			if _, err := db.Exec("REINDEX"); err != nil {
+				return nil, fmt.Errorf("failed to rebuild indexes: %w", err)
+			}
+			report.Reindexed = true
+		}
+		if opts.Analyze {
+			if _, err := db.Exec("ANALYZE"); err != nil {
+				return nil, fmt.Errorf("failed to analyze: %w", err)
+			}
+			report.Analyzed = true
+		}
+		if opts.Vacuum {
+			if _, err := db.Exec("VACUUM"); err != nil {
+				return nil, fmt.Errorf("failed to vacuum: %w", err)
+			}
+			// VACUUM may renumber the objects rowids the search index is keyed by
+			indexed, err := searchIndexUsable(db)
+			if err != nil {
+				return nil, err
+			}
+			if indexed {
+				if err := RebuildSearchIndex(db); err != nil {
+					return nil, err
+				}
+			}
+			// In WAL mode the rewritten pages land in the WAL; fold them back
+			// so the reclaimed space shows up on disk
+			if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+				return nil, fmt.Errorf("failed to checkpoint after vacuum: %w", err)
+			}
+			report.Vacuumed = true
+		}
+	}
+
+	if report.SizeAfter, err = DatabaseSize(db); err != nil {
+		return nil, err
+	}
+	if report.Tables, err = TableSizes(db); err != nil {
+		return nil, err
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems it
+// found, or nil when the database is sound.
+func IntegrityCheck(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check integrity: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to read integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read integrity check: %w", err)
+	}
+	return problems, nil
+}
+
+// DatabaseSize returns the size of the database in bytes.
+func DatabaseSize(db *sql.DB) (int64, error) {
+	var pages, pageSize int64
+	if err := db.QueryRow("PRAGMA page_count").Scan(&pages); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return pages * pageSize, nil
+}
+
+// TableSizes returns every table's row count and size, largest first.
+// Virtual tables are left out; their shadow tables hold the rows, and may
+// need a module this driver lacks.
+func TableSizes(db *sql.DB) ([]TableSize, error) {
+	rows, err := db.Query(`SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND sql NOT LIKE 'CREATE VIRTUAL TABLE%'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		names = append(names, name)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	bytes, err := tableBytes(db)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]TableSize, 0, len(names))
+	for _, name := range names {
+		table := TableSize{Name: name, Bytes: bytes[name]}
+		quoted := `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + quoted).Scan(&table.Rows); err != nil {
+			return nil, fmt.Errorf("failed to count rows in %s: %w", name, err)
+		}
+		tables = append(tables, table)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Bytes != tables[j].Bytes {
+			return tables[i].Bytes > tables[j].Bytes
+		}
+		if tables[i].Rows != tables[j].Rows {
+			return tables[i].Rows > tables[j].Rows
+		}
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
+
+// tableBytes sums dbstat page sizes per table, counting indexes toward the
+// table they belong to. SQLite builds without dbstat yield an empty map.
+func tableBytes(db *sql.DB) (map[string]int64, error) {
+	bytes := make(map[string]int64)
+	rows, err := db.Query(`
+		SELECT m.tbl_name, SUM(s.pgsize)
+		FROM dbstat s
+		JOIN sqlite_master m ON m.name = s.name
+		GROUP BY m.tbl_name
+	`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table: dbstat") {
+			return bytes, nil
+		}
+		return nil, fmt.Errorf("failed to measure tables: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, fmt.Errorf("failed to measure tables: %w", err)
+		}
+		bytes[name] = size
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to measure tables: %w", err)
+	}
+	return bytes, nil
+}
@@ -0,0 +1,76 @@
+// ABOUTME: Tests for database maintenance
+// ABOUTME: Verifies vacuum reclaims space, statistics and table sizes are reported, and check-only runs only the check
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaintain(t *testing.T) {
+	database, err := OpenDatabase(filepath.Join(t.TempDir(), "pagen.db"))
+	if err != nil {
+		t.Fatalf("OpenDatabase failed: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	// Import a batch, then delete most of it to leave free pages behind
+	metadata := `{"note":"` + strings.Repeat("x", 512) + `"}`
+	for i := 0; i < 500; i++ {
+		id := fmt.Sprintf("log-%d", i)
+		if err := CreateSyncLog(database, id, "gmail", id, "interaction", id, metadata); err != nil {
+			t.Fatalf("CreateSyncLog failed: %v", err)
+		}
+	}
+	if _, err := database.Exec(`DELETE FROM sync_log WHERE source_id != 'log-0'`); err != nil {
+		t.Fatalf("failed to delete sync logs: %v", err)
+	}
+
+	report, err := Maintain(database, FullMaintenance)
+	if err != nil {
+		t.Fatalf("Maintain failed: %v", err)
+	}
+	if !report.IntegrityOK || !report.Reindexed || !report.Analyzed || !report.Vacuumed {
+		t.Errorf("expected every step to run, got %+v", report)
+	}
+	if report.SizeAfter >= report.SizeBefore {
+		t.Errorf("expected vacuum to reclaim space, %d -> %d bytes", report.SizeBefore, report.SizeAfter)
+	}
+
+	var syncLog *TableSize
+	for i := range report.Tables {
+		if report.Tables[i].Name == "sync_log" {
+			syncLog = &report.Tables[i]
+		}
+		if strings.HasPrefix(report.Tables[i].Name, "sqlite_") {
+			t.Errorf("internal table %s should not be listed", report.Tables[i].Name)
+		}
+	}
+	if syncLog == nil || syncLog.Rows != 1 {
+		t.Errorf("expected sync_log with 1 row, got %+v", syncLog)
+	}
+
+	// ANALYZE leaves planner statistics behind
+	var stats int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM sqlite_stat1`).Scan(&stats); err != nil || stats == 0 {
+		t.Errorf("expected planner statistics, got %d, %v", stats, err)
+	}
+}
+
+func TestMaintainCheckOnly(t *testing.T) {
+	database := setupTestDB(t)
+	defer func() { _ = database.Close() }()
+
+	report, err := Maintain(database, MaintenanceOptions{})
+	if err != nil {
+		t.Fatalf("Maintain failed: %v", err)
+	}
+	if !report.IntegrityOK || report.Reindexed || report.Analyzed || report.Vacuumed {
+		t.Errorf("expected only the integrity check, got %+v", report)
+	}
+	if len(report.Tables) == 0 {
+		t.Error("expected table sizes to be reported")
+	}
+}
@@ -8,16 +8,97 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 var (
-	ErrObjectNotFound = errors.New("object not found")
-	ErrInvalidObject  = errors.New("invalid object")
+	ErrObjectNotFound  = errors.New("object not found")
+	ErrInvalidObject   = errors.New("invalid object")
+	ErrInvalidFilter   = errors.New("invalid filter")
+	ErrVersionConflict = errors.New("object changed since it was read")
 )
 
+// VersionConflictError is returned by Update when the object's version in
+// the database isn't the one the caller read, i.e. someone else saved it in
+// between. It matches ErrVersionConflict with errors.Is.
+type VersionConflictError struct {
+	ID       string
+	Expected int64 // the version the caller read
+	Current  int64 // the version now in the database
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s is at version %d, not %d", ErrVersionConflict, e.ID, e.Current, e.Expected)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// ConflictResolver decides what to save when an update hits a version
+// conflict. It gets the object as it is now in the database and the one the
+// caller tried to save, and returns the object to save instead, or nil to
+// keep current and drop the update. Returning an error aborts the update.
+type ConflictResolver func(current, proposed *Object) (*Object, error)
+
+// maxConflictRetries bounds how many times UpdateResolving resolves and
+// retries before giving up on an object that keeps changing.
+const maxConflictRetries = 5
+
+// KeepCurrent is a ConflictResolver that drops the update, so the other
+// writer wins.
+func KeepCurrent(current, proposed *Object) (*Object, error) {
+	return nil, nil
+}
+
+// KeepProposed is a ConflictResolver that saves the update over the other
+// writer's, the behavior before objects had versions.
+func KeepProposed(current, proposed *Object) (*Object, error) {
+	return proposed, nil
+}
+
+// MergeFields returns a ConflictResolver that copies only the named fields
+// from the update onto the current object, so the other writer's changes to
+// any other field survive. A name missing from the update is deleted.
+func MergeFields(names ...string) ConflictResolver {
+	return func(current, proposed *Object) (*Object, error) {
+		merged := *current
+		merged.Fields = make(map[string]interface{}, len(current.Fields))
+		for key, value := range current.Fields {
+			merged.Fields[key] = value
+		}
+		for _, name := range names {
+			if value, ok := proposed.Fields[name]; ok {
+				merged.Fields[name] = value
+			} else {
+				delete(merged.Fields, name)
+			}
+		}
+		return &merged, nil
+	}
+}
+
+// FillEmptyFields returns a ConflictResolver like MergeFields that only
+// copies a named field when it's missing or empty in the current object, for
+// writers such as importers that fill gaps rather than overwrite.
+func FillEmptyFields(names ...string) ConflictResolver {
+	return func(current, proposed *Object) (*Object, error) {
+		var empty []string
+		for _, name := range names {
+			if value, ok := current.Fields[name]; !ok || value == nil || value == "" {
+				empty = append(empty, name)
+			}
+		}
+		return MergeFields(empty...)(current, proposed)
+	}
+}
+
 // ObjectsRepository provides CRUD operations for Office OS objects.
 type ObjectsRepository struct {
 	db *sql.DB
@@ -41,6 +122,7 @@ func (r *ObjectsRepository) Create(ctx context.Context, obj *Object) error {
 	now := time.Now().UTC()
 	obj.CreatedAt = now
 	obj.UpdatedAt = now
+	obj.Version = 1
 
 	// Default values for new fields
 	if obj.CreatedBy == "" {
@@ -62,8 +144,8 @@ func (r *ObjectsRepository) Create(ctx context.Context, obj *Object) error {
 	}
 
 	query := `
-		INSERT INTO objects (id, kind, created_at, updated_at, created_by, acl, tags, fields)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO objects (id, kind, created_at, updated_at, version, created_by, acl, tags, fields)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -71,6 +153,7 @@ func (r *ObjectsRepository) Create(ctx context.Context, obj *Object) error {
 		obj.Kind,
 		obj.CreatedAt,
 		obj.UpdatedAt,
+		obj.Version,
 		obj.CreatedBy,
 		obj.ACL,
 		obj.Tags,
@@ -83,7 +166,7 @@ func (r *ObjectsRepository) Create(ctx context.Context, obj *Object) error {
 // Get retrieves an object by ID.
 func (r *ObjectsRepository) Get(ctx context.Context, id string) (*Object, error) {
 	query := `
-		SELECT id, kind, created_at, updated_at, created_by, acl, tags, fields
+		SELECT id, kind, created_at, updated_at, version, created_by, acl, tags, fields
 		FROM objects
 		WHERE id = ?
 	`
@@ -96,6 +179,7 @@ func (r *ObjectsRepository) Get(ctx context.Context, id string) (*Object, error)
 		&obj.Kind,
 		&obj.CreatedAt,
 		&obj.UpdatedAt,
+		&obj.Version,
 		&obj.CreatedBy,
 		&obj.ACL,
 		&obj.Tags,
@@ -120,14 +204,15 @@ func (r *ObjectsRepository) Get(ctx context.Context, id string) (*Object, error)
 	return &obj, nil
 }
 
-// Update updates an existing object.
+// Update saves an existing object if it's still at obj.Version, the version
+// the caller read, and bumps the version. If someone else saved it in
+// between, Update returns a *VersionConflictError rather than overwriting
+// their change; see UpdateResolving.
 func (r *ObjectsRepository) Update(ctx context.Context, obj *Object) error {
 	if obj == nil || obj.ID == "" {
 		return ErrInvalidObject
 	}
 
-	obj.UpdatedAt = time.Now().UTC()
-
 	if obj.Fields == nil {
 		obj.Fields = make(map[string]interface{})
 	}
@@ -137,10 +222,11 @@ func (r *ObjectsRepository) Update(ctx context.Context, obj *Object) error {
 		return err
 	}
 
+	updatedAt := time.Now().UTC()
 	query := `
 		UPDATE objects
-		SET kind = ?, created_by = ?, acl = ?, tags = ?, fields = ?, updated_at = ?
-		WHERE id = ?
+		SET kind = ?, created_by = ?, acl = ?, tags = ?, fields = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -149,8 +235,9 @@ func (r *ObjectsRepository) Update(ctx context.Context, obj *Object) error {
 		obj.ACL,
 		obj.Tags,
 		fieldsJSON,
-		obj.UpdatedAt,
+		updatedAt,
 		obj.ID,
+		obj.Version,
 	)
 
 	if err != nil {
@@ -163,12 +250,50 @@ func (r *ObjectsRepository) Update(ctx context.Context, obj *Object) error {
 	}
 
 	if rows == 0 {
-		return ErrObjectNotFound
+		var current int64
+		err := r.db.QueryRowContext(ctx, `SELECT version FROM objects WHERE id = ?`, obj.ID).Scan(&current)
+		if err == sql.ErrNoRows {
+			return ErrObjectNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return &VersionConflictError{ID: obj.ID, Expected: obj.Version, Current: current}
 	}
 
+	obj.UpdatedAt = updatedAt
+	obj.Version++
 	return nil
 }
 
+// UpdateResolving is Update with a hook for version conflicts: on a
+// conflict it passes the current object and obj to resolve and saves what it
+// returns, retrying if the object changed again meanwhile. A nil resolve
+// returns the conflict. On success obj holds what was saved, which is the
+// current object when resolve kept it.
+func (r *ObjectsRepository) UpdateResolving(ctx context.Context, obj *Object, resolve ConflictResolver) error {
+	err := r.Update(ctx, obj)
+	for attempt := 0; attempt < maxConflictRetries && resolve != nil && errors.Is(err, ErrVersionConflict); attempt++ {
+		current, getErr := r.Get(ctx, obj.ID)
+		if getErr != nil {
+			return getErr
+		}
+		resolved, resolveErr := resolve(current, obj)
+		if resolveErr != nil {
+			return resolveErr
+		}
+		if resolved == nil {
+			*obj = *current
+			return nil
+		}
+		resolved.Version = current.Version
+		if err = r.Update(ctx, resolved); err == nil && resolved != obj {
+			*obj = *resolved
+		}
+	}
+	return err
+}
+
 // Delete deletes an object by ID.
 func (r *ObjectsRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM objects WHERE id = ?`
@@ -192,23 +317,93 @@ func (r *ObjectsRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves all objects, optionally filtered by kind.
 func (r *ObjectsRepository) List(ctx context.Context, objectKind string) ([]*Object, error) {
-	var query string
+	return r.ListPage(ctx, objectKind, ObjectFilter{}, ObjectSort{Desc: true}, 0, 0)
+}
+
+// ObjectFilter narrows ListPage. Conditions are combined with AND.
+type ObjectFilter struct {
+	// Fields matches objects whose JSON field equals the value, e.g.
+	// {"company_id": id}. Keys are plain field names.
+	Fields map[string]string
+	// IDs limits results to these objects when non-nil; an empty slice matches nothing.
+	IDs []string
+}
+
+// ObjectSort orders ListPage results. Field is created_at (the default),
+// updated_at, or a JSON field name such as "name".
+type ObjectSort struct {
+	Field string
+	Desc  bool
+}
+
+// fieldNamePattern is what a JSON field name may look like in a filter or
+// sort. Names are written into the SQL so expression indexes apply, so
+// anything else is rejected.
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ListPage retrieves one page of objects of a kind (all kinds when empty),
+// filtering and ordering in SQL so large tables aren't loaded into memory.
+// A limit of 0 returns every match after offset.
+func (r *ObjectsRepository) ListPage(ctx context.Context, objectKind string, filter ObjectFilter, sort ObjectSort, limit, offset int) ([]*Object, error) {
+	var where []string
 	var args []interface{}
 
 	if objectKind != "" {
-		query = `
-			SELECT id, kind, created_at, updated_at, created_by, acl, tags, fields
-			FROM objects
-			WHERE kind = ?
-			ORDER BY created_at DESC
-		`
+		where = append(where, "kind = ?")
 		args = append(args, objectKind)
-	} else {
-		query = `
-			SELECT id, kind, created_at, updated_at, created_by, acl, tags, fields
-			FROM objects
-			ORDER BY created_at DESC
-		`
+	}
+	// Sorted so the same filter always produces the same query
+	keys := make([]string, 0, len(filter.Fields))
+	for key := range filter.Fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !fieldNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: invalid field name %q", ErrInvalidFilter, key)
+		}
+		where = append(where, fmt.Sprintf("json_extract(fields, '$.%s') = ?", key))
+		args = append(args, filter.Fields[key])
+	}
+	if filter.IDs != nil {
+		ids, err := json.Marshal(filter.IDs)
+		if err != nil {
+			return nil, err
+		}
+		// One JSON parameter rather than one per ID, which could exceed
+		// SQLite's variable limit for large searches
+		where = append(where, "id IN (SELECT value FROM json_each(?))")
+		args = append(args, string(ids))
+	}
+
+	order := "created_at"
+	switch sort.Field {
+	case "", "created_at":
+	case "updated_at":
+		order = "updated_at"
+	default:
+		if !fieldNamePattern.MatchString(sort.Field) {
+			return nil, fmt.Errorf("%w: invalid sort field %q", ErrInvalidFilter, sort.Field)
+		}
+		order = fmt.Sprintf("json_extract(fields, '$.%s') COLLATE NOCASE", sort.Field)
+	}
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+
+	query := `SELECT id, kind, created_at, updated_at, version, created_by, acl, tags, fields FROM objects`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// id breaks ties so pages don't overlap
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s", order, direction, direction)
+	if limit > 0 || offset > 0 {
+		if limit <= 0 {
+			limit = -1 // SQLite's "no limit"
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
 	}
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -228,6 +423,7 @@ func (r *ObjectsRepository) List(ctx context.Context, objectKind string) ([]*Obj
 			&obj.Kind,
 			&obj.CreatedAt,
 			&obj.UpdatedAt,
+			&obj.Version,
 			&obj.CreatedBy,
 			&obj.ACL,
 			&obj.Tags,
@@ -1,7 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -28,3 +31,180 @@ func TestObjectsTableCreation(t *testing.T) {
 		t.Errorf("Expected table name 'objects', got %s", tableName)
 	}
 }
+
+func TestListPage(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	repo := NewObjectsRepository(db)
+	for _, fields := range []map[string]interface{}{
+		{"name": "delta", "company_id": "acme"},
+		{"name": "Alpha", "company_id": "acme"},
+		{"name": "charlie", "company_id": "globex"},
+		{"name": "bravo", "company_id": "acme"},
+	} {
+		if err := repo.Create(ctx, &Object{Kind: ObjectTypeContact, Fields: fields}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, &Object{Kind: ObjectTypeCompany, Fields: map[string]interface{}{"name": "acme"}}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	names := func(objects []*Object) string {
+		var out []string
+		for _, obj := range objects {
+			out = append(out, getStringFromMetadata(obj.Fields, "name"))
+		}
+		return strings.Join(out, ",")
+	}
+
+	filter := ObjectFilter{Fields: map[string]string{"company_id": "acme"}}
+	byName := ObjectSort{Field: "name"}
+	page, err := repo.ListPage(ctx, ObjectTypeContact, filter, byName, 2, 0)
+	if err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+	if got := names(page); got != "Alpha,bravo" {
+		t.Errorf("first page = %s, want Alpha,bravo", got)
+	}
+	page, err = repo.ListPage(ctx, ObjectTypeContact, filter, byName, 2, 2)
+	if err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+	if got := names(page); got != "delta" {
+		t.Errorf("second page = %s, want delta", got)
+	}
+
+	all, err := repo.ListPage(ctx, ObjectTypeContact, ObjectFilter{}, ObjectSort{Field: "name", Desc: true}, 0, 1)
+	if err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+	if got := names(all); got != "charlie,bravo,Alpha" {
+		t.Errorf("offset without limit = %s, want charlie,bravo,Alpha", got)
+	}
+
+	none, err := repo.ListPage(ctx, ObjectTypeContact, ObjectFilter{IDs: []string{}}, ObjectSort{}, 0, 0)
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected an empty ID set to match nothing, got %d, %v", len(none), err)
+	}
+
+	if _, err := repo.ListPage(ctx, ObjectTypeContact, ObjectFilter{Fields: map[string]string{"name') OR 1=1 --": "x"}}, ObjectSort{}, 0, 0); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected ErrInvalidFilter for a bad field name, got %v", err)
+	}
+	if _, err := repo.ListPage(ctx, ObjectTypeContact, ObjectFilter{}, ObjectSort{Field: "name desc"}, 0, 0); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected ErrInvalidFilter for a bad sort field, got %v", err)
+	}
+}
+
+func TestListPageUsesIndex(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query(`EXPLAIN QUERY PLAN
+		SELECT id FROM objects
+		WHERE kind = ? AND json_extract(fields, '$.company_id') = ?
+		ORDER BY created_at DESC, id DESC`, ObjectTypeContact, "acme")
+	if err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var plan string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		plan += detail + "\n"
+	}
+	if !strings.Contains(plan, "idx_objects_kind_company_id") {
+		t.Errorf("expected the company index to be used, got plan:\n%s", plan)
+	}
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	repo := NewObjectsRepository(db)
+	obj := &Object{Kind: ObjectTypeContact, Fields: map[string]interface{}{"name": "Ada", "phone": "555"}}
+	if err := repo.Create(ctx, obj); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if obj.Version != 1 {
+		t.Fatalf("expected version 1, got %d", obj.Version)
+	}
+
+	// Two writers read the same version
+	web, _ := repo.Get(ctx, obj.ID)
+	mcp, _ := repo.Get(ctx, obj.ID)
+	web.Fields["name"] = "Ada Lovelace"
+	if err := repo.Update(ctx, web); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if web.Version != 2 {
+		t.Errorf("expected version 2 after an update, got %d", web.Version)
+	}
+
+	mcp.Fields["phone"] = "556"
+	err := repo.Update(ctx, mcp)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrVersionConflict) || conflict.Expected != 1 || conflict.Current != 2 {
+		t.Fatalf("expected a version conflict, got %v", err)
+	}
+	if err := repo.Update(ctx, &Object{ID: "missing", Version: 1}); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+
+	// The resolver sees both sides; merging keeps the other writer's name
+	if err := repo.UpdateResolving(ctx, mcp, MergeFields("phone")); err != nil {
+		t.Fatalf("UpdateResolving failed: %v", err)
+	}
+	got, _ := repo.Get(ctx, obj.ID)
+	if got.Fields["name"] != "Ada Lovelace" || got.Fields["phone"] != "556" || got.Version != 3 || mcp.Version != 3 {
+		t.Errorf("unexpected merge %+v (caller at version %d)", got, mcp.Version)
+	}
+
+	stale := *web
+	stale.Fields = map[string]interface{}{"name": "Stale"}
+	if err := repo.UpdateResolving(ctx, &stale, KeepCurrent); err != nil {
+		t.Fatalf("UpdateResolving failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, obj.ID); got.Fields["name"] != "Ada Lovelace" || got.Version != 3 || stale.Version != 3 {
+		t.Errorf("expected the current object kept, got %+v", got)
+	}
+	if err := repo.UpdateResolving(ctx, web, nil); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("expected a nil resolver to return the conflict, got %v", err)
+	}
+}
+
+func TestObjectsVersionMigration(t *testing.T) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test db: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	database.SetMaxOpenConns(1)
+
+	// An objects table from before versions
+	if _, err := database.Exec(`CREATE TABLE objects (id TEXT PRIMARY KEY, kind TEXT NOT NULL,
+		created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL, created_by TEXT NOT NULL,
+		acl TEXT NOT NULL DEFAULT '[]', tags TEXT NOT NULL DEFAULT '[]', fields TEXT NOT NULL DEFAULT '{}');
+		INSERT INTO objects (id, kind, created_at, updated_at, created_by) VALUES ('old', 'Contact', '2024-01-01', '2024-01-01', 'system')`); err != nil {
+		t.Fatalf("failed to create old table: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := InitSchema(database); err != nil {
+			t.Fatalf("InitSchema failed: %v", err)
+		}
+	}
+
+	obj, err := NewObjectsRepository(database).Get(context.Background(), "old")
+	if err != nil || obj.Version != 1 {
+		t.Errorf("expected the old object at version 1, got %+v (%v)", obj, err)
+	}
+}
@@ -4,6 +4,7 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type Object struct {
 	Kind      string                 `json:"kind"`
 	CreatedAt time.Time              `json:"created_at"`
 	UpdatedAt time.Time              `json:"updated_at"`
+	Version   int64                  `json:"version"` // bumped on every update, see ObjectsRepository.Update
 	CreatedBy string                 `json:"created_by"`
 	ACL       string                 `json:"acl"`  // JSON string
 	Tags      string                 `json:"tags"` // JSON string
@@ -31,6 +33,7 @@ func InitSchema(db *sql.DB) error {
 		kind TEXT NOT NULL,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_by TEXT NOT NULL,
 		acl TEXT NOT NULL DEFAULT '[]',
 		tags TEXT NOT NULL DEFAULT '[]',
@@ -41,6 +44,18 @@ func InitSchema(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_objects_created_at ON objects(created_at);
 	CREATE INDEX IF NOT EXISTS idx_objects_created_by ON objects(created_by);
 
+	-- Contact lookups by email during sync (see FindContactByEmail)
+	CREATE INDEX IF NOT EXISTS idx_objects_contact_email
+		ON objects(lower(trim(json_extract(fields, '$.email')))) WHERE kind = 'Contact';
+
+	-- Paging and filtering in SQL (see ObjectsRepository.ListPage). Not
+	-- partial indexes, so they also apply when the kind is a parameter.
+	CREATE INDEX IF NOT EXISTS idx_objects_kind_created_at ON objects(kind, created_at);
+	CREATE INDEX IF NOT EXISTS idx_objects_kind_company_id
+		ON objects(kind, json_extract(fields, '$.company_id'), created_at);
+	CREATE INDEX IF NOT EXISTS idx_objects_kind_stage
+		ON objects(kind, json_extract(fields, '$.stage'), created_at);
+
 	CREATE TABLE IF NOT EXISTS relationships (
 		id TEXT PRIMARY KEY,
 		source_id TEXT NOT NULL,
@@ -122,6 +137,39 @@ func InitSchema(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_suggestions_type ON suggestions(type);
 	`
 
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+	if err := addObjectsVersion(db); err != nil {
+		return err
+	}
+	return initSearchIndex(db)
+}
+
+// addObjectsVersion adds the version column to objects tables created
+// before it existed. Existing objects start at version 1.
+func addObjectsVersion(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('objects')")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == "version" {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	if _, err := db.Exec("ALTER TABLE objects ADD COLUMN version INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return fmt.Errorf("failed to add objects.version: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,301 @@
+// ABOUTME: Full-text search index over objects, kept current by triggers
+// ABOUTME: Uses FTS5 when the SQLite driver has it and FTS4 otherwise; results are ranked here
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchResult is an object matching a search.
+type SearchResult struct {
+	ID    string
+	Kind  string
+	Name  string // name, or title for deals
+	Email string
+	Score float64
+}
+
+// SearchOptions narrows a search.
+type SearchOptions struct {
+	Kinds []string // Object kinds to return; empty returns all
+	Limit int      // Max results (0 = unlimited)
+}
+
+// searchIndexSchemas create the index, preferring FTS5. mattn/go-sqlite3
+// only builds FTS5 with the sqlite_fts5 tag, but always has FTS4. An
+// existing index is kept whichever module created it.
+var searchIndexSchemas = []string{
+	`CREATE VIRTUAL TABLE search_index USING fts5(
+		object_id UNINDEXED, kind UNINDEXED, name, email, notes, metadata
+	)`,
+	`CREATE VIRTUAL TABLE search_index USING fts4(
+		object_id, kind, name, email, notes, metadata,
+		notindexed=object_id, notindexed=kind, tokenize=unicode61
+	)`,
+}
+
+// searchColumns extracts the indexed text from the objects row {row}. Deal
+// notes are an array of {content} objects; metadata is every other text
+// field except IDs, timestamps, and dates.
+const searchColumns = `
+	{row}.id,
+	{row}.kind,
+	coalesce(json_extract({row}.fields, '$.name'), json_extract({row}.fields, '$.title'), ''),
+	coalesce(json_extract({row}.fields, '$.email'), ''),
+	CASE json_type({row}.fields, '$.notes')
+		WHEN 'text' THEN json_extract({row}.fields, '$.notes')
+		WHEN 'array' THEN (SELECT group_concat(json_extract(value, '$.content'), ' ') FROM json_each({row}.fields, '$.notes'))
+		ELSE ''
+	END,
+	coalesce((SELECT group_concat(value, ' ') FROM json_each({row}.fields)
+		WHERE type = 'text' AND key NOT IN ('name', 'title', 'email', 'notes')
+			AND key NOT LIKE '%id' AND key NOT LIKE '%\_at' ESCAPE '\' AND key NOT LIKE '%date'), '')`
+
+const searchIndexInsert = `INSERT INTO search_index (rowid, object_id, kind, name, email, notes, metadata)`
+
+// The index shares rowids with objects so triggers can find a row's entry
+// without scanning.
+var searchTriggers = strings.ReplaceAll(`
+	CREATE TRIGGER IF NOT EXISTS objects_search_insert AFTER INSERT ON objects BEGIN
+		`+searchIndexInsert+` SELECT new.rowid, `+searchColumns+`;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS objects_search_update AFTER UPDATE OF kind, fields ON objects BEGIN
+		DELETE FROM search_index WHERE rowid = old.rowid;
+		`+searchIndexInsert+` SELECT new.rowid, `+searchColumns+`;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS objects_search_delete AFTER DELETE ON objects BEGIN
+		DELETE FROM search_index WHERE rowid = old.rowid;
+	END;
+	`, "{row}", "new")
+
+// initSearchIndex creates the search index and its triggers, filling the
+// index from existing objects the first time.
+func initSearchIndex(db *sql.DB) error {
+	exists, err := hasSearchIndex(db)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		for _, schema := range searchIndexSchemas {
+			if _, err = db.Exec(schema); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	if _, err := db.Exec(searchTriggers); err != nil {
+		return fmt.Errorf("failed to create search triggers: %w", err)
+	}
+
+	if !exists {
+		return RebuildSearchIndex(db)
+	}
+	return nil
+}
+
+func hasSearchIndex(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'search_index'`).Scan(&count)
+	return count > 0, err
+}
+
+// searchIndexUsable reports whether the search index exists and this
+// driver has the module it was created with. modernc.org/sqlite has FTS5
+// but not FTS4, so db maintain can't touch an index made under mattn.
+func searchIndexUsable(db *sql.DB) (bool, error) {
+	exists, err := hasSearchIndex(db)
+	if err != nil || !exists {
+		return false, err
+	}
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM search_index WHERE rowid = 0`).Scan(&count); err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// RebuildSearchIndex refills the search index from objects. VACUUM
+// renumbers the rowids the index is keyed by, so Maintain calls this after
+// vacuuming.
+func RebuildSearchIndex(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM search_index`); err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+	fill := searchIndexInsert + ` SELECT objects.rowid, ` + strings.ReplaceAll(searchColumns, "{row}", "objects") + ` FROM objects`
+	if _, err := tx.Exec(fill); err != nil {
+		return fmt.Errorf("failed to fill search index: %w", err)
+	}
+	return tx.Commit()
+}
+
+// searchWeights rank a hit in a name above one in an email, metadata, or
+// notes, in search_index column order.
+var searchWeights = [4]float64{8, 4, 1, 2}
+
+// SearchAll finds objects whose names, emails, notes, or other text fields
+// contain a word starting with each word of the query, best match first.
+// Ranking is done here rather than with FTS5's bm25 so the FTS4 fallback
+// orders results the same way: a whole-word hit scores twice a prefix hit,
+// weighted by the column it is in.
+func SearchAll(db *sql.DB, query string, opts SearchOptions) ([]SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = term + "*"
+	}
+	sqlQuery := `SELECT object_id, kind, name, email, notes, metadata FROM search_index WHERE search_index MATCH ?`
+	args := []interface{}{strings.Join(match, " ")}
+	if len(opts.Kinds) > 0 {
+		sqlQuery += ` AND kind IN (?` + strings.Repeat(`, ?`, len(opts.Kinds)-1) + `)`
+		for _, kind := range opts.Kinds {
+			args = append(args, kind)
+		}
+	}
+
+	rows, err := db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		var columns [4]string
+		if err := rows.Scan(&result.ID, &result.Kind, &columns[0], &columns[1], &columns[2], &columns[3]); err != nil {
+			return nil, err
+		}
+		result.Name, result.Email = columns[0], columns[1]
+		for i, column := range columns {
+			result.Score += searchWeights[i] * scoreSearchColumn(terms, column)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// ScoreSearchFields scores a record's name, email, notes, and other text
+// the way SearchAll ranks index rows, for records that aren't in a database.
+// It returns 0 unless every word of the query starts a word in one of them,
+// as the index's MATCH requires.
+func ScoreSearchFields(query string, fields [4]string) float64 {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return 0
+	}
+	all := searchTerms(strings.Join(fields[:], " "))
+	for _, term := range terms {
+		found := false
+		for _, word := range all {
+			if strings.HasPrefix(word, term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+
+	var score float64
+	for i, field := range fields {
+		score += searchWeights[i] * scoreSearchColumn(terms, field)
+	}
+	return score
+}
+
+// searchTerms splits a query into lowercase words the way the unicode61
+// tokenizer does, which also keeps FTS query syntax out of the match.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// scoreSearchColumn scores 2 for each term that is a whole word of text
+// and 1 for each that only starts one.
+func scoreSearchColumn(terms []string, text string) float64 {
+	words := searchTerms(text)
+	var score float64
+	for _, term := range terms {
+		best := 0.0
+		for _, word := range words {
+			if word == term {
+				best = 2
+				break
+			}
+			if strings.HasPrefix(word, term) {
+				best = 1
+			}
+		}
+		score += best
+	}
+	return score
+}
+
+// findObjects returns up to limit objects of a kind matching filter (0 is
+// unlimited), best search match first when query is set and newest first
+// otherwise. The filter is applied in SQL either way.
+func findObjects(db *sql.DB, kind, query string, filter ObjectFilter, limit int) ([]*Object, error) {
+	repo := NewObjectsRepository(db)
+	if query == "" {
+		return repo.ListPage(context.Background(), kind, filter, ObjectSort{Desc: true}, limit, 0)
+	}
+
+	results, err := SearchAll(db, query, SearchOptions{Kinds: []string{kind}})
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(results))
+	filter.IDs = make([]string, len(results))
+	for i, result := range results {
+		rank[result.ID] = i
+		filter.IDs[i] = result.ID
+	}
+	objects, err := repo.ListPage(context.Background(), kind, filter, ObjectSort{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(objects, func(i, j int) bool { return rank[objects[i].ID] < rank[objects[j].ID] })
+	if limit > 0 && len(objects) > limit {
+		objects = objects[:limit]
+	}
+	return objects, nil
+}
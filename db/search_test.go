@@ -0,0 +1,142 @@
+// ABOUTME: Tests for the full-text search index
+// ABOUTME: Covers ranking, prefix matching, deal notes, kind filters, and keeping the index current
+
+package db
+
+import (
+	"testing"
+
+	"github.com/harperreed/pagen/models"
+)
+
+func TestSearchAll(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	company := &models.Company{Name: "Acme Corp", Domain: "acme.com", Industry: "Robotics"}
+	if err := CreateCompany(db, company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	alice := &models.Contact{Name: "Alice Robertson", Email: "alice@acme.com"}
+	bob := &models.Contact{Name: "Bob Smith", Email: "bob@example.com", Notes: "Intro from Alice at the robotics meetup"}
+	for _, contact := range []*models.Contact{alice, bob} {
+		if err := CreateContact(db, contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	deal := &models.Deal{Title: "Pilot", CompanyID: company.ID, Stage: models.StageProspecting}
+	if err := CreateDeal(db, deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if err := AddDealNote(db, &models.DealNote{DealID: deal.ID, Content: "Waiting on the security questionnaire"}); err != nil {
+		t.Fatalf("AddDealNote failed: %v", err)
+	}
+
+	names := func(query string, opts SearchOptions) []string {
+		t.Helper()
+		results, err := SearchAll(db, query, opts)
+		if err != nil {
+			t.Fatalf("SearchAll(%q) failed: %v", query, err)
+		}
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Name)
+		}
+		return got
+	}
+	expect := func(query string, opts SearchOptions, want ...string) {
+		t.Helper()
+		got := names(query, opts)
+		if len(got) != len(want) {
+			t.Fatalf("SearchAll(%q): expected %v, got %v", query, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("SearchAll(%q): expected %v, got %v", query, want, got)
+				break
+			}
+		}
+	}
+
+	// A name hit outranks the same word in notes; prefixes match
+	expect("alice", SearchOptions{}, "Alice Robertson", "Bob Smith")
+	expect("rob", SearchOptions{}, "Alice Robertson", "Acme Corp", "Bob Smith")
+	// Every word must match, across columns
+	expect("alice meetup", SearchOptions{}, "Bob Smith")
+	// Emails, metadata, and deal notes are searchable
+	expect("example.com", SearchOptions{}, "Bob Smith")
+	expect("prospect", SearchOptions{}, "Pilot")
+	expect("questionnaire", SearchOptions{}, "Pilot")
+	// Kinds and limits narrow results
+	expect("acme", SearchOptions{Kinds: []string{ObjectTypeContact}}, "Alice Robertson")
+	expect("alice", SearchOptions{Limit: 1}, "Alice Robertson")
+	// FTS syntax in the query is treated as words
+	expect(`"alice" OR NOT*`, SearchOptions{})
+	expect("  ", SearchOptions{})
+
+	// Updates and deletes keep the index current
+	bob.Notes = ""
+	if err := UpdateContact(db, bob.ID, bob); err != nil {
+		t.Fatalf("UpdateContact failed: %v", err)
+	}
+	expect("meetup", SearchOptions{})
+	if err := DeleteContact(db, alice.ID); err != nil {
+		t.Fatalf("DeleteContact failed: %v", err)
+	}
+	expect("alice", SearchOptions{})
+}
+
+func TestScoreSearchFields(t *testing.T) {
+	fields := [4]string{"Alice Robertson", "alice@acme.com", "Met at the robotics meetup", "Acme Robotics CTO"}
+
+	if score := ScoreSearchFields("rob meetup", fields); score == 0 {
+		t.Error("Expected every word to match")
+	}
+	if score := ScoreSearchFields("rob gardening", fields); score != 0 {
+		t.Errorf("Expected no match when a word is missing, got %v", score)
+	}
+	if whole, prefix := ScoreSearchFields("alice", fields), ScoreSearchFields("ali", fields); whole <= prefix {
+		t.Errorf("Expected a whole word (%v) to outrank a prefix (%v)", whole, prefix)
+	}
+	if name, notes := ScoreSearchFields("robertson", fields), ScoreSearchFields("meetup", fields); name <= notes {
+		t.Errorf("Expected a name hit (%v) to outrank a notes hit (%v)", name, notes)
+	}
+	if score := ScoreSearchFields("", fields); score != 0 {
+		t.Errorf("Expected an empty query to score 0, got %v", score)
+	}
+}
+
+func TestSearchIndexSurvivesVacuum(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	var contacts []*models.Contact
+	for _, name := range []string{"Ada Lovelace", "Grace Hopper", "Alan Turing"} {
+		contact := &models.Contact{Name: name}
+		if err := CreateContact(db, contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+		contacts = append(contacts, contact)
+	}
+	if err := DeleteContact(db, contacts[0].ID); err != nil {
+		t.Fatalf("DeleteContact failed: %v", err)
+	}
+
+	if _, err := Maintain(db, FullMaintenance); err != nil {
+		t.Fatalf("Maintain failed: %v", err)
+	}
+	if err := DeleteContact(db, contacts[1].ID); err != nil {
+		t.Fatalf("DeleteContact failed: %v", err)
+	}
+
+	found, err := FindContacts(db, "alan", nil, 10)
+	if err != nil {
+		t.Fatalf("FindContacts failed: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != contacts[2].ID {
+		t.Errorf("expected only Alan Turing, got %+v", found)
+	}
+	if found, _ := FindContacts(db, "grace", nil, 10); len(found) != 0 {
+		t.Errorf("expected the deleted contact to leave the index, got %+v", found)
+	}
+}
@@ -0,0 +1,112 @@
+// ABOUTME: Detail levels and continuation cursors for list-returning MCP tools
+// ABOUTME: Keeps results within an output budget so large lists don't flood the model's context
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Detail levels for list results.
+const (
+	DetailIDs     = "ids"     // id and name only
+	DetailSummary = "summary" // key fields, without notes and timestamps
+	DetailFull    = "full"    // every field (default)
+)
+
+// MaxOutputBytes is the JSON size a page of results is kept under. Results past
+// it are left for the next page, so one call can't blow the model's context.
+const MaxOutputBytes = 24 * 1024
+
+// ListOptions are the detail and paging parameters shared by list tools.
+type ListOptions struct {
+	Detail string `json:"detail,omitempty" jsonschema:"Fields per result: ids, summary, or full (default full)"`
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor from a previous call, to fetch the next page"`
+}
+
+// Page is the paging information returned by list tools.
+type Page struct {
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"` // cut short by the output budget, not the limit
+}
+
+// EntityRef is the ids detail level of a result whose full form has required fields.
+type EntityRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"` // the title, for deals
+}
+
+// detail validates and defaults the requested detail level.
+func (o ListOptions) detail() (string, error) {
+	switch o.Detail {
+	case "":
+		return DetailFull, nil
+	case DetailIDs, DetailSummary, DetailFull:
+		return o.Detail, nil
+	default:
+		return "", fmt.Errorf("invalid detail: %s (valid: %s, %s, %s)", o.Detail, DetailIDs, DetailSummary, DetailFull)
+	}
+}
+
+// offset decodes the cursor into the index of the first result.
+func (o ListOptions) offset() (int, error) {
+	if o.Cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(o.Cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), "offset:"))
+	if err != nil || offset < 0 || !strings.HasPrefix(string(data), "offset:") {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// paginate returns up to limit items starting at the cursor, stopping early
+// once the page would exceed MaxOutputBytes. A page always holds at least one
+// item so paging makes progress. A limit of 0 means no limit.
+func paginate[T any](items []T, opts ListOptions, limit int) ([]T, Page, error) {
+	page := Page{Total: len(items)}
+	offset, err := opts.offset()
+	if err != nil {
+		return nil, page, err
+	}
+	if offset >= len(items) {
+		return []T{}, page, nil
+	}
+
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	size := 0
+	result := make([]T, 0, end-offset)
+	for _, item := range items[offset:end] {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, page, fmt.Errorf("failed to encode result: %w", err)
+		}
+		if len(result) > 0 && size+len(data) > MaxOutputBytes {
+			page.Truncated = true
+			break
+		}
+		size += len(data) + 1
+		result = append(result, item)
+	}
+
+	if next := offset + len(result); next < len(items) {
+		page.NextCursor = encodeCursor(next)
+	}
+	return result, page, nil
+}
@@ -0,0 +1,117 @@
+// ABOUTME: Tests for detail levels and continuation cursors on list tools
+// ABOUTME: Checks paging by limit and by output budget, and trimmed fields per detail level
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+
+	page, info, err := paginate(items, ListOptions{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || info.Total != 5 || info.NextCursor == "" || info.Truncated {
+		t.Fatalf("unexpected first page %v %+v", page, info)
+	}
+
+	var seen []int
+	seen = append(seen, page...)
+	for info.NextCursor != "" {
+		page, info, err = paginate(items, ListOptions{Cursor: info.NextCursor}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen = append(seen, page...)
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(items) {
+		t.Errorf("expected every item once across pages, got %v", seen)
+	}
+
+	if _, _, err := paginate(items, ListOptions{Cursor: "bogus!"}, 2); err == nil {
+		t.Error("expected an invalid cursor to fail")
+	}
+}
+
+func TestPaginateOutputBudget(t *testing.T) {
+	big := strings.Repeat("x", MaxOutputBytes/3)
+	items := []string{big, big, big, big}
+
+	page, info, err := paginate(items, ListOptions{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || !info.Truncated || info.NextCursor == "" {
+		t.Errorf("expected the budget to cut the page at 2, got %d %+v", len(page), info)
+	}
+
+	// A single oversized item is still returned so paging makes progress
+	huge := []string{strings.Repeat("x", MaxOutputBytes*2), "small"}
+	page, info, _ = paginate(huge, ListOptions{}, 0)
+	if len(page) != 1 || info.NextCursor == "" {
+		t.Errorf("expected one oversized item per page, got %d %+v", len(page), info)
+	}
+}
+
+func TestFindContactsDetail(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewContactHandlers(client)
+	for i := 0; i < 3; i++ {
+		contact := &charm.Contact{Name: fmt.Sprintf("Contact %d", i), Email: fmt.Sprintf("c%d@example.com", i), Notes: "Long notes"}
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	ctx := context.Background()
+
+	_, out, err := handler.FindContacts(ctx, nil, FindContactsInput{Limit: 2, ListOptions: ListOptions{Detail: DetailIDs}})
+	if err != nil {
+		t.Fatalf("FindContacts failed: %v", err)
+	}
+	data, _ := json.Marshal(out.Contacts[0])
+	if !strings.HasPrefix(string(data), `{"id":`) || strings.Contains(string(data), "email") {
+		t.Errorf("expected only id and name, got %s", data)
+	}
+	if out.Total != 3 || out.NextCursor == "" {
+		t.Errorf("expected a cursor to the third contact, got %+v", out.Page)
+	}
+
+	_, next, err := handler.FindContacts(ctx, nil, FindContactsInput{Limit: 2, ListOptions: ListOptions{Detail: DetailSummary, Cursor: out.NextCursor}})
+	if err != nil {
+		t.Fatalf("FindContacts failed: %v", err)
+	}
+	if len(next.Contacts) != 1 || next.Contacts[0].Name != "Contact 2" || next.NextCursor != "" {
+		t.Errorf("unexpected last page %+v", next)
+	}
+	if c := next.Contacts[0]; c.Email == "" || c.Notes != "" || c.CreatedAt != "" {
+		t.Errorf("expected summary to keep email and drop notes and timestamps, got %+v", c)
+	}
+
+	if _, _, err := handler.FindContacts(ctx, nil, FindContactsInput{ListOptions: ListOptions{Detail: "everything"}}); err == nil {
+		t.Error("expected an invalid detail level to fail")
+	}
+}
+
+func TestQueryCRMDealDetail(t *testing.T) {
+	client := charm.NewTestClient(t)
+	_, deal, err := NewDealHandlers(client).CreateDeal(context.Background(), nil, CreateDealInput{Title: "Pilot", CompanyName: "Acme"})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	_, out, err := NewQueryHandlers(client).QueryCRM(context.Background(), nil, QueryCRMInput{EntityType: "deal", ListOptions: ListOptions{Detail: DetailIDs}})
+	if err != nil {
+		t.Fatalf("QueryCRM failed: %v", err)
+	}
+	if ref, ok := out.Results[0].(EntityRef); !ok || ref.ID != deal.ID || ref.Name != "Pilot" {
+		t.Errorf("expected a deal reference, got %+v", out.Results[0])
+	}
+}
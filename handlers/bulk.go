@@ -0,0 +1,306 @@
+// ABOUTME: Bulk upsert MCP tool handler
+// ABOUTME: Imports contacts, companies, and deals in one call with a result per record
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MaxBulkRecords is the most records a single bulk_upsert call accepts.
+const MaxBulkRecords = 500
+
+// Bulk record statuses.
+const (
+	BulkCreated   = "created"
+	BulkUpdated   = "updated"
+	BulkUnchanged = "unchanged"
+	BulkFailed    = "failed"
+)
+
+type BulkHandlers struct {
+	client    *charm.Client
+	contacts  *ContactHandlers
+	companies *CompanyHandlers
+	deals     *DealHandlers
+}
+
+func NewBulkHandlers(client *charm.Client) *BulkHandlers {
+	return &BulkHandlers{
+		client:    client,
+		contacts:  NewContactHandlers(client),
+		companies: NewCompanyHandlers(client),
+		deals:     NewDealHandlers(client),
+	}
+}
+
+// BulkRecord is one contact, company, or deal to create or update.
+type BulkRecord struct {
+	Type string `json:"type" jsonschema:"Record type: contact, company, or deal (required)"`
+
+	// Contacts and companies
+	Name  string `json:"name,omitempty" jsonschema:"Contact or company name (required for contacts and companies)"`
+	Email string `json:"email,omitempty" jsonschema:"Contact email; matches an existing contact"`
+	Phone string `json:"phone,omitempty" jsonschema:"Contact phone number"`
+	Notes string `json:"notes,omitempty" jsonschema:"Notes for a contact or company"`
+
+	// Companies
+	Domain   string `json:"domain,omitempty" jsonschema:"Company domain (e.g., acme.com)"`
+	Industry string `json:"industry,omitempty" jsonschema:"Company industry"`
+
+	// Contacts and deals
+	CompanyName string `json:"company_name,omitempty" jsonschema:"Company for a contact or deal (required for deals, created if not found)"`
+
+	// Deals
+	Title             string `json:"title,omitempty" jsonschema:"Deal title (required for deals); matches an existing deal at the same company"`
+	Amount            int64  `json:"amount,omitempty" jsonschema:"Deal amount in cents"`
+	Currency          string `json:"currency,omitempty" jsonschema:"Deal currency code (default USD)"`
+	Stage             string `json:"stage,omitempty" jsonschema:"Deal stage"`
+	ContactName       string `json:"contact_name,omitempty" jsonschema:"Primary contact for a deal"`
+	ExpectedCloseDate string `json:"expected_close_date,omitempty" jsonschema:"Deal expected close date in ISO 8601 format"`
+	Source            string `json:"source,omitempty" jsonschema:"Deal source: referral, inbound, outbound, event, partner"`
+}
+
+type BulkUpsertInput struct {
+	Records []BulkRecord `json:"records" jsonschema:"Records to create or update, processed in order"`
+}
+
+// BulkRecordResult is the outcome of one record, in input order.
+type BulkRecordResult struct {
+	Index  int    `json:"index"`
+	Type   string `json:"type"`
+	Status string `json:"status"` // created, updated, unchanged, or failed
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkUpsertOutput struct {
+	Results   []BulkRecordResult `json:"results"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+}
+
+// BulkUpsert creates or updates each record. Contacts match on email (or exact
+// name without one), companies on name, and deals on title and company; a
+// matched record only has its non-empty fields applied. A failed record doesn't
+// stop the rest.
+func (h *BulkHandlers) BulkUpsert(ctx context.Context, request *mcp.CallToolRequest, input BulkUpsertInput) (*mcp.CallToolResult, BulkUpsertOutput, error) {
+	if len(input.Records) == 0 {
+		return nil, BulkUpsertOutput{}, fmt.Errorf("records is required")
+	}
+	if len(input.Records) > MaxBulkRecords {
+		return nil, BulkUpsertOutput{}, fmt.Errorf("too many records: %d (max %d per call)", len(input.Records), MaxBulkRecords)
+	}
+
+	output := BulkUpsertOutput{Results: make([]BulkRecordResult, 0, len(input.Records))}
+	for i, record := range input.Records {
+		result := BulkRecordResult{Index: i, Type: record.Type}
+
+		var err error
+		switch strings.ToLower(strings.TrimSpace(record.Type)) {
+		case "contact":
+			err = h.upsertContact(ctx, record, &result)
+		case "company":
+			err = h.upsertCompany(ctx, record, &result)
+		case "deal":
+			err = h.upsertDeal(ctx, record, &result)
+		default:
+			err = fmt.Errorf("invalid type: %q (valid: contact, company, deal)", record.Type)
+		}
+
+		if err != nil {
+			result.Status = BulkFailed
+			result.Error = err.Error()
+		}
+		switch result.Status {
+		case BulkCreated:
+			output.Created++
+		case BulkUpdated:
+			output.Updated++
+		case BulkUnchanged:
+			output.Unchanged++
+		case BulkFailed:
+			output.Failed++
+		}
+		output.Results = append(output.Results, result)
+	}
+
+	return nil, output, nil
+}
+
+func (h *BulkHandlers) upsertContact(ctx context.Context, record BulkRecord, result *BulkRecordResult) error {
+	if record.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	// add_contact matches on email; attendee lists often have only names
+	var existing *charm.Contact
+	if record.Email == "" {
+		var err error
+		if existing, err = h.client.FindContactByName(record.Name); err != nil {
+			return fmt.Errorf("failed to lookup contact: %w", err)
+		}
+	}
+	if existing == nil {
+		_, contact, err := h.contacts.AddContact(ctx, nil, AddContactInput{
+			Name:        record.Name,
+			Email:       record.Email,
+			Phone:       record.Phone,
+			CompanyName: record.CompanyName,
+			Notes:       record.Notes,
+		})
+		if err != nil {
+			return err
+		}
+		result.ID, result.Name = contact.ID, contact.Name
+		if !contact.Existing {
+			result.Status = BulkCreated
+			return nil
+		}
+		id, err := uuid.Parse(contact.ID)
+		if err != nil {
+			return fmt.Errorf("invalid contact id: %w", err)
+		}
+		if existing, err = h.client.GetContact(id); err != nil {
+			return fmt.Errorf("failed to get contact: %w", err)
+		}
+	}
+	result.ID, result.Name = existing.ID.String(), existing.Name
+
+	update := UpdateContactInput{ID: existing.ID.String()}
+	changed := false
+	if record.Phone != "" && record.Phone != existing.Phone {
+		update.Phone, changed = record.Phone, true
+	}
+	if record.Notes != "" && record.Notes != existing.Notes {
+		update.Notes, changed = record.Notes, true
+	}
+	if !changed {
+		result.Status = BulkUnchanged
+		return nil
+	}
+	if _, _, err := h.contacts.UpdateContact(ctx, nil, update); err != nil {
+		return err
+	}
+	result.Status = BulkUpdated
+	return nil
+}
+
+func (h *BulkHandlers) upsertCompany(ctx context.Context, record BulkRecord, result *BulkRecordResult) error {
+	if record.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	existing, err := h.client.FindCompanyByName(record.Name)
+	if err != nil {
+		return fmt.Errorf("failed to lookup company: %w", err)
+	}
+	if existing == nil {
+		_, company, err := h.companies.AddCompany(ctx, nil, AddCompanyInput{
+			Name:     record.Name,
+			Domain:   record.Domain,
+			Industry: record.Industry,
+			Notes:    record.Notes,
+		})
+		if err != nil {
+			return err
+		}
+		result.ID, result.Name, result.Status = company.ID, company.Name, BulkCreated
+		return nil
+	}
+	result.ID, result.Name = existing.ID.String(), existing.Name
+
+	update := UpdateCompanyInput{CompanyID: existing.ID.String()}
+	changed := false
+	if record.Domain != "" && record.Domain != existing.Domain {
+		update.Domain, changed = record.Domain, true
+	}
+	if record.Industry != "" && record.Industry != existing.Industry {
+		update.Industry, changed = record.Industry, true
+	}
+	if record.Notes != "" && record.Notes != existing.Notes {
+		update.Notes, changed = record.Notes, true
+	}
+	if !changed {
+		result.Status = BulkUnchanged
+		return nil
+	}
+	if _, _, err := h.companies.UpdateCompany(ctx, nil, update); err != nil {
+		return err
+	}
+	result.Status = BulkUpdated
+	return nil
+}
+
+func (h *BulkHandlers) upsertDeal(ctx context.Context, record BulkRecord, result *BulkRecordResult) error {
+	_, deal, err := h.deals.CreateDeal(ctx, nil, CreateDealInput{
+		Title:             record.Title,
+		Amount:            record.Amount,
+		Currency:          record.Currency,
+		Stage:             record.Stage,
+		CompanyName:       record.CompanyName,
+		ContactName:       record.ContactName,
+		ExpectedCloseDate: record.ExpectedCloseDate,
+		Source:            record.Source,
+	})
+	if err != nil {
+		return err
+	}
+	result.ID, result.Name = deal.ID, deal.Title
+	if !deal.Existing {
+		result.Status = BulkCreated
+		return nil
+	}
+
+	id, err := uuid.Parse(deal.ID)
+	if err != nil {
+		return fmt.Errorf("invalid deal id: %w", err)
+	}
+	existing, err := h.client.GetDeal(id)
+	if err != nil {
+		return fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	update := UpdateDealInput{ID: deal.ID}
+	changed := false
+	if record.Amount != 0 && record.Amount != existing.Amount {
+		amount := record.Amount
+		update.Amount, changed = &amount, true
+	}
+	if record.Currency != "" && record.Currency != existing.Currency {
+		update.Currency, changed = record.Currency, true
+	}
+	if record.Stage != "" && record.Stage != existing.Stage {
+		update.Stage, changed = record.Stage, true
+	}
+	if record.Source != "" && record.Source != existing.Source {
+		update.Source, changed = record.Source, true
+	}
+	if record.ExpectedCloseDate != "" {
+		closeDate, err := time.Parse(time.RFC3339, record.ExpectedCloseDate)
+		if err != nil {
+			return fmt.Errorf("invalid expected_close_date format (use ISO 8601/RFC3339): %w", err)
+		}
+		if existing.ExpectedCloseDate == nil || !existing.ExpectedCloseDate.Equal(closeDate) {
+			update.ExpectedCloseDate, changed = record.ExpectedCloseDate, true
+		}
+	}
+	if !changed {
+		result.Status = BulkUnchanged
+		return nil
+	}
+	if _, _, err := h.deals.UpdateDeal(ctx, nil, update); err != nil {
+		return err
+	}
+	result.Status = BulkUpdated
+	return nil
+}
@@ -0,0 +1,87 @@
+// ABOUTME: Tests for the bulk_upsert MCP tool
+// ABOUTME: Covers mixed record types, matching existing records, and per-record failures
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestBulkUpsert(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewBulkHandlers(client)
+	ctx := context.Background()
+
+	if err := client.CreateContact(&charm.Contact{Name: "Jane Doe", Email: "jane@example.com", Phone: "555-0000"}); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	_, out, err := handler.BulkUpsert(ctx, nil, BulkUpsertInput{Records: []BulkRecord{
+		{Type: "company", Name: "Acme Corp", Domain: "acme.com"},
+		{Type: "contact", Name: "Jane", Email: "JANE@example.com", Phone: "555-1234"},
+		{Type: "contact", Name: "Bob Smith", CompanyName: "Acme Corp"},
+		{Type: "deal", Title: "Pilot", CompanyName: "Acme Corp", Amount: 500000},
+		{Type: "deal", Title: "Pilot", CompanyName: "Acme Corp", Stage: "bogus"},
+		{Type: "person", Name: "Nobody"},
+		{Type: "contact", Email: "noname@example.com"},
+	}})
+	if err != nil {
+		t.Fatalf("BulkUpsert failed: %v", err)
+	}
+
+	wantStatus := []string{BulkCreated, BulkUpdated, BulkCreated, BulkCreated, BulkFailed, BulkFailed, BulkFailed}
+	for i, want := range wantStatus {
+		if got := out.Results[i]; got.Status != want || got.Index != i {
+			t.Errorf("record %d: expected %s, got %+v", i, want, got)
+		}
+	}
+	if out.Created != 3 || out.Updated != 1 || out.Failed != 3 {
+		t.Errorf("unexpected totals %+v", out)
+	}
+	if out.Results[1].Name != "Jane Doe" {
+		t.Errorf("expected the matched contact's name to be kept, got %q", out.Results[1].Name)
+	}
+
+	jane, err := client.FindContactByEmail("jane@example.com")
+	if err != nil || jane == nil || jane.Phone != "555-1234" {
+		t.Errorf("expected Jane's phone to be updated, got %+v, %v", jane, err)
+	}
+
+	// Importing the same table again changes nothing
+	_, again, err := handler.BulkUpsert(ctx, nil, BulkUpsertInput{Records: []BulkRecord{
+		{Type: "company", Name: "Acme Corp", Domain: "acme.com"},
+		{Type: "contact", Name: "Bob Smith", CompanyName: "Acme Corp"},
+		{Type: "deal", Title: "Pilot", CompanyName: "Acme Corp", Amount: 500000},
+		{Type: "deal", Title: "Pilot", CompanyName: "Acme Corp", Stage: charm.StageProposal},
+	}})
+	if err != nil {
+		t.Fatalf("BulkUpsert failed: %v", err)
+	}
+	if again.Unchanged != 3 || again.Updated != 1 || again.Created != 0 {
+		t.Errorf("expected a repeat import to match existing records, got %+v", again.Results)
+	}
+
+	contacts, _ := client.ListContacts(&charm.ContactFilter{})
+	deals, _ := client.ListDeals(&charm.DealFilter{})
+	companies, _ := client.ListCompanies(&charm.CompanyFilter{})
+	if len(contacts) != 2 || len(deals) != 1 || len(companies) != 1 {
+		t.Errorf("expected 2 contacts, 1 deal, 1 company; got %d, %d, %d", len(contacts), len(deals), len(companies))
+	}
+	if deals[0].Stage != charm.StageProposal {
+		t.Errorf("expected the deal stage to be updated, got %s", deals[0].Stage)
+	}
+}
+
+func TestBulkUpsertLimits(t *testing.T) {
+	handler := NewBulkHandlers(charm.NewTestClient(t))
+
+	if _, _, err := handler.BulkUpsert(context.Background(), nil, BulkUpsertInput{}); err == nil {
+		t.Error("expected empty records to fail")
+	}
+	records := make([]BulkRecord, MaxBulkRecords+1)
+	if _, _, err := handler.BulkUpsert(context.Background(), nil, BulkUpsertInput{Records: records}); err == nil {
+		t.Error("expected too many records to fail")
+	}
+}
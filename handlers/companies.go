@@ -20,20 +20,30 @@ func NewCompanyHandlers(client *charm.Client) *CompanyHandlers {
 }
 
 type AddCompanyInput struct {
-	Name     string `json:"name" jsonschema:"Company name (required)"`
-	Domain   string `json:"domain,omitempty" jsonschema:"Company domain (e.g., acme.com)"`
-	Industry string `json:"industry,omitempty" jsonschema:"Industry or sector"`
-	Notes    string `json:"notes,omitempty" jsonschema:"Additional notes about the company"`
+	Name         string `json:"name" jsonschema:"Company name (required)"`
+	Domain       string `json:"domain,omitempty" jsonschema:"Company domain (e.g., acme.com)"`
+	Industry     string `json:"industry,omitempty" jsonschema:"Industry or sector"`
+	Notes        string `json:"notes,omitempty" jsonschema:"Additional notes about the company"`
+	Employees    string `json:"employees,omitempty" jsonschema:"Employee count or range (e.g., 42 or 11-50)"`
+	FundingStage string `json:"funding_stage,omitempty" jsonschema:"Funding stage (e.g., seed, series_a)"`
+	HQLocation   string `json:"hq_location,omitempty" jsonschema:"Headquarters location"`
 }
 
 type CompanyOutput struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Domain    string `json:"domain,omitempty"`
-	Industry  string `json:"industry,omitempty"`
-	Notes     string `json:"notes,omitempty"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Domain             string   `json:"domain,omitempty"`
+	Industry           string   `json:"industry,omitempty"`
+	Notes              string   `json:"notes,omitempty"`
+	Employees          string   `json:"employees,omitempty"`
+	FundingStage       string   `json:"funding_stage,omitempty"`
+	HQLocation         string   `json:"hq_location,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	PrimaryContactID   string   `json:"primary_contact_id,omitempty"`
+	PrimaryContactName string   `json:"primary_contact_name,omitempty"`
+	CreatedAt          string   `json:"created_at,omitempty"`
+	UpdatedAt          string   `json:"updated_at,omitempty"`
+	Version            int64    `json:"version,omitempty"`
 }
 
 func (h *CompanyHandlers) AddCompany(_ context.Context, request *mcp.CallToolRequest, input AddCompanyInput) (*mcp.CallToolResult, CompanyOutput, error) {
@@ -42,10 +52,15 @@ func (h *CompanyHandlers) AddCompany(_ context.Context, request *mcp.CallToolReq
 	}
 
 	company := &charm.Company{
-		Name:     input.Name,
-		Domain:   input.Domain,
-		Industry: input.Industry,
-		Notes:    input.Notes,
+		Name:         input.Name,
+		Domain:       input.Domain,
+		Industry:     input.Industry,
+		Notes:        input.Notes,
+		FundingStage: charm.NormalizeFundingStage(input.FundingStage),
+		HQLocation:   input.HQLocation,
+	}
+	if err := company.SetEmployeeCount(input.Employees); err != nil {
+		return nil, CompanyOutput{}, err
 	}
 
 	if err := h.client.CreateCompany(company); err != nil {
@@ -56,12 +71,19 @@ func (h *CompanyHandlers) AddCompany(_ context.Context, request *mcp.CallToolReq
 }
 
 type FindCompaniesInput struct {
-	Query string `json:"query,omitempty" jsonschema:"Search query (searches name and domain)"`
-	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of results (default 10)"`
+	Query        string `json:"query,omitempty" jsonschema:"Search query (searches name and domain); field terms such as industry:saas employees:>50 narrow the results"`
+	FundingStage string `json:"funding_stage,omitempty" jsonschema:"Only companies at this funding stage (e.g., seed, series_a)"`
+	MinEmployees int    `json:"min_employees,omitempty" jsonschema:"Only companies with at least this many employees"`
+	MaxEmployees int    `json:"max_employees,omitempty" jsonschema:"Only companies with at most this many employees"`
+	HQ           string `json:"hq,omitempty" jsonschema:"Only companies headquartered here (substring of the location)"`
+	Tag          string `json:"tag,omitempty" jsonschema:"Only companies with this tag"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of results per page (default 10)"`
+	ListOptions
 }
 
 type FindCompaniesOutput struct {
 	Companies []CompanyOutput `json:"companies"`
+	Page
 }
 
 func (h *CompanyHandlers) FindCompanies(_ context.Context, request *mcp.CallToolRequest, input FindCompaniesInput) (*mcp.CallToolResult, FindCompaniesOutput, error) {
@@ -70,9 +92,18 @@ func (h *CompanyHandlers) FindCompanies(_ context.Context, request *mcp.CallTool
 		limit = 10
 	}
 
+	detail, err := input.detail()
+	if err != nil {
+		return nil, FindCompaniesOutput{}, err
+	}
+
 	filter := &charm.CompanyFilter{
-		Query: input.Query,
-		Limit: limit,
+		Query:        input.Query,
+		FundingStage: input.FundingStage,
+		MinEmployees: input.MinEmployees,
+		MaxEmployees: input.MaxEmployees,
+		HQ:           input.HQ,
+		Tag:          input.Tag,
 	}
 
 	companies, err := h.client.ListCompanies(filter)
@@ -82,22 +113,48 @@ func (h *CompanyHandlers) FindCompanies(_ context.Context, request *mcp.CallTool
 
 	result := make([]CompanyOutput, len(companies))
 	for i, company := range companies {
-		result[i] = companyToOutput(company)
+		result[i] = companyToOutput(company).withDetail(detail)
 	}
 
-	return nil, FindCompaniesOutput{Companies: result}, nil
+	result, page, err := paginate(result, input.ListOptions, limit)
+	if err != nil {
+		return nil, FindCompaniesOutput{}, err
+	}
+
+	return nil, FindCompaniesOutput{Companies: result, Page: page}, nil
 }
 
 func companyToOutput(company *charm.Company) CompanyOutput {
-	return CompanyOutput{
-		ID:        company.ID.String(),
-		Name:      company.Name,
-		Domain:    company.Domain,
-		Industry:  company.Industry,
-		Notes:     company.Notes,
-		CreatedAt: company.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: company.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	output := CompanyOutput{
+		ID:           company.ID.String(),
+		Name:         company.Name,
+		Domain:       company.Domain,
+		Industry:     company.Industry,
+		Notes:        company.Notes,
+		Employees:    company.Employees(),
+		FundingStage: company.FundingStage,
+		HQLocation:   company.HQLocation,
+		Tags:         company.Tags,
+		CreatedAt:    company.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:    company.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:      company.Version,
+	}
+	if company.PrimaryContactID != nil {
+		output.PrimaryContactID = company.PrimaryContactID.String()
+		output.PrimaryContactName = company.PrimaryContactName
 	}
+	return output
+}
+
+// withDetail trims a company to the requested detail level.
+func (o CompanyOutput) withDetail(detail string) CompanyOutput {
+	switch detail {
+	case DetailIDs:
+		return CompanyOutput{ID: o.ID, Name: o.Name}
+	case DetailSummary:
+		o.Notes, o.CreatedAt, o.UpdatedAt = "", "", ""
+	}
+	return o
 }
 
 // Legacy map-based functions for tests.
@@ -172,11 +229,17 @@ func companyToMap(company *charm.Company) map[string]interface{} {
 }
 
 type UpdateCompanyInput struct {
-	CompanyID string `json:"company_id" jsonschema:"UUID of the company to update"`
-	Name      string `json:"name,omitempty" jsonschema:"Updated company name"`
-	Domain    string `json:"domain,omitempty" jsonschema:"Updated domain"`
-	Industry  string `json:"industry,omitempty" jsonschema:"Updated industry"`
-	Notes     string `json:"notes,omitempty" jsonschema:"Updated notes"`
+	CompanyID           string `json:"company_id" jsonschema:"UUID of the company to update"`
+	Name                string `json:"name,omitempty" jsonschema:"Updated company name"`
+	Domain              string `json:"domain,omitempty" jsonschema:"Updated domain"`
+	Industry            string `json:"industry,omitempty" jsonschema:"Updated industry"`
+	Notes               string `json:"notes,omitempty" jsonschema:"Updated notes"`
+	Employees           string `json:"employees,omitempty" jsonschema:"Updated employee count or range (e.g., 42 or 11-50)"`
+	FundingStage        string `json:"funding_stage,omitempty" jsonschema:"Updated funding stage (e.g., seed, series_a)"`
+	HQLocation          string `json:"hq_location,omitempty" jsonschema:"Updated headquarters location"`
+	PrimaryContactID    string `json:"primary_contact_id,omitempty" jsonschema:"UUID of the contact to reach at the company by default"`
+	ClearPrimaryContact bool   `json:"clear_primary_contact,omitempty" jsonschema:"Remove the company's primary contact"`
+	Version             *int64 `json:"version,omitempty" jsonschema:"version of the company as you last read it; the update fails if it changed since (default: the current version)"`
 }
 
 func (h *CompanyHandlers) UpdateCompany(_ context.Context, request *mcp.CallToolRequest, input UpdateCompanyInput) (*mcp.CallToolResult, CompanyOutput, error) {
@@ -208,8 +271,36 @@ func (h *CompanyHandlers) UpdateCompany(_ context.Context, request *mcp.CallTool
 	if input.Notes != "" {
 		company.Notes = input.Notes
 	}
+	if input.Employees != "" {
+		if err := company.SetEmployeeCount(input.Employees); err != nil {
+			return nil, CompanyOutput{}, err
+		}
+	}
+	if input.FundingStage != "" {
+		company.FundingStage = charm.NormalizeFundingStage(input.FundingStage)
+	}
+	if input.HQLocation != "" {
+		company.HQLocation = input.HQLocation
+	}
+	if input.PrimaryContactID != "" {
+		contactID, err := uuid.Parse(input.PrimaryContactID)
+		if err != nil {
+			return nil, CompanyOutput{}, fmt.Errorf("invalid primary_contact_id: %w", err)
+		}
+		contact, err := h.client.GetContact(contactID)
+		if err != nil {
+			return nil, CompanyOutput{}, fmt.Errorf("primary contact not found: %w", err)
+		}
+		company.SetPrimaryContact(contact)
+	}
+	if input.ClearPrimaryContact {
+		company.SetPrimaryContact(nil)
+	}
 
-	err = h.client.UpdateCompany(company)
+	if input.Version != nil {
+		company.Version = *input.Version
+	}
+	err = h.client.UpdateCompanyIfUnchanged(company)
 	if err != nil {
 		return nil, CompanyOutput{}, fmt.Errorf("failed to update company: %w", err)
 	}
@@ -24,22 +24,51 @@ type AddContactInput struct {
 	Name        string `json:"name" jsonschema:"Contact name (required)"`
 	Email       string `json:"email,omitempty" jsonschema:"Contact email address"`
 	Phone       string `json:"phone,omitempty" jsonschema:"Contact phone number"`
+	Title       string `json:"title,omitempty" jsonschema:"Job title"`
 	CompanyName string `json:"company_name,omitempty" jsonschema:"Company name (will be looked up or created)"`
 	Notes       string `json:"notes,omitempty" jsonschema:"Additional notes about the contact"`
+	Birthday    string `json:"birthday,omitempty" jsonschema:"Birthday (YYYY-MM-DD, or MM-DD when the year is unknown)"`
+	WorkStart   string `json:"work_start_date,omitempty" jsonschema:"Date they started at their company (YYYY-MM-DD)"`
+	Country     string `json:"country,omitempty" jsonschema:"ISO 3166 country code, selects holiday greetings"`
+	MetVia      string `json:"met_via,omitempty" jsonschema:"How you met (e.g., Zoom, intro from Bob)"`
+	MetAt       string `json:"met_at,omitempty" jsonschema:"Event or place you met"`
+	MetDate     string `json:"met_date,omitempty" jsonschema:"Date you met (YYYY-MM-DD)"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty" jsonschema:"Unique key for this request; retrying with the same key returns the contact created the first time"`
+	OnDuplicate    string `json:"on_duplicate,omitempty" jsonschema:"When another contact has this email: return it (default), reject with an error naming it, or allow a duplicate"`
 }
 
 type ContactOutput struct {
-	ID              string  `json:"id"`
-	Name            string  `json:"name"`
-	Email           string  `json:"email,omitempty"`
-	Phone           string  `json:"phone,omitempty"`
-	CompanyID       *string `json:"company_id,omitempty"`
-	Notes           string  `json:"notes,omitempty"`
-	LastContactedAt *string `json:"last_contacted_at,omitempty"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Email           string   `json:"email,omitempty"`
+	Phone           string   `json:"phone,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	CompanyID       *string  `json:"company_id,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+	Pinned          []string `json:"pinned,omitempty"`
+	LastContactedAt *string  `json:"last_contacted_at,omitempty"`
+	Birthday        string   `json:"birthday,omitempty"`
+	WorkStartDate   *string  `json:"work_start_date,omitempty"`
+	Country         string   `json:"country,omitempty"`
+	MetVia          string   `json:"met_via,omitempty"`
+	MetAt           string   `json:"met_at,omitempty"`
+	MetDate         *string  `json:"met_date,omitempty"`
+	Seniority       string   `json:"seniority,omitempty"`
+	Function        string   `json:"function,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	CreatedAt       string   `json:"created_at,omitempty"`
+	UpdatedAt       string   `json:"updated_at,omitempty"`
+	Version         int64    `json:"version,omitempty"`
+
+	// Existing is set when add_contact returned a matching contact instead of creating one
+	Existing bool `json:"existing,omitempty"`
 }
 
+// AddContact creates a contact. Calls are safe to repeat: a contact with the same
+// email, or the contact created by an earlier call with the same idempotency key,
+// is returned unchanged instead of creating a duplicate. The duplicate_emails
+// config or on_duplicate can reject or allow a taken email instead.
 func (h *ContactHandlers) AddContact(_ context.Context, request *mcp.CallToolRequest, input AddContactInput) (*mcp.CallToolResult, ContactOutput, error) {
 	if input.Name == "" {
 		return nil, ContactOutput{}, fmt.Errorf("name is required")
@@ -49,8 +78,40 @@ func (h *ContactHandlers) AddContact(_ context.Context, request *mcp.CallToolReq
 		Name:  input.Name,
 		Email: input.Email,
 		Phone: input.Phone,
+		Title: input.Title,
 		Notes: input.Notes,
 	}
+	if err := contact.SetGreetingFields(input.Birthday, input.WorkStart, input.Country); err != nil {
+		return nil, ContactOutput{}, err
+	}
+	if err := contact.SetMetFields(input.MetVia, input.MetAt, input.MetDate); err != nil {
+		return nil, ContactOutput{}, err
+	}
+
+	if input.IdempotencyKey != "" {
+		record, err := h.client.LookupIdempotencyKey("add_contact", input.IdempotencyKey)
+		if err != nil {
+			return nil, ContactOutput{}, fmt.Errorf("failed to check idempotency key: %w", err)
+		}
+		if record != nil {
+			if existing, err := h.client.GetContact(record.ObjectID); err == nil {
+				return nil, existingContactOutput(existing), nil
+			}
+		}
+	}
+
+	// Email is the contact's natural key
+	policy := input.OnDuplicate
+	if policy == "" {
+		policy = h.client.DuplicateEmailPolicy(charm.EntryMCP)
+	}
+	existing, err := h.client.CheckDuplicateEmail(input.Email, policy)
+	if err != nil {
+		return nil, ContactOutput{}, err
+	}
+	if existing != nil {
+		return nil, existingContactOutput(existing), nil
+	}
 
 	// Handle company lookup/creation if company_name provided
 	if input.CompanyName != "" {
@@ -73,6 +134,14 @@ func (h *ContactHandlers) AddContact(_ context.Context, request *mcp.CallToolReq
 		contact.CompanyName = company.Name
 	}
 
+	// Record the key before creating, so a retry after a failed create tries again
+	if input.IdempotencyKey != "" {
+		contact.ID = uuid.New()
+		if err := h.client.SaveIdempotencyKey("add_contact", input.IdempotencyKey, contact.ID); err != nil {
+			return nil, ContactOutput{}, fmt.Errorf("failed to save idempotency key: %w", err)
+		}
+	}
+
 	if err := h.client.CreateContact(contact); err != nil {
 		return nil, ContactOutput{}, fmt.Errorf("failed to create contact: %w", err)
 	}
@@ -80,14 +149,36 @@ func (h *ContactHandlers) AddContact(_ context.Context, request *mcp.CallToolReq
 	return nil, contactToOutput(contact), nil
 }
 
+// withDetail trims a contact to the requested detail level.
+func (o ContactOutput) withDetail(detail string) ContactOutput {
+	switch detail {
+	case DetailIDs:
+		return ContactOutput{ID: o.ID, Name: o.Name}
+	case DetailSummary:
+		o.Notes, o.CreatedAt, o.UpdatedAt = "", "", ""
+	}
+	return o
+}
+
+func existingContactOutput(contact *charm.Contact) ContactOutput {
+	output := contactToOutput(contact)
+	output.Existing = true
+	return output
+}
+
 type FindContactsInput struct {
-	Query     string `json:"query,omitempty" jsonschema:"Search query (searches name and email)"`
+	Query     string `json:"query,omitempty" jsonschema:"Search query (searches name and email); field terms such as company:acme tag:vip last_contacted:<90d narrow the results"`
 	CompanyID string `json:"company_id,omitempty" jsonschema:"Filter by company ID"`
-	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of results (default 10)"`
+	MetIn     string `json:"met_in,omitempty" jsonschema:"Only contacts met in this year, month, or day (YYYY, YYYY-MM, or YYYY-MM-DD)"`
+	City      string `json:"city,omitempty" jsonschema:"Only contacts met in person in this city (from meeting locations)"`
+	Tag       string `json:"tag,omitempty" jsonschema:"Only contacts with this tag, e.g. ai or fundraising"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum number of results per page (default 10)"`
+	ListOptions
 }
 
 type FindContactsOutput struct {
 	Contacts []ContactOutput `json:"contacts"`
+	Page
 }
 
 func (h *ContactHandlers) FindContacts(_ context.Context, request *mcp.CallToolRequest, input FindContactsInput) (*mcp.CallToolResult, FindContactsOutput, error) {
@@ -96,6 +187,11 @@ func (h *ContactHandlers) FindContacts(_ context.Context, request *mcp.CallToolR
 		limit = 10
 	}
 
+	detail, err := input.detail()
+	if err != nil {
+		return nil, FindContactsOutput{}, err
+	}
+
 	var companyID *uuid.UUID
 	if input.CompanyID != "" {
 		cid, err := uuid.Parse(input.CompanyID)
@@ -108,7 +204,15 @@ func (h *ContactHandlers) FindContacts(_ context.Context, request *mcp.CallToolR
 	filter := &charm.ContactFilter{
 		Query:     input.Query,
 		CompanyID: companyID,
-		Limit:     limit,
+		City:      input.City,
+		Tag:       input.Tag,
+	}
+	if input.MetIn != "" {
+		from, to, err := charm.ParseMetPeriod(input.MetIn)
+		if err != nil {
+			return nil, FindContactsOutput{}, fmt.Errorf("invalid met_in: %w", err)
+		}
+		filter.MetFrom, filter.MetTo = &from, &to
 	}
 
 	contacts, err := h.client.ListContacts(filter)
@@ -118,10 +222,15 @@ func (h *ContactHandlers) FindContacts(_ context.Context, request *mcp.CallToolR
 
 	result := make([]ContactOutput, len(contacts))
 	for i, contact := range contacts {
-		result[i] = contactToOutput(contact)
+		result[i] = contactToOutput(contact).withDetail(detail)
+	}
+
+	result, page, err := paginate(result, input.ListOptions, limit)
+	if err != nil {
+		return nil, FindContactsOutput{}, err
 	}
 
-	return nil, FindContactsOutput{Contacts: result}, nil
+	return nil, FindContactsOutput{Contacts: result, Page: page}, nil
 }
 
 type UpdateContactInput struct {
@@ -129,7 +238,18 @@ type UpdateContactInput struct {
 	Name  string `json:"name,omitempty" jsonschema:"Updated contact name"`
 	Email string `json:"email,omitempty" jsonschema:"Updated email address"`
 	Phone string `json:"phone,omitempty" jsonschema:"Updated phone number"`
+	Title string `json:"title,omitempty" jsonschema:"Updated job title"`
 	Notes string `json:"notes,omitempty" jsonschema:"Updated notes"`
+
+	Birthday  string `json:"birthday,omitempty" jsonschema:"Birthday (YYYY-MM-DD, or MM-DD when the year is unknown)"`
+	WorkStart string `json:"work_start_date,omitempty" jsonschema:"Date they started at their company (YYYY-MM-DD)"`
+	Country   string `json:"country,omitempty" jsonschema:"ISO 3166 country code, selects holiday greetings"`
+
+	MetVia  string `json:"met_via,omitempty" jsonschema:"How you met (e.g., Zoom, intro from Bob)"`
+	MetAt   string `json:"met_at,omitempty" jsonschema:"Event or place you met"`
+	MetDate string `json:"met_date,omitempty" jsonschema:"Date you met (YYYY-MM-DD)"`
+
+	Version *int64 `json:"version,omitempty" jsonschema:"version of the contact as you last read it; the update fails if it changed since (default: the current version)"`
 }
 
 func (h *ContactHandlers) UpdateContact(_ context.Context, request *mcp.CallToolRequest, input UpdateContactInput) (*mcp.CallToolResult, ContactOutput, error) {
@@ -157,11 +277,23 @@ func (h *ContactHandlers) UpdateContact(_ context.Context, request *mcp.CallTool
 	if input.Phone != "" {
 		contact.Phone = input.Phone
 	}
+	if input.Title != "" {
+		contact.Title = input.Title
+	}
 	if input.Notes != "" {
 		contact.Notes = input.Notes
 	}
+	if err := contact.SetGreetingFields(input.Birthday, input.WorkStart, input.Country); err != nil {
+		return nil, ContactOutput{}, err
+	}
+	if err := contact.SetMetFields(input.MetVia, input.MetAt, input.MetDate); err != nil {
+		return nil, ContactOutput{}, err
+	}
 
-	if err := h.client.UpdateContact(contact); err != nil {
+	if input.Version != nil {
+		contact.Version = *input.Version
+	}
+	if err := h.client.UpdateContactIfUnchanged(contact); err != nil {
 		return nil, ContactOutput{}, fmt.Errorf("failed to update contact: %w", err)
 	}
 
@@ -220,6 +352,43 @@ func (h *ContactHandlers) LogContactInteraction(_ context.Context, request *mcp.
 	return nil, contactToOutput(contact), nil
 }
 
+type PinContactFactInput struct {
+	ContactID string `json:"contact_id" jsonschema:"Contact ID (required)"`
+	Fact      string `json:"fact" jsonschema:"Key fact to keep at the top of summaries and meeting briefs, e.g. kids: 2, likes cycling (required)"`
+}
+
+// PinContactFact pins a key fact on a contact. Pinning a fact again is a no-op.
+func (h *ContactHandlers) PinContactFact(_ context.Context, request *mcp.CallToolRequest, input PinContactFactInput) (*mcp.CallToolResult, ContactOutput, error) {
+	contactID, err := uuid.Parse(input.ContactID)
+	if err != nil {
+		return nil, ContactOutput{}, fmt.Errorf("invalid contact_id: %w", err)
+	}
+
+	contact, err := h.client.PinContactFact(contactID, input.Fact)
+	if err != nil {
+		return nil, ContactOutput{}, err
+	}
+	return nil, contactToOutput(contact), nil
+}
+
+type UnpinContactFactInput struct {
+	ContactID string `json:"contact_id" jsonschema:"Contact ID (required)"`
+	Fact      string `json:"fact" jsonschema:"Pinned fact to remove, by its text or its position in pinned starting at 1 (required)"`
+}
+
+func (h *ContactHandlers) UnpinContactFact(_ context.Context, request *mcp.CallToolRequest, input UnpinContactFactInput) (*mcp.CallToolResult, ContactOutput, error) {
+	contactID, err := uuid.Parse(input.ContactID)
+	if err != nil {
+		return nil, ContactOutput{}, fmt.Errorf("invalid contact_id: %w", err)
+	}
+
+	contact, err := h.client.UnpinContactFact(contactID, input.Fact)
+	if err != nil {
+		return nil, ContactOutput{}, err
+	}
+	return nil, contactToOutput(contact), nil
+}
+
 type DeleteContactInput struct {
 	ID string `json:"id" jsonschema:"Contact ID (required)"`
 }
@@ -255,9 +424,19 @@ func contactToOutput(contact *charm.Contact) ContactOutput {
 		Name:      contact.Name,
 		Email:     contact.Email,
 		Phone:     contact.Phone,
+		Title:     contact.Title,
 		Notes:     contact.Notes,
+		Pinned:    contact.Pinned,
+		Birthday:  contact.Birthday,
+		Country:   contact.Country,
+		MetVia:    contact.MetVia,
+		MetAt:     contact.MetAt,
+		Seniority: contact.Seniority,
+		Function:  contact.Function,
+		Tags:      contact.Tags,
 		CreatedAt: contact.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt: contact.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   contact.Version,
 	}
 
 	if contact.CompanyID != nil {
@@ -270,6 +449,16 @@ func contactToOutput(contact *charm.Contact) ContactOutput {
 		output.LastContactedAt = &lca
 	}
 
+	if contact.WorkStartDate != nil {
+		wsd := contact.WorkStartDate.Format("2006-01-02")
+		output.WorkStartDate = &wsd
+	}
+
+	if contact.MetDate != nil {
+		md := contact.MetDate.Format("2006-01-02")
+		output.MetDate = &md
+	}
+
 	return output
 }
 
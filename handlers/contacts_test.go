@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -269,6 +270,31 @@ func TestUpdateContactHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateContactConflict(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewContactHandlers(client)
+
+	contact := &charm.Contact{Name: "Ada"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	// A version from before the last save means another edit came in since the read
+	read := contact.Version
+	if err := client.UpdateContact(contact); err != nil {
+		t.Fatalf("UpdateContact failed: %v", err)
+	}
+	_, _, err := handler.UpdateContact(context.Background(), nil, UpdateContactInput{ID: contact.ID.String(), Title: "CEO", Version: &read})
+	if charm.CodeOf(err) != charm.CodeConflict {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	_, output, err := handler.UpdateContact(context.Background(), nil, UpdateContactInput{ID: contact.ID.String(), Title: "CEO", Version: &contact.Version})
+	if err != nil || output.Title != "CEO" || output.Version != contact.Version+1 {
+		t.Errorf("expected an update of the current version to succeed, got %+v, %v", output, err)
+	}
+}
+
 func TestUpdateContactNotFound(t *testing.T) {
 	client := charm.NewTestClient(t)
 
@@ -386,3 +412,63 @@ func TestLogContactInteractionNotFound(t *testing.T) {
 		t.Error("Expected error for non-existent contact")
 	}
 }
+
+func TestAddContactIdempotent(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewContactHandlers(client)
+	ctx := context.Background()
+
+	_, first, err := handler.AddContact(ctx, nil, AddContactInput{Name: "Jane Doe", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	if first.Existing {
+		t.Error("expected the first call to create the contact")
+	}
+
+	// Same email, different case: no duplicate and no new company
+	_, again, err := handler.AddContact(ctx, nil, AddContactInput{Name: "Jane", Email: "JANE@example.com", CompanyName: "Acme Corp"})
+	if err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	if again.ID != first.ID || !again.Existing || again.Name != "Jane Doe" {
+		t.Errorf("expected the existing contact unchanged, got %+v", again)
+	}
+	if company, _ := client.FindCompanyByName("Acme Corp"); company != nil {
+		t.Error("expected no company to be created for a duplicate")
+	}
+
+	// Without an email, the idempotency key dedupes retries
+	_, keyed, err := handler.AddContact(ctx, nil, AddContactInput{Name: "Bob", IdempotencyKey: "call-42"})
+	if err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	_, retried, err := handler.AddContact(ctx, nil, AddContactInput{Name: "Bob", IdempotencyKey: "call-42"})
+	if err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	if retried.ID != keyed.ID || !retried.Existing {
+		t.Errorf("expected the keyed contact, got %+v", retried)
+	}
+
+	// A key whose contact was deleted creates a new one
+	id, _ := uuid.Parse(keyed.ID)
+	if err := client.DeleteContact(id); err != nil {
+		t.Fatalf("DeleteContact failed: %v", err)
+	}
+	_, recreated, err := handler.AddContact(ctx, nil, AddContactInput{Name: "Bob", IdempotencyKey: "call-42"})
+	if err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	if recreated.Existing || recreated.ID == keyed.ID {
+		t.Errorf("expected a new contact, got %+v", recreated)
+	}
+
+	contacts, err := client.ListContacts(&charm.ContactFilter{})
+	if err != nil {
+		t.Fatalf("ListContacts failed: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Errorf("expected 2 contacts, got %d", len(contacts))
+	}
+}
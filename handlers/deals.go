@@ -4,7 +4,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,26 +27,74 @@ type CreateDealInput struct {
 	Amount            int64  `json:"amount,omitempty" jsonschema:"Deal amount in cents"`
 	Currency          string `json:"currency,omitempty" jsonschema:"Currency code (default USD)"`
 	Stage             string `json:"stage,omitempty" jsonschema:"Deal stage: prospecting, qualification, proposal, negotiation, closed_won, closed_lost"`
-	CompanyName       string `json:"company_name" jsonschema:"Company name (required, will be created if not found)"`
-	ContactName       string `json:"contact_name,omitempty" jsonschema:"Contact name (optional)"`
+	CompanyName       string `json:"company_name" jsonschema:"Company name or ID (required, will be created if not found)"`
+	ContactName       string `json:"contact_name,omitempty" jsonschema:"Contact name or ID (optional)"`
 	ExpectedCloseDate string `json:"expected_close_date,omitempty" jsonschema:"Expected close date in ISO 8601 format"`
+	CloseReason       string `json:"close_reason,omitempty" jsonschema:"Why the deal was won or lost"`
+	Source            string `json:"source,omitempty" jsonschema:"Where the deal came from: referral, inbound, outbound, event, partner"`
+	Referrer          string `json:"referrer,omitempty" jsonschema:"ID or name of the contact who referred the deal"`
+	Probability       *int   `json:"probability,omitempty" jsonschema:"Win probability override (0-100); defaults to the stage probability"`
 	InitialNote       string `json:"initial_note,omitempty" jsonschema:"Initial note for the deal"`
+	IdempotencyKey    string `json:"idempotency_key,omitempty" jsonschema:"Unique key for this request; retrying with the same key returns the deal created the first time"`
+	Strict            *bool  `json:"strict,omitempty" jsonschema:"Fail with a disambiguation listing the candidates instead of guessing when a name matches several companies or contacts (default: the strict_resolution config)"`
+}
+
+// DealContactOutput is a contact's role on a deal.
+type DealContactOutput struct {
+	ContactID   string `json:"contact_id"`
+	ContactName string `json:"contact_name"`
+	Role        string `json:"role"`
 }
 
 type DealOutput struct {
-	ID                string  `json:"id"`
-	Title             string  `json:"title"`
-	Amount            int64   `json:"amount,omitempty"`
-	Currency          string  `json:"currency"`
-	Stage             string  `json:"stage"`
-	CompanyID         string  `json:"company_id"`
-	ContactID         *string `json:"contact_id,omitempty"`
-	ExpectedCloseDate *string `json:"expected_close_date,omitempty"`
-	CreatedAt         string  `json:"created_at"`
-	UpdatedAt         string  `json:"updated_at"`
-	LastActivityAt    string  `json:"last_activity_at"`
+	ID                string              `json:"id"`
+	Title             string              `json:"title"`
+	Amount            int64               `json:"amount,omitempty"`
+	Currency          string              `json:"currency"`
+	AmountFormatted   string              `json:"amount_formatted,omitempty"`
+	Stage             string              `json:"stage"`
+	CompanyID         string              `json:"company_id"`
+	ContactID         *string             `json:"contact_id,omitempty"`
+	ExpectedCloseDate *string             `json:"expected_close_date,omitempty"`
+	CloseReason       string              `json:"close_reason,omitempty"`
+	Source            string              `json:"source,omitempty"`
+	ReferrerID        *string             `json:"referrer_id,omitempty"`
+	ReferrerName      string              `json:"referrer_name,omitempty"`
+	Probability       int                 `json:"probability"`
+	ProbabilitySet    bool                `json:"probability_override,omitempty"`
+	ExpectedValue     int64               `json:"expected_value"`
+	Contacts          []DealContactOutput `json:"contacts,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	StageHistory      []charm.StageChange `json:"stage_history,omitempty"`
+	CreatedAt         string              `json:"created_at,omitempty"`
+	UpdatedAt         string              `json:"updated_at,omitempty"`
+	Version           int64               `json:"version,omitempty"`
+	LastActivityAt    string              `json:"last_activity_at"`
+
+	// Existing is set when create_deal returned a matching deal instead of creating one
+	Existing bool `json:"existing,omitempty"`
+
+	// Disambiguation is set when a strict create_deal couldn't tell which record a name meant
+	Disambiguation *DisambiguationOutput `json:"disambiguation,omitempty"`
+}
+
+// DisambiguationOutput lists the records an ambiguous name could refer to.
+type DisambiguationOutput struct {
+	Field      string            `json:"field"`
+	Query      string            `json:"query"`
+	Candidates []CandidateOutput `json:"candidates"`
+}
+
+// CandidateOutput is one record an ambiguous name could refer to.
+type CandidateOutput struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
 }
 
+// CreateDeal creates a deal. Calls are safe to repeat: a deal with the same title
+// at the same company, or the deal created by an earlier call with the same
+// idempotency key, is returned unchanged instead of creating a duplicate.
 func (h *DealHandlers) CreateDeal(_ context.Context, request *mcp.CallToolRequest, input CreateDealInput) (*mcp.CallToolResult, DealOutput, error) {
 	if input.Title == "" {
 		return nil, DealOutput{}, fmt.Errorf("title is required")
@@ -69,13 +119,99 @@ func (h *DealHandlers) CreateDeal(_ context.Context, request *mcp.CallToolReques
 		return nil, DealOutput{}, fmt.Errorf("invalid stage: %s (valid: prospecting, qualification, proposal, negotiation, closed_won, closed_lost)", stage)
 	}
 
+	// Parse expected close date if provided
+	var expectedClose *time.Time
+	if input.ExpectedCloseDate != "" {
+		parsedTime, err := time.Parse(time.RFC3339, input.ExpectedCloseDate)
+		if err != nil {
+			return nil, DealOutput{}, fmt.Errorf("invalid expected_close_date format (use ISO 8601/RFC3339): %w", err)
+		}
+		expectedClose = &parsedTime
+	}
+
+	if input.Source != "" && !charm.IsValidDealSource(input.Source) {
+		return nil, DealOutput{}, fmt.Errorf("invalid source: %s (valid: %s)", input.Source, strings.Join(charm.DealSources, ", "))
+	}
+
+	if input.IdempotencyKey != "" {
+		record, err := h.client.LookupIdempotencyKey("create_deal", input.IdempotencyKey)
+		if err != nil {
+			return nil, DealOutput{}, fmt.Errorf("failed to check idempotency key: %w", err)
+		}
+		if record != nil {
+			if existing, err := h.client.GetDeal(record.ObjectID); err == nil {
+				return nil, h.existingDealOutput(existing), nil
+			}
+		}
+	}
+
+	strict := input.Strict != nil && *input.Strict
+	if input.Strict == nil {
+		if cfg := h.client.Config(); cfg != nil {
+			strict = cfg.StrictResolution
+		}
+	}
+
+	// Resolve the referrer before creating any company
+	var referrer *charm.Contact
+	if input.Referrer != "" {
+		var err error
+		if strict {
+			referrer, err = resolveContactStrict(h.client, input.Referrer)
+			if err == nil && referrer == nil {
+				err = fmt.Errorf("no contact found matching: %s", input.Referrer)
+			}
+		} else {
+			referrer, err = resolveContact(h.client, input.Referrer)
+		}
+		if err != nil {
+			return disambiguation("referrer", err)
+		}
+	}
+
+	// Check stage rules before creating any company
+	candidate := &charm.Deal{Stage: stage, Amount: input.Amount, ExpectedCloseDate: expectedClose, CloseReason: input.CloseReason}
+	if input.ContactName != "" {
+		candidate.ContactID = &uuid.Nil
+	}
+	if err := charm.CheckStageRequirements(candidate, h.client.StageRequirements()); err != nil {
+		return nil, DealOutput{}, err
+	}
+
 	// Handle company lookup/creation (required)
-	company, err := h.client.FindCompanyByName(input.CompanyName)
-	if err != nil {
-		return nil, DealOutput{}, fmt.Errorf("failed to lookup company: %w", err)
+	var company *charm.Company
+	var err error
+	if id, parseErr := uuid.Parse(input.CompanyName); parseErr == nil {
+		if company, err = h.client.GetCompany(id); err != nil {
+			return nil, DealOutput{}, fmt.Errorf("company not found: %w", err)
+		}
+	} else if company, err = h.client.ResolveCompanyName(input.CompanyName, strict); err != nil {
+		return disambiguation("company_name", err)
 	}
 
-	if company == nil {
+	// Resolve the contact before creating any company
+	var contact *charm.Contact
+	if input.ContactName != "" {
+		if strict {
+			contact, err = resolveContactStrict(h.client, input.ContactName)
+		} else {
+			contact, err = h.client.ResolveContactName(input.ContactName, false)
+		}
+		if err != nil {
+			return disambiguation("contact_name", err)
+		}
+	}
+
+	if company != nil {
+		// Title and company are the deal's natural key
+		existing, err := h.client.FindDealByTitle(company.ID, input.Title)
+		if err != nil {
+			return nil, DealOutput{}, fmt.Errorf("failed to lookup deal: %w", err)
+		}
+		if existing != nil {
+			return nil, h.existingDealOutput(existing), nil
+		}
+	} else {
 		// Create new company
 		company = &charm.Company{
 			Name: input.CompanyName,
@@ -92,32 +228,28 @@ func (h *DealHandlers) CreateDeal(_ context.Context, request *mcp.CallToolReques
 		Stage:       stage,
 		CompanyID:   company.ID,
 		CompanyName: company.Name,
+		CloseReason: input.CloseReason,
+		Source:      input.Source,
+		Probability: input.Probability,
+	}
+	if referrer != nil {
+		deal.ReferrerID = &referrer.ID
+		deal.ReferrerName = referrer.Name
 	}
 
-	// Handle contact lookup if provided (optional)
-	if input.ContactName != "" {
-		filter := &charm.ContactFilter{
-			Query: input.ContactName,
-			Limit: 1,
-		}
-		contacts, err := h.client.ListContacts(filter)
-		if err != nil {
-			return nil, DealOutput{}, fmt.Errorf("failed to lookup contact: %w", err)
-		}
-
-		if len(contacts) > 0 {
-			deal.ContactID = &contacts[0].ID
-			deal.ContactName = contacts[0].Name
-		}
+	if contact != nil {
+		deal.ContactID = &contact.ID
+		deal.ContactName = contact.Name
 	}
 
-	// Parse expected close date if provided
-	if input.ExpectedCloseDate != "" {
-		parsedTime, err := time.Parse(time.RFC3339, input.ExpectedCloseDate)
-		if err != nil {
-			return nil, DealOutput{}, fmt.Errorf("invalid expected_close_date format (use ISO 8601/RFC3339): %w", err)
+	deal.ExpectedCloseDate = expectedClose
+
+	// Record the key before creating, so a retry after a failed create tries again
+	if input.IdempotencyKey != "" {
+		deal.ID = uuid.New()
+		if err := h.client.SaveIdempotencyKey("create_deal", input.IdempotencyKey, deal.ID); err != nil {
+			return nil, DealOutput{}, fmt.Errorf("failed to save idempotency key: %w", err)
 		}
-		deal.ExpectedCloseDate = &parsedTime
 	}
 
 	if err := h.client.CreateDeal(deal); err != nil {
@@ -143,7 +275,27 @@ func (h *DealHandlers) CreateDeal(_ context.Context, request *mcp.CallToolReques
 		}
 	}
 
-	return nil, dealToOutput(deal), nil
+	return nil, dealOutputWithContacts(h.client, deal), nil
+}
+
+// dealDetail returns a deal at the requested detail level. Deal outputs always
+// carry their stage and amounts, so ids returns a reference instead.
+func dealDetail(client *charm.Client, deal *charm.Deal, detail string) interface{} {
+	switch detail {
+	case DetailIDs:
+		return EntityRef{ID: deal.ID.String(), Name: deal.Title}
+	case DetailSummary:
+		output := dealToOutput(deal)
+		output.CreatedAt, output.UpdatedAt = "", ""
+		return output
+	}
+	return dealOutputWithContacts(client, deal)
+}
+
+func (h *DealHandlers) existingDealOutput(deal *charm.Deal) DealOutput {
+	output := dealOutputWithContacts(h.client, deal)
+	output.Existing = true
+	return output
 }
 
 type UpdateDealInput struct {
@@ -153,6 +305,11 @@ type UpdateDealInput struct {
 	Currency          string `json:"currency,omitempty" jsonschema:"Updated currency code"`
 	Stage             string `json:"stage,omitempty" jsonschema:"Updated deal stage"`
 	ExpectedCloseDate string `json:"expected_close_date,omitempty" jsonschema:"Updated expected close date in ISO 8601 format"`
+	CloseReason       string `json:"close_reason,omitempty" jsonschema:"Why the deal was won or lost"`
+	Source            string `json:"source,omitempty" jsonschema:"Where the deal came from: referral, inbound, outbound, event, partner"`
+	Referrer          string `json:"referrer,omitempty" jsonschema:"ID or name of the contact who referred the deal"`
+	Probability       *int   `json:"probability,omitempty" jsonschema:"Win probability override (0-100), or -1 to use the stage default"`
+	Version           *int64 `json:"version,omitempty" jsonschema:"version of the deal as you last read it; the update fails if it changed since (default: the current version)"`
 }
 
 func (h *DealHandlers) UpdateDeal(_ context.Context, request *mcp.CallToolRequest, input UpdateDealInput) (*mcp.CallToolResult, DealOutput, error) {
@@ -193,12 +350,36 @@ func (h *DealHandlers) UpdateDeal(_ context.Context, request *mcp.CallToolReques
 		}
 		deal.ExpectedCloseDate = &parsedTime
 	}
+	if input.CloseReason != "" {
+		deal.CloseReason = input.CloseReason
+	}
+	if input.Source != "" {
+		deal.Source = input.Source
+	}
+	if input.Referrer != "" {
+		referrer, err := resolveContact(h.client, input.Referrer)
+		if err != nil {
+			return nil, DealOutput{}, err
+		}
+		deal.ReferrerID = &referrer.ID
+		deal.ReferrerName = referrer.Name
+	}
+	if input.Probability != nil {
+		if *input.Probability < 0 {
+			deal.Probability = nil
+		} else {
+			deal.Probability = input.Probability
+		}
+	}
 
-	if err := h.client.UpdateDeal(deal); err != nil {
+	if input.Version != nil {
+		deal.Version = *input.Version
+	}
+	if err := h.client.UpdateDealIfUnchanged(deal); err != nil {
 		return nil, DealOutput{}, fmt.Errorf("failed to update deal: %w", err)
 	}
 
-	return nil, dealToOutput(deal), nil
+	return nil, dealOutputWithContacts(h.client, deal), nil
 }
 
 type AddDealNoteInput struct {
@@ -254,11 +435,24 @@ func dealToOutput(deal *charm.Deal) DealOutput {
 		Currency:       deal.Currency,
 		Stage:          deal.Stage,
 		CompanyID:      deal.CompanyID.String(),
+		CloseReason:    deal.CloseReason,
+		Source:         deal.Source,
+		ReferrerName:   deal.ReferrerName,
+		Probability:    deal.WinProbability(),
+		ProbabilitySet: deal.Probability != nil,
+		ExpectedValue:  deal.ExpectedValue(),
+		Tags:           deal.Tags,
+		StageHistory:   deal.StageHistory,
 		CreatedAt:      deal.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:      deal.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:        deal.Version,
 		LastActivityAt: deal.LastActivityAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
+	if deal.Amount != 0 {
+		output.AmountFormatted = charm.FormatMoney(deal.Amount, deal.Currency)
+	}
+
 	if deal.ContactID != nil {
 		cid := deal.ContactID.String()
 		output.ContactID = &cid
@@ -269,9 +463,177 @@ func dealToOutput(deal *charm.Deal) DealOutput {
 		output.ExpectedCloseDate = &ecd
 	}
 
+	if deal.ReferrerID != nil {
+		rid := deal.ReferrerID.String()
+		output.ReferrerID = &rid
+	}
+
 	return output
 }
 
+// dealOutputWithContacts converts a deal and includes its contacts with roles.
+func dealOutputWithContacts(client *charm.Client, deal *charm.Deal) DealOutput {
+	output := dealToOutput(deal)
+
+	roles, err := client.ListDealContacts(deal.ID)
+	if err != nil {
+		return output
+	}
+	for _, role := range roles {
+		output.Contacts = append(output.Contacts, DealContactOutput{
+			ContactID:   role.ContactID.String(),
+			ContactName: role.ContactName,
+			Role:        role.Role,
+		})
+	}
+	return output
+}
+
+type AddDealContactInput struct {
+	DealID  string `json:"deal_id" jsonschema:"Deal ID (required)"`
+	Contact string `json:"contact" jsonschema:"Contact ID or name (required)"`
+	Role    string `json:"role" jsonschema:"Role on the deal: decision_maker, champion, influencer, procurement (required)"`
+}
+
+func (h *DealHandlers) AddDealContact(_ context.Context, request *mcp.CallToolRequest, input AddDealContactInput) (*mcp.CallToolResult, DealOutput, error) {
+	if input.DealID == "" {
+		return nil, DealOutput{}, fmt.Errorf("deal_id is required")
+	}
+	if input.Contact == "" {
+		return nil, DealOutput{}, fmt.Errorf("contact is required")
+	}
+	if !charm.IsValidDealRole(input.Role) {
+		return nil, DealOutput{}, fmt.Errorf("invalid role: %s (valid: %s)", input.Role, strings.Join(charm.DealRoles, ", "))
+	}
+
+	dealID, err := uuid.Parse(input.DealID)
+	if err != nil {
+		return nil, DealOutput{}, fmt.Errorf("invalid deal_id: %w", err)
+	}
+
+	deal, err := h.client.GetDeal(dealID)
+	if err != nil {
+		return nil, DealOutput{}, fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	contact, err := resolveContact(h.client, input.Contact)
+	if err != nil {
+		return nil, DealOutput{}, err
+	}
+
+	if err := h.client.SaveDealContact(&charm.DealContact{
+		DealID:      deal.ID,
+		DealTitle:   deal.Title,
+		ContactID:   contact.ID,
+		ContactName: contact.Name,
+		Role:        input.Role,
+	}); err != nil {
+		return nil, DealOutput{}, fmt.Errorf("failed to add contact to deal: %w", err)
+	}
+
+	return nil, dealOutputWithContacts(h.client, deal), nil
+}
+
+type RemoveDealContactInput struct {
+	DealID  string `json:"deal_id" jsonschema:"Deal ID (required)"`
+	Contact string `json:"contact" jsonschema:"Contact ID or name (required)"`
+}
+
+func (h *DealHandlers) RemoveDealContact(_ context.Context, request *mcp.CallToolRequest, input RemoveDealContactInput) (*mcp.CallToolResult, DealOutput, error) {
+	if input.DealID == "" {
+		return nil, DealOutput{}, fmt.Errorf("deal_id is required")
+	}
+	if input.Contact == "" {
+		return nil, DealOutput{}, fmt.Errorf("contact is required")
+	}
+
+	dealID, err := uuid.Parse(input.DealID)
+	if err != nil {
+		return nil, DealOutput{}, fmt.Errorf("invalid deal_id: %w", err)
+	}
+
+	deal, err := h.client.GetDeal(dealID)
+	if err != nil {
+		return nil, DealOutput{}, fmt.Errorf("failed to get deal: %w", err)
+	}
+
+	contact, err := resolveContact(h.client, input.Contact)
+	if err != nil {
+		return nil, DealOutput{}, err
+	}
+
+	if err := h.client.DeleteDealContact(deal.ID, contact.ID); err != nil {
+		return nil, DealOutput{}, fmt.Errorf("failed to remove contact from deal: %w", err)
+	}
+
+	return nil, dealOutputWithContacts(h.client, deal), nil
+}
+
+// resolveContact finds a contact by ID or by a name that matches exactly one contact.
+func resolveContact(client *charm.Client, ref string) (*charm.Contact, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		contact, err := client.GetContact(id)
+		if err != nil {
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		return contact, nil
+	}
+
+	contacts, err := client.ListContacts(&charm.ContactFilter{Query: ref, Limit: 10})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find contact: %w", err)
+	}
+	if len(contacts) == 0 {
+		return nil, fmt.Errorf("no contact found matching: %s", ref)
+	}
+	if len(contacts) > 1 {
+		for _, contact := range contacts {
+			if strings.EqualFold(contact.Name, ref) {
+				return contact, nil
+			}
+		}
+		return nil, fmt.Errorf("multiple contacts match %q, please use an ID", ref)
+	}
+	return contacts[0], nil
+}
+
+// resolveContactStrict resolves a contact ID, or a name matching exactly one
+// contact; nil means nothing matched.
+func resolveContactStrict(client *charm.Client, ref string) (*charm.Contact, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		contact, err := client.GetContact(id)
+		if err != nil {
+			return nil, fmt.Errorf("contact not found: %w", err)
+		}
+		return contact, nil
+	}
+	return client.ResolveContactName(ref, true)
+}
+
+// disambiguation turns an ambiguous strict lookup into an error result that
+// lists the candidates, so the caller can retry with an ID. Other errors are
+// returned as they are.
+func disambiguation(field string, err error) (*mcp.CallToolResult, DealOutput, error) {
+	var ambiguous *charm.AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		return nil, DealOutput{}, err
+	}
+
+	output := DealOutput{Disambiguation: &DisambiguationOutput{Field: field, Query: ambiguous.Ref}}
+	for _, candidate := range ambiguous.Candidates {
+		output.Disambiguation.Candidates = append(output.Disambiguation.Candidates, CandidateOutput{
+			ID:     candidate.ID.String(),
+			Name:   candidate.Name,
+			Detail: candidate.Detail,
+		})
+	}
+	result := &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s: %v", field, err)}},
+	}
+	return result, output, nil
+}
+
 func dealNoteToOutput(note *charm.DealNote) DealNoteOutput {
 	return DealNoteOutput{
 		ID:        note.ID.String(),
@@ -310,6 +672,18 @@ func (h *DealHandlers) DeleteDeal(_ context.Context, request *mcp.CallToolReques
 	}, nil
 }
 
+type PipelineMetricsInput struct {
+	StalledDays int `json:"stalled_days,omitempty" jsonschema:"Days in one stage before an open deal counts as stalled (default 30)"`
+}
+
+func (h *DealHandlers) PipelineMetrics(_ context.Context, _ *mcp.CallToolRequest, input PipelineMetricsInput) (*mcp.CallToolResult, charm.PipelineMetrics, error) {
+	metrics, err := h.client.PipelineMetrics(input.StalledDays, time.Now())
+	if err != nil {
+		return nil, charm.PipelineMetrics{}, fmt.Errorf("failed to measure pipeline: %w", err)
+	}
+	return nil, *metrics, nil
+}
+
 func isValidStage(stage string) bool {
 	validStages := []string{
 		charm.StageProspecting,
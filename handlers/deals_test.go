@@ -3,6 +3,8 @@
 package handlers
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -471,3 +473,306 @@ func TestAddDealNoteNotFound(t *testing.T) {
 		t.Error("Expected error for non-existent deal")
 	}
 }
+
+func TestDealStageRequirements(t *testing.T) {
+	client := charm.NewTestClient(t)
+	client.SetStageRequirements(map[string][]string{
+		charm.StageProposal:   {charm.DealFieldAmount},
+		charm.StageClosedLost: {charm.DealFieldCloseReason},
+	})
+
+	handler := NewDealHandlers(client)
+
+	_, _, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Proposal Deal",
+		Stage:       charm.StageProposal,
+		CompanyName: "Acme Corp",
+	})
+	if err == nil || !strings.Contains(err.Error(), "amount") {
+		t.Fatalf("expected missing amount error, got %v", err)
+	}
+
+	companies, _ := client.ListCompanies(nil)
+	if len(companies) != 0 {
+		t.Errorf("expected no company created for rejected deal, got %d", len(companies))
+	}
+
+	_, deal, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Proposal Deal",
+		Amount:      500000,
+		Stage:       charm.StageProposal,
+		CompanyName: "Acme Corp",
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+
+	if _, _, err := handler.UpdateDeal(context.Background(), nil, UpdateDealInput{ID: deal.ID, Stage: charm.StageClosedLost}); err == nil {
+		t.Error("expected closed_lost without close_reason to fail")
+	}
+
+	_, updated, err := handler.UpdateDeal(context.Background(), nil, UpdateDealInput{
+		ID:          deal.ID,
+		Stage:       charm.StageClosedLost,
+		CloseReason: "Chose a competitor",
+	})
+	if err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if updated.CloseReason != "Chose a competitor" {
+		t.Errorf("expected close reason in output, got %q", updated.CloseReason)
+	}
+}
+
+func TestAddAndRemoveDealContact(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewDealHandlers(client)
+
+	_, deal, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Enterprise Deal",
+		CompanyName: "Acme Corp",
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	contact := &charm.Contact{Name: "Jane Doe"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	if _, _, err := handler.AddDealContact(context.Background(), nil, AddDealContactInput{DealID: deal.ID, Contact: "Jane Doe", Role: "sponsor"}); err == nil {
+		t.Error("expected invalid role to fail")
+	}
+
+	_, out, err := handler.AddDealContact(context.Background(), nil, AddDealContactInput{
+		DealID:  deal.ID,
+		Contact: "Jane Doe",
+		Role:    charm.DealRoleChampion,
+	})
+	if err != nil {
+		t.Fatalf("AddDealContact failed: %v", err)
+	}
+	if len(out.Contacts) != 1 || out.Contacts[0].ContactName != "Jane Doe" || out.Contacts[0].Role != charm.DealRoleChampion {
+		t.Fatalf("unexpected deal contacts: %+v", out.Contacts)
+	}
+
+	_, out, err = handler.RemoveDealContact(context.Background(), nil, RemoveDealContactInput{
+		DealID:  deal.ID,
+		Contact: contact.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("RemoveDealContact failed: %v", err)
+	}
+	if len(out.Contacts) != 0 {
+		t.Errorf("expected no deal contacts after removal, got %d", len(out.Contacts))
+	}
+}
+
+func TestCreateDealWithSourceAndReferrer(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewDealHandlers(client)
+
+	referrer := &charm.Contact{Name: "Alice"}
+	if err := client.CreateContact(referrer); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	if _, _, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Bad Source",
+		CompanyName: "Acme Corp",
+		Source:      "cold-call",
+	}); err == nil {
+		t.Error("expected invalid source to fail")
+	}
+
+	_, deal, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Referred Deal",
+		CompanyName: "Acme Corp",
+		Source:      charm.DealSourceReferral,
+		Referrer:    "Alice",
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if deal.Source != charm.DealSourceReferral || deal.ReferrerName != "Alice" || deal.ReferrerID == nil {
+		t.Errorf("unexpected source output: %+v", deal)
+	}
+
+	// Deleting the referrer clears it from the deal
+	if err := client.DeleteContactWithCascade(referrer.ID); err != nil {
+		t.Fatalf("DeleteContactWithCascade failed: %v", err)
+	}
+	id, _ := uuid.Parse(deal.ID)
+	stored, err := client.GetDeal(id)
+	if err != nil {
+		t.Fatalf("GetDeal failed: %v", err)
+	}
+	if stored.ReferrerID != nil || stored.ReferrerName != "" {
+		t.Errorf("expected referrer cleared, got %v %q", stored.ReferrerID, stored.ReferrerName)
+	}
+}
+
+func TestDealProbabilityOverride(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewDealHandlers(client)
+
+	_, deal, err := handler.CreateDeal(context.Background(), nil, CreateDealInput{
+		Title:       "Weighted Deal",
+		CompanyName: "Acme Corp",
+		Amount:      1000000,
+		Stage:       charm.StageProposal,
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if deal.Probability != 50 || deal.ProbabilitySet || deal.ExpectedValue != 500000 {
+		t.Errorf("expected stage default 50%% and expected value 500000, got %d%% %d", deal.Probability, deal.ExpectedValue)
+	}
+
+	override := 80
+	_, deal, err = handler.UpdateDeal(context.Background(), nil, UpdateDealInput{ID: deal.ID, Probability: &override})
+	if err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if deal.Probability != 80 || !deal.ProbabilitySet || deal.ExpectedValue != 800000 {
+		t.Errorf("expected override 80%% and expected value 800000, got %d%% %d", deal.Probability, deal.ExpectedValue)
+	}
+
+	// The override sticks across stage changes until reset
+	_, deal, err = handler.UpdateDeal(context.Background(), nil, UpdateDealInput{ID: deal.ID, Stage: charm.StageNegotiation})
+	if err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if deal.Probability != 80 {
+		t.Errorf("expected override to survive stage change, got %d%%", deal.Probability)
+	}
+
+	reset := -1
+	_, deal, err = handler.UpdateDeal(context.Background(), nil, UpdateDealInput{ID: deal.ID, Probability: &reset})
+	if err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	if deal.Probability != 75 || deal.ProbabilitySet {
+		t.Errorf("expected reset to negotiation default 75%%, got %d%%", deal.Probability)
+	}
+
+	invalid := 150
+	if _, _, err := handler.UpdateDeal(context.Background(), nil, UpdateDealInput{ID: deal.ID, Probability: &invalid}); err == nil {
+		t.Error("expected probability over 100 to fail")
+	}
+}
+
+func TestCreateDealIdempotent(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewDealHandlers(client)
+	ctx := context.Background()
+
+	_, first, err := handler.CreateDeal(ctx, nil, CreateDealInput{
+		Title:       "Enterprise License",
+		CompanyName: "Acme Corp",
+		InitialNote: "Kickoff call",
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if first.Existing {
+		t.Error("expected the first call to create the deal")
+	}
+
+	// Same title and company, even with different case
+	_, again, err := handler.CreateDeal(ctx, nil, CreateDealInput{
+		Title:       "enterprise license",
+		CompanyName: "Acme Corp",
+		InitialNote: "Kickoff call",
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if again.ID != first.ID || !again.Existing {
+		t.Errorf("expected the existing deal, got %+v", again)
+	}
+	id, _ := uuid.Parse(first.ID)
+	if notes, _ := client.ListDealNotes(id); len(notes) != 1 {
+		t.Errorf("expected the initial note once, got %d", len(notes))
+	}
+
+	// An idempotency key returns the first deal even after it is renamed
+	_, keyed, err := handler.CreateDeal(ctx, nil, CreateDealInput{Title: "Pilot", CompanyName: "Acme Corp", IdempotencyKey: "req-1"})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if _, _, err := handler.UpdateDeal(ctx, nil, UpdateDealInput{ID: keyed.ID, Title: "Pilot (renamed)"}); err != nil {
+		t.Fatalf("UpdateDeal failed: %v", err)
+	}
+	_, retried, err := handler.CreateDeal(ctx, nil, CreateDealInput{Title: "Pilot", CompanyName: "Acme Corp", IdempotencyKey: "req-1"})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if retried.ID != keyed.ID || !retried.Existing {
+		t.Errorf("expected the keyed deal, got %+v", retried)
+	}
+
+	deals, err := client.ListDeals(&charm.DealFilter{})
+	if err != nil {
+		t.Fatalf("ListDeals failed: %v", err)
+	}
+	if len(deals) != 2 {
+		t.Errorf("expected 2 deals, got %d", len(deals))
+	}
+}
+
+func TestCreateDealStrictDisambiguation(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewDealHandlers(client)
+	ctx := context.Background()
+
+	first := &charm.Contact{Name: "John Smith", Email: "john@acme.com"}
+	second := &charm.Contact{Name: "John Smith", Email: "jsmith@globex.com"}
+	for _, contact := range []*charm.Contact{first, second} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	strict := true
+	result, output, err := handler.CreateDeal(ctx, nil, CreateDealInput{
+		Title:       "Pilot",
+		CompanyName: "Acme Corp",
+		ContactName: "John Smith",
+		Strict:      &strict,
+	})
+	if err != nil {
+		t.Fatalf("expected a disambiguation result, got error: %v", err)
+	}
+	if result == nil || !result.IsError {
+		t.Fatalf("expected an error result, got %+v", result)
+	}
+	if output.Disambiguation == nil || output.Disambiguation.Field != "contact_name" || len(output.Disambiguation.Candidates) != 2 {
+		t.Fatalf("unexpected disambiguation: %+v", output.Disambiguation)
+	}
+	if deals, _ := client.ListDeals(&charm.DealFilter{}); len(deals) != 0 {
+		t.Errorf("expected no deal to be created, got %d", len(deals))
+	}
+	if company, _ := client.FindCompanyByName("Acme Corp"); company != nil {
+		t.Error("expected no company to be created")
+	}
+
+	// Retrying with a candidate's ID succeeds
+	_, deal, err := handler.CreateDeal(ctx, nil, CreateDealInput{
+		Title:       "Pilot",
+		CompanyName: "Acme Corp",
+		ContactName: second.ID.String(),
+		Strict:      &strict,
+	})
+	if err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if deal.ContactID == nil || *deal.ContactID != second.ID.String() {
+		t.Errorf("expected the chosen contact, got %+v", deal.ContactID)
+	}
+
+	// Without strict the first match is taken as before
+	if _, _, err := handler.CreateDeal(ctx, nil, CreateDealInput{Title: "Lenient", CompanyName: "Acme Corp", ContactName: "John Smith"}); err != nil {
+		t.Errorf("expected lenient lookup to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+// ABOUTME: Contact enrichment MCP tool handler
+// ABOUTME: Implements enrich_contact: seniority and function via sampling or title rules, plus public-source metadata
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultEnrichLimit caps how many new contacts one enrich_contact call classifies.
+const defaultEnrichLimit = 10
+
+type EnrichmentHandlers struct {
+	client *charm.Client
+	// fallback classifies contacts when the MCP client can't sample from its LLM
+	fallback charm.Classifier
+	// enricher looks contacts up in Gravatar, company sites, and the enrichment API
+	enricher *sync.PublicEnricher
+}
+
+func NewEnrichmentHandlers(client *charm.Client) *EnrichmentHandlers {
+	return &EnrichmentHandlers{client: client, fallback: charm.RuleClassifier{}, enricher: sync.NewPublicEnricher(client.Config())}
+}
+
+type EnrichContactInput struct {
+	ContactID string `json:"contact_id,omitempty" jsonschema:"Contact to classify (default: contacts not classified yet, newest first)"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Maximum contacts to classify when no contact_id is given (default 10)"`
+	Method    string `json:"method,omitempty" jsonschema:"auto (default: your LLM via sampling when supported, otherwise title rules), sampling, or rules"`
+	Sources   string `json:"sources,omitempty" jsonschema:"Comma-separated: classify, gravatar, website, api (default: all for a contact_id, classify for new contacts)"`
+	DryRun    bool   `json:"dry_run,omitempty" jsonschema:"Enrich without saving the results"`
+}
+
+type EnrichedContact struct {
+	ContactID string `json:"contact_id"`
+	Name      string `json:"name"`
+	Title     string `json:"title,omitempty"`
+	Seniority string `json:"seniority,omitempty"`
+	Function  string `json:"function,omitempty"`
+	Source    string `json:"source,omitempty"`
+	// Metadata found in public sources, each value with its provenance
+	Metadata        charm.Metadata `json:"metadata,omitempty"`
+	CompanyMetadata charm.Metadata `json:"company_metadata,omitempty"`
+	Notes           []string       `json:"notes,omitempty"`
+	Applied         bool           `json:"applied"`
+	Error           string         `json:"error,omitempty"`
+}
+
+type EnrichContactOutput struct {
+	Contacts []EnrichedContact `json:"contacts"`
+	Method   string            `json:"method"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+}
+
+// EnrichContact classifies contacts' seniority and job function and looks
+// them up in public sources, writing the results back. By default the
+// calling client's own LLM does the classifying through MCP sampling, so
+// pagen needs no model or API key of its own; clients without sampling get
+// the keyword rules instead.
+func (h *EnrichmentHandlers) EnrichContact(ctx context.Context, request *mcp.CallToolRequest, input EnrichContactInput) (*mcp.CallToolResult, EnrichContactOutput, error) {
+	sourceList := input.Sources
+	if sourceList == "" && input.ContactID == "" {
+		// Batches stay offline unless asked; public lookups are per-contact requests
+		sourceList = sync.EnrichClassify
+	}
+	sources, err := sync.ParseEnrichSources(sourceList)
+	if err != nil {
+		return nil, EnrichContactOutput{}, err
+	}
+	classify := sync.HasEnrichSource(sources, sync.EnrichClassify)
+	public := len(sources) > 1 || !classify
+
+	var classifier charm.Classifier
+	method := ""
+	if classify {
+		if classifier, method, err = h.classifierFor(request, input.Method); err != nil {
+			return nil, EnrichContactOutput{}, err
+		}
+	}
+
+	var contacts []*charm.Contact
+	if input.ContactID != "" {
+		contactID, err := uuid.Parse(input.ContactID)
+		if err != nil {
+			return nil, EnrichContactOutput{}, fmt.Errorf("invalid contact_id: %w", err)
+		}
+		contact, err := h.client.GetContact(contactID)
+		if err != nil {
+			return nil, EnrichContactOutput{}, fmt.Errorf("contact not found: %w", err)
+		}
+		contacts = []*charm.Contact{contact}
+	} else {
+		limit := input.Limit
+		if limit <= 0 {
+			limit = defaultEnrichLimit
+		}
+		contacts, err = h.client.ListUnenrichedContacts(limit)
+		if err != nil {
+			return nil, EnrichContactOutput{}, fmt.Errorf("failed to list contacts: %w", err)
+		}
+	}
+
+	output := EnrichContactOutput{Contacts: []EnrichedContact{}, Method: method, DryRun: input.DryRun}
+	for _, contact := range contacts {
+		result := EnrichedContact{ContactID: contact.ID.String(), Name: contact.Name, Title: contact.Title}
+		if classify {
+			h.classify(ctx, classifier, contact, input.DryRun, &result)
+		}
+		if public {
+			h.lookup(ctx, contact, sources, input.DryRun, &result)
+		}
+		output.Contacts = append(output.Contacts, result)
+	}
+	return nil, output, nil
+}
+
+// classify classifies one contact into result. A failure is recorded on the
+// result so one bad classification doesn't lose the rest of the batch.
+func (h *EnrichmentHandlers) classify(ctx context.Context, classifier charm.Classifier, contact *charm.Contact, dryRun bool, result *EnrichedContact) {
+	classification, err := classifier.Classify(ctx, charm.ClassificationText(contact))
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Seniority, result.Function, result.Source = classification.Seniority, classification.Function, classification.Source
+	if !dryRun && (classification.Seniority != "" || classification.Function != "") {
+		if _, err := h.client.ApplyClassification(contact.ID, classification); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Applied = true
+		}
+	}
+}
+
+// lookup enriches one contact from public sources into result. Sources that
+// fail or find nothing become notes.
+func (h *EnrichmentHandlers) lookup(ctx context.Context, contact *charm.Contact, sources []string, dryRun bool, result *EnrichedContact) {
+	found := h.enricher.Enrich(ctx, h.client, contact, sources)
+	result.Notes = append(found.Notes, found.Errors...)
+	if len(found.Contact) > 0 {
+		result.Metadata = found.Contact
+	}
+	if len(found.Company) > 0 {
+		result.CompanyMetadata = found.Company
+	}
+	if dryRun || (len(found.Contact) == 0 && len(found.Company) == 0) {
+		return
+	}
+	if _, _, err := found.Apply(h.client, contact.ID); err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Applied = true
+}
+
+// classifierFor picks the classifier for a method, using sampling only when
+// the client advertised support for it.
+func (h *EnrichmentHandlers) classifierFor(request *mcp.CallToolRequest, method string) (charm.Classifier, string, error) {
+	canSample := false
+	if request != nil && request.Session != nil {
+		if params := request.Session.InitializeParams(); params != nil && params.Capabilities != nil {
+			canSample = params.Capabilities.Sampling != nil
+		}
+	}
+
+	switch strings.ToLower(method) {
+	case "", "auto":
+		if canSample {
+			return &SamplingClassifier{Session: request.Session}, "sampling", nil
+		}
+		return h.fallback, "rules", nil
+	case "sampling":
+		if !canSample {
+			return nil, "", fmt.Errorf("this MCP client does not support sampling; use method rules")
+		}
+		return &SamplingClassifier{Session: request.Session}, "sampling", nil
+	case "rules":
+		return h.fallback, "rules", nil
+	default:
+		return nil, "", fmt.Errorf("invalid method %q (use auto, sampling, or rules)", method)
+	}
+}
+
+// SamplingClassifier classifies contacts by asking the MCP client's LLM
+// through sampling/createMessage.
+type SamplingClassifier struct {
+	Session *mcp.ServerSession
+}
+
+const classifySystemPrompt = `You classify business contacts for a CRM. Reply with only a JSON object: {"seniority": "...", "function": "..."}.
+seniority is one of: %s.
+function is one of: %s.
+Use an empty string for either when the text doesn't say enough to tell.`
+
+// Classify sends the contact description to the client's LLM and parses its
+// JSON reply.
+func (s *SamplingClassifier) Classify(ctx context.Context, text string) (*charm.ContactClassification, error) {
+	result, err := s.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+		SystemPrompt: fmt.Sprintf(classifySystemPrompt, strings.Join(charm.Seniorities, ", "), strings.Join(charm.Functions, ", ")),
+		Messages: []*mcp.SamplingMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: text},
+		}},
+		MaxTokens:        100,
+		ModelPreferences: &mcp.ModelPreferences{SpeedPriority: 0.8, CostPriority: 0.8, IntelligencePriority: 0.2},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sampling failed: %w", err)
+	}
+	reply, ok := result.Content.(*mcp.TextContent)
+	if !ok {
+		return nil, fmt.Errorf("sampling returned non-text content")
+	}
+
+	classification, err := parseClassificationReply(reply.Text)
+	if err != nil {
+		return nil, err
+	}
+	classification.Source = "sampling"
+	if result.Model != "" {
+		classification.Source += ":" + result.Model
+	}
+	return classification, nil
+}
+
+// parseClassificationReply extracts the JSON object from an LLM reply, which
+// may be wrapped in prose or a code fence, and normalizes its values.
+func parseClassificationReply(reply string) (*charm.ContactClassification, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in sampling reply: %q", reply)
+	}
+	var raw struct {
+		Seniority string `json:"seniority"`
+		Function  string `json:"function"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON in sampling reply: %w", err)
+	}
+
+	seniority, err := charm.NormalizeSeniority(raw.Seniority)
+	if err != nil {
+		return nil, err
+	}
+	function, err := charm.NormalizeFunction(raw.Function)
+	if err != nil {
+		return nil, err
+	}
+	return &charm.ContactClassification{Seniority: seniority, Function: function}, nil
+}
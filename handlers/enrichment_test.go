@@ -0,0 +1,148 @@
+// ABOUTME: Tests for the enrich_contact MCP tool
+// ABOUTME: Runs it over an in-memory MCP session, with and without a client that supports sampling
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callEnrichContact calls enrich_contact through a real client session. A nil
+// sample makes a client without sampling support.
+func callEnrichContact(t *testing.T, client *charm.Client, sample func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error), input EnrichContactInput) (EnrichContactOutput, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "crm", Version: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "enrich_contact"}, NewEnrichmentHandlers(client).EnrichContact)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{CreateMessageHandler: sample})
+	session, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "enrich_contact", Arguments: input})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		return EnrichContactOutput{}, &toolError{result}
+	}
+	var output EnrichContactOutput
+	data, _ := json.Marshal(result.StructuredContent)
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	return output, nil
+}
+
+type toolError struct{ result *mcp.CallToolResult }
+
+func (e *toolError) Error() string {
+	if len(e.result.Content) > 0 {
+		if text, ok := e.result.Content[0].(*mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool error"
+}
+
+func TestEnrichContactWithSampling(t *testing.T) {
+	client := charm.NewTestClient(t)
+	contact := &charm.Contact{Name: "Alice", Title: "Makes the trains run on time", Notes: "Runs the platform team of 40"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	var prompt string
+	sample := func(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		prompt = req.Params.Messages[0].Content.(*mcp.TextContent).Text
+		return &mcp.CreateMessageResult{
+			Role:    "assistant",
+			Model:   "test-model",
+			Content: &mcp.TextContent{Text: "Sure!\n```json\n{\"seniority\": \"Director\", \"function\": \"Engineering\"}\n```"},
+		}, nil
+	}
+
+	output, err := callEnrichContact(t, client, sample, EnrichContactInput{})
+	if err != nil {
+		t.Fatalf("enrich_contact failed: %v", err)
+	}
+	if output.Method != "sampling" || len(output.Contacts) != 1 {
+		t.Fatalf("expected 1 contact classified by sampling, got %+v", output)
+	}
+	if !strings.Contains(prompt, "Title: Makes the trains run on time") || !strings.Contains(prompt, "platform team") {
+		t.Errorf("expected the title and notes in the prompt, got %q", prompt)
+	}
+
+	got := output.Contacts[0]
+	if !got.Applied || got.Seniority != charm.SeniorityDirector || got.Function != "engineering" || got.Source != "sampling:test-model" {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	saved, _ := client.GetContact(contact.ID)
+	if saved.Seniority != charm.SeniorityDirector || saved.EnrichedBy != "sampling:test-model" {
+		t.Errorf("expected the classification saved, got %+v", saved)
+	}
+}
+
+func TestEnrichContactWithoutSampling(t *testing.T) {
+	client := charm.NewTestClient(t)
+	contact := &charm.Contact{Name: "Bob", Title: "VP Sales"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+
+	if _, err := callEnrichContact(t, client, nil, EnrichContactInput{Method: "sampling"}); err == nil {
+		t.Error("expected an error asking for sampling from a client without it")
+	}
+
+	output, err := callEnrichContact(t, client, nil, EnrichContactInput{ContactID: contact.ID.String(), DryRun: true})
+	if err != nil {
+		t.Fatalf("enrich_contact failed: %v", err)
+	}
+	if output.Method != "rules" || len(output.Contacts) != 1 {
+		t.Fatalf("expected the rules fallback, got %+v", output)
+	}
+	got := output.Contacts[0]
+	if got.Applied || got.Seniority != charm.SeniorityVP || got.Function != "sales" {
+		t.Errorf("unexpected dry run result: %+v", got)
+	}
+	// A single contact also gets the public sources, which note what they skipped
+	if len(got.Notes) != 3 || got.Notes[0] != "gravatar: no email" || got.Metadata != nil {
+		t.Errorf("expected notes for the skipped public sources, got %+v", got)
+	}
+	saved, _ := client.GetContact(contact.ID)
+	if saved.Seniority != "" {
+		t.Errorf("dry run should not save, got seniority %q", saved.Seniority)
+	}
+
+	if _, err := callEnrichContact(t, client, nil, EnrichContactInput{ContactID: contact.ID.String(), Sources: "clearbit"}); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+func TestParseClassificationReply(t *testing.T) {
+	if _, err := parseClassificationReply("I can't tell"); err == nil {
+		t.Error("expected an error for a reply without JSON")
+	}
+	if _, err := parseClassificationReply(`{"seniority": "overlord", "function": ""}`); err == nil {
+		t.Error("expected an error for an unknown seniority")
+	}
+	classification, err := parseClassificationReply(`{"seniority": "", "function": "HR"}`)
+	if err != nil || classification.Seniority != "" || classification.Function != "people" {
+		t.Errorf("unexpected classification %+v, %v", classification, err)
+	}
+}
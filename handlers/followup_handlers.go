@@ -21,14 +21,33 @@ func NewFollowupHandlers(client *charm.Client) *FollowupHandlers {
 }
 
 type GetFollowupListInput struct {
-	Limit       *int     `json:"limit,omitempty" jsonschema:"Maximum number of contacts to return (default 10)"`
+	Limit       *int     `json:"limit,omitempty" jsonschema:"Maximum number of contacts per page (default 10)"`
 	OverdueOnly *bool    `json:"overdue_only,omitempty" jsonschema:"Only show overdue contacts"`
 	MinPriority *float64 `json:"min_priority,omitempty" jsonschema:"Minimum priority score"`
+	ListOptions
+}
+
+// FollowupOutput is a contact needing follow-up. Zero scores are omitted.
+type FollowupOutput struct {
+	ID                   string     `json:"id"`
+	Name                 string     `json:"name"`
+	Email                string     `json:"email,omitempty"`
+	Phone                string     `json:"phone,omitempty"`
+	CompanyName          string     `json:"company_name,omitempty"`
+	Notes                string     `json:"notes,omitempty"`
+	LastContactedAt      *time.Time `json:"last_contacted_at,omitempty"`
+	NextFollowupDate     *time.Time `json:"next_followup_date,omitempty"`
+	CadenceDays          int        `json:"cadence_days,omitempty"`
+	RelationshipStrength string     `json:"relationship_strength,omitempty"`
+	PriorityScore        float64    `json:"priority_score,omitempty"`
+	DaysSinceContact     int        `json:"days_since_contact,omitempty"`
+	EngagementScore      float64    `json:"engagement_score,omitempty"`
 }
 
 type GetFollowupListOutput struct {
-	Followups []*charm.FollowupContact `json:"followups"`
-	Count     int                      `json:"count"`
+	Followups []FollowupOutput `json:"followups"`
+	Count     int              `json:"count"`
+	Page
 }
 
 func (h *FollowupHandlers) GetFollowupList(_ context.Context, _ *mcp.CallToolRequest, input GetFollowupListInput) (*mcp.CallToolResult, GetFollowupListOutput, error) {
@@ -47,13 +66,18 @@ func (h *FollowupHandlers) GetFollowupList(_ context.Context, _ *mcp.CallToolReq
 		minPriority = *input.MinPriority
 	}
 
-	allFollowups, err := h.client.GetFollowupList(limit)
+	detail, err := input.detail()
+	if err != nil {
+		return nil, GetFollowupListOutput{}, err
+	}
+
+	allFollowups, err := h.client.GetFollowupList(0)
 	if err != nil {
 		return nil, GetFollowupListOutput{}, fmt.Errorf("failed to get followup list: %w", err)
 	}
 
 	// Apply filters in memory
-	var followups []*charm.FollowupContact
+	var followups []FollowupOutput
 	for _, f := range allFollowups {
 		if overdueOnly && f.PriorityScore <= 0 {
 			continue
@@ -61,22 +85,55 @@ func (h *FollowupHandlers) GetFollowupList(_ context.Context, _ *mcp.CallToolReq
 		if minPriority > 0 && f.PriorityScore < minPriority {
 			continue
 		}
-		followups = append(followups, f)
+		followups = append(followups, followupToOutput(f, detail))
+	}
+
+	followups, page, err := paginate(followups, input.ListOptions, limit)
+	if err != nil {
+		return nil, GetFollowupListOutput{}, err
 	}
 
 	output := GetFollowupListOutput{
 		Followups: followups,
 		Count:     len(followups),
+		Page:      page,
 	}
 
 	return nil, output, nil
 }
 
+// followupToOutput converts a follow-up at the requested detail level.
+func followupToOutput(f *charm.FollowupContact, detail string) FollowupOutput {
+	output := FollowupOutput{ID: f.ID.String(), Name: f.Name}
+	if detail == DetailIDs {
+		return output
+	}
+
+	output.Email = f.Email
+	output.CompanyName = f.CompanyName
+	output.LastContactedAt = f.LastContactedAt
+	output.NextFollowupDate = f.NextFollowupDate
+	output.CadenceDays = f.CadenceDays
+	output.RelationshipStrength = f.RelationshipStrength
+	output.PriorityScore = f.PriorityScore
+	output.DaysSinceContact = f.DaysSinceContact
+	output.EngagementScore = f.EngagementScore
+	if detail == DetailFull {
+		output.Phone = f.Phone
+		output.Notes = f.Notes
+	}
+	return output
+}
+
 type LogInteractionInput struct {
 	ContactID       string  `json:"contact_id" jsonschema:"Contact ID or name (required)"`
 	InteractionType string  `json:"interaction_type" jsonschema:"Type of interaction: meeting, call, email, message, or event (required)"`
 	Notes           *string `json:"notes,omitempty" jsonschema:"Notes about the interaction"`
 	Sentiment       *string `json:"sentiment,omitempty" jsonschema:"Sentiment: positive, neutral, or negative"`
+	Location        *string `json:"location,omitempty" jsonschema:"Where an in-person meeting happened (address or venue, city)"`
+	Outcome         *string `json:"outcome,omitempty" jsonschema:"How the follow-up went: replied, no_answer, or meeting_booked"`
+	NextStep        *string `json:"next_step,omitempty" jsonschema:"What happens next"`
+	Template        *string `json:"template,omitempty" jsonschema:"Outreach email template this follows up (default: the contact's latest outreach email)"`
 }
 
 type LogInteractionOutput struct {
@@ -84,6 +141,8 @@ type LogInteractionOutput struct {
 	Message         string  `json:"message"`
 	InteractionID   string  `json:"interaction_id"`
 	UpdatedPriority float64 `json:"updated_priority"`
+	Outcome         string  `json:"outcome,omitempty"`
+	Template        string  `json:"template,omitempty"`
 }
 
 func (h *FollowupHandlers) LogInteraction(_ context.Context, _ *mcp.CallToolRequest, input LogInteractionInput) (*mcp.CallToolResult, LogInteractionOutput, error) {
@@ -124,8 +183,20 @@ func (h *FollowupHandlers) LogInteraction(_ context.Context, _ *mcp.CallToolRequ
 	if input.Notes != nil {
 		interaction.Notes = *input.Notes
 	}
+	if input.Location != nil {
+		interaction.Location = *input.Location
+	}
+	if input.Outcome != nil {
+		interaction.Outcome = *input.Outcome
+	}
+	if input.NextStep != nil {
+		interaction.NextStep = *input.NextStep
+	}
+	if input.Template != nil {
+		interaction.Template = *input.Template
+	}
 
-	err = h.client.CreateInteractionLog(interaction)
+	err = h.client.LogFollowup(interaction)
 	if err != nil {
 		return nil, LogInteractionOutput{}, fmt.Errorf("failed to log interaction: %w", err)
 	}
@@ -149,6 +220,8 @@ func (h *FollowupHandlers) LogInteraction(_ context.Context, _ *mcp.CallToolRequ
 		Message:         fmt.Sprintf("Logged %s interaction", input.InteractionType),
 		InteractionID:   interaction.ID.String(),
 		UpdatedPriority: priority,
+		Outcome:         interaction.Outcome,
+		Template:        interaction.Template,
 	}
 
 	return nil, output, nil
@@ -211,3 +284,21 @@ func (h *FollowupHandlers) SetCadence(_ context.Context, _ *mcp.CallToolRequest,
 
 	return nil, output, nil
 }
+
+type GetOutcomeStatsInput struct {
+	Days *int `json:"days,omitempty" jsonschema:"Only count outcomes from the last n days (default: all)"`
+}
+
+func (h *FollowupHandlers) GetOutcomeStats(_ context.Context, _ *mcp.CallToolRequest, input GetOutcomeStatsInput) (*mcp.CallToolResult, charm.OutcomeReport, error) {
+	var since *time.Time
+	if input.Days != nil && *input.Days > 0 {
+		t := time.Now().AddDate(0, 0, -*input.Days)
+		since = &t
+	}
+
+	report, err := h.client.GetOutcomeReport(since)
+	if err != nil {
+		return nil, charm.OutcomeReport{}, err
+	}
+	return nil, *report, nil
+}
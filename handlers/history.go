@@ -0,0 +1,103 @@
+// ABOUTME: Audit log MCP tool handler
+// ABOUTME: Implements get_entity_history, the changes made to an object and who made them
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type HistoryHandlers struct {
+	client *charm.Client
+}
+
+func NewHistoryHandlers(client *charm.Client) *HistoryHandlers {
+	return &HistoryHandlers{client: client}
+}
+
+type GetEntityHistoryInput struct {
+	EntityID string `json:"entity_id,omitempty" jsonschema:"ID of the contact, company, deal, or relationship (default: changes to everything)"`
+	Actor    string `json:"actor,omitempty" jsonschema:"Only changes made through cli, mcp, tui, web, or api"`
+	Days     int    `json:"days,omitempty" jsonschema:"Only changes from the last n days (default: all)"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"Maximum number of entries (default 50)"`
+}
+
+type FieldChangeOutput struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+type HistoryEntryOutput struct {
+	ID         string              `json:"id"`
+	EntityType string              `json:"entity_type"`
+	EntityID   string              `json:"entity_id"`
+	Op         string              `json:"op"`
+	Actor      string              `json:"actor"`
+	Summary    string              `json:"summary,omitempty"`
+	Rev        int                 `json:"rev"`
+	UndoRev    int                 `json:"undo_rev,omitempty"`
+	Undoes     string              `json:"undoes,omitempty"` // ID of the entry this one undid
+	Changes    []FieldChangeOutput `json:"changes,omitempty"`
+	At         string              `json:"at"`
+}
+
+type GetEntityHistoryOutput struct {
+	Entries []HistoryEntryOutput `json:"entries"`
+	Count   int                  `json:"count"`
+}
+
+// GetEntityHistory returns audit log entries, newest first. undo_rev is the
+// revision `pagen crm restore --rev` takes to undo an entry's change.
+func (h *HistoryHandlers) GetEntityHistory(_ context.Context, request *mcp.CallToolRequest, input GetEntityHistoryInput) (*mcp.CallToolResult, GetEntityHistoryOutput, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	filter := &charm.AuditFilter{Actor: input.Actor, Limit: limit}
+	if input.EntityID != "" {
+		id, err := uuid.Parse(input.EntityID)
+		if err != nil {
+			return nil, GetEntityHistoryOutput{}, fmt.Errorf("invalid entity_id: %w", err)
+		}
+		filter.EntityID = &id
+	}
+	if input.Days > 0 {
+		since := time.Now().AddDate(0, 0, -input.Days)
+		filter.Since = &since
+	}
+
+	entries, err := h.client.ListAuditLog(filter)
+	if err != nil {
+		return nil, GetEntityHistoryOutput{}, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	output := GetEntityHistoryOutput{Entries: make([]HistoryEntryOutput, len(entries)), Count: len(entries)}
+	for i, entry := range entries {
+		out := HistoryEntryOutput{
+			ID:         entry.ID.String(),
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID.String(),
+			Op:         entry.Op,
+			Actor:      entry.Actor,
+			Summary:    entry.Summary,
+			Rev:        entry.Rev,
+			UndoRev:    entry.UndoRev(),
+			At:         entry.At.Format(time.RFC3339),
+		}
+		if entry.Undoes != nil {
+			out.Undoes = entry.Undoes.String()
+		}
+		for _, change := range entry.Changes {
+			out.Changes = append(out.Changes, FieldChangeOutput{Field: change.Field, Old: change.Old, New: change.New})
+		}
+		output.Entries[i] = out
+	}
+	return nil, output, nil
+}
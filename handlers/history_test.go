@@ -0,0 +1,56 @@
+// ABOUTME: Tests for the audit log MCP tool handler
+// ABOUTME: Validates get_entity_history output and its entity and actor filters
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestGetEntityHistory(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Alice", Email: "alice@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	mcpClient := client.ForActor(charm.EntryMCP)
+	contact.Email = "alice@acme.com"
+	if err := mcpClient.UpdateContact(contact); err != nil {
+		t.Fatalf("failed to update contact: %v", err)
+	}
+	if err := mcpClient.CreateCompany(&charm.Company{Name: "Acme"}); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	handler := NewHistoryHandlers(client)
+
+	_, output, err := handler.GetEntityHistory(context.Background(), nil, GetEntityHistoryInput{EntityID: contact.ID.String()})
+	if err != nil {
+		t.Fatalf("GetEntityHistory failed: %v", err)
+	}
+	if output.Count != 2 {
+		t.Fatalf("expected 2 entries, got %d", output.Count)
+	}
+	update := output.Entries[0]
+	if update.Op != charm.RevisionOpUpdate || update.Actor != charm.EntryMCP || update.UndoRev != 1 {
+		t.Errorf("unexpected update entry: %+v", update)
+	}
+	if len(update.Changes) != 1 || update.Changes[0].Field != "email" || string(update.Changes[0].New) != `"alice@acme.com"` {
+		t.Errorf("expected the email change, got %+v", update.Changes)
+	}
+
+	_, output, err = handler.GetEntityHistory(context.Background(), nil, GetEntityHistoryInput{Actor: charm.EntryMCP})
+	if err != nil {
+		t.Fatalf("GetEntityHistory failed: %v", err)
+	}
+	if output.Count != 2 || output.Entries[0].EntityType != "company" {
+		t.Errorf("expected the two mcp changes, newest first, got %+v", output.Entries)
+	}
+
+	if _, _, err := handler.GetEntityHistory(context.Background(), nil, GetEntityHistoryInput{EntityID: "nope"}); err == nil {
+		t.Error("expected an error for an invalid entity_id")
+	}
+}
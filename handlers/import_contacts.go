@@ -0,0 +1,67 @@
+// ABOUTME: Contact import MCP tool handler
+// ABOUTME: Implements import_contacts for CSV and vCard contact lists given as a file path or inline content
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harperreed/pagen/sync"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ImportContactsInput struct {
+	Path    string `json:"path,omitempty" jsonschema:"Path to a CSV or vCard (.vcf) file on this machine (this or content is required)"`
+	Content string `json:"content,omitempty" jsonschema:"CSV or vCard text to import (this or path is required)"`
+	Format  string `json:"format,omitempty" jsonschema:"csv or vcard (default: detected from the content)"`
+	DryRun  bool   `json:"dry_run,omitempty" jsonschema:"Report what would be imported without writing"`
+}
+
+type ImportContactsOutput struct {
+	Records          int      `json:"records"`
+	Created          int      `json:"created"`
+	Merged           int      `json:"merged"`
+	Skipped          int      `json:"skipped"`
+	CompaniesCreated []string `json:"companies_created,omitempty"`
+	Problems         []string `json:"problems,omitempty"`
+	DryRun           bool     `json:"dry_run,omitempty"`
+}
+
+// ImportContacts imports a contact list. Existing contacts match on email (or
+// a unique name without one) and only have missing fields filled in.
+func (h *BulkHandlers) ImportContacts(_ context.Context, request *mcp.CallToolRequest, input ImportContactsInput) (*mcp.CallToolResult, ImportContactsOutput, error) {
+	if (input.Path == "") == (input.Content == "") {
+		return nil, ImportContactsOutput{}, fmt.Errorf("exactly one of path or content is required")
+	}
+
+	var r io.Reader = strings.NewReader(input.Content)
+	if input.Path != "" {
+		f, err := os.Open(input.Path)
+		if err != nil {
+			return nil, ImportContactsOutput{}, fmt.Errorf("failed to open contacts file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	records, err := sync.ParseContactFile(r, input.Format)
+	if err != nil {
+		return nil, ImportContactsOutput{}, fmt.Errorf("failed to parse contacts: %w", err)
+	}
+	result, err := sync.ImportContactRecords(h.client, records, input.DryRun)
+	if err != nil {
+		return nil, ImportContactsOutput{}, err
+	}
+	return nil, ImportContactsOutput{
+		Records:          len(records),
+		Created:          result.Created,
+		Merged:           result.Merged,
+		Skipped:          result.Skipped,
+		CompaniesCreated: result.CompaniesCreated,
+		Problems:         result.Problems,
+		DryRun:           input.DryRun,
+	}, nil
+}
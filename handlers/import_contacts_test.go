@@ -0,0 +1,46 @@
+// ABOUTME: Tests for the import_contacts MCP tool
+// ABOUTME: Verifies imports from inline content and files, and input validation
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestImportContacts(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewBulkHandlers(client)
+
+	csv := "Name,Email,Company\nAlice,alice@acme.com,Acme\nBob,bob@acme.com,\n"
+	_, output, err := handler.ImportContacts(context.Background(), nil, ImportContactsInput{Content: csv})
+	if err != nil {
+		t.Fatalf("ImportContacts failed: %v", err)
+	}
+	if output.Records != 2 || output.Created != 2 || len(output.CompaniesCreated) != 1 {
+		t.Errorf("expected 2 contacts and 1 company created, got %+v", output)
+	}
+
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+	vcard := "BEGIN:VCARD\nVERSION:3.0\nFN:Alice\nEMAIL:alice@acme.com\nTITLE:CTO\nEND:VCARD\n"
+	if err := os.WriteFile(path, []byte(vcard), 0644); err != nil {
+		t.Fatalf("failed to write vCard: %v", err)
+	}
+	_, output, err = handler.ImportContacts(context.Background(), nil, ImportContactsInput{Path: path})
+	if err != nil {
+		t.Fatalf("ImportContacts failed: %v", err)
+	}
+	if output.Merged != 1 || output.Created != 0 {
+		t.Errorf("expected Alice merged, got %+v", output)
+	}
+
+	if _, _, err := handler.ImportContacts(context.Background(), nil, ImportContactsInput{}); err == nil {
+		t.Error("expected an error without path or content")
+	}
+	if _, _, err := handler.ImportContacts(context.Background(), nil, ImportContactsInput{Path: path, Content: csv}); err == nil {
+		t.Error("expected an error with both path and content")
+	}
+}
@@ -0,0 +1,315 @@
+// ABOUTME: User-provided MCP prompt templates loaded from the config directory
+// ABOUTME: Parses Go templates with a metadata header and renders them against CRM data
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// PromptTemplateExt is the file extension for user prompt templates.
+const PromptTemplateExt = ".tmpl"
+
+// PromptTemplate is a user-defined prompt backed by a Go template.
+//
+// Templates live in the prompts directory (see PromptTemplatesDir) and may
+// start with a comment block declaring metadata:
+//
+//	{{/*
+//	description: Summarize a contact for the sales team
+//	arg: contact_id! UUID of the contact
+//	arg: focus Optional area to focus on
+//	*/}}
+//
+// A trailing "!" on an argument name marks it as required. The prompt name is
+// the file name without the extension; a template named after a built-in
+// prompt replaces it.
+type PromptTemplate struct {
+	Name        string
+	Description string
+	Arguments   []*mcp.PromptArgument
+	Path        string
+
+	tmpl *template.Template
+}
+
+// PromptTemplateData is the value passed to user templates as ".".
+type PromptTemplateData struct {
+	Args map[string]string
+}
+
+// Arg returns the named argument or an empty string.
+func (d PromptTemplateData) Arg(name string) string {
+	return d.Args[name]
+}
+
+// PromptTemplatesDir returns the directory user prompt templates are loaded
+// from, which belongs to the active profile.
+func PromptTemplatesDir() string {
+	return filepath.Join(charm.DataDir(), "prompts")
+}
+
+// LoadPromptTemplates parses every template in dir. A missing directory is not
+// an error and yields no templates.
+func LoadPromptTemplates(dir string) ([]*PromptTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read prompt templates: %w", err)
+	}
+
+	var templates []*PromptTemplate
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != PromptTemplateExt {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		pt, err := parsePromptTemplate(strings.TrimSuffix(entry.Name(), PromptTemplateExt), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		pt.Path = path
+		templates = append(templates, pt)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+func parsePromptTemplate(name, text string) (*PromptTemplate, error) {
+	pt := &PromptTemplate{
+		Name:        name,
+		Description: fmt.Sprintf("Custom prompt: %s", name),
+	}
+
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "{{/*") {
+		end := strings.Index(trimmed, "*/}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated metadata comment")
+		}
+		header := trimmed[len("{{/*"):end]
+		for _, line := range strings.Split(header, "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "description":
+				pt.Description = value
+			case "arg", "argument":
+				argName, argDesc, _ := strings.Cut(value, " ")
+				required := strings.HasSuffix(argName, "!")
+				argName = strings.TrimSuffix(argName, "!")
+				if argName == "" {
+					continue
+				}
+				pt.Arguments = append(pt.Arguments, &mcp.PromptArgument{
+					Name:        argName,
+					Description: strings.TrimSpace(argDesc),
+					Required:    required,
+				})
+			}
+		}
+	}
+
+	// Parse with placeholder funcs so syntax errors surface at load time;
+	// the real client-bound funcs are installed per render.
+	tmpl, err := template.New(name).Funcs(promptTemplateFuncs(nil)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	pt.tmpl = tmpl
+
+	return pt, nil
+}
+
+// MCPPrompt returns the prompt definition to register with the MCP server.
+func (pt *PromptTemplate) MCPPrompt() *mcp.Prompt {
+	return &mcp.Prompt{
+		Name:        pt.Name,
+		Description: pt.Description,
+		Arguments:   pt.Arguments,
+	}
+}
+
+// Render executes the template against the CRM with the given arguments.
+func (pt *PromptTemplate) Render(client *charm.Client, args map[string]string) (*mcp.GetPromptResult, error) {
+	for _, arg := range pt.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			return nil, fmt.Errorf("%s is required", arg.Name)
+		}
+	}
+
+	tmpl, err := pt.tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(promptTemplateFuncs(client)).Execute(&buf, PromptTemplateData{Args: args}); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", pt.Name, err)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: pt.Description,
+		Messages: []*mcp.PromptMessage{
+			{
+				Role: "user",
+				Content: &mcp.TextContent{
+					Text: strings.TrimSpace(buf.String()),
+				},
+			},
+		},
+	}, nil
+}
+
+// promptTemplateFuncs exposes the same CRM queries the built-in prompts use.
+func promptTemplateFuncs(client *charm.Client) template.FuncMap {
+	requireClient := func() error {
+		if client == nil {
+			return fmt.Errorf("no CRM client available")
+		}
+		return nil
+	}
+
+	return template.FuncMap{
+		"contact": func(id string) (*charm.Contact, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			contactID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contact id: %w", err)
+			}
+			return client.GetContact(contactID)
+		},
+		"company": func(id string) (*charm.Company, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			companyID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid company id: %w", err)
+			}
+			return client.GetCompany(companyID)
+		},
+		"deal": func(id string) (*charm.Deal, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			dealID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deal id: %w", err)
+			}
+			return client.GetDeal(dealID)
+		},
+		"contacts": func() ([]*charm.Contact, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			return client.ListContacts(&charm.ContactFilter{Limit: 10000})
+		},
+		"companyContacts": func(id string) ([]*charm.Contact, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			companyID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid company id: %w", err)
+			}
+			return client.ListContacts(&charm.ContactFilter{CompanyID: &companyID, Limit: 1000})
+		},
+		"deals": func() ([]*charm.Deal, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			return client.ListDeals(&charm.DealFilter{Limit: 10000})
+		},
+		"companyDeals": func(id string) ([]*charm.Deal, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			companyID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid company id: %w", err)
+			}
+			return client.ListDeals(&charm.DealFilter{CompanyID: &companyID, Limit: 1000})
+		},
+		"dealNotes": func(id string) ([]*charm.DealNote, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			dealID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deal id: %w", err)
+			}
+			return client.ListDealNotes(dealID)
+		},
+		"relationships": func(id string) ([]*charm.Relationship, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			contactID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contact id: %w", err)
+			}
+			return client.ListRelationshipsForContact(contactID)
+		},
+		"interactions": func(id string) ([]*charm.InteractionLog, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			contactID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contact id: %w", err)
+			}
+			return client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &contactID, Limit: 50})
+		},
+		"followups": func(limit int) ([]*charm.FollowupContact, error) {
+			if err := requireClient(); err != nil {
+				return nil, err
+			}
+			return client.GetFollowupList(limit)
+		},
+		"dollars": func(cents int64) string {
+			return charm.FormatMoneyRounded(cents, "")
+		},
+		"money": charm.FormatMoney,
+		"date": func(v interface{}) string {
+			switch t := v.(type) {
+			case time.Time:
+				return t.Format("2006-01-02")
+			case *time.Time:
+				if t == nil {
+					return ""
+				}
+				return t.Format("2006-01-02")
+			default:
+				return ""
+			}
+		},
+	}
+}
@@ -0,0 +1,145 @@
+// ABOUTME: Tests for user-provided MCP prompt templates
+// ABOUTME: Validates metadata parsing, rendering against CRM data, and overrides
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func writePromptTemplate(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+}
+
+func TestLoadPromptTemplatesMissingDir(t *testing.T) {
+	templates, err := LoadPromptTemplates(filepath.Join(t.TempDir(), "nope"))
+	if err != nil {
+		t.Fatalf("expected no error for missing dir, got %v", err)
+	}
+	if len(templates) != 0 {
+		t.Errorf("expected no templates, got %d", len(templates))
+	}
+}
+
+func TestPromptTemplatesDirFollowsProfile(t *testing.T) {
+	// Runs after t.Setenv restores the environment
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+	t.Cleanup(func() { _ = charm.SetProfile("") })
+
+	if got, want := PromptTemplatesDir(), filepath.Join(xdg.DataHome, charm.AppName, "prompts"); got != want {
+		t.Errorf("expected the default profile's templates in %s, got %s", want, got)
+	}
+
+	if err := charm.CreateProfile("work"); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	if err := charm.SetProfile("work"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	if got, want := PromptTemplatesDir(), filepath.Join(xdg.DataHome, charm.AppName, "profiles", "work", "prompts"); got != want {
+		t.Errorf("expected the work profile's templates in %s, got %s", want, got)
+	}
+}
+
+func TestLoadPromptTemplatesMetadata(t *testing.T) {
+	dir := t.TempDir()
+	writePromptTemplate(t, dir, "account-brief.tmpl", `{{/*
+description: Brief for an account
+arg: contact_id! UUID of the contact
+arg: focus Area to focus on
+*/}}
+Hello`)
+	writePromptTemplate(t, dir, "ignored.txt", "not a template")
+
+	templates, err := LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates failed: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("expected 1 template, got %d", len(templates))
+	}
+
+	prompt := templates[0].MCPPrompt()
+	if prompt.Name != "account-brief" {
+		t.Errorf("expected name 'account-brief', got %q", prompt.Name)
+	}
+	if prompt.Description != "Brief for an account" {
+		t.Errorf("unexpected description %q", prompt.Description)
+	}
+	if len(prompt.Arguments) != 2 {
+		t.Fatalf("expected 2 arguments, got %d", len(prompt.Arguments))
+	}
+	if !prompt.Arguments[0].Required || prompt.Arguments[0].Name != "contact_id" {
+		t.Errorf("expected required contact_id, got %+v", prompt.Arguments[0])
+	}
+	if prompt.Arguments[1].Required {
+		t.Error("expected focus to be optional")
+	}
+}
+
+func TestLoadPromptTemplatesSyntaxError(t *testing.T) {
+	dir := t.TempDir()
+	writePromptTemplate(t, dir, "broken.tmpl", "{{ .Args.x ")
+
+	if _, err := LoadPromptTemplates(dir); err == nil {
+		t.Error("expected parse error for broken template")
+	}
+}
+
+func TestPromptTemplateOverridesBuiltin(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Ada Lovelace", Email: "ada@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	dir := t.TempDir()
+	writePromptTemplate(t, dir, "contact-summary.tmpl", `{{/*
+arg: contact_id! UUID of the contact
+*/}}
+{{ $c := contact (.Arg "contact_id") }}Custom summary for {{ $c.Name }} <{{ $c.Email }}>`)
+
+	templates, err := LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates failed: %v", err)
+	}
+
+	handler := NewPromptHandlers(client)
+	handler.AddTemplates(templates)
+
+	result, err := handler.GetPrompt(context.Background(), &mcp.GetPromptRequest{
+		Params: &mcp.GetPromptParams{
+			Name:      "contact-summary",
+			Arguments: map[string]string{"contact_id": contact.ID.String()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt failed: %v", err)
+	}
+
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	if !strings.Contains(text, "Custom summary for Ada Lovelace <ada@example.com>") {
+		t.Errorf("unexpected prompt text: %q", text)
+	}
+
+	// Required arguments are enforced
+	_, err = handler.GetPrompt(context.Background(), &mcp.GetPromptRequest{
+		Params: &mcp.GetPromptParams{Name: "contact-summary"},
+	})
+	if err == nil {
+		t.Error("expected error for missing required argument")
+	}
+}
@@ -4,7 +4,9 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,17 +16,32 @@ import (
 )
 
 type PromptHandlers struct {
-	client *charm.Client
+	client    *charm.Client
+	templates map[string]*PromptTemplate
 }
 
 func NewPromptHandlers(client *charm.Client) *PromptHandlers {
 	return &PromptHandlers{client: client}
 }
 
+// AddTemplates registers user-provided prompt templates. A template with the
+// same name as a built-in prompt takes precedence over it.
+func (h *PromptHandlers) AddTemplates(templates []*PromptTemplate) {
+	if h.templates == nil {
+		h.templates = make(map[string]*PromptTemplate)
+	}
+	for _, pt := range templates {
+		h.templates[pt.Name] = pt
+	}
+}
+
 // GetPrompt generates the prompt message based on the template.
 func (h *PromptHandlers) GetPrompt(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 	name := request.Params.Name
 	arguments := request.Params.Arguments
+	if pt, ok := h.templates[name]; ok {
+		return pt.Render(h.client, arguments)
+	}
 	switch name {
 	case "contact-summary":
 		return h.getContactSummaryPrompt(arguments)
@@ -36,6 +53,10 @@ func (h *PromptHandlers) GetPrompt(ctx context.Context, request *mcp.GetPromptRe
 		return h.getFollowUpSuggestionsPrompt(arguments)
 	case "company-overview":
 		return h.getCompanyOverviewPrompt(arguments)
+	case "meeting-prep":
+		return h.getMeetingPrepPrompt(arguments)
+	case "qbr":
+		return h.getQBRPrompt(arguments)
 	default:
 		return nil, fmt.Errorf("unknown prompt: %s", name)
 	}
@@ -70,6 +91,7 @@ func (h *PromptHandlers) getContactSummaryPrompt(args map[string]string) (*mcp.G
 	var promptText strings.Builder
 	promptText.WriteString("Please provide a comprehensive summary of this contact:\n\n")
 	promptText.WriteString(fmt.Sprintf("Name: %s\n", contact.Name))
+	writePinnedFacts(&promptText, contact)
 	if contact.Email != "" {
 		promptText.WriteString(fmt.Sprintf("Email: %s\n", contact.Email))
 	}
@@ -82,6 +104,9 @@ func (h *PromptHandlers) getContactSummaryPrompt(args map[string]string) (*mcp.G
 	if contact.LastContactedAt != nil {
 		promptText.WriteString(fmt.Sprintf("Last Contacted: %s\n", contact.LastContactedAt.Format("2006-01-02")))
 	}
+	if howWeMet := contact.HowWeMet(); howWeMet != "" {
+		promptText.WriteString(fmt.Sprintf("How We Met: %s\n", howWeMet))
+	}
 	if len(relationships) > 0 {
 		promptText.WriteString(fmt.Sprintf("\nRelationships: %d connections\n", len(relationships)))
 	}
@@ -108,6 +133,18 @@ func (h *PromptHandlers) getContactSummaryPrompt(args map[string]string) (*mcp.G
 	}, nil
 }
 
+// writePinnedFacts lists a contact's pinned facts, which lead summaries and
+// briefs ahead of the chronological notes.
+func writePinnedFacts(b *strings.Builder, contact *charm.Contact) {
+	if len(contact.Pinned) == 0 {
+		return
+	}
+	b.WriteString("Pinned:\n")
+	for _, fact := range contact.Pinned {
+		b.WriteString(fmt.Sprintf("  - %s\n", fact))
+	}
+}
+
 func (h *PromptHandlers) getDealAnalysisPrompt(args map[string]string) (*mcp.GetPromptResult, error) {
 	// Get all deals
 	deals, err := h.client.ListDeals(&charm.DealFilter{Limit: 10000})
@@ -119,6 +156,7 @@ func (h *PromptHandlers) getDealAnalysisPrompt(args map[string]string) (*mcp.Get
 	stageCount := make(map[string]int)
 	stageValue := make(map[string]int64)
 	totalValue := int64(0)
+	expectedValue := int64(0)
 
 	for _, deal := range deals {
 		stage := deal.Stage
@@ -128,16 +166,18 @@ func (h *PromptHandlers) getDealAnalysisPrompt(args map[string]string) (*mcp.Get
 		stageCount[stage]++
 		stageValue[stage] += deal.Amount
 		totalValue += deal.Amount
+		expectedValue += deal.ExpectedValue()
 	}
 
 	// Build the prompt
 	var promptText strings.Builder
 	promptText.WriteString("Please analyze the current deal pipeline:\n\n")
 	promptText.WriteString(fmt.Sprintf("Total Deals: %d\n", len(deals)))
-	promptText.WriteString(fmt.Sprintf("Total Value: $%d\n\n", totalValue/100))
+	promptText.WriteString(fmt.Sprintf("Total Value: %s\n", charm.FormatMoneyRounded(totalValue, "")))
+	promptText.WriteString(fmt.Sprintf("Expected Value (probability-weighted): %s\n\n", charm.FormatMoneyRounded(expectedValue, "")))
 	promptText.WriteString("Pipeline by Stage:\n")
 	for stage, count := range stageCount {
-		promptText.WriteString(fmt.Sprintf("  - %s: %d deals, $%d\n", stage, count, stageValue[stage]/100))
+		promptText.WriteString(fmt.Sprintf("  - %s: %d deals, %s\n", stage, count, charm.FormatMoneyRounded(stageValue[stage], "")))
 	}
 
 	promptText.WriteString("\nPlease provide:")
@@ -325,6 +365,362 @@ func (h *PromptHandlers) getCompanyOverviewPrompt(args map[string]string) (*mcp.
 		return nil, fmt.Errorf("failed to fetch company: %w", err)
 	}
 
+	var promptText strings.Builder
+	primary, err := writeCompanyOverview(&promptText, h.client, company)
+	if err != nil {
+		return nil, err
+	}
+
+	promptText.WriteString("\nPlease provide:")
+	promptText.WriteString("\n1. A summary of the relationship with this company")
+	promptText.WriteString("\n2. Key opportunities or risks, including anything in the recent news")
+	promptText.WriteString("\n3. Recommended next actions")
+	if primary != nil {
+		promptText.WriteString(fmt.Sprintf(", starting with what to raise with %s", primary.Name))
+	}
+
+	return &mcp.GetPromptResult{
+		Description: fmt.Sprintf("Overview of %s", company.Name),
+		Messages: []*mcp.PromptMessage{
+			{
+				Role: "user",
+				Content: &mcp.TextContent{
+
+					Text: promptText.String(),
+				},
+			},
+		},
+	}, nil
+}
+
+// writeCompanyOverview writes a company's details, contacts, deals, and recent
+// news, for the company-overview prompt and the crm://companies/{id}/overview
+// resource. It returns the company's primary contact, if any.
+func writeCompanyOverview(out *strings.Builder, client *charm.Client, company *charm.Company) (*charm.Contact, error) {
+	contacts, err := client.ListContacts(&charm.ContactFilter{CompanyID: &company.ID, Limit: 1000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contacts: %w", err)
+	}
+
+	deals, err := client.ListDeals(&charm.DealFilter{CompanyID: &company.ID, Limit: 1000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deals: %w", err)
+	}
+
+	out.WriteString(fmt.Sprintf("Complete overview of: %s\n\n", company.Name))
+
+	if company.Industry != "" {
+		out.WriteString(fmt.Sprintf("Industry: %s\n", company.Industry))
+	}
+	if company.Domain != "" {
+		out.WriteString(fmt.Sprintf("Domain: %s\n", company.Domain))
+	}
+
+	// The primary contact leads the list, even when they work elsewhere
+	primary := client.PrimaryContact(company)
+	if primary != nil {
+		out.WriteString(fmt.Sprintf("Primary contact: %s\n", primary.Name))
+		others := []*charm.Contact{primary}
+		for _, contact := range contacts {
+			if contact.ID != primary.ID {
+				others = append(others, contact)
+			}
+		}
+		contacts = others
+	}
+
+	out.WriteString(fmt.Sprintf("\nContacts: %d people\n", len(contacts)))
+	for _, contact := range contacts {
+		out.WriteString(fmt.Sprintf("  - %s", contact.Name))
+		if contact.Email != "" {
+			out.WriteString(fmt.Sprintf(" <%s>", contact.Email))
+		}
+		if primary != nil && contact.ID == primary.ID {
+			out.WriteString(" (primary contact)")
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString(fmt.Sprintf("\nDeals: %d active\n", len(deals)))
+	totalValue := int64(0)
+	for _, deal := range deals {
+		out.WriteString(fmt.Sprintf("  - %s: %s (%s)\n", deal.Title, charm.FormatMoneyRounded(deal.Amount, deal.Currency), deal.Stage))
+		totalValue += deal.Amount
+	}
+	if len(deals) > 0 {
+		out.WriteString(fmt.Sprintf("\nTotal Deal Value: %s\n", charm.FormatMoneyRounded(totalValue, "")))
+	}
+
+	if company.Notes != "" {
+		out.WriteString(fmt.Sprintf("\nNotes: %s\n", company.Notes))
+	}
+
+	news, err := client.RecentCompanyNews(company.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch company news: %w", err)
+	}
+	if len(news) > 0 {
+		out.WriteString("\nRecent News:\n")
+		for _, item := range news {
+			out.WriteString(fmt.Sprintf("  - [%s] %s", item.PublishedAt.Format("2006-01-02"), item.Title))
+			if item.Source != "" {
+				out.WriteString(fmt.Sprintf(" (%s)", item.Source))
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	return primary, nil
+}
+
+func (h *PromptHandlers) getMeetingPrepPrompt(args map[string]string) (*mcp.GetPromptResult, error) {
+	eventID := strings.TrimSpace(args["event_id"])
+	attendees := strings.TrimSpace(args["attendees"])
+	if eventID == "" && attendees == "" {
+		return nil, fmt.Errorf("event_id or attendees is required")
+	}
+
+	var contacts []*charm.Contact
+	var unknown []string
+	seen := make(map[uuid.UUID]bool)
+	addContact := func(contact *charm.Contact) {
+		if contact != nil && !seen[contact.ID] {
+			seen[contact.ID] = true
+			contacts = append(contacts, contact)
+		}
+	}
+
+	meetingTitle := ""
+	if eventID != "" {
+		// Calendar sync stores the event ID in each attendee's interaction metadata
+		meetings, err := h.client.ListInteractionLogs(&charm.InteractionFilter{InteractionType: charm.InteractionMeeting})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch interactions: %w", err)
+		}
+		for _, meeting := range meetings {
+			var metadata map[string]interface{}
+			if err := json.Unmarshal([]byte(meeting.Metadata), &metadata); err != nil {
+				continue
+			}
+			if id, _ := metadata["calendar_event_id"].(string); id != eventID {
+				continue
+			}
+			if meetingTitle == "" {
+				meetingTitle = meeting.Notes
+			}
+			contact, err := h.client.GetContact(meeting.ContactID)
+			if err != nil {
+				continue
+			}
+			addContact(contact)
+		}
+	}
+
+	for _, email := range strings.Split(attendees, ",") {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		contact, err := h.client.FindContactByEmail(email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", email, err)
+		}
+		if contact == nil {
+			unknown = append(unknown, email)
+			continue
+		}
+		addContact(contact)
+	}
+
+	if len(contacts) == 0 && len(unknown) == 0 {
+		return nil, fmt.Errorf("no attendees found for event: %s", eventID)
+	}
+
+	now := time.Now()
+	var talkingPoints []string
+
+	var promptText strings.Builder
+	if meetingTitle != "" {
+		promptText.WriteString(fmt.Sprintf("Help me prepare for the meeting \"%s\".\n\n", meetingTitle))
+	} else {
+		promptText.WriteString("Help me prepare for an upcoming meeting.\n\n")
+	}
+	promptText.WriteString(fmt.Sprintf("Attendees: %d known", len(contacts)))
+	if len(unknown) > 0 {
+		promptText.WriteString(fmt.Sprintf(", %d not in CRM", len(unknown)))
+	}
+	promptText.WriteString("\n")
+
+	companyDeals := make(map[uuid.UUID]bool)
+	for _, contact := range contacts {
+		promptText.WriteString(fmt.Sprintf("\n## %s\n", contact.Name))
+		writePinnedFacts(&promptText, contact)
+		if contact.Email != "" {
+			promptText.WriteString(fmt.Sprintf("Email: %s\n", contact.Email))
+		}
+		if contact.CompanyName != "" {
+			promptText.WriteString(fmt.Sprintf("Company: %s\n", contact.CompanyName))
+		}
+		if contact.LastContactedAt != nil {
+			days := int(now.Sub(*contact.LastContactedAt).Hours() / 24)
+			promptText.WriteString(fmt.Sprintf("Last Contacted: %s (%d days ago)\n", contact.LastContactedAt.Format("2006-01-02"), days))
+			if days > 60 {
+				talkingPoints = append(talkingPoints, fmt.Sprintf("Reconnect with %s - it has been %d days since the last touchpoint", contact.Name, days))
+			}
+		}
+		if cadence, err := h.client.GetContactCadence(contact.ID); err == nil && cadence != nil {
+			promptText.WriteString(fmt.Sprintf("Relationship: %s (every %d days)\n", cadence.RelationshipStrength, cadence.CadenceDays))
+		}
+		if contact.Notes != "" {
+			promptText.WriteString(fmt.Sprintf("Notes: %s\n", contact.Notes))
+		}
+
+		contactID := contact.ID
+		interactions, err := h.client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &contactID, Limit: 5})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch interactions: %w", err)
+		}
+		if len(interactions) > 0 {
+			promptText.WriteString("Recent Interactions:\n")
+			for _, interaction := range interactions {
+				promptText.WriteString(fmt.Sprintf("  - %s %s", interaction.Timestamp.Format("2006-01-02"), interaction.InteractionType))
+				if interaction.Notes != "" {
+					promptText.WriteString(fmt.Sprintf(": %s", interaction.Notes))
+				}
+				if interaction.Outcome != "" {
+					promptText.WriteString(fmt.Sprintf(" [%s]", strings.ReplaceAll(interaction.Outcome, "_", " ")))
+				}
+				if interaction.NextStep != "" {
+					promptText.WriteString(fmt.Sprintf(" (next step: %s)", interaction.NextStep))
+				}
+				if interaction.Sentiment != nil && *interaction.Sentiment == charm.SentimentNegative {
+					talkingPoints = append(talkingPoints, fmt.Sprintf("Address concerns from %s's last %s on %s", contact.Name, interaction.InteractionType, interaction.Timestamp.Format("2006-01-02")))
+				}
+				promptText.WriteString("\n")
+			}
+		}
+
+		deals, err := h.client.ListDeals(&charm.DealFilter{ContactID: &contactID, Limit: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deals: %w", err)
+		}
+		if contact.CompanyID != nil && !companyDeals[*contact.CompanyID] {
+			companyDeals[*contact.CompanyID] = true
+			more, err := h.client.ListDeals(&charm.DealFilter{CompanyID: contact.CompanyID, Limit: 100})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch deals: %w", err)
+			}
+			for _, deal := range more {
+				if deal.ContactID == nil || *deal.ContactID != contactID {
+					deals = append(deals, deal)
+				}
+			}
+		}
+
+		// Deals where the attendee holds a role, even at another company
+		roles, err := h.client.ListContactDealRoles(contactID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deal roles: %w", err)
+		}
+		roleByDeal := make(map[uuid.UUID]string)
+		for _, role := range roles {
+			roleByDeal[role.DealID] = role.Role
+		}
+		listed := make(map[uuid.UUID]bool)
+		for _, deal := range deals {
+			listed[deal.ID] = true
+		}
+		for _, role := range roles {
+			if listed[role.DealID] {
+				continue
+			}
+			if deal, err := h.client.GetDeal(role.DealID); err == nil {
+				deals = append(deals, deal)
+			}
+		}
+
+		var open []*charm.Deal
+		for _, deal := range deals {
+			if deal.Stage != charm.StageClosedWon && deal.Stage != charm.StageClosedLost {
+				open = append(open, deal)
+			}
+		}
+		if len(open) > 0 {
+			promptText.WriteString("Open Deals:\n")
+			for _, deal := range open {
+				promptText.WriteString(fmt.Sprintf("  - %s: %s (%s)", deal.Title, charm.FormatMoneyRounded(deal.Amount, deal.Currency), deal.Stage))
+				if role, ok := roleByDeal[deal.ID]; ok {
+					promptText.WriteString(fmt.Sprintf(" [%s]", charm.DealRoleLabel(role)))
+				}
+				if deal.ExpectedCloseDate != nil {
+					promptText.WriteString(fmt.Sprintf(", closes %s", deal.ExpectedCloseDate.Format("2006-01-02")))
+					if deal.ExpectedCloseDate.Before(now.AddDate(0, 0, 30)) {
+						talkingPoints = append(talkingPoints, fmt.Sprintf("Confirm next steps on %s - expected close %s", deal.Title, deal.ExpectedCloseDate.Format("2006-01-02")))
+					}
+				}
+				promptText.WriteString("\n")
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		promptText.WriteString("\nNot in CRM:\n")
+		for _, email := range unknown {
+			promptText.WriteString(fmt.Sprintf("  - %s\n", email))
+		}
+	}
+
+	if len(talkingPoints) > 0 {
+		promptText.WriteString("\nSuggested Talking Points:\n")
+		for _, point := range talkingPoints {
+			promptText.WriteString(fmt.Sprintf("  - %s\n", point))
+		}
+	}
+
+	promptText.WriteString("\nPlease provide:")
+	promptText.WriteString("\n1. A one-paragraph briefing on each attendee")
+	promptText.WriteString("\n2. Goals for this meeting based on open deals and recent history")
+	promptText.WriteString("\n3. Talking points and questions to ask")
+	promptText.WriteString("\n4. Follow-up actions to log afterwards")
+
+	return &mcp.GetPromptResult{
+		Description: "Meeting preparation brief",
+		Messages: []*mcp.PromptMessage{
+			{
+				Role: "user",
+				Content: &mcp.TextContent{
+
+					Text: promptText.String(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (h *PromptHandlers) getQBRPrompt(args map[string]string) (*mcp.GetPromptResult, error) {
+	companyIDStr, ok := args["company_id"]
+	if !ok {
+		return nil, fmt.Errorf("company_id is required")
+	}
+
+	companyID, err := uuid.Parse(companyIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company_id: %w", err)
+	}
+
+	// Default to a 90 day review period
+	periodDays := 90
+	if d, ok := args["period_days"]; ok {
+		if parsed, err := fmt.Sscanf(d, "%d", &periodDays); err != nil || parsed != 1 || periodDays <= 0 {
+			periodDays = 90
+		}
+	}
+
+	company, err := h.client.GetCompany(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch company: %w", err)
+	}
+
 	contacts, err := h.client.ListContacts(&charm.ContactFilter{CompanyID: &companyID, Limit: 1000})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch contacts: %w", err)
@@ -335,9 +731,12 @@ func (h *PromptHandlers) getCompanyOverviewPrompt(args map[string]string) (*mcp.
 		return nil, fmt.Errorf("failed to fetch deals: %w", err)
 	}
 
-	var promptText strings.Builder
-	promptText.WriteString(fmt.Sprintf("Complete overview of: %s\n\n", company.Name))
+	now := time.Now()
+	since := now.AddDate(0, 0, -periodDays)
 
+	var promptText strings.Builder
+	promptText.WriteString(fmt.Sprintf("Quarterly Business Review: %s\n", company.Name))
+	promptText.WriteString(fmt.Sprintf("Review period: %s to %s\n", since.Format("2006-01-02"), now.Format("2006-01-02")))
 	if company.Industry != "" {
 		promptText.WriteString(fmt.Sprintf("Industry: %s\n", company.Industry))
 	}
@@ -345,36 +744,127 @@ func (h *PromptHandlers) getCompanyOverviewPrompt(args map[string]string) (*mcp.
 		promptText.WriteString(fmt.Sprintf("Domain: %s\n", company.Domain))
 	}
 
-	promptText.WriteString(fmt.Sprintf("\nContacts: %d people\n", len(contacts)))
+	// Deal history
+	var won, lost, open int64
+	promptText.WriteString(fmt.Sprintf("\n## Deal History (%d deals)\n", len(deals)))
+	for _, deal := range deals {
+		switch deal.Stage {
+		case charm.StageClosedWon:
+			won += deal.Amount
+		case charm.StageClosedLost:
+			lost += deal.Amount
+		default:
+			open += deal.Amount
+		}
+
+		promptText.WriteString(fmt.Sprintf("- %s: %s (%s), opened %s", deal.Title, charm.FormatMoneyRounded(deal.Amount, deal.Currency), deal.Stage, deal.CreatedAt.Format("2006-01-02")))
+		if deal.ContactName != "" {
+			promptText.WriteString(fmt.Sprintf(", owner contact %s", deal.ContactName))
+		}
+		promptText.WriteString("\n")
+
+		notes, err := h.client.ListDealNotes(deal.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deal notes: %w", err)
+		}
+		for _, note := range notes {
+			if note.CreatedAt.Before(since) {
+				continue
+			}
+			promptText.WriteString(fmt.Sprintf("    %s: %s\n", note.CreatedAt.Format("2006-01-02"), note.Content))
+		}
+	}
+	promptText.WriteString(fmt.Sprintf("Won: %s  Lost: %s  Open pipeline: %s\n", charm.FormatMoneyRounded(won, ""), charm.FormatMoneyRounded(lost, ""), charm.FormatMoneyRounded(open, "")))
+
+	// Stakeholders and interaction cadence
+	typeCounts := make(map[string]int)
+	totalInteractions := 0
+	promptText.WriteString(fmt.Sprintf("\n## Stakeholders (%d)\n", len(contacts)))
 	for _, contact := range contacts {
-		promptText.WriteString(fmt.Sprintf("  - %s", contact.Name))
+		contactID := contact.ID
+		interactions, err := h.client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &contactID, Since: &since})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch interactions: %w", err)
+		}
+		for _, interaction := range interactions {
+			typeCounts[interaction.InteractionType]++
+		}
+		totalInteractions += len(interactions)
+
+		promptText.WriteString(fmt.Sprintf("- %s", contact.Name))
 		if contact.Email != "" {
 			promptText.WriteString(fmt.Sprintf(" <%s>", contact.Email))
 		}
+		if cadence, err := h.client.GetContactCadence(contact.ID); err == nil && cadence != nil {
+			promptText.WriteString(fmt.Sprintf(", %s relationship, target every %d days", cadence.RelationshipStrength, cadence.CadenceDays))
+		}
+		promptText.WriteString(fmt.Sprintf(", %d interactions this period", len(interactions)))
+		if contact.LastContactedAt != nil {
+			promptText.WriteString(fmt.Sprintf(", last contacted %s", contact.LastContactedAt.Format("2006-01-02")))
+		} else {
+			promptText.WriteString(", never contacted")
+		}
 		promptText.WriteString("\n")
 	}
 
-	promptText.WriteString(fmt.Sprintf("\nDeals: %d active\n", len(deals)))
-	totalValue := int64(0)
+	promptText.WriteString("\n## Interaction Cadence\n")
+	promptText.WriteString(fmt.Sprintf("Total interactions: %d over %d days", totalInteractions, periodDays))
+	if totalInteractions > 0 {
+		promptText.WriteString(fmt.Sprintf(" (about one every %d days)", periodDays/totalInteractions))
+	}
+	promptText.WriteString("\n")
+	var types []string
+	for t := range typeCounts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		promptText.WriteString(fmt.Sprintf("  - %s: %d\n", t, typeCounts[t]))
+	}
+
+	// Renewal and close dates: open deals use their expected close date,
+	// won deals renew a year after they closed
+	promptText.WriteString("\n## Upcoming Renewals and Close Dates\n")
+	upcoming := 0
 	for _, deal := range deals {
-		promptText.WriteString(fmt.Sprintf("  - %s: $%d (%s)\n", deal.Title, deal.Amount/100, deal.Stage))
-		totalValue += deal.Amount
+		switch deal.Stage {
+		case charm.StageClosedLost:
+			continue
+		case charm.StageClosedWon:
+			closed := deal.UpdatedAt
+			if deal.ExpectedCloseDate != nil {
+				closed = *deal.ExpectedCloseDate
+			}
+			renewal := closed.AddDate(1, 0, 0)
+			for renewal.Before(now) {
+				renewal = renewal.AddDate(1, 0, 0)
+			}
+			promptText.WriteString(fmt.Sprintf("- %s renews %s\n", deal.Title, renewal.Format("2006-01-02")))
+			upcoming++
+		default:
+			if deal.ExpectedCloseDate != nil {
+				promptText.WriteString(fmt.Sprintf("- %s expected to close %s\n", deal.Title, deal.ExpectedCloseDate.Format("2006-01-02")))
+				upcoming++
+			}
+		}
 	}
-	if len(deals) > 0 {
-		promptText.WriteString(fmt.Sprintf("\nTotal Deal Value: $%d\n", totalValue/100))
+	if upcoming == 0 {
+		promptText.WriteString("No renewal or close dates on record.\n")
 	}
 
 	if company.Notes != "" {
 		promptText.WriteString(fmt.Sprintf("\nNotes: %s\n", company.Notes))
 	}
 
-	promptText.WriteString("\nPlease provide:")
-	promptText.WriteString("\n1. A summary of the relationship with this company")
-	promptText.WriteString("\n2. Key opportunities or risks")
-	promptText.WriteString("\n3. Recommended next actions")
+	promptText.WriteString("\nPlease expand this into a QBR deck outline with speaker notes covering:")
+	promptText.WriteString("\n1. Executive summary of the account this period")
+	promptText.WriteString("\n2. Commercial results: wins, losses, and open pipeline")
+	promptText.WriteString("\n3. Stakeholder map and engagement health")
+	promptText.WriteString("\n4. Renewal risks and expansion opportunities")
+	promptText.WriteString("\n5. Goals and action items for next quarter")
 
 	return &mcp.GetPromptResult{
-		Description: fmt.Sprintf("Overview of %s", company.Name),
+		Description: fmt.Sprintf("QBR brief for %s", company.Name),
 		Messages: []*mcp.PromptMessage{
 			{
 				Role: "user",
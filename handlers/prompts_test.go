@@ -0,0 +1,190 @@
+// ABOUTME: Tests for MCP prompt handlers
+// ABOUTME: Validates prompt assembly from CRM data
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func getPromptText(t *testing.T, handler *PromptHandlers, name string, args map[string]string) string {
+	t.Helper()
+	result, err := handler.GetPrompt(context.Background(), &mcp.GetPromptRequest{
+		Params: &mcp.GetPromptParams{Name: name, Arguments: args},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt(%s) failed: %v", name, err)
+	}
+	return result.Messages[0].Content.(*mcp.TextContent).Text
+}
+
+func TestMeetingPrepPromptByEvent(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	company := &charm.Company{Name: "Acme Corp"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	contact := &charm.Contact{Name: "Alice", Email: "alice@acme.com", CompanyID: &company.ID, CompanyName: company.Name}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	closeDate := time.Now().AddDate(0, 0, 10)
+	deal := &charm.Deal{Title: "Enterprise License", Amount: 5000000, Stage: charm.StageNegotiation, CompanyID: company.ID, CompanyName: company.Name, ExpectedCloseDate: &closeDate}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+
+	if err := client.CreateInteractionLog(&charm.InteractionLog{
+		ContactID:       contact.ID,
+		InteractionType: charm.InteractionMeeting,
+		Timestamp:       time.Now().AddDate(0, 0, -7),
+		Notes:           "Quarterly sync",
+		Metadata:        `{"calendar_event_id":"evt123"}`,
+	}); err != nil {
+		t.Fatalf("failed to log interaction: %v", err)
+	}
+
+	handler := NewPromptHandlers(client)
+	text := getPromptText(t, handler, "meeting-prep", map[string]string{"event_id": "evt123"})
+
+	for _, want := range []string{"Quarterly sync", "## Alice", "Acme Corp", "Enterprise License", "Suggested Talking Points"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestMeetingPrepPromptByAttendees(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Bob", Email: "bob@example.com"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	handler := NewPromptHandlers(client)
+	text := getPromptText(t, handler, "meeting-prep", map[string]string{"attendees": "BOB@example.com, stranger@example.com"})
+
+	if !strings.Contains(text, "## Bob") {
+		t.Errorf("expected attendee section for Bob, got:\n%s", text)
+	}
+	if !strings.Contains(text, "stranger@example.com") {
+		t.Errorf("expected unknown attendee listed, got:\n%s", text)
+	}
+
+	_, err := handler.GetPrompt(context.Background(), &mcp.GetPromptRequest{
+		Params: &mcp.GetPromptParams{Name: "meeting-prep"},
+	})
+	if err == nil {
+		t.Error("expected error without event_id or attendees")
+	}
+}
+
+func TestPromptsLeadWithPinnedFacts(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Carol", Email: "carol@example.com", Notes: "Talked about the roadmap", Pinned: []string{"kids: 2", "likes cycling"}}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	handler := NewPromptHandlers(client)
+	for name, args := range map[string]map[string]string{
+		"contact-summary": {"contact_id": contact.ID.String()},
+		"meeting-prep":    {"attendees": "carol@example.com"},
+	} {
+		text := getPromptText(t, handler, name, args)
+		pinned := strings.Index(text, "  - kids: 2\n  - likes cycling")
+		if pinned < 0 {
+			t.Errorf("%s: expected pinned facts, got:\n%s", name, text)
+			continue
+		}
+		if notes := strings.Index(text, "Talked about the roadmap"); notes >= 0 && notes < pinned {
+			t.Errorf("%s: expected pinned facts before notes, got:\n%s", name, text)
+		}
+	}
+}
+
+func TestQBRPrompt(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	company := &charm.Company{Name: "Globex", Industry: "Manufacturing"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	contact := &charm.Contact{Name: "Hank", Email: "hank@globex.com", CompanyID: &company.ID, CompanyName: company.Name}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	closed := time.Now().AddDate(0, -6, 0)
+	won := &charm.Deal{Title: "Platform Subscription", Amount: 12000000, Stage: charm.StageClosedWon, CompanyID: company.ID, CompanyName: company.Name, ExpectedCloseDate: &closed}
+	if err := client.CreateDeal(won); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	if err := client.CreateDealNote(&charm.DealNote{DealID: won.ID, Content: "Signed two-year term"}); err != nil {
+		t.Fatalf("failed to add note: %v", err)
+	}
+	if err := client.CreateInteractionLog(&charm.InteractionLog{
+		ContactID:       contact.ID,
+		InteractionType: charm.InteractionCall,
+		Timestamp:       time.Now().AddDate(0, 0, -3),
+	}); err != nil {
+		t.Fatalf("failed to log interaction: %v", err)
+	}
+
+	handler := NewPromptHandlers(client)
+	text := getPromptText(t, handler, "qbr", map[string]string{"company_id": company.ID.String()})
+
+	renewal := closed.AddDate(1, 0, 0).Format("2006-01-02")
+	for _, want := range []string{"Quarterly Business Review: Globex", "Platform Subscription", "Signed two-year term", "Hank", "call: 1", "renews " + renewal} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestCompanyOverviewPromptPrimaryContact(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	company := &charm.Company{Name: "Acme Corp"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	for _, name := range []string{"Alice", "Zed"} {
+		if err := client.CreateContact(&charm.Contact{Name: name, CompanyID: &company.ID, CompanyName: company.Name}); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+	zed, err := client.ResolveContactName("Zed", true)
+	if err != nil || zed == nil {
+		t.Fatalf("failed to find Zed: %v", err)
+	}
+
+	_, output, err := NewCompanyHandlers(client).UpdateCompany(context.Background(), nil, UpdateCompanyInput{
+		CompanyID:        company.ID.String(),
+		PrimaryContactID: zed.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCompany failed: %v", err)
+	}
+	if output.PrimaryContactID != zed.ID.String() || output.PrimaryContactName != "Zed" {
+		t.Fatalf("expected Zed as primary contact, got %+v", output)
+	}
+
+	handler := NewPromptHandlers(client)
+	text := getPromptText(t, handler, "company-overview", map[string]string{"company_id": company.ID.String()})
+
+	for _, want := range []string{"Primary contact: Zed", "  - Zed (primary contact)\n  - Alice\n", "what to raise with Zed"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+}
@@ -5,6 +5,8 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
@@ -20,46 +22,95 @@ func NewQueryHandlers(client *charm.Client) *QueryHandlers {
 }
 
 type QueryCRMInput struct {
-	EntityType string                 `json:"entity_type" jsonschema:"Type of entity to query (contact, company, deal, relationship)"`
-	Query      string                 `json:"query,omitempty" jsonschema:"Search query (for name/email/domain)"`
+	Select     string                 `json:"select,omitempty" jsonschema:"Read-only analytics query over the deals, contacts, companies, or interactions view, e.g. SELECT industry, sum(amount) AS pipeline FROM deals WHERE open = true GROUP BY industry ORDER BY pipeline DESC. Supports WHERE (=, !=, <, >, LIKE, IN, IS NULL joined by AND), count/sum/avg/min/max, GROUP BY, ORDER BY, and LIMIT. Dates take 2025-01-31 or an age like 90d. When set, entity_type and the other arguments are ignored"`
+	EntityType string                 `json:"entity_type,omitempty" jsonschema:"Type of entity to query (contact, company, deal, relationship)"`
+	Query      string                 `json:"query,omitempty" jsonschema:"Search query (for name/email/domain); field terms such as company:acme tag:vip last_contacted:<90d stage:negotiation narrow the results"`
 	Filters    map[string]interface{} `json:"filters,omitempty" jsonschema:"Additional filters as key-value pairs"`
-	Limit      int                    `json:"limit,omitempty" jsonschema:"Maximum results to return (default 10)"`
+	Limit      int                    `json:"limit,omitempty" jsonschema:"Maximum results per page (default 10)"`
+	ListOptions
 }
 
 type QueryCRMOutput struct {
 	EntityType string        `json:"entity_type"`
 	Results    []interface{} `json:"results"`
 	Count      int           `json:"count"`
+	Page
+
+	// Set for select queries instead of the above; total counts rows before the LIMIT
+	View    string   `json:"view,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	Rows    [][]any  `json:"rows,omitempty"`
 }
 
 func (h *QueryHandlers) QueryCRM(ctx context.Context, req *mcp.CallToolRequest, input QueryCRMInput) (*mcp.CallToolResult, QueryCRMOutput, error) {
+	if strings.TrimSpace(input.Select) != "" {
+		return h.selectCRM(input.Select)
+	}
+
 	// Set default limit
 	if input.Limit == 0 {
 		input.Limit = 10
 	}
 
+	detail, err := input.detail()
+	if err != nil {
+		return nil, QueryCRMOutput{}, err
+	}
+
+	var results []interface{}
 	switch input.EntityType {
 	case "contact":
-		return h.queryContacts(input)
+		results, err = h.queryContacts(input, detail)
 	case "company":
-		return h.queryCompanies(input)
+		results, err = h.queryCompanies(input, detail)
 	case "deal":
-		return h.queryDeals(input)
+		results, err = h.queryDeals(input, detail)
 	case "relationship":
-		return h.queryRelationships(input)
+		results, err = h.queryRelationships(input, detail)
 	default:
 		return nil, QueryCRMOutput{}, fmt.Errorf("invalid entity_type: %s (valid: contact, company, deal, relationship)", input.EntityType)
 	}
+	if err != nil {
+		return nil, QueryCRMOutput{}, err
+	}
+
+	results, page, err := paginate(results, input.ListOptions, input.Limit)
+	if err != nil {
+		return nil, QueryCRMOutput{}, err
+	}
+
+	return &mcp.CallToolResult{}, QueryCRMOutput{
+		EntityType: input.EntityType,
+		Results:    results,
+		Count:      len(results),
+		Page:       page,
+	}, nil
+}
+
+// selectCRM runs a read-only analytics query. Rows are arrays in column order.
+func (h *QueryHandlers) selectCRM(query string) (*mcp.CallToolResult, QueryCRMOutput, error) {
+	result, err := h.client.RunAnalyticsQuery(query, time.Now())
+	if err != nil {
+		return nil, QueryCRMOutput{}, err
+	}
+	return &mcp.CallToolResult{}, QueryCRMOutput{
+		Results: []interface{}{}, // always an array, as for entity queries
+		View:    result.View,
+		Columns: result.Columns,
+		Rows:    result.Rows,
+		Count:   len(result.Rows),
+		Page:    Page{Total: result.Total},
+	}, nil
 }
 
-func (h *QueryHandlers) queryContacts(input QueryCRMInput) (*mcp.CallToolResult, QueryCRMOutput, error) {
+func (h *QueryHandlers) queryContacts(input QueryCRMInput, detail string) ([]interface{}, error) {
 	// Extract company_id filter if present
 	var companyID *uuid.UUID
 	if input.Filters != nil {
 		if cid, ok := input.Filters["company_id"].(string); ok && cid != "" {
 			id, err := uuid.Parse(cid)
 			if err != nil {
-				return nil, QueryCRMOutput{}, fmt.Errorf("invalid company_id: %w", err)
+				return nil, fmt.Errorf("invalid company_id: %w", err)
 			}
 			companyID = &id
 		}
@@ -69,53 +120,41 @@ func (h *QueryHandlers) queryContacts(input QueryCRMInput) (*mcp.CallToolResult,
 	contacts, err := h.client.ListContacts(&charm.ContactFilter{
 		Query:     input.Query,
 		CompanyID: companyID,
-		Limit:     input.Limit,
 	})
 	if err != nil {
-		return nil, QueryCRMOutput{}, fmt.Errorf("failed to find contacts: %w", err)
+		return nil, fmt.Errorf("failed to find contacts: %w", err)
 	}
 
 	// Convert to interface{} array
 	results := make([]interface{}, len(contacts))
 	for i, c := range contacts {
-		results[i] = contactToOutput(c)
+		results[i] = contactToOutput(c).withDetail(detail)
 	}
 
-	return &mcp.CallToolResult{}, QueryCRMOutput{
-		EntityType: "contact",
-		Results:    results,
-		Count:      len(results),
-	}, nil
+	return results, nil
 }
 
-func (h *QueryHandlers) queryCompanies(input QueryCRMInput) (*mcp.CallToolResult, QueryCRMOutput, error) {
+func (h *QueryHandlers) queryCompanies(input QueryCRMInput, detail string) ([]interface{}, error) {
 	// Query companies using charm client
 	companies, err := h.client.ListCompanies(&charm.CompanyFilter{
 		Query: input.Query,
-		Limit: input.Limit,
 	})
 	if err != nil {
-		return nil, QueryCRMOutput{}, fmt.Errorf("failed to find companies: %w", err)
+		return nil, fmt.Errorf("failed to find companies: %w", err)
 	}
 
 	// Convert to interface{} array
 	results := make([]interface{}, len(companies))
 	for i, c := range companies {
-		results[i] = companyToOutput(c)
+		results[i] = companyToOutput(c).withDetail(detail)
 	}
 
-	return &mcp.CallToolResult{}, QueryCRMOutput{
-		EntityType: "company",
-		Results:    results,
-		Count:      len(results),
-	}, nil
+	return results, nil
 }
 
-func (h *QueryHandlers) queryDeals(input QueryCRMInput) (*mcp.CallToolResult, QueryCRMOutput, error) {
+func (h *QueryHandlers) queryDeals(input QueryCRMInput, detail string) ([]interface{}, error) {
 	// Build filter from input
-	filter := &charm.DealFilter{
-		Limit: input.Limit,
-	}
+	filter := &charm.DealFilter{Query: input.Query}
 
 	if input.Filters != nil {
 		// Extract stage filter
@@ -127,7 +166,7 @@ func (h *QueryHandlers) queryDeals(input QueryCRMInput) (*mcp.CallToolResult, Qu
 		if cid, ok := input.Filters["company_id"].(string); ok && cid != "" {
 			id, err := uuid.Parse(cid)
 			if err != nil {
-				return nil, QueryCRMOutput{}, fmt.Errorf("invalid company_id: %w", err)
+				return nil, fmt.Errorf("invalid company_id: %w", err)
 			}
 			filter.CompanyID = &id
 		}
@@ -150,34 +189,28 @@ func (h *QueryHandlers) queryDeals(input QueryCRMInput) (*mcp.CallToolResult, Qu
 	// Query deals using charm client
 	deals, err := h.client.ListDeals(filter)
 	if err != nil {
-		return nil, QueryCRMOutput{}, fmt.Errorf("failed to find deals: %w", err)
+		return nil, fmt.Errorf("failed to find deals: %w", err)
 	}
 
 	// Convert to interface{} array
 	results := make([]interface{}, len(deals))
 	for i, d := range deals {
-		results[i] = dealToOutput(d)
+		results[i] = dealDetail(h.client, d, detail)
 	}
 
-	return &mcp.CallToolResult{}, QueryCRMOutput{
-		EntityType: "deal",
-		Results:    results,
-		Count:      len(results),
-	}, nil
+	return results, nil
 }
 
-func (h *QueryHandlers) queryRelationships(input QueryCRMInput) (*mcp.CallToolResult, QueryCRMOutput, error) {
+func (h *QueryHandlers) queryRelationships(input QueryCRMInput, detail string) ([]interface{}, error) {
 	// Build filter from input
-	filter := &charm.RelationshipFilter{
-		Limit: input.Limit,
-	}
+	filter := &charm.RelationshipFilter{}
 
 	if input.Filters != nil {
 		// Extract contact_id filter (required for relationships)
 		if cid, ok := input.Filters["contact_id"].(string); ok && cid != "" {
 			id, err := uuid.Parse(cid)
 			if err != nil {
-				return nil, QueryCRMOutput{}, fmt.Errorf("invalid contact_id: %w", err)
+				return nil, fmt.Errorf("invalid contact_id: %w", err)
 			}
 			filter.ContactID = &id
 		}
@@ -190,24 +223,20 @@ func (h *QueryHandlers) queryRelationships(input QueryCRMInput) (*mcp.CallToolRe
 
 	// contact_id is required for relationship queries
 	if filter.ContactID == nil {
-		return nil, QueryCRMOutput{}, fmt.Errorf("contact_id filter is required for relationship queries")
+		return nil, fmt.Errorf("contact_id filter is required for relationship queries")
 	}
 
 	// Query relationships using charm client
 	relationships, err := h.client.ListRelationships(filter)
 	if err != nil {
-		return nil, QueryCRMOutput{}, fmt.Errorf("failed to find relationships: %w", err)
+		return nil, fmt.Errorf("failed to find relationships: %w", err)
 	}
 
 	// Convert to interface{} array
 	results := make([]interface{}, len(relationships))
 	for i, r := range relationships {
-		results[i] = relationshipToOutput(r)
+		results[i] = relationshipToOutput(r).withDetail(detail)
 	}
 
-	return &mcp.CallToolResult{}, QueryCRMOutput{
-		EntityType: "relationship",
-		Results:    results,
-		Count:      len(results),
-	}, nil
+	return results, nil
 }
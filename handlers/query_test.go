@@ -1,9 +1,11 @@
 // ABOUTME: Query tool test suite
-// ABOUTME: Tests universal query_crm tool with filtering across all entity types
+// ABOUTME: Tests universal query_crm tool with filtering across all entity types and select queries
 package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
@@ -387,6 +389,42 @@ func TestQueryCRMInvalidEntityType(t *testing.T) {
 }
 
 // Helper function to check if string contains substring.
+func TestQueryCRMSelect(t *testing.T) {
+	client := charm.NewTestClient(t)
+	acme := &charm.Company{Name: "Acme", Industry: "SaaS"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	for _, deal := range []*charm.Deal{
+		{Title: "Pilot", Amount: 1000000, Stage: charm.StageProposal, CompanyID: acme.ID, CompanyName: "Acme"},
+		{Title: "Expansion", Amount: 250000, Stage: charm.StageNegotiation, CompanyID: acme.ID, CompanyName: "Acme"},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+
+	handler := NewQueryHandlers(client)
+	_, output, err := handler.QueryCRM(context.Background(), nil, QueryCRMInput{
+		Select:     "SELECT industry, sum(amount) AS pipeline FROM deals WHERE open = true GROUP BY industry",
+		EntityType: "ignored",
+	})
+	if err != nil {
+		t.Fatalf("QueryCRM failed: %v", err)
+	}
+	if output.View != "deals" || fmt.Sprint(output.Columns) != "[industry pipeline]" {
+		t.Errorf("unexpected view %s, columns %v", output.View, output.Columns)
+	}
+	if got := fmt.Sprint(output.Rows); got != "[[SaaS 12500]]" || output.Count != 1 || output.Total != 1 {
+		t.Errorf("unexpected rows %s, count %d, total %d", got, output.Count, output.Total)
+	}
+
+	_, _, err = handler.QueryCRM(context.Background(), nil, QueryCRMInput{Select: "DELETE FROM deals"})
+	if !errors.Is(err, charm.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsHelper(s, substr))
 }
@@ -5,6 +5,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
@@ -24,6 +25,7 @@ type LinkContactsInput struct {
 	ContactID2       string `json:"contact_id_2" jsonschema:"Second contact ID (required)"`
 	RelationshipType string `json:"relationship_type,omitempty" jsonschema:"Type of relationship (e.g., colleague, friend, saw_together)"`
 	Context          string `json:"context,omitempty" jsonschema:"Description of how they're connected"`
+	Strength         string `json:"strength,omitempty" jsonschema:"How well they know each other: weak, medium, or strong"`
 }
 
 type RelationshipOutput struct {
@@ -32,8 +34,10 @@ type RelationshipOutput struct {
 	ContactID2       string `json:"contact_id_2"`
 	RelationshipType string `json:"relationship_type,omitempty"`
 	Context          string `json:"context,omitempty"`
-	CreatedAt        string `json:"created_at"`
-	UpdatedAt        string `json:"updated_at"`
+	Strength         string `json:"strength,omitempty"`
+	LastValidatedAt  string `json:"last_validated_at,omitempty"`
+	CreatedAt        string `json:"created_at,omitempty"`
+	UpdatedAt        string `json:"updated_at,omitempty"`
 }
 
 func (h *RelationshipHandlers) LinkContacts(_ context.Context, request *mcp.CallToolRequest, input LinkContactsInput) (*mcp.CallToolResult, RelationshipOutput, error) {
@@ -55,6 +59,10 @@ func (h *RelationshipHandlers) LinkContacts(_ context.Context, request *mcp.Call
 		return nil, RelationshipOutput{}, fmt.Errorf("invalid contact_id_2: %w", err)
 	}
 
+	if err := charm.ValidateRelationshipStrength(input.Strength); err != nil {
+		return nil, RelationshipOutput{}, err
+	}
+
 	// Get contact names for denormalization
 	contact1, err := h.client.GetContact(contactID1)
 	if err != nil {
@@ -73,6 +81,7 @@ func (h *RelationshipHandlers) LinkContacts(_ context.Context, request *mcp.Call
 		Contact2Name:     contact2.Name,
 		RelationshipType: input.RelationshipType,
 		Context:          input.Context,
+		Strength:         input.Strength,
 	}
 
 	if err := h.client.CreateRelationship(relationship); err != nil {
@@ -85,6 +94,7 @@ func (h *RelationshipHandlers) LinkContacts(_ context.Context, request *mcp.Call
 type FindContactRelationshipsInput struct {
 	ContactID        string `json:"contact_id" jsonschema:"Contact ID (required)"`
 	RelationshipType string `json:"relationship_type,omitempty" jsonschema:"Filter by relationship type"`
+	ListOptions
 }
 
 type ContactBriefOutput struct {
@@ -98,12 +108,15 @@ type RelationshipWithContactsOutput struct {
 	Contact2         ContactBriefOutput `json:"contact_2"`
 	RelationshipType string             `json:"relationship_type,omitempty"`
 	Context          string             `json:"context,omitempty"`
-	CreatedAt        string             `json:"created_at"`
-	UpdatedAt        string             `json:"updated_at"`
+	Strength         string             `json:"strength,omitempty"`
+	LastValidatedAt  string             `json:"last_validated_at,omitempty"`
+	CreatedAt        string             `json:"created_at,omitempty"`
+	UpdatedAt        string             `json:"updated_at,omitempty"`
 }
 
 type FindContactRelationshipsOutput struct {
 	Relationships []RelationshipWithContactsOutput `json:"relationships"`
+	Page
 }
 
 func (h *RelationshipHandlers) FindContactRelationships(_ context.Context, request *mcp.CallToolRequest, input FindContactRelationshipsInput) (*mcp.CallToolResult, FindContactRelationshipsOutput, error) {
@@ -116,6 +129,11 @@ func (h *RelationshipHandlers) FindContactRelationships(_ context.Context, reque
 		return nil, FindContactRelationshipsOutput{}, fmt.Errorf("invalid contact_id: %w", err)
 	}
 
+	detail, err := input.detail()
+	if err != nil {
+		return nil, FindContactRelationshipsOutput{}, err
+	}
+
 	relationships, err := h.client.ListRelationshipsForContact(contactID)
 	if err != nil {
 		return nil, FindContactRelationshipsOutput{}, fmt.Errorf("failed to find relationships: %w", err)
@@ -147,12 +165,19 @@ func (h *RelationshipHandlers) FindContactRelationships(_ context.Context, reque
 			},
 			RelationshipType: rel.RelationshipType,
 			Context:          rel.Context,
+			Strength:         rel.Strength,
+			LastValidatedAt:  formatValidatedAt(rel),
 			CreatedAt:        rel.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:        rel.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+		}.withDetail(detail)
 	}
 
-	return nil, FindContactRelationshipsOutput{Relationships: result}, nil
+	result, page, err := paginate(result, input.ListOptions, 0)
+	if err != nil {
+		return nil, FindContactRelationshipsOutput{}, err
+	}
+
+	return nil, FindContactRelationshipsOutput{Relationships: result, Page: page}, nil
 }
 
 type RemoveRelationshipInput struct {
@@ -188,6 +213,8 @@ type UpdateRelationshipInput struct {
 	RelationshipID   string `json:"relationship_id" jsonschema:"Relationship ID (required)"`
 	RelationshipType string `json:"relationship_type,omitempty" jsonschema:"Updated relationship type"`
 	Context          string `json:"context,omitempty" jsonschema:"Updated relationship context"`
+	Strength         string `json:"strength,omitempty" jsonschema:"Updated strength: weak, medium, or strong (also confirms the relationship)"`
+	Validate         bool   `json:"validate,omitempty" jsonschema:"Confirm the relationship still holds, restarting its strength decay"`
 }
 
 type UpdateRelationshipOutput struct {
@@ -205,6 +232,10 @@ func (h *RelationshipHandlers) UpdateRelationship(_ context.Context, request *mc
 		return nil, UpdateRelationshipOutput{}, fmt.Errorf("invalid relationship_id: %w", err)
 	}
 
+	if err := charm.ValidateRelationshipStrength(input.Strength); err != nil {
+		return nil, UpdateRelationshipOutput{}, err
+	}
+
 	// Get existing relationship
 	rel, err := h.client.GetRelationship(relationshipID)
 	if err != nil {
@@ -218,6 +249,13 @@ func (h *RelationshipHandlers) UpdateRelationship(_ context.Context, request *mc
 	if input.Context != "" {
 		rel.Context = input.Context
 	}
+	if input.Strength != "" {
+		rel.Strength = input.Strength
+	}
+	if input.Strength != "" || input.Validate {
+		now := time.Now()
+		rel.LastValidatedAt = &now
+	}
 
 	if err := h.client.UpdateRelationship(rel); err != nil {
 		return nil, UpdateRelationshipOutput{}, fmt.Errorf("failed to update relationship: %w", err)
@@ -236,11 +274,43 @@ func relationshipToOutput(relationship *charm.Relationship) RelationshipOutput {
 		ContactID2:       relationship.ContactID2.String(),
 		RelationshipType: relationship.RelationshipType,
 		Context:          relationship.Context,
+		Strength:         relationship.Strength,
+		LastValidatedAt:  formatValidatedAt(relationship),
 		CreatedAt:        relationship.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:        relationship.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
 
+// formatValidatedAt formats when a relationship was last confirmed, if ever.
+func formatValidatedAt(relationship *charm.Relationship) string {
+	if relationship.LastValidatedAt == nil {
+		return ""
+	}
+	return relationship.LastValidatedAt.Format("2006-01-02T15:04:05Z07:00")
+}
+
+// withDetail trims a relationship to the requested detail level.
+func (o RelationshipOutput) withDetail(detail string) RelationshipOutput {
+	switch detail {
+	case DetailIDs:
+		return RelationshipOutput{ID: o.ID, ContactID1: o.ContactID1, ContactID2: o.ContactID2}
+	case DetailSummary:
+		o.CreatedAt, o.UpdatedAt = "", ""
+	}
+	return o
+}
+
+// withDetail trims a relationship to the requested detail level.
+func (o RelationshipWithContactsOutput) withDetail(detail string) RelationshipWithContactsOutput {
+	switch detail {
+	case DetailIDs:
+		return RelationshipWithContactsOutput{ID: o.ID, Contact1: o.Contact1, Contact2: o.Contact2}
+	case DetailSummary:
+		o.CreatedAt, o.UpdatedAt = "", ""
+	}
+	return o
+}
+
 // Legacy map-based functions for tests.
 func (h *RelationshipHandlers) LinkContacts_Legacy(args map[string]interface{}) (interface{}, error) {
 	contactID1Str, ok := args["contact_id_1"].(string)
@@ -1,5 +1,5 @@
 // ABOUTME: MCP resource handlers for exposing CRM data
-// ABOUTME: Provides read-only access to contacts, companies, and deals via URI
+// ABOUTME: Provides read-only access to contacts, companies, deals, and the pipeline via crm:// URIs
 package handlers
 
 import (
@@ -32,30 +32,33 @@ func (h *ResourceHandlers) ReadResource(ctx context.Context, request *mcp.ReadRe
 	path := strings.TrimPrefix(uri, "crm://")
 	parts := strings.Split(path, "/")
 
-	switch parts[0] {
-	case "contacts":
-		if len(parts) == 1 {
-			return h.readAllContacts()
-		}
+	switch {
+	case path == "contacts":
+		return h.readAllContacts()
+	case parts[0] == "contacts" && len(parts) == 2:
 		return h.readContact(parts[1])
+	case parts[0] == "contacts" && len(parts) == 3 && parts[2] == "interactions":
+		return h.readContactInteractions(parts[1])
 
-	case "companies":
-		if len(parts) == 1 {
-			return h.readAllCompanies()
-		}
+	case path == "companies":
+		return h.readAllCompanies()
+	case parts[0] == "companies" && len(parts) == 2:
 		return h.readCompany(parts[1])
-
-	case "deals":
-		if len(parts) == 1 {
-			return h.readAllDeals()
-		}
+	case parts[0] == "companies" && len(parts) == 3 && parts[2] == "overview":
+		return h.readCompanyOverview(parts[1])
+
+	case path == "deals":
+		return h.readAllDeals()
+	case path == "deals/pipeline":
+		return h.readDealPipeline()
+	case parts[0] == "deals" && len(parts) == 2:
 		return h.readDeal(parts[1])
 
-	case "pipeline":
+	case path == "pipeline":
 		return h.readPipeline()
 
 	default:
-		return nil, fmt.Errorf("unknown resource: %s", parts[0])
+		return nil, mcp.ResourceNotFoundError(uri)
 	}
 }
 
@@ -104,6 +107,36 @@ func (h *ResourceHandlers) readContact(idStr string) (*mcp.ReadResourceResult, e
 	}}, nil
 }
 
+func (h *ResourceHandlers) readContactInteractions(idStr string) (*mcp.ReadResourceResult, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contact ID: %w", err)
+	}
+
+	if _, err := h.client.GetContact(id); err != nil {
+		return nil, fmt.Errorf("failed to fetch contact: %w", err)
+	}
+
+	// Most recent first
+	logs, err := h.client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &id, Limit: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interactions: %w", err)
+	}
+
+	data, err := json.MarshalIndent(logs, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal interactions: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+		{
+			URI:      fmt.Sprintf("crm://contacts/%s/interactions", idStr),
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}}, nil
+}
+
 func (h *ResourceHandlers) readAllCompanies() (*mcp.ReadResourceResult, error) {
 	companies, err := h.client.ListCompanies(&charm.CompanyFilter{Limit: 1000})
 	if err != nil {
@@ -163,6 +196,33 @@ func (h *ResourceHandlers) readCompany(idStr string) (*mcp.ReadResourceResult, e
 	}}, nil
 }
 
+// readCompanyOverview returns the same overview the company-overview prompt
+// starts with, as Markdown-friendly text.
+func (h *ResourceHandlers) readCompanyOverview(idStr string) (*mcp.ReadResourceResult, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	company, err := h.client.GetCompany(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch company: %w", err)
+	}
+
+	var out strings.Builder
+	if _, err := writeCompanyOverview(&out, h.client, company); err != nil {
+		return nil, err
+	}
+
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+		{
+			URI:      fmt.Sprintf("crm://companies/%s/overview", idStr),
+			MIMEType: "text/markdown",
+			Text:     out.String(),
+		},
+	}}, nil
+}
+
 func (h *ResourceHandlers) readAllDeals() (*mcp.ReadResourceResult, error) {
 	deals, err := h.client.ListDeals(&charm.DealFilter{Limit: 1000})
 	if err != nil {
@@ -258,3 +318,72 @@ func (h *ResourceHandlers) readPipeline() (*mcp.ReadResourceResult, error) {
 		},
 	}}, nil
 }
+
+// pipelineStage is one stage of the crm://deals/pipeline resource.
+type pipelineStage struct {
+	Stage         string              `json:"stage"`
+	Count         int                 `json:"count"`
+	Amount        int64               `json:"total_amount"`   // in cents
+	ExpectedValue int64               `json:"expected_value"` // amount × win probability, in cents
+	Deals         []pipelineStageDeal `json:"deals"`
+}
+
+type pipelineStageDeal struct {
+	ID          string `json:"id"`
+	URI         string `json:"uri"`
+	Title       string `json:"title"`
+	CompanyName string `json:"company_name,omitempty"`
+	Amount      int64  `json:"amount,omitempty"`
+	Currency    string `json:"currency,omitempty"`
+	Probability int    `json:"probability"`
+}
+
+// readDealPipeline lists every stage in pipeline order with its deals, most
+// recently active first, so a client can browse the pipeline in one read.
+func (h *ResourceHandlers) readDealPipeline() (*mcp.ReadResourceResult, error) {
+	deals, err := h.client.ListDeals(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deals: %w", err)
+	}
+
+	stages := make([]*pipelineStage, 0, len(charm.DealStages))
+	byStage := make(map[string]*pipelineStage)
+	for _, stage := range charm.DealStages {
+		byStage[stage] = &pipelineStage{Stage: stage, Deals: []pipelineStageDeal{}}
+		stages = append(stages, byStage[stage])
+	}
+
+	for _, deal := range deals {
+		stage, ok := byStage[deal.Stage]
+		if !ok {
+			stage = &pipelineStage{Stage: deal.Stage, Deals: []pipelineStageDeal{}}
+			byStage[deal.Stage] = stage
+			stages = append(stages, stage)
+		}
+		stage.Count++
+		stage.Amount += deal.Amount
+		stage.ExpectedValue += deal.ExpectedValue()
+		stage.Deals = append(stage.Deals, pipelineStageDeal{
+			ID:          deal.ID.String(),
+			URI:         fmt.Sprintf("crm://deals/%s", deal.ID),
+			Title:       deal.Title,
+			CompanyName: deal.CompanyName,
+			Amount:      deal.Amount,
+			Currency:    deal.Currency,
+			Probability: deal.WinProbability(),
+		})
+	}
+
+	data, err := json.MarshalIndent(stages, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pipeline: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+		{
+			URI:      "crm://deals/pipeline",
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}}, nil
+}
@@ -0,0 +1,107 @@
+// ABOUTME: Tests for MCP resource handlers
+// ABOUTME: Validates crm:// URI routing, the pipeline resource, and company overviews
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func readResource(t *testing.T, handler *ResourceHandlers, uri string) *mcp.ResourceContents {
+	t.Helper()
+	result, err := handler.ReadResource(context.Background(), &mcp.ReadResourceRequest{
+		Params: &mcp.ReadResourceParams{URI: uri},
+	})
+	if err != nil {
+		t.Fatalf("ReadResource(%s) failed: %v", uri, err)
+	}
+	return result.Contents[0]
+}
+
+func TestReadResources(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	company := &charm.Company{Name: "Acme Corp", Industry: "Software"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	contact := &charm.Contact{Name: "Alice", Email: "alice@acme.com", CompanyID: &company.ID, CompanyName: company.Name}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	company.SetPrimaryContact(contact)
+	if err := client.UpdateCompany(company); err != nil {
+		t.Fatalf("failed to update company: %v", err)
+	}
+	if err := client.CreateInteractionLog(&charm.InteractionLog{ContactID: contact.ID, InteractionType: charm.InteractionCall, Notes: "Intro call"}); err != nil {
+		t.Fatalf("failed to log interaction: %v", err)
+	}
+	for _, deal := range []*charm.Deal{
+		{Title: "Renewal", Amount: 1000000, Currency: "USD", Stage: charm.StageNegotiation, CompanyID: company.ID, CompanyName: company.Name},
+		{Title: "Pilot", Amount: 200000, Currency: "USD", Stage: charm.StageProspecting, CompanyID: company.ID, CompanyName: company.Name},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("failed to create deal: %v", err)
+		}
+	}
+
+	handler := NewResourceHandlers(client)
+
+	t.Run("pipeline", func(t *testing.T) {
+		contents := readResource(t, handler, "crm://deals/pipeline")
+		var stages []pipelineStage
+		if err := json.Unmarshal([]byte(contents.Text), &stages); err != nil {
+			t.Fatalf("invalid pipeline JSON: %v", err)
+		}
+		if len(stages) != len(charm.DealStages) || stages[0].Stage != charm.StageProspecting {
+			t.Fatalf("expected every stage in pipeline order, got %+v", stages)
+		}
+		negotiation := stages[3]
+		if negotiation.Count != 1 || negotiation.ExpectedValue != 750000 || negotiation.Deals[0].Title != "Renewal" {
+			t.Errorf("unexpected negotiation stage: %+v", negotiation)
+		}
+		if !strings.HasPrefix(negotiation.Deals[0].URI, "crm://deals/") {
+			t.Errorf("expected a deal URI, got %q", negotiation.Deals[0].URI)
+		}
+	})
+
+	t.Run("company overview", func(t *testing.T) {
+		contents := readResource(t, handler, "crm://companies/"+company.ID.String()+"/overview")
+		if contents.MIMEType != "text/markdown" {
+			t.Errorf("expected text/markdown, got %s", contents.MIMEType)
+		}
+		for _, want := range []string{"Complete overview of: Acme Corp", "Primary contact: Alice", "Renewal", "Pilot"} {
+			if !strings.Contains(contents.Text, want) {
+				t.Errorf("expected overview to contain %q, got:\n%s", want, contents.Text)
+			}
+		}
+		if strings.Contains(contents.Text, "Please provide") {
+			t.Errorf("expected no prompt instructions in the resource, got:\n%s", contents.Text)
+		}
+	})
+
+	t.Run("contact interactions", func(t *testing.T) {
+		contents := readResource(t, handler, "crm://contacts/"+contact.ID.String()+"/interactions")
+		var logs []*charm.InteractionLog
+		if err := json.Unmarshal([]byte(contents.Text), &logs); err != nil {
+			t.Fatalf("invalid interactions JSON: %v", err)
+		}
+		if len(logs) != 1 || logs[0].Notes != "Intro call" {
+			t.Errorf("expected the intro call, got %+v", logs)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := handler.ReadResource(context.Background(), &mcp.ReadResourceRequest{
+			Params: &mcp.ReadResourceParams{URI: "crm://companies/" + company.ID.String() + "/secrets"},
+		})
+		if err == nil {
+			t.Error("expected an error for an unknown resource")
+		}
+	})
+}
@@ -0,0 +1,194 @@
+// ABOUTME: CRM-wide search MCP tool handler
+// ABOUTME: Implements search_crm by ranking contacts, companies, and deals in memory
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/db"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type SearchHandlers struct {
+	client *charm.Client
+}
+
+func NewSearchHandlers(client *charm.Client) *SearchHandlers {
+	return &SearchHandlers{client: client}
+}
+
+type SearchCRMInput struct {
+	Query string   `json:"query" jsonschema:"Words to find; each matches the start of a word in names, emails, notes, and other fields. Field terms narrow the results, e.g. company:acme tag:vip last_contacted:<90d stage:negotiation amount:>10k (required)"`
+	Types []string `json:"types,omitempty" jsonschema:"Only these record types: contact, company, deal"`
+	Limit int      `json:"limit,omitempty" jsonschema:"Maximum number of results (default 20)"`
+}
+
+type SearchResultOutput struct {
+	Type   string  `json:"type"`
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Detail string  `json:"detail,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+type SearchCRMOutput struct {
+	Results []SearchResultOutput `json:"results"`
+}
+
+func (h *SearchHandlers) SearchCRM(_ context.Context, request *mcp.CallToolRequest, input SearchCRMInput) (*mcp.CallToolResult, SearchCRMOutput, error) {
+	if strings.TrimSpace(input.Query) == "" {
+		return nil, SearchCRMOutput{}, fmt.Errorf("query is required")
+	}
+	limit := input.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	results, err := SearchCRM(h.client, input.Query, input.Types, limit)
+	if err != nil {
+		return nil, SearchCRMOutput{}, err
+	}
+	return nil, SearchCRMOutput{Results: results}, nil
+}
+
+// searchTypes are the record types SearchCRM can return.
+var searchTypes = []string{charm.EntityContact, charm.EntityCompany, charm.EntityDeal}
+
+// searchRecord is a result row with the text it is ranked on: its name,
+// email, notes, and other fields, in db.ScoreSearchFields order.
+type searchRecord struct {
+	result SearchResultOutput
+	fields [4]string
+}
+
+// SearchCRM ranks the contacts, companies, and deals matching query, best
+// first, scoring them the way the db search index does. Field terms in query
+// (see charm.ParseSearchQuery) pick which records are considered; with no
+// other words, all of those are returned in list order.
+func SearchCRM(client *charm.Client, query string, types []string, limit int) ([]SearchResultOutput, error) {
+	parsed, err := charm.ParseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var wanted []string
+	for _, recordType := range types {
+		normalized := strings.ToLower(strings.TrimSpace(recordType))
+		if !slices.Contains(searchTypes, normalized) {
+			return nil, fmt.Errorf("unknown record type %q (use contact, company, or deal)", recordType)
+		}
+		wanted = append(wanted, normalized)
+	}
+	if len(wanted) == 0 {
+		wanted = searchTypes
+	}
+
+	records, err := loadSearchRecords(client, wanted, parsed.Filters())
+	if err != nil {
+		return nil, err
+	}
+
+	var output []SearchResultOutput
+	if strings.TrimSpace(parsed.Text) == "" {
+		for _, record := range records {
+			output = append(output, record.result)
+		}
+	} else {
+		for _, record := range records {
+			if score := db.ScoreSearchFields(parsed.Text, record.fields); score > 0 {
+				record.result.Score = score
+				output = append(output, record.result)
+			}
+		}
+		sort.SliceStable(output, func(i, j int) bool {
+			if output[i].Score != output[j].Score {
+				return output[i].Score > output[j].Score
+			}
+			return strings.ToLower(output[i].Name) < strings.ToLower(output[j].Name)
+		})
+	}
+	if output == nil {
+		output = []SearchResultOutput{}
+	}
+	if limit > 0 && len(output) > limit {
+		output = output[:limit]
+	}
+	return output, nil
+}
+
+// loadSearchRecords returns the records of the given types that match the
+// filters query, in list order.
+func loadSearchRecords(client *charm.Client, types []string, filters string) ([]searchRecord, error) {
+	var records []searchRecord
+	join := func(values ...string) string {
+		return strings.Join(strings.Fields(strings.Join(values, " ")), " ")
+	}
+
+	if slices.Contains(types, charm.EntityContact) {
+		contacts, err := client.ListContacts(&charm.ContactFilter{Query: filters, IncludeArchived: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		for _, contact := range contacts {
+			detail := contact.Email
+			if detail == "" {
+				detail = contact.CompanyName
+			}
+			if contact.ArchivedAt != nil {
+				detail = strings.TrimSpace(detail + " (archived)")
+			}
+			records = append(records, searchRecord{
+				result: SearchResultOutput{Type: charm.EntityContact, ID: contact.ID.String(), Name: contact.Name, Detail: detail},
+				fields: [4]string{contact.Name, contact.Email, contact.Notes, join(
+					contact.Phone, contact.Title, contact.CompanyName,
+					strings.Join(contact.Pinned, "; "), contact.MetVia, contact.MetAt,
+				)},
+			})
+		}
+	}
+
+	if slices.Contains(types, charm.EntityCompany) {
+		companies, err := client.ListCompanies(&charm.CompanyFilter{Query: filters})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list companies: %w", err)
+		}
+		for _, company := range companies {
+			records = append(records, searchRecord{
+				result: SearchResultOutput{Type: charm.EntityCompany, ID: company.ID.String(), Name: company.Name, Detail: company.Domain},
+				fields: [4]string{company.Name, "", company.Notes, join(company.Domain, company.Industry)},
+			})
+		}
+	}
+
+	if slices.Contains(types, charm.EntityDeal) {
+		deals, err := client.ListDeals(&charm.DealFilter{Query: filters})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deals: %w", err)
+		}
+		notes, err := client.DealNotesByDeal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deal notes: %w", err)
+		}
+		for _, deal := range deals {
+			contents := make([]string, len(notes[deal.ID]))
+			for i, note := range notes[deal.ID] {
+				contents[i] = note.Content
+			}
+			detail := strings.TrimSpace(fmt.Sprintf("%s (%s)", deal.CompanyName, deal.Stage))
+			records = append(records, searchRecord{
+				result: SearchResultOutput{Type: charm.EntityDeal, ID: deal.ID.String(), Name: deal.Title, Detail: detail},
+				fields: [4]string{deal.Title, "", join(contents...), join(
+					deal.Stage, deal.CompanyName, deal.ContactName,
+					deal.ReferrerName, deal.Source, deal.CloseReason,
+				)},
+			})
+		}
+	}
+
+	return records, nil
+}
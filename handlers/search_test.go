@@ -0,0 +1,82 @@
+// ABOUTME: Tests for the search_crm MCP tool
+// ABOUTME: Verifies ranking across record types, deal note matches, and type filters
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestSearchCRM(t *testing.T) {
+	client := charm.NewTestClient(t)
+	company := &charm.Company{Name: "Acme Robotics", Domain: "acme.com"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	for _, contact := range []*charm.Contact{
+		{Name: "Alice Robertson", Email: "alice@acme.com"},
+		{Name: "Bob Smith", Notes: "Builds robots for fun"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+	deal := &charm.Deal{Title: "Pilot", CompanyID: company.ID, CompanyName: company.Name, Stage: charm.StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	if err := client.CreateDealNote(&charm.DealNote{DealID: deal.ID, Content: "- [ ] Security review"}); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	handler := NewSearchHandlers(client)
+	search := func(input SearchCRMInput) []string {
+		t.Helper()
+		_, output, err := handler.SearchCRM(context.Background(), nil, input)
+		if err != nil {
+			t.Fatalf("SearchCRM(%+v) failed: %v", input, err)
+		}
+		var got []string
+		for _, result := range output.Results {
+			got = append(got, result.Type+":"+result.Name)
+		}
+		return got
+	}
+	expect := func(input SearchCRMInput, want ...string) {
+		t.Helper()
+		got := search(input)
+		if len(got) != len(want) {
+			t.Fatalf("SearchCRM(%+v): expected %v, got %v", input, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("SearchCRM(%+v): expected %v, got %v", input, want, got)
+				break
+			}
+		}
+	}
+
+	// Name prefixes tie and sort by name; the deal matches on its company name, Bob on notes
+	expect(SearchCRMInput{Query: "rob"}, "company:Acme Robotics", "contact:Alice Robertson", "deal:Pilot", "contact:Bob Smith")
+	expect(SearchCRMInput{Query: "security"}, "deal:Pilot")
+	expect(SearchCRMInput{Query: "acme", Types: []string{"deal"}}, "deal:Pilot")
+	expect(SearchCRMInput{Query: "robertson", Limit: 1}, "contact:Alice Robertson")
+
+	// Field terms narrow the records searched, or list them when alone
+	expect(SearchCRMInput{Query: "rob stage:prospecting"}, "deal:Pilot")
+	expect(SearchCRMInput{Query: "company:acme"}, "deal:Pilot")
+	expect(SearchCRMInput{Query: "stage:negotiation"})
+
+	if _, _, err := handler.SearchCRM(context.Background(), nil, SearchCRMInput{Query: "acme", Types: []string{"event"}}); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+	if _, _, err := handler.SearchCRM(context.Background(), nil, SearchCRMInput{}); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+	if _, _, err := handler.SearchCRM(context.Background(), nil, SearchCRMInput{Query: "last_activity:<soon"}); err == nil {
+		t.Error("expected an error for a bad field value")
+	}
+}
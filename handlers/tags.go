@@ -0,0 +1,118 @@
+// ABOUTME: Tag MCP tool handlers
+// ABOUTME: Implements add_tag, remove_tag, and find_by_tag for contacts, companies, and deals
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type TagHandlers struct {
+	client *charm.Client
+}
+
+func NewTagHandlers(client *charm.Client) *TagHandlers {
+	return &TagHandlers{client: client}
+}
+
+type TagInput struct {
+	ID  string `json:"id" jsonschema:"ID of the contact, company, or deal (required)"`
+	Tag string `json:"tag" jsonschema:"Tag, e.g. ai or investor; separate several with commas (required)"`
+}
+
+type TaggedOutput struct {
+	EntityType string   `json:"entity_type"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Tags       []string `json:"tags"`
+}
+
+func (h *TagHandlers) AddTag(_ context.Context, request *mcp.CallToolRequest, input TagInput) (*mcp.CallToolResult, TaggedOutput, error) {
+	return h.retag(input, true)
+}
+
+func (h *TagHandlers) RemoveTag(_ context.Context, request *mcp.CallToolRequest, input TagInput) (*mcp.CallToolResult, TaggedOutput, error) {
+	return h.retag(input, false)
+}
+
+func (h *TagHandlers) retag(input TagInput, add bool) (*mcp.CallToolResult, TaggedOutput, error) {
+	if input.ID == "" {
+		return nil, TaggedOutput{}, fmt.Errorf("id is required")
+	}
+	id, err := uuid.Parse(input.ID)
+	if err != nil {
+		return nil, TaggedOutput{}, fmt.Errorf("invalid id: %w", err)
+	}
+	tags := charm.ParseTags(input.Tag)
+	if len(tags) == 0 {
+		return nil, TaggedOutput{}, fmt.Errorf("tag is required")
+	}
+
+	var obj *charm.TaggedObject
+	if add {
+		obj, err = h.client.UpdateTags(id, tags, nil)
+	} else {
+		obj, err = h.client.UpdateTags(id, nil, tags)
+	}
+	if err != nil {
+		return nil, TaggedOutput{}, err
+	}
+	return nil, taggedToOutput(obj), nil
+}
+
+type FindByTagInput struct {
+	Tag        string `json:"tag" jsonschema:"Tag to look for (required)"`
+	EntityType string `json:"entity_type,omitempty" jsonschema:"Only this type: contact, company, or deal (default: all)"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"Maximum number of results (default 50)"`
+}
+
+type FindByTagOutput struct {
+	Results []TaggedOutput `json:"results"`
+	Count   int            `json:"count"`
+}
+
+func (h *TagHandlers) FindByTag(_ context.Context, request *mcp.CallToolRequest, input FindByTagInput) (*mcp.CallToolResult, FindByTagOutput, error) {
+	if strings.TrimSpace(input.Tag) == "" {
+		return nil, FindByTagOutput{}, fmt.Errorf("tag is required")
+	}
+	var entityTypes []string
+	if input.EntityType != "" {
+		entityTypes = []string{input.EntityType}
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	found, err := h.client.FindByTag(input.Tag, entityTypes...)
+	if err != nil {
+		return nil, FindByTagOutput{}, err
+	}
+	if len(found) > limit {
+		found = found[:limit]
+	}
+
+	results := make([]TaggedOutput, len(found))
+	for i, obj := range found {
+		results[i] = taggedToOutput(obj)
+	}
+	return nil, FindByTagOutput{Results: results, Count: len(results)}, nil
+}
+
+func taggedToOutput(obj *charm.TaggedObject) TaggedOutput {
+	tags := obj.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return TaggedOutput{
+		EntityType: obj.EntityType,
+		ID:         obj.ID.String(),
+		Name:       obj.Name,
+		Tags:       tags,
+	}
+}
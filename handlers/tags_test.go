@@ -0,0 +1,60 @@
+// ABOUTME: Tests for the tag MCP tools
+// ABOUTME: Validates add_tag, remove_tag, and find_by_tag across contacts, companies, and deals
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestTagHandlers(t *testing.T) {
+	client := charm.NewTestClient(t)
+	handler := NewTagHandlers(client)
+	ctx := context.Background()
+
+	contact := &charm.Contact{Name: "Ada"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	company := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+
+	_, out, err := handler.AddTag(ctx, nil, TagInput{ID: contact.ID.String(), Tag: "Investor, ai"})
+	if err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	if out.EntityType != charm.EntityContact || !reflect.DeepEqual(out.Tags, []string{"ai", "investor"}) {
+		t.Errorf("unexpected add_tag output: %+v", out)
+	}
+	if _, _, err := handler.AddTag(ctx, nil, TagInput{ID: company.ID.String(), Tag: "ai"}); err != nil {
+		t.Fatalf("AddTag on company failed: %v", err)
+	}
+
+	_, out, err = handler.RemoveTag(ctx, nil, TagInput{ID: contact.ID.String(), Tag: "ai"})
+	if err != nil {
+		t.Fatalf("RemoveTag failed: %v", err)
+	}
+	if !reflect.DeepEqual(out.Tags, []string{"investor"}) {
+		t.Errorf("expected only investor left, got %v", out.Tags)
+	}
+
+	_, found, err := handler.FindByTag(ctx, nil, FindByTagInput{Tag: "ai"})
+	if err != nil {
+		t.Fatalf("FindByTag failed: %v", err)
+	}
+	if found.Count != 1 || found.Results[0].Name != "Acme" {
+		t.Errorf("expected only Acme tagged ai, got %+v", found.Results)
+	}
+
+	if _, _, err := handler.AddTag(ctx, nil, TagInput{ID: contact.ID.String(), Tag: " , "}); err == nil {
+		t.Error("expected an error without a tag")
+	}
+	if _, _, err := handler.FindByTag(ctx, nil, FindByTagInput{Tag: "ai", EntityType: "task"}); err == nil {
+		t.Error("expected an error for an entity type that can't be tagged")
+	}
+}
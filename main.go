@@ -7,12 +7,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/harperreed/pagen/charm"
 	"github.com/harperreed/pagen/cli"
 	"github.com/harperreed/pagen/tui"
-	"github.com/harperreed/pagen/web"
 	"github.com/joho/godotenv"
 )
 
@@ -26,6 +27,11 @@ func main() {
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	showHelp := flag.Bool("help", false, "Show help and exit")
 	initOnly := flag.Bool("init", false, "Initialize Charm KV and exit")
+	headless := flag.Bool("headless", isTruthy(os.Getenv("PAGEN_HEADLESS")), "Never start interactive interfaces (for containers and services)")
+	timing := flag.Bool("timing", false, "Report time spent opening the client, querying KV, and rendering")
+	strictDeprecations := flag.Bool("strict-deprecations", isTruthy(os.Getenv("PAGEN_STRICT_DEPRECATIONS")), "Exit instead of running renamed commands under their old names")
+	jsonErrors := flag.Bool("json", false, "Report errors as JSON on stderr")
+	profile := flag.String("profile", os.Getenv("PAGEN_PROFILE"), "Use this profile's database, config, and sync tokens")
 
 	// Parse global flags but don't fail on unknown (for subcommands)
 	_ = flag.CommandLine.Parse(os.Args[1:])
@@ -45,6 +51,31 @@ func main() {
 	// Get remaining args after flags
 	args := flag.Args()
 
+	// Failures exit with a status per error code (see cli.ExitStatus), and
+	// are reported as JSON for --json before or after the command
+	asJSON := *jsonErrors || cli.WantsJSON(args)
+	fail := func(err error) { cli.Fail(err, asJSON) }
+
+	// The profile picks the database and config every client opens
+	if err := charm.SetProfile(*profile); err != nil {
+		fail(err)
+	}
+
+	// Old command names keep working with a migration hint, or exit with a
+	// distinct code when removed (see pagen deprecations)
+	if deprecation := cli.FindDeprecation(args); deprecation != nil {
+		if code := deprecation.Warn(os.Stderr, *strictDeprecations); code != 0 {
+			os.Exit(code)
+		}
+		args = deprecation.Rewrite(args)
+	}
+
+	// Containers have no terminal: refuse interactive modes instead of hanging
+	if *headless && (len(args) == 0 || args[0] == "shell") {
+		fmt.Fprintln(os.Stderr, "Error: interactive mode is disabled in headless mode; run a command such as `pagen web`")
+		os.Exit(2)
+	}
+
 	// If no command specified, show welcome banner and launch TUI
 	if len(args) == 0 {
 		// Display ASCII art welcome banner
@@ -62,20 +93,31 @@ func main() {
 
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
 		fmt.Println("  🔐 Loading interactive interface...")
 		fmt.Println()
 
-		tuiModel := tui.NewModel(client)
+		tuiModel := tui.NewModel(client.ForActor(charm.EntryTUI))
 		p := tea.NewProgram(tuiModel, tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
-			log.Fatalf("TUI error: %v", err)
+			fail(fmt.Errorf("TUI error: %w", err))
 		}
 		return
 	}
 
+	// Commands share the global client, so observing it here times them all
+	if *timing {
+		t := cli.NewTiming()
+		openStart := time.Now()
+		if client, err := charm.GetClient(); err == nil {
+			client.SetQueryObserver(t.ObserveQuery)
+		}
+		t.Opened(time.Since(openStart))
+		defer t.Report(os.Stderr)
+	}
+
 	// Route to top-level command
 	command := args[0]
 	commandArgs := args[1:]
@@ -85,18 +127,18 @@ func main() {
 		// MCP server uses Charm KV
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
 		if err := cli.MCPCommand(client); err != nil {
-			log.Fatalf("MCP server failed: %v", err)
+			fail(fmt.Errorf("MCP server failed: %w", err))
 		}
 
 	case "crm":
 		// CRM subcommands - use Charm KV
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
 		log.Printf("CRM using Charm KV (server: %s)", client.Config().Host)
@@ -117,64 +159,316 @@ func main() {
 		crmArgs := commandArgs[1:]
 
 		switch crmCommand {
+		case "search":
+			if err := cli.SearchCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+
 		// Contact commands
 		case "add-contact":
 			if err := cli.AddContactCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "list-contacts":
 			if err := cli.ListContactsCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "update-contact":
 			if err := cli.UpdateContactCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "delete-contact":
 			if err := cli.DeleteContactCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "import-contacts":
+			if err := cli.ImportContactsCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "fill-met":
+			if err := cli.FillMetCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "dedupe":
+			if err := cli.DedupeCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "archive-stale":
+			if err := cli.ArchiveStaleCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "archive-policy":
+			if err := cli.ArchivePolicyCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "unarchive-contact":
+			if err := cli.UnarchiveContactCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "pin":
+			if err := cli.PinCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "unpin":
+			if err := cli.UnpinCommand(client, crmArgs); err != nil {
+				fail(err)
 			}
 
 		// Company commands
 		case "add-company":
 			if err := cli.AddCompanyCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "list-companies":
 			if err := cli.ListCompaniesCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "update-company":
 			if err := cli.UpdateCompanyCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "delete-company":
 			if err := cli.DeleteCompanyCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "infer-companies":
+			if err := cli.InferCompaniesCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "import-companies":
+			if err := cli.ImportCompaniesCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "enrich":
+			if err := cli.EnrichCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "logos":
+			if len(crmArgs) == 0 {
+				fmt.Println("Error: logos requires a subcommand (fetch, clear)")
+				os.Exit(1)
+			}
+			logoArgs := crmArgs[1:]
+			switch crmArgs[0] {
+			case "fetch":
+				if err := cli.LogosFetchCommand(client, logoArgs); err != nil {
+					fail(err)
+				}
+			case "clear":
+				if err := cli.LogosClearCommand(client, logoArgs); err != nil {
+					fail(err)
+				}
+			default:
+				fmt.Printf("Unknown logos command: %s\n\n", crmArgs[0])
+				printUsage()
+				os.Exit(1)
 			}
 
 		// Deal commands
 		case "add-deal":
 			if err := cli.AddDealCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "list-deals":
 			if err := cli.ListDealsCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "delete-deal":
 			if err := cli.DeleteDealCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "deal-notes":
+			if err := cli.DealNotesCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "stage-requirements":
+			if err := cli.StageRequirementsCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "stage-timers":
+			if err := cli.StageTimersCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "deal":
+			if len(crmArgs) == 0 {
+				fmt.Println("Error: deal requires a subcommand (add-contact, remove-contact, contacts)")
+				os.Exit(1)
+			}
+			dealArgs := crmArgs[1:]
+			switch crmArgs[0] {
+			case "add-contact":
+				if err := cli.DealAddContactCommand(client, dealArgs); err != nil {
+					fail(err)
+				}
+			case "remove-contact":
+				if err := cli.DealRemoveContactCommand(client, dealArgs); err != nil {
+					fail(err)
+				}
+			case "contacts":
+				if err := cli.DealContactsCommand(client, dealArgs); err != nil {
+					fail(err)
+				}
+			default:
+				fmt.Printf("Unknown deal command: %s\n\n", crmArgs[0])
+				printUsage()
+				os.Exit(1)
+			}
+
+		// Tag commands
+		case "tag":
+			if err := cli.TagCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "untag":
+			if err := cli.UntagCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "tags":
+			if len(crmArgs) == 0 {
+				fmt.Println("Error: tags requires a subcommand (list, find, derive, suggestions, accept, reject)")
+				os.Exit(1)
+			}
+			tagArgs := crmArgs[1:]
+			switch crmArgs[0] {
+			case "list":
+				if err := cli.TagsListCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			case "find":
+				if err := cli.TagsFindCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			case "derive":
+				if err := cli.TagsDeriveCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			case "suggestions":
+				if err := cli.TagsSuggestionsCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			case "accept":
+				if err := cli.TagsAcceptCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			case "reject":
+				if err := cli.TagsRejectCommand(client, tagArgs); err != nil {
+					fail(err)
+				}
+			default:
+				fmt.Printf("Unknown tags command: %s\n\n", crmArgs[0])
+				printUsage()
+				os.Exit(1)
+			}
+
+		// Event commands
+		case "events":
+			if len(crmArgs) == 0 {
+				fmt.Println("Error: events requires a subcommand (add, list, attend, attendees, report, delete)")
+				os.Exit(1)
+			}
+			eventArgs := crmArgs[1:]
+			switch crmArgs[0] {
+			case "add":
+				if err := cli.EventsAddCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			case "list":
+				if err := cli.EventsListCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			case "attend":
+				if err := cli.EventsAttendCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			case "attendees":
+				if err := cli.EventsAttendeesCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			case "report":
+				if err := cli.EventsReportCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			case "delete":
+				if err := cli.EventsDeleteCommand(client, eventArgs); err != nil {
+					fail(err)
+				}
+			default:
+				fmt.Printf("Unknown events command: %s\n\n", crmArgs[0])
+				printUsage()
+				os.Exit(1)
 			}
 
 		// Relationship commands
 		case "update-relationship":
 			if err := cli.UpdateRelationshipCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "delete-relationship":
 			if err := cli.DeleteRelationshipCommand(client, crmArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+
+		// Revision history commands
+		case "revisions":
+			if err := cli.RevisionsCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "restore":
+			if err := cli.RestoreCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "history":
+			if err := cli.HistoryCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "undo":
+			if err := cli.UndoCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "asof":
+			if err := cli.AsOfCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+
+		case "copy":
+			if err := cli.CopyContactsCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "export":
+			if err := cli.ExportCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "import":
+			if err := cli.ImportCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "segment":
+			if err := cli.SegmentCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+
+		// Outreach email commands
+		case "email":
+			if err := cli.EmailCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "outreach":
+			if err := cli.OutreachCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+
+		// Task commands
+		case "capture":
+			if err := cli.CaptureCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "list-tasks":
+			if err := cli.ListTasksCommand(client, crmArgs); err != nil {
+				fail(err)
+			}
+		case "complete-task":
+			if err := cli.CompleteTaskCommand(client, crmArgs); err != nil {
+				fail(err)
 			}
 
 		default:
@@ -187,7 +481,7 @@ func main() {
 		// Visualization subcommands - use Charm KV
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
 		log.Printf("Viz using Charm KV (server: %s)", client.Config().Host)
@@ -195,7 +489,7 @@ func main() {
 		if len(commandArgs) == 0 {
 			// No subcommand = dashboard
 			if err := cli.VizDashboardCommand(client, commandArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 			return
 		}
@@ -206,7 +500,7 @@ func main() {
 		switch vizCommand {
 		case "graph":
 			if len(vizArgs) == 0 {
-				fmt.Println("Error: viz graph requires a type (contacts, company, or pipeline)")
+				fmt.Println("Error: viz graph requires a type (contacts, company, deal, or pipeline)")
 				printUsage()
 				os.Exit(1)
 			}
@@ -217,19 +511,23 @@ func main() {
 			switch graphType {
 			case "all":
 				if err := cli.VizGraphAllCommand(client, graphArgs); err != nil {
-					log.Fatalf("Error: %v", err)
+					fail(err)
 				}
 			case "contacts":
 				if err := cli.VizGraphContactsCommand(client, graphArgs); err != nil {
-					log.Fatalf("Error: %v", err)
+					fail(err)
 				}
 			case "company":
 				if err := cli.VizGraphCompanyCommand(client, graphArgs); err != nil {
-					log.Fatalf("Error: %v", err)
+					fail(err)
+				}
+			case "deal":
+				if err := cli.VizGraphDealCommand(client, graphArgs); err != nil {
+					fail(err)
 				}
 			case "pipeline":
 				if err := cli.VizGraphPipelineCommand(client, graphArgs); err != nil {
-					log.Fatalf("Error: %v", err)
+					fail(err)
 				}
 			default:
 				fmt.Printf("Unknown graph type: %s\n\n", graphType)
@@ -237,44 +535,84 @@ func main() {
 				os.Exit(1)
 			}
 
+		case "sources":
+			if err := cli.VizSourcesCommand(client, vizArgs); err != nil {
+				fail(err)
+			}
+
+		case "pipeline-velocity":
+			if err := cli.VizPipelineVelocityCommand(client, vizArgs); err != nil {
+				fail(err)
+			}
+
+		case "places":
+			if err := cli.VizPlacesCommand(client, vizArgs); err != nil {
+				fail(err)
+			}
+
+		case "trend":
+			if err := cli.VizTrendCommand(client, vizArgs); err != nil {
+				fail(err)
+			}
+
+		case "snapshot":
+			if err := cli.VizSnapshotCommand(client, vizArgs); err != nil {
+				fail(err)
+			}
+
 		default:
 			fmt.Printf("Unknown viz command: %s\n\n", vizCommand)
 			printUsage()
 			os.Exit(1)
 		}
 
-	case "web":
-		port := 10666
-		if len(commandArgs) > 0 && commandArgs[0] == "--port" && len(commandArgs) > 1 {
-			_, _ = fmt.Sscanf(commandArgs[1], "%d", &port)
-		}
-
+	case "shell":
+		// Interactive shell - keeps one Charm KV client open across commands
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if err := cli.ShellCommand(client, commandArgs); err != nil {
+			fail(fmt.Errorf("shell error: %w", err))
 		}
 
-		server, err := web.NewServer(client)
+	case "web":
+		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to create web server: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
-		if err := server.Start(port); err != nil {
-			log.Fatalf("Web server error: %v", err)
+		switch {
+		case len(commandArgs) > 0 && commandArgs[0] == "maintenance":
+			err = cli.WebMaintenanceCommand(client, commandArgs[1:])
+		case len(commandArgs) > 0 && commandArgs[0] == "auth":
+			err = cli.WebAuthCommand(client, commandArgs[1:])
+		case len(commandArgs) > 0 && commandArgs[0] == "healthcheck":
+			err = cli.WebHealthcheckCommand(client, commandArgs[1:])
+		case len(commandArgs) > 0 && commandArgs[0] == "share":
+			err = cli.WebShareCommand(client, commandArgs[1:])
+		case len(commandArgs) > 0 && commandArgs[0] == "token":
+			err = cli.WebTokenCommand(client, commandArgs[1:])
+		default:
+			err = cli.WebCommand(client, commandArgs)
+		}
+		if err != nil {
+			fail(fmt.Errorf("web server error: %w", err))
 		}
 
 	case "followups":
 		// Follow-up tracking subcommands - use Charm KV
 		client, err := charm.GetClient()
 		if err != nil {
-			log.Fatalf("Failed to initialize Charm KV: %v", err)
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
 		}
 
 		log.Printf("Followups using Charm KV (server: %s)", client.Config().Host)
 
 		if len(commandArgs) == 0 {
 			fmt.Println("Usage: pagen followups <command>")
-			fmt.Println("Commands: list, log, set-cadence, stats, digest")
+			fmt.Println("Commands: list, log, set-cadence, schedule, stats, goals, digest, import-attendance, recompute, tracking, track-email, tracked, pending-replies")
 			os.Exit(1)
 		}
 
@@ -284,35 +622,381 @@ func main() {
 		switch followupCommand {
 		case "list":
 			if err := cli.FollowupListCommand(client, followupArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "log":
 			if err := cli.LogInteractionCommand(client, followupArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "set-cadence":
 			if err := cli.SetCadenceCommand(client, followupArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "schedule":
+			if err := cli.ScheduleFollowupCommand(client, followupArgs); err != nil {
+				fail(err)
 			}
 		case "stats":
 			if err := cli.FollowupStatsCommand(client, followupArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "goals":
+			if err := cli.GoalsCommand(client, followupArgs); err != nil {
+				fail(err)
 			}
 		case "digest":
 			if err := cli.DigestCommand(client, followupArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+		case "import-attendance":
+			if err := cli.ImportAttendanceCommand(client, followupArgs); err != nil {
+				fail(err)
+			}
+		case "recompute":
+			if err := cli.RecomputePrioritiesCommand(client, followupArgs); err != nil {
+				fail(err)
+			}
+		case "tracking":
+			if err := cli.EmailTrackingCommand(client, followupArgs); err != nil {
+				fail(err)
+			}
+		case "track-email":
+			if err := cli.TrackEmailCommand(client, followupArgs); err != nil {
+				fail(err)
+			}
+		case "tracked":
+			if err := cli.TrackedEmailsCommand(client, followupArgs); err != nil {
+				fail(err)
+			}
+		case "pending-replies":
+			if err := cli.PendingRepliesCommand(client, followupArgs); err != nil {
+				fail(err)
 			}
 		default:
 			fmt.Printf("Unknown followups command: %s\n", followupCommand)
-			fmt.Println("Commands: list, log, set-cadence, stats, digest")
+			fmt.Println("Commands: list, log, set-cadence, schedule, stats, goals, digest, import-attendance, recompute, tracking, track-email, tracked, pending-replies")
+			os.Exit(1)
+		}
+
+	case "greetings":
+		// Greeting queue subcommands - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen greetings <command>")
+			fmt.Println("Commands: today, holidays, met, template")
+			os.Exit(1)
+		}
+
+		greetingsCommand := commandArgs[0]
+		greetingsArgs := commandArgs[1:]
+
+		switch greetingsCommand {
+		case "today":
+			if err := cli.GreetingsTodayCommand(client, greetingsArgs); err != nil {
+				fail(err)
+			}
+		case "holidays":
+			if err := cli.GreetingsHolidaysCommand(client, greetingsArgs); err != nil {
+				fail(err)
+			}
+		case "met":
+			if err := cli.GreetingsMetCommand(client, greetingsArgs); err != nil {
+				fail(err)
+			}
+		case "template":
+			if err := cli.GreetingsTemplateCommand(client, greetingsArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown greetings command: %s\n", greetingsCommand)
+			fmt.Println("Commands: today, holidays, met, template")
 			os.Exit(1)
 		}
 
+	case "watch":
+		// Watch subscriptions - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen watch <command>")
+			fmt.Println("Commands: deal, contact, list, remove, run")
+			os.Exit(1)
+		}
+
+		watchCommand := commandArgs[0]
+		watchArgs := commandArgs[1:]
+
+		switch watchCommand {
+		case "deal":
+			if err := cli.WatchDealCommand(client, watchArgs); err != nil {
+				fail(err)
+			}
+		case "contact":
+			if err := cli.WatchContactCommand(client, watchArgs); err != nil {
+				fail(err)
+			}
+		case "list":
+			if err := cli.WatchListCommand(client, watchArgs); err != nil {
+				fail(err)
+			}
+		case "remove":
+			if err := cli.WatchRemoveCommand(client, watchArgs); err != nil {
+				fail(err)
+			}
+		case "run":
+			if err := cli.WatchRunCommand(client, watchArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown watch command: %s\n", watchCommand)
+			fmt.Println("Commands: deal, contact, list, remove, run")
+			os.Exit(1)
+		}
+
+	case "report":
+		// Reports - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen report <command>")
+			fmt.Println("Commands: hygiene")
+			os.Exit(1)
+		}
+
+		reportCommand := commandArgs[0]
+		reportArgs := commandArgs[1:]
+
+		switch reportCommand {
+		case "hygiene":
+			if err := cli.ReportHygieneCommand(client, reportArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown report command: %s\n", reportCommand)
+			fmt.Println("Commands: hygiene")
+			os.Exit(1)
+		}
+
+	case "export":
+		// Exports for external tools - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen export <command>")
+			fmt.Println("Commands: timeline (for contacts, companies, deals, and interactions see pagen crm export)")
+			os.Exit(1)
+		}
+
+		switch commandArgs[0] {
+		case "timeline":
+			if err := cli.ExportTimelineCommand(client, commandArgs[1:]); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown export command: %s\n", commandArgs[0])
+			fmt.Println("Commands: timeline (for contacts, companies, deals, and interactions see pagen crm export)")
+			os.Exit(1)
+		}
+
+	case "news":
+		// Company news enrichment - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen news <command>")
+			fmt.Println("Commands: fetch, list, feeds")
+			os.Exit(1)
+		}
+
+		newsCommand := commandArgs[0]
+		newsArgs := commandArgs[1:]
+
+		switch newsCommand {
+		case "fetch":
+			if err := cli.NewsFetchCommand(client, newsArgs); err != nil {
+				fail(err)
+			}
+		case "list":
+			if err := cli.NewsListCommand(client, newsArgs); err != nil {
+				fail(err)
+			}
+		case "feeds":
+			if err := cli.NewsFeedsCommand(client, newsArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown news command: %s\n", newsCommand)
+			fmt.Println("Commands: fetch, list, feeds")
+			os.Exit(1)
+		}
+
+	case "send", "receive":
+		// Handoffs between linked devices - use Charm KV
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		run := cli.SendCommand
+		if command == "receive" {
+			run = cli.ReceiveCommand
+		}
+		if err := run(client, commandArgs); err != nil {
+			fail(err)
+		}
+
+	case "config":
+		// Settings export/import - use Charm KV for cadences
+		client, err := charm.GetClient()
+		if err != nil {
+			fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+		}
+
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen config <command>")
+			fmt.Println("Commands: export, import")
+			os.Exit(1)
+		}
+
+		configCommand := commandArgs[0]
+		configArgs := commandArgs[1:]
+
+		switch configCommand {
+		case "export":
+			if err := cli.ConfigExportCommand(client, configArgs); err != nil {
+				fail(err)
+			}
+		case "import":
+			if err := cli.ConfigImportCommand(client, configArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown config command: %s\n", configCommand)
+			fmt.Println("Commands: export, import")
+			os.Exit(1)
+		}
+
+	case "profiles":
+		// Profile management - no Charm KV needed
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen profiles <command>")
+			fmt.Println("Commands: list, create, switch")
+			os.Exit(1)
+		}
+
+		profilesCommand := commandArgs[0]
+		profilesArgs := commandArgs[1:]
+
+		switch profilesCommand {
+		case "list":
+			if err := cli.ProfilesListCommand(profilesArgs); err != nil {
+				fail(err)
+			}
+		case "create":
+			if err := cli.ProfilesCreateCommand(profilesArgs); err != nil {
+				fail(err)
+			}
+		case "switch":
+			if err := cli.ProfilesSwitchCommand(profilesArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown profiles command: %s\n", profilesCommand)
+			fmt.Println("Commands: list, create, switch")
+			os.Exit(1)
+		}
+
+	case "deprecations":
+		// Lists old command names - no Charm KV needed
+		if err := cli.DeprecationsCommand(commandArgs); err != nil {
+			fail(err)
+		}
+
+	case "debug":
+		// Diagnostics for bug reports - still useful when Charm KV fails to open
+		if len(commandArgs) == 0 {
+			fmt.Println("Usage: pagen debug <command>")
+			fmt.Println("Commands: env, bundle")
+			os.Exit(1)
+		}
+
+		client, err := charm.GetClient()
+		if err != nil {
+			log.Printf("warning: failed to initialize Charm KV: %v", err)
+			client = nil
+		}
+
+		debugCommand := commandArgs[0]
+		debugArgs := commandArgs[1:]
+
+		switch debugCommand {
+		case "env":
+			if err := cli.DebugEnvCommand(client, version, debugArgs); err != nil {
+				fail(err)
+			}
+		case "bundle":
+			if err := cli.DebugBundleCommand(client, version, debugArgs); err != nil {
+				fail(err)
+			}
+		default:
+			fmt.Printf("Unknown debug command: %s\n", debugCommand)
+			fmt.Println("Commands: env, bundle")
+			os.Exit(1)
+		}
+
+	case "db":
+		// Database maintenance - works on a file by --path even when Charm KV fails to open
+		if len(commandArgs) == 0 || (commandArgs[0] != "maintain" && commandArgs[0] != "health") {
+			fmt.Println("Usage: pagen db maintain [--check] [--no-vacuum] [--path <file>]")
+			fmt.Println("       pagen db health [--path <file>]")
+			os.Exit(1)
+		}
+
+		client, err := charm.GetClient()
+		if err != nil {
+			log.Printf("warning: failed to initialize Charm KV: %v", err)
+			client = nil
+		}
+		if commandArgs[0] == "health" {
+			err = cli.DBHealthCommand(client, commandArgs[1:])
+		} else {
+			err = cli.DBMaintainCommand(client, commandArgs[1:])
+		}
+		if err != nil {
+			fail(err)
+		}
+
+	case "logs":
+		// Log viewing - reads local log files, no Charm KV needed
+		if len(commandArgs) == 0 || commandArgs[0] != "web" {
+			fmt.Println("Usage: pagen logs web [--limit N] [--slow] [--errors] [--follow]")
+			os.Exit(1)
+		}
+		if err := cli.LogsWebCommand(nil, commandArgs[1:]); err != nil {
+			fail(err)
+		}
+
 	case "sync":
 		// Charm KV sync commands
 		if len(commandArgs) == 0 {
 			fmt.Println("Usage: pagen sync <command>")
-			fmt.Println("Commands: link, status, unlink, wipe, wipedb, reset, repair, now, auto")
+			fmt.Println("Commands: link, status, unlink, wipe, wipedb, reset, repair, now, auto, viz")
 			os.Exit(1)
 		}
 
@@ -323,73 +1007,75 @@ func main() {
 		// Charm sync commands
 		case "link":
 			if err := charm.SyncLinkCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "status":
 			if err := charm.SyncStatusCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "unlink":
 			if err := charm.SyncUnlinkCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "wipe":
 			if err := charm.SyncWipeCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "wipedb":
 			if err := charm.SyncWipeDBCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "reset":
 			if err := charm.SyncResetCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "repair":
 			if err := charm.SyncRepairCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
 			}
 		case "now":
 			if err := charm.SyncNowCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
+				fail(err)
+			}
+			// Replies to pending outreach are picked up here when a Google token exists
+			if client, err := charm.GetClient(); err == nil {
+				if pending, err := client.ListOutreach(charm.OutreachPending); err == nil && len(pending) > 0 {
+					if result, err := cli.CheckOutreachReplies(client); err != nil {
+						fmt.Printf("⚠ Outreach reply check skipped: %v\n", err)
+					} else if len(result.Replied) > 0 {
+						fmt.Printf("✓ %d outreach email(s) replied to\n", len(result.Replied))
+					}
+				}
+				// Gmail threads with contacts are checked too, so unanswered emails become pending follow-ups
+				if cli.HasGoogleToken() {
+					if result, err := cli.CheckEmailThreads(client, charm.ReplyWaitDays(client.Config()), time.Now()); err != nil {
+						fmt.Printf("⚠ Email thread check skipped: %v\n", err)
+					} else if len(result.Pending) > 0 {
+						fmt.Printf("✓ %d email(s) now waiting on a reply (see 'pagen followups pending-replies')\n", len(result.Pending))
+					}
+				}
+				// Scheduled upkeep runs here, so a cron job or launchd timer on
+				// 'pagen sync now' keeps it current without a daemon
+				cli.RunBackgroundJobs(client, time.Now(), func(format string, args ...any) {
+					fmt.Printf(format+"\n", args...)
+				})
 			}
 		case "auto":
 			if err := charm.SetAutoSyncCommand(syncArgs); err != nil {
-				log.Fatalf("Error: %v", err)
-			}
-
-		// Legacy Google sync commands (deprecated - now using Charm KV)
-		case "init", "contacts", "calendar", "gmail", "daemon":
-			fmt.Printf("Command 'sync %s' is deprecated.\n", syncCommand)
-			fmt.Println("Google sync has been replaced by Charm KV sync.")
-			fmt.Println("Available sync commands:")
-			fmt.Println("  sync link   - Link this device to Charm cloud")
-			fmt.Println("  sync status - Show sync status")
-			fmt.Println("  sync now    - Sync immediately")
-			fmt.Println("  sync auto   - Configure auto-sync")
-			fmt.Println("  sync repair - Repair database issues")
-			fmt.Println("  sync reset  - Reset local database")
-			fmt.Println("  sync unlink - Unlink device")
-			fmt.Println("  sync wipe   - Wipe local data")
-			fmt.Println("  sync wipedb - Wipe all data (local + cloud)")
-			os.Exit(1)
-
-		// Legacy vault commands (deprecated)
-		case "vault-init", "vault-login", "vault-status", "vault-now", "vault-pending", "vault-logout", "vault-wipe", "charm-status":
-			fmt.Printf("Command '%s' is deprecated. Use the new sync commands instead:\n", syncCommand)
-			fmt.Println("  sync link   - Link this device")
-			fmt.Println("  sync status - Show sync status")
-			fmt.Println("  sync unlink - Unlink device")
-			fmt.Println("  sync wipe   - Wipe local data")
-			fmt.Println("  sync wipedb - Wipe all data (local + cloud)")
-			fmt.Println("  sync reset  - Reset local database")
-			fmt.Println("  sync repair - Repair database")
-			fmt.Println("  sync now    - Sync immediately")
-			os.Exit(1)
+				fail(err)
+			}
+		case "viz":
+			client, err := charm.GetClient()
+			if err != nil {
+				fail(fmt.Errorf("failed to initialize Charm KV: %w", err))
+			}
+			if err := cli.SyncVizCommand(client, syncArgs); err != nil {
+				fail(err)
+			}
 
 		default:
 			fmt.Printf("Unknown sync command: %s\n", syncCommand)
-			fmt.Println("Commands: link, status, unlink, wipe, wipedb, reset, repair, now, auto")
+			fmt.Println("Commands: link, status, unlink, wipe, wipedb, reset, repair, now, auto, viz")
 			os.Exit(1)
 		}
 
@@ -400,6 +1086,15 @@ func main() {
 	}
 }
 
+// isTruthy reports whether an environment variable value means "on".
+func isTruthy(v string) bool {
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
 func printUsage() {
 	fmt.Printf(`pagen v%s - Personal Agent toolkit
 
@@ -409,105 +1104,412 @@ USAGE:
 GLOBAL FLAGS:
   --version              Show version and exit
   --init                 Initialize Charm KV and exit (use with 'crm')
+  --headless             Never start the TUI or shell (also PAGEN_HEADLESS=1)
+  --timing               Print open/query/render durations to stderr when the command finishes
+  --strict-deprecations  Exit 3 instead of running renamed commands under old names (also PAGEN_STRICT_DEPRECATIONS=1)
+  --json                 Report errors as JSON on stderr (also when the command has --json)
+  --profile <name>       Use this profile instead of the current one (also PAGEN_PROFILE)
 
 COMMANDS:
   (none)                 Launch interactive TUI (default)
   mcp                    Start MCP server for Claude Desktop
   crm                    CRM management commands
+  shell                  Interactive CRM shell (REPL)
   viz                    Visualization commands
   web                    Start web UI server
   sync                   Google sync commands (contacts, calendar, gmail)
+  greetings              Birthdays, work and met anniversaries, and holidays to greet
+  watch                  Notifications when a deal or contact changes
+  report                 Data-hygiene and completeness reports
+  export                 Contact timelines for external dashboards
+  news                   Recent headlines for companies from RSS/Atom feeds
+  send                   Leave a note or contact for your other linked device
+  receive                Pop what your other devices sent
+  config                 Export or import rules, templates, and cadences
+  db                     Database maintenance (VACUUM, ANALYZE, integrity check)
+  logs                   Web server request log
+  debug                  Diagnostics for bug reports
+  profiles               Separate CRMs, such as work and personal
+  deprecations           Old command names and their replacements (--json)
 
 MCP SERVER:
   pagen mcp              Start MCP server (for Claude Desktop integration)
 
 CRM COMMANDS:
+  IDs can be a unique prefix of 4+ characters (as shown in lists) or a name
+
+  pagen crm search <query>  Ranked search of contacts, companies, and deals
+                            Field terms narrow it: company:acme tag:vip last_contacted:<90d stage:negotiation amount:>10k
+    --type <types>            Only these types, comma-separated: contact, company, deal
+    --limit <n>               Max results (default: 20)
+
   pagen crm add-contact     Add a new contact
     --name <name>             Contact name (required)
     --email <email>           Email address
     --phone <phone>           Phone number
+    --title <title>           Job title
     --company <company>       Company name
     --notes <notes>           Notes about contact
+    --birthday <date>         Birthday (YYYY-MM-DD, or MM-DD)
+    --work-start <date>       Started at their company (YYYY-MM-DD)
+    --country <code>          Country code for holiday greetings (e.g. US)
+    --met-via <how>           How you met (e.g. Zoom, intro from Bob)
+    --met-at <where>          Event or place you met
+    --met-date <date>         Date you met (YYYY-MM-DD)
+    --tag <tags>              Comma-separated tags (e.g. ai,investor)
 
   pagen crm list-contacts   List contacts
     --query <text>            Search by name or email
     --company <company>       Filter by company name
     --limit <n>               Max results (default: 50)
+    --archived                Include archived contacts
+    --met-in <period>         Met in a year, month, or day (2024, 2024-03, 2024-03-02)
+    --city <city>             Met in person in this city (from meeting locations)
+    --tag <tag>               Only contacts with this tag
 
   pagen crm update-contact [flags] <id>  Update an existing contact
     --name <name>             Contact name
     --email <email>           Email address
     --phone <phone>           Phone number
+    --title <title>           Job title
     --company <company>       Company name
     --notes <notes>           Notes about contact
+    --birthday <date>         Birthday (YYYY-MM-DD, or MM-DD)
+    --work-start <date>       Started at their company (YYYY-MM-DD)
+    --country <code>          Country code for holiday greetings (e.g. US)
+    --met-via <how>           How you met
+    --met-at <where>          Event or place you met
+    --met-date <date>         Date you met (YYYY-MM-DD)
     Note: flags must come before the contact ID
 
   pagen crm delete-contact <id>  Delete a contact
 
+  pagen crm import-contacts Import contacts from a CSV or vCard file
+    --file <path>             CSV or .vcf file (required)
+    --format <csv|vcard>      File format (default: from the extension or content)
+    --dry-run                 Show counts without writing
+
+  pagen crm fill-met        Fill in how we met from each contact's first imported interaction
+
+  pagen crm dedupe          Find likely duplicate contacts and merge them, asking about each
+    --auto                    Merge same-email and same-name-and-company matches without asking
+    --dry-run                 List likely duplicates without merging
+
+  pagen crm tag <ref> <tag>...    Tag a contact, company, or deal
+  pagen crm untag <ref> <tag>...  Remove tags from a contact, company, or deal
+  pagen crm tags list       List tags in use with counts per type
+  pagen crm tags find <tag> Contacts, companies, and deals with a tag
+    --type <types>            Only these types, comma-separated: contact, company, deal
+  pagen crm tags derive     Suggest topic tags (ai, fundraising, hiring, ...) from meeting titles and email subjects
+    --min-mentions <n>        Mentions of a topic needed to suggest it (default: 2)
+    --dry-run                 Show suggestions without saving them
+  pagen crm tags suggestions  List tag suggestions waiting for review
+    --status <status>         pending (default), accepted, rejected, or all
+  pagen crm tags accept <id>...  Add suggested tags to their contacts
+    --all                     Every pending suggestion (with --min-confidence <0-1>)
+  pagen crm tags reject <id>...  Reject suggested tags so they aren't suggested again
+
+  pagen crm archive-stale   Contacts created by sync that were never contacted or edited
+    --months <n>              Imported more than n months ago (default: policy, or 12)
+    --apply                   Archive them (default: dry run)
+
+  pagen crm archive-policy  Show or change automatic archiving (applied daily by the background jobs)
+    --enable / --disable      Turn the policy on (starts in dry run) or off
+    --months <n>              Age threshold in months
+    --dry-run=false           Archive instead of only logging candidates
+
+  pagen crm unarchive-contact <id>  Return an archived contact to lists
+
+  pagen crm pin <contact> [fact]   Pin a key fact on a contact, or list its pins
+  pagen crm unpin <contact> <n|fact>  Remove a pinned fact by number or text
+
   pagen crm add-company     Add a new company
     --name <name>             Company name (required)
     --domain <domain>         Company domain (e.g., acme.com)
     --industry <industry>     Industry
+    --employees <n|range>     Employee count, e.g. 42 or 11-50
+    --funding-stage <stage>   Funding stage, e.g. seed or "Series A"
+    --hq <location>           Headquarters location
     --notes <notes>           Notes about company
+    --tag <tags>              Comma-separated tags (e.g. ai,portfolio)
 
   pagen crm list-companies  List companies
     --query <text>            Search by name or domain
+    --industry <industry>     Filter by industry
+    --stage <stage>           Filter by funding stage
+    --min-employees <n>       At least n employees
+    --max-employees <n>       At most n employees
+    --hq <text>               Headquarters location contains text
+    --tag <tag>               Only companies with this tag
     --limit <n>               Max results (default: 50)
 
+  pagen crm infer-companies Link contacts without a company by email domain
+    --dry-run                 Show what would be linked
+    --review                  Confirm or rename each domain's company
+    --domain <domain>         Only this email domain
+    --min <n>                 Only domains with at least n contacts
+
+  pagen crm import-companies Set size, funding, and HQ from a company CSV
+    --file <path>             CSV keyed by domain or website (required)
+    --update-only             Skip domains not already in the CRM
+    --dry-run                 Show what would change
+
+  pagen crm enrich <contact> Add avatar, company site, and API metadata to a contact
+    --sources <list>          classify, gravatar, website, api (default: all)
+    --dry-run                 Show what would be saved
+
+  pagen crm logos fetch     Fetch and cache company logos for the web UI
+    --company <id|name>       Only this company
+    --refresh                 Fetch again even when cached
+
+  pagen crm logos clear     Remove cached logos so they are fetched again
+    --company <id|name>       Only this company
+
   pagen crm add-deal        Add a new deal
     --title <title>           Deal title (required)
-    --company <company>       Company name (required)
+    --company <company>       Company name or ID (required)
+    --contact <name|id>       Contact (created when not found)
     --amount <cents>          Deal amount in cents
     --currency <code>         Currency code (default: USD)
     --stage <stage>           Stage (default: prospecting)
     --notes <notes>           Initial notes
+    --close-date <date>       Expected close date (YYYY-MM-DD)
+    --close-reason <reason>   Why the deal was won or lost
+    --source <source>         referral, inbound, outbound, event, partner
+    --referrer <name|id>      Contact who referred the deal
+    --strict                  Fail with candidates instead of guessing ambiguous names
+    --tag <tags>              Comma-separated tags (e.g. enterprise,q3)
 
   pagen crm list-deals      List deals
     --stage <stage>           Filter by stage
+    --source <source>         Filter by source
     --company <company>       Filter by company name
+    --tag <tag>               Only deals with this tag
+    --query <query>           Search by title or company, with field terms like stage:negotiation
     --limit <n>               Max results (default: 50)
+    --with-stats              Show totals and expected value per stage
+    --group-by <grouping>     Group by company, quarter (expected close), or tag
 
   pagen crm delete-deal <id>   Delete a deal
 
+  pagen crm deal-notes <id>    Show a deal's notes as plain text
+
+  pagen crm stage-requirements  Show or set fields required before a deal enters a stage
+    --stage <stage>           Stage to configure
+    --require <fields>        Comma-separated: amount, expected_close_date, contact, close_reason
+
+  pagen crm stage-timers      Show or set actions for deals that sit in a stage too long
+    --stage <stage>           Stage to configure
+    --days <n>                Days in the stage before the timer fires (0 clears the stage)
+    --actions <actions>       Comma-separated: task, notify, flag
+    --tag <tag>               Tag the flag action adds (default: stalled)
+    --webhook <url>           URL the notify action also posts to
+
+  pagen crm deal add-contact  Add a contact to a deal with a role
+    --deal <id>               Deal ID (required)
+    --contact <name|id>       Contact name or ID (required)
+    --role <role>             decision_maker, champion, influencer, procurement (required)
+
+  pagen crm deal remove-contact  Remove a contact from a deal
+    --deal <id>               Deal ID (required)
+    --contact <name|id>       Contact name or ID (required)
+
+  pagen crm deal contacts <id>  List the contacts and roles on a deal
+
+  pagen crm events add      Add a conference, dinner, or meetup
+    --name <name>             Event name (required)
+    --type <type>             conference, dinner, meetup, other (default: conference)
+    --date <date>             Event date (YYYY-MM-DD, default: today)
+    --location <location>     Location
+
+  pagen crm events list     List events
+    --type <type>             Filter by event type
+
+  pagen crm events attend   Record contacts attending an event
+    --event <name|id>         Event name or ID (required)
+    --contact <names>         Contact names or IDs, comma-separated (required)
+    --met                     First met these contacts at the event
+
+  pagen crm events attendees <event>  List who attended an event
+  pagen crm events report   Contacts and deals sourced per event
+    --verbose                 List the contacts sourced at each event
+  pagen crm events delete <id>  Delete an event
+
   pagen crm update-relationship [flags] <id>  Update a relationship
     --type <type>             Relationship type
     --context <context>       Relationship context
+    --strength <strength>     weak, medium, or strong (also confirms it)
+    --validate                Confirm the relationship still holds
     Note: flags must come before the relationship ID
 
   pagen crm delete-relationship <id>  Delete a relationship
 
+  pagen crm revisions <id>  List saved revisions of a contact, company, deal, or relationship
+
+  pagen crm restore [flags] <id>  Restore an object to a previous revision
+    --rev <n>                 Revision number (required)
+    Note: flags must come before the object ID
+
+  pagen crm history [flags] [<id>]  Show the audit log of changes, with who made them and undo hints
+    --actor <name>            Only changes made through cli, mcp, tui, web, or api
+    --days <n>                Only changes from the last n days
+    --limit <n>               Maximum entries to show (default: 50, 0 = all)
+    Note: flags must come before the object ID
+
+  pagen crm undo [flags]    Reverse the most recent changes, newest first
+    --last <n>                How many changes to undo (default: 1)
+    --actor <name>            Only changes made through cli, mcp, tui, web, or api
+    --dry-run                 List what would be undone
+
+  pagen crm asof <date> <list-deals|list-contacts|list-companies> [flags]
+                            List records as they were at the end of a past date (YYYY-MM-DD or RFC3339)
+                            Takes the list command's filters, e.g. --stage, --company, --with-stats
+
+  pagen crm copy            Copy matching contacts to the clipboard
+    --query <text>            Search by name or email
+    --company <company>       Filter by company name
+    --event <name|id>         Only contacts who attended an event
+    --fields <list>           id, name, email, phone, title, company, country, met_at (default: name,email)
+    --format <csv|markdown>   CSV rows or a Markdown table (default: csv)
+    --no-header               Leave out the CSV header row
+    --print                   Print instead of copying
+
+  pagen crm export          Export all contacts, companies, deals, or interactions
+    --type <type>             contacts, companies, deals, or interactions (required)
+    --format <format>         csv, json, or ndjson (default: from --output extension, else csv)
+    --output <file>           Write to a file instead of stdout
+
+  pagen crm export sheet    Export contacts or deals with their IDs for editing in a spreadsheet
+    --type <type>             contacts or deals (required)
+    --output <file>           Write to a file instead of stdout
+
+  pagen crm import sheet    Apply an edited sheet, changing only the cells that differ
+    --type <type>             contacts or deals (required)
+    --file <file>             The edited sheet (required)
+    --dry-run                 Show the changes without applying them
+    --force                   Apply cells edited in both pagen and the sheet since the export
+    Rows without an id create records; rows left out of the sheet are not deleted
+
+  pagen crm segment         List the emailable contacts matching a query, or export them for mail merge
+    --query <query>           Contacts to include, as in list-contacts (default: everyone)
+    --export <file>           Write a mail-merge CSV (- for stdout)
+    --fields <list>           Columns, e.g. first_name,last_name,email,company,title (the default)
+    Contacts tagged do-not-contact or without an email are left out
+
+  pagen crm email <contact>  Draft a templated email and wait for the contact's reply
+    --company <name|id>       Email the company's primary contact instead
+    --template <name>         intro, follow-up, check-in, or one from email_templates (default: intro)
+    --sender <name>           Name to sign with (default: email_sender in config)
+    --browser                 Open a prefilled Gmail compose window instead of a draft
+    --dry-run                 Print the email only
+
+  pagen crm outreach        List outreach emails waiting on a reply
+    --all                     Include replied outreach
+  pagen crm outreach check  Check Gmail for replies now (also runs after 'pagen sync now')
+
+  pagen crm capture <text>  Save a line as a task or interaction (also ctrl+n in the TUI)
+                            e.g. "call Dana re: renewal #task @dana due:fri"
+  pagen crm list-tasks      List open tasks, soonest due first
+    --all                     Include completed tasks
+  pagen crm complete-task <id|title>  Mark a task done
+
+SHELL:
+  pagen shell                    Interactive shell with history and tab completion
+                                 Accepts the same commands as the CLI (crm prefix optional)
+                                 new-contact runs a guided create/link/log flow
+
 VIZ COMMANDS:
   pagen viz                      Show terminal dashboard
 
   pagen viz graph all            Generate complete graph (all contacts, companies, deals)
     --output <file>               Output file (default: stdout)
+    --format <fmt>                dot, mermaid, graphml, or json (default: from --output extension, else dot)
 
   pagen viz graph contacts [id]  Generate contact relationship network
     --output <file>               Output file (default: stdout)
+    --format <fmt>                dot, mermaid, graphml, or json (default: from --output extension, else dot)
     [id]                          Optional contact ID to center graph on
 
   pagen viz graph company <id>   Generate company org chart
     --output <file>               Output file (default: stdout)
+    --format <fmt>                dot, mermaid, graphml, or json (default: from --output extension, else dot)
+
+  pagen viz graph deal <id>      Generate a deal's influencer map: people, roles, and who knows whom
+    --output <file>               Output file (default: stdout)
+    --format <fmt>                dot, mermaid, graphml, or json (default: from --output extension, else dot)
+    --involved-only               Leave out people at the company who aren't on the deal
 
   pagen viz graph pipeline       Generate deal pipeline graph
     --output <file>               Output file (default: stdout)
+    --format <fmt>                dot, mermaid, graphml, or json (default: from --output extension, else dot)
+
+  pagen viz sources              Pipeline and win rate by deal source, top referrers
+    --top <n>                     Number of top referrers (default: 5)
+
+  pagen viz pipeline-velocity    Average days in each stage, stage conversion, win rate, and stalled deals
+    --stalled-days <n>            Days in one stage before an open deal is stalled (default: 30)
+
+  pagen viz places               Cities where you meet people in person, from meeting locations
+    --top <n>                     Number of cities (default: 10, 0 for all)
+    --geocode                     Look up new locations with OpenStreetMap (or geocode_url) first
+    --unresolved                  List locations that couldn't be placed in a city
+
+  pagen viz trend                Open pipeline over time, from weekly pipeline snapshots
+    --by <period>                 week, month, or quarter (default: quarter)
+    --metric <metric>             value, expected, or count (default: value)
+    --format <format>             text or svg (default: svg for .svg output files)
+    --output <file>               Output file (default: stdout)
+    --last <n>                    Only the last n periods
+
+  pagen viz snapshot             Snapshot this week's pipeline now (the background jobs do it weekly)
+    --backfill <weeks>            Also rebuild past weeks from revision history
 
 WEB UI:
-  pagen web                      Start web UI server at http://localhost:8080
-    --port <port>                 Port to listen on (default: 8080)
+  pagen web                      Start web UI server at http://localhost:10666
+    --port <port>                 Port to listen on (default: 10666)
+    --cert <file> --key <file>    Serve HTTPS with a certificate on disk
+    --autocert <domains>          Serve HTTPS with Let's Encrypt certificates
+    --base-url <url>              Public URL when served under a subpath (e.g. https://host/pagen/)
+    --shutdown-timeout <dur>      Connection draining on shutdown (default: 15s)
+    --slow-query <dur>            Log KV operations slower than this (default: 100ms)
+  pagen web healthcheck          Exit non-zero unless the local server answers /healthz
+    --port <port>                 Port to check (default: 10666)
+    --ready                       Check /readyz (database opens, not in maintenance)
+    --url <url>                   Full URL to check instead
+  pagen web maintenance [on|off] Show or toggle maintenance mode (503 for every request)
+    --message <text>              Message shown on the maintenance page
+  pagen web auth                 Show or change web UI login
+    --user <name>                 Password login (prompts for the password, stored as bcrypt)
+    --oidc-issuer <url>           OIDC login, with --oidc-client-id, --oidc-client-secret
+    --allow <emails>              Emails or @domains allowed to sign in with OIDC
+    --disable-password, --disable-oidc, --reset-sessions
+  pagen web share                List share links (read-only deal or company status pages)
+  pagen web share deal <ref>     Create a share link for a deal (also: company <ref>)
+    --days <n>                    Days until the link stops working (default: 30, 0 = never)
+  pagen web share revoke <id>    Revoke a share link
+  pagen web share on|off         Serve share pages at /share/<token> without login (off by default)
+  pagen web token                List REST API tokens (/api/v1, see README)
+  pagen web token create <name>  Create a bearer token for scripts (shown once)
+  pagen web token revoke <name>  Revoke a token (name or ID)
 
 SYNC COMMANDS (Charm KV Cloud Sync):
   pagen sync link                Link this device to Charm cloud
                                  Uses SSH key authentication
                                  Creates encrypted cloud backup
 
-  pagen sync status              Show sync status and configuration
+  pagen sync status              Show sync status and configuration, resetting stuck syncs
 
   pagen sync now                 Sync immediately
                                  Pushes local changes and pulls remote updates
+                                 Then logs replies to pending outreach and checks Gmail threads
+                                 for unanswered emails (needs a Google token)
 
   pagen sync auto <on|off>       Enable or disable auto-sync on write
 
+  pagen sync viz                 Diagram of linked devices, the Charm cloud, outbox size,
+                                 and local vs cloud sequence numbers (DOT)
+    --output <file>               Output file (default: stdout)
+
   pagen sync repair [--force]    Repair database issues
                                  Checkpoints WAL, removes SHM, runs integrity check
                                  Use --force to run full repair even if healthy
@@ -525,6 +1527,133 @@ SYNC COMMANDS (Charm KV Cloud Sync):
                                  WARNING: Permanently deletes cloud backups
                                  Requires typing 'wipe' to confirm
 
+GREETINGS:
+  pagen greetings today          Birthdays, work and met anniversaries, and holidays due today
+    --days <n>                    Include the next n days (default: 1)
+    --kind <kind>                 Only birthday, anniversary, met, or holiday
+    --draft                       Print a message draft for each greeting
+
+  pagen greetings holidays       List holidays greeted per contact country
+    --add <name> --date <MM-DD>   Add or replace a holiday
+    --countries <codes>           Countries for --add, comma-separated (default: all contacts)
+    --remove <name>               Remove a holiday
+
+  pagen greetings met            List which years after first meeting are greeted, per tag
+    --tag <tag> --years <list>    Set years for a tag, e.g. --tag vip --years 1,5,10 ("*" for everyone else)
+    --remove <tag>                Stop listing a tag
+
+  pagen greetings template       Show or change draft templates (Go templates)
+    --kind <kind>                 birthday, anniversary, met, or holiday
+    --text <template>             e.g. "Happy birthday, {{.FirstName}}!"
+    --reset                       Restore the default for --kind
+
+WATCH COMMANDS:
+  pagen watch deal <id>          Notify when a deal changes
+  pagen watch contact <id>       Notify when a contact changes
+    --webhook <url>               Also POST each change as JSON to this URL
+    --no-desktop                  Skip desktop notifications (use with --webhook)
+
+  pagen watch list               List watched deals and contacts
+  pagen watch remove <id>        Stop watching a deal or contact
+
+  pagen watch run                Deliver changes and run stage timers (terminal-notifier on macOS, notify-send elsewhere)
+    --interval <duration>         How often to check (default: 1m)
+    --once                        Check once and exit
+
+REPORT COMMANDS:
+  pagen report hygiene           Contacts missing email, company, or title; companies
+                                 without domains; open deals without close dates
+    --limit <n>                   Records per section (default: 20, 0 for all)
+    --fix                         Prompt for each missing field and save the answers
+
+EXPORT COMMANDS:
+  pagen export timeline          A contact's interactions and deal milestones as vis.js timeline JSON
+    --contact <id-or-name>        Contact (required)
+    --format json                 Output format (default: json)
+    --output <file>               Write to a file instead of stdout
+
+NEWS COMMANDS:
+  pagen news feeds               List configured feeds and retention
+    --add <url>                   Add an RSS/Atom feed; {name} or {domain} make it a
+                                  per-company search, plain feeds are filtered by mention
+    --remove <url>                Remove a feed
+    --max-items <n>               Headlines kept per company (default: 5)
+    --max-age-days <n>            Drop headlines older than this (default: 30)
+
+  pagen news fetch               Fetch headlines for companies with domains
+    --company <id|name>           Only this company
+    --watch                       Keep running and fetch on an interval
+    --interval <duration>         Fetch interval when watching (default: 6h)
+
+  pagen news list                Stored headlines per company
+    --company <id|name>           Only this company
+
+SEND COMMANDS:
+  pagen send <note|->            Leave a note (or stdin) for another linked device
+  pagen send <contact-id>        Send a contact; an ID, ID prefix, or @name
+    --contact <id|name>           Send this contact
+
+  pagen receive                  Print and remove what other devices sent, oldest first
+    --latest                      Only the newest; older ones keep waiting
+    --peek                        Show without removing
+    --copy                        Also copy the newest to the clipboard
+
+CONFIG COMMANDS:
+  pagen config export            Write rules, templates, preferences, and cadences as JSON
+    --output <file>               Write to a file instead of stdout
+    --no-cadences                 Leave contact cadences out
+
+  pagen config import <file|->   Merge a bundle into this setup (bundle wins on conflicts)
+    --dry-run                     Show what would change without saving
+    --no-cadences                 Skip the bundle's contact cadences
+
+DATABASE COMMANDS:
+  pagen db maintain              Check integrity, rebuild indexes, ANALYZE, VACUUM, and show table sizes
+    --check                       Only check integrity and report sizes
+    --no-vacuum                   Skip VACUUM, which rewrites the whole file
+    --path <file>                 Maintain this SQLite file (default: the Charm KV database)
+
+  pagen db health                Check the live database's journal mode, integrity, and sync lock
+    --path <file>                 Check this SQLite file (default: the Charm KV database)
+
+LOG COMMANDS:
+  pagen logs web                 Recent web requests and slow KV operations, with latency percentiles
+    --limit <n>                   Entries to show (default: 50)
+    --slow                        Only slow KV operations
+    --errors                      Only 5xx responses and failed KV operations
+    --follow                      Keep printing new entries
+
+DEBUG COMMANDS:
+  pagen debug env                Print version, config, and environment (secrets redacted)
+    --json                        Output as JSON
+
+  pagen debug bundle             Write a diagnostics zip to attach to GitHub issues
+    --output <file>               Output file (default: pagen-debug-<timestamp>.zip)
+
+PROFILE COMMANDS:
+  Each profile has its own database, Charm sync namespace, config, and Google tokens.
+  The default profile keeps the data pagen stored before profiles existed.
+
+  pagen profiles list            List profiles; * marks the current one
+  pagen profiles create <name>   Add an empty profile
+    --switch                      Make it current
+  pagen profiles switch <name>   Make a profile current for later runs
+  pagen --profile <name> <command>  Run one command against another profile
+
+DEPRECATED COMMANDS:
+  pagen deprecations             Old command names and what replaces them
+    --json                        Output as JSON
+  Renamed commands still run, with a "pagen: deprecated:" hint on stderr.
+  Removed commands print a hint and exit 4. With --strict-deprecations,
+  renamed commands exit 3 instead of running.
+
+EXIT STATUSES:
+  0  success                 5  not-found (no such record or reference)
+  1  other error             6  validation (bad flag value or missing input)
+  2  bad usage               7  conflict (ambiguous name, duplicate, already done)
+  3  deprecated (strict)     8  sync-unavailable (Charm server unreachable or refused)
+  4  removed command
+
 EXAMPLES:
   # Start MCP server for Claude Desktop
   pagen mcp
@@ -539,7 +1668,7 @@ EXAMPLES:
   pagen crm add-deal --title "Enterprise License" --company "Acme Corp" --amount 5000000
 
   # List deals in negotiation stage
-  pagen crm list-deals --stage negotiation
+  pagen crm list-deals --stage negotiation --with-stats
 
   # Link device to Charm cloud sync
   pagen sync link
@@ -18,6 +18,7 @@ type Contact struct {
 	LastContactedAt *time.Time `json:"last_contacted_at,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int64      `json:"version,omitempty"` // as read; 0 when not read from the database
 }
 
 type Company struct {
@@ -28,6 +29,7 @@ type Company struct {
 	Notes     string    `json:"notes,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"version,omitempty"` // as read; 0 when not read from the database
 }
 
 type Deal struct {
@@ -39,9 +41,11 @@ type Deal struct {
 	CompanyID         uuid.UUID  `json:"company_id"`
 	ContactID         *uuid.UUID `json:"contact_id,omitempty"`
 	ExpectedCloseDate *time.Time `json:"expected_close_date,omitempty"`
+	Probability       *int       `json:"probability,omitempty"` // win % override
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	LastActivityAt    time.Time  `json:"last_activity_at"`
+	Version           int64      `json:"version,omitempty"` // as read; 0 when not read from the database
 }
 
 type DealNote struct {
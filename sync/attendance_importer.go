@@ -0,0 +1,425 @@
+// ABOUTME: Importer for Zoom and Google Meet attendance CSV exports
+// ABOUTME: Logs meeting interactions with per-participant join/leave durations matched by email
+package sync
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// Attendance export sources.
+const (
+	AttendanceSourceZoom = "zoom"
+	AttendanceSourceMeet = "meet"
+)
+
+// AttendanceRecord is one participant's aggregated attendance for a meeting.
+// Participants who rejoin appear on several CSV rows; these are merged so
+// JoinedAt is the earliest join, LeftAt the latest leave and Duration the sum.
+type AttendanceRecord struct {
+	Name     string
+	Email    string
+	JoinedAt time.Time
+	LeftAt   time.Time
+	Duration time.Duration
+}
+
+// AttendanceReport is a parsed attendance export.
+type AttendanceReport struct {
+	Source    string
+	MeetingID string
+	Topic     string
+	StartTime time.Time
+	Records   []*AttendanceRecord
+}
+
+// AttendanceImportResult summarizes an attendance import.
+type AttendanceImportResult struct {
+	Logged    int
+	Skipped   int
+	Unmatched []string
+}
+
+// attendanceTimeLayouts covers Zoom (US and ISO) and Meet exports.
+var attendanceTimeLayouts = []string{
+	time.RFC3339,
+	"01/02/2006 03:04:05 PM",
+	"1/2/2006 3:04:05 PM",
+	"01/02/2006 03:04 PM",
+	"1/2/2006 3:04 PM",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 03:04:05 PM",
+	"Jan 2, 2006 03:04:05 PM",
+	"Jan 2, 2006 3:04 PM",
+}
+
+// attendanceClockLayouts are time-of-day only formats used by Meet reports.
+var attendanceClockLayouts = []string{
+	"3:04:05 PM",
+	"3:04 PM",
+	"15:04:05",
+	"15:04",
+}
+
+var durationPartRe = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(h|hr|hrs|hour|hours|m|min|mins|minute|minutes|s|sec|secs|second|seconds)\b`)
+
+// ParseAttendanceCSV parses a Zoom or Google Meet attendance export. The
+// meetingDate is used for exports that only record the time of day.
+func ParseAttendanceCSV(r io.Reader, meetingDate time.Time) (*AttendanceReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	report := &AttendanceReport{}
+	headerIdx := -1
+
+	for i, row := range rows {
+		cols := normalizeHeader(row)
+
+		// Zoom prefixes participant rows with a meeting summary block whose
+		// header also carries the host's "User Email"
+		if columnIndex(cols, "topic") >= 0 {
+			if i+1 < len(rows) {
+				values := rows[i+1]
+				if idx := columnIndex(cols, "meeting id"); idx >= 0 {
+					report.MeetingID = strings.TrimSpace(cell(values, idx))
+				}
+				report.Topic = strings.TrimSpace(cell(values, columnIndex(cols, "topic")))
+				if t, ok := parseAttendanceTime(cell(values, columnIndex(cols, "start time")), meetingDate); ok {
+					report.StartTime = t
+				}
+			}
+			continue
+		}
+
+		if columnIndex(cols, "email") >= 0 && (columnIndex(cols, "join") >= 0 || columnIndex(cols, "duration") >= 0) {
+			headerIdx = i
+			break
+		}
+	}
+
+	if headerIdx < 0 {
+		return nil, fmt.Errorf("no participant header row with an email column found")
+	}
+
+	header := normalizeHeader(rows[headerIdx])
+	emailCol := columnIndex(header, "email")
+	nameCol := columnIndex(header, "name (original name)")
+	if nameCol < 0 {
+		nameCol = exactColumn(header, "name")
+	}
+	firstCol := columnIndex(header, "first name")
+	lastCol := columnIndex(header, "last name")
+	joinCol := columnIndex(header, "join")
+	leaveCol := columnIndex(header, "leave")
+	if leaveCol < 0 {
+		leaveCol = columnIndex(header, "exit")
+	}
+	durationCol := columnIndex(header, "duration")
+
+	// Meet exports have "First name"/"Last name" and no join date; Zoom has "Name (Original Name)"
+	if firstCol >= 0 || lastCol >= 0 {
+		report.Source = AttendanceSourceMeet
+	} else {
+		report.Source = AttendanceSourceZoom
+	}
+
+	byEmail := make(map[string]*AttendanceRecord)
+	var order []string
+
+	for _, row := range rows[headerIdx+1:] {
+		email := strings.ToLower(strings.TrimSpace(cell(row, emailCol)))
+		if email == "" || !strings.Contains(email, "@") {
+			continue
+		}
+
+		name := strings.TrimSpace(cell(row, nameCol))
+		if name == "" {
+			name = strings.TrimSpace(cell(row, firstCol) + " " + cell(row, lastCol))
+		}
+		// Zoom renders renamed participants as "Display (Original)"
+		if idx := strings.Index(name, " ("); idx > 0 && strings.HasSuffix(name, ")") {
+			name = name[:idx]
+		}
+
+		joined, hasJoin := parseAttendanceTime(cell(row, joinCol), meetingDate)
+		left, hasLeave := parseAttendanceTime(cell(row, leaveCol), meetingDate)
+		if hasJoin && hasLeave && left.Before(joined) {
+			// Meet clock times can roll past midnight
+			left = left.Add(24 * time.Hour)
+		}
+
+		duration, hasDuration := parseAttendanceDuration(cell(row, durationCol))
+		if !hasDuration && hasJoin && hasLeave {
+			duration = left.Sub(joined)
+		}
+
+		rec, ok := byEmail[email]
+		if !ok {
+			rec = &AttendanceRecord{Name: name, Email: email}
+			byEmail[email] = rec
+			order = append(order, email)
+		}
+		if rec.Name == "" {
+			rec.Name = name
+		}
+		rec.Duration += duration
+		if hasJoin && (rec.JoinedAt.IsZero() || joined.Before(rec.JoinedAt)) {
+			rec.JoinedAt = joined
+		}
+		if hasLeave && left.After(rec.LeftAt) {
+			rec.LeftAt = left
+		}
+	}
+
+	for _, email := range order {
+		report.Records = append(report.Records, byEmail[email])
+	}
+
+	if report.StartTime.IsZero() {
+		for _, rec := range report.Records {
+			if !rec.JoinedAt.IsZero() && (report.StartTime.IsZero() || rec.JoinedAt.Before(report.StartTime)) {
+				report.StartTime = rec.JoinedAt
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// ImportAttendance logs a meeting interaction for each participant that
+// matches a contact by email. Re-importing the same report is a no-op.
+func ImportAttendance(client *charm.Client, report *AttendanceReport, dryRun bool) (*AttendanceImportResult, error) {
+	result := &AttendanceImportResult{}
+
+	meetingKey := report.MeetingID
+	if meetingKey == "" {
+		meetingKey = report.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	for _, rec := range report.Records {
+		contact, err := client.FindContactByEmail(rec.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", rec.Email, err)
+		}
+		if contact == nil {
+			result.Unmatched = append(result.Unmatched, rec.Email)
+			continue
+		}
+
+		sourceID := fmt.Sprintf("%s:%s", meetingKey, rec.Email)
+		existing, err := client.FindSyncLogBySource(report.Source, sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check sync log: %w", err)
+		}
+		if existing != nil {
+			result.Skipped++
+			continue
+		}
+
+		if dryRun {
+			result.Logged++
+			continue
+		}
+
+		timestamp := rec.JoinedAt
+		if timestamp.IsZero() {
+			timestamp = report.StartTime
+		}
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		metadata := map[string]interface{}{
+			"source":           report.Source,
+			"duration_minutes": int(rec.Duration.Round(time.Minute) / time.Minute),
+		}
+		if report.MeetingID != "" {
+			metadata["meeting_id"] = report.MeetingID
+		}
+		if !rec.JoinedAt.IsZero() {
+			metadata["joined_at"] = rec.JoinedAt.Format(time.RFC3339)
+		}
+		if !rec.LeftAt.IsZero() {
+			metadata["left_at"] = rec.LeftAt.Format(time.RFC3339)
+		}
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+
+		notes := report.Topic
+		if notes == "" {
+			notes = fmt.Sprintf("%s meeting", attendanceSourceLabel(report.Source))
+		}
+
+		interaction := &charm.InteractionLog{
+			ContactID:       contact.ID,
+			ContactName:     contact.Name,
+			InteractionType: charm.InteractionMeeting,
+			Timestamp:       timestamp,
+			Notes:           notes,
+			Metadata:        string(metadataJSON),
+		}
+		if err := client.CreateInteractionLog(interaction); err != nil {
+			return nil, fmt.Errorf("failed to log interaction for %s: %w", rec.Email, err)
+		}
+
+		// The first imported meeting is how we met, unless that's already recorded
+		changed := contact.FillMet(timestamp, attendanceSourceLabel(report.Source), notes)
+		contacted := contact.LastContactedAt == nil || timestamp.After(*contact.LastContactedAt)
+		if contacted {
+			contact.LastContactedAt = &timestamp
+		}
+		if changed || contacted {
+			if err := client.UpdateContact(contact); err != nil {
+				return nil, fmt.Errorf("failed to update contact: %w", err)
+			}
+		}
+		if contacted {
+			if err := client.UpdateCadenceAfterInteraction(contact.ID, timestamp); err != nil {
+				return nil, fmt.Errorf("failed to update cadence: %w", err)
+			}
+		}
+
+		if err := client.CreateSyncLog(&charm.SyncLog{
+			SourceService: report.Source,
+			SourceID:      sourceID,
+			EntityType:    "interaction",
+			EntityID:      interaction.ID,
+			Metadata:      string(metadataJSON),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to record sync log: %w", err)
+		}
+
+		result.Logged++
+	}
+
+	return result, nil
+}
+
+func attendanceSourceLabel(source string) string {
+	switch source {
+	case AttendanceSourceMeet:
+		return "Google Meet"
+	case AttendanceSourceZoom:
+		return "Zoom"
+	}
+	return source
+}
+
+func normalizeHeader(row []string) []string {
+	cols := make([]string, len(row))
+	for i, c := range row {
+		cols[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(c, "\ufeff")))
+	}
+	return cols
+}
+
+func columnIndex(cols []string, substr string) int {
+	for i, c := range cols {
+		if strings.Contains(c, substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func exactColumn(cols []string, name string) int {
+	for i, c := range cols {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func cell(row []string, idx int) string {
+	if idx < 0 || idx >= len(row) {
+		return ""
+	}
+	return row[idx]
+}
+
+func parseAttendanceTime(value string, meetingDate time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range attendanceTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+
+	for _, layout := range attendanceClockLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			y, m, d := meetingDate.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.Local), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseAttendanceDuration accepts plain minutes (Zoom), "1 hr 5 min" (Meet)
+// and "h:mm:ss" clock durations.
+func parseAttendanceDuration(value string) (time.Duration, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, false
+	}
+
+	if minutes, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(minutes * float64(time.Minute)), true
+	}
+
+	if strings.Contains(value, ":") {
+		// h:mm:ss or mm:ss
+		var seconds time.Duration
+		for _, p := range strings.Split(value, ":") {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return 0, false
+			}
+			seconds = seconds*60 + time.Duration(n)
+		}
+		return seconds * time.Second, true
+	}
+
+	matches := durationPartRe.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, m := range matches {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		switch m[2][0] {
+		case 'h':
+			total += time.Duration(n * float64(time.Hour))
+		case 'm':
+			total += time.Duration(n * float64(time.Minute))
+		case 's':
+			total += time.Duration(n * float64(time.Second))
+		}
+	}
+	return total, true
+}
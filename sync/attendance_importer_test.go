@@ -0,0 +1,171 @@
+// ABOUTME: Tests for Zoom/Google Meet attendance CSV importer
+// ABOUTME: Verifies parsing of both export formats and idempotent interaction logging
+package sync
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const zoomAttendanceCSV = `Meeting ID,Topic,Start Time,End Time,User Email,Duration (Minutes),Participants
+81234567890,Weekly Sync,03/04/2024 10:00:00 AM,03/04/2024 11:00:00 AM,host@example.com,60,3
+
+Name (Original Name),User Email,Join Time,Leave Time,Duration (Minutes),Guest
+Alice Smith,alice@acme.com,03/04/2024 10:01:00 AM,03/04/2024 10:31:00 AM,30,No
+Alice Smith,alice@acme.com,03/04/2024 10:40:00 AM,03/04/2024 11:00:00 AM,20,No
+Bob (Robert Jones),bob@other.com,03/04/2024 10:05:00 AM,03/04/2024 10:50:00 AM,45,Yes
+`
+
+const meetAttendanceCSV = `First name,Last name,Email,Duration,Time joined,Time exited
+Alice,Smith,Alice@Acme.com,1 hr 5 min,9:58 AM,11:03 AM
+Carol,White,carol@acme.com,45 min,10:15 AM,11:00 AM
+`
+
+func TestParseAttendanceCSVZoom(t *testing.T) {
+	report, err := ParseAttendanceCSV(strings.NewReader(zoomAttendanceCSV), time.Now())
+	if err != nil {
+		t.Fatalf("ParseAttendanceCSV failed: %v", err)
+	}
+
+	if report.Source != AttendanceSourceZoom {
+		t.Errorf("expected zoom source, got %s", report.Source)
+	}
+	if report.MeetingID != "81234567890" || report.Topic != "Weekly Sync" {
+		t.Errorf("unexpected meeting metadata: %q %q", report.MeetingID, report.Topic)
+	}
+	if len(report.Records) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(report.Records))
+	}
+
+	alice := report.Records[0]
+	if alice.Duration != 50*time.Minute {
+		t.Errorf("expected rejoins to be summed to 50m, got %v", alice.Duration)
+	}
+	if alice.JoinedAt.Hour() != 10 || alice.JoinedAt.Minute() != 1 {
+		t.Errorf("expected earliest join 10:01, got %v", alice.JoinedAt)
+	}
+	if alice.LeftAt.Hour() != 11 {
+		t.Errorf("expected latest leave 11:00, got %v", alice.LeftAt)
+	}
+
+	if report.Records[1].Name != "Bob" {
+		t.Errorf("expected renamed participant to be 'Bob', got %q", report.Records[1].Name)
+	}
+}
+
+func TestParseAttendanceCSVMeet(t *testing.T) {
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)
+	report, err := ParseAttendanceCSV(strings.NewReader(meetAttendanceCSV), date)
+	if err != nil {
+		t.Fatalf("ParseAttendanceCSV failed: %v", err)
+	}
+
+	if report.Source != AttendanceSourceMeet {
+		t.Errorf("expected meet source, got %s", report.Source)
+	}
+	if len(report.Records) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(report.Records))
+	}
+
+	alice := report.Records[0]
+	if alice.Email != "alice@acme.com" || alice.Name != "Alice Smith" {
+		t.Errorf("unexpected participant: %+v", alice)
+	}
+	if alice.Duration != 65*time.Minute {
+		t.Errorf("expected 65m, got %v", alice.Duration)
+	}
+	if y, m, d := alice.JoinedAt.Date(); y != 2024 || m != 3 || d != 5 {
+		t.Errorf("expected join on meeting date, got %v", alice.JoinedAt)
+	}
+}
+
+func TestParseAttendanceDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"45", 45 * time.Minute},
+		{"1 hr 5 min", 65 * time.Minute},
+		{"2 hours", 2 * time.Hour},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"30 sec", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		got, ok := parseAttendanceDuration(tt.in)
+		if !ok || got != tt.want {
+			t.Errorf("parseAttendanceDuration(%q) = %v, %v; want %v", tt.in, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := parseAttendanceDuration("soon"); ok {
+		t.Error("expected unparseable duration to fail")
+	}
+}
+
+func TestImportAttendance(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	alice := &charm.Contact{Name: "Alice Smith", Email: "alice@acme.com"}
+	if err := client.CreateContact(alice); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	report, err := ParseAttendanceCSV(strings.NewReader(zoomAttendanceCSV), time.Now())
+	if err != nil {
+		t.Fatalf("ParseAttendanceCSV failed: %v", err)
+	}
+
+	result, err := ImportAttendance(client, report, false)
+	if err != nil {
+		t.Fatalf("ImportAttendance failed: %v", err)
+	}
+	if result.Logged != 1 {
+		t.Errorf("expected 1 logged interaction, got %d", result.Logged)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "bob@other.com" {
+		t.Errorf("expected bob to be unmatched, got %v", result.Unmatched)
+	}
+
+	logs, err := client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &alice.ID})
+	if err != nil {
+		t.Fatalf("failed to list interactions: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 interaction, got %d", len(logs))
+	}
+	if logs[0].Notes != "Weekly Sync" || logs[0].InteractionType != charm.InteractionMeeting {
+		t.Errorf("unexpected interaction: %+v", logs[0])
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(logs[0].Metadata), &metadata); err != nil {
+		t.Fatalf("invalid metadata: %v", err)
+	}
+	if metadata["duration_minutes"] != float64(50) {
+		t.Errorf("expected duration_minutes 50, got %v", metadata["duration_minutes"])
+	}
+
+	updated, err := client.GetContact(alice.ID)
+	if err != nil {
+		t.Fatalf("failed to get contact: %v", err)
+	}
+	if updated.LastContactedAt == nil {
+		t.Error("expected last contacted to be set")
+	}
+	if updated.MetDate == nil || updated.MetAt != "Weekly Sync" {
+		t.Errorf("expected how we met from the meeting, got %q", updated.HowWeMet())
+	}
+
+	// Importing again is a no-op
+	result, err = ImportAttendance(client, report, false)
+	if err != nil {
+		t.Fatalf("second ImportAttendance failed: %v", err)
+	}
+	if result.Logged != 0 || result.Skipped != 1 {
+		t.Errorf("expected re-import to skip, got logged=%d skipped=%d", result.Logged, result.Skipped)
+	}
+}
@@ -0,0 +1,128 @@
+// ABOUTME: Calendar availability from Google Calendar free/busy
+// ABOUTME: Finds open slots within working hours for proposing catch-ups
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// TimeSlot is a span of time on the calendar.
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// String formats the slot compactly, e.g. "Thu 10–11" or "Thu 10:30–11:30".
+func (s TimeSlot) String() string {
+	return s.Start.Format("Mon") + " " + slotClock(s.Start) + "–" + slotClock(s.End)
+}
+
+func slotClock(t time.Time) string {
+	if t.Minute() == 0 {
+		return fmt.Sprintf("%d", t.Hour())
+	}
+	return fmt.Sprintf("%d:%02d", t.Hour(), t.Minute())
+}
+
+// SlotOptions controls which open time counts as a slot.
+type SlotOptions struct {
+	Duration time.Duration // slot length (default 1h)
+	DayStart int           // working hours start, hour of day (default 9)
+	DayEnd   int           // working hours end, hour of day (default 17)
+	Weekends bool          // also suggest Saturdays and Sundays
+}
+
+func (o SlotOptions) withDefaults() SlotOptions {
+	if o.Duration <= 0 {
+		o.Duration = time.Hour
+	}
+	if o.DayStart == 0 && o.DayEnd == 0 {
+		o.DayStart, o.DayEnd = 9, 17
+	}
+	return o
+}
+
+// slotStep is the granularity slot starts are aligned to.
+const slotStep = 30 * time.Minute
+
+// QueryBusy returns the busy periods on the primary calendar between from
+// and to, using the free/busy API (covered by the calendar.readonly scope).
+func QueryBusy(ctx context.Context, service *calendar.Service, from, to time.Time) ([]TimeSlot, error) {
+	response, err := service.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: from.Format(time.RFC3339),
+		TimeMax: to.Format(time.RFC3339),
+		Items:   []*calendar.FreeBusyRequestItem{{Id: "primary"}},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query free/busy: %w", err)
+	}
+
+	var busy []TimeSlot
+	for id, cal := range response.Calendars {
+		for _, e := range cal.Errors {
+			return nil, fmt.Errorf("free/busy unavailable for %s: %s", id, e.Reason)
+		}
+		for _, period := range cal.Busy {
+			start, err := time.Parse(time.RFC3339, period.Start)
+			if err != nil {
+				continue
+			}
+			end, err := time.Parse(time.RFC3339, period.End)
+			if err != nil {
+				continue
+			}
+			busy = append(busy, TimeSlot{Start: start, End: end})
+		}
+	}
+	return busy, nil
+}
+
+// FreeSlots returns non-overlapping open slots between from and to, earliest
+// first, that fall within working hours in from's time zone and don't
+// overlap any busy period. Slots start on the half hour.
+func FreeSlots(busy []TimeSlot, from, to time.Time, opts SlotOptions) []TimeSlot {
+	opts = opts.withDefaults()
+	busy = append([]TimeSlot(nil), busy...)
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	loc := from.Location()
+	var slots []TimeSlot
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !opts.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		dayEnd := time.Date(day.Year(), day.Month(), day.Day(), opts.DayEnd, 0, 0, 0, loc)
+		start := time.Date(day.Year(), day.Month(), day.Day(), opts.DayStart, 0, 0, 0, loc)
+		for start.Before(from) {
+			start = start.Add(slotStep)
+		}
+		for end := start.Add(opts.Duration); !end.After(dayEnd) && !end.After(to); end = start.Add(opts.Duration) {
+			if conflict := overlapping(busy, start, end); conflict != nil {
+				// Resume at the first half hour after the conflict ends
+				for start.Before(conflict.End) {
+					start = start.Add(slotStep)
+				}
+				continue
+			}
+			slots = append(slots, TimeSlot{Start: start, End: end})
+			start = end
+		}
+	}
+	return slots
+}
+
+// overlapping returns the busy period overlapping [start, end), or nil.
+func overlapping(busy []TimeSlot, start, end time.Time) *TimeSlot {
+	for i := range busy {
+		if busy[i].Start.Before(end) && busy[i].End.After(start) {
+			return &busy[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// ABOUTME: Tests for calendar availability
+// ABOUTME: Verifies free/busy parsing, working hours, weekends, and slot formatting
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestFreeSlots(t *testing.T) {
+	loc := time.FixedZone("test", -5*3600)
+	// Thursday 2024-03-07, 9:40
+	now := time.Date(2024, 3, 7, 9, 40, 0, 0, loc)
+	busy := []TimeSlot{
+		{Start: time.Date(2024, 3, 7, 11, 0, 0, 0, loc), End: time.Date(2024, 3, 7, 15, 15, 0, 0, loc)},
+	}
+
+	slots := FreeSlots(busy, now, now.AddDate(0, 0, 5), SlotOptions{})
+	var got []string
+	for _, slot := range slots {
+		got = append(got, slot.String())
+	}
+
+	want := []string{
+		"Thu 10–11", "Thu 15:30–16:30",
+		"Fri 9–10", "Fri 10–11", "Fri 11–12", "Fri 12–13", "Fri 13–14", "Fri 14–15", "Fri 15–16", "Fri 16–17",
+		"Mon 9–10",
+	}
+	if len(got) < len(want) {
+		t.Fatalf("expected at least %d slots, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("slot %d = %q, want %q (all: %v)", i, got[i], want[i], got)
+		}
+	}
+
+	weekend := FreeSlots(nil, time.Date(2024, 3, 9, 8, 0, 0, 0, loc), time.Date(2024, 3, 10, 0, 0, 0, 0, loc), SlotOptions{Duration: 30 * time.Minute, Weekends: true})
+	if len(weekend) != 16 {
+		t.Errorf("expected 16 half-hour weekend slots, got %d", len(weekend))
+	}
+}
+
+func TestQueryBusy(t *testing.T) {
+	fake := newFakeGoogle(t)
+	fake.busy = []*calendar.TimePeriod{
+		{Start: "2024-03-07T16:00:00Z", End: "2024-03-07T17:00:00Z"},
+	}
+
+	from := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+	busy, err := QueryBusy(context.Background(), fake.calendarService(), from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("QueryBusy failed: %v", err)
+	}
+	if len(busy) != 1 || !busy[0].Start.Equal(time.Date(2024, 3, 7, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected busy periods: %+v", busy)
+	}
+	if len(fake.requested("/calendar/v3/freeBusy")) != 1 {
+		t.Error("expected a free/busy request")
+	}
+}
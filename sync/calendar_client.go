@@ -8,7 +8,6 @@ import (
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/calendar/v3"
-	"google.golang.org/api/option"
 )
 
 // NewCalendarClient creates a Google Calendar API service from an OAuth token.
@@ -17,17 +16,9 @@ func NewCalendarClient(token *oauth2.Token) (*calendar.Service, error) {
 		return nil, fmt.Errorf("token cannot be nil")
 	}
 
-	ctx := context.Background()
-	config := NewOAuthConfig()
-
 	// Create HTTP client from token
-	client := config.Client(ctx, token)
-
-	// Create Calendar API service
-	service, err := calendar.NewService(ctx, option.WithHTTPClient(client))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create calendar service: %w", err)
-	}
+	config := NewOAuthConfig()
+	client := config.Client(context.Background(), token)
 
-	return service, nil
+	return NewCalendarService(client, "")
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -91,16 +92,8 @@ func ImportCalendar(database *sql.DB, client *calendar.Service, initial bool) er
 	}
 	userEmail := calendarInfo.Id
 
-	// Load all existing contacts for matching
-	allContacts, err := db.FindContacts(database, "", nil, 10000)
-	if err != nil {
-		errMsg := err.Error()
-		_ = db.UpdateSyncStatus(database, calendarService, "error", &errMsg)
-		return fmt.Errorf("failed to load existing contacts: %w", err)
-	}
-
 	// Create ContactMatcher for deduplication
-	matcher := NewContactMatcher(allContacts)
+	matcher := NewIndexedContactMatcher(database)
 
 	// Get current sync state
 	state, err := db.GetSyncState(database, calendarService)
@@ -149,7 +142,7 @@ func ImportCalendar(database *sql.DB, client *calendar.Service, initial bool) er
 		if err != nil {
 			// Handle 410 Gone error (invalid sync token)
 			apiErr := &googleapi.Error{}
-			if errors.As(err, &apiErr) {
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
 				fmt.Println("  → Sync token invalid, falling back to time-based sync...")
 
 				// Fall back to time-based sync using last sync time or 6 months ago
@@ -160,11 +153,11 @@ func ImportCalendar(database *sql.DB, client *calendar.Service, initial bool) er
 					fallbackTime = time.Now().AddDate(0, -6, 0)
 				}
 
-				// Rebuild call with timeMin instead of sync token and reset pagination
+				// Rebuild call with timeMin instead of sync token and reset pagination.
+				// No OrderBy, so the last page still returns a fresh sync token.
 				call = client.Events.List("primary").
 					MaxResults(maxResults).
 					SingleEvents(true).
-					OrderBy("startTime").
 					TimeMin(fallbackTime.Format(time.RFC3339))
 				totalEvents = 0
 
@@ -316,13 +309,8 @@ func extractContacts(database *sql.DB, event *calendar.Event, userEmail string,
 			continue
 		}
 
-		// Check if contact exists using ContactMatcher
-		existingContact, found := matcher.FindMatch(attendee.Email, attendee.DisplayName)
-		if found {
-			// Use existing contact ID
-			contactIDs = append(contactIDs, existingContact.ID)
-		} else {
-			// Create new contact
+		// Match an existing contact, or create one
+		contact, _, err := matcher.FindOrCreate(attendee.Email, func() (*models.Contact, error) {
 			newContact := &models.Contact{
 				Name:  attendee.DisplayName,
 				Email: attendee.Email,
@@ -330,11 +318,12 @@ func extractContacts(database *sql.DB, event *calendar.Event, userEmail string,
 			if err := db.CreateContact(database, newContact); err != nil {
 				return nil, fmt.Errorf("failed to create contact for %s: %w", attendee.Email, err)
 			}
-			contactIDs = append(contactIDs, newContact.ID)
-
-			// Add to matcher to prevent duplicates within the same import session
-			matcher.AddContact(newContact)
+			return newContact, nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		contactIDs = append(contactIDs, contact.ID)
 	}
 
 	return contactIDs, nil
@@ -0,0 +1,183 @@
+// ABOUTME: Importer for company data CSVs keyed by domain, such as Crunchbase exports
+// ABOUTME: Sets employee counts, funding stages, and headquarters on matching companies or creates them
+package sync
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// CompanyRecord is one row of a company data CSV.
+type CompanyRecord struct {
+	Name         string
+	Domain       string
+	Industry     string
+	Employees    string // a count or range, see charm.ParseEmployeeCount
+	FundingStage string
+	HQLocation   string
+}
+
+// CompanyImportOptions controls a company data import.
+type CompanyImportOptions struct {
+	UpdateOnly bool // only update existing companies; skip unknown domains
+	DryRun     bool
+}
+
+// CompanyImportResult summarizes a company data import.
+type CompanyImportResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Skipped   int
+	Problems  []string
+}
+
+// companyCSVColumns maps each field to the header names that hold it, covering
+// Crunchbase's web and bulk exports and plain spreadsheets.
+var companyCSVColumns = map[string][]string{
+	"name":      {"organization name", "company name", "name", "company", "organization"},
+	"domain":    {"domain", "website", "company website", "website url", "homepage url", "homepage_url"},
+	"industry":  {"industries", "industry", "category_list"},
+	"employees": {"number of employees", "employees", "employee count", "headcount", "num_employees_enum", "employee_count"},
+	"stage":     {"last funding type", "funding stage", "stage", "last_funding_type", "funding_stage"},
+	"hq":        {"headquarters location", "headquarters", "hq", "hq location", "location"},
+}
+
+// ParseCompaniesCSV parses a CSV of company data with a header row. Rows are
+// matched to companies by the domain or website column.
+func ParseCompaniesCSV(r io.Reader) ([]*CompanyRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty CSV")
+	}
+
+	cols := normalizeHeader(rows[0])
+	columns := make(map[string]int)
+	for field, names := range companyCSVColumns {
+		for _, name := range names {
+			if idx := exactColumn(cols, name); idx >= 0 {
+				columns[field] = idx
+				break
+			}
+		}
+	}
+	if _, ok := columns["domain"]; !ok {
+		return nil, fmt.Errorf("no domain or website column found")
+	}
+
+	get := func(row []string, field string) string {
+		idx, ok := columns[field]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(cell(row, idx))
+	}
+
+	var records []*CompanyRecord
+	for _, row := range rows[1:] {
+		record := &CompanyRecord{
+			Name:         get(row, "name"),
+			Domain:       charm.DomainFromWebsite(get(row, "domain")),
+			Industry:     get(row, "industry"),
+			Employees:    get(row, "employees"),
+			FundingStage: get(row, "stage"),
+			HQLocation:   get(row, "hq"),
+		}
+		if *record == (CompanyRecord{}) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ImportCompanyRecords applies each record to the company with its domain
+// (or, failing that, its name). Size, funding stage, and headquarters are
+// replaced by the record's values; name, domain, and industry are only filled
+// in when empty. Unknown domains create a company unless opts.UpdateOnly.
+func ImportCompanyRecords(client *charm.Client, records []*CompanyRecord, opts CompanyImportOptions) (*CompanyImportResult, error) {
+	companies, err := client.ListCompanies(&charm.CompanyFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+
+	result := &CompanyImportResult{}
+	problem := func(i int, format string, args ...interface{}) {
+		result.Skipped++
+		result.Problems = append(result.Problems, fmt.Sprintf("row %d: %s", i+1, fmt.Sprintf(format, args...)))
+	}
+
+	for i, record := range records {
+		if record.Domain == "" {
+			problem(i, "no domain for %q", record.Name)
+			continue
+		}
+		count, max, err := charm.ParseEmployeeCount(record.Employees)
+		if err != nil {
+			problem(i, "%v", err)
+			continue
+		}
+
+		company := charm.MatchDomainCompany(companies, record.Domain, record.Name)
+		isNew := company == nil
+		if isNew {
+			if opts.UpdateOnly {
+				result.Skipped++
+				continue
+			}
+			company = &charm.Company{Name: record.Name}
+			if company.Name == "" {
+				company.Name = charm.CompanyNameFromDomain(record.Domain)
+			}
+		}
+
+		changed := false
+		set := func(field *string, value string, replace bool) {
+			if value != "" && *field != value && (replace || *field == "") {
+				*field = value
+				changed = true
+			}
+		}
+		set(&company.Domain, record.Domain, false)
+		set(&company.Industry, record.Industry, false)
+		set(&company.FundingStage, charm.NormalizeFundingStage(record.FundingStage), true)
+		set(&company.HQLocation, record.HQLocation, true)
+		if count > 0 && (company.EmployeeCount != count || company.EmployeeCountMax != max) {
+			company.EmployeeCount, company.EmployeeCountMax = count, max
+			changed = true
+		}
+
+		switch {
+		case isNew:
+			if !opts.DryRun {
+				if err := client.CreateCompany(company); err != nil {
+					return nil, fmt.Errorf("failed to create company %s: %w", company.Name, err)
+				}
+			}
+			companies = append(companies, company)
+			result.Created++
+		case !changed:
+			result.Unchanged++
+		default:
+			if !opts.DryRun {
+				if err := client.UpdateCompany(company); err != nil {
+					return nil, fmt.Errorf("failed to update company %s: %w", company.Name, err)
+				}
+			}
+			result.Updated++
+		}
+	}
+	return result, nil
+}
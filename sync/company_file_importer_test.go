@@ -0,0 +1,101 @@
+// ABOUTME: Tests for the company data CSV importer
+// ABOUTME: Verifies Crunchbase header detection, domain matching, field updates, and update-only mode
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const crunchbaseCompaniesCSV = `Organization Name,Organization Name URL,Website,Industries,Headquarters Location,Number of Employees,Last Funding Type
+Acme,https://www.crunchbase.com/organization/acme,https://www.acme.com/,"Software, SaaS","Chicago, Illinois, United States",11-50,Series A
+Globex,https://www.crunchbase.com/organization/globex,globex.io,Energy,"Springfield, Oregon, United States",251-500,Seed
+No Site,https://www.crunchbase.com/organization/nosite,,Retail,,1-10,Pre-Seed
+Initech,https://www.crunchbase.com/organization/initech,initech.com,Software,,lots,Seed
+`
+
+func TestParseCompaniesCSV(t *testing.T) {
+	records, err := ParseCompaniesCSV(strings.NewReader(crunchbaseCompaniesCSV))
+	if err != nil {
+		t.Fatalf("ParseCompaniesCSV failed: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+	want := CompanyRecord{
+		Name:         "Acme",
+		Domain:       "acme.com",
+		Industry:     "Software, SaaS",
+		Employees:    "11-50",
+		FundingStage: "Series A",
+		HQLocation:   "Chicago, Illinois, United States",
+	}
+	if *records[0] != want {
+		t.Errorf("expected %+v, got %+v", want, *records[0])
+	}
+
+	if _, err := ParseCompaniesCSV(strings.NewReader("name,employees\nAcme,5\n")); err == nil {
+		t.Error("expected an error without a domain or website column")
+	}
+}
+
+func TestImportCompanyRecords(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	acme := &charm.Company{Name: "Acme Corp", Domain: "acme.com", Industry: "Manufacturing", FundingStage: charm.FundingSeed}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+
+	records, err := ParseCompaniesCSV(strings.NewReader(crunchbaseCompaniesCSV))
+	if err != nil {
+		t.Fatalf("ParseCompaniesCSV failed: %v", err)
+	}
+
+	result, err := ImportCompanyRecords(client, records, CompanyImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Skipped != 2 {
+		t.Errorf("unexpected dry run result: %+v", result)
+	}
+	if companies, _ := client.ListCompanies(&charm.CompanyFilter{}); len(companies) != 1 {
+		t.Errorf("dry run should not create companies, found %d", len(companies))
+	}
+
+	result, err = ImportCompanyRecords(client, records, CompanyImportOptions{UpdateOnly: true})
+	if err != nil {
+		t.Fatalf("update-only import failed: %v", err)
+	}
+	if result.Created != 0 || result.Updated != 1 || result.Skipped != 3 || len(result.Problems) != 2 {
+		t.Errorf("unexpected update-only result: %+v", result)
+	}
+
+	updated, err := client.GetCompany(acme.ID)
+	if err != nil {
+		t.Fatalf("GetCompany failed: %v", err)
+	}
+	if updated.Name != "Acme Corp" || updated.Industry != "Manufacturing" {
+		t.Errorf("name and industry should be kept, got %q, %q", updated.Name, updated.Industry)
+	}
+	if updated.FundingStage != charm.FundingSeriesA || updated.Employees() != "11-50" || updated.HQLocation != "Chicago, Illinois, United States" {
+		t.Errorf("firmographics not updated: %+v", updated)
+	}
+
+	result, err = ImportCompanyRecords(client, records, CompanyImportOptions{})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if result.Created != 1 || result.Unchanged != 1 {
+		t.Errorf("unexpected import result: %+v", result)
+	}
+	globex, err := client.ListCompanies(&charm.CompanyFilter{FundingStage: "seed", MinEmployees: 300})
+	if err != nil {
+		t.Fatalf("ListCompanies failed: %v", err)
+	}
+	if len(globex) != 1 || globex[0].Domain != "globex.io" || globex[0].Industry != "Energy" {
+		t.Errorf("expected Globex to be created with its fields, got %+v", globex)
+	}
+}
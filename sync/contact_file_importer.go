@@ -0,0 +1,439 @@
+// ABOUTME: Importer for contact lists exported as CSV or vCard files
+// ABOUTME: Dedupes against existing contacts, fills in missing fields, and links companies from email domains
+package sync
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// Contact file formats.
+const (
+	ContactFormatCSV   = "csv"
+	ContactFormatVCard = "vcard"
+)
+
+// ContactRecord is one contact read from an import file.
+type ContactRecord struct {
+	Name    string
+	Email   string
+	Phone   string
+	Title   string
+	Company string
+	Notes   string
+}
+
+// ContactImportResult summarizes a contact import. Merged contacts already
+// existed and had missing fields filled in; skipped ones had nothing new or
+// could not be imported (see Problems).
+type ContactImportResult struct {
+	Created          int
+	Merged           int
+	Skipped          int
+	CompaniesCreated []string
+	Problems         []string
+}
+
+// contactCSVColumns maps each field to the header names that hold it, covering
+// plain spreadsheets and Google Contacts, Outlook, and LinkedIn exports.
+var contactCSVColumns = map[string][]string{
+	"name":    {"name", "full name", "display name", "contact name"},
+	"first":   {"first name", "given name"},
+	"last":    {"last name", "family name", "surname"},
+	"email":   {"email", "e-mail", "email address", "e-mail address", "email 1 - value", "e-mail 1 - value", "primary email", "work email"},
+	"phone":   {"phone", "phone number", "mobile", "mobile phone", "phone 1 - value", "business phone", "primary phone"},
+	"title":   {"title", "job title", "position", "organization 1 - title", "organization title"},
+	"company": {"company", "company name", "organization", "organization 1 - name", "organization name"},
+	"notes":   {"notes", "note"},
+}
+
+// ParseContactFile reads contacts in the given format, or detects CSV or
+// vCard from the content when format is empty.
+func ParseContactFile(r io.Reader, format string) ([]*ContactRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contacts: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "":
+		trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+		if len(trimmed) >= 11 && strings.EqualFold(string(trimmed[:11]), "BEGIN:VCARD") {
+			return ParseVCards(bytes.NewReader(data))
+		}
+		return ParseContactsCSV(bytes.NewReader(data))
+	case ContactFormatCSV:
+		return ParseContactsCSV(bytes.NewReader(data))
+	case ContactFormatVCard, "vcf":
+		return ParseVCards(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported contact format %q (use csv or vcard)", format)
+	}
+}
+
+// ParseContactsCSV parses a CSV with a header row naming its columns. Rows
+// before the header, such as the notes LinkedIn puts atop its export, are
+// ignored.
+func ParseContactsCSV(r io.Reader) ([]*ContactRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	headerIdx := -1
+	var columns map[string]int
+	for i, row := range rows {
+		columns = contactColumns(normalizeHeader(row))
+		_, hasName := columns["name"]
+		_, hasFirst := columns["first"]
+		_, hasEmail := columns["email"]
+		if hasName || hasFirst || hasEmail {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, fmt.Errorf("no header row with a name or email column found")
+	}
+
+	get := func(row []string, field string) string {
+		idx, ok := columns[field]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(cell(row, idx))
+	}
+
+	var records []*ContactRecord
+	for _, row := range rows[headerIdx+1:] {
+		record := &ContactRecord{
+			Name:    get(row, "name"),
+			Email:   get(row, "email"),
+			Phone:   get(row, "phone"),
+			Title:   get(row, "title"),
+			Company: get(row, "company"),
+			Notes:   get(row, "notes"),
+		}
+		if record.Name == "" {
+			record.Name = strings.TrimSpace(get(row, "first") + " " + get(row, "last"))
+		}
+		if *record == (ContactRecord{}) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// contactColumns finds the index of each contact field in a normalized header.
+func contactColumns(cols []string) map[string]int {
+	columns := make(map[string]int)
+	for field, names := range contactCSVColumns {
+		for _, name := range names {
+			if idx := exactColumn(cols, name); idx >= 0 {
+				columns[field] = idx
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// ParseVCards parses vCard 2.1, 3.0, and 4.0 cards. Only the first email and
+// phone number of each card are kept.
+func ParseVCards(r io.Reader) ([]*ContactRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// Unfold continuation lines, which start with a space or tab
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vCard: %w", err)
+	}
+
+	var records []*ContactRecord
+	var card *ContactRecord
+	var structuredName string
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		params := strings.Split(key, ";")
+		// Drop a property group such as "item1.EMAIL"
+		property := strings.ToUpper(params[0])
+		if dot := strings.LastIndex(property, "."); dot >= 0 {
+			property = property[dot+1:]
+		}
+
+		switch {
+		case property == "BEGIN" && strings.EqualFold(strings.TrimSpace(value), "VCARD"):
+			card, structuredName = &ContactRecord{}, ""
+		case card == nil:
+			continue
+		case property == "END":
+			if card.Name == "" {
+				card.Name = structuredName
+			}
+			if *card != (ContactRecord{}) {
+				records = append(records, card)
+			}
+			card = nil
+		case property == "FN":
+			card.Name = unescapeVCard(value)
+		case property == "N":
+			// Family;Given;Additional;Prefix;Suffix
+			parts := splitVCard(value)
+			for len(parts) < 2 {
+				parts = append(parts, "")
+			}
+			structuredName = strings.TrimSpace(parts[1] + " " + parts[0])
+		case property == "EMAIL" && card.Email == "":
+			card.Email = strings.TrimSpace(unescapeVCard(value))
+		case property == "TEL" && card.Phone == "":
+			card.Phone = strings.TrimPrefix(strings.TrimSpace(unescapeVCard(value)), "tel:")
+		case property == "TITLE":
+			card.Title = unescapeVCard(value)
+		case property == "ORG":
+			card.Company = splitVCard(value)[0]
+		case property == "NOTE":
+			card.Notes = unescapeVCard(value)
+		}
+	}
+	return records, nil
+}
+
+// splitVCard splits a structured vCard value on unescaped semicolons.
+func splitVCard(value string) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+			continue
+		}
+		if value[i] == ';' {
+			parts = append(parts, strings.TrimSpace(unescapeVCard(current.String())))
+			current.Reset()
+			continue
+		}
+		current.WriteByte(value[i])
+	}
+	return append(parts, strings.TrimSpace(unescapeVCard(current.String())))
+}
+
+func unescapeVCard(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// contactImport tracks existing contacts and companies across one import so
+// each lookup doesn't reload the store, and so duplicates within the file
+// merge into the first copy.
+type contactImport struct {
+	client    *charm.Client
+	dryRun    bool
+	byEmail   map[string]*charm.Contact
+	byName    map[string][]*charm.Contact
+	companies []*charm.Company
+	result    *ContactImportResult
+}
+
+// ImportContactRecords adds each record as a contact. Records match existing
+// contacts by email, or without one by a name only a single contact has. A
+// match has its empty fields filled in (merged) or is left alone when the
+// record adds nothing (skipped). Contacts without a company are linked to the
+// company named in the record, or else the one their work email domain
+// belongs to, created when missing.
+//
+// This doesn't use sync's ContactMatcher: that matches the legacy SQLite
+// contacts rather than Charm ones, and only by email, since calendar and
+// Gmail names are display names that say little about who someone is. A
+// contact list is curated, and exports such as LinkedIn's often leave the
+// email out, so a unique name is worth matching here.
+func ImportContactRecords(client *charm.Client, records []*ContactRecord, dryRun bool) (*ContactImportResult, error) {
+	contacts, err := client.ListContacts(&charm.ContactFilter{IncludeArchived: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	companies, err := client.ListCompanies(&charm.CompanyFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies: %w", err)
+	}
+
+	imp := &contactImport{
+		client:    client,
+		dryRun:    dryRun,
+		byEmail:   make(map[string]*charm.Contact),
+		byName:    make(map[string][]*charm.Contact),
+		companies: companies,
+		result:    &ContactImportResult{},
+	}
+	for _, contact := range contacts {
+		imp.add(contact)
+	}
+
+	for i, record := range records {
+		if err := imp.importRecord(record); err != nil {
+			imp.result.Skipped++
+			imp.result.Problems = append(imp.result.Problems, fmt.Sprintf("record %d: %v", i+1, err))
+		}
+	}
+	sort.Strings(imp.result.CompaniesCreated)
+	return imp.result, nil
+}
+
+func (imp *contactImport) add(contact *charm.Contact) {
+	if email := normalizeEmail(contact.Email); email != "" {
+		imp.byEmail[email] = contact
+	}
+	name := strings.ToLower(strings.TrimSpace(contact.Name))
+	imp.byName[name] = append(imp.byName[name], contact)
+}
+
+func (imp *contactImport) match(record *ContactRecord) *charm.Contact {
+	if email := normalizeEmail(record.Email); email != "" {
+		return imp.byEmail[email]
+	}
+	if named := imp.byName[strings.ToLower(record.Name)]; len(named) == 1 {
+		return named[0]
+	}
+	return nil
+}
+
+func (imp *contactImport) importRecord(record *ContactRecord) error {
+	record.Name = strings.TrimSpace(record.Name)
+	record.Email = strings.TrimSpace(record.Email)
+	if record.Email != "" && !strings.Contains(record.Email, "@") {
+		return fmt.Errorf("invalid email %q", record.Email)
+	}
+	if record.Name == "" {
+		// An address is better than no name at all
+		record.Name = record.Email
+	}
+	if record.Name == "" {
+		return fmt.Errorf("no name or email")
+	}
+
+	existing := imp.match(record)
+	if existing == nil {
+		contact := &charm.Contact{
+			ID:    uuid.New(),
+			Name:  record.Name,
+			Email: record.Email,
+			Phone: record.Phone,
+			Title: record.Title,
+			Notes: record.Notes,
+		}
+		if err := imp.linkCompany(contact, record); err != nil {
+			return err
+		}
+		if !imp.dryRun {
+			if err := imp.client.CreateContact(contact); err != nil {
+				return fmt.Errorf("failed to create %s: %w", contact.Name, err)
+			}
+		}
+		imp.add(contact)
+		imp.result.Created++
+		return nil
+	}
+
+	changed := false
+	fill := func(field *string, value string) {
+		if *field == "" && strings.TrimSpace(value) != "" {
+			*field = strings.TrimSpace(value)
+			changed = true
+		}
+	}
+	fill(&existing.Phone, record.Phone)
+	fill(&existing.Title, record.Title)
+	if notes := strings.TrimSpace(record.Notes); notes != "" && !strings.Contains(existing.Notes, notes) {
+		existing.Notes = strings.TrimSpace(existing.Notes + "\n\n" + notes)
+		changed = true
+	}
+	if existing.CompanyID == nil {
+		if err := imp.linkCompany(existing, record); err != nil {
+			return err
+		}
+		changed = changed || existing.CompanyID != nil
+	}
+
+	if !changed {
+		imp.result.Skipped++
+		return nil
+	}
+	if !imp.dryRun {
+		if err := imp.client.UpdateContact(existing); err != nil {
+			return fmt.Errorf("failed to update %s: %w", existing.Name, err)
+		}
+	}
+	imp.result.Merged++
+	return nil
+}
+
+// linkCompany sets the contact's company from the record's company name or
+// its work email domain, creating the company when none matches.
+func (imp *contactImport) linkCompany(contact *charm.Contact, record *ContactRecord) error {
+	domain := charm.EmailDomain(contact.Email)
+	if charm.IsCommonEmailDomain(domain) {
+		domain = ""
+	}
+
+	var company *charm.Company
+	name := strings.TrimSpace(record.Company)
+	switch {
+	case name != "":
+		for _, c := range imp.companies {
+			if strings.EqualFold(c.Name, name) {
+				company = c
+				break
+			}
+		}
+		if company == nil {
+			company = &charm.Company{Name: name, Domain: domain}
+		}
+	case domain != "":
+		company = charm.MatchDomainCompany(imp.companies, domain, charm.CompanyNameFromDomain(domain))
+		if company == nil {
+			company = &charm.Company{Name: charm.CompanyNameFromDomain(domain), Domain: domain}
+		}
+	default:
+		return nil
+	}
+
+	if company.ID == uuid.Nil {
+		company.ID = uuid.New()
+		if !imp.dryRun {
+			if err := imp.client.CreateCompany(company); err != nil {
+				return fmt.Errorf("failed to create company %s: %w", company.Name, err)
+			}
+		}
+		imp.companies = append(imp.companies, company)
+		imp.result.CompaniesCreated = append(imp.result.CompaniesCreated, company.Name)
+	}
+	contact.CompanyID = &company.ID
+	contact.CompanyName = company.Name
+	return nil
+}
@@ -0,0 +1,148 @@
+// ABOUTME: Tests for the CSV and vCard contact importer
+// ABOUTME: Verifies header detection, vCard unfolding, deduplication, merging, and company linking
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const linkedInContactsCSV = `Notes:
+"When exporting your connection data, you may notice that some of the email addresses are missing."
+
+First Name,Last Name,URL,Email Address,Company,Position,Connected On
+Alice,Smith,https://www.linkedin.com/in/alice,alice@acme.com,Acme,CTO,12 Mar 2024
+Bob,Jones,https://www.linkedin.com/in/bob,,Globex,Engineer,01 Feb 2023
+`
+
+const contactsVCard = "BEGIN:VCARD\r\n" +
+	"VERSION:3.0\r\n" +
+	"FN:Carol White\r\n" +
+	"N:White;Carol;;;\r\n" +
+	"item1.EMAIL;TYPE=INTERNET:carol@initech.io\r\n" +
+	"EMAIL;TYPE=HOME:carol@gmail.com\r\n" +
+	"TEL;TYPE=CELL:+1 555 0100\r\n" +
+	"ORG:Initech;Platform\r\n" +
+	"NOTE:Met at GopherCon\\, loves\r\n" +
+	"  climbing\r\n" +
+	"END:VCARD\r\n" +
+	"BEGIN:VCARD\r\n" +
+	"VERSION:4.0\r\n" +
+	"N:Brown;Dan;;;\r\n" +
+	"END:VCARD\r\n"
+
+func TestParseContactsCSV(t *testing.T) {
+	records, err := ParseContactFile(strings.NewReader(linkedInContactsCSV), "")
+	if err != nil {
+		t.Fatalf("ParseContactFile failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	want := ContactRecord{Name: "Alice Smith", Email: "alice@acme.com", Title: "CTO", Company: "Acme"}
+	if *records[0] != want {
+		t.Errorf("expected %+v, got %+v", want, *records[0])
+	}
+	if records[1].Name != "Bob Jones" || records[1].Email != "" {
+		t.Errorf("unexpected second record: %+v", *records[1])
+	}
+
+	if _, err := ParseContactsCSV(strings.NewReader("foo,bar\n1,2\n")); err == nil {
+		t.Error("expected an error without a name or email column")
+	}
+}
+
+func TestParseVCards(t *testing.T) {
+	records, err := ParseContactFile(strings.NewReader(contactsVCard), "")
+	if err != nil {
+		t.Fatalf("ParseContactFile failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	want := ContactRecord{Name: "Carol White", Email: "carol@initech.io", Phone: "+1 555 0100", Company: "Initech", Notes: "Met at GopherCon, loves climbing"}
+	if *records[0] != want {
+		t.Errorf("expected %+v, got %+v", want, *records[0])
+	}
+	if records[1].Name != "Dan Brown" {
+		t.Errorf("expected the name from N, got %q", records[1].Name)
+	}
+}
+
+func TestImportContactRecords(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	acme := &charm.Company{Name: "Acme Corp", Domain: "acme.com"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	existing := &charm.Contact{Name: "Alice Smith", Email: "Alice@Acme.com"}
+	unchanged := &charm.Contact{Name: "Erin Black", Phone: "555-0199"}
+	for _, contact := range []*charm.Contact{existing, unchanged} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	records := []*ContactRecord{
+		{Name: "Alice S.", Email: "alice@acme.com", Phone: "555-0100", Title: "CTO"}, // merged by email
+		{Name: "Bob Jones", Email: "bob@globex.com"},                                 // created, with a new company
+		{Name: "Bobby", Email: "BOB@globex.com", Title: "Engineer"},                  // merged into the row above
+		{Name: "Carol White", Email: "carol@gmail.com", Company: "Initech"},          // created, named company
+		{Name: "Erin Black", Phone: "555-0199"},                                      // skipped, nothing new
+		{Name: "", Email: ""},                                                        // skipped, problem
+		{Name: "Frank", Email: "not-an-email"},                                       // skipped, problem
+	}
+
+	dryRun, err := ImportContactRecords(client, records, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if contacts, _ := client.ListContacts(nil); len(contacts) != 2 {
+		t.Errorf("expected the dry run to write nothing, got %d contacts", len(contacts))
+	}
+
+	result, err := ImportContactRecords(client, records, false)
+	if err != nil {
+		t.Fatalf("ImportContactRecords failed: %v", err)
+	}
+	for name, r := range map[string]*ContactImportResult{"dry run": dryRun, "import": result} {
+		if r.Created != 2 || r.Merged != 2 || r.Skipped != 3 || len(r.Problems) != 2 {
+			t.Errorf("%s: expected 2 created, 2 merged, 3 skipped with 2 problems, got %+v", name, r)
+		}
+		if strings.Join(r.CompaniesCreated, ",") != "Globex,Initech" {
+			t.Errorf("%s: expected Globex and Initech created, got %v", name, r.CompaniesCreated)
+		}
+	}
+
+	alice, err := client.GetContact(existing.ID)
+	if err != nil {
+		t.Fatalf("GetContact failed: %v", err)
+	}
+	if alice.Name != "Alice Smith" || alice.Phone != "555-0100" || alice.Title != "CTO" || alice.CompanyName != "Acme Corp" {
+		t.Errorf("expected missing fields filled and the Acme domain linked, got %+v", alice)
+	}
+
+	bob, err := client.FindContactByEmail("bob@globex.com")
+	if err != nil || bob == nil {
+		t.Fatalf("expected Bob to be created: %v", err)
+	}
+	if bob.Title != "Engineer" || bob.CompanyName != "Globex" {
+		t.Errorf("expected the duplicate row merged and Globex linked, got %+v", bob)
+	}
+	globex, err := client.FindCompanyByName("Globex")
+	if err != nil || globex == nil || globex.Domain != "globex.com" {
+		t.Errorf("expected Globex created with its domain, got %+v, %v", globex, err)
+	}
+
+	// Importing again finds everything already there
+	again, err := ImportContactRecords(client, records[:5], false)
+	if err != nil {
+		t.Fatalf("second import failed: %v", err)
+	}
+	if again.Created != 0 || again.Merged != 0 || again.Skipped != 5 {
+		t.Errorf("expected a second import to skip everything, got %+v", again)
+	}
+}
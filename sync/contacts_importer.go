@@ -29,14 +29,18 @@ type GoogleContact struct {
 
 func NewContactsImporter(database *sql.DB) *ContactsImporter {
 	return &ContactsImporter{
-		db: database,
+		db:      database,
+		matcher: NewIndexedContactMatcher(database),
 	}
 }
 
 // ImportContact imports a single contact from Google.
 func (ci *ContactsImporter) ImportContact(gc *GoogleContact) (bool, error) {
 	// Check for existing contact
-	existing, found := ci.matcher.FindMatch(gc.Email, gc.Name)
+	existing, found, err := ci.matcher.FindMatch(gc.Email)
+	if err != nil {
+		return false, err
+	}
 	if found {
 		// Update existing contact if needed
 		_, err := ci.updateContact(existing, gc)
@@ -93,16 +97,16 @@ func (ci *ContactsImporter) updateContact(existing *models.Contact, gc *GoogleCo
 	}
 
 	// Only update if Google data is more complete
-	updated := false
+	var filled []string
 
 	if gc.Phone != "" && freshContact.Phone == "" {
 		freshContact.Phone = gc.Phone
-		updated = true
+		filled = append(filled, "phone")
 	}
 
 	if gc.Notes != "" && freshContact.Notes == "" {
 		freshContact.Notes = gc.Notes
-		updated = true
+		filled = append(filled, "notes")
 	}
 
 	// Update company if contact doesn't have one
@@ -112,14 +116,15 @@ func (ci *ContactsImporter) updateContact(existing *models.Contact, gc *GoogleCo
 			return false, fmt.Errorf("failed to handle company: %w", err)
 		}
 		freshContact.CompanyID = &company.ID
-		updated = true
+		filled = append(filled, "company_id")
 	}
 
-	if !updated {
+	if len(filled) == 0 {
 		return false, nil
 	}
 
-	if err := db.UpdateContact(ci.db, freshContact.ID, freshContact); err != nil {
+	// If the contact was edited since it was loaded, only fill what is still empty
+	if err := db.UpdateContact(ci.db, freshContact.ID, freshContact, db.FillEmptyFields(filled...)); err != nil {
 		return false, err
 	}
 
@@ -198,17 +203,7 @@ func ImportContacts(database *sql.DB, client *people.Service) error {
 		return fmt.Errorf("failed to update sync status: %w", err)
 	}
 
-	// Load all existing contacts for matching (do this ONCE, not per contact)
-	allContacts, err := db.FindContacts(database, "", nil, 20000)
-	if err != nil {
-		errMsg := err.Error()
-		_ = db.UpdateSyncStatus(database, contactsService, "error", &errMsg)
-		return fmt.Errorf("failed to load existing contacts: %w", err)
-	}
-
-	// Create importer with pre-loaded matcher
 	importer := NewContactsImporter(database)
-	importer.matcher = NewContactMatcher(allContacts)
 
 	// Fetch contacts with pagination
 	totalFetched := 0
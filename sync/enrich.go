@@ -0,0 +1,408 @@
+// ABOUTME: Contact enrichment from public sources: Gravatar avatars, company website metadata, and an optional API
+// ABOUTME: Results are metadata values tagged with their provenance, applied by `pagen crm enrich` and enrich_contact
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// Enrichment sources. Classification runs in charm; the rest fetch from the web.
+const (
+	EnrichClassify = "classify"
+	EnrichGravatar = "gravatar"
+	EnrichWebsite  = "website"
+	EnrichAPI      = "api"
+)
+
+// EnrichSources lists the enrichment sources in the order they run.
+var EnrichSources = []string{EnrichClassify, EnrichGravatar, EnrichWebsite, EnrichAPI}
+
+// DefaultEnrichmentAPIKeyEnv holds the enrichment API key when no other variable is configured.
+const DefaultEnrichmentAPIKeyEnv = "PAGEN_ENRICHMENT_API_KEY"
+
+// DefaultGravatarURL is where avatars are looked up by email hash.
+const DefaultGravatarURL = "https://gravatar.com/avatar/"
+
+// EnrichTimeout bounds each enrichment request.
+const EnrichTimeout = 10 * time.Second
+
+// maxAPIFields caps how many fields one API response adds.
+const maxAPIFields = 50
+
+// ParseEnrichSources parses a comma-separated source list; empty means all.
+func ParseEnrichSources(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return EnrichSources, nil
+	}
+	var sources []string
+	for _, source := range strings.Split(list, ",") {
+		source = strings.ToLower(strings.TrimSpace(source))
+		valid := false
+		for _, known := range EnrichSources {
+			valid = valid || source == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid enrichment source %q (use %s)", source, strings.Join(EnrichSources, ", "))
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// HasEnrichSource reports whether source is in sources.
+func HasEnrichSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicEnricher looks contacts up in public sources.
+type PublicEnricher struct {
+	GravatarURL string
+	SiteScheme  string // scheme of company sites (default: https)
+	API         *charm.EnrichmentAPIConfig
+	APIKey      string
+	HTTPClient  *http.Client
+}
+
+// NewPublicEnricher returns an enricher using the enrichment API in cfg, if any.
+func NewPublicEnricher(cfg *charm.Config) *PublicEnricher {
+	e := &PublicEnricher{
+		GravatarURL: DefaultGravatarURL,
+		SiteScheme:  "https",
+		HTTPClient:  &http.Client{Timeout: EnrichTimeout},
+	}
+	if cfg != nil && cfg.EnrichmentAPI != nil && cfg.EnrichmentAPI.URL != "" {
+		e.API = cfg.EnrichmentAPI
+		keyEnv := e.API.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = DefaultEnrichmentAPIKeyEnv
+		}
+		e.APIKey = os.Getenv(keyEnv)
+	}
+	return e
+}
+
+// EnrichmentResult is what the public sources found for a contact and its
+// company. Notes say which sources were skipped or found nothing, and Errors
+// which failed; neither stops the other sources.
+type EnrichmentResult struct {
+	Contact   charm.Metadata `json:"contact,omitempty"`
+	Company   charm.Metadata `json:"company,omitempty"`
+	CompanyID uuid.UUID      `json:"company_id,omitempty"`
+	Notes     []string       `json:"notes,omitempty"`
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// Apply saves the result to the contact and its company and returns the
+// metadata keys that changed on each.
+func (r *EnrichmentResult) Apply(client *charm.Client, contactID uuid.UUID) (contactKeys, companyKeys []string, err error) {
+	if len(r.Contact) > 0 {
+		if contactKeys, err = client.ApplyContactMetadata(contactID, r.Contact); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(r.Company) > 0 && r.CompanyID != uuid.Nil {
+		if companyKeys, err = client.ApplyCompanyMetadata(r.CompanyID, r.Company); err != nil {
+			return contactKeys, nil, err
+		}
+	}
+	return contactKeys, companyKeys, nil
+}
+
+// Enrich looks the contact up in each of sources (classification is left to
+// the caller). Website metadata belongs to the contact's company.
+func (e *PublicEnricher) Enrich(ctx context.Context, client *charm.Client, contact *charm.Contact, sources []string) *EnrichmentResult {
+	result := &EnrichmentResult{Contact: charm.Metadata{}, Company: charm.Metadata{}}
+	note := func(format string, args ...any) { result.Notes = append(result.Notes, fmt.Sprintf(format, args...)) }
+	fail := func(source string, err error) { result.Errors = append(result.Errors, source+": "+err.Error()) }
+
+	if HasEnrichSource(sources, EnrichGravatar) {
+		switch {
+		case contact.Email == "":
+			note("gravatar: no email")
+		default:
+			avatar, err := e.gravatar(ctx, contact.Email)
+			switch {
+			case err != nil:
+				fail(EnrichGravatar, err)
+			case avatar == "":
+				note("gravatar: no avatar for %s", contact.Email)
+			default:
+				result.Contact[charm.MetaAvatarURL] = charm.MetaValue{Value: avatar, Source: EnrichGravatar, FetchedAt: time.Now()}
+			}
+		}
+	}
+
+	if HasEnrichSource(sources, EnrichWebsite) {
+		var company *charm.Company
+		if contact.CompanyID != nil {
+			company, _ = client.GetCompany(*contact.CompanyID)
+		}
+		domain := charm.LogoDomain(company)
+		switch {
+		case company == nil:
+			note("website: no company")
+		case domain == "":
+			note("website: %s has no domain", company.Name)
+		default:
+			result.CompanyID = company.ID
+			values, err := e.website(ctx, domain)
+			switch {
+			case err != nil:
+				fail(EnrichWebsite, err)
+			case len(values) == 0:
+				note("website: nothing found on %s", domain)
+			default:
+				for key, value := range values {
+					result.Company[key] = charm.MetaValue{Value: value, Source: "website:" + domain, FetchedAt: time.Now()}
+				}
+			}
+		}
+	}
+
+	if HasEnrichSource(sources, EnrichAPI) {
+		switch {
+		case e.API == nil:
+			note("api: not configured (set enrichment_api in charm-config.json)")
+		case contact.Email == "" && strings.Contains(e.API.URL, "{email}"):
+			note("api: no email")
+		default:
+			values, source, err := e.lookup(ctx, contact)
+			switch {
+			case err != nil:
+				fail(EnrichAPI, err)
+			case len(values) == 0:
+				note("api: no match")
+			default:
+				for key, value := range values {
+					result.Contact[key] = charm.MetaValue{Value: value, Source: source, FetchedAt: time.Now()}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// gravatar returns the contact's avatar URL, or "" when they have none.
+func (e *PublicEnricher) gravatar(ctx context.Context, email string) (string, error) {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	avatar := e.GravatarURL + hex.EncodeToString(sum[:])
+	resp, err := e.get(ctx, avatar+"?d=404&s=200", "")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return avatar + "?s=200", nil
+	case http.StatusNotFound:
+		return "", nil
+	}
+	return "", fmt.Errorf("gravatar returned %s", resp.Status)
+}
+
+// Website metadata patterns
+var (
+	titleTag  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaTag   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	anchorTag = regexp.MustCompile(`(?is)<a\s[^>]*>`)
+)
+
+// socialHosts maps the hosts of social profiles linked from a company site
+// to the network name used in their metadata key.
+var socialHosts = map[string]string{
+	"linkedin.com":   "linkedin",
+	"twitter.com":    "twitter",
+	"x.com":          "twitter",
+	"github.com":     "github",
+	"crunchbase.com": "crunchbase",
+	"facebook.com":   "facebook",
+	"instagram.com":  "instagram",
+	"youtube.com":    "youtube",
+}
+
+// website reads the company home page's title, description, preview image,
+// and the first profile link per social network.
+func (e *PublicEnricher) website(ctx context.Context, domain string) (map[string]string, error) {
+	resp, err := e.get(ctx, e.SiteScheme+"://"+domain+"/", "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", domain, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", domain, err)
+	}
+	page := string(data)
+	base := resp.Request.URL
+
+	values := map[string]string{}
+	set := func(key, value string) {
+		value = strings.Join(strings.Fields(value), " ")
+		if value != "" && values[key] == "" {
+			values[key] = value
+		}
+	}
+
+	meta := map[string]string{}
+	for _, tag := range metaTag.FindAllString(page, -1) {
+		attrs := tagAttrs(tag)
+		name := strings.ToLower(attrs["property"] + attrs["name"])
+		if name != "" && meta[name] == "" {
+			meta[name] = attrs["content"]
+		}
+	}
+	set(charm.MetaSiteTitle, meta["og:site_name"])
+	set(charm.MetaSiteTitle, meta["og:title"])
+	if m := titleTag.FindStringSubmatch(page); m != nil {
+		set(charm.MetaSiteTitle, html.UnescapeString(m[1]))
+	}
+	set(charm.MetaSiteDescription, meta["og:description"])
+	set(charm.MetaSiteDescription, meta["description"])
+	if image := meta["og:image"]; image != "" {
+		if ref, err := base.Parse(strings.TrimSpace(image)); err == nil && (ref.Scheme == "http" || ref.Scheme == "https") {
+			set(charm.MetaSiteImage, ref.String())
+		}
+	}
+
+	for _, tag := range anchorTag.FindAllString(page, -1) {
+		ref, err := base.Parse(strings.TrimSpace(tagAttrs(tag)["href"]))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") || strings.Trim(ref.Path, "/") == "" {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+		if network, ok := socialHosts[host]; ok {
+			// Share buttons point at intent pages, not the company's profile
+			if strings.Contains(ref.Path, "share") || strings.Contains(ref.Path, "intent") {
+				continue
+			}
+			ref.RawQuery, ref.Fragment = "", ""
+			set(network+"_url", ref.String())
+		}
+	}
+	return values, nil
+}
+
+// lookup queries the enrichment API about a contact and returns the mapped
+// fields and their provenance. A 404 is no match.
+func (e *PublicEnricher) lookup(ctx context.Context, contact *charm.Contact) (map[string]string, string, error) {
+	domain := ""
+	if at := strings.LastIndex(contact.Email, "@"); at >= 0 {
+		domain = strings.ToLower(contact.Email[at+1:])
+	}
+	target := strings.NewReplacer(
+		"{email}", url.QueryEscape(contact.Email),
+		"{name}", url.QueryEscape(contact.Name),
+		"{domain}", url.QueryEscape(domain),
+	).Replace(e.API.URL)
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid enrichment_api url: %w", err)
+	}
+	source := "api:" + parsed.Host
+
+	resp, err := e.get(ctx, target, e.APIKey)
+	if err != nil {
+		return nil, source, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, source, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, source, fmt.Errorf("%s returned %s", parsed.Host, resp.Status)
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, 1<<20))
+	decoder.UseNumber()
+	var body map[string]any
+	if err := decoder.Decode(&body); err != nil {
+		return nil, source, fmt.Errorf("failed to parse enrichment response: %w", err)
+	}
+
+	values := map[string]string{}
+	if len(e.API.Fields) > 0 {
+		for field, key := range e.API.Fields {
+			if value := scalarString(lookupField(body, field)); value != "" {
+				values[key] = value
+			}
+		}
+		return values, source, nil
+	}
+	fields := make([]string, 0, len(body))
+	for field := range body {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if value := scalarString(body[field]); value != "" && len(values) < maxAPIFields {
+			values[field] = value
+		}
+	}
+	return values, source, nil
+}
+
+// lookupField follows a dotted path into nested JSON objects.
+func lookupField(body map[string]any, path string) any {
+	var value any = body
+	for _, part := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+	return value
+}
+
+// scalarString formats a JSON string, number, or boolean; anything else is "".
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func (e *PublicEnricher) get(ctx context.Context, rawURL, apiKey string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "pagen (https://github.com/harperreed/pagen)")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+// ABOUTME: Tests for contact enrichment from public sources
+// ABOUTME: Serves fake Gravatar, company site, and enrichment API responses by host
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// fakeEnricher returns an enricher whose requests to any host are answered
+// from routes, keyed by host and path.
+func fakeEnricher(t *testing.T, routes map[string]string) (*PublicEnricher, *http.Request) {
+	t.Helper()
+	last := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r
+		body, ok := routes[r.Host+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	enricher := NewPublicEnricher(nil)
+	enricher.GravatarURL = "http://gravatar.test/avatar/"
+	enricher.SiteScheme = "http"
+	enricher.HTTPClient.Transport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	return enricher, last
+}
+
+func TestParseEnrichSources(t *testing.T) {
+	if sources, err := ParseEnrichSources(""); err != nil || len(sources) != len(EnrichSources) {
+		t.Errorf("expected all sources by default, got %v, %v", sources, err)
+	}
+	if sources, err := ParseEnrichSources(" Gravatar, website "); err != nil || len(sources) != 2 || sources[0] != EnrichGravatar {
+		t.Errorf("expected gravatar and website, got %v, %v", sources, err)
+	}
+	if _, err := ParseEnrichSources("linkedin"); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	client := charm.NewTestClient(t)
+	company := &charm.Company{Name: "Acme", Domain: "acme.test"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	contact := &charm.Contact{Name: "Alice Smith", Email: " Alice@Acme.test ", CompanyID: &company.ID}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	sum := sha256.Sum256([]byte("alice@acme.test"))
+	hash := hex.EncodeToString(sum[:])
+
+	enricher, last := fakeEnricher(t, map[string]string{
+		"gravatar.test/avatar/" + hash: "avatar",
+		"acme.test/": `<html><head>
+			<title>Acme &amp; Co</title>
+			<meta name="description" content="  We make   everything ">
+			<meta property="og:image" content="/preview.png">
+		</head><body>
+			<a href="https://twitter.com/intent/tweet?text=hi">Share</a>
+			<a href="https://x.com/acme?ref=site">X</a>
+			<a href='https://www.linkedin.com/company/acme/'>LinkedIn</a>
+			<a href="https://github.com/">GitHub home</a>
+		</body></html>`,
+		"api.test/people/Alice@Acme.test": `{"title": "CTO", "employees": 120, "location": {"city": "Chicago"}, "tags": ["a"]}`,
+	})
+
+	t.Run("gravatar and website", func(t *testing.T) {
+		result := enricher.Enrich(context.Background(), client, contact, []string{EnrichGravatar, EnrichWebsite, EnrichAPI})
+		if len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+		avatar := result.Contact[charm.MetaAvatarURL]
+		if !strings.Contains(avatar.Value, hash) || avatar.Source != EnrichGravatar {
+			t.Errorf("expected a gravatar avatar, got %+v", avatar)
+		}
+		if result.CompanyID != company.ID {
+			t.Errorf("expected website metadata for the contact's company")
+		}
+		want := map[string]string{
+			charm.MetaSiteTitle:       "Acme & Co",
+			charm.MetaSiteDescription: "We make everything",
+			charm.MetaSiteImage:       "http://acme.test/preview.png",
+			"twitter_url":             "https://x.com/acme",
+			"linkedin_url":            "https://www.linkedin.com/company/acme/",
+		}
+		for key, value := range want {
+			if got := result.Company[key]; got.Value != value || got.Source != "website:acme.test" {
+				t.Errorf("%s: expected %q from website:acme.test, got %+v", key, value, got)
+			}
+		}
+		if _, ok := result.Company["github_url"]; ok {
+			t.Error("expected a link to a network's home page to be skipped")
+		}
+		if len(result.Notes) != 1 || !strings.HasPrefix(result.Notes[0], "api: not configured") {
+			t.Errorf("expected a note that the API isn't configured, got %v", result.Notes)
+		}
+	})
+
+	t.Run("enrichment API", func(t *testing.T) {
+		t.Setenv(DefaultEnrichmentAPIKeyEnv, "secret")
+		api := NewPublicEnricher(&charm.Config{EnrichmentAPI: &charm.EnrichmentAPIConfig{URL: "http://api.test/people/{email}"}})
+		api.HTTPClient = enricher.HTTPClient
+		trimmed := *contact
+		trimmed.Email = "Alice@Acme.test"
+
+		result := api.Enrich(context.Background(), client, &trimmed, []string{EnrichAPI})
+		if len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+		if last.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected the API key as a bearer token, got %q", last.Header.Get("Authorization"))
+		}
+		if len(result.Contact) != 2 || result.Contact["title"].Value != "CTO" || result.Contact["employees"].Value != "120" {
+			t.Errorf("expected the top-level scalars, got %+v", result.Contact)
+		}
+		if result.Contact["title"].Source != "api:api.test" {
+			t.Errorf("expected api:api.test provenance, got %q", result.Contact["title"].Source)
+		}
+
+		api.API.Fields = map[string]string{"location.city": "city", "missing": "nope"}
+		result = api.Enrich(context.Background(), client, &trimmed, []string{EnrichAPI})
+		if len(result.Contact) != 1 || result.Contact["city"].Value != "Chicago" {
+			t.Errorf("expected only the mapped nested field, got %+v", result.Contact)
+		}
+
+		trimmed.Email = "nobody@acme.test"
+		result = api.Enrich(context.Background(), client, &trimmed, []string{EnrichAPI})
+		if len(result.Contact) != 0 || len(result.Errors) != 0 || len(result.Notes) != 1 {
+			t.Errorf("expected a 404 to be a no-match note, got %+v", result)
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		result := enricher.Enrich(context.Background(), client, contact, []string{EnrichGravatar, EnrichWebsite})
+		contactKeys, companyKeys, err := result.Apply(client, contact.ID)
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if len(contactKeys) != 1 || len(companyKeys) != 5 {
+			t.Errorf("expected 1 contact and 5 company keys, got %v and %v", contactKeys, companyKeys)
+		}
+		saved, _ := client.GetCompany(company.ID)
+		if saved.Metadata[charm.MetaSiteTitle].Value != "Acme & Co" {
+			t.Errorf("expected the company metadata saved, got %+v", saved.Metadata)
+		}
+	})
+}
@@ -0,0 +1,296 @@
+// ABOUTME: Fake Google People, Calendar (events and free/busy), and Gmail APIs for sync integration tests
+// ABOUTME: Serves fixtures over httptest with pagination, sync tokens, expired-token errors, and injected failures
+package sync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+)
+
+// fakeGoogle serves the parts of the Google APIs the importers use. Lists are
+// returned pageSize items at a time, with the offset as the page token.
+type fakeGoogle struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       gosync.Mutex
+	pageSize int
+	requests []string
+	failures map[string]int // path -> status returned instead of a response
+
+	people []*people.Person
+
+	calendarEmail string
+	events        []*calendar.Event
+	syncToken     string                  // handed out at the end of a full listing
+	eventChanges  map[string]eventChanges // valid sync tokens; others get 410 Gone
+	busy          []*calendar.TimePeriod  // free/busy for the primary calendar
+
+	gmailEmail      string
+	historyID       uint64
+	oldestHistoryID uint64 // history requests starting before this get 404
+	messages        []*gmail.Message
+	history         []*gmail.History
+}
+
+// eventChanges are the events changed since a sync token, and the token
+// handed out after them.
+type eventChanges struct {
+	events    []*calendar.Event
+	syncToken string
+}
+
+// newFakeGoogle starts a fake server that is closed when the test ends.
+func newFakeGoogle(t *testing.T) *fakeGoogle {
+	f := &fakeGoogle{
+		t:             t,
+		pageSize:      2,
+		failures:      make(map[string]int),
+		calendarEmail: "me@example.com",
+		eventChanges:  make(map[string]eventChanges),
+		gmailEmail:    "me@example.com",
+	}
+	f.server = httptest.NewServer(f)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGoogle) peopleService() *people.Service {
+	service, err := NewPeopleService(f.server.Client(), f.server.URL)
+	if err != nil {
+		f.t.Fatalf("failed to create People service: %v", err)
+	}
+	return service
+}
+
+func (f *fakeGoogle) calendarService() *calendar.Service {
+	service, err := NewCalendarService(f.server.Client(), f.server.URL)
+	if err != nil {
+		f.t.Fatalf("failed to create calendar service: %v", err)
+	}
+	return service
+}
+
+func (f *fakeGoogle) gmailService() *gmail.Service {
+	service, err := NewGmailService(f.server.Client(), f.server.URL)
+	if err != nil {
+		f.t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	return service
+}
+
+// fail makes every request to path return status.
+func (f *fakeGoogle) fail(path string, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[path] = status
+}
+
+// requested returns the requests (path and query) containing substr.
+func (f *fakeGoogle) requested(substr string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []string
+	for _, request := range f.requests {
+		if strings.Contains(request, substr) {
+			matched = append(matched, request)
+		}
+	}
+	return matched
+}
+
+func (f *fakeGoogle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.URL.RequestURI())
+
+	if status, ok := f.failures[r.URL.Path]; ok {
+		writeGoogleError(w, status, "injected failure")
+		return
+	}
+
+	query := r.URL.Query()
+	switch path := r.URL.Path; {
+	case path == "/v1/people/me/connections":
+		start, end, next := paginate(len(f.people), query.Get("pageToken"), f.pageSize)
+		writeJSON(w, &people.ListConnectionsResponse{
+			Connections:   f.people[start:end],
+			NextPageToken: next,
+			TotalItems:    int64(len(f.people)),
+		})
+
+	case path == "/calendar/v3/users/me/calendarList/primary":
+		writeJSON(w, &calendar.CalendarListEntry{Id: f.calendarEmail})
+	case path == "/calendar/v3/calendars/primary/events":
+		f.serveEvents(w, query.Get("syncToken"), query.Get("timeMin"), query.Get("pageToken"))
+	case path == "/calendar/v3/freeBusy":
+		writeJSON(w, &calendar.FreeBusyResponse{Calendars: map[string]calendar.FreeBusyCalendar{
+			"primary": {Busy: f.busy},
+		}})
+
+	case path == "/gmail/v1/users/me/profile":
+		writeJSON(w, &gmail.Profile{EmailAddress: f.gmailEmail, HistoryId: f.historyID})
+	case path == "/gmail/v1/users/me/messages":
+		start, end, next := paginate(len(f.messages), query.Get("pageToken"), f.pageSize)
+		var refs []*gmail.Message
+		for _, message := range f.messages[start:end] {
+			refs = append(refs, &gmail.Message{Id: message.Id, ThreadId: message.ThreadId})
+		}
+		writeJSON(w, &gmail.ListMessagesResponse{Messages: refs, NextPageToken: next})
+	case strings.HasPrefix(path, "/gmail/v1/users/me/messages/"):
+		id := strings.TrimPrefix(path, "/gmail/v1/users/me/messages/")
+		for _, message := range f.messages {
+			if message.Id == id {
+				writeJSON(w, message)
+				return
+			}
+		}
+		writeGoogleError(w, http.StatusNotFound, "Requested entity was not found.")
+	case path == "/gmail/v1/users/me/history":
+		f.serveHistory(w, query.Get("startHistoryId"), query.Get("pageToken"))
+
+	default:
+		f.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		writeGoogleError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// serveEvents lists changes since a sync token, or every event starting at
+// or after timeMin, handing out a sync token with the last page.
+func (f *fakeGoogle) serveEvents(w http.ResponseWriter, syncToken, timeMin, pageToken string) {
+	events := f.events
+	nextSyncToken := f.syncToken
+	if syncToken != "" {
+		changes, ok := f.eventChanges[syncToken]
+		if !ok {
+			writeGoogleError(w, http.StatusGone, "Sync token is no longer valid, a full sync is required.")
+			return
+		}
+		events, nextSyncToken = changes.events, changes.syncToken
+	} else if timeMin != "" {
+		since, err := time.Parse(time.RFC3339, timeMin)
+		if err != nil {
+			writeGoogleError(w, http.StatusBadRequest, "Bad timeMin")
+			return
+		}
+		events = nil
+		for _, event := range f.events {
+			start, err := time.Parse(time.RFC3339, event.Start.DateTime)
+			if err != nil || !start.Before(since) {
+				events = append(events, event)
+			}
+		}
+	}
+
+	start, end, next := paginate(len(events), pageToken, f.pageSize)
+	response := &calendar.Events{Items: events[start:end], NextPageToken: next}
+	if next == "" {
+		response.NextSyncToken = nextSyncToken
+	}
+	writeJSON(w, response)
+}
+
+// serveHistory lists history records after startHistoryId, or 404s when the
+// start is older than the history the fake keeps.
+func (f *fakeGoogle) serveHistory(w http.ResponseWriter, startHistoryID, pageToken string) {
+	startID, err := strconv.ParseUint(startHistoryID, 10, 64)
+	if err != nil || startID < f.oldestHistoryID {
+		writeGoogleError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	var records []*gmail.History
+	for _, record := range f.history {
+		if record.Id > startID {
+			records = append(records, record)
+		}
+	}
+	start, end, next := paginate(len(records), pageToken, f.pageSize)
+	writeJSON(w, &gmail.ListHistoryResponse{History: records[start:end], NextPageToken: next, HistoryId: f.historyID})
+}
+
+// paginate returns the slice bounds for a page and the next page token, which
+// is empty on the last page.
+func paginate(total int, pageToken string, pageSize int) (int, int, string) {
+	start, _ := strconv.Atoi(pageToken)
+	if start < 0 || start > total {
+		start = total
+	}
+	end := min(start+pageSize, total)
+	if end < total {
+		return start, end, strconv.Itoa(end)
+	}
+	return start, end, ""
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeGoogleError writes an error in the shape googleapi.CheckResponse parses.
+func writeGoogleError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"code": status, "message": message},
+	})
+}
+
+// personFixture builds a Google contact with a name and email.
+func personFixture(name, email string) *people.Person {
+	return &people.Person{
+		ResourceName:   "people/" + strings.ReplaceAll(strings.ToLower(name), " ", "-"),
+		Names:          []*people.Name{{DisplayName: name}},
+		EmailAddresses: []*people.EmailAddress{{Value: email}},
+	}
+}
+
+// meetingFixture builds a 30-minute timed event the user accepted, with the
+// given guests.
+func meetingFixture(id, summary string, start time.Time, guests ...string) *calendar.Event {
+	attendees := []*calendar.EventAttendee{{Email: "me@example.com", Self: true, ResponseStatus: "accepted"}}
+	for _, guest := range guests {
+		attendees = append(attendees, &calendar.EventAttendee{Email: guest, ResponseStatus: "accepted"})
+	}
+	return &calendar.Event{
+		Id:        id,
+		Summary:   summary,
+		Status:    "confirmed",
+		Start:     &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+		End:       &calendar.EventDateTime{DateTime: start.Add(30 * time.Minute).Format(time.RFC3339)},
+		Attendees: attendees,
+	}
+}
+
+// emailFixture builds a message with the headers the Gmail importer reads.
+func emailFixture(id, from, to, subject string, date time.Time) *gmail.Message {
+	return &gmail.Message{
+		Id:       id,
+		ThreadId: "thread-" + id,
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: from},
+			{Name: "To", Value: to},
+			{Name: "Subject", Value: subject},
+			{Name: "Date", Value: date.Format(time.RFC1123Z)},
+		}},
+	}
+}
+
+// historyFixture builds a history record adding the given messages.
+func historyFixture(id uint64, messageIDs ...string) *gmail.History {
+	record := &gmail.History{Id: id}
+	for _, messageID := range messageIDs {
+		record.MessagesAdded = append(record.MessagesAdded, &gmail.HistoryMessageAdded{Message: &gmail.Message{Id: messageID}})
+	}
+	return record
+}
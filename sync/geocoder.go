@@ -0,0 +1,152 @@
+// ABOUTME: Geocoding for meeting locations via a Nominatim-compatible API
+// ABOUTME: Resolves locations address parsing can't place and caches the results in Charm KV
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// DefaultGeocodeURL is OpenStreetMap's public Nominatim instance.
+const DefaultGeocodeURL = "https://nominatim.openstreetmap.org"
+
+// GeocodeInterval respects Nominatim's limit of one request per second.
+const GeocodeInterval = time.Second
+
+// Geocoder resolves a free-form location to a city. It returns nil when the
+// location isn't found.
+type Geocoder interface {
+	Geocode(ctx context.Context, location string) (*charm.Place, error)
+}
+
+// NominatimGeocoder geocodes with a Nominatim search endpoint.
+type NominatimGeocoder struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewNominatimGeocoder returns a geocoder for baseURL, or the public instance when empty.
+func NewNominatimGeocoder(baseURL string) *NominatimGeocoder {
+	if baseURL == "" {
+		baseURL = DefaultGeocodeURL
+	}
+	return &NominatimGeocoder{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Geocode looks up a location and returns its city, region, and country.
+func (g *NominatimGeocoder) Geocode(ctx context.Context, location string) (*charm.Place, error) {
+	query := url.Values{
+		"q":              {location},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"limit":          {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid geocode URL: %w", err)
+	}
+	// Nominatim's usage policy requires an identifying user agent
+	req.Header.Set("User-Agent", "pagen (https://github.com/harperreed/pagen)")
+
+	resp, err := g.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocode request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocode request failed: %s", resp.Status)
+	}
+
+	var results []struct {
+		Address struct {
+			City         string `json:"city"`
+			Town         string `json:"town"`
+			Village      string `json:"village"`
+			Municipality string `json:"municipality"`
+			State        string `json:"state"`
+			Country      string `json:"country"`
+		} `json:"address"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to parse geocode response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	address := results[0].Address
+	city := address.City
+	for _, alt := range []string{address.Town, address.Village, address.Municipality} {
+		if city == "" {
+			city = alt
+		}
+	}
+	if city == "" {
+		return nil, nil
+	}
+	return &charm.Place{City: city, Region: address.State, Country: address.Country}, nil
+}
+
+// GeocodeResult summarizes a geocoding run.
+type GeocodeResult struct {
+	Resolved int
+	NotFound int
+	Errors   []string
+}
+
+// GeocodeMeetingLocations geocodes meeting locations that haven't been
+// geocoded yet and caches the results, waiting between requests so public
+// endpoints aren't hammered. A failed lookup isn't cached and is retried on the
+// next run.
+func GeocodeMeetingLocations(ctx context.Context, client *charm.Client, geocoder Geocoder, interval time.Duration) (*GeocodeResult, error) {
+	locations, err := client.MeetingLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GeocodeResult{}
+	first := true
+	for _, location := range locations {
+		if _, cached, err := client.GetGeocode(location); err != nil {
+			return result, err
+		} else if cached {
+			continue
+		}
+
+		if !first && interval > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		first = false
+
+		place, err := geocoder.Geocode(ctx, location)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", location, err))
+			continue
+		}
+		if err := client.SaveGeocode(location, place); err != nil {
+			return result, fmt.Errorf("failed to cache geocode: %w", err)
+		}
+		if place == nil {
+			result.NotFound++
+		} else {
+			result.Resolved++
+		}
+	}
+	return result, nil
+}
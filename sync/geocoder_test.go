@@ -0,0 +1,70 @@
+// ABOUTME: Tests for geocoding meeting locations
+// ABOUTME: Verifies Nominatim response handling and caching of looked-up locations
+package sync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestGeocodeMeetingLocations(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	contact := &charm.Contact{Name: "Alice"}
+	if err := client.CreateContact(contact); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	for _, location := range []string{"Soho House", "Nowhere Cafe", "https://zoom.us/j/1", "Soho House"} {
+		if err := client.CreateInteractionLog(&charm.InteractionLog{ContactID: contact.ID, InteractionType: charm.InteractionMeeting, Location: location}); err != nil {
+			t.Fatalf("failed to log interaction: %v", err)
+		}
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("expected a user agent")
+		}
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+		w.Header().Set("Content-Type", "application/json")
+		if q == "Soho House" {
+			_, _ = w.Write([]byte(`[{"address":{"town":"London","state":"England","country":"United Kingdom"}}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL + "/")
+	result, err := GeocodeMeetingLocations(context.Background(), client, geocoder, 0)
+	if err != nil {
+		t.Fatalf("GeocodeMeetingLocations failed: %v", err)
+	}
+	if result.Resolved != 1 || result.NotFound != 1 || len(result.Errors) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(queries) != 2 {
+		t.Errorf("expected each in-person location looked up once, got %v", queries)
+	}
+
+	place, err := client.ResolvePlace("Soho House")
+	if err != nil {
+		t.Fatalf("ResolvePlace failed: %v", err)
+	}
+	if place == nil || place.City != "London" {
+		t.Errorf("expected Soho House in London, got %+v", place)
+	}
+
+	// Cached locations aren't looked up again
+	if _, err := GeocodeMeetingLocations(context.Background(), client, geocoder, 0); err != nil {
+		t.Fatalf("GeocodeMeetingLocations failed: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Errorf("expected no new lookups, got %v", queries)
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/gmail/v1"
-	"google.golang.org/api/option"
 )
 
 // NewGmailClient creates a new Google Gmail API client.
@@ -20,10 +19,5 @@ func NewGmailClient(token *oauth2.Token) (*gmail.Service, error) {
 	config := NewOAuthConfig()
 	client := config.Client(context.Background(), token)
 
-	service, err := gmail.NewService(context.Background(), option.WithHTTPClient(client))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gmail service: %w", err)
-	}
-
-	return service, nil
+	return NewGmailService(client, "")
 }
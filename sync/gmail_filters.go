@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 
 	"google.golang.org/api/gmail/v1"
 )
@@ -113,20 +114,37 @@ func isAutomatedSender(from string) bool {
 	return false
 }
 
-// countRecipients counts email addresses in a header value (comma-separated).
+// countRecipients counts the addresses in a To or Cc header. Commas inside a
+// quoted name or angle brackets don't separate addresses.
 func countRecipients(headerValue string) int {
-	if headerValue == "" {
-		return 0
-	}
-
-	// Split by comma and count non-empty entries
-	parts := strings.Split(headerValue, ",")
 	count := 0
-	for _, part := range parts {
-		if strings.TrimSpace(part) != "" {
-			count++
+	inQuotes, inAngle, escaped, hasText := false, false, false, false
+	for _, r := range headerValue {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '<' && !inQuotes:
+			inAngle = true
+		case r == '>' && !inQuotes:
+			inAngle = false
+		case r == ',' && !inQuotes && !inAngle:
+			if hasText {
+				count++
+			}
+			hasText = false
+			continue
+		}
+		if !unicode.IsSpace(r) {
+			hasText = true
 		}
 	}
+	if hasText {
+		count++
+	}
 
 	return count
 }
@@ -199,16 +217,15 @@ func ExtractEmailAddress(emailField string) (name string, email string, domain s
 		return "", "", ""
 	}
 
-	// Check for "Name <email>" format
+	// Check for "Name <email>" format. The address is in the last angle
+	// brackets, since a quoted name may contain brackets of its own.
 	if strings.Contains(emailField, "<") && strings.Contains(emailField, ">") {
-		parts := strings.Split(emailField, "<")
-		if len(parts) >= 2 {
-			name = strings.TrimSpace(parts[0])
-			name = strings.Trim(name, "\"") // Remove quotes if present
+		start := strings.LastIndex(emailField, "<")
+		name = strings.TrimSpace(emailField[:start])
+		name = strings.Trim(name, "\"") // Remove quotes if present
 
-			emailPart := strings.Split(parts[1], ">")[0]
-			email = strings.TrimSpace(emailPart)
-		}
+		emailPart, _, _ := strings.Cut(emailField[start+1:], ">")
+		email = strings.TrimSpace(emailPart)
 	} else {
 		// Just email address
 		email = strings.TrimSpace(emailField)
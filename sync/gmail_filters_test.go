@@ -3,8 +3,11 @@
 package sync
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+	"unicode"
 
 	"google.golang.org/api/gmail/v1"
 )
@@ -466,6 +469,16 @@ func TestCountRecipients(t *testing.T) {
 			headerValue: "a@x.com,,,b@x.com",
 			want:        2,
 		},
+		{
+			name:        "comma in quoted name",
+			headerValue: `"Smith, John" <john@x.com>, "Doe, Jane" <jane@x.com>`,
+			want:        2,
+		},
+		{
+			name:        "escaped quote in quoted name",
+			headerValue: `"The \"Boss\", CEO" <boss@x.com>, b@x.com`,
+			want:        2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -774,6 +787,13 @@ func TestExtractEmailAddress(t *testing.T) {
 			wantEmail:  "Name <email@example.com",
 			wantDomain: "example.com", // Domain still extracted from malformed email
 		},
+		{
+			name:       "brackets in quoted name",
+			emailField: `"Smith <Sales>" <smith@example.com>`,
+			wantName:   "Smith <Sales>",
+			wantEmail:  "smith@example.com",
+			wantDomain: "example.com",
+		},
 		{
 			name:       "whitespace around plain email",
 			emailField: "  user@example.com  ",
@@ -798,3 +818,94 @@ func TestExtractEmailAddress(t *testing.T) {
 		})
 	}
 }
+
+// FuzzExtractEmailAddress checks invariants of address parsing on arbitrary
+// header values.
+func FuzzExtractEmailAddress(f *testing.F) {
+	for _, seed := range []string{
+		"", "user@example.com", "John Doe <john@example.com>", `"Jane" <jane@EXAMPLE.com>`,
+		"Name <email@example.com", "a>b<c", "<>", "<<a@b>>", "user@@example.com", "\"x\" <@>",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, field string) {
+		_, email, domain := ExtractEmailAddress(field)
+		if email != strings.TrimSpace(email) {
+			t.Errorf("email %q is not trimmed", email)
+		}
+		if domain != strings.ToLower(domain) {
+			t.Errorf("domain %q is not lowercase", domain)
+		}
+		if domain != "" {
+			if strings.Count(email, "@") != 1 || !strings.HasSuffix(strings.ToLower(email), "@"+domain) {
+				t.Errorf("domain %q does not come from email %q", domain, email)
+			}
+		}
+	})
+}
+
+// FuzzExtractEmailAddressRoundTrip checks that a formatted address parses back
+// into its parts.
+func FuzzExtractEmailAddressRoundTrip(f *testing.F) {
+	f.Add("John Doe", "john", "example.com")
+	f.Add("Smith, <Sales>", "a.b+tag", "Mail.Example.COM")
+	f.Fuzz(func(t *testing.T, name, local, domain string) {
+		name = strings.TrimSpace(name)
+		if strings.ContainsAny(name, `"`) || !isAtom(local) || !isAtom(domain) {
+			t.Skip()
+		}
+		field := fmt.Sprintf(`"%s" <%s@%s>`, name, local, domain)
+
+		gotName, gotEmail, gotDomain := ExtractEmailAddress(field)
+		if gotName != name || gotEmail != local+"@"+domain || gotDomain != strings.ToLower(domain) {
+			t.Errorf("ExtractEmailAddress(%q) = %q, %q, %q", field, gotName, gotEmail, gotDomain)
+		}
+	})
+}
+
+// isAtom reports whether s can be one side of a generated address.
+func isAtom(s string) bool {
+	return s != "" && !strings.ContainsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("<>@\",", r)
+	})
+}
+
+// FuzzCountRecipients checks that a list of addresses, whatever their names
+// contain, counts as one recipient each.
+func FuzzCountRecipients(f *testing.F) {
+	f.Add("Smith, John", uint8(3))
+	f.Add("<weird>, name", uint8(1))
+	f.Add("", uint8(6))
+	f.Fuzz(func(t *testing.T, name string, n uint8) {
+		if strings.ContainsAny(name, `"\`) {
+			t.Skip()
+		}
+		count := int(n%20) + 1
+		addresses := make([]string, count)
+		for i := range addresses {
+			addresses[i] = fmt.Sprintf(`"%s" <user%d@example.com>`, name, i)
+		}
+		header := strings.Join(addresses, ", ")
+
+		if got := countRecipients(header); got != count {
+			t.Errorf("countRecipients(%q) = %d, want %d", header, got, count)
+		}
+	})
+}
+
+// FuzzCountRecipientsBounds checks the count against the header's commas on
+// arbitrary input.
+func FuzzCountRecipientsBounds(f *testing.F) {
+	for _, seed := range []string{"", "a@x.com", "a,,b", `"unterminated, <x>`, " , , ", `\",\"`} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, header string) {
+		got := countRecipients(header)
+		if got > strings.Count(header, ",")+1 {
+			t.Errorf("countRecipients(%q) = %d, more than the comma-separated parts", header, got)
+		}
+		if (got == 0) != (strings.TrimFunc(header, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) == "") {
+			t.Errorf("countRecipients(%q) = %d", header, got)
+		}
+	})
+}
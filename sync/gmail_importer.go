@@ -5,13 +5,17 @@ package sync
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
 
+	"github.com/harperreed/pagen/charm"
 	"github.com/harperreed/pagen/db"
 	"github.com/harperreed/pagen/models"
 )
@@ -44,16 +48,8 @@ func ImportGmail(database *sql.DB, client *gmail.Service, initial bool) error {
 	userEmail := profile.EmailAddress
 	currentHistoryId := profile.HistoryId
 
-	// Load existing contacts for matching
-	allContacts, err := db.FindContacts(database, "", nil, 20000)
-	if err != nil {
-		errMsg := err.Error()
-		_ = db.UpdateSyncStatus(database, gmailService, "error", &errMsg)
-		return fmt.Errorf("failed to load existing contacts: %w", err)
-	}
-
 	// Create contact matcher
-	matcher := NewContactMatcher(allContacts)
+	matcher := NewIndexedContactMatcher(database)
 
 	// Check if we can use historyId-based sync
 	var useHistorySync bool
@@ -360,8 +356,7 @@ func processMessage(database *sql.DB, client *gmail.Service, messageId, userEmai
 		InteractionType: models.InteractionEmail,
 		Timestamp:       emailDate,
 		Notes:           subject,
-		Metadata: fmt.Sprintf(`{"message_id": "%s", "thread_id": "%s"}`,
-			message.Id, message.ThreadId),
+		Metadata:        emailMetadata(message.Id, message.ThreadId),
 	}
 
 	if err := db.LogInteraction(database, interaction); err != nil {
@@ -383,6 +378,10 @@ func isHistoryExpiredError(err error) bool {
 	if err == nil {
 		return false
 	}
+	apiErr := &googleapi.Error{}
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return true
+	}
 	errStr := err.Error()
 	// Gmail API returns 404 for expired historyId
 	return strings.Contains(errStr, "404") || strings.Contains(errStr, "historyId")
@@ -390,49 +389,43 @@ func isHistoryExpiredError(err error) bool {
 
 // findOrCreateEmailContact finds existing contact by email or creates new one.
 func findOrCreateEmailContact(database *sql.DB, matcher *ContactMatcher, name, email, domain string) (uuid.UUID, bool, error) {
-	// Try to find existing contact
-	existing, found := matcher.FindMatch(email, name)
-	if found {
-		return existing.ID, false, nil
-	}
-
-	// Create new contact
-	contact := &models.Contact{
-		Name:  name,
-		Email: email,
-	}
+	contact, created, err := matcher.FindOrCreate(email, func() (*models.Contact, error) {
+		contact := &models.Contact{
+			Name:  name,
+			Email: email,
+		}
 
-	// If name is empty, use email username as name
-	if contact.Name == "" && email != "" {
-		parts := strings.Split(email, "@")
-		if len(parts) > 0 {
-			contact.Name = parts[0]
+		// If name is empty, use email username as name
+		if contact.Name == "" && email != "" {
+			parts := strings.Split(email, "@")
+			if len(parts) > 0 {
+				contact.Name = parts[0]
+			}
 		}
-	}
 
-	// Try to find or create company from domain
-	if domain != "" && !isCommonEmailDomain(domain) {
-		company, err := findOrCreateCompanyFromDomain(database, domain)
-		if err == nil && company != nil {
-			contact.CompanyID = &company.ID
+		// Try to find or create company from domain
+		if domain != "" && !charm.IsCommonEmailDomain(domain) {
+			company, err := findOrCreateCompanyFromDomain(database, domain)
+			if err == nil && company != nil {
+				contact.CompanyID = &company.ID
+			}
 		}
-	}
 
-	// Create contact
-	if err := db.CreateContact(database, contact); err != nil {
+		if err := db.CreateContact(database, contact); err != nil {
+			return nil, err
+		}
+		return contact, nil
+	})
+	if err != nil {
 		return uuid.Nil, false, err
 	}
-
-	// Add to matcher
-	matcher.AddContact(contact)
-
-	return contact.ID, true, nil
+	return contact.ID, created, nil
 }
 
 // findOrCreateCompanyFromDomain creates company from email domain.
 func findOrCreateCompanyFromDomain(database *sql.DB, domain string) (*models.Company, error) {
 	// Capitalize domain as company name
-	companyName := capitalizeCompanyName(domain)
+	companyName := charm.CompanyNameFromDomain(domain)
 
 	// Try to find existing
 	company, err := db.FindCompanyByName(database, companyName)
@@ -464,56 +457,6 @@ func findOrCreateCompanyFromDomain(database *sql.DB, domain string) (*models.Com
 	return newCompany, nil
 }
 
-// isCommonEmailDomain checks if domain is a common email provider (not company-specific).
-func isCommonEmailDomain(domain string) bool {
-	commonDomains := []string{
-		"gmail.com",
-		"googlemail.com",
-		"yahoo.com",
-		"hotmail.com",
-		"outlook.com",
-		"live.com",
-		"msn.com",
-		"icloud.com",
-		"me.com",
-		"mac.com",
-		"aol.com",
-		"protonmail.com",
-		"pm.me",
-	}
-
-	lowerDomain := strings.ToLower(domain)
-	for _, common := range commonDomains {
-		if lowerDomain == common {
-			return true
-		}
-	}
-
-	return false
-}
-
-// capitalizeCompanyName converts domain to company name.
-func capitalizeCompanyName(domain string) string {
-	// Remove common TLDs
-	name := strings.TrimSuffix(domain, ".com")
-	name = strings.TrimSuffix(name, ".org")
-	name = strings.TrimSuffix(name, ".net")
-	name = strings.TrimSuffix(name, ".io")
-
-	// Capitalize first letter of each word (split by dot or dash)
-	parts := strings.FieldsFunc(name, func(r rune) bool {
-		return r == '.' || r == '-'
-	})
-
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + part[1:]
-		}
-	}
-
-	return strings.Join(parts, " ")
-}
-
 // parseEmailDate parses RFC 2822 email date.
 func parseEmailDate(dateStr string) (time.Time, error) {
 	if dateStr == "" {
@@ -546,6 +489,16 @@ func parseEmailDate(dateStr string) (time.Time, error) {
 	return time.Now(), fmt.Errorf("failed to parse date: %s", dateStr)
 }
 
+// emailMetadata builds the interaction metadata for a message, encoding the
+// IDs rather than pasting them into the JSON.
+func emailMetadata(messageID, threadID string) string {
+	b, err := json.Marshal(map[string]string{"message_id": messageID, "thread_id": threadID})
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
 // jsonEscape escapes a string for safe JSON embedding.
 func jsonEscape(s string) string {
 	b, err := json.Marshal(s)
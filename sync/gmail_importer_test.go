@@ -3,7 +3,10 @@
 package sync
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 // TestIsHistoryExpiredError tests expired historyId error detection.
@@ -217,3 +220,83 @@ func sscanf(str, format string, value *uint64) (int, error) {
 // 4. Contact creation fails -> logs error, continues
 // 5. Interaction logging fails -> logs error, continues
 // 6. Successful messages still get processed despite individual failures
+
+// FuzzParseEmailDate checks that formatted Date headers parse back to the same
+// instant, with or without a trailing zone comment.
+func FuzzParseEmailDate(f *testing.F) {
+	f.Add(int64(0), int16(0), false)
+	f.Add(int64(1718000000), int16(-420), true)
+	f.Add(int64(4102444799), int16(840), false)
+	f.Fuzz(func(t *testing.T, seconds int64, offsetMinutes int16, comment bool) {
+		seconds = ((seconds % 4102444800) + 4102444800) % 4102444800 // 1970 to 2099
+		offset := int(offsetMinutes) % (14 * 60)
+		date := time.Unix(seconds, 0).In(time.FixedZone("", offset*60))
+
+		for _, layout := range []string{time.RFC1123Z, "Mon, 2 Jan 2006 15:04:05 -0700"} {
+			header := date.Format(layout)
+			if comment {
+				header += " (UTC)"
+			}
+			got, err := parseEmailDate(header)
+			if err != nil {
+				t.Fatalf("parseEmailDate(%q) failed: %v", header, err)
+			}
+			if !got.Equal(date) {
+				t.Errorf("parseEmailDate(%q) = %s, want %s", header, got, date)
+			}
+		}
+	})
+}
+
+// FuzzParseEmailDateInput checks that arbitrary Date headers never panic and
+// always yield a usable time.
+func FuzzParseEmailDateInput(f *testing.F) {
+	for _, seed := range []string{"", " (", "(UTC)", "Mon, 02 Jan 2006 15:04:05 -0700 (MST)", "not a date", "31 Feb 99 25:61 XYZ"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, header string) {
+		got, _ := parseEmailDate(header)
+		if got.IsZero() {
+			t.Errorf("parseEmailDate(%q) returned the zero time", header)
+		}
+	})
+}
+
+// FuzzEmailMetadata checks that interaction metadata is valid JSON holding the
+// message and thread IDs, whatever characters they contain.
+func FuzzEmailMetadata(f *testing.F) {
+	f.Add("18c2f0a1b2c3d4e5", "18c2f0a1b2c3d4e5")
+	f.Add(`id","x":"y`, "\\\n\x00")
+	f.Fuzz(func(t *testing.T, messageID, threadID string) {
+		metadata := emailMetadata(messageID, threadID)
+		var decoded map[string]string
+		if err := json.Unmarshal([]byte(metadata), &decoded); err != nil {
+			t.Fatalf("emailMetadata(%q, %q) is not valid JSON: %s", messageID, threadID, metadata)
+		}
+		if len(decoded) != 2 {
+			t.Errorf("expected only message_id and thread_id, got %v", decoded)
+		}
+		if utf8.ValidString(messageID) && utf8.ValidString(threadID) &&
+			(decoded["message_id"] != messageID || decoded["thread_id"] != threadID) {
+			t.Errorf("IDs did not round-trip: %v", decoded)
+		}
+	})
+}
+
+// FuzzJSONEscape checks that escaped subjects embed as a JSON string that
+// decodes back to the subject.
+func FuzzJSONEscape(f *testing.F) {
+	for _, seed := range []string{"", "Quarterly plan", `Re: "quotes" \\ backslash`, "tab\tnewline\n", "\u2028", "\xff\xfe"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, subject string) {
+		metadata := `{"subject": ` + jsonEscape(subject) + `}`
+		var decoded map[string]string
+		if err := json.Unmarshal([]byte(metadata), &decoded); err != nil {
+			t.Fatalf("metadata for %q is not valid JSON: %s", subject, metadata)
+		}
+		if utf8.ValidString(subject) && decoded["subject"] != subject {
+			t.Errorf("subject %q decoded as %q", subject, decoded["subject"])
+		}
+	})
+}
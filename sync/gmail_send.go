@@ -0,0 +1,31 @@
+// ABOUTME: Sending mail through the Gmail API
+// ABOUTME: Sends prebuilt RFC 2822 messages and looks up the mailbox's own address
+package sync
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// SendGmailMessage sends a complete RFC 2822 message from the user's mailbox
+// and returns the sent message's ID. Needs a token with a send or compose scope.
+func SendGmailMessage(ctx context.Context, service *gmail.Service, raw []byte) (string, error) {
+	message := &gmail.Message{Raw: base64.URLEncoding.EncodeToString(raw)}
+	sent, err := service.Users.Messages.Send("me", message).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to send Gmail message: %w", err)
+	}
+	return sent.Id, nil
+}
+
+// GmailAddress returns the email address of the user's mailbox.
+func GmailAddress(ctx context.Context, service *gmail.Service) (string, error) {
+	profile, err := service.Users.GetProfile("me").Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Gmail profile: %w", err)
+	}
+	return profile.EmailAddress, nil
+}
@@ -0,0 +1,150 @@
+// ABOUTME: Gmail conversation tracking for reply follow-ups
+// ABOUTME: Records who sent the last message in each thread with a known contact and flags unanswered ones
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// ThreadLookbackDays is how far back a thread check reads mail. Threads
+// already tracked keep their state, so only recent messages matter.
+const ThreadLookbackDays = 14
+
+// ThreadTrackResult summarizes a thread check.
+type ThreadTrackResult struct {
+	Messages int                  // messages exchanged with known contacts
+	Threads  int                  // threads those messages belong to
+	Pending  []*charm.EmailThread // threads that just became pending reply follow-ups
+}
+
+// TrackEmailThreads reads mail since the given time, records the latest
+// message of each thread with a known contact, and marks threads where the
+// contact hasn't replied within waitDays as pending follow-ups. Mail with
+// unknown addresses, automated senders, groups, and calendar invites is
+// skipped, and no contacts are created.
+func TrackEmailThreads(ctx context.Context, service *gmail.Service, client *charm.Client, since time.Time, waitDays int, now time.Time) (*ThreadTrackResult, error) {
+	profile, err := service.Users.GetProfile("me").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %w", err)
+	}
+	userEmail := profile.EmailAddress
+
+	contacts, err := client.ListContacts(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+	byEmail := make(map[string]*charm.Contact, len(contacts))
+	for _, contact := range contacts {
+		if contact.Email != "" {
+			byEmail[strings.ToLower(contact.Email)] = contact
+		}
+	}
+
+	messages, err := listThreadMessages(ctx, service, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ThreadTrackResult{}
+	threads := make(map[string]bool)
+	for _, tm := range messages {
+		if ok, _ := IsHighSignalEmail(tm.message, userEmail); !ok {
+			continue
+		}
+
+		headers := parseHeaders(tm.message.Payload)
+		direction := charm.ThreadReceived
+		_, from, _ := ExtractEmailAddress(headers["From"])
+		address := from
+		if strings.EqualFold(from, userEmail) {
+			direction = charm.ThreadSent
+			address = firstRecipient(headers["To"])
+		}
+		contact := byEmail[strings.ToLower(address)]
+		if contact == nil {
+			continue
+		}
+
+		if _, err := client.RecordThreadMessage(tm.message.ThreadId, contact, direction, headers["Subject"], tm.at); err != nil {
+			return result, fmt.Errorf("failed to record thread %s: %w", tm.message.ThreadId, err)
+		}
+		result.Messages++
+		threads[tm.message.ThreadId] = true
+	}
+	result.Threads = len(threads)
+
+	result.Pending, err = client.MarkPendingReplies(waitDays, now)
+	if err != nil {
+		return result, fmt.Errorf("failed to mark pending replies: %w", err)
+	}
+	return result, nil
+}
+
+// threadMessage is a fetched message and when it was sent.
+type threadMessage struct {
+	message *gmail.Message
+	at      time.Time
+}
+
+// listThreadMessages fetches the headers of every message since the given
+// time, oldest first so each thread ends on its latest message.
+func listThreadMessages(ctx context.Context, service *gmail.Service, since time.Time) ([]threadMessage, error) {
+	query := fmt.Sprintf("after:%d -in:spam -in:trash -in:chats", since.Unix())
+
+	var messages []threadMessage
+	pageToken := ""
+	for {
+		call := service.Users.Messages.List("me").Q(query).MaxResults(maxGmailResults).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		response, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch messages: %w", err)
+		}
+
+		for _, ref := range response.Messages {
+			message, err := service.Users.Messages.Get("me", ref.Id).
+				Format("metadata").
+				MetadataHeaders("From", "To", "Cc", "Subject", "Date").
+				Context(ctx).Do()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get message %s: %w", ref.Id, err)
+			}
+			at := time.UnixMilli(message.InternalDate)
+			if message.InternalDate == 0 {
+				at, _ = parseEmailDate(parseHeaders(message.Payload)["Date"])
+			}
+			messages = append(messages, threadMessage{message: message, at: at})
+		}
+
+		pageToken = response.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].at.Before(messages[j].at)
+	})
+	return messages, nil
+}
+
+// firstRecipient returns the first address in a To header.
+func firstRecipient(to string) string {
+	if addresses, err := mail.ParseAddressList(to); err == nil && len(addresses) > 0 {
+		return addresses[0].Address
+	}
+	first, _, _ := strings.Cut(to, ",")
+	_, email, _ := ExtractEmailAddress(first)
+	return email
+}
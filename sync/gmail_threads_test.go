@@ -0,0 +1,67 @@
+// ABOUTME: Tests for Gmail thread tracking against the fake Google server
+// ABOUTME: Verifies direction detection, known-contact matching, and pending reply marking
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestTrackEmailThreads(t *testing.T) {
+	client := charm.NewTestClient(t)
+	for _, contact := range []*charm.Contact{
+		{Name: "Alice Smith", Email: "alice@acme.com"},
+		{Name: "Bob Jones", Email: "bob@globex.com"},
+	} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+
+	now := time.Now()
+	fake := newFakeGoogle(t)
+	// Alice got a proposal 5 days ago and never answered
+	proposal := emailFixture("m1", "me@example.com", "Alice Smith <alice@acme.com>, ops@acme.com", "Proposal", now.AddDate(0, 0, -5))
+	// Bob was asked 6 days ago and replied yesterday
+	question := emailFixture("m2", "me@example.com", "bob@globex.com", "Question", now.AddDate(0, 0, -6))
+	answer := emailFixture("m3", "Bob Jones <bob@globex.com>", "me@example.com", "Re: Question", now.AddDate(0, 0, -1))
+	answer.ThreadId = question.ThreadId
+	// Strangers and automated mail aren't tracked
+	stranger := emailFixture("m4", "me@example.com", "someone@else.com", "Hello", now.AddDate(0, 0, -5))
+	automated := emailFixture("m5", "noreply@acme.com", "me@example.com", "Your receipt", now.AddDate(0, 0, -5))
+	fake.messages = append(fake.messages, answer, proposal, question, stranger, automated)
+
+	result, err := TrackEmailThreads(context.Background(), fake.gmailService(), client, now.AddDate(0, 0, -14), 3, now)
+	if err != nil {
+		t.Fatalf("TrackEmailThreads failed: %v", err)
+	}
+	if result.Messages != 3 || result.Threads != 2 {
+		t.Errorf("expected 3 messages in 2 threads, got %d in %d", result.Messages, result.Threads)
+	}
+	if len(result.Pending) != 1 || result.Pending[0].ContactName != "Alice Smith" || result.Pending[0].Subject != "Proposal" {
+		t.Fatalf("expected Alice's proposal to be pending, got %+v", result.Pending)
+	}
+
+	bob, err := client.GetEmailThread(question.ThreadId)
+	if err != nil || bob == nil {
+		t.Fatalf("expected Bob's thread to be tracked: %v", err)
+	}
+	if bob.LastDirection != charm.ThreadReceived {
+		t.Errorf("expected Bob's reply to be the last message, got %s", bob.LastDirection)
+	}
+	if stranger, _ := client.GetEmailThread(stranger.ThreadId); stranger != nil {
+		t.Error("expected mail to unknown addresses to be skipped")
+	}
+
+	// Checking again finds nothing new
+	result, err = TrackEmailThreads(context.Background(), fake.gmailService(), client, now.AddDate(0, 0, -14), 3, now)
+	if err != nil {
+		t.Fatalf("TrackEmailThreads failed: %v", err)
+	}
+	if len(result.Pending) != 0 {
+		t.Errorf("expected no newly pending threads, got %d", len(result.Pending))
+	}
+}
@@ -0,0 +1,62 @@
+// ABOUTME: Google API service construction shared by the sync clients
+// ABOUTME: Builds People, Calendar, and Gmail services on any HTTP client and base URL, so sync can run against a fake server
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/api/people/v1"
+)
+
+// Paths of each API below a base URL, matching Google's default endpoints.
+const (
+	peopleAPIPath   = "/"
+	calendarAPIPath = "/calendar/v3/"
+	gmailAPIPath    = "/"
+)
+
+// googleServiceOptions returns the client options for a service. An empty
+// baseURL keeps Google's default endpoint.
+func googleServiceOptions(httpClient *http.Client, baseURL, apiPath string) []option.ClientOption {
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if baseURL != "" {
+		opts = append(opts, option.WithEndpoint(strings.TrimRight(baseURL, "/")+apiPath))
+	}
+	return opts
+}
+
+// NewPeopleService creates a People API service that sends requests through
+// httpClient to baseURL (Google when empty).
+func NewPeopleService(httpClient *http.Client, baseURL string) (*people.Service, error) {
+	service, err := people.NewService(context.Background(), googleServiceOptions(httpClient, baseURL, peopleAPIPath)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create People service: %w", err)
+	}
+	return service, nil
+}
+
+// NewCalendarService creates a Calendar API service that sends requests
+// through httpClient to baseURL (Google when empty).
+func NewCalendarService(httpClient *http.Client, baseURL string) (*calendar.Service, error) {
+	service, err := calendar.NewService(context.Background(), googleServiceOptions(httpClient, baseURL, calendarAPIPath)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar service: %w", err)
+	}
+	return service, nil
+}
+
+// NewGmailService creates a Gmail API service that sends requests through
+// httpClient to baseURL (Google when empty).
+func NewGmailService(httpClient *http.Client, baseURL string) (*gmail.Service, error) {
+	service, err := gmail.NewService(context.Background(), googleServiceOptions(httpClient, baseURL, gmailAPIPath)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gmail service: %w", err)
+	}
+	return service, nil
+}
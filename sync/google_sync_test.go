@@ -0,0 +1,198 @@
+// ABOUTME: End-to-end sync tests against the fake Google APIs
+// ABOUTME: Covers pagination, calendar sync tokens and the 410 fallback, and Gmail history with the 404 fallback
+package sync
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/people/v1"
+
+	"github.com/harperreed/pagen/db"
+)
+
+func assertImported(t *testing.T, database *sql.DB, service string, sourceIDs ...string) {
+	t.Helper()
+	for _, id := range sourceIDs {
+		exists, err := db.CheckSyncLogExists(database, service, id)
+		if err != nil {
+			t.Fatalf("failed to check sync log: %v", err)
+		}
+		if !exists {
+			t.Errorf("expected %s %s to be imported", service, id)
+		}
+	}
+}
+
+func assertSyncToken(t *testing.T, database *sql.DB, service, want string) {
+	t.Helper()
+	state, err := db.GetSyncState(database, service)
+	if err != nil {
+		t.Fatalf("failed to get sync state: %v", err)
+	}
+	if state == nil || state.LastSyncToken == nil || *state.LastSyncToken != want {
+		t.Errorf("expected %s sync token %q, got %+v", service, want, state)
+	}
+}
+
+func TestImportContactsFromFakeGoogle(t *testing.T) {
+	database := setupTestDB(t)
+	fake := newFakeGoogle(t)
+	fake.people = []*people.Person{
+		personFixture("Alice Smith", "alice@acme.com"),
+		personFixture("Bob Jones", "bob@globex.com"),
+		personFixture("Carol White", "carol@initech.com"),
+		personFixture("Dan Brown", "dan@acme.com"),
+		personFixture("Eve Black", "eve@umbrella.com"),
+	}
+
+	for run := 0; run < 2; run++ {
+		if err := ImportContacts(database, fake.peopleService()); err != nil {
+			t.Fatalf("ImportContacts failed: %v", err)
+		}
+	}
+
+	if pages := fake.requested("/v1/people/me/connections"); len(pages) != 6 {
+		t.Errorf("expected 3 pages per run, got %d requests", len(pages))
+	}
+	contacts, err := db.FindContacts(database, "", nil, 100)
+	if err != nil {
+		t.Fatalf("failed to list contacts: %v", err)
+	}
+	if len(contacts) != 5 {
+		t.Errorf("expected 5 contacts after two runs, got %d", len(contacts))
+	}
+}
+
+func TestImportCalendarSyncTokens(t *testing.T) {
+	database := setupTestDB(t)
+	fake := newFakeGoogle(t)
+	now := time.Now()
+	fake.events = []*calendar.Event{
+		meetingFixture("e1", "Kickoff", now.AddDate(0, 0, -20), "alice@acme.com"),
+		meetingFixture("e2", "Review", now.AddDate(0, 0, -10), "bob@globex.com"),
+		meetingFixture("e3", "Planning", now.AddDate(0, 0, -5), "alice@acme.com", "carol@initech.com"),
+	}
+	fake.syncToken = "sync-1"
+	fake.eventChanges["sync-1"] = eventChanges{
+		events:    []*calendar.Event{meetingFixture("e4", "Follow-up", now.AddDate(0, 0, -1), "bob@globex.com")},
+		syncToken: "sync-2",
+	}
+
+	if err := ImportCalendar(database, fake.calendarService(), true); err != nil {
+		t.Fatalf("initial ImportCalendar failed: %v", err)
+	}
+	assertImported(t, database, calendarService, "e1", "e2", "e3")
+	assertSyncToken(t, database, calendarService, "sync-1")
+	if pages := fake.requested("pageToken=2"); len(pages) != 1 {
+		t.Errorf("expected the second page to be requested once, got %v", pages)
+	}
+
+	if err := ImportCalendar(database, fake.calendarService(), false); err != nil {
+		t.Fatalf("incremental ImportCalendar failed: %v", err)
+	}
+	if requests := fake.requested("syncToken=sync-1"); len(requests) != 1 {
+		t.Errorf("expected an incremental request with the stored token, got %v", requests)
+	}
+	assertImported(t, database, calendarService, "e4")
+	assertSyncToken(t, database, calendarService, "sync-2")
+}
+
+func TestImportCalendarExpiredSyncToken(t *testing.T) {
+	database := setupTestDB(t)
+	fake := newFakeGoogle(t)
+	now := time.Now()
+	fake.events = []*calendar.Event{
+		meetingFixture("old", "Before the last sync", now.AddDate(0, 0, -2), "alice@acme.com"),
+		meetingFixture("new", "After the last sync", now.AddDate(0, 0, 1), "bob@globex.com"),
+	}
+	fake.syncToken = "sync-fresh"
+
+	if err := db.UpdateSyncToken(database, calendarService, "sync-stale"); err != nil {
+		t.Fatalf("failed to store sync token: %v", err)
+	}
+	if err := ImportCalendar(database, fake.calendarService(), false); err != nil {
+		t.Fatalf("ImportCalendar should fall back after 410: %v", err)
+	}
+
+	if requests := fake.requested("timeMin="); len(requests) != 1 {
+		t.Errorf("expected one time-based fallback request, got %v", requests)
+	}
+	assertImported(t, database, calendarService, "new")
+	if exists, _ := db.CheckSyncLogExists(database, calendarService, "old"); exists {
+		t.Error("the fallback should only fetch events since the last sync")
+	}
+	assertSyncToken(t, database, calendarService, "sync-fresh")
+}
+
+func TestImportCalendarErrorDoesNotFallBack(t *testing.T) {
+	database := setupTestDB(t)
+	fake := newFakeGoogle(t)
+	fake.fail("/calendar/v3/calendars/primary/events", http.StatusForbidden)
+
+	if err := db.UpdateSyncToken(database, calendarService, "sync-1"); err != nil {
+		t.Fatalf("failed to store sync token: %v", err)
+	}
+	if err := ImportCalendar(database, fake.calendarService(), false); err == nil {
+		t.Fatal("expected a 403 to fail the sync")
+	}
+	if requests := fake.requested("timeMin="); len(requests) != 0 {
+		t.Errorf("only 410 should trigger the time-based fallback, got %v", requests)
+	}
+	state, err := db.GetSyncState(database, calendarService)
+	if err != nil || state == nil || state.Status != "error" {
+		t.Errorf("expected the sync state to record the error, got %+v, %v", state, err)
+	}
+}
+
+func TestImportGmailHistory(t *testing.T) {
+	database := setupTestDB(t)
+	fake := newFakeGoogle(t)
+	now := time.Now()
+	fake.historyID = 100
+	fake.messages = []*gmail.Message{
+		emailFixture("m1", "Alice Smith <alice@acme.com>", "me@example.com", "Quarterly plan", now.AddDate(0, 0, -3)),
+		emailFixture("m2", "Bob Jones <bob@globex.com>", "me@example.com", "Contract draft", now.AddDate(0, 0, -2)),
+		emailFixture("m3", "me@example.com", "Carol White <carol@initech.com>", "Intro call", now.AddDate(0, 0, -1)),
+	}
+
+	// Initial sync searches and pages through messages
+	if err := ImportGmail(database, fake.gmailService(), true); err != nil {
+		t.Fatalf("initial ImportGmail failed: %v", err)
+	}
+	assertImported(t, database, gmailService, "m1", "m2", "m3")
+	assertSyncToken(t, database, gmailService, "100")
+	if pages := fake.requested("/gmail/v1/users/me/messages?"); len(pages) != 2 {
+		t.Errorf("expected 2 pages of messages, got %d", len(pages))
+	}
+
+	// Incremental sync reads history since the stored historyId
+	fake.messages = append(fake.messages, emailFixture("m4", "Alice Smith <alice@acme.com>", "me@example.com", "Re: Quarterly plan", now))
+	fake.history = []*gmail.History{historyFixture(101, "m4")}
+	fake.historyID = 101
+	if err := ImportGmail(database, fake.gmailService(), false); err != nil {
+		t.Fatalf("incremental ImportGmail failed: %v", err)
+	}
+	if requests := fake.requested("startHistoryId=100"); len(requests) != 1 {
+		t.Errorf("expected a history request from the stored historyId, got %v", requests)
+	}
+	assertImported(t, database, gmailService, "m4")
+	assertSyncToken(t, database, gmailService, "101")
+
+	// An expired historyId (404) falls back to a search
+	fake.messages = append(fake.messages, emailFixture("m5", "Bob Jones <bob@globex.com>", "me@example.com", "Signed contract", now))
+	fake.oldestHistoryID = 150
+	fake.historyID = 160
+	if err := ImportGmail(database, fake.gmailService(), false); err != nil {
+		t.Fatalf("ImportGmail should fall back after 404: %v", err)
+	}
+	if pages := fake.requested("/gmail/v1/users/me/messages?"); len(pages) != 5 {
+		t.Errorf("expected the fallback to search messages again, got %d list requests", len(pages))
+	}
+	assertImported(t, database, gmailService, "m5")
+	assertSyncToken(t, database, gmailService, "160")
+}
@@ -0,0 +1,231 @@
+// ABOUTME: Company logo fetching from a logo API or the company site's favicon
+// ABOUTME: Only raster images are kept; unreachable sites are errors so they aren't cached as misses
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// LogoFetchTimeout bounds fetching one company's logo, all candidates included.
+const LogoFetchTimeout = 10 * time.Second
+
+// logoTypes are the image types kept as logos. SVG is left out because it can
+// carry scripts and logos are served from the UI's own origin.
+var logoTypes = map[string]bool{
+	"image/png":    true,
+	"image/jpeg":   true,
+	"image/gif":    true,
+	"image/webp":   true,
+	"image/x-icon": true,
+	"image/bmp":    true,
+}
+
+// LogoFetcher finds a logo for a domain: the logo API when one is set, then
+// the biggest icon the home page links, then /favicon.ico.
+type LogoFetcher struct {
+	LogoURL    string // with {domain} replaced, e.g. https://logo.clearbit.com/{domain}
+	SiteScheme string // scheme of the company site (default: https)
+	HTTPClient *http.Client
+}
+
+// NewLogoFetcher returns a fetcher that tries logoURL first when it is set.
+func NewLogoFetcher(logoURL string) *LogoFetcher {
+	return &LogoFetcher{
+		LogoURL:    logoURL,
+		SiteScheme: "https",
+		HTTPClient: &http.Client{Timeout: LogoFetchTimeout},
+	}
+}
+
+// errNotLogo marks a response that was reached but isn't a usable logo.
+var errNotLogo = errors.New("not a logo")
+
+// FetchLogo returns the logo for domain, or a logo with no data when the site
+// answered but has none. It fails when nothing could be reached, e.g. offline,
+// so the miss isn't cached.
+func (f *LogoFetcher) FetchLogo(ctx context.Context, domain string) (*charm.CompanyLogo, error) {
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required")
+	}
+	site := f.SiteScheme + "://" + domain
+
+	var candidates []string
+	if f.LogoURL != "" {
+		candidates = append(candidates, strings.ReplaceAll(f.LogoURL, "{domain}", url.PathEscape(domain)))
+	}
+	icons, pageErr := f.pageIcons(ctx, site+"/")
+	candidates = append(candidates, icons...)
+	candidates = append(candidates, site+"/favicon.ico")
+
+	reached := pageErr == nil
+	var lastErr error = pageErr
+	for _, candidate := range candidates {
+		data, contentType, err := f.fetchImage(ctx, candidate)
+		if err == nil {
+			return &charm.CompanyLogo{Domain: domain, ContentType: contentType, Data: data, Source: candidate, FetchedAt: time.Now()}, nil
+		}
+		if errors.Is(err, errNotLogo) {
+			reached = true
+		} else {
+			lastErr = err
+		}
+	}
+	if !reached {
+		return nil, fmt.Errorf("failed to fetch logo for %s: %w", domain, lastErr)
+	}
+	return &charm.CompanyLogo{Domain: domain, FetchedAt: time.Now()}, nil
+}
+
+// fetchImage downloads a candidate and returns it when it is a raster image.
+// A response that isn't one is errNotLogo.
+func (f *LogoFetcher) fetchImage(ctx context.Context, rawURL string) ([]byte, string, error) {
+	resp, err := f.get(ctx, rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s: %s: %w", rawURL, resp.Status, errNotLogo)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, charm.MaxLogoSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if len(data) == 0 || len(data) > charm.MaxLogoSize {
+		return nil, "", fmt.Errorf("%s: %d bytes: %w", rawURL, len(data), errNotLogo)
+	}
+	// Trust the bytes, not the header; favicons are often served as octet-stream
+	contentType := http.DetectContentType(data)
+	if !logoTypes[contentType] {
+		return nil, "", fmt.Errorf("%s: %s: %w", rawURL, contentType, errNotLogo)
+	}
+	return data, contentType, nil
+}
+
+// linkTag and tagAttr pick <link> tags and their attributes out of a page.
+var (
+	linkTag = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	tagAttr = regexp.MustCompile(`(?is)\b([a-z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// tagAttrs returns a tag's attributes by lowercased name, entities decoded.
+func tagAttrs(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range tagAttr.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+// pageIcons returns the icons the home page links, apple-touch icons and
+// larger sizes first, as absolute URLs.
+func (f *LogoFetcher) pageIcons(ctx context.Context, pageURL string) ([]string, error) {
+	resp, err := f.get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	page, err := io.ReadAll(io.LimitReader(resp.Body, 512<<10))
+	if err != nil {
+		return nil, nil
+	}
+	base := resp.Request.URL // after redirects
+
+	type icon struct {
+		href  string
+		score int
+	}
+	var icons []icon
+	for _, tag := range linkTag.FindAllString(string(page), -1) {
+		attrs := tagAttrs(tag)
+		rel := strings.ToLower(attrs["rel"])
+		if attrs["href"] == "" || !strings.Contains(rel, "icon") || strings.Contains(rel, "mask-icon") {
+			continue
+		}
+		ref, err := base.Parse(strings.TrimSpace(attrs["href"]))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		score := 0
+		if strings.Contains(rel, "apple-touch-icon") {
+			score = 180
+		}
+		var size int
+		if _, err := fmt.Sscanf(strings.ToLower(attrs["sizes"]), "%dx", &size); err == nil && size > score {
+			score = size
+		}
+		icons = append(icons, icon{href: ref.String(), score: score})
+	}
+
+	// Stable insertion sort: a handful of links at most
+	for i := 1; i < len(icons); i++ {
+		for j := i; j > 0 && icons[j].score > icons[j-1].score; j-- {
+			icons[j], icons[j-1] = icons[j-1], icons[j]
+		}
+	}
+	hrefs := make([]string, len(icons))
+	for i, icon := range icons {
+		hrefs[i] = icon.href
+	}
+	return hrefs, nil
+}
+
+func (f *LogoFetcher) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logo URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "pagen (https://github.com/harperreed/pagen)")
+	resp, err := f.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("logo request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// CompanyLogo returns the cached logo for a company, fetching it when it
+// isn't cached or a cached miss is stale and fetcher is set. It returns nil
+// when there is nothing to show, and fetch errors (e.g. offline) aren't cached.
+func CompanyLogo(ctx context.Context, client *charm.Client, fetcher *LogoFetcher, company *charm.Company) (*charm.CompanyLogo, error) {
+	domain := charm.LogoDomain(company)
+	if domain == "" {
+		return nil, nil
+	}
+	cached, err := client.GetCompanyLogo(domain)
+	if err != nil {
+		return nil, err
+	}
+	if fetcher == nil || (cached != nil && !cached.Stale(time.Now())) {
+		if cached != nil && cached.Found() {
+			return cached, nil
+		}
+		return nil, nil
+	}
+
+	logo, err := fetcher.FetchLogo(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SaveCompanyLogo(logo); err != nil {
+		return nil, err
+	}
+	if !logo.Found() {
+		return nil, nil
+	}
+	return logo, nil
+}
@@ -0,0 +1,143 @@
+// ABOUTME: Tests for company logo fetching
+// ABOUTME: Serves fake company sites to check icon discovery, the logo API, and offline behavior
+package sync
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// pngLogo is enough of a PNG for content sniffing.
+var pngLogo = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x10")
+
+// fakeSite returns a fetcher whose requests to any host are answered from
+// routes, by path, and the domain to fetch.
+func fakeSite(t *testing.T, routes map[string]string) (*LogoFetcher, string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := NewLogoFetcher("")
+	fetcher.SiteScheme = "http"
+	fetcher.HTTPClient.Transport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	return fetcher, "acme.test"
+}
+
+func TestFetchLogo(t *testing.T) {
+	t.Run("prefers the largest linked icon", func(t *testing.T) {
+		fetcher, domain := fakeSite(t, map[string]string{
+			"/": `<html><head>
+				<link rel="icon" href="/small.png" sizes="16x16">
+				<link rel="mask-icon" href="/mask.svg">
+				<link href='/touch.png' rel='apple-touch-icon'>
+			</head></html>`,
+			"/small.png":   string(pngLogo) + "small",
+			"/touch.png":   string(pngLogo) + "touch",
+			"/favicon.ico": string(pngLogo) + "favicon",
+		})
+		logo, err := fetcher.FetchLogo(context.Background(), domain)
+		if err != nil {
+			t.Fatalf("FetchLogo failed: %v", err)
+		}
+		if !strings.HasSuffix(logo.Source, "/touch.png") || logo.ContentType != "image/png" {
+			t.Errorf("expected the apple-touch icon as PNG, got %s (%s)", logo.Source, logo.ContentType)
+		}
+	})
+
+	t.Run("falls back to favicon.ico and skips SVG", func(t *testing.T) {
+		fetcher, domain := fakeSite(t, map[string]string{
+			"/":            `<link rel="icon" href="/logo.svg">`,
+			"/logo.svg":    `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`,
+			"/favicon.ico": "\x00\x00\x01\x00\x01\x00",
+		})
+		logo, err := fetcher.FetchLogo(context.Background(), domain)
+		if err != nil {
+			t.Fatalf("FetchLogo failed: %v", err)
+		}
+		if !strings.HasSuffix(logo.Source, "/favicon.ico") || logo.ContentType != "image/x-icon" {
+			t.Errorf("expected favicon.ico, got %s (%s)", logo.Source, logo.ContentType)
+		}
+	})
+
+	t.Run("logo API comes first", func(t *testing.T) {
+		fetcher, domain := fakeSite(t, map[string]string{
+			"/logos/acme.test": string(pngLogo) + "api",
+			"/favicon.ico":     string(pngLogo) + "favicon",
+		})
+		fetcher.LogoURL = "http://logos.test/logos/{domain}"
+
+		logo, err := fetcher.FetchLogo(context.Background(), domain)
+		if err != nil {
+			t.Fatalf("FetchLogo failed: %v", err)
+		}
+		if logo.Source != "http://logos.test/logos/acme.test" {
+			t.Errorf("expected the logo API, got %s", logo.Source)
+		}
+	})
+
+	t.Run("a site without a logo is a miss", func(t *testing.T) {
+		fetcher, domain := fakeSite(t, map[string]string{"/": "<html></html>"})
+		logo, err := fetcher.FetchLogo(context.Background(), domain)
+		if err != nil {
+			t.Fatalf("FetchLogo failed: %v", err)
+		}
+		if logo.Found() {
+			t.Errorf("expected no logo, got %s", logo.Source)
+		}
+	})
+
+	t.Run("an unreachable site is an error", func(t *testing.T) {
+		fetcher := NewLogoFetcher("")
+		fetcher.HTTPClient.Transport = &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: io.ErrUnexpectedEOF}
+			},
+		}
+		if _, err := fetcher.FetchLogo(context.Background(), "acme.test"); err == nil {
+			t.Error("expected an error when offline, so the miss isn't cached")
+		}
+	})
+}
+
+func TestCompanyLogoCaching(t *testing.T) {
+	client := charm.NewTestClient(t)
+	fetcher, domain := fakeSite(t, map[string]string{"/favicon.ico": string(pngLogo)})
+	company := &charm.Company{Name: "Acme", Domain: "https://www." + domain + "/about"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+
+	// Without a fetcher only the cache is consulted
+	if logo, err := CompanyLogo(context.Background(), client, nil, company); err != nil || logo != nil {
+		t.Fatalf("expected nothing cached, got %v, %v", logo, err)
+	}
+
+	logo, err := CompanyLogo(context.Background(), client, fetcher, company)
+	if err != nil || logo == nil {
+		t.Fatalf("expected a fetched logo, got %v, %v", logo, err)
+	}
+	if cached, _ := client.GetCompanyLogo(domain); cached == nil || !cached.Found() {
+		t.Fatal("expected the logo to be cached under the bare domain")
+	}
+	if logo, err := CompanyLogo(context.Background(), client, nil, company); err != nil || logo == nil {
+		t.Errorf("expected the cached logo offline, got %v, %v", logo, err)
+	}
+}
@@ -1,15 +1,23 @@
 // ABOUTME: Contact deduplication and matching logic
-// ABOUTME: Finds existing contacts by email to prevent duplicates during sync
+// ABOUTME: Finds existing contacts by email to prevent duplicates during sync, looking up misses in the SQLite email index
 package sync
 
 import (
+	"database/sql"
+	"fmt"
 	"strings"
 
+	"github.com/harperreed/pagen/db"
 	"github.com/harperreed/pagen/models"
 )
 
+// ContactMatcher finds existing contacts during one import so it doesn't
+// create duplicates. Contacts it has seen are kept by email; an indexed
+// matcher looks up the rest in the contact email index instead of loading
+// every contact up front.
 type ContactMatcher struct {
-	byEmail map[string]*models.Contact
+	database *sql.DB // nil unless indexed
+	byEmail  map[string]*models.Contact
 }
 
 // NewContactMatcher creates a matcher from existing contacts.
@@ -28,15 +36,55 @@ func NewContactMatcher(contacts []models.Contact) *ContactMatcher {
 	return m
 }
 
-// FindMatch looks for existing contact by email.
-func (m *ContactMatcher) FindMatch(email, name string) (*models.Contact, bool) {
+// NewIndexedContactMatcher creates a matcher that looks up contacts in
+// database by email as the import asks for them.
+func NewIndexedContactMatcher(database *sql.DB) *ContactMatcher {
+	return &ContactMatcher{
+		database: database,
+		byEmail:  make(map[string]*models.Contact),
+	}
+}
+
+// FindMatch looks for existing contact by email. Contacts without an email
+// never match.
+func (m *ContactMatcher) FindMatch(email string) (*models.Contact, bool, error) {
 	normalized := normalizeEmail(email)
 	if normalized == "" {
-		return nil, false
+		return nil, false, nil
 	}
 
-	contact, found := m.byEmail[normalized]
-	return contact, found
+	if contact, found := m.byEmail[normalized]; found || m.database == nil {
+		return contact, found, nil
+	}
+
+	contact, err := db.FindContactByEmail(m.database, normalized)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up contact by email: %w", err)
+	}
+	if contact == nil {
+		return nil, false, nil
+	}
+	m.byEmail[normalized] = contact
+	return contact, true, nil
+}
+
+// FindOrCreate returns the contact matching email, or saves the one
+// create builds. It reports whether the contact was created.
+func (m *ContactMatcher) FindOrCreate(email string, create func() (*models.Contact, error)) (*models.Contact, bool, error) {
+	existing, found, err := m.FindMatch(email)
+	if err != nil {
+		return nil, false, err
+	}
+	if found {
+		return existing, false, nil
+	}
+
+	contact, err := create()
+	if err != nil {
+		return nil, false, err
+	}
+	m.AddContact(contact)
+	return contact, true, nil
 }
 
 // AddContact adds a newly created contact to the matcher to prevent duplicates
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/harperreed/pagen/db"
 	"github.com/harperreed/pagen/models"
 )
 
@@ -16,7 +17,7 @@ func TestMatchContactByEmail(t *testing.T) {
 	matcher := NewContactMatcher(existing)
 
 	// Test exact match
-	match, found := matcher.FindMatch("alice@example.com", "")
+	match, found, _ := matcher.FindMatch("alice@example.com")
 	if !found {
 		t.Error("expected to find match for alice@example.com")
 	}
@@ -25,12 +26,47 @@ func TestMatchContactByEmail(t *testing.T) {
 	}
 
 	// Test no match
-	_, found = matcher.FindMatch("charlie@example.com", "")
+	_, found, _ = matcher.FindMatch("charlie@example.com")
 	if found {
 		t.Error("expected no match for charlie@example.com")
 	}
 }
 
+func TestIndexedMatcher(t *testing.T) {
+	database := setupTestDB(t)
+	alice := &models.Contact{Name: "Alice", Email: "Alice@Example.com"}
+	if err := db.CreateContact(database, alice); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	matcher := NewIndexedContactMatcher(database)
+
+	// Found through the email index, ignoring case
+	match, found, err := matcher.FindMatch("alice@example.com")
+	if err != nil || !found || match.ID != alice.ID {
+		t.Fatalf("expected to find Alice, got %v, %v, %v", match, found, err)
+	}
+	if _, found, _ := matcher.FindMatch("charlie@example.com"); found {
+		t.Error("expected no match for charlie@example.com")
+	}
+
+	// A contact created during the import is found again without a second one
+	calls := 0
+	create := func() (*models.Contact, error) {
+		calls++
+		contact := &models.Contact{Name: "Bob", Email: "bob@example.com"}
+		return contact, db.CreateContact(database, contact)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := matcher.FindOrCreate("Bob@example.com", create); err != nil {
+			t.Fatalf("FindOrCreate failed: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected Bob created once, got %d", calls)
+	}
+}
+
 func TestNormalizeEmail(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -84,7 +120,7 @@ func TestAddContact(t *testing.T) {
 	matcher.AddContact(newContact)
 
 	// Verify we can find it
-	match, found := matcher.FindMatch("bob@example.com", "")
+	match, found, _ := matcher.FindMatch("bob@example.com")
 	if !found {
 		t.Error("expected to find newly added contact")
 	}
@@ -101,7 +137,7 @@ func TestAddContact(t *testing.T) {
 	matcher.AddContact(anotherContact)
 
 	// Should find it with lowercase email
-	match, found = matcher.FindMatch("charlie@example.com", "")
+	match, found, _ = matcher.FindMatch("charlie@example.com")
 	if !found {
 		t.Error("expected to find contact with normalized email")
 	}
@@ -118,7 +154,7 @@ func TestAddContact(t *testing.T) {
 	matcher.AddContact(emptyContact)
 
 	// Should not be findable
-	_, found = matcher.FindMatch("", "")
+	_, found, _ = matcher.FindMatch("")
 	if found {
 		t.Error("should not find contact with empty email")
 	}
@@ -0,0 +1,222 @@
+// ABOUTME: Company news enrichment from RSS and Atom feeds
+// ABOUTME: Fetches headlines for companies with domains and stores the recent items per company
+package sync
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// NewsFetchTimeout bounds each feed request.
+const NewsFetchTimeout = 15 * time.Second
+
+// maxFeedBytes caps how much of a feed response is read.
+const maxFeedBytes = 5 << 20
+
+// NewsFetchResult summarizes a news enrichment run.
+type NewsFetchResult struct {
+	Companies int      // companies with a domain that were checked
+	Added     int      // new headlines stored
+	Errors    []string // feeds that failed; the run continues past them
+}
+
+// newsFeed covers RSS 2.0 (<rss><channel><item>) and Atom (<feed><entry>).
+type newsFeed struct {
+	Title   string          `xml:"channel>title"`
+	Items   []newsFeedEntry `xml:"channel>item"`
+	Feed    string          `xml:"title"`
+	Entries []newsFeedEntry `xml:"entry"`
+}
+
+type newsFeedEntry struct {
+	Title     string `xml:"title"`
+	PubDate   string `xml:"pubDate"`
+	Source    string `xml:"source"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Links     []struct {
+		Text string `xml:",chardata"` // RSS
+		Href string `xml:"href,attr"` // Atom
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+}
+
+// newsTimeLayouts covers RSS (RFC 1123 variants) and Atom (RFC 3339) dates.
+var newsTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+}
+
+// ParseNewsFeed reads an RSS or Atom feed. Items without a parseable date are
+// dated fetchedAt so they still show up as recent.
+func ParseNewsFeed(r io.Reader, fetchedAt time.Time) ([]*charm.NewsItem, error) {
+	var feed newsFeed
+	if err := xml.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	source := strings.TrimSpace(feed.Title)
+	if source == "" {
+		source = strings.TrimSpace(feed.Feed)
+	}
+
+	var items []*charm.NewsItem
+	for _, entry := range append(feed.Items, feed.Entries...) {
+		title := strings.TrimSpace(entry.Title)
+		if title == "" {
+			continue
+		}
+
+		item := &charm.NewsItem{Title: title, URL: entryLink(entry), Source: source, PublishedAt: fetchedAt}
+		if s := strings.TrimSpace(entry.Source); s != "" {
+			item.Source = s
+		}
+		for _, value := range []string{entry.PubDate, entry.Published, entry.Updated} {
+			if t, ok := parseNewsTime(value); ok {
+				item.PublishedAt = t
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// entryLink returns an RSS item's <link> text or an Atom entry's alternate href.
+func entryLink(entry newsFeedEntry) string {
+	for _, l := range entry.Links {
+		if text := strings.TrimSpace(l.Text); text != "" {
+			return text
+		}
+		if l.Href != "" && (l.Rel == "" || l.Rel == "alternate") {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func parseNewsTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range newsTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MentionsCompany reports whether a headline names the company or its domain.
+func MentionsCompany(item *charm.NewsItem, company *charm.Company) bool {
+	title := strings.ToLower(item.Title)
+	if name := strings.ToLower(strings.TrimSpace(company.Name)); name != "" && strings.Contains(title, name) {
+		return true
+	}
+	if domain := strings.ToLower(strings.TrimSpace(company.Domain)); domain != "" {
+		if strings.Contains(title, domain) {
+			return true
+		}
+		if u, err := url.Parse(item.URL); err == nil {
+			host := strings.ToLower(u.Hostname())
+			return host == domain || strings.HasSuffix(host, "."+domain)
+		}
+	}
+	return false
+}
+
+// FetchCompanyNews fetches the configured feeds for each of the companies that
+// has a domain and stores new headlines. A failing feed is recorded in the
+// result and skipped.
+func FetchCompanyNews(ctx context.Context, httpClient *http.Client, client *charm.Client, cfg *charm.NewsConfig, companies []*charm.Company) (*NewsFetchResult, error) {
+	if cfg == nil || len(cfg.Feeds) == 0 {
+		return nil, fmt.Errorf("no news feeds configured (add one with: pagen news feeds --add <url>)")
+	}
+
+	now := time.Now()
+	result := &NewsFetchResult{}
+
+	// Plain feeds are fetched once and shared across companies
+	shared := make(map[string][]*charm.NewsItem)
+	for _, feed := range cfg.Feeds {
+		if charm.IsCompanyFeed(feed) {
+			continue
+		}
+		items, err := fetchNewsFeed(ctx, httpClient, feed, now)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		shared[feed] = items
+	}
+
+	for _, company := range companies {
+		if strings.TrimSpace(company.Domain) == "" {
+			continue
+		}
+		result.Companies++
+
+		var fetched []*charm.NewsItem
+		for _, feed := range cfg.Feeds {
+			if !charm.IsCompanyFeed(feed) {
+				for _, item := range shared[feed] {
+					if MentionsCompany(item, company) {
+						fetched = append(fetched, item)
+					}
+				}
+				continue
+			}
+
+			items, err := fetchNewsFeed(ctx, httpClient, charm.CompanyFeedURL(feed, company), now)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", company.Name, err))
+				continue
+			}
+			fetched = append(fetched, items...)
+		}
+
+		added, err := client.SaveCompanyNews(company.ID, fetched, cfg, now)
+		if err != nil {
+			return result, fmt.Errorf("failed to save news for %s: %w", company.Name, err)
+		}
+		result.Added += added
+	}
+	return result, nil
+}
+
+func fetchNewsFeed(ctx context.Context, httpClient *http.Client, feedURL string, now time.Time) ([]*charm.NewsItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed URL %s: %w", feedURL, err)
+	}
+	req.Header.Set("User-Agent", "pagen")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", feedURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: %s", feedURL, resp.Status)
+	}
+
+	items, err := ParseNewsFeed(io.LimitReader(resp.Body, maxFeedBytes), now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", feedURL, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,159 @@
+// ABOUTME: Tests for the company news enrichment importer
+// ABOUTME: Verifies RSS and Atom parsing, mention filtering, and fetching feeds per company
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const rssNewsFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+<title>Tech Daily</title>
+<item><title>Acme raises $20M</title><link>https://news.example.com/acme-raise</link><pubDate>%s</pubDate></item>
+<item><title>Globex cuts staff</title><link>https://news.example.com/globex</link><pubDate>%s</pubDate><source url="https://wire.example.com">Wire</source></item>
+<item><title></title><link>https://news.example.com/untitled</link></item>
+</channel></rss>`
+
+const atomNewsFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title>Acme Blog</title>
+<entry><title>Introducing Widgets 2.0</title><link rel="alternate" href="https://blog.acme.com/widgets"/><updated>%s</updated></entry>
+</feed>`
+
+func TestParseNewsFeed(t *testing.T) {
+	fetchedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	rss := fmt.Sprintf(rssNewsFeed, "Fri, 31 May 2024 09:00:00 +0000", "not a date")
+	items, err := ParseNewsFeed(strings.NewReader(rss), fetchedAt)
+	if err != nil {
+		t.Fatalf("ParseNewsFeed failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items (untitled skipped), got %d", len(items))
+	}
+	if items[0].URL != "https://news.example.com/acme-raise" || items[0].Source != "Tech Daily" {
+		t.Errorf("unexpected RSS item: %+v", items[0])
+	}
+	if !items[0].PublishedAt.Equal(time.Date(2024, 5, 31, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected published date: %s", items[0].PublishedAt)
+	}
+	if items[1].Source != "Wire" || !items[1].PublishedAt.Equal(fetchedAt) {
+		t.Errorf("expected item source and fetch-time date fallback, got %+v", items[1])
+	}
+
+	atom := fmt.Sprintf(atomNewsFeed, "2024-05-30T08:00:00Z")
+	items, err = ParseNewsFeed(strings.NewReader(atom), fetchedAt)
+	if err != nil {
+		t.Fatalf("ParseNewsFeed failed: %v", err)
+	}
+	if len(items) != 1 || items[0].URL != "https://blog.acme.com/widgets" || items[0].Source != "Acme Blog" {
+		t.Errorf("unexpected Atom items: %+v", items)
+	}
+
+	if _, err := ParseNewsFeed(strings.NewReader("not xml"), fetchedAt); err == nil {
+		t.Error("expected error for invalid feed")
+	}
+}
+
+func TestMentionsCompany(t *testing.T) {
+	company := &charm.Company{Name: "Acme", Domain: "acme.com"}
+	tests := []struct {
+		item *charm.NewsItem
+		want bool
+	}{
+		{&charm.NewsItem{Title: "ACME raises $20M"}, true},
+		{&charm.NewsItem{Title: "Widgets 2.0", URL: "https://blog.acme.com/widgets"}, true},
+		{&charm.NewsItem{Title: "Globex cuts staff", URL: "https://notacme.com/x"}, false},
+	}
+	for _, tt := range tests {
+		if got := MentionsCompany(tt.item, company); got != tt.want {
+			t.Errorf("MentionsCompany(%q, %q) = %v, want %v", tt.item.Title, tt.item.URL, got, tt.want)
+		}
+	}
+}
+
+func TestFetchCompanyNews(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	acme := &charm.Company{Name: "Acme", Domain: "acme.com"}
+	globex := &charm.Company{Name: "Globex", Domain: "globex.com"}
+	noDomain := &charm.Company{Name: "Initech"}
+	for _, company := range []*charm.Company{acme, globex, noDomain} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("failed to create company: %v", err)
+		}
+	}
+
+	recent := time.Now().Add(-time.Hour)
+	var searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tech":
+			_, _ = fmt.Fprintf(w, rssNewsFeed, recent.Format(time.RFC1123Z), recent.Format(time.RFC1123Z))
+		case "/search":
+			searches = append(searches, r.URL.Query().Get("q"))
+			if r.URL.Query().Get("q") == "globex.com" {
+				http.Error(w, "rate limited", http.StatusTooManyRequests)
+				return
+			}
+			_, _ = fmt.Fprintf(w, atomNewsFeed, recent.Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &charm.NewsConfig{Feeds: []string{server.URL + "/tech", server.URL + "/search?q={domain}"}}
+	companies, err := client.ListCompanies(&charm.CompanyFilter{})
+	if err != nil {
+		t.Fatalf("failed to list companies: %v", err)
+	}
+
+	result, err := FetchCompanyNews(context.Background(), server.Client(), client, cfg, companies)
+	if err != nil {
+		t.Fatalf("FetchCompanyNews failed: %v", err)
+	}
+	if result.Companies != 2 {
+		t.Errorf("expected 2 companies with domains checked, got %d", result.Companies)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "Globex") {
+		t.Errorf("expected one Globex feed error, got %v", result.Errors)
+	}
+	if len(searches) != 2 {
+		t.Errorf("expected one search per company with a domain, got %v", searches)
+	}
+
+	// Acme: its mention in the shared feed plus its search result
+	items, err := client.RecentCompanyNews(acme.ID)
+	if err != nil {
+		t.Fatalf("RecentCompanyNews failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 Acme headlines, got %d", len(items))
+	}
+
+	// Globex: its search failed but the shared feed still matched
+	items, err = client.RecentCompanyNews(globex.ID)
+	if err != nil {
+		t.Fatalf("RecentCompanyNews failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Globex cuts staff" {
+		t.Errorf("unexpected Globex headlines: %+v", items)
+	}
+
+	if result.Added != 3 {
+		t.Errorf("expected 3 headlines added, got %d", result.Added)
+	}
+
+	if _, err := FetchCompanyNews(context.Background(), server.Client(), client, &charm.NewsConfig{}, companies); err == nil {
+		t.Error("expected error without feeds")
+	}
+}
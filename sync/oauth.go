@@ -9,7 +9,7 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/adrg/xdg"
+	"github.com/harperreed/pagen/charm"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
@@ -47,9 +47,10 @@ func NewOAuthConfig() *oauth2.Config {
 	}
 }
 
-// TokenPath returns XDG-compliant path for storing OAuth tokens.
+// TokenPath returns XDG-compliant path for storing OAuth tokens. Each
+// profile keeps its own, so work and personal sync different accounts.
 func TokenPath() string {
-	return filepath.Join(xdg.DataHome, "pagen", "google-credentials.json")
+	return filepath.Join(charm.DataDir(), "google-credentials.json")
 }
 
 // SaveToken saves OAuth token to XDG data directory.
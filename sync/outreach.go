@@ -0,0 +1,151 @@
+// ABOUTME: Gmail side of outreach emails
+// ABOUTME: Creates drafts, builds browser compose links, and detects replies to pending outreach
+package sync
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// OutreachCheckResult summarizes a reply check.
+type OutreachCheckResult struct {
+	Checked int
+	Replied []*charm.Outreach
+}
+
+// CreateGmailDraft saves a plain-text draft to the user's mailbox and returns
+// the draft and thread IDs. Needs a token with a compose scope.
+func CreateGmailDraft(ctx context.Context, service *gmail.Service, to, subject, body string) (draftID, threadID string, err error) {
+	draft := &gmail.Draft{
+		Message: &gmail.Message{
+			Raw: base64.URLEncoding.EncodeToString([]byte(buildRawEmail(to, subject, body))),
+		},
+	}
+	created, err := service.Users.Drafts.Create("me", draft).Context(ctx).Do()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create Gmail draft: %w", err)
+	}
+	if created.Message != nil {
+		threadID = created.Message.ThreadId
+	}
+	return created.Id, threadID, nil
+}
+
+// buildRawEmail formats an RFC 2822 plain-text message.
+func buildRawEmail(to, subject, body string) string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return msg.String()
+}
+
+// GmailComposeURL returns a link that opens a prefilled Gmail compose window.
+func GmailComposeURL(to, subject, body string) string {
+	params := url.Values{}
+	params.Set("view", "cm")
+	params.Set("fs", "1")
+	params.Set("to", to)
+	params.Set("su", subject)
+	params.Set("body", body)
+	return "https://mail.google.com/mail/?" + params.Encode()
+}
+
+// CheckOutreachReplies looks for a reply from each pending outreach recipient
+// and marks the outreach replied, logging the reply as an email interaction.
+// Outreach with a known thread is checked in that thread; otherwise any email
+// from the recipient since the outreach was created counts as a reply.
+func CheckOutreachReplies(ctx context.Context, service *gmail.Service, client *charm.Client) (*OutreachCheckResult, error) {
+	pending, err := client.ListOutreach(charm.OutreachPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outreach: %w", err)
+	}
+
+	result := &OutreachCheckResult{}
+	for _, outreach := range pending {
+		result.Checked++
+
+		var reply *gmail.Message
+		if outreach.ThreadID != "" {
+			reply, err = findThreadReply(ctx, service, outreach)
+		} else {
+			reply, err = findReplySince(ctx, service, outreach)
+		}
+		if err != nil {
+			return result, err
+		}
+		if reply == nil {
+			continue
+		}
+
+		at := time.UnixMilli(reply.InternalDate)
+		subject := parseHeaders(reply.Payload)["Subject"]
+		if subject == "" {
+			subject = outreach.Subject
+		}
+		if err := client.MarkOutreachReplied(outreach, at, "Replied: "+subject); err != nil {
+			return result, fmt.Errorf("failed to record reply: %w", err)
+		}
+		result.Replied = append(result.Replied, outreach)
+	}
+	return result, nil
+}
+
+// findThreadReply returns the first message in the outreach thread sent by the recipient.
+func findThreadReply(ctx context.Context, service *gmail.Service, outreach *charm.Outreach) (*gmail.Message, error) {
+	thread, err := service.Users.Threads.Get("me", outreach.ThreadID).
+		Format("metadata").MetadataHeaders("From", "Subject").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread %s: %w", outreach.ThreadID, err)
+	}
+	for _, message := range thread.Messages {
+		if isReplyFrom(message, outreach) {
+			return message, nil
+		}
+	}
+	return nil, nil
+}
+
+// findReplySince returns the earliest message from the recipient since the outreach was created.
+func findReplySince(ctx context.Context, service *gmail.Service, outreach *charm.Outreach) (*gmail.Message, error) {
+	query := fmt.Sprintf("from:%s after:%d", outreach.Email, outreach.CreatedAt.Unix())
+	list, err := service.Users.Messages.List("me").Q(query).MaxResults(10).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search replies from %s: %w", outreach.Email, err)
+	}
+
+	var earliest *gmail.Message
+	for _, ref := range list.Messages {
+		message, err := service.Users.Messages.Get("me", ref.Id).
+			Format("metadata").MetadataHeaders("From", "Subject").Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message %s: %w", ref.Id, err)
+		}
+		if !isReplyFrom(message, outreach) {
+			continue
+		}
+		if earliest == nil || message.InternalDate < earliest.InternalDate {
+			earliest = message
+		}
+	}
+	return earliest, nil
+}
+
+// isReplyFrom reports whether message came from the outreach recipient after it was created.
+func isReplyFrom(message *gmail.Message, outreach *charm.Outreach) bool {
+	if message.InternalDate < outreach.CreatedAt.UnixMilli() {
+		return false
+	}
+	_, from, _ := ExtractEmailAddress(parseHeaders(message.Payload)["From"])
+	return strings.EqualFold(from, outreach.Email)
+}
@@ -0,0 +1,179 @@
+// ABOUTME: Tests for the Gmail side of outreach emails
+// ABOUTME: Verifies draft creation, sending, and reply detection by thread and by sender against a fake Gmail API
+package sync
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func newFakeGmail(t *testing.T, handler http.HandlerFunc) *gmail.Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	service, err := gmail.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"), option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	return service
+}
+
+func gmailMessage(id, from, subject string, at time.Time) map[string]any {
+	return map[string]any{
+		"id":           id,
+		"internalDate": fmt.Sprintf("%d", at.UnixMilli()),
+		"payload": map[string]any{"headers": []map[string]string{
+			{"name": "From", "value": from},
+			{"name": "Subject", "value": subject},
+		}},
+	}
+}
+
+func TestCreateGmailDraft(t *testing.T) {
+	var raw string
+	service := newFakeGmail(t, func(w http.ResponseWriter, r *http.Request) {
+		var draft gmail.Draft
+		_ = json.NewDecoder(r.Body).Decode(&draft)
+		raw = draft.Message.Raw
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "message": map[string]string{"id": "m1", "threadId": "t1"}})
+	})
+
+	draftID, threadID, err := CreateGmailDraft(context.Background(), service, "ada@example.com", "Hello", "Hi Ada,\nThanks")
+	if err != nil {
+		t.Fatalf("CreateGmailDraft failed: %v", err)
+	}
+	if draftID != "d1" || threadID != "t1" {
+		t.Errorf("unexpected draft %q thread %q", draftID, threadID)
+	}
+	decoded, _ := base64.URLEncoding.DecodeString(raw)
+	if !strings.Contains(string(decoded), "To: ada@example.com\r\n") || !strings.HasSuffix(string(decoded), "Hi Ada,\r\nThanks") {
+		t.Errorf("unexpected raw message: %q", decoded)
+	}
+}
+
+func TestGmailComposeURL(t *testing.T) {
+	u, err := url.Parse(GmailComposeURL("ada@example.com", "Hi & hello", "Line one\nLine two"))
+	if err != nil {
+		t.Fatalf("invalid URL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("to") != "ada@example.com" || q.Get("su") != "Hi & hello" || q.Get("body") != "Line one\nLine two" {
+		t.Errorf("unexpected compose params: %v", q)
+	}
+}
+
+func TestCheckOutreachReplies(t *testing.T) {
+	client := charm.NewTestClient(t)
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	contacts := map[string]*charm.Contact{}
+	for _, name := range []string{"Ada", "Grace", "Linus"} {
+		contact := &charm.Contact{Name: name, Email: strings.ToLower(name) + "@example.com"}
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+		contacts[name] = contact
+	}
+	outreach := []*charm.Outreach{
+		{ContactID: contacts["Ada"].ID, ContactName: "Ada", Email: "ada@example.com", Subject: "Intro", ThreadID: "t-ada", CreatedAt: created},
+		{ContactID: contacts["Grace"].ID, ContactName: "Grace", Email: "grace@example.com", Subject: "Check in", CreatedAt: created},
+		{ContactID: contacts["Linus"].ID, ContactName: "Linus", Email: "linus@example.com", Subject: "Hello", ThreadID: "t-linus", CreatedAt: created},
+	}
+	for _, o := range outreach {
+		if err := client.CreateOutreach(o); err != nil {
+			t.Fatalf("CreateOutreach failed: %v", err)
+		}
+	}
+
+	replyAt := created.Add(30 * time.Minute)
+	service := newFakeGmail(t, func(w http.ResponseWriter, r *http.Request) {
+		var resp any
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/threads/t-ada"):
+			resp = map[string]any{"messages": []any{
+				gmailMessage("m1", "me@example.com", "Intro", created),
+				gmailMessage("m2", "Ada <ada@example.com>", "Re: Intro", replyAt),
+			}}
+		case strings.HasSuffix(r.URL.Path, "/threads/t-linus"):
+			// Only our own message so far
+			resp = map[string]any{"messages": []any{gmailMessage("m3", "me@example.com", "Hello", created)}}
+		case strings.HasSuffix(r.URL.Path, "/messages"):
+			if !strings.Contains(r.URL.Query().Get("q"), "from:grace@example.com") {
+				t.Errorf("unexpected search: %s", r.URL.Query().Get("q"))
+			}
+			resp = map[string]any{"messages": []any{map[string]string{"id": "m4"}}}
+		case strings.HasSuffix(r.URL.Path, "/messages/m4"):
+			resp = gmailMessage("m4", "grace@example.com", "Catching up", replyAt)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	result, err := CheckOutreachReplies(context.Background(), service, client)
+	if err != nil {
+		t.Fatalf("CheckOutreachReplies failed: %v", err)
+	}
+	if result.Checked != 3 || len(result.Replied) != 2 {
+		t.Fatalf("expected 2 of 3 replied, got %d of %d", len(result.Replied), result.Checked)
+	}
+
+	pending, _ := client.ListOutreach(charm.OutreachPending)
+	if len(pending) != 1 || pending[0].ContactName != "Linus" {
+		t.Errorf("expected only Linus pending, got %+v", pending)
+	}
+
+	interactions, err := client.ListInteractionLogs(&charm.InteractionFilter{ContactID: &contacts["Ada"].ID})
+	if err != nil {
+		t.Fatalf("ListInteractionLogs failed: %v", err)
+	}
+	if len(interactions) != 1 || interactions[0].Notes != "Replied: Re: Intro" || !interactions[0].Timestamp.Equal(replyAt) {
+		t.Errorf("unexpected reply interaction: %+v", interactions)
+	}
+}
+
+func TestSendGmailMessage(t *testing.T) {
+	var raw string
+	service := newFakeGmail(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/profile":
+			_ = json.NewEncoder(w).Encode(map[string]any{"emailAddress": "me@example.com"})
+		case "/gmail/v1/users/me/messages/send":
+			var message gmail.Message
+			_ = json.NewDecoder(r.Body).Decode(&message)
+			raw = message.Raw
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	address, err := GmailAddress(context.Background(), service)
+	if err != nil || address != "me@example.com" {
+		t.Fatalf("expected me@example.com, got %q, %v", address, err)
+	}
+	id, err := SendGmailMessage(context.Background(), service, []byte("To: me@example.com\r\nSubject: Digest\r\n\r\nHello"))
+	if err != nil || id != "m1" {
+		t.Fatalf("expected message m1, got %q, %v", id, err)
+	}
+	decoded, _ := base64.URLEncoding.DecodeString(raw)
+	if !strings.HasPrefix(string(decoded), "To: me@example.com\r\n") {
+		t.Errorf("unexpected raw message: %q", decoded)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"golang.org/x/oauth2"
-	"google.golang.org/api/option"
 	"google.golang.org/api/people/v1"
 )
 
@@ -20,10 +19,5 @@ func NewPeopleClient(token *oauth2.Token) (*people.Service, error) {
 	config := NewOAuthConfig()
 	client := config.Client(context.Background(), token)
 
-	service, err := people.NewService(context.Background(), option.WithHTTPClient(client))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create People service: %w", err)
-	}
-
-	return service, nil
+	return NewPeopleService(client, "")
 }
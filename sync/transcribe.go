@@ -0,0 +1,174 @@
+// ABOUTME: Voice memo transcription through a local command (e.g. whisper.cpp) or a hosted API
+// ABOUTME: Turns an audio file into text for interaction notes, configured under transcription in charm-config.json
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// DefaultTranscriptionModel is the model sent to hosted APIs when none is configured.
+const DefaultTranscriptionModel = "whisper-1"
+
+// DefaultTranscriptionAPIKeyEnv holds the hosted API key when no other variable is configured.
+const DefaultTranscriptionAPIKeyEnv = "PAGEN_TRANSCRIPTION_API_KEY"
+
+// TranscribeTimeout bounds one transcription; local models on long memos are slow.
+const TranscribeTimeout = 10 * time.Minute
+
+// Transcriber turns an audio file into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, path string) (string, error)
+}
+
+// NewTranscriber returns the transcriber cfg describes: its command when set,
+// and otherwise its API.
+func NewTranscriber(cfg *charm.TranscriptionConfig) (Transcriber, error) {
+	switch {
+	case cfg == nil || (cfg.Command == "" && cfg.URL == ""):
+		return nil, fmt.Errorf("no transcription configured; set transcription.command or transcription.url in charm-config.json")
+	case cfg.Command != "":
+		return &CommandTranscriber{Command: cfg.Command}, nil
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = DefaultTranscriptionModel
+	}
+	keyEnv := cfg.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = DefaultTranscriptionAPIKeyEnv
+	}
+	return &APITranscriber{
+		URL:        cfg.URL,
+		Model:      model,
+		APIKey:     os.Getenv(keyEnv),
+		HTTPClient: &http.Client{Timeout: TranscribeTimeout},
+	}, nil
+}
+
+// CommandTranscriber runs a shell command and reads the transcript from its
+// output. {file} in the command is replaced by the quoted audio path, which
+// is appended when the command doesn't mention it.
+type CommandTranscriber struct {
+	Command string
+}
+
+// Transcribe runs the command on path.
+func (c *CommandTranscriber) Transcribe(ctx context.Context, path string) (string, error) {
+	quoted := shellQuote(path)
+	command := strings.ReplaceAll(c.Command, "{file}", quoted)
+	if !strings.Contains(c.Command, "{file}") {
+		command += " " + quoted
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := lastLine(stderr.String()); detail != "" {
+			return "", fmt.Errorf("transcription command failed: %w: %s", err, detail)
+		}
+		return "", fmt.Errorf("transcription command failed: %w", err)
+	}
+	return cleanTranscript(stdout.String()), nil
+}
+
+// APITranscriber posts the audio to an OpenAI-compatible transcription endpoint.
+type APITranscriber struct {
+	URL        string
+	Model      string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Transcribe uploads path and returns the text of the response.
+func (a *APITranscriber) Transcribe(ctx context.Context, path string) (string, error) {
+	audio, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open audio: %w", err)
+	}
+	defer func() { _ = audio.Close() }()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return "", fmt.Errorf("failed to read audio: %w", err)
+	}
+	_ = form.WriteField("model", a.Model)
+	_ = form.WriteField("response_format", "json")
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, &body)
+	if err != nil {
+		return "", fmt.Errorf("invalid transcription URL: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if a.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+a.APIKey)
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read transcription: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("transcription API returned %s: %s", resp.Status, lastLine(string(data)))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("failed to parse transcription: %w", err)
+	}
+	return cleanTranscript(result.Text), nil
+}
+
+// cleanTranscript joins the transcript's lines into one paragraph. Local
+// tools print a segment per line, often with leading spaces.
+func cleanTranscript(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// lastLine returns the last non-empty line of s, for error messages.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Tests for voice memo transcription
+// ABOUTME: Runs the command transcriber through sh and the API transcriber against a fake endpoint
+package sync
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func writeMemo(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write memo: %v", err)
+	}
+	return path
+}
+
+func TestCommandTranscriber(t *testing.T) {
+	// A name with a space and a quote checks the path is quoted for sh.
+	memo := writeMemo(t, "Bob's call.m4a", "  Called Bob from the car.\n  He wants a quote by Friday.\n")
+
+	for _, command := range []string{"cat {file}", "cat"} {
+		transcriber, err := NewTranscriber(&charm.TranscriptionConfig{Command: command})
+		if err != nil {
+			t.Fatalf("NewTranscriber failed: %v", err)
+		}
+		got, err := transcriber.Transcribe(context.Background(), memo)
+		if err != nil {
+			t.Fatalf("%q: Transcribe failed: %v", command, err)
+		}
+		if want := "Called Bob from the car. He wants a quote by Friday."; got != want {
+			t.Errorf("%q: got %q, want %q", command, got, want)
+		}
+	}
+
+	failing := &CommandTranscriber{Command: "echo 'model not found' >&2; exit 1"}
+	if _, err := failing.Transcribe(context.Background(), memo); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("expected the command's error output, got %v", err)
+	}
+}
+
+func TestAPITranscriber(t *testing.T) {
+	memo := writeMemo(t, "memo.m4a", "fake audio")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, `{"error":"bad key"}`, http.StatusUnauthorized)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("missing file: %v", err)
+			return
+		}
+		audio, _ := io.ReadAll(file)
+		if header.Filename != "memo.m4a" || string(audio) != "fake audio" {
+			t.Errorf("unexpected upload %s: %q", header.Filename, audio)
+		}
+		if model := r.FormValue("model"); model != DefaultTranscriptionModel {
+			t.Errorf("unexpected model %q", model)
+		}
+		_, _ = io.WriteString(w, `{"text":" Quote by Friday. "}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("MEMO_KEY", "secret")
+	transcriber, err := NewTranscriber(&charm.TranscriptionConfig{URL: server.URL, APIKeyEnv: "MEMO_KEY"})
+	if err != nil {
+		t.Fatalf("NewTranscriber failed: %v", err)
+	}
+	got, err := transcriber.Transcribe(context.Background(), memo)
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if got != "Quote by Friday." {
+		t.Errorf("got %q", got)
+	}
+
+	t.Setenv("MEMO_KEY", "wrong")
+	transcriber, _ = NewTranscriber(&charm.TranscriptionConfig{URL: server.URL, APIKeyEnv: "MEMO_KEY"})
+	if _, err := transcriber.Transcribe(context.Background(), memo); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected a 401 error, got %v", err)
+	}
+
+	if _, err := NewTranscriber(nil); err == nil {
+		t.Error("expected an error without transcription configured")
+	}
+}
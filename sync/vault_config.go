@@ -10,7 +10,7 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/adrg/xdg"
+	"github.com/harperreed/pagen/charm"
 	"github.com/oklog/ulid/v2"
 )
 
@@ -25,11 +25,16 @@ type VaultConfig struct {
 	DeviceID     string `json:"device_id"`
 	VaultDB      string `json:"vault_db"`
 	AutoSync     bool   `json:"auto_sync"`
+
+	// StrictResolution makes applying pulled changes fail, listing the candidates,
+	// when a company or contact name matches several records instead of taking the first
+	StrictResolution bool `json:"strict_resolution,omitempty"`
 }
 
-// VaultConfigDir returns XDG-compliant directory for vault configuration.
+// VaultConfigDir returns XDG-compliant directory for vault configuration,
+// which is the active profile's data directory.
 func VaultConfigDir() string {
-	return filepath.Join(xdg.DataHome, "pagen")
+	return charm.DataDir()
 }
 
 // VaultConfigPath returns XDG-compliant path for storing vault configuration.
@@ -44,7 +49,8 @@ func VaultConfigPath() string {
 // - PAGEN_VAULT_TOKEN
 // - PAGEN_VAULT_USER_ID
 // - PAGEN_VAULT_DEVICE_ID
-// - PAGEN_VAULT_AUTO_SYNC.
+// - PAGEN_VAULT_AUTO_SYNC
+// - PAGEN_VAULT_STRICT.
 func LoadVaultConfig() (*VaultConfig, error) {
 	path := VaultConfigPath()
 
@@ -93,6 +99,9 @@ func applyEnvOverrides(cfg *VaultConfig) {
 	if autoSync := os.Getenv("PAGEN_VAULT_AUTO_SYNC"); autoSync != "" {
 		cfg.AutoSync = autoSync == "true" || autoSync == "1"
 	}
+	if strict := os.Getenv("PAGEN_VAULT_STRICT"); strict != "" {
+		cfg.StrictResolution = strict == "true" || strict == "1"
+	}
 }
 
 // SaveVaultConfig saves vault configuration to XDG data directory.
@@ -50,6 +50,7 @@ type DealPayload struct {
 	ContactID         string  `json:"contact_id,omitempty"`
 	ContactName       string  `json:"contact_name,omitempty"`        // denormalized from ContactID
 	ExpectedCloseDate *string `json:"expected_close_date,omitempty"` // RFC3339 timestamp
+	Probability       *int    `json:"probability,omitempty"`         // win % override
 }
 
 // DealNotePayload represents a note attached to a deal.
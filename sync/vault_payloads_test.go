@@ -171,6 +171,7 @@ func TestCompanyPayloadJSON_OmitEmpty(t *testing.T) {
 func TestDealPayloadJSON(t *testing.T) {
 	// Create test deal with all fields
 	closeDate := "2025-12-31T00:00:00Z"
+	probability := 60
 	original := &DealPayload{
 		ID:                "deal-123",
 		Title:             "Enterprise Contract",
@@ -180,6 +181,7 @@ func TestDealPayloadJSON(t *testing.T) {
 		CompanyName:       "Big Customer",
 		ContactName:       "Decision Maker",
 		ExpectedCloseDate: &closeDate,
+		Probability:       &probability,
 	}
 
 	// Marshal to JSON
@@ -201,6 +203,8 @@ func TestDealPayloadJSON(t *testing.T) {
 	assert.Equal(t, original.ContactName, decoded.ContactName)
 	require.NotNil(t, decoded.ExpectedCloseDate)
 	assert.Equal(t, *original.ExpectedCloseDate, *decoded.ExpectedCloseDate)
+	require.NotNil(t, decoded.Probability)
+	assert.Equal(t, probability, *decoded.Probability)
 }
 
 func TestDealPayloadJSON_MinimalFields(t *testing.T) {
@@ -256,6 +260,7 @@ func TestDealPayloadJSON_OmitEmpty(t *testing.T) {
 	assert.NotContains(t, parsed, "company_name")
 	assert.NotContains(t, parsed, "contact_name")
 	assert.NotContains(t, parsed, "expected_close_date")
+	assert.NotContains(t, parsed, "probability")
 }
 
 func TestDealPayloadJSON_AmountPrecision(t *testing.T) {
@@ -16,6 +16,7 @@ import (
 	"github.com/harperreed/sweet/vault"
 
 	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
 	"github.com/harperreed/pagen/db"
 	"github.com/harperreed/pagen/models"
 )
@@ -42,6 +43,23 @@ type VaultSyncer struct {
 	client      *vault.Client
 	vaultSyncer *vault.Syncer
 	appDB       *sql.DB
+	resolve     db.ConflictResolver // see SetConflictResolver
+}
+
+// SetConflictResolver sets how a vault change is saved over a record that
+// another process updated while the change was being applied. By default the
+// change's fields are re-applied to the record as it is now, keeping the
+// other process's edits to anything else.
+func (s *VaultSyncer) SetConflictResolver(resolve db.ConflictResolver) {
+	s.resolve = resolve
+}
+
+// resolvers returns the resolver for the legacy Update functions, if set.
+func (s *VaultSyncer) resolvers() []db.ConflictResolver {
+	if s.resolve == nil {
+		return nil
+	}
+	return []db.ConflictResolver{s.resolve}
 }
 
 // NewVaultSyncer creates a new vault syncer instance.
@@ -214,6 +232,7 @@ func (s *VaultSyncer) QueueDealChange(ctx context.Context, deal *models.Deal, co
 		ContactID:         contactID,
 		ContactName:       contactName,
 		ExpectedCloseDate: expectedCloseDate,
+		Probability:       deal.Probability,
 	}
 	return s.queueChange(ctx, EntityDeal, deal.ID.String(), op, payload)
 }
@@ -361,7 +380,7 @@ func (s *VaultSyncer) applyContactChange(ctx context.Context, c vault.Change) er
 				}
 			}
 		} else if payload.CompanyName != "" {
-			company, err := db.FindCompanyByName(s.appDB, payload.CompanyName)
+			company, err := s.findCompanyByName(payload.CompanyName)
 			if err != nil {
 				return fmt.Errorf("failed to find company: %w", err)
 			}
@@ -395,7 +414,7 @@ func (s *VaultSyncer) applyContactChange(ctx context.Context, c vault.Change) er
 				return fmt.Errorf("failed to create contact: %w", err)
 			}
 		} else {
-			if err := db.UpdateContact(s.appDB, id, contact); err != nil {
+			if err := db.UpdateContact(s.appDB, id, contact, s.resolvers()...); err != nil {
 				return fmt.Errorf("failed to update contact: %w", err)
 			}
 		}
@@ -448,7 +467,7 @@ func (s *VaultSyncer) applyCompanyChange(ctx context.Context, c vault.Change) er
 				return fmt.Errorf("failed to create company: %w", err)
 			}
 		} else {
-			if err := db.UpdateCompany(s.appDB, id, company); err != nil {
+			if err := db.UpdateCompany(s.appDB, id, company, s.resolvers()...); err != nil {
 				return fmt.Errorf("failed to update company: %w", err)
 			}
 		}
@@ -482,12 +501,13 @@ func (s *VaultSyncer) applyDealChange(ctx context.Context, c vault.Change) error
 		}
 
 		deal := &models.Deal{
-			ID:        id,
-			Title:     payload.Title,
-			Amount:    payload.Amount,
-			Currency:  payload.Currency,
-			Stage:     payload.Stage,
-			CompanyID: company.ID,
+			ID:          id,
+			Title:       payload.Title,
+			Amount:      payload.Amount,
+			Currency:    payload.Currency,
+			Stage:       payload.Stage,
+			CompanyID:   company.ID,
+			Probability: payload.Probability,
 		}
 
 		if payload.ExpectedCloseDate != nil {
@@ -505,12 +525,12 @@ func (s *VaultSyncer) applyDealChange(ctx context.Context, c vault.Change) error
 				}
 			}
 		} else if payload.ContactName != "" {
-			contacts, err := db.FindContacts(s.appDB, payload.ContactName, &company.ID, 1)
+			contact, err := s.findContactByName(payload.ContactName, &company.ID)
 			if err != nil {
 				return fmt.Errorf("failed to find contact: %w", err)
 			}
-			if len(contacts) > 0 {
-				deal.ContactID = &contacts[0].ID
+			if contact != nil {
+				deal.ContactID = &contact.ID
 			}
 		}
 
@@ -525,7 +545,7 @@ func (s *VaultSyncer) applyDealChange(ctx context.Context, c vault.Change) error
 			}
 		} else {
 			deal.CreatedAt = existing.CreatedAt
-			if err := db.UpdateDeal(s.appDB, deal); err != nil {
+			if err := db.UpdateDeal(s.appDB, deal, s.resolvers()...); err != nil {
 				return fmt.Errorf("failed to update deal: %w", err)
 			}
 		}
@@ -827,18 +847,84 @@ func (s *VaultSyncer) ensureContact(ctx context.Context, id uuid.UUID, name stri
 	return placeholder, nil
 }
 
+// strictLookupLimit bounds how many records a strict lookup compares.
+const strictLookupLimit = 100
+
+// findContactByName returns the first contact matching name. With
+// StrictResolution it only accepts a name matching exactly one contact and
+// returns a *charm.AmbiguousMatchError otherwise.
 func (s *VaultSyncer) findContactByName(name string, companyID *uuid.UUID) (*models.Contact, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, nil
 	}
-	contacts, err := db.FindContacts(s.appDB, name, companyID, 1)
+	if !s.config.StrictResolution {
+		contacts, err := db.FindContacts(s.appDB, name, companyID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(contacts) == 0 {
+			return nil, nil
+		}
+		return &contacts[0], nil
+	}
+
+	contacts, err := db.FindContacts(s.appDB, name, companyID, strictLookupLimit)
 	if err != nil {
 		return nil, err
 	}
 	if len(contacts) == 0 {
 		return nil, nil
 	}
-	return &contacts[0], nil
+	var exact []models.Contact
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Name, strings.TrimSpace(name)) || strings.EqualFold(contact.Email, strings.TrimSpace(name)) {
+			exact = append(exact, contact)
+		}
+	}
+	if len(exact) == 1 {
+		return &exact[0], nil
+	}
+	if len(exact) == 0 {
+		exact = contacts
+	}
+
+	ambiguous := &charm.AmbiguousMatchError{EntityType: charm.EntityContact, Ref: name}
+	for _, contact := range exact {
+		ambiguous.Candidates = append(ambiguous.Candidates, charm.MatchCandidate{ID: contact.ID, Name: contact.Name, Detail: contact.Email})
+	}
+	return nil, ambiguous
+}
+
+// findCompanyByName returns the company with this name, ignoring case. With
+// StrictResolution a name shared by several companies returns a
+// *charm.AmbiguousMatchError instead of the first one.
+func (s *VaultSyncer) findCompanyByName(name string) (*models.Company, error) {
+	if !s.config.StrictResolution {
+		return db.FindCompanyByName(s.appDB, name)
+	}
+
+	companies, err := db.FindCompanies(s.appDB, name, strictLookupLimit)
+	if err != nil {
+		return nil, err
+	}
+	var exact []models.Company
+	for _, company := range companies {
+		if strings.EqualFold(company.Name, strings.TrimSpace(name)) {
+			exact = append(exact, company)
+		}
+	}
+	switch len(exact) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &exact[0], nil
+	}
+
+	ambiguous := &charm.AmbiguousMatchError{EntityType: charm.EntityCompany, Ref: name}
+	for _, company := range exact {
+		ambiguous.Candidates = append(ambiguous.Candidates, charm.MatchCandidate{ID: company.ID, Name: company.Name, Detail: company.Domain})
+	}
+	return nil, ambiguous
 }
 
 func (s *VaultSyncer) resolveContactIdentifier(ctx context.Context, idStr, name string) (uuid.UUID, error) {
@@ -875,7 +961,7 @@ func (s *VaultSyncer) resolveCompanyForDeal(ctx context.Context, payload DealPay
 		}
 	}
 	if payload.CompanyName != "" {
-		company, err := db.FindCompanyByName(s.appDB, payload.CompanyName)
+		company, err := s.findCompanyByName(payload.CompanyName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find company: %w", err)
 		}
@@ -893,7 +979,7 @@ func (s *VaultSyncer) resolveNoteCompany(ctx context.Context, payload DealNotePa
 		}
 	}
 	if payload.DealCompanyName != "" {
-		company, err := db.FindCompanyByName(s.appDB, payload.DealCompanyName)
+		company, err := s.findCompanyByName(payload.DealCompanyName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find company for deal note: %w", err)
 		}
@@ -0,0 +1,88 @@
+// ABOUTME: Quick-capture scratchpad overlay for the TUI
+// ABOUTME: Opens from any view with ctrl+n and saves a free-form line as a task or interaction
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// captureKey opens the quick-capture overlay from any view.
+const captureKey = "ctrl+n"
+
+var (
+	captureBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("170")).
+			Padding(0, 1).
+			Width(70)
+
+	captureErrorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("9"))
+)
+
+// openCapture shows the quick-capture overlay over the current view.
+func (m Model) openCapture() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "call Dana re: renewal #task @dana due:fri"
+	input.CharLimit = 500
+	input.Width = 64
+	input.Focus()
+
+	m.captureActive = true
+	m.captureInput = input
+	m.captureError = ""
+	m.captureMessage = ""
+	return m, textinput.Blink
+}
+
+// handleCaptureKeys edits the capture line; enter saves it and esc cancels.
+// Errors keep the overlay open so the line can be fixed.
+func (m Model) handleCaptureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.captureActive = false
+		return m, nil
+	case "enter":
+		line := strings.TrimSpace(m.captureInput.Value())
+		if line == "" {
+			m.captureActive = false
+			return m, nil
+		}
+		result, err := m.client.Capture(line)
+		if err != nil {
+			m.captureError = err.Error()
+			return m, nil
+		}
+		m.invalidateLists()
+		m.captureActive = false
+		m.captureMessage = result.Summary()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.captureInput, cmd = m.captureInput.Update(msg)
+	m.captureError = ""
+	return m, cmd
+}
+
+// renderCaptureOverlay draws the capture box below the current view.
+func (m Model) renderCaptureOverlay(base string) string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Quick capture"))
+	s.WriteString("\n")
+	s.WriteString(m.captureInput.View())
+	if m.captureError != "" {
+		s.WriteString("\n")
+		s.WriteString(captureErrorStyle.Render("✗ " + m.captureError))
+	}
+	s.WriteString("\n")
+	s.WriteString(helpStyle.Render("#task #call #meeting #email • @name • due:fri • Enter: Save • Esc: Cancel"))
+
+	return base + "\n" + captureBoxStyle.Render(s.String())
+}
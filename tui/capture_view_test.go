@@ -0,0 +1,73 @@
+// ABOUTME: Tests for the quick-capture overlay
+// ABOUTME: Verifies the hotkey, typing over global keys, saving, and error display
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func typeCapture(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestCaptureOverlay(t *testing.T) {
+	client := charm.NewTestClient(t)
+	if err := client.CreateContact(&charm.Contact{Name: "Dana Scully"}); err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+
+	m := NewModel(client)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = updated.(Model)
+	if !m.captureActive {
+		t.Fatal("ctrl+n should open the capture overlay")
+	}
+	if !contains(m.View(), "Quick capture") {
+		t.Error("view should show the capture overlay")
+	}
+
+	// q is typed into the line instead of quitting
+	m = typeCapture(t, m, "quote #task @dana due:fri")
+	if m.captureInput.Value() != "quote #task @dana due:fri" {
+		t.Fatalf("unexpected capture line: %q", m.captureInput.Value())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.captureActive || !contains(m.captureMessage, "Task added: quote (Dana Scully") {
+		t.Fatalf("expected the task to be saved, got active=%v message=%q error=%q", m.captureActive, m.captureMessage, m.captureError)
+	}
+	tasks, err := client.ListTasks(false)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected one task, got %v, %v", tasks, err)
+	}
+}
+
+func TestCaptureOverlayError(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	m := NewModel(client)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = typeCapture(t, updated.(Model), "lunch @nobody")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.captureActive || m.captureError == "" {
+		t.Fatal("an unknown reference should keep the overlay open with an error")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.captureActive {
+		t.Error("esc should close the overlay")
+	}
+}
@@ -114,6 +114,7 @@ func (m Model) handleConfirmDeleteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.deleteMessage = "Error: " + err.Error()
 			m.viewMode = ViewList
 		} else {
+			m.invalidateLists()
 			m.deleteMessage = "Successfully deleted"
 			m.viewMode = ViewList
 			m.selectedID = "" // Clear selection
@@ -60,6 +60,14 @@ func (m Model) renderContactDetail() string {
 	var s strings.Builder
 
 	s.WriteString(m.renderField("Name", contact.Name))
+	if len(contact.Pinned) > 0 {
+		s.WriteString(lipgloss.NewStyle().Bold(true).Render("PINNED"))
+		s.WriteString("\n")
+		for _, fact := range contact.Pinned {
+			s.WriteString(fmt.Sprintf("  • %s\n", fact))
+		}
+		s.WriteString("\n")
+	}
 	s.WriteString(m.renderField("Email", contact.Email))
 	s.WriteString(m.renderField("Phone", contact.Phone))
 
@@ -72,7 +80,25 @@ func (m Model) renderContactDetail() string {
 		s.WriteString(m.renderField("Last Contacted", contact.LastContactedAt.Format("2006-01-02")))
 	}
 
-	s.WriteString(m.renderField("Notes", contact.Notes))
+	if role := contact.Role(); role != "" {
+		s.WriteString(m.renderField("Role", role))
+	}
+
+	if len(contact.Tags) > 0 {
+		s.WriteString(m.renderField("Tags", strings.Join(contact.Tags, ", ")))
+	}
+
+	if howWeMet := contact.HowWeMet(); howWeMet != "" {
+		s.WriteString(m.renderField("How We Met", howWeMet))
+	}
+
+	if contact.Notes != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Bold(true).Render("NOTES"))
+		s.WriteString("\n")
+		s.WriteString(renderMarkdown(contact.Notes, m.width))
+		s.WriteString("\n")
+	}
 
 	// Related entities
 	s.WriteString("\n")
@@ -103,6 +129,18 @@ func (m Model) renderCompanyDetail() string {
 	s.WriteString(m.renderField("Name", company.Name))
 	s.WriteString(m.renderField("Domain", company.Domain))
 	s.WriteString(m.renderField("Industry", company.Industry))
+	if employees := company.Employees(); employees != "" {
+		s.WriteString(m.renderField("Employees", employees))
+	}
+	if company.FundingStage != "" {
+		s.WriteString(m.renderField("Funding", charm.FundingStageLabel(company.FundingStage)))
+	}
+	if company.HQLocation != "" {
+		s.WriteString(m.renderField("HQ", company.HQLocation))
+	}
+	if len(company.Tags) > 0 {
+		s.WriteString(m.renderField("Tags", strings.Join(company.Tags, ", ")))
+	}
 	s.WriteString(m.renderField("Notes", company.Notes))
 
 	// Contacts at company
@@ -146,12 +184,36 @@ func (m Model) renderDealDetail() string {
 	}
 
 	s.WriteString(m.renderField("Stage", deal.Stage))
-	s.WriteString(m.renderField("Amount", fmt.Sprintf("$%d %s", deal.Amount/100, deal.Currency)))
+	s.WriteString(m.renderField("Amount", charm.FormatMoney(deal.Amount, deal.Currency)))
+	s.WriteString(m.renderField("Probability", fmt.Sprintf("%d%% (expected %s)", deal.WinProbability(), charm.FormatMoney(deal.ExpectedValue(), deal.Currency))))
 
 	if deal.ExpectedCloseDate != nil {
 		s.WriteString(m.renderField("Expected Close", deal.ExpectedCloseDate.Format("2006-01-02")))
 	}
 
+	if deal.Source != "" {
+		s.WriteString(m.renderField("Source", deal.Source))
+	}
+
+	if deal.ReferrerName != "" {
+		s.WriteString(m.renderField("Referred By", deal.ReferrerName))
+	}
+
+	if len(deal.Tags) > 0 {
+		s.WriteString(m.renderField("Tags", strings.Join(deal.Tags, ", ")))
+	}
+
+	// Contacts with roles
+	roles, _ := m.client.ListDealContacts(id)
+	if len(roles) > 0 {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Bold(true).Render("CONTACTS"))
+		s.WriteString("\n")
+		for _, role := range roles {
+			s.WriteString(fmt.Sprintf("  • %s (%s)\n", role.ContactName, charm.DealRoleLabel(role.Role)))
+		}
+	}
+
 	// Notes
 	s.WriteString("\n")
 	s.WriteString(lipgloss.NewStyle().Bold(true).Render("NOTES"))
@@ -159,7 +221,16 @@ func (m Model) renderDealDetail() string {
 
 	notes, _ := m.client.ListDealNotes(id)
 	for _, note := range notes {
-		s.WriteString(fmt.Sprintf("  • [%s] %s\n", note.CreatedAt.Format("2006-01-02"), note.Content))
+		s.WriteString(fmt.Sprintf("  • [%s]\n", note.CreatedAt.Format("2006-01-02")))
+		s.WriteString(renderMarkdown(note.Content, m.width))
+		s.WriteString("\n")
+	}
+
+	if items := m.dealChecklist(); len(items) > 0 {
+		s.WriteString(m.renderDealChecklist(items))
+	}
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("\nError: %v\n", m.err))
 	}
 
 	return s.String()
@@ -182,11 +253,18 @@ func (m Model) renderDetailHelp() string {
 		"g: View graph",
 		"q: Quit",
 	}
+	if m.entityType == EntityDeals {
+		help = append([]string{"↑/↓: Checklist item", "Space: Check off"}, help...)
+	}
 	return helpStyle.Render(strings.Join(help, " • "))
 }
 
 func (m Model) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
+	case "up", "k", "down", "j", " ", "x":
+		if m.entityType == EntityDeals {
+			return m.handleChecklistKeys(msg.String())
+		}
 	case "esc":
 		m.viewMode = ViewList
 	case "e":
@@ -33,6 +33,9 @@ func (m Model) renderEditView() string {
 		s.WriteString("\n")
 	}
 
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("\nError: %v\n", m.err))
+	}
 	s.WriteString("\n")
 
 	// Help
@@ -77,6 +80,8 @@ func (m Model) handleEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if err != nil {
 			m.err = err
 		} else {
+			m.err = nil
+			m.invalidateLists()
 			m.viewMode = ViewList
 		}
 		return m, nil
@@ -261,6 +266,15 @@ func (m Model) saveContact() error {
 		Notes: m.formInputs[4].Value(),
 	}
 
+	// A taken email is handled before a company is created for it; an
+	// existing contact returned by the policy is kept as it is
+	if m.selectedID == "" {
+		existing, err := m.client.CheckDuplicateEmail(contact.Email, m.client.DuplicateEmailPolicy(charm.EntryTUI))
+		if err != nil || existing != nil {
+			return err
+		}
+	}
+
 	// Handle company lookup/creation if company_name provided
 	companyName := m.formInputs[3].Value()
 	if companyName != "" {
@@ -1,57 +1,54 @@
 // ABOUTME: TUI view for follow-up tracking
-// ABOUTME: Displays prioritized list of contacts needing follow-up
+// ABOUTME: Displays weekly outreach goal progress and the prioritized list of contacts needing follow-up
 package tui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/harperreed/pagen/charm"
 )
 
 func (m Model) renderFollowupsTable() string {
-	followups, err := m.client.GetFollowupList(100)
-	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
-	}
+	t := m.list()
+	return m.renderGoals(t.goals) + t.render(m.selectedRow, m.pageSize(), "No contacts need follow-up")
+}
 
-	columns := []table.Column{
-		{Title: "Status", Width: 6},
-		{Title: "Name", Width: 25},
-		{Title: "Days", Width: 8},
-		{Title: "Priority", Width: 10},
-		{Title: "Strength", Width: 10},
-		{Title: "Email", Width: 30},
+// loadGoals measures the configured weekly outreach goals.
+func (m Model) loadGoals() ([]*charm.GoalProgress, error) {
+	cfg := m.client.Config()
+	if cfg == nil {
+		return nil, nil
 	}
+	return m.client.GoalProgress(cfg.OutreachGoals, time.Now())
+}
 
-	var rows []table.Row
-	for _, f := range followups {
-		indicator := "🟢"
-		if f.DaysSinceContact > f.CadenceDays+7 {
-			indicator = "🔴"
-		} else if f.DaysSinceContact >= f.CadenceDays-3 {
-			indicator = "🟡"
-		}
-
-		rows = append(rows, table.Row{
-			indicator,
-			f.Name,
-			fmt.Sprintf("%d", f.DaysSinceContact),
-			fmt.Sprintf("%.1f", f.PriorityScore),
-			f.RelationshipStrength,
-			f.Email,
-		})
+// renderGoals draws a progress bar per outreach goal, green when met and
+// yellow when behind pace.
+func (m Model) renderGoals(goals []*charm.GoalProgress) string {
+	if len(goals) == 0 {
+		return ""
 	}
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(m.height-10),
-	)
-
-	if m.selectedRow < len(rows) {
-		t.SetCursor(m.selectedRow)
+	met := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	behind := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+
+	now := time.Now()
+	var s strings.Builder
+	for _, goal := range goals {
+		line := fmt.Sprintf("%-14s %s  %d/%d this week", goal.Tag, goal.Bar(20), goal.Touched, goal.Target)
+		switch {
+		case goal.Met():
+			line = met.Render(line + "  ✓")
+		case goal.Behind(now):
+			line = behind.Render(line)
+			if len(goal.Suggestions) > 0 {
+				line += helpStyle.Render("  try " + strings.Join(goal.Suggestions, ", "))
+			}
+		}
+		s.WriteString(line + "\n")
 	}
-
-	return t.View()
+	s.WriteString("\n")
+	return s.String()
 }
@@ -1,14 +1,10 @@
 package tui
 
 import (
-	"fmt"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-
-	"github.com/harperreed/pagen/charm"
 )
 
 func (m Model) renderListView() string {
@@ -26,11 +22,23 @@ func (m Model) renderListView() string {
 		s.WriteString(msgStyle.Render("✓ " + m.deleteMessage))
 		s.WriteString("\n\n")
 	}
+	if m.captureMessage != "" {
+		msgStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")).
+			Bold(true)
+		s.WriteString(msgStyle.Render("✓ " + m.captureMessage))
+		s.WriteString("\n\n")
+	}
 
 	// Tabs
 	s.WriteString(m.renderTabs())
 	s.WriteString("\n\n")
 
+	// Search
+	if m.entityType <= EntityDeals {
+		s.WriteString(m.renderSearchLine())
+	}
+
 	// Table
 	s.WriteString(m.renderTable())
 	s.WriteString("\n\n")
@@ -73,134 +81,32 @@ func (m Model) renderTable() string {
 }
 
 func (m Model) renderContactsTable() string {
-	contacts, err := m.client.ListContacts(&charm.ContactFilter{
-		Query: m.searchQuery,
-		Limit: 100,
-	})
-	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
-	}
-
-	columns := []table.Column{
-		{Title: "Name", Width: 30},
-		{Title: "Email", Width: 30},
-		{Title: "Company", Width: 20},
-	}
-
-	var rows []table.Row
-	for _, contact := range contacts {
-		// Company name is denormalized in charm model
-		rows = append(rows, table.Row{
-			contact.Name,
-			contact.Email,
-			contact.CompanyName,
-		})
-	}
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(m.height-10),
-	)
-
-	// Set selected row
-	if m.selectedRow < len(rows) {
-		t.SetCursor(m.selectedRow)
-	}
-
-	return t.View()
+	return m.list().render(m.selectedRow, m.pageSize(), "No contacts")
 }
 
 func (m Model) renderCompaniesTable() string {
-	companies, err := m.client.ListCompanies(&charm.CompanyFilter{
-		Query: m.searchQuery,
-		Limit: 100,
-	})
-	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
-	}
-
-	columns := []table.Column{
-		{Title: "Name", Width: 30},
-		{Title: "Domain", Width: 30},
-		{Title: "Industry", Width: 20},
-	}
-
-	var rows []table.Row
-	for _, company := range companies {
-		rows = append(rows, table.Row{
-			company.Name,
-			company.Domain,
-			company.Industry,
-		})
-	}
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(m.height-10),
-	)
-
-	if m.selectedRow < len(rows) {
-		t.SetCursor(m.selectedRow)
-	}
-
-	return t.View()
+	return m.list().render(m.selectedRow, m.pageSize(), "No companies")
 }
 
 func (m Model) renderDealsTable() string {
-	deals, err := m.client.ListDeals(&charm.DealFilter{
-		Limit: 100,
-	})
-	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
-	}
-
-	columns := []table.Column{
-		{Title: "Title", Width: 30},
-		{Title: "Company", Width: 25},
-		{Title: "Stage", Width: 15},
-		{Title: "Amount", Width: 10},
-	}
-
-	var rows []table.Row
-	for _, deal := range deals {
-		// Company name is denormalized in charm model
-		amountStr := fmt.Sprintf("$%dK", deal.Amount/100000)
-
-		rows = append(rows, table.Row{
-			deal.Title,
-			deal.CompanyName,
-			deal.Stage,
-			amountStr,
-		})
-	}
-
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(m.height-10),
-	)
-
-	if m.selectedRow < len(rows) {
-		t.SetCursor(m.selectedRow)
-	}
-
-	return t.View()
+	return m.list().render(m.selectedRow, m.pageSize(), "No deals")
 }
 
 func (m Model) renderListHelp() string {
 	help := []string{
 		"↑/↓: Navigate",
+		"←/→: Page",
+		"o: Sort",
+		"O: Reverse",
+		"1-9: Columns",
+		"g: Group deals",
 		"Tab: Switch tabs",
 		"f: Followups",
 		"s: Sync",
 		"Enter: View details",
 		"/: Search",
 		"n: New",
+		"ctrl+n: Quick capture",
 		"q: Quit",
 	}
 	return helpStyle.Render(strings.Join(help, " • "))
@@ -212,13 +118,37 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSyncKeys(msg)
 	}
 
-	switch msg.String() {
+	key := msg.String()
+	switch key {
 	case "up", "k":
 		if m.selectedRow > 0 {
 			m.selectedRow--
 		}
 	case "down", "j":
-		m.selectedRow++
+		if m.selectedRow < len(m.list().visibleRows())-1 {
+			m.selectedRow++
+		}
+	case "right", "pgdown":
+		m.selectedRow = min(m.selectedRow+m.pageSize(), max(len(m.list().visibleRows())-1, 0))
+	case "left", "pgup":
+		m.selectedRow = max(m.selectedRow-m.pageSize(), 0)
+	case "home":
+		m.selectedRow = 0
+	case "end":
+		m.selectedRow = max(len(m.list().visibleRows())-1, 0)
+	case "o":
+		m.list().cycleSort()
+		m.selectedRow = 0
+	case "O":
+		m.list().reverseSort()
+		m.selectedRow = 0
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m.list().toggleColumn(int(key[0] - '1'))
+	case "g":
+		if m.entityType == EntityDeals {
+			m.list().cycleGrouping()
+			m.selectedRow = 0
+		}
 	case "tab":
 		m.entityType = (m.entityType + 1) % 5
 		m.selectedRow = 0
@@ -231,11 +161,19 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.entityType = EntitySync
 		m.selectedRow = 0
 	case "enter":
+		// Enter on a group header collapses or expands the group
+		if rows := m.list().visibleRows(); m.selectedRow < len(rows) && rows[m.selectedRow].id == "" {
+			m.list().toggleGroup(rows[m.selectedRow].groupKey)
+			return m, nil
+		}
 		// Switch to detail view
 		m.viewMode = ViewDetail
 		m.selectedID = m.getSelectedID()
+		m.checklistCursor = 0
 	case "/":
-		// TODO: Enter search mode
+		if m.entityType <= EntityDeals {
+			return m.openSearch()
+		}
 	case "n":
 		// Switch to edit view (new)
 		m.viewMode = ViewEdit
@@ -247,30 +185,9 @@ func (m Model) handleListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) getSelectedID() string {
-	switch m.entityType {
-	case EntityContacts:
-		contacts, _ := m.client.ListContacts(&charm.ContactFilter{
-			Query: m.searchQuery,
-			Limit: 100,
-		})
-		if m.selectedRow < len(contacts) {
-			return contacts[m.selectedRow].ID.String()
-		}
-	case EntityCompanies:
-		companies, _ := m.client.ListCompanies(&charm.CompanyFilter{
-			Query: m.searchQuery,
-			Limit: 100,
-		})
-		if m.selectedRow < len(companies) {
-			return companies[m.selectedRow].ID.String()
-		}
-	case EntityDeals:
-		deals, _ := m.client.ListDeals(&charm.DealFilter{
-			Limit: 100,
-		})
-		if m.selectedRow < len(deals) {
-			return deals[m.selectedRow].ID.String()
-		}
+	rows := m.list().visibleRows()
+	if m.selectedRow < len(rows) {
+		return rows[m.selectedRow].id
 	}
 	return ""
 }
@@ -0,0 +1,98 @@
+// ABOUTME: Markdown note rendering and checklist toggling for the detail view
+// ABOUTME: Renders notes with glamour and lets the deal view check off task list items
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+var checklistCursorStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("170"))
+
+// renderMarkdown renders note Markdown for a terminal of the given width,
+// falling back to the raw text if glamour fails.
+func renderMarkdown(source string, width int) string {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(max(width-4, 20)),
+	)
+	if err != nil {
+		return source
+	}
+	out, err := renderer.Render(source)
+	if err != nil {
+		return source
+	}
+	return strings.Trim(out, "\n")
+}
+
+// dealChecklistItem is a checklist item in one of a deal's notes.
+type dealChecklistItem struct {
+	noteID uuid.UUID
+	charm.ChecklistItem
+}
+
+// dealChecklist returns the checklist items across the selected deal's notes.
+func (m Model) dealChecklist() []dealChecklistItem {
+	id, err := uuid.Parse(m.selectedID)
+	if err != nil {
+		return nil
+	}
+	notes, _ := m.client.ListDealNotes(id)
+	var items []dealChecklistItem
+	for _, note := range notes {
+		for _, item := range charm.ParseChecklist(note.Content) {
+			items = append(items, dealChecklistItem{noteID: note.ID, ChecklistItem: item})
+		}
+	}
+	return items
+}
+
+// renderDealChecklist lists the deal's checklist items with the cursor.
+func (m Model) renderDealChecklist(items []dealChecklistItem) string {
+	var s strings.Builder
+	s.WriteString("\n")
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render("CHECKLIST"))
+	s.WriteString("\n")
+	for i, item := range items {
+		box := "[ ]"
+		if item.Done {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", box, item.Text)
+		if i == m.checklistCursor {
+			s.WriteString(checklistCursorStyle.Render("> " + line))
+		} else {
+			s.WriteString("  " + line)
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// handleChecklistKeys moves the checklist cursor and toggles the item under it.
+func (m Model) handleChecklistKeys(key string) (tea.Model, tea.Cmd) {
+	items := m.dealChecklist()
+	if len(items) == 0 {
+		return m, nil
+	}
+	switch key {
+	case "up", "k":
+		m.checklistCursor = max(m.checklistCursor-1, 0)
+	case "down", "j":
+		m.checklistCursor = min(m.checklistCursor+1, len(items)-1)
+	case " ", "x":
+		item := items[min(m.checklistCursor, len(items)-1)]
+		_, m.err = m.client.ToggleDealNoteItem(item.noteID, item.Index)
+	}
+	return m, nil
+}
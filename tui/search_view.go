@@ -0,0 +1,58 @@
+// ABOUTME: Search prompt for the TUI list tabs
+// ABOUTME: Opens with / and filters contacts, companies, and deals with the search query language
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openSearch shows the search prompt, starting from the current query.
+func (m Model) openSearch() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "acme tag:vip last_contacted:<90d stage:negotiation"
+	input.CharLimit = 200
+	input.Width = 64
+	input.SetValue(m.searchQuery)
+	input.Focus()
+
+	m.searchActive = true
+	m.searchInput = input
+	return m, textinput.Blink
+}
+
+// handleSearchKeys edits the query; enter applies it to every list tab and
+// esc leaves the current one in place. An empty query shows everything.
+func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.searchActive = false
+		return m, nil
+	case "enter":
+		m.searchActive = false
+		m.searchQuery = strings.TrimSpace(m.searchInput.Value())
+		m.selectedRow = 0
+		m.invalidateLists()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+// renderSearchLine is the prompt while searching, or the active query.
+func (m Model) renderSearchLine() string {
+	if m.searchActive {
+		return "Search: " + m.searchInput.View() + "\n" +
+			helpStyle.Render("company:, tag:, last_contacted:<90d, stage:, amount:>10k • Enter: Apply • Esc: Cancel") + "\n\n"
+	}
+	if m.searchQuery != "" {
+		return helpStyle.Render("Search: "+m.searchQuery+" (/ to change, empty to clear)") + "\n\n"
+	}
+	return ""
+}
@@ -0,0 +1,572 @@
+// ABOUTME: Paginated, sortable list tables for the TUI tabs
+// ABOUTME: Loads each tab's rows once, sorts them in memory, and renders only the current page
+
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/google/uuid"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// sortKey is what a column sorts by. Columns without one can't be sorted.
+type sortKey int
+
+const (
+	sortNone sortKey = iota
+	sortName
+	sortLastContacted
+	sortPriority
+	sortDealValue
+)
+
+// listColumn is one column of a list table.
+type listColumn struct {
+	title string
+	width int
+	sort  sortKey
+}
+
+// listRow is one row of a list table: the cells shown plus the values its
+// sortable columns sort by. Group headers have no id and carry their group's
+// key instead.
+type listRow struct {
+	id            string
+	cells         []string
+	name          string
+	lastContacted time.Time
+	priority      float64
+	dealValue     int64
+
+	deal     *charm.Deal // deals tab only, for grouping
+	groupKey string      // group headers only
+}
+
+// listTable is one tab's table. The rows are loaded on first view and kept
+// until invalidated, so paging and sorting tens of thousands of rows never
+// goes back to the store; only the current page is rendered. The sort order
+// and hidden columns survive reloads, and so do the deals tab's grouping and
+// collapsed groups.
+type listTable struct {
+	columns []listColumn
+	hidden  map[int]bool
+	sortCol int
+	desc    bool
+
+	groupBy   string          // one of charm.DealGroupings, or "" for a flat list
+	collapsed map[string]bool // collapsed group keys, prefixed by grouping
+
+	rows   []listRow
+	loaded bool
+	err    error
+
+	goals []*charm.GoalProgress // followups tab only, this week's outreach goals
+}
+
+func newListTable(columns []listColumn, sortCol int, desc bool) *listTable {
+	return &listTable{columns: columns, hidden: make(map[int]bool), collapsed: make(map[string]bool), sortCol: sortCol, desc: desc}
+}
+
+// newListTables sets up the tables behind the list tabs, each sorted the way
+// its old fixed list was.
+func newListTables() map[EntityType]*listTable {
+	return map[EntityType]*listTable{
+		EntityContacts: newListTable([]listColumn{
+			{title: "Name", width: 25, sort: sortName},
+			{title: "Email", width: 28},
+			{title: "Company", width: 20},
+			{title: "Last Contacted", width: 14, sort: sortLastContacted},
+			{title: "Priority", width: 8, sort: sortPriority},
+			{title: "Deal Value", width: 10, sort: sortDealValue},
+		}, 0, false),
+		EntityCompanies: newListTable([]listColumn{
+			{title: "Name", width: 25, sort: sortName},
+			{title: "Domain", width: 24},
+			{title: "Industry", width: 18},
+			{title: "Last Contacted", width: 14, sort: sortLastContacted},
+			{title: "Deal Value", width: 10, sort: sortDealValue},
+		}, 0, false),
+		EntityDeals: newListTable([]listColumn{
+			{title: "Title", width: 28, sort: sortName},
+			{title: "Company", width: 22},
+			{title: "Stage", width: 14},
+			{title: "Amount", width: 10, sort: sortDealValue},
+			{title: "Last Activity", width: 14, sort: sortLastContacted},
+		}, 4, true),
+		EntityFollowups: newListTable([]listColumn{
+			{title: "Status", width: 6},
+			{title: "Name", width: 25, sort: sortName},
+			{title: "Days", width: 6},
+			{title: "Priority", width: 8, sort: sortPriority},
+			{title: "Strength", width: 10},
+			{title: "Email", width: 28},
+			{title: "Last Contacted", width: 14, sort: sortLastContacted},
+		}, 3, true),
+	}
+}
+
+// list returns the current tab's table, loading its rows if needed. The
+// tables are shared between model copies, so loading from View sticks.
+func (m Model) list() *listTable {
+	t := m.lists[m.entityType]
+	if t == nil {
+		return &listTable{}
+	}
+	if !t.loaded {
+		t.rows, t.err = m.loadRows(m.entityType)
+		if m.entityType == EntityFollowups && t.err == nil {
+			t.goals, t.err = m.loadGoals()
+		}
+		t.loaded = true
+		t.sortRows()
+	}
+	return t
+}
+
+// invalidateLists drops the loaded rows so every tab reloads on next view.
+// Call it after anything that changes contacts, companies, or deals.
+func (m Model) invalidateLists() {
+	for _, t := range m.lists {
+		t.rows = nil
+		t.loaded = false
+	}
+}
+
+// pageSize is how many rows fit on screen below the tabs and above the help.
+func (m Model) pageSize() int {
+	size := m.height - 12
+	if t := m.lists[m.entityType]; t != nil && len(t.goals) > 0 {
+		size -= len(t.goals) + 1 // goal bars above the followups table
+	}
+	if size < 5 {
+		size = 5
+	}
+	return size
+}
+
+// cycleSort sorts by the next sortable column, ascending by name and
+// descending by everything else.
+func (t *listTable) cycleSort() {
+	for i := 1; i <= len(t.columns); i++ {
+		col := (t.sortCol + i) % len(t.columns)
+		if t.columns[col].sort != sortNone && !t.hidden[col] {
+			t.sortCol = col
+			t.desc = t.columns[col].sort != sortName
+			t.sortRows()
+			return
+		}
+	}
+}
+
+// reverseSort flips the sort direction.
+func (t *listTable) reverseSort() {
+	t.desc = !t.desc
+	t.sortRows()
+}
+
+// toggleColumn hides or shows column col, always leaving one shown.
+func (t *listTable) toggleColumn(col int) {
+	if col < 0 || col >= len(t.columns) {
+		return
+	}
+	if !t.hidden[col] && len(t.hidden) == len(t.columns)-1 {
+		return
+	}
+	if t.hidden[col] {
+		delete(t.hidden, col)
+	} else {
+		t.hidden[col] = true
+	}
+}
+
+// cycleGrouping moves to the next deal grouping, then back to a flat list.
+func (t *listTable) cycleGrouping() {
+	next := ""
+	for i, g := range charm.DealGroupings {
+		if g == t.groupBy {
+			if i+1 < len(charm.DealGroupings) {
+				next = charm.DealGroupings[i+1]
+			}
+			t.groupBy = next
+			return
+		}
+	}
+	t.groupBy = charm.DealGroupings[0]
+}
+
+// toggleGroup collapses or expands the group with key.
+func (t *listTable) toggleGroup(key string) {
+	key = t.groupBy + ":" + key
+	if t.collapsed[key] {
+		delete(t.collapsed, key)
+	} else {
+		t.collapsed[key] = true
+	}
+}
+
+// visibleRows returns the rows as shown: the sorted rows, or when grouped a
+// header per group followed by its rows unless the group is collapsed.
+func (t *listTable) visibleRows() []listRow {
+	if t.groupBy == "" {
+		return t.rows
+	}
+
+	byID := make(map[string]listRow, len(t.rows))
+	deals := make([]*charm.Deal, 0, len(t.rows))
+	for _, row := range t.rows {
+		if row.deal != nil {
+			byID[row.id] = row
+			deals = append(deals, row.deal)
+		}
+	}
+	groups, err := charm.GroupDeals(deals, t.groupBy)
+	if err != nil {
+		return t.rows
+	}
+
+	rows := make([]listRow, 0, len(t.rows)+len(groups))
+	for _, group := range groups {
+		collapsed := t.collapsed[t.groupBy+":"+group.Key]
+		marker := "▾ "
+		if collapsed {
+			marker = "▸ "
+		}
+		header := listRow{groupKey: group.Key, cells: make([]string, len(t.columns))}
+		header.cells[0] = fmt.Sprintf("%s%s (%d)", marker, group.Label, len(group.Deals))
+		for i, col := range t.columns {
+			if col.sort == sortDealValue {
+				header.cells[i] = charm.FormatMoneyCompact(group.Total, group.Currency)
+			}
+		}
+		rows = append(rows, header)
+		if collapsed {
+			continue
+		}
+		for _, deal := range group.Deals {
+			rows = append(rows, byID[deal.ID.String()])
+		}
+	}
+	return rows
+}
+
+// sortRows orders the rows by the sort column, breaking ties by name.
+func (t *listTable) sortRows() {
+	if t.sortCol >= len(t.columns) {
+		return
+	}
+	key := t.columns[t.sortCol].sort
+	compare := func(a, b *listRow) int {
+		switch key {
+		case sortLastContacted:
+			return a.lastContacted.Compare(b.lastContacted)
+		case sortPriority:
+			return compareNumbers(a.priority, b.priority)
+		case sortDealValue:
+			return compareNumbers(a.dealValue, b.dealValue)
+		}
+		return 0
+	}
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := &t.rows[i], &t.rows[j]
+		if c := compare(a, b); c != 0 {
+			if t.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		c := strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+		if key == sortName && t.desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func compareNumbers[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// render draws the page holding cursor, with a footer saying where it is.
+func (t *listTable) render(cursor, pageSize int, empty string) string {
+	if t.err != nil {
+		return fmt.Sprintf("Error: %v", t.err)
+	}
+	if len(t.rows) == 0 {
+		return helpStyle.Render(empty)
+	}
+	visible := t.visibleRows()
+	cursor = min(max(cursor, 0), len(visible)-1)
+
+	var columns []table.Column
+	var shown []int
+	for i, col := range t.columns {
+		if t.hidden[i] {
+			continue
+		}
+		title := col.title
+		if i == t.sortCol {
+			title += sortArrow(t.desc)
+		}
+		columns = append(columns, table.Column{Title: title, Width: col.width})
+		shown = append(shown, i)
+	}
+
+	start := cursor / pageSize * pageSize
+	end := min(start+pageSize, len(visible))
+	rows := make([]table.Row, 0, end-start)
+	for _, row := range visible[start:end] {
+		cells := make(table.Row, len(shown))
+		for j, col := range shown {
+			cells[j] = row.cells[col]
+		}
+		// Group headers keep their label when the first column is hidden
+		if row.id == "" && len(shown) > 0 {
+			cells[0] = row.cells[0]
+		}
+		rows = append(rows, cells)
+	}
+
+	tbl := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(pageSize+1),
+	)
+	tbl.SetCursor(cursor - start)
+
+	pages := (len(visible) + pageSize - 1) / pageSize
+	footer := fmt.Sprintf("Rows %d-%d of %d • Page %d/%d • Sorted by %s%s",
+		start+1, end, len(visible), start/pageSize+1, pages, t.columns[t.sortCol].title, sortArrow(t.desc))
+	if t.groupBy != "" {
+		footer += " • Grouped by " + t.groupBy
+	}
+	return tbl.View() + "\n" + helpStyle.Render(footer)
+}
+
+func sortArrow(desc bool) string {
+	if desc {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// loadRows reads every row for a tab.
+func (m Model) loadRows(entityType EntityType) ([]listRow, error) {
+	switch entityType {
+	case EntityContacts:
+		return m.loadContactRows()
+	case EntityCompanies:
+		return m.loadCompanyRows()
+	case EntityDeals:
+		return m.loadDealRows()
+	case EntityFollowups:
+		return m.loadFollowupRows()
+	}
+	return nil, nil
+}
+
+func (m Model) loadContactRows() ([]listRow, error) {
+	contacts, err := m.client.ListContacts(&charm.ContactFilter{Query: m.searchQuery})
+	if err != nil {
+		return nil, err
+	}
+	cadences, err := m.client.ListContactCadences()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	priorities := make(map[uuid.UUID]float64, len(cadences))
+	for _, cadence := range cadences {
+		priorities[cadence.ContactID] = charm.ComputePriorityScore(cadence, now)
+	}
+	values, err := m.openDealValues(func(deal *charm.Deal) *uuid.UUID { return deal.ContactID })
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]listRow, 0, len(contacts))
+	for _, contact := range contacts {
+		row := listRow{
+			id:        contact.ID.String(),
+			name:      contact.Name,
+			priority:  priorities[contact.ID],
+			dealValue: values[contact.ID].cents,
+		}
+		if contact.LastContactedAt != nil {
+			row.lastContacted = *contact.LastContactedAt
+		}
+		row.cells = []string{
+			contact.Name,
+			contact.Email,
+			// Company name is denormalized in charm model
+			contact.CompanyName,
+			formatListDate(row.lastContacted),
+			formatPriority(row.priority),
+			values[contact.ID].String(),
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (m Model) loadCompanyRows() ([]listRow, error) {
+	companies, err := m.client.ListCompanies(&charm.CompanyFilter{Query: m.searchQuery})
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := m.client.ListContacts(nil)
+	if err != nil {
+		return nil, err
+	}
+	lastContacted := make(map[uuid.UUID]time.Time)
+	for _, contact := range contacts {
+		if contact.CompanyID != nil && contact.LastContactedAt != nil && contact.LastContactedAt.After(lastContacted[*contact.CompanyID]) {
+			lastContacted[*contact.CompanyID] = *contact.LastContactedAt
+		}
+	}
+	values, err := m.openDealValues(func(deal *charm.Deal) *uuid.UUID { return &deal.CompanyID })
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]listRow, 0, len(companies))
+	for _, company := range companies {
+		row := listRow{
+			id:            company.ID.String(),
+			name:          company.Name,
+			lastContacted: lastContacted[company.ID],
+			dealValue:     values[company.ID].cents,
+		}
+		row.cells = []string{
+			company.Name,
+			company.Domain,
+			company.Industry,
+			formatListDate(row.lastContacted),
+			values[company.ID].String(),
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (m Model) loadDealRows() ([]listRow, error) {
+	deals, err := m.client.ListDeals(&charm.DealFilter{Query: m.searchQuery})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]listRow, 0, len(deals))
+	for _, deal := range deals {
+		rows = append(rows, listRow{
+			id:            deal.ID.String(),
+			name:          deal.Title,
+			lastContacted: deal.LastActivityAt,
+			dealValue:     deal.Amount,
+			deal:          deal,
+			cells: []string{
+				deal.Title,
+				// Company name is denormalized in charm model
+				deal.CompanyName,
+				deal.Stage,
+				charm.FormatMoneyCompact(deal.Amount, deal.Currency),
+				formatListDate(deal.LastActivityAt),
+			},
+		})
+	}
+	return rows, nil
+}
+
+func (m Model) loadFollowupRows() ([]listRow, error) {
+	followups, err := m.client.GetFollowupList(0)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]listRow, 0, len(followups))
+	for _, f := range followups {
+		indicator := "🟢"
+		if f.DaysSinceContact > f.CadenceDays+7 {
+			indicator = "🔴"
+		} else if f.DaysSinceContact >= f.CadenceDays-3 {
+			indicator = "🟡"
+		}
+
+		row := listRow{id: f.ID.String(), name: f.Name, priority: f.PriorityScore}
+		if f.LastContactedAt != nil {
+			row.lastContacted = *f.LastContactedAt
+		}
+		row.cells = []string{
+			indicator,
+			f.Name,
+			fmt.Sprintf("%d", f.DaysSinceContact),
+			formatPriority(f.PriorityScore),
+			f.RelationshipStrength,
+			f.Email,
+			formatListDate(row.lastContacted),
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// dealValue totals open deal amounts. Like the digest, amounts in different
+// currencies are added as-is and shown in the first deal's currency.
+type dealValue struct {
+	cents    int64
+	currency string
+}
+
+func (v dealValue) String() string {
+	if v.cents == 0 {
+		return ""
+	}
+	return charm.FormatMoneyCompact(v.cents, v.currency)
+}
+
+// openDealValues totals the open deals for whatever owner returns.
+func (m Model) openDealValues(owner func(*charm.Deal) *uuid.UUID) (map[uuid.UUID]dealValue, error) {
+	deals, err := m.client.ListDeals(nil)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[uuid.UUID]dealValue)
+	for _, deal := range deals {
+		id := owner(deal)
+		if id == nil || deal.Stage == charm.StageClosedWon || deal.Stage == charm.StageClosedLost {
+			continue
+		}
+		v := values[*id]
+		if v.currency == "" {
+			v.currency = deal.Currency
+		}
+		v.cents += deal.Amount
+		values[*id] = v
+	}
+	return values, nil
+}
+
+func formatListDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatPriority(score float64) string {
+	if score <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", score)
+}
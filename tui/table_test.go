@@ -0,0 +1,149 @@
+// ABOUTME: Tests for the paginated, sortable TUI list tables
+// ABOUTME: Verifies paging, sort cycling, column toggling, and selection by sorted row
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestListTablePaging(t *testing.T) {
+	client := charm.NewTestClient(t)
+	for i := 0; i < 25; i++ {
+		require.NoError(t, client.CreateContact(&charm.Contact{Name: fmt.Sprintf("Contact %02d", i)}))
+	}
+
+	m := NewModel(client)
+	m.height = 22 // 10 rows per page
+
+	output := m.renderContactsTable()
+	assert.Contains(t, output, "Contact 00")
+	assert.Contains(t, output, "Contact 09")
+	assert.NotContains(t, output, "Contact 10")
+	assert.Contains(t, output, "Rows 1-10 of 25 • Page 1/3")
+
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = updated.(Model)
+	assert.Equal(t, 10, m.selectedRow)
+	output = m.renderContactsTable()
+	assert.Contains(t, output, "Contact 10")
+	assert.NotContains(t, output, "Contact 09")
+
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnd})
+	m = updated.(Model)
+	assert.Equal(t, 24, m.selectedRow)
+	assert.Contains(t, m.renderContactsTable(), "Rows 21-25 of 25 • Page 3/3")
+
+	// Down stops at the last row
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	assert.Equal(t, 24, m.selectedRow)
+}
+
+func TestListTableSortAndColumns(t *testing.T) {
+	client := charm.NewTestClient(t)
+	old := time.Now().AddDate(0, -2, 0)
+	recent := time.Now().AddDate(0, 0, -1)
+	require.NoError(t, client.CreateContact(&charm.Contact{Name: "Alice", LastContactedAt: &old}))
+	bob := &charm.Contact{Name: "Bob", LastContactedAt: &recent}
+	require.NoError(t, client.CreateContact(bob))
+	require.NoError(t, client.CreateContact(&charm.Contact{Name: "Carol"}))
+
+	m := NewModel(client)
+	assert.Equal(t, "Alice", m.list().rows[0].name)
+
+	// o moves from name to last contacted, newest first
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m = updated.(Model)
+	assert.Contains(t, m.renderContactsTable(), "Sorted by Last Contacted ▼")
+	assert.Equal(t, bob.ID.String(), m.getSelectedID())
+
+	// O reverses it, never-contacted first
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})
+	m = updated.(Model)
+	assert.Equal(t, "Carol", m.list().rows[0].name)
+
+	// 2 hides the email column, and pressing it again shows it
+	require.NoError(t, client.UpdateContact(&charm.Contact{ID: bob.ID, Name: "Bob", Email: "bob@example.com", LastContactedAt: &recent}))
+	m.invalidateLists()
+	assert.Contains(t, m.renderContactsTable(), "bob@example.com")
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(Model)
+	output := m.renderContactsTable()
+	assert.NotContains(t, output, "bob@example.com")
+	assert.False(t, strings.Contains(output, "Email"))
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(Model)
+	assert.Contains(t, m.renderContactsTable(), "bob@example.com")
+}
+
+func TestListTableDealValue(t *testing.T) {
+	client := charm.NewTestClient(t)
+	company := &charm.Company{Name: "Acme"}
+	require.NoError(t, client.CreateCompany(company))
+	small := &charm.Company{Name: "Small Co"}
+	require.NoError(t, client.CreateCompany(small))
+	require.NoError(t, client.CreateDeal(&charm.Deal{Title: "Big", Stage: charm.StageNegotiation, Amount: 5_000_000, Currency: "USD", CompanyID: company.ID}))
+	require.NoError(t, client.CreateDeal(&charm.Deal{Title: "Won", Stage: charm.StageClosedWon, Amount: 90_000_000, Currency: "USD", CompanyID: small.ID}))
+
+	m := NewModel(client)
+	m.entityType = EntityCompanies
+	table := m.list()
+	table.sortCol = 4 // Deal Value
+	table.desc = true
+	table.sortRows()
+
+	// Closed deals don't count toward deal value
+	assert.Equal(t, "Acme", table.rows[0].name)
+	assert.Equal(t, int64(5_000_000), table.rows[0].dealValue)
+	assert.Equal(t, int64(0), table.rows[1].dealValue)
+}
+
+func TestListTableGroupDeals(t *testing.T) {
+	client := charm.NewTestClient(t)
+	require.NoError(t, client.CreateDeal(&charm.Deal{Title: "Pilot", CompanyName: "Acme", Stage: charm.StageProposal, Amount: 100_000, Currency: "USD"}))
+	require.NoError(t, client.CreateDeal(&charm.Deal{Title: "Renewal", CompanyName: "Acme", Stage: charm.StageNegotiation, Amount: 50_000, Currency: "USD"}))
+	require.NoError(t, client.CreateDeal(&charm.Deal{Title: "Expansion", CompanyName: "Globex", Stage: charm.StageProspecting}))
+
+	m := NewModel(client)
+	m.entityType = EntityDeals
+
+	// g groups by company, with a header per company
+	updated, _ := m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = updated.(Model)
+	rows := m.list().visibleRows()
+	require.Len(t, rows, 5)
+	assert.Equal(t, "▾ Acme (2)", rows[0].cells[0])
+	assert.Equal(t, "▾ Globex (1)", rows[3].cells[0])
+	assert.Contains(t, m.renderDealsTable(), "Grouped by company")
+
+	// Enter on a header collapses the group instead of opening a deal
+	updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.Equal(t, ViewList, m.viewMode)
+	rows = m.list().visibleRows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, "▸ Acme (2)", rows[0].cells[0])
+
+	// Deals are still selectable under the expanded group
+	m.selectedRow = 2
+	assert.Equal(t, "Expansion", rows[2].name)
+	assert.NotEmpty(t, m.getSelectedID())
+
+	// Cycling past the last grouping goes back to a flat list
+	for range charm.DealGroupings {
+		updated, _ = m.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+		m = updated.(Model)
+	}
+	assert.Len(t, m.list().visibleRows(), 3)
+	assert.NotContains(t, m.renderDealsTable(), "Grouped by")
+}
@@ -38,11 +38,13 @@ type Model struct {
 	entityType EntityType
 
 	// List view state
-	selectedRow int    //nolint:unused // will be used in Task 4.2
-	searchQuery string //nolint:unused // will be used in Task 4.2
+	selectedRow int    // row index into the current tab's sorted rows
+	searchQuery string // query language filter for the contact, company, and deal tabs
+	lists       map[EntityType]*listTable
 
 	// Detail view state
-	selectedID string //nolint:unused // will be used in Task 4.3
+	selectedID      string //nolint:unused // will be used in Task 4.3
+	checklistCursor int    // selected checklist item in the deal view
 
 	// Edit view state
 	formInputs []textinput.Model //nolint:unused // will be used in Task 4.4
@@ -55,6 +57,16 @@ type Model struct {
 	deleteConfirmed bool
 	deleteMessage   string
 
+	// Search prompt state
+	searchActive bool
+	searchInput  textinput.Model
+
+	// Quick-capture overlay state
+	captureActive  bool
+	captureInput   textinput.Model
+	captureError   string
+	captureMessage string
+
 	// Sync view state
 	syncInProgress  map[string]bool //nolint:unused // used in sync view
 	syncMessages    []string        //nolint:unused // used in sync view
@@ -72,6 +84,7 @@ func NewModel(client *charm.Client) Model {
 		client:         client,
 		viewMode:       ViewList,
 		entityType:     EntityContacts,
+		lists:          newListTables(),
 		width:          80,
 		height:         24,
 		syncInProgress: make(map[string]bool),
@@ -92,6 +105,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 	case SyncCompleteMsg:
+		m.invalidateLists()
 		return m, m.handleSyncComplete(msg)
 	case AutoSyncToggleMsg:
 		return m, m.handleAutoSyncToggle(msg)
@@ -100,25 +114,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	var view string
 	switch m.viewMode {
 	case ViewList:
-		return m.renderListView()
+		view = m.renderListView()
 	case ViewDetail:
-		return m.renderDetailView()
+		view = m.renderDetailView()
 	case ViewEdit:
-		return m.renderEditView()
+		view = m.renderEditView()
 	case ViewGraph:
-		return m.renderGraphView()
+		view = m.renderGraphView()
 	case ViewConfirmDelete:
-		return m.renderConfirmDeleteView()
+		view = m.renderConfirmDeleteView()
 	}
-	return ""
+	if m.captureActive {
+		return m.renderCaptureOverlay(view)
+	}
+	return view
 }
 
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The quick-capture overlay takes all keys while open
+	if m.captureActive {
+		return m.handleCaptureKeys(msg)
+	}
+	// So does the search prompt
+	if m.searchActive {
+		return m.handleSearchKeys(msg)
+	}
+
 	// Check for global quit keys first, before view-specific handlers
 	key := msg.String()
 
+	if key == captureKey {
+		return m.openCapture()
+	}
+
 	// Handle quit keys (q, ctrl+c always quit; esc quits except in edit mode)
 	if key == "q" || key == "ctrl+c" {
 		return m, tea.Quit
@@ -130,6 +161,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// In edit mode, esc cancels the edit
 	if key == "esc" && m.viewMode == ViewEdit {
 		m.viewMode = ViewList
+		m.err = nil
 		return m, nil
 	}
 
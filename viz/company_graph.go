@@ -1,9 +1,9 @@
 package viz
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-graphviz"
 	"github.com/goccy/go-graphviz/cgraph"
@@ -61,6 +61,7 @@ func (g *GraphGenerator) GenerateCompanyGraph(companyID uuid.UUID) (string, erro
 	}
 
 	// Add relationships between contacts
+	days, now := charm.RelationshipRevalidateDays(g.client.Config()), time.Now()
 	for _, contact := range contacts {
 		relationships, _ := g.client.ListRelationshipsForContact(contact.ID)
 		for _, rel := range relationships {
@@ -75,17 +76,10 @@ func (g *GraphGenerator) GenerateCompanyGraph(companyID uuid.UUID) (string, erro
 					continue
 				}
 				edge.SetStyle(cgraph.DashedEdgeStyle)
-				if rel.RelationshipType != "" {
-					edge.SetLabel(rel.RelationshipType)
-				}
+				styleRelationshipEdge(edge, rel, days, now)
 			}
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
-		return "", fmt.Errorf("failed to render graph: %w", err)
-	}
-
-	return buf.String(), nil
+	return g.render(ctx, gv, graph)
 }
@@ -3,9 +3,9 @@
 package viz
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-graphviz"
 	"github.com/goccy/go-graphviz/cgraph"
@@ -99,8 +99,7 @@ func (g *GraphGenerator) GenerateCompleteGraph() (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("failed to create deal node: %w", err)
 		}
-		amountK := deal.Amount / 100000
-		node.SetLabel(fmt.Sprintf("%s\n$%dK\n(%s)", deal.Title, amountK, deal.Stage))
+		node.SetLabel(fmt.Sprintf("%s\n%s\n(%s)", deal.Title, charm.FormatMoneyCompact(deal.Amount, deal.Currency), deal.Stage))
 		node.SetShape("diamond")
 		node.SetStyle("filled")
 		node.SetFillColor("lightyellow")
@@ -133,6 +132,7 @@ func (g *GraphGenerator) GenerateCompleteGraph() (string, error) {
 		return "", fmt.Errorf("failed to fetch relationships: %w", err)
 	}
 
+	days, now := charm.RelationshipRevalidateDays(g.client.Config()), time.Now()
 	for _, rel := range relationships {
 		node1, ok1 := contactNodes[rel.ContactID1.String()]
 		node2, ok2 := contactNodes[rel.ContactID2.String()]
@@ -141,15 +141,10 @@ func (g *GraphGenerator) GenerateCompleteGraph() (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("failed to create relationship edge: %w", err)
 			}
-			edge.SetLabel(rel.RelationshipType)
+			styleRelationshipEdge(edge, rel, days, now)
 			edge.SetDir("none") // Undirected edge for relationships
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
-		return "", fmt.Errorf("failed to render graph: %w", err)
-	}
-
-	return buf.String(), nil
+	return g.render(ctx, gv, graph)
 }
@@ -1,9 +1,9 @@
 package viz
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/goccy/go-graphviz"
 	"github.com/goccy/go-graphviz/cgraph"
@@ -45,6 +45,7 @@ func (g *GraphGenerator) GenerateContactGraph(contactID *uuid.UUID) (string, err
 	// Create nodes for all unique contacts
 	// Use denormalized names from relationships where possible
 	nodes := make(map[string]*cgraph.Node)
+	days, now := charm.RelationshipRevalidateDays(g.client.Config()), time.Now()
 	for _, rel := range relationships {
 		id1 := rel.ContactID1.String()
 		id2 := rel.ContactID2.String()
@@ -67,16 +68,31 @@ func (g *GraphGenerator) GenerateContactGraph(contactID *uuid.UUID) (string, err
 
 		// Create edge
 		edge, _ := graph.CreateEdgeByName("", nodes[id1], nodes[id2])
-		if rel.RelationshipType != "" {
-			edge.SetLabel(rel.RelationshipType)
-		}
+		styleRelationshipEdge(edge, rel, days, now)
 	}
 
 	// Generate DOT source
-	var buf bytes.Buffer
-	if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
-		return "", fmt.Errorf("failed to render graph: %w", err)
+	return g.render(ctx, gv, graph)
+}
+
+// styleRelationshipEdge draws a relationship heavier the stronger it is now
+// and dotted once it has gone unconfirmed past the revalidation period,
+// labelled with its type and current strength.
+func styleRelationshipEdge(edge *cgraph.Edge, rel *charm.Relationship, days int, now time.Time) {
+	strength := rel.CurrentStrength(days, now)
+	edge.SetPenWidth(charm.RelationshipEdgeWeight(strength))
+	if rel.IsStale(days, now) {
+		edge.SetStyle(cgraph.DottedEdgeStyle)
 	}
 
-	return buf.String(), nil
+	label := rel.RelationshipType
+	switch {
+	case label == "":
+		label = strength
+	case strength != "":
+		label += " (" + strength + ")"
+	}
+	if label != "" {
+		edge.SetLabel(label)
+	}
 }
@@ -14,6 +14,9 @@ type DashboardStats struct {
 	// Pipeline overview
 	PipelineByStage map[string]PipelineStageStats
 
+	// Forecast is the expected value of open deals (amount × win probability), in cents
+	Forecast int64
+
 	// Overall stats
 	TotalContacts  int
 	TotalCompanies int
@@ -22,15 +25,19 @@ type DashboardStats struct {
 	// Recent activity (last 7 days)
 	RecentActivity []ActivityItem
 
+	// Weekly outreach goals from the config, by tag
+	Goals []*charm.GoalProgress
+
 	// Needs attention
 	StaleContacts []StaleContact
 	StaleDeals    []StaleDeal
 }
 
 type PipelineStageStats struct {
-	Stage  string
-	Count  int
-	Amount int64 // in cents
+	Stage         string
+	Count         int
+	Amount        int64 // in cents
+	ExpectedValue int64 // in cents
 }
 
 type ActivityItem struct {
@@ -69,7 +76,12 @@ func GenerateDashboardStats(client *charm.Client) (*DashboardStats, error) {
 		pstats.Stage = stage
 		pstats.Count++
 		pstats.Amount += deal.Amount
+		pstats.ExpectedValue += deal.ExpectedValue()
 		stats.PipelineByStage[stage] = pstats
+
+		if stage != charm.StageClosedWon && stage != charm.StageClosedLost {
+			stats.Forecast += deal.ExpectedValue()
+		}
 	}
 
 	stats.TotalDeals = len(deals)
@@ -88,8 +100,15 @@ func GenerateDashboardStats(client *charm.Client) (*DashboardStats, error) {
 	}
 	stats.TotalCompanies = len(companies)
 
-	// Find stale contacts (no contact in 30+ days)
+	// Progress on weekly outreach goals
 	now := time.Now()
+	if cfg := client.Config(); cfg != nil {
+		if stats.Goals, err = client.GoalProgress(cfg.OutreachGoals, now); err != nil {
+			return nil, fmt.Errorf("failed to measure outreach goals: %w", err)
+		}
+	}
+
+	// Find stale contacts (no contact in 30+ days)
 	for _, contact := range contacts {
 		if contact.LastContactedAt == nil {
 			stats.StaleContacts = append(stats.StaleContacts, StaleContact{
@@ -132,6 +151,7 @@ func RenderDashboard(stats *DashboardStats) string {
 	// Pipeline overview
 	out.WriteString("PIPELINE OVERVIEW\n")
 	renderPipeline(&out, stats.PipelineByStage)
+	out.WriteString(fmt.Sprintf("  Forecast (weighted open pipeline): %s\n", charm.FormatMoneyCompact(stats.Forecast, "")))
 	out.WriteString("\n")
 
 	// Stats
@@ -139,6 +159,15 @@ func RenderDashboard(stats *DashboardStats) string {
 	out.WriteString(fmt.Sprintf("  📇 %d contacts  🏢 %d companies  💼 %d deals\n\n",
 		stats.TotalContacts, stats.TotalCompanies, stats.TotalDeals))
 
+	// Weekly goals
+	if len(stats.Goals) > 0 {
+		out.WriteString("WEEKLY GOALS\n")
+		for _, goal := range stats.Goals {
+			out.WriteString(fmt.Sprintf("  %-13s %s  %2d/%d\n", goal.Tag, goal.Bar(10), goal.Touched, goal.Target))
+		}
+		out.WriteString("\n")
+	}
+
 	// Needs attention
 	if len(stats.StaleContacts) > 0 || len(stats.StaleDeals) > 0 {
 		out.WriteString("NEEDS ATTENTION\n")
@@ -190,10 +219,7 @@ func renderPipeline(out *strings.Builder, pipeline map[string]PipelineStageStats
 		// Build bar
 		bar := strings.Repeat("█", barLength) + strings.Repeat("░", 10-barLength)
 
-		// Format amount in K
-		amountK := pstats.Amount / 100000
-
-		fmt.Fprintf(out, "  %-13s %s  %2d ($%dK)\n",
-			stage, bar, pstats.Count, amountK)
+		fmt.Fprintf(out, "  %-13s %s  %2d (%s)\n",
+			stage, bar, pstats.Count, charm.FormatMoneyCompact(pstats.Amount, ""))
 	}
 }
@@ -0,0 +1,163 @@
+package viz
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-graphviz"
+	"github.com/goccy/go-graphviz/cgraph"
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// influenceColors fills a person's node by their most important role.
+var influenceColors = map[string]string{
+	charm.DealRoleDecisionMaker: "lightsalmon",
+	charm.DealRoleChampion:      "palegreen",
+	charm.DealRoleInfluencer:    "lightgoldenrod1",
+	charm.DealRoleProcurement:   "lightgray",
+	charm.InfluenceRolePrimary:  "lightblue",
+	charm.InfluenceRoleReferrer: "plum",
+}
+
+// influenceRoleOrder ranks roles when a person has several.
+var influenceRoleOrder = append(append([]string{}, charm.DealRoles...), charm.InfluenceRolePrimary, charm.InfluenceRoleReferrer)
+
+// GenerateDealGraph renders a deal's influencer map: the deal, me, the people
+// involved with their roles, how well I know each of them, and how they're
+// related to each other. With withCompany, people at the deal's company who
+// aren't on it yet are drawn dashed, as people to bring in.
+func (g *GraphGenerator) GenerateDealGraph(dealID uuid.UUID, withCompany bool) (string, error) {
+	m, err := g.client.DealInfluenceMap(dealID, withCompany)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	gv, err := graphviz.New(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create graphviz instance: %w", err)
+	}
+	defer func() { _ = gv.Close() }()
+
+	graph, err := gv.Graph()
+	if err != nil {
+		return "", fmt.Errorf("failed to create graph: %w", err)
+	}
+	defer func() { _ = graph.Close() }()
+
+	graph.SetLayout("dot")
+	graph.SetRankDir(cgraph.LRRank)
+
+	meNode, err := graph.CreateNodeByName("me")
+	if err != nil {
+		return "", fmt.Errorf("failed to create node: %w", err)
+	}
+	meNode.SetLabel("Me")
+	meNode.SetShape(cgraph.DoubleCircleShape)
+
+	deal := m.Deal
+	dealNode, err := graph.CreateNodeByName(deal.ID.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to create node: %w", err)
+	}
+	dealNode.SetLabel(fmt.Sprintf("%s\n%s, %s", deal.Title, deal.Stage, charm.FormatMoney(deal.Amount, deal.Currency)))
+	dealNode.SetShape(cgraph.BoxShape)
+	dealNode.SetStyle(cgraph.FilledNodeStyle)
+	dealNode.SetFillColor("lightblue")
+
+	nodes := make(map[uuid.UUID]*cgraph.Node)
+	for _, person := range m.People {
+		node, err := graph.CreateNodeByName(person.Contact.ID.String())
+		if err != nil {
+			continue
+		}
+		nodes[person.Contact.ID] = node
+
+		label := person.Contact.Name
+		if person.Contact.Title != "" {
+			label += "\n" + person.Contact.Title
+		}
+		node.SetLabel(label)
+		node.SetShape(cgraph.BoxShape)
+
+		if person.Involved() {
+			node.SetStyle(cgraph.NodeStyle("filled,rounded"))
+			node.SetFillColor(influenceColors[topInfluenceRole(person.Roles)])
+			if edge, err := graph.CreateEdgeByName("", dealNode, node); err == nil {
+				edge.SetLabel(roleLabels(person.Roles))
+				edge.SetColor("gray40")
+			}
+		} else {
+			node.SetStyle(cgraph.NodeStyle("dashed,rounded"))
+		}
+
+		if person.Known() {
+			if edge, err := graph.CreateEdgeByName("", meNode, node); err == nil {
+				styleKnownEdge(edge, person)
+			}
+		}
+	}
+
+	days, now := charm.RelationshipRevalidateDays(g.client.Config()), time.Now()
+	for _, rel := range m.Links {
+		edge, err := graph.CreateEdgeByName("", nodes[rel.ContactID1], nodes[rel.ContactID2])
+		if err != nil {
+			continue
+		}
+		edge.SetDir(cgraph.NoneDir)
+		edge.SetStyle(cgraph.DashedEdgeStyle)
+		edge.SetColor("steelblue")
+		styleRelationshipEdge(edge, rel, days, now)
+	}
+
+	return g.render(ctx, gv, graph)
+}
+
+// styleKnownEdge draws my link to a person heavier the better I know them,
+// labelled with the strength or, when untracked, the interaction count.
+func styleKnownEdge(edge *cgraph.Edge, person *charm.InfluencePerson) {
+	edge.SetDir(cgraph.NoneDir)
+	edge.SetColor("darkgreen")
+	switch person.Strength {
+	case charm.StrengthStrong:
+		edge.SetStyle(cgraph.BoldEdgeStyle)
+	case charm.StrengthWeak:
+		edge.SetStyle(cgraph.DashedEdgeStyle)
+	case charm.StrengthMedium:
+	default:
+		edge.SetStyle(cgraph.DottedEdgeStyle)
+	}
+
+	label := person.Strength
+	if label == "" {
+		label = fmt.Sprintf("%d interaction(s)", person.Interactions)
+	}
+	if person.LastContact != nil {
+		label += "\nlast " + person.LastContact.Format("2006-01-02")
+	}
+	edge.SetLabel(label)
+}
+
+// topInfluenceRole returns the first of roles in influenceRoleOrder.
+func topInfluenceRole(roles []string) string {
+	for _, role := range influenceRoleOrder {
+		for _, r := range roles {
+			if r == role {
+				return role
+			}
+		}
+	}
+	return ""
+}
+
+// roleLabels formats roles for display, e.g. "decision maker, primary".
+func roleLabels(roles []string) string {
+	labels := make([]string, len(roles))
+	for i, role := range roles {
+		labels[i] = strings.ReplaceAll(role, "_", " ")
+	}
+	return strings.Join(labels, ", ")
+}
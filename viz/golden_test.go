@@ -0,0 +1,220 @@
+// ABOUTME: Golden-file tests for every graph and report renderer in viz
+// ABOUTME: Run `go test ./viz -update-golden` to rewrite testdata/*.golden after a deliberate change
+package viz
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite testdata/*.golden with the current output")
+
+// assertGolden compares got with testdata/<name>.golden, or rewrites the file
+// when -update-golden is set.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("Failed to create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run with -update-golden to create it): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("%s output changed; review it and rerun with -update-golden if intended\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+// goldenData is the seeded dataset the golden tests render. IDs are fixed so
+// node names stay stable between runs.
+type goldenData struct {
+	acme, globex      *charm.Company
+	alice, bob, carol *charm.Contact
+	renewal, pilot    *charm.Deal
+}
+
+func seedGoldenData(t *testing.T, client *charm.Client) *goldenData {
+	t.Helper()
+	d := &goldenData{
+		acme:   &charm.Company{ID: uuid.MustParse("10000000-0000-0000-0000-000000000001"), Name: "Acme Corp", Domain: "acme.com", Industry: "Manufacturing"},
+		globex: &charm.Company{ID: uuid.MustParse("10000000-0000-0000-0000-000000000002"), Name: "Globex", Domain: "globex.com", Industry: "Software"},
+	}
+	for _, company := range []*charm.Company{d.acme, d.globex} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("Failed to create company: %v", err)
+		}
+	}
+
+	recent := time.Now().AddDate(0, 0, -3)
+	stale := time.Now().AddDate(0, 0, -45)
+	d.alice = &charm.Contact{ID: uuid.MustParse("20000000-0000-0000-0000-000000000001"), Name: "Alice Adams", Email: "alice@acme.com", CompanyID: &d.acme.ID, CompanyName: d.acme.Name, LastContactedAt: &recent}
+	d.bob = &charm.Contact{ID: uuid.MustParse("20000000-0000-0000-0000-000000000002"), Name: "Bob Brown", Email: "bob@acme.com", CompanyID: &d.acme.ID, CompanyName: d.acme.Name, LastContactedAt: &stale}
+	d.carol = &charm.Contact{ID: uuid.MustParse("20000000-0000-0000-0000-000000000003"), Name: "Carol Chen", Email: "carol@globex.com", CompanyID: &d.globex.ID, CompanyName: d.globex.Name}
+	for _, contact := range []*charm.Contact{d.alice, d.bob, d.carol} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("Failed to create contact: %v", err)
+		}
+	}
+
+	rels := []*charm.Relationship{
+		{ID: uuid.MustParse("30000000-0000-0000-0000-000000000001"), ContactID1: d.alice.ID, ContactID2: d.bob.ID, RelationshipType: "colleague", Strength: charm.StrengthStrong},
+		{ID: uuid.MustParse("30000000-0000-0000-0000-000000000002"), ContactID1: d.alice.ID, ContactID2: d.carol.ID, RelationshipType: "friend"},
+	}
+	for _, rel := range rels {
+		if err := client.CreateRelationship(rel); err != nil {
+			t.Fatalf("Failed to create relationship: %v", err)
+		}
+	}
+
+	d.renewal = &charm.Deal{ID: uuid.MustParse("40000000-0000-0000-0000-000000000001"), Title: "Acme Renewal", CompanyID: d.acme.ID, ContactID: &d.alice.ID, Amount: 1200000, Currency: "USD", Stage: charm.StageNegotiation, Source: charm.DealSourceReferral, ReferrerID: &d.carol.ID, ReferrerName: d.carol.Name}
+	d.pilot = &charm.Deal{ID: uuid.MustParse("40000000-0000-0000-0000-000000000002"), Title: "Globex Pilot", CompanyID: d.globex.ID, ContactID: &d.carol.ID, Amount: 250000, Currency: "USD", Stage: charm.StageProspecting}
+	won := &charm.Deal{ID: uuid.MustParse("40000000-0000-0000-0000-000000000003"), Title: "Acme Onboarding", CompanyID: d.acme.ID, Amount: 500000, Currency: "USD", Stage: charm.StageClosedWon, Source: charm.DealSourceInbound}
+	for _, deal := range []*charm.Deal{d.renewal, d.pilot, won} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("Failed to create deal: %v", err)
+		}
+	}
+	return d
+}
+
+func TestGraphGolden(t *testing.T) {
+	client := charm.NewTestClient(t)
+	d := seedGoldenData(t, client)
+	generator := NewGraphGenerator(client)
+
+	tests := []struct {
+		name   string
+		render func() (string, error)
+	}{
+		{"contacts", func() (string, error) { return generator.GenerateContactGraph(nil) }},
+		{"contact_alice", func() (string, error) { return generator.GenerateContactGraph(&d.alice.ID) }},
+		{"company_acme", func() (string, error) { return generator.GenerateCompanyGraph(d.acme.ID) }},
+		{"deal_renewal", func() (string, error) { return generator.GenerateDealGraph(d.renewal.ID, true) }},
+		{"pipeline", generator.GeneratePipelineGraph},
+		{"complete", generator.GenerateCompleteGraph},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.render()
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			assertGolden(t, "graph_"+tt.name, got)
+		})
+	}
+}
+
+func TestGraphFormatsGolden(t *testing.T) {
+	data := testGraphData()
+	for _, format := range []string{GraphFormatMermaid, GraphFormatGraphML, GraphFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			got, err := data.Render(format)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			assertGolden(t, "graph_format_"+format, got)
+		})
+	}
+}
+
+func TestSyncGraphGolden(t *testing.T) {
+	// Labels print times in local time
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	at := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	topology := &charm.SyncTopology{
+		Host:         "cloud.charm.sh",
+		AutoSync:     true,
+		LocalSeq:     41,
+		LastSync:     at,
+		CloudSeq:     42,
+		CloudBackups: 3,
+		Uploaders:    []charm.SyncUploader{{DeviceID: "a1b2c3d4e5f6", Seq: 42, At: at}},
+		Devices:      []charm.SyncDevice{{Name: "ed25519 SHA256:abcd", Current: true}, {Name: "ed25519 SHA256:efgh"}},
+	}
+
+	got, err := GenerateSyncGraph(topology)
+	if err != nil {
+		t.Fatalf("GenerateSyncGraph failed: %v", err)
+	}
+	assertGolden(t, "graph_sync", got)
+}
+
+func TestReportGolden(t *testing.T) {
+	client := charm.NewTestClient(t)
+	seedGoldenData(t, client)
+
+	stats, err := GenerateDashboardStats(client)
+	if err != nil {
+		t.Fatalf("GenerateDashboardStats failed: %v", err)
+	}
+	assertGolden(t, "dashboard", RenderDashboard(stats))
+
+	report, err := GenerateSourceReport(client, 5)
+	if err != nil {
+		t.Fatalf("GenerateSourceReport failed: %v", err)
+	}
+	assertGolden(t, "sources", RenderSourceReport(report))
+
+	met := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+	places := []*charm.PlaceSummary{
+		{Place: charm.Place{City: "Chicago", Region: "IL"}, Meetings: 6, Contacts: []string{"Alice Adams", "Bob Brown", "Carol Chen", "Dan Diaz"}, LastMet: met},
+		{Place: charm.Place{City: "Berlin", Country: "Germany"}, Meetings: 2, Contacts: []string{"Carol Chen"}, LastMet: met.AddDate(0, -2, 0)},
+		{Place: charm.Place{City: "Austin"}, Meetings: 1, LastMet: met.AddDate(-1, 0, 0)},
+	}
+	unresolved := []string{"the usual spot"}
+	assertGolden(t, "places", RenderPlaces(places, unresolved, 2, false))
+	assertGolden(t, "places_unresolved", RenderPlaces(places, unresolved, 0, true))
+
+	points := []charm.TrendPoint{
+		{Label: "2024-04-01", Snapshot: &charm.PipelineSnapshot{Stages: map[string]charm.StageTotals{
+			charm.StageProspecting: {Count: 2, Amount: 400000, Expected: 40000},
+			charm.StageProposal:    {Count: 1, Amount: 300000, Expected: 150000},
+		}}},
+		{Label: "2024-04-08", Snapshot: &charm.PipelineSnapshot{Stages: map[string]charm.StageTotals{
+			charm.StageProspecting: {Count: 1, Amount: 250000, Expected: 25000},
+			charm.StageNegotiation: {Count: 1, Amount: 1200000, Expected: 960000},
+			charm.StageClosedWon:   {Count: 1, Amount: 500000, Expected: 500000},
+		}}},
+		{Label: "2024-04-15", Snapshot: &charm.PipelineSnapshot{Stages: map[string]charm.StageTotals{
+			charm.StageNegotiation: {Count: 1, Amount: 1200000, Expected: 960000},
+			charm.StageClosedWon:   {Count: 2, Amount: 750000, Expected: 750000},
+		}}},
+	}
+	assertGolden(t, "trend", RenderTrend(points, TrendMetricValue))
+	assertGolden(t, "trend_svg", RenderTrendSVG(points, TrendMetricValue))
+
+	velocity := &charm.PipelineMetrics{
+		Stages: []charm.StageVelocity{
+			{Stage: charm.StageProspecting, Entered: 5, Current: 1, AvgDays: 6.5, Advanced: 3, Lost: 1, ConversionRate: 0.75},
+			{Stage: charm.StageQualification, Entered: 3, AvgDays: 12.25, Advanced: 2, Lost: 1, ConversionRate: 2.0 / 3},
+			{Stage: charm.StageProposal, Entered: 2, Current: 1, AvgDays: 20, Advanced: 1, ConversionRate: 1},
+			{Stage: charm.StageNegotiation, Entered: 1, Current: 1},
+		},
+		Won: 1, Lost: 2, WinRate: 1.0 / 3, AvgDaysToClose: 41.5,
+		StalledDays: 30,
+		Stalled: []charm.StalledDeal{
+			{Title: "Enterprise renewal with a long name", CompanyName: "Acme Corp", Stage: charm.StageNegotiation, DaysInStage: 45, Amount: 1200000, Currency: "USD"},
+			{Title: "Pilot", Stage: charm.StageProposal, DaysInStage: 31, Amount: 250000, Currency: "EUR"},
+		},
+	}
+	assertGolden(t, "velocity", RenderPipelineVelocity(velocity))
+	assertGolden(t, "velocity_empty", RenderPipelineVelocity(&charm.PipelineMetrics{StalledDays: 14}))
+}
@@ -0,0 +1,408 @@
+// ABOUTME: Graph output formats beyond DOT: Mermaid for Markdown docs, GraphML for Gephi, and JSON
+// ABOUTME: Reads nodes, edges, clusters, and their styling back out of a built graph and writes them in each format
+package viz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-graphviz"
+	"github.com/goccy/go-graphviz/cgraph"
+)
+
+// Graph output formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatGraphML = "graphml"
+	GraphFormatJSON    = "json"
+)
+
+// GraphFormats lists the formats graphs can be written in.
+var GraphFormats = []string{GraphFormatDOT, GraphFormatMermaid, GraphFormatGraphML, GraphFormatJSON}
+
+// ValidGraphFormat reports whether format is one of GraphFormats.
+func ValidGraphFormat(format string) bool {
+	for _, f := range GraphFormats {
+		if format == f {
+			return true
+		}
+	}
+	return false
+}
+
+// GraphFormatForFile picks a format from an output file's extension, or ""
+// when the extension doesn't say.
+func GraphFormatForFile(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".dot"), strings.HasSuffix(lower, ".gv"):
+		return GraphFormatDOT
+	case strings.HasSuffix(lower, ".mmd"), strings.HasSuffix(lower, ".mermaid"):
+		return GraphFormatMermaid
+	case strings.HasSuffix(lower, ".graphml"):
+		return GraphFormatGraphML
+	case strings.HasSuffix(lower, ".json"):
+		return GraphFormatJSON
+	}
+	return ""
+}
+
+// WithFormat returns a generator that writes graphs in format instead of DOT.
+func (g *GraphGenerator) WithFormat(format string) *GraphGenerator {
+	return &GraphGenerator{client: g.client, format: format}
+}
+
+// render writes a built graph in the generator's format.
+func (g *GraphGenerator) render(ctx context.Context, gv *graphviz.Graphviz, graph *cgraph.Graph) (string, error) {
+	if g.format == "" || g.format == GraphFormatDOT {
+		var buf bytes.Buffer
+		if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
+			return "", fmt.Errorf("failed to render graph: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	data, err := exportGraph(graph)
+	if err != nil {
+		return "", fmt.Errorf("failed to read graph: %w", err)
+	}
+	return data.Render(g.format)
+}
+
+// GraphData is a graph as plain nodes and edges, for formats other than DOT.
+type GraphData struct {
+	Label     string      `json:"label,omitempty"`
+	Direction string      `json:"direction"` // TB or LR
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a graph node. Group is the label of the cluster it's drawn
+// in, e.g. a pipeline stage.
+type GraphNode struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Group string `json:"group,omitempty"`
+	Shape string `json:"shape,omitempty"`
+	Color string `json:"color,omitempty"` // fill color
+}
+
+// GraphEdge is a directed edge between two node IDs. Style is dashed,
+// dotted, or bold, and Weight the line width.
+type GraphEdge struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Label  string  `json:"label,omitempty"`
+	Style  string  `json:"style,omitempty"`
+	Color  string  `json:"color,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// Render writes the graph as Mermaid, GraphML, or JSON.
+func (d *GraphData) Render(format string) (string, error) {
+	switch format {
+	case GraphFormatMermaid:
+		return d.Mermaid(), nil
+	case GraphFormatGraphML:
+		return d.GraphML()
+	case GraphFormatJSON:
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode graph: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	return "", fmt.Errorf("invalid graph format: %s (use %s)", format, strings.Join(GraphFormats, ", "))
+}
+
+// exportGraph reads a built graph's nodes, edges, and clusters.
+func exportGraph(graph *cgraph.Graph) (*GraphData, error) {
+	data := &GraphData{Direction: "TB", Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	name, _ := graph.Name()
+	data.Label = graphLabel(graph.GetStr("label"), name)
+	if graph.GetStr("rankdir") == "LR" {
+		data.Direction = "LR"
+	}
+
+	// Clusters become groups, by node name
+	groups := make(map[string]string)
+	sub, err := graph.FirstSubGraph()
+	for err == nil && sub != nil {
+		subName, _ := sub.Name()
+		group := graphLabel(sub.GetStr("label"), subName)
+		node, nodeErr := sub.FirstNode()
+		for nodeErr == nil && node != nil {
+			if nodeName, err := node.Name(); err == nil {
+				groups[nodeName] = group
+			}
+			node, nodeErr = sub.NextNode(node)
+		}
+		sub, err = sub.NextSubGraph()
+	}
+
+	ids := make(map[string]string)
+	var nodes []*cgraph.Node
+	node, err := graph.FirstNode()
+	for node != nil {
+		if err != nil {
+			return nil, err
+		}
+		nodeName, err := node.Name()
+		if err != nil {
+			return nil, err
+		}
+		id := "n" + strconv.Itoa(len(data.Nodes))
+		ids[nodeName] = id
+		nodes = append(nodes, node)
+		data.Nodes = append(data.Nodes, GraphNode{
+			ID:    id,
+			Label: graphLabel(node.GetStr("label"), nodeName),
+			Group: groups[nodeName],
+			Shape: node.GetStr("shape"),
+			Color: node.GetStr("fillcolor"),
+		})
+		node, err = graph.NextNode(node)
+	}
+
+	for _, node := range nodes {
+		edge, err := graph.FirstOut(node)
+		for edge != nil {
+			if err != nil {
+				return nil, err
+			}
+			head, err := edge.Head()
+			if err != nil {
+				return nil, err
+			}
+			tail, err := edge.Tail()
+			if err != nil {
+				return nil, err
+			}
+			headName, _ := head.Name()
+			tailName, _ := tail.Name()
+			weight, _ := strconv.ParseFloat(edge.GetStr("penwidth"), 64)
+			data.Edges = append(data.Edges, GraphEdge{
+				Source: ids[tailName],
+				Target: ids[headName],
+				Label:  graphLabel(edge.GetStr("label"), ""),
+				Style:  edge.GetStr("style"),
+				Color:  edge.GetStr("color"),
+				Weight: weight,
+			})
+			edge, err = graph.NextOut(edge)
+		}
+	}
+	return data, nil
+}
+
+// graphLabel turns a DOT label into plain text with real newlines. Graphviz
+// shows the object's name for an empty label or \N and \G.
+func graphLabel(label, name string) string {
+	if label == "" || label == `\N` || label == `\G` {
+		label = name
+	}
+	return strings.NewReplacer(`\n`, "\n", `\l`, "\n", `\r`, "\n").Replace(label)
+}
+
+// mermaidShapes maps Graphviz node shapes to Mermaid's brackets.
+var mermaidShapes = map[string][2]string{
+	"box":           {"[", "]"},
+	"rect":          {"[", "]"},
+	"rectangle":     {"[", "]"},
+	"diamond":       {"{", "}"},
+	"circle":        {"((", "))"},
+	"doublecircle":  {"(((", ")))"},
+	"cylinder":      {"[(", ")]"},
+	"ellipse":       {"(", ")"},
+	"":              {"(", ")"},
+	"oval":          {"(", ")"},
+	"plaintext":     {"[", "]"},
+	"rounded":       {"(", ")"},
+	"parallelogram": {"[/", "/]"},
+}
+
+// Mermaid writes the graph as a Mermaid flowchart for Markdown documents.
+// Clusters become subgraphs; fill and line colors that aren't plain CSS
+// colors are left out.
+func (d *GraphData) Mermaid() string {
+	var out strings.Builder
+	if d.Label != "" {
+		fmt.Fprintf(&out, "---\ntitle: %s\n---\n", mermaidText(d.Label))
+	}
+	fmt.Fprintf(&out, "flowchart %s\n", d.Direction)
+
+	writeNode := func(indent string, node GraphNode) {
+		brackets, ok := mermaidShapes[node.Shape]
+		if !ok {
+			brackets = mermaidShapes["box"]
+		}
+		fmt.Fprintf(&out, "%s%s%s\"%s\"%s\n", indent, node.ID, brackets[0], mermaidText(node.Label), brackets[1])
+	}
+
+	// Ungrouped nodes first, then a subgraph per group in first-seen order
+	var groups []string
+	members := make(map[string][]GraphNode)
+	for _, node := range d.Nodes {
+		if node.Group == "" {
+			writeNode("    ", node)
+			continue
+		}
+		if _, seen := members[node.Group]; !seen {
+			groups = append(groups, node.Group)
+		}
+		members[node.Group] = append(members[node.Group], node)
+	}
+	for i, group := range groups {
+		fmt.Fprintf(&out, "    subgraph g%d[\"%s\"]\n", i, mermaidText(group))
+		for _, node := range members[group] {
+			writeNode("        ", node)
+		}
+		out.WriteString("    end\n")
+	}
+
+	for _, edge := range d.Edges {
+		arrow := "-->"
+		switch {
+		case strings.Contains(edge.Style, "dashed"), strings.Contains(edge.Style, "dotted"):
+			arrow = "-.->"
+		case strings.Contains(edge.Style, "bold"):
+			arrow = "==>"
+		}
+		if edge.Label != "" {
+			fmt.Fprintf(&out, "    %s %s|\"%s\"| %s\n", edge.Source, arrow, mermaidText(edge.Label), edge.Target)
+		} else {
+			fmt.Fprintf(&out, "    %s %s %s\n", edge.Source, arrow, edge.Target)
+		}
+	}
+
+	for _, node := range d.Nodes {
+		if color := cssColor(node.Color); color != "" {
+			fmt.Fprintf(&out, "    style %s fill:%s\n", node.ID, color)
+		}
+	}
+	for i, edge := range d.Edges {
+		var styles []string
+		if color := cssColor(edge.Color); color != "" {
+			styles = append(styles, "stroke:"+color)
+		}
+		if edge.Weight > 1 {
+			styles = append(styles, fmt.Sprintf("stroke-width:%gpx", edge.Weight))
+		}
+		if len(styles) > 0 {
+			fmt.Fprintf(&out, "    linkStyle %d %s\n", i, strings.Join(styles, ","))
+		}
+	}
+	return out.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s)
+}
+
+// cssColor returns a Graphviz color that is also a CSS color: a hex value or
+// a plain name. Graphviz-only names like gray40 are dropped.
+func cssColor(color string) string {
+	if strings.HasPrefix(color, "#") {
+		return color
+	}
+	for _, r := range color {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return color
+}
+
+// GraphML writes the graph as GraphML, which Gephi, yEd, and Cytoscape open.
+// Labels, groups, shapes, colors, styles, and weights are GraphML attributes.
+func (d *GraphData) GraphML() (string, error) {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	type key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	type node struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+	type edge struct {
+		ID     string `xml:"id,attr"`
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+	type graph struct {
+		ID          string `xml:"id,attr"`
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Data        []data `xml:"data"`
+		Nodes       []node `xml:"node"`
+		Edges       []edge `xml:"edge"`
+	}
+	type graphml struct {
+		XMLName xml.Name `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+		Keys    []key    `xml:"key"`
+		Graph   graph    `xml:"graph"`
+	}
+
+	// optional drops empty values so absent attributes stay absent
+	optional := func(values ...data) []data {
+		var kept []data
+		for _, v := range values {
+			if v.Value != "" {
+				kept = append(kept, v)
+			}
+		}
+		return kept
+	}
+
+	doc := graphml{
+		Keys: []key{
+			{ID: "title", For: "graph", Name: "label", Type: "string"},
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "group", For: "node", Name: "group", Type: "string"},
+			{ID: "shape", For: "node", Name: "shape", Type: "string"},
+			{ID: "color", For: "node", Name: "color", Type: "string"},
+			{ID: "edge_label", For: "edge", Name: "label", Type: "string"},
+			{ID: "style", For: "edge", Name: "style", Type: "string"},
+			{ID: "edge_color", For: "edge", Name: "color", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+		},
+		Graph: graph{ID: "G", EdgeDefault: "directed", Data: optional(data{"title", d.Label})},
+	}
+	for _, n := range d.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{
+			ID:   n.ID,
+			Data: optional(data{"label", n.Label}, data{"group", n.Group}, data{"shape", n.Shape}, data{"color", n.Color}),
+		})
+	}
+	for i, e := range d.Edges {
+		weight := ""
+		if e.Weight > 0 {
+			weight = strconv.FormatFloat(e.Weight, 'g', -1, 64)
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: e.Source,
+			Target: e.Target,
+			Data:   optional(data{"edge_label", e.Label}, data{"style", e.Style}, data{"edge_color", e.Color}, data{"weight", weight}),
+		})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode graph: %w", err)
+	}
+	return xml.Header + string(out) + "\n", nil
+}
@@ -0,0 +1,154 @@
+// ABOUTME: Tests for graph output formats
+// ABOUTME: Validates Mermaid, GraphML, and JSON output and format selection
+package viz
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func testGraphData() *GraphData {
+	return &GraphData{
+		Label:     "Pipeline",
+		Direction: "LR",
+		Nodes: []GraphNode{
+			{ID: "n0", Label: "Acme \"Big\" Deal\n$50000", Group: "proposal", Shape: "box", Color: "lightblue"},
+			{ID: "n1", Label: "Alice", Shape: "ellipse", Color: "gray90"},
+			{ID: "n2", Label: "Globex", Group: "proposal", Shape: "diamond", Color: "#ffcc00"},
+		},
+		Edges: []GraphEdge{
+			{Source: "n1", Target: "n0", Label: "champion", Style: "dashed", Color: "blue"},
+			{Source: "n1", Target: "n2", Style: "bold", Weight: 3},
+		},
+	}
+}
+
+func TestGraphMermaid(t *testing.T) {
+	out := testGraphData().Mermaid()
+
+	for _, want := range []string{
+		"title: Pipeline\n",
+		"flowchart LR\n",
+		`n1("Alice")`,
+		`    subgraph g0["proposal"]`,
+		`        n0["Acme #quot;Big#quot; Deal<br/>$50000"]`,
+		`        n2{"Globex"}`,
+		`n1 -.->|"champion"| n0`,
+		"n1 ==> n2",
+		"style n0 fill:lightblue",
+		"style n2 fill:#ffcc00",
+		"linkStyle 0 stroke:blue",
+		"linkStyle 1 stroke-width:3px",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+	// Graphviz-only color names aren't CSS
+	if strings.Contains(out, "gray90") {
+		t.Errorf("Mermaid kept a Graphviz-only color:\n%s", out)
+	}
+}
+
+func TestGraphGraphML(t *testing.T) {
+	out, err := testGraphData().GraphML()
+	if err != nil {
+		t.Fatalf("GraphML failed: %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GraphML isn't valid XML: %v\n%s", err, out)
+	}
+	if doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("Expected directed graph, got %q", doc.Graph.EdgeDefault)
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if edge := doc.Graph.Edges[0]; edge.Source != "n1" || edge.Target != "n0" {
+		t.Errorf("Expected edge n1 -> n0, got %s -> %s", edge.Source, edge.Target)
+	}
+	data := map[string]string{}
+	for _, d := range doc.Graph.Nodes[0].Data {
+		data[d.Key] = d.Value
+	}
+	if data["label"] != "Acme \"Big\" Deal\n$50000" || data["group"] != "proposal" {
+		t.Errorf("Unexpected node data: %v", data)
+	}
+	// Empty values are left out
+	for _, d := range doc.Graph.Nodes[1].Data {
+		if d.Key == "group" {
+			t.Error("Ungrouped node has a group attribute")
+		}
+	}
+}
+
+func TestGraphRenderJSON(t *testing.T) {
+	out, err := testGraphData().Render(GraphFormatJSON)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var decoded GraphData
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("JSON doesn't decode: %v", err)
+	}
+	if len(decoded.Nodes) != 3 || decoded.Edges[1].Weight != 3 {
+		t.Errorf("Unexpected JSON graph: %+v", decoded)
+	}
+
+	if _, err := testGraphData().Render("svg"); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+}
+
+func TestGraphLabel(t *testing.T) {
+	tests := []struct{ label, name, want string }{
+		{"", "node1", "node1"},
+		{`\N`, "node1", "node1"},
+		{`Deal\n$5000`, "x", "Deal\n$5000"},
+		{"Line one\nLine two", "x", "Line one\nLine two"},
+	}
+	for _, tt := range tests {
+		if got := graphLabel(tt.label, tt.name); got != tt.want {
+			t.Errorf("graphLabel(%q, %q) = %q, want %q", tt.label, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGraphFormatForFile(t *testing.T) {
+	tests := map[string]string{
+		"":              "",
+		"graph.dot":     GraphFormatDOT,
+		"graph.MMD":     GraphFormatMermaid,
+		"crm.graphml":   GraphFormatGraphML,
+		"crm.json":      GraphFormatJSON,
+		"graph.svg":     "",
+		"notes/deal.gv": GraphFormatDOT,
+	}
+	for path, want := range tests {
+		if got := GraphFormatForFile(path); got != want {
+			t.Errorf("GraphFormatForFile(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if !ValidGraphFormat(GraphFormatMermaid) || ValidGraphFormat("png") {
+		t.Error("ValidGraphFormat disagrees with GraphFormats")
+	}
+}
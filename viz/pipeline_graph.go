@@ -1,7 +1,6 @@
 package viz
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 
@@ -65,7 +64,7 @@ func (g *GraphGenerator) GeneratePipelineGraph() (string, error) {
 		subgraph.SetLabel(stage)
 
 		for _, deal := range dealsByStage[stage] {
-			label := fmt.Sprintf("%s\\n$%d", deal.Title, deal.Amount/100)
+			label := fmt.Sprintf("%s\\n%s", deal.Title, charm.FormatMoneyRounded(deal.Amount, deal.Currency))
 			node, err := subgraph.CreateNodeByName(label)
 			if err != nil {
 				continue
@@ -74,10 +73,5 @@ func (g *GraphGenerator) GeneratePipelineGraph() (string, error) {
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
-		return "", fmt.Errorf("failed to render graph: %w", err)
-	}
-
-	return buf.String(), nil
+	return g.render(ctx, gv, graph)
 }
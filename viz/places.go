@@ -0,0 +1,75 @@
+// ABOUTME: Meeting places report for the terminal
+// ABOUTME: Ranks the cities where you meet people in person, with the people met there most
+package viz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// RenderPlaces draws the top cities by in-person meetings. Unresolved
+// locations are counted, or listed when showUnresolved is set.
+func RenderPlaces(places []*charm.PlaceSummary, unresolved []string, top int, showUnresolved bool) string {
+	var out strings.Builder
+
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	out.WriteString("  PLACES\n")
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(places) == 0 {
+		out.WriteString("  No in-person meetings with a location yet\n")
+	}
+
+	maxMeetings := 0
+	for _, p := range places {
+		if p.Meetings > maxMeetings {
+			maxMeetings = p.Meetings
+		}
+	}
+
+	for i, p := range places {
+		if top > 0 && i >= top {
+			fmt.Fprintf(&out, "  ... %d more\n", len(places)-top)
+			break
+		}
+
+		barLength := (p.Meetings * 10) / maxMeetings
+		bar := strings.Repeat("█", barLength) + strings.Repeat("░", 10-barLength)
+		fmt.Fprintf(&out, "  %-24s %s %3d meeting(s)  last %s\n", truncate(p.Name(), 24), bar, p.Meetings, p.LastMet.Format("2006-01-02"))
+
+		people := p.Contacts
+		more := ""
+		if len(people) > 3 {
+			more = fmt.Sprintf(" +%d more", len(people)-3)
+			people = people[:3]
+		}
+		if len(people) > 0 {
+			fmt.Fprintf(&out, "  %-24s %s%s\n", "", strings.Join(people, ", "), more)
+		}
+	}
+
+	if len(unresolved) > 0 {
+		fmt.Fprintf(&out, "\n  %d location(s) without a city", len(unresolved))
+		if !showUnresolved {
+			out.WriteString(" (--unresolved lists them, --geocode looks them up)\n")
+			return out.String()
+		}
+		out.WriteString(":\n")
+		for _, location := range unresolved {
+			fmt.Fprintf(&out, "    - %s\n", location)
+		}
+	}
+
+	return out.String()
+}
+
+// truncate shortens s to n runes, ending with an ellipsis when cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
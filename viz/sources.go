@@ -0,0 +1,154 @@
+// ABOUTME: Deal source reporting for the terminal
+// ABOUTME: Summarizes pipeline and win rate by deal source and ranks top referrers
+package viz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// unknownSource groups deals without a source.
+const unknownSource = "unknown"
+
+type SourceReport struct {
+	Sources      []SourceStats
+	TopReferrers []ReferrerStats
+}
+
+type SourceStats struct {
+	Source       string
+	Deals        int
+	Won          int
+	Lost         int
+	OpenPipeline int64 // in cents
+	WonValue     int64 // in cents
+}
+
+// WinRate returns won / (won + lost), or 0 if no deals have closed.
+func (s SourceStats) WinRate() float64 {
+	closed := s.Won + s.Lost
+	if closed == 0 {
+		return 0
+	}
+	return float64(s.Won) / float64(closed)
+}
+
+type ReferrerStats struct {
+	ContactID uuid.UUID
+	Name      string
+	Deals     int
+	Won       int
+	WonValue  int64 // in cents
+}
+
+// GenerateSourceReport groups deals by source and ranks the top n referrers
+// by won value, then deal count.
+func GenerateSourceReport(client *charm.Client, topReferrers int) (*SourceReport, error) {
+	deals, err := client.ListDeals(&charm.DealFilter{Limit: 10000})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deals: %w", err)
+	}
+
+	bySource := make(map[string]*SourceStats)
+	byReferrer := make(map[uuid.UUID]*ReferrerStats)
+
+	for _, deal := range deals {
+		source := deal.Source
+		if source == "" {
+			source = unknownSource
+		}
+		stats, ok := bySource[source]
+		if !ok {
+			stats = &SourceStats{Source: source}
+			bySource[source] = stats
+		}
+		stats.Deals++
+
+		switch deal.Stage {
+		case charm.StageClosedWon:
+			stats.Won++
+			stats.WonValue += deal.Amount
+		case charm.StageClosedLost:
+			stats.Lost++
+		default:
+			stats.OpenPipeline += deal.Amount
+		}
+
+		if deal.ReferrerID == nil {
+			continue
+		}
+		ref, ok := byReferrer[*deal.ReferrerID]
+		if !ok {
+			ref = &ReferrerStats{ContactID: *deal.ReferrerID, Name: deal.ReferrerName}
+			byReferrer[*deal.ReferrerID] = ref
+		}
+		ref.Deals++
+		if deal.Stage == charm.StageClosedWon {
+			ref.Won++
+			ref.WonValue += deal.Amount
+		}
+	}
+
+	report := &SourceReport{}
+	for _, source := range append(append([]string{}, charm.DealSources...), unknownSource) {
+		if stats, ok := bySource[source]; ok {
+			report.Sources = append(report.Sources, *stats)
+		}
+	}
+
+	for _, ref := range byReferrer {
+		report.TopReferrers = append(report.TopReferrers, *ref)
+	}
+	sort.Slice(report.TopReferrers, func(i, j int) bool {
+		a, b := report.TopReferrers[i], report.TopReferrers[j]
+		if a.WonValue != b.WonValue {
+			return a.WonValue > b.WonValue
+		}
+		if a.Deals != b.Deals {
+			return a.Deals > b.Deals
+		}
+		return a.Name < b.Name
+	})
+	if topReferrers > 0 && len(report.TopReferrers) > topReferrers {
+		report.TopReferrers = report.TopReferrers[:topReferrers]
+	}
+
+	return report, nil
+}
+
+func RenderSourceReport(report *SourceReport) string {
+	var out strings.Builder
+
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	out.WriteString("  DEAL SOURCES\n")
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(report.Sources) == 0 {
+		out.WriteString("  No deals yet\n")
+		return out.String()
+	}
+
+	fmt.Fprintf(&out, "  %-10s %6s %12s %10s %9s\n", "SOURCE", "DEALS", "PIPELINE", "WON", "WIN RATE")
+	for _, s := range report.Sources {
+		winRate := "-"
+		if s.Won+s.Lost > 0 {
+			winRate = fmt.Sprintf("%.0f%%", s.WinRate()*100)
+		}
+		fmt.Fprintf(&out, "  %-10s %6d %12s %10s %9s\n",
+			s.Source, s.Deals, charm.FormatMoneyRounded(s.OpenPipeline, ""), charm.FormatMoneyRounded(s.WonValue, ""), winRate)
+	}
+
+	if len(report.TopReferrers) > 0 {
+		out.WriteString("\nTOP REFERRERS\n")
+		for i, ref := range report.TopReferrers {
+			fmt.Fprintf(&out, "  %d. %-20s %2d deal(s), %d won (%s)\n",
+				i+1, ref.Name, ref.Deals, ref.Won, charm.FormatMoneyRounded(ref.WonValue, ""))
+		}
+	}
+
+	return out.String()
+}
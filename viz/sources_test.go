@@ -0,0 +1,71 @@
+// ABOUTME: Tests for deal source reporting
+// ABOUTME: Validates per-source pipeline, win rate, and referrer ranking
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestGenerateSourceReport(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	company := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("Failed to create company: %v", err)
+	}
+	alice := &charm.Contact{Name: "Alice"}
+	bob := &charm.Contact{Name: "Bob"}
+	for _, contact := range []*charm.Contact{alice, bob} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("Failed to create contact: %v", err)
+		}
+	}
+
+	deals := []*charm.Deal{
+		{Title: "A", Amount: 100000, Stage: charm.StageClosedWon, Source: charm.DealSourceReferral, ReferrerID: &alice.ID, ReferrerName: "Alice"},
+		{Title: "B", Amount: 50000, Stage: charm.StageClosedLost, Source: charm.DealSourceReferral, ReferrerID: &bob.ID, ReferrerName: "Bob"},
+		{Title: "C", Amount: 20000, Stage: charm.StageProposal, Source: charm.DealSourceReferral, ReferrerID: &bob.ID, ReferrerName: "Bob"},
+		{Title: "D", Amount: 30000, Stage: charm.StageProspecting},
+	}
+	for _, deal := range deals {
+		deal.CompanyID = company.ID
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("Failed to create deal: %v", err)
+		}
+	}
+
+	if err := client.CreateDeal(&charm.Deal{Title: "Bad", CompanyID: company.ID, Source: "cold-call"}); err == nil {
+		t.Error("Expected error for invalid source")
+	}
+
+	report, err := GenerateSourceReport(client, 5)
+	if err != nil {
+		t.Fatalf("GenerateSourceReport failed: %v", err)
+	}
+
+	if len(report.Sources) != 2 {
+		t.Fatalf("Expected 2 sources, got %d", len(report.Sources))
+	}
+	referral := report.Sources[0]
+	if referral.Source != charm.DealSourceReferral || referral.Deals != 3 || referral.OpenPipeline != 20000 || referral.WonValue != 100000 {
+		t.Errorf("Unexpected referral stats: %+v", referral)
+	}
+	if referral.WinRate() != 0.5 {
+		t.Errorf("Expected 50%% win rate, got %v", referral.WinRate())
+	}
+	if report.Sources[1].Source != unknownSource {
+		t.Errorf("Expected deals without a source last, got %s", report.Sources[1].Source)
+	}
+
+	if len(report.TopReferrers) != 2 || report.TopReferrers[0].Name != "Alice" {
+		t.Errorf("Expected Alice as top referrer, got %+v", report.TopReferrers)
+	}
+
+	output := RenderSourceReport(report)
+	if !strings.Contains(output, "$1,000") || !strings.Contains(output, "50%") {
+		t.Errorf("Unexpected report output:\n%s", output)
+	}
+}
@@ -0,0 +1,172 @@
+// ABOUTME: Sync topology graph generation
+// ABOUTME: Draws this device, the Charm cloud, linked devices, outbox size, and sequence numbers
+package viz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-graphviz"
+	"github.com/goccy/go-graphviz/cgraph"
+	"github.com/harperreed/pagen/charm"
+)
+
+// GenerateSyncGraph renders the sync topology as a DOT graph.
+func GenerateSyncGraph(t *charm.SyncTopology) (string, error) {
+	ctx := context.Background()
+	gv, err := graphviz.New(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create graphviz instance: %w", err)
+	}
+	defer func() { _ = gv.Close() }()
+
+	graph, err := gv.Graph()
+	if err != nil {
+		return "", fmt.Errorf("failed to create graph: %w", err)
+	}
+	defer func() { _ = graph.Close() }()
+
+	graph.SetLayout("dot")
+	graph.SetRankDir(cgraph.LRRank)
+	graph.SetLabel("Pagen Sync Topology")
+
+	// This device
+	local, err := graph.CreateNodeByName("local")
+	if err != nil {
+		return "", fmt.Errorf("failed to create local node: %w", err)
+	}
+	local.SetLabel(localLabel(t))
+	local.SetShape(cgraph.BoxShape)
+	local.SetStyle("filled")
+	local.SetFillColor(syncHealthColor(t))
+
+	// Charm cloud
+	cloud, err := graph.CreateNodeByName("cloud")
+	if err != nil {
+		return "", fmt.Errorf("failed to create cloud node: %w", err)
+	}
+	cloud.SetLabel(cloudLabel(t))
+	cloud.SetShape("cylinder")
+	cloud.SetStyle("filled")
+	cloud.SetFillColor("lightblue")
+
+	push, err := graph.CreateEdgeByName("push", local, cloud)
+	if err != nil {
+		return "", fmt.Errorf("failed to create edge: %w", err)
+	}
+	push.SetLabel(fmt.Sprintf("outbox: %d", t.PendingOps))
+	if t.PendingOps > 0 {
+		push.SetColor("orange")
+		push.SetPenWidth(2)
+	}
+
+	pull, err := graph.CreateEdgeByName("pull", cloud, local)
+	if err != nil {
+		return "", fmt.Errorf("failed to create edge: %w", err)
+	}
+	if t.Behind() {
+		pull.SetLabel(fmt.Sprintf("%d seq behind", t.CloudSeq-t.LocalSeq))
+		pull.SetColor("orange")
+		pull.SetPenWidth(2)
+	} else {
+		pull.SetLabel("up to date")
+		pull.SetStyle("dashed")
+	}
+
+	// Other linked devices
+	for i, device := range t.Devices {
+		if device.Current {
+			continue
+		}
+		node, err := graph.CreateNodeByName(fmt.Sprintf("device_%d", i))
+		if err != nil {
+			return "", fmt.Errorf("failed to create device node: %w", err)
+		}
+		label := device.Name
+		if device.LinkedAt != nil {
+			label += "\nlinked " + device.LinkedAt.Format("2006-01-02")
+		}
+		node.SetLabel(label)
+		node.SetShape(cgraph.BoxShape)
+
+		edge, err := graph.CreateEdgeByName(fmt.Sprintf("link_%d", i), node, cloud)
+		if err != nil {
+			return "", fmt.Errorf("failed to create edge: %w", err)
+		}
+		edge.SetDir(cgraph.BothDir)
+		edge.SetStyle("dotted")
+	}
+
+	if len(t.Errors) > 0 {
+		node, err := graph.CreateNodeByName("errors")
+		if err != nil {
+			return "", fmt.Errorf("failed to create errors node: %w", err)
+		}
+		node.SetLabel("Unavailable:\n" + strings.Join(t.Errors, "\n"))
+		node.SetShape("note")
+		node.SetFontColor("red")
+	}
+
+	var buf bytes.Buffer
+	if err := gv.Render(ctx, graph, graphviz.XDOT, &buf); err != nil {
+		return "", fmt.Errorf("failed to render graph: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func localLabel(t *charm.SyncTopology) string {
+	lines := []string{"This device"}
+	for _, d := range t.Devices {
+		if d.Current {
+			lines[0] += " (" + d.Name + ")"
+		}
+	}
+	lines = append(lines, fmt.Sprintf("local seq: %d", t.LocalSeq))
+	if t.PendingOps > 0 && !t.OldestPendingOp.IsZero() {
+		lines = append(lines, fmt.Sprintf("oldest pending: %s ago", time.Since(t.OldestPendingOp).Round(time.Second)))
+	}
+	if t.LastSync.IsZero() {
+		lines = append(lines, "last sync: never")
+	} else {
+		lines = append(lines, "last sync: "+t.LastSync.Local().Format("2006-01-02 15:04"))
+	}
+	if !t.AutoSync {
+		lines = append(lines, "auto-sync off")
+	}
+	if t.SyncLockHolder != "" {
+		lines = append(lines, "sync lock: "+t.SyncLockHolder)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func cloudLabel(t *charm.SyncTopology) string {
+	lines := []string{
+		"Charm cloud",
+		t.Host,
+		fmt.Sprintf("latest seq: %d (%d backups)", t.CloudSeq, t.CloudBackups),
+	}
+	for _, u := range t.Uploaders {
+		id := u.DeviceID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		lines = append(lines, fmt.Sprintf("%s: seq %d at %s", id, u.Seq, u.At.Local().Format("2006-01-02 15:04")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// syncHealthColor is green when in sync, yellow with unpushed or unpulled changes,
+// and red when collection failed.
+func syncHealthColor(t *charm.SyncTopology) string {
+	switch {
+	case len(t.Errors) > 0:
+		return "lightpink"
+	case t.PendingOps > 0 || t.Behind():
+		return "lightyellow"
+	default:
+		return "lightgreen"
+	}
+}
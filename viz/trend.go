@@ -0,0 +1,185 @@
+// ABOUTME: Pipeline trend charts from weekly pipeline snapshots
+// ABOUTME: Renders open pipeline per period as an ASCII bar chart or a stacked-bar SVG
+package viz
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// Trend metrics.
+const (
+	TrendMetricValue    = "value"
+	TrendMetricExpected = "expected"
+	TrendMetricCount    = "count"
+)
+
+// openStages are the stages stacked in trend charts, bottom first.
+var openStages = []string{
+	charm.StageProspecting,
+	charm.StageQualification,
+	charm.StageProposal,
+	charm.StageNegotiation,
+}
+
+// stageColors fill each stage in the SVG chart.
+var stageColors = map[string]string{
+	charm.StageProspecting:   "#9ecae1",
+	charm.StageQualification: "#6baed6",
+	charm.StageProposal:      "#3182bd",
+	charm.StageNegotiation:   "#08519c",
+}
+
+// ValidTrendMetric reports whether metric is value, expected, or count.
+func ValidTrendMetric(metric string) bool {
+	switch metric {
+	case TrendMetricValue, TrendMetricExpected, TrendMetricCount:
+		return true
+	}
+	return false
+}
+
+// metricOf returns a stage total's value for the metric.
+func metricOf(totals charm.StageTotals, metric string) int64 {
+	switch metric {
+	case TrendMetricCount:
+		return int64(totals.Count)
+	case TrendMetricExpected:
+		return totals.Expected
+	}
+	return totals.Amount
+}
+
+// formatMetric formats a metric value: a count, or compact money.
+func formatMetric(value int64, metric string) string {
+	if metric == TrendMetricCount {
+		return fmt.Sprintf("%d", value)
+	}
+	return charm.FormatMoneyCompact(value, "")
+}
+
+// RenderTrend draws the open pipeline per period as horizontal bars, with the
+// change from the previous period and the closed-won total.
+func RenderTrend(points []charm.TrendPoint, metric string) string {
+	var out strings.Builder
+
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	fmt.Fprintf(&out, "  PIPELINE TREND (open %s)\n", metric)
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(points) == 0 {
+		out.WriteString("  No pipeline snapshots yet\n")
+		return out.String()
+	}
+
+	var maxValue int64
+	for _, point := range points {
+		if v := metricOf(point.Snapshot.Open(), metric); v > maxValue {
+			maxValue = v
+		}
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+
+	var prev int64
+	for i, point := range points {
+		open := point.Snapshot.Open()
+		value := metricOf(open, metric)
+		barLength := int(value * 30 / maxValue)
+		bar := strings.Repeat("█", barLength) + strings.Repeat("░", 30-barLength)
+
+		change := ""
+		if i > 0 && prev > 0 {
+			pct := float64(value-prev) * 100 / float64(prev)
+			switch {
+			case pct > 0:
+				change = fmt.Sprintf("▲%.0f%%", pct)
+			case pct < 0:
+				change = fmt.Sprintf("▼%.0f%%", -pct)
+			default:
+				change = "="
+			}
+		}
+		prev = value
+
+		won := point.Snapshot.Stages[charm.StageClosedWon]
+		fmt.Fprintf(&out, "  %-10s %s %8s %6s  %3d open  won %s\n",
+			point.Label, bar, formatMetric(value, metric), change, open.Count, charm.FormatMoneyCompact(won.Amount, ""))
+	}
+
+	return out.String()
+}
+
+// RenderTrendSVG draws the open pipeline per period as stacked bars, one
+// segment per stage.
+func RenderTrendSVG(points []charm.TrendPoint, metric string) string {
+	const (
+		height  = 320
+		top     = 40
+		bottom  = 50
+		left    = 70
+		barSlot = 48
+		barW    = 32
+	)
+	width := left + barSlot*len(points) + 140
+	if width < 400 {
+		width = 400
+	}
+	plotH := height - top - bottom
+
+	var maxValue int64
+	for _, point := range points {
+		if v := metricOf(point.Snapshot.Open(), metric); v > maxValue {
+			maxValue = v
+		}
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height)
+	fmt.Fprintf(&out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&out, `<text x="%d" y="22" font-size="14" font-weight="bold">Pipeline trend (open %s)</text>`+"\n", left, html.EscapeString(metric))
+
+	// Axes with the max and midpoint marked
+	fmt.Fprintf(&out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`+"\n", left, top, left, top+plotH)
+	fmt.Fprintf(&out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`+"\n", left, top+plotH, left+barSlot*len(points), top+plotH)
+	for _, frac := range []int64{1, 2} {
+		y := top + plotH - int(int64(plotH)*frac/2)
+		fmt.Fprintf(&out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#ddd"/>`+"\n", left, y, left+barSlot*len(points), y)
+		fmt.Fprintf(&out, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", left-6, y+4, html.EscapeString(formatMetric(maxValue*frac/2, metric)))
+	}
+
+	for i, point := range points {
+		x := left + barSlot*i + (barSlot-barW)/2
+		y := top + plotH
+		for _, stage := range openStages {
+			v := metricOf(point.Snapshot.Stages[stage], metric)
+			h := int(v * int64(plotH) / maxValue)
+			if h == 0 {
+				continue
+			}
+			y -= h
+			fmt.Fprintf(&out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s %s: %s</title></rect>`+"\n",
+				x, y, barW, h, stageColors[stage], html.EscapeString(point.Label), stage, html.EscapeString(formatMetric(v, metric)))
+		}
+		fmt.Fprintf(&out, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", x+barW/2, top+plotH+16, html.EscapeString(point.Label))
+	}
+
+	// Legend, top stage first to match the stacking
+	legendX := left + barSlot*len(points) + 20
+	for i := range openStages {
+		stage := openStages[len(openStages)-1-i]
+		y := top + i*18
+		fmt.Fprintf(&out, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n", legendX, y, stageColors[stage])
+		fmt.Fprintf(&out, `<text x="%d" y="%d">%s</text>`+"\n", legendX+18, y+10, stage)
+	}
+
+	out.WriteString("</svg>\n")
+	return out.String()
+}
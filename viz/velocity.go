@@ -0,0 +1,53 @@
+// ABOUTME: Pipeline velocity reporting for the terminal
+// ABOUTME: Shows average time in stage, stage conversion, win rate, and stalled deals
+package viz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func RenderPipelineVelocity(metrics *charm.PipelineMetrics) string {
+	var out strings.Builder
+
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	out.WriteString("  PIPELINE VELOCITY\n")
+	out.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	fmt.Fprintf(&out, "  %-14s %7s %7s %9s %10s\n", "STAGE", "ENTERED", "NOW", "AVG DAYS", "CONVERTED")
+	for _, s := range metrics.Stages {
+		avgDays, converted := "-", "-"
+		if s.Advanced+s.Lost > 0 {
+			avgDays = fmt.Sprintf("%.1f", s.AvgDays)
+			converted = fmt.Sprintf("%.0f%%", s.ConversionRate*100)
+		}
+		fmt.Fprintf(&out, "  %-14s %7d %7d %9s %10s\n", s.Stage, s.Entered, s.Current, avgDays, converted)
+	}
+
+	out.WriteString("\n")
+	if metrics.Won+metrics.Lost == 0 {
+		out.WriteString("  No closed deals yet\n")
+	} else {
+		fmt.Fprintf(&out, "  Win rate: %.0f%% (%d won, %d lost)\n", metrics.WinRate*100, metrics.Won, metrics.Lost)
+		if metrics.Won > 0 {
+			fmt.Fprintf(&out, "  Average days to win: %.1f\n", metrics.AvgDaysToClose)
+		}
+	}
+
+	fmt.Fprintf(&out, "\nSTALLED (%d+ DAYS IN STAGE)\n", metrics.StalledDays)
+	if len(metrics.Stalled) == 0 {
+		out.WriteString("  None\n")
+		return out.String()
+	}
+	for _, deal := range metrics.Stalled {
+		name := deal.Title
+		if deal.CompanyName != "" {
+			name += " (" + deal.CompanyName + ")"
+		}
+		fmt.Fprintf(&out, "  %-32s %-14s %4dd %10s\n",
+			truncate(name, 32), deal.Stage, deal.DaysInStage, charm.FormatMoneyRounded(deal.Amount, deal.Currency))
+	}
+	return out.String()
+}
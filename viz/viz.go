@@ -8,6 +8,7 @@ import (
 
 type GraphGenerator struct {
 	client *charm.Client
+	format string // output format; DOT when empty
 }
 
 func NewGraphGenerator(client *charm.Client) *GraphGenerator {
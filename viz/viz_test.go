@@ -1,8 +1,9 @@
 // ABOUTME: Tests for the viz package graph generation
-// ABOUTME: Validates DOT graph output for contacts, companies, and pipelines
+// ABOUTME: Validates DOT graph output for contacts, companies, deals, and pipelines
 package viz
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -118,3 +119,47 @@ func TestGeneratePipelineGraph(t *testing.T) {
 		t.Errorf("Expected non-empty graph, got %d bytes", len(graph))
 	}
 }
+
+func TestGenerateDealGraph(t *testing.T) {
+	client := charm.NewTestClient(t)
+
+	// Create test data
+	company := &charm.Company{ID: uuid.New(), Name: "Test Corp", Domain: "test.com", Industry: "Tech"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("Failed to create company: %v", err)
+	}
+
+	contact1 := &charm.Contact{ID: uuid.New(), Name: "Alice", Email: "alice@test.com", CompanyID: &company.ID, CompanyName: company.Name}
+	if err := client.CreateContact(contact1); err != nil {
+		t.Fatalf("Failed to create contact1: %v", err)
+	}
+
+	contact2 := &charm.Contact{ID: uuid.New(), Name: "Bob", Email: "bob@test.com", CompanyID: &company.ID, CompanyName: company.Name}
+	if err := client.CreateContact(contact2); err != nil {
+		t.Fatalf("Failed to create contact2: %v", err)
+	}
+
+	deal := &charm.Deal{ID: uuid.New(), CompanyID: company.ID, CompanyName: company.Name, Title: "Deal 1", Amount: 100000, Currency: "USD", Stage: "prospecting", ContactID: &contact1.ID}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("Failed to create deal: %v", err)
+	}
+
+	if err := client.SaveDealContact(&charm.DealContact{DealID: deal.ID, ContactID: contact1.ID, Role: charm.DealRoleChampion}); err != nil {
+		t.Fatalf("Failed to add deal contact: %v", err)
+	}
+
+	rel := &charm.Relationship{ID: uuid.New(), ContactID1: contact1.ID, ContactID2: contact2.ID, RelationshipType: "colleague"}
+	if err := client.CreateRelationship(rel); err != nil {
+		t.Fatalf("Failed to create relationship: %v", err)
+	}
+
+	generator := NewGraphGenerator(client)
+
+	graph, err := generator.GenerateDealGraph(deal.ID, true)
+	if err != nil {
+		t.Fatalf("GenerateDealGraph failed: %v", err)
+	}
+	if !strings.Contains(graph, "champion") || !strings.Contains(graph, "Bob") {
+		t.Errorf("Expected roles and company contacts in graph, got %s", graph)
+	}
+}
@@ -0,0 +1,510 @@
+// ABOUTME: JSON REST API for contacts, companies, deals, and interactions under /api/v1
+// ABOUTME: Authenticated with bearer tokens from `pagen web token`, separately from UI logins
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// apiPrefix is where the REST API is served.
+const apiPrefix = "/api/v1/"
+
+// REST API page sizes.
+const (
+	defaultAPILimit = 50
+	maxAPILimit     = 200
+)
+
+// apiList is the JSON body of every list endpoint.
+type apiList[T any] struct {
+	Data       []T  `json:"data"`
+	Offset     int  `json:"offset"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	HasMore    bool `json:"has_more"`
+	NextOffset int  `json:"next_offset,omitempty"`
+}
+
+// apiErrorBody is the JSON body of every error response.
+type apiErrorBody struct {
+	Error      string `json:"error"`
+	ExistingID string `json:"existing_id,omitempty"` // the contact a duplicate email belongs to
+}
+
+// requireAPIToken admits requests with a valid "Authorization: Bearer" token.
+// UI sessions don't count: the API is for scripts, and ignoring cookies keeps
+// it safe from cross-site requests without CSRF tokens.
+func (s *Server) requireAPIToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token, err := s.client.FindAPIToken(strings.TrimSpace(secret))
+		if err != nil {
+			log.Printf("Failed to look up API token: %v", err)
+			writeAPIError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !ok || token == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pagen"`)
+			writeAPIError(w, http.StatusUnauthorized, "missing or invalid API token (create one with `pagen web token create`)")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleAPI routes /api/v1/{resource} and /api/v1/{resource}/{id}.
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	resource, idStr, hasID := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+	var id uuid.UUID
+	if hasID {
+		var err error
+		if id, err = uuid.Parse(idStr); err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid ID: "+idStr)
+			return
+		}
+	}
+
+	var handler func(http.ResponseWriter, *http.Request, *uuid.UUID)
+	switch resource {
+	case "contacts":
+		handler = s.apiContacts
+	case "companies":
+		handler = s.apiCompanies
+	case "deals":
+		handler = s.apiDeals
+	case "interactions":
+		handler = s.apiInteractions
+	default:
+		writeAPIError(w, http.StatusNotFound, "unknown resource (use contacts, companies, deals, or interactions)")
+		return
+	}
+
+	allowed := []string{http.MethodGet, http.MethodPost}
+	if hasID {
+		allowed = []string{http.MethodGet, http.MethodPatch, http.MethodDelete}
+	}
+	if !slices.Contains(allowed, r.Method) {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if hasID {
+		handler(w, r, &id)
+	} else {
+		handler(w, r, nil)
+	}
+}
+
+func (s *Server) apiContacts(w http.ResponseWriter, r *http.Request, id *uuid.UUID) {
+	switch {
+	case id == nil && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		contacts, err := s.apiClient.ListContacts(&charm.ContactFilter{
+			Query:           query.Get("q"),
+			Tag:             query.Get("tag"),
+			IncludeArchived: query.Get("include_archived") == "true",
+		})
+		writeAPIList(w, r, contacts, err)
+
+	case id == nil:
+		var contact charm.Contact
+		if !decodeAPIBody(w, r, &contact) {
+			return
+		}
+		contact.ID = uuid.Nil
+		if err := s.prepareAPIContact(&contact); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Under the "return" duplicate policy an existing contact comes back with 200
+		saved, existed, err := s.apiClient.CreateContactUnique(&contact, s.apiClient.DuplicateEmailPolicy(charm.EntryAPI))
+		var dup *charm.DuplicateEmailError
+		switch {
+		case errors.As(err, &dup):
+			writeAPIJSON(w, http.StatusConflict, apiErrorBody{Error: err.Error(), ExistingID: dup.Existing.ID.String()})
+		case err != nil:
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+		case existed:
+			writeAPIJSON(w, http.StatusOK, saved)
+		default:
+			writeAPIJSON(w, http.StatusCreated, saved)
+		}
+
+	default:
+		contact, err := s.apiClient.GetContact(*id)
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, "contact not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeAPIJSON(w, http.StatusOK, contact)
+		case http.MethodPatch:
+			created := contact.CreatedAt
+			if !decodeAPIBody(w, r, contact) {
+				return
+			}
+			contact.ID, contact.CreatedAt = *id, created
+			if err := s.prepareAPIContact(contact); err != nil {
+				writeAPIError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			// The body's version, else the one just read, is the version the edit is based on
+			writeAPISaved(w, contact, s.apiClient.UpdateContactIfUnchanged(contact))
+		case http.MethodDelete:
+			writeAPIDeleted(w, s.apiClient.DeleteContact(*id))
+		}
+	}
+}
+
+// prepareAPIContact checks a contact from a request body and fills in its
+// company name.
+func (s *Server) prepareAPIContact(contact *charm.Contact) error {
+	contact.Name = strings.TrimSpace(contact.Name)
+	if contact.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	contact.CompanyName = ""
+	if contact.CompanyID != nil {
+		company, err := s.apiClient.GetCompany(*contact.CompanyID)
+		if err != nil {
+			return fmt.Errorf("unknown company_id: %s", contact.CompanyID)
+		}
+		contact.CompanyName = company.Name
+	}
+	return nil
+}
+
+func (s *Server) apiCompanies(w http.ResponseWriter, r *http.Request, id *uuid.UUID) {
+	switch {
+	case id == nil && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		companies, err := s.apiClient.ListCompanies(&charm.CompanyFilter{Query: query.Get("q"), Tag: query.Get("tag")})
+		writeAPIList(w, r, companies, err)
+
+	case id == nil:
+		var company charm.Company
+		if !decodeAPIBody(w, r, &company) {
+			return
+		}
+		company.ID = uuid.Nil
+		if company.Name = strings.TrimSpace(company.Name); company.Name == "" {
+			writeAPIError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if err := s.apiClient.CreateCompany(&company); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusCreated, &company)
+
+	default:
+		company, err := s.apiClient.GetCompany(*id)
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, "company not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeAPIJSON(w, http.StatusOK, company)
+		case http.MethodPatch:
+			created := company.CreatedAt
+			if !decodeAPIBody(w, r, company) {
+				return
+			}
+			company.ID, company.CreatedAt = *id, created
+			if company.Name = strings.TrimSpace(company.Name); company.Name == "" {
+				writeAPIError(w, http.StatusBadRequest, "name is required")
+				return
+			}
+			writeAPISaved(w, company, s.apiClient.UpdateCompanyIfUnchanged(company))
+		case http.MethodDelete:
+			writeAPIDeleted(w, s.apiClient.DeleteCompany(*id))
+		}
+	}
+}
+
+func (s *Server) apiDeals(w http.ResponseWriter, r *http.Request, id *uuid.UUID) {
+	switch {
+	case id == nil && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		deals, err := s.apiClient.ListDeals(&charm.DealFilter{Query: query.Get("q"), Stage: query.Get("stage"), Tag: query.Get("tag")})
+		writeAPIList(w, r, deals, err)
+
+	case id == nil:
+		deal := charm.Deal{Stage: charm.StageProspecting, Currency: "USD"}
+		if !decodeAPIBody(w, r, &deal) {
+			return
+		}
+		deal.ID = uuid.Nil
+		if err := s.prepareAPIDeal(&deal); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.apiClient.CreateDeal(&deal); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusCreated, &deal)
+
+	default:
+		deal, err := s.apiClient.GetDeal(*id)
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, "deal not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeAPIJSON(w, http.StatusOK, deal)
+		case http.MethodPatch:
+			created := deal.CreatedAt
+			if !decodeAPIBody(w, r, deal) {
+				return
+			}
+			deal.ID, deal.CreatedAt = *id, created
+			if err := s.prepareAPIDeal(deal); err != nil {
+				writeAPIError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if err := s.apiClient.UpdateDealIfUnchanged(deal); charm.CodeOf(err) == charm.CodeConflict {
+				writeAPIError(w, http.StatusConflict, err.Error())
+				return
+			} else if err != nil {
+				writeAPIError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeAPIJSON(w, http.StatusOK, deal)
+		case http.MethodDelete:
+			writeAPIDeleted(w, s.apiClient.DeleteDeal(*id))
+		}
+	}
+}
+
+// prepareAPIDeal checks a deal from a request body and fills in its company
+// and contact names.
+func (s *Server) prepareAPIDeal(deal *charm.Deal) error {
+	deal.Title = strings.TrimSpace(deal.Title)
+	if deal.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	valid := false
+	for _, stage := range charm.DealStages {
+		valid = valid || deal.Stage == stage
+	}
+	if !valid {
+		return fmt.Errorf("invalid stage: %s (valid: %s)", deal.Stage, strings.Join(charm.DealStages, ", "))
+	}
+
+	company, err := s.apiClient.GetCompany(deal.CompanyID)
+	if err != nil {
+		return fmt.Errorf("company_id is required and must be an existing company")
+	}
+	deal.CompanyName = company.Name
+	deal.ContactName = ""
+	if deal.ContactID != nil {
+		contact, err := s.apiClient.GetContact(*deal.ContactID)
+		if err != nil {
+			return fmt.Errorf("unknown contact_id: %s", deal.ContactID)
+		}
+		deal.ContactName = contact.Name
+	}
+	return nil
+}
+
+func (s *Server) apiInteractions(w http.ResponseWriter, r *http.Request, id *uuid.UUID) {
+	switch {
+	case id == nil && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		filter := &charm.InteractionFilter{InteractionType: query.Get("type")}
+		if v := query.Get("contact_id"); v != "" {
+			contactID, err := uuid.Parse(v)
+			if err != nil {
+				writeAPIError(w, http.StatusBadRequest, "invalid contact_id: "+v)
+				return
+			}
+			filter.ContactID = &contactID
+		}
+		if v := query.Get("since"); v != "" {
+			since, err := parseAPITime(v)
+			if err != nil {
+				writeAPIError(w, http.StatusBadRequest, "invalid since: "+v)
+				return
+			}
+			filter.Since = &since
+		}
+		logs, err := s.apiClient.ListInteractionLogs(filter)
+		writeAPIList(w, r, logs, err)
+
+	case id == nil:
+		interaction := charm.InteractionLog{InteractionType: charm.InteractionMeeting}
+		if !decodeAPIBody(w, r, &interaction) {
+			return
+		}
+		interaction.ID = uuid.Nil
+		contact, err := s.prepareAPIInteraction(&interaction)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.apiClient.LogFollowup(&interaction); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Keep last contact and follow-up cadence current, as logging from the CLI does
+		if contact.LastContactedAt == nil || interaction.Timestamp.After(*contact.LastContactedAt) {
+			contact.LastContactedAt = &interaction.Timestamp
+			if err := s.apiClient.UpdateContactIfUnchanged(contact); err != nil {
+				writeAPIError(w, apiErrorStatus(err), err.Error())
+				return
+			}
+		}
+		if err := s.apiClient.UpdateCadenceAfterInteraction(contact.ID, interaction.Timestamp); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusCreated, &interaction)
+
+	default:
+		interaction, err := s.apiClient.GetInteractionLog(*id)
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, "interaction not found")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeAPIJSON(w, http.StatusOK, interaction)
+		case http.MethodPatch:
+			if !decodeAPIBody(w, r, interaction) {
+				return
+			}
+			interaction.ID = *id
+			if _, err := s.prepareAPIInteraction(interaction); err != nil {
+				writeAPIError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeAPISaved(w, interaction, s.apiClient.UpdateInteractionLog(interaction))
+		case http.MethodDelete:
+			writeAPIDeleted(w, s.apiClient.DeleteInteractionLog(*id))
+		}
+	}
+}
+
+// prepareAPIInteraction checks an interaction from a request body, fills in
+// its contact name, and returns the contact.
+func (s *Server) prepareAPIInteraction(interaction *charm.InteractionLog) (*charm.Contact, error) {
+	contact, err := s.apiClient.GetContact(interaction.ContactID)
+	if err != nil {
+		return nil, fmt.Errorf("contact_id is required and must be an existing contact")
+	}
+	interaction.ContactName = contact.Name
+	if interaction.InteractionType == "" {
+		return nil, fmt.Errorf("interaction_type is required")
+	}
+	if _, err := charm.NormalizeOutcome(interaction.Outcome); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// parseAPITime accepts RFC 3339 timestamps and YYYY-MM-DD dates.
+func parseAPITime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// decodeAPIBody decodes a JSON request body into v, over any fields it
+// already has, and reports a 400 when the body is not valid.
+func decodeAPIBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeAPIList writes one page of items. Query parameters offset and limit
+// pick the page.
+func writeAPIList[T any](w http.ResponseWriter, r *http.Request, items []T, err error) {
+	if err != nil {
+		writeAPIError(w, listErrorStatus(err), err.Error())
+		return
+	}
+	query := r.URL.Query()
+	offset, err := nonNegativeParam(query.Get("offset"), 0)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+	limit, err := nonNegativeParam(query.Get("limit"), defaultAPILimit)
+	if err != nil || limit == 0 {
+		writeAPIError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	limit = min(limit, maxAPILimit)
+
+	page := apiList[T]{Data: []T{}, Offset: offset, Limit: limit, Total: len(items)}
+	if offset < len(items) {
+		end := min(offset+limit, len(items))
+		page.Data = items[offset:end]
+		if end < len(items) {
+			page.HasMore = true
+			page.NextOffset = end
+		}
+	}
+	writeAPIJSON(w, http.StatusOK, page)
+}
+
+// writeAPISaved writes v after an update, or the update's error.
+func writeAPISaved(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		writeAPIError(w, apiErrorStatus(err), err.Error())
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, v)
+}
+
+// apiErrorStatus answers 409 when a record changed after the version an
+// update was based on, and 500 otherwise.
+func apiErrorStatus(err error) int {
+	if charm.CodeOf(err) == charm.CodeConflict {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// writeAPIDeleted answers a delete with 204, or the delete's error.
+func writeAPIDeleted(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, apiErrorBody{Error: message})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing API response: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// ABOUTME: Tests for the REST API
+// ABOUTME: Covers bearer token auth alongside UI login, CRUD, pagination, validation, and interaction side effects
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRESTAPI(t *testing.T) {
+	client := charm.NewTestClient(t)
+	_, secret, err := client.CreateAPIToken("scripts")
+	if err != nil {
+		t.Fatalf("CreateAPIToken failed: %v", err)
+	}
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	auth, err := newAuthenticator(&charm.WebAuthConfig{Username: "harper", PasswordHash: string(hash)})
+	if err != nil {
+		t.Fatalf("newAuthenticator failed: %v", err)
+	}
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	s.auth = auth
+	handler := s.requireAuth(s.csrfProtect(s.routes()))
+
+	call := func(method, path, token, body string, out any) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(rec, req)
+		if out != nil && rec.Body.Len() > 0 {
+			if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+				t.Fatalf("%s %s: bad JSON %q: %v", method, path, rec.Body.String(), err)
+			}
+		}
+		return rec.Code
+	}
+
+	if code := call(http.MethodGet, "/api/v1/contacts", "", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", code)
+	}
+	if code := call(http.MethodGet, "/api/v1/contacts", "pagen_wrong", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong token, got %d", code)
+	}
+
+	// Writes need no CSRF token
+	var company charm.Company
+	if code := call(http.MethodPost, "/api/v1/companies", secret, `{"name":"Acme"}`, &company); code != http.StatusCreated {
+		t.Fatalf("expected 201 creating a company, got %d", code)
+	}
+	var ada charm.Contact
+	if code := call(http.MethodPost, "/api/v1/contacts", secret, `{"name":"Ada","email":"ada@acme.com","company_id":"`+company.ID.String()+`"}`, &ada); code != http.StatusCreated {
+		t.Fatalf("expected 201 creating a contact, got %d", code)
+	}
+	if ada.CompanyName != "Acme" {
+		t.Errorf("expected the company name filled in, got %q", ada.CompanyName)
+	}
+	for _, name := range []string{"Bea", "Cy"} {
+		if code := call(http.MethodPost, "/api/v1/contacts", secret, `{"name":"`+name+`"}`, nil); code != http.StatusCreated {
+			t.Fatalf("expected 201 creating %s, got %d", name, code)
+		}
+	}
+	var apiErr apiErrorBody
+	if code := call(http.MethodPost, "/api/v1/contacts", secret, `{"email":"x@y.z"}`, &apiErr); code != http.StatusBadRequest || apiErr.Error != "name is required" {
+		t.Errorf("expected 400 without a name, got %d %+v", code, apiErr)
+	}
+	if code := call(http.MethodPost, "/api/v1/contacts", secret, `{"name":"Dee","nickname":"D"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown field, got %d", code)
+	}
+
+	client.Config().DuplicateEmails = map[string]string{charm.EntryAPI: charm.DuplicateEmailReject}
+	if code := call(http.MethodPost, "/api/v1/contacts", secret, `{"name":"Ada L","email":"ADA@acme.com"}`, &apiErr); code != http.StatusConflict || apiErr.ExistingID != ada.ID.String() {
+		t.Errorf("expected 409 pointing at Ada for a duplicate email, got %d %+v", code, apiErr)
+	}
+
+	var page apiList[charm.Contact]
+	call(http.MethodGet, "/api/v1/contacts?limit=2", secret, "", &page)
+	if page.Total != 3 || len(page.Data) != 2 || !page.HasMore || page.NextOffset != 2 || page.Data[0].Name != "Ada" {
+		t.Errorf("unexpected first page: %+v", page)
+	}
+	call(http.MethodGet, "/api/v1/contacts?q=company:acme", secret, "", &page)
+	if page.Total != 1 || page.Data[0].ID != ada.ID {
+		t.Errorf("expected the query language to filter, got %+v", page)
+	}
+
+	var patched charm.Contact
+	if code := call(http.MethodPatch, "/api/v1/contacts/"+ada.ID.String(), secret, `{"title":"CTO"}`, &patched); code != http.StatusOK {
+		t.Fatalf("expected 200 patching, got %d", code)
+	}
+	if patched.Title != "CTO" || patched.Email != "ada@acme.com" || patched.ID != ada.ID {
+		t.Errorf("expected a partial update, got %+v", patched)
+	}
+	// A version older than the stored one means the edit is based on an outdated read
+	outdated := strconv.FormatInt(patched.Version-1, 10)
+	if code := call(http.MethodPatch, "/api/v1/contacts/"+ada.ID.String(), secret, `{"title":"CEO","version":`+outdated+`}`, &apiErr); code != http.StatusConflict {
+		t.Errorf("expected 409 patching an outdated version, got %d %+v", code, apiErr)
+	}
+	if current, _ := client.GetContact(ada.ID); current.Title != "CTO" {
+		t.Errorf("expected the conflicting patch not applied, got title %q", current.Title)
+	}
+
+	var deal charm.Deal
+	if code := call(http.MethodPost, "/api/v1/deals", secret, `{"title":"Pilot"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a deal without a company, got %d", code)
+	}
+	if code := call(http.MethodPost, "/api/v1/deals", secret, `{"title":"Pilot","company_id":"`+company.ID.String()+`","contact_id":"`+ada.ID.String()+`"}`, &deal); code != http.StatusCreated {
+		t.Fatalf("expected 201 creating a deal, got %d", code)
+	}
+	if deal.Stage != charm.StageProspecting || deal.CompanyName != "Acme" || deal.ContactName != "Ada" {
+		t.Errorf("unexpected deal: %+v", deal)
+	}
+
+	var interaction charm.InteractionLog
+	if code := call(http.MethodPost, "/api/v1/interactions", secret, `{"contact_id":"`+ada.ID.String()+`","interaction_type":"call","notes":"Catch up"}`, &interaction); code != http.StatusCreated {
+		t.Fatalf("expected 201 logging an interaction, got %d", code)
+	}
+	if updated, _ := client.GetContact(ada.ID); updated.LastContactedAt == nil {
+		t.Error("expected logging an interaction to set last contacted")
+	}
+	var logs apiList[charm.InteractionLog]
+	call(http.MethodGet, "/api/v1/interactions?contact_id="+ada.ID.String(), secret, "", &logs)
+	if logs.Total != 1 || logs.Data[0].ID != interaction.ID {
+		t.Errorf("unexpected interactions: %+v", logs)
+	}
+
+	if code := call(http.MethodDelete, "/api/v1/deals/"+deal.ID.String(), secret, "", nil); code != http.StatusNoContent {
+		t.Errorf("expected 204 deleting, got %d", code)
+	}
+	if code := call(http.MethodGet, "/api/v1/deals/"+deal.ID.String(), secret, "", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", code)
+	}
+	if code := call(http.MethodPut, "/api/v1/contacts/"+ada.ID.String(), secret, "{}", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for PUT, got %d", code)
+	}
+	if code := call(http.MethodGet, "/api/v1/widgets", secret, "", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown resource, got %d", code)
+	}
+
+	// Revoked tokens stop working right away
+	if _, err := client.RevokeAPIToken("scripts"); err != nil {
+		t.Fatalf("RevokeAPIToken failed: %v", err)
+	}
+	if code := call(http.MethodGet, "/api/v1/contacts", secret, "", nil); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 after revoking, got %d", code)
+	}
+}
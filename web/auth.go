@@ -0,0 +1,381 @@
+// ABOUTME: Web UI authentication with a bcrypt password (HTTP Basic) or an OIDC provider
+// ABOUTME: Successful logins get a signed session cookie; email tracking endpoints stay public
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	sessionCookie   = "pagen_session"
+	oidcStateCookie = "pagen_oidc_state"
+	sessionLifetime = 7 * 24 * time.Hour
+)
+
+// publicPaths are served without login: tracking pixels and links are opened by
+// email recipients, the login flow itself must be reachable, and the REST API
+// checks its own bearer tokens.
+var publicPaths = []string{"/t/o/", "/t/c/", "/auth/", apiPrefix}
+
+// authenticator checks web UI logins.
+type authenticator struct {
+	cfg    charm.WebAuthConfig
+	secret []byte
+
+	// OIDC endpoints are discovered on first login
+	mu          sync.Mutex
+	oauth       *oauth2.Config
+	userinfoURL string
+}
+
+// newAuthenticator returns nil when no login method is configured.
+func newAuthenticator(cfg *charm.WebAuthConfig) (*authenticator, error) {
+	if !cfg.PasswordEnabled() && !cfg.OIDCEnabled() {
+		return nil, nil
+	}
+
+	a := &authenticator{cfg: *cfg}
+	if cfg.SessionSecret != "" {
+		secret, err := hex.DecodeString(cfg.SessionSecret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid web_auth session_secret: %w", err)
+		}
+		a.secret = secret
+	} else {
+		// Sessions won't survive a restart without a configured secret
+		a.secret = make([]byte, 32)
+		if _, err := rand.Read(a.secret); err != nil {
+			return nil, fmt.Errorf("failed to generate session secret: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// requireAuth lets requests through with a valid session or password, and otherwise
+// starts OIDC login or asks for HTTP Basic credentials.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	if s.auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range publicPaths {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if _, ok := s.auth.session(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Share pages are for people without a login; the token is the credential
+		if s.sharing && strings.HasPrefix(r.URL.Path, sharePathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Calendar apps subscribe to the follow-up feed with its token
+		if r.URL.Path == followupFeedPath && s.auth.validFeedToken(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if user, pass, ok := r.BasicAuth(); ok && s.auth.checkPassword(user, pass) {
+			s.setSession(w, r, user)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Browsers navigating to a page go straight to the identity provider
+		if s.auth.cfg.OIDCEnabled() && !s.auth.cfg.PasswordEnabled() && r.Method == http.MethodGet && r.Header.Get("HX-Request") == "" {
+			http.Redirect(w, r, s.url("/auth/login"), http.StatusFound)
+			return
+		}
+
+		if s.auth.cfg.PasswordEnabled() {
+			w.Header().Set("WWW-Authenticate", `Basic realm="pagen", charset="UTF-8"`)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusUnauthorized)
+		if s.auth.cfg.OIDCEnabled() {
+			_, _ = fmt.Fprintf(w, `<p>Login required. <a href="%s">Sign in with SSO</a></p>`, template.HTMLEscapeString(s.url("/auth/login")))
+		} else {
+			_, _ = fmt.Fprint(w, "<p>Login required.</p>")
+		}
+	})
+}
+
+// checkPassword compares credentials against the configured username and bcrypt hash.
+func (a *authenticator) checkPassword(user, pass string) bool {
+	if !a.cfg.PasswordEnabled() {
+		return false
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(user), []byte(a.cfg.Username)) == 1
+	passOK := bcrypt.CompareHashAndPassword([]byte(a.cfg.PasswordHash), []byte(pass)) == nil
+	return userOK && passOK
+}
+
+// sign returns the session cookie value for user, valid until expires.
+func (a *authenticator) sign(user string, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(user + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// session returns the logged-in user from a valid, unexpired session cookie.
+func (a *authenticator) session(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+	payload, sig, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return "", false
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	want := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return "", false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", false
+	}
+	user, expStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return "", false
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return "", false
+	}
+	return user, true
+}
+
+func (s *Server) setSession(w http.ResponseWriter, r *http.Request, user string) {
+	expires := time.Now().Add(sessionLifetime)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    s.auth.sign(user, expires),
+		Path:     s.url("/"),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// handleLogout clears the session cookie.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     s.url("/"),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	_, _ = fmt.Fprint(w, "Signed out")
+}
+
+// handleOIDCLogin redirects to the identity provider.
+func (s *Server) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	oauth, err := s.oidcConfig(r)
+	if err != nil {
+		log.Printf("OIDC discovery failed: %v", err)
+		http.Error(w, "login provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		http.Error(w, "failed to start login", http.StatusInternalServerError)
+		return
+	}
+	state := hex.EncodeToString(stateBytes)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     s.url("/auth/"),
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, oauth.AuthCodeURL(state), http.StatusFound)
+}
+
+// handleOIDCCallback exchanges the code, checks the user's email, and starts a session.
+func (s *Server) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	stateCookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || r.URL.Query().Get("state") == "" ||
+		subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	oauth, err := s.oidcConfig(r)
+	if err != nil {
+		http.Error(w, "login provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	token, err := oauth.Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		log.Printf("OIDC code exchange failed: %v", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	email, err := s.auth.fetchEmail(ctx, oauth, token)
+	if err != nil {
+		log.Printf("OIDC userinfo failed: %v", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+	if !s.auth.emailAllowed(email) {
+		log.Printf("OIDC login rejected for %s", email)
+		http.Error(w, "this account is not allowed", http.StatusForbidden)
+		return
+	}
+
+	s.setSession(w, r, email)
+	http.Redirect(w, r, s.url("/"), http.StatusFound)
+}
+
+// oidcConfig discovers the provider endpoints once and returns the OAuth2 config.
+func (s *Server) oidcConfig(r *http.Request) (*oauth2.Config, error) {
+	a := s.auth
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.oauth != nil {
+		return a.oauth, nil
+	}
+
+	discoveryURL := strings.TrimRight(a.cfg.OIDCIssuer, "/") + "/.well-known/openid-configuration"
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery returned %s", resp.Status)
+	}
+
+	var discovery struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("discovery document is missing endpoints")
+	}
+
+	a.oauth = &oauth2.Config{
+		ClientID:     a.cfg.OIDCClientID,
+		ClientSecret: a.cfg.OIDCClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+		RedirectURL: s.externalURL(r, "/auth/callback"),
+		Scopes:      []string{"openid", "email"},
+	}
+	a.userinfoURL = discovery.UserinfoEndpoint
+	return a.oauth, nil
+}
+
+// fetchEmail reads the verified email address from the userinfo endpoint.
+func (a *authenticator) fetchEmail(ctx context.Context, oauth *oauth2.Config, token *oauth2.Token) (string, error) {
+	resp, err := oauth.Client(ctx, token).Get(a.userinfoURL)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("userinfo returned %s", resp.Status)
+	}
+
+	var info struct {
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to decode userinfo: %w", err)
+	}
+	if info.Email == "" {
+		return "", fmt.Errorf("no email in userinfo")
+	}
+	if info.EmailVerified != nil && !*info.EmailVerified {
+		return "", fmt.Errorf("email %s is not verified", info.Email)
+	}
+	return info.Email, nil
+}
+
+// emailAllowed matches an address against the allow list of addresses and "@domain" entries.
+func (a *authenticator) emailAllowed(email string) bool {
+	email = strings.ToLower(email)
+	for _, allowed := range a.cfg.OIDCAllowedEmails {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == email || (strings.HasPrefix(allowed, "@") && strings.HasSuffix(email, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalURL builds an absolute URL for path, preferring the configured base URL.
+func (s *Server) externalURL(r *http.Request, path string) string {
+	if s.baseURL != "" {
+		if u, err := url.Parse(s.baseURL); err == nil && u.Scheme != "" && u.Host != "" {
+			return u.Scheme + "://" + u.Host + s.url(path)
+		}
+	}
+	scheme := "http"
+	if isHTTPS(r) {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + s.url(path)
+}
+
+// isHTTPS reports whether the client connected over TLS, directly or via a proxy.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,189 @@
+// ABOUTME: Tests for web UI login
+// ABOUTME: Covers password login with session cookies, public paths, and an OIDC flow against a fake provider
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newAuthTestServer(t *testing.T, cfg *charm.WebAuthConfig) (*Server, http.Handler) {
+	t.Helper()
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("newAuthenticator failed: %v", err)
+	}
+	s := &Server{auth: auth}
+	handler := s.requireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	return s, handler
+}
+
+func TestPasswordLogin(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, handler := newAuthTestServer(t, &charm.WebAuthConfig{Username: "harper", PasswordHash: string(hash)})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deals", nil))
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected 401 with Basic challenge, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/deals", nil)
+	req.SetBasicAuth("harper", "wrong")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected wrong password to fail, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/deals", nil)
+	req.SetBasicAuth("harper", "hunter2")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected login to succeed, got %d", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie || !cookies[0].HttpOnly {
+		t.Fatalf("expected an HttpOnly session cookie, got %+v", cookies)
+	}
+
+	// The session cookie alone is enough afterwards
+	req = httptest.NewRequest(http.MethodGet, "/contacts", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected session to authenticate, got %d", rec.Code)
+	}
+
+	// A tampered cookie is rejected
+	req = httptest.NewRequest(http.MethodGet, "/contacts", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookie, Value: cookies[0].Value + "x"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected tampered session to fail, got %d", rec.Code)
+	}
+
+	// Tracking endpoints stay public
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/t/o/abc.gif", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected tracking pixel to be public, got %d", rec.Code)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+	s, _ := newAuthTestServer(t, &charm.WebAuthConfig{Username: "u", PasswordHash: string(hash)})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookie, Value: s.auth.sign("u", time.Now().Add(-time.Minute))})
+	if _, ok := s.auth.session(req); ok {
+		t.Error("expected expired session to be rejected")
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	var provider *httptest.Server
+	provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"authorization_endpoint": provider.URL + "/authorize",
+				"token_endpoint":         provider.URL + "/token",
+				"userinfo_endpoint":      provider.URL + "/userinfo",
+			})
+		case "/token":
+			_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "tok", "token_type": "Bearer"})
+		case "/userinfo":
+			if r.Header.Get("Authorization") != "Bearer tok" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"email": "harper@example.com", "email_verified": true})
+		}
+	}))
+	defer provider.Close()
+
+	s, _ := newAuthTestServer(t, &charm.WebAuthConfig{
+		OIDCIssuer:        provider.URL,
+		OIDCClientID:      "pagen",
+		OIDCAllowedEmails: []string{"@example.com"},
+	})
+	handler := s.requireAuth(s.routes())
+
+	// Unauthenticated page loads go to the provider
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deals", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/auth/login" {
+		t.Fatalf("expected redirect to /auth/login, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://crm.local/auth/login", nil))
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil || !strings.HasPrefix(location.String(), provider.URL+"/authorize") {
+		t.Fatalf("expected redirect to provider, got %q", rec.Header().Get("Location"))
+	}
+	if location.Query().Get("redirect_uri") != "http://crm.local/auth/callback" {
+		t.Errorf("unexpected redirect_uri %q", location.Query().Get("redirect_uri"))
+	}
+	state := location.Query().Get("state")
+	stateCookie := rec.Result().Cookies()[0]
+
+	// A callback without the matching state cookie is rejected
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/callback?code=c&state="+state, nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected missing state cookie to fail, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/callback?code=c&state="+state, nil)
+	req.AddCookie(stateCookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected callback to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var session *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sessionCookie {
+			session = c
+		}
+	}
+	if session == nil {
+		t.Fatal("expected session cookie after callback")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(session)
+	if user, ok := s.auth.session(req); !ok || user != "harper@example.com" {
+		t.Errorf("expected session for harper@example.com, got %q %v", user, ok)
+	}
+
+	// Accounts outside the allow list are refused
+	s.auth.cfg.OIDCAllowedEmails = []string{"someone@else.com"}
+	req = httptest.NewRequest(http.MethodGet, "/auth/callback?code=c&state="+state, nil)
+	req.AddCookie(stateCookie)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected disallowed email to be refused, got %d", rec.Code)
+	}
+}
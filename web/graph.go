@@ -0,0 +1,264 @@
+// ABOUTME: Relationship graph explorer: the JSON feed of contacts, companies, and the links between them
+// ABOUTME: Serves a whole-network overview or the neighborhood of one node, filtered by relationship type
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// Graph explorer limits for GET /api/graph.
+const (
+	maxGraphDepth = 3
+	maxGraphNodes = 250 // the overview keeps the best-connected nodes
+)
+
+// Edge types besides relationship types.
+const (
+	graphWorksAt     = "works_at" // a contact and their company
+	graphUntypedLink = "related"  // a relationship with no type
+)
+
+// graphNode is a contact or company in the explorer. Degree counts its edges
+// in the whole filtered graph, not just the ones returned.
+type graphNode struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"` // contact or company
+	Label  string `json:"label"`
+	Detail string `json:"detail,omitempty"` // job title, or industry
+	URL    string `json:"url"`
+	Degree int    `json:"degree"`
+}
+
+// graphEdge links two node IDs. Type is a relationship type or works_at.
+type graphEdge struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Type     string `json:"type"`
+	Strength string `json:"strength,omitempty"`
+}
+
+// graphPayload is the JSON body of GET /api/graph.
+type graphPayload struct {
+	Center    string      `json:"center,omitempty"`
+	Depth     int         `json:"depth,omitempty"`
+	Nodes     []graphNode `json:"nodes"`
+	Edges     []graphEdge `json:"edges"`
+	Types     []string    `json:"types"` // every edge type, for the filter
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+// handleGraphAPI serves GET /api/graph. Query parameters: center (a contact
+// or company ID) and depth for its neighborhood, otherwise an overview of
+// everything connected; type (comma-separated edge types) to keep only those.
+func (s *Server) handleGraphAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+
+	var center uuid.UUID
+	if v := query.Get("center"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid center", http.StatusBadRequest)
+			return
+		}
+		center = id
+	}
+	depth, err := nonNegativeParam(query.Get("depth"), 1)
+	if err != nil || depth == 0 {
+		http.Error(w, "Invalid depth", http.StatusBadRequest)
+		return
+	}
+	depth = min(depth, maxGraphDepth)
+
+	types := make(map[string]bool)
+	for _, t := range strings.Split(query.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[t] = true
+		}
+	}
+
+	graph, err := s.relationshipGraph(types)
+	if err != nil {
+		http.Error(w, err.Error(), listErrorStatus(err))
+		return
+	}
+
+	var payload *graphPayload
+	if center != uuid.Nil {
+		if _, ok := graph.nodes[center.String()]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		payload = graph.neighborhood(center.String(), depth)
+	} else {
+		payload = graph.overview(maxGraphNodes)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("Error writing graph: %v", err)
+	}
+}
+
+// relationshipGraph holds every non-archived contact and company and the
+// edges between them that pass the type filter.
+type relationshipGraph struct {
+	nodes map[string]*graphNode
+	edges []graphEdge
+	adj   map[string][]int // node ID to indexes into edges
+	types []string
+}
+
+// relationshipGraph loads the graph, keeping only edges whose type is in
+// types when it isn't empty.
+func (s *Server) relationshipGraph(types map[string]bool) (*relationshipGraph, error) {
+	contacts, err := s.client.ListContacts(&charm.ContactFilter{})
+	if err != nil {
+		return nil, err
+	}
+	companies, err := s.client.ListCompanies(&charm.CompanyFilter{})
+	if err != nil {
+		return nil, err
+	}
+	relationships, err := s.client.ListRelationships(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &relationshipGraph{nodes: make(map[string]*graphNode), adj: make(map[string][]int)}
+	for _, company := range companies {
+		id := company.ID.String()
+		g.nodes[id] = &graphNode{ID: id, Kind: "company", Label: company.Name, Detail: company.Industry,
+			URL: s.url("/companies?q=" + url.QueryEscape(company.Name))}
+	}
+	for _, contact := range contacts {
+		id := contact.ID.String()
+		g.nodes[id] = &graphNode{ID: id, Kind: "contact", Label: contact.Name, Detail: contact.Title,
+			URL: s.url("/contacts/" + id)}
+	}
+
+	seen := make(map[string]bool)
+	add := func(edge graphEdge) {
+		if g.nodes[edge.Source] == nil || g.nodes[edge.Target] == nil {
+			return // an archived or deleted end
+		}
+		if !seen[edge.Type] {
+			seen[edge.Type] = true
+			g.types = append(g.types, edge.Type)
+		}
+		if len(types) > 0 && !types[edge.Type] {
+			return
+		}
+		g.adj[edge.Source] = append(g.adj[edge.Source], len(g.edges))
+		g.adj[edge.Target] = append(g.adj[edge.Target], len(g.edges))
+		g.nodes[edge.Source].Degree++
+		g.nodes[edge.Target].Degree++
+		g.edges = append(g.edges, edge)
+	}
+	for _, contact := range contacts {
+		if contact.CompanyID != nil {
+			add(graphEdge{Source: contact.ID.String(), Target: contact.CompanyID.String(), Type: graphWorksAt})
+		}
+	}
+	for _, rel := range relationships {
+		relType := rel.RelationshipType
+		if relType == "" {
+			relType = graphUntypedLink
+		}
+		add(graphEdge{Source: rel.ContactID1.String(), Target: rel.ContactID2.String(), Type: relType, Strength: rel.Strength})
+	}
+	sort.Strings(g.types)
+	return g, nil
+}
+
+// neighborhood returns the nodes within depth edges of center and the edges
+// among them.
+func (g *relationshipGraph) neighborhood(center string, depth int) *graphPayload {
+	keep := map[string]bool{center: true}
+	frontier := []string{center}
+	for range depth {
+		var next []string
+		for _, id := range frontier {
+			for _, i := range g.adj[id] {
+				other := g.edges[i].Source
+				if other == id {
+					other = g.edges[i].Target
+				}
+				if !keep[other] {
+					keep[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+	payload := g.subgraph(keep)
+	payload.Center, payload.Depth = center, depth
+	return payload
+}
+
+// overview returns every node with an edge, keeping the limit best-connected
+// when there are more.
+func (g *relationshipGraph) overview(limit int) *graphPayload {
+	var connected []*graphNode
+	for _, node := range g.nodes {
+		if node.Degree > 0 {
+			connected = append(connected, node)
+		}
+	}
+	sort.Slice(connected, func(i, j int) bool {
+		if connected[i].Degree != connected[j].Degree {
+			return connected[i].Degree > connected[j].Degree
+		}
+		return connected[i].ID < connected[j].ID
+	})
+
+	keep := make(map[string]bool)
+	for _, node := range connected[:min(len(connected), limit)] {
+		keep[node.ID] = true
+	}
+	payload := g.subgraph(keep)
+	payload.Truncated = len(connected) > limit
+	return payload
+}
+
+// subgraph returns the kept nodes, sorted by label, and the edges between them.
+func (g *relationshipGraph) subgraph(keep map[string]bool) *graphPayload {
+	payload := &graphPayload{Nodes: []graphNode{}, Edges: []graphEdge{}, Types: g.types}
+	if payload.Types == nil {
+		payload.Types = []string{}
+	}
+	for id := range keep {
+		payload.Nodes = append(payload.Nodes, *g.nodes[id])
+	}
+	sort.Slice(payload.Nodes, func(i, j int) bool {
+		a, b := payload.Nodes[i], payload.Nodes[j]
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.ID < b.ID
+	})
+	for _, edge := range g.edges {
+		if keep[edge.Source] && keep[edge.Target] {
+			payload.Edges = append(payload.Edges, edge)
+		}
+	}
+	return payload
+}
+
+// graphExplorerURL links to the explorer centered on a contact or company.
+func (s *Server) graphExplorerURL(id uuid.UUID) string {
+	return s.url("/graphs?center=" + id.String())
+}
@@ -0,0 +1,131 @@
+// ABOUTME: Tests for the relationship graph explorer feed
+// ABOUTME: Verifies the overview, neighborhoods by depth, type filters, deep links, and bad requests
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestGraphAPI(t *testing.T) {
+	client := charm.NewTestClient(t)
+	acme := &charm.Company{Name: "Acme & Co"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	ada := &charm.Contact{Name: "Ada", CompanyID: &acme.ID}
+	bob := &charm.Contact{Name: "Bob"}
+	cy := &charm.Contact{Name: "Cy"}
+	loner := &charm.Contact{Name: "Loner"}
+	for _, contact := range []*charm.Contact{ada, bob, cy, loner} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+	}
+	for _, rel := range []*charm.Relationship{
+		{ContactID1: ada.ID, ContactID2: bob.ID, RelationshipType: "colleague", Strength: "strong"},
+		{ContactID1: bob.ID, ContactID2: cy.ID, RelationshipType: "friend"},
+	} {
+		if err := client.CreateRelationship(rel); err != nil {
+			t.Fatalf("CreateRelationship failed: %v", err)
+		}
+	}
+
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	handler := s.routes()
+	get := func(path string) (*httptest.ResponseRecorder, graphPayload) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var payload graphPayload
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("bad graph JSON: %v", err)
+			}
+		}
+		return rec, payload
+	}
+	labels := func(payload graphPayload) string {
+		var names []string
+		for _, node := range payload.Nodes {
+			names = append(names, node.Label)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// The overview leaves out people with no links
+	_, overview := get("/api/graph")
+	if got := labels(overview); got != "Acme & Co,Ada,Bob,Cy" {
+		t.Errorf("overview nodes = %s", got)
+	}
+	if len(overview.Edges) != 3 {
+		t.Errorf("expected 3 edges, got %+v", overview.Edges)
+	}
+	if got := strings.Join(overview.Types, ","); got != "colleague,friend,works_at" {
+		t.Errorf("types = %s", got)
+	}
+
+	// Neighborhoods grow with depth
+	_, near := get("/api/graph?center=" + ada.ID.String())
+	if got := labels(near); got != "Acme & Co,Ada,Bob" {
+		t.Errorf("depth 1 nodes = %s", got)
+	}
+	if near.Center != ada.ID.String() || near.Depth != 1 {
+		t.Errorf("unexpected center %s, depth %d", near.Center, near.Depth)
+	}
+	_, far := get("/api/graph?center=" + ada.ID.String() + "&depth=2")
+	if got := labels(far); got != "Acme & Co,Ada,Bob,Cy" {
+		t.Errorf("depth 2 nodes = %s", got)
+	}
+
+	// Filtering by type drops other links, but still lists every type
+	_, friends := get("/api/graph?center=" + bob.ID.String() + "&type=friend")
+	if got := labels(friends); got != "Bob,Cy" {
+		t.Errorf("friend nodes = %s", got)
+	}
+	if len(friends.Types) != 3 {
+		t.Errorf("expected every type for the filter, got %v", friends.Types)
+	}
+
+	// Nodes deep-link to their pages
+	for _, node := range near.Nodes {
+		want := "/contacts/" + node.ID
+		if node.Kind == "company" {
+			want = "/companies?q=Acme+%26+Co"
+		}
+		if node.URL != want {
+			t.Errorf("%s links to %s, want %s", node.Label, node.URL, want)
+		}
+	}
+	for _, edge := range near.Edges {
+		if edge.Type == "colleague" && edge.Strength != "strong" {
+			t.Errorf("expected the relationship strength, got %+v", edge)
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/api/graph?center=nope":                http.StatusBadRequest,
+		"/api/graph?depth=0":                    http.StatusBadRequest,
+		"/api/graph?center=" + uuid.NewString(): http.StatusNotFound,
+	} {
+		if rec, _ := get(path); rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	// The explorer page starts from the center it's linked with
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphs?center="+ada.ID.String(), nil))
+	if !strings.Contains(rec.Body.String(), `data-center="`+ada.ID.String()+`"`) {
+		t.Error("expected the explorer to start from the linked center")
+	}
+}
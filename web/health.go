@@ -0,0 +1,74 @@
+// ABOUTME: Liveness and readiness endpoints for container orchestrators
+// ABOUTME: Served ahead of login, maintenance mode, and the request log
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthStatus is the JSON body of /healthz and /readyz.
+type healthStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// withHealth answers /healthz and /readyz (with or without the base path) before
+// any other middleware, so probes work without credentials and don't fill the log.
+func (s *Server) withHealth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, s.basePath)
+		switch path {
+		case "/healthz":
+			writeHealth(w, http.StatusOK, healthStatus{Status: "ok"})
+		case "/readyz":
+			if err := s.ready(); err != nil {
+				writeHealth(w, http.StatusServiceUnavailable, healthStatus{Status: "unavailable", Reason: err.Error()})
+				return
+			}
+			writeHealth(w, http.StatusOK, healthStatus{Status: "ready"})
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// ready reports whether the server can serve pages: not in maintenance, and the
+// local database opens. It doesn't contact the Charm cloud.
+func (s *Server) ready() error {
+	if state := s.maintenance.Load(); state != nil && state.enabled {
+		return fmt.Errorf("maintenance mode")
+	}
+	if s.client == nil {
+		return nil
+	}
+	if _, err := s.client.LastSyncTime(); err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	return nil
+}
+
+func writeHealth(w http.ResponseWriter, status int, body healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// CheckHealth requests url and fails unless it answers 200. It backs
+// `pagen web healthcheck`, for container images without curl.
+func CheckHealth(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: %s", resp.Status)
+	}
+	return nil
+}
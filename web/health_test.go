@@ -0,0 +1,56 @@
+// ABOUTME: Tests for container health endpoints
+// ABOUTME: Verifies probes bypass login and report maintenance as not ready
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+	auth, err := newAuthenticator(&charm.WebAuthConfig{Username: "u", PasswordHash: string(hash)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{auth: auth, basePath: "/pagen"}
+	handler := s.withHealth(s.maintenanceGuard(s.withBasePath(s.requireAuth(s.routes()))))
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("expected /healthz without login to be 200, got %d", code)
+	}
+	if code := get("/pagen/readyz"); code != http.StatusOK {
+		t.Errorf("expected /readyz under the base path to be 200, got %d", code)
+	}
+	if code := get("/pagen/deals"); code != http.StatusUnauthorized {
+		t.Errorf("expected other pages to still need login, got %d", code)
+	}
+
+	s.setMaintenance(true, "")
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected not ready during maintenance, got %d", code)
+	}
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("expected still live during maintenance, got %d", code)
+	}
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	if err := CheckHealth(srv.URL+"/healthz", 0); err != nil {
+		t.Errorf("expected CheckHealth to pass: %v", err)
+	}
+	if err := CheckHealth(srv.URL+"/readyz", 0); err == nil {
+		t.Error("expected CheckHealth to fail while not ready")
+	}
+}
@@ -0,0 +1,164 @@
+// ABOUTME: iCalendar feed of overdue and upcoming follow-ups at /followups.ics
+// ABOUTME: Regenerated on each request from contact cadences, for subscribing from calendar apps
+
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const (
+	followupFeedPath = "/followups.ics"
+	// followupFeedDays is how far ahead upcoming follow-ups are included by default.
+	followupFeedDays = 30
+)
+
+// handleFollowupsICS serves follow-ups as all-day events: upcoming ones on
+// their due date, and overdue ones on today so they stay in view.
+func (s *Server) handleFollowupsICS(w http.ResponseWriter, r *http.Request) {
+	days := followupFeedDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 365 {
+			http.Error(w, "days must be 0-365", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	now := time.Now()
+	due, err := s.client.GetFollowupDueDates(now, days)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="followups.ics"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := writeFollowupCalendar(w, due, now, s.externalURL(r, "/followups")); err != nil {
+		log.Printf("Failed to write follow-up calendar: %v", err)
+	}
+}
+
+// writeFollowupCalendar writes due follow-ups as an iCalendar (RFC 5545)
+// feed. Event UIDs are per contact, so subscribed calendars move an event
+// when its due date changes rather than adding another.
+func writeFollowupCalendar(w io.Writer, due []*charm.FollowupDue, now time.Time, link string) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICSLine(s)) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//pagen//Follow-ups//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Follow-ups")
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	line("X-PUBLISHED-TTL:PT1H")
+	for _, f := range due {
+		day, summary := f.Due, "Follow up with "+f.Name
+		details := []string{fmt.Sprintf("Every %d days", f.CadenceDays)}
+		if f.Overdue() {
+			day = today
+			summary = fmt.Sprintf("Overdue: follow up with %s (%d days)", f.Name, f.DaysOverdue)
+		}
+		if f.RelationshipStrength != "" {
+			details = append(details, f.RelationshipStrength+" relationship")
+		}
+		details = append(details, "last contact "+f.LastInteraction.Format("2006-01-02"))
+		description := strings.Join(details, ", ")
+		if f.CompanyName != "" {
+			description = f.CompanyName + "\n" + description
+		}
+		if f.Email != "" {
+			description += "\n" + f.Email
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:followup-" + f.ContactID.String() + "@pagen")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICSText(summary))
+		line("DESCRIPTION:" + escapeICSText(description))
+		if link != "" {
+			line("URL:" + link)
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICSText escapes a TEXT property value.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICSLine ends a content line with CRLF, folding it so no line is longer
+// than 75 octets without splitting a UTF-8 character.
+func foldICSLine(s string) string {
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// feedToken is the secret that lets calendar apps, which can't sign in,
+// fetch the follow-up feed. It is derived from the session secret, so
+// resetting sessions revokes it too.
+func (a *authenticator) feedToken() string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte("feed:" + followupFeedPath))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// validFeedToken reports whether the request carries the feed token.
+func (a *authenticator) validFeedToken(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	return token != "" && hmac.Equal([]byte(token), []byte(a.feedToken()))
+}
+
+// followupFeedURL is the subscription URL for the follow-up feed, with the
+// feed token when login is required.
+func (s *Server) followupFeedURL(r *http.Request) string {
+	feed := s.externalURL(r, followupFeedPath)
+	if s.auth != nil {
+		feed += "?token=" + url.QueryEscape(s.auth.feedToken())
+	}
+	return feed
+}
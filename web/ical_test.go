@@ -0,0 +1,122 @@
+// ABOUTME: Tests for the follow-up iCalendar feed
+// ABOUTME: Verifies due dates from cadences, overdue events on today, line folding, and feed token access
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestFollowupsICS(t *testing.T) {
+	client := charm.NewTestClient(t)
+	now := time.Now()
+
+	overdue := &charm.Contact{Name: "Ada, Lovelace", Email: "ada@example.com"}
+	upcoming := &charm.Contact{Name: "Grace"}
+	distant := &charm.Contact{Name: "Linus"}
+	for contact, lastDays := range map[*charm.Contact]int{overdue: 40, upcoming: 25, distant: 0} {
+		if err := client.CreateContact(contact); err != nil {
+			t.Fatalf("CreateContact failed: %v", err)
+		}
+		last := now.AddDate(0, 0, -lastDays)
+		if err := client.SaveContactCadence(&charm.ContactCadence{ContactID: contact.ID, CadenceDays: 30, RelationshipStrength: charm.StrengthStrong, LastInteractionDate: &last}); err != nil {
+			t.Fatalf("SaveContactCadence failed: %v", err)
+		}
+	}
+
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/followups.ics?days=14", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	body := rec.Body.String()
+	today := now.Format("20060102")
+	inFive := now.AddDate(0, 0, 5).Format("20060102")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:followup-" + overdue.ID.String() + "@pagen\r\n",
+		"SUMMARY:Overdue: follow up with Ada\\, Lovelace (10 days)\r\n",
+		"DTSTART;VALUE=DATE:" + today + "\r\n",
+		"SUMMARY:Follow up with Grace\r\n",
+		"DTSTART;VALUE=DATE:" + inFive + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in feed:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Linus") {
+		t.Error("follow-ups beyond the window should be left out")
+	}
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/followups.ics?days=forever", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid days, got %d", rec.Code)
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 60)
+	folded := foldICSLine(line)
+	if strings.ReplaceAll(folded, "\r\n ", "") != line+"\r\n" {
+		t.Errorf("unfolding should restore the line, got %q", folded)
+	}
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > 75 || !strings.HasPrefix(part, "D") && !strings.HasPrefix(part, " ") {
+			t.Errorf("bad folded line %q", part)
+		}
+	}
+}
+
+func TestFollowupFeedToken(t *testing.T) {
+	s, handler := newAuthTestServer(t, &charm.WebAuthConfig{Username: "harper", PasswordHash: "x", SessionSecret: "00112233"})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/followups.ics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected the feed to need a login or token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/followups.ics?token=wrong", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected a wrong token to fail, got %d", rec.Code)
+	}
+
+	feedURL := s.followupFeedURL(httptest.NewRequest(http.MethodGet, "http://crm.local/followups", nil))
+	if !strings.HasPrefix(feedURL, "http://crm.local/followups.ics?token=") {
+		t.Fatalf("unexpected feed URL %q", feedURL)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, feedURL, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected the feed token to work, got %d", rec.Code)
+	}
+
+	// The token only opens the feed
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strings.Replace(feedURL, "/followups.ics", "/deals", 1), nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected the feed token not to open other pages, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,201 @@
+// ABOUTME: Web server lifecycle: TLS, graceful shutdown with connection draining, maintenance mode, and request logging
+// ABOUTME: Lets `pagen web` run exposed on a home server and be taken offline without stopping it
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultShutdownTimeout is how long in-flight requests get to finish on shutdown.
+const DefaultShutdownTimeout = 15 * time.Second
+
+// maintenancePollInterval is how often the server re-reads the maintenance setting.
+const maintenancePollInterval = 5 * time.Second
+
+// Options configures how the web server listens.
+type Options struct {
+	Port int
+
+	// CertFile and KeyFile serve HTTPS from a certificate on disk
+	CertFile string
+	KeyFile  string
+
+	// AutocertDomains serves HTTPS with Let's Encrypt certificates for these hosts
+	AutocertDomains []string
+
+	// ShutdownTimeout bounds connection draining (default: DefaultShutdownTimeout)
+	ShutdownTimeout time.Duration
+
+	// SlowQueryThreshold is when KV operations are logged as slow
+	// (default: web_slow_query_threshold config, then DefaultSlowQueryThreshold)
+	SlowQueryThreshold time.Duration
+
+	// BackgroundJobs runs alongside the server until ctx is done, for
+	// scheduled upkeep such as priority recomputes
+	BackgroundJobs func(ctx context.Context)
+}
+
+// maintenanceState is swapped atomically so requests never see a half-updated message.
+type maintenanceState struct {
+	enabled bool
+	message string
+}
+
+var maintenancePage = template.Must(template.New("maintenance").Parse(`<!DOCTYPE html>
+<html><head><title>Pagen - Maintenance</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 20vh">
+<h1>Down for maintenance</h1>
+<p>{{if .}}{{.}}{{else}}Pagen will be back shortly.{{end}}</p>
+</body></html>
+`))
+
+// Run serves the web UI until SIGINT/SIGTERM, then drains connections and returns.
+func (s *Server) Run(opts Options) error {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return fmt.Errorf("--cert and --key must be used together")
+	}
+	if opts.CertFile != "" && len(opts.AutocertDomains) > 0 {
+		return fmt.Errorf("--cert/--key and --autocert are mutually exclusive")
+	}
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	if cfg, err := charm.LoadConfig(); err == nil {
+		s.setMaintenance(cfg.WebMaintenance, cfg.WebMaintenanceMessage)
+		if opts.SlowQueryThreshold == 0 {
+			opts.SlowQueryThreshold = cfg.WebSlowQueryThreshold
+		}
+	}
+
+	s.telemetry = newTelemetry(LogPath(), opts.SlowQueryThreshold)
+	defer s.telemetry.close()
+	s.client.SetQueryObserver(s.telemetry.observeQuery)
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", opts.Port),
+		Handler:           s.withHealth(s.logRequests(securityHeaders(s.maintenanceGuard(s.withBasePath(s.requireAuth(s.csrfProtect(s.routes()))))))),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	scheme := "http"
+	if len(opts.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.AutocertDomains...),
+			Cache:      autocert.DirCache(filepath.Join(charm.DataDir(), "autocert")),
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		scheme = "https"
+	} else if opts.CertFile != "" {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		scheme = "https"
+	}
+
+	if s.auth == nil {
+		log.Printf("Warning: web UI has no login; anyone who can reach port %d can read your CRM (see `pagen web auth`)", opts.Port)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go s.watchMaintenance(ctx)
+	if opts.BackgroundJobs != nil {
+		go opts.BackgroundJobs(ctx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Starting web server at %s://localhost%s%s/", scheme, srv.Addr, s.basePath)
+		var err error
+		switch {
+		case len(opts.AutocertDomains) > 0:
+			// Certificates come from TLSConfig.GetCertificate
+			err = srv.ListenAndServeTLS("", "")
+		case opts.CertFile != "":
+			err = srv.ListenAndServeTLS(opts.CertFile, opts.KeyFile)
+		default:
+			err = srv.ListenAndServe()
+		}
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down web server (draining connections for up to %s)", opts.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down cleanly: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Printf("Web server stopped")
+	return nil
+}
+
+// maintenanceGuard answers 503 with a maintenance page while maintenance mode is on.
+func (s *Server) maintenanceGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := s.maintenance.Load()
+		if state == nil || !state.enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", "300")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = maintenancePage.Execute(w, state.message)
+	})
+}
+
+// setMaintenance turns maintenance mode on or off, logging changes.
+func (s *Server) setMaintenance(enabled bool, message string) {
+	prev := s.maintenance.Swap(&maintenanceState{enabled: enabled, message: message})
+	if prev != nil && prev.enabled == enabled {
+		return
+	}
+	if enabled {
+		log.Printf("Maintenance mode on")
+	} else if prev != nil {
+		log.Printf("Maintenance mode off")
+	}
+}
+
+// watchMaintenance follows the web_maintenance config setting until ctx is done,
+// so `pagen web maintenance on|off` takes effect on a running server.
+func (s *Server) watchMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(maintenancePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg, err := charm.LoadConfig()
+			if err != nil {
+				continue
+			}
+			s.setMaintenance(cfg.WebMaintenance, cfg.WebMaintenanceMessage)
+		}
+	}
+}
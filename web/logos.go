@@ -0,0 +1,65 @@
+// ABOUTME: Company logo endpoint for web lists and detail panels
+// ABOUTME: Serves the cached logo, fetching it when company_logos is on, or an initials placeholder
+package web
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
+)
+
+// logoFetchSlots caps concurrent logo fetches so a page of new companies
+// doesn't open dozens of outbound connections at once.
+const logoFetchSlots = 4
+
+// logoPath returns the logo URL of a company.
+func (s *Server) logoPath(companyID uuid.UUID) string {
+	return s.url("/logos/" + companyID.String())
+}
+
+// handleLogo serves /logos/<company-id>. Found logos are cached by the
+// browser for a day and placeholders for five minutes, so a logo fetched
+// later shows up soon.
+func (s *Server) handleLogo(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/logos/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	company, err := s.client.GetCompany(id)
+	if err != nil || company == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	fetcher := s.logos
+	if fetcher != nil {
+		select {
+		case s.logoSlots <- struct{}{}:
+			defer func() { <-s.logoSlots }()
+		case <-r.Context().Done():
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), sync.LogoFetchTimeout)
+	defer cancel()
+	logo, err := sync.CompanyLogo(ctx, s.client, fetcher, company)
+	if err != nil {
+		log.Printf("logo for %s: %v", company.Name, err)
+	}
+
+	if logo != nil {
+		w.Header().Set("Content-Type", logo.ContentType)
+		w.Header().Set("Cache-Control", "private, max-age=86400")
+		_, _ = w.Write(logo.Data)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	_, _ = w.Write(charm.LogoPlaceholderSVG(company.Name))
+}
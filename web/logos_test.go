@@ -0,0 +1,59 @@
+// ABOUTME: Tests for the company logo endpoint
+// ABOUTME: Checks cached logos are served and companies without one get an initials placeholder
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestHandleLogo(t *testing.T) {
+	client := charm.NewTestClient(t)
+	cached := &charm.Company{Name: "Acme Corp", Domain: "acme.com"}
+	plain := &charm.Company{Name: "Globex", Domain: "globex.com"}
+	for _, company := range []*charm.Company{cached, plain} {
+		if err := client.CreateCompany(company); err != nil {
+			t.Fatalf("CreateCompany failed: %v", err)
+		}
+	}
+	png := []byte("\x89PNG\r\n\x1a\nlogo")
+	if err := client.SaveCompanyLogo(&charm.CompanyLogo{Domain: "acme.com", ContentType: "image/png", Data: png}); err != nil {
+		t.Fatalf("SaveCompanyLogo failed: %v", err)
+	}
+
+	// company_logos is off, so nothing is fetched
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logos/"+cached.ID.String(), nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Body.String() != string(png) {
+		t.Errorf("expected the cached PNG, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logos/"+plain.ID.String(), nil))
+	if rec.Header().Get("Content-Type") != "image/svg+xml" || !strings.Contains(rec.Body.String(), ">G</text>") {
+		t.Errorf("expected an initials placeholder, got %s: %s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/companies", nil))
+	if !strings.Contains(rec.Body.String(), `src="/logos/`+cached.ID.String()+`"`) {
+		t.Error("expected the companies list to show logos")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logos/not-an-id", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a bad ID, got %d", rec.Code)
+	}
+}
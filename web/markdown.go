@@ -0,0 +1,102 @@
+// ABOUTME: Markdown rendering for deal and contact notes in the web UI
+// ABOUTME: GitHub-flavored Markdown with raw HTML dropped; deal note checklists toggle over HTMX
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdown renders note Markdown as HTML. Raw HTML and dangerous link
+// schemes are left out, since notes come from synced and imported data.
+func markdown(source string) template.HTML {
+	return renderMarkdown(source, nil)
+}
+
+// dealNoteMarkdown renders a deal note with checklist boxes that toggle the
+// item in place.
+func (s *Server) dealNoteMarkdown(note *charm.DealNote) template.HTML {
+	return renderMarkdown(note.Content, &checklistRenderer{
+		toggleURL: s.url("/deals/notes/toggle?note=" + note.ID.String() + "&item="),
+		target:    "#note-" + note.ID.String(),
+	})
+}
+
+func renderMarkdown(source string, checklist *checklistRenderer) template.HTML {
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	if checklist != nil {
+		md.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(checklist, 100)))
+	}
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return template.HTML(template.HTMLEscapeString(source))
+	}
+	return template.HTML(buf.String())
+}
+
+// checklistRenderer replaces GFM's disabled task boxes with ones that post
+// the item's index, counted in document order like charm.ParseChecklist.
+type checklistRenderer struct {
+	toggleURL string
+	target    string
+	count     int
+}
+
+func (r *checklistRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(east.KindTaskCheckBox, r.renderCheckBox)
+}
+
+func (r *checklistRenderer) renderCheckBox(w util.BufWriter, _ []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	checked := ""
+	if node.(*east.TaskCheckBox).IsChecked {
+		checked = ` checked=""`
+	}
+	_, _ = fmt.Fprintf(w, `<input type="checkbox"%s hx-post="%s%d" hx-target="%s" hx-swap="outerHTML" class="cursor-pointer"> `,
+		checked, template.HTMLEscapeString(r.toggleURL), r.count, r.target)
+	r.count++
+	return gast.WalkContinue, nil
+}
+
+// handleToggleNoteItem flips a checklist item in a deal note and returns the
+// re-rendered note.
+func (s *Server) handleToggleNoteItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	noteID, err := uuid.Parse(r.URL.Query().Get("note"))
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return
+	}
+	item, err := strconv.Atoi(r.URL.Query().Get("item"))
+	if err != nil {
+		http.Error(w, "Invalid checklist item", http.StatusBadRequest)
+		return
+	}
+
+	note, err := s.client.ToggleDealNoteItem(noteID, item)
+	if err != nil {
+		log.Printf("Failed to toggle checklist item: %v", err)
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	s.renderTemplate(w, "partials/deal-note.html", note)
+}
@@ -0,0 +1,74 @@
+// ABOUTME: Tests for Markdown notes in the web UI
+// ABOUTME: Verifies raw HTML is dropped and deal note checklist boxes toggle over HTMX
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestMarkdownDropsRawHTML(t *testing.T) {
+	got := string(markdown("**Budget** <script>alert(1)</script> [x](javascript:alert(1))"))
+	if !strings.Contains(got, "<strong>Budget</strong>") {
+		t.Errorf("expected emphasis to render, got %q", got)
+	}
+	if strings.Contains(got, "<script>") || strings.Contains(got, "javascript:") {
+		t.Errorf("expected raw HTML and script links to be dropped, got %q", got)
+	}
+}
+
+func TestToggleNoteItem(t *testing.T) {
+	client := charm.NewTestClient(t)
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	deal := &charm.Deal{Title: "Acme renewal", Stage: charm.StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("failed to create deal: %v", err)
+	}
+	note := &charm.DealNote{DealID: deal.ID, Content: "- [ ] Send proposal\n- [x] Intro call"}
+	if err := client.CreateDealNote(note); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/partials/deal-detail?id="+deal.ID.String(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for deal detail, got %d: %s", rec.Code, rec.Body.String())
+	}
+	toggle := `hx-post="/deals/notes/toggle?note=` + note.ID.String() + `&amp;item=0"`
+	if body := rec.Body.String(); !strings.Contains(body, toggle) || !strings.Contains(body, `id="note-`+note.ID.String()+`"`) {
+		t.Errorf("expected toggleable checklist in deal detail, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/deals/notes/toggle?note="+note.ID.String()+"&item=0", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for toggle, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Count(rec.Body.String(), `checked=""`) != 2 {
+		t.Errorf("expected both boxes checked in the re-rendered note, got:\n%s", rec.Body.String())
+	}
+	saved, err := client.GetDealNote(note.ID)
+	if err != nil || !strings.HasPrefix(saved.Content, "- [x] Send proposal") {
+		t.Errorf("expected the toggle to be saved, got %+v, %v", saved, err)
+	}
+
+	for target, want := range map[string]int{
+		"/deals/notes/toggle?note=bad&item=0":                      http.StatusBadRequest,
+		"/deals/notes/toggle?note=" + note.ID.String() + "&item=9": http.StatusUnprocessableEntity,
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, nil))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", target, want, rec.Code)
+		}
+	}
+}
@@ -4,15 +4,20 @@ package web
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/pagen/charm"
+	"github.com/harperreed/pagen/sync"
 	"github.com/harperreed/pagen/viz"
 )
 
@@ -21,11 +26,53 @@ var templatesFS embed.FS
 
 type Server struct {
 	client    *charm.Client
+	apiClient *charm.Client // client for the REST API, crediting changes to api in the audit log
 	templates *template.Template
 	generator *viz.GraphGenerator
+	tracking  bool // serve email open/click tracking endpoints
+	sharing   bool // serve public share pages
+
+	logos     *sync.LogoFetcher // fetches uncached company logos; nil serves only the cache
+	logoSlots chan struct{}
+
+	shareLimiter *rateLimiter
+
+	maintenance atomic.Pointer[maintenanceState]
+
+	// basePath is the subpath the UI is served under behind a reverse proxy, e.g. "/pagen" ("" at the root)
+	basePath string
+	baseURL  string
+
+	auth *authenticator // nil when the UI is open
+
+	telemetry *telemetry // request and KV timing, set by Run
 }
 
 func NewServer(client *charm.Client) (*Server, error) {
+	s := &Server{
+		client:    client.ForActor(charm.EntryWeb),
+		apiClient: client.ForActor(charm.EntryAPI),
+		generator: viz.NewGraphGenerator(client),
+
+		shareLimiter: newRateLimiter(shareRateBurst, shareRateInterval),
+		logoSlots:    make(chan struct{}, logoFetchSlots),
+	}
+	if cfg := client.Config(); cfg != nil {
+		s.tracking = cfg.EmailTracking
+		s.sharing = cfg.WebSharing
+		if cfg.CompanyLogos {
+			s.logos = sync.NewLogoFetcher(cfg.LogoURL)
+		}
+		if err := s.SetBaseURL(cfg.WebBaseURL); err != nil {
+			return nil, err
+		}
+		auth, err := newAuthenticator(cfg.WebAuth)
+		if err != nil {
+			return nil, err
+		}
+		s.auth = auth
+	}
+
 	// Helper functions for templates
 	funcMap := template.FuncMap{
 		"divide": func(a, b int64) int64 {
@@ -43,6 +90,18 @@ func NewServer(client *charm.Client) (*Server, error) {
 		"sub": func(a, b int) int {
 			return a - b
 		},
+		"money":        charm.FormatMoney,
+		"moneyCompact": charm.FormatMoneyCompact,
+		"roleLabel":    charm.DealRoleLabel,
+		"fundingStage": charm.FundingStageLabel,
+		"markdown":     markdown,
+		"dealNote":     s.dealNoteMarkdown,
+		"url":          s.url,
+		"logo":         s.logoPath,
+		"isLink": func(value string) bool {
+			return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+		},
+		"basePath": func() string { return s.basePath },
 	}
 
 	tmpl, err := template.New("").Funcs(funcMap).ParseFS(templatesFS, "templates/*.html", "templates/partials/*.html")
@@ -50,32 +109,112 @@ func NewServer(client *charm.Client) (*Server, error) {
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
 
-	return &Server{
-		client:    client,
-		templates: tmpl,
-		generator: viz.NewGraphGenerator(client),
-	}, nil
+	s.templates = tmpl
+	return s, nil
+}
+
+// SetBaseURL serves the UI under the path of baseURL, e.g. "https://home.example.com/pagen/"
+// or just "/pagen". An empty URL serves from the root.
+func (s *Server) SetBaseURL(baseURL string) error {
+	s.baseURL = baseURL
+	if baseURL == "" {
+		s.basePath = ""
+		return nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	s.basePath = strings.TrimRight(u.Path, "/")
+	if s.basePath != "" && !strings.HasPrefix(s.basePath, "/") {
+		s.basePath = "/" + s.basePath
+	}
+	return nil
 }
 
+// url prefixes an absolute UI path with the base path.
+func (s *Server) url(path string) string {
+	return s.basePath + path
+}
+
+// Start serves the web UI over plain HTTP on port until interrupted.
 func (s *Server) Start(port int) error {
-	// Routes
-	http.HandleFunc("/", s.handleDashboard)
-	http.HandleFunc("/contacts", s.handleContacts)
-	http.HandleFunc("/companies", s.handleCompanies)
-	http.HandleFunc("/deals", s.handleDeals)
-	http.HandleFunc("/graphs", s.handleGraphs)
-	http.HandleFunc("/followups", s.handleFollowups)
+	return s.Run(Options{Port: port})
+}
+
+// routes registers every page, partial, and optional endpoint.
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleDashboard)
+	mux.HandleFunc("/contacts", s.handleContacts)
+	mux.HandleFunc("/contacts/", s.handleContactPage)
+	mux.HandleFunc("/companies", s.handleCompanies)
+	mux.HandleFunc("/deals", s.handleDeals)
+	mux.HandleFunc("/graphs", s.handleGraphs)
+	mux.HandleFunc("/followups", s.handleFollowups)
+	mux.HandleFunc(followupFeedPath, s.handleFollowupsICS)
 
 	// Partials for HTMX
-	http.HandleFunc("/partials/contact-detail", s.handleContactDetail)
-	http.HandleFunc("/partials/company-detail", s.handleCompanyDetail)
-	http.HandleFunc("/partials/deal-detail", s.handleDealDetail)
-	http.HandleFunc("/partials/graph", s.handleGraphPartial)
-	http.HandleFunc("/followups/log/", s.handleFollowupLog)
-
-	addr := fmt.Sprintf(":%d", port)
-	log.Printf("Starting web server at http://localhost%s", addr)
-	return http.ListenAndServe(addr, nil)
+	mux.HandleFunc("/partials/contact-detail", s.handleContactDetail)
+	mux.HandleFunc("/partials/company-detail", s.handleCompanyDetail)
+	mux.HandleFunc("/partials/deal-detail", s.handleDealDetail)
+	mux.HandleFunc("/partials/graph", s.handleGraphPartial)
+	mux.HandleFunc("/logos/", s.handleLogo)
+	mux.HandleFunc("/followups/log/", s.handleFollowupLog)
+	mux.HandleFunc("/deals/notes/toggle", s.handleToggleNoteItem)
+	mux.Handle("/static/", staticHandler())
+
+	// JSON feeds for pages that load as they scroll
+	mux.HandleFunc("/api/contacts/", s.handleContactTimelineAPI)
+	mux.HandleFunc("/api/graph", s.handleGraphAPI)
+
+	// REST API for scripts, with its own token auth
+	mux.Handle(apiPrefix, s.requireAPIToken(http.HandlerFunc(s.handleAPI)))
+
+	// Email tracking endpoints are off unless enabled in config
+	if s.tracking {
+		mux.HandleFunc("/t/o/", s.handleTrackOpen)
+		mux.HandleFunc("/t/c/", s.handleTrackClick)
+		log.Printf("Email open/click tracking enabled")
+	}
+
+	// Read-only share pages are off unless enabled in config
+	if s.sharing {
+		mux.HandleFunc(sharePathPrefix, s.handleShare)
+		log.Printf("Public share pages enabled")
+	}
+
+	if s.telemetry != nil {
+		mux.HandleFunc("/metrics", s.handleMetrics)
+	}
+
+	if s.auth != nil {
+		mux.HandleFunc("/auth/logout", s.handleLogout)
+		if s.auth.cfg.OIDCEnabled() {
+			mux.HandleFunc("/auth/login", s.handleOIDCLogin)
+			mux.HandleFunc("/auth/callback", s.handleOIDCCallback)
+		}
+	}
+	return mux
+}
+
+// withBasePath strips the base path from requests. Requests without it are served as-is,
+// so both prefix-stripping and prefix-preserving proxies work.
+func (s *Server) withBasePath(next http.Handler) http.Handler {
+	if s.basePath == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == s.basePath {
+			http.Redirect(w, r, s.basePath+"/", http.StatusMovedPermanently)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, s.basePath+"/") {
+			http.StripPrefix(s.basePath, next).ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
@@ -107,12 +246,14 @@ func (s *Server) renderTemplate(w http.ResponseWriter, name string, data interfa
 
 func (s *Server) handleContacts(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
+	tag := r.URL.Query().Get("tag")
 	contacts, err := s.client.ListContacts(&charm.ContactFilter{
 		Query: query,
+		Tag:   tag,
 		Limit: 100,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), listErrorStatus(err))
 		return
 	}
 
@@ -122,6 +263,7 @@ func (s *Server) handleContacts(w http.ResponseWriter, r *http.Request) {
 		Name        string
 		Email       string
 		CompanyName string
+		Tags        []string
 	}
 
 	var contactViews []ContactView
@@ -131,11 +273,13 @@ func (s *Server) handleContacts(w http.ResponseWriter, r *http.Request) {
 			Name:        contact.Name,
 			Email:       contact.Email,
 			CompanyName: contact.CompanyName, // Already denormalized in charm model
+			Tags:        contact.Tags,
 		})
 	}
 
 	data := map[string]interface{}{
 		"Contacts":        contactViews,
+		"Tag":             tag,
 		"Title":           "Contacts",
 		"ContentTemplate": "contacts-content",
 	}
@@ -145,17 +289,20 @@ func (s *Server) handleContacts(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
+	tag := r.URL.Query().Get("tag")
 	companies, err := s.client.ListCompanies(&charm.CompanyFilter{
 		Query: query,
+		Tag:   tag,
 		Limit: 100,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), listErrorStatus(err))
 		return
 	}
 
 	data := map[string]interface{}{
 		"Companies":       companies,
+		"Tag":             tag,
 		"Title":           "Companies",
 		"ContentTemplate": "companies-content",
 	}
@@ -166,14 +313,21 @@ func (s *Server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleDeals(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	stage := r.URL.Query().Get("stage")
+	tag := r.URL.Query().Get("tag")
+	group := r.URL.Query().Get("group")
+	if err := charm.ValidateDealGrouping(group); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	deals, err := s.client.ListDeals(&charm.DealFilter{
 		Query: query,
 		Stage: stage,
+		Tag:   tag,
 		Limit: 100,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), listErrorStatus(err))
 		return
 	}
 
@@ -181,26 +335,61 @@ func (s *Server) handleDeals(w http.ResponseWriter, r *http.Request) {
 	type DealView struct {
 		ID          string
 		Title       string
+		CompanyID   uuid.UUID
 		CompanyName string
 		Stage       string
 		Amount      int64
 		Currency    string
+		Tags        []string
 	}
 
-	var dealViews []DealView
-	for _, deal := range deals {
-		dealViews = append(dealViews, DealView{
+	dealView := func(deal *charm.Deal) DealView {
+		return DealView{
 			ID:          deal.ID.String(),
 			Title:       deal.Title,
+			CompanyID:   deal.CompanyID,
 			CompanyName: deal.CompanyName, // Already denormalized in charm model
 			Stage:       deal.Stage,
 			Amount:      deal.Amount,
 			Currency:    deal.Currency,
-		})
+			Tags:        deal.Tags,
+		}
+	}
+
+	var dealViews []DealView
+	for _, deal := range deals {
+		dealViews = append(dealViews, dealView(deal))
+	}
+
+	// Grouped deals render as collapsible sections
+	type DealGroupView struct {
+		Label    string
+		Total    int64
+		Currency string
+		Deals    []DealView
+	}
+
+	var groupViews []DealGroupView
+	if group != "" {
+		groups, err := charm.GroupDeals(deals, group)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, g := range groups {
+			view := DealGroupView{Label: g.Label, Total: g.Total, Currency: g.Currency}
+			for _, deal := range g.Deals {
+				view.Deals = append(view.Deals, dealView(deal))
+			}
+			groupViews = append(groupViews, view)
+		}
 	}
 
 	data := map[string]interface{}{
 		"Deals":           dealViews,
+		"Groups":          groupViews,
+		"Group":           group,
+		"Tag":             tag,
 		"Title":           "Deals",
 		"ContentTemplate": "deals-content",
 	}
@@ -248,10 +437,13 @@ func (s *Server) handleCompanyDetail(w http.ResponseWriter, r *http.Request) {
 		CompanyID: &id,
 		Limit:     100,
 	})
+	news, _ := s.client.RecentCompanyNews(id)
 
 	data := map[string]interface{}{
 		"Company":  company,
 		"Contacts": contacts,
+		"News":     news,
+		"GraphURL": s.graphExplorerURL(id),
 	}
 
 	s.renderTemplate(w, "partials/company-detail.html", data)
@@ -272,12 +464,28 @@ func (s *Server) handleDealDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	notes, _ := s.client.ListDealNotes(id)
+	roles, _ := s.client.ListDealContacts(id)
+
+	// Recent headlines about the deal's company
+	var news []*charm.NewsItem
+	if deal.CompanyID != uuid.Nil {
+		news, _ = s.client.RecentCompanyNews(deal.CompanyID)
+	}
+
+	// Flag deals that predate a stage rule and are missing required fields
+	var stageWarning string
+	if err := charm.CheckStageRequirements(deal, s.client.StageRequirements()); err != nil {
+		stageWarning = err.Error()
+	}
 
 	data := map[string]interface{}{
-		"Deal":        deal,
-		"CompanyName": deal.CompanyName, // Already denormalized in charm model
-		"ContactName": deal.ContactName, // Already denormalized in charm model
-		"Notes":       notes,
+		"Deal":         deal,
+		"CompanyName":  deal.CompanyName, // Already denormalized in charm model
+		"ContactName":  deal.ContactName, // Already denormalized in charm model
+		"Notes":        notes,
+		"Roles":        roles,
+		"News":         news,
+		"StageWarning": stageWarning,
 	}
 
 	s.renderTemplate(w, "partials/deal-detail.html", data)
@@ -285,6 +493,8 @@ func (s *Server) handleDealDetail(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleGraphs(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
+		"GraphAPI":        s.url("/api/graph"),
+		"Center":          r.URL.Query().Get("center"),
 		"Title":           "Graphs",
 		"ContentTemplate": "graphs-content",
 	}
@@ -351,8 +561,10 @@ func (s *Server) handleFollowups(w http.ResponseWriter, r *http.Request) {
 
 	data := struct {
 		Followups []*charm.FollowupContact
+		FeedURL   string
 	}{
 		Followups: followups,
+		FeedURL:   s.followupFeedURL(r),
 	}
 
 	err = s.templates.ExecuteTemplate(w, "followups", data)
@@ -390,16 +602,87 @@ func (s *Server) handleFollowupLog(w http.ResponseWriter, r *http.Request) {
 		InteractionType: charm.InteractionMessage,
 		Timestamp:       time.Now(),
 		Notes:           "Quick contact via web UI",
+		Outcome:         r.FormValue("outcome"),
 	}
 
-	err = s.client.CreateInteractionLog(interaction)
+	if _, err := charm.NormalizeOutcome(interaction.Outcome); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = s.client.LogFollowup(interaction)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	_, err = w.Write([]byte(`<td colspan="5" class="px-4 py-3 text-green-600">✓ Interaction logged</td>`))
+	message := "✓ Interaction logged"
+	if interaction.Outcome != "" {
+		message += " (" + strings.ReplaceAll(interaction.Outcome, "_", " ") + ")"
+	}
+	_, err = w.Write([]byte(`<span class="text-green-600">` + message + `</span>`))
 	if err != nil {
 		log.Printf("Error writing response: %v", err)
 	}
 }
+
+// trackingPixel is a 1x1 transparent GIF.
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// handleTrackOpen records an email open and serves the tracking pixel.
+// The pixel is served even for unknown IDs so the endpoint reveals nothing.
+func (s *Server) handleTrackOpen(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/t/o/"), ".gif")
+	if id, err := uuid.Parse(token); err == nil {
+		if err := s.client.RecordEmailOpen(id, time.Now()); err != nil {
+			log.Printf("Error recording email open: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate")
+	if _, err := w.Write(trackingPixel); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// handleTrackClick records a link click and redirects to the link's destination.
+// Only links stored on the tracked email can be redirected to.
+func (s *Server) handleTrackClick(w http.ResponseWriter, r *http.Request) {
+	token, index, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/t/c/"), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := uuid.Parse(token)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	link, err := s.client.RecordEmailClick(id, i, time.Now())
+	if err != nil {
+		log.Printf("Error recording email click: %v", err)
+		http.NotFound(w, r)
+		return
+	}
+
+	http.Redirect(w, r, link, http.StatusFound)
+}
+
+// listErrorStatus is the status for a failed list: a bad search query is the
+// caller's mistake, anything else is ours.
+func listErrorStatus(err error) int {
+	if errors.Is(err, charm.ErrInvalidQuery) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,109 @@
+// ABOUTME: Tests for serving the web UI under a base path, and for the deals page
+// ABOUTME: Verifies prefixed routing, redirects, rendered links carrying the prefix, and grouped deals
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestBasePath(t *testing.T) {
+	s, err := NewServer(charm.NewTestClient(t))
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := s.SetBaseURL("https://home.example.com/pagen/"); err != nil {
+		t.Fatalf("SetBaseURL failed: %v", err)
+	}
+	if s.basePath != "/pagen" {
+		t.Fatalf("expected base path /pagen, got %q", s.basePath)
+	}
+	handler := s.withBasePath(s.routes())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pagen", nil))
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/pagen/" {
+		t.Errorf("expected redirect to /pagen/, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// Prefix-preserving proxy
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pagen/contacts", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for /pagen/contacts, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `href="/pagen/deals"`) || !strings.Contains(body, `hx-get="/pagen/contacts"`) {
+		t.Errorf("expected links under /pagen, got:\n%s", body)
+	}
+	if strings.Contains(body, `href="/deals"`) {
+		t.Error("found unprefixed link")
+	}
+
+	// Prefix-stripping proxy
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/contacts", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for stripped /contacts, got %d", rec.Code)
+	}
+}
+
+func TestSetBaseURL(t *testing.T) {
+	s := &Server{}
+	for input, want := range map[string]string{
+		"":                         "",
+		"/":                        "",
+		"/pagen":                   "/pagen",
+		"pagen/":                   "/pagen",
+		"https://example.com":      "",
+		"https://example.com/a/b/": "/a/b",
+	} {
+		if err := s.SetBaseURL(input); err != nil {
+			t.Fatalf("SetBaseURL(%q) failed: %v", input, err)
+		}
+		if s.basePath != want {
+			t.Errorf("SetBaseURL(%q) = %q, want %q", input, s.basePath, want)
+		}
+	}
+}
+
+func TestDealsGrouped(t *testing.T) {
+	client := charm.NewTestClient(t)
+	for _, deal := range []*charm.Deal{
+		{Title: "Pilot", CompanyName: "Acme", Stage: charm.StageProposal, Amount: 100_000, Currency: "USD"},
+		{Title: "Expansion", CompanyName: "Globex", Stage: charm.StageProspecting, Currency: "USD"},
+	} {
+		if err := client.CreateDeal(deal); err != nil {
+			t.Fatalf("CreateDeal failed: %v", err)
+		}
+	}
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	handler := s.routes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deals?group=company", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if strings.Count(body, "<details open") != 2 || !strings.Contains(body, "Acme") || !strings.Contains(body, "Pilot") {
+		t.Errorf("expected a collapsible section per company, got:\n%s", body)
+	}
+	if !strings.Contains(body, `value="company" selected`) {
+		t.Error("expected the grouping to stay selected")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deals?group=owner", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown grouping, got %d", rec.Code)
+	}
+}
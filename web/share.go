@@ -0,0 +1,147 @@
+// ABOUTME: Public read-only share pages for a deal's or company's status at /share/<token>
+// ABOUTME: Served without login when web_sharing is on, rate limited per client to slow token guessing
+
+package web
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+const (
+	sharePathPrefix = "/share/"
+
+	// Each client may load shareRateBurst pages at once, refilled at one
+	// per shareRateInterval.
+	shareRateBurst    = 10
+	shareRateInterval = 6 * time.Second
+)
+
+// handleShare renders the status page for a share token. Unknown, revoked, and
+// expired tokens all get the same 404 so a guesser learns nothing.
+func (s *Server) handleShare(w http.ResponseWriter, r *http.Request) {
+	if !s.shareLimiter.allow(clientIP(r), time.Now()) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, sharePathPrefix)
+	share, err := s.client.FindShare(token)
+	if err != nil {
+		log.Printf("Failed to look up share: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if share == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	status, err := s.client.ShareStatus(share)
+	if err != nil {
+		// The deal or company was deleted after it was shared
+		log.Printf("Failed to build share %s: %v", share.ID, err)
+		http.NotFound(w, r)
+		return
+	}
+
+	// Keep the token out of Referer headers and search indexes
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Cache-Control", "no-store")
+	s.renderTemplate(w, "share.html", map[string]interface{}{
+		"Status": status,
+		"Share":  share,
+	})
+}
+
+// redactSharePath hides the token in a share page path, for request logs.
+func redactSharePath(path string) string {
+	if i := strings.Index(path, sharePathPrefix); i >= 0 {
+		return path[:i+len(sharePathPrefix)] + "…"
+	}
+	return path
+}
+
+// clientIP is the address rate limits apply to. Requests relayed by a proxy on
+// the same machine are attributed to the client it forwarded for.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	return host
+}
+
+// rateLimiter is a token bucket per client.
+type rateLimiter struct {
+	burst    float64
+	interval time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(burst int, interval time.Duration) *rateLimiter {
+	return &rateLimiter{burst: float64(burst), interval: interval, buckets: make(map[string]*rateBucket)}
+}
+
+// allow takes a token from key's bucket, reporting false when it is empty.
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Buckets idle long enough to have refilled are the same as new ones
+	full := l.interval * time.Duration(l.burst)
+	if len(l.buckets) > 1000 {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > full {
+				delete(l.buckets, k)
+			}
+		}
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += float64(now.Sub(b.last)) / float64(l.interval)
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// ShareURL is the public URL of a share page under baseURL, e.g.
+// "https://home.example.com/pagen/share/<token>".
+func ShareURL(baseURL string, share *charm.Share) string {
+	return strings.TrimRight(baseURL, "/") + sharePathPrefix + share.Token
+}
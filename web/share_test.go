@@ -0,0 +1,138 @@
+// ABOUTME: Tests for public share pages
+// ABOUTME: Covers token access without login, the off switch, per-client rate limits, and log redaction
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSharePage(t *testing.T) {
+	client := charm.NewTestClient(t)
+	company := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(company); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	deal := &charm.Deal{Title: "Pilot <b>", Amount: 250000, Currency: "USD", Stage: charm.StageProposal, CompanyID: company.ID}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	share, err := client.CreateShare(charm.EntityDeal, deal.ID, 0)
+	if err != nil {
+		t.Fatalf("CreateShare failed: %v", err)
+	}
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	auth, err := newAuthenticator(&charm.WebAuthConfig{Username: "harper", PasswordHash: string(hash)})
+	if err != nil {
+		t.Fatalf("newAuthenticator failed: %v", err)
+	}
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	s.auth = auth
+	s.sharing = true
+	handler := s.requireAuth(s.routes())
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:5555"
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/share/" + share.Token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 without login, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Pilot &lt;b&gt;", "Acme", charm.StageProposal, "Deal opened"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q on share page", want)
+		}
+	}
+	if strings.Contains(body, `href="/deals`) {
+		t.Error("share page should not link into the UI")
+	}
+	if rec.Header().Get("Referrer-Policy") != "no-referrer" {
+		t.Error("share page should not send its URL as a referrer")
+	}
+
+	if rec := get("/share/wrong"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown token, got %d", rec.Code)
+	}
+	if rec := get("/deals"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("other pages should still need login, got %d", rec.Code)
+	}
+
+	// Past the burst, the same client is turned away
+	limited := false
+	for i := 0; i < shareRateBurst; i++ {
+		if get("/share/wrong").Code == http.StatusTooManyRequests {
+			limited = true
+			break
+		}
+	}
+	if !limited {
+		t.Error("expected 429 once the burst is used up")
+	}
+
+	s.sharing = false
+	handler = s.requireAuth(s.routes())
+	if rec := get("/share/" + share.Token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("share pages should need login when sharing is off, got %d", rec.Code)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(2, time.Second)
+	now := time.Now()
+	if !l.allow("a", now) || !l.allow("a", now) {
+		t.Fatal("burst should be allowed")
+	}
+	if l.allow("a", now) {
+		t.Error("third request in the same instant should be refused")
+	}
+	if !l.allow("b", now) {
+		t.Error("other clients have their own bucket")
+	}
+	if !l.allow("a", now.Add(time.Second)) {
+		t.Error("a token should refill after the interval")
+	}
+	if l.allow("a", now.Add(time.Second)) {
+		t.Error("only one token should have refilled")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/share/x", nil)
+	req.RemoteAddr = "198.51.100.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	if got := clientIP(req); got != "198.51.100.1" {
+		t.Errorf("remote clients can't pick their address with X-Forwarded-For, got %s", got)
+	}
+
+	req.RemoteAddr = "127.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9, 10.0.0.1")
+	if got := clientIP(req); got != "203.0.113.9" {
+		t.Errorf("expected the forwarded client behind a local proxy, got %s", got)
+	}
+}
+
+func TestRedactSharePath(t *testing.T) {
+	if got := redactSharePath("/pagen/share/abc123"); got != "/pagen/share/…" {
+		t.Errorf("unexpected redaction: %s", got)
+	}
+	if got := redactSharePath("/deals"); got != "/deals" {
+		t.Errorf("other paths should be unchanged, got %s", got)
+	}
+}
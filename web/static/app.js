@@ -0,0 +1,508 @@
+// Pagen web UI behavior. Kept out of the templates so the
+// Content-Security-Policy can forbid inline scripts.
+(function () {
+    'use strict';
+
+    const basePath = document.body.dataset.basePath || '';
+
+    function csrfToken() {
+        const match = document.cookie.match(/(?:^|; )pagen_csrf=([^;]+)/);
+        return match ? decodeURIComponent(match[1]) : '';
+    }
+
+    // Send the CSRF token with every htmx request
+    document.body.addEventListener('htmx:configRequest', function (event) {
+        event.detail.headers['X-CSRF-Token'] = csrfToken();
+    });
+
+    document.addEventListener('click', function (event) {
+        // Close buttons on detail panels
+        const dismiss = event.target.closest('[data-dismiss]');
+        if (dismiss) {
+            const panel = dismiss.closest('[data-panel]');
+            if (panel) {
+                panel.remove();
+            }
+            return;
+        }
+
+        // Show or hide the DOT source of a generated graph
+        if (event.target.closest('[data-toggle-dot]')) {
+            const dotSource = document.getElementById('dot-source');
+            if (dotSource) {
+                dotSource.classList.toggle('hidden');
+            }
+            return;
+        }
+
+        if (event.target.closest('[data-generate-graph]')) {
+            generateGraph();
+        }
+    });
+
+    // Show the entity selector only for graphs that need one
+    const graphType = document.getElementById('graph-type');
+    if (graphType) {
+        graphType.addEventListener('change', function () {
+            const entitySelector = document.getElementById('entity-selector');
+            entitySelector.classList.toggle('hidden', this.value !== 'company');
+        });
+    }
+
+    function generateGraph() {
+        const type = document.getElementById('graph-type').value;
+        const entityId = document.getElementById('entity-id').value;
+
+        let url = basePath + '/partials/graph?type=' + encodeURIComponent(type);
+        if (entityId) {
+            url += '&entity_id=' + encodeURIComponent(entityId);
+        }
+
+        htmx.ajax('GET', url, {target: '#graph-display'});
+    }
+
+    // Contact timeline: pages in entries from the JSON feed as the end of the
+    // list scrolls into view, filtered by the selected kinds
+    const timeline = document.getElementById('timeline');
+    if (timeline) {
+        const status = document.getElementById('timeline-status');
+        const more = document.getElementById('timeline-more');
+        const selected = new Set();
+        let offset = 0;
+        let done = false;
+        let loading = false;
+        let generation = 0;
+
+        function entryItem(entry) {
+            const item = document.createElement('li');
+            item.className = 'mb-6 ml-4';
+
+            const when = document.createElement('time');
+            when.className = 'text-xs text-gray-500';
+            when.textContent = new Date(entry.timestamp).toLocaleString();
+
+            const kind = document.createElement('span');
+            kind.className = 'ml-2 px-2 py-0.5 text-xs rounded-full bg-purple-100 text-purple-800';
+            kind.textContent = entry.kind.replaceAll('_', ' ') + (entry.manual ? '' : ' · imported');
+
+            const summary = document.createElement('p');
+            summary.className = 'mt-1 text-sm font-medium text-gray-900';
+            summary.textContent = entry.summary || '(no notes)';
+
+            item.append(when, kind, summary);
+
+            const details = [];
+            if (entry.deal_title) details.push('Deal: ' + entry.deal_title);
+            if (entry.location) details.push('Location: ' + entry.location);
+            if (entry.outcome) details.push('Outcome: ' + entry.outcome.replaceAll('_', ' '));
+            if (entry.next_step) details.push('Next step: ' + entry.next_step);
+            if (entry.body && entry.body !== entry.summary) details.unshift(entry.body);
+            if (details.length) {
+                const body = document.createElement('p');
+                body.className = 'mt-1 text-sm text-gray-600 whitespace-pre-line';
+                body.textContent = details.join('\n');
+                item.append(body);
+            }
+            return item;
+        }
+
+        async function loadMore() {
+            if (loading || done) {
+                return;
+            }
+            loading = true;
+            const current = generation;
+            let url = timeline.dataset.timelineUrl + '?offset=' + offset;
+            if (selected.size) {
+                url += '&type=' + encodeURIComponent(Array.from(selected).join(','));
+            }
+            try {
+                const response = await fetch(url, {headers: {'Accept': 'application/json'}});
+                if (!response.ok) {
+                    throw new Error(response.statusText);
+                }
+                const page = await response.json();
+                if (current !== generation) {
+                    return; // the filters changed while this page loaded
+                }
+                page.entries.forEach(function (entry) {
+                    timeline.append(entryItem(entry));
+                });
+                offset = page.next_offset;
+                done = !page.has_more;
+                status.textContent = done ? (page.total ? 'No more activity' : 'No activity yet') : '';
+            } catch (err) {
+                status.textContent = 'Failed to load the timeline: ' + err.message;
+                done = true;
+            } finally {
+                if (current === generation) {
+                    loading = false;
+                }
+            }
+            // A short page leaves the end in view, which the observer won't report again
+            if (current === generation && !done && more.getBoundingClientRect().top < window.innerHeight) {
+                loadMore();
+            }
+        }
+
+        function reset() {
+            generation++;
+            timeline.replaceChildren();
+            offset = 0;
+            done = false;
+            loading = false;
+            status.textContent = 'Loading…';
+            loadMore();
+        }
+
+        document.querySelectorAll('[data-timeline-kind]').forEach(function (button) {
+            button.addEventListener('click', function () {
+                const kind = button.dataset.timelineKind;
+                if (selected.has(kind)) {
+                    selected.delete(kind);
+                } else {
+                    selected.add(kind);
+                }
+                button.classList.toggle('bg-purple-600', selected.has(kind));
+                button.classList.toggle('text-white', selected.has(kind));
+                reset();
+            });
+        });
+
+        new IntersectionObserver(function (entries) {
+            if (entries.some(function (entry) { return entry.isIntersecting; })) {
+                loadMore();
+            }
+        }).observe(more);
+        loadMore();
+    }
+
+    // Relationship explorer: a force-directed graph of contacts and companies
+    // from the JSON feed. Clicking a node pulls in its neighborhood.
+    const explorer = document.getElementById('graph-explorer');
+    if (explorer) {
+        const svgNS = 'http://www.w3.org/2000/svg';
+        const typeBar = document.getElementById('graph-types');
+        const selection = document.getElementById('graph-selection');
+        const status = document.getElementById('graph-status');
+        const nodes = new Map(); // id -> node with x, y, vx, vy, and its SVG group
+        const edges = new Map(); // source|target|type -> edge with its SVG line
+        const expanded = new Set();
+        const hidden = new Set(); // edge types filtered out
+        let knownTypes = [];
+        let generation = 0;
+        let alpha = 0;
+        let dragging = null;
+
+        const edgeLayer = document.createElementNS(svgNS, 'g');
+        const nodeLayer = document.createElementNS(svgNS, 'g');
+        explorer.append(edgeLayer, nodeLayer);
+
+        function size() {
+            return {width: explorer.clientWidth || 800, height: explorer.clientHeight || 560};
+        }
+
+        function typeColor(type) {
+            if (type === 'works_at') {
+                return '#9ca3af';
+            }
+            let hash = 0;
+            for (const ch of type) {
+                hash = (hash * 31 + ch.charCodeAt(0)) % 360;
+            }
+            return 'hsl(' + hash + ', 60%, 45%)';
+        }
+
+        function query(center) {
+            const params = new URLSearchParams();
+            if (center) {
+                params.set('center', center);
+            }
+            const shown = knownTypes.filter(function (type) { return !hidden.has(type); });
+            if (hidden.size && shown.length) {
+                params.set('type', shown.join(','));
+            }
+            return explorer.dataset.graphUrl + '?' + params.toString();
+        }
+
+        async function fetchGraph(center) {
+            const response = await fetch(query(center), {headers: {'Accept': 'application/json'}});
+            if (!response.ok) {
+                throw new Error(response.status === 404 ? 'not found' : response.statusText);
+            }
+            return response.json();
+        }
+
+        function addNode(data, near) {
+            let node = nodes.get(data.id);
+            if (node) {
+                Object.assign(node.data, data);
+                return node;
+            }
+            const {width, height} = size();
+            const origin = near || {x: width / 2, y: height / 2};
+            node = {
+                id: data.id,
+                data: data,
+                x: origin.x + (Math.random() - 0.5) * 80,
+                y: origin.y + (Math.random() - 0.5) * 80,
+                vx: 0,
+                vy: 0,
+            };
+
+            const group = document.createElementNS(svgNS, 'g');
+            group.style.cursor = 'pointer';
+            const shape = document.createElementNS(svgNS, data.kind === 'company' ? 'rect' : 'circle');
+            if (data.kind === 'company') {
+                shape.setAttribute('x', -9);
+                shape.setAttribute('y', -9);
+                shape.setAttribute('width', 18);
+                shape.setAttribute('height', 18);
+                shape.setAttribute('rx', 3);
+                shape.setAttribute('fill', '#fde68a');
+            } else {
+                shape.setAttribute('r', 8);
+                shape.setAttribute('fill', '#c4b5fd');
+            }
+            shape.setAttribute('stroke', '#4b5563');
+            const label = document.createElementNS(svgNS, 'text');
+            label.setAttribute('x', 12);
+            label.setAttribute('y', 4);
+            label.setAttribute('font-size', 11);
+            label.setAttribute('fill', '#1f2937');
+            label.textContent = data.label;
+            const title = document.createElementNS(svgNS, 'title');
+            title.textContent = data.label + (data.detail ? ' · ' + data.detail : '');
+            group.append(title, shape, label);
+
+            group.addEventListener('mousedown', function (event) {
+                dragging = {node: node, moved: false};
+                event.preventDefault();
+            });
+            nodeLayer.append(group);
+            node.group = group;
+            node.shape = shape;
+            nodes.set(node.id, node);
+            return node;
+        }
+
+        function addEdge(data) {
+            const key = data.source + '|' + data.target + '|' + data.type;
+            if (edges.has(key) || !nodes.has(data.source) || !nodes.has(data.target)) {
+                return;
+            }
+            const line = document.createElementNS(svgNS, 'line');
+            line.setAttribute('stroke', typeColor(data.type));
+            line.setAttribute('stroke-width', data.strength === 'strong' ? 3 : data.strength === 'weak' ? 1 : 1.5);
+            if (data.type === 'works_at') {
+                line.setAttribute('stroke-dasharray', '4 3');
+            }
+            const title = document.createElementNS(svgNS, 'title');
+            title.textContent = data.type.replaceAll('_', ' ') + (data.strength ? ' (' + data.strength + ')' : '');
+            line.append(title);
+            edgeLayer.append(line);
+            edges.set(key, {data: data, line: line});
+        }
+
+        function merge(graph, near) {
+            graph.nodes.forEach(function (data) {
+                addNode(data, near);
+            });
+            graph.edges.forEach(addEdge);
+            if (graph.types.join() !== knownTypes.join()) {
+                knownTypes = graph.types;
+                renderTypes();
+            }
+            status.textContent = nodes.size + ' nodes, ' + edges.size + ' links' +
+                (graph.truncated ? ' (showing the best connected; click to explore further)' : '');
+            alpha = 1;
+        }
+
+        function renderTypes() {
+            typeBar.replaceChildren();
+            knownTypes.forEach(function (type) {
+                const chip = document.createElement('button');
+                chip.className = 'px-3 py-1 text-xs rounded-full border';
+                chip.style.borderColor = typeColor(type);
+                chip.style.color = hidden.has(type) ? '#9ca3af' : typeColor(type);
+                chip.style.textDecoration = hidden.has(type) ? 'line-through' : 'none';
+                chip.textContent = type.replaceAll('_', ' ');
+                chip.addEventListener('click', function () {
+                    if (hidden.has(type)) {
+                        hidden.delete(type);
+                    } else if (hidden.size < knownTypes.length - 1) {
+                        hidden.add(type); // keep at least one type shown
+                    }
+                    renderTypes();
+                    reload();
+                });
+                typeBar.append(chip);
+            });
+        }
+
+        function select(node) {
+            nodes.forEach(function (other) {
+                other.shape.setAttribute('stroke-width', other === node ? 3 : 1);
+            });
+            const name = document.createElement('p');
+            name.className = 'text-lg font-semibold text-gray-800';
+            name.textContent = node.data.label;
+            const detail = document.createElement('p');
+            detail.textContent = [node.data.kind, node.data.detail].filter(Boolean).join(' · ');
+            const degree = document.createElement('p');
+            degree.className = 'mt-2';
+            degree.textContent = node.data.degree + ' connection' + (node.data.degree === 1 ? '' : 's');
+            const open = document.createElement('a');
+            open.className = 'mt-2 block text-purple-600 hover:text-purple-800';
+            open.href = node.data.url;
+            open.textContent = 'Open ' + node.data.kind + ' →';
+            selection.replaceChildren(name, detail, degree, open);
+        }
+
+        async function expand(node) {
+            select(node);
+            if (expanded.has(node.id)) {
+                return;
+            }
+            expanded.add(node.id);
+            const current = generation;
+            try {
+                const graph = await fetchGraph(node.id);
+                if (current === generation) {
+                    merge(graph, node);
+                }
+            } catch (err) {
+                status.textContent = 'Failed to load connections: ' + err.message;
+                expanded.delete(node.id);
+            }
+        }
+
+        // reload starts over with the current filters from the center, or
+        // the overview, then expands the same nodes again
+        async function reload() {
+            const current = ++generation;
+            const reopen = Array.from(expanded);
+            const start = explorer.dataset.center || '';
+            nodes.clear();
+            edges.clear();
+            expanded.clear();
+            edgeLayer.replaceChildren();
+            nodeLayer.replaceChildren();
+            selection.textContent = 'Nothing selected';
+            status.textContent = 'Loading…';
+            try {
+                const graph = await fetchGraph(start);
+                if (current !== generation) {
+                    return; // the filters changed while this loaded
+                }
+                merge(graph);
+                if (start) {
+                    expanded.add(start);
+                }
+                for (const id of reopen) {
+                    if (current !== generation) {
+                        return;
+                    }
+                    if (nodes.has(id) && !expanded.has(id)) {
+                        expanded.add(id);
+                        const neighborhood = await fetchGraph(id);
+                        if (current === generation) {
+                            merge(neighborhood, nodes.get(id));
+                        }
+                    }
+                }
+                if (start && nodes.has(start)) {
+                    select(nodes.get(start));
+                }
+            } catch (err) {
+                status.textContent = 'Failed to load the graph: ' + err.message;
+            }
+        }
+
+        function tick() {
+            if (alpha > 0.01 || dragging) {
+                const {width, height} = size();
+                const list = Array.from(nodes.values());
+                // Repulsion between every pair
+                for (let i = 0; i < list.length; i++) {
+                    for (let j = i + 1; j < list.length; j++) {
+                        const a = list[i], b = list[j];
+                        let dx = b.x - a.x, dy = b.y - a.y;
+                        const dist2 = Math.max(dx * dx + dy * dy, 25);
+                        const force = 1200 / dist2;
+                        const dist = Math.sqrt(dist2);
+                        dx /= dist;
+                        dy /= dist;
+                        a.vx -= dx * force;
+                        a.vy -= dy * force;
+                        b.vx += dx * force;
+                        b.vy += dy * force;
+                    }
+                }
+                // Springs along edges
+                edges.forEach(function (edge) {
+                    const a = nodes.get(edge.data.source), b = nodes.get(edge.data.target);
+                    const dx = b.x - a.x, dy = b.y - a.y;
+                    const dist = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+                    const force = (dist - 90) * 0.02;
+                    a.vx += dx / dist * force;
+                    a.vy += dy / dist * force;
+                    b.vx -= dx / dist * force;
+                    b.vy -= dy / dist * force;
+                });
+                list.forEach(function (node) {
+                    if (dragging && dragging.node === node) {
+                        node.vx = node.vy = 0;
+                        return;
+                    }
+                    // A gentle pull to the middle keeps islands in view
+                    node.vx += (width / 2 - node.x) * 0.002;
+                    node.vy += (height / 2 - node.y) * 0.002;
+                    node.x = Math.min(Math.max(node.x + node.vx * alpha, 10), width - 10);
+                    node.y = Math.min(Math.max(node.y + node.vy * alpha, 10), height - 10);
+                    node.vx *= 0.6;
+                    node.vy *= 0.6;
+                    node.group.setAttribute('transform', 'translate(' + node.x + ',' + node.y + ')');
+                });
+                edges.forEach(function (edge) {
+                    const a = nodes.get(edge.data.source), b = nodes.get(edge.data.target);
+                    edge.line.setAttribute('x1', a.x);
+                    edge.line.setAttribute('y1', a.y);
+                    edge.line.setAttribute('x2', b.x);
+                    edge.line.setAttribute('y2', b.y);
+                });
+                alpha *= 0.98;
+            }
+            requestAnimationFrame(tick);
+        }
+
+        explorer.addEventListener('mousemove', function (event) {
+            if (!dragging) {
+                return;
+            }
+            const box = explorer.getBoundingClientRect();
+            dragging.node.x = event.clientX - box.left;
+            dragging.node.y = event.clientY - box.top;
+            dragging.moved = true;
+            alpha = Math.max(alpha, 0.3);
+        });
+        window.addEventListener('mouseup', function () {
+            if (dragging && !dragging.moved) {
+                expand(dragging.node);
+            }
+            dragging = null;
+        });
+
+        document.querySelectorAll('[data-graph-reset]').forEach(function (button) {
+            button.addEventListener('click', function () {
+                explorer.dataset.center = '';
+                expanded.clear();
+                history.replaceState(null, '', location.pathname);
+                reload();
+            });
+        });
+
+        reload();
+        requestAnimationFrame(tick);
+    }
+})();
@@ -0,0 +1,265 @@
+// ABOUTME: Request logging, slow KV operation tracing, and a Prometheus metrics endpoint
+// ABOUTME: Writes JSON lines to the web log read by `pagen logs web`
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+// DefaultSlowQueryThreshold is how long a KV operation may take before it is logged.
+const DefaultSlowQueryThreshold = 100 * time.Millisecond
+
+// maxLogSize is when the web log is rotated to web.log.1.
+const maxLogSize = 10 << 20
+
+// LogEntry is one line of the web log.
+type LogEntry struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"` // "request" or "slow_query"
+
+	// Requests
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Status int    `json:"status,omitempty"`
+
+	// Slow queries
+	Op  string `json:"op,omitempty"`
+	Key string `json:"key,omitempty"`
+
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// LogPath returns where the web server writes its request log.
+func LogPath() string {
+	return filepath.Join(charm.DataDir(), "logs", "web.log")
+}
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// telemetry aggregates counters for /metrics and appends entries to the web log.
+type telemetry struct {
+	slowThreshold time.Duration
+
+	mu            sync.Mutex
+	file          *os.File
+	path          string
+	requests      map[string]int64 // by status class, e.g. "2xx"
+	latencyCounts []int64          // cumulative per latencyBuckets
+	latencySum    float64
+	latencyCount  int64
+	queries       map[string]int64 // by op
+	queryErrors   int64
+	slowQueries   int64
+	querySeconds  float64
+	started       time.Time
+}
+
+func newTelemetry(path string, slowThreshold time.Duration) *telemetry {
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowQueryThreshold
+	}
+	t := &telemetry{
+		slowThreshold: slowThreshold,
+		path:          path,
+		requests:      make(map[string]int64),
+		latencyCounts: make([]int64, len(latencyBuckets)),
+		queries:       make(map[string]int64),
+		started:       time.Now(),
+	}
+	if path != "" {
+		if err := t.open(); err != nil {
+			log.Printf("Warning: request log disabled: %v", err)
+		}
+	}
+	return t
+}
+
+func (t *telemetry) open() error {
+	if err := os.MkdirAll(filepath.Dir(t.path), 0700); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(t.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	t.file = f
+	return nil
+}
+
+// write appends an entry to the log, rotating it when it grows too large. Callers hold t.mu.
+func (t *telemetry) write(entry LogEntry) {
+	if t.file == nil {
+		return
+	}
+	if info, err := t.file.Stat(); err == nil && info.Size() > maxLogSize {
+		_ = t.file.Close()
+		_ = os.Rename(t.path, t.path+".1")
+		if err := t.open(); err != nil {
+			t.file = nil
+			log.Printf("Warning: request log disabled: %v", err)
+			return
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_, _ = t.file.Write(append(data, '\n'))
+}
+
+func (t *telemetry) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
+
+// recordRequest counts a finished request and logs it.
+func (t *telemetry) recordRequest(r *http.Request, status int, elapsed time.Duration) {
+	seconds := elapsed.Seconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests[fmt.Sprintf("%dxx", status/100)]++
+	t.latencySum += seconds
+	t.latencyCount++
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			t.latencyCounts[i]++
+		}
+	}
+	t.write(LogEntry{
+		Time:       time.Now(),
+		Kind:       "request",
+		Method:     r.Method,
+		Path:       redactSharePath(r.URL.Path),
+		Status:     status,
+		DurationMS: durationMS(elapsed),
+	})
+}
+
+// observeQuery is installed as the client's charm.QueryObserver.
+func (t *telemetry) observeQuery(op string, key []byte, elapsed time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries[op]++
+	t.querySeconds += elapsed.Seconds()
+	if err != nil {
+		t.queryErrors++
+	}
+	if elapsed < t.slowThreshold {
+		return
+	}
+	t.slowQueries++
+	entry := LogEntry{
+		Time:       time.Now(),
+		Kind:       "slow_query",
+		Op:         op,
+		Key:        string(key),
+		DurationMS: durationMS(elapsed),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	t.write(entry)
+}
+
+// logRequests records the status and latency of every request.
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		s.telemetry.recordRequest(r, rec.status, time.Since(start))
+	})
+}
+
+// handleMetrics serves counters in the Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	t := s.telemetry
+	var b bytes.Buffer
+
+	// Render under the lock, write after it so a slow scraper can't stall requests
+	t.mu.Lock()
+	fmt.Fprintln(&b, "# HELP pagen_http_requests_total Web requests by status class.")
+	fmt.Fprintln(&b, "# TYPE pagen_http_requests_total counter")
+	for _, class := range sortedKeys(t.requests) {
+		fmt.Fprintf(&b, "pagen_http_requests_total{code=%q} %d\n", class, t.requests[class])
+	}
+
+	fmt.Fprintln(&b, "# HELP pagen_http_request_duration_seconds Web request latency.")
+	fmt.Fprintln(&b, "# TYPE pagen_http_request_duration_seconds histogram")
+	for i, bound := range latencyBuckets {
+		fmt.Fprintf(&b, "pagen_http_request_duration_seconds_bucket{le=\"%g\"} %d\n", bound, t.latencyCounts[i])
+	}
+	fmt.Fprintf(&b, "pagen_http_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", t.latencyCount)
+	fmt.Fprintf(&b, "pagen_http_request_duration_seconds_sum %g\n", t.latencySum)
+	fmt.Fprintf(&b, "pagen_http_request_duration_seconds_count %d\n", t.latencyCount)
+
+	fmt.Fprintln(&b, "# HELP pagen_kv_operations_total KV operations by type.")
+	fmt.Fprintln(&b, "# TYPE pagen_kv_operations_total counter")
+	for _, op := range sortedKeys(t.queries) {
+		fmt.Fprintf(&b, "pagen_kv_operations_total{op=%q} %d\n", op, t.queries[op])
+	}
+	fmt.Fprintln(&b, "# HELP pagen_kv_operation_seconds_total Time spent in KV operations.")
+	fmt.Fprintln(&b, "# TYPE pagen_kv_operation_seconds_total counter")
+	fmt.Fprintf(&b, "pagen_kv_operation_seconds_total %g\n", t.querySeconds)
+	fmt.Fprintln(&b, "# HELP pagen_kv_operation_errors_total KV operations that failed.")
+	fmt.Fprintln(&b, "# TYPE pagen_kv_operation_errors_total counter")
+	fmt.Fprintf(&b, "pagen_kv_operation_errors_total %d\n", t.queryErrors)
+	fmt.Fprintln(&b, "# HELP pagen_kv_slow_operations_total KV operations slower than the slow query threshold.")
+	fmt.Fprintln(&b, "# TYPE pagen_kv_slow_operations_total counter")
+	fmt.Fprintf(&b, "pagen_kv_slow_operations_total %d\n", t.slowQueries)
+
+	fmt.Fprintln(&b, "# HELP pagen_uptime_seconds Seconds since the web server started.")
+	fmt.Fprintln(&b, "# TYPE pagen_uptime_seconds gauge")
+	fmt.Fprintf(&b, "pagen_uptime_seconds %g\n", time.Since(t.started).Seconds())
+	t.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(b.Bytes())
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
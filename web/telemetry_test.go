@@ -0,0 +1,101 @@
+// ABOUTME: Tests for request logging, slow KV tracing, and the metrics endpoint
+// ABOUTME: Uses a temp log file and a test client with the query observer installed
+
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func readTestLog(t *testing.T, path string) []LogEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("bad log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestRequestLogging(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "web.log")
+	s := &Server{telemetry: newTelemetry(path, 50*time.Millisecond)}
+	defer s.telemetry.close()
+
+	handler := s.logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	for _, p := range []string{"/", "/deals", "/broken"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, p, nil))
+	}
+
+	// Only operations over the threshold are logged, but all are counted
+	s.telemetry.observeQuery("get", []byte("contact:1"), time.Millisecond, nil)
+	s.telemetry.observeQuery("scan", []byte("deal:"), 80*time.Millisecond, errors.New("disk"))
+
+	entries := readTestLog(t, path)
+	if len(entries) != 4 {
+		t.Fatalf("expected 3 requests and 1 slow query logged, got %+v", entries)
+	}
+	if entries[2].Path != "/broken" || entries[2].Status != http.StatusInternalServerError {
+		t.Errorf("expected failed request to be logged with its status, got %+v", entries[2])
+	}
+	if slow := entries[3]; slow.Kind != "slow_query" || slow.Op != "scan" || slow.Key != "deal:" || slow.Error != "disk" {
+		t.Errorf("unexpected slow query entry %+v", slow)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`pagen_http_requests_total{code="2xx"} 2`,
+		`pagen_http_requests_total{code="5xx"} 1`,
+		`pagen_http_request_duration_seconds_count 3`,
+		`pagen_kv_operations_total{op="get"} 1`,
+		`pagen_kv_operation_errors_total 1`,
+		`pagen_kv_slow_operations_total 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestQueryObserver(t *testing.T) {
+	client := charm.NewTestClient(t)
+	var ops []string
+	client.SetQueryObserver(func(op string, key []byte, elapsed time.Duration, err error) {
+		ops = append(ops, op)
+	})
+
+	if err := client.CreateContact(&charm.Contact{Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatal("expected KV operations to be observed")
+	}
+}
@@ -0,0 +1,150 @@
+// ABOUTME: Contact page with a chronological timeline of interactions and deal notes
+// ABOUTME: Serves the page and the paginated JSON feed its infinite scroll reads
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/pagen/charm"
+)
+
+// Timeline page sizes for GET /api/contacts/{id}/timeline.
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+)
+
+// timelineKinds are the filters offered on the contact page, in display order.
+var timelineKinds = []string{
+	charm.InteractionMeeting,
+	charm.InteractionCall,
+	charm.InteractionEmail,
+	charm.InteractionMessage,
+	charm.InteractionEvent,
+	charm.ActivityDealNote,
+}
+
+// timelinePage is the JSON body of GET /api/contacts/{id}/timeline.
+type timelinePage struct {
+	ContactID  string                 `json:"contact_id"`
+	Entries    []*charm.ActivityEntry `json:"entries"`
+	Offset     int                    `json:"offset"`
+	Limit      int                    `json:"limit"`
+	Total      int                    `json:"total"`
+	HasMore    bool                   `json:"has_more"`
+	NextOffset int                    `json:"next_offset,omitempty"`
+}
+
+// handleContactPage serves /contacts/{id}: the contact's details and their
+// timeline, which the page loads from the JSON feed as it scrolls.
+func (s *Server) handleContactPage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/contacts/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	contact, err := s.client.GetContact(id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Contact":         contact,
+		"CompanyName":     contact.CompanyName, // Already denormalized in charm model
+		"TimelineKinds":   timelineKinds,
+		"TimelineURL":     s.url("/api/contacts/" + id.String() + "/timeline"),
+		"GraphURL":        s.graphExplorerURL(id),
+		"Title":           contact.Name,
+		"ContentTemplate": "contact-content",
+	}
+
+	s.renderTemplate(w, "layout.html", data)
+}
+
+// handleContactTimelineAPI serves GET /api/contacts/{id}/timeline, newest
+// first. Query parameters: type (comma-separated kinds), offset, and limit.
+func (s *Server) handleContactTimelineAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/contacts/"), "/timeline")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "Invalid contact ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := s.client.GetContact(id); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	offset, err := nonNegativeParam(query.Get("offset"), 0)
+	if err != nil {
+		http.Error(w, "Invalid offset", http.StatusBadRequest)
+		return
+	}
+	limit, err := nonNegativeParam(query.Get("limit"), defaultTimelineLimit)
+	if err != nil || limit == 0 {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	var kinds []string
+	for _, kind := range strings.Split(query.Get("type"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	entries, err := s.client.ListContactActivity(id, kinds)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page := timelinePage{ContactID: id.String(), Entries: []*charm.ActivityEntry{}, Offset: offset, Limit: limit, Total: len(entries)}
+	if offset < len(entries) {
+		end := min(offset+limit, len(entries))
+		page.Entries = entries[offset:end]
+		if end < len(entries) {
+			page.HasMore = true
+			page.NextOffset = end
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Printf("Error writing timeline: %v", err)
+	}
+}
+
+// nonNegativeParam parses a non-negative integer query parameter, or
+// returns fallback when it is empty.
+func nonNegativeParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return n, nil
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for the contact page and its timeline feed
+// ABOUTME: Verifies newest-first paging, type filters, deal notes, and bad requests
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harperreed/pagen/charm"
+)
+
+func TestContactTimeline(t *testing.T) {
+	client := charm.NewTestClient(t)
+	ada := &charm.Contact{Name: "Ada"}
+	if err := client.CreateContact(ada); err != nil {
+		t.Fatalf("CreateContact failed: %v", err)
+	}
+	now := time.Now()
+	for i, kind := range []string{charm.InteractionMeeting, charm.InteractionEmail, charm.InteractionCall} {
+		log := &charm.InteractionLog{ContactID: ada.ID, InteractionType: kind, Notes: kind + " notes", Timestamp: now.AddDate(0, 0, -3+i)}
+		if err := client.CreateInteractionLog(log); err != nil {
+			t.Fatalf("CreateInteractionLog failed: %v", err)
+		}
+	}
+	acme := &charm.Company{Name: "Acme"}
+	if err := client.CreateCompany(acme); err != nil {
+		t.Fatalf("CreateCompany failed: %v", err)
+	}
+	deal := &charm.Deal{Title: "Pilot", CompanyID: acme.ID, ContactID: &ada.ID, Stage: charm.StageProspecting}
+	if err := client.CreateDeal(deal); err != nil {
+		t.Fatalf("CreateDeal failed: %v", err)
+	}
+	if err := client.CreateDealNote(&charm.DealNote{DealID: deal.ID, Content: "Sent pricing"}); err != nil {
+		t.Fatalf("CreateDealNote failed: %v", err)
+	}
+
+	s, err := NewServer(client)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	handler := s.routes()
+	get := func(path string) (*httptest.ResponseRecorder, timelinePage) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var page timelinePage
+		if rec.Code == http.StatusOK && strings.HasPrefix(path, "/api/") {
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("bad timeline JSON: %v", err)
+			}
+		}
+		return rec, page
+	}
+	feed := "/api/contacts/" + ada.ID.String() + "/timeline"
+
+	_, page := get(feed + "?limit=2")
+	if page.Total != 4 || len(page.Entries) != 2 || !page.HasMore || page.NextOffset != 2 {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if page.Entries[0].Kind != charm.ActivityDealNote || page.Entries[0].DealTitle != "Pilot" || page.Entries[1].Kind != charm.InteractionCall {
+		t.Errorf("expected newest first, got %s then %s", page.Entries[0].Kind, page.Entries[1].Kind)
+	}
+	_, page = get(feed + "?limit=2&offset=2")
+	if len(page.Entries) != 2 || page.HasMore || page.Entries[1].Kind != charm.InteractionMeeting {
+		t.Errorf("unexpected last page: %+v", page)
+	}
+
+	_, page = get(feed + "?type=meeting,email")
+	if page.Total != 2 || page.Entries[0].Kind != charm.InteractionEmail {
+		t.Errorf("expected only meetings and emails, got %+v", page)
+	}
+
+	if rec, _ := get(feed + "?limit=abc"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad limit, got %d", rec.Code)
+	}
+	if rec, _ := get("/api/contacts/not-an-id/timeline"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad ID, got %d", rec.Code)
+	}
+
+	rec, _ := get("/contacts/" + ada.ID.String())
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-timeline-url="`+feed+`"`) {
+		t.Errorf("expected the contact page to point at its feed, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+}